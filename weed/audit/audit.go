@@ -0,0 +1,271 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/stats"
+)
+
+// The audit log records the mutating operations of the filer and the s3 gateway: who did what, when, and with which result,
+// as one JSON object per line. The records are queued without blocking the requests, dropped if the queue is full,
+// and written in batches into the sink, a local file rotated by size, or the objects of an S3 bucket.
+// A batch failing to write is retried with backoff, collecting the new records up to the queue size, and the records beyond are dropped.
+// The filer and the s3 gateway running in the same process share the audit log, the records tell the service.
+
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+	OperationRename Operation = "rename"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+type Record struct {
+	Time       time.Time `json:"time"`
+	Service    string    `json:"service"`
+	Operation  Operation `json:"operation"`
+	Action     string    `json:"action,omitempty"`
+	Identity   string    `json:"identity,omitempty"`
+	RemoteAddr string    `json:"remoteAddr,omitempty"`
+	RequestId  string    `json:"requestId,omitempty"`
+	Path       string    `json:"path"`
+	NewPath    string    `json:"newPath,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	Result     string    `json:"result"`
+	Status     int       `json:"status,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Sink keeps the audit log
+type Sink interface {
+	// Write writes a batch of records, one JSON object per line
+	Write(data []byte) error
+	Close() error
+}
+
+const (
+	defaultQueueSize = 10000
+	maxBatchSize     = 1024 * 1024
+	maxRetryInterval = 5 * time.Minute
+)
+
+type Logger struct {
+	sink          Sink
+	records       chan *Record
+	queueSize     int
+	flushInterval time.Duration
+	retryInterval time.Duration // zero unless the last write failed
+	retryAt       time.Time
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+func NewLogger(sink Sink, queueSize int, flushInterval time.Duration) *Logger {
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	l := &Logger{
+		sink:          sink,
+		records:       make(chan *Record, queueSize),
+		queueSize:     queueSize,
+		flushInterval: flushInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go l.loop()
+	return l
+}
+
+// Log never blocks, the record is dropped if the queue is full
+func (l *Logger) Log(record *Record) {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	select {
+	case l.records <- record:
+	default:
+		stats.AuditLogCounter.WithLabelValues(record.Service, "dropped").Inc()
+	}
+}
+
+// Close writes the queued records, and closes the sink
+func (l *Logger) Close() error {
+	close(l.stop)
+	<-l.done
+	return l.sink.Close()
+}
+
+// auditBatch collects the lines of the records until written
+type auditBatch struct {
+	data     bytes.Buffer
+	count    int
+	services map[string]int
+}
+
+func (l *Logger) loop() {
+
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+	defer close(l.done)
+
+	batch := &auditBatch{services: make(map[string]int)}
+	for {
+		select {
+		case record := <-l.records:
+			l.add(batch, record)
+			if batch.data.Len() >= maxBatchSize && l.retryInterval == 0 {
+				l.flush(batch)
+			}
+		case now := <-ticker.C:
+			if !now.Before(l.retryAt) {
+				l.flush(batch)
+			}
+		case <-l.stop:
+			for {
+				select {
+				case record := <-l.records:
+					l.add(batch, record)
+				default:
+					if !l.flush(batch) {
+						batch.reset("dropped")
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// add collects the record into the batch, or drops it if the batch kept by the failing writes is as large as the queue
+func (l *Logger) add(batch *auditBatch, record *Record) {
+	if batch.count >= l.queueSize {
+		stats.AuditLogCounter.WithLabelValues(record.Service, "dropped").Inc()
+		return
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		glog.Errorf("encode audit record %+v: %v", record, err)
+		return
+	}
+	batch.data.Write(line)
+	batch.data.WriteByte('\n')
+	batch.count++
+	batch.services[record.Service]++
+}
+
+// flush writes the batch into the sink, and keeps it to retry with backoff if the write fails
+func (l *Logger) flush(batch *auditBatch) bool {
+	if batch.count == 0 {
+		return true
+	}
+	if err := l.sink.Write(batch.data.Bytes()); err != nil {
+		if l.retryInterval == 0 {
+			l.retryInterval = l.flushInterval
+		} else if l.retryInterval *= 2; l.retryInterval > maxRetryInterval {
+			l.retryInterval = maxRetryInterval
+		}
+		l.retryAt = time.Now().Add(l.retryInterval)
+		glog.Errorf("write audit log of %d records, retry in %v: %v", batch.count, l.retryInterval, err)
+		return false
+	}
+	l.retryInterval, l.retryAt = 0, time.Time{}
+	batch.reset("written")
+	return true
+}
+
+// reset counts the records of the batch as written or dropped, and empties it
+func (batch *auditBatch) reset(counterType string) {
+	for service, count := range batch.services {
+		stats.AuditLogCounter.WithLabelValues(service, counterType).Add(float64(count))
+	}
+	batch.data.Reset()
+	batch.count = 0
+	batch.services = make(map[string]int)
+}
+
+var (
+	logger     *Logger
+	loggerOnce sync.Once
+)
+
+// SetLogger replaces the audit logger of the process, nil to stop auditing
+func SetLogger(l *Logger) {
+	logger = l
+}
+
+func Enabled() bool {
+	return logger != nil
+}
+
+// Log records a mutating operation, if the audit log is enabled
+func Log(record *Record) {
+	if logger != nil {
+		logger.Log(record)
+	}
+}
+
+// LoadConfiguration enables the audit log with the sink enabled in the audit.toml configuration, once for the process
+func LoadConfiguration(config *viper.Viper, prefix string) {
+
+	if config == nil {
+		return
+	}
+
+	loggerOnce.Do(func() {
+		fileEnabled, s3Enabled := config.GetBool(prefix+"file.enabled"), config.GetBool(prefix+"s3.enabled")
+		if fileEnabled && s3Enabled {
+			glog.Fatalf("Audit log is enabled for both file and s3")
+		}
+
+		var sink Sink
+		var flushSeconds int
+		var err error
+		switch {
+		case fileEnabled:
+			config.SetDefault(prefix+"file.max_size_mb", 100)
+			config.SetDefault(prefix+"file.max_backups", 10)
+			config.SetDefault(prefix+"file.flush_seconds", 1)
+			sink, err = NewFileSink(
+				config.GetString(prefix+"file.path"),
+				config.GetInt64(prefix+"file.max_size_mb")*1024*1024,
+				config.GetInt(prefix+"file.max_backups"),
+			)
+			flushSeconds = config.GetInt(prefix + "file.flush_seconds")
+		case s3Enabled:
+			config.SetDefault(prefix+"s3.flush_seconds", 60)
+			sink, err = NewS3Sink(
+				config.GetString(prefix+"s3.endpoint"),
+				config.GetString(prefix+"s3.region"),
+				config.GetString(prefix+"s3.aws_access_key_id"),
+				config.GetString(prefix+"s3.aws_secret_access_key"),
+				config.GetString(prefix+"s3.bucket"),
+				config.GetString(prefix+"s3.prefix"),
+			)
+			flushSeconds = config.GetInt(prefix + "s3.flush_seconds")
+		default:
+			return
+		}
+		if err != nil {
+			glog.Fatalf("Failed to initialize audit log: %v", err)
+		}
+		if flushSeconds <= 0 {
+			flushSeconds = 1
+		}
+
+		SetLogger(NewLogger(sink, config.GetInt(prefix+"queue_size"), time.Duration(flushSeconds)*time.Second))
+		glog.V(0).Infof("Configure audit log into %s", sink)
+	})
+
+}
@@ -0,0 +1,181 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memorySink struct {
+	sync.Mutex
+	data     bytes.Buffer
+	batches  int
+	failures int // the number of the writes to fail
+	blocked  chan struct{}
+}
+
+func (sink *memorySink) Write(data []byte) error {
+	if sink.blocked != nil {
+		<-sink.blocked
+	}
+	sink.Lock()
+	defer sink.Unlock()
+	if sink.failures > 0 {
+		sink.failures--
+		return fmt.Errorf("simulated failure")
+	}
+	sink.data.Write(data)
+	sink.batches++
+	return nil
+}
+
+func (sink *memorySink) Close() error {
+	return nil
+}
+
+func (sink *memorySink) records(t *testing.T) (records []Record) {
+	sink.Lock()
+	defer sink.Unlock()
+	scanner := bufio.NewScanner(bytes.NewReader(sink.data.Bytes()))
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decode %s: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	sink := &memorySink{}
+	l := NewLogger(sink, 100, time.Hour)
+	for i := 0; i < 10; i++ {
+		l.Log(&Record{Service: "filer", Operation: OperationCreate, Path: fmt.Sprintf("/dir/file%d", i), Result: ResultSuccess})
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	records := sink.records(t)
+	if len(records) != 10 || sink.batches != 1 {
+		t.Fatalf("%d records in %d batches", len(records), sink.batches)
+	}
+	for i, record := range records {
+		if record.Path != fmt.Sprintf("/dir/file%d", i) || record.Operation != OperationCreate || record.Time.IsZero() {
+			t.Errorf("record %d: %+v", i, record)
+		}
+	}
+}
+
+func TestLoggerNeverBlocks(t *testing.T) {
+	sink := &memorySink{blocked: make(chan struct{})}
+	l := NewLogger(sink, 5, time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			l.Log(&Record{Service: "s3", Operation: OperationDelete, Path: "/bucket/object"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("logging blocked on the sink")
+	}
+
+	close(sink.blocked)
+	l.Close()
+	// the queue, and the batch being written while the queue filled up
+	if records := sink.records(t); len(records) == 0 || len(records) > 100 {
+		t.Errorf("%d records", len(records))
+	}
+}
+
+func TestLoggerRetry(t *testing.T) {
+	sink := &memorySink{failures: 2}
+	l := NewLogger(sink, 5, time.Millisecond)
+	for i := 0; i < 3; i++ {
+		l.Log(&Record{Service: "filer", Operation: OperationCreate, Path: fmt.Sprintf("/dir/file%d", i)})
+	}
+	for start := time.Now(); len(sink.records(t)) == 0; time.Sleep(time.Millisecond) {
+		if time.Since(start) > 10*time.Second {
+			t.Fatalf("failed batch not retried")
+		}
+	}
+	l.Close()
+	if records := sink.records(t); len(records) != 3 || records[0].Path != "/dir/file0" {
+		t.Errorf("records %+v", records)
+	}
+
+	// the failed batch keeps up to the queue size
+	sink = &memorySink{failures: 1}
+	l = NewLogger(sink, 5, time.Hour)
+	batch := &auditBatch{services: make(map[string]int)}
+	for i := 0; i < 3; i++ {
+		l.add(batch, &Record{Service: "filer", Path: fmt.Sprintf("/dir/file%d", i)})
+	}
+	if l.flush(batch) || batch.count != 3 || l.retryAt.IsZero() {
+		t.Fatalf("failed flush: %d records, retry at %v", batch.count, l.retryAt)
+	}
+	for i := 3; i < 10; i++ {
+		l.add(batch, &Record{Service: "filer", Path: fmt.Sprintf("/dir/file%d", i)})
+	}
+	if !l.flush(batch) || batch.count != 0 || !l.retryAt.IsZero() {
+		t.Fatalf("retried flush: %d records, retry at %v", batch.count, l.retryAt)
+	}
+	l.Close()
+	if records := sink.records(t); len(records) != 5 || records[4].Path != "/dir/file4" {
+		t.Errorf("records %+v", records)
+	}
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("temp dir creation: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "log", "audit.log")
+
+	sink, err := NewFileSink(path, 100, 2)
+	if err != nil {
+		t.Fatalf("create file sink: %v", err)
+	}
+	line := bytes.Repeat([]byte("x"), 59)
+	line = append(line, '\n')
+	for i := 0; i < 5; i++ {
+		if err = sink.Write(line); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+	sink.Close()
+
+	// 5 batches of 60 bytes in files of 100 bytes, the oldest rotated file removed
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		if data, err := ioutil.ReadFile(name); err != nil || !bytes.Equal(data, line) {
+			t.Errorf("%s: %d bytes, %v", name, len(data), err)
+		}
+	}
+	if _, err = os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 is kept: %v", path, err)
+	}
+
+	// appended to after a restart
+	if sink, err = NewFileSink(path, 100, 2); err != nil {
+		t.Fatalf("reopen file sink: %v", err)
+	}
+	sink.Write([]byte("y\n"))
+	sink.Close()
+	if data, _ := ioutil.ReadFile(path); len(data) != len(line)+2 {
+		t.Errorf("reopened %s: %q", path, data)
+	}
+}
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileSink appends the records to a local file, rotated once it reaches the max size.
+// The rotated files are renamed <path>.1, <path>.2, ..., up to <path>.<max backups>, the oldest removed.
+type FileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit log file path is not set")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	sink := &FileSink{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (sink *FileSink) open() error {
+	file, err := os.OpenFile(sink.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	sink.file, sink.size = file, stat.Size()
+	return nil
+}
+
+func (sink *FileSink) Write(data []byte) error {
+	if sink.file == nil {
+		// a failed rotation is retried with the next batch
+		if err := sink.open(); err != nil {
+			return err
+		}
+	}
+	if sink.maxSize > 0 && sink.size > 0 && sink.size+int64(len(data)) > sink.maxSize {
+		if err := sink.rotate(); err != nil {
+			return fmt.Errorf("rotate %s: %v", sink.path, err)
+		}
+	}
+	n, err := sink.file.Write(data)
+	sink.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return sink.file.Sync()
+}
+
+func (sink *FileSink) rotate() error {
+	if err := sink.file.Close(); err != nil {
+		return err
+	}
+	sink.file = nil
+	for i := sink.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", sink.path, i), fmt.Sprintf("%s.%d", sink.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if sink.maxBackups > 0 {
+		if err := os.Rename(sink.path, sink.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(sink.path); err != nil {
+		return err
+	}
+	return sink.open()
+}
+
+func (sink *FileSink) Close() error {
+	if sink.file == nil {
+		return nil
+	}
+	return sink.file.Close()
+}
+
+func (sink *FileSink) String() string {
+	return "file " + sink.path
+}
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// S3Sink writes each batch of records as an object "<prefix>YYYY/MM/DD/HH-MM-SS-<unique string>.log" of the bucket,
+// on AWS or any S3 compatible endpoint, e.g. a SeaweedFS s3 gateway of another cluster.
+type S3Sink struct {
+	svc    s3iface.S3API
+	bucket string
+	prefix string
+}
+
+func NewS3Sink(endpoint, region, awsAccessKeyId, awsSecretAccessKey, bucket, prefix string) (*S3Sink, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("audit log bucket is not set")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	config := &aws.Config{
+		Region: aws.String(region),
+	}
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+		config.S3ForcePathStyle = aws.Bool(true)
+	}
+	if awsAccessKeyId != "" && awsSecretAccessKey != "" {
+		config.Credentials = credentials.NewStaticCredentials(awsAccessKeyId, awsSecretAccessKey, "")
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("create aws session in region %s: %v", region, err)
+	}
+	return &S3Sink{
+		svc:    s3.New(sess),
+		bucket: bucket,
+		prefix: prefix,
+	}, nil
+}
+
+func (sink *S3Sink) Write(data []byte) error {
+	key := fmt.Sprintf("%s%s-%016X.log", sink.prefix, time.Now().UTC().Format("2006/01/02/15-04-05"), rand.Uint64())
+	_, err := sink.svc.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(sink.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/x-ndjson"),
+	})
+	if err != nil {
+		return fmt.Errorf("put %s/%s: %v", sink.bucket, key, err)
+	}
+	return nil
+}
+
+func (sink *S3Sink) Close() error {
+	return nil
+}
+
+func (sink *S3Sink) String() string {
+	return "s3 bucket " + sink.bucket
+}
@@ -6,8 +6,10 @@ import (
 	"strings"
 	"time"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/chrislusf/seaweedfs/weed/filer2"
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/pb"
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
@@ -30,11 +32,15 @@ type FilerOptions struct {
 	defaultReplicaPlacement *string
 	disableDirListing       *bool
 	maxMB                   *int
+	saveToFilerLimit        *int
 	dirListingLimit         *int
 	dataCenter              *string
 	enableNotification      *bool
 	disableHttp             *bool
 	cipher                  *bool
+	maxFilenameLength       *int
+	maxPathLength           *int
+	peers                   *string
 
 	// default leveldb directory, used in "weed server" mode
 	defaultLevelDbDirectory *string
@@ -50,11 +56,15 @@ func init() {
 	f.publicPort = cmdFiler.Flag.Int("port.readonly", 0, "readonly port opened to public")
 	f.defaultReplicaPlacement = cmdFiler.Flag.String("defaultReplicaPlacement", "000", "default replication type if not specified")
 	f.disableDirListing = cmdFiler.Flag.Bool("disableDirListing", false, "turn off directory listing")
-	f.maxMB = cmdFiler.Flag.Int("maxMB", 32, "split files larger than the limit")
+	f.maxMB = cmdFiler.Flag.Int("maxMB", 32, "split files larger than the limit, at most 256")
+	f.saveToFilerLimit = cmdFiler.Flag.Int("saveToFilerLimit", 0, "files up to this many bytes are saved inside the filer entries, instead of on the volume servers")
 	f.dirListingLimit = cmdFiler.Flag.Int("dirListLimit", 100000, "limit sub dir listing size")
 	f.dataCenter = cmdFiler.Flag.String("dataCenter", "", "prefer to write to volumes in this data center")
 	f.disableHttp = cmdFiler.Flag.Bool("disableHttp", false, "disable http request, only gRpc operations are allowed")
 	f.cipher = cmdFiler.Flag.Bool("encryptVolumeData", false, "encrypt data on volume servers")
+	f.maxFilenameLength = cmdFiler.Flag.Int("maxFilenameLength", filer2.DefaultMaxFilenameLength, "maximum bytes of a file name, 0 for unlimited")
+	f.maxPathLength = cmdFiler.Flag.Int("maxPathLength", filer2.DefaultMaxPathLength, "maximum bytes of a full path, 0 for unlimited")
+	f.peers = cmdFiler.Flag.String("peers", "", "other filers sharing the same filer store, in comma separated ip:port list, to follow their path policy updates")
 }
 
 var cmdFiler = &Command{
@@ -68,6 +78,8 @@ var cmdFiler = &Command{
 	GET /path/to/file
 	//create or overwrite the file, the filename in the multipart request will be used
 	POST /path/to/
+	//split the file into chunks of this many bytes, instead of the -maxMB default
+	POST /path/to/file -H "X-Seaweed-Chunk-Size: 4194304"
 	//return a json format subdirectory and files listing
 	GET /path/to/
 
@@ -107,6 +119,7 @@ func (fo *FilerOptions) startFiler() {
 		DefaultReplication: *fo.defaultReplicaPlacement,
 		DisableDirListing:  *fo.disableDirListing,
 		MaxMB:              *fo.maxMB,
+		SaveToFilerLimit:   *fo.saveToFilerLimit,
 		DirListingLimit:    *fo.dirListingLimit,
 		DataCenter:         *fo.dataCenter,
 		DefaultLevelDbDir:  defaultLevelDbDirectory,
@@ -114,6 +127,9 @@ func (fo *FilerOptions) startFiler() {
 		Host:               *fo.ip,
 		Port:               uint32(*fo.port),
 		Cipher:             *fo.cipher,
+		MaxFilenameLength:  *fo.maxFilenameLength,
+		MaxPathLength:      *fo.maxPathLength,
+		Peers:              strings.Split(*fo.peers, ","),
 	})
 	if nfs_err != nil {
 		glog.Fatalf("Filer startup error: %v", nfs_err)
@@ -148,7 +164,7 @@ func (fo *FilerOptions) startFiler() {
 	if err != nil {
 		glog.Fatalf("failed to listen on grpc port %d: %v", grpcPort, err)
 	}
-	grpcS := pb.NewGrpcServer(security.LoadServerTLS(util.GetViper(), "grpc.filer"))
+	grpcS := pb.NewGrpcServer(security.LoadServerTLS(util.GetViper(), "grpc.filer"), grpc.UnaryInterceptor(weed_server.FilerAuditInterceptor))
 	filer_pb.RegisterSeaweedFilerServer(grpcS, fs)
 	reflection.Register(grpcS)
 	go grpcS.Serve(grpcL)
@@ -2,6 +2,7 @@ package command
 
 import (
 	"os"
+	"time"
 )
 
 type MountOptions struct {
@@ -13,14 +14,19 @@ type MountOptions struct {
 	replication                 *string
 	ttlSec                      *int
 	chunkSizeLimitMB            *int
+	writeBufferSizeMB           *int
+	writeFlushInterval          *time.Duration
 	cacheDir                    *string
 	cacheSizeMB                 *int64
+	readAhead                   *int
+	readAheadCacheMB            *int
 	dataCenter                  *string
 	allowOthers                 *bool
 	umaskString                 *string
 	nonempty                    *bool
 	outsideContainerClusterMode *bool
 	asyncMetaDataCaching        *bool
+	checkPermissions            *bool
 }
 
 var (
@@ -39,8 +45,12 @@ func init() {
 	mountOptions.replication = cmdMount.Flag.String("replication", "", "replication(e.g. 000, 001) to create to files. If empty, let filer decide.")
 	mountOptions.ttlSec = cmdMount.Flag.Int("ttl", 0, "file ttl in seconds")
 	mountOptions.chunkSizeLimitMB = cmdMount.Flag.Int("chunkSizeLimitMB", 16, "local write buffer size, also chunk large files")
+	mountOptions.writeBufferSizeMB = cmdMount.Flag.Int("writeBufferSizeMB", 0, "dirty data buffered per open file before uploading, coalescing small writes. 0 means the same as chunkSizeLimitMB")
+	mountOptions.writeFlushInterval = cmdMount.Flag.Duration("writeFlushInterval", 0, "upload and commit the data written earlier than this interval on files kept open, e.g. 30s, at least 1s. 0 disables the periodic flush")
 	mountOptions.cacheDir = cmdMount.Flag.String("cacheDir", os.TempDir(), "local cache directory for file chunks")
 	mountOptions.cacheSizeMB = cmdMount.Flag.Int64("cacheCapacityMB", 1000, "local cache capacity in MB (0 will disable cache)")
+	mountOptions.readAhead = cmdMount.Flag.Int("readAhead", 2, "number of chunks prefetched ahead of sequential reads, 0 disables the read ahead")
+	mountOptions.readAheadCacheMB = cmdMount.Flag.Int("readAheadCacheMB", 64, "limit of the prefetched data kept in memory per open file")
 	mountOptions.dataCenter = cmdMount.Flag.String("dataCenter", "", "prefer to write to the data center")
 	mountOptions.allowOthers = cmdMount.Flag.Bool("allowOthers", true, "allows other users to access the file system")
 	mountOptions.umaskString = cmdMount.Flag.String("umask", "022", "octal umask, e.g., 022, 0111")
@@ -48,6 +58,7 @@ func init() {
 	mountCpuProfile = cmdMount.Flag.String("cpuprofile", "", "cpu profile output file")
 	mountMemProfile = cmdMount.Flag.String("memprofile", "", "memory profile output file")
 	mountOptions.outsideContainerClusterMode = cmdMount.Flag.Bool("outsideContainerClusterMode", false, "allows other users to access the file system")
+	mountOptions.checkPermissions = cmdMount.Flag.Bool("checkPermissions", false, "check the unix permissions of the callers on open, create, remove, rename, chmod and chown, for mounts shared by multiple users with -allowOthers")
 	mountOptions.asyncMetaDataCaching = cmdMount.Flag.Bool("asyncMetaDataCaching", true, "async meta data caching. this feature will be permanent and this option will be removed.")
 }
 
@@ -72,5 +83,15 @@ var cmdMount = &Command{
     * All volume server containers are accessible through the same hostname or IP address as the filer.
     * All volume server container ports are open external to the cluster.
 
+  Writes are buffered in memory per open file, up to "writeBufferSizeMB", and uploaded as large chunks.
+  The buffered data is uploaded and the file is committed to the filer before close() and fsync() return,
+  and every "writeFlushInterval" for files kept open.
+  If the mount process or the machine crashes, the data written since the last close(), fsync(),
+  or periodic flush is lost, and the file keeps its last committed content.
+
+  When a file is read sequentially, the next "readAhead" chunks are fetched from the volume servers
+  in the background, up to "readAheadCacheMB" per open file. A random seek stops the read ahead
+  until the file is read sequentially again.
+
   `,
 }
@@ -82,6 +82,10 @@ func RunMount(option *MountOptions, umask os.FileMode) bool {
 		fmt.Printf("Please specify a reasonable buffer size.")
 		return false
 	}
+	if *option.writeFlushInterval != 0 && *option.writeFlushInterval < filesys.MinWriteFlushInterval {
+		fmt.Printf("Please specify a -writeFlushInterval of at least %v, or 0 to disable the periodic flush.", filesys.MinWriteFlushInterval)
+		return false
+	}
 
 	fuse.Unmount(dir)
 
@@ -155,8 +159,12 @@ func RunMount(option *MountOptions, umask os.FileMode) bool {
 		Replication:                 *option.replication,
 		TtlSec:                      int32(*option.ttlSec),
 		ChunkSizeLimit:              int64(chunkSizeLimitMB) * 1024 * 1024,
+		WriteBufferSize:             int64(*option.writeBufferSizeMB) * 1024 * 1024,
+		WriteFlushInterval:          *option.writeFlushInterval,
 		CacheDir:                    *option.cacheDir,
 		CacheSizeMB:                 *option.cacheSizeMB,
+		ReadAheadChunks:             *option.readAhead,
+		ReadAheadCacheSize:          int64(*option.readAheadCacheMB) * 1024 * 1024,
 		DataCenter:                  *option.dataCenter,
 		DirListCacheLimit:           *option.dirListCacheLimit,
 		EntryCacheTtl:               3 * time.Second,
@@ -168,6 +176,7 @@ func RunMount(option *MountOptions, umask os.FileMode) bool {
 		Umask:                       umask,
 		OutsideContainerClusterMode: *mountOptions.outsideContainerClusterMode,
 		AsyncMetaDataCaching:        *mountOptions.asyncMetaDataCaching,
+		CheckPermissions:            *mountOptions.checkPermissions,
 		Cipher:                      cipher,
 	})
 
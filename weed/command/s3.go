@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/chrislusf/seaweedfs/weed/audit"
 	"github.com/chrislusf/seaweedfs/weed/pb"
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
 	"github.com/chrislusf/seaweedfs/weed/security"
+	stats_collect "github.com/chrislusf/seaweedfs/weed/stats"
 
 	"github.com/gorilla/mux"
 
@@ -28,16 +31,49 @@ type S3Options struct {
 	domainName     *string
 	tlsPrivateKey  *string
 	tlsCertificate *string
+
+	lifecycleScanIntervalMinutes *int
+	lifecycleDeletesPerSecond    *int
+	uploadExpireAfterHours       *int
+	uploadCleanupIntervalMinutes *int
+	metricsAddress               *string
+	metricsIntervalSec           *int
+	msgBrokers                   *string
+	websitePort                  *int
+	websiteDomainName            *string
+	replicationConfig            *string
+	storageClassConfig           *string
+	batchJobConcurrency          *int
+	maxObjectSizeMB              *int64
+	multipartThresholdMB         *int64
+	allowRename                  *bool
+	region                       *string
 }
 
 func init() {
 	cmdS3.Run = runS3 // break init cycle
 	s3StandaloneOptions.filer = cmdS3.Flag.String("filer", "localhost:8888", "filer server address")
 	s3StandaloneOptions.port = cmdS3.Flag.Int("port", 8333, "s3 server http listen port")
-	s3StandaloneOptions.domainName = cmdS3.Flag.String("domainName", "", "suffix of the host name, {bucket}.{domainName}")
+	s3StandaloneOptions.domainName = cmdS3.Flag.String("domainName", "", "comma separated suffixes of the host names of the virtual-hosted-style requests, {bucket}.{domainName}")
 	s3StandaloneOptions.config = cmdS3.Flag.String("config", "", "path to the config file")
 	s3StandaloneOptions.tlsPrivateKey = cmdS3.Flag.String("key.file", "", "path to the TLS private key file")
 	s3StandaloneOptions.tlsCertificate = cmdS3.Flag.String("cert.file", "", "path to the TLS certificate file")
+	s3StandaloneOptions.lifecycleScanIntervalMinutes = cmdS3.Flag.Int("lifecycle.scanIntervalMinutes", 60, "minutes between bucket lifecycle scans, 0 to disable lifecycle expiration")
+	s3StandaloneOptions.lifecycleDeletesPerSecond = cmdS3.Flag.Int("lifecycle.deletesPerSecond", 100, "limit of deletions per second by bucket lifecycle scans, 0 for unlimited")
+	s3StandaloneOptions.uploadExpireAfterHours = cmdS3.Flag.Int("upload.expireAfterHours", 0, "hours after which the incomplete multipart uploads are aborted, 0 to only abort by bucket lifecycle rules")
+	s3StandaloneOptions.uploadCleanupIntervalMinutes = cmdS3.Flag.Int("upload.cleanupIntervalMinutes", 60, "minutes between the cleanups of the incomplete multipart uploads, 0 to disable")
+	s3StandaloneOptions.metricsAddress = cmdS3.Flag.String("metrics.address", "", "Prometheus gateway address")
+	s3StandaloneOptions.metricsIntervalSec = cmdS3.Flag.Int("metrics.intervalSeconds", 15, "Prometheus push interval in seconds")
+	s3StandaloneOptions.msgBrokers = cmdS3.Flag.String("msgBroker", "", "comma separated message broker addresses to publish bucket event notifications")
+	s3StandaloneOptions.websitePort = cmdS3.Flag.Int("website.port", 0, "http listen port of the bucket website endpoint, 0 to disable")
+	s3StandaloneOptions.websiteDomainName = cmdS3.Flag.String("website.domainName", "", "suffix of the website host name, {bucket}.{website.domainName}")
+	s3StandaloneOptions.replicationConfig = cmdS3.Flag.String("replication.config", "", "path to the config file of the bucket replication destinations")
+	s3StandaloneOptions.storageClassConfig = cmdS3.Flag.String("storageClass.config", "", "path to the config file mapping the storage classes to the storage tiers")
+	s3StandaloneOptions.batchJobConcurrency = cmdS3.Flag.Int("batchJob.concurrency", 8, "concurrent object operations of the batch jobs, 0 to disable the batch jobs")
+	s3StandaloneOptions.maxObjectSizeMB = cmdS3.Flag.Int64("object.maxSizeMB", 0, "max object size in MB, 0 for unlimited, can be overridden by bucket.limits")
+	s3StandaloneOptions.multipartThresholdMB = cmdS3.Flag.Int64("object.multipartThresholdMB", 0, "objects larger than this in MB must be uploaded in parts, 0 for unlimited, can be overridden by bucket.limits")
+	s3StandaloneOptions.allowRename = cmdS3.Flag.Bool("allowRename", false, "allow renaming objects and prefixes with the x-seaweedfs-rename header, a SeaweedFS extension")
+	s3StandaloneOptions.region = cmdS3.Flag.String("region", "", "the region of the s3 endpoint, only accepting the requests signed for it. Empty to accept any region, reporting us-east-1")
 }
 
 var cmdS3 = &Command{
@@ -105,6 +141,138 @@ var cmdS3 = &Command{
   ]
 }
 
+	Temporary credentials are issued by STS AssumeRole and AssumeRoleWithWebIdentity,
+	sent to the s3 endpoint, if the [s3.sts] key is set in security.toml.
+	The roles are defined in the same config.json file:
+
+{
+  "identities": [ ... ],
+  "roles": [
+    {
+      "name": "uploader",
+      "actions": [
+        "Write:bucket1"
+      ],
+      "trustedIdentities": [
+        "some_name"
+      ],
+      "trustedWebIdentities": [
+        {
+          "issuer": "https://accounts.example.com",
+          "audience": "seaweedfs"
+        },
+        {
+          "issuer": "https://sso.example.com/realms/main",
+          "conditions": [
+            { "claim": "groups", "values": [ "uploaders" ] }
+          ]
+        }
+      ],
+      "maxSessionDurationSeconds": 3600
+    }
+  ],
+  "webIdentityProviders": [
+    {
+      "issuer": "https://accounts.example.com",
+      "publicKey": "-----BEGIN PUBLIC KEY-----\n...\n-----END PUBLIC KEY-----\n"
+    },
+    {
+      "issuer": "https://sso.example.com/realms/main",
+      "clientIds": [ "seaweedfs" ]
+    }
+  ]
+}
+
+	A web identity provider without a signingKey or publicKey is an OpenID Connect provider.
+	Its signing keys are fetched from the jwksUri, or the one discovered at {issuer}/.well-known/openid-configuration,
+	and refreshed every jwksRefreshSeconds, or when a token is signed by a new key.
+	The clientIds are required for such a provider, since it issues the tokens to many clients.
+	The tokens must be signed by one of the keys, not expired, and for one of the clientIds, which are optional
+	for the providers with a signingKey or publicKey.
+
+	The buckets with a website configuration are served as static websites on -website.port,
+	for the host names {bucket}.{website.domainName}, or the host names same as the bucket names.
+
+	The bucket replication rules copy the objects to the destination buckets in the background.
+	The destination buckets are on other S3 endpoints, or on the filers of other SeaweedFS clusters,
+	listed in the -replication.config file, and named arn:aws:s3:::{bucket} in the replication rules:
+
+{
+  "destinations": [
+    {
+      "bucket": "backup",
+      "endpoint": "https://s3.us-west-2.amazonaws.com",
+      "region": "us-west-2",
+      "accessKey": "some_access_key",
+      "secretKey": "some_secret_key"
+    },
+    {
+      "bucket": "mirror",
+      "filer": "filer.other-cluster:8888"
+    }
+  ]
+}
+
+	The objects of the storage classes, given by the x-amz-storage-class header, are written
+	with the replication, ttl, data center and disk type of their tier in the -storageClass.config file.
+	The tiers can be overridden for some buckets. The classes without a tier are stored as STANDARD:
+
+{
+  "storageClasses": {
+    "REDUCED_REDUNDANCY": {
+      "replication": "000"
+    },
+    "INTELLIGENT_TIERING": {
+      "diskType": "ssd"
+    },
+    "GLACIER": {
+      "replication": "010",
+      "dataCenter": "dc-cold"
+    }
+  },
+  "buckets": {
+    "logs": {
+      "STANDARD_IA": {
+        "replication": "000",
+        "ttl": "30d"
+      }
+    }
+  }
+}
+
+	The batch jobs set the tags or the retention of many objects, or copy them, e.g. to change their
+	storage class. The objects are under a prefix of a bucket, or listed in a csv manifest object of bucket,key.
+	The jobs are created, listed, described and cancelled by the S3 Control job API at /v20180820/jobs,
+	run -batchJob.concurrency objects at a time, and resume after a restart:
+
+<CreateJobRequest>
+  <Operation>
+    <S3PutObjectTagging>
+      <TagSet><Tag><Key>project</Key><Value>archive</Value></Tag></TagSet>
+    </S3PutObjectTagging>
+  </Operation>
+  <ManifestGenerator>
+    <SourceBucket>arn:aws:s3:::bucket1</SourceBucket>
+    <Prefix>logs/2020/</Prefix>
+  </ManifestGenerator>
+  <Report>
+    <Enabled>true</Enabled>
+    <Bucket>arn:aws:s3:::reports</Bucket>
+    <Prefix>batch</Prefix>
+  </Report>
+</CreateJobRequest>
+
+	With -allowRename, a CopyObject request with the "x-seaweedfs-rename: true" header moves the source
+	object to the destination key of the same bucket, without copying the data. A source and destination
+	ending with "/" rename the prefix with all its objects. This is a SeaweedFS extension, not part of the
+	S3 API, and is not allowed on the versioned buckets, nor for the prefixes of the buckets with object lock.
+
+	With -region, the requests signed for another region are rejected with AuthorizationHeaderMalformed,
+	except GetBucketLocation signed for us-east-1. The region is returned by GetBucketLocation,
+	in the x-amz-bucket-region header and in the error responses, and CreateBucket only accepts
+	this region as the LocationConstraint. Without -region, any LocationConstraint is accepted,
+	kept with the bucket, and returned by GetBucketLocation.
+
 `,
 }
 
@@ -126,6 +294,9 @@ func (s3opt *S3Options) startS3Server() bool {
 
 	filerBucketsPath := "/buckets"
 
+	util.LoadConfiguration("audit", false)
+	audit.LoadConfiguration(util.GetViper(), "audit.")
+
 	grpcDialOption := security.LoadClientTLS(util.GetViper(), "grpc.client")
 
 	for {
@@ -149,18 +320,58 @@ func (s3opt *S3Options) startS3Server() bool {
 
 	router := mux.NewRouter().SkipClean(true)
 
-	_, s3ApiServer_err := s3api.NewS3ApiServer(router, &s3api.S3ApiServerOption{
+	var msgBrokers []string
+	if *s3opt.msgBrokers != "" {
+		msgBrokers = strings.Split(*s3opt.msgBrokers, ",")
+	}
+
+	s3ApiServer, s3ApiServer_err := s3api.NewS3ApiServer(router, &s3api.S3ApiServerOption{
 		Filer:            *s3opt.filer,
 		FilerGrpcAddress: filerGrpcAddress,
 		Config:           *s3opt.config,
 		DomainName:       *s3opt.domainName,
 		BucketsPath:      filerBucketsPath,
 		GrpcDialOption:   grpcDialOption,
+
+		LifecycleScanInterval:     time.Duration(*s3opt.lifecycleScanIntervalMinutes) * time.Minute,
+		LifecycleDeletesPerSecond: *s3opt.lifecycleDeletesPerSecond,
+		MultipartUploadTTL:        time.Duration(*s3opt.uploadExpireAfterHours) * time.Hour,
+		MultipartCleanupInterval:  time.Duration(*s3opt.uploadCleanupIntervalMinutes) * time.Minute,
+		MessageBrokers:            msgBrokers,
+		WebsiteDomainName:         *s3opt.websiteDomainName,
+		ReplicationConfig:         *s3opt.replicationConfig,
+		StorageClassConfig:        *s3opt.storageClassConfig,
+		BatchJobConcurrency:       *s3opt.batchJobConcurrency,
+		MaxObjectSize:             *s3opt.maxObjectSizeMB * 1024 * 1024,
+		MultipartThreshold:        *s3opt.multipartThresholdMB * 1024 * 1024,
+		AllowRename:               *s3opt.allowRename,
+		Region:                    *s3opt.region,
 	})
 	if s3ApiServer_err != nil {
 		glog.Fatalf("S3 API Server startup error: %v", s3ApiServer_err)
 	}
 
+	if *s3opt.metricsAddress != "" {
+		go stats_collect.LoopPushingMetric("s3", stats_collect.SourceName(uint32(*s3opt.port)), stats_collect.S3Gather,
+			func() (addr string, intervalSeconds int) {
+				return *s3opt.metricsAddress, *s3opt.metricsIntervalSec
+			})
+	}
+
+	if *s3opt.websitePort > 0 {
+		websiteAddress := fmt.Sprintf(":%d", *s3opt.websitePort)
+		websiteListener, err := util.NewListener(websiteAddress, time.Duration(10)*time.Second)
+		if err != nil {
+			glog.Fatalf("S3 website listener on %s error: %v", websiteAddress, err)
+		}
+		glog.V(0).Infof("Start Seaweed S3 website endpoint at http port %d", *s3opt.websitePort)
+		go func() {
+			if err := http.Serve(websiteListener, s3ApiServer.WebsiteHandler()); err != nil {
+				glog.Fatalf("S3 website endpoint fail to serve: %v", err)
+			}
+		}()
+	}
+
 	httpS := &http.Server{Handler: router}
 
 	listenAddress := fmt.Sprintf(":%d", *s3opt.port)
@@ -10,7 +10,7 @@ func init() {
 }
 
 var cmdScaffold = &Command{
-	UsageLine: "scaffold -config=[filer|notification|replication|security|master]",
+	UsageLine: "scaffold -config=[filer|notification|replication|security|master|audit]",
 	Short:     "generate basic configuration files",
 	Long: `Generate filer.toml with all possible configurations for you to customize.
 
@@ -27,7 +27,7 @@ var cmdScaffold = &Command{
 
 var (
 	outputPath = cmdScaffold.Flag.String("output", "", "if not empty, save the configuration file to this directory")
-	config     = cmdScaffold.Flag.String("config", "filer", "[filer|notification|replication|security|master] the configuration file to generate")
+	config     = cmdScaffold.Flag.String("config", "filer", "[filer|notification|replication|security|master|audit] the configuration file to generate")
 )
 
 func runScaffold(cmd *Command, args []string) bool {
@@ -44,6 +44,8 @@ func runScaffold(cmd *Command, args []string) bool {
 		content = SECURITY_TOML_EXAMPLE
 	case "master":
 		content = MASTER_TOML_EXAMPLE
+	case "audit":
+		content = AUDIT_TOML_EXAMPLE
 	}
 	if content == "" {
 		println("need a valid -config option")
@@ -80,6 +82,12 @@ buckets_fsync = [          # a list of buckets with all write requests fsync=tru
 	"important_bucket",
 	"should_always_fsync",
 ]
+# the files with a ttl are swept after they expire, by one of the filers sharing the filer store
+# set the interval to 0 to only delete the expired files when they are read or listed
+ttl_sweep_interval_seconds = 60
+ttl_sweep_entries_per_second = 100
+# the entries deleted per second by the throttled tree deletions, e.g. for the s3 buckets, 0 for unlimited
+delete_tree_entries_per_second = 1000
 
 ####################################################
 # The following are filer store options
@@ -94,11 +102,12 @@ dir = "."					# directory to store level db files
 [mysql]  # or tidb
 # CREATE TABLE IF NOT EXISTS filemeta (
 #   dirhash     BIGINT         COMMENT 'first 64 bits of MD5 hash value of directory field',
-#   name        VARCHAR(1000)  COMMENT 'directory or file name',
+#   name        VARCHAR(1000) BINARY COMMENT 'directory or file name',
 #   directory   TEXT           COMMENT 'full path to parent directory',
 #   meta        LONGBLOB,
 #   PRIMARY KEY (dirhash, name)
 # ) DEFAULT CHARSET=utf8;
+# the names are listed in the byte order with the binary collation
 
 enabled = false
 hostname = "localhost"
@@ -113,11 +122,12 @@ interpolateParams = false
 [postgres] # or cockroachdb
 # CREATE TABLE IF NOT EXISTS filemeta (
 #   dirhash     BIGINT,
-#   name        VARCHAR(65535),
+#   name        VARCHAR(65535) COLLATE "C",
 #   directory   VARCHAR(65535),
 #   meta        bytea,
 #   PRIMARY KEY (dirhash, name)
 # );
+# the names are listed in the byte order with the "C" collation
 enabled = false
 hostname = "localhost"
 port = 5432
@@ -169,6 +179,7 @@ servers = "localhost:2379"
 timeout = "3s"
 
 [mongodb]
+# the filer operations run in transactions when connected to a replica set or a sharded cluster
 enabled = false
 uri = "mongodb://localhost:27017"
 option_pool_size = 0
@@ -311,6 +322,12 @@ expires_after_seconds = 10           # seconds
 key = ""
 expires_after_seconds = 10           # seconds
 
+# the jwt signing key of the key value api of the filer, http://<filer>/.kv/<key>
+# the clients send a jwt signed by the key, in the "Authorization: Bearer <jwt>" header
+# the key value api is disabled without the key
+[jwt.filer_signing]
+key = ""
+
 # all grpc tls authentications are mutual
 # the values for the following ca, cert, and key are paths to the PERM files.
 # the host name is not checked, so the PERM files can be shared.
@@ -349,6 +366,17 @@ enabled = true
 cert = ""
 key  = ""
 
+# the master key of s3 server side encryption (SSE-S3), read by the s3 server.
+# it is a base64 encoded 256-bit key, and wraps the data key of each encrypted object.
+# the encrypted objects can not be read any more if this key is lost or changed.
+[s3.encryption]
+key = ""
+
+# the key signing the session tokens of the temporary credentials issued by STS AssumeRole, read by the s3 server.
+# the roles are defined in the s3 -config file. STS is disabled if the key is empty.
+[s3.sts]
+key = ""
+
 
 `
 
@@ -375,6 +403,15 @@ sleep_minutes = 17          # sleep minutes between each script execution
 [master.filer]
 default = "localhost:8888"    # used by maintenance scripts if the scripts needs to use fs related commands
 
+# erasure code the volumes in the background, once all replicas are readonly and not modified for a while
+# the shards are verified before the volume replicas are deleted
+# check the progress, pause or resume with http://<master>/ec/status, /ec/pause, /ec/resume
+[master.erasure_coding]
+enabled = false
+quiet_for = "24h"             # only encode volumes not modified for this long
+concurrency = 1               # number of volumes encoded at the same time
+check_interval_minutes = 17
+
 
 [master.sequencer]
 type = "memory"     # Choose [memory|etcd] type for storing the file id sequence
@@ -393,6 +430,7 @@ sequencer_etcd_urls = "http://127.0.0.1:2379"
 	region = "us-east-2"
 	bucket = "your_bucket_name"    # an existing bucket
 	endpoint = ""
+	read_cache_entries = 0         # the volume servers cache this number of small reads from the tiered volumes
 
 # create this number of logical volumes if no more writable volumes
 # count_x means how many copies of data.
@@ -405,6 +443,31 @@ copy_1 = 7                # create 1 x 7 = 7 actual volumes
 copy_2 = 6                # create 2 x 6 = 12 actual volumes
 copy_3 = 3                # create 3 x 3 = 9 actual volumes
 copy_other = 1            # create n x 1 = n actual volumes
+# how many logical volumes to grow when no volume is writable:
+#   default: the copy_x counts above, or the count asked by the client
+#   fixed: always the same count, preallocating ahead of the write bursts
+#   write_rate: more volumes while they fill up quickly, with a limit per minute
+#   capacity_watermark: as default, but one volume at a time when the free volume slots run low
+strategy = "default"
+
+[master.volume_growth.fixed]
+count = 7
+
+[master.volume_growth.write_rate]
+min_count = 1
+max_count = 16            # doubled from min_count while the previous growth was less than fast_fill_seconds ago
+fast_fill_seconds = 300
+max_per_minute = 32       # for all the collections together, 0 for unlimited
+
+[master.volume_growth.capacity_watermark]
+free_percent = 20         # of the volume slots of the disk type
+
+# the volumes are vacuumed every 15 minutes once their garbage ratio is above -garbageThreshold,
+# or above the threshold of their collection. Vacuum on demand with volume.vacuum in 'weed shell'.
+[master.vacuum]
+collection_garbage_thresholds = [
+#  "logs=0.1",            # <collection>=<garbage ratio>
+]
 
 # configuration flags for replication
 [master.replication]
@@ -413,6 +476,47 @@ copy_other = 1            # create n x 1 = n actual volumes
 # try to replicate to all available volumes. You should only use this option
 # if you are doing your own replication or periodic sync of volumes.
 treat_replication_as_minimums = false
+# by default, a volume is not created when its replicas can not be placed as the replication requires,
+# e.g., 010 with only one rack. With this option, the replicas are spread as much as the topology allows,
+# but never two copies on the same volume server.
+best_effort_placement = false
+
+`
 
+	AUDIT_TOML_EXAMPLE = `
+# A sample TOML config file for the SeaweedFS audit log
+# Used with "weed filer", "weed s3", or "weed server -filer -s3"
+# Put this file to one of the location, with descending priority
+#    ./audit.toml
+#    $HOME/.seaweedfs/audit.toml
+#    /etc/seaweedfs/audit.toml
+
+####################################################
+# audit log
+# record the creates, updates, deletes and renames of the filer and the s3 gateway,
+# with the identity, time, path and result, as one JSON object per line.
+# The records are written asynchronously, and dropped if more than queue_size are waiting.
+# A failed write is retried with backoff, keeping up to queue_size records meanwhile.
+# Only one of the sinks can be enabled.
+####################################################
+[audit]
+queue_size = 10000
+
+[audit.file]
+enabled = false
+path = "/var/log/seaweedfs/audit.log"
+max_size_mb = 100         # rotated to audit.log.1, audit.log.2, ... when reaching the size
+max_backups = 10          # the oldest rotated files beyond these are removed
+flush_seconds = 1
+
+[audit.s3]
+enabled = false
+endpoint = ""             # empty for AWS, or any S3 compatible endpoint, e.g. "http://localhost:8333"
+region = "us-east-1"
+aws_access_key_id     = ""        # if empty, loads from the shared credentials file (~/.aws/credentials).
+aws_secret_access_key = ""        # if empty, loads from the shared credentials file (~/.aws/credentials).
+bucket = "audit"          # an existing bucket
+prefix = "seaweedfs/"     # the objects are <prefix>YYYY/MM/DD/HH-MM-SS-<unique string>.log
+flush_seconds = 60        # one object for the records of these seconds, or 1MB
 `
 )
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chrislusf/seaweedfs/weed/filer2"
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/util"
 )
@@ -81,9 +82,13 @@ func init() {
 	filerOptions.publicPort = cmdServer.Flag.Int("filer.port.public", 0, "filer server public http listen port")
 	filerOptions.defaultReplicaPlacement = cmdServer.Flag.String("filer.defaultReplicaPlacement", "", "Default replication type if not specified during runtime.")
 	filerOptions.disableDirListing = cmdServer.Flag.Bool("filer.disableDirListing", false, "turn off directory listing")
-	filerOptions.maxMB = cmdServer.Flag.Int("filer.maxMB", 32, "split files larger than the limit")
+	filerOptions.maxMB = cmdServer.Flag.Int("filer.maxMB", 32, "split files larger than the limit, at most 256")
+	filerOptions.saveToFilerLimit = cmdServer.Flag.Int("filer.saveToFilerLimit", 0, "files up to this many bytes are saved inside the filer entries, instead of on the volume servers")
 	filerOptions.dirListingLimit = cmdServer.Flag.Int("filer.dirListLimit", 1000, "limit sub dir listing size")
 	filerOptions.cipher = cmdServer.Flag.Bool("filer.encryptVolumeData", false, "encrypt data on volume servers")
+	filerOptions.maxFilenameLength = cmdServer.Flag.Int("filer.maxFilenameLength", filer2.DefaultMaxFilenameLength, "maximum bytes of a file name, 0 for unlimited")
+	filerOptions.maxPathLength = cmdServer.Flag.Int("filer.maxPathLength", filer2.DefaultMaxPathLength, "maximum bytes of a full path, 0 for unlimited")
+	filerOptions.peers = cmdServer.Flag.String("filer.peers", "", "other filers sharing the same filer store, in comma separated ip:port list, to follow their path policy updates")
 
 	serverOptions.v.port = cmdServer.Flag.Int("volume.port", 8080, "volume server http listen port")
 	serverOptions.v.publicPort = cmdServer.Flag.Int("volume.port.public", 0, "volume server public port")
@@ -92,13 +97,39 @@ func init() {
 	serverOptions.v.readRedirect = cmdServer.Flag.Bool("volume.read.redirect", true, "Redirect moved or non-local volumes.")
 	serverOptions.v.compactionMBPerSecond = cmdServer.Flag.Int("volume.compactionMBps", 0, "limit compaction speed in mega bytes per second")
 	serverOptions.v.fileSizeLimitMB = cmdServer.Flag.Int("volume.fileSizeLimitMB", 256, "limit file size to avoid out of memory")
+	serverOptions.v.readMBPerSecond = cmdServer.Flag.Int("volume.readMBps", 0, "limit the read responses in mega bytes per second, 0 means no limit")
+	serverOptions.v.replicationMBPerSecond = cmdServer.Flag.Int("volume.replicationMBps", 0, "limit the replicated writes and volume copies in mega bytes per second, 0 means no limit")
+	serverOptions.v.scrubIntervalHours = cmdServer.Flag.Int("volume.scrub.intervalHours", 0, "verify the CRC of all the needles every these hours, and repair the corrupted needles from the replicas, 0 means no scrubbing")
+	serverOptions.v.scrubHours = cmdServer.Flag.String("volume.scrub.hours", "", "only scrub in these local hours, e.g. 1-5, or 22-4 across midnight. Any time if empty")
+	serverOptions.v.scrubMBPerSecond = cmdServer.Flag.Int("volume.scrubMBps", 8, "limit the scrub reads in mega bytes per second, 0 means no limit")
+	serverOptions.v.fsyncPolicy = cmdServer.Flag.String("volume.fsync", "os", "when to flush the writes to disk, [os|always|batch]")
+	serverOptions.v.fsyncBatchMs = cmdServer.Flag.Int("volume.fsync.batchMs", 100, "with -volume.fsync=batch, flush the written volumes every these milliseconds")
+	serverOptions.v.fsyncBatchWrites = cmdServer.Flag.Int("volume.fsync.batchWrites", 0, "with -volume.fsync=batch, also flush a volume once it has these unflushed writes, 0 means no limit")
+	serverOptions.v.dataFileIO = cmdServer.Flag.String("volume.dataFileIO", "buffered", "how to read the volume data files, [buffered|mmap]")
 	serverOptions.v.publicUrl = cmdServer.Flag.String("volume.publicUrl", "", "publicly accessible address")
+	serverOptions.v.diskType = cmdServer.Flag.String("volume.disk", "", "volume server's disk type, [hdd|ssd|<tag>], hdd by default")
 
 	s3Options.port = cmdServer.Flag.Int("s3.port", 8333, "s3 server http listen port")
-	s3Options.domainName = cmdServer.Flag.String("s3.domainName", "", "suffix of the host name, {bucket}.{domainName}")
+	s3Options.domainName = cmdServer.Flag.String("s3.domainName", "", "comma separated suffixes of the host names of the virtual-hosted-style requests, {bucket}.{domainName}")
 	s3Options.tlsPrivateKey = cmdServer.Flag.String("s3.key.file", "", "path to the TLS private key file")
 	s3Options.tlsCertificate = cmdServer.Flag.String("s3.cert.file", "", "path to the TLS certificate file")
 	s3Options.config = cmdServer.Flag.String("s3.config", "", "path to the config file")
+	s3Options.lifecycleScanIntervalMinutes = cmdServer.Flag.Int("s3.lifecycle.scanIntervalMinutes", 60, "minutes between bucket lifecycle scans, 0 to disable lifecycle expiration")
+	s3Options.lifecycleDeletesPerSecond = cmdServer.Flag.Int("s3.lifecycle.deletesPerSecond", 100, "limit of deletions per second by bucket lifecycle scans, 0 for unlimited")
+	s3Options.uploadExpireAfterHours = cmdServer.Flag.Int("s3.upload.expireAfterHours", 0, "hours after which the incomplete multipart uploads are aborted, 0 to only abort by bucket lifecycle rules")
+	s3Options.uploadCleanupIntervalMinutes = cmdServer.Flag.Int("s3.upload.cleanupIntervalMinutes", 60, "minutes between the cleanups of the incomplete multipart uploads, 0 to disable")
+	s3Options.metricsAddress = cmdServer.Flag.String("s3.metrics.address", "", "Prometheus gateway address")
+	s3Options.metricsIntervalSec = cmdServer.Flag.Int("s3.metrics.intervalSeconds", 15, "Prometheus push interval in seconds")
+	s3Options.msgBrokers = cmdServer.Flag.String("s3.msgBroker", "", "comma separated message broker addresses to publish bucket event notifications")
+	s3Options.websitePort = cmdServer.Flag.Int("s3.website.port", 0, "http listen port of the bucket website endpoint, 0 to disable")
+	s3Options.websiteDomainName = cmdServer.Flag.String("s3.website.domainName", "", "suffix of the website host name, {bucket}.{s3.website.domainName}")
+	s3Options.replicationConfig = cmdServer.Flag.String("s3.replication.config", "", "path to the config file of the bucket replication destinations")
+	s3Options.storageClassConfig = cmdServer.Flag.String("s3.storageClass.config", "", "path to the config file mapping the storage classes to the storage tiers")
+	s3Options.batchJobConcurrency = cmdServer.Flag.Int("s3.batchJob.concurrency", 8, "concurrent object operations of the batch jobs, 0 to disable the batch jobs")
+	s3Options.maxObjectSizeMB = cmdServer.Flag.Int64("s3.object.maxSizeMB", 0, "max object size in MB, 0 for unlimited, can be overridden by bucket.limits")
+	s3Options.multipartThresholdMB = cmdServer.Flag.Int64("s3.object.multipartThresholdMB", 0, "objects larger than this in MB must be uploaded in parts, 0 for unlimited, can be overridden by bucket.limits")
+	s3Options.allowRename = cmdServer.Flag.Bool("s3.allowRename", false, "allow renaming objects and prefixes with the x-seaweedfs-rename header, a SeaweedFS extension")
+	s3Options.region = cmdServer.Flag.String("s3.region", "", "the region of the s3 endpoint, only accepting the requests signed for it. Empty to accept any region, reporting us-east-1")
 
 	msgBrokerOptions.port = cmdServer.Flag.Int("msgBroker.port", 17777, "broker gRPC listen port")
 
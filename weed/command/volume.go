@@ -32,26 +32,36 @@ var (
 )
 
 type VolumeServerOptions struct {
-	port                  *int
-	publicPort            *int
-	folders               []string
-	folderMaxLimits       []int
-	ip                    *string
-	publicUrl             *string
-	bindIp                *string
-	masters               *string
-	pulseSeconds          *int
-	idleConnectionTimeout *int
-	dataCenter            *string
-	rack                  *string
-	whiteList             []string
-	indexType             *string
-	fixJpgOrientation     *bool
-	readRedirect          *bool
-	cpuProfile            *string
-	memProfile            *string
-	compactionMBPerSecond *int
-	fileSizeLimitMB       *int
+	port                   *int
+	publicPort             *int
+	folders                []string
+	folderMaxLimits        []int
+	ip                     *string
+	publicUrl              *string
+	bindIp                 *string
+	masters                *string
+	pulseSeconds           *int
+	idleConnectionTimeout  *int
+	dataCenter             *string
+	rack                   *string
+	diskType               *string
+	whiteList              []string
+	indexType              *string
+	fixJpgOrientation      *bool
+	readRedirect           *bool
+	cpuProfile             *string
+	memProfile             *string
+	compactionMBPerSecond  *int
+	fileSizeLimitMB        *int
+	readMBPerSecond        *int
+	replicationMBPerSecond *int
+	scrubIntervalHours     *int
+	scrubHours             *string
+	scrubMBPerSecond       *int
+	fsyncPolicy            *string
+	fsyncBatchMs           *int
+	fsyncBatchWrites       *int
+	dataFileIO             *string
 }
 
 func init() {
@@ -66,6 +76,7 @@ func init() {
 	v.idleConnectionTimeout = cmdVolume.Flag.Int("idleTimeout", 30, "connection idle seconds")
 	v.dataCenter = cmdVolume.Flag.String("dataCenter", "", "current volume server's data center name")
 	v.rack = cmdVolume.Flag.String("rack", "", "current volume server's rack name")
+	v.diskType = cmdVolume.Flag.String("disk", "", "current volume server's disk type, [hdd|ssd|<tag>], hdd by default")
 	v.indexType = cmdVolume.Flag.String("index", "memory", "Choose [memory|leveldb|leveldbMedium|leveldbLarge] mode for memory~performance balance.")
 	v.fixJpgOrientation = cmdVolume.Flag.Bool("images.fix.orientation", false, "Adjust jpg orientation when uploading.")
 	v.readRedirect = cmdVolume.Flag.Bool("read.redirect", true, "Redirect moved or non-local volumes.")
@@ -73,6 +84,15 @@ func init() {
 	v.memProfile = cmdVolume.Flag.String("memprofile", "", "memory profile output file")
 	v.compactionMBPerSecond = cmdVolume.Flag.Int("compactionMBps", 0, "limit background compaction or copying speed in mega bytes per second")
 	v.fileSizeLimitMB = cmdVolume.Flag.Int("fileSizeLimitMB", 256, "limit file size to avoid out of memory")
+	v.readMBPerSecond = cmdVolume.Flag.Int("readMBps", 0, "limit the read responses in mega bytes per second, 0 means no limit. Adjustable with http://<volume server>/admin/bandwidth")
+	v.replicationMBPerSecond = cmdVolume.Flag.Int("replicationMBps", 0, "limit the replicated writes and volume copies in mega bytes per second, 0 means no limit. Adjustable with http://<volume server>/admin/bandwidth")
+	v.scrubIntervalHours = cmdVolume.Flag.Int("scrub.intervalHours", 0, "verify the CRC of all the needles every these hours, and repair the corrupted needles from the replicas, 0 means no scrubbing")
+	v.scrubHours = cmdVolume.Flag.String("scrub.hours", "", "only scrub in these local hours, e.g. 1-5, or 22-4 across midnight. Any time if empty")
+	v.scrubMBPerSecond = cmdVolume.Flag.Int("scrubMBps", 8, "limit the scrub reads in mega bytes per second, 0 means no limit. Adjustable with http://<volume server>/admin/bandwidth")
+	v.fsyncPolicy = cmdVolume.Flag.String("fsync", "os", "when to flush the writes to disk, [os|always|batch]. os leaves it to the OS, always flushes each write before responding, batch flushes every fsync.batchMs or fsync.batchWrites")
+	v.fsyncBatchMs = cmdVolume.Flag.Int("fsync.batchMs", 100, "with -fsync=batch, flush the written volumes every these milliseconds")
+	v.fsyncBatchWrites = cmdVolume.Flag.Int("fsync.batchWrites", 0, "with -fsync=batch, also flush a volume once it has these unflushed writes, 0 means no limit")
+	v.dataFileIO = cmdVolume.Flag.String("dataFileIO", "buffered", "how to read the volume data files, [buffered|mmap]. mmap reads from memory mapped files, avoid it on network filesystems")
 }
 
 var cmdVolume = &Command{
@@ -161,11 +181,16 @@ func (v VolumeServerOptions) startVolumeServer(volumeFolders, maxVolumeCounts, v
 		*v.ip, *v.port, *v.publicUrl,
 		v.folders, v.folderMaxLimits,
 		volumeNeedleMapKind,
-		strings.Split(masters, ","), *v.pulseSeconds, *v.dataCenter, *v.rack,
+		strings.Split(masters, ","), *v.pulseSeconds, *v.dataCenter, *v.rack, *v.diskType,
 		v.whiteList,
 		*v.fixJpgOrientation, *v.readRedirect,
 		*v.compactionMBPerSecond,
 		*v.fileSizeLimitMB,
+		*v.readMBPerSecond,
+		*v.replicationMBPerSecond,
+		*v.scrubIntervalHours, *v.scrubHours, *v.scrubMBPerSecond,
+		*v.fsyncPolicy, *v.fsyncBatchMs, *v.fsyncBatchWrites,
+		*v.dataFileIO,
 	)
 
 	// starting grpc server
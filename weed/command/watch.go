@@ -6,6 +6,8 @@ import (
 	"io"
 	"time"
 
+	"google.golang.org/grpc"
+
 	"github.com/chrislusf/seaweedfs/weed/pb"
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
 	"github.com/chrislusf/seaweedfs/weed/security"
@@ -25,15 +27,27 @@ var cmdWatch = &Command{
 }
 
 var (
-	watchFiler  = cmdWatch.Flag.String("filer", "localhost:8888", "filer hostname:port")
-	watchTarget = cmdWatch.Flag.String("pathPrefix", "/", "path to a folder or file, or common prefix for the folders or files on filer")
-	watchStart  = cmdWatch.Flag.Duration("timeAgo", 0, "start time before now. \"300ms\", \"1.5h\" or \"2h45m\". Valid time units are \"ns\", \"us\" (or \"µs\"), \"ms\", \"s\", \"m\", \"h\"")
+	watchFiler      = cmdWatch.Flag.String("filer", "localhost:8888", "filer hostname:port")
+	watchTarget     = cmdWatch.Flag.String("pathPrefix", "/", "path to a folder or file, or common prefix for the folders or files on filer")
+	watchSubscriber = cmdWatch.Flag.String("subscriber", "", "a durable subscriber name. If set, the watch resumes after the last printed event when restarted")
+	watchStart      = cmdWatch.Flag.Duration("timeAgo", 0, "start time before now. \"300ms\", \"1.5h\" or \"2h45m\". Valid time units are \"ns\", \"us\" (or \"µs\"), \"ms\", \"s\", \"m\", \"h\"")
 )
 
 func runWatch(cmd *Command, args []string) bool {
 
 	grpcDialOption := security.LoadClientTLS(util.GetViper(), "grpc.client")
 
+	if *watchSubscriber != "" {
+		filerClient := &watchFilerClient{filer: *watchFiler, grpcDialOption: grpcDialOption}
+		subscriber := filer_pb.NewMetadataSubscriber(filerClient, *watchSubscriber, *watchTarget, time.Now().Add(-*watchStart).UnixNano())
+		watchErr := subscriber.Subscribe(context.Background(), func(resp *filer_pb.SubscribeMetadataResponse) error {
+			fmt.Printf("events: %+v\n", resp.EventNotification)
+			return subscriber.Ack(resp.TsNs)
+		})
+		fmt.Printf("watch %s: %v\n", *watchFiler, watchErr)
+		return true
+	}
+
 	watchErr := pb.WithFilerClient(*watchFiler, grpcDialOption, func(client filer_pb.SeaweedFilerClient) error {
 
 		stream, err := client.SubscribeMetadata(context.Background(), &filer_pb.SubscribeMetadataRequest{
@@ -63,3 +77,16 @@ func runWatch(cmd *Command, args []string) bool {
 
 	return true
 }
+
+type watchFilerClient struct {
+	filer          string
+	grpcDialOption grpc.DialOption
+}
+
+func (c *watchFilerClient) WithFilerClient(fn func(filer_pb.SeaweedFilerClient) error) error {
+	return pb.WithFilerClient(c.filer, c.grpcDialOption, fn)
+}
+
+func (c *watchFilerClient) AdjustedUrl(hostAndPort string) string {
+	return hostAndPort
+}
@@ -28,6 +28,10 @@ type TxOrDB interface {
 	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 }
 
+func (store *AbstractSqlStore) SupportsTransaction() bool {
+	return true
+}
+
 func (store *AbstractSqlStore) BeginTransaction(ctx context.Context) (context.Context, error) {
 	tx, err := store.DB.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelReadCommitted,
@@ -99,6 +103,74 @@ func (store *AbstractSqlStore) UpdateEntry(ctx context.Context, entry *filer2.En
 	return nil
 }
 
+// UpdateEntryIf checks the entry with its row locked. A missing entry has no row to lock, so two conditional
+// creates can both pass the check, and the later insert fails on the primary key. The loser checks the condition
+// again, with the row of the entry created by the winner locked.
+func (store *AbstractSqlStore) UpdateEntryIf(ctx context.Context, entry *filer2.Entry, condition filer2.EntryCondition) error {
+	for {
+		inserted := false
+		err := store.withLockedEntry(ctx, entry.FullPath, func(ctx context.Context, existing *filer2.Entry) error {
+			if err := condition(existing); err != nil {
+				return err
+			}
+			if existing == nil {
+				inserted = true
+				return store.InsertEntry(ctx, entry)
+			}
+			return store.UpdateEntry(ctx, entry)
+		})
+		if err != nil && inserted {
+			if _, findErr := store.FindEntry(ctx, entry.FullPath); findErr == nil {
+				continue
+			}
+		}
+		return err
+	}
+}
+
+func (store *AbstractSqlStore) DeleteEntryIf(ctx context.Context, fullpath util.FullPath, condition filer2.EntryCondition) error {
+	return store.withLockedEntry(ctx, fullpath, func(ctx context.Context, existing *filer2.Entry) error {
+		if err := condition(existing); err != nil {
+			return err
+		}
+		return store.DeleteEntry(ctx, fullpath)
+	})
+}
+
+// withLockedEntry runs fn in a transaction, with the row of the entry locked until the transaction ends
+func (store *AbstractSqlStore) withLockedEntry(ctx context.Context, fullpath util.FullPath, fn func(ctx context.Context, existing *filer2.Entry) error) (err error) {
+
+	if _, ok := ctx.Value("tx").(*sql.Tx); !ok {
+		if ctx, err = store.BeginTransaction(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				store.RollbackTransaction(ctx)
+				return
+			}
+			err = store.CommitTransaction(ctx)
+		}()
+	}
+
+	dir, name := fullpath.DirAndName()
+	row := store.getTxOrDB(ctx).QueryRowContext(ctx, store.SqlFind+" FOR UPDATE", util.HashStringToLong(dir), name, dir)
+	var data []byte
+	var existing *filer2.Entry
+	if scanErr := row.Scan(&data); scanErr == nil {
+		existing = &filer2.Entry{
+			FullPath: fullpath,
+		}
+		if err = existing.DecodeAttributesAndChunks(data); err != nil {
+			return fmt.Errorf("decode %s : %v", fullpath, err)
+		}
+	} else if scanErr != sql.ErrNoRows {
+		return fmt.Errorf("find %s: %v", fullpath, scanErr)
+	}
+
+	return fn(ctx, existing)
+}
+
 func (store *AbstractSqlStore) FindEntry(ctx context.Context, fullpath util.FullPath) (*filer2.Entry, error) {
 
 	dir, name := fullpath.DirAndName()
@@ -0,0 +1,47 @@
+package cassandra
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2/storetest"
+)
+
+// The tests run against a cassandra test container, e.g.
+//   docker run -d -p 9042:9042 cassandra:3
+//   SEAWEEDFS_TEST_CASSANDRA_HOSTS=localhost:9042 go test ./filer2/cassandra/
+
+func TestListConformance(t *testing.T) {
+	hosts := os.Getenv("SEAWEEDFS_TEST_CASSANDRA_HOSTS")
+	if hosts == "" {
+		t.Skip("SEAWEEDFS_TEST_CASSANDRA_HOSTS is not set")
+	}
+
+	// the keyspace and the table of README.txt
+	cluster := gocql.NewCluster(strings.Split(hosts, ",")...)
+	session, err := cluster.CreateSession()
+	if err != nil {
+		t.Fatalf("connect to %s: %v", hosts, err)
+	}
+	for _, statement := range []string{
+		"CREATE KEYSPACE IF NOT EXISTS seaweedfs WITH replication = {'class':'SimpleStrategy', 'replication_factor' : 1}",
+		"CREATE TABLE IF NOT EXISTS seaweedfs.filemeta (directory varchar, name varchar, meta blob, PRIMARY KEY (directory, name)) WITH CLUSTERING ORDER BY (name ASC)",
+	} {
+		if err = session.Query(statement).Exec(); err != nil {
+			session.Close()
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	session.Close()
+
+	store := &CassandraStore{}
+	if err = store.initialize("seaweedfs", strings.Split(hosts, ",")); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	defer store.Shutdown()
+
+	storetest.TestListDirectoryEntries(t, store)
+}
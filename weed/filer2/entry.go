@@ -36,9 +36,15 @@ type Entry struct {
 
 	// the following is for files
 	Chunks []*filer_pb.FileChunk `json:"chunks,omitempty"`
+
+	// small files can be saved here instead of in the chunks
+	Content []byte `json:"content,omitempty"`
 }
 
 func (entry *Entry) Size() uint64 {
+	if len(entry.Content) > 0 {
+		return uint64(len(entry.Content))
+	}
 	return TotalSize(entry.Chunks)
 }
 
@@ -60,6 +66,7 @@ func (entry *Entry) ToProtoEntry() *filer_pb.Entry {
 		Attributes:  EntryAttributeToPb(entry),
 		Chunks:      entry.Chunks,
 		Extended:    entry.Extended,
+		Content:     entry.Content,
 	}
 }
 
@@ -79,5 +86,6 @@ func FromPbEntry(dir string, entry *filer_pb.Entry) *Entry {
 		FullPath: util.NewFullPath(dir, entry.Name),
 		Attr:     PbToEntryAttribute(entry.Attributes),
 		Chunks:   entry.Chunks,
+		Content:  entry.Content,
 	}
 }
@@ -16,6 +16,7 @@ func (entry *Entry) EncodeAttributesAndChunks() ([]byte, error) {
 		Attributes: EntryAttributeToPb(entry),
 		Chunks:     entry.Chunks,
 		Extended:   entry.Extended,
+		Content:    entry.Content,
 	}
 	return proto.Marshal(message)
 }
@@ -34,6 +35,8 @@ func (entry *Entry) DecodeAttributesAndChunks(blob []byte) error {
 
 	entry.Chunks = message.Chunks
 
+	entry.Content = message.Content
+
 	return nil
 }
 
@@ -99,6 +102,10 @@ func EqualEntry(a, b *Entry) bool {
 		return false
 	}
 
+	if !bytes.Equal(a.Content, b.Content) {
+		return false
+	}
+
 	for i := 0; i < len(a.Chunks); i++ {
 		if !proto.Equal(a.Chunks[i], b.Chunks[i]) {
 			return false
@@ -115,6 +115,60 @@ func (store *EtcdStore) FindEntry(ctx context.Context, fullpath weed_util.FullPa
 	return entry, nil
 }
 
+// UpdateEntryIf puts the entry only if its revision is still the one checked by the condition, and retries otherwise
+func (store *EtcdStore) UpdateEntryIf(ctx context.Context, entry *filer2.Entry, condition filer2.EntryCondition) error {
+	key := string(genKey(entry.DirAndName()))
+
+	value, err := entry.EncodeAttributesAndChunks()
+	if err != nil {
+		return fmt.Errorf("encoding %s %+v: %v", entry.FullPath, entry.Attr, err)
+	}
+
+	return store.changeEntryIf(ctx, entry.FullPath, condition, clientv3.OpPut(key, string(value)))
+}
+
+// DeleteEntryIf deletes the entry only if its revision is still the one checked by the condition, and retries otherwise
+func (store *EtcdStore) DeleteEntryIf(ctx context.Context, fullpath weed_util.FullPath, condition filer2.EntryCondition) error {
+	key := string(genKey(fullpath.DirAndName()))
+
+	return store.changeEntryIf(ctx, fullpath, condition, clientv3.OpDelete(key))
+}
+
+func (store *EtcdStore) changeEntryIf(ctx context.Context, fullpath weed_util.FullPath, condition filer2.EntryCondition, op clientv3.Op) error {
+	key := string(genKey(fullpath.DirAndName()))
+
+	for {
+		resp, err := store.client.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("get %s : %v", fullpath, err)
+		}
+
+		// a missing key has the revision 0
+		var existing *filer2.Entry
+		var revision int64
+		if len(resp.Kvs) > 0 {
+			existing = &filer2.Entry{
+				FullPath: fullpath,
+			}
+			if err = existing.DecodeAttributesAndChunks(resp.Kvs[0].Value); err != nil {
+				return fmt.Errorf("decode %s : %v", fullpath, err)
+			}
+			revision = resp.Kvs[0].ModRevision
+		}
+		if err = condition(existing); err != nil {
+			return err
+		}
+
+		txnResp, err := store.client.Txn(ctx).If(clientv3.Compare(clientv3.ModRevision(key), "=", revision)).Then(op).Commit()
+		if err != nil {
+			return fmt.Errorf("change %s : %v", fullpath, err)
+		}
+		if txnResp.Succeeded {
+			return nil
+		}
+	}
+}
+
 func (store *EtcdStore) DeleteEntry(ctx context.Context, fullpath weed_util.FullPath) (err error) {
 	key := genKey(fullpath.DirAndName())
 
@@ -139,9 +193,13 @@ func (store *EtcdStore) ListDirectoryEntries(
 	ctx context.Context, fullpath weed_util.FullPath, startFileName string, inclusive bool, limit int,
 ) (entries []*filer2.Entry, err error) {
 	directoryPrefix := genDirectoryKeyPrefix(fullpath, "")
+	lastFileStart := genDirectoryKeyPrefix(fullpath, startFileName)
 
-	resp, err := store.client.Get(ctx, string(directoryPrefix),
-		clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+	// one more for the start file, if not inclusive
+	resp, err := store.client.Get(ctx, string(lastFileStart),
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(string(directoryPrefix))),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend),
+		clientv3.WithLimit(int64(limit)+1))
 	if err != nil {
 		return nil, fmt.Errorf("list %s : %v", fullpath, err)
 	}
@@ -0,0 +1,26 @@
+package etcd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2/storetest"
+)
+
+// The tests run against an etcd test container, e.g.
+//   docker run -d -p 2379:2379 quay.io/coreos/etcd:v3.3.15 etcd --listen-client-urls http://0.0.0.0:2379 --advertise-client-urls http://localhost:2379
+//   SEAWEEDFS_TEST_ETCD_SERVERS=localhost:2379 go test ./filer2/etcd/
+
+func TestListConformance(t *testing.T) {
+	servers := os.Getenv("SEAWEEDFS_TEST_ETCD_SERVERS")
+	if servers == "" {
+		t.Skip("SEAWEEDFS_TEST_ETCD_SERVERS is not set")
+	}
+	store := &EtcdStore{}
+	if err := store.initialize(servers, "3s"); err != nil {
+		t.Fatalf("connect %s: %v", servers, err)
+	}
+	defer store.Shutdown()
+
+	storetest.TestListDirectoryEntries(t, store)
+}
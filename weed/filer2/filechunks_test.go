@@ -258,7 +258,7 @@ func TestChunksReading(t *testing.T) {
 			Size:   50,
 			Expected: []*ChunkView{
 				{Offset: 25, Size: 25, FileId: "asdf", LogicOffset: 25},
-				{Offset: 0, Size: 25, FileId: "abc", LogicOffset: 50},
+				{Offset: 50, Size: 25, FileId: "abc", LogicOffset: 50},
 			},
 		},
 		// case 3: updates overwrite full chunks
@@ -301,7 +301,7 @@ func TestChunksReading(t *testing.T) {
 			Size:   220,
 			Expected: []*ChunkView{
 				{Offset: 0, Size: 200, FileId: "asdf", LogicOffset: 0},
-				{Offset: 0, Size: 20, FileId: "abc", LogicOffset: 200},
+				{Offset: 130, Size: 20, FileId: "abc", LogicOffset: 200},
 			},
 		},
 		// case 6: same updates
@@ -367,6 +367,39 @@ func TestChunksReading(t *testing.T) {
 				{Offset: 0, Size: 153578836 - 137269248, FileId: "1,114201d5bbdb", LogicOffset: 137269248},
 			},
 		},
+		// case 10: chunks of different sizes, e.g. from multipart upload parts
+		{
+			Chunks: []*filer_pb.FileChunk{
+				{Offset: 0, Size: 4096, FileId: "abc", Mtime: 1},
+				{Offset: 4096, Size: 100, FileId: "asdf", Mtime: 1},
+				{Offset: 4196, Size: 1024, FileId: "fsad", Mtime: 1},
+				{Offset: 5220, Size: 4096, FileId: "xxxx", Mtime: 1},
+			},
+			Offset: 4000,
+			Size:   1300,
+			Expected: []*ChunkView{
+				{Offset: 4000, Size: 96, FileId: "abc", LogicOffset: 4000},
+				{Offset: 0, Size: 100, FileId: "asdf", LogicOffset: 4096},
+				{Offset: 0, Size: 1024, FileId: "fsad", LogicOffset: 4196},
+				{Offset: 0, Size: 80, FileId: "xxxx", LogicOffset: 5220},
+			},
+		},
+		// case 11: the middle of a chunk is overwritten, the rest is read from the right place of the old chunk
+		{
+			Chunks: []*filer_pb.FileChunk{
+				{Offset: 0, Size: 4096, FileId: "abc", Mtime: 1},
+				{Offset: 4096, Size: 3000, FileId: "asdf", Mtime: 1},
+				{Offset: 4500, Size: 77, FileId: "fsad", Mtime: 2},
+			},
+			Offset: 4095,
+			Size:   1000,
+			Expected: []*ChunkView{
+				{Offset: 4095, Size: 1, FileId: "abc", LogicOffset: 4095},
+				{Offset: 0, Size: 404, FileId: "asdf", LogicOffset: 4096},
+				{Offset: 0, Size: 77, FileId: "fsad", LogicOffset: 4500},
+				{Offset: 481, Size: 518, FileId: "asdf", LogicOffset: 4577},
+			},
+		},
 	}
 
 	for i, testcase := range testcases {
@@ -418,3 +451,16 @@ func BenchmarkCompactFileChunks(b *testing.B) {
 		CompactFileChunks(chunks)
 	}
 }
+
+func TestFileSize(t *testing.T) {
+	chunks := []*filer_pb.FileChunk{
+		{Offset: 0, Size: 100, FileId: "abc", Mtime: 1},
+		{Offset: 100, Size: 50, FileId: "asdf", Mtime: 2},
+	}
+	if size := FileSize(&filer_pb.Entry{Chunks: chunks}); size != 150 {
+		t.Errorf("chunks size %d, expected 150", size)
+	}
+	if size := FileSize(&filer_pb.Entry{Content: []byte("hello")}); size != 5 {
+		t.Errorf("content size %d, expected 5", size)
+	}
+}
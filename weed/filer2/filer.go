@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
@@ -38,6 +39,16 @@ type Filer struct {
 	MetaLogBuffer       *log_buffer.LogBuffer
 	metaLogCollection   string
 	metaLogReplication  string
+	quotas              *FilerQuotas
+	pathPolicies        *filerPathPolicies
+	MaxFilenameLength   int
+	MaxPathLength       int
+	DeleteTreeLimiter   *util.RateLimiter
+
+	subscriberOffsetLock sync.Mutex
+	deleteTreeJobs       map[string]*DeleteTreeJob
+	deleteTreeJobsLock   sync.Mutex
+	unsortedListingOnce  sync.Once
 }
 
 func NewFiler(masters []string, grpcDialOption grpc.DialOption, filerHost string, filerGrpcPort uint32, collection string, replication string, notifyFn func()) *Filer {
@@ -46,6 +57,9 @@ func NewFiler(masters []string, grpcDialOption grpc.DialOption, filerHost string
 		MasterClient:        wdclient.NewMasterClient(grpcDialOption, "filer", filerHost, filerGrpcPort, masters),
 		fileIdDeletionQueue: util.NewUnboundedQueue(),
 		GrpcDialOption:      grpcDialOption,
+		MaxFilenameLength:   DefaultMaxFilenameLength,
+		MaxPathLength:       DefaultMaxPathLength,
+		deleteTreeJobs:      make(map[string]*DeleteTreeJob),
 	}
 	f.MetaLogBuffer = log_buffer.NewLogBuffer(time.Minute, f.logFlushFunc, notifyFn)
 	f.metaLogCollection = collection
@@ -85,11 +99,32 @@ func (f *Filer) RollbackTransaction(ctx context.Context) error {
 }
 
 func (f *Filer) CreateEntry(ctx context.Context, entry *Entry, o_excl bool) error {
+	return f.createEntry(ctx, entry, o_excl, nil)
+}
+
+type replacedKey struct{}
+
+// ReportingReplaced makes the creations of the entries set replaced to whether they overwrite an existing file
+func ReportingReplaced(ctx context.Context, replaced *bool) context.Context {
+	return context.WithValue(ctx, replacedKey{}, replaced)
+}
+
+func reportReplaced(ctx context.Context, oldEntry *Entry) {
+	if replaced, ok := ctx.Value(replacedKey{}).(*bool); ok {
+		*replaced = oldEntry != nil && !oldEntry.IsDirectory()
+	}
+}
+
+func (f *Filer) createEntry(ctx context.Context, entry *Entry, o_excl bool, condition EntryCondition) error {
 
 	if string(entry.FullPath) == "/" {
 		return nil
 	}
 
+	if err := f.CheckPath(entry.FullPath); err != nil {
+		return err
+	}
+
 	dirParts := strings.Split(string(entry.FullPath), "/")
 
 	// fmt.Printf("directory parts: %+v\n", dirParts)
@@ -172,10 +207,17 @@ func (f *Filer) CreateEntry(ctx context.Context, entry *Entry, o_excl bool) erro
 		}
 	*/
 
+	if condition != nil {
+		return f.createEntryIf(ctx, entry, condition)
+	}
+
 	oldEntry, _ := f.FindEntry(ctx, entry.FullPath)
 
 	glog.V(4).Infof("CreateEntry %s: old entry: %v exclusive:%v", entry.FullPath, oldEntry, o_excl)
 	if oldEntry == nil {
+		if err := f.checkQuota(ctx, nil, entry); err != nil {
+			return err
+		}
 		if err := f.store.InsertEntry(ctx, entry); err != nil {
 			glog.Errorf("insert entry %s: %v", entry.FullPath, err)
 			return fmt.Errorf("insert entry %s: %v", entry.FullPath, err)
@@ -187,14 +229,18 @@ func (f *Filer) CreateEntry(ctx context.Context, entry *Entry, o_excl bool) erro
 		}
 		if err := f.UpdateEntry(ctx, oldEntry, entry); err != nil {
 			glog.Errorf("update entry %s: %v", entry.FullPath, err)
+			if err == filer_pb.ErrQuotaExceeded {
+				return err
+			}
 			return fmt.Errorf("update entry %s: %v", entry.FullPath, err)
 		}
 	}
 
 	f.maybeAddBucket(entry)
 	f.NotifyUpdateEvent(oldEntry, entry, true)
+	reportReplaced(ctx, oldEntry)
 
-	f.deleteChunksIfNotNew(oldEntry, entry)
+	f.deleteChunksIfNotNew(ctx, oldEntry, entry)
 
 	glog.V(4).Infof("CreateEntry %s: created", entry.FullPath)
 
@@ -212,7 +258,15 @@ func (f *Filer) UpdateEntry(ctx context.Context, oldEntry, entry *Entry) (err er
 			return fmt.Errorf("existing %s is a file", entry.FullPath)
 		}
 	}
-	return f.store.UpdateEntry(ctx, entry)
+	if err = f.checkQuota(ctx, oldEntry, entry); err != nil {
+		return err
+	}
+	if err = f.store.UpdateEntry(ctx, entry); err != nil {
+		return err
+	}
+	// the bucket options could be changed
+	f.maybeAddBucket(entry)
+	return nil
 }
 
 func (f *Filer) FindEntry(ctx context.Context, p util.FullPath) (entry *Entry, err error) {
@@ -234,7 +288,7 @@ func (f *Filer) FindEntry(ctx context.Context, p util.FullPath) (entry *Entry, e
 	entry, err = f.store.FindEntry(ctx, p)
 	if entry != nil && entry.TtlSec > 0 {
 		if entry.Crtime.Add(time.Duration(entry.TtlSec) * time.Second).Before(time.Now()) {
-			f.store.DeleteEntry(ctx, p.Child(entry.Name()))
+			f.deleteExpiredEntry(ctx, entry)
 			return nil, filer_pb.ErrNotFound
 		}
 	}
@@ -264,11 +318,12 @@ func (f *Filer) doListDirectoryEntries(ctx context.Context, p util.FullPath, sta
 	if listErr != nil {
 		return listedEntries, expiredCount, "", listErr
 	}
+	listedEntries = f.inListingOrder(p, listedEntries, startFileName, inclusive)
 	for _, entry := range listedEntries {
 		lastFileName = entry.Name()
 		if entry.TtlSec > 0 {
 			if entry.Crtime.Add(time.Duration(entry.TtlSec) * time.Second).Before(time.Now()) {
-				f.store.DeleteEntry(ctx, p.Child(entry.Name()))
+				f.deleteExpiredEntry(ctx, entry)
 				expiredCount++
 				continue
 			}
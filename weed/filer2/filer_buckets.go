@@ -9,11 +9,22 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
+// BucketAppendOnlyKey is the extended attribute of the bucket entry, to write the bucket files to append only volumes
+const BucketAppendOnlyKey = "append-only"
+
+// BucketMaxObjectSizeKey and BucketMultipartThresholdKey are the extended attributes of the bucket entry,
+// the s3 object size limits of the bucket in bytes, overriding the limits of the s3 server
+const (
+	BucketMaxObjectSizeKey      = "s3-max-object-size"
+	BucketMultipartThresholdKey = "s3-multipart-threshold"
+)
+
 type BucketName string
 type BucketOption struct {
 	Name        BucketName
 	Replication string
 	fsync       bool
+	appendOnly  bool
 }
 type FilerBuckets struct {
 	dirBucketsPath string
@@ -36,21 +47,11 @@ func (f *Filer) LoadBuckets() {
 		return
 	}
 
-	shouldFsyncMap := make(map[string]bool)
-	for _, bucket := range f.FsyncBuckets {
-		shouldFsyncMap[bucket] = true
-	}
-
 	glog.V(1).Infof("buckets found: %d", len(entries))
 
 	f.buckets.Lock()
 	for _, entry := range entries {
-		_, shouldFsnyc := shouldFsyncMap[entry.Name()]
-		f.buckets.buckets[BucketName(entry.Name())] = &BucketOption{
-			Name:        BucketName(entry.Name()),
-			Replication: entry.Replication,
-			fsync:       shouldFsnyc,
-		}
+		f.buckets.buckets[BucketName(entry.Name())] = f.newBucketOption(entry)
 	}
 	f.buckets.Unlock()
 
@@ -96,10 +97,34 @@ func (f *Filer) maybeAddBucket(entry *Entry) {
 	if parent != f.DirBucketsPath {
 		return
 	}
-	f.addBucket(dirName, &BucketOption{
-		Name:        BucketName(dirName),
+	f.addBucket(dirName, f.newBucketOption(entry))
+}
+
+func (f *Filer) newBucketOption(entry *Entry) *BucketOption {
+	shouldFsync := false
+	for _, bucket := range f.FsyncBuckets {
+		if bucket == entry.Name() {
+			shouldFsync = true
+		}
+	}
+	return &BucketOption{
+		Name:        BucketName(entry.Name()),
 		Replication: entry.Replication,
-	})
+		fsync:       shouldFsync,
+		appendOnly:  string(entry.Extended[BucketAppendOnlyKey]) == "true",
+	}
+}
+
+// IsBucketAppendOnly checks whether the files of the bucket are written to append only volumes
+func (f *Filer) IsBucketAppendOnly(buketName string) bool {
+
+	f.buckets.RLock()
+	defer f.buckets.RUnlock()
+
+	option, found := f.buckets.buckets[BucketName(buketName)]
+
+	return found && option.appendOnly
+
 }
 
 func (f *Filer) addBucket(buketName string, bucketOption *BucketOption) {
@@ -0,0 +1,106 @@
+package filer2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// A conditional change checks the current entry and changes it atomically, so a client can overwrite
+// or delete a file only if it is still the version the client has read, without losing concurrent updates.
+
+// CreateEntryIf creates or overwrites the file entry, only if the condition holds for the current entry
+func (f *Filer) CreateEntryIf(ctx context.Context, entry *Entry, condition EntryCondition) error {
+	return f.createEntry(ctx, entry, false, condition)
+}
+
+// createEntryIf saves the entry once its parent directories exist, with the condition checked by the store
+func (f *Filer) createEntryIf(ctx context.Context, entry *Entry, condition EntryCondition) error {
+
+	var oldEntry *Entry
+	err := f.store.UpdateEntryIf(ctx, entry, func(existing *Entry) error {
+		if err := condition(existing); err != nil {
+			return err
+		}
+		if existing != nil && existing.IsDirectory() {
+			return fmt.Errorf("existing %s is a directory", entry.FullPath)
+		}
+		oldEntry = existing
+		return f.checkQuota(ctx, existing, entry)
+	})
+	if err != nil {
+		glog.V(3).Infof("CreateEntryIf %s: %v", entry.FullPath, err)
+		return err
+	}
+
+	f.maybeAddBucket(entry)
+	f.NotifyUpdateEvent(oldEntry, entry, true)
+	reportReplaced(ctx, oldEntry)
+
+	f.deleteChunksIfNotNew(ctx, oldEntry, entry)
+
+	return nil
+}
+
+// DeleteEntryIf deletes the file entry, only if the condition holds for it
+func (f *Filer) DeleteEntryIf(ctx context.Context, p util.FullPath, shouldDeleteChunks bool, condition EntryCondition) error {
+
+	var entry *Entry
+	err := f.store.DeleteEntryIf(ctx, p, func(existing *Entry) error {
+		if err := condition(existing); err != nil {
+			return err
+		}
+		if existing == nil {
+			return filer_pb.ErrNotFound
+		}
+		if existing.IsDirectory() {
+			return fmt.Errorf("%s is a directory", p)
+		}
+		entry = existing
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.NotifyUpdateEvent(entry, nil, shouldDeleteChunks)
+	if shouldDeleteChunks {
+		go f.DeleteChunks(entry.Chunks)
+	}
+	return nil
+}
+
+// ETagCondition is the condition of the If-Match and If-None-Match header values, or nil without any
+func ETagCondition(ifMatch, ifNoneMatch string) EntryCondition {
+	if ifMatch == "" && ifNoneMatch == "" {
+		return nil
+	}
+	return func(existing *Entry) error {
+		if ifMatch != "" && (existing == nil || !ETagMatches(ifMatch, ETagEntry(existing))) {
+			return filer_pb.ErrPreconditionFailed
+		}
+		if ifNoneMatch != "" && existing != nil && ETagMatches(ifNoneMatch, ETagEntry(existing)) {
+			return filer_pb.ErrPreconditionFailed
+		}
+		return nil
+	}
+}
+
+// ETagMatches checks an If-Match or If-None-Match header value, a list of quoted etags or "*"
+func ETagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		candidate = strings.TrimPrefix(candidate, "W/")
+		if strings.Trim(candidate, "\"") == etag {
+			return true
+		}
+	}
+	return false
+}
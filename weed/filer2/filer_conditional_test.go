@@ -0,0 +1,122 @@
+package filer2
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestConditionalCreateAndDelete(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	ctx := context.Background()
+
+	file := func(p string, md5 byte) *Entry {
+		return &Entry{FullPath: util.FullPath(p), Attr: Attr{Mode: 0644, Md5: []byte{md5}}}
+	}
+	if err := f.CreateEntry(ctx, file("/a/f", 1), false); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	tests := []struct {
+		entry       *Entry
+		ifMatch     string
+		ifNoneMatch string
+		expected    error
+	}{
+		{file("/a/f", 2), `"02"`, "", filer_pb.ErrPreconditionFailed},
+		{file("/a/f", 2), `"01"`, "", nil},
+		{file("/a/f", 3), `"01"`, "", filer_pb.ErrPreconditionFailed},
+		{file("/a/f", 3), `"00", "02"`, "", nil},
+		{file("/a/f", 4), "", "*", filer_pb.ErrPreconditionFailed},
+		{file("/a/g", 1), "", "*", nil},
+		{file("/a/h", 1), "*", "", filer_pb.ErrPreconditionFailed},
+	}
+	for _, test := range tests {
+		err := f.CreateEntryIf(ctx, test.entry, ETagCondition(test.ifMatch, test.ifNoneMatch))
+		if err != test.expected {
+			t.Errorf("create %s if-match %s if-none-match %s: %v, expecting %v", test.entry.FullPath, test.ifMatch, test.ifNoneMatch, err, test.expected)
+		}
+	}
+	if etag := ETagEntry(store.entries["/a/f"]); etag != "03" {
+		t.Errorf("etag of /a/f: %s", etag)
+	}
+	if _, found := store.entries["/a/h"]; found {
+		t.Errorf("/a/h is created")
+	}
+
+	if err := f.DeleteEntryIf(ctx, "/a/f", false, ETagCondition(`"02"`, "")); err != filer_pb.ErrPreconditionFailed {
+		t.Errorf("delete with a stale etag: %v", err)
+	}
+	if err := f.DeleteEntryIf(ctx, "/a/f", false, ETagCondition(`"03"`, "")); err != nil {
+		t.Errorf("delete: %v", err)
+	}
+	if _, found := store.entries["/a/f"]; found {
+		t.Errorf("/a/f is not deleted")
+	}
+	if err := f.DeleteEntryIf(ctx, "/a/f", false, ETagCondition(`"03"`, "")); err != filer_pb.ErrPreconditionFailed {
+		t.Errorf("delete a deleted entry: %v", err)
+	}
+	if err := f.DeleteEntryIf(ctx, "/a", false, ETagCondition("", `"00"`)); err == nil {
+		t.Errorf("conditionally deleted a directory")
+	}
+}
+
+func TestConcurrentConditionalCreates(t *testing.T) {
+	// the filer serializes the conditional changes for the store without them
+	store := &syncStore{crashingStore: crashingStore{entries: make(map[util.FullPath]*Entry)}}
+	f := NewFiler(nil, nil, "", 0, "", "", nil)
+	f.SetStore(store)
+	f.DisableDirectoryCache()
+	ctx := context.Background()
+
+	var created int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			entry := &Entry{FullPath: "/a/f", Attr: Attr{Mode: 0644, Md5: []byte{byte(i)}}}
+			if err := f.CreateEntryIf(ctx, entry, ETagCondition("", "*")); err == nil {
+				atomic.AddInt32(&created, 1)
+			} else if err != filer_pb.ErrPreconditionFailed {
+				t.Errorf("create: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if created != 1 {
+		t.Errorf("created %d times with If-None-Match", created)
+	}
+}
+
+func TestReportingReplaced(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+
+	create := func(p string, condition EntryCondition) bool {
+		var replaced bool
+		ctx := ReportingReplaced(context.Background(), &replaced)
+		entry := &Entry{FullPath: util.FullPath(p), Attr: Attr{Mode: 0644}}
+		var err error
+		if condition != nil {
+			err = f.CreateEntryIf(ctx, entry, condition)
+		} else {
+			err = f.CreateEntry(ctx, entry, false)
+		}
+		if err != nil {
+			t.Fatalf("create %s: %v", p, err)
+		}
+		return replaced
+	}
+	if create("/a/f", nil) || !create("/a/f", nil) {
+		t.Errorf("unconditional creation")
+	}
+	if create("/a/g", ETagCondition("", "*")) || !create("/a/g", ETagCondition("*", "")) {
+		t.Errorf("conditional creation")
+	}
+}
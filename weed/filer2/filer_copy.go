@@ -0,0 +1,160 @@
+package filer2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The copied entries share the chunks with the source entries, so copying only writes the metadata.
+// A chunk is referenced once by default. The extra references are counted in the entries under SystemChunkRefDir,
+// named by the file id, and the chunk is deleted from the volume servers only after the last reference is released.
+// The counts are changed with the conditional updates of the store, so the filers sharing a store with
+// conditional updates, such as the sql stores, do not lose the changes of each other. With the other stores,
+// the changes are only serialized within one filer, and only one filer should copy the entries.
+
+const chunkReferenceKey = "refs"
+
+var errChunkReferencesChanged = errors.New("chunk references changed")
+
+// CopyEntry creates the target file with the same chunks and attributes as the source file.
+// The extended attributes are not copied.
+func (f *Filer) CopyEntry(ctx context.Context, source, target util.FullPath) (*Entry, error) {
+
+	if source == target {
+		return nil, fmt.Errorf("copy %s to itself", source)
+	}
+
+	entry, err := f.FindEntry(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsDirectory() {
+		return nil, fmt.Errorf("copy %s: is a directory", source)
+	}
+
+	now := time.Now()
+	newEntry := &Entry{
+		FullPath: target,
+		Attr:     entry.Attr,
+		Chunks:   entry.Chunks,
+		Content:  entry.Content,
+	}
+	newEntry.Mtime = now
+	newEntry.Crtime = now
+
+	// the chunks already referenced by the replaced target keep their references
+	referenced := entry.Chunks
+	if oldEntry, _ := f.FindEntry(ctx, target); oldEntry != nil {
+		referenced = MinusChunks(entry.Chunks, oldEntry.Chunks)
+	}
+
+	// add the references first, so a failure in between only leaks the chunks
+	if err = f.addChunkReferences(ctx, referenced); err != nil {
+		return nil, err
+	}
+	if err = f.CreateEntry(ctx, newEntry, false); err != nil {
+		f.DeleteChunks(referenced)
+		return nil, err
+	}
+
+	return newEntry, nil
+}
+
+func (f *Filer) addChunkReferences(ctx context.Context, chunks []*filer_pb.FileChunk) error {
+
+	for i, chunk := range chunks {
+		fileId := chunk.GetFileIdString()
+		if _, err := f.changeChunkReferences(ctx, fileId, 1); err != nil {
+			f.releaseChunkReferences(ctx, chunks[:i])
+			return fmt.Errorf("add reference to chunk %s: %v", fileId, err)
+		}
+	}
+
+	return nil
+}
+
+// releaseChunkReferences returns the chunks not referenced by any other entries
+func (f *Filer) releaseChunkReferences(ctx context.Context, chunks []*filer_pb.FileChunk) (unreferenced []*filer_pb.FileChunk) {
+
+	for _, chunk := range chunks {
+		fileId := chunk.GetFileIdString()
+		refs, err := f.changeChunkReferences(ctx, fileId, -1)
+		if err != nil {
+			// keeping the chunk is safer than deleting the data of another entry
+			glog.Errorf("release reference to chunk %s: %v", fileId, err)
+			continue
+		}
+		if refs == 0 {
+			unreferenced = append(unreferenced, chunk)
+		}
+	}
+
+	return
+}
+
+// changeChunkReferences adds delta to the extra references of the chunk, and returns the references before the change.
+// The change is retried if the references are changed in between. Releasing a chunk without extra references changes nothing.
+func (f *Filer) changeChunkReferences(ctx context.Context, fileId string, delta int64) (refs int64, err error) {
+
+	p := util.NewFullPath(SystemChunkRefDir, fileId)
+	for {
+		if refs, err = f.loadChunkReferences(ctx, fileId); err != nil || refs+delta < 0 {
+			return refs, err
+		}
+
+		unchanged := func(existing *Entry) error {
+			if existingRefs, err := chunkReferencesOf(existing); err != nil || existingRefs != refs {
+				return errChunkReferencesChanged
+			}
+			return nil
+		}
+		if refs+delta == 0 {
+			err = f.DeleteEntryIf(ctx, p, false, unchanged)
+		} else {
+			now := time.Now()
+			err = f.CreateEntryIf(ctx, &Entry{
+				FullPath: p,
+				Attr: Attr{
+					Mtime:  now,
+					Crtime: now,
+					Mode:   os.FileMode(0644),
+					Uid:    OS_UID,
+					Gid:    OS_GID,
+				},
+				Extended: map[string][]byte{
+					chunkReferenceKey: []byte(strconv.FormatInt(refs+delta, 10)),
+				},
+			}, unchanged)
+		}
+		if err != errChunkReferencesChanged {
+			return refs, err
+		}
+	}
+}
+
+// loadChunkReferences returns the number of the extra references to the chunk
+func (f *Filer) loadChunkReferences(ctx context.Context, fileId string) (int64, error) {
+	entry, err := f.FindEntry(ctx, util.NewFullPath(SystemChunkRefDir, fileId))
+	if err == filer_pb.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return chunkReferencesOf(entry)
+}
+
+func chunkReferencesOf(entry *Entry) (int64, error) {
+	if entry == nil {
+		return 0, nil
+	}
+	return strconv.ParseInt(string(entry.Extended[chunkReferenceKey]), 10, 64)
+}
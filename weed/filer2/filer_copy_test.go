@@ -0,0 +1,194 @@
+package filer2
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestCopyEntrySharesChunks(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	ctx := context.Background()
+
+	chunks := []*filer_pb.FileChunk{
+		{FileId: "1,01", Offset: 0, Size: 10},
+		{FileId: "1,02", Offset: 10, Size: 10},
+	}
+	if err := f.CreateEntry(ctx, &Entry{FullPath: "/dir/a", Attr: Attr{Mode: 0644}, Chunks: chunks}, false); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if _, err := f.CopyEntry(ctx, "/dir/a", "/dir/b"); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	// copying again to the same target does not add more references
+	copied, err := f.CopyEntry(ctx, "/dir/a", "/dir/b")
+	if err != nil {
+		t.Fatalf("copy again: %v", err)
+	}
+	if len(copied.Chunks) != 2 || copied.Chunks[0].GetFileIdString() != "1,01" {
+		t.Errorf("unexpected copied chunks %v", copied.Chunks)
+	}
+	if refs, _ := f.loadChunkReferences(ctx, "1,01"); refs != 1 {
+		t.Errorf("expected 1 extra reference, got %d", refs)
+	}
+
+	// deleting the first entry keeps the chunks
+	if unreferenced := f.releaseChunkReferences(ctx, chunks); len(unreferenced) != 0 {
+		t.Errorf("shared chunks released: %v", unreferenced)
+	}
+	// deleting the second entry releases the chunks
+	if unreferenced := f.releaseChunkReferences(ctx, chunks); len(unreferenced) != 2 {
+		t.Errorf("expected 2 unreferenced chunks, got %v", unreferenced)
+	}
+	if children := listTree(store, SystemChunkRefDir); len(children) != 0 {
+		t.Errorf("chunk references are not removed: %v", children)
+	}
+
+	if _, err := f.CopyEntry(ctx, "/dir/a", "/dir/a"); err == nil {
+		t.Errorf("expected error copying to itself")
+	}
+	if _, err := f.CopyEntry(ctx, "/dir", "/dir2"); err == nil {
+		t.Errorf("expected error copying a directory")
+	}
+	if _, err := f.CopyEntry(ctx, "/dir/missing", "/dir/c"); err != filer_pb.ErrNotFound {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+// syncStore keeps the entries in memory for the concurrent changes, without the conditional changes
+type syncStore struct {
+	sync.Mutex
+	crashingStore
+}
+
+func (store *syncStore) InsertEntry(ctx context.Context, entry *Entry) error {
+	store.Lock()
+	defer store.Unlock()
+	return store.crashingStore.InsertEntry(ctx, entry)
+}
+func (store *syncStore) UpdateEntry(ctx context.Context, entry *Entry) error {
+	return store.InsertEntry(ctx, entry)
+}
+func (store *syncStore) FindEntry(ctx context.Context, p util.FullPath) (*Entry, error) {
+	store.Lock()
+	defer store.Unlock()
+	return store.crashingStore.FindEntry(ctx, p)
+}
+func (store *syncStore) DeleteEntry(ctx context.Context, p util.FullPath) error {
+	store.Lock()
+	defer store.Unlock()
+	return store.crashingStore.DeleteEntry(ctx, p)
+}
+
+// conditionalStore checks and changes the entries atomically, for the filers sharing it
+type conditionalStore struct {
+	syncStore
+}
+
+func (store *conditionalStore) UpdateEntryIf(ctx context.Context, entry *Entry, condition EntryCondition) error {
+	store.Lock()
+	defer store.Unlock()
+	existing, _ := store.crashingStore.FindEntry(ctx, entry.FullPath)
+	if err := condition(existing); err != nil {
+		return err
+	}
+	return store.crashingStore.InsertEntry(ctx, entry)
+}
+func (store *conditionalStore) DeleteEntryIf(ctx context.Context, p util.FullPath, condition EntryCondition) error {
+	store.Lock()
+	defer store.Unlock()
+	existing, _ := store.crashingStore.FindEntry(ctx, p)
+	if err := condition(existing); err != nil {
+		return err
+	}
+	return store.crashingStore.DeleteEntry(ctx, p)
+}
+
+func TestChunkReferencesOfFilersSharingStore(t *testing.T) {
+	store := &conditionalStore{syncStore{crashingStore: crashingStore{entries: make(map[util.FullPath]*Entry)}}}
+	var filers []*Filer
+	for i := 0; i < 2; i++ {
+		f := NewFiler(nil, nil, "", 0, "", "", nil)
+		f.SetStore(store)
+		f.DisableDirectoryCache()
+		filers = append(filers, f)
+	}
+	ctx := context.Background()
+	chunks := []*filer_pb.FileChunk{{FileId: "1,01", Size: 10}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(f *Filer) {
+			defer wg.Done()
+			if err := f.addChunkReferences(ctx, chunks); err != nil {
+				t.Errorf("add reference: %v", err)
+			}
+		}(filers[i%2])
+	}
+	wg.Wait()
+	if refs, _ := filers[0].loadChunkReferences(ctx, "1,01"); refs != 20 {
+		t.Fatalf("expected 20 extra references, got %d", refs)
+	}
+
+	var unreferenced int32
+	for i := 0; i < 21; i++ {
+		wg.Add(1)
+		go func(f *Filer) {
+			defer wg.Done()
+			atomic.AddInt32(&unreferenced, int32(len(f.releaseChunkReferences(ctx, chunks))))
+		}(filers[i%2])
+	}
+	wg.Wait()
+	if unreferenced != 1 {
+		t.Errorf("chunk is unreferenced %d times", unreferenced)
+	}
+}
+
+func TestOverwriteCopiedEntryKeepsSharedChunks(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	ctx := context.Background()
+
+	chunks := []*filer_pb.FileChunk{
+		{FileId: "1,01", Offset: 0, Size: 10, Mtime: 1},
+		{FileId: "1,02", Offset: 10, Size: 10, Mtime: 1},
+	}
+	if err := f.CreateEntry(ctx, &Entry{FullPath: "/dir/a", Attr: Attr{Mode: 0644}, Chunks: chunks}, false); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := f.CopyEntry(ctx, "/dir/a", "/dir/b"); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+
+	// the mount overwrites the first chunk, and the filer compacts it away, as CreateEntry of the grpc server
+	compacted, garbages := CompactFileChunks(append(chunks, &filer_pb.FileChunk{FileId: "1,03", Offset: 0, Size: 10, Mtime: 2}))
+	if len(garbages) != 1 || garbages[0].GetFileIdString() != "1,01" {
+		t.Fatalf("garbage chunks %v", garbages)
+	}
+	if err := f.CreateEntry(WithGarbageChunks(ctx, garbages), &Entry{FullPath: "/dir/a", Attr: Attr{Mode: 0644}, Chunks: compacted}, false); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+
+	var deleted []string
+	f.fileIdDeletionQueue.Consume(func(fileIds []string) {
+		deleted = append(deleted, fileIds...)
+	})
+	if len(deleted) != 0 {
+		t.Errorf("chunks of the copy deleted: %v", deleted)
+	}
+	copied, err := f.FindEntry(ctx, "/dir/b")
+	if err != nil || len(copied.Chunks) != 2 || copied.Chunks[0].GetFileIdString() != "1,01" {
+		t.Fatalf("copy %v: %v", copied, err)
+	}
+	// the copy holds the last reference of the overwritten chunk
+	if unreferenced := f.releaseChunkReferences(ctx, copied.Chunks); len(unreferenced) != 1 || unreferenced[0].GetFileIdString() != "1,01" {
+		t.Errorf("expected the overwritten chunk unreferenced, got %v", unreferenced)
+	}
+}
@@ -57,6 +57,7 @@ func (f *Filer) doBatchDeleteFolderMetaAndData(ctx context.Context, entry *Entry
 
 	lastFileName := ""
 	includeLastFile := false
+	var usage QuotaUsage
 	for {
 		entries, err := f.ListDirectoryEntries(ctx, entry.FullPath, lastFileName, includeLastFile, PaginationSize)
 		if err != nil {
@@ -78,6 +79,8 @@ func (f *Filer) doBatchDeleteFolderMetaAndData(ctx context.Context, entry *Entry
 				chunks = append(chunks, dirChunks...)
 			} else {
 				chunks = append(chunks, sub.Chunks...)
+				usage.Bytes += int64(sub.Size())
+				usage.Files++
 			}
 			if err != nil && !ignoreRecursiveError {
 				return nil, err
@@ -94,6 +97,7 @@ func (f *Filer) doBatchDeleteFolderMetaAndData(ctx context.Context, entry *Entry
 	if storeDeletionErr := f.store.DeleteFolderChildren(ctx, entry.FullPath); storeDeletionErr != nil {
 		return nil, fmt.Errorf("filer store delete: %v", storeDeletionErr)
 	}
+	f.removeFolderUsage(entry.FullPath, usage)
 
 	return chunks, nil
 }
@@ -0,0 +1,324 @@
+package filer2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// A tree deletion removes a directory and everything under it one entry at a time, the children before their directory.
+// The deletion of a non-empty directory is journaled under SystemDeleteDir before deleting anything,
+// and a journaled deletion left unfinished by a restart is resumed when the filer starts.
+// Since the children are deleted before their directory, the entries left under the path are exactly the ones still to be deleted.
+// The entries deleted per second by all the tree deletions are limited by DeleteTreeLimiter,
+// and the chunks of the deleted files are sent to the deletion queue in batches.
+
+const (
+	deleteJournalPathKey         = "path"
+	deleteJournalDeleteChunksKey = "deleteChunks"
+	deleteJournalIgnoreErrorKey  = "ignoreRecursiveError"
+	deleteTreeListSize           = 1024
+	deleteTreeChunkBatchSize     = 1024
+	deleteTreeJobRetention       = time.Hour
+)
+
+// DeleteTreeProgress is the progress of a tree deletion, polled by the clients
+type DeleteTreeProgress struct {
+	JobId          string `json:"jobId"`
+	Path           string `json:"path"`
+	DeletedEntries int64  `json:"deletedEntries"`
+	DeletedBytes   int64  `json:"deletedBytes"`
+	Done           bool   `json:"done"`
+	Error          string `json:"error,omitempty"`
+}
+
+type DeleteTreeJob struct {
+	id                   string
+	path                 util.FullPath
+	ignoreRecursiveError bool
+	shouldDeleteChunks   bool
+	journal              *Entry
+	cancel               context.CancelFunc
+	done                 chan struct{}
+
+	deletedEntries int64
+	deletedBytes   int64
+	chunks         []*filer_pb.FileChunk
+	err            error
+	finishedAt     time.Time
+}
+
+func (job *DeleteTreeJob) Id() string {
+	return job.id
+}
+
+// Cancel stops the deletion, leaving the entries not deleted yet
+func (job *DeleteTreeJob) Cancel() {
+	job.cancel()
+}
+
+// Wait returns the error of the deletion once finished
+func (job *DeleteTreeJob) Wait() error {
+	<-job.done
+	return job.err
+}
+
+func (job *DeleteTreeJob) Progress() DeleteTreeProgress {
+	progress := DeleteTreeProgress{
+		JobId:          job.id,
+		Path:           string(job.path),
+		DeletedEntries: atomic.LoadInt64(&job.deletedEntries),
+		DeletedBytes:   atomic.LoadInt64(&job.deletedBytes),
+	}
+	select {
+	case <-job.done:
+		progress.Done = true
+		if job.err != nil {
+			progress.Error = job.err.Error()
+		}
+	default:
+	}
+	return progress
+}
+
+// DeleteTree deletes the entry and all its children, and waits for the deletion to finish
+func (f *Filer) DeleteTree(ctx context.Context, p util.FullPath, isRecursive, ignoreRecursiveError, shouldDeleteChunks bool) error {
+	job, err := f.StartDeleteTree(ctx, p, isRecursive, ignoreRecursiveError, shouldDeleteChunks)
+	if err != nil {
+		return err
+	}
+	return job.Wait()
+}
+
+// StartDeleteTree starts deleting the entry and all its children in the background.
+// The returned job can be found by its id with FindDeleteTreeJob until an hour after it finishes.
+func (f *Filer) StartDeleteTree(ctx context.Context, p util.FullPath, isRecursive, ignoreRecursiveError, shouldDeleteChunks bool) (*DeleteTreeJob, error) {
+	if p == "/" {
+		return nil, fmt.Errorf("can not delete the root directory")
+	}
+
+	entry, err := f.FindEntry(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// the filers sharing the store share the journals, so the time alone may collide
+	id := fmt.Sprintf("%019d-%s", time.Now().UnixNano(), uuid.New().String())
+	var journal *Entry
+	if entry.IsDirectory() {
+		children, listErr := f.ListDirectoryEntries(ctx, p, "", false, 1)
+		if listErr != nil {
+			return nil, fmt.Errorf("list folder %s: %v", p, listErr)
+		}
+		if len(children) > 0 {
+			if !isRecursive {
+				return nil, fmt.Errorf("fail to delete non-empty folder: %s", p)
+			}
+			if journal, err = f.writeDeleteJournal(ctx, id, p, ignoreRecursiveError, shouldDeleteChunks); err != nil {
+				return nil, fmt.Errorf("journal deleting %s: %v", p, err)
+			}
+		}
+	}
+
+	return f.startDeleteTreeJob(id, entry, journal, ignoreRecursiveError, shouldDeleteChunks), nil
+}
+
+// FindDeleteTreeJob returns nil if the job is not found
+func (f *Filer) FindDeleteTreeJob(id string) *DeleteTreeJob {
+	f.deleteTreeJobsLock.Lock()
+	defer f.deleteTreeJobsLock.Unlock()
+	return f.deleteTreeJobs[id]
+}
+
+func (f *Filer) startDeleteTreeJob(id string, entry, journal *Entry, ignoreRecursiveError, shouldDeleteChunks bool) *DeleteTreeJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &DeleteTreeJob{
+		id:                   id,
+		path:                 entry.FullPath,
+		ignoreRecursiveError: ignoreRecursiveError,
+		shouldDeleteChunks:   shouldDeleteChunks,
+		journal:              journal,
+		cancel:               cancel,
+		done:                 make(chan struct{}),
+	}
+
+	f.deleteTreeJobsLock.Lock()
+	now := time.Now()
+	for jobId, oldJob := range f.deleteTreeJobs {
+		if !oldJob.finishedAt.IsZero() && now.Sub(oldJob.finishedAt) > deleteTreeJobRetention {
+			delete(f.deleteTreeJobs, jobId)
+		}
+	}
+	f.deleteTreeJobs[id] = job
+	f.deleteTreeJobsLock.Unlock()
+
+	go f.runDeleteTreeJob(ctx, job, entry)
+
+	return job
+}
+
+func (f *Filer) runDeleteTreeJob(ctx context.Context, job *DeleteTreeJob, entry *Entry) {
+
+	glog.V(1).Infof("deleting tree %s", job.path)
+
+	isCollection := f.isBucket(entry)
+	shouldDeleteChunks := job.shouldDeleteChunks && !isCollection
+
+	var err error
+	if entry.IsDirectory() {
+		err = f.deleteTreeChildren(ctx, job, entry.FullPath, shouldDeleteChunks)
+	}
+	if err == nil {
+		err = f.deleteTreeEntry(ctx, job, entry, shouldDeleteChunks)
+	}
+	f.flushDeleteTreeChunks(job)
+
+	if err == nil && isCollection {
+		collectionName := entry.Name()
+		f.doDeleteCollection(collectionName)
+		f.deleteBucket(collectionName)
+	}
+	if err != nil {
+		glog.V(0).Infof("delete tree %s: %v", job.path, err)
+		err = fmt.Errorf("delete tree %s: %v", job.path, err)
+	}
+
+	// a failed deletion keeps its journal, to be resumed when the filer restarts
+	if job.journal != nil && (err == nil || ctx.Err() != nil) {
+		if journalErr := f.doDeleteEntryMetaAndData(context.Background(), job.journal, false); journalErr != nil {
+			glog.V(0).Infof("remove delete journal %s: %v", job.journal.FullPath, journalErr)
+		}
+	}
+
+	f.deleteTreeJobsLock.Lock()
+	job.err = err
+	job.finishedAt = time.Now()
+	f.deleteTreeJobsLock.Unlock()
+	job.cancel()
+	close(job.done)
+
+	glog.V(1).Infof("deleted tree %s: %d entries, %d bytes", job.path, job.deletedEntries, job.deletedBytes)
+}
+
+func (f *Filer) deleteTreeChildren(ctx context.Context, job *DeleteTreeJob, dir util.FullPath, shouldDeleteChunks bool) error {
+
+	lastFileName := ""
+	for {
+		entries, err := f.ListDirectoryEntries(ctx, dir, lastFileName, false, deleteTreeListSize)
+		if err != nil {
+			return fmt.Errorf("list folder %s: %v", dir, err)
+		}
+
+		for _, sub := range entries {
+			lastFileName = sub.Name()
+			if sub.IsDirectory() {
+				err = f.deleteTreeChildren(ctx, job, sub.FullPath, shouldDeleteChunks)
+			}
+			if err == nil {
+				err = f.deleteTreeEntry(ctx, job, sub, shouldDeleteChunks)
+			}
+			if err != nil {
+				if ctx.Err() != nil || !job.ignoreRecursiveError {
+					return err
+				}
+				glog.V(0).Infof("delete %s: %v", sub.FullPath, err)
+				err = nil
+			}
+		}
+
+		if len(entries) < deleteTreeListSize {
+			return nil
+		}
+	}
+}
+
+func (f *Filer) deleteTreeEntry(ctx context.Context, job *DeleteTreeJob, entry *Entry, shouldDeleteChunks bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.DeleteTreeLimiter.Wait(1)
+
+	if err := f.doDeleteEntryMetaAndData(ctx, entry, shouldDeleteChunks); err != nil {
+		return fmt.Errorf("delete %s: %v", entry.FullPath, err)
+	}
+	atomic.AddInt64(&job.deletedEntries, 1)
+	atomic.AddInt64(&job.deletedBytes, int64(entry.Size()))
+
+	if shouldDeleteChunks {
+		job.chunks = append(job.chunks, entry.Chunks...)
+		if len(job.chunks) >= deleteTreeChunkBatchSize {
+			f.flushDeleteTreeChunks(job)
+		}
+	}
+	return nil
+}
+
+func (f *Filer) flushDeleteTreeChunks(job *DeleteTreeJob) {
+	f.DeleteChunks(job.chunks)
+	job.chunks = nil
+}
+
+func (f *Filer) writeDeleteJournal(ctx context.Context, id string, p util.FullPath, ignoreRecursiveError, shouldDeleteChunks bool) (*Entry, error) {
+	now := time.Now()
+	journal := &Entry{
+		FullPath: util.NewFullPath(SystemDeleteDir, id),
+		Attr: Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   os.FileMode(0644),
+			Uid:    OS_UID,
+			Gid:    OS_GID,
+		},
+		Extended: map[string][]byte{
+			deleteJournalPathKey:         []byte(p),
+			deleteJournalDeleteChunksKey: []byte(fmt.Sprint(shouldDeleteChunks)),
+			deleteJournalIgnoreErrorKey:  []byte(fmt.Sprint(ignoreRecursiveError)),
+		},
+	}
+	return journal, f.CreateEntry(ctx, journal, true)
+}
+
+// RecoverDeletes resumes the journaled tree deletions not finished before the filer stopped
+func (f *Filer) RecoverDeletes() {
+
+	ctx := context.Background()
+	journals, err := f.ListDirectoryEntries(ctx, SystemDeleteDir, "", false, PaginationSize)
+	if err != nil {
+		glog.V(0).Infof("list delete journals: %v", err)
+		return
+	}
+
+	for _, journal := range journals {
+		p := util.FullPath(journal.Extended[deleteJournalPathKey])
+		if p == "" {
+			glog.Errorf("invalid delete journal %s", journal.FullPath)
+			continue
+		}
+
+		entry, findErr := f.FindEntry(ctx, p)
+		if findErr == filer_pb.ErrNotFound {
+			if err = f.doDeleteEntryMetaAndData(ctx, journal, false); err != nil {
+				glog.V(0).Infof("remove delete journal %s: %v", journal.FullPath, err)
+			}
+			continue
+		}
+		if findErr != nil {
+			glog.Errorf("resume deleting %s: %v", p, findErr)
+			continue
+		}
+
+		glog.V(0).Infof("resume deleting %s", p)
+		f.startDeleteTreeJob(journal.Name(), entry, journal,
+			string(journal.Extended[deleteJournalIgnoreErrorKey]) == "true",
+			string(journal.Extended[deleteJournalDeleteChunksKey]) == "true")
+	}
+}
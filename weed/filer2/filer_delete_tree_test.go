@@ -0,0 +1,156 @@
+package filer2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestDeleteTree(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	createTestEntries(t, f, "/dir/a", "/dir/b/c", "/dir/b/d/e", "/dir/f/", "/other")
+	ctx := context.Background()
+
+	if err := f.DeleteTree(ctx, "/dir", false, false, true); err == nil {
+		t.Errorf("deleted a non-empty folder without recursive")
+	}
+
+	job, err := f.StartDeleteTree(ctx, "/dir", true, false, true)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err = job.Wait(); err != nil {
+		t.Fatalf("delete tree: %v", err)
+	}
+	if _, found := store.entries["/dir"]; found || len(listTree(store, "/dir")) > 0 {
+		t.Errorf("left %v", listTree(store, "/dir"))
+	}
+	if _, found := store.entries["/other"]; !found {
+		t.Errorf("deleted an entry outside of the tree")
+	}
+	if progress := f.FindDeleteTreeJob(job.Id()).Progress(); !progress.Done || progress.DeletedEntries != 7 || progress.Error != "" {
+		t.Errorf("progress %+v", progress)
+	}
+	if journals := listTree(store, SystemDeleteDir); len(journals) > 0 {
+		t.Errorf("journals left %v", journals)
+	}
+}
+
+func TestDeleteTreeResume(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	createTestEntries(t, f, "/dir/a", "/dir/b/c", "/dir/b/d/e")
+	ctx := context.Background()
+
+	// stopped after deleting the first entries
+	if _, err := f.writeDeleteJournal(ctx, "1", "/dir", false, true); err != nil {
+		t.Fatalf("journal: %v", err)
+	}
+	delete(store.entries, "/dir/a")
+	delete(store.entries, "/dir/b/c")
+
+	// restart
+	f = newTestRenameFiler(store)
+	f.RecoverDeletes()
+	job := f.FindDeleteTreeJob("1")
+	if job == nil {
+		t.Fatalf("deletion not resumed")
+	}
+	if err := job.Wait(); err != nil {
+		t.Fatalf("resumed deletion: %v", err)
+	}
+	if _, found := store.entries["/dir"]; found || len(listTree(store, "/dir")) > 0 {
+		t.Errorf("left %v", listTree(store, "/dir"))
+	}
+	if journals := listTree(store, SystemDeleteDir); len(journals) > 0 {
+		t.Errorf("journals left %v", journals)
+	}
+}
+
+func TestDeleteTreeCancel(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	for i := 0; i < 10; i++ {
+		createTestEntries(t, f, fmt.Sprintf("/dir/%d", i))
+	}
+	f.DeleteTreeLimiter = util.NewRateLimiter(2)
+
+	job, err := f.StartDeleteTree(context.Background(), "/dir", true, false, true)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	job.Cancel()
+	if err = job.Wait(); err == nil {
+		t.Errorf("canceled deletion finished without error")
+	}
+	if _, found := store.entries["/dir"]; !found || len(listTree(store, "/dir")) == 0 {
+		t.Errorf("deleted the whole tree")
+	}
+	if progress := job.Progress(); !progress.Done || progress.Error == "" {
+		t.Errorf("progress %+v", progress)
+	}
+}
+
+// failingDeleteStore fails deleting the entry at failAt
+type failingDeleteStore struct {
+	*crashingStore
+	failAt util.FullPath
+}
+
+func (store *failingDeleteStore) DeleteEntry(ctx context.Context, p util.FullPath) error {
+	if p == store.failAt {
+		return fmt.Errorf("simulated failure")
+	}
+	return store.crashingStore.DeleteEntry(ctx, p)
+}
+
+func TestDeleteTreeFailureKeepsJournal(t *testing.T) {
+	store := &failingDeleteStore{crashingStore: &crashingStore{entries: make(map[util.FullPath]*Entry)}, failAt: "/dir/b"}
+	f := NewFiler(nil, nil, "", 0, "", "", nil)
+	f.SetStore(store)
+	f.DisableDirectoryCache()
+	createTestEntries(t, f, "/dir/a", "/dir/b/c", "/other/d")
+	ctx := context.Background()
+
+	first, err := f.StartDeleteTree(ctx, "/dir", true, false, true)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	second, err := f.StartDeleteTree(ctx, "/other", true, false, true)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if first.Id() == second.Id() {
+		t.Errorf("same job id %s", first.Id())
+	}
+	if err = first.Wait(); err == nil {
+		t.Errorf("failed deletion finished without error")
+	}
+	if err = second.Wait(); err != nil {
+		t.Errorf("delete tree: %v", err)
+	}
+	if journals := listTree(store.crashingStore, SystemDeleteDir); len(journals) != 1 {
+		t.Fatalf("journals %v", journals)
+	}
+
+	// resumed after the restart
+	store.failAt = ""
+	f = NewFiler(nil, nil, "", 0, "", "", nil)
+	f.SetStore(store)
+	f.DisableDirectoryCache()
+	f.RecoverDeletes()
+	if job := f.FindDeleteTreeJob(first.Id()); job == nil {
+		t.Fatalf("deletion not resumed")
+	} else if err = job.Wait(); err != nil {
+		t.Fatalf("resumed deletion: %v", err)
+	}
+	if _, found := store.entries["/dir"]; found || len(listTree(store.crashingStore, "/dir")) > 0 {
+		t.Errorf("left %v", listTree(store.crashingStore, "/dir"))
+	}
+	if journals := listTree(store.crashingStore, SystemDeleteDir); len(journals) > 0 {
+		t.Errorf("journals left %v", journals)
+	}
+}
@@ -1,6 +1,7 @@
 package filer2
 
 import (
+	"context"
 	"time"
 
 	"github.com/chrislusf/seaweedfs/weed/glog"
@@ -68,8 +69,12 @@ func (f *Filer) loopProcessingDeletion() {
 	}
 }
 
+// DeleteChunks deletes the chunks not shared with any copied entries
 func (f *Filer) DeleteChunks(chunks []*filer_pb.FileChunk) {
-	for _, chunk := range chunks {
+	if len(chunks) == 0 {
+		return
+	}
+	for _, chunk := range f.releaseChunkReferences(context.Background(), chunks) {
 		f.fileIdDeletionQueue.EnQueue(chunk.GetFileIdString())
 	}
 }
@@ -80,24 +85,41 @@ func (f *Filer) DeleteFileByFileId(fileId string) {
 	f.fileIdDeletionQueue.EnQueue(fileId)
 }
 
-func (f *Filer) deleteChunksIfNotNew(oldEntry, newEntry *Entry) {
+type garbageChunksKey struct{}
+
+// WithGarbageChunks passes the chunks compacted away from the new entry, to be deleted along with the chunks
+// of the replaced entry. A chunk in both is deleted once, so the references of a chunk shared with
+// a copied entry are released once.
+func WithGarbageChunks(ctx context.Context, garbages []*filer_pb.FileChunk) context.Context {
+	return context.WithValue(ctx, garbageChunksKey{}, garbages)
+}
 
+// deleteChunksIfNotNew deletes the chunks of the old entry and the garbage chunks of the context,
+// which are not in the new entry
+func (f *Filer) deleteChunksIfNotNew(ctx context.Context, oldEntry, newEntry *Entry) {
+
+	garbages, _ := ctx.Value(garbageChunksKey{}).([]*filer_pb.FileChunk)
 	if oldEntry == nil {
+		f.DeleteChunks(garbages)
 		return
 	}
 	if newEntry == nil {
 		f.DeleteChunks(oldEntry.Chunks)
+		return
 	}
 
 	var toDelete []*filer_pb.FileChunk
-	newChunkIds := make(map[string]bool)
+	keptChunkIds := make(map[string]bool)
 	for _, newChunk := range newEntry.Chunks {
-		newChunkIds[newChunk.GetFileIdString()] = true
+		keptChunkIds[newChunk.GetFileIdString()] = true
 	}
 
-	for _, oldChunk := range oldEntry.Chunks {
-		if _, found := newChunkIds[oldChunk.GetFileIdString()]; !found {
-			toDelete = append(toDelete, oldChunk)
+	for _, chunks := range [][]*filer_pb.FileChunk{oldEntry.Chunks, garbages} {
+		for _, chunk := range chunks {
+			if fileId := chunk.GetFileIdString(); !keptChunkIds[fileId] {
+				keptChunkIds[fileId] = true
+				toDelete = append(toDelete, chunk)
+			}
 		}
 	}
 	f.DeleteChunks(toDelete)
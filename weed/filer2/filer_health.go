@@ -0,0 +1,31 @@
+package filer2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chrislusf/seaweedfs/weed/pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+)
+
+// PingMaster asks the current master for its configuration, failing when the filer is not connected to any master
+func (f *Filer) PingMaster(ctx context.Context) error {
+	master := f.MasterClient.GetMaster()
+	if master == "" {
+		return fmt.Errorf("not connected to any master")
+	}
+	return pb.WithMasterClient(master, f.GrpcDialOption, func(client master_pb.SeaweedClient) error {
+		_, err := client.GetMasterConfiguration(ctx, &master_pb.GetMasterConfigurationRequest{})
+		return err
+	})
+}
+
+// PingStore reads an entry from the filer store. The entry does not need to exist.
+func (f *Filer) PingStore(ctx context.Context) error {
+	_, err := f.store.FindEntry(ctx, SystemLogDir)
+	if err == filer_pb.ErrNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package filer2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The key value pairs of the applications are saved as small entries under KvDir,
+// so the keys can not clash with the other system entries. An expired pair is removed as any entry with a ttl.
+
+const (
+	KvDir            = SystemDir + "/kv"
+	KvMaxKeyLength   = 256
+	KvMaxValueLength = 64 * 1024
+)
+
+// ErrKvPath is returned by the generic file operations on KvDir, since the pairs are only served by the kv api,
+// which checks the jwt of the requests.
+var ErrKvPath = errors.New("the key value pairs are only accessible with the kv api")
+
+// CheckNotKvPath denies the paths under KvDir. With children, it also denies the parent directories of KvDir,
+// for the operations affecting the whole tree, such as the deletions and the renames.
+func CheckNotKvPath(p util.FullPath, withChildren bool) error {
+	path := strings.TrimSuffix(string(p), "/")
+	if path == KvDir || strings.HasPrefix(path, KvDir+"/") {
+		return ErrKvPath
+	}
+	if withChildren && strings.HasPrefix(KvDir, path+"/") {
+		return ErrKvPath
+	}
+	return nil
+}
+
+// CheckKvKey checks the key is a list of segments separated by "/", of letters, digits, and -_.
+func CheckKvKey(key string) error {
+	if key == "" || len(key) > KvMaxKeyLength {
+		return fmt.Errorf("key length should be between 1 and %d", KvMaxKeyLength)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid key %q", key)
+		}
+		for _, c := range segment {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+				return fmt.Errorf("invalid key %q, expecting letters, digits, and -_./", key)
+			}
+		}
+	}
+	return nil
+}
+
+func kvPath(key string) (util.FullPath, error) {
+	if err := CheckKvKey(key); err != nil {
+		return "", err
+	}
+	return util.FullPath(KvDir + "/" + key), nil
+}
+
+// KvGet returns filer_pb.ErrNotFound if the key does not exist or is expired
+func (f *Filer) KvGet(ctx context.Context, key string) ([]byte, error) {
+	p, err := kvPath(key)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := f.FindEntry(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || entry.IsDirectory() {
+		return nil, filer_pb.ErrNotFound
+	}
+	return entry.Content, nil
+}
+
+// KvPut saves the value of the key, which expires after ttlSec if positive
+func (f *Filer) KvPut(ctx context.Context, key string, value []byte, ttlSec int32) error {
+	p, err := kvPath(key)
+	if err != nil {
+		return err
+	}
+	if len(value) > KvMaxValueLength {
+		return fmt.Errorf("value of %d bytes is larger than %d bytes", len(value), KvMaxValueLength)
+	}
+	now := time.Now()
+	return f.CreateEntry(ctx, &Entry{
+		FullPath: p,
+		Attr: Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   os.FileMode(0600),
+			Uid:    OS_UID,
+			Gid:    OS_GID,
+			TtlSec: ttlSec,
+		},
+		Content: value,
+	}, false)
+}
+
+func (f *Filer) KvDelete(ctx context.Context, key string) error {
+	p, err := kvPath(key)
+	if err != nil {
+		return err
+	}
+	return f.DeleteEntryIf(ctx, p, false, func(existing *Entry) error {
+		return nil
+	})
+}
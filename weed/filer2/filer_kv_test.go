@@ -0,0 +1,92 @@
+package filer2
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestCheckKvKey(t *testing.T) {
+	for key, valid := range map[string]bool{
+		"config":                   true,
+		"app/v1.2/feature_flags-x": true,
+		"":                         false,
+		"/config":                  false,
+		"app//config":              false,
+		"app/../config":            false,
+		"app/config?":              false,
+		strings.Repeat("k", 257):   false,
+		strings.Repeat("k", 256):   true,
+		"ключ":                     false,
+		"app/./config":             false,
+		"trailing/":                false,
+	} {
+		if err := CheckKvKey(key); (err == nil) != valid {
+			t.Errorf("key %q: %v", key, err)
+		}
+	}
+}
+
+func TestCheckNotKvPath(t *testing.T) {
+	tests := []struct {
+		path         util.FullPath
+		withChildren bool
+		allowed      bool
+	}{
+		{KvDir, false, false},
+		{KvDir + "/", false, false},
+		{KvDir + "/app/config", false, false},
+		{SystemDir, false, true},
+		{SystemDir, true, false},
+		{"/", true, false},
+		{"/", false, true},
+		{KvDir + "2", true, true},
+		{"/buckets/b/object", true, true},
+	}
+	for _, test := range tests {
+		if err := CheckNotKvPath(test.path, test.withChildren); (err == nil) != test.allowed {
+			t.Errorf("path %s with children %v: %v", test.path, test.withChildren, err)
+		}
+	}
+}
+
+func TestKvPutGetDelete(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	ctx := context.Background()
+
+	if err := f.KvPut(ctx, "app/config", []byte("v1"), 0); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, found := store.entries[KvDir+"/app/config"]; !found {
+		t.Errorf("not saved under %s", KvDir)
+	}
+	if value, err := f.KvGet(ctx, "app/config"); err != nil || string(value) != "v1" {
+		t.Errorf("get: %q %v", value, err)
+	}
+	if _, err := f.KvGet(ctx, "app"); err != filer_pb.ErrNotFound {
+		t.Errorf("get a key prefix: %v", err)
+	}
+	if err := f.KvPut(ctx, "app/large", make([]byte, KvMaxValueLength+1), 0); err == nil {
+		t.Errorf("put a too large value")
+	}
+
+	if err := f.KvDelete(ctx, "app/config"); err != nil {
+		t.Errorf("delete: %v", err)
+	}
+	if _, err := f.KvGet(ctx, "app/config"); err != filer_pb.ErrNotFound {
+		t.Errorf("get a deleted key: %v", err)
+	}
+
+	if err := f.KvPut(ctx, "session", []byte("s"), 60); err != nil {
+		t.Fatalf("put with ttl: %v", err)
+	}
+	store.entries[KvDir+"/session"].Crtime = time.Now().Add(-time.Hour)
+	if _, err := f.KvGet(ctx, "session"); err != filer_pb.ErrNotFound {
+		t.Errorf("get an expired key: %v", err)
+	}
+}
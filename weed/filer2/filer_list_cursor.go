@@ -0,0 +1,91 @@
+package filer2
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// A ListCursor resumes a directory listing exactly where it stopped, even if the client reconnects to another filer.
+// The entries are listed in the lexicographic order of the bytes of their names, for all the filer stores,
+// so resuming after the last seen name neither skips nor repeats an entry, even if that entry was deleted since.
+type ListCursor struct {
+	Directory       string
+	LastFileName    string
+	IncludeLastFile bool
+}
+
+const listCursorVersion = 1
+
+// Encode returns the opaque cursor token given to the clients
+func (cursor ListCursor) Encode() string {
+	buf := make([]byte, 2, 2+binary.MaxVarintLen64+len(cursor.Directory)+len(cursor.LastFileName))
+	buf[0] = listCursorVersion
+	if cursor.IncludeLastFile {
+		buf[1] = 1
+	}
+	buf = buf[:2+binary.PutUvarint(buf[2:2+binary.MaxVarintLen64], uint64(len(cursor.Directory)))]
+	buf = append(buf, cursor.Directory...)
+	buf = append(buf, cursor.LastFileName...)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func ParseListCursor(token string) (cursor ListCursor, err error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) < 3 || buf[0] != listCursorVersion || buf[1] > 1 {
+		return cursor, fmt.Errorf("invalid list cursor %q", token)
+	}
+	dirLen, n := binary.Uvarint(buf[2:])
+	if n <= 0 || dirLen > uint64(len(buf)-2-n) {
+		return cursor, fmt.Errorf("invalid list cursor %q", token)
+	}
+	cursor.IncludeLastFile = buf[1] == 1
+	buf = buf[2+n:]
+	cursor.Directory, cursor.LastFileName = string(buf[:dirLen]), string(buf[dirLen:])
+	return cursor, nil
+}
+
+// inListingOrder sorts the entries of a store not listing them in the byte order of their names,
+// e.g. a sql table with a case insensitive collation, and drops the entries not after the start file.
+// Such a store may still skip entries across pages, its name column should have a binary collation.
+func (f *Filer) inListingOrder(p util.FullPath, entries []*Entry, startFileName string, inclusive bool) []*Entry {
+	if isInListingOrder(entries, startFileName, inclusive) {
+		return entries
+	}
+	f.unsortedListingOnce.Do(func() {
+		glog.Warningf("filer store %s lists %s out of the byte order of the names", f.store.GetName(), p)
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	var t []*Entry
+	for _, entry := range entries {
+		name := entry.Name()
+		if name < startFileName || name == startFileName && !inclusive {
+			continue
+		}
+		if len(t) > 0 && t[len(t)-1].Name() == name {
+			continue
+		}
+		t = append(t, entry)
+	}
+	return t
+}
+
+func isInListingOrder(entries []*Entry, startFileName string, inclusive bool) bool {
+	for i, entry := range entries {
+		name := entry.Name()
+		if name < startFileName || name == startFileName && !inclusive {
+			return false
+		}
+		if i > 0 && name <= entries[i-1].Name() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package filer2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestListCursor(t *testing.T) {
+	for _, cursor := range []ListCursor{
+		{Directory: "/", LastFileName: "a"},
+		{Directory: "/buckets/b1", LastFileName: "日本 x.txt", IncludeLastFile: true},
+		{Directory: "/dir", LastFileName: ""},
+	} {
+		parsed, err := ParseListCursor(cursor.Encode())
+		if err != nil || parsed != cursor {
+			t.Errorf("%+v: parsed %+v, %v", cursor, parsed, err)
+		}
+	}
+
+	for _, token := range []string{"", "not base64!", "AQ", "AgAB", "AQIB", "AQAK"} {
+		if cursor, err := ParseListCursor(token); err == nil {
+			t.Errorf("parsed %q: %+v", token, cursor)
+		}
+	}
+}
+
+func TestInListingOrder(t *testing.T) {
+	f := newTestRenameFiler(&crashingStore{})
+	entries := func(names ...string) (entries []*Entry) {
+		for _, name := range names {
+			entries = append(entries, &Entry{FullPath: util.NewFullPath("/dir", name)})
+		}
+		return
+	}
+	names := func(entries []*Entry) (names []string) {
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return
+	}
+
+	for _, test := range []struct {
+		listed    []string
+		start     string
+		inclusive bool
+		expected  []string
+	}{
+		{[]string{"B", "a", "b"}, "", false, []string{"B", "a", "b"}},
+		{[]string{"a", "B", "b"}, "", false, []string{"B", "a", "b"}},
+		{[]string{"a", "B", "b", "c"}, "a", false, []string{"b", "c"}},
+		{[]string{"a", "B", "b", "c"}, "a", true, []string{"a", "b", "c"}},
+		{[]string{"a", "a", "b"}, "", false, []string{"a", "b"}},
+	} {
+		if listed := names(f.inListingOrder("/dir", entries(test.listed...), test.start, test.inclusive)); fmt.Sprint(listed) != fmt.Sprint(test.expected) {
+			t.Errorf("%v from %q: %v, expecting %v", test.listed, test.start, listed, test.expected)
+		}
+	}
+}
@@ -16,6 +16,11 @@ import (
 )
 
 func (f *Filer) NotifyUpdateEvent(oldEntry, newEntry *Entry, deleteChunks bool) {
+	f.updateQuotaUsage(oldEntry, newEntry)
+	f.maybeUpdateQuota(oldEntry, newEntry)
+	f.maybeIndexTtl(oldEntry, newEntry)
+	f.maybeReloadPathPolicies(oldEntry, newEntry)
+
 	var fullpath string
 	if oldEntry != nil {
 		fullpath = string(oldEntry.FullPath)
@@ -0,0 +1,36 @@
+package filer2
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The names and the paths are limited in bytes, as in most file systems, so a name of multibyte
+// characters is rejected as a whole, instead of truncated by a store or a mount later.
+const (
+	DefaultMaxFilenameLength = 255
+	DefaultMaxPathLength     = 4096
+)
+
+// CheckPath rejects the paths of invalid UTF-8, or with a name or the whole path longer than the limits.
+// A limit of 0 is unlimited.
+func (f *Filer) CheckPath(p util.FullPath) error {
+	s := string(p)
+	if !utf8.ValidString(s) || strings.IndexByte(s, 0) >= 0 {
+		return filer_pb.ErrInvalidName
+	}
+	if f.MaxPathLength > 0 && len(s) > f.MaxPathLength {
+		return filer_pb.ErrNameTooLong
+	}
+	if f.MaxFilenameLength > 0 {
+		for _, name := range strings.Split(s, "/") {
+			if len(name) > f.MaxFilenameLength {
+				return filer_pb.ErrNameTooLong
+			}
+		}
+	}
+	return nil
+}
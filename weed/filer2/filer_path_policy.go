@@ -0,0 +1,183 @@
+package filer2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The path policies set the collection, replication, ttl and disk type of the files written under a path prefix,
+// when the writes do not specify them. The longest matching prefix wins.
+// The policies are saved as json in the content of PathPolicyFile, and reloaded when the file is updated,
+// by this filer or, followed through the metadata subscription, by a peer filer sharing the same store.
+
+const PathPolicyFile = SystemDir + "/path_policy.json"
+
+type PathPolicy struct {
+	Prefix      string `json:"prefix"`
+	Collection  string `json:"collection,omitempty"`
+	Replication string `json:"replication,omitempty"`
+	Ttl         string `json:"ttl,omitempty"`
+	DiskType    string `json:"diskType,omitempty"`
+}
+
+type PathPolicies struct {
+	Policies []*PathPolicy `json:"policies"`
+}
+
+type filerPathPolicies struct {
+	policies *PathPolicies
+	sync.RWMutex
+}
+
+func (p *PathPolicy) validate() error {
+	if !strings.HasPrefix(p.Prefix, "/") {
+		return fmt.Errorf("prefix %q should start with /", p.Prefix)
+	}
+	if p.Replication != "" {
+		if _, err := super_block.NewReplicaPlacementFromString(p.Replication); err != nil {
+			return fmt.Errorf("prefix %s replication %q: %v", p.Prefix, p.Replication, err)
+		}
+	}
+	if p.Ttl != "" {
+		if _, err := needle.ReadTTL(p.Ttl); err != nil {
+			return fmt.Errorf("prefix %s ttl %q: %v", p.Prefix, p.Ttl, err)
+		}
+	}
+	return nil
+}
+
+func ParsePathPolicies(data []byte) (*PathPolicies, error) {
+	policies := &PathPolicies{}
+	if len(data) == 0 {
+		return policies, nil
+	}
+	if err := json.Unmarshal(data, policies); err != nil {
+		return nil, fmt.Errorf("parse path policies: %v", err)
+	}
+	for _, p := range policies.Policies {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+func (policies *PathPolicies) Bytes() []byte {
+	data, _ := json.MarshalIndent(policies, "", "  ")
+	return data
+}
+
+// Match returns the policy with the longest prefix of the path, or nil
+func (policies *PathPolicies) Match(path string) (matched *PathPolicy) {
+	for _, p := range policies.Policies {
+		if strings.HasPrefix(path, p.Prefix) && (matched == nil || len(p.Prefix) > len(matched.Prefix)) {
+			matched = p
+		}
+	}
+	return
+}
+
+// Set adds the policy, or replaces the one with the same prefix
+func (policies *PathPolicies) Set(policy *PathPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	for i, p := range policies.Policies {
+		if p.Prefix == policy.Prefix {
+			policies.Policies[i] = policy
+			return nil
+		}
+	}
+	policies.Policies = append(policies.Policies, policy)
+	sort.Slice(policies.Policies, func(i, j int) bool {
+		return policies.Policies[i].Prefix < policies.Policies[j].Prefix
+	})
+	return nil
+}
+
+func (policies *PathPolicies) Delete(prefix string) (found bool) {
+	for i, p := range policies.Policies {
+		if p.Prefix == prefix {
+			policies.Policies = append(policies.Policies[:i], policies.Policies[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filer) LoadPathPolicies() {
+	f.pathPolicies = &filerPathPolicies{policies: &PathPolicies{}}
+
+	entry, err := f.FindEntry(context.Background(), util.FullPath(PathPolicyFile))
+	if err != nil || entry == nil {
+		glog.V(1).Infof("no path policies found: %v", err)
+		return
+	}
+	f.reloadPathPolicies(entry)
+}
+
+// ReloadPathPolicies reads PathPolicyFile again, after a peer filer updates or deletes it
+func (f *Filer) ReloadPathPolicies() {
+	if f.pathPolicies == nil {
+		return
+	}
+	entry, err := f.FindEntry(context.Background(), util.FullPath(PathPolicyFile))
+	if err != nil && err != filer_pb.ErrNotFound {
+		glog.Errorf("keep the current path policies: %v", err)
+		return
+	}
+	f.reloadPathPolicies(entry)
+}
+
+// MatchPathPolicy returns the policy of the path, or an empty policy if none matches.
+// The system files, including PathPolicyFile itself, are not subject to the policies.
+func (f *Filer) MatchPathPolicy(path string) PathPolicy {
+	if f.pathPolicies == nil || strings.HasPrefix(path, SystemDir+"/") {
+		return PathPolicy{}
+	}
+	f.pathPolicies.RLock()
+	defer f.pathPolicies.RUnlock()
+	if p := f.pathPolicies.policies.Match(path); p != nil {
+		return *p
+	}
+	return PathPolicy{}
+}
+
+func (f *Filer) reloadPathPolicies(entry *Entry) {
+	policies := &PathPolicies{}
+	if entry != nil {
+		var err error
+		if policies, err = ParsePathPolicies(entry.Content); err != nil {
+			glog.Errorf("keep the current path policies: %v", err)
+			return
+		}
+	}
+	f.pathPolicies.Lock()
+	f.pathPolicies.policies = policies
+	f.pathPolicies.Unlock()
+	glog.V(0).Infof("loaded %d path policies", len(policies.Policies))
+}
+
+// maybeReloadPathPolicies reloads the path policies when PathPolicyFile is updated or deleted
+func (f *Filer) maybeReloadPathPolicies(oldEntry, newEntry *Entry) {
+	if f.pathPolicies == nil {
+		return
+	}
+	if newEntry != nil && newEntry.FullPath == PathPolicyFile {
+		f.reloadPathPolicies(newEntry)
+		return
+	}
+	if oldEntry != nil && oldEntry.FullPath == PathPolicyFile {
+		f.reloadPathPolicies(nil)
+	}
+}
@@ -0,0 +1,88 @@
+package filer2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestPathPolicyMatch(t *testing.T) {
+	policies, err := ParsePathPolicies([]byte(`{"policies":[
+		{"prefix":"/logs/","replication":"000","ttl":"7d"},
+		{"prefix":"/logs/audit/","replication":"001"},
+		{"prefix":"/critical/","replication":"011","diskType":"ssd"}
+	]}`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		prefix string
+	}{
+		{"/logs/a.log", "/logs/"},
+		{"/logs/audit/a.log", "/logs/audit/"},
+		{"/critical/db/1", "/critical/"},
+		{"/logs", ""},
+		{"/other/a", ""},
+	}
+	for _, tt := range tests {
+		p := policies.Match(tt.path)
+		if (p == nil && tt.prefix != "") || (p != nil && p.Prefix != tt.prefix) {
+			t.Errorf("match %s: %+v, expect %q", tt.path, p, tt.prefix)
+		}
+	}
+
+	if err := policies.Set(&PathPolicy{Prefix: "/logs/", Ttl: "1h"}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if p := policies.Match("/logs/a.log"); p.Ttl != "1h" || p.Replication != "" {
+		t.Errorf("changed policy %+v", p)
+	}
+	if !policies.Delete("/logs/") || policies.Match("/logs/a.log") != nil {
+		t.Errorf("deleted policy still matches")
+	}
+
+	for _, invalid := range []string{
+		`{"policies":[{"prefix":"logs/"}]}`,
+		`{"policies":[{"prefix":"/logs/","replication":"abc"}]}`,
+		`{"policies":[{"prefix":"/logs/","ttl":"abc"}]}`,
+	} {
+		if _, err := ParsePathPolicies([]byte(invalid)); err == nil {
+			t.Errorf("parsed invalid policies %s", invalid)
+		}
+	}
+}
+
+func TestReloadPathPolicies(t *testing.T) {
+	store := &syncStore{crashingStore: crashingStore{entries: make(map[util.FullPath]*Entry)}}
+	f := NewFiler(nil, nil, "", 0, "", "", nil)
+	f.SetStore(store)
+	f.DisableDirectoryCache()
+	f.LoadPathPolicies()
+
+	// a peer filer sharing the store updates the policies
+	store.InsertEntry(context.Background(), &Entry{
+		FullPath: PathPolicyFile,
+		Attr:     Attr{Mode: 0644},
+		Content:  []byte(`{"policies":[{"prefix":"/logs/","replication":"001"}]}`),
+	})
+	f.ReloadPathPolicies()
+	if p := f.MatchPathPolicy("/logs/a.log"); p.Replication != "001" {
+		t.Errorf("reloaded policy %+v", p)
+	}
+
+	// invalid policies keep the current ones
+	store.InsertEntry(context.Background(), &Entry{FullPath: PathPolicyFile, Attr: Attr{Mode: 0644}, Content: []byte(`{"policies":[{"prefix":"logs/"}]}`)})
+	f.ReloadPathPolicies()
+	if p := f.MatchPathPolicy("/logs/a.log"); p.Replication != "001" {
+		t.Errorf("policy after invalid update %+v", p)
+	}
+
+	store.DeleteEntry(context.Background(), PathPolicyFile)
+	f.ReloadPathPolicies()
+	if p := f.MatchPathPolicy("/logs/a.log"); p.Prefix != "" {
+		t.Errorf("deleted policy still matches: %+v", p)
+	}
+}
@@ -0,0 +1,41 @@
+package filer2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestCheckPath(t *testing.T) {
+	f := &Filer{MaxFilenameLength: 8, MaxPathLength: 20}
+
+	tests := []struct {
+		path     string
+		expected error
+	}{
+		{"/a/b/c.txt", nil},
+		{"/a/12345678", nil},
+		{"/a/123456789", filer_pb.ErrNameTooLong},
+		{"/a/文件", nil},
+		{"/a/文件名", filer_pb.ErrNameTooLong}, // 9 bytes in 3 characters
+		{"/a/b/c/d/e/f/g/h/i/j", nil},
+		{"/a/b/c/d/e/f/g/h/i/jk", filer_pb.ErrNameTooLong},
+		{"/a/\xff", filer_pb.ErrInvalidName},
+		{"/a/\x00", filer_pb.ErrInvalidName},
+	}
+	for _, test := range tests {
+		if err := f.CheckPath(util.FullPath(test.path)); err != test.expected {
+			t.Errorf("%q: %v, expecting %v", test.path, err, test.expected)
+		}
+	}
+
+	unlimited := &Filer{}
+	if err := unlimited.CheckPath(util.FullPath("/" + strings.Repeat("x", 10000))); err != nil {
+		t.Errorf("unlimited: %v", err)
+	}
+	if err := unlimited.CheckPath("/\xff"); err != filer_pb.ErrInvalidName {
+		t.Errorf("unlimited invalid utf-8: %v", err)
+	}
+}
@@ -0,0 +1,407 @@
+package filer2
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// A directory quota limits the bytes and the number of files of the directory tree.
+// The usage of the quota directories is counted when the quota is set and when the filer starts,
+// and then kept up to date with the entry updates, see NotifyUpdateEvent.
+// The writes going over the quota fail with filer_pb.ErrQuotaExceeded.
+//
+// The quota is checked against the usage counted so far, before saving the entry, and the entry is counted
+// only after it is saved, without reserving the growth in between. So the concurrent writes into a quota
+// directory can go over its quota together: the overshoot is at most the sum of the growth of the writes
+// checked but not yet counted, i.e. the size of the writes in flight. A quota is a soft limit by that much.
+
+const (
+	// extended attribute keys on the directory entry
+	QuotaKey      = "quota"
+	QuotaUsageKey = "quota.usage"
+
+	// the quota directories are listed here, to load them when the filer starts
+	SystemQuotaDir = SystemDir + "/quota"
+
+	quotaUsageFlushInterval = 3 * time.Second
+)
+
+// DirectoryQuota is the json value of the QuotaKey attribute, 0 is unlimited
+type DirectoryQuota struct {
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	MaxFiles int64 `json:"maxFiles,omitempty"`
+}
+
+// QuotaUsage is the json value of the QuotaUsageKey attribute, saved from time to time
+type QuotaUsage struct {
+	Bytes int64 `json:"bytes"`
+	Files int64 `json:"files"`
+}
+
+type directoryQuota struct {
+	DirectoryQuota
+	usage QuotaUsage
+	dirty bool
+}
+
+type FilerQuotas struct {
+	quotas map[util.FullPath]*directoryQuota
+	sync.Mutex
+}
+
+func LoadDirectoryQuota(extended map[string][]byte) (quota DirectoryQuota, found bool) {
+	data, found := extended[QuotaKey]
+	if !found {
+		return quota, false
+	}
+	if err := json.Unmarshal(data, &quota); err != nil {
+		glog.Errorf("invalid directory quota %s: %v", string(data), err)
+		return quota, false
+	}
+	return quota, true
+}
+
+func LoadQuotaUsage(extended map[string][]byte) (usage QuotaUsage) {
+	if data, found := extended[QuotaUsageKey]; found {
+		json.Unmarshal(data, &usage)
+	}
+	return
+}
+
+func entryQuotaUsage(entry *Entry) (usage QuotaUsage) {
+	if entry == nil || entry.IsDirectory() {
+		return
+	}
+	return QuotaUsage{Bytes: int64(entry.Size()), Files: 1}
+}
+
+func (f *Filer) LoadQuotas() {
+
+	f.quotas = &FilerQuotas{
+		quotas: make(map[util.FullPath]*directoryQuota),
+	}
+
+	ctx := context.Background()
+	var markers []*Entry
+	lastFileName := ""
+	for {
+		entries, err := f.ListDirectoryEntries(ctx, SystemQuotaDir, lastFileName, false, PaginationSize)
+		if err != nil {
+			glog.V(1).Infof("no quotas found: %v", err)
+			break
+		}
+		markers = append(markers, entries...)
+		if len(entries) < PaginationSize {
+			break
+		}
+		lastFileName = entries[len(entries)-1].Name()
+	}
+
+	var dirs []util.FullPath
+	for _, marker := range markers {
+		dir, err := quotaDirectory(marker.Name())
+		if err != nil {
+			continue
+		}
+		dirEntry, _ := f.FindEntry(ctx, dir)
+		if dirEntry == nil {
+			f.removeQuotaMarker(dir)
+			continue
+		}
+		quota, found := LoadDirectoryQuota(dirEntry.Extended)
+		if !found {
+			f.removeQuotaMarker(dir)
+			continue
+		}
+		// the saved usage is enforced until the directory is counted again
+		f.quotas.quotas[dir] = &directoryQuota{
+			DirectoryQuota: quota,
+			usage:          LoadQuotaUsage(dirEntry.Extended),
+		}
+		dirs = append(dirs, dir)
+	}
+
+	glog.V(1).Infof("quotas found: %d", len(dirs))
+
+	go func() {
+		for _, dir := range dirs {
+			f.countQuotaUsage(dir)
+		}
+	}()
+
+	go f.loopFlushQuotaUsage()
+}
+
+// checkQuota returns filer_pb.ErrQuotaExceeded if replacing the oldEntry with the entry
+// would put any quota directory containing the entry over its quota.
+// The growth is not reserved, and counted by NotifyUpdateEvent after the entry is saved.
+func (f *Filer) checkQuota(ctx context.Context, oldEntry, entry *Entry) error {
+	if f.quotas == nil {
+		return nil
+	}
+
+	delta := entryQuotaUsage(entry)
+	if oldEntry != nil {
+		oldUsage := entryQuotaUsage(oldEntry)
+		delta.Bytes -= oldUsage.Bytes
+		delta.Files -= oldUsage.Files
+	}
+	if delta.Bytes <= 0 && delta.Files <= 0 {
+		return nil
+	}
+	movedFrom, _ := ctx.Value(movedFromKey{}).(util.FullPath)
+
+	f.quotas.Lock()
+	defer f.quotas.Unlock()
+
+	return f.quotas.eachQuota(entry.FullPath, func(dir util.FullPath, quota *directoryQuota) error {
+		if movedFrom != "" && strings.HasPrefix(string(movedFrom), string(dir)+"/") {
+			return nil
+		}
+		if quota.MaxBytes > 0 && delta.Bytes > 0 && quota.usage.Bytes+delta.Bytes > quota.MaxBytes {
+			glog.V(1).Infof("%s: %d bytes over the quota of %s", entry.FullPath, quota.usage.Bytes+delta.Bytes-quota.MaxBytes, dir)
+			return filer_pb.ErrQuotaExceeded
+		}
+		if quota.MaxFiles > 0 && delta.Files > 0 && quota.usage.Files+delta.Files > quota.MaxFiles {
+			glog.V(1).Infof("%s: over the %d files quota of %s", entry.FullPath, quota.MaxFiles, dir)
+			return filer_pb.ErrQuotaExceeded
+		}
+		return nil
+	})
+}
+
+// eachQuota visits the quotas of the directories containing the path, stopping at the first error
+func (quotas *FilerQuotas) eachQuota(p util.FullPath, fn func(dir util.FullPath, quota *directoryQuota) error) error {
+	for dir := p; dir != "/"; {
+		parent, _ := dir.DirAndName()
+		dir = util.FullPath(parent)
+		if quota, found := quotas.quotas[dir]; found {
+			if err := fn(dir, quota); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckQuota tells whether the file can be written with the size, before uploading its content
+// the size is unknown if negative
+func (f *Filer) CheckQuota(ctx context.Context, p util.FullPath, size int64) error {
+	if size < 0 {
+		size = 0
+	}
+	oldEntry, _ := f.FindEntry(ctx, p)
+	return f.checkQuota(ctx, oldEntry, &Entry{
+		FullPath: p,
+		Chunks:   []*filer_pb.FileChunk{{Size: uint64(size)}},
+	})
+}
+
+type movedFromKey struct{}
+
+// MovingFrom marks the creations of the entries moved from the old path,
+// which are not checked against the quotas of the directories containing both paths
+func MovingFrom(ctx context.Context, oldPath util.FullPath) context.Context {
+	return context.WithValue(ctx, movedFromKey{}, oldPath)
+}
+
+// updateQuotaUsage counts the replacement of the oldEntry by the newEntry, either one can be nil
+func (f *Filer) updateQuotaUsage(oldEntry, newEntry *Entry) {
+	if f.quotas == nil {
+		return
+	}
+
+	f.quotas.Lock()
+	defer f.quotas.Unlock()
+
+	if len(f.quotas.quotas) == 0 {
+		return
+	}
+	if usage := entryQuotaUsage(oldEntry); usage.Files > 0 {
+		f.quotas.addUsage(oldEntry.FullPath, -usage.Bytes, -usage.Files)
+	}
+	if usage := entryQuotaUsage(newEntry); usage.Files > 0 {
+		f.quotas.addUsage(newEntry.FullPath, usage.Bytes, usage.Files)
+	}
+}
+
+// removeFolderUsage counts the files deleted along with their folder, without notifying the deletions
+func (f *Filer) removeFolderUsage(dir util.FullPath, usage QuotaUsage) {
+	if f.quotas == nil || usage.Files == 0 {
+		return
+	}
+
+	f.quotas.Lock()
+	defer f.quotas.Unlock()
+
+	if quota, found := f.quotas.quotas[dir]; found {
+		quota.usage.Bytes -= usage.Bytes
+		quota.usage.Files -= usage.Files
+		quota.dirty = true
+	}
+	f.quotas.addUsage(dir, -usage.Bytes, -usage.Files)
+}
+
+func (quotas *FilerQuotas) addUsage(p util.FullPath, bytes, files int64) {
+	quotas.eachQuota(p, func(dir util.FullPath, quota *directoryQuota) error {
+		quota.usage.Bytes += bytes
+		quota.usage.Files += files
+		quota.dirty = true
+		return nil
+	})
+}
+
+// maybeUpdateQuota follows the quota attribute of the directory entries
+func (f *Filer) maybeUpdateQuota(oldEntry, newEntry *Entry) {
+	if f.quotas == nil {
+		return
+	}
+
+	if newEntry != nil && newEntry.IsDirectory() {
+		if quota, found := LoadDirectoryQuota(newEntry.Extended); found {
+			f.quotas.Lock()
+			existing, isSet := f.quotas.quotas[newEntry.FullPath]
+			if isSet {
+				existing.DirectoryQuota = quota
+			} else {
+				f.quotas.quotas[newEntry.FullPath] = &directoryQuota{DirectoryQuota: quota}
+			}
+			f.quotas.Unlock()
+			if !isSet {
+				glog.V(0).Infof("set quota of %s: %+v", newEntry.FullPath, quota)
+				f.addQuotaMarker(newEntry.FullPath)
+				f.countQuotaUsage(newEntry.FullPath)
+			}
+			return
+		}
+	}
+
+	if oldEntry != nil && oldEntry.IsDirectory() {
+		if _, found := LoadDirectoryQuota(oldEntry.Extended); found {
+			if newEntry == nil || newEntry.FullPath == oldEntry.FullPath {
+				f.quotas.Lock()
+				delete(f.quotas.quotas, oldEntry.FullPath)
+				f.quotas.Unlock()
+				glog.V(0).Infof("remove quota of %s", oldEntry.FullPath)
+				f.removeQuotaMarker(oldEntry.FullPath)
+			}
+		}
+	}
+}
+
+// countQuotaUsage walks the directory tree to count its usage
+func (f *Filer) countQuotaUsage(dir util.FullPath) {
+	usage, err := f.walkQuotaUsage(context.Background(), dir)
+	if err != nil {
+		glog.Errorf("count usage of %s: %v", dir, err)
+		return
+	}
+
+	f.quotas.Lock()
+	if quota, found := f.quotas.quotas[dir]; found {
+		quota.usage = usage
+		quota.dirty = true
+	}
+	f.quotas.Unlock()
+
+	glog.V(1).Infof("quota directory %s: %d bytes, %d files", dir, usage.Bytes, usage.Files)
+}
+
+func (f *Filer) walkQuotaUsage(ctx context.Context, dir util.FullPath) (usage QuotaUsage, err error) {
+	lastFileName := ""
+	for {
+		entries, err := f.ListDirectoryEntries(ctx, dir, lastFileName, false, PaginationSize)
+		if err != nil {
+			return usage, err
+		}
+		for _, entry := range entries {
+			lastFileName = entry.Name()
+			if entry.IsDirectory() {
+				subUsage, err := f.walkQuotaUsage(ctx, entry.FullPath)
+				if err != nil {
+					return usage, err
+				}
+				usage.Bytes += subUsage.Bytes
+				usage.Files += subUsage.Files
+			} else {
+				usage.Bytes += int64(entry.Size())
+				usage.Files++
+			}
+		}
+		if len(entries) < PaginationSize {
+			return usage, nil
+		}
+	}
+}
+
+func (f *Filer) loopFlushQuotaUsage() {
+	for range time.Tick(quotaUsageFlushInterval) {
+
+		dirty := make(map[util.FullPath]QuotaUsage)
+		f.quotas.Lock()
+		for dir, quota := range f.quotas.quotas {
+			if quota.dirty {
+				dirty[dir] = quota.usage
+				quota.dirty = false
+			}
+		}
+		f.quotas.Unlock()
+
+		for dir, usage := range dirty {
+			if err := f.saveQuotaUsage(dir, usage); err != nil {
+				glog.V(0).Infof("save usage of %s: %v", dir, err)
+			}
+		}
+	}
+}
+
+// saveQuotaUsage writes the usage into the directory entry, without notifying the update
+func (f *Filer) saveQuotaUsage(dir util.FullPath, usage QuotaUsage) error {
+	ctx := context.Background()
+	entry, err := f.FindEntry(ctx, dir)
+	if err != nil {
+		return err
+	}
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	entry.Extended[QuotaUsageKey], _ = json.Marshal(usage)
+	return f.store.UpdateEntry(ctx, entry)
+}
+
+func (f *Filer) addQuotaMarker(dir util.FullPath) {
+	now := time.Now()
+	if err := f.CreateEntry(context.Background(), &Entry{
+		FullPath: util.NewFullPath(SystemQuotaDir, url.PathEscape(string(dir))),
+		Attr: Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   os.FileMode(0644),
+			Uid:    OS_UID,
+			Gid:    OS_GID,
+		},
+	}, false); err != nil {
+		glog.Errorf("add quota directory %s: %v", dir, err)
+	}
+}
+
+func (f *Filer) removeQuotaMarker(dir util.FullPath) {
+	p := util.NewFullPath(SystemQuotaDir, url.PathEscape(string(dir)))
+	if err := f.DeleteEntryMetaAndData(context.Background(), p, false, false, false); err != nil && err != filer_pb.ErrNotFound {
+		glog.Errorf("remove quota directory %s: %v", dir, err)
+	}
+}
+
+func quotaDirectory(markerName string) (util.FullPath, error) {
+	dir, err := url.PathUnescape(markerName)
+	return util.FullPath(dir), err
+}
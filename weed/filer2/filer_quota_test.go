@@ -0,0 +1,90 @@
+package filer2
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func newTestQuotaFiler(quotas map[util.FullPath]*directoryQuota) *Filer {
+	return &Filer{quotas: &FilerQuotas{quotas: quotas}}
+}
+
+func testFile(p string, size uint64) *Entry {
+	return &Entry{
+		FullPath: util.FullPath(p),
+		Chunks:   []*filer_pb.FileChunk{{Size: size}},
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	f := newTestQuotaFiler(map[util.FullPath]*directoryQuota{
+		"/a":   {DirectoryQuota: DirectoryQuota{MaxBytes: 100}, usage: QuotaUsage{Bytes: 90, Files: 3}},
+		"/a/b": {DirectoryQuota: DirectoryQuota{MaxFiles: 2}, usage: QuotaUsage{Bytes: 10, Files: 1}},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		oldEntry *Entry
+		entry    *Entry
+		expected error
+	}{
+		{nil, testFile("/a/x", 10), nil},
+		{nil, testFile("/a/x", 11), filer_pb.ErrQuotaExceeded},
+		{testFile("/a/x", 5), testFile("/a/x", 15), nil},
+		{nil, testFile("/a/b/x", 10), nil},
+		{testFile("/a/b/y", 1), testFile("/a/b/y", 12), filer_pb.ErrQuotaExceeded},
+		{testFile("/a/x", 50), testFile("/a/x", 10), nil},
+		{nil, testFile("/ab/x", 1000), nil},
+		{nil, testFile("/x", 1000), nil},
+		{nil, &Entry{FullPath: "/a/b/c", Attr: Attr{Mode: os.ModeDir | 0755}}, nil},
+	}
+	for _, test := range tests {
+		if err := f.checkQuota(ctx, test.oldEntry, test.entry); err != test.expected {
+			t.Errorf("%s: %v, expecting %v", test.entry.FullPath, err, test.expected)
+		}
+	}
+
+	f.quotas.quotas["/a/b"].usage.Files = 2
+	if err := f.checkQuota(ctx, nil, testFile("/a/b/z", 0)); err != filer_pb.ErrQuotaExceeded {
+		t.Errorf("file count: %v", err)
+	}
+	if err := f.checkQuota(MovingFrom(ctx, "/a/m"), nil, testFile("/a/n", 50)); err != nil {
+		t.Errorf("move inside the quota directory: %v", err)
+	}
+	if err := f.checkQuota(MovingFrom(ctx, "/c/m"), nil, testFile("/a/n", 50)); err != filer_pb.ErrQuotaExceeded {
+		t.Errorf("move into the quota directory: %v", err)
+	}
+}
+
+func TestUpdateQuotaUsage(t *testing.T) {
+	f := newTestQuotaFiler(map[util.FullPath]*directoryQuota{
+		"/":    {},
+		"/a":   {},
+		"/a/b": {},
+	})
+
+	f.updateQuotaUsage(nil, testFile("/a/b/x", 10))
+	f.updateQuotaUsage(testFile("/a/b/x", 10), testFile("/a/b/x", 30))
+	f.updateQuotaUsage(nil, testFile("/a/y", 5))
+	f.updateQuotaUsage(testFile("/a/y", 5), testFile("/c/y", 5))
+	f.removeFolderUsage("/a/b", QuotaUsage{Bytes: 30, Files: 1})
+
+	expected := map[util.FullPath]QuotaUsage{
+		"/":    {Bytes: 5, Files: 1},
+		"/a":   {},
+		"/a/b": {},
+	}
+	for dir, usage := range expected {
+		quota := f.quotas.quotas[dir]
+		if quota.usage != usage {
+			t.Errorf("%s: %+v, expecting %+v", dir, quota.usage, usage)
+		}
+		if !quota.dirty {
+			t.Errorf("%s: usage not to be saved", dir)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package filer2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// A rename moves the entry and all its children, one at a time.
+// With a transactional store, the whole move runs in one transaction.
+// Otherwise the rename is journaled under SystemRenameDir before moving anything,
+// and a journaled rename left unfinished by a crash is rolled forward when the filer starts.
+// Since the children are moved before their directory is deleted, the entries left
+// under the old path are exactly the ones still to be moved.
+
+const (
+	renameJournalFromKey = "from"
+	renameJournalToKey   = "to"
+)
+
+func (f *Filer) AtomicRenameEntry(ctx context.Context, oldPath, newPath util.FullPath) (err error) {
+
+	if oldPath == newPath {
+		return nil
+	}
+	if strings.HasPrefix(string(newPath), string(oldPath)+"/") {
+		return fmt.Errorf("can not move %s into its own sub directory %s", oldPath, newPath)
+	}
+
+	oldEntry, err := f.FindEntry(ctx, oldPath)
+	if err != nil {
+		return fmt.Errorf("%s not found: %v", oldPath, err)
+	}
+
+	if !f.store.SupportsTransaction() {
+		return f.journaledRename(ctx, oldEntry, newPath)
+	}
+
+	ctx, err = f.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	if err = f.checkRenameTarget(ctx, oldEntry, newPath); err == nil {
+		err = f.moveEntry(ctx, oldEntry, newPath)
+	}
+	if err != nil {
+		f.RollbackTransaction(ctx)
+		return err
+	}
+	if err = f.CommitTransaction(ctx); err != nil {
+		f.RollbackTransaction(ctx)
+		return fmt.Errorf("commit move %s => %s: %v", oldPath, newPath, err)
+	}
+
+	return nil
+}
+
+// checkRenameTarget allows to overwrite a file by a file, or to replace an empty directory by a directory
+func (f *Filer) checkRenameTarget(ctx context.Context, oldEntry *Entry, newPath util.FullPath) error {
+
+	targetEntry, err := f.FindEntry(ctx, newPath)
+	if err == filer_pb.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("find %s: %v", newPath, err)
+	}
+
+	if oldEntry.IsDirectory() != targetEntry.IsDirectory() {
+		return fmt.Errorf("move %s => %s: %v", oldEntry.FullPath, newPath, filer_pb.ErrRenameTargetExists)
+	}
+	if !targetEntry.IsDirectory() {
+		return nil
+	}
+
+	children, err := f.ListDirectoryEntries(ctx, newPath, "", false, 1)
+	if err != nil {
+		return fmt.Errorf("list %s: %v", newPath, err)
+	}
+	if len(children) > 0 {
+		return fmt.Errorf("move %s => %s: %v", oldEntry.FullPath, newPath, filer_pb.ErrRenameTargetNotEmpty)
+	}
+	return nil
+}
+
+func (f *Filer) journaledRename(ctx context.Context, oldEntry *Entry, newPath util.FullPath) error {
+
+	if err := f.checkRenameTarget(ctx, oldEntry, newPath); err != nil {
+		return err
+	}
+
+	journal, err := f.writeRenameJournal(ctx, oldEntry.FullPath, newPath)
+	if err != nil {
+		return fmt.Errorf("journal move %s => %s: %v", oldEntry.FullPath, newPath, err)
+	}
+
+	if err = f.moveEntry(ctx, oldEntry, newPath); err != nil {
+		// partially moved, to be rolled forward by the next recovery
+		return err
+	}
+
+	if err = f.doDeleteEntryMetaAndData(ctx, journal, false); err != nil {
+		glog.V(0).Infof("remove rename journal %s: %v", journal.FullPath, err)
+	}
+	return nil
+}
+
+func (f *Filer) writeRenameJournal(ctx context.Context, oldPath, newPath util.FullPath) (*Entry, error) {
+	now := time.Now()
+	journal := &Entry{
+		FullPath: util.NewFullPath(SystemRenameDir, fmt.Sprintf("%019d", now.UnixNano())),
+		Attr: Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   os.FileMode(0644),
+			Uid:    OS_UID,
+			Gid:    OS_GID,
+		},
+		Extended: map[string][]byte{
+			renameJournalFromKey: []byte(oldPath),
+			renameJournalToKey:   []byte(newPath),
+		},
+	}
+	return journal, f.CreateEntry(ctx, journal, true)
+}
+
+// RecoverRenames rolls forward the journaled renames not finished before the filer stopped
+func (f *Filer) RecoverRenames() {
+
+	ctx := context.Background()
+	journals, err := f.ListDirectoryEntries(ctx, SystemRenameDir, "", false, PaginationSize)
+	if err != nil {
+		glog.V(0).Infof("list rename journals: %v", err)
+		return
+	}
+
+	for _, journal := range journals {
+		oldPath := util.FullPath(journal.Extended[renameJournalFromKey])
+		newPath := util.FullPath(journal.Extended[renameJournalToKey])
+		if oldPath == "" || newPath == "" {
+			glog.Errorf("invalid rename journal %s", journal.FullPath)
+			continue
+		}
+
+		glog.V(0).Infof("recover move %s => %s", oldPath, newPath)
+		oldEntry, findErr := f.FindEntry(ctx, oldPath)
+		if findErr != nil && findErr != filer_pb.ErrNotFound {
+			glog.Errorf("recover move %s => %s: %v", oldPath, newPath, findErr)
+			continue
+		}
+		if oldEntry != nil {
+			if err = f.moveEntry(ctx, oldEntry, newPath); err != nil {
+				glog.Errorf("recover move %s => %s: %v", oldPath, newPath, err)
+				continue
+			}
+		}
+
+		if err = f.doDeleteEntryMetaAndData(ctx, journal, false); err != nil {
+			glog.V(0).Infof("remove rename journal %s: %v", journal.FullPath, err)
+		}
+	}
+}
+
+func (f *Filer) moveEntry(ctx context.Context, entry *Entry, newPath util.FullPath) error {
+
+	if err := f.moveSelfEntry(ctx, entry, newPath, func() error {
+		if entry.IsDirectory() {
+			if err := f.moveFolderSubEntries(ctx, entry, newPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("fail to move %s => %s: %v", entry.FullPath, newPath, err)
+	}
+
+	return nil
+}
+
+func (f *Filer) moveFolderSubEntries(ctx context.Context, entry *Entry, newDirPath util.FullPath) error {
+
+	currentDirPath := entry.FullPath
+
+	glog.V(1).Infof("moving folder %s => %s", currentDirPath, newDirPath)
+
+	lastFileName := ""
+	includeLastFile := false
+	for {
+
+		entries, err := f.ListDirectoryEntries(ctx, currentDirPath, lastFileName, includeLastFile, 1024)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range entries {
+			lastFileName = item.Name()
+			err := f.moveEntry(ctx, item, newDirPath.Child(item.Name()))
+			if err != nil {
+				return err
+			}
+		}
+		if len(entries) < 1024 {
+			break
+		}
+	}
+	return nil
+}
+
+func (f *Filer) moveSelfEntry(ctx context.Context, entry *Entry, newPath util.FullPath, moveFolderSubEntries func() error) error {
+
+	oldPath := entry.FullPath
+
+	glog.V(1).Infof("moving entry %s => %s", oldPath, newPath)
+
+	// add to new directory
+	newEntry := &Entry{
+		FullPath: newPath,
+		Attr:     entry.Attr,
+		Chunks:   entry.Chunks,
+		Extended: entry.Extended,
+		Content:  entry.Content,
+	}
+	createErr := f.CreateEntry(MovingFrom(ctx, oldPath), newEntry, false)
+	if createErr != nil {
+		return createErr
+	}
+
+	if moveFolderSubEntries != nil {
+		if moveChildrenErr := moveFolderSubEntries(); moveChildrenErr != nil {
+			return moveChildrenErr
+		}
+	}
+
+	// delete old entry
+	deleteErr := f.DeleteEntryMetaAndData(ctx, oldPath, false, false, false)
+	if deleteErr != nil {
+		return deleteErr
+	}
+
+	return nil
+
+}
@@ -0,0 +1,198 @@
+package filer2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// crashingStore keeps the entries in memory, and panics on the mutation numbered crashAt
+type crashingStore struct {
+	entries   map[util.FullPath]*Entry
+	mutations int
+	crashAt   int
+}
+
+type simulatedCrash struct{}
+
+func (store *crashingStore) mutate() {
+	store.mutations++
+	if store.mutations == store.crashAt {
+		panic(simulatedCrash{})
+	}
+}
+
+func (store *crashingStore) GetName() string { return "crashing" }
+func (store *crashingStore) Initialize(configuration util.Configuration, prefix string) error {
+	return nil
+}
+func (store *crashingStore) InsertEntry(ctx context.Context, entry *Entry) error {
+	store.mutate()
+	e := *entry
+	store.entries[entry.FullPath] = &e
+	return nil
+}
+func (store *crashingStore) UpdateEntry(ctx context.Context, entry *Entry) error {
+	return store.InsertEntry(ctx, entry)
+}
+func (store *crashingStore) FindEntry(ctx context.Context, p util.FullPath) (*Entry, error) {
+	if entry, found := store.entries[p]; found {
+		e := *entry
+		return &e, nil
+	}
+	return nil, filer_pb.ErrNotFound
+}
+func (store *crashingStore) DeleteEntry(ctx context.Context, p util.FullPath) error {
+	store.mutate()
+	delete(store.entries, p)
+	return nil
+}
+func (store *crashingStore) DeleteFolderChildren(ctx context.Context, p util.FullPath) error {
+	store.mutate()
+	for child := range store.entries {
+		if dir, _ := child.DirAndName(); dir == string(p) {
+			delete(store.entries, child)
+		}
+	}
+	return nil
+}
+func (store *crashingStore) ListDirectoryEntries(ctx context.Context, dirPath util.FullPath, startFileName string, includeStartFile bool, limit int) (entries []*Entry, err error) {
+	var names []string
+	for child := range store.entries {
+		if dir, name := child.DirAndName(); dir == string(dirPath) {
+			if name > startFileName || includeStartFile && name == startFileName {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if len(entries) >= limit {
+			break
+		}
+		e := *store.entries[dirPath.Child(name)]
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
+func (store *crashingStore) BeginTransaction(ctx context.Context) (context.Context, error) {
+	return ctx, nil
+}
+func (store *crashingStore) CommitTransaction(ctx context.Context) error   { return nil }
+func (store *crashingStore) RollbackTransaction(ctx context.Context) error { return nil }
+func (store *crashingStore) Shutdown()                                     {}
+
+func newTestRenameFiler(store *crashingStore) *Filer {
+	f := NewFiler(nil, nil, "", 0, "", "", nil)
+	f.SetStore(store)
+	f.DisableDirectoryCache()
+	return f
+}
+
+func createTestEntries(t *testing.T, f *Filer, paths ...string) {
+	for _, p := range paths {
+		mode := os.FileMode(0644)
+		if strings.HasSuffix(p, "/") {
+			p, mode = strings.TrimSuffix(p, "/"), os.ModeDir|0755
+		}
+		if err := f.CreateEntry(context.Background(), &Entry{FullPath: util.FullPath(p), Attr: Attr{Mode: mode}}, false); err != nil {
+			t.Fatalf("create %s: %v", p, err)
+		}
+	}
+}
+
+// listTree lists the paths under the dir, relative to the dir
+func listTree(store *crashingStore, dir string) (paths []string) {
+	for p := range store.entries {
+		if strings.HasPrefix(string(p), dir+"/") {
+			paths = append(paths, strings.TrimPrefix(string(p), dir))
+		}
+	}
+	sort.Strings(paths)
+	return
+}
+
+func TestRenameCrashRecovery(t *testing.T) {
+
+	expectedTree := fmt.Sprint([]string{"/a", "/b", "/b/c", "/b/d", "/b/d/e"})
+
+	for crashAt := 1; ; crashAt++ {
+		store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+		createTestEntries(t, newTestRenameFiler(store), "/src/a", "/src/b/c", "/src/b/d/e", "/dst/")
+
+		store.mutations, store.crashAt = 0, crashAt
+		crashed := func() (crashed bool) {
+			defer func() {
+				if r := recover(); r != nil {
+					if _, ok := r.(simulatedCrash); !ok {
+						panic(r)
+					}
+					crashed = true
+				}
+			}()
+			if err := newTestRenameFiler(store).AtomicRenameEntry(context.Background(), "/src", "/dst"); err != nil {
+				t.Fatalf("rename: %v", err)
+			}
+			return false
+		}()
+
+		// restart
+		store.crashAt = 0
+		newTestRenameFiler(store).RecoverRenames()
+
+		oldTree, newTree := fmt.Sprint(listTree(store, "/src")), fmt.Sprint(listTree(store, "/dst"))
+		_, oldFound := store.entries["/src"]
+		movedCompletely := !oldFound && oldTree == "[]" && newTree == expectedTree
+		notMoved := oldFound && oldTree == expectedTree && newTree == "[]"
+		if !movedCompletely && !notMoved {
+			t.Fatalf("crash at mutation %d: inconsistent /src %s, /dst %s", crashAt, oldTree, newTree)
+		}
+		if journals := listTree(store, SystemRenameDir); len(journals) > 0 {
+			t.Fatalf("crash at mutation %d: journals left %v", crashAt, journals)
+		}
+
+		if !crashed {
+			if !movedCompletely {
+				t.Fatalf("rename finished but not moved")
+			}
+			break
+		}
+	}
+}
+
+func TestRenameTargetErrors(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	createTestEntries(t, f, "/dir1/a", "/dir2/b", "/dir3/", "/file1", "/file2")
+	ctx := context.Background()
+
+	tests := []struct {
+		oldPath, newPath util.FullPath
+		expected         error
+	}{
+		{"/dir1", "/dir2", filer_pb.ErrRenameTargetNotEmpty},
+		{"/dir1", "/file1", filer_pb.ErrRenameTargetExists},
+		{"/file1", "/dir2", filer_pb.ErrRenameTargetExists},
+		{"/dir1", "/dir3", nil},
+		{"/file1", "/file2", nil},
+	}
+	for _, tt := range tests {
+		err := f.AtomicRenameEntry(ctx, tt.oldPath, tt.newPath)
+		if tt.expected == nil && err != nil || tt.expected != nil && (err == nil || !strings.Contains(err.Error(), tt.expected.Error())) {
+			t.Errorf("rename %s => %s: %v, expected %v", tt.oldPath, tt.newPath, err, tt.expected)
+		}
+	}
+
+	if _, found := store.entries["/dir3/a"]; !found {
+		t.Errorf("directory not moved into the empty directory")
+	}
+	if _, found := store.entries["/file1"]; found {
+		t.Errorf("file not moved over the existing file")
+	}
+}
@@ -0,0 +1,77 @@
+package filer2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+const subscriberOffsetKey = "ts_ns"
+
+func subscriberOffsetPath(subscriberName string) (util.FullPath, error) {
+	if subscriberName == "" || strings.ContainsAny(subscriberName, "/\x00") || subscriberName == "." || subscriberName == ".." {
+		return "", fmt.Errorf("invalid subscriber name %q", subscriberName)
+	}
+	return util.NewFullPath(SystemSubscriberDir, subscriberName), nil
+}
+
+// LoadSubscriberOffset returns the timestamp of the last event acknowledged by the subscriber, or 0 if none.
+func (f *Filer) LoadSubscriberOffset(ctx context.Context, subscriberName string) (tsNs int64, err error) {
+	p, err := subscriberOffsetPath(subscriberName)
+	if err != nil {
+		return 0, err
+	}
+	entry, err := f.FindEntry(ctx, p)
+	if err == filer_pb.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("find subscriber %s offset: %v", subscriberName, err)
+	}
+	return strconv.ParseInt(string(entry.Extended[subscriberOffsetKey]), 10, 64)
+}
+
+// SaveSubscriberOffset persists the timestamp of the last event acknowledged by the subscriber.
+// The offset never moves backwards, and the saved offset is returned.
+func (f *Filer) SaveSubscriberOffset(ctx context.Context, subscriberName string, tsNs int64) (int64, error) {
+	p, err := subscriberOffsetPath(subscriberName)
+	if err != nil {
+		return 0, err
+	}
+
+	f.subscriberOffsetLock.Lock()
+	defer f.subscriberOffsetLock.Unlock()
+
+	savedTsNs, err := f.LoadSubscriberOffset(ctx, subscriberName)
+	if err != nil {
+		return 0, err
+	}
+	if tsNs <= savedTsNs {
+		return savedTsNs, nil
+	}
+
+	now := time.Now()
+	entry := &Entry{
+		FullPath: p,
+		Attr: Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   os.FileMode(0644),
+			Uid:    OS_UID,
+			Gid:    OS_GID,
+		},
+		Extended: map[string][]byte{
+			subscriberOffsetKey: []byte(strconv.FormatInt(tsNs, 10)),
+		},
+	}
+	if err = f.CreateEntry(ctx, entry, false); err != nil {
+		return 0, fmt.Errorf("save subscriber %s offset: %v", subscriberName, err)
+	}
+	return tsNs, nil
+}
@@ -0,0 +1,36 @@
+package filer2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestSubscriberOffset(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	ctx := context.Background()
+
+	if tsNs, err := f.LoadSubscriberOffset(ctx, "s1"); err != nil || tsNs != 0 {
+		t.Fatalf("load new subscriber: %d %v", tsNs, err)
+	}
+	if tsNs, err := f.SaveSubscriberOffset(ctx, "s1", 100); err != nil || tsNs != 100 {
+		t.Fatalf("save: %d %v", tsNs, err)
+	}
+	if tsNs, err := f.SaveSubscriberOffset(ctx, "s1", 50); err != nil || tsNs != 100 {
+		t.Errorf("offset moved backwards: %d %v", tsNs, err)
+	}
+	if tsNs, err := f.LoadSubscriberOffset(ctx, "s1"); err != nil || tsNs != 100 {
+		t.Errorf("load: %d %v", tsNs, err)
+	}
+	if tsNs, err := f.LoadSubscriberOffset(ctx, "s2"); err != nil || tsNs != 0 {
+		t.Errorf("load other subscriber: %d %v", tsNs, err)
+	}
+
+	for _, name := range []string{"", ".", "..", "a/b"} {
+		if _, err := f.SaveSubscriberOffset(ctx, name, 1); err == nil {
+			t.Errorf("expected error for subscriber name %q", name)
+		}
+	}
+}
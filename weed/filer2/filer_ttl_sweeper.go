@@ -0,0 +1,292 @@
+package filer2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/stats"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The files written with a ttl are indexed by their expiry time, in hourly folders under SystemTtlDir.
+// The sweeper goes through the folders of the past hours, and deletes the expired files and their chunks.
+// The index entries are removed once processed, so a restarted sweeper resumes where it stopped.
+// Only one filer sweeps at a time, holding a lease on the SystemTtlDir entry in the filer store.
+// The lease is taken with a conditional update, so the filers sharing a store with the conditional changes
+// never hold it together.
+
+const (
+	SystemTtlDir = SystemDir + "/ttl"
+
+	ttlIndexPeriod  = time.Hour
+	ttlSweepLockKey = "sweeper"
+)
+
+var errTtlSweepLockTaken = errors.New("ttl sweeper lock taken")
+
+type TtlSweeperOption struct {
+	Owner            string
+	EntriesPerSecond int
+	Interval         time.Duration
+}
+
+func expiryTime(entry *Entry) time.Time {
+	return entry.Crtime.Add(time.Duration(entry.TtlSec) * time.Second)
+}
+
+func ttlIndexFolder(expiry time.Time) string {
+	return fmt.Sprintf("%012d", expiry.Truncate(ttlIndexPeriod).Unix())
+}
+
+// maybeIndexTtl adds the new file with a ttl to the index
+func (f *Filer) maybeIndexTtl(oldEntry, newEntry *Entry) {
+	if newEntry == nil || newEntry.IsDirectory() || newEntry.TtlSec <= 0 {
+		return
+	}
+	if oldEntry != nil && oldEntry.TtlSec == newEntry.TtlSec && oldEntry.Crtime.Equal(newEntry.Crtime) {
+		return
+	}
+	if strings.HasPrefix(string(newEntry.FullPath), SystemTtlDir+"/") {
+		return
+	}
+
+	now := time.Now()
+	if err := f.CreateEntry(context.Background(), &Entry{
+		FullPath: util.NewFullPath(SystemTtlDir+"/"+ttlIndexFolder(expiryTime(newEntry)), url.PathEscape(string(newEntry.FullPath))),
+		Attr: Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   os.FileMode(0644),
+			Uid:    OS_UID,
+			Gid:    OS_GID,
+		},
+	}, false); err != nil {
+		glog.Errorf("index ttl of %s: %v", newEntry.FullPath, err)
+	}
+}
+
+func (f *Filer) LoopSweepExpiredEntries(option TtlSweeperOption) {
+
+	lease := 2 * option.Interval
+	if lease < time.Minute {
+		lease = time.Minute
+	}
+
+	for range time.Tick(option.Interval) {
+		if !f.acquireTtlSweepLock(option.Owner, lease) {
+			continue
+		}
+		start := time.Now()
+		sweptEntries, reclaimedBytes, err := f.sweepExpiredEntries(option, lease)
+		if err != nil {
+			glog.Errorf("sweep expired entries: %v", err)
+		}
+		if sweptEntries > 0 {
+			glog.V(0).Infof("swept %d expired entries, %d bytes in %v", sweptEntries, reclaimedBytes, time.Since(start))
+		}
+		stats.FilerTtlSweepGauge.WithLabelValues("entries").Set(float64(sweptEntries))
+		stats.FilerTtlSweepGauge.WithLabelValues("bytes").Set(float64(reclaimedBytes))
+		stats.FilerTtlSweepCounter.WithLabelValues("entries").Add(float64(sweptEntries))
+		stats.FilerTtlSweepCounter.WithLabelValues("bytes").Add(float64(reclaimedBytes))
+	}
+}
+
+func (f *Filer) sweepExpiredEntries(option TtlSweeperOption, lease time.Duration) (sweptEntries int, reclaimedBytes uint64, err error) {
+
+	ctx := context.Background()
+	folders, err := f.ListDirectoryEntries(ctx, SystemTtlDir, "", false, PaginationSize)
+	if err != nil {
+		return 0, 0, nil
+	}
+
+	var delay time.Duration
+	if option.EntriesPerSecond > 0 {
+		delay = time.Second / time.Duration(option.EntriesPerSecond)
+	}
+	lockRenewed := time.Now()
+
+	for _, folder := range folders {
+		periodStart, parseErr := strconv.ParseInt(folder.Name(), 10, 64)
+		if parseErr != nil || !folder.IsDirectory() {
+			continue
+		}
+		if time.Unix(periodStart, 0).After(time.Now()) {
+			break
+		}
+
+		pending := false
+		lastFileName := ""
+		for {
+			indexEntries, listErr := f.ListDirectoryEntries(ctx, folder.FullPath, lastFileName, false, 1024)
+			if listErr != nil {
+				return sweptEntries, reclaimedBytes, fmt.Errorf("list %s: %v", folder.FullPath, listErr)
+			}
+			for _, indexEntry := range indexEntries {
+				lastFileName = indexEntry.Name()
+
+				if time.Since(lockRenewed) > lease/2 {
+					if !f.acquireTtlSweepLock(option.Owner, lease) {
+						return sweptEntries, reclaimedBytes, fmt.Errorf("lost the sweeper lock")
+					}
+					lockRenewed = time.Now()
+				}
+
+				swept, expired, size := f.sweepIndexEntry(ctx, folder.Name(), indexEntry)
+				if swept {
+					sweptEntries++
+					reclaimedBytes += size
+				} else if !expired {
+					pending = true
+				}
+				if delay > 0 {
+					time.Sleep(delay)
+				}
+			}
+			if len(indexEntries) < 1024 {
+				break
+			}
+		}
+
+		if !pending && time.Unix(periodStart, 0).Add(ttlIndexPeriod).Before(time.Now()) {
+			if err = f.DeleteEntryMetaAndData(ctx, folder.FullPath, true, true, false); err != nil {
+				glog.V(0).Infof("remove ttl index %s: %v", folder.FullPath, err)
+			}
+		}
+	}
+
+	return sweptEntries, reclaimedBytes, nil
+}
+
+// sweepIndexEntry deletes the file if expired, and removes the index entry unless the file expires later in the same period
+func (f *Filer) sweepIndexEntry(ctx context.Context, folderName string, indexEntry *Entry) (swept, expired bool, size uint64) {
+
+	p, err := url.PathUnescape(indexEntry.Name())
+	if err != nil {
+		f.removeTtlIndexEntry(ctx, indexEntry)
+		return false, true, 0
+	}
+
+	// the filer store is used directly, since finding an expired entry through the filer deletes it already
+	entry, findErr := f.store.FindEntry(ctx, util.FullPath(p))
+	if findErr != nil && findErr != filer_pb.ErrNotFound {
+		glog.V(1).Infof("sweep %s: %v", p, findErr)
+		return false, false, 0
+	}
+	if entry == nil || entry.TtlSec <= 0 || ttlIndexFolder(expiryTime(entry)) != folderName {
+		// deleted, or written again since indexed
+		f.removeTtlIndexEntry(ctx, indexEntry)
+		return false, true, 0
+	}
+	if expiryTime(entry).After(time.Now()) {
+		return false, false, 0
+	}
+
+	if size, err = f.deleteExpiredEntry(ctx, entry); err != nil {
+		glog.V(0).Infof("sweep %s: %v", p, err)
+		return false, false, 0
+	}
+	f.removeTtlIndexEntry(ctx, indexEntry)
+
+	glog.V(3).Infof("swept expired %s", p)
+	return true, true, size
+}
+
+// deleteExpiredEntry deletes the expired file, its index entry is left to the sweeper
+func (f *Filer) deleteExpiredEntry(ctx context.Context, entry *Entry) (size uint64, err error) {
+	if err = f.doDeleteEntryMetaAndData(ctx, entry, false); err != nil {
+		return 0, err
+	}
+	return f.deleteExpiredChunks(entry.Chunks), nil
+}
+
+// deleteExpiredChunks skips the chunks of the volumes already reclaimed by the volume servers,
+// since the chunks written with a ttl are in volumes with the same ttl
+func (f *Filer) deleteExpiredChunks(chunks []*filer_pb.FileChunk) (size uint64) {
+	var toDelete []*filer_pb.FileChunk
+	for _, chunk := range chunks {
+		fid, err := needle.ParseFileIdFromString(chunk.GetFileIdString())
+		if err != nil {
+			continue
+		}
+		if _, found := f.MasterClient.GetLocations(uint32(fid.VolumeId)); !found {
+			continue
+		}
+		toDelete = append(toDelete, chunk)
+		size += chunk.Size
+	}
+	f.DeleteChunks(toDelete)
+	return size
+}
+
+func (f *Filer) removeTtlIndexEntry(ctx context.Context, indexEntry *Entry) {
+	if err := f.doDeleteEntryMetaAndData(ctx, indexEntry, false); err != nil {
+		glog.V(0).Infof("remove ttl index %s: %v", indexEntry.FullPath, err)
+	}
+}
+
+// acquireTtlSweepLock takes or renews the sweeper lease, kept in the SystemTtlDir entry
+func (f *Filer) acquireTtlSweepLock(owner string, lease time.Duration) bool {
+
+	ctx := context.Background()
+	entry, err := f.store.FindEntry(ctx, SystemTtlDir)
+	if err == filer_pb.ErrNotFound {
+		// nothing indexed yet
+		return false
+	}
+	if err != nil {
+		glog.V(1).Infof("lookup %s: %v", SystemTtlDir, err)
+		return false
+	}
+
+	previous := entry.Extended[ttlSweepLockKey]
+	if lockOwner, expiresAt := parseTtlSweepLock(previous); lockOwner != owner && time.Now().Before(expiresAt) {
+		glog.V(4).Infof("ttl sweeper lock held by %s until %v", lockOwner, expiresAt)
+		return false
+	}
+
+	extended := make(map[string][]byte)
+	for k, v := range entry.Extended {
+		extended[k] = v
+	}
+	extended[ttlSweepLockKey] = []byte(fmt.Sprintf("%s,%d", owner, time.Now().Add(lease).Unix()))
+	entry.Extended = extended
+
+	// another filer taking the lock at the same time has changed it
+	err = f.store.UpdateEntryIf(ctx, entry, func(existing *Entry) error {
+		if existing == nil || !bytes.Equal(existing.Extended[ttlSweepLockKey], previous) {
+			return errTtlSweepLockTaken
+		}
+		return nil
+	})
+	if err == errTtlSweepLockTaken {
+		glog.V(4).Infof("ttl sweeper lock taken by another filer")
+		return false
+	}
+	if err != nil {
+		glog.V(0).Infof("update ttl sweeper lock: %v", err)
+		return false
+	}
+	return true
+}
+
+func parseTtlSweepLock(lock []byte) (owner string, expiresAt time.Time) {
+	parts := strings.SplitN(string(lock), ",", 2)
+	if len(parts) != 2 {
+		return "", time.Time{}
+	}
+	expiresAtSec, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}
+	}
+	return parts[0], time.Unix(expiresAtSec, 0)
+}
@@ -0,0 +1,69 @@
+package filer2
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestTtlIndexFolder(t *testing.T) {
+	entry := &Entry{Attr: Attr{Crtime: time.Unix(7200+60, 0), TtlSec: 3600}}
+	if folder := ttlIndexFolder(expiryTime(entry)); folder != "000000010800" {
+		t.Errorf("unexpected folder %s", folder)
+	}
+
+	// folders are listed in expiry order
+	if ttlIndexFolder(time.Unix(999*3600, 0)) >= ttlIndexFolder(time.Unix(1000*3600, 0)) {
+		t.Errorf("folders not ordered by expiry")
+	}
+}
+
+func TestParseTtlSweepLock(t *testing.T) {
+	owner, expiresAt := parseTtlSweepLock([]byte("localhost:8888,1600000000"))
+	if owner != "localhost:8888" || expiresAt.Unix() != 1600000000 {
+		t.Errorf("unexpected lock %s %v", owner, expiresAt)
+	}
+
+	for _, lock := range []string{"", "localhost:8888", "localhost:8888,abc"} {
+		if owner, expiresAt := parseTtlSweepLock([]byte(lock)); owner != "" || !expiresAt.IsZero() {
+			t.Errorf("lock %q: unexpected %s %v", lock, owner, expiresAt)
+		}
+	}
+}
+
+func TestConcurrentTtlSweepLock(t *testing.T) {
+	store := &conditionalStore{syncStore{crashingStore: crashingStore{entries: make(map[util.FullPath]*Entry)}}}
+	store.InsertEntry(context.Background(), &Entry{FullPath: SystemTtlDir, Attr: Attr{Mode: 0755}})
+
+	var filers []*Filer
+	for i := 0; i < 2; i++ {
+		f := NewFiler(nil, nil, "", 0, "", "", nil)
+		f.SetStore(store)
+		f.DisableDirectoryCache()
+		filers = append(filers, f)
+	}
+
+	var lock sync.Mutex
+	holders := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			owner := fmt.Sprintf("filer%d", i%2)
+			if filers[i%2].acquireTtlSweepLock(owner, time.Minute) {
+				lock.Lock()
+				holders[owner] = true
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if len(holders) != 1 {
+		t.Errorf("ttl sweeper lock held by %v", holders)
+	}
+}
@@ -0,0 +1,159 @@
+package filer2
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The extended attributes of the applications, set through the mount or the filer api, are kept in Entry.Extended
+// with XattrPrefix before their names. The prefix is in lower case, so the xattrs can not collide with
+// the keys of the s3 gateway, which are canonical header names, nor with the other internal keys.
+// The xattrs set by the mount before the prefix are kept by their names, found by the namespaces of the xattr names,
+// and still read, listed and removed, until they are set again under the prefix.
+
+const (
+	XattrPrefix         = "xattr-"
+	XattrMaxNameLength  = 255
+	XattrMaxValueLength = 64 * 1024
+	// the names and values of all the xattrs of an entry
+	XattrMaxTotalLength = 256 * 1024
+)
+
+// the namespaces of the xattr names on linux and macos
+var legacyXattrNamespaces = []string{"user.", "trusted.", "security.", "system.", "com.apple."}
+
+func IsXattrKey(key string) bool {
+	return strings.HasPrefix(key, XattrPrefix)
+}
+
+// isLegacyXattrKey tells the xattrs kept by their names, without XattrPrefix
+func isLegacyXattrKey(key string) bool {
+	for _, namespace := range legacyXattrNamespaces {
+		if strings.HasPrefix(key, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// xattrName returns the name of the xattr kept with the key
+func xattrName(key string) (name string, ok bool) {
+	if IsXattrKey(key) {
+		return strings.TrimPrefix(key, XattrPrefix), true
+	}
+	return key, isLegacyXattrKey(key)
+}
+
+func CheckXattrName(name string) error {
+	if name == "" || len(name) > XattrMaxNameLength || strings.IndexByte(name, 0) >= 0 {
+		return filer_pb.ErrXattrInvalidName
+	}
+	return nil
+}
+
+func GetXattr(extended map[string][]byte, name string) (value []byte, found bool) {
+	if value, found = extended[XattrPrefix+name]; !found && isLegacyXattrKey(name) {
+		value, found = extended[name]
+	}
+	return
+}
+
+// ListXattrs returns the sorted names of the xattrs
+func ListXattrs(extended map[string][]byte) (names []string) {
+	for key := range extended {
+		if name, ok := xattrName(key); ok {
+			if _, found := extended[XattrPrefix+name]; found && !IsXattrKey(key) {
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// SetXattr checks the size limits before setting the xattr under XattrPrefix, extended should not be nil
+func SetXattr(extended map[string][]byte, name string, value []byte) error {
+	if err := CheckXattrName(name); err != nil {
+		return err
+	}
+	if len(value) > XattrMaxValueLength {
+		return filer_pb.ErrXattrTooLarge
+	}
+	total := len(name) + len(value)
+	for key, v := range extended {
+		if keyName, ok := xattrName(key); ok && keyName != name {
+			total += len(keyName) + len(v)
+		}
+	}
+	if total > XattrMaxTotalLength {
+		return filer_pb.ErrXattrNoSpace
+	}
+	extended[XattrPrefix+name] = value
+	if isLegacyXattrKey(name) {
+		delete(extended, name)
+	}
+	return nil
+}
+
+// RemoveXattr removes the xattr, and its legacy key kept without XattrPrefix
+func RemoveXattr(extended map[string][]byte, name string) error {
+	_, found := extended[XattrPrefix+name]
+	_, legacyFound := extended[name]
+	legacyFound = legacyFound && isLegacyXattrKey(name)
+	if !found && !legacyFound {
+		return filer_pb.ErrXattrNotFound
+	}
+	delete(extended, XattrPrefix+name)
+	if legacyFound {
+		delete(extended, name)
+	}
+	return nil
+}
+
+// GetXattrs returns the xattrs of the entry by their names
+func (f *Filer) GetXattrs(ctx context.Context, p util.FullPath) (map[string][]byte, error) {
+	entry, err := f.FindEntry(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	xattrs := make(map[string][]byte)
+	for _, name := range ListXattrs(entry.Extended) {
+		xattrs[name], _ = GetXattr(entry.Extended, name)
+	}
+	return xattrs, nil
+}
+
+// UpdateXattrs removes and then sets the xattrs of the entry, changing nothing if any of them fails
+func (f *Filer) UpdateXattrs(ctx context.Context, p util.FullPath, set map[string][]byte, remove []string) error {
+	entry, err := f.FindEntry(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	newEntry := *entry
+	newEntry.Extended = make(map[string][]byte, len(entry.Extended)+len(set))
+	for key, value := range entry.Extended {
+		newEntry.Extended[key] = value
+	}
+	for _, name := range remove {
+		if err = RemoveXattr(newEntry.Extended, name); err != nil {
+			return err
+		}
+	}
+	for name, value := range set {
+		if err = SetXattr(newEntry.Extended, name, value); err != nil {
+			return err
+		}
+	}
+
+	if err = f.UpdateEntry(ctx, entry, &newEntry); err != nil {
+		return err
+	}
+	f.NotifyUpdateEvent(entry, &newEntry, false)
+	return nil
+}
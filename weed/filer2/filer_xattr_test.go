@@ -0,0 +1,137 @@
+package filer2
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestSetXattrLimits(t *testing.T) {
+	extended := map[string][]byte{
+		"X-Amz-Meta-Tag": []byte("s3"),
+	}
+
+	if err := SetXattr(extended, "user.tag", []byte("v")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if value, found := GetXattr(extended, "user.tag"); !found || string(value) != "v" {
+		t.Errorf("get: %q %v", value, found)
+	}
+	if err := SetXattr(extended, "X-Amz-Meta-Tag", []byte("xattr")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if string(extended["X-Amz-Meta-Tag"]) != "s3" {
+		t.Errorf("overwrote the s3 metadata")
+	}
+	if names := ListXattrs(extended); !reflect.DeepEqual(names, []string{"X-Amz-Meta-Tag", "user.tag"}) {
+		t.Errorf("list: %v", names)
+	}
+
+	if err := SetXattr(extended, "", []byte("v")); err != filer_pb.ErrXattrInvalidName {
+		t.Errorf("set an empty name: %v", err)
+	}
+	if err := SetXattr(extended, strings.Repeat("n", XattrMaxNameLength+1), []byte("v")); err != filer_pb.ErrXattrInvalidName {
+		t.Errorf("set a too long name: %v", err)
+	}
+	if err := SetXattr(extended, "large", make([]byte, XattrMaxValueLength+1)); err != filer_pb.ErrXattrTooLarge {
+		t.Errorf("set a too large value: %v", err)
+	}
+	for i := 0; i < XattrMaxTotalLength/XattrMaxValueLength-1; i++ {
+		if err := SetXattr(extended, string(rune('a'+i)), make([]byte, XattrMaxValueLength)); err != nil {
+			t.Fatalf("set %d: %v", i, err)
+		}
+	}
+	if err := SetXattr(extended, "full", make([]byte, XattrMaxValueLength)); err != filer_pb.ErrXattrNoSpace {
+		t.Errorf("set over the entry limit: %v", err)
+	}
+	// replacing a value does not count the old one
+	if err := SetXattr(extended, "a", bytes.Repeat([]byte("b"), XattrMaxValueLength)); err != nil {
+		t.Errorf("replace: %v", err)
+	}
+
+	if err := RemoveXattr(extended, "user.tag"); err != nil {
+		t.Errorf("remove: %v", err)
+	}
+	if err := RemoveXattr(extended, "user.tag"); err != filer_pb.ErrXattrNotFound {
+		t.Errorf("remove a missing xattr: %v", err)
+	}
+}
+
+func TestLegacyXattrs(t *testing.T) {
+	// set by the mount before the prefix
+	extended := map[string][]byte{
+		"user.old":             []byte("1"),
+		"com.apple.FinderInfo": []byte("2"),
+		"X-Amz-Meta-Tag":       []byte("s3"),
+	}
+
+	if value, found := GetXattr(extended, "user.old"); !found || string(value) != "1" {
+		t.Errorf("get legacy: %q %v", value, found)
+	}
+	if _, found := GetXattr(extended, "X-Amz-Meta-Tag"); found {
+		t.Errorf("got the s3 metadata")
+	}
+	if names := ListXattrs(extended); !reflect.DeepEqual(names, []string{"com.apple.FinderInfo", "user.old"}) {
+		t.Errorf("list: %v", names)
+	}
+
+	// set again under the prefix
+	if err := SetXattr(extended, "user.old", []byte("3")); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, found := extended["user.old"]; found {
+		t.Errorf("legacy key kept after set")
+	}
+	if value, _ := GetXattr(extended, "user.old"); string(value) != "3" {
+		t.Errorf("get after set: %q", value)
+	}
+	if names := ListXattrs(extended); !reflect.DeepEqual(names, []string{"com.apple.FinderInfo", "user.old"}) {
+		t.Errorf("list after set: %v", names)
+	}
+
+	if err := RemoveXattr(extended, "com.apple.FinderInfo"); err != nil {
+		t.Errorf("remove legacy: %v", err)
+	}
+	if _, found := extended["com.apple.FinderInfo"]; found {
+		t.Errorf("legacy key not removed")
+	}
+	if err := RemoveXattr(extended, "X-Amz-Meta-Tag"); err != filer_pb.ErrXattrNotFound {
+		t.Errorf("removed the s3 metadata: %v", err)
+	}
+}
+
+func TestUpdateXattrs(t *testing.T) {
+	store := &crashingStore{entries: make(map[util.FullPath]*Entry)}
+	f := newTestRenameFiler(store)
+	createTestEntries(t, f, "/dir/file")
+	ctx := context.Background()
+
+	if err := f.UpdateXattrs(ctx, "/dir/file", map[string][]byte{"user.a": []byte("1"), "user.b": []byte("2")}, nil); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := f.UpdateXattrs(ctx, "/dir", map[string][]byte{"user.a": []byte("dir")}, nil); err != nil {
+		t.Fatalf("set on a folder: %v", err)
+	}
+	if err := f.UpdateXattrs(ctx, "/dir/file", map[string][]byte{"user.c": []byte("3")}, []string{"user.a", "missing"}); err != filer_pb.ErrXattrNotFound {
+		t.Errorf("remove a missing xattr: %v", err)
+	}
+	if err := f.UpdateXattrs(ctx, "/dir/file", nil, []string{"user.a"}); err != nil {
+		t.Errorf("remove: %v", err)
+	}
+
+	xattrs, err := f.GetXattrs(ctx, "/dir/file")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !reflect.DeepEqual(xattrs, map[string][]byte{"user.b": []byte("2")}) {
+		t.Errorf("xattrs %v", xattrs)
+	}
+	if _, err = f.GetXattrs(ctx, "/dir/missing"); err != filer_pb.ErrNotFound {
+		t.Errorf("get on a missing entry: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package filer2
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
@@ -29,8 +30,29 @@ type FilerStore interface {
 	Shutdown()
 }
 
+// TransactionalFilerStore is implemented by the stores able to run the operations of a transaction atomically,
+// the other stores only pretend to begin and commit the transactions
+type TransactionalFilerStore interface {
+	SupportsTransaction() bool
+}
+
+// EntryCondition checks the current entry, nil if not found, before it is changed
+type EntryCondition func(existing *Entry) error
+
+// ConditionalFilerStore is implemented by the stores able to check an entry and change it atomically,
+// even when several filers share the store, such as the sql stores with the locked rows, redis with the watched keys,
+// and etcd with the compared revisions. For the other stores, the filer only serializes the conditional changes
+// of each path within its own process, so the conditions are not atomic across the filers sharing the store.
+type ConditionalFilerStore interface {
+	// UpdateEntryIf inserts or updates the entry, only if the condition holds for the current one
+	UpdateEntryIf(ctx context.Context, entry *Entry, condition EntryCondition) error
+	// DeleteEntryIf deletes the entry, only if the condition holds for the current one
+	DeleteEntryIf(ctx context.Context, fp util.FullPath, condition EntryCondition) error
+}
+
 type FilerStoreWrapper struct {
 	actualStore FilerStore
+	entryLocks  [256]sync.Mutex
 }
 
 func NewFilerStoreWrapper(store FilerStore) *FilerStoreWrapper {
@@ -136,6 +158,92 @@ func (fsw *FilerStoreWrapper) RollbackTransaction(ctx context.Context) error {
 	return fsw.actualStore.RollbackTransaction(ctx)
 }
 
+func (fsw *FilerStoreWrapper) SupportsTransaction() bool {
+	if transactionalStore, ok := fsw.actualStore.(TransactionalFilerStore); ok {
+		return transactionalStore.SupportsTransaction()
+	}
+	return false
+}
+
+func (fsw *FilerStoreWrapper) UpdateEntryIf(ctx context.Context, entry *Entry, condition EntryCondition) error {
+	stats.FilerStoreCounter.WithLabelValues(fsw.actualStore.GetName(), "updateIf").Inc()
+	start := time.Now()
+	defer func() {
+		stats.FilerStoreHistogram.WithLabelValues(fsw.actualStore.GetName(), "updateIf").Observe(time.Since(start).Seconds())
+	}()
+
+	filer_pb.BeforeEntrySerialization(entry.Chunks)
+	if conditionalStore, ok := fsw.actualStore.(ConditionalFilerStore); ok {
+		return conditionalStore.UpdateEntryIf(ctx, entry, deserializedCondition(condition))
+	}
+
+	defer fsw.lockEntry(entry.FullPath)()
+	existing, err := fsw.findExistingEntry(ctx, entry.FullPath)
+	if err != nil {
+		return err
+	}
+	if err = condition(existing); err != nil {
+		return err
+	}
+	if existing == nil {
+		return fsw.actualStore.InsertEntry(ctx, entry)
+	}
+	return fsw.actualStore.UpdateEntry(ctx, entry)
+}
+
+func (fsw *FilerStoreWrapper) DeleteEntryIf(ctx context.Context, fp util.FullPath, condition EntryCondition) error {
+	stats.FilerStoreCounter.WithLabelValues(fsw.actualStore.GetName(), "deleteIf").Inc()
+	start := time.Now()
+	defer func() {
+		stats.FilerStoreHistogram.WithLabelValues(fsw.actualStore.GetName(), "deleteIf").Observe(time.Since(start).Seconds())
+	}()
+
+	if conditionalStore, ok := fsw.actualStore.(ConditionalFilerStore); ok {
+		return conditionalStore.DeleteEntryIf(ctx, fp, deserializedCondition(condition))
+	}
+
+	defer fsw.lockEntry(fp)()
+	existing, err := fsw.findExistingEntry(ctx, fp)
+	if err != nil {
+		return err
+	}
+	if err = condition(existing); err != nil {
+		return err
+	}
+	return fsw.actualStore.DeleteEntry(ctx, fp)
+}
+
+// lockEntry serializes the conditional changes of the path within this filer, for the stores not able to change
+// an entry conditionally. The unconditional changes and the other filers do not take the lock.
+// It returns the function to unlock.
+func (fsw *FilerStoreWrapper) lockEntry(fp util.FullPath) func() {
+	lock := &fsw.entryLocks[uint64(util.HashStringToLong(string(fp)))%uint64(len(fsw.entryLocks))]
+	lock.Lock()
+	return lock.Unlock
+}
+
+// findExistingEntry returns nil if the entry is not found
+func (fsw *FilerStoreWrapper) findExistingEntry(ctx context.Context, fp util.FullPath) (*Entry, error) {
+	entry, err := fsw.actualStore.FindEntry(ctx, fp)
+	if err == filer_pb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	filer_pb.AfterEntryDeserialization(entry.Chunks)
+	return entry, nil
+}
+
+func deserializedCondition(condition EntryCondition) EntryCondition {
+	return func(existing *Entry) error {
+		if existing != nil {
+			filer_pb.AfterEntryDeserialization(existing.Chunks)
+		}
+		return condition(existing)
+	}
+}
+
 func (fsw *FilerStoreWrapper) Shutdown() {
 	fsw.actualStore.Shutdown()
 }
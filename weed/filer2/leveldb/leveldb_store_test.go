@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/filer2/storetest"
 	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
@@ -88,3 +89,13 @@ func TestEmptyRoot(t *testing.T) {
 	}
 
 }
+
+func TestListConformance(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "seaweedfs_filer_test")
+	defer os.RemoveAll(dir)
+	store := &LevelDBStore{}
+	store.initialize(dir)
+	defer store.Shutdown()
+
+	storetest.TestListDirectoryEntries(t, store)
+}
@@ -19,9 +19,10 @@ func init() {
 }
 
 type MongodbStore struct {
-	connect        *mongo.Client
-	database       string
-	collectionName string
+	connect             *mongo.Client
+	database            string
+	collectionName      string
+	supportsTransaction bool
 }
 
 type Model struct {
@@ -42,7 +43,8 @@ func (store *MongodbStore) Initialize(configuration util.Configuration, prefix s
 }
 
 func (store *MongodbStore) connection(uri string, poolSize uint64) (err error) {
-	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 	opts := options.Client().ApplyURI(uri)
 
 	if poolSize > 0 {
@@ -57,7 +59,32 @@ func (store *MongodbStore) connection(uri string, poolSize uint64) (err error) {
 	c := client.Database(store.database).Collection(store.collectionName)
 	err = store.indexUnique(c)
 	store.connect = client
-	return err
+	if err != nil {
+		return err
+	}
+
+	store.supportsTransaction = store.checkTransactionSupport()
+	if !store.supportsTransaction {
+		glog.V(0).Infof("mongodb at %s is not a replica set or sharded cluster, transactions are disabled", uri)
+	}
+	return nil
+}
+
+// checkTransactionSupport tells whether the deployment is a replica set or a sharded cluster,
+// since a standalone mongod rejects transactions
+func (store *MongodbStore) checkTransactionSupport() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var result bson.M
+	if err := store.connect.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result); err != nil {
+		glog.V(0).Infof("mongodb isMaster: %v", err)
+		return false
+	}
+	if _, found := result["setName"]; found {
+		return true
+	}
+	return result["msg"] == "isdbgrid"
 }
 
 func (store *MongodbStore) createIndex(c *mongo.Collection, index mongo.IndexModel, opts *options.CreateIndexesOptions) error {
@@ -81,15 +108,45 @@ func (store *MongodbStore) indexUnique(c *mongo.Collection) error {
 	return store.createIndex(c, index, opts)
 }
 
+func (store *MongodbStore) SupportsTransaction() bool {
+	return store.supportsTransaction
+}
+
 func (store *MongodbStore) BeginTransaction(ctx context.Context) (context.Context, error) {
-	return ctx, nil
+	if !store.supportsTransaction {
+		return ctx, nil
+	}
+
+	sess, err := store.connect.StartSession()
+	if err != nil {
+		return ctx, err
+	}
+	if err = sess.StartTransaction(); err != nil {
+		sess.EndSession(ctx)
+		return ctx, err
+	}
+
+	// the session context is kept after the callback, so the following operations run in the transaction
+	err = mongo.WithSession(ctx, sess, func(sessCtx mongo.SessionContext) error {
+		ctx = sessCtx
+		return nil
+	})
+	return ctx, err
 }
 
 func (store *MongodbStore) CommitTransaction(ctx context.Context) error {
+	if sessCtx, ok := ctx.(mongo.SessionContext); ok {
+		defer sessCtx.EndSession(ctx)
+		return sessCtx.CommitTransaction(ctx)
+	}
 	return nil
 }
 
 func (store *MongodbStore) RollbackTransaction(ctx context.Context) error {
+	if sessCtx, ok := ctx.(mongo.SessionContext); ok {
+		defer sessCtx.EndSession(ctx)
+		return sessCtx.AbortTransaction(ctx)
+	}
 	return nil
 }
 
@@ -108,12 +165,30 @@ func (store *MongodbStore) InsertEntry(ctx context.Context, entry *filer2.Entry)
 		Name:      name,
 		Meta:      meta,
 	})
+	if err != nil {
+		return fmt.Errorf("insert %s: %v", entry.FullPath, err)
+	}
 
 	return nil
 }
 
 func (store *MongodbStore) UpdateEntry(ctx context.Context, entry *filer2.Entry) (err error) {
-	return store.InsertEntry(ctx, entry)
+
+	dir, name := entry.FullPath.DirAndName()
+	meta, err := entry.EncodeAttributesAndChunks()
+	if err != nil {
+		return fmt.Errorf("encode %s: %s", entry.FullPath, err)
+	}
+
+	c := store.connect.Database(store.database).Collection(store.collectionName)
+
+	where := bson.M{"directory": dir, "name": name}
+	update := bson.M{"$set": bson.M{"meta": meta}}
+	if _, err = c.UpdateOne(ctx, where, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("update %s: %v", entry.FullPath, err)
+	}
+
+	return nil
 }
 
 func (store *MongodbStore) FindEntry(ctx context.Context, fullpath util.FullPath) (entry *filer2.Entry, err error) {
@@ -123,9 +198,12 @@ func (store *MongodbStore) FindEntry(ctx context.Context, fullpath util.FullPath
 
 	var where = bson.M{"directory": dir, "name": name}
 	err = store.connect.Database(store.database).Collection(store.collectionName).FindOne(ctx, where).Decode(&data)
-	if err != mongo.ErrNoDocuments && err != nil {
+	if err == mongo.ErrNoDocuments {
 		return nil, filer_pb.ErrNotFound
 	}
+	if err != nil {
+		return nil, fmt.Errorf("find %s: %v", fullpath, err)
+	}
 
 	if len(data.Meta) == 0 {
 		return nil, filer_pb.ErrNotFound
@@ -158,7 +236,7 @@ func (store *MongodbStore) DeleteEntry(ctx context.Context, fullpath util.FullPa
 
 func (store *MongodbStore) DeleteFolderChildren(ctx context.Context, fullpath util.FullPath) error {
 
-	where := bson.M{"directory": fullpath}
+	where := bson.M{"directory": string(fullpath)}
 	_, err := store.connect.Database(store.database).Collection(store.collectionName).DeleteMany(ctx, where)
 	if err != nil {
 		return fmt.Errorf("delete %s : %v", fullpath, err)
@@ -178,11 +256,14 @@ func (store *MongodbStore) ListDirectoryEntries(ctx context.Context, fullpath ut
 	optLimit := int64(limit)
 	opts := &options.FindOptions{Limit: &optLimit, Sort: bson.M{"name": 1}}
 	cur, err := store.connect.Database(store.database).Collection(store.collectionName).Find(ctx, where, opts)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %v", fullpath, err)
+	}
 	for cur.Next(ctx) {
 		var data Model
-		err := cur.Decode(&data)
-		if err != nil && err != mongo.ErrNoDocuments {
-			return nil, err
+		if decodeErr := cur.Decode(&data); decodeErr != nil {
+			cur.Close(ctx)
+			return nil, fmt.Errorf("list %s: %v", fullpath, decodeErr)
 		}
 
 		entry := &filer2.Entry{
@@ -205,6 +286,7 @@ func (store *MongodbStore) ListDirectoryEntries(ctx context.Context, fullpath ut
 }
 
 func (store *MongodbStore) Shutdown() {
-	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 	store.connect.Disconnect(ctx)
 }
@@ -0,0 +1,182 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/filer2/storetest"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The tests run against a MongoDB test container, e.g.
+//   docker run -d -p 27017:27017 mongo:4.2 --replSet rs0 && docker exec <container> mongo --eval "rs.initiate()"
+//   SEAWEEDFS_TEST_MONGODB_URI=mongodb://localhost:27017 go test ./filer2/mongodb/
+// A standalone mongod works as well, with the transactions disabled.
+
+func newTestStore(t *testing.T) *MongodbStore {
+	uri := os.Getenv("SEAWEEDFS_TEST_MONGODB_URI")
+	if uri == "" {
+		t.Skip("SEAWEEDFS_TEST_MONGODB_URI is not set")
+	}
+	store := &MongodbStore{
+		database:       fmt.Sprintf("seaweedfs_test_%d", time.Now().UnixNano()),
+		collectionName: "filemeta",
+	}
+	if err := store.connection(uri, 0); err != nil {
+		t.Fatalf("connect %s: %v", uri, err)
+	}
+	return store
+}
+
+func cleanupTestStore(store *MongodbStore) {
+	store.connect.Database(store.database).Drop(context.Background())
+	store.Shutdown()
+}
+
+func testEntry(p string, mode uint32) *filer2.Entry {
+	return &filer2.Entry{
+		FullPath: util.FullPath(p),
+		Attr: filer2.Attr{
+			Mode: os.FileMode(mode),
+			Uid:  1234,
+			Gid:  5678,
+		},
+	}
+}
+
+func TestCreateUpdateFindDelete(t *testing.T) {
+	store := newTestStore(t)
+	defer cleanupTestStore(store)
+	ctx := context.Background()
+
+	if err := store.InsertEntry(ctx, testEntry("/home/chris/file1.jpg", 0440)); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := store.InsertEntry(ctx, testEntry("/home/chris/file1.jpg", 0440)); err == nil {
+		t.Errorf("duplicated insert should fail")
+	}
+
+	if err := store.UpdateEntry(ctx, testEntry("/home/chris/file1.jpg", 0644)); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	entry, err := store.FindEntry(ctx, "/home/chris/file1.jpg")
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if entry.Mode != 0644 || entry.Uid != 1234 {
+		t.Errorf("unexpected entry %+v", entry.Attr)
+	}
+
+	// update creates the missing entry
+	if err := store.UpdateEntry(ctx, testEntry("/home/chris/file2.jpg", 0644)); err != nil {
+		t.Fatalf("update missing: %v", err)
+	}
+	if _, err := store.FindEntry(ctx, "/home/chris/file2.jpg"); err != nil {
+		t.Errorf("find updated: %v", err)
+	}
+
+	if err := store.DeleteEntry(ctx, "/home/chris/file1.jpg"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.FindEntry(ctx, "/home/chris/file1.jpg"); err != filer_pb.ErrNotFound {
+		t.Errorf("find deleted: %v", err)
+	}
+}
+
+func TestListAndDeleteFolderChildren(t *testing.T) {
+	store := newTestStore(t)
+	defer cleanupTestStore(store)
+	ctx := context.Background()
+
+	for _, p := range []string{"/dir/a", "/dir/b", "/dir/c", "/dir/d", "/dir2/a", "/dir/sub/a"} {
+		if err := store.InsertEntry(ctx, testEntry(p, 0644)); err != nil {
+			t.Fatalf("insert %s: %v", p, err)
+		}
+	}
+
+	tests := []struct {
+		startFileName string
+		inclusive     bool
+		limit         int
+		expected      []string
+	}{
+		{"", false, 100, []string{"a", "b", "c", "d"}},
+		{"b", false, 100, []string{"c", "d"}},
+		{"b", true, 100, []string{"b", "c", "d"}},
+		{"", false, 2, []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		entries, err := store.ListDirectoryEntries(ctx, "/dir", tt.startFileName, tt.inclusive, tt.limit)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		if fmt.Sprint(names) != fmt.Sprint(tt.expected) {
+			t.Errorf("list from %q inclusive %v limit %d: got %v, expected %v", tt.startFileName, tt.inclusive, tt.limit, names, tt.expected)
+		}
+	}
+
+	if err := store.DeleteFolderChildren(ctx, "/dir"); err != nil {
+		t.Fatalf("delete folder children: %v", err)
+	}
+	if entries, _ := store.ListDirectoryEntries(ctx, "/dir", "", false, 100); len(entries) != 0 {
+		t.Errorf("list deleted folder: %d entries", len(entries))
+	}
+	if _, err := store.FindEntry(ctx, "/dir2/a"); err != nil {
+		t.Errorf("find other folder: %v", err)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	store := newTestStore(t)
+	defer cleanupTestStore(store)
+	ctx := context.Background()
+
+	txCtx, err := store.BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err = store.InsertEntry(txCtx, testEntry("/tx/committed", 0644)); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err = store.CommitTransaction(txCtx); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if _, err = store.FindEntry(ctx, "/tx/committed"); err != nil {
+		t.Errorf("find committed: %v", err)
+	}
+
+	if !store.supportsTransaction {
+		t.Log("transactions are not supported, skip rollback")
+		return
+	}
+
+	txCtx, err = store.BeginTransaction(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err = store.InsertEntry(txCtx, testEntry("/tx/rolled_back", 0644)); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err = store.RollbackTransaction(txCtx); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if _, err = store.FindEntry(ctx, "/tx/rolled_back"); err != filer_pb.ErrNotFound {
+		t.Errorf("find rolled back: %v", err)
+	}
+}
+
+func TestListConformance(t *testing.T) {
+	store := newTestStore(t)
+	defer cleanupTestStore(store)
+
+	storetest.TestListDirectoryEntries(t, store)
+}
@@ -0,0 +1,43 @@
+package mysql
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2/storetest"
+)
+
+// The tests run against a mysql test container, e.g.
+//   docker run -d -p 3306:3306 -e MYSQL_ALLOW_EMPTY_PASSWORD=yes -e MYSQL_DATABASE=seaweedfs mysql:5.7
+//   SEAWEEDFS_TEST_MYSQL_ADDRESS=localhost:3306 go test ./filer2/mysql/
+
+func TestListConformance(t *testing.T) {
+	address := os.Getenv("SEAWEEDFS_TEST_MYSQL_ADDRESS")
+	if address == "" {
+		t.Skip("SEAWEEDFS_TEST_MYSQL_ADDRESS is not set")
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatalf("parse %s: %v", address, err)
+	}
+	portNumber, _ := strconv.Atoi(port)
+	store := &MysqlStore{}
+	if err = store.initialize("root", "", host, portNumber, "seaweedfs", 2, 10, false); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	defer store.Shutdown()
+
+	if _, err = store.DB.Exec(`CREATE TABLE IF NOT EXISTS filemeta (
+		dirhash     BIGINT,
+		name        VARCHAR(1000) BINARY,
+		directory   TEXT,
+		meta        LONGBLOB,
+		PRIMARY KEY (dirhash, name)
+	) DEFAULT CHARSET=utf8`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	storetest.TestListDirectoryEntries(t, store)
+}
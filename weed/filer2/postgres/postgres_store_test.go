@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2/storetest"
+)
+
+// The tests run against a postgres test container, e.g.
+//   docker run -d -p 5432:5432 -e POSTGRES_HOST_AUTH_METHOD=trust -e POSTGRES_DB=seaweedfs postgres:12
+//   SEAWEEDFS_TEST_POSTGRES_ADDRESS=localhost:5432 go test ./filer2/postgres/
+
+func TestListConformance(t *testing.T) {
+	address := os.Getenv("SEAWEEDFS_TEST_POSTGRES_ADDRESS")
+	if address == "" {
+		t.Skip("SEAWEEDFS_TEST_POSTGRES_ADDRESS is not set")
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatalf("parse %s: %v", address, err)
+	}
+	portNumber, _ := strconv.Atoi(port)
+	store := &PostgresStore{}
+	if err = store.initialize("postgres", "", host, portNumber, "seaweedfs", "disable", 2, 10); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	defer store.Shutdown()
+
+	if _, err = store.DB.Exec(`CREATE TABLE IF NOT EXISTS filemeta (
+		dirhash     BIGINT,
+		name        VARCHAR(65535) COLLATE "C",
+		directory   VARCHAR(65535),
+		meta        bytea,
+		PRIMARY KEY (dirhash, name)
+	)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	storetest.TestListDirectoryEntries(t, store)
+}
@@ -23,6 +23,20 @@ type ChunkReadAt struct {
 	readerLock   sync.Mutex
 
 	chunkCache *chunk_cache.ChunkCache
+
+	// read ahead, only when the file is read sequentially
+	readAheadChunks int
+	readAheadLimit  int64 // the prefetched data kept in memory
+	lastReadStop    int64
+	prefetched      map[int64]*prefetchedChunk // by the logic offset of the chunk
+	prefetchedBytes int64
+}
+
+type prefetchedChunk struct {
+	size int64
+	done chan struct{}
+	data []byte
+	err  error
 }
 
 // var _ = io.ReaderAt(&ChunkReadAt{})
@@ -63,14 +77,39 @@ func NewChunkReaderAtFromClient(filerClient filer_pb.FilerClient, chunkViews []*
 		lookupFileId: LookupFn(filerClient),
 		bufferOffset: -1,
 		chunkCache:   chunkCache,
+		prefetched:   make(map[int64]*prefetchedChunk),
 	}
 }
 
+// SetReadAhead prefetches up to chunkCount chunks after the current one when the file is read sequentially,
+// keeping at most cacheLimit bytes of the prefetched chunks not read yet.
+func (c *ChunkReadAt) SetReadAhead(chunkCount int, cacheLimit int64) {
+
+	c.readerLock.Lock()
+	defer c.readerLock.Unlock()
+
+	c.readAheadChunks = chunkCount
+	c.readAheadLimit = cacheLimit
+}
+
 func (c *ChunkReadAt) ReadAt(p []byte, offset int64) (n int, err error) {
 
 	c.readerLock.Lock()
 	defer c.readerLock.Unlock()
 
+	sequential := offset == c.lastReadStop
+	if !sequential && !c.isBuffered(offset) {
+		// a random seek, the prefetched chunks are unlikely to be read
+		c.resetReadAhead()
+	}
+
+	defer func() {
+		c.lastReadStop = offset + int64(n)
+		if sequential && c.readAheadChunks > 0 {
+			c.readAhead(c.lastReadStop)
+		}
+	}()
+
 	for n < len(p) && err == nil {
 		readCount, readErr := c.doReadAt(p[n:], offset+int64(n))
 		n += readCount
@@ -82,6 +121,68 @@ func (c *ChunkReadAt) ReadAt(p []byte, offset int64) (n int, err error) {
 	return
 }
 
+func (c *ChunkReadAt) isBuffered(offset int64) bool {
+	return c.bufferOffset >= 0 && c.bufferOffset <= offset && offset < c.bufferOffset+int64(len(c.buffer))
+}
+
+// readAhead starts fetching the chunks after the offset, except the buffered one
+func (c *ChunkReadAt) readAhead(offset int64) {
+
+	scheduled := 0
+	for _, chunk := range c.chunkViews {
+		if chunk.LogicOffset+int64(chunk.Size) <= offset || chunk.LogicOffset == c.bufferOffset {
+			// the chunks behind are not going to be read
+			c.dropPrefetched(chunk.LogicOffset)
+			continue
+		}
+		if scheduled >= c.readAheadChunks {
+			break
+		}
+		scheduled++
+		if _, found := c.prefetched[chunk.LogicOffset]; found {
+			continue
+		}
+		if c.prefetchedBytes+int64(chunk.Size) > c.readAheadLimit {
+			break
+		}
+		p := &prefetchedChunk{size: int64(chunk.Size), done: make(chan struct{})}
+		c.prefetched[chunk.LogicOffset] = p
+		c.prefetchedBytes += p.size
+		go func(chunk *ChunkView) {
+			p.data, p.err = c.fetchChunkData(chunk)
+			close(p.done)
+		}(chunk)
+	}
+}
+
+func (c *ChunkReadAt) dropPrefetched(logicOffset int64) {
+	if p, found := c.prefetched[logicOffset]; found {
+		delete(c.prefetched, logicOffset)
+		c.prefetchedBytes -= p.size
+	}
+}
+
+// resetReadAhead forgets the prefetched chunks. The fetches in flight are not interrupted, and their data is dropped.
+func (c *ChunkReadAt) resetReadAhead() {
+	if len(c.prefetched) == 0 {
+		return
+	}
+	c.prefetched = make(map[int64]*prefetchedChunk)
+	c.prefetchedBytes = 0
+}
+
+// readChunk uses the prefetched data if any
+func (c *ChunkReadAt) readChunk(chunk *ChunkView) ([]byte, error) {
+	if p, found := c.prefetched[chunk.LogicOffset]; found {
+		<-p.done
+		c.dropPrefetched(chunk.LogicOffset)
+		if p.err == nil {
+			return p.data, nil
+		}
+	}
+	return c.fetchChunkData(chunk)
+}
+
 func (c *ChunkReadAt) doReadAt(p []byte, offset int64) (n int, err error) {
 
 	var found bool
@@ -89,7 +190,7 @@ func (c *ChunkReadAt) doReadAt(p []byte, offset int64) (n int, err error) {
 		if chunk.LogicOffset <= offset && offset < chunk.LogicOffset+int64(chunk.Size) {
 			found = true
 			if c.bufferOffset != chunk.LogicOffset {
-				c.buffer, err = c.fetchChunkData(chunk)
+				c.buffer, err = c.readChunk(chunk)
 				c.bufferOffset = chunk.LogicOffset
 			}
 			break
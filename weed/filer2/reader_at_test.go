@@ -0,0 +1,207 @@
+package filer2
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVolumeServer serves the chunks by file id, after the latency
+type fakeVolumeServer struct {
+	*httptest.Server
+	latency time.Duration
+	chunks  map[string][]byte
+	sync.Mutex
+	fetched []string
+}
+
+func newFakeVolumeServer(latency time.Duration) *fakeVolumeServer {
+	s := &fakeVolumeServer{latency: latency, chunks: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fileId := strings.TrimPrefix(r.URL.Path, "/")
+		s.Lock()
+		s.fetched = append(s.fetched, fileId)
+		data, found := s.chunks[fileId]
+		s.Unlock()
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		time.Sleep(s.latency)
+		w.Write(data)
+	}))
+	return s
+}
+
+func (s *fakeVolumeServer) fetchCount() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.fetched)
+}
+
+// newReader creates a file of the chunks, and returns the reader and the file content
+func (s *fakeVolumeServer) newReader(chunkSizes ...int) (*ChunkReadAt, []byte) {
+	var content []byte
+	var chunkViews []*ChunkView
+	for i, size := range chunkSizes {
+		fileId := fmt.Sprintf("1,%x", i+1)
+		data := make([]byte, size)
+		for j := range data {
+			data[j] = byte(len(content) + j)
+		}
+		s.chunks[fileId] = data
+		chunkViews = append(chunkViews, &ChunkView{
+			FileId:      fileId,
+			Size:        uint64(size),
+			LogicOffset: int64(len(content)),
+			ChunkSize:   uint64(size),
+		})
+		content = append(content, data...)
+	}
+	return &ChunkReadAt{
+		chunkViews: chunkViews,
+		lookupFileId: func(fileId string) (string, error) {
+			return s.URL + "/" + fileId, nil
+		},
+		bufferOffset: -1,
+		prefetched:   make(map[int64]*prefetchedChunk),
+	}, content
+}
+
+func readSequentially(t testing.TB, reader *ChunkReadAt, size int, bufferSize int) []byte {
+	var result []byte
+	buff := make([]byte, bufferSize)
+	for offset := 0; offset < size; {
+		n, err := reader.ReadAt(buff, int64(offset))
+		if err != nil {
+			t.Fatalf("read at %d: %v", offset, err)
+		}
+		if n == 0 {
+			break
+		}
+		result = append(result, buff[:n]...)
+		offset += n
+	}
+	return result
+}
+
+func TestReadAheadSequential(t *testing.T) {
+	server := newFakeVolumeServer(0)
+	defer server.Close()
+
+	reader, content := server.newReader(1000, 300, 1500, 700, 1000)
+	reader.SetReadAhead(2, 4096)
+
+	buff := make([]byte, 100)
+	if _, err := reader.ReadAt(buff, 0); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(reader.prefetched) != 2 || reader.prefetched[1000] == nil || reader.prefetched[1300] == nil {
+		t.Fatalf("unexpected prefetched chunks %+v", reader.prefetched)
+	}
+	if reader.prefetchedBytes != 300+1500 {
+		t.Errorf("prefetched bytes %d", reader.prefetchedBytes)
+	}
+
+	if result := readSequentially(t, reader, len(content), 128); !bytes.Equal(result, content) {
+		t.Fatalf("unexpected content")
+	}
+	if server.fetchCount() != 5 {
+		t.Errorf("fetched %d chunks, expect 5", server.fetchCount())
+	}
+}
+
+func TestReadAheadCacheLimit(t *testing.T) {
+	server := newFakeVolumeServer(0)
+	defer server.Close()
+
+	reader, _ := server.newReader(1000, 1000, 1000, 1000)
+	reader.SetReadAhead(3, 1500)
+
+	buff := make([]byte, 100)
+	if _, err := reader.ReadAt(buff, 0); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(reader.prefetched) != 1 || reader.prefetchedBytes != 1000 {
+		t.Errorf("prefetched %d chunks of %d bytes, expect 1 chunk within the limit", len(reader.prefetched), reader.prefetchedBytes)
+	}
+}
+
+func TestReadAheadResetOnSeek(t *testing.T) {
+	server := newFakeVolumeServer(0)
+	defer server.Close()
+
+	reader, content := server.newReader(1000, 1000, 1000, 1000, 1000)
+	reader.SetReadAhead(2, 4096)
+
+	buff := make([]byte, 100)
+	if _, err := reader.ReadAt(buff, 0); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(reader.prefetched) != 2 {
+		t.Fatalf("prefetched %d chunks, expect 2", len(reader.prefetched))
+	}
+
+	// reading within the buffered chunk is not a seek
+	if _, err := reader.ReadAt(buff, 500); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(reader.prefetched) != 2 {
+		t.Fatalf("prefetched %d chunks after reading the buffered chunk, expect 2", len(reader.prefetched))
+	}
+
+	n, err := reader.ReadAt(buff, 4200)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(buff[:n], content[4200:4300]) {
+		t.Errorf("unexpected content after seek")
+	}
+	if len(reader.prefetched) != 0 || reader.prefetchedBytes != 0 {
+		t.Errorf("prefetched %d chunks after the random seek, expect none", len(reader.prefetched))
+	}
+
+	// reading sequentially again restarts the read ahead
+	if _, err := reader.ReadAt(buff, 2000); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if _, err := reader.ReadAt(buff, 2100); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(reader.prefetched) != 2 || reader.prefetched[3000] == nil || reader.prefetched[4000] == nil {
+		t.Errorf("unexpected prefetched chunks %+v", reader.prefetched)
+	}
+}
+
+func benchmarkSequentialRead(b *testing.B, readAheadChunks int) {
+	server := newFakeVolumeServer(2 * time.Millisecond)
+	defer server.Close()
+
+	chunkSizes := make([]int, 32)
+	for i := range chunkSizes {
+		chunkSizes[i] = 256 * 1024
+	}
+	_, content := server.newReader(chunkSizes...)
+
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reader, _ := server.newReader(chunkSizes...)
+		reader.SetReadAhead(readAheadChunks, 64*1024*1024)
+		readSequentially(b, reader, len(content), 128*1024)
+	}
+}
+
+// go test -run none -bench SequentialRead ./filer2/
+func BenchmarkSequentialReadWithoutReadAhead(b *testing.B) {
+	benchmarkSequentialRead(b, 0)
+}
+
+func BenchmarkSequentialReadWithReadAhead(b *testing.B) {
+	benchmarkSequentialRead(b, 4)
+}
@@ -84,6 +84,83 @@ func (store *UniversalRedisStore) FindEntry(ctx context.Context, fullpath util.F
 	return entry, nil
 }
 
+// UpdateEntryIf watches the key of the entry, so the entry is only set if the key is not changed after the check,
+// and retries otherwise. The directory listing is updated after the entry, as InsertEntry does.
+func (store *UniversalRedisStore) UpdateEntryIf(ctx context.Context, entry *filer2.Entry, condition filer2.EntryCondition) error {
+
+	value, err := entry.EncodeAttributesAndChunks()
+	if err != nil {
+		return fmt.Errorf("encoding %s %+v: %v", entry.FullPath, entry.Attr, err)
+	}
+
+	err = store.changeEntryIf(entry.FullPath, condition, func(pipe redis.Pipeliner) {
+		pipe.Set(string(entry.FullPath), value, time.Duration(entry.TtlSec)*time.Second)
+	})
+	if err != nil {
+		return err
+	}
+
+	dir, name := entry.FullPath.DirAndName()
+	if name != "" {
+		if err = store.Client.SAdd(genDirectoryListKey(dir), name).Err(); err != nil {
+			return fmt.Errorf("persisting %s in parent dir: %v", entry.FullPath, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteEntryIf watches the key of the entry, so the entry is only deleted if the key is not changed after the check
+func (store *UniversalRedisStore) DeleteEntryIf(ctx context.Context, fullpath util.FullPath, condition filer2.EntryCondition) error {
+
+	err := store.changeEntryIf(fullpath, condition, func(pipe redis.Pipeliner) {
+		pipe.Del(string(fullpath))
+	})
+	if err != nil {
+		return err
+	}
+
+	dir, name := fullpath.DirAndName()
+	if name != "" {
+		if err = store.Client.SRem(genDirectoryListKey(dir), name).Err(); err != nil {
+			return fmt.Errorf("delete %s in parent dir: %v", fullpath, err)
+		}
+	}
+
+	return nil
+}
+
+func (store *UniversalRedisStore) changeEntryIf(fullpath util.FullPath, condition filer2.EntryCondition, change func(pipe redis.Pipeliner)) error {
+	for {
+		err := store.Client.Watch(func(tx *redis.Tx) error {
+			var existing *filer2.Entry
+			data, err := tx.Get(string(fullpath)).Result()
+			if err != nil && err != redis.Nil {
+				return fmt.Errorf("get %s : %v", fullpath, err)
+			}
+			if err == nil {
+				existing = &filer2.Entry{
+					FullPath: fullpath,
+				}
+				if err = existing.DecodeAttributesAndChunks([]byte(data)); err != nil {
+					return fmt.Errorf("decode %s : %v", fullpath, err)
+				}
+			}
+			if err = condition(existing); err != nil {
+				return err
+			}
+			_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+				change(pipe)
+				return nil
+			})
+			return err
+		}, string(fullpath))
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+}
+
 func (store *UniversalRedisStore) DeleteEntry(ctx context.Context, fullpath util.FullPath) (err error) {
 
 	_, err = store.Client.Del(string(fullpath)).Result()
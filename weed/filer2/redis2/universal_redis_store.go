@@ -79,6 +79,83 @@ func (store *UniversalRedis2Store) FindEntry(ctx context.Context, fullpath util.
 	return entry, nil
 }
 
+// UpdateEntryIf watches the key of the entry, so the entry is only set if the key is not changed after the check,
+// and retries otherwise. The directory listing is updated after the entry, as InsertEntry does.
+func (store *UniversalRedis2Store) UpdateEntryIf(ctx context.Context, entry *filer2.Entry, condition filer2.EntryCondition) error {
+
+	value, err := entry.EncodeAttributesAndChunks()
+	if err != nil {
+		return fmt.Errorf("encoding %s %+v: %v", entry.FullPath, entry.Attr, err)
+	}
+
+	err = store.changeEntryIf(entry.FullPath, condition, func(pipe redis.Pipeliner) {
+		pipe.Set(string(entry.FullPath), value, time.Duration(entry.TtlSec)*time.Second)
+	})
+	if err != nil {
+		return err
+	}
+
+	dir, name := entry.FullPath.DirAndName()
+	if name != "" {
+		if err = store.Client.ZAddNX(genDirectoryListKey(dir), redis.Z{Score: 0, Member: name}).Err(); err != nil {
+			return fmt.Errorf("persisting %s in parent dir: %v", entry.FullPath, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteEntryIf watches the key of the entry, so the entry is only deleted if the key is not changed after the check
+func (store *UniversalRedis2Store) DeleteEntryIf(ctx context.Context, fullpath util.FullPath, condition filer2.EntryCondition) error {
+
+	err := store.changeEntryIf(fullpath, condition, func(pipe redis.Pipeliner) {
+		pipe.Del(string(fullpath))
+	})
+	if err != nil {
+		return err
+	}
+
+	dir, name := fullpath.DirAndName()
+	if name != "" {
+		if err = store.Client.ZRem(genDirectoryListKey(dir), name).Err(); err != nil {
+			return fmt.Errorf("delete %s in parent dir: %v", fullpath, err)
+		}
+	}
+
+	return nil
+}
+
+func (store *UniversalRedis2Store) changeEntryIf(fullpath util.FullPath, condition filer2.EntryCondition, change func(pipe redis.Pipeliner)) error {
+	for {
+		err := store.Client.Watch(func(tx *redis.Tx) error {
+			var existing *filer2.Entry
+			data, err := tx.Get(string(fullpath)).Result()
+			if err != nil && err != redis.Nil {
+				return fmt.Errorf("get %s : %v", fullpath, err)
+			}
+			if err == nil {
+				existing = &filer2.Entry{
+					FullPath: fullpath,
+				}
+				if err = existing.DecodeAttributesAndChunks([]byte(data)); err != nil {
+					return fmt.Errorf("decode %s : %v", fullpath, err)
+				}
+			}
+			if err = condition(existing); err != nil {
+				return err
+			}
+			_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+				change(pipe)
+				return nil
+			})
+			return err
+		}, string(fullpath))
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+}
+
 func (store *UniversalRedis2Store) DeleteEntry(ctx context.Context, fullpath util.FullPath) (err error) {
 
 	_, err = store.Client.Del(string(fullpath)).Result()
@@ -120,14 +197,16 @@ func (store *UniversalRedis2Store) ListDirectoryEntries(ctx context.Context, ful
 	limit int) (entries []*filer2.Entry, err error) {
 
 	dirListKey := genDirectoryListKey(string(fullpath))
-	start := int64(0)
+	// the members all have the score 0, sorted by their bytes,
+	// and the start file may have been deleted since the previous page
+	start := "-"
 	if startFileName != "" {
-		start, _ = store.Client.ZRank(dirListKey, startFileName).Result()
-		if !inclusive {
-			start++
+		start = "(" + startFileName
+		if inclusive {
+			start = "[" + startFileName
 		}
 	}
-	members, err := store.Client.ZRange(dirListKey, start, start+int64(limit)-1).Result()
+	members, err := store.Client.ZRangeByLex(dirListKey, redis.ZRangeBy{Min: start, Max: "+", Count: int64(limit)}).Result()
 	if err != nil {
 		return nil, fmt.Errorf("list %s : %v", fullpath, err)
 	}
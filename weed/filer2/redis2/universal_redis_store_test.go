@@ -0,0 +1,24 @@
+package redis2
+
+import (
+	"os"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2/storetest"
+)
+
+// The tests run against a redis test container, e.g.
+//   docker run -d -p 6379:6379 redis:5
+//   SEAWEEDFS_TEST_REDIS_ADDRESS=localhost:6379 go test ./filer2/redis2/
+
+func TestListConformance(t *testing.T) {
+	address := os.Getenv("SEAWEEDFS_TEST_REDIS_ADDRESS")
+	if address == "" {
+		t.Skip("SEAWEEDFS_TEST_REDIS_ADDRESS is not set")
+	}
+	store := &Redis2Store{}
+	store.initialize(address, "", 0)
+	defer store.Shutdown()
+
+	storetest.TestListDirectoryEntries(t, store)
+}
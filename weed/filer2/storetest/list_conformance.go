@@ -0,0 +1,135 @@
+// Package storetest checks the filer stores behave the same, for the features the filer relies on.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// listingNames have mixed cases, digits, punctuation and multi-byte characters,
+// which a collation other than the byte order would sort differently
+var listingNames = []string{
+	"b", "B", "a", "A", "a0", "a b", "a.b", "a_b", "ab", "abc", "a-b", "10", "2", "1",
+	"_x", "~x", "Z", "z", "é", "e", "f", "日本", "Ω",
+}
+
+// TestListDirectoryEntries checks a store lists the entries of a directory in the byte order of their names,
+// and resumes the listing exactly after a name, even if that name was deleted since
+func TestListDirectoryEntries(t *testing.T, store filer2.FilerStore) {
+
+	ctx := context.Background()
+	dir := util.FullPath(fmt.Sprintf("/conformance%d", time.Now().UnixNano()))
+
+	// the neighbours, listed by neither the directory nor its sub directory
+	neighbours := []util.FullPath{dir + "x/a", dir + "/sub/a", util.FullPath(string(dir) + "0/a")}
+	var expected []string
+	for _, name := range listingNames {
+		insertEntry(t, store, dir.Child(name), 0644)
+		expected = append(expected, name)
+	}
+	insertEntry(t, store, dir.Child("sub"), os.ModeDir|0755)
+	expected = append(expected, "sub")
+	for _, p := range neighbours {
+		insertEntry(t, store, p, 0644)
+	}
+	sort.Strings(expected)
+	defer func() {
+		store.DeleteFolderChildren(ctx, dir)
+		for _, p := range neighbours {
+			store.DeleteEntry(ctx, p)
+		}
+		store.DeleteEntry(ctx, dir.Child("sub"))
+	}()
+
+	list := func(startFileName string, inclusive bool, limit int) (names []string) {
+		entries, err := store.ListDirectoryEntries(ctx, dir, startFileName, inclusive, limit)
+		if err != nil {
+			t.Fatalf("list %s from %q: %v", dir, startFileName, err)
+		}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return
+	}
+
+	if names := list("", false, 1000); fmt.Sprint(names) != fmt.Sprint(expected) {
+		t.Errorf("listed %q, expecting %q", names, expected)
+	}
+
+	// resume each page after the last name
+	for _, pageSize := range []int{1, 3, 7} {
+		var names []string
+		lastFileName := ""
+		for {
+			page := list(lastFileName, false, pageSize)
+			if len(page) > pageSize {
+				t.Fatalf("page size %d: listed %d", pageSize, len(page))
+			}
+			names = append(names, page...)
+			if len(page) < pageSize {
+				break
+			}
+			lastFileName = page[len(page)-1]
+		}
+		if fmt.Sprint(names) != fmt.Sprint(expected) {
+			t.Errorf("page size %d: listed %q, expecting %q", pageSize, names, expected)
+		}
+	}
+
+	for i, name := range expected {
+		if names := list(name, true, 2); fmt.Sprint(names) != fmt.Sprint(head(expected[i:], 2)) {
+			t.Errorf("from %q inclusive: listed %q, expecting %q", name, names, head(expected[i:], 2))
+		}
+		if names := list(name, false, 2); fmt.Sprint(names) != fmt.Sprint(head(expected[i+1:], 2)) {
+			t.Errorf("after %q: listed %q, expecting %q", name, names, head(expected[i+1:], 2))
+		}
+	}
+
+	// the last seen name is deleted before the next page
+	if err := store.DeleteEntry(ctx, dir.Child("ab")); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	for _, startFileName := range []string{"ab", "aa", "a~"} {
+		var after []string
+		for _, name := range expected {
+			if name > startFileName && name != "ab" {
+				after = append(after, name)
+			}
+		}
+		if names := list(startFileName, false, 3); fmt.Sprint(names) != fmt.Sprint(head(after, 3)) {
+			t.Errorf("after the missing %q: listed %q, expecting %q", startFileName, names, head(after, 3))
+		}
+		if names := list(startFileName, true, 3); fmt.Sprint(names) != fmt.Sprint(head(after, 3)) {
+			t.Errorf("from the missing %q: listed %q, expecting %q", startFileName, names, head(after, 3))
+		}
+	}
+}
+
+func insertEntry(t *testing.T, store filer2.FilerStore, p util.FullPath, mode os.FileMode) {
+	now := time.Now()
+	entry := &filer2.Entry{
+		FullPath: p,
+		Attr: filer2.Attr{
+			Mtime:  now,
+			Crtime: now,
+			Mode:   mode,
+		},
+	}
+	if err := store.InsertEntry(context.Background(), entry); err != nil {
+		t.Fatalf("insert %s: %v", p, err)
+	}
+}
+
+func head(names []string, n int) []string {
+	if len(names) > n {
+		return names[:n]
+	}
+	return names
+}
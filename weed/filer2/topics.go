@@ -1,6 +1,11 @@
 package filer2
 
 const (
-	TopicsDir    = "/topics"
-	SystemLogDir = TopicsDir + "/.system/log"
+	TopicsDir           = "/topics"
+	SystemDir           = TopicsDir + "/.system"
+	SystemLogDir        = SystemDir + "/log"
+	SystemRenameDir     = SystemDir + "/rename"
+	SystemDeleteDir     = SystemDir + "/delete"
+	SystemSubscriberDir = SystemDir + "/subscriber"
+	SystemChunkRefDir   = SystemDir + "/chunkref"
 )
@@ -5,6 +5,7 @@ import (
 	"context"
 	"os"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/chrislusf/seaweedfs/weed/filer2"
@@ -111,6 +112,10 @@ func (dir *Dir) newDirectory(fullpath util.FullPath, entry *filer_pb.Entry) fs.N
 func (dir *Dir) Create(ctx context.Context, req *fuse.CreateRequest,
 	resp *fuse.CreateResponse) (fs.Node, fs.Handle, error) {
 
+	if err := dir.checkModifyEntries(req.Header); err != nil {
+		return nil, nil, err
+	}
+
 	request := &filer_pb.CreateEntryRequest{
 		Directory: dir.FullPath(),
 		Entry: &filer_pb.Entry{
@@ -136,6 +141,12 @@ func (dir *Dir) Create(ctx context.Context, req *fuse.CreateRequest,
 			if strings.Contains(err.Error(), "EEXIST") {
 				return fuse.EEXIST
 			}
+			if err == filer_pb.ErrQuotaExceeded {
+				return fuse.Errno(syscall.ENOSPC)
+			}
+			if err == filer_pb.ErrNameTooLong {
+				return fuse.Errno(syscall.ENAMETOOLONG)
+			}
 			return fuse.EIO
 		}
 
@@ -165,6 +176,10 @@ func (dir *Dir) Mkdir(ctx context.Context, req *fuse.MkdirRequest) (fs.Node, err
 
 	glog.V(4).Infof("mkdir %s: %s", dir.FullPath(), req.Name)
 
+	if err := dir.checkModifyEntries(req.Header); err != nil {
+		return nil, err
+	}
+
 	newEntry := &filer_pb.Entry{
 		Name:        req.Name,
 		IsDirectory: true,
@@ -300,6 +315,19 @@ func (dir *Dir) ReadDirAll(ctx context.Context) (ret []fuse.Dirent, err error) {
 
 func (dir *Dir) Remove(ctx context.Context, req *fuse.RemoveRequest) error {
 
+	if err := dir.checkModifyEntries(req.Header); err != nil {
+		return err
+	}
+	if dir.wfs.option.CheckPermissions {
+		entry, err := dir.wfs.maybeLoadEntry(dir.FullPath(), req.Name)
+		if err != nil {
+			return err
+		}
+		if err = dir.wfs.checkSticky(dir.attributes(), entry.GetAttributes(), req.Header); err != nil {
+			return err
+		}
+	}
+
 	if !req.Dir {
 		return dir.removeOneFile(req)
 	}
@@ -319,8 +347,6 @@ func (dir *Dir) removeOneFile(req *fuse.RemoveRequest) error {
 		return nil
 	}
 
-	dir.wfs.deleteFileChunks(entry.Chunks)
-
 	dir.wfs.cacheDelete(filePath)
 	dir.wfs.fsNodeCache.DeleteFsNode(filePath)
 
@@ -329,7 +355,8 @@ func (dir *Dir) removeOneFile(req *fuse.RemoveRequest) error {
 	}
 
 	glog.V(3).Infof("remove file: %v", req)
-	err = filer_pb.Remove(dir.wfs, dir.FullPath(), req.Name, false, false, false)
+	// the filer deletes the chunks, unless they are shared with copied files
+	err = filer_pb.Remove(dir.wfs, dir.FullPath(), req.Name, true, false, false)
 	if err != nil {
 		glog.V(3).Infof("not found remove file %s/%s: %v", dir.FullPath(), req.Name, err)
 		return fuse.ENOENT
@@ -368,6 +395,10 @@ func (dir *Dir) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fus
 		return err
 	}
 
+	if err := dir.wfs.checkSetattr(dir.entry.Attributes, req); err != nil {
+		return err
+	}
+
 	if req.Valid.Mode() {
 		dir.entry.Attributes.FileMode = uint32(req.Mode)
 	}
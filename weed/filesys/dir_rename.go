@@ -2,6 +2,7 @@ package filesys
 
 import (
 	"context"
+	"syscall"
 
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
@@ -19,6 +20,22 @@ func (dir *Dir) Rename(ctx context.Context, req *fuse.RenameRequest, newDirector
 
 	glog.V(4).Infof("dir Rename %s => %s", oldPath, newPath)
 
+	if err := dir.checkModifyEntries(req.Header); err != nil {
+		return err
+	}
+	if err := newDir.checkModifyEntries(req.Header); err != nil {
+		return err
+	}
+	if dir.wfs.option.CheckPermissions {
+		entry, err := dir.wfs.maybeLoadEntry(dir.FullPath(), req.OldName)
+		if err != nil {
+			return err
+		}
+		if err = dir.wfs.checkSticky(dir.attributes(), entry.GetAttributes(), req.Header); err != nil {
+			return err
+		}
+	}
+
 	err := dir.wfs.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
 
 		request := &filer_pb.AtomicRenameEntryRequest{
@@ -28,9 +45,15 @@ func (dir *Dir) Rename(ctx context.Context, req *fuse.RenameRequest, newDirector
 			NewName:      req.NewName,
 		}
 
-		_, err := client.AtomicRenameEntry(context.Background(), request)
+		err := filer_pb.AtomicRenameEntry(client, request)
 		if err != nil {
 			glog.V(0).Infof("dir Rename %s => %s : %v", oldPath, newPath, err)
+			if err == filer_pb.ErrRenameTargetExists {
+				return fuse.EEXIST
+			}
+			if err == filer_pb.ErrRenameTargetNotEmpty {
+				return fuse.Errno(syscall.ENOTEMPTY)
+			}
 			return fuse.EIO
 		}
 
@@ -51,16 +51,27 @@ func (pages *ContinuousDirtyPages) AddPage(offset int64, data []byte) (chunks []
 	var chunk *filer_pb.FileChunk
 	var hasSavedData bool
 
-	if pages.intervals.TotalSize() > pages.f.wfs.option.ChunkSizeLimit {
+	// upload the continuous intervals as soon as they fill a chunk,
+	// and the largest ones when the write buffer is full
+	for pages.intervals.LargestIntervalSize() >= pages.f.wfs.option.ChunkSizeLimit ||
+		pages.intervals.TotalSize() > pages.writeBufferSize() {
 		chunk, hasSavedData, err = pages.saveExistingLargestPageToStorage()
-		if hasSavedData {
-			chunks = append(chunks, chunk)
+		if !hasSavedData || err != nil {
+			break
 		}
+		chunks = append(chunks, chunk)
 	}
 
 	return
 }
 
+func (pages *ContinuousDirtyPages) writeBufferSize() int64 {
+	if pages.f.wfs.option.WriteBufferSize > 0 {
+		return pages.f.wfs.option.WriteBufferSize
+	}
+	return pages.f.wfs.option.ChunkSizeLimit
+}
+
 func (pages *ContinuousDirtyPages) flushAndSave(offset int64, data []byte) (chunks []*filer_pb.FileChunk, err error) {
 
 	var chunk *filer_pb.FileChunk
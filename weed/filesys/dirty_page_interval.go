@@ -152,6 +152,15 @@ func (c *ContinuousIntervals) AddInterval(data []byte, offset int64) {
 	return
 }
 
+func (c *ContinuousIntervals) LargestIntervalSize() (maxSize int64) {
+	for _, list := range c.lists {
+		if maxSize < list.Size() {
+			maxSize = list.Size()
+		}
+	}
+	return
+}
+
 func (c *ContinuousIntervals) RemoveLargestIntervalLinkedList() *IntervalLinkedList {
 	var maxSize int64
 	maxIndex := -1
@@ -66,6 +66,25 @@ func TestContinuousIntervals_RealCase1(t *testing.T) {
 
 }
 
+func TestContinuousIntervals_LargestIntervalSize(t *testing.T) {
+
+	c := &ContinuousIntervals{}
+
+	// 1, 1, _, _, 2, 2, 2
+	c.AddInterval(getBytes(1, 2), 0)
+	c.AddInterval(getBytes(2, 3), 4)
+	if size := c.LargestIntervalSize(); size != 3 {
+		t.Errorf("expected largest interval size 3, actual %d", size)
+	}
+
+	//  _,  _, 3, 3
+	c.AddInterval(getBytes(3, 2), 2)
+	if size := c.LargestIntervalSize(); size != 7 {
+		t.Errorf("expected coalesced interval size 7, actual %d", size)
+	}
+
+}
+
 func expectedData(t *testing.T, c *ContinuousIntervals, offset int, data ...byte) {
 	start, stop := int64(offset), int64(offset+len(data))
 	for _, list := range c.lists {
@@ -54,7 +54,7 @@ func (file *File) Attr(ctx context.Context, attr *fuse.Attr) error {
 	attr.Inode = file.fullpath().AsInode()
 	attr.Valid = time.Second
 	attr.Mode = os.FileMode(file.entry.Attributes.FileMode)
-	attr.Size = filer2.TotalSize(file.entry.Chunks)
+	attr.Size = filer2.FileSize(file.entry)
 	if file.isOpen > 0 {
 		attr.Size = file.entry.Attributes.FileSize
 		glog.V(4).Infof("file Attr %s, open:%v, size: %d", file.fullpath(), file.isOpen, attr.Size)
@@ -85,6 +85,15 @@ func (file *File) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.Op
 
 	glog.V(4).Infof("file %v open %+v", file.fullpath(), req)
 
+	if file.isOpen <= 0 {
+		if err := file.maybeLoadEntry(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := file.wfs.checkPermission(file.entry.Attributes, req.Header, openFlagsToPermission(req.Flags)); err != nil {
+		return nil, err
+	}
+
 	file.isOpen++
 
 	handle := file.wfs.AcquireHandle(file, req.Uid, req.Gid)
@@ -99,15 +108,22 @@ func (file *File) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.Op
 
 func (file *File) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *fuse.SetattrResponse) error {
 
+	if err := file.maybeLoadEntry(ctx); err != nil {
+		return err
+	}
+
 	glog.V(3).Infof("%v file setattr %+v, old:%+v", file.fullpath(), req, file.entry.Attributes)
 
-	if err := file.maybeLoadEntry(ctx); err != nil {
+	if err := file.wfs.checkSetattr(file.entry.Attributes, req); err != nil {
 		return err
 	}
 
 	if req.Valid.Size() {
 
 		glog.V(3).Infof("%v file setattr set size=%v", file.fullpath(), req.Size)
+		if req.Size < uint64(len(file.entry.Content)) {
+			file.entry.Content = file.entry.Content[:req.Size]
+		}
 		if req.Size < filer2.TotalSize(file.entry.Chunks) {
 			// fmt.Printf("truncate %v \n", fullPath)
 			var chunks []*filer_pb.FileChunk
@@ -124,6 +140,9 @@ func (file *File) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *f
 			file.entryViewCache = nil
 			file.reader = nil
 		}
+		if req.Size != file.entry.Attributes.FileSize {
+			file.contentChanged()
+		}
 		file.entry.Attributes.FileSize = req.Size
 	}
 	if req.Valid.Mode() {
@@ -146,12 +165,18 @@ func (file *File) Setattr(ctx context.Context, req *fuse.SetattrRequest, resp *f
 		file.entry.Attributes.Mtime = req.Mtime.Unix()
 	}
 
+	file.wfs.cacheDelete(file.fullpath())
+
 	if file.isOpen > 0 {
-		return nil
+		// save together with the chunks written by the open handle
+		if fh, found := file.wfs.getHandle(file.fullpath()); found {
+			fh.Lock()
+			defer fh.Unlock()
+			fh.dirtyMetadata = true
+			return fh.doFlush(req.Header.Uid, req.Header.Gid)
+		}
 	}
 
-	file.wfs.cacheDelete(file.fullpath())
-
 	return file.saveEntry()
 
 }
@@ -213,7 +238,15 @@ func (file *File) Fsync(ctx context.Context, req *fuse.FsyncRequest) error {
 	// write the file chunks to the filerGrpcAddress
 	glog.V(3).Infof("%s/%s fsync file %+v", file.dir.FullPath(), file.Name, req)
 
-	return nil
+	fh, found := file.wfs.getHandle(file.fullpath())
+	if !found {
+		return nil
+	}
+
+	fh.Lock()
+	defer fh.Unlock()
+
+	return fh.doFlush(req.Uid, req.Gid)
 }
 
 func (file *File) Forget() {
@@ -255,6 +288,17 @@ func (file *File) addChunks(chunks []*filer_pb.FileChunk) {
 	glog.V(3).Infof("%s existing %d chunks adds %d more", file.fullpath(), len(file.entry.Chunks), len(chunks))
 
 	file.entry.Chunks = append(file.entry.Chunks, chunks...)
+	if len(chunks) > 0 {
+		file.contentChanged()
+	}
+}
+
+// contentChanged drops the md5 and the etag, e.g. of a s3 multipart upload, which no longer match the content
+func (file *File) contentChanged() {
+	if file.entry.Attributes != nil {
+		file.entry.Attributes.Md5 = nil
+	}
+	delete(file.entry.Extended, filer2.ETagKey)
 }
 
 func (file *File) setEntry(entry *filer_pb.Entry) {
@@ -0,0 +1,33 @@
+package filesys
+
+import (
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+func TestAddChunksDropsETag(t *testing.T) {
+	file := &File{
+		Name: "a.txt",
+		dir:  &Dir{name: "/"},
+		entry: &filer_pb.Entry{
+			Attributes: &filer_pb.FuseAttributes{Md5: []byte{1, 2, 3}},
+			Extended:   map[string][]byte{filer2.ETagKey: []byte("abc-2"), "user.key": []byte("value")},
+		},
+	}
+
+	file.addChunks(nil)
+	if filer2.ETag(file.entry) != "abc-2" {
+		t.Errorf("etag changed without new chunks: %s", filer2.ETag(file.entry))
+	}
+
+	chunk := &filer_pb.FileChunk{FileId: "1,2", Size: 3, Mtime: 1, ETag: "chunk"}
+	file.addChunks([]*filer_pb.FileChunk{chunk})
+	if _, found := file.entry.Extended[filer2.ETagKey]; found || file.entry.Attributes.Md5 != nil {
+		t.Errorf("stale etag %v md5 %v", file.entry.Extended, file.entry.Attributes.Md5)
+	}
+	if filer2.ETag(file.entry) != "chunk" || string(file.entry.Extended["user.key"]) != "value" {
+		t.Errorf("etag %s, extended %v", filer2.ETag(file.entry), file.entry.Extended)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/chrislusf/seaweedfs/weed/filer2"
@@ -19,8 +21,12 @@ type FileHandle struct {
 	dirtyPages    *ContinuousDirtyPages
 	contentType   string
 	dirtyMetadata bool
+	dirtySince    time.Time // when the unflushed data or metadata was first written
 	handle        uint64
 
+	// serializes the reads, writes and flushes, including the periodic flushes
+	sync.Mutex
+
 	f         *File
 	RequestId fuse.RequestID // unique ID for request
 	NodeId    fuse.NodeID    // file or directory the request is about
@@ -37,7 +43,7 @@ func newFileHandle(file *File, uid, gid uint32) *FileHandle {
 		Gid:        gid,
 	}
 	if fh.f.entry != nil {
-		fh.f.entry.Attributes.FileSize = filer2.TotalSize(fh.f.entry.Chunks)
+		fh.f.entry.Attributes.FileSize = filer2.FileSize(fh.f.entry)
 	}
 	return fh
 }
@@ -54,6 +60,9 @@ func (fh *FileHandle) Read(ctx context.Context, req *fuse.ReadRequest, resp *fus
 
 	glog.V(4).Infof("%s read fh %d: [%d,%d)", fh.f.fullpath(), fh.handle, req.Offset, req.Offset+int64(req.Size))
 
+	fh.Lock()
+	defer fh.Unlock()
+
 	buff := make([]byte, req.Size)
 
 	totalRead, err := fh.readFromChunks(buff, req.Offset)
@@ -80,6 +89,13 @@ func (fh *FileHandle) readFromDirtyPages(buff []byte, startOffset int64) (offset
 
 func (fh *FileHandle) readFromChunks(buff []byte, offset int64) (int64, error) {
 
+	if len(fh.f.entry.Content) > 0 {
+		if offset >= int64(len(fh.f.entry.Content)) {
+			return 0, nil
+		}
+		return int64(copy(buff, fh.f.entry.Content[offset:])), nil
+	}
+
 	// this value should come from the filer instead of the old f
 	if len(fh.f.entry.Chunks) == 0 {
 		glog.V(1).Infof("empty fh %v", fh.f.fullpath())
@@ -93,7 +109,9 @@ func (fh *FileHandle) readFromChunks(buff []byte, offset int64) (int64, error) {
 
 	if fh.f.reader == nil {
 		chunkViews := filer2.ViewFromVisibleIntervals(fh.f.entryViewCache, 0, math.MaxInt32)
-		fh.f.reader = filer2.NewChunkReaderAtFromClient(fh.f.wfs, chunkViews, fh.f.wfs.chunkCache)
+		reader := filer2.NewChunkReaderAtFromClient(fh.f.wfs, chunkViews, fh.f.wfs.chunkCache)
+		reader.SetReadAhead(fh.f.wfs.option.ReadAheadChunks, fh.f.wfs.option.ReadAheadCacheSize)
+		fh.f.reader = reader
 	}
 
 	totalRead, err := fh.f.reader.ReadAt(buff, offset)
@@ -110,13 +128,32 @@ func (fh *FileHandle) readFromChunks(buff []byte, offset int64) (int64, error) {
 // Write to the file handle
 func (fh *FileHandle) Write(ctx context.Context, req *fuse.WriteRequest, resp *fuse.WriteResponse) error {
 
+	fh.Lock()
+	defer fh.Unlock()
+
+	if fh.dirtySince.IsZero() {
+		fh.dirtySince = time.Now()
+	}
+
 	// write the request to volume servers
 	data := make([]byte, len(req.Data))
 	copy(data, req.Data)
 
 	fh.f.entry.Attributes.FileSize = uint64(max(req.Offset+int64(len(data)), int64(fh.f.entry.Attributes.FileSize)))
+	fh.f.contentChanged()
 	// glog.V(0).Infof("%v write [%d,%d)", fh.f.fullpath(), req.Offset, req.Offset+int64(len(req.Data)))
 
+	// the content saved inside the entry is written to the chunks together with the changes
+	if len(fh.f.entry.Content) > 0 {
+		chunks, err := fh.dirtyPages.AddPage(0, fh.f.entry.Content)
+		if err != nil {
+			glog.Errorf("%v write fh %d content: %v", fh.f.fullpath(), fh.handle, err)
+			return fuse.EIO
+		}
+		fh.f.entry.Content = nil
+		fh.f.addChunks(chunks)
+	}
+
 	chunks, err := fh.dirtyPages.AddPage(req.Offset, data)
 	if err != nil {
 		glog.Errorf("%v write fh %d: [%d,%d): %v", fh.f.fullpath(), fh.handle, req.Offset, req.Offset+int64(len(data)), err)
@@ -162,6 +199,30 @@ func (fh *FileHandle) Flush(ctx context.Context, req *fuse.FlushRequest) error {
 	// send the data to the OS
 	glog.V(4).Infof("%s fh %d flush %v", fh.f.fullpath(), fh.handle, req)
 
+	fh.Lock()
+	defer fh.Unlock()
+
+	return fh.doFlush(req.Uid, req.Gid)
+}
+
+// flushIfDirtyFor uploads the dirty pages and commits the metadata written earlier than the duration
+func (fh *FileHandle) flushIfDirtyFor(duration time.Duration) error {
+
+	fh.Lock()
+	defer fh.Unlock()
+
+	if fh.dirtySince.IsZero() || time.Since(fh.dirtySince) < duration {
+		return nil
+	}
+
+	glog.V(4).Infof("%s fh %d flush data dirty since %v", fh.f.fullpath(), fh.handle, fh.dirtySince)
+
+	return fh.doFlush(fh.Uid, fh.Gid)
+}
+
+// doFlush returns after all written data is saved on the volume servers and the file entry is saved on the filer
+func (fh *FileHandle) doFlush(uid, gid uint32) error {
+
 	chunks, err := fh.dirtyPages.FlushToStorage()
 	if err != nil {
 		glog.Errorf("flush %s: %v", fh.f.fullpath(), err)
@@ -174,20 +235,28 @@ func (fh *FileHandle) Flush(ctx context.Context, req *fuse.FlushRequest) error {
 	}
 
 	if !fh.dirtyMetadata {
+		fh.dirtySince = time.Time{}
 		return nil
 	}
 
 	err = fh.f.wfs.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
 
 		if fh.f.entry.Attributes != nil {
-			fh.f.entry.Attributes.Mime = fh.contentType
-			fh.f.entry.Attributes.Uid = req.Uid
-			fh.f.entry.Attributes.Gid = req.Gid
+			if fh.contentType != "" {
+				fh.f.entry.Attributes.Mime = fh.contentType
+			}
+			// the ownership and mode are set on creation, or changed by setattr
+			if fh.f.entry.Attributes.Crtime == 0 {
+				fh.f.entry.Attributes.Uid = uid
+				fh.f.entry.Attributes.Gid = gid
+				fh.f.entry.Attributes.Crtime = time.Now().Unix()
+				fh.f.entry.Attributes.FileMode = uint32(0666 &^ fh.f.wfs.option.Umask)
+			}
 			fh.f.entry.Attributes.Mtime = time.Now().Unix()
-			fh.f.entry.Attributes.Crtime = time.Now().Unix()
-			fh.f.entry.Attributes.FileMode = uint32(0666 &^ fh.f.wfs.option.Umask)
-			fh.f.entry.Attributes.Collection = fh.dirtyPages.collection
-			fh.f.entry.Attributes.Replication = fh.dirtyPages.replication
+			if fh.dirtyPages.collection != "" {
+				fh.f.entry.Attributes.Collection = fh.dirtyPages.collection
+				fh.f.entry.Attributes.Replication = fh.dirtyPages.replication
+			}
 		}
 
 		request := &filer_pb.CreateEntryRequest{
@@ -200,20 +269,23 @@ func (fh *FileHandle) Flush(ctx context.Context, req *fuse.FlushRequest) error {
 			glog.V(3).Infof("%s chunks %d: %v [%d,%d)", fh.f.fullpath(), i, chunk.FileId, chunk.Offset, chunk.Offset+int64(chunk.Size))
 		}
 
+		// the filer compacts the chunks again, and deletes the garbage chunks unless they are shared with copied files
 		chunks, garbages := filer2.CompactFileChunks(fh.f.entry.Chunks)
-		fh.f.entry.Chunks = chunks
 		// fh.f.entryViewCache = nil
 
 		if err := filer_pb.CreateEntry(client, request); err != nil {
 			glog.Errorf("fh flush create %s: %v", fh.f.fullpath(), err)
+			if err == filer_pb.ErrQuotaExceeded {
+				return err
+			}
 			return fmt.Errorf("fh flush create %s: %v", fh.f.fullpath(), err)
 		}
+		fh.f.entry.Chunks = chunks
 
 		if fh.f.wfs.option.AsyncMetaDataCaching {
 			fh.f.wfs.metaCache.InsertEntry(context.Background(), filer2.FromPbEntry(request.Directory, request.Entry))
 		}
 
-		fh.f.wfs.deleteFileChunks(garbages)
 		for i, chunk := range garbages {
 			glog.V(3).Infof("garbage %s chunks %d: %v [%d,%d)", fh.f.fullpath(), i, chunk.FileId, chunk.Offset, chunk.Offset+int64(chunk.Size))
 		}
@@ -223,8 +295,13 @@ func (fh *FileHandle) Flush(ctx context.Context, req *fuse.FlushRequest) error {
 
 	if err == nil {
 		fh.dirtyMetadata = false
+		fh.dirtySince = time.Time{}
 	}
 
+	if err == filer_pb.ErrQuotaExceeded {
+		glog.Errorf("%v fh %d flush: %v", fh.f.fullpath(), fh.handle, err)
+		return fuse.Errno(syscall.ENOSPC)
+	}
 	if err != nil {
 		glog.Errorf("%v fh %d flush: %v", fh.f.fullpath(), fh.handle, err)
 		return fuse.EIO
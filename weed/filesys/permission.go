@@ -0,0 +1,109 @@
+package filesys
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/seaweedfs/fuse"
+)
+
+const (
+	permissionRead    = 4
+	permissionWrite   = 2
+	permissionExecute = 1
+)
+
+// hasPermission checks the unix permission bits against the caller, the same way as the kernel does for local files.
+// FUSE only passes the primary group of the caller, so the supplementary groups are not checked.
+func hasPermission(attributes *filer_pb.FuseAttributes, uid, gid uint32, mask uint32) bool {
+	if uid == 0 {
+		return true
+	}
+	perm := uint32(os.FileMode(attributes.FileMode).Perm())
+	switch {
+	case uid == attributes.Uid:
+		perm = perm >> 6
+	case gid == attributes.Gid:
+		perm = perm >> 3
+	}
+	return perm&mask == mask
+}
+
+func openFlagsToPermission(flags fuse.OpenFlags) (mask uint32) {
+	if !flags.IsWriteOnly() {
+		mask |= permissionRead
+	}
+	if !flags.IsReadOnly() || flags&fuse.OpenTruncate != 0 {
+		mask |= permissionWrite
+	}
+	return
+}
+
+func (wfs *WFS) checkPermission(attributes *filer_pb.FuseAttributes, header fuse.Header, mask uint32) error {
+	if !wfs.option.CheckPermissions || attributes == nil {
+		return nil
+	}
+	if !hasPermission(attributes, header.Uid, header.Gid, mask) {
+		return fuse.Errno(syscall.EACCES)
+	}
+	return nil
+}
+
+// checkSetattr allows the owner to change the mode, times, and the group to its own group,
+// and only root to change the owner
+func (wfs *WFS) checkSetattr(attributes *filer_pb.FuseAttributes, req *fuse.SetattrRequest) error {
+	if !wfs.option.CheckPermissions || attributes == nil || req.Header.Uid == 0 {
+		return nil
+	}
+	isOwner := req.Header.Uid == attributes.Uid
+	if req.Valid.Uid() && req.Uid != attributes.Uid {
+		return fuse.EPERM
+	}
+	if req.Valid.Gid() && req.Gid != attributes.Gid && (!isOwner || req.Gid != req.Header.Gid) {
+		return fuse.EPERM
+	}
+	if (req.Valid.Mode() || req.Valid.Crtime()) && !isOwner {
+		return fuse.EPERM
+	}
+	if req.Valid.Mtime() && !isOwner && !hasPermission(attributes, req.Header.Uid, req.Header.Gid, permissionWrite) {
+		return fuse.EPERM
+	}
+	if req.Valid.Size() && !hasPermission(attributes, req.Header.Uid, req.Header.Gid, permissionWrite) {
+		return fuse.Errno(syscall.EACCES)
+	}
+	return nil
+}
+
+func (dir *Dir) attributes() *filer_pb.FuseAttributes {
+	if dir.FullPath() == dir.wfs.option.FilerMountRootPath {
+		return &filer_pb.FuseAttributes{
+			Uid:      dir.wfs.option.MountUid,
+			Gid:      dir.wfs.option.MountGid,
+			FileMode: uint32(dir.wfs.option.MountMode),
+		}
+	}
+	if err := dir.maybeLoadEntry(); err != nil || dir.entry == nil {
+		return nil
+	}
+	return dir.entry.Attributes
+}
+
+// checkModifyEntries checks the permission to create, remove, or rename the entries in the directory
+func (dir *Dir) checkModifyEntries(header fuse.Header) error {
+	return dir.wfs.checkPermission(dir.attributes(), header, permissionWrite|permissionExecute)
+}
+
+// checkSticky only allows the owners of the directory or the entry to remove or rename the entry in a sticky directory
+func (wfs *WFS) checkSticky(dirAttributes, attributes *filer_pb.FuseAttributes, header fuse.Header) error {
+	if !wfs.option.CheckPermissions || dirAttributes == nil || attributes == nil || header.Uid == 0 {
+		return nil
+	}
+	if os.FileMode(dirAttributes.FileMode)&os.ModeSticky == 0 {
+		return nil
+	}
+	if header.Uid != dirAttributes.Uid && header.Uid != attributes.Uid {
+		return fuse.EPERM
+	}
+	return nil
+}
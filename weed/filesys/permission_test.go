@@ -0,0 +1,173 @@
+package filesys
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/seaweedfs/fuse"
+	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// fakeFiler keeps the entries in memory, and only implements the lookup, create and update
+type fakeFiler struct {
+	filer_pb.SeaweedFilerServer
+	sync.Mutex
+	entries map[util.FullPath]*filer_pb.Entry
+}
+
+func (f *fakeFiler) LookupDirectoryEntry(ctx context.Context, req *filer_pb.LookupDirectoryEntryRequest) (*filer_pb.LookupDirectoryEntryResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	entry, found := f.entries[util.NewFullPath(req.Directory, req.Name)]
+	if !found {
+		return nil, filer_pb.ErrNotFound
+	}
+	return &filer_pb.LookupDirectoryEntryResponse{Entry: proto.Clone(entry).(*filer_pb.Entry)}, nil
+}
+
+func (f *fakeFiler) CreateEntry(ctx context.Context, req *filer_pb.CreateEntryRequest) (*filer_pb.CreateEntryResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.entries[util.NewFullPath(req.Directory, req.Entry.Name)] = proto.Clone(req.Entry).(*filer_pb.Entry)
+	return &filer_pb.CreateEntryResponse{}, nil
+}
+
+func (f *fakeFiler) UpdateEntry(ctx context.Context, req *filer_pb.UpdateEntryRequest) (*filer_pb.UpdateEntryResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.entries[util.NewFullPath(req.Directory, req.Entry.Name)] = proto.Clone(req.Entry).(*filer_pb.Entry)
+	return &filer_pb.UpdateEntryResponse{}, nil
+}
+
+func startFakeFiler(t *testing.T, checkPermissions bool, entries ...*filer_pb.Entry) (*WFS, func()) {
+	filer := &fakeFiler{entries: make(map[util.FullPath]*filer_pb.Entry)}
+	for _, entry := range entries {
+		filer.entries[util.NewFullPath("/", entry.Name)] = entry
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	filer_pb.RegisterSeaweedFilerServer(grpcServer, filer)
+	go grpcServer.Serve(listener)
+
+	wfs := NewSeaweedFileSystem(&Option{
+		FilerGrpcAddress:   listener.Addr().String(),
+		GrpcDialOption:     grpc.WithInsecure(),
+		FilerMountRootPath: "/",
+		ChunkSizeLimit:     1024,
+		DirListCacheLimit:  100,
+		MountUid:           0,
+		MountGid:           0,
+		MountMode:          os.ModeDir | 0777,
+		CheckPermissions:   checkPermissions,
+	})
+	return wfs, grpcServer.Stop
+}
+
+func loadFileAttr(t *testing.T, wfs *WFS, name string) (attr fuse.Attr) {
+	file := &File{Name: name, dir: wfs.root.(*Dir), wfs: wfs}
+	if err := file.Attr(context.Background(), &attr); err != nil {
+		t.Fatalf("attr %s: %v", name, err)
+	}
+	return
+}
+
+func TestFileSetattrRoundTrip(t *testing.T) {
+	wfs, stop := startFakeFiler(t, false, &filer_pb.Entry{
+		Name:       "a.txt",
+		Attributes: &filer_pb.FuseAttributes{FileMode: 0644, Uid: 1000, Gid: 1000, Crtime: 1, Mtime: 1},
+	})
+	defer stop()
+
+	attr := loadFileAttr(t, wfs, "a.txt")
+	if attr.Mode != 0644 || attr.Uid != 1000 || attr.Gid != 1000 {
+		t.Fatalf("unexpected attributes %+v", attr)
+	}
+
+	mtime := time.Unix(1500000000, 0)
+	file := &File{Name: "a.txt", dir: wfs.root.(*Dir), wfs: wfs}
+	err := file.Setattr(context.Background(), &fuse.SetattrRequest{
+		Valid: fuse.SetattrMode | fuse.SetattrUid | fuse.SetattrGid | fuse.SetattrMtime,
+		Mode:  0750,
+		Uid:   1001,
+		Gid:   1002,
+		Mtime: mtime,
+	}, &fuse.SetattrResponse{})
+	if err != nil {
+		t.Fatalf("setattr: %v", err)
+	}
+
+	attr = loadFileAttr(t, wfs, "a.txt")
+	if attr.Mode != 0750 || attr.Uid != 1001 || attr.Gid != 1002 || !attr.Mtime.Equal(mtime) {
+		t.Errorf("setattr is not saved: %+v", attr)
+	}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	wfs, stop := startFakeFiler(t, true, &filer_pb.Entry{
+		Name:       "a.txt",
+		Attributes: &filer_pb.FuseAttributes{FileMode: 0640, Uid: 1000, Gid: 1000, Crtime: 1, Mtime: 1},
+	})
+	defer stop()
+
+	owner := fuse.Header{Uid: 1000, Gid: 1000}
+	groupMember := fuse.Header{Uid: 1001, Gid: 1000}
+	other := fuse.Header{Uid: 1002, Gid: 1002}
+
+	open := func(header fuse.Header, flags fuse.OpenFlags) error {
+		file := &File{Name: "a.txt", dir: wfs.root.(*Dir), wfs: wfs}
+		_, err := file.Open(context.Background(), &fuse.OpenRequest{Header: header, Flags: flags}, &fuse.OpenResponse{})
+		if err == nil {
+			wfs.ReleaseHandle(file.fullpath(), 0)
+		}
+		return err
+	}
+	if err := open(owner, fuse.OpenReadWrite); err != nil {
+		t.Errorf("owner open read write: %v", err)
+	}
+	if err := open(groupMember, fuse.OpenReadOnly); err != nil {
+		t.Errorf("group open read only: %v", err)
+	}
+	if err := open(groupMember, fuse.OpenWriteOnly); err != fuse.Errno(syscall.EACCES) {
+		t.Errorf("group open write only: %v", err)
+	}
+	if err := open(other, fuse.OpenReadOnly); err != fuse.Errno(syscall.EACCES) {
+		t.Errorf("other open read only: %v", err)
+	}
+
+	chmod := func(header fuse.Header) error {
+		file := &File{Name: "a.txt", dir: wfs.root.(*Dir), wfs: wfs}
+		return file.Setattr(context.Background(), &fuse.SetattrRequest{Header: header, Valid: fuse.SetattrMode, Mode: 0644}, &fuse.SetattrResponse{})
+	}
+	if err := chmod(groupMember); err != fuse.EPERM {
+		t.Errorf("non owner chmod: %v", err)
+	}
+	if err := chmod(owner); err != nil {
+		t.Errorf("owner chmod: %v", err)
+	}
+	if err := open(other, fuse.OpenReadOnly); err != nil {
+		t.Errorf("other open read only after chmod: %v", err)
+	}
+
+	chown := &fuse.SetattrRequest{Header: owner, Valid: fuse.SetattrUid, Uid: 1001}
+	file := &File{Name: "a.txt", dir: wfs.root.(*Dir), wfs: wfs}
+	if err := file.Setattr(context.Background(), chown, &fuse.SetattrResponse{}); err != fuse.EPERM {
+		t.Errorf("non root chown: %v", err)
+	}
+	chown.Header = fuse.Header{Uid: 0}
+	if err := file.Setattr(context.Background(), chown, &fuse.SetattrResponse{}); err != nil {
+		t.Errorf("root chown: %v", err)
+	}
+}
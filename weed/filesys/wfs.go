@@ -24,6 +24,9 @@ import (
 	"github.com/seaweedfs/fuse/fs"
 )
 
+// MinWriteFlushInterval is the shortest periodic flush interval of the open files
+const MinWriteFlushInterval = time.Second
+
 type Option struct {
 	FilerGrpcAddress   string
 	GrpcDialOption     grpc.DialOption
@@ -32,8 +35,12 @@ type Option struct {
 	Replication        string
 	TtlSec             int32
 	ChunkSizeLimit     int64
+	WriteBufferSize    int64         // dirty data buffered per file before uploading
+	WriteFlushInterval time.Duration // upload and commit the dirty data written earlier than this, at least MinWriteFlushInterval, 0 to disable
 	CacheDir           string
 	CacheSizeMB        int64
+	ReadAheadChunks    int   // chunks prefetched ahead of sequential reads, 0 to disable
+	ReadAheadCacheSize int64 // prefetched data kept in memory per open file
 	DataCenter         string
 	DirListCacheLimit  int64
 	EntryCacheTtl      time.Duration
@@ -48,6 +55,7 @@ type Option struct {
 	OutsideContainerClusterMode bool // whether the mount runs outside SeaweedFS containers
 	Cipher                      bool // whether encrypt data on volume server
 	AsyncMetaDataCaching        bool // whether asynchronously cache meta data
+	CheckPermissions            bool // whether check the unix permissions of the callers
 
 }
 
@@ -110,6 +118,13 @@ func NewSeaweedFileSystem(option *Option) *WFS {
 	wfs.root = &Dir{name: wfs.option.FilerMountRootPath, wfs: wfs}
 	wfs.fsNodeCache = newFsCache(wfs.root)
 
+	if wfs.option.WriteFlushInterval > 0 {
+		if wfs.option.WriteFlushInterval < MinWriteFlushInterval {
+			wfs.option.WriteFlushInterval = MinWriteFlushInterval
+		}
+		go wfs.loopFlushDirtyHandles()
+	}
+
 	return wfs
 }
 
@@ -155,6 +170,32 @@ func (wfs *WFS) AcquireHandle(file *File, uid, gid uint32) (fileHandle *FileHand
 	return
 }
 
+func (wfs *WFS) getHandle(fullpath util.FullPath) (fileHandle *FileHandle, found bool) {
+	wfs.handlesLock.Lock()
+	defer wfs.handlesLock.Unlock()
+
+	fileHandle, found = wfs.handles[fullpath.AsInode()]
+	return fileHandle, found && fileHandle != nil
+}
+
+// loopFlushDirtyHandles bounds how long written data stays only in the mount process memory
+func (wfs *WFS) loopFlushDirtyHandles() {
+	for range time.Tick(wfs.option.WriteFlushInterval / 2) {
+		wfs.handlesLock.Lock()
+		var fileHandles []*FileHandle
+		for _, fh := range wfs.handles {
+			fileHandles = append(fileHandles, fh)
+		}
+		wfs.handlesLock.Unlock()
+
+		for _, fh := range fileHandles {
+			if err := fh.flushIfDirtyFor(wfs.option.WriteFlushInterval); err != nil {
+				glog.Errorf("%v periodic flush: %v", fh.f.fullpath(), err)
+			}
+		}
+	}
+}
+
 func (wfs *WFS) ReleaseHandle(fullpath util.FullPath, handleId fuse.HandleID) {
 	wfs.handlesLock.Lock()
 	defer wfs.handlesLock.Unlock()
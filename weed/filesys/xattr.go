@@ -2,7 +2,9 @@ package filesys
 
 import (
 	"context"
+	"syscall"
 
+	"github.com/chrislusf/seaweedfs/weed/filer2"
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
 	"github.com/chrislusf/seaweedfs/weed/util"
@@ -14,10 +16,7 @@ func getxattr(entry *filer_pb.Entry, req *fuse.GetxattrRequest, resp *fuse.Getxa
 	if entry == nil {
 		return fuse.ErrNoXattr
 	}
-	if entry.Extended == nil {
-		return fuse.ErrNoXattr
-	}
-	data, found := entry.Extended[req.Name]
+	data, found := filer2.GetXattr(entry.Extended, req.Name)
 	if !found {
 		return fuse.ErrNoXattr
 	}
@@ -46,7 +45,7 @@ func setxattr(entry *filer_pb.Entry, req *fuse.SetxattrRequest) error {
 	if entry.Extended == nil {
 		entry.Extended = make(map[string][]byte)
 	}
-	data, _ := entry.Extended[req.Name]
+	data, _ := filer2.GetXattr(entry.Extended, req.Name)
 
 	newData := make([]byte, int(req.Position)+len(req.Xattr))
 
@@ -54,9 +53,7 @@ func setxattr(entry *filer_pb.Entry, req *fuse.SetxattrRequest) error {
 
 	copy(newData[int(req.Position):], req.Xattr)
 
-	entry.Extended[req.Name] = newData
-
-	return nil
+	return toFuseXattrError(filer2.SetXattr(entry.Extended, req.Name, newData))
 
 }
 
@@ -66,19 +63,7 @@ func removexattr(entry *filer_pb.Entry, req *fuse.RemovexattrRequest) error {
 		return fuse.ErrNoXattr
 	}
 
-	if entry.Extended == nil {
-		return fuse.ErrNoXattr
-	}
-
-	_, found := entry.Extended[req.Name]
-
-	if !found {
-		return fuse.ErrNoXattr
-	}
-
-	delete(entry.Extended, req.Name)
-
-	return nil
+	return toFuseXattrError(filer2.RemoveXattr(entry.Extended, req.Name))
 
 }
 
@@ -88,8 +73,8 @@ func listxattr(entry *filer_pb.Entry, req *fuse.ListxattrRequest, resp *fuse.Lis
 		return fuse.EIO
 	}
 
-	for k := range entry.Extended {
-		resp.Append(k)
+	for _, name := range filer2.ListXattrs(entry.Extended) {
+		resp.Append(name)
 	}
 
 	size := req.Size
@@ -107,6 +92,23 @@ func listxattr(entry *filer_pb.Entry, req *fuse.ListxattrRequest, resp *fuse.Lis
 
 }
 
+// toFuseXattrError returns the errno of the xattr system calls for the errors of the filer
+func toFuseXattrError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case filer_pb.ErrXattrNotFound:
+		return fuse.ErrNoXattr
+	case filer_pb.ErrXattrInvalidName:
+		return fuse.ERANGE
+	case filer_pb.ErrXattrTooLarge:
+		return fuse.Errno(syscall.E2BIG)
+	case filer_pb.ErrXattrNoSpace:
+		return fuse.Errno(syscall.ENOSPC)
+	}
+	return fuse.EIO
+}
+
 func (wfs *WFS) maybeLoadEntry(dir, name string) (entry *filer_pb.Entry, err error) {
 
 	fullpath := util.NewFullPath(dir, name)
@@ -21,6 +21,8 @@ type VolumeAssignRequest struct {
 	Rack                string
 	DataNode            string
 	WritableVolumeCount uint32
+	DiskType            string
+	AppendOnly          bool
 }
 
 type AssignResult struct {
@@ -57,6 +59,8 @@ func Assign(server string, grpcDialOption grpc.DialOption, primaryRequest *Volum
 				Rack:                primaryRequest.Rack,
 				DataNode:            primaryRequest.DataNode,
 				WritableVolumeCount: primaryRequest.WritableVolumeCount,
+				DiskType:            primaryRequest.DiskType,
+				AppendOnly:          primaryRequest.AppendOnly,
 			}
 			resp, grpcErr := masterClient.Assign(context.Background(), req)
 			if grpcErr != nil {
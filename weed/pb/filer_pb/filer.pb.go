@@ -48,6 +48,14 @@ It has these top-level messages:
 	KeepConnectedResponse
 	LocateBrokerRequest
 	LocateBrokerResponse
+	AckMetadataRequest
+	AckMetadataResponse
+	CopyEntryRequest
+	CopyEntryResponse
+	GetXattrsRequest
+	GetXattrsResponse
+	UpdateXattrsRequest
+	UpdateXattrsResponse
 */
 package filer_pb
 
@@ -117,6 +125,8 @@ type ListEntriesRequest struct {
 	StartFromFileName  string `protobuf:"bytes,3,opt,name=startFromFileName" json:"startFromFileName,omitempty"`
 	InclusiveStartFrom bool   `protobuf:"varint,4,opt,name=inclusiveStartFrom" json:"inclusiveStartFrom,omitempty"`
 	Limit              uint32 `protobuf:"varint,5,opt,name=limit" json:"limit,omitempty"`
+	// resumes a previous listing of the directory, instead of startFromFileName and inclusiveStartFrom
+	Cursor string `protobuf:"bytes,6,opt,name=cursor" json:"cursor,omitempty"`
 }
 
 func (m *ListEntriesRequest) Reset()                    { *m = ListEntriesRequest{} }
@@ -159,8 +169,17 @@ func (m *ListEntriesRequest) GetLimit() uint32 {
 	return 0
 }
 
+func (m *ListEntriesRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
 type ListEntriesResponse struct {
 	Entry *Entry `protobuf:"bytes,1,opt,name=entry" json:"entry,omitempty"`
+	// resumes the listing after this entry
+	Cursor string `protobuf:"bytes,2,opt,name=cursor" json:"cursor,omitempty"`
 }
 
 func (m *ListEntriesResponse) Reset()                    { *m = ListEntriesResponse{} }
@@ -175,12 +194,20 @@ func (m *ListEntriesResponse) GetEntry() *Entry {
 	return nil
 }
 
+func (m *ListEntriesResponse) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
 type Entry struct {
 	Name        string            `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	IsDirectory bool              `protobuf:"varint,2,opt,name=is_directory,json=isDirectory" json:"is_directory,omitempty"`
 	Chunks      []*FileChunk      `protobuf:"bytes,3,rep,name=chunks" json:"chunks,omitempty"`
 	Attributes  *FuseAttributes   `protobuf:"bytes,4,opt,name=attributes" json:"attributes,omitempty"`
 	Extended    map[string][]byte `protobuf:"bytes,5,rep,name=extended" json:"extended,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Content     []byte            `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
 }
 
 func (m *Entry) Reset()                    { *m = Entry{} }
@@ -223,6 +250,13 @@ func (m *Entry) GetExtended() map[string][]byte {
 	return nil
 }
 
+func (m *Entry) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
 type FullEntry struct {
 	Dir   string `protobuf:"bytes,1,opt,name=dir" json:"dir,omitempty"`
 	Entry *Entry `protobuf:"bytes,2,opt,name=entry" json:"entry,omitempty"`
@@ -531,6 +565,9 @@ type CreateEntryRequest struct {
 	Directory string `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
 	Entry     *Entry `protobuf:"bytes,2,opt,name=entry" json:"entry,omitempty"`
 	OExcl     bool   `protobuf:"varint,3,opt,name=o_excl,json=oExcl" json:"o_excl,omitempty"`
+	// create or overwrite only if the etag of the existing entry matches, as the http If-Match and If-None-Match
+	IfMatch     string `protobuf:"bytes,4,opt,name=if_match,json=ifMatch" json:"if_match,omitempty"`
+	IfNoneMatch string `protobuf:"bytes,5,opt,name=if_none_match,json=ifNoneMatch" json:"if_none_match,omitempty"`
 }
 
 func (m *CreateEntryRequest) Reset()                    { *m = CreateEntryRequest{} }
@@ -559,6 +596,20 @@ func (m *CreateEntryRequest) GetOExcl() bool {
 	return false
 }
 
+func (m *CreateEntryRequest) GetIfMatch() string {
+	if m != nil {
+		return m.IfMatch
+	}
+	return ""
+}
+
+func (m *CreateEntryRequest) GetIfNoneMatch() string {
+	if m != nil {
+		return m.IfNoneMatch
+	}
+	return ""
+}
+
 type CreateEntryResponse struct {
 	Error string `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
 }
@@ -654,6 +705,8 @@ type DeleteEntryRequest struct {
 	IsDeleteData         bool `protobuf:"varint,4,opt,name=is_delete_data,json=isDeleteData" json:"is_delete_data,omitempty"`
 	IsRecursive          bool `protobuf:"varint,5,opt,name=is_recursive,json=isRecursive" json:"is_recursive,omitempty"`
 	IgnoreRecursiveError bool `protobuf:"varint,6,opt,name=ignore_recursive_error,json=ignoreRecursiveError" json:"ignore_recursive_error,omitempty"`
+	// delete one entry at a time, throttled and resumed after a filer restart
+	IsDeleteTree bool `protobuf:"varint,7,opt,name=is_delete_tree,json=isDeleteTree" json:"is_delete_tree,omitempty"`
 }
 
 func (m *DeleteEntryRequest) Reset()                    { *m = DeleteEntryRequest{} }
@@ -696,6 +749,13 @@ func (m *DeleteEntryRequest) GetIgnoreRecursiveError() bool {
 	return false
 }
 
+func (m *DeleteEntryRequest) GetIsDeleteTree() bool {
+	if m != nil {
+		return m.IsDeleteTree
+	}
+	return false
+}
+
 type DeleteEntryResponse struct {
 	Error string `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
 }
@@ -767,6 +827,7 @@ type AssignVolumeRequest struct {
 	TtlSec      int32  `protobuf:"varint,4,opt,name=ttl_sec,json=ttlSec" json:"ttl_sec,omitempty"`
 	DataCenter  string `protobuf:"bytes,5,opt,name=data_center,json=dataCenter" json:"data_center,omitempty"`
 	ParentPath  string `protobuf:"bytes,6,opt,name=parent_path,json=parentPath" json:"parent_path,omitempty"`
+	DiskType    string `protobuf:"bytes,7,opt,name=disk_type,json=diskType" json:"disk_type,omitempty"`
 }
 
 func (m *AssignVolumeRequest) Reset()                    { *m = AssignVolumeRequest{} }
@@ -816,6 +877,13 @@ func (m *AssignVolumeRequest) GetParentPath() string {
 	return ""
 }
 
+func (m *AssignVolumeRequest) GetDiskType() string {
+	if m != nil {
+		return m.DiskType
+	}
+	return ""
+}
+
 type AssignVolumeResponse struct {
 	FileId      string `protobuf:"bytes,1,opt,name=file_id,json=fileId" json:"file_id,omitempty"`
 	Url         string `protobuf:"bytes,2,opt,name=url" json:"url,omitempty"`
@@ -1137,9 +1205,10 @@ func (m *GetFilerConfigurationResponse) GetCipher() bool {
 }
 
 type SubscribeMetadataRequest struct {
-	ClientName string `protobuf:"bytes,1,opt,name=client_name,json=clientName" json:"client_name,omitempty"`
-	PathPrefix string `protobuf:"bytes,2,opt,name=path_prefix,json=pathPrefix" json:"path_prefix,omitempty"`
-	SinceNs    int64  `protobuf:"varint,3,opt,name=since_ns,json=sinceNs" json:"since_ns,omitempty"`
+	ClientName     string `protobuf:"bytes,1,opt,name=client_name,json=clientName" json:"client_name,omitempty"`
+	PathPrefix     string `protobuf:"bytes,2,opt,name=path_prefix,json=pathPrefix" json:"path_prefix,omitempty"`
+	SinceNs        int64  `protobuf:"varint,3,opt,name=since_ns,json=sinceNs" json:"since_ns,omitempty"`
+	SubscriberName string `protobuf:"bytes,4,opt,name=subscriber_name,json=subscriberName" json:"subscriber_name,omitempty"`
 }
 
 func (m *SubscribeMetadataRequest) Reset()                    { *m = SubscribeMetadataRequest{} }
@@ -1168,6 +1237,13 @@ func (m *SubscribeMetadataRequest) GetSinceNs() int64 {
 	return 0
 }
 
+func (m *SubscribeMetadataRequest) GetSubscriberName() string {
+	if m != nil {
+		return m.SubscriberName
+	}
+	return ""
+}
+
 type SubscribeMetadataResponse struct {
 	Directory         string             `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
 	EventNotification *EventNotification `protobuf:"bytes,2,opt,name=event_notification,json=eventNotification" json:"event_notification,omitempty"`
@@ -1340,6 +1416,190 @@ func (m *LocateBrokerResponse_Resource) GetResourceCount() int32 {
 	return 0
 }
 
+type AckMetadataRequest struct {
+	SubscriberName string `protobuf:"bytes,1,opt,name=subscriber_name,json=subscriberName" json:"subscriber_name,omitempty"`
+	TsNs           int64  `protobuf:"varint,2,opt,name=ts_ns,json=tsNs" json:"ts_ns,omitempty"`
+}
+
+func (m *AckMetadataRequest) Reset()                    { *m = AckMetadataRequest{} }
+func (m *AckMetadataRequest) String() string            { return proto.CompactTextString(m) }
+func (*AckMetadataRequest) ProtoMessage()               {}
+func (*AckMetadataRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *AckMetadataRequest) GetSubscriberName() string {
+	if m != nil {
+		return m.SubscriberName
+	}
+	return ""
+}
+
+func (m *AckMetadataRequest) GetTsNs() int64 {
+	if m != nil {
+		return m.TsNs
+	}
+	return 0
+}
+
+type AckMetadataResponse struct {
+	TsNs int64 `protobuf:"varint,1,opt,name=ts_ns,json=tsNs" json:"ts_ns,omitempty"`
+}
+
+func (m *AckMetadataResponse) Reset()                    { *m = AckMetadataResponse{} }
+func (m *AckMetadataResponse) String() string            { return proto.CompactTextString(m) }
+func (*AckMetadataResponse) ProtoMessage()               {}
+func (*AckMetadataResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *AckMetadataResponse) GetTsNs() int64 {
+	if m != nil {
+		return m.TsNs
+	}
+	return 0
+}
+
+type CopyEntryRequest struct {
+	SourceDirectory string `protobuf:"bytes,1,opt,name=source_directory,json=sourceDirectory" json:"source_directory,omitempty"`
+	SourceName      string `protobuf:"bytes,2,opt,name=source_name,json=sourceName" json:"source_name,omitempty"`
+	TargetDirectory string `protobuf:"bytes,3,opt,name=target_directory,json=targetDirectory" json:"target_directory,omitempty"`
+	TargetName      string `protobuf:"bytes,4,opt,name=target_name,json=targetName" json:"target_name,omitempty"`
+}
+
+func (m *CopyEntryRequest) Reset()                    { *m = CopyEntryRequest{} }
+func (m *CopyEntryRequest) String() string            { return proto.CompactTextString(m) }
+func (*CopyEntryRequest) ProtoMessage()               {}
+func (*CopyEntryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *CopyEntryRequest) GetSourceDirectory() string {
+	if m != nil {
+		return m.SourceDirectory
+	}
+	return ""
+}
+
+func (m *CopyEntryRequest) GetSourceName() string {
+	if m != nil {
+		return m.SourceName
+	}
+	return ""
+}
+
+func (m *CopyEntryRequest) GetTargetDirectory() string {
+	if m != nil {
+		return m.TargetDirectory
+	}
+	return ""
+}
+
+func (m *CopyEntryRequest) GetTargetName() string {
+	if m != nil {
+		return m.TargetName
+	}
+	return ""
+}
+
+type CopyEntryResponse struct {
+	Entry *Entry `protobuf:"bytes,1,opt,name=entry" json:"entry,omitempty"`
+}
+
+func (m *CopyEntryResponse) Reset()                    { *m = CopyEntryResponse{} }
+func (m *CopyEntryResponse) String() string            { return proto.CompactTextString(m) }
+func (*CopyEntryResponse) ProtoMessage()               {}
+func (*CopyEntryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *CopyEntryResponse) GetEntry() *Entry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+type GetXattrsRequest struct {
+	Directory string `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	Name      string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *GetXattrsRequest) Reset()                    { *m = GetXattrsRequest{} }
+func (m *GetXattrsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetXattrsRequest) ProtoMessage()               {}
+func (*GetXattrsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *GetXattrsRequest) GetDirectory() string {
+	if m != nil {
+		return m.Directory
+	}
+	return ""
+}
+
+func (m *GetXattrsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetXattrsResponse struct {
+	Xattrs map[string][]byte `protobuf:"bytes,1,rep,name=xattrs" json:"xattrs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *GetXattrsResponse) Reset()                    { *m = GetXattrsResponse{} }
+func (m *GetXattrsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetXattrsResponse) ProtoMessage()               {}
+func (*GetXattrsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *GetXattrsResponse) GetXattrs() map[string][]byte {
+	if m != nil {
+		return m.Xattrs
+	}
+	return nil
+}
+
+type UpdateXattrsRequest struct {
+	Directory string            `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	Name      string            `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Set       map[string][]byte `protobuf:"bytes,3,rep,name=set" json:"set,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Remove    []string          `protobuf:"bytes,4,rep,name=remove" json:"remove,omitempty"`
+}
+
+func (m *UpdateXattrsRequest) Reset()                    { *m = UpdateXattrsRequest{} }
+func (m *UpdateXattrsRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateXattrsRequest) ProtoMessage()               {}
+func (*UpdateXattrsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *UpdateXattrsRequest) GetDirectory() string {
+	if m != nil {
+		return m.Directory
+	}
+	return ""
+}
+
+func (m *UpdateXattrsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UpdateXattrsRequest) GetSet() map[string][]byte {
+	if m != nil {
+		return m.Set
+	}
+	return nil
+}
+
+func (m *UpdateXattrsRequest) GetRemove() []string {
+	if m != nil {
+		return m.Remove
+	}
+	return nil
+}
+
+type UpdateXattrsResponse struct {
+}
+
+func (m *UpdateXattrsResponse) Reset()                    { *m = UpdateXattrsResponse{} }
+func (m *UpdateXattrsResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateXattrsResponse) ProtoMessage()               {}
+func (*UpdateXattrsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
 func init() {
 	proto.RegisterType((*LookupDirectoryEntryRequest)(nil), "filer_pb.LookupDirectoryEntryRequest")
 	proto.RegisterType((*LookupDirectoryEntryResponse)(nil), "filer_pb.LookupDirectoryEntryResponse")
@@ -1381,6 +1641,14 @@ func init() {
 	proto.RegisterType((*LocateBrokerRequest)(nil), "filer_pb.LocateBrokerRequest")
 	proto.RegisterType((*LocateBrokerResponse)(nil), "filer_pb.LocateBrokerResponse")
 	proto.RegisterType((*LocateBrokerResponse_Resource)(nil), "filer_pb.LocateBrokerResponse.Resource")
+	proto.RegisterType((*AckMetadataRequest)(nil), "filer_pb.AckMetadataRequest")
+	proto.RegisterType((*AckMetadataResponse)(nil), "filer_pb.AckMetadataResponse")
+	proto.RegisterType((*CopyEntryRequest)(nil), "filer_pb.CopyEntryRequest")
+	proto.RegisterType((*CopyEntryResponse)(nil), "filer_pb.CopyEntryResponse")
+	proto.RegisterType((*GetXattrsRequest)(nil), "filer_pb.GetXattrsRequest")
+	proto.RegisterType((*GetXattrsResponse)(nil), "filer_pb.GetXattrsResponse")
+	proto.RegisterType((*UpdateXattrsRequest)(nil), "filer_pb.UpdateXattrsRequest")
+	proto.RegisterType((*UpdateXattrsResponse)(nil), "filer_pb.UpdateXattrsResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1409,6 +1677,10 @@ type SeaweedFilerClient interface {
 	SubscribeMetadata(ctx context.Context, in *SubscribeMetadataRequest, opts ...grpc.CallOption) (SeaweedFiler_SubscribeMetadataClient, error)
 	KeepConnected(ctx context.Context, opts ...grpc.CallOption) (SeaweedFiler_KeepConnectedClient, error)
 	LocateBroker(ctx context.Context, in *LocateBrokerRequest, opts ...grpc.CallOption) (*LocateBrokerResponse, error)
+	AckMetadata(ctx context.Context, in *AckMetadataRequest, opts ...grpc.CallOption) (*AckMetadataResponse, error)
+	CopyEntry(ctx context.Context, in *CopyEntryRequest, opts ...grpc.CallOption) (*CopyEntryResponse, error)
+	GetXattrs(ctx context.Context, in *GetXattrsRequest, opts ...grpc.CallOption) (*GetXattrsResponse, error)
+	UpdateXattrs(ctx context.Context, in *UpdateXattrsRequest, opts ...grpc.CallOption) (*UpdateXattrsResponse, error)
 }
 
 type seaweedFilerClient struct {
@@ -1622,6 +1894,42 @@ func (c *seaweedFilerClient) LocateBroker(ctx context.Context, in *LocateBrokerR
 	return out, nil
 }
 
+func (c *seaweedFilerClient) AckMetadata(ctx context.Context, in *AckMetadataRequest, opts ...grpc.CallOption) (*AckMetadataResponse, error) {
+	out := new(AckMetadataResponse)
+	err := grpc.Invoke(ctx, "/filer_pb.SeaweedFiler/AckMetadata", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seaweedFilerClient) CopyEntry(ctx context.Context, in *CopyEntryRequest, opts ...grpc.CallOption) (*CopyEntryResponse, error) {
+	out := new(CopyEntryResponse)
+	err := grpc.Invoke(ctx, "/filer_pb.SeaweedFiler/CopyEntry", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seaweedFilerClient) GetXattrs(ctx context.Context, in *GetXattrsRequest, opts ...grpc.CallOption) (*GetXattrsResponse, error) {
+	out := new(GetXattrsResponse)
+	err := grpc.Invoke(ctx, "/filer_pb.SeaweedFiler/GetXattrs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seaweedFilerClient) UpdateXattrs(ctx context.Context, in *UpdateXattrsRequest, opts ...grpc.CallOption) (*UpdateXattrsResponse, error) {
+	out := new(UpdateXattrsResponse)
+	err := grpc.Invoke(ctx, "/filer_pb.SeaweedFiler/UpdateXattrs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for SeaweedFiler service
 
 type SeaweedFilerServer interface {
@@ -1640,6 +1948,10 @@ type SeaweedFilerServer interface {
 	SubscribeMetadata(*SubscribeMetadataRequest, SeaweedFiler_SubscribeMetadataServer) error
 	KeepConnected(SeaweedFiler_KeepConnectedServer) error
 	LocateBroker(context.Context, *LocateBrokerRequest) (*LocateBrokerResponse, error)
+	AckMetadata(context.Context, *AckMetadataRequest) (*AckMetadataResponse, error)
+	CopyEntry(context.Context, *CopyEntryRequest) (*CopyEntryResponse, error)
+	GetXattrs(context.Context, *GetXattrsRequest) (*GetXattrsResponse, error)
+	UpdateXattrs(context.Context, *UpdateXattrsRequest) (*UpdateXattrsResponse, error)
 }
 
 func RegisterSeaweedFilerServer(s *grpc.Server, srv SeaweedFilerServer) {
@@ -1930,6 +2242,78 @@ func _SeaweedFiler_LocateBroker_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SeaweedFiler_AckMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeaweedFilerServer).AckMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filer_pb.SeaweedFiler/AckMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeaweedFilerServer).AckMetadata(ctx, req.(*AckMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SeaweedFiler_CopyEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeaweedFilerServer).CopyEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filer_pb.SeaweedFiler/CopyEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeaweedFilerServer).CopyEntry(ctx, req.(*CopyEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SeaweedFiler_GetXattrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetXattrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeaweedFilerServer).GetXattrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filer_pb.SeaweedFiler/GetXattrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeaweedFilerServer).GetXattrs(ctx, req.(*GetXattrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SeaweedFiler_UpdateXattrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateXattrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeaweedFilerServer).UpdateXattrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filer_pb.SeaweedFiler/UpdateXattrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeaweedFilerServer).UpdateXattrs(ctx, req.(*UpdateXattrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SeaweedFiler_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "filer_pb.SeaweedFiler",
 	HandlerType: (*SeaweedFilerServer)(nil),
@@ -1982,6 +2366,22 @@ var _SeaweedFiler_serviceDesc = grpc.ServiceDesc{
 			MethodName: "LocateBroker",
 			Handler:    _SeaweedFiler_LocateBroker_Handler,
 		},
+		{
+			MethodName: "AckMetadata",
+			Handler:    _SeaweedFiler_AckMetadata_Handler,
+		},
+		{
+			MethodName: "CopyEntry",
+			Handler:    _SeaweedFiler_CopyEntry_Handler,
+		},
+		{
+			MethodName: "GetXattrs",
+			Handler:    _SeaweedFiler_GetXattrs_Handler,
+		},
+		{
+			MethodName: "UpdateXattrs",
+			Handler:    _SeaweedFiler_UpdateXattrs_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2007,139 +2407,161 @@ var _SeaweedFiler_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("filer.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2142 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x59, 0x5f, 0x6f, 0xdb, 0xc8,
-	0x11, 0x37, 0x25, 0x4b, 0x16, 0x47, 0x52, 0xce, 0x5e, 0x3b, 0x89, 0xa2, 0xc4, 0x89, 0x8f, 0x69,
-	0xee, 0x5c, 0x24, 0x70, 0x53, 0xf7, 0x0a, 0xdc, 0xf5, 0xda, 0x07, 0xc7, 0x71, 0xae, 0x69, 0x12,
-	0x9f, 0x41, 0x27, 0x87, 0x2b, 0x0a, 0x94, 0xa5, 0xc9, 0xb5, 0xbc, 0x35, 0x45, 0xb2, 0xbb, 0x4b,
-	0xff, 0xb9, 0xa7, 0xfb, 0x1c, 0x05, 0xfa, 0xda, 0x4f, 0xd0, 0xc7, 0xa2, 0x2f, 0x45, 0x81, 0x02,
-	0x45, 0xbf, 0x44, 0x3f, 0x49, 0xb1, 0xb3, 0x24, 0xb5, 0x14, 0x25, 0xfb, 0x82, 0xc3, 0xbd, 0x71,
-	0x67, 0x66, 0x67, 0x67, 0xe7, 0xcf, 0x6f, 0x66, 0x25, 0xe8, 0x1e, 0xb3, 0x88, 0xf2, 0xad, 0x94,
-	0x27, 0x32, 0x21, 0x1d, 0x5c, 0x78, 0xe9, 0x91, 0xf3, 0x25, 0xdc, 0x7d, 0x9d, 0x24, 0xa7, 0x59,
-	0xfa, 0x9c, 0x71, 0x1a, 0xc8, 0x84, 0x5f, 0xee, 0xc5, 0x92, 0x5f, 0xba, 0xf4, 0x4f, 0x19, 0x15,
-	0x92, 0xdc, 0x03, 0x3b, 0x2c, 0x18, 0x03, 0x6b, 0xc3, 0xda, 0xb4, 0xdd, 0x09, 0x81, 0x10, 0x58,
-	0x8c, 0xfd, 0x31, 0x1d, 0x34, 0x90, 0x81, 0xdf, 0xce, 0x1e, 0xdc, 0x9b, 0xad, 0x50, 0xa4, 0x49,
-	0x2c, 0x28, 0x79, 0x04, 0x2d, 0xaa, 0x08, 0xa8, 0xad, 0xbb, 0xfd, 0xc1, 0x56, 0x61, 0xca, 0x96,
-	0x96, 0xd3, 0x5c, 0xe7, 0x1f, 0x16, 0x90, 0xd7, 0x4c, 0x48, 0x45, 0x64, 0x54, 0x7c, 0x37, 0x7b,
-	0x6e, 0x41, 0x3b, 0xe5, 0xf4, 0x98, 0x5d, 0xe4, 0x16, 0xe5, 0x2b, 0xf2, 0x04, 0x56, 0x84, 0xf4,
-	0xb9, 0x7c, 0xc1, 0x93, 0xf1, 0x0b, 0x16, 0xd1, 0x7d, 0x65, 0x74, 0x13, 0x45, 0xea, 0x0c, 0xb2,
-	0x05, 0x84, 0xc5, 0x41, 0x94, 0x09, 0x76, 0x46, 0x0f, 0x0b, 0xee, 0x60, 0x71, 0xc3, 0xda, 0xec,
-	0xb8, 0x33, 0x38, 0x64, 0x0d, 0x5a, 0x11, 0x1b, 0x33, 0x39, 0x68, 0x6d, 0x58, 0x9b, 0x7d, 0x57,
-	0x2f, 0x9c, 0x5f, 0xc2, 0x6a, 0xc5, 0xfe, 0xf7, 0xbb, 0xfe, 0x5f, 0x1a, 0xd0, 0x42, 0x42, 0xe9,
-	0x63, 0x6b, 0xe2, 0x63, 0xf2, 0x21, 0xf4, 0x98, 0xf0, 0x26, 0x8e, 0x68, 0xa0, 0x6d, 0x5d, 0x26,
-	0x4a, 0x9f, 0x93, 0xc7, 0xd0, 0x0e, 0x4e, 0xb2, 0xf8, 0x54, 0x0c, 0x9a, 0x1b, 0xcd, 0xcd, 0xee,
-	0xf6, 0xea, 0xe4, 0x20, 0x75, 0xd1, 0x5d, 0xc5, 0x73, 0x73, 0x11, 0xf2, 0x29, 0x80, 0x2f, 0x25,
-	0x67, 0x47, 0x99, 0xa4, 0x02, 0x6f, 0xda, 0xdd, 0x1e, 0x18, 0x1b, 0x32, 0x41, 0x77, 0x4a, 0xbe,
-	0x6b, 0xc8, 0x92, 0xcf, 0xa0, 0x43, 0x2f, 0x24, 0x8d, 0x43, 0x1a, 0x0e, 0x5a, 0x78, 0xd0, 0xfa,
-	0xd4, 0x8d, 0xb6, 0xf6, 0x72, 0xbe, 0xbe, 0x5f, 0x29, 0x3e, 0xfc, 0x1c, 0xfa, 0x15, 0x16, 0x59,
-	0x86, 0xe6, 0x29, 0x2d, 0xa2, 0xaa, 0x3e, 0x95, 0x67, 0xcf, 0xfc, 0x28, 0xd3, 0x09, 0xd6, 0x73,
-	0xf5, 0xe2, 0x17, 0x8d, 0x4f, 0x2d, 0xe7, 0x39, 0xd8, 0x2f, 0xb2, 0x28, 0x2a, 0x37, 0x86, 0x8c,
-	0x17, 0x1b, 0x43, 0xc6, 0x27, 0x5e, 0x6e, 0x5c, 0xe9, 0xe5, 0xbf, 0x5b, 0xb0, 0xb2, 0x77, 0x46,
-	0x63, 0xb9, 0x9f, 0x48, 0x76, 0xcc, 0x02, 0x5f, 0xb2, 0x24, 0x26, 0x4f, 0xc0, 0x4e, 0xa2, 0xd0,
-	0xbb, 0x32, 0x4c, 0x9d, 0x24, 0xca, 0xad, 0x7e, 0x02, 0x76, 0x4c, 0xcf, 0xbd, 0x2b, 0x8f, 0xeb,
-	0xc4, 0xf4, 0x5c, 0x4b, 0x3f, 0x84, 0x7e, 0x48, 0x23, 0x2a, 0xa9, 0x57, 0x46, 0x47, 0x85, 0xae,
-	0xa7, 0x89, 0xbb, 0x3a, 0x1c, 0x1f, 0xc1, 0x07, 0x4a, 0x65, 0xea, 0x73, 0x1a, 0x4b, 0x2f, 0xf5,
-	0xe5, 0x09, 0xc6, 0xc4, 0x76, 0xfb, 0x31, 0x3d, 0x3f, 0x40, 0xea, 0x81, 0x2f, 0x4f, 0x9c, 0xbf,
-	0x35, 0xc0, 0x2e, 0x83, 0x49, 0x6e, 0xc3, 0x92, 0x3a, 0xd6, 0x63, 0x61, 0xee, 0x89, 0xb6, 0x5a,
-	0xbe, 0x0c, 0x55, 0x55, 0x24, 0xc7, 0xc7, 0x82, 0x4a, 0x34, 0xaf, 0xe9, 0xe6, 0x2b, 0x95, 0x59,
-	0x82, 0x7d, 0xa3, 0x0b, 0x61, 0xd1, 0xc5, 0x6f, 0xe5, 0xf1, 0xb1, 0x64, 0x63, 0x8a, 0x07, 0x36,
-	0x5d, 0xbd, 0x20, 0xab, 0xd0, 0xa2, 0x9e, 0xf4, 0x47, 0x98, 0xe1, 0xb6, 0xbb, 0x48, 0xdf, 0xfa,
-	0x23, 0xf2, 0x23, 0xb8, 0x21, 0x92, 0x8c, 0x07, 0xd4, 0x2b, 0x8e, 0x6d, 0x23, 0xb7, 0xa7, 0xa9,
-	0x2f, 0xf4, 0xe1, 0x0e, 0x34, 0x8f, 0x59, 0x38, 0x58, 0x42, 0xc7, 0x2c, 0x57, 0x93, 0xf0, 0x65,
-	0xe8, 0x2a, 0x26, 0xf9, 0x09, 0x40, 0xa9, 0x29, 0x1c, 0x74, 0xe6, 0x88, 0xda, 0x85, 0xde, 0x90,
-	0xac, 0x03, 0x04, 0x2c, 0x3d, 0xa1, 0xdc, 0x53, 0x09, 0x63, 0x63, 0x72, 0xd8, 0x9a, 0xf2, 0x8a,
-	0x5e, 0x2a, 0x36, 0x13, 0xde, 0xe8, 0x1b, 0x96, 0xa6, 0x34, 0x1c, 0x00, 0x7a, 0xd8, 0x66, 0xe2,
-	0x0b, 0x4d, 0x70, 0xbe, 0x86, 0x76, 0x6e, 0xdc, 0x5d, 0xb0, 0xcf, 0x92, 0x28, 0x1b, 0x97, 0x4e,
-	0xeb, 0xbb, 0x1d, 0x4d, 0x78, 0x19, 0x92, 0x3b, 0x80, 0x28, 0x89, 0x47, 0x34, 0xd0, 0x45, 0xe8,
-	0x5f, 0x75, 0xc0, 0x2d, 0x68, 0x07, 0x49, 0x72, 0xca, 0xb4, 0xef, 0x96, 0xdc, 0x7c, 0xe5, 0x7c,
-	0xdb, 0x84, 0x1b, 0xd5, 0x62, 0x51, 0x47, 0xa0, 0x16, 0xf4, 0xb4, 0x85, 0x6a, 0x50, 0xed, 0x61,
-	0xc5, 0xdb, 0x0d, 0xd3, 0xdb, 0xc5, 0x96, 0x71, 0x12, 0xea, 0x03, 0xfa, 0x7a, 0xcb, 0x9b, 0x24,
-	0xa4, 0x2a, 0xd7, 0x33, 0x16, 0x62, 0x78, 0xfa, 0xae, 0xfa, 0x54, 0x94, 0x11, 0x0b, 0x73, 0xf0,
-	0x51, 0x9f, 0x68, 0x1e, 0x47, 0xbd, 0x6d, 0x1d, 0x70, 0xbd, 0x52, 0x01, 0x1f, 0x2b, 0xea, 0x92,
-	0x8e, 0xa2, 0xfa, 0x26, 0x1b, 0xd0, 0xe5, 0x34, 0x8d, 0xf2, 0xdc, 0x47, 0xe7, 0xdb, 0xae, 0x49,
-	0x22, 0xf7, 0x01, 0x82, 0x24, 0x8a, 0x68, 0x80, 0x02, 0x36, 0x0a, 0x18, 0x14, 0x95, 0x77, 0x52,
-	0x46, 0x9e, 0xa0, 0x01, 0xba, 0xba, 0xe5, 0xb6, 0xa5, 0x8c, 0x0e, 0x69, 0xa0, 0xee, 0x91, 0x09,
-	0xca, 0x3d, 0x84, 0xaf, 0x2e, 0xee, 0xeb, 0x28, 0x02, 0x82, 0xec, 0x3a, 0xc0, 0x88, 0x27, 0x59,
-	0xaa, 0xb9, 0xbd, 0x8d, 0xa6, 0x42, 0x72, 0xa4, 0x20, 0xfb, 0x11, 0xdc, 0x10, 0x97, 0xe3, 0x88,
-	0xc5, 0xa7, 0x9e, 0xf4, 0xf9, 0x88, 0xca, 0x41, 0x5f, 0x57, 0x40, 0x4e, 0x7d, 0x8b, 0x44, 0x75,
-	0xf7, 0x71, 0xf8, 0xf3, 0xc1, 0x0d, 0xcc, 0x00, 0xf5, 0xe9, 0xa4, 0x40, 0x76, 0x39, 0xf5, 0x25,
-	0x7d, 0x8f, 0x36, 0xf6, 0xdd, 0xd0, 0x82, 0xdc, 0x84, 0x76, 0xe2, 0xd1, 0x8b, 0x20, 0xca, 0x8b,
-	0xb6, 0x95, 0xec, 0x5d, 0x04, 0x91, 0xf3, 0x18, 0x56, 0x2b, 0x27, 0xe6, 0x40, 0xbf, 0x06, 0x2d,
-	0xca, 0x79, 0x52, 0xc0, 0x92, 0x5e, 0x38, 0xbf, 0x05, 0xf2, 0x2e, 0x0d, 0x7f, 0x08, 0xf3, 0x9c,
-	0x9b, 0xb0, 0x5a, 0x51, 0xad, 0xed, 0x70, 0xbe, 0xb5, 0x60, 0x6d, 0x27, 0x4d, 0x69, 0x1c, 0xbe,
-	0x4d, 0xde, 0xe3, 0xd0, 0x75, 0x00, 0x54, 0xeb, 0x19, 0x0d, 0xde, 0x46, 0x0a, 0xc6, 0xe7, 0x7d,
-	0xda, 0x8b, 0x73, 0x1b, 0x6e, 0x4e, 0x59, 0x90, 0xdb, 0xf6, 0x2f, 0x0b, 0xc8, 0x73, 0x44, 0xbe,
-	0xef, 0x37, 0x74, 0x28, 0x2c, 0x52, 0x0d, 0x51, 0x23, 0x6b, 0xe8, 0x4b, 0x3f, 0x6f, 0xd7, 0x3d,
-	0x26, 0xb4, 0xfe, 0xe7, 0xbe, 0xf4, 0xf3, 0xb6, 0xc9, 0x69, 0x90, 0x71, 0xd5, 0xc1, 0xb1, 0x64,
-	0xb0, 0x6d, 0xba, 0x05, 0x89, 0x7c, 0x02, 0xb7, 0xd8, 0x28, 0x4e, 0x38, 0x9d, 0x88, 0x79, 0x3a,
-	0x8c, 0x6d, 0x14, 0x5e, 0xd3, 0xdc, 0x72, 0xc3, 0x1e, 0x46, 0xf5, 0x31, 0xac, 0x56, 0xae, 0x71,
-	0x65, 0x0a, 0xfc, 0xd9, 0x82, 0xc1, 0x8e, 0x4c, 0xc6, 0x2c, 0x70, 0xa9, 0x32, 0xbe, 0x72, 0xf5,
-	0x87, 0xd0, 0x57, 0xbd, 0x67, 0xfa, 0xfa, 0xbd, 0x24, 0x0a, 0x27, 0xbd, 0xfd, 0x0e, 0xa8, 0xf6,
-	0x63, 0x46, 0x66, 0x29, 0x89, 0x42, 0x8c, 0xcb, 0x43, 0x50, 0x3d, 0xc2, 0xd8, 0xaf, 0xa7, 0x9c,
-	0x5e, 0x4c, 0xcf, 0x2b, 0xfb, 0x95, 0x10, 0xee, 0xd7, 0x8d, 0x65, 0x29, 0xa6, 0xe7, 0x6a, 0xbf,
-	0x73, 0x17, 0xee, 0xcc, 0xb0, 0x2d, 0x0f, 0xd7, 0xbf, 0x2d, 0x58, 0xdd, 0x11, 0x82, 0x8d, 0xe2,
-	0xaf, 0x10, 0x24, 0x0b, 0xa3, 0xd7, 0xa0, 0x15, 0x24, 0x59, 0x2c, 0xd1, 0xd8, 0x96, 0xab, 0x17,
-	0x53, 0xb8, 0xd1, 0xa8, 0xe1, 0xc6, 0x14, 0xf2, 0x34, 0xeb, 0xc8, 0x63, 0x20, 0xcb, 0x62, 0x05,
-	0x59, 0x1e, 0x40, 0x57, 0x05, 0xd9, 0x0b, 0x68, 0x2c, 0x29, 0xcf, 0xbb, 0x12, 0x28, 0xd2, 0x2e,
-	0x52, 0x94, 0x80, 0xd9, 0x3d, 0x75, 0x63, 0x82, 0x74, 0xd2, 0x3a, 0xff, 0xa7, 0xaa, 0xa2, 0x72,
-	0x95, 0x3c, 0x66, 0x73, 0xbb, 0xa8, 0x02, 0x5e, 0x1e, 0xe5, 0xf7, 0x50, 0x9f, 0xaa, 0x44, 0xd2,
-	0xec, 0x28, 0x62, 0x81, 0xa7, 0x18, 0xda, 0x7e, 0x5b, 0x53, 0xde, 0xf1, 0x68, 0xe2, 0x95, 0x45,
-	0xd3, 0x2b, 0x04, 0x16, 0xfd, 0x4c, 0x9e, 0x14, 0x9d, 0x54, 0x7d, 0x4f, 0x79, 0xaa, 0x7d, 0x9d,
-	0xa7, 0x96, 0xea, 0x9e, 0x2a, 0x33, 0xad, 0x63, 0x66, 0xda, 0x27, 0xb0, 0xaa, 0x47, 0xf1, 0x6a,
-	0xb8, 0xd6, 0x01, 0xca, 0xae, 0x27, 0x06, 0x96, 0x86, 0xde, 0xa2, 0xed, 0x09, 0xe7, 0x57, 0x60,
-	0xbf, 0x4e, 0xb4, 0x5e, 0x41, 0x9e, 0x82, 0x1d, 0x15, 0x0b, 0x14, 0xed, 0x6e, 0x93, 0x49, 0xa9,
-	0x17, 0x72, 0xee, 0x44, 0xc8, 0xf9, 0x1c, 0x3a, 0x05, 0xb9, 0xf0, 0x99, 0x35, 0xcf, 0x67, 0x8d,
-	0x29, 0x9f, 0x39, 0xff, 0xb4, 0x60, 0xad, 0x6a, 0x72, 0x1e, 0x96, 0x77, 0xd0, 0x2f, 0x8f, 0xf0,
-	0xc6, 0x7e, 0x9a, 0xdb, 0xf2, 0xd4, 0xb4, 0xa5, 0xbe, 0xad, 0x34, 0x50, 0xbc, 0xf1, 0x53, 0x9d,
-	0xcb, 0xbd, 0xc8, 0x20, 0x0d, 0xdf, 0xc2, 0x4a, 0x4d, 0x64, 0xc6, 0x1c, 0xfa, 0x63, 0x73, 0x0e,
-	0xad, 0x80, 0x5d, 0xb9, 0xdb, 0x1c, 0x4e, 0x3f, 0x83, 0xdb, 0x1a, 0x0e, 0x76, 0xcb, 0x18, 0x16,
-	0xbe, 0xaf, 0x86, 0xda, 0x9a, 0x0e, 0xb5, 0x33, 0x84, 0x41, 0x7d, 0x6b, 0x5e, 0x7e, 0x23, 0x58,
-	0x39, 0x94, 0xbe, 0x64, 0x42, 0xb2, 0xa0, 0x7c, 0x10, 0x4d, 0xe5, 0x86, 0x75, 0x5d, 0xff, 0xae,
-	0xd7, 0xe1, 0x32, 0x34, 0xa5, 0x2c, 0xf2, 0x57, 0x7d, 0xaa, 0x28, 0x10, 0xf3, 0xa4, 0x3c, 0x06,
-	0x3f, 0xc0, 0x51, 0x2a, 0x1f, 0x64, 0x22, 0xfd, 0x48, 0xcf, 0x47, 0x8b, 0x38, 0x1f, 0xd9, 0x48,
-	0xc1, 0x01, 0x49, 0x8f, 0x10, 0xa1, 0xe6, 0xb6, 0xf4, 0xf4, 0xa4, 0x08, 0xc8, 0x5c, 0x07, 0xc0,
-	0x52, 0xd5, 0x55, 0xd6, 0xd6, 0x7b, 0x15, 0x65, 0x57, 0x11, 0x9c, 0xfb, 0x70, 0xef, 0x0b, 0x2a,
-	0x55, 0x37, 0xe2, 0xbb, 0x49, 0x7c, 0xcc, 0x46, 0x19, 0xf7, 0x8d, 0x50, 0x38, 0xff, 0xb1, 0x60,
-	0x7d, 0x8e, 0x40, 0x7e, 0xe1, 0x01, 0x2c, 0x8d, 0x7d, 0x21, 0x29, 0x2f, 0xaa, 0xa4, 0x58, 0x4e,
-	0xbb, 0xa2, 0x71, 0x9d, 0x2b, 0x9a, 0x35, 0x57, 0xdc, 0x84, 0xf6, 0xd8, 0xbf, 0xf0, 0xc6, 0x47,
-	0xf9, 0x28, 0xd7, 0x1a, 0xfb, 0x17, 0x6f, 0x8e, 0x10, 0xd9, 0x18, 0xf7, 0x8e, 0xb2, 0xe0, 0x94,
-	0x4a, 0x51, 0x22, 0x1b, 0xe3, 0xcf, 0x34, 0x05, 0x67, 0x3b, 0x1c, 0x74, 0x11, 0x06, 0x3a, 0x6e,
-	0xbe, 0x72, 0xce, 0x61, 0x70, 0x98, 0x1d, 0x89, 0x80, 0xb3, 0x23, 0xfa, 0x86, 0x4a, 0x5f, 0x81,
-	0x61, 0x91, 0x23, 0x0f, 0xa0, 0x1b, 0x44, 0x4c, 0xa1, 0xa1, 0xf1, 0x92, 0x04, 0x4d, 0xc2, 0xae,
-	0x81, 0x70, 0x29, 0x4f, 0xbc, 0xca, 0xe3, 0x19, 0x14, 0xe9, 0x40, 0x3f, 0xa0, 0xef, 0x40, 0x47,
-	0xb0, 0x38, 0xa0, 0x5e, 0xac, 0x5f, 0x2c, 0x4d, 0x77, 0x09, 0xd7, 0xfb, 0x42, 0xb5, 0xb3, 0x3b,
-	0x33, 0x4e, 0xce, 0x5d, 0x78, 0x75, 0x2b, 0xff, 0x0d, 0x10, 0x7a, 0x86, 0x76, 0x19, 0xef, 0xaf,
-	0xbc, 0xc8, 0xee, 0x1a, 0x63, 0xce, 0xf4, 0x13, 0xcd, 0x5d, 0xa1, 0xb5, 0x57, 0xdb, 0x2a, 0xb4,
-	0xa4, 0x98, 0xd8, 0xb7, 0x28, 0xc5, 0xbe, 0x70, 0x7c, 0x05, 0x46, 0x23, 0x5d, 0xd6, 0xa5, 0x80,
-	0x35, 0x11, 0x20, 0x4f, 0x80, 0xa4, 0x3e, 0x97, 0x4c, 0xa9, 0x50, 0x93, 0xbe, 0x77, 0xe2, 0x8b,
-	0x13, 0xb4, 0xa0, 0xe5, 0x2e, 0x97, 0x9c, 0x57, 0xf4, 0xf2, 0xd7, 0xbe, 0x38, 0x51, 0xe0, 0x8d,
-	0xc3, 0x45, 0x13, 0xe7, 0x4d, 0xfc, 0x76, 0x28, 0xac, 0xbd, 0xa2, 0x34, 0xdd, 0x4d, 0xe2, 0x98,
-	0x06, 0x92, 0x86, 0x85, 0xd3, 0x67, 0xbd, 0xdb, 0xef, 0x82, 0x3d, 0xe2, 0x69, 0xe0, 0xa5, 0x09,
-	0xd7, 0x8f, 0xb1, 0xbe, 0xdb, 0x51, 0x84, 0x83, 0x84, 0xe3, 0xd4, 0xc3, 0xa9, 0x7e, 0xe3, 0xe8,
-	0xa9, 0xca, 0x76, 0x27, 0x04, 0x35, 0x43, 0x4d, 0x1d, 0x93, 0xa3, 0xc2, 0x4f, 0x15, 0xc8, 0x07,
-	0xbe, 0xa4, 0xcf, 0x78, 0x72, 0x4a, 0x79, 0x71, 0xfc, 0x10, 0x3a, 0xc5, 0xe6, 0xdc, 0x84, 0x72,
-	0xed, 0xfc, 0x17, 0x51, 0xd6, 0xdc, 0x33, 0x19, 0x58, 0x8e, 0x93, 0x2c, 0xd6, 0xad, 0xaf, 0xe3,
-	0xea, 0x05, 0xd9, 0x33, 0x0d, 0x6b, 0x20, 0xee, 0x7e, 0x3c, 0x85, 0x80, 0x53, 0x8a, 0xb6, 0xdc,
-	0x5c, 0xde, 0xb8, 0xc1, 0xf0, 0x6b, 0xe8, 0x14, 0x64, 0x35, 0xde, 0xa3, 0x23, 0xfc, 0x30, 0xe4,
-	0x54, 0x08, 0x2a, 0x72, 0x1b, 0xfb, 0x8a, 0xba, 0x53, 0x10, 0x95, 0x58, 0xb1, 0x3f, 0xaf, 0x72,
-	0x1d, 0x99, 0x7e, 0x41, 0xc5, 0x4a, 0xdf, 0xfe, 0x2b, 0x40, 0xef, 0x90, 0xfa, 0xe7, 0x94, 0x86,
-	0x58, 0xcd, 0x64, 0x54, 0x74, 0x91, 0xea, 0x6f, 0x50, 0xe4, 0xd1, 0x74, 0xbb, 0x98, 0xf9, 0xa3,
-	0xd7, 0xf0, 0xa3, 0xeb, 0xc4, 0x72, 0xd7, 0x2f, 0x90, 0x7d, 0xe8, 0x1a, 0x3f, 0xf2, 0x90, 0x7b,
-	0xc6, 0xc6, 0xda, 0x6f, 0x57, 0xc3, 0xf5, 0x39, 0xdc, 0x42, 0xdb, 0x53, 0x8b, 0xbc, 0x86, 0xae,
-	0xf1, 0x96, 0x30, 0xf5, 0xd5, 0x1f, 0x35, 0xa6, 0xbe, 0x19, 0x0f, 0x10, 0x67, 0x41, 0x69, 0x33,
-	0x5e, 0x04, 0xa6, 0xb6, 0xfa, 0x1b, 0xc4, 0xd4, 0x36, 0xeb, 0x19, 0xb1, 0x40, 0x5c, 0xe8, 0x57,
-	0xa6, 0x78, 0x72, 0x7f, 0xb2, 0x63, 0xd6, 0x03, 0x63, 0xf8, 0x60, 0x2e, 0xdf, 0xb4, 0xd0, 0x18,
-	0x9c, 0x4d, 0x0b, 0xeb, 0xcf, 0x02, 0xd3, 0xc2, 0x19, 0xd3, 0xb6, 0xb3, 0x40, 0x7e, 0x0f, 0x2b,
-	0xb5, 0xe1, 0x95, 0x38, 0x86, 0x15, 0x73, 0xa6, 0xee, 0xe1, 0xc3, 0x2b, 0x65, 0x4a, 0xfd, 0x5f,
-	0x42, 0xcf, 0x9c, 0x19, 0x89, 0x61, 0xd0, 0x8c, 0xb1, 0x78, 0x78, 0x7f, 0x1e, 0xdb, 0x54, 0x68,
-	0x8e, 0x2d, 0xa6, 0xc2, 0x19, 0x83, 0x9b, 0xa9, 0x70, 0xd6, 0xb4, 0xe3, 0x2c, 0x90, 0xdf, 0xc1,
-	0xf2, 0xf4, 0xf8, 0x40, 0x3e, 0x9c, 0x76, 0x5b, 0x6d, 0x2a, 0x19, 0x3a, 0x57, 0x89, 0x94, 0xca,
-	0x5f, 0x02, 0x4c, 0xa6, 0x02, 0x62, 0xe0, 0x73, 0x6d, 0x2a, 0x19, 0xde, 0x9b, 0xcd, 0x2c, 0x55,
-	0xfd, 0x11, 0x6e, 0xce, 0x6c, 0xbd, 0xc4, 0x28, 0xbd, 0xab, 0x9a, 0xf7, 0xf0, 0xe3, 0x6b, 0xe5,
-	0xca, 0xb3, 0xfe, 0x00, 0x2b, 0xb5, 0xfe, 0x64, 0x66, 0xc5, 0xbc, 0xb6, 0x69, 0x66, 0xc5, 0xdc,
-	0x06, 0x87, 0x55, 0xfb, 0x15, 0xf4, 0x2b, 0xd8, 0x6c, 0x56, 0xc6, 0xac, 0xde, 0x60, 0x56, 0xc6,
-	0x6c, 0x50, 0x5f, 0xd8, 0xb4, 0x9e, 0x5a, 0x3a, 0x3d, 0x26, 0xe8, 0x5a, 0x4d, 0x8f, 0x1a, 0xe4,
-	0x57, 0xd3, 0xa3, 0x0e, 0xca, 0xce, 0xc2, 0xb3, 0xfb, 0xb0, 0x2c, 0x34, 0x4e, 0x1e, 0x8b, 0x2d,
-	0xdd, 0xff, 0x9f, 0x01, 0x3a, 0xef, 0x80, 0x27, 0x32, 0x39, 0x6a, 0xe3, 0xbf, 0x03, 0x3f, 0xfb,
-	0x7f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x32, 0x17, 0xbf, 0x34, 0x2c, 0x18, 0x00, 0x00,
+	// 2490 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x19, 0x4d, 0x73, 0xdc, 0x48,
+	0x15, 0xcd, 0x78, 0xc6, 0xa3, 0x37, 0x33, 0x89, 0xdd, 0x76, 0x36, 0x13, 0x39, 0xe3, 0x78, 0x15,
+	0xf2, 0xc5, 0xa6, 0x4c, 0x08, 0x0b, 0x95, 0xec, 0x16, 0x45, 0x39, 0x8e, 0x13, 0x42, 0x12, 0x6f,
+	0x4a, 0x76, 0xb6, 0x42, 0x51, 0x85, 0x90, 0xa5, 0x9e, 0x71, 0x63, 0x8d, 0x24, 0xba, 0x7b, 0xfc,
+	0xb1, 0xa7, 0xfd, 0x07, 0xdc, 0xf7, 0xc4, 0x99, 0x0b, 0x17, 0xaa, 0xb8, 0x70, 0xe3, 0x48, 0x71,
+	0xe0, 0x00, 0x3f, 0x80, 0x2a, 0x4e, 0xfc, 0x09, 0xaa, 0x3f, 0xa4, 0x69, 0x69, 0x66, 0x9c, 0x35,
+	0x5b, 0x7b, 0x53, 0xbf, 0xf7, 0xfa, 0xf5, 0xeb, 0xf7, 0xfd, 0x5a, 0xd0, 0x1e, 0x90, 0x18, 0xd3,
+	0xcd, 0x8c, 0xa6, 0x3c, 0x45, 0x2d, 0xb9, 0xf0, 0xb3, 0x03, 0xf7, 0x33, 0x58, 0x7b, 0x95, 0xa6,
+	0x47, 0xe3, 0xec, 0x29, 0xa1, 0x38, 0xe4, 0x29, 0x3d, 0xdb, 0x49, 0x38, 0x3d, 0xf3, 0xf0, 0x6f,
+	0xc7, 0x98, 0x71, 0x74, 0x1d, 0xec, 0x28, 0x47, 0xf4, 0xac, 0x0d, 0xeb, 0xae, 0xed, 0x4d, 0x00,
+	0x08, 0xc1, 0x42, 0x12, 0x8c, 0x70, 0xaf, 0x26, 0x11, 0xf2, 0xdb, 0xdd, 0x81, 0xeb, 0xb3, 0x19,
+	0xb2, 0x2c, 0x4d, 0x18, 0x46, 0xb7, 0xa0, 0x81, 0x13, 0xae, 0xb9, 0xb5, 0x1f, 0x5e, 0xde, 0xcc,
+	0x45, 0xd9, 0x54, 0x74, 0x0a, 0xeb, 0xfe, 0xcb, 0x02, 0xf4, 0x8a, 0x30, 0x2e, 0x80, 0x04, 0xb3,
+	0xaf, 0x27, 0xcf, 0x07, 0xd0, 0xcc, 0x28, 0x1e, 0x90, 0x53, 0x2d, 0x91, 0x5e, 0xa1, 0xfb, 0xb0,
+	0xcc, 0x78, 0x40, 0xf9, 0x33, 0x9a, 0x8e, 0x9e, 0x91, 0x18, 0xef, 0x0a, 0xa1, 0xeb, 0x92, 0x64,
+	0x1a, 0x81, 0x36, 0x01, 0x91, 0x24, 0x8c, 0xc7, 0x8c, 0x1c, 0xe3, 0xbd, 0x1c, 0xdb, 0x5b, 0xd8,
+	0xb0, 0xee, 0xb6, 0xbc, 0x19, 0x18, 0xb4, 0x0a, 0x8d, 0x98, 0x8c, 0x08, 0xef, 0x35, 0x36, 0xac,
+	0xbb, 0x5d, 0x4f, 0x2d, 0x84, 0x2c, 0xe1, 0x98, 0xb2, 0x94, 0xf6, 0x9a, 0x4a, 0x16, 0xb5, 0x72,
+	0xf7, 0x61, 0xa5, 0x74, 0xaf, 0x0b, 0xa9, 0xc5, 0xe0, 0x5a, 0x2b, 0x71, 0xfd, 0x73, 0x0d, 0x1a,
+	0x92, 0xb0, 0xb0, 0x89, 0x35, 0xb1, 0x09, 0xfa, 0x10, 0x3a, 0x84, 0xf9, 0x13, 0xc5, 0xd5, 0xe4,
+	0x5d, 0xda, 0x84, 0x15, 0x36, 0x42, 0x1f, 0x41, 0x33, 0x3c, 0x1c, 0x27, 0x47, 0xac, 0x57, 0xdf,
+	0xa8, 0xdf, 0x6d, 0x3f, 0x5c, 0x99, 0x08, 0x20, 0x14, 0xb3, 0x2d, 0x70, 0x9e, 0x26, 0x41, 0x8f,
+	0x00, 0x02, 0xce, 0x29, 0x39, 0x18, 0x73, 0xcc, 0xa4, 0x66, 0xda, 0x0f, 0x7b, 0xc6, 0x86, 0x31,
+	0xc3, 0x5b, 0x05, 0xde, 0x33, 0x68, 0xd1, 0x63, 0x68, 0xe1, 0x53, 0x8e, 0x93, 0x08, 0x47, 0xbd,
+	0x86, 0x3c, 0xa8, 0x5f, 0xb9, 0xe9, 0xe6, 0x8e, 0xc6, 0xab, 0x7b, 0x17, 0xe4, 0xa8, 0x07, 0x8b,
+	0x61, 0x9a, 0x70, 0x9c, 0x70, 0xa9, 0xd1, 0x8e, 0x97, 0x2f, 0x9d, 0x4f, 0xa1, 0x5b, 0xda, 0x84,
+	0x96, 0xa0, 0x7e, 0x84, 0x73, 0xff, 0x10, 0x9f, 0xc2, 0x46, 0xc7, 0x41, 0x3c, 0x56, 0xae, 0xda,
+	0xf1, 0xd4, 0xe2, 0x93, 0xda, 0x23, 0xcb, 0x7d, 0x0a, 0xf6, 0xb3, 0x71, 0x1c, 0x17, 0x1b, 0x23,
+	0x42, 0xf3, 0x8d, 0x11, 0xa1, 0x13, 0xbb, 0xd4, 0xce, 0x75, 0xd7, 0xbf, 0x58, 0xb0, 0xbc, 0x73,
+	0x8c, 0x13, 0xbe, 0x9b, 0x72, 0x32, 0x20, 0x61, 0xc0, 0x49, 0x9a, 0xa0, 0xfb, 0x60, 0xa7, 0x71,
+	0xe4, 0x9f, 0x6b, 0xd8, 0x56, 0x1a, 0x6b, 0xa9, 0xef, 0x83, 0x9d, 0xe0, 0x13, 0xff, 0xdc, 0xe3,
+	0x5a, 0x09, 0x3e, 0x51, 0xd4, 0x37, 0xa1, 0x1b, 0xe1, 0x18, 0x73, 0xec, 0x17, 0x76, 0x13, 0x46,
+	0xed, 0x28, 0xe0, 0xb6, 0x32, 0xd4, 0x6d, 0xb8, 0x2c, 0x58, 0x66, 0x01, 0xc5, 0x09, 0xf7, 0xb3,
+	0x80, 0x1f, 0x4a, 0x6b, 0xd9, 0x5e, 0x37, 0xc1, 0x27, 0x6f, 0x24, 0xf4, 0x4d, 0xc0, 0x0f, 0xdd,
+	0x3f, 0xd5, 0xc0, 0x2e, 0xcc, 0x8c, 0xae, 0xc2, 0xa2, 0x38, 0xd6, 0x27, 0x91, 0xd6, 0x44, 0x53,
+	0x2c, 0x5f, 0x44, 0xc2, 0xfb, 0xd2, 0xc1, 0x80, 0x61, 0x2e, 0xc5, 0xab, 0x7b, 0x7a, 0x25, 0x7c,
+	0x8e, 0x91, 0x2f, 0x54, 0x48, 0x2d, 0x78, 0xf2, 0x5b, 0x68, 0x7c, 0xc4, 0xc9, 0x08, 0xcb, 0x03,
+	0xeb, 0x9e, 0x5a, 0xa0, 0x15, 0x68, 0x60, 0x9f, 0x07, 0x43, 0x19, 0x2b, 0xb6, 0xb7, 0x80, 0xf7,
+	0x83, 0x21, 0xfa, 0x2e, 0x5c, 0x62, 0xe9, 0x98, 0x86, 0xd8, 0xcf, 0x8f, 0x55, 0x21, 0xd3, 0x51,
+	0xd0, 0x67, 0xea, 0x70, 0x17, 0xea, 0x03, 0x12, 0xf5, 0x16, 0xa5, 0x62, 0x96, 0xca, 0xee, 0xf9,
+	0x22, 0xf2, 0x04, 0x12, 0x7d, 0x1f, 0xa0, 0xe0, 0x14, 0xf5, 0x5a, 0x73, 0x48, 0xed, 0x9c, 0x6f,
+	0x84, 0xfa, 0x00, 0x21, 0xc9, 0x0e, 0x31, 0xf5, 0x85, 0xc3, 0xd8, 0xd2, 0x39, 0x6c, 0x05, 0x79,
+	0x89, 0xcf, 0x04, 0x9a, 0x30, 0x7f, 0xf8, 0x05, 0xc9, 0x32, 0x1c, 0xf5, 0x40, 0x6a, 0xd8, 0x26,
+	0xec, 0xb9, 0x02, 0xb8, 0xef, 0xa0, 0xa9, 0x85, 0x5b, 0x03, 0xfb, 0x38, 0x8d, 0xc7, 0xa3, 0x42,
+	0x69, 0x5d, 0xaf, 0xa5, 0x00, 0x2f, 0x22, 0x74, 0x0d, 0x64, 0xbe, 0x95, 0x47, 0xd4, 0xa4, 0x8a,
+	0xa4, 0x7e, 0x5f, 0x62, 0x15, 0xcf, 0x69, 0x7a, 0x44, 0x94, 0xee, 0x16, 0x3d, 0xbd, 0x72, 0xbf,
+	0xac, 0xc3, 0xa5, 0x72, 0x18, 0x89, 0x23, 0x24, 0x17, 0xa9, 0x69, 0x4b, 0xb2, 0x91, 0x6c, 0xf7,
+	0x4a, 0xda, 0xae, 0x99, 0xda, 0xce, 0xb7, 0x8c, 0xd2, 0x48, 0x1d, 0xd0, 0x55, 0x5b, 0x5e, 0xa7,
+	0x11, 0x16, 0xbe, 0x3e, 0x26, 0x91, 0x34, 0x4f, 0xd7, 0x13, 0x9f, 0x02, 0x32, 0x24, 0x91, 0x4e,
+	0x63, 0xe2, 0x53, 0x8a, 0x47, 0x25, 0xdf, 0xa6, 0x32, 0xb8, 0x5a, 0x09, 0x83, 0x8f, 0x04, 0x74,
+	0x51, 0x59, 0x51, 0x7c, 0xa3, 0x0d, 0x68, 0x53, 0x9c, 0xc5, 0xda, 0xf7, 0xa5, 0xf2, 0x6d, 0xcf,
+	0x04, 0xa1, 0x75, 0x80, 0x30, 0x8d, 0x63, 0x1c, 0x4a, 0x02, 0x5b, 0x12, 0x18, 0x10, 0xe1, 0x77,
+	0x9c, 0xc7, 0x3e, 0xc3, 0xa1, 0x54, 0x75, 0xc3, 0x6b, 0x72, 0x1e, 0xef, 0xe1, 0x50, 0xdc, 0x63,
+	0xcc, 0x30, 0xf5, 0x65, 0x62, 0x6b, 0xcb, 0x7d, 0x2d, 0x01, 0x90, 0xe9, 0xba, 0x0f, 0x30, 0xa4,
+	0xe9, 0x38, 0x53, 0xd8, 0xce, 0x46, 0x5d, 0xd4, 0x04, 0x09, 0x91, 0xe8, 0x5b, 0x70, 0x89, 0x9d,
+	0x8d, 0x62, 0x92, 0x1c, 0xf9, 0x3c, 0xa0, 0x43, 0xcc, 0x7b, 0x5d, 0x15, 0x01, 0x1a, 0xba, 0x2f,
+	0x81, 0xe2, 0xee, 0xa3, 0xe8, 0x47, 0xbd, 0x4b, 0xd2, 0x03, 0xc4, 0xa7, 0xfb, 0x47, 0x0b, 0xd0,
+	0x36, 0xc5, 0x01, 0xc7, 0x17, 0xa8, 0x88, 0x5f, 0x2f, 0x5d, 0xa0, 0x2b, 0xd0, 0x4c, 0x7d, 0x7c,
+	0x1a, 0xc6, 0x3a, 0x6a, 0x1b, 0xe9, 0xce, 0x69, 0x18, 0x0b, 0x47, 0x21, 0x03, 0x7f, 0x14, 0xf0,
+	0x30, 0x8f, 0xd3, 0x45, 0x32, 0x78, 0x2d, 0x96, 0xc8, 0x85, 0x2e, 0x19, 0xf8, 0x49, 0x9a, 0x60,
+	0x8d, 0x57, 0x01, 0xd4, 0x26, 0x83, 0xdd, 0x34, 0xc1, 0x92, 0xc6, 0xfd, 0x08, 0x56, 0x4a, 0x02,
+	0xeb, 0xd2, 0xb2, 0x0a, 0x0d, 0x4c, 0x69, 0x9a, 0xa7, 0x35, 0xb5, 0x70, 0x7f, 0x01, 0xe8, 0x6d,
+	0x16, 0x7d, 0x1b, 0xb7, 0x73, 0xaf, 0xc0, 0x4a, 0x89, 0xb5, 0x92, 0xc3, 0xfd, 0xd2, 0x82, 0xd5,
+	0xad, 0x2c, 0xc3, 0x49, 0xb4, 0x9f, 0x5e, 0xe0, 0xd0, 0x3e, 0x80, 0x64, 0xeb, 0x1b, 0xad, 0x86,
+	0x2d, 0x21, 0xd2, 0xbe, 0x17, 0x29, 0x5c, 0xee, 0x55, 0xb8, 0x52, 0x91, 0x40, 0xcb, 0xf6, 0x5f,
+	0x0b, 0xd0, 0x53, 0x99, 0x39, 0xbf, 0x59, 0xfb, 0x23, 0x72, 0x99, 0x28, 0xb5, 0x2a, 0x33, 0x47,
+	0x01, 0x0f, 0x74, 0xe3, 0xd0, 0x21, 0x4c, 0xf1, 0x7f, 0x1a, 0xf0, 0x40, 0x17, 0x64, 0x8a, 0x45,
+	0xf5, 0x26, 0xc7, 0xb8, 0xd7, 0xc8, 0x0b, 0xb2, 0x97, 0x83, 0xd0, 0xc7, 0xf0, 0x01, 0x19, 0x26,
+	0x29, 0xc5, 0x13, 0x32, 0x5f, 0x99, 0xb1, 0x29, 0x89, 0x57, 0x15, 0xb6, 0xd8, 0xb0, 0x23, 0x70,
+	0xe5, 0xe3, 0x39, 0xc5, 0x2a, 0x44, 0x8d, 0xe3, 0xf7, 0x29, 0xc6, 0xc2, 0x51, 0x4a, 0x97, 0x3d,
+	0xd7, 0x51, 0xbe, 0xb2, 0xa0, 0xb7, 0xc5, 0xd3, 0x11, 0x09, 0x3d, 0x2c, 0xae, 0x58, 0x52, 0xd0,
+	0x4d, 0xe8, 0x8a, 0x0a, 0x57, 0x55, 0x52, 0x27, 0x8d, 0xa3, 0x49, 0x6f, 0x71, 0x0d, 0x44, 0x91,
+	0x33, 0xed, 0xb7, 0x98, 0xc6, 0x91, 0xb4, 0xde, 0x4d, 0x10, 0x95, 0xc8, 0xd8, 0xaf, 0xba, 0xb2,
+	0x4e, 0x82, 0x4f, 0x4a, 0xfb, 0x05, 0x91, 0xdc, 0xaf, 0xc3, 0x22, 0xc1, 0x27, 0x62, 0xbf, 0xbb,
+	0x06, 0xd7, 0x66, 0xc8, 0xa6, 0x8d, 0xfa, 0x1f, 0x0b, 0x56, 0xb6, 0x18, 0x23, 0xc3, 0xe4, 0x73,
+	0x99, 0x8a, 0x73, 0xa1, 0x57, 0xa1, 0x11, 0xa6, 0xe3, 0x84, 0x4b, 0x61, 0x1b, 0x9e, 0x5a, 0x54,
+	0xb2, 0x53, 0x6d, 0x2a, 0x3b, 0x55, 0xf2, 0x5b, 0x7d, 0x3a, 0xbf, 0x19, 0xf9, 0x6b, 0xa1, 0x94,
+	0xbf, 0x6e, 0x40, 0x5b, 0xb8, 0x82, 0x1f, 0xe2, 0x84, 0x63, 0xaa, 0x43, 0x17, 0x04, 0x68, 0x5b,
+	0x42, 0x04, 0x81, 0x59, 0xa3, 0x55, 0xf9, 0x83, 0xac, 0x28, 0xd0, 0x22, 0x03, 0x46, 0x84, 0x1d,
+	0xf9, 0xfc, 0x2c, 0xcb, 0xb3, 0x6e, 0x4b, 0x00, 0xf6, 0xcf, 0x32, 0xec, 0xfe, 0x5b, 0x04, 0x56,
+	0xe9, 0x9e, 0xda, 0xa0, 0x73, 0x0b, 0xb9, 0xc8, 0xfd, 0x34, 0xd6, 0x97, 0x14, 0x9f, 0x22, 0xca,
+	0xb2, 0xf1, 0x41, 0x4c, 0x42, 0x5f, 0x20, 0xd4, 0xe5, 0x6c, 0x05, 0x79, 0x4b, 0xe3, 0x89, 0xca,
+	0x16, 0x4c, 0x95, 0x21, 0x58, 0x08, 0xc6, 0x3c, 0xcf, 0x45, 0xf2, 0xbb, 0xa2, 0xc6, 0xe6, 0xfb,
+	0xd4, 0xb8, 0x38, 0xad, 0xc6, 0xc2, 0x0d, 0x5b, 0xa6, 0x1b, 0x7e, 0x0c, 0x2b, 0x6a, 0xae, 0x28,
+	0xdb, 0xb2, 0x0f, 0x50, 0x14, 0x5e, 0xd6, 0xb3, 0x54, 0xf6, 0xcf, 0x2b, 0x2f, 0x73, 0x7f, 0x02,
+	0xf6, 0xab, 0x54, 0xf1, 0x65, 0xe8, 0x01, 0xd8, 0x71, 0xbe, 0x90, 0xa4, 0xed, 0x87, 0x68, 0x92,
+	0x2d, 0x72, 0x3a, 0x6f, 0x42, 0xe4, 0x7e, 0x0a, 0xad, 0x1c, 0x9c, 0xeb, 0xcc, 0x9a, 0xa7, 0xb3,
+	0x5a, 0x45, 0x67, 0xee, 0x5f, 0x2d, 0x58, 0x2d, 0x8b, 0xac, 0xcd, 0xf2, 0x16, 0xba, 0xc5, 0x11,
+	0xfe, 0x28, 0xc8, 0xb4, 0x2c, 0x0f, 0x4c, 0x59, 0xa6, 0xb7, 0x15, 0x02, 0xb2, 0xd7, 0x41, 0xa6,
+	0x1c, 0xbd, 0x13, 0x1b, 0x20, 0x67, 0x1f, 0x96, 0xa7, 0x48, 0x66, 0xb4, 0xc2, 0xf7, 0xcc, 0x56,
+	0xb8, 0x94, 0x2f, 0x8b, 0xdd, 0x66, 0x7f, 0xfc, 0x18, 0xae, 0xaa, 0x5c, 0xb1, 0x5d, 0xd8, 0x30,
+	0xd7, 0x7d, 0xd9, 0xd4, 0x56, 0xd5, 0xd4, 0xae, 0x03, 0xbd, 0xe9, 0xad, 0x3a, 0x36, 0x87, 0xb0,
+	0xbc, 0xc7, 0x03, 0x4e, 0x18, 0x27, 0x61, 0x31, 0xdd, 0x55, 0x7c, 0xc3, 0x7a, 0x5f, 0x0b, 0x31,
+	0x1d, 0xa4, 0x4b, 0x50, 0xe7, 0x3c, 0xf7, 0x5f, 0xf1, 0x29, 0xac, 0x80, 0xcc, 0x93, 0xb4, 0x0d,
+	0xbe, 0x85, 0xa3, 0x84, 0x3f, 0xf0, 0x94, 0x07, 0xb1, 0x6a, 0xd1, 0x16, 0x64, 0x8b, 0x66, 0x4b,
+	0x88, 0xec, 0xd1, 0x54, 0x17, 0x13, 0x29, 0x6c, 0x43, 0x35, 0x70, 0x02, 0x20, 0x91, 0x7d, 0x00,
+	0x19, 0xaa, 0x2a, 0xca, 0x9a, 0x6a, 0xaf, 0x80, 0x6c, 0x0b, 0x80, 0xbb, 0x0e, 0xd7, 0x9f, 0x63,
+	0x2e, 0x0a, 0x1a, 0xdd, 0x4e, 0x93, 0x01, 0x19, 0x8e, 0x69, 0x60, 0x98, 0xc2, 0xfd, 0x9b, 0x05,
+	0xfd, 0x39, 0x04, 0xfa, 0xc2, 0x3d, 0x58, 0x1c, 0x05, 0x8c, 0x63, 0x9a, 0x47, 0x49, 0xbe, 0xac,
+	0xaa, 0xa2, 0xf6, 0x3e, 0x55, 0xd4, 0xa7, 0x54, 0x71, 0x05, 0x9a, 0xa3, 0xe0, 0xd4, 0x1f, 0x1d,
+	0xe8, 0x6e, 0xb2, 0x31, 0x0a, 0x4e, 0x5f, 0x1f, 0xc8, 0xb4, 0x47, 0xa8, 0x7f, 0x30, 0x0e, 0x8f,
+	0x30, 0x67, 0x45, 0xda, 0x23, 0xf4, 0x89, 0x82, 0xc8, 0xf6, 0x52, 0xf6, 0xda, 0xba, 0x4a, 0xe9,
+	0x95, 0xfb, 0x7b, 0x0b, 0x7a, 0x7b, 0xe3, 0x03, 0x16, 0x52, 0x72, 0x80, 0x5f, 0x63, 0x1e, 0x88,
+	0x54, 0x99, 0x3b, 0xc9, 0x0d, 0x68, 0x87, 0x31, 0x11, 0xb9, 0xd2, 0x98, 0x73, 0x41, 0x81, 0x64,
+	0x4d, 0x91, 0xc9, 0x94, 0x1f, 0xfa, 0xa5, 0xa7, 0x00, 0x10, 0xa0, 0x37, 0x12, 0x22, 0xea, 0x09,
+	0x23, 0x49, 0x88, 0xfd, 0x44, 0x4d, 0x4d, 0x75, 0x6f, 0x51, 0xae, 0x77, 0x19, 0xba, 0x03, 0x97,
+	0x59, 0x7e, 0x30, 0x35, 0x2b, 0xce, 0xa5, 0x09, 0x58, 0x16, 0x9e, 0xaf, 0x2c, 0xb8, 0x36, 0x43,
+	0x44, 0xad, 0xec, 0xf3, 0xfb, 0x86, 0x9f, 0x03, 0xc2, 0xc7, 0xf2, 0x02, 0xc6, 0xb0, 0xa8, 0xc3,
+	0x71, 0xcd, 0xe8, 0xa9, 0xaa, 0xf3, 0xa4, 0xb7, 0x8c, 0xab, 0x20, 0x31, 0x50, 0x71, 0x36, 0xb9,
+	0xc8, 0x02, 0x67, 0xbb, 0xcc, 0x0d, 0x44, 0xda, 0x1a, 0xaa, 0x04, 0x50, 0x10, 0x58, 0x13, 0x02,
+	0x74, 0x1f, 0x50, 0x16, 0x50, 0x4e, 0x04, 0x0b, 0x31, 0x96, 0xf8, 0x87, 0x01, 0x3b, 0x94, 0x12,
+	0x34, 0xbc, 0xa5, 0x02, 0xf3, 0x12, 0x9f, 0xfd, 0x2c, 0x60, 0x87, 0x22, 0xcd, 0xcb, 0x4e, 0xa6,
+	0x2e, 0x9b, 0x63, 0xf9, 0xed, 0x62, 0x58, 0x7d, 0x89, 0x71, 0xb6, 0x9d, 0x26, 0x09, 0x0e, 0x39,
+	0x8e, 0x72, 0xeb, 0xcc, 0x7a, 0x7e, 0x58, 0x03, 0x7b, 0x48, 0xb3, 0xd0, 0xcf, 0x52, 0xaa, 0x26,
+	0xc7, 0xae, 0xd7, 0x12, 0x80, 0x37, 0x29, 0x95, 0x2d, 0x16, 0xc5, 0x6a, 0x20, 0x53, 0x2d, 0x9c,
+	0xed, 0x4d, 0x00, 0xa2, 0x61, 0xab, 0x1c, 0xa3, 0xf3, 0xc7, 0x0f, 0x44, 0x39, 0x08, 0x03, 0x8e,
+	0x9f, 0xd0, 0xf4, 0x08, 0xd3, 0xfc, 0x78, 0x07, 0x5a, 0xf9, 0x66, 0x2d, 0x42, 0xb1, 0x76, 0xff,
+	0x2e, 0xf3, 0xb1, 0xb9, 0x67, 0xd2, 0xf7, 0x0c, 0xd2, 0x71, 0xa2, 0x8a, 0x64, 0xcb, 0x53, 0x0b,
+	0xb4, 0x63, 0x0a, 0x56, 0x93, 0x19, 0xfa, 0x4e, 0x25, 0x57, 0x56, 0x18, 0x6d, 0x7a, 0x9a, 0xde,
+	0xb8, 0x81, 0xf3, 0x0e, 0x5a, 0x39, 0x58, 0xcc, 0x22, 0x52, 0x11, 0x41, 0x14, 0x51, 0xcc, 0x18,
+	0x66, 0x5a, 0xc6, 0xae, 0x80, 0x6e, 0xe5, 0x40, 0x41, 0x96, 0xef, 0xd7, 0xf9, 0x40, 0x59, 0xa6,
+	0x9b, 0x43, 0x55, 0x4e, 0xf0, 0x00, 0x6d, 0x85, 0x47, 0xd5, 0xf0, 0x98, 0xe1, 0xc1, 0xd6, 0x2c,
+	0x0f, 0x9e, 0x38, 0x46, 0xcd, 0xf0, 0x9c, 0xef, 0xc1, 0x4a, 0x89, 0xa7, 0xd6, 0xd0, 0x2c, 0x27,
+	0x72, 0xff, 0x60, 0xc1, 0xd2, 0x76, 0x9a, 0x95, 0x1f, 0x0c, 0xef, 0xc1, 0x92, 0x96, 0xbc, 0x1a,
+	0x00, 0x97, 0x15, 0x7c, 0xd2, 0xd6, 0xdd, 0x80, 0xb6, 0x26, 0x35, 0x3a, 0x43, 0x3d, 0xbf, 0x4b,
+	0x09, 0xef, 0xc1, 0x92, 0x1a, 0xd9, 0xa6, 0xfa, 0xc3, 0xcb, 0x0a, 0x5e, 0xe2, 0xa5, 0x49, 0x8d,
+	0x98, 0x05, 0x05, 0x92, 0xf1, 0xfa, 0x09, 0x2c, 0x1b, 0xb2, 0x5e, 0xec, 0x2d, 0xf2, 0x29, 0x2c,
+	0x3d, 0xc7, 0xfc, 0x5d, 0xc0, 0x39, 0x65, 0xff, 0xff, 0xc3, 0xe8, 0xef, 0x2c, 0x58, 0x36, 0xd8,
+	0x68, 0x11, 0x7e, 0x0a, 0xcd, 0x53, 0x09, 0xe9, 0x59, 0x55, 0x17, 0x9b, 0x22, 0xde, 0x54, 0x4b,
+	0x25, 0x9b, 0xde, 0xe6, 0x3c, 0x86, 0xb6, 0x01, 0xbe, 0xd0, 0xd3, 0xd7, 0x3f, 0xac, 0x7c, 0x50,
+	0xfb, 0x86, 0x77, 0x43, 0x8f, 0xa0, 0xce, 0x30, 0xd7, 0x13, 0xd8, 0xed, 0xc9, 0x15, 0x66, 0x70,
+	0xdf, 0xdc, 0xc3, 0x5c, 0xdd, 0x40, 0x6c, 0x11, 0x25, 0x80, 0xe2, 0x51, 0x7a, 0x2c, 0x6c, 0x26,
+	0x62, 0x5f, 0xaf, 0x9c, 0x1f, 0x43, 0x2b, 0x27, 0xbc, 0xd0, 0x9d, 0x3e, 0x80, 0xd5, 0xf2, 0xa1,
+	0x4a, 0x75, 0x0f, 0xff, 0xd9, 0x81, 0xce, 0x1e, 0x0e, 0x4e, 0x30, 0x8e, 0x64, 0x91, 0x44, 0xc3,
+	0xbc, 0x39, 0x2b, 0xbf, 0x53, 0xa3, 0x5b, 0xd5, 0x2e, 0x6c, 0xe6, 0xc3, 0xb8, 0x73, 0xfb, 0x7d,
+	0x64, 0x3a, 0x4f, 0x7d, 0x07, 0xed, 0x42, 0xdb, 0x78, 0xf0, 0x45, 0xd7, 0x8d, 0x8d, 0x53, 0xef,
+	0xdb, 0x4e, 0x7f, 0x0e, 0x36, 0xe7, 0xf6, 0xc0, 0x42, 0xaf, 0xa0, 0x6d, 0x4c, 0xf9, 0x26, 0xbf,
+	0xe9, 0xd7, 0x0a, 0xa7, 0x3f, 0x07, 0x5b, 0x48, 0xf7, 0x0a, 0xda, 0xc6, 0xac, 0x6e, 0x72, 0x9b,
+	0x7e, 0x1d, 0x70, 0xfa, 0x73, 0xb0, 0x05, 0x37, 0x0f, 0xba, 0xa5, 0xf9, 0x1a, 0xad, 0x4f, 0x76,
+	0xcc, 0x1a, 0xfd, 0x9d, 0x1b, 0x73, 0xf1, 0xa6, 0x84, 0xc6, 0xb0, 0x6a, 0x4a, 0x38, 0x3d, 0xb0,
+	0x3b, 0xfd, 0x39, 0xd8, 0x82, 0xdb, 0xaf, 0x60, 0x79, 0x6a, 0x60, 0x44, 0xae, 0x21, 0xc5, 0x9c,
+	0x49, 0xd7, 0xb9, 0x79, 0x2e, 0x4d, 0xc1, 0xff, 0x33, 0xe8, 0x98, 0xa3, 0x18, 0x32, 0x04, 0x9a,
+	0x31, 0x8a, 0x3a, 0xeb, 0xf3, 0xd0, 0x26, 0x43, 0x73, 0x1a, 0x30, 0x19, 0xce, 0x98, 0x87, 0x9c,
+	0xf5, 0x79, 0xe8, 0x82, 0xe1, 0x2f, 0x61, 0xa9, 0xda, 0x95, 0xa3, 0x0f, 0xab, 0x6a, 0x9b, 0x6a,
+	0xf6, 0x1d, 0xf7, 0x3c, 0x92, 0x82, 0xf9, 0x0b, 0x80, 0x49, 0xb3, 0x8d, 0x8c, 0x66, 0x66, 0xaa,
+	0xd9, 0x77, 0xae, 0xcf, 0x46, 0x16, 0xac, 0x7e, 0x03, 0x57, 0x66, 0x76, 0xb4, 0xe8, 0x76, 0x29,
+	0x45, 0xce, 0xed, 0x89, 0x9d, 0x3b, 0xef, 0xa5, 0x2b, 0xce, 0xfa, 0x35, 0x2c, 0x4f, 0x35, 0x73,
+	0xa6, 0x57, 0xcc, 0x6b, 0x46, 0x9d, 0x9b, 0xe7, 0xd2, 0x18, 0x51, 0xfb, 0x39, 0x74, 0x4b, 0x8d,
+	0x8c, 0x19, 0x19, 0xb3, 0x1a, 0x29, 0xe7, 0xc6, 0x5c, 0x7c, 0xce, 0xf5, 0xae, 0xf5, 0xc0, 0x52,
+	0xee, 0x31, 0x69, 0x45, 0xca, 0xee, 0x31, 0xd5, 0x1f, 0x39, 0xeb, 0xf3, 0xd0, 0x66, 0xb8, 0x19,
+	0x1d, 0x80, 0x19, 0x6e, 0xd3, 0xcd, 0x86, 0xd3, 0x9f, 0x83, 0x2d, 0xb8, 0x3d, 0x03, 0xbb, 0x28,
+	0xbb, 0xc8, 0x31, 0x92, 0x51, 0xa5, 0x6f, 0x70, 0xd6, 0x66, 0xe2, 0x4c, 0x3e, 0x45, 0x39, 0x34,
+	0xf9, 0x54, 0xeb, 0xb2, 0xb3, 0x36, 0x13, 0x67, 0x46, 0x93, 0x59, 0x1e, 0x50, 0xff, 0xdc, 0x5a,
+	0xe5, 0xac, 0xcf, 0x43, 0xe7, 0x0c, 0x9f, 0xac, 0xc3, 0x12, 0x53, 0x65, 0x65, 0xc0, 0x36, 0xd5,
+	0x10, 0xf2, 0x04, 0xa4, 0xaf, 0xbd, 0xa1, 0x29, 0x4f, 0x0f, 0x9a, 0xf2, 0x87, 0xeb, 0x0f, 0xff,
+	0x37, 0x00, 0x05, 0x3a, 0xcb, 0x37, 0x7f, 0x1d, 0x00, 0x00,
 }
@@ -70,52 +70,82 @@ func List(filerClient FilerClient, parentDirectoryPath, prefix string, fn EachEn
 
 }
 
+// listResumeAttempts is how many times a broken listing is resumed from its cursor
+const listResumeAttempts = 3
+
 func doList(filerClient FilerClient, fullDirPath util.FullPath, prefix string, fn EachEntryFunciton, startFrom string, inclusive bool, limit uint32) (err error) {
 
-	err = filerClient.WithFilerClient(func(client SeaweedFilerClient) error {
+	var prevEntry *Entry
+	var cursor string
+	var received uint32
+	var fnErr error
+
+	for attempt := 0; ; attempt++ {
 
-		request := &ListEntriesRequest{
-			Directory:          string(fullDirPath),
-			Prefix:             prefix,
-			StartFromFileName:  startFrom,
-			Limit:              limit,
-			InclusiveStartFrom: inclusive,
+		requestLimit := limit
+		if limit > 0 {
+			requestLimit = limit - received
 		}
 
-		glog.V(3).Infof("read directory: %v", request)
-		ctx, cancel := context.WithCancel(context.Background())
-		stream, err := client.ListEntries(ctx, request)
-		if err != nil {
-			return fmt.Errorf("list %s: %v", fullDirPath, err)
-		}
-		defer cancel()
-
-		var prevEntry *Entry
-		for {
-			resp, recvErr := stream.Recv()
-			if recvErr != nil {
-				if recvErr == io.EOF {
-					if prevEntry != nil {
-						if err := fn(prevEntry, true); err != nil {
-							return err
-						}
+		err = filerClient.WithFilerClient(func(client SeaweedFilerClient) error {
+
+			request := &ListEntriesRequest{
+				Directory:          string(fullDirPath),
+				Prefix:             prefix,
+				StartFromFileName:  startFrom,
+				Limit:              requestLimit,
+				InclusiveStartFrom: inclusive,
+				Cursor:             cursor,
+			}
+
+			glog.V(3).Infof("read directory: %v", request)
+			ctx, cancel := context.WithCancel(context.Background())
+			stream, err := client.ListEntries(ctx, request)
+			if err != nil {
+				return fmt.Errorf("list %s: %v", fullDirPath, err)
+			}
+			defer cancel()
+
+			for {
+				resp, recvErr := stream.Recv()
+				if recvErr != nil {
+					if recvErr == io.EOF {
+						break
+					} else {
+						return recvErr
 					}
-					break
-				} else {
-					return recvErr
 				}
-			}
-			if prevEntry != nil {
-				if err := fn(prevEntry, false); err != nil {
-					return err
+				if prevEntry != nil {
+					if fnErr = fn(prevEntry, false); fnErr != nil {
+						return fnErr
+					}
 				}
+				prevEntry, cursor = resp.Entry, resp.Cursor
+				received++
 			}
-			prevEntry = resp.Entry
-		}
 
-		return nil
+			return nil
 
-	})
+		})
+
+		if err == nil || fnErr != nil {
+			break
+		}
+		if limit > 0 && received >= limit {
+			err = nil
+			break
+		}
+		// resume after the last received entry, if the filer gives the cursors
+		if cursor == "" || attempt+1 >= listResumeAttempts {
+			return err
+		}
+		glog.V(0).Infof("resume listing %s after %d entries: %v", fullDirPath, received, err)
+		time.Sleep(time.Duration(attempt+1) * time.Second)
+	}
+
+	if err == nil && prevEntry != nil {
+		err = fn(prevEntry, true)
+	}
 
 	return
 }
@@ -214,6 +244,15 @@ func MkFile(filerClient FilerClient, parentDirectoryPath string, fileName string
 }
 
 func Remove(filerClient FilerClient, parentDirectoryPath string, name string, isDeleteData, isRecursive, ignoreRecursiveErr bool) error {
+	return doRemove(filerClient, parentDirectoryPath, name, isDeleteData, isRecursive, ignoreRecursiveErr, false)
+}
+
+// RemoveTree deletes the entries one at a time, throttled by the filer, and resumed after a filer restart
+func RemoveTree(filerClient FilerClient, parentDirectoryPath string, name string, isDeleteData, isRecursive, ignoreRecursiveErr bool) error {
+	return doRemove(filerClient, parentDirectoryPath, name, isDeleteData, isRecursive, ignoreRecursiveErr, true)
+}
+
+func doRemove(filerClient FilerClient, parentDirectoryPath string, name string, isDeleteData, isRecursive, ignoreRecursiveErr, isDeleteTree bool) error {
 	return filerClient.WithFilerClient(func(client SeaweedFilerClient) error {
 
 		if resp, err := client.DeleteEntry(context.Background(), &DeleteEntryRequest{
@@ -222,6 +261,7 @@ func Remove(filerClient FilerClient, parentDirectoryPath string, name string, is
 			IsDeleteData:         isDeleteData,
 			IsRecursive:          isRecursive,
 			IgnoreRecursiveError: ignoreRecursiveErr,
+			IsDeleteTree:         isDeleteTree,
 		}); err != nil {
 			return err
 		} else {
@@ -82,11 +82,38 @@ func CreateEntry(client SeaweedFilerClient, request *CreateEntryRequest) error {
 	}
 	if resp.Error != "" {
 		glog.V(1).Infof("create entry %s/%s %v: %v", request.Directory, request.Entry.Name, request.OExcl, err)
+		if strings.Contains(resp.Error, ErrQuotaExceeded.Error()) {
+			return ErrQuotaExceeded
+		}
+		if strings.Contains(resp.Error, ErrNameTooLong.Error()) {
+			return ErrNameTooLong
+		}
+		if strings.Contains(resp.Error, ErrInvalidName.Error()) {
+			return ErrInvalidName
+		}
+		if strings.Contains(resp.Error, ErrPreconditionFailed.Error()) {
+			return ErrPreconditionFailed
+		}
 		return fmt.Errorf("CreateEntry : %v", resp.Error)
 	}
 	return nil
 }
 
+func AtomicRenameEntry(client SeaweedFilerClient, request *AtomicRenameEntryRequest) error {
+	_, err := client.AtomicRenameEntry(context.Background(), request)
+	if err != nil {
+		glog.V(1).Infof("rename %s/%s => %s/%s: %v", request.OldDirectory, request.OldName, request.NewDirectory, request.NewName, err)
+		if strings.Contains(err.Error(), ErrRenameTargetExists.Error()) {
+			return ErrRenameTargetExists
+		}
+		if strings.Contains(err.Error(), ErrRenameTargetNotEmpty.Error()) {
+			return ErrRenameTargetNotEmpty
+		}
+		return fmt.Errorf("AtomicRenameEntry: %v", err)
+	}
+	return nil
+}
+
 func LookupEntry(client SeaweedFilerClient, request *LookupDirectoryEntryRequest) (*LookupDirectoryEntryResponse, error) {
 	resp, err := client.LookupDirectoryEntry(context.Background(), request)
 	if err != nil {
@@ -102,4 +129,51 @@ func LookupEntry(client SeaweedFilerClient, request *LookupDirectoryEntryRequest
 	return resp, nil
 }
 
+func GetXattrs(client SeaweedFilerClient, request *GetXattrsRequest) (map[string][]byte, error) {
+	resp, err := client.GetXattrs(context.Background(), request)
+	if err != nil {
+		glog.V(3).Infof("get xattrs %s/%s: %v", request.Directory, request.Name, err)
+		return nil, xattrError(err)
+	}
+	return resp.Xattrs, nil
+}
+
+func UpdateXattrs(client SeaweedFilerClient, request *UpdateXattrsRequest) error {
+	_, err := client.UpdateXattrs(context.Background(), request)
+	if err != nil {
+		glog.V(1).Infof("update xattrs %s/%s: %v", request.Directory, request.Name, err)
+		return xattrError(err)
+	}
+	return nil
+}
+
+func xattrError(err error) error {
+	for _, e := range []error{ErrNotFound, ErrXattrNotFound, ErrXattrInvalidName, ErrXattrTooLarge, ErrXattrNoSpace} {
+		if strings.Contains(err.Error(), e.Error()) {
+			return e
+		}
+	}
+	return err
+}
+
 var ErrNotFound = errors.New("filer: no entry is found in filer store")
+
+var ErrQuotaExceeded = errors.New("filer: directory quota exceeded")
+
+var ErrNameTooLong = errors.New("filer: file name or path too long")
+
+var ErrInvalidName = errors.New("filer: file name or path is not valid utf-8")
+
+var ErrPreconditionFailed = errors.New("filer: precondition failed")
+
+var ErrRenameTargetExists = errors.New("filer: rename target exists")
+
+var ErrRenameTargetNotEmpty = errors.New("filer: rename target directory is not empty")
+
+var ErrXattrNotFound = errors.New("filer: no such extended attribute")
+
+var ErrXattrInvalidName = errors.New("filer: extended attribute name is empty or too long")
+
+var ErrXattrTooLarge = errors.New("filer: extended attribute value too large")
+
+var ErrXattrNoSpace = errors.New("filer: extended attributes of the entry too large")
@@ -0,0 +1,96 @@
+package filer_pb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// MetadataSubscriber receives the filer metadata events with a durable offset.
+//
+// The filer keeps the offset of each subscriber name. After reconnecting, or restarting the process,
+// the events after the last acknowledged one are delivered again. So the events are delivered at least once,
+// and processing them should be idempotent.
+type MetadataSubscriber struct {
+	filerClient    FilerClient
+	subscriberName string
+	pathPrefix     string
+	sinceNs        int64
+}
+
+// NewMetadataSubscriber starts from sinceNs if the subscriber has never acknowledged any event
+func NewMetadataSubscriber(filerClient FilerClient, subscriberName, pathPrefix string, sinceNs int64) *MetadataSubscriber {
+	return &MetadataSubscriber{
+		filerClient:    filerClient,
+		subscriberName: subscriberName,
+		pathPrefix:     pathPrefix,
+		sinceNs:        sinceNs,
+	}
+}
+
+type processEventError struct {
+	err error
+}
+
+func (e processEventError) Error() string {
+	return e.err.Error()
+}
+
+// Subscribe calls processEventFn for each event, and reconnects to the filer on errors.
+// It returns when the context is done, or processEventFn returns an error.
+func (s *MetadataSubscriber) Subscribe(ctx context.Context, processEventFn func(resp *SubscribeMetadataResponse) error) error {
+
+	for {
+		err := s.filerClient.WithFilerClient(func(client SeaweedFilerClient) error {
+			stream, err := client.SubscribeMetadata(ctx, &SubscribeMetadataRequest{
+				ClientName:     s.subscriberName,
+				PathPrefix:     s.pathPrefix,
+				SinceNs:        s.sinceNs,
+				SubscriberName: s.subscriberName,
+			})
+			if err != nil {
+				return fmt.Errorf("subscribe: %v", err)
+			}
+
+			for {
+				resp, listenErr := stream.Recv()
+				if listenErr == io.EOF {
+					return nil
+				}
+				if listenErr != nil {
+					return listenErr
+				}
+				if err := processEventFn(resp); err != nil {
+					return processEventError{err}
+				}
+			}
+		})
+		if processErr, ok := err.(processEventError); ok {
+			return processErr.err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			glog.V(0).Infof("subscriber %s: %v", s.subscriberName, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// Ack persists on the filer that the events up to and including tsNs are processed.
+func (s *MetadataSubscriber) Ack(tsNs int64) error {
+	return s.filerClient.WithFilerClient(func(client SeaweedFilerClient) error {
+		_, err := client.AckMetadata(context.Background(), &AckMetadataRequest{
+			SubscriberName: s.subscriberName,
+			TsNs:           tsNs,
+		})
+		if err != nil {
+			return fmt.Errorf("ack %s at %d: %v", s.subscriberName, tsNs, err)
+		}
+		return nil
+	})
+}
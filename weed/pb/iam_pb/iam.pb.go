@@ -12,6 +12,10 @@ It has these top-level messages:
 	S3ApiConfiguration
 	Identity
 	Credential
+	Role
+	WebIdentityTrust
+	WebIdentityClaimCondition
+	WebIdentityProvider
 */
 package iam_pb
 
@@ -36,7 +40,9 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type S3ApiConfiguration struct {
-	Identities []*Identity `protobuf:"bytes,1,rep,name=identities" json:"identities,omitempty"`
+	Identities           []*Identity            `protobuf:"bytes,1,rep,name=identities" json:"identities,omitempty"`
+	Roles                []*Role                `protobuf:"bytes,2,rep,name=roles" json:"roles,omitempty"`
+	WebIdentityProviders []*WebIdentityProvider `protobuf:"bytes,3,rep,name=web_identity_providers,json=webIdentityProviders" json:"web_identity_providers,omitempty"`
 }
 
 func (m *S3ApiConfiguration) Reset()                    { *m = S3ApiConfiguration{} }
@@ -51,6 +57,20 @@ func (m *S3ApiConfiguration) GetIdentities() []*Identity {
 	return nil
 }
 
+func (m *S3ApiConfiguration) GetRoles() []*Role {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *S3ApiConfiguration) GetWebIdentityProviders() []*WebIdentityProvider {
+	if m != nil {
+		return m.WebIdentityProviders
+	}
+	return nil
+}
+
 type Identity struct {
 	Name        string        `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Credentials []*Credential `protobuf:"bytes,2,rep,name=credentials" json:"credentials,omitempty"`
@@ -107,10 +127,196 @@ func (m *Credential) GetSecretKey() string {
 	return ""
 }
 
+// a role is assumed with STS AssumeRole or AssumeRoleWithWebIdentity,
+// and the temporary credentials are granted the actions of the role
+type Role struct {
+	Name    string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Actions []string `protobuf:"bytes,2,rep,name=actions" json:"actions,omitempty"`
+	// names of the identities allowed to assume this role
+	TrustedIdentities    []string            `protobuf:"bytes,3,rep,name=trusted_identities,json=trustedIdentities" json:"trusted_identities,omitempty"`
+	TrustedWebIdentities []*WebIdentityTrust `protobuf:"bytes,4,rep,name=trusted_web_identities,json=trustedWebIdentities" json:"trusted_web_identities,omitempty"`
+	// defaults to 3600 seconds
+	MaxSessionDurationSeconds int64 `protobuf:"varint,5,opt,name=max_session_duration_seconds,json=maxSessionDurationSeconds" json:"max_session_duration_seconds,omitempty"`
+}
+
+func (m *Role) Reset()                    { *m = Role{} }
+func (m *Role) String() string            { return proto.CompactTextString(m) }
+func (*Role) ProtoMessage()               {}
+func (*Role) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *Role) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Role) GetActions() []string {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func (m *Role) GetTrustedIdentities() []string {
+	if m != nil {
+		return m.TrustedIdentities
+	}
+	return nil
+}
+
+func (m *Role) GetTrustedWebIdentities() []*WebIdentityTrust {
+	if m != nil {
+		return m.TrustedWebIdentities
+	}
+	return nil
+}
+
+func (m *Role) GetMaxSessionDurationSeconds() int64 {
+	if m != nil {
+		return m.MaxSessionDurationSeconds
+	}
+	return 0
+}
+
+type WebIdentityTrust struct {
+	Issuer string `protobuf:"bytes,1,opt,name=issuer" json:"issuer,omitempty"`
+	// empty to accept any audience
+	Audience string `protobuf:"bytes,2,opt,name=audience" json:"audience,omitempty"`
+	// empty to accept any subject
+	Subjects []string `protobuf:"bytes,3,rep,name=subjects" json:"subjects,omitempty"`
+	// all the conditions must match the claims of the token
+	Conditions []*WebIdentityClaimCondition `protobuf:"bytes,4,rep,name=conditions" json:"conditions,omitempty"`
+}
+
+func (m *WebIdentityTrust) Reset()                    { *m = WebIdentityTrust{} }
+func (m *WebIdentityTrust) String() string            { return proto.CompactTextString(m) }
+func (*WebIdentityTrust) ProtoMessage()               {}
+func (*WebIdentityTrust) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *WebIdentityTrust) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *WebIdentityTrust) GetAudience() string {
+	if m != nil {
+		return m.Audience
+	}
+	return ""
+}
+
+func (m *WebIdentityTrust) GetSubjects() []string {
+	if m != nil {
+		return m.Subjects
+	}
+	return nil
+}
+
+func (m *WebIdentityTrust) GetConditions() []*WebIdentityClaimCondition {
+	if m != nil {
+		return m.Conditions
+	}
+	return nil
+}
+
+// matches a string claim, or a claim of strings like groups, with one of the values
+type WebIdentityClaimCondition struct {
+	Claim  string   `protobuf:"bytes,1,opt,name=claim" json:"claim,omitempty"`
+	Values []string `protobuf:"bytes,2,rep,name=values" json:"values,omitempty"`
+}
+
+func (m *WebIdentityClaimCondition) Reset()                    { *m = WebIdentityClaimCondition{} }
+func (m *WebIdentityClaimCondition) String() string            { return proto.CompactTextString(m) }
+func (*WebIdentityClaimCondition) ProtoMessage()               {}
+func (*WebIdentityClaimCondition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *WebIdentityClaimCondition) GetClaim() string {
+	if m != nil {
+		return m.Claim
+	}
+	return ""
+}
+
+func (m *WebIdentityClaimCondition) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// verifies the web identity tokens, signed with HS256 by the signing_key,
+// or with RS256 or ES256 by the private key of the PEM encoded public_key,
+// or else by the keys of the OpenID Connect provider at the jwks_uri,
+// discovered from {issuer}/.well-known/openid-configuration if empty
+type WebIdentityProvider struct {
+	Issuer     string `protobuf:"bytes,1,opt,name=issuer" json:"issuer,omitempty"`
+	SigningKey string `protobuf:"bytes,2,opt,name=signing_key,json=signingKey" json:"signing_key,omitempty"`
+	PublicKey  string `protobuf:"bytes,3,opt,name=public_key,json=publicKey" json:"public_key,omitempty"`
+	JwksUri    string `protobuf:"bytes,4,opt,name=jwks_uri,json=jwksUri" json:"jwks_uri,omitempty"`
+	// the audiences accepted from this issuer, required with the jwks keys, empty to accept any audience otherwise
+	ClientIds []string `protobuf:"bytes,5,rep,name=client_ids,json=clientIds" json:"client_ids,omitempty"`
+	// defaults to 3600 seconds, the keys are also refreshed for a token signed by an unknown key
+	JwksRefreshSeconds int64 `protobuf:"varint,6,opt,name=jwks_refresh_seconds,json=jwksRefreshSeconds" json:"jwks_refresh_seconds,omitempty"`
+}
+
+func (m *WebIdentityProvider) Reset()                    { *m = WebIdentityProvider{} }
+func (m *WebIdentityProvider) String() string            { return proto.CompactTextString(m) }
+func (*WebIdentityProvider) ProtoMessage()               {}
+func (*WebIdentityProvider) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *WebIdentityProvider) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *WebIdentityProvider) GetSigningKey() string {
+	if m != nil {
+		return m.SigningKey
+	}
+	return ""
+}
+
+func (m *WebIdentityProvider) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *WebIdentityProvider) GetJwksUri() string {
+	if m != nil {
+		return m.JwksUri
+	}
+	return ""
+}
+
+func (m *WebIdentityProvider) GetClientIds() []string {
+	if m != nil {
+		return m.ClientIds
+	}
+	return nil
+}
+
+func (m *WebIdentityProvider) GetJwksRefreshSeconds() int64 {
+	if m != nil {
+		return m.JwksRefreshSeconds
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*S3ApiConfiguration)(nil), "iam_pb.S3ApiConfiguration")
 	proto.RegisterType((*Identity)(nil), "iam_pb.Identity")
 	proto.RegisterType((*Credential)(nil), "iam_pb.Credential")
+	proto.RegisterType((*Role)(nil), "iam_pb.Role")
+	proto.RegisterType((*WebIdentityTrust)(nil), "iam_pb.WebIdentityTrust")
+	proto.RegisterType((*WebIdentityClaimCondition)(nil), "iam_pb.WebIdentityClaimCondition")
+	proto.RegisterType((*WebIdentityProvider)(nil), "iam_pb.WebIdentityProvider")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -154,21 +360,41 @@ var _SeaweedIdentityAccessManagement_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("iam.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 250 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x90, 0x41, 0x4b, 0xc3, 0x40,
-	0x10, 0x85, 0x69, 0x23, 0xb5, 0x99, 0x5e, 0xca, 0x9c, 0xf6, 0xa0, 0x18, 0x73, 0xca, 0x29, 0x48,
-	0xeb, 0x1f, 0xa8, 0x05, 0xa1, 0x16, 0x41, 0xd2, 0x1f, 0x50, 0xa6, 0xdb, 0x69, 0x19, 0xec, 0x6e,
-	0x42, 0x76, 0x45, 0xf2, 0xef, 0x25, 0xbb, 0x46, 0x7b, 0xdb, 0x7d, 0xdf, 0x7b, 0xb3, 0x3b, 0x0f,
-	0x52, 0x21, 0x53, 0x36, 0x6d, 0xed, 0x6b, 0x9c, 0x08, 0x99, 0x7d, 0x73, 0xc8, 0x5f, 0x01, 0x77,
-	0xcb, 0x55, 0x23, 0xeb, 0xda, 0x9e, 0xe4, 0xfc, 0xd5, 0x92, 0x97, 0xda, 0xe2, 0x13, 0x80, 0x1c,
-	0xd9, 0x7a, 0xf1, 0xc2, 0x4e, 0x8d, 0xb2, 0xa4, 0x98, 0x2d, 0xe6, 0x65, 0x8c, 0x94, 0x9b, 0x48,
-	0xba, 0xea, 0xca, 0x93, 0x5b, 0x98, 0x0e, 0x3a, 0x22, 0xdc, 0x58, 0x32, 0xac, 0x46, 0xd9, 0xa8,
-	0x48, 0xab, 0x70, 0xc6, 0x67, 0x98, 0xe9, 0x96, 0x83, 0x83, 0x2e, 0x4e, 0x8d, 0xc3, 0x48, 0x1c,
-	0x46, 0xae, 0xff, 0x50, 0x75, 0x6d, 0x43, 0x05, 0xb7, 0xa4, 0xfb, 0x1f, 0x39, 0x95, 0x64, 0x49,
-	0x91, 0x56, 0xc3, 0x35, 0x7f, 0x03, 0xf8, 0x0f, 0xe1, 0x3d, 0x00, 0x69, 0xcd, 0xce, 0xed, 0x3f,
-	0xb9, 0xfb, 0x7d, 0x37, 0x8d, 0xca, 0x96, 0xbb, 0x1e, 0x3b, 0xd6, 0x2d, 0xfb, 0x80, 0xc7, 0x11,
-	0x47, 0x65, 0xcb, 0xdd, 0xe2, 0x11, 0x1e, 0x76, 0x4c, 0xdf, 0xcc, 0xc7, 0x61, 0x85, 0x55, 0x88,
-	0xbe, 0x93, 0xa5, 0x33, 0x1b, 0xb6, 0xfe, 0xe5, 0x0e, 0xe6, 0x2e, 0x5a, 0x4e, 0xae, 0xd4, 0x17,
-	0xe9, 0xb5, 0xe9, 0x86, 0xcc, 0x47, 0x5f, 0xe6, 0x61, 0x12, 0x3a, 0x5d, 0xfe, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x83, 0x4f, 0x61, 0x03, 0x60, 0x01, 0x00, 0x00,
+	// 573 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0x96, 0xf3, 0xd7, 0x64, 0xc2, 0x21, 0x2c, 0x51, 0xe4, 0x40, 0x51, 0x53, 0x9f, 0x72, 0x21,
+	0xaa, 0x5a, 0xee, 0x28, 0x0d, 0x97, 0x50, 0x81, 0x8a, 0x03, 0xe2, 0x68, 0xad, 0xed, 0x49, 0xd8,
+	0xd6, 0x5e, 0x47, 0xbb, 0x76, 0x53, 0x3f, 0x10, 0x8f, 0xc1, 0x83, 0xf0, 0x1e, 0x3c, 0x00, 0xda,
+	0x1f, 0x27, 0x06, 0xd2, 0x5b, 0x66, 0xbe, 0xef, 0xcb, 0xcc, 0x7c, 0x33, 0x5e, 0xe8, 0x31, 0x9a,
+	0xce, 0xb6, 0x22, 0xcb, 0x33, 0xd2, 0x61, 0x34, 0x0d, 0xb6, 0xa1, 0xf7, 0xd3, 0x01, 0xb2, 0xba,
+	0x9a, 0x6f, 0xd9, 0x22, 0xe3, 0x6b, 0xb6, 0x29, 0x04, 0xcd, 0x59, 0xc6, 0xc9, 0x05, 0x00, 0x8b,
+	0x91, 0xe7, 0x2c, 0x67, 0x28, 0x5d, 0x67, 0xd2, 0x9c, 0xf6, 0x2f, 0x07, 0x33, 0xa3, 0x99, 0x2d,
+	0x0d, 0x52, 0xfa, 0x35, 0x0e, 0xf1, 0xa0, 0x2d, 0xb2, 0x04, 0xa5, 0xdb, 0xd0, 0xe4, 0x67, 0x15,
+	0xd9, 0xcf, 0x12, 0xf4, 0x0d, 0x44, 0x3e, 0xc3, 0x68, 0x87, 0x61, 0x60, 0x55, 0x65, 0xb0, 0x15,
+	0xd9, 0x03, 0x8b, 0x51, 0x48, 0xb7, 0xa9, 0x45, 0xaf, 0x2a, 0xd1, 0x37, 0x0c, 0xab, 0x22, 0xb7,
+	0x96, 0xe3, 0x0f, 0x77, 0xff, 0x27, 0xa5, 0xc7, 0xa1, 0x5b, 0x25, 0x09, 0x81, 0x16, 0xa7, 0x29,
+	0xba, 0xce, 0xc4, 0x99, 0xf6, 0x7c, 0xfd, 0x9b, 0xbc, 0x85, 0x7e, 0x24, 0x50, 0x33, 0x68, 0x52,
+	0x35, 0x47, 0xaa, 0x3a, 0x8b, 0x3d, 0xe4, 0xd7, 0x69, 0xc4, 0x85, 0x13, 0x1a, 0x29, 0x23, 0x4c,
+	0x67, 0x3d, 0xbf, 0x0a, 0xbd, 0x0f, 0x00, 0x07, 0x11, 0x79, 0x0d, 0x40, 0xa3, 0x08, 0xa5, 0x0c,
+	0xee, 0xb1, 0xb4, 0x75, 0x7b, 0x26, 0x73, 0x83, 0xa5, 0x82, 0x25, 0x46, 0x02, 0x73, 0x0d, 0x37,
+	0x0c, 0x6c, 0x32, 0x37, 0x58, 0x7a, 0xbf, 0x1d, 0x68, 0x29, 0x7b, 0x8e, 0x36, 0x5e, 0x6b, 0xa1,
+	0xf1, 0x57, 0x0b, 0xe4, 0x0d, 0x90, 0x5c, 0x14, 0x32, 0xc7, 0x38, 0xa8, 0xed, 0xc8, 0xf4, 0xf9,
+	0xdc, 0x22, 0xcb, 0xc3, 0x62, 0x3e, 0xc1, 0xa8, 0xa2, 0xd7, 0xcc, 0x57, 0x92, 0x96, 0x36, 0xc3,
+	0x3d, 0x62, 0xfa, 0x17, 0x25, 0xf0, 0x87, 0x56, 0x77, 0x00, 0xd4, 0xff, 0xbd, 0x83, 0xd3, 0x94,
+	0x3e, 0x06, 0x12, 0xa5, 0x64, 0x19, 0x0f, 0x62, 0x7b, 0x32, 0x81, 0xc4, 0x28, 0xe3, 0xb1, 0x74,
+	0xdb, 0x13, 0x67, 0xda, 0xf4, 0xc7, 0x29, 0x7d, 0x5c, 0x19, 0xca, 0x7b, 0xcb, 0x58, 0x19, 0x82,
+	0xf7, 0xc3, 0x81, 0xc1, 0xbf, 0xb5, 0xc8, 0x08, 0x3a, 0x4c, 0xca, 0x02, 0x85, 0x35, 0xc1, 0x46,
+	0xe4, 0x25, 0x74, 0x69, 0x11, 0x33, 0xe4, 0x11, 0x5a, 0x03, 0xf7, 0xb1, 0xc2, 0x64, 0x11, 0xde,
+	0x61, 0x94, 0x57, 0xe3, 0xef, 0x63, 0x32, 0x07, 0x50, 0xd5, 0x98, 0x71, 0xd0, 0x4c, 0x7a, 0x7e,
+	0x64, 0xd2, 0x45, 0x42, 0x59, 0xba, 0xa8, 0x98, 0x7e, 0x4d, 0xe4, 0x2d, 0x61, 0xfc, 0x24, 0x91,
+	0x0c, 0xa1, 0x1d, 0xa9, 0x8c, 0x6d, 0xd7, 0x04, 0x6a, 0x8a, 0x07, 0x9a, 0x14, 0x58, 0xed, 0xcc,
+	0x46, 0xde, 0x2f, 0x07, 0x5e, 0x1c, 0xb9, 0xe9, 0x27, 0xa7, 0x3e, 0x83, 0xbe, 0x64, 0x1b, 0xce,
+	0xf8, 0xa6, 0x76, 0x39, 0x60, 0x53, 0xf6, 0xb2, 0xb6, 0x45, 0x98, 0xb0, 0x48, 0xe3, 0x4d, 0x73,
+	0x59, 0x26, 0xa3, 0xe0, 0x31, 0x74, 0xef, 0x76, 0xf7, 0x32, 0x28, 0x04, 0x73, 0x5b, 0x1a, 0x3c,
+	0x51, 0xf1, 0x57, 0xc1, 0x94, 0x32, 0x4a, 0x18, 0xf2, 0x3c, 0x60, 0x7a, 0x59, 0xaa, 0xcd, 0x9e,
+	0xc9, 0x2c, 0x63, 0x49, 0x2e, 0x60, 0xa8, 0x95, 0x02, 0xd7, 0x02, 0xe5, 0xf7, 0xfd, 0x56, 0x3b,
+	0x7a, 0xab, 0x44, 0x61, 0xbe, 0x81, 0xec, 0x3a, 0x2f, 0xcf, 0xe1, 0x6c, 0x85, 0x74, 0x87, 0xfb,
+	0xa3, 0x2b, 0xe7, 0xfa, 0x03, 0xf8, 0x48, 0x39, 0xdd, 0x60, 0x8a, 0x3c, 0xbf, 0x3e, 0x85, 0x81,
+	0x34, 0x94, 0xb5, 0x9c, 0x99, 0x5a, 0xd7, 0xdd, 0x25, 0x4d, 0x6f, 0xd5, 0x53, 0x14, 0x76, 0xf4,
+	0x8b, 0x74, 0xf5, 0x67, 0x00, 0x86, 0xda, 0x87, 0x29, 0x9e, 0x04, 0x00, 0x00,
 }
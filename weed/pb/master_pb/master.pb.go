@@ -48,6 +48,11 @@ It has these top-level messages:
 	LeaseAdminTokenResponse
 	ReleaseAdminTokenRequest
 	ReleaseAdminTokenResponse
+	VacuumVolumeRequest
+	VacuumVolumeResponse
+	VacuumVolumeResult
+	SubscribeTopologyRequest
+	TopologyEvent
 */
 package master_pb
 
@@ -91,6 +96,7 @@ type Heartbeat struct {
 	NewEcShards     []*VolumeEcShardInformationMessage `protobuf:"bytes,17,rep,name=new_ec_shards,json=newEcShards" json:"new_ec_shards,omitempty"`
 	DeletedEcShards []*VolumeEcShardInformationMessage `protobuf:"bytes,18,rep,name=deleted_ec_shards,json=deletedEcShards" json:"deleted_ec_shards,omitempty"`
 	HasNoEcShards   bool                               `protobuf:"varint,19,opt,name=has_no_ec_shards,json=hasNoEcShards" json:"has_no_ec_shards,omitempty"`
+	DiskType        string                             `protobuf:"bytes,20,opt,name=disk_type,json=diskType" json:"disk_type,omitempty"`
 }
 
 func (m *Heartbeat) Reset()                    { *m = Heartbeat{} }
@@ -210,6 +216,13 @@ func (m *Heartbeat) GetHasNoEcShards() bool {
 	return false
 }
 
+func (m *Heartbeat) GetDiskType() string {
+	if m != nil {
+		return m.DiskType
+	}
+	return ""
+}
+
 type HeartbeatResponse struct {
 	VolumeSizeLimit        uint64            `protobuf:"varint,1,opt,name=volume_size_limit,json=volumeSizeLimit" json:"volume_size_limit,omitempty"`
 	Leader                 string            `protobuf:"bytes,2,opt,name=leader" json:"leader,omitempty"`
@@ -273,6 +286,7 @@ type VolumeInformationMessage struct {
 	ModifiedAtSecond  int64  `protobuf:"varint,12,opt,name=modified_at_second,json=modifiedAtSecond" json:"modified_at_second,omitempty"`
 	RemoteStorageName string `protobuf:"bytes,13,opt,name=remote_storage_name,json=remoteStorageName" json:"remote_storage_name,omitempty"`
 	RemoteStorageKey  string `protobuf:"bytes,14,opt,name=remote_storage_key,json=remoteStorageKey" json:"remote_storage_key,omitempty"`
+	AppendOnly        bool   `protobuf:"varint,15,opt,name=append_only,json=appendOnly" json:"append_only,omitempty"`
 }
 
 func (m *VolumeInformationMessage) Reset()                    { *m = VolumeInformationMessage{} }
@@ -378,12 +392,20 @@ func (m *VolumeInformationMessage) GetRemoteStorageKey() string {
 	return ""
 }
 
+func (m *VolumeInformationMessage) GetAppendOnly() bool {
+	if m != nil {
+		return m.AppendOnly
+	}
+	return false
+}
+
 type VolumeShortInformationMessage struct {
 	Id               uint32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
 	Collection       string `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
 	ReplicaPlacement uint32 `protobuf:"varint,8,opt,name=replica_placement,json=replicaPlacement" json:"replica_placement,omitempty"`
 	Version          uint32 `protobuf:"varint,9,opt,name=version" json:"version,omitempty"`
 	Ttl              uint32 `protobuf:"varint,10,opt,name=ttl" json:"ttl,omitempty"`
+	AppendOnly       bool   `protobuf:"varint,11,opt,name=append_only,json=appendOnly" json:"append_only,omitempty"`
 }
 
 func (m *VolumeShortInformationMessage) Reset()                    { *m = VolumeShortInformationMessage{} }
@@ -426,6 +448,13 @@ func (m *VolumeShortInformationMessage) GetTtl() uint32 {
 	return 0
 }
 
+func (m *VolumeShortInformationMessage) GetAppendOnly() bool {
+	if m != nil {
+		return m.AppendOnly
+	}
+	return false
+}
+
 type VolumeEcShardInformationMessage struct {
 	Id          uint32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
 	Collection  string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
@@ -728,6 +757,8 @@ type AssignRequest struct {
 	DataNode            string `protobuf:"bytes,7,opt,name=data_node,json=dataNode" json:"data_node,omitempty"`
 	MemoryMapMaxSizeMb  uint32 `protobuf:"varint,8,opt,name=memory_map_max_size_mb,json=memoryMapMaxSizeMb" json:"memory_map_max_size_mb,omitempty"`
 	WritableVolumeCount uint32 `protobuf:"varint,9,opt,name=Writable_volume_count,json=WritableVolumeCount" json:"Writable_volume_count,omitempty"`
+	DiskType            string `protobuf:"bytes,10,opt,name=disk_type,json=diskType" json:"disk_type,omitempty"`
+	AppendOnly          bool   `protobuf:"varint,11,opt,name=append_only,json=appendOnly" json:"append_only,omitempty"`
 }
 
 func (m *AssignRequest) Reset()                    { *m = AssignRequest{} }
@@ -798,6 +829,20 @@ func (m *AssignRequest) GetWritableVolumeCount() uint32 {
 	return 0
 }
 
+func (m *AssignRequest) GetDiskType() string {
+	if m != nil {
+		return m.DiskType
+	}
+	return ""
+}
+
+func (m *AssignRequest) GetAppendOnly() bool {
+	if m != nil {
+		return m.AppendOnly
+	}
+	return false
+}
+
 type AssignResponse struct {
 	Fid       string `protobuf:"bytes,1,opt,name=fid" json:"fid,omitempty"`
 	Url       string `protobuf:"bytes,2,opt,name=url" json:"url,omitempty"`
@@ -1058,6 +1103,7 @@ type DataNodeInfo struct {
 	VolumeInfos       []*VolumeInformationMessage        `protobuf:"bytes,6,rep,name=volume_infos,json=volumeInfos" json:"volume_infos,omitempty"`
 	EcShardInfos      []*VolumeEcShardInformationMessage `protobuf:"bytes,7,rep,name=ec_shard_infos,json=ecShardInfos" json:"ec_shard_infos,omitempty"`
 	RemoteVolumeCount uint64                             `protobuf:"varint,8,opt,name=remote_volume_count,json=remoteVolumeCount" json:"remote_volume_count,omitempty"`
+	DiskType          string                             `protobuf:"bytes,9,opt,name=disk_type,json=diskType" json:"disk_type,omitempty"`
 }
 
 func (m *DataNodeInfo) Reset()                    { *m = DataNodeInfo{} }
@@ -1121,6 +1167,13 @@ func (m *DataNodeInfo) GetRemoteVolumeCount() uint64 {
 	return 0
 }
 
+func (m *DataNodeInfo) GetDiskType() string {
+	if m != nil {
+		return m.DiskType
+	}
+	return ""
+}
+
 type RackInfo struct {
 	Id                string          `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 	VolumeCount       uint64          `protobuf:"varint,2,opt,name=volume_count,json=volumeCount" json:"volume_count,omitempty"`
@@ -1573,6 +1626,246 @@ func (m *ReleaseAdminTokenResponse) String() string            { return proto.Co
 func (*ReleaseAdminTokenResponse) ProtoMessage()               {}
 func (*ReleaseAdminTokenResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
 
+type VacuumVolumeRequest struct {
+	GarbageThreshold float64  `protobuf:"fixed64,1,opt,name=garbage_threshold,json=garbageThreshold" json:"garbage_threshold,omitempty"`
+	VolumeId         uint32   `protobuf:"varint,2,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
+	Collections      []string `protobuf:"bytes,3,rep,name=collections" json:"collections,omitempty"`
+	DryRun           bool     `protobuf:"varint,4,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+}
+
+func (m *VacuumVolumeRequest) Reset()                    { *m = VacuumVolumeRequest{} }
+func (m *VacuumVolumeRequest) String() string            { return proto.CompactTextString(m) }
+func (*VacuumVolumeRequest) ProtoMessage()               {}
+func (*VacuumVolumeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *VacuumVolumeRequest) GetGarbageThreshold() float64 {
+	if m != nil {
+		return m.GarbageThreshold
+	}
+	return 0
+}
+
+func (m *VacuumVolumeRequest) GetVolumeId() uint32 {
+	if m != nil {
+		return m.VolumeId
+	}
+	return 0
+}
+
+func (m *VacuumVolumeRequest) GetCollections() []string {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+func (m *VacuumVolumeRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type VacuumVolumeResponse struct {
+	Volumes []*VacuumVolumeResult `protobuf:"bytes,1,rep,name=volumes" json:"volumes,omitempty"`
+}
+
+func (m *VacuumVolumeResponse) Reset()                    { *m = VacuumVolumeResponse{} }
+func (m *VacuumVolumeResponse) String() string            { return proto.CompactTextString(m) }
+func (*VacuumVolumeResponse) ProtoMessage()               {}
+func (*VacuumVolumeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *VacuumVolumeResponse) GetVolumes() []*VacuumVolumeResult {
+	if m != nil {
+		return m.Volumes
+	}
+	return nil
+}
+
+type VacuumVolumeResult struct {
+	VolumeId       uint32  `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
+	Collection     string  `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	GarbageRatio   float64 `protobuf:"fixed64,3,opt,name=garbage_ratio,json=garbageRatio" json:"garbage_ratio,omitempty"`
+	GarbageBytes   uint64  `protobuf:"varint,4,opt,name=garbage_bytes,json=garbageBytes" json:"garbage_bytes,omitempty"`
+	Vacuumed       bool    `protobuf:"varint,5,opt,name=vacuumed" json:"vacuumed,omitempty"`
+	ReclaimedBytes uint64  `protobuf:"varint,6,opt,name=reclaimed_bytes,json=reclaimedBytes" json:"reclaimed_bytes,omitempty"`
+	Error          string  `protobuf:"bytes,7,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *VacuumVolumeResult) Reset()                    { *m = VacuumVolumeResult{} }
+func (m *VacuumVolumeResult) String() string            { return proto.CompactTextString(m) }
+func (*VacuumVolumeResult) ProtoMessage()               {}
+func (*VacuumVolumeResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *VacuumVolumeResult) GetVolumeId() uint32 {
+	if m != nil {
+		return m.VolumeId
+	}
+	return 0
+}
+
+func (m *VacuumVolumeResult) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *VacuumVolumeResult) GetGarbageRatio() float64 {
+	if m != nil {
+		return m.GarbageRatio
+	}
+	return 0
+}
+
+func (m *VacuumVolumeResult) GetGarbageBytes() uint64 {
+	if m != nil {
+		return m.GarbageBytes
+	}
+	return 0
+}
+
+func (m *VacuumVolumeResult) GetVacuumed() bool {
+	if m != nil {
+		return m.Vacuumed
+	}
+	return false
+}
+
+func (m *VacuumVolumeResult) GetReclaimedBytes() uint64 {
+	if m != nil {
+		return m.ReclaimedBytes
+	}
+	return 0
+}
+
+func (m *VacuumVolumeResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type SubscribeTopologyRequest struct {
+	ClientName       string `protobuf:"bytes,1,opt,name=client_name,json=clientName" json:"client_name,omitempty"`
+	SinceSequence    uint64 `protobuf:"varint,2,opt,name=since_sequence,json=sinceSequence" json:"since_sequence,omitempty"`
+	HeartbeatSeconds uint32 `protobuf:"varint,3,opt,name=heartbeat_seconds,json=heartbeatSeconds" json:"heartbeat_seconds,omitempty"`
+}
+
+func (m *SubscribeTopologyRequest) Reset()                    { *m = SubscribeTopologyRequest{} }
+func (m *SubscribeTopologyRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeTopologyRequest) ProtoMessage()               {}
+func (*SubscribeTopologyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *SubscribeTopologyRequest) GetClientName() string {
+	if m != nil {
+		return m.ClientName
+	}
+	return ""
+}
+
+func (m *SubscribeTopologyRequest) GetSinceSequence() uint64 {
+	if m != nil {
+		return m.SinceSequence
+	}
+	return 0
+}
+
+func (m *SubscribeTopologyRequest) GetHeartbeatSeconds() uint32 {
+	if m != nil {
+		return m.HeartbeatSeconds
+	}
+	return 0
+}
+
+type TopologyEvent struct {
+	Sequence   uint64                             `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	TsNs       int64                              `protobuf:"varint,2,opt,name=ts_ns,json=tsNs" json:"ts_ns,omitempty"`
+	Type       string                             `protobuf:"bytes,3,opt,name=type" json:"type,omitempty"`
+	DataCenter string                             `protobuf:"bytes,4,opt,name=data_center,json=dataCenter" json:"data_center,omitempty"`
+	Rack       string                             `protobuf:"bytes,5,opt,name=rack" json:"rack,omitempty"`
+	Url        string                             `protobuf:"bytes,6,opt,name=url" json:"url,omitempty"`
+	PublicUrl  string                             `protobuf:"bytes,7,opt,name=public_url,json=publicUrl" json:"public_url,omitempty"`
+	Volumes    []*VolumeShortInformationMessage   `protobuf:"bytes,8,rep,name=volumes" json:"volumes,omitempty"`
+	EcShards   []*VolumeEcShardInformationMessage `protobuf:"bytes,9,rep,name=ec_shards,json=ecShards" json:"ec_shards,omitempty"`
+	Leader     string                             `protobuf:"bytes,10,opt,name=leader" json:"leader,omitempty"`
+}
+
+func (m *TopologyEvent) Reset()                    { *m = TopologyEvent{} }
+func (m *TopologyEvent) String() string            { return proto.CompactTextString(m) }
+func (*TopologyEvent) ProtoMessage()               {}
+func (*TopologyEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *TopologyEvent) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *TopologyEvent) GetTsNs() int64 {
+	if m != nil {
+		return m.TsNs
+	}
+	return 0
+}
+
+func (m *TopologyEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *TopologyEvent) GetDataCenter() string {
+	if m != nil {
+		return m.DataCenter
+	}
+	return ""
+}
+
+func (m *TopologyEvent) GetRack() string {
+	if m != nil {
+		return m.Rack
+	}
+	return ""
+}
+
+func (m *TopologyEvent) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *TopologyEvent) GetPublicUrl() string {
+	if m != nil {
+		return m.PublicUrl
+	}
+	return ""
+}
+
+func (m *TopologyEvent) GetVolumes() []*VolumeShortInformationMessage {
+	if m != nil {
+		return m.Volumes
+	}
+	return nil
+}
+
+func (m *TopologyEvent) GetEcShards() []*VolumeEcShardInformationMessage {
+	if m != nil {
+		return m.EcShards
+	}
+	return nil
+}
+
+func (m *TopologyEvent) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Heartbeat)(nil), "master_pb.Heartbeat")
 	proto.RegisterType((*HeartbeatResponse)(nil), "master_pb.HeartbeatResponse")
@@ -1616,6 +1909,11 @@ func init() {
 	proto.RegisterType((*LeaseAdminTokenResponse)(nil), "master_pb.LeaseAdminTokenResponse")
 	proto.RegisterType((*ReleaseAdminTokenRequest)(nil), "master_pb.ReleaseAdminTokenRequest")
 	proto.RegisterType((*ReleaseAdminTokenResponse)(nil), "master_pb.ReleaseAdminTokenResponse")
+	proto.RegisterType((*VacuumVolumeRequest)(nil), "master_pb.VacuumVolumeRequest")
+	proto.RegisterType((*VacuumVolumeResponse)(nil), "master_pb.VacuumVolumeResponse")
+	proto.RegisterType((*VacuumVolumeResult)(nil), "master_pb.VacuumVolumeResult")
+	proto.RegisterType((*SubscribeTopologyRequest)(nil), "master_pb.SubscribeTopologyRequest")
+	proto.RegisterType((*TopologyEvent)(nil), "master_pb.TopologyEvent")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1642,6 +1940,8 @@ type SeaweedClient interface {
 	ListMasterClients(ctx context.Context, in *ListMasterClientsRequest, opts ...grpc.CallOption) (*ListMasterClientsResponse, error)
 	LeaseAdminToken(ctx context.Context, in *LeaseAdminTokenRequest, opts ...grpc.CallOption) (*LeaseAdminTokenResponse, error)
 	ReleaseAdminToken(ctx context.Context, in *ReleaseAdminTokenRequest, opts ...grpc.CallOption) (*ReleaseAdminTokenResponse, error)
+	VacuumVolume(ctx context.Context, in *VacuumVolumeRequest, opts ...grpc.CallOption) (*VacuumVolumeResponse, error)
+	SubscribeTopology(ctx context.Context, in *SubscribeTopologyRequest, opts ...grpc.CallOption) (Seaweed_SubscribeTopologyClient, error)
 }
 
 type seaweedClient struct {
@@ -1813,6 +2113,47 @@ func (c *seaweedClient) ReleaseAdminToken(ctx context.Context, in *ReleaseAdminT
 	return out, nil
 }
 
+func (c *seaweedClient) VacuumVolume(ctx context.Context, in *VacuumVolumeRequest, opts ...grpc.CallOption) (*VacuumVolumeResponse, error) {
+	out := new(VacuumVolumeResponse)
+	err := grpc.Invoke(ctx, "/master_pb.Seaweed/VacuumVolume", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seaweedClient) SubscribeTopology(ctx context.Context, in *SubscribeTopologyRequest, opts ...grpc.CallOption) (Seaweed_SubscribeTopologyClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Seaweed_serviceDesc.Streams[2], c.cc, "/master_pb.Seaweed/SubscribeTopology", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &seaweedSubscribeTopologyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Seaweed_SubscribeTopologyClient interface {
+	Recv() (*TopologyEvent, error)
+	grpc.ClientStream
+}
+
+type seaweedSubscribeTopologyClient struct {
+	grpc.ClientStream
+}
+
+func (x *seaweedSubscribeTopologyClient) Recv() (*TopologyEvent, error) {
+	m := new(TopologyEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Seaweed service
 
 type SeaweedServer interface {
@@ -1829,6 +2170,8 @@ type SeaweedServer interface {
 	ListMasterClients(context.Context, *ListMasterClientsRequest) (*ListMasterClientsResponse, error)
 	LeaseAdminToken(context.Context, *LeaseAdminTokenRequest) (*LeaseAdminTokenResponse, error)
 	ReleaseAdminToken(context.Context, *ReleaseAdminTokenRequest) (*ReleaseAdminTokenResponse, error)
+	VacuumVolume(context.Context, *VacuumVolumeRequest) (*VacuumVolumeResponse, error)
+	SubscribeTopology(*SubscribeTopologyRequest, Seaweed_SubscribeTopologyServer) error
 }
 
 func RegisterSeaweedServer(s *grpc.Server, srv SeaweedServer) {
@@ -2085,6 +2428,45 @@ func _Seaweed_ReleaseAdminToken_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Seaweed_VacuumVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VacuumVolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeaweedServer).VacuumVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/master_pb.Seaweed/VacuumVolume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeaweedServer).VacuumVolume(ctx, req.(*VacuumVolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seaweed_SubscribeTopology_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTopologyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SeaweedServer).SubscribeTopology(m, &seaweedSubscribeTopologyServer{stream})
+}
+
+type Seaweed_SubscribeTopologyServer interface {
+	Send(*TopologyEvent) error
+	grpc.ServerStream
+}
+
+type seaweedSubscribeTopologyServer struct {
+	grpc.ServerStream
+}
+
+func (x *seaweedSubscribeTopologyServer) Send(m *TopologyEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Seaweed_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "master_pb.Seaweed",
 	HandlerType: (*SeaweedServer)(nil),
@@ -2133,6 +2515,10 @@ var _Seaweed_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ReleaseAdminToken",
 			Handler:    _Seaweed_ReleaseAdminToken_Handler,
 		},
+		{
+			MethodName: "VacuumVolume",
+			Handler:    _Seaweed_VacuumVolume_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2147,6 +2533,11 @@ var _Seaweed_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "SubscribeTopology",
+			Handler:       _Seaweed_SubscribeTopology_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "master.proto",
 }
@@ -2154,151 +2545,175 @@ var _Seaweed_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("master.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2334 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xd4, 0x59, 0x4b, 0x6f, 0x1c, 0xc7,
-	0x11, 0xd6, 0xec, 0xf2, 0xb1, 0x5b, 0xcb, 0x7d, 0x35, 0x29, 0x6a, 0xb9, 0x7a, 0x90, 0x1a, 0xdb,
-	0x30, 0xa5, 0x38, 0x8c, 0x43, 0x1b, 0x88, 0x11, 0xc7, 0x30, 0x28, 0x8a, 0x56, 0x08, 0x89, 0xb4,
-	0x34, 0x64, 0x64, 0xc0, 0x40, 0x30, 0xee, 0x9d, 0x69, 0x52, 0x03, 0xce, 0x2b, 0xd3, 0xbd, 0x14,
-	0xd7, 0xb9, 0x04, 0xc8, 0x2d, 0xc9, 0x25, 0xc8, 0x21, 0x7f, 0x21, 0x97, 0x9c, 0x92, 0xb3, 0x2f,
-	0xf9, 0x47, 0xb9, 0xe4, 0xe0, 0x4b, 0xd0, 0xaf, 0x99, 0x9e, 0x7d, 0x90, 0xa6, 0x01, 0x03, 0xd6,
-	0x6d, 0xa6, 0xaa, 0xba, 0xba, 0xfa, 0xab, 0xee, 0xaa, 0xaf, 0x67, 0x60, 0x29, 0xc2, 0x94, 0x91,
-	0x6c, 0x2b, 0xcd, 0x12, 0x96, 0xa0, 0xba, 0x7c, 0x73, 0xd3, 0x81, 0xfd, 0xe7, 0x05, 0xa8, 0xff,
-	0x9a, 0xe0, 0x8c, 0x0d, 0x08, 0x66, 0xa8, 0x05, 0x95, 0x20, 0xed, 0x59, 0x1b, 0xd6, 0x66, 0xdd,
-	0xa9, 0x04, 0x29, 0x42, 0x30, 0x97, 0x26, 0x19, 0xeb, 0x55, 0x36, 0xac, 0xcd, 0xa6, 0x23, 0x9e,
-	0xd1, 0x5d, 0x80, 0x74, 0x38, 0x08, 0x03, 0xcf, 0x1d, 0x66, 0x61, 0xaf, 0x2a, 0x6c, 0xeb, 0x52,
-	0xf2, 0x9b, 0x2c, 0x44, 0x9b, 0xd0, 0x89, 0xf0, 0x85, 0x7b, 0x9e, 0x84, 0xc3, 0x88, 0xb8, 0x5e,
-	0x32, 0x8c, 0x59, 0x6f, 0x4e, 0x0c, 0x6f, 0x45, 0xf8, 0xe2, 0xa5, 0x10, 0xef, 0x72, 0x29, 0xda,
-	0xe0, 0x51, 0x5d, 0xb8, 0x27, 0x41, 0x48, 0xdc, 0x33, 0x32, 0xea, 0xcd, 0x6f, 0x58, 0x9b, 0x73,
-	0x0e, 0x44, 0xf8, 0xe2, 0xb3, 0x20, 0x24, 0x4f, 0xc9, 0x08, 0xad, 0x43, 0xc3, 0xc7, 0x0c, 0xbb,
-	0x1e, 0x89, 0x19, 0xc9, 0x7a, 0x0b, 0x62, 0x2e, 0xe0, 0xa2, 0x5d, 0x21, 0xe1, 0xf1, 0x65, 0xd8,
-	0x3b, 0xeb, 0x2d, 0x0a, 0x8d, 0x78, 0xe6, 0xf1, 0x61, 0x3f, 0x0a, 0x62, 0x57, 0x44, 0x5e, 0x13,
-	0x53, 0xd7, 0x85, 0xe4, 0x39, 0x0f, 0xff, 0x13, 0x58, 0x94, 0xb1, 0xd1, 0x5e, 0x7d, 0xa3, 0xba,
-	0xd9, 0xd8, 0x7e, 0x6b, 0x2b, 0x47, 0x63, 0x4b, 0x86, 0xb7, 0x1f, 0x9f, 0x24, 0x59, 0x84, 0x59,
-	0x90, 0xc4, 0x07, 0x84, 0x52, 0x7c, 0x4a, 0x1c, 0x3d, 0x06, 0xed, 0x43, 0x23, 0x26, 0xaf, 0x5d,
-	0xed, 0x02, 0x84, 0x8b, 0xcd, 0x09, 0x17, 0x47, 0xaf, 0x92, 0x8c, 0x4d, 0xf1, 0x03, 0x31, 0x79,
-	0xfd, 0x52, 0xb9, 0x7a, 0x01, 0x6d, 0x9f, 0x84, 0x84, 0x11, 0x3f, 0x77, 0xd7, 0xb8, 0xa6, 0xbb,
-	0x96, 0x72, 0xa0, 0x5d, 0xbe, 0x0d, 0xad, 0x57, 0x98, 0xba, 0x71, 0x92, 0x7b, 0x5c, 0xda, 0xb0,
-	0x36, 0x6b, 0xce, 0xd2, 0x2b, 0x4c, 0x0f, 0x13, 0x6d, 0xf5, 0x04, 0xea, 0xc4, 0x73, 0xe9, 0x2b,
-	0x9c, 0xf9, 0xb4, 0xd7, 0x11, 0x53, 0x3e, 0x9c, 0x98, 0x72, 0xcf, 0x3b, 0xe2, 0x06, 0x53, 0x26,
-	0xad, 0x11, 0xa9, 0xa2, 0xe8, 0x10, 0x9a, 0x1c, 0x8c, 0xc2, 0x59, 0xf7, 0xda, 0xce, 0x38, 0x9a,
-	0x7b, 0xda, 0xdf, 0x4b, 0xe8, 0x6a, 0x44, 0x0a, 0x9f, 0xe8, 0xda, 0x3e, 0x35, 0xac, 0xb9, 0xdf,
-	0x77, 0xa1, 0xa3, 0x60, 0x29, 0xdc, 0x2e, 0x0b, 0x60, 0x9a, 0x02, 0x18, 0x6d, 0x68, 0xff, 0xa1,
-	0x02, 0xdd, 0xfc, 0x34, 0x38, 0x84, 0xa6, 0x49, 0x4c, 0x09, 0x7a, 0x08, 0x5d, 0xb5, 0x9d, 0x69,
-	0xf0, 0x35, 0x71, 0xc3, 0x20, 0x0a, 0x98, 0x38, 0x24, 0x73, 0x4e, 0x5b, 0x2a, 0x8e, 0x82, 0xaf,
-	0xc9, 0x33, 0x2e, 0x46, 0xab, 0xb0, 0x10, 0x12, 0xec, 0x93, 0x4c, 0x9c, 0x99, 0xba, 0xa3, 0xde,
-	0xd0, 0xbb, 0xd0, 0x8e, 0x08, 0xcb, 0x02, 0x8f, 0xba, 0xd8, 0xf7, 0x33, 0x42, 0xa9, 0x3a, 0x3a,
-	0x2d, 0x25, 0xde, 0x91, 0x52, 0xf4, 0x11, 0xf4, 0xb4, 0x61, 0xc0, 0xf7, 0xf8, 0x39, 0x0e, 0x5d,
-	0x4a, 0xbc, 0x24, 0xf6, 0xa9, 0x3a, 0x47, 0xab, 0x4a, 0xbf, 0xaf, 0xd4, 0x47, 0x52, 0x8b, 0x1e,
-	0x43, 0x87, 0xb2, 0x24, 0xc3, 0xa7, 0xc4, 0x1d, 0x60, 0xef, 0x8c, 0xf0, 0x11, 0xf3, 0x02, 0xbc,
-	0x35, 0x03, 0xbc, 0x23, 0x69, 0xf2, 0x48, 0x5a, 0x38, 0x6d, 0x5a, 0x7a, 0xa7, 0xf6, 0xb7, 0x55,
-	0xe8, 0xcd, 0x3a, 0x06, 0xa2, 0x3e, 0xf8, 0x62, 0xe9, 0x4d, 0xa7, 0x12, 0xf8, 0xfc, 0xfc, 0x71,
-	0x48, 0xc4, 0x5a, 0xe7, 0x1c, 0xf1, 0x8c, 0xee, 0x01, 0x78, 0x49, 0x18, 0x12, 0x8f, 0x0f, 0x54,
-	0x8b, 0x34, 0x24, 0xfc, 0x7c, 0x8a, 0x23, 0x5f, 0x94, 0x86, 0x39, 0xa7, 0xce, 0x25, 0xb2, 0x2a,
-	0xdc, 0x87, 0x25, 0x99, 0x3e, 0x65, 0x20, 0xab, 0x42, 0x43, 0xca, 0xa4, 0xc9, 0x7b, 0x80, 0xf4,
-	0x36, 0x19, 0x8c, 0x72, 0xc3, 0x05, 0x61, 0xd8, 0x51, 0x9a, 0x47, 0x23, 0x6d, 0x7d, 0x1b, 0xea,
-	0x19, 0xc1, 0xbe, 0x9b, 0xc4, 0xe1, 0x48, 0x14, 0x8a, 0x9a, 0x53, 0xe3, 0x82, 0xcf, 0xe3, 0x70,
-	0x84, 0x7e, 0x02, 0xdd, 0x8c, 0xa4, 0x61, 0xe0, 0x61, 0x37, 0x0d, 0xb1, 0x47, 0x22, 0x12, 0xeb,
-	0x9a, 0xd1, 0x51, 0x8a, 0xe7, 0x5a, 0x8e, 0x7a, 0xb0, 0x78, 0x4e, 0x32, 0xca, 0x97, 0x55, 0x17,
-	0x26, 0xfa, 0x15, 0x75, 0xa0, 0xca, 0x58, 0xd8, 0x03, 0x21, 0xe5, 0x8f, 0xe8, 0x01, 0x74, 0xbc,
-	0x24, 0x4a, 0xb1, 0xc7, 0xdc, 0x8c, 0x9c, 0x07, 0x62, 0x50, 0x43, 0xa8, 0xdb, 0x4a, 0xee, 0x28,
-	0x31, 0x5f, 0x4e, 0x94, 0xf8, 0xc1, 0x49, 0x40, 0x7c, 0x17, 0x33, 0x95, 0x6c, 0x71, 0x70, 0xab,
-	0x4e, 0x47, 0x6b, 0x76, 0x98, 0x4c, 0x33, 0xda, 0x82, 0xe5, 0x8c, 0x44, 0x09, 0x23, 0xae, 0x4e,
-	0x76, 0x8c, 0x23, 0xd2, 0x6b, 0x0a, 0x9c, 0xbb, 0x52, 0xa5, 0x72, 0x7c, 0x88, 0x23, 0xc2, 0xbd,
-	0x8f, 0xd9, 0xf3, 0x5a, 0xdb, 0x12, 0xe6, 0x9d, 0x92, 0xf9, 0x53, 0x32, 0xb2, 0xff, 0x61, 0xc1,
-	0xdd, 0x4b, 0x4b, 0xce, 0xc4, 0x16, 0xb8, 0x2a, 0xdd, 0x3f, 0x14, 0xc2, 0xf6, 0x10, 0xd6, 0xaf,
-	0x28, 0x04, 0x57, 0xc4, 0x5a, 0x99, 0x88, 0xd5, 0x86, 0x26, 0xf1, 0xdc, 0x20, 0xf6, 0xc9, 0x85,
-	0x3b, 0x08, 0x98, 0x3c, 0xa2, 0x4d, 0xa7, 0x41, 0xbc, 0x7d, 0x2e, 0x7b, 0x14, 0x30, 0x6a, 0x7f,
-	0x63, 0x41, 0xab, 0x7c, 0x86, 0xf8, 0x29, 0x60, 0xa3, 0x94, 0xa8, 0xbe, 0x29, 0x9e, 0xd5, 0xd4,
-	0x15, 0xd5, 0x49, 0x7d, 0xb4, 0x0f, 0x90, 0x66, 0x49, 0x4a, 0x32, 0x16, 0x10, 0xee, 0x97, 0x1f,
-	0xcb, 0x07, 0x33, 0x8f, 0xe5, 0xd6, 0xf3, 0xdc, 0x76, 0x2f, 0x66, 0xd9, 0xc8, 0x31, 0x06, 0xf7,
-	0x3f, 0x81, 0xf6, 0x98, 0x9a, 0xa3, 0xc3, 0xb3, 0x2a, 0x03, 0xe0, 0x8f, 0x68, 0x05, 0xe6, 0xcf,
-	0x71, 0x38, 0x24, 0x2a, 0x04, 0xf9, 0xf2, 0xcb, 0xca, 0x47, 0x96, 0xbd, 0x08, 0xf3, 0x7b, 0x51,
-	0xca, 0x46, 0x7c, 0x25, 0xed, 0xa3, 0x61, 0x4a, 0xb2, 0x47, 0x61, 0xe2, 0x9d, 0xed, 0x5d, 0xb0,
-	0x0c, 0xa3, 0xcf, 0xa1, 0x45, 0x32, 0x4c, 0x87, 0x19, 0x3f, 0x55, 0x7e, 0x10, 0x9f, 0x0a, 0x9f,
-	0xe5, 0x96, 0x34, 0x36, 0x66, 0x6b, 0x4f, 0x0e, 0xd8, 0x15, 0xf6, 0x4e, 0x93, 0x98, 0xaf, 0xfd,
-	0x2f, 0xa1, 0x59, 0xd2, 0x73, 0xb0, 0x78, 0x03, 0x57, 0x59, 0x11, 0xcf, 0xbc, 0x68, 0xa6, 0x38,
-	0x0b, 0xd8, 0x48, 0x11, 0x0d, 0xf5, 0xc6, 0x4b, 0x85, 0x2a, 0xbc, 0x81, 0x2f, 0x41, 0x6b, 0x3a,
-	0x75, 0x29, 0xd9, 0xf7, 0xa9, 0xfd, 0x04, 0x56, 0x9e, 0x12, 0x92, 0xee, 0x26, 0x71, 0x4c, 0x3c,
-	0x46, 0x7c, 0x87, 0xfc, 0x6e, 0x48, 0x28, 0xe3, 0x53, 0x88, 0x33, 0xa1, 0xf2, 0xc1, 0x9f, 0x79,
-	0x15, 0x38, 0xcd, 0x52, 0xcf, 0x35, 0xe8, 0x4c, 0x8d, 0x0b, 0x38, 0x27, 0xb0, 0xff, 0x6e, 0x41,
-	0x4b, 0xee, 0xa5, 0x67, 0x89, 0x27, 0x76, 0x10, 0x47, 0x94, 0xd3, 0x1b, 0x85, 0xe8, 0x30, 0x0b,
-	0xc7, 0x78, 0x4f, 0x65, 0x9c, 0xf7, 0xac, 0x41, 0x4d, 0x10, 0x83, 0x22, 0xd2, 0x45, 0xde, 0xeb,
-	0x03, 0x9f, 0x16, 0x25, 0xcd, 0x97, 0xea, 0x39, 0xa1, 0x6e, 0xe8, 0xde, 0xcd, 0x4d, 0x8a, 0xb6,
-	0x31, 0x6f, 0xb6, 0x0d, 0xfb, 0x18, 0x96, 0x9f, 0x25, 0xc9, 0xd9, 0x30, 0x95, 0xe1, 0xe9, 0x15,
-	0x96, 0x81, 0xb1, 0x36, 0xaa, 0x3c, 0x96, 0x1c, 0x98, 0xab, 0xf6, 0xb9, 0xfd, 0x5f, 0x0b, 0x56,
-	0xca, 0x6e, 0x55, 0xa7, 0xfb, 0x0a, 0x96, 0x73, 0xbf, 0x6e, 0xa8, 0xb0, 0x90, 0x13, 0x34, 0xb6,
-	0xdf, 0x37, 0xf6, 0xc0, 0xb4, 0xd1, 0x9a, 0x3d, 0xf9, 0x1a, 0x44, 0xa7, 0x7b, 0x3e, 0x26, 0xa1,
-	0xfd, 0x0b, 0xe8, 0x8c, 0x9b, 0xf1, 0xdc, 0xe4, 0xb3, 0x2a, 0xc4, 0x6b, 0x7a, 0x24, 0xfa, 0x39,
-	0xd4, 0x8b, 0x40, 0x2a, 0x22, 0x90, 0xe5, 0x52, 0x20, 0x6a, 0xae, 0xc2, 0x8a, 0xef, 0x7d, 0x92,
-	0x65, 0x49, 0xa6, 0xaa, 0x91, 0x7c, 0xb1, 0x3f, 0x86, 0xda, 0xf7, 0xce, 0xae, 0xfd, 0xaf, 0x0a,
-	0x34, 0x77, 0x28, 0x0d, 0x4e, 0x63, 0x9d, 0x82, 0x15, 0x98, 0x97, 0x7d, 0x47, 0x12, 0x01, 0xf9,
-	0x82, 0x36, 0xa0, 0xa1, 0x8a, 0x9a, 0x01, 0xbd, 0x29, 0xba, 0xb2, 0x5e, 0xaa, 0x42, 0x37, 0x27,
-	0x43, 0xe3, 0xad, 0x64, 0x8c, 0x05, 0xcf, 0xcf, 0x64, 0xc1, 0x0b, 0x06, 0x0b, 0xbe, 0x0d, 0x75,
-	0x31, 0x28, 0x4e, 0x7c, 0xa2, 0xe8, 0x71, 0x8d, 0x0b, 0x0e, 0x13, 0x9f, 0xa0, 0x6d, 0x58, 0x8d,
-	0x48, 0x94, 0x64, 0x23, 0x37, 0xc2, 0xa9, 0xcb, 0x49, 0xb8, 0x20, 0x36, 0xd1, 0x40, 0x15, 0x66,
-	0x24, 0xb5, 0x07, 0x38, 0x3d, 0xc0, 0x17, 0x9c, 0xdb, 0x1c, 0x0c, 0xd0, 0x36, 0xdc, 0xfc, 0x22,
-	0x0b, 0x18, 0x1e, 0x84, 0xa4, 0x4c, 0xee, 0x65, 0xa1, 0x5e, 0xd6, 0x4a, 0x83, 0xe1, 0xdb, 0x7f,
-	0xb3, 0xa0, 0xa5, 0x51, 0x53, 0x3b, 0xac, 0x03, 0xd5, 0x93, 0x3c, 0xcb, 0xfc, 0x51, 0xe7, 0xa2,
-	0x32, 0x2b, 0x17, 0x13, 0x37, 0x8c, 0x1c, 0xf9, 0x39, 0x13, 0xf9, 0x3c, 0xe9, 0xf3, 0x46, 0xd2,
-	0x39, 0x34, 0x78, 0xc8, 0x5e, 0x69, 0x68, 0xf8, 0xb3, 0x7d, 0x0a, 0xdd, 0x23, 0x86, 0x59, 0x40,
-	0x59, 0xe0, 0x51, 0x9d, 0xce, 0xb1, 0xc4, 0x59, 0x57, 0x25, 0xae, 0x32, 0x2b, 0x71, 0xd5, 0x3c,
-	0x71, 0xf6, 0x7f, 0x2c, 0x40, 0xe6, 0x4c, 0x0a, 0x82, 0x1f, 0x60, 0x2a, 0x0e, 0x19, 0x4b, 0x18,
-	0xa7, 0x8a, 0x9c, 0x8e, 0x29, 0x52, 0x25, 0x24, 0x3c, 0x7d, 0x7c, 0x37, 0x0c, 0x29, 0xf1, 0xa5,
-	0x56, 0x32, 0xaa, 0x1a, 0x17, 0x08, 0x65, 0x99, 0x90, 0x2d, 0x8c, 0x11, 0x32, 0x7b, 0x07, 0x1a,
-	0xaa, 0x39, 0x1d, 0xf3, 0xc6, 0x76, 0x75, 0xf4, 0x2a, 0xba, 0x4a, 0x01, 0xc4, 0x06, 0xc0, 0x6e,
-	0x11, 0xfd, 0x94, 0xf2, 0x6c, 0xff, 0x1e, 0x6e, 0x16, 0x16, 0xcf, 0x02, 0xca, 0x74, 0x5e, 0x3e,
-	0x84, 0xd5, 0x20, 0xf6, 0xc2, 0xa1, 0x4f, 0xdc, 0x98, 0xb7, 0xf7, 0x30, 0xbf, 0xd9, 0x58, 0x82,
-	0xca, 0xad, 0x28, 0xed, 0xa1, 0x50, 0xea, 0x1b, 0xce, 0x7b, 0x80, 0xf4, 0x28, 0xe2, 0xe5, 0x23,
-	0x2a, 0x62, 0x44, 0x47, 0x69, 0xf6, 0x3c, 0x65, 0x6d, 0xbf, 0x80, 0xd5, 0xf1, 0xc9, 0x55, 0xaa,
-	0x7e, 0x01, 0x8d, 0x02, 0x76, 0x5d, 0x07, 0x6f, 0x1a, 0xe5, 0xa7, 0x18, 0xe7, 0x98, 0x96, 0xf6,
-	0x4f, 0xe1, 0x56, 0xa1, 0x7a, 0x2c, 0x0a, 0xfd, 0x25, 0xdd, 0xc9, 0xee, 0x43, 0x6f, 0xd2, 0x5c,
-	0xc6, 0x60, 0xff, 0xb5, 0x0a, 0x4b, 0x8f, 0xd5, 0xc9, 0xe5, 0x1c, 0xc7, 0x60, 0x35, 0x92, 0x5a,
-	0xdc, 0x87, 0xa5, 0xd2, 0x81, 0x94, 0x64, 0xbc, 0x71, 0x6e, 0x5c, 0xb5, 0xa7, 0x5d, 0xca, 0xab,
-	0xc2, 0x6c, 0xfc, 0x52, 0xfe, 0x10, 0xba, 0x27, 0x19, 0x21, 0x93, 0xf7, 0xf7, 0x39, 0xa7, 0xcd,
-	0x15, 0xa6, 0xed, 0x16, 0x2c, 0x63, 0x8f, 0x05, 0xe7, 0x63, 0xd6, 0x72, 0x7f, 0x75, 0xa5, 0xca,
-	0xb4, 0xff, 0x2c, 0x0f, 0x34, 0x88, 0x4f, 0x12, 0xda, 0x5b, 0xf8, 0xee, 0xf7, 0x6f, 0xb5, 0x1a,
-	0xae, 0xa1, 0xe8, 0x39, 0xb4, 0xf4, 0x3d, 0x4e, 0x79, 0x5a, 0xbc, 0xf6, 0x1d, 0x71, 0x89, 0x14,
-	0x2a, 0x6a, 0x90, 0xea, 0xd2, 0x4a, 0x6a, 0x72, 0x25, 0x52, 0x65, 0x16, 0xb6, 0x7f, 0x57, 0xa0,
-	0xe6, 0x60, 0xef, 0xec, 0xcd, 0xce, 0xc7, 0xa7, 0xd0, 0xce, 0x7b, 0x44, 0x29, 0x25, 0xb7, 0x0c,
-	0x20, 0xcd, 0xad, 0xe7, 0x34, 0x7d, 0xe3, 0x6d, 0x26, 0x6c, 0x8b, 0xb3, 0x60, 0xfb, 0x67, 0x05,
-	0x5a, 0x8f, 0xf3, 0xbe, 0xf5, 0x66, 0x83, 0xb7, 0x0d, 0xc0, 0x1b, 0x6d, 0x09, 0x37, 0x93, 0x98,
-	0xe8, 0xed, 0xe1, 0xd4, 0x33, 0xf5, 0x74, 0x7d, 0xbc, 0xbe, 0xa9, 0xc0, 0xd2, 0x71, 0x92, 0x26,
-	0x61, 0x72, 0x3a, 0x7a, 0xb3, 0xd1, 0xda, 0x83, 0xae, 0xc1, 0x61, 0x4a, 0xa0, 0xad, 0x8d, 0x6d,
-	0xb6, 0x62, 0x73, 0x38, 0x6d, 0xbf, 0xf4, 0x7e, 0x7d, 0x00, 0x97, 0xa1, 0xab, 0x78, 0x7d, 0xd1,
-	0x52, 0xec, 0x3f, 0x5a, 0x80, 0x4c, 0xa9, 0xaa, 0xf5, 0xbf, 0x82, 0x26, 0x53, 0x58, 0x8b, 0xf8,
-	0xd4, 0xcd, 0xc7, 0x3c, 0x0b, 0x66, 0x2e, 0x9c, 0x25, 0x66, 0x66, 0xe6, 0x67, 0xb0, 0x32, 0xf1,
-	0x8d, 0x88, 0x13, 0x2a, 0x99, 0x91, 0xee, 0xd8, 0x67, 0xa2, 0x83, 0x81, 0xfd, 0x21, 0xdc, 0x94,
-	0x24, 0x5a, 0xf7, 0x21, 0xdd, 0x1f, 0x26, 0xd8, 0x70, 0xb3, 0x60, 0xc3, 0xf6, 0xb7, 0x16, 0xac,
-	0x8e, 0x0f, 0x53, 0xf1, 0x5f, 0x36, 0x0e, 0x61, 0x40, 0xaa, 0x5e, 0x9a, 0xbc, 0x5e, 0xd2, 0xe9,
-	0x0f, 0x26, 0x78, 0xfd, 0xb8, 0xef, 0x2d, 0x5d, 0x47, 0x0b, 0x6a, 0xdf, 0xa1, 0x65, 0x01, 0xed,
-	0x63, 0xe8, 0x4e, 0x98, 0xf1, 0x5b, 0x91, 0x9e, 0x57, 0xc5, 0xb4, 0xa8, 0x06, 0x7e, 0x0f, 0x62,
-	0x6f, 0xaf, 0xc3, 0xdd, 0x27, 0x84, 0x1d, 0x08, 0x9b, 0xdd, 0x24, 0x3e, 0x09, 0x4e, 0x87, 0x99,
-	0x34, 0x2a, 0x52, 0x7b, 0x6f, 0x96, 0x85, 0x82, 0x69, 0xca, 0x87, 0x38, 0xeb, 0xda, 0x1f, 0xe2,
-	0x2a, 0x97, 0x7d, 0x88, 0xb3, 0x3f, 0x86, 0x1e, 0xdf, 0x59, 0x2a, 0x8a, 0x30, 0x20, 0x31, 0xcb,
-	0x79, 0xe6, 0x3a, 0x34, 0x3c, 0x21, 0x71, 0x8d, 0x4f, 0x06, 0x20, 0x45, 0x9c, 0x5f, 0xd9, 0x8f,
-	0x60, 0x6d, 0xca, 0x60, 0x15, 0xfc, 0x3b, 0xd0, 0x12, 0xb7, 0x58, 0x15, 0x39, 0xd1, 0x77, 0xbf,
-	0x26, 0x97, 0xee, 0x68, 0xa1, 0xfd, 0x27, 0xbe, 0x4b, 0x08, 0xa6, 0x64, 0xc7, 0x8f, 0x82, 0xf8,
-	0x38, 0x39, 0x23, 0xf9, 0xb5, 0xe5, 0x1d, 0x68, 0xa5, 0x19, 0x39, 0x0f, 0x92, 0x21, 0x75, 0x19,
-	0x57, 0x88, 0x10, 0xaa, 0x4e, 0x53, 0x4b, 0x85, 0x35, 0x27, 0x50, 0xb9, 0x19, 0xbf, 0xe9, 0xbb,
-	0x2c, 0x88, 0xe4, 0xb7, 0x84, 0xaa, 0xd3, 0xd1, 0x9a, 0x67, 0x89, 0x77, 0x76, 0x1c, 0xc8, 0xcb,
-	0xb5, 0x30, 0x12, 0xbc, 0x46, 0xb2, 0xd2, 0x1a, 0x17, 0x1c, 0x72, 0x6e, 0x73, 0x00, 0xb7, 0x26,
-	0x62, 0x51, 0xcb, 0x59, 0x81, 0x79, 0x33, 0x06, 0xf9, 0x82, 0xee, 0x00, 0xc8, 0x29, 0xa9, 0x1b,
-	0x53, 0x35, 0xa7, 0x70, 0x77, 0x4c, 0x0f, 0xa9, 0xfd, 0x17, 0x0b, 0x7a, 0x0e, 0x09, 0x7f, 0x2c,
-	0xab, 0xbb, 0x0d, 0x6b, 0x53, 0xa2, 0x91, 0xeb, 0xdb, 0xfe, 0x5f, 0x0d, 0x16, 0x8f, 0x08, 0x7e,
-	0x4d, 0x88, 0x8f, 0xf6, 0xa1, 0x79, 0x44, 0x62, 0xbf, 0xf8, 0xd7, 0xb2, 0x62, 0x6c, 0xf6, 0x5c,
-	0xda, 0xbf, 0x33, 0x4d, 0x9a, 0x73, 0xc1, 0x1b, 0x9b, 0xd6, 0xfb, 0x16, 0x7a, 0x01, 0xcd, 0xd2,
-	0x77, 0x0f, 0xb4, 0x6e, 0x0c, 0x9a, 0xf6, 0x45, 0xa4, 0xbf, 0x36, 0xc1, 0x8c, 0xf4, 0xf1, 0xca,
-	0x5d, 0x2e, 0x99, 0x57, 0x7a, 0x74, 0x6f, 0xe6, 0x5d, 0x5f, 0x3a, 0x5c, 0xbf, 0xe2, 0x5b, 0x80,
-	0x7d, 0x03, 0x7d, 0x0a, 0x0b, 0xf2, 0xee, 0x87, 0x7a, 0x86, 0x71, 0xe9, 0x12, 0x5d, 0x8a, 0xab,
-	0x7c, 0x51, 0xb4, 0x6f, 0xa0, 0xa7, 0x00, 0xc5, 0xed, 0x09, 0xdd, 0x29, 0x7d, 0x2c, 0x1b, 0xbb,
-	0xbe, 0xf5, 0xef, 0xce, 0xd0, 0xe6, 0xce, 0xbe, 0x80, 0x56, 0x99, 0xe3, 0xa3, 0x8d, 0xa9, 0x34,
-	0xde, 0x68, 0x14, 0xfd, 0xfb, 0x97, 0x58, 0xe4, 0x8e, 0x7f, 0x0b, 0x9d, 0x71, 0xea, 0x8e, 0xec,
-	0xa9, 0x03, 0x4b, 0xd7, 0x80, 0xfe, 0x5b, 0x97, 0xda, 0x98, 0x20, 0x14, 0xbd, 0xaa, 0x04, 0xc2,
-	0x44, 0x63, 0x2b, 0x81, 0x30, 0xd9, 0xe0, 0x24, 0x08, 0xe5, 0x02, 0x5f, 0x02, 0x61, 0x6a, 0x3b,
-	0x2a, 0x81, 0x30, 0xbd, 0x3b, 0xd8, 0x37, 0x50, 0x02, 0xab, 0xd3, 0xcb, 0x2e, 0x32, 0x3f, 0x1c,
-	0x5e, 0x5a, 0xbb, 0xfb, 0x0f, 0xbe, 0x83, 0x65, 0x3e, 0xe1, 0x57, 0xd0, 0x9d, 0xa8, 0x92, 0xc8,
-	0x84, 0x74, 0x56, 0x01, 0xee, 0xbf, 0x7d, 0xb9, 0x51, 0x3e, 0xc3, 0x97, 0xd0, 0x1e, 0x2b, 0x5b,
-	0xa8, 0x04, 0xc5, 0xd4, 0x02, 0xd4, 0xb7, 0x2f, 0x33, 0x31, 0xa3, 0x9f, 0x28, 0x1a, 0xa5, 0xe8,
-	0x67, 0x15, 0xb8, 0x52, 0xf4, 0x33, 0xeb, 0x8e, 0x7d, 0x63, 0xb0, 0x20, 0x7e, 0xf4, 0x7e, 0xf0,
-	0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0x6d, 0x11, 0x8c, 0x9d, 0xf8, 0x1d, 0x00, 0x00,
+	// 2719 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xd4, 0x5a, 0x4b, 0x6f, 0xe4, 0xc6,
+	0xf1, 0x37, 0x67, 0x46, 0x9a, 0x99, 0x9a, 0x77, 0x4b, 0xab, 0x1d, 0x8d, 0xbd, 0x96, 0x96, 0xb6,
+	0x61, 0xf9, 0xf1, 0xd7, 0xdf, 0x91, 0x0d, 0xc4, 0x88, 0x63, 0x18, 0x92, 0x56, 0x76, 0x04, 0xaf,
+	0xe4, 0x5d, 0x4a, 0x59, 0x03, 0x06, 0x02, 0x9a, 0x43, 0xb6, 0x24, 0x42, 0x1c, 0x92, 0x61, 0xf7,
+	0x8c, 0x35, 0xce, 0x25, 0x40, 0x6e, 0x41, 0x80, 0x1c, 0x72, 0x08, 0x10, 0xe4, 0x94, 0x53, 0x2e,
+	0xc9, 0x29, 0x67, 0x5f, 0x72, 0xca, 0xa7, 0xc8, 0x67, 0x48, 0x0e, 0xb9, 0xe4, 0x12, 0xf4, 0x8b,
+	0x6c, 0x72, 0x1e, 0xb2, 0x1c, 0x18, 0x88, 0x6f, 0xec, 0xaa, 0xea, 0xea, 0xea, 0xea, 0xae, 0xea,
+	0x5f, 0xd5, 0x0c, 0x34, 0x47, 0x0e, 0xa1, 0x38, 0xd9, 0x8d, 0x93, 0x88, 0x46, 0xa8, 0x2e, 0x46,
+	0x76, 0x3c, 0x34, 0xff, 0xb8, 0x0a, 0xf5, 0x1f, 0x61, 0x27, 0xa1, 0x43, 0xec, 0x50, 0xd4, 0x86,
+	0x92, 0x1f, 0xf7, 0x8d, 0x6d, 0x63, 0xa7, 0x6e, 0x95, 0xfc, 0x18, 0x21, 0xa8, 0xc4, 0x51, 0x42,
+	0xfb, 0xa5, 0x6d, 0x63, 0xa7, 0x65, 0xf1, 0x6f, 0xf4, 0x00, 0x20, 0x1e, 0x0f, 0x03, 0xdf, 0xb5,
+	0xc7, 0x49, 0xd0, 0x2f, 0x73, 0xd9, 0xba, 0xa0, 0xfc, 0x38, 0x09, 0xd0, 0x0e, 0x74, 0x47, 0xce,
+	0x8d, 0x3d, 0x89, 0x82, 0xf1, 0x08, 0xdb, 0x6e, 0x34, 0x0e, 0x69, 0xbf, 0xc2, 0xa7, 0xb7, 0x47,
+	0xce, 0xcd, 0x33, 0x4e, 0x3e, 0x64, 0x54, 0xb4, 0xcd, 0xac, 0xba, 0xb1, 0x2f, 0xfc, 0x00, 0xdb,
+	0xd7, 0x78, 0xda, 0x5f, 0xd9, 0x36, 0x76, 0x2a, 0x16, 0x8c, 0x9c, 0x9b, 0x0f, 0xfd, 0x00, 0x7f,
+	0x8c, 0xa7, 0x68, 0x0b, 0x1a, 0x9e, 0x43, 0x1d, 0xdb, 0xc5, 0x21, 0xc5, 0x49, 0x7f, 0x95, 0xaf,
+	0x05, 0x8c, 0x74, 0xc8, 0x29, 0xcc, 0xbe, 0xc4, 0x71, 0xaf, 0xfb, 0x55, 0xce, 0xe1, 0xdf, 0xcc,
+	0x3e, 0xc7, 0x1b, 0xf9, 0xa1, 0xcd, 0x2d, 0xaf, 0xf1, 0xa5, 0xeb, 0x9c, 0xf2, 0x84, 0x99, 0xff,
+	0x3e, 0x54, 0x85, 0x6d, 0xa4, 0x5f, 0xdf, 0x2e, 0xef, 0x34, 0xf6, 0x5e, 0xda, 0x4d, 0xbd, 0xb1,
+	0x2b, 0xcc, 0x3b, 0x0e, 0x2f, 0xa2, 0x64, 0xe4, 0x50, 0x3f, 0x0a, 0x4f, 0x30, 0x21, 0xce, 0x25,
+	0xb6, 0xd4, 0x1c, 0x74, 0x0c, 0x8d, 0x10, 0x7f, 0x61, 0x2b, 0x15, 0xc0, 0x55, 0xec, 0xcc, 0xa8,
+	0x38, 0xbb, 0x8a, 0x12, 0x3a, 0x47, 0x0f, 0x84, 0xf8, 0x8b, 0x67, 0x52, 0xd5, 0x53, 0xe8, 0x78,
+	0x38, 0xc0, 0x14, 0x7b, 0xa9, 0xba, 0xc6, 0x1d, 0xd5, 0xb5, 0xa5, 0x02, 0xa5, 0xf2, 0x65, 0x68,
+	0x5f, 0x39, 0xc4, 0x0e, 0xa3, 0x54, 0x63, 0x73, 0xdb, 0xd8, 0xa9, 0x59, 0xcd, 0x2b, 0x87, 0x9c,
+	0x46, 0x4a, 0xea, 0x23, 0xa8, 0x63, 0xd7, 0x26, 0x57, 0x4e, 0xe2, 0x91, 0x7e, 0x97, 0x2f, 0xf9,
+	0xfa, 0xcc, 0x92, 0x47, 0xee, 0x19, 0x13, 0x98, 0xb3, 0x68, 0x0d, 0x0b, 0x16, 0x41, 0xa7, 0xd0,
+	0x62, 0xce, 0xc8, 0x94, 0xf5, 0xee, 0xac, 0x8c, 0x79, 0xf3, 0x48, 0xe9, 0x7b, 0x06, 0x3d, 0xe5,
+	0x91, 0x4c, 0x27, 0xba, 0xb3, 0x4e, 0xe5, 0xd6, 0x54, 0xef, 0xab, 0xd0, 0x95, 0x6e, 0xc9, 0xd4,
+	0xae, 0x71, 0xc7, 0xb4, 0xb8, 0x63, 0x52, 0xc1, 0xe7, 0xa1, 0xee, 0xf9, 0xe4, 0xda, 0xa6, 0xd3,
+	0x18, 0xf7, 0xd7, 0xf9, 0xa5, 0xaa, 0x31, 0xc2, 0xf9, 0x34, 0xc6, 0xe6, 0xcf, 0x4b, 0xd0, 0x4b,
+	0x43, 0xc5, 0xc2, 0x24, 0x8e, 0x42, 0x82, 0xd1, 0xeb, 0xd0, 0x93, 0x77, 0x9d, 0xf8, 0x5f, 0x62,
+	0x3b, 0xf0, 0x47, 0x3e, 0xe5, 0x11, 0x54, 0xb1, 0x3a, 0x82, 0x71, 0xe6, 0x7f, 0x89, 0x1f, 0x33,
+	0x32, 0xda, 0x80, 0xd5, 0x00, 0x3b, 0x1e, 0x4e, 0x78, 0x40, 0xd5, 0x2d, 0x39, 0x42, 0xaf, 0x42,
+	0x67, 0x84, 0x69, 0xe2, 0xbb, 0xc4, 0x76, 0x3c, 0x2f, 0xc1, 0x84, 0xc8, 0xb8, 0x6a, 0x4b, 0xf2,
+	0xbe, 0xa0, 0xa2, 0x77, 0xa1, 0xaf, 0x04, 0x7d, 0x16, 0x00, 0x13, 0x27, 0xb0, 0x09, 0x76, 0xa3,
+	0xd0, 0x23, 0x32, 0xc8, 0x36, 0x24, 0xff, 0x58, 0xb2, 0xcf, 0x04, 0x17, 0x3d, 0x82, 0x2e, 0xa1,
+	0x51, 0xe2, 0x5c, 0x62, 0x7b, 0xe8, 0xb8, 0xd7, 0x98, 0xcd, 0x58, 0xe1, 0x9e, 0xdd, 0xd4, 0x3c,
+	0x7b, 0x26, 0x44, 0x0e, 0x84, 0x84, 0xd5, 0x21, 0xb9, 0x31, 0x31, 0x7f, 0x5f, 0x81, 0xfe, 0xa2,
+	0x18, 0xe1, 0xc9, 0xc3, 0xe3, 0x5b, 0x6f, 0x59, 0x25, 0xdf, 0x63, 0xc1, 0xc9, 0x5c, 0xc2, 0xf7,
+	0x5a, 0xb1, 0xf8, 0x37, 0x7a, 0x11, 0xc0, 0x8d, 0x82, 0x00, 0xbb, 0x6c, 0xa2, 0xdc, 0xa4, 0x46,
+	0x61, 0xc1, 0xcb, 0xf3, 0x41, 0x96, 0x37, 0x2a, 0x56, 0x9d, 0x51, 0x44, 0xca, 0x78, 0x08, 0x4d,
+	0x71, 0xb6, 0x52, 0x40, 0xa4, 0x8c, 0x86, 0xa0, 0x09, 0x91, 0x37, 0x01, 0xa9, 0x3b, 0x34, 0x9c,
+	0xa6, 0x82, 0xab, 0x5c, 0xb0, 0x2b, 0x39, 0x07, 0x53, 0x25, 0xfd, 0x3c, 0xd4, 0x13, 0xec, 0x78,
+	0x76, 0x14, 0x06, 0x53, 0x9e, 0x45, 0x6a, 0x56, 0x8d, 0x11, 0x3e, 0x09, 0x83, 0x29, 0x7a, 0x03,
+	0x7a, 0x09, 0x8e, 0x03, 0xdf, 0x75, 0xec, 0x38, 0x70, 0x5c, 0x3c, 0xc2, 0xa1, 0x4a, 0x28, 0x5d,
+	0xc9, 0x78, 0xa2, 0xe8, 0xa8, 0x0f, 0xd5, 0x09, 0x4e, 0x08, 0xdb, 0x56, 0x9d, 0x8b, 0xa8, 0x21,
+	0xea, 0x42, 0x99, 0xd2, 0xa0, 0x0f, 0x9c, 0xca, 0x3e, 0xd1, 0x6b, 0xd0, 0x75, 0xa3, 0x51, 0xec,
+	0xb8, 0xd4, 0x4e, 0xf0, 0xc4, 0xe7, 0x93, 0x1a, 0x9c, 0xdd, 0x91, 0x74, 0x4b, 0x92, 0xd9, 0x76,
+	0x46, 0x91, 0xe7, 0x5f, 0xf8, 0xd8, 0xb3, 0x1d, 0x2a, 0x0f, 0x9b, 0x47, 0x75, 0xd9, 0xea, 0x2a,
+	0xce, 0x3e, 0x15, 0xc7, 0x8c, 0x76, 0x61, 0x2d, 0xc1, 0xa3, 0x88, 0x62, 0x5b, 0x1d, 0x76, 0xe8,
+	0x8c, 0x70, 0xbf, 0xc5, 0xfd, 0xdc, 0x13, 0x2c, 0x79, 0xc6, 0xa7, 0xce, 0x08, 0x33, 0xed, 0x05,
+	0x79, 0x96, 0x88, 0xdb, 0x5c, 0xbc, 0x9b, 0x13, 0x97, 0xe9, 0xd8, 0x89, 0x63, 0x1c, 0x4a, 0x77,
+	0x75, 0xb8, 0xbb, 0x40, 0x90, 0x98, 0xc3, 0xcc, 0xbf, 0x19, 0xf0, 0x60, 0x69, 0xc2, 0x9a, 0xb9,
+	0x23, 0xb7, 0xdd, 0x87, 0x6f, 0xed, 0x08, 0x0a, 0x7b, 0x69, 0xcc, 0xec, 0x65, 0x0c, 0x5b, 0xb7,
+	0xe4, 0x99, 0x5b, 0x36, 0x53, 0x9a, 0xd9, 0x8c, 0x09, 0x2d, 0xec, 0xda, 0x7e, 0xe8, 0xe1, 0x1b,
+	0x7b, 0xe8, 0x53, 0x11, 0xe4, 0x2d, 0xab, 0x81, 0xdd, 0x63, 0x46, 0x3b, 0xf0, 0x29, 0x31, 0xbf,
+	0x32, 0xa0, 0x9d, 0x8f, 0x42, 0x16, 0x47, 0x3c, 0x1f, 0x89, 0x67, 0x99, 0x7f, 0xcb, 0xa5, 0x4b,
+	0xf2, 0xa1, 0xf6, 0xd0, 0x31, 0x40, 0x9c, 0x44, 0x31, 0x4e, 0xa8, 0x8f, 0x99, 0x5e, 0x16, 0xd8,
+	0xaf, 0x2d, 0x0c, 0xec, 0xdd, 0x27, 0xa9, 0xec, 0x51, 0x48, 0x93, 0xa9, 0xa5, 0x4d, 0x1e, 0xbc,
+	0x0f, 0x9d, 0x02, 0x9b, 0xb9, 0x8f, 0xdd, 0x0b, 0x61, 0x00, 0xfb, 0x44, 0xeb, 0xb0, 0x32, 0x71,
+	0x82, 0x31, 0x96, 0x26, 0x88, 0xc1, 0x0f, 0x4a, 0xef, 0x1a, 0x66, 0x15, 0x56, 0x8e, 0x46, 0x31,
+	0x9d, 0xb2, 0x9d, 0x74, 0xce, 0xc6, 0x31, 0x4e, 0x0e, 0x82, 0xc8, 0xbd, 0x3e, 0xba, 0xa1, 0x89,
+	0x83, 0x3e, 0x81, 0x36, 0x4e, 0x1c, 0x32, 0x4e, 0x58, 0x5c, 0x7a, 0x7e, 0x78, 0xc9, 0x75, 0xe6,
+	0x5f, 0xbc, 0xc2, 0x9c, 0xdd, 0x23, 0x31, 0xe1, 0x90, 0xcb, 0x5b, 0x2d, 0xac, 0x0f, 0x07, 0x9f,
+	0x41, 0x2b, 0xc7, 0x67, 0xce, 0x62, 0xf8, 0x40, 0x9e, 0x0a, 0xff, 0x66, 0x69, 0x37, 0x76, 0x12,
+	0x9f, 0x4e, 0x25, 0x8e, 0x91, 0x23, 0x96, 0x6c, 0x64, 0xea, 0xf6, 0x3d, 0xe1, 0xb4, 0x96, 0x55,
+	0x17, 0x94, 0x63, 0x8f, 0x98, 0x1f, 0xc1, 0xfa, 0xc7, 0x18, 0xc7, 0x87, 0x51, 0x18, 0x62, 0x97,
+	0x62, 0xcf, 0xc2, 0x3f, 0x1d, 0x63, 0x42, 0xd9, 0x12, 0x3c, 0xaa, 0xe4, 0x79, 0xb0, 0x6f, 0x96,
+	0x47, 0x2e, 0x93, 0xd8, 0xb5, 0x35, 0xb4, 0x54, 0x63, 0x04, 0x06, 0x39, 0xcc, 0xdf, 0x1a, 0xd0,
+	0x16, 0x77, 0xe9, 0x71, 0xe4, 0xf2, 0x1b, 0xc4, 0x3c, 0xca, 0xd0, 0x93, 0xf4, 0xe8, 0x38, 0x09,
+	0x0a, 0xb0, 0xaa, 0x54, 0x84, 0x55, 0x9b, 0x50, 0xe3, 0xb8, 0x23, 0xb3, 0xb4, 0xca, 0xa0, 0x84,
+	0xef, 0x91, 0x2c, 0x29, 0x7a, 0x82, 0x5d, 0xe1, 0x6c, 0x99, 0x14, 0x3d, 0x2e, 0x92, 0x3d, 0x3c,
+	0x2b, 0xfa, 0xc3, 0x63, 0x9e, 0xc3, 0xda, 0xe3, 0x28, 0xba, 0x1e, 0xc7, 0xc2, 0x3c, 0xb5, 0xc3,
+	0xbc, 0x63, 0x8c, 0xed, 0x32, 0xb3, 0x25, 0x75, 0xcc, 0x6d, 0xf7, 0xdc, 0xfc, 0xa7, 0x01, 0xeb,
+	0x79, 0xb5, 0xf2, 0xad, 0xfc, 0x1c, 0xd6, 0x52, 0xbd, 0x76, 0x20, 0x7d, 0x21, 0x16, 0x68, 0xec,
+	0xbd, 0xa5, 0xdd, 0x81, 0x79, 0xb3, 0x15, 0x38, 0xf3, 0x94, 0x13, 0xad, 0xde, 0xa4, 0x40, 0x21,
+	0x83, 0x1b, 0xe8, 0x16, 0xc5, 0xd8, 0xd9, 0xa4, 0xab, 0x4a, 0x8f, 0xd7, 0xd4, 0x4c, 0xf4, 0x3d,
+	0xa8, 0x67, 0x86, 0x94, 0xb8, 0x21, 0x6b, 0x39, 0x43, 0xe4, 0x5a, 0x99, 0x14, 0xbb, 0xfb, 0x38,
+	0x49, 0xa2, 0x44, 0xa6, 0x2b, 0x31, 0x30, 0xdf, 0x83, 0xda, 0x37, 0x3e, 0x5d, 0xf3, 0x1f, 0x25,
+	0x68, 0xed, 0x13, 0xe2, 0x5f, 0x86, 0xea, 0x08, 0xd6, 0x61, 0x45, 0xbc, 0x5c, 0x02, 0x4a, 0x88,
+	0x01, 0xda, 0x86, 0x86, 0xcc, 0x7a, 0x9a, 0xeb, 0x75, 0xd2, 0xad, 0x09, 0x55, 0x66, 0xc2, 0x8a,
+	0x30, 0x4d, 0x66, 0x42, 0x1d, 0x64, 0xaf, 0x2c, 0x04, 0xd9, 0xab, 0x1a, 0xc8, 0x66, 0x40, 0x89,
+	0x4d, 0x0a, 0x23, 0x0f, 0x4b, 0xf4, 0x5d, 0x63, 0x84, 0xd3, 0xc8, 0xc3, 0x68, 0x0f, 0x36, 0x46,
+	0x78, 0x14, 0x25, 0x53, 0x7b, 0xe4, 0xc4, 0x36, 0xc3, 0xf8, 0x1c, 0x1a, 0x8d, 0x86, 0x32, 0x73,
+	0x23, 0xc1, 0x3d, 0x71, 0xe2, 0x13, 0xe7, 0x86, 0xa1, 0xa3, 0x93, 0x21, 0xda, 0x83, 0x7b, 0x9f,
+	0x26, 0x3e, 0x75, 0x86, 0x01, 0xce, 0xd7, 0x0e, 0x22, 0x93, 0xaf, 0x29, 0xa6, 0x5e, 0x40, 0xe4,
+	0xd0, 0x1a, 0xe4, 0xd1, 0xda, 0xed, 0x09, 0xfe, 0x37, 0x06, 0xb4, 0x95, 0xcf, 0xe5, 0xfd, 0xec,
+	0x42, 0xf9, 0x22, 0xbd, 0x23, 0xec, 0x53, 0x9d, 0x64, 0x69, 0xd1, 0x49, 0xce, 0x94, 0x3f, 0xe9,
+	0xb9, 0x55, 0xf4, 0x73, 0x4b, 0xaf, 0xcc, 0x8a, 0x76, 0x65, 0x98, 0x63, 0x9d, 0x31, 0xbd, 0x52,
+	0x8e, 0x65, 0xdf, 0xe6, 0x25, 0xf4, 0xce, 0xa8, 0x43, 0x7d, 0x42, 0x7d, 0x97, 0xa8, 0xcb, 0x50,
+	0x38, 0x76, 0xe3, 0xb6, 0x63, 0x2f, 0x2d, 0x3a, 0xf6, 0x72, 0x7a, 0xec, 0xe6, 0x5f, 0x0d, 0x40,
+	0xfa, 0x4a, 0xd2, 0x05, 0xdf, 0xc2, 0x52, 0xcc, 0x65, 0x34, 0xa2, 0x0c, 0xaa, 0x32, 0x38, 0x28,
+	0x41, 0x1d, 0xa7, 0xb0, 0xc3, 0x67, 0xc7, 0x38, 0x26, 0xd8, 0x13, 0x5c, 0x81, 0xe8, 0x6a, 0x8c,
+	0xc0, 0x99, 0x79, 0x40, 0xb8, 0x5a, 0x00, 0x84, 0xe6, 0x3e, 0x34, 0xe4, 0xd3, 0xc6, 0x0f, 0xfd,
+	0x76, 0xeb, 0xa5, 0x75, 0xa5, 0xcc, 0x11, 0xdb, 0x00, 0x87, 0x99, 0xf5, 0x73, 0x92, 0xbb, 0xf9,
+	0x33, 0xb8, 0x97, 0x49, 0x3c, 0xf6, 0x09, 0x55, 0xe7, 0xf2, 0x0e, 0x6c, 0xf8, 0xa1, 0x1b, 0x8c,
+	0x3d, 0x6c, 0x87, 0x0c, 0x1c, 0x04, 0x69, 0xd9, 0x65, 0xf0, 0xeb, 0xb6, 0x2e, 0xb9, 0xa7, 0x9c,
+	0xa9, 0xca, 0xaf, 0x37, 0x01, 0xa9, 0x59, 0xd8, 0x4d, 0x67, 0x94, 0xf8, 0x8c, 0xae, 0xe4, 0x1c,
+	0xb9, 0x52, 0xda, 0x7c, 0x0a, 0x1b, 0xc5, 0xc5, 0xe5, 0x51, 0x7d, 0x1f, 0x1a, 0x99, 0xdb, 0x55,
+	0x16, 0xbd, 0xa7, 0x25, 0xaf, 0x6c, 0x9e, 0xa5, 0x4b, 0x9a, 0xff, 0x07, 0xf7, 0x33, 0xd6, 0x23,
+	0xfe, 0x4c, 0x2c, 0x79, 0xdb, 0xcc, 0x01, 0xf4, 0x67, 0xc5, 0x85, 0x0d, 0xe6, 0x9f, 0xcb, 0xd0,
+	0x7c, 0x24, 0xe3, 0x9e, 0x21, 0x24, 0x0d, 0x13, 0x09, 0x60, 0xf2, 0x10, 0x9a, 0xb9, 0x70, 0x16,
+	0xc5, 0x40, 0x63, 0xa2, 0x85, 0xf1, 0xbc, 0x8e, 0x41, 0x99, 0x8b, 0x15, 0x3b, 0x06, 0xaf, 0x43,
+	0xef, 0x22, 0xc1, 0x78, 0xb6, 0xb9, 0x50, 0xb1, 0x3a, 0x8c, 0xa1, 0xcb, 0xee, 0xc2, 0x9a, 0xe3,
+	0x52, 0x7f, 0x52, 0x90, 0x16, 0xf7, 0xab, 0x27, 0x58, 0xba, 0xfc, 0x87, 0xa9, 0xa1, 0x7e, 0x78,
+	0x11, 0x91, 0xfe, 0xea, 0xd7, 0x6f, 0x0e, 0x34, 0x26, 0x29, 0x87, 0xa0, 0x27, 0xd0, 0x56, 0x45,
+	0xa6, 0xd4, 0x54, 0xbd, 0x73, 0x01, 0xdb, 0xc4, 0x19, 0x8b, 0x68, 0xa0, 0x3e, 0xb7, 0x93, 0x9a,
+	0xd8, 0x89, 0x60, 0x2d, 0x4c, 0x8b, 0xf5, 0x42, 0x11, 0xfb, 0x97, 0x12, 0xd4, 0x2c, 0xc7, 0xbd,
+	0xfe, 0x6e, 0x1f, 0xd6, 0x07, 0xd0, 0x49, 0x9f, 0x9f, 0xdc, 0x79, 0xdd, 0xd7, 0xbc, 0xac, 0xdf,
+	0x4b, 0xab, 0xe5, 0x69, 0xa3, 0x85, 0x3e, 0xad, 0x2e, 0xf0, 0xa9, 0xf9, 0xa7, 0x12, 0xb4, 0x1f,
+	0xa5, 0x4f, 0xe2, 0x77, 0xdb, 0x79, 0x7b, 0x00, 0xec, 0x0d, 0xcf, 0xf9, 0x4d, 0xc7, 0x3c, 0xea,
+	0x7a, 0x58, 0xf5, 0x44, 0x7e, 0xdd, 0xdd, 0x5f, 0x5f, 0x95, 0xa0, 0x79, 0x1e, 0xc5, 0x51, 0x10,
+	0x5d, 0x4e, 0xbf, 0xdb, 0xde, 0x3a, 0x82, 0x9e, 0x06, 0x8f, 0x72, 0x4e, 0xdb, 0x2c, 0x5c, 0xb6,
+	0xec, 0x72, 0x58, 0x1d, 0x2f, 0x37, 0xbe, 0xbb, 0x03, 0xd7, 0xa0, 0x27, 0x86, 0xda, 0x7b, 0x63,
+	0xfe, 0xc2, 0x00, 0xa4, 0x53, 0xe5, 0x43, 0xf0, 0x43, 0x68, 0x51, 0xe9, 0x6b, 0x6e, 0x9f, 0x2c,
+	0xaa, 0xf4, 0x58, 0xd0, 0xcf, 0xc2, 0x6a, 0x52, 0x6d, 0x84, 0xfe, 0x1f, 0xd6, 0x67, 0x1a, 0x58,
+	0x0c, 0xab, 0x89, 0x13, 0xe9, 0x15, 0x7a, 0x58, 0x27, 0x43, 0xf3, 0x1d, 0xb8, 0x27, 0xf0, 0xb9,
+	0x7a, 0xa4, 0xd4, 0xe3, 0x31, 0x03, 0xb4, 0x5b, 0x19, 0xd0, 0x36, 0xff, 0x6d, 0xc0, 0x46, 0x71,
+	0x9a, 0xb4, 0x7f, 0xd9, 0x3c, 0xe4, 0x00, 0x92, 0xc9, 0xd4, 0xb3, 0x8b, 0x48, 0xfd, 0xed, 0x99,
+	0x92, 0xa1, 0xa8, 0x7b, 0x57, 0x25, 0xd9, 0xac, 0x6a, 0xe8, 0x92, 0x3c, 0x81, 0x0c, 0x1c, 0xe8,
+	0xcd, 0x88, 0xb1, 0x82, 0x4b, 0xad, 0x2b, 0x6d, 0xaa, 0xca, 0x89, 0xdf, 0xa0, 0x66, 0x30, 0xb7,
+	0xe0, 0xc1, 0x47, 0x98, 0x9e, 0x70, 0x99, 0xc3, 0x28, 0xbc, 0xf0, 0x2f, 0xc7, 0x89, 0x10, 0xca,
+	0x8e, 0xf6, 0xc5, 0x45, 0x12, 0xd2, 0x4d, 0x73, 0xba, 0x84, 0xc6, 0x9d, 0xbb, 0x84, 0xa5, 0x65,
+	0x5d, 0x42, 0xf3, 0x3d, 0xe8, 0xb3, 0x9b, 0x25, 0xad, 0x08, 0x7c, 0x1c, 0xd2, 0x14, 0x84, 0x6e,
+	0x41, 0xc3, 0xe5, 0x14, 0x5b, 0xeb, 0x46, 0x80, 0x20, 0xf1, 0xa7, 0xe5, 0x00, 0x36, 0xe7, 0x4c,
+	0x96, 0xc6, 0xbf, 0x02, 0x6d, 0x5e, 0x20, 0x4b, 0xcb, 0xb1, 0x2a, 0x2b, 0x5b, 0x8c, 0xba, 0xaf,
+	0x88, 0xe6, 0x2f, 0xd9, 0x2d, 0xc1, 0x0e, 0xc1, 0xfb, 0xac, 0x61, 0x7f, 0x1e, 0x5d, 0xe3, 0xb4,
+	0x22, 0x7a, 0x05, 0xda, 0x31, 0xeb, 0x96, 0x45, 0x63, 0x62, 0x53, 0xc6, 0xe0, 0x26, 0x94, 0xad,
+	0x96, 0xa2, 0x72, 0x69, 0x86, 0xae, 0x52, 0x31, 0xd6, 0x44, 0xb0, 0xa9, 0x3f, 0x12, 0x6d, 0x8a,
+	0xb2, 0xd5, 0x55, 0x9c, 0xc7, 0x91, 0x7b, 0x7d, 0xee, 0x8b, 0xba, 0x9d, 0x0b, 0x71, 0xd0, 0x23,
+	0x20, 0x6b, 0x8d, 0x11, 0x58, 0x77, 0xcc, 0x3c, 0x81, 0xfb, 0x33, 0xb6, 0xc8, 0xed, 0xac, 0xc3,
+	0x8a, 0x6e, 0x83, 0x18, 0xa0, 0x17, 0x00, 0xc4, 0x92, 0xc4, 0x0e, 0x89, 0x5c, 0x93, 0xab, 0x3b,
+	0x27, 0xa7, 0xc4, 0xfc, 0x95, 0x01, 0x7d, 0x0b, 0x07, 0xff, 0x2b, 0xbb, 0x7b, 0x1e, 0x36, 0xe7,
+	0x58, 0x23, 0x71, 0xdd, 0xef, 0x0c, 0x58, 0x7b, 0xe6, 0xb8, 0xe3, 0xf1, 0x28, 0x1f, 0xe2, 0x6f,
+	0x40, 0xef, 0xd2, 0x49, 0x86, 0xac, 0x53, 0x48, 0xaf, 0x12, 0x4c, 0xae, 0xa2, 0x40, 0x84, 0x87,
+	0x61, 0x75, 0x25, 0xe3, 0x5c, 0xd1, 0xf3, 0x71, 0x5d, 0x2a, 0xc4, 0xf5, 0x76, 0x1e, 0xbd, 0x96,
+	0xf9, 0x6d, 0xd0, 0x49, 0xe8, 0x3e, 0x54, 0xbd, 0x64, 0x6a, 0x27, 0xe3, 0x90, 0xe7, 0xf2, 0x9a,
+	0xb5, 0xea, 0x25, 0x53, 0x6b, 0x1c, 0x9a, 0x9f, 0xc0, 0x7a, 0xde, 0xb6, 0x14, 0x10, 0x57, 0x33,
+	0xfc, 0xcd, 0xa2, 0xf2, 0x81, 0x8e, 0xb9, 0xf2, 0x33, 0xc6, 0x01, 0x4d, 0x7f, 0xd4, 0x31, 0xff,
+	0xc5, 0xf2, 0xea, 0x0c, 0x7f, 0x79, 0x5e, 0xba, 0xad, 0x0c, 0x7a, 0x09, 0x5a, 0xca, 0x53, 0x3c,
+	0x8e, 0xb9, 0xff, 0x0d, 0xab, 0x29, 0x89, 0x16, 0xa3, 0xe9, 0x42, 0xac, 0x59, 0x4d, 0xe4, 0xa3,
+	0xa5, 0x84, 0x58, 0x9f, 0x9a, 0xa0, 0x01, 0xd4, 0x26, 0xdc, 0x38, 0xec, 0xf1, 0x67, 0xaa, 0x66,
+	0xa5, 0x63, 0x96, 0x13, 0x12, 0xec, 0x06, 0x8e, 0x3f, 0x92, 0xfd, 0x6e, 0x22, 0x6b, 0xa4, 0x76,
+	0x4a, 0x16, 0x4a, 0xd2, 0x0a, 0xb4, 0xaa, 0x37, 0x2d, 0x7e, 0x6d, 0x40, 0xff, 0x6c, 0x3c, 0x24,
+	0x6e, 0xe2, 0x0f, 0xb1, 0x7a, 0x23, 0x66, 0x03, 0x5e, 0x2b, 0x09, 0x64, 0xc0, 0xf3, 0xee, 0xf1,
+	0x2b, 0xd0, 0x26, 0x7e, 0xe8, 0x62, 0x9b, 0xb0, 0x19, 0xa1, 0xab, 0x5a, 0xfd, 0x2d, 0x4e, 0x3d,
+	0x93, 0x44, 0x76, 0x67, 0xae, 0xd4, 0xcf, 0x26, 0x69, 0x1e, 0x12, 0xad, 0xcf, 0x6e, 0xca, 0x50,
+	0x19, 0xe8, 0xef, 0x25, 0x68, 0x29, 0x43, 0x8e, 0x26, 0x38, 0xa4, 0x6c, 0xfb, 0xa9, 0x7e, 0xd1,
+	0x0c, 0x49, 0xc7, 0x68, 0x0d, 0x56, 0xf4, 0x58, 0xab, 0x50, 0x72, 0x4a, 0xd2, 0x7e, 0x69, 0x59,
+	0xeb, 0x97, 0x16, 0x9a, 0x1c, 0x95, 0x85, 0x4d, 0x8e, 0x15, 0xad, 0xc9, 0x21, 0x8b, 0xff, 0xd5,
+	0x45, 0xc5, 0x7f, 0xb5, 0x58, 0xfc, 0x1f, 0x64, 0x17, 0xb0, 0x76, 0xc7, 0x5f, 0xf2, 0xaa, 0x93,
+	0x79, 0x3f, 0xce, 0xd5, 0xff, 0x8b, 0x1f, 0xe7, 0xb2, 0x9e, 0x1f, 0xe8, 0x3d, 0xbf, 0xbd, 0x3f,
+	0x00, 0x54, 0xcf, 0xb0, 0xf3, 0x05, 0xc6, 0xac, 0x6d, 0xdc, 0x3a, 0xc3, 0xa1, 0x97, 0xfd, 0x00,
+	0xbc, 0xae, 0x2d, 0x95, 0x52, 0x07, 0x2f, 0xcc, 0xa3, 0xa6, 0xb9, 0xe2, 0xb9, 0x1d, 0xe3, 0x2d,
+	0x03, 0x3d, 0x85, 0x56, 0xae, 0x5b, 0x8a, 0xb6, 0xb4, 0x49, 0xf3, 0xfa, 0xa8, 0x83, 0xcd, 0x99,
+	0x6d, 0xa9, 0x97, 0x33, 0x55, 0xd9, 0xd4, 0x1b, 0x81, 0xe8, 0xc5, 0x85, 0x1d, 0x42, 0xa1, 0x70,
+	0xeb, 0x96, 0x0e, 0xa2, 0xf9, 0x1c, 0xfa, 0x00, 0x56, 0x45, 0xcf, 0x07, 0xf5, 0x35, 0xe1, 0x5c,
+	0xeb, 0x6d, 0xb0, 0x39, 0x87, 0x93, 0x2a, 0xf8, 0x18, 0x20, 0xeb, 0x9a, 0xa0, 0x17, 0x72, 0x2d,
+	0xf6, 0x42, 0xdb, 0x66, 0xf0, 0x60, 0x01, 0x37, 0x55, 0xf6, 0x29, 0xb4, 0xf3, 0xb5, 0x3d, 0xda,
+	0x9e, 0x5b, 0xbe, 0x6b, 0x18, 0x70, 0xf0, 0x70, 0x89, 0x44, 0xaa, 0xf8, 0x27, 0xd0, 0x2d, 0x96,
+	0xec, 0xc8, 0x9c, 0x3b, 0x31, 0x57, 0xfe, 0x0f, 0x5e, 0x5a, 0x2a, 0xa3, 0x3b, 0x21, 0x83, 0xa1,
+	0x39, 0x27, 0xcc, 0x60, 0xd6, 0xc1, 0x83, 0x05, 0x5c, 0xdd, 0x09, 0x79, 0xec, 0x96, 0x73, 0xc2,
+	0x5c, 0xa4, 0x39, 0x78, 0xb8, 0x44, 0x22, 0x55, 0x1c, 0xc1, 0xc6, 0x7c, 0x44, 0x85, 0xf4, 0xb0,
+	0x5c, 0x0a, 0xcb, 0x06, 0xaf, 0x7d, 0x0d, 0xc9, 0x74, 0xc1, 0xcf, 0xa1, 0x37, 0x03, 0x80, 0x90,
+	0xee, 0xd2, 0x45, 0xd8, 0x6a, 0xf0, 0xf2, 0x72, 0xa1, 0x74, 0x85, 0xcf, 0xa0, 0x53, 0x40, 0x24,
+	0x28, 0xe7, 0x8a, 0xb9, 0xd8, 0x62, 0x60, 0x2e, 0x13, 0xd1, 0xad, 0x9f, 0xc1, 0x03, 0x39, 0xeb,
+	0x17, 0x61, 0x97, 0xc1, 0xcb, 0xcb, 0x85, 0xd2, 0x15, 0x9e, 0x42, 0x53, 0x7f, 0x65, 0x73, 0xf1,
+	0x3c, 0x07, 0x6c, 0x0c, 0xb6, 0x16, 0xf2, 0x53, 0x95, 0xcf, 0xa0, 0x37, 0xf3, 0x7e, 0xe5, 0x8c,
+	0x5e, 0xf4, 0xba, 0x0d, 0xfa, 0x73, 0xaa, 0x23, 0xfe, 0xe0, 0x98, 0xcf, 0xbd, 0x65, 0x0c, 0x57,
+	0xf9, 0x1f, 0x65, 0xde, 0xfe, 0xcf, 0x00, 0x52, 0x64, 0x3d, 0x58, 0x38, 0x23, 0x00, 0x00,
 }
@@ -14,6 +14,8 @@ It has these top-level messages:
 	DeleteResult
 	FileGetRequest
 	FileGetResponse
+	ReadNeedleBlobRequest
+	ReadNeedleBlobResponse
 	Empty
 	VacuumVolumeCheckRequest
 	VacuumVolumeCheckResponse
@@ -39,6 +41,10 @@ It has these top-level messages:
 	VolumeDeleteResponse
 	VolumeMarkReadonlyRequest
 	VolumeMarkReadonlyResponse
+	VolumeMarkWritableRequest
+	VolumeMarkWritableResponse
+	VolumeServerMarkReadonlyRequest
+	VolumeServerMarkReadonlyResponse
 	VolumeConfigureRequest
 	VolumeConfigureResponse
 	VolumeCopyRequest
@@ -302,7 +308,7 @@ type Empty struct {
 func (m *Empty) Reset()                    { *m = Empty{} }
 func (m *Empty) String() string            { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 type VacuumVolumeCheckRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -311,7 +317,7 @@ type VacuumVolumeCheckRequest struct {
 func (m *VacuumVolumeCheckRequest) Reset()                    { *m = VacuumVolumeCheckRequest{} }
 func (m *VacuumVolumeCheckRequest) String() string            { return proto.CompactTextString(m) }
 func (*VacuumVolumeCheckRequest) ProtoMessage()               {}
-func (*VacuumVolumeCheckRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*VacuumVolumeCheckRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *VacuumVolumeCheckRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -322,12 +328,13 @@ func (m *VacuumVolumeCheckRequest) GetVolumeId() uint32 {
 
 type VacuumVolumeCheckResponse struct {
 	GarbageRatio float64 `protobuf:"fixed64,1,opt,name=garbage_ratio,json=garbageRatio" json:"garbage_ratio,omitempty"`
+	GarbageBytes uint64  `protobuf:"varint,2,opt,name=garbage_bytes,json=garbageBytes" json:"garbage_bytes,omitempty"`
 }
 
 func (m *VacuumVolumeCheckResponse) Reset()                    { *m = VacuumVolumeCheckResponse{} }
 func (m *VacuumVolumeCheckResponse) String() string            { return proto.CompactTextString(m) }
 func (*VacuumVolumeCheckResponse) ProtoMessage()               {}
-func (*VacuumVolumeCheckResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*VacuumVolumeCheckResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *VacuumVolumeCheckResponse) GetGarbageRatio() float64 {
 	if m != nil {
@@ -336,6 +343,13 @@ func (m *VacuumVolumeCheckResponse) GetGarbageRatio() float64 {
 	return 0
 }
 
+func (m *VacuumVolumeCheckResponse) GetGarbageBytes() uint64 {
+	if m != nil {
+		return m.GarbageBytes
+	}
+	return 0
+}
+
 type VacuumVolumeCompactRequest struct {
 	VolumeId    uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
 	Preallocate int64  `protobuf:"varint,2,opt,name=preallocate" json:"preallocate,omitempty"`
@@ -344,7 +358,7 @@ type VacuumVolumeCompactRequest struct {
 func (m *VacuumVolumeCompactRequest) Reset()                    { *m = VacuumVolumeCompactRequest{} }
 func (m *VacuumVolumeCompactRequest) String() string            { return proto.CompactTextString(m) }
 func (*VacuumVolumeCompactRequest) ProtoMessage()               {}
-func (*VacuumVolumeCompactRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*VacuumVolumeCompactRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *VacuumVolumeCompactRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -366,7 +380,7 @@ type VacuumVolumeCompactResponse struct {
 func (m *VacuumVolumeCompactResponse) Reset()                    { *m = VacuumVolumeCompactResponse{} }
 func (m *VacuumVolumeCompactResponse) String() string            { return proto.CompactTextString(m) }
 func (*VacuumVolumeCompactResponse) ProtoMessage()               {}
-func (*VacuumVolumeCompactResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*VacuumVolumeCompactResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 type VacuumVolumeCommitRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -375,7 +389,7 @@ type VacuumVolumeCommitRequest struct {
 func (m *VacuumVolumeCommitRequest) Reset()                    { *m = VacuumVolumeCommitRequest{} }
 func (m *VacuumVolumeCommitRequest) String() string            { return proto.CompactTextString(m) }
 func (*VacuumVolumeCommitRequest) ProtoMessage()               {}
-func (*VacuumVolumeCommitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*VacuumVolumeCommitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *VacuumVolumeCommitRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -385,13 +399,14 @@ func (m *VacuumVolumeCommitRequest) GetVolumeId() uint32 {
 }
 
 type VacuumVolumeCommitResponse struct {
-	IsReadOnly bool `protobuf:"varint,1,opt,name=is_read_only,json=isReadOnly" json:"is_read_only,omitempty"`
+	IsReadOnly     bool   `protobuf:"varint,1,opt,name=is_read_only,json=isReadOnly" json:"is_read_only,omitempty"`
+	ReclaimedBytes uint64 `protobuf:"varint,2,opt,name=reclaimed_bytes,json=reclaimedBytes" json:"reclaimed_bytes,omitempty"`
 }
 
 func (m *VacuumVolumeCommitResponse) Reset()                    { *m = VacuumVolumeCommitResponse{} }
 func (m *VacuumVolumeCommitResponse) String() string            { return proto.CompactTextString(m) }
 func (*VacuumVolumeCommitResponse) ProtoMessage()               {}
-func (*VacuumVolumeCommitResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*VacuumVolumeCommitResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *VacuumVolumeCommitResponse) GetIsReadOnly() bool {
 	if m != nil {
@@ -400,6 +415,13 @@ func (m *VacuumVolumeCommitResponse) GetIsReadOnly() bool {
 	return false
 }
 
+func (m *VacuumVolumeCommitResponse) GetReclaimedBytes() uint64 {
+	if m != nil {
+		return m.ReclaimedBytes
+	}
+	return 0
+}
+
 type VacuumVolumeCleanupRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
 }
@@ -407,7 +429,7 @@ type VacuumVolumeCleanupRequest struct {
 func (m *VacuumVolumeCleanupRequest) Reset()                    { *m = VacuumVolumeCleanupRequest{} }
 func (m *VacuumVolumeCleanupRequest) String() string            { return proto.CompactTextString(m) }
 func (*VacuumVolumeCleanupRequest) ProtoMessage()               {}
-func (*VacuumVolumeCleanupRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*VacuumVolumeCleanupRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *VacuumVolumeCleanupRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -422,7 +444,7 @@ type VacuumVolumeCleanupResponse struct {
 func (m *VacuumVolumeCleanupResponse) Reset()                    { *m = VacuumVolumeCleanupResponse{} }
 func (m *VacuumVolumeCleanupResponse) String() string            { return proto.CompactTextString(m) }
 func (*VacuumVolumeCleanupResponse) ProtoMessage()               {}
-func (*VacuumVolumeCleanupResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*VacuumVolumeCleanupResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 type DeleteCollectionRequest struct {
 	Collection string `protobuf:"bytes,1,opt,name=collection" json:"collection,omitempty"`
@@ -431,7 +453,7 @@ type DeleteCollectionRequest struct {
 func (m *DeleteCollectionRequest) Reset()                    { *m = DeleteCollectionRequest{} }
 func (m *DeleteCollectionRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeleteCollectionRequest) ProtoMessage()               {}
-func (*DeleteCollectionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*DeleteCollectionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *DeleteCollectionRequest) GetCollection() string {
 	if m != nil {
@@ -446,7 +468,7 @@ type DeleteCollectionResponse struct {
 func (m *DeleteCollectionResponse) Reset()                    { *m = DeleteCollectionResponse{} }
 func (m *DeleteCollectionResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeleteCollectionResponse) ProtoMessage()               {}
-func (*DeleteCollectionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*DeleteCollectionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 type AllocateVolumeRequest struct {
 	VolumeId           uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -455,12 +477,13 @@ type AllocateVolumeRequest struct {
 	Replication        string `protobuf:"bytes,4,opt,name=replication" json:"replication,omitempty"`
 	Ttl                string `protobuf:"bytes,5,opt,name=ttl" json:"ttl,omitempty"`
 	MemoryMapMaxSizeMb uint32 `protobuf:"varint,6,opt,name=memory_map_max_size_mb,json=memoryMapMaxSizeMb" json:"memory_map_max_size_mb,omitempty"`
+	AppendOnly         bool   `protobuf:"varint,7,opt,name=append_only,json=appendOnly" json:"append_only,omitempty"`
 }
 
 func (m *AllocateVolumeRequest) Reset()                    { *m = AllocateVolumeRequest{} }
 func (m *AllocateVolumeRequest) String() string            { return proto.CompactTextString(m) }
 func (*AllocateVolumeRequest) ProtoMessage()               {}
-func (*AllocateVolumeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*AllocateVolumeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *AllocateVolumeRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -504,13 +527,20 @@ func (m *AllocateVolumeRequest) GetMemoryMapMaxSizeMb() uint32 {
 	return 0
 }
 
+func (m *AllocateVolumeRequest) GetAppendOnly() bool {
+	if m != nil {
+		return m.AppendOnly
+	}
+	return false
+}
+
 type AllocateVolumeResponse struct {
 }
 
 func (m *AllocateVolumeResponse) Reset()                    { *m = AllocateVolumeResponse{} }
 func (m *AllocateVolumeResponse) String() string            { return proto.CompactTextString(m) }
 func (*AllocateVolumeResponse) ProtoMessage()               {}
-func (*AllocateVolumeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*AllocateVolumeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 type VolumeSyncStatusRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -519,7 +549,7 @@ type VolumeSyncStatusRequest struct {
 func (m *VolumeSyncStatusRequest) Reset()                    { *m = VolumeSyncStatusRequest{} }
 func (m *VolumeSyncStatusRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeSyncStatusRequest) ProtoMessage()               {}
-func (*VolumeSyncStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*VolumeSyncStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *VolumeSyncStatusRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -541,7 +571,7 @@ type VolumeSyncStatusResponse struct {
 func (m *VolumeSyncStatusResponse) Reset()                    { *m = VolumeSyncStatusResponse{} }
 func (m *VolumeSyncStatusResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeSyncStatusResponse) ProtoMessage()               {}
-func (*VolumeSyncStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (*VolumeSyncStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *VolumeSyncStatusResponse) GetVolumeId() uint32 {
 	if m != nil {
@@ -600,7 +630,7 @@ type VolumeIncrementalCopyRequest struct {
 func (m *VolumeIncrementalCopyRequest) Reset()                    { *m = VolumeIncrementalCopyRequest{} }
 func (m *VolumeIncrementalCopyRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeIncrementalCopyRequest) ProtoMessage()               {}
-func (*VolumeIncrementalCopyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (*VolumeIncrementalCopyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 func (m *VolumeIncrementalCopyRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -623,7 +653,7 @@ type VolumeIncrementalCopyResponse struct {
 func (m *VolumeIncrementalCopyResponse) Reset()                    { *m = VolumeIncrementalCopyResponse{} }
 func (m *VolumeIncrementalCopyResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeIncrementalCopyResponse) ProtoMessage()               {}
-func (*VolumeIncrementalCopyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*VolumeIncrementalCopyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *VolumeIncrementalCopyResponse) GetFileContent() []byte {
 	if m != nil {
@@ -639,7 +669,7 @@ type VolumeMountRequest struct {
 func (m *VolumeMountRequest) Reset()                    { *m = VolumeMountRequest{} }
 func (m *VolumeMountRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeMountRequest) ProtoMessage()               {}
-func (*VolumeMountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*VolumeMountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *VolumeMountRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -654,7 +684,7 @@ type VolumeMountResponse struct {
 func (m *VolumeMountResponse) Reset()                    { *m = VolumeMountResponse{} }
 func (m *VolumeMountResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeMountResponse) ProtoMessage()               {}
-func (*VolumeMountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (*VolumeMountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 type VolumeUnmountRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -663,7 +693,7 @@ type VolumeUnmountRequest struct {
 func (m *VolumeUnmountRequest) Reset()                    { *m = VolumeUnmountRequest{} }
 func (m *VolumeUnmountRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeUnmountRequest) ProtoMessage()               {}
-func (*VolumeUnmountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+func (*VolumeUnmountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 func (m *VolumeUnmountRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -678,7 +708,7 @@ type VolumeUnmountResponse struct {
 func (m *VolumeUnmountResponse) Reset()                    { *m = VolumeUnmountResponse{} }
 func (m *VolumeUnmountResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeUnmountResponse) ProtoMessage()               {}
-func (*VolumeUnmountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+func (*VolumeUnmountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 type VolumeDeleteRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -687,7 +717,7 @@ type VolumeDeleteRequest struct {
 func (m *VolumeDeleteRequest) Reset()                    { *m = VolumeDeleteRequest{} }
 func (m *VolumeDeleteRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeDeleteRequest) ProtoMessage()               {}
-func (*VolumeDeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+func (*VolumeDeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
 func (m *VolumeDeleteRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -702,7 +732,7 @@ type VolumeDeleteResponse struct {
 func (m *VolumeDeleteResponse) Reset()                    { *m = VolumeDeleteResponse{} }
 func (m *VolumeDeleteResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeDeleteResponse) ProtoMessage()               {}
-func (*VolumeDeleteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (*VolumeDeleteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
 
 type VolumeMarkReadonlyRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -711,7 +741,7 @@ type VolumeMarkReadonlyRequest struct {
 func (m *VolumeMarkReadonlyRequest) Reset()                    { *m = VolumeMarkReadonlyRequest{} }
 func (m *VolumeMarkReadonlyRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeMarkReadonlyRequest) ProtoMessage()               {}
-func (*VolumeMarkReadonlyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+func (*VolumeMarkReadonlyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
 
 func (m *VolumeMarkReadonlyRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -726,7 +756,7 @@ type VolumeMarkReadonlyResponse struct {
 func (m *VolumeMarkReadonlyResponse) Reset()                    { *m = VolumeMarkReadonlyResponse{} }
 func (m *VolumeMarkReadonlyResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeMarkReadonlyResponse) ProtoMessage()               {}
-func (*VolumeMarkReadonlyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+func (*VolumeMarkReadonlyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
 
 type VolumeConfigureRequest struct {
 	VolumeId    uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -736,7 +766,7 @@ type VolumeConfigureRequest struct {
 func (m *VolumeConfigureRequest) Reset()                    { *m = VolumeConfigureRequest{} }
 func (m *VolumeConfigureRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeConfigureRequest) ProtoMessage()               {}
-func (*VolumeConfigureRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+func (*VolumeConfigureRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
 
 func (m *VolumeConfigureRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -759,7 +789,7 @@ type VolumeConfigureResponse struct {
 func (m *VolumeConfigureResponse) Reset()                    { *m = VolumeConfigureResponse{} }
 func (m *VolumeConfigureResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeConfigureResponse) ProtoMessage()               {}
-func (*VolumeConfigureResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+func (*VolumeConfigureResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
 
 func (m *VolumeConfigureResponse) GetError() string {
 	if m != nil {
@@ -769,17 +799,18 @@ func (m *VolumeConfigureResponse) GetError() string {
 }
 
 type VolumeCopyRequest struct {
-	VolumeId       uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
-	Collection     string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
-	Replication    string `protobuf:"bytes,3,opt,name=replication" json:"replication,omitempty"`
-	Ttl            string `protobuf:"bytes,4,opt,name=ttl" json:"ttl,omitempty"`
-	SourceDataNode string `protobuf:"bytes,5,opt,name=source_data_node,json=sourceDataNode" json:"source_data_node,omitempty"`
+	VolumeId        uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
+	Collection      string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	Replication     string `protobuf:"bytes,3,opt,name=replication" json:"replication,omitempty"`
+	Ttl             string `protobuf:"bytes,4,opt,name=ttl" json:"ttl,omitempty"`
+	SourceDataNode  string `protobuf:"bytes,5,opt,name=source_data_node,json=sourceDataNode" json:"source_data_node,omitempty"`
+	IoBytePerSecond int64  `protobuf:"varint,6,opt,name=io_byte_per_second,json=ioBytePerSecond" json:"io_byte_per_second,omitempty"`
 }
 
 func (m *VolumeCopyRequest) Reset()                    { *m = VolumeCopyRequest{} }
 func (m *VolumeCopyRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeCopyRequest) ProtoMessage()               {}
-func (*VolumeCopyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+func (*VolumeCopyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
 
 func (m *VolumeCopyRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -816,6 +847,13 @@ func (m *VolumeCopyRequest) GetSourceDataNode() string {
 	return ""
 }
 
+func (m *VolumeCopyRequest) GetIoBytePerSecond() int64 {
+	if m != nil {
+		return m.IoBytePerSecond
+	}
+	return 0
+}
+
 type VolumeCopyResponse struct {
 	LastAppendAtNs uint64 `protobuf:"varint,1,opt,name=last_append_at_ns,json=lastAppendAtNs" json:"last_append_at_ns,omitempty"`
 }
@@ -823,7 +861,7 @@ type VolumeCopyResponse struct {
 func (m *VolumeCopyResponse) Reset()                    { *m = VolumeCopyResponse{} }
 func (m *VolumeCopyResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeCopyResponse) ProtoMessage()               {}
-func (*VolumeCopyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+func (*VolumeCopyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
 
 func (m *VolumeCopyResponse) GetLastAppendAtNs() uint64 {
 	if m != nil {
@@ -845,7 +883,7 @@ type CopyFileRequest struct {
 func (m *CopyFileRequest) Reset()                    { *m = CopyFileRequest{} }
 func (m *CopyFileRequest) String() string            { return proto.CompactTextString(m) }
 func (*CopyFileRequest) ProtoMessage()               {}
-func (*CopyFileRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+func (*CopyFileRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
 
 func (m *CopyFileRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -903,7 +941,7 @@ type CopyFileResponse struct {
 func (m *CopyFileResponse) Reset()                    { *m = CopyFileResponse{} }
 func (m *CopyFileResponse) String() string            { return proto.CompactTextString(m) }
 func (*CopyFileResponse) ProtoMessage()               {}
-func (*CopyFileResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+func (*CopyFileResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
 
 func (m *CopyFileResponse) GetFileContent() []byte {
 	if m != nil {
@@ -921,7 +959,7 @@ type VolumeTailSenderRequest struct {
 func (m *VolumeTailSenderRequest) Reset()                    { *m = VolumeTailSenderRequest{} }
 func (m *VolumeTailSenderRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeTailSenderRequest) ProtoMessage()               {}
-func (*VolumeTailSenderRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+func (*VolumeTailSenderRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
 
 func (m *VolumeTailSenderRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -953,7 +991,7 @@ type VolumeTailSenderResponse struct {
 func (m *VolumeTailSenderResponse) Reset()                    { *m = VolumeTailSenderResponse{} }
 func (m *VolumeTailSenderResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeTailSenderResponse) ProtoMessage()               {}
-func (*VolumeTailSenderResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+func (*VolumeTailSenderResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
 
 func (m *VolumeTailSenderResponse) GetNeedleHeader() []byte {
 	if m != nil {
@@ -986,7 +1024,7 @@ type VolumeTailReceiverRequest struct {
 func (m *VolumeTailReceiverRequest) Reset()                    { *m = VolumeTailReceiverRequest{} }
 func (m *VolumeTailReceiverRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeTailReceiverRequest) ProtoMessage()               {}
-func (*VolumeTailReceiverRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+func (*VolumeTailReceiverRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
 
 func (m *VolumeTailReceiverRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1022,7 +1060,7 @@ type VolumeTailReceiverResponse struct {
 func (m *VolumeTailReceiverResponse) Reset()                    { *m = VolumeTailReceiverResponse{} }
 func (m *VolumeTailReceiverResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeTailReceiverResponse) ProtoMessage()               {}
-func (*VolumeTailReceiverResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+func (*VolumeTailReceiverResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
 
 type VolumeEcShardsGenerateRequest struct {
 	VolumeId   uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -1032,7 +1070,7 @@ type VolumeEcShardsGenerateRequest struct {
 func (m *VolumeEcShardsGenerateRequest) Reset()                    { *m = VolumeEcShardsGenerateRequest{} }
 func (m *VolumeEcShardsGenerateRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsGenerateRequest) ProtoMessage()               {}
-func (*VolumeEcShardsGenerateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+func (*VolumeEcShardsGenerateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
 
 func (m *VolumeEcShardsGenerateRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1054,7 +1092,7 @@ type VolumeEcShardsGenerateResponse struct {
 func (m *VolumeEcShardsGenerateResponse) Reset()                    { *m = VolumeEcShardsGenerateResponse{} }
 func (m *VolumeEcShardsGenerateResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsGenerateResponse) ProtoMessage()               {}
-func (*VolumeEcShardsGenerateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+func (*VolumeEcShardsGenerateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
 
 type VolumeEcShardsRebuildRequest struct {
 	VolumeId   uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -1064,7 +1102,7 @@ type VolumeEcShardsRebuildRequest struct {
 func (m *VolumeEcShardsRebuildRequest) Reset()                    { *m = VolumeEcShardsRebuildRequest{} }
 func (m *VolumeEcShardsRebuildRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsRebuildRequest) ProtoMessage()               {}
-func (*VolumeEcShardsRebuildRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+func (*VolumeEcShardsRebuildRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
 
 func (m *VolumeEcShardsRebuildRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1087,7 +1125,7 @@ type VolumeEcShardsRebuildResponse struct {
 func (m *VolumeEcShardsRebuildResponse) Reset()                    { *m = VolumeEcShardsRebuildResponse{} }
 func (m *VolumeEcShardsRebuildResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsRebuildResponse) ProtoMessage()               {}
-func (*VolumeEcShardsRebuildResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+func (*VolumeEcShardsRebuildResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
 
 func (m *VolumeEcShardsRebuildResponse) GetRebuiltShardIds() []uint32 {
 	if m != nil {
@@ -1109,7 +1147,7 @@ type VolumeEcShardsCopyRequest struct {
 func (m *VolumeEcShardsCopyRequest) Reset()                    { *m = VolumeEcShardsCopyRequest{} }
 func (m *VolumeEcShardsCopyRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsCopyRequest) ProtoMessage()               {}
-func (*VolumeEcShardsCopyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+func (*VolumeEcShardsCopyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
 
 func (m *VolumeEcShardsCopyRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1166,7 +1204,7 @@ type VolumeEcShardsCopyResponse struct {
 func (m *VolumeEcShardsCopyResponse) Reset()                    { *m = VolumeEcShardsCopyResponse{} }
 func (m *VolumeEcShardsCopyResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsCopyResponse) ProtoMessage()               {}
-func (*VolumeEcShardsCopyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+func (*VolumeEcShardsCopyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
 
 type VolumeEcShardsDeleteRequest struct {
 	VolumeId   uint32   `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -1177,7 +1215,7 @@ type VolumeEcShardsDeleteRequest struct {
 func (m *VolumeEcShardsDeleteRequest) Reset()                    { *m = VolumeEcShardsDeleteRequest{} }
 func (m *VolumeEcShardsDeleteRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsDeleteRequest) ProtoMessage()               {}
-func (*VolumeEcShardsDeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+func (*VolumeEcShardsDeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
 
 func (m *VolumeEcShardsDeleteRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1206,7 +1244,7 @@ type VolumeEcShardsDeleteResponse struct {
 func (m *VolumeEcShardsDeleteResponse) Reset()                    { *m = VolumeEcShardsDeleteResponse{} }
 func (m *VolumeEcShardsDeleteResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsDeleteResponse) ProtoMessage()               {}
-func (*VolumeEcShardsDeleteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+func (*VolumeEcShardsDeleteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
 
 type VolumeEcShardsMountRequest struct {
 	VolumeId   uint32   `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -1217,7 +1255,7 @@ type VolumeEcShardsMountRequest struct {
 func (m *VolumeEcShardsMountRequest) Reset()                    { *m = VolumeEcShardsMountRequest{} }
 func (m *VolumeEcShardsMountRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsMountRequest) ProtoMessage()               {}
-func (*VolumeEcShardsMountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+func (*VolumeEcShardsMountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
 
 func (m *VolumeEcShardsMountRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1246,7 +1284,7 @@ type VolumeEcShardsMountResponse struct {
 func (m *VolumeEcShardsMountResponse) Reset()                    { *m = VolumeEcShardsMountResponse{} }
 func (m *VolumeEcShardsMountResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsMountResponse) ProtoMessage()               {}
-func (*VolumeEcShardsMountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+func (*VolumeEcShardsMountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
 
 type VolumeEcShardsUnmountRequest struct {
 	VolumeId uint32   `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -1256,7 +1294,7 @@ type VolumeEcShardsUnmountRequest struct {
 func (m *VolumeEcShardsUnmountRequest) Reset()                    { *m = VolumeEcShardsUnmountRequest{} }
 func (m *VolumeEcShardsUnmountRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsUnmountRequest) ProtoMessage()               {}
-func (*VolumeEcShardsUnmountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+func (*VolumeEcShardsUnmountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
 
 func (m *VolumeEcShardsUnmountRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1278,7 +1316,7 @@ type VolumeEcShardsUnmountResponse struct {
 func (m *VolumeEcShardsUnmountResponse) Reset()                    { *m = VolumeEcShardsUnmountResponse{} }
 func (m *VolumeEcShardsUnmountResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsUnmountResponse) ProtoMessage()               {}
-func (*VolumeEcShardsUnmountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+func (*VolumeEcShardsUnmountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
 
 type VolumeEcShardReadRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -1291,7 +1329,7 @@ type VolumeEcShardReadRequest struct {
 func (m *VolumeEcShardReadRequest) Reset()                    { *m = VolumeEcShardReadRequest{} }
 func (m *VolumeEcShardReadRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardReadRequest) ProtoMessage()               {}
-func (*VolumeEcShardReadRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+func (*VolumeEcShardReadRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
 
 func (m *VolumeEcShardReadRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1336,7 +1374,7 @@ type VolumeEcShardReadResponse struct {
 func (m *VolumeEcShardReadResponse) Reset()                    { *m = VolumeEcShardReadResponse{} }
 func (m *VolumeEcShardReadResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardReadResponse) ProtoMessage()               {}
-func (*VolumeEcShardReadResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+func (*VolumeEcShardReadResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
 
 func (m *VolumeEcShardReadResponse) GetData() []byte {
 	if m != nil {
@@ -1362,7 +1400,7 @@ type VolumeEcBlobDeleteRequest struct {
 func (m *VolumeEcBlobDeleteRequest) Reset()                    { *m = VolumeEcBlobDeleteRequest{} }
 func (m *VolumeEcBlobDeleteRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcBlobDeleteRequest) ProtoMessage()               {}
-func (*VolumeEcBlobDeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+func (*VolumeEcBlobDeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
 
 func (m *VolumeEcBlobDeleteRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1398,7 +1436,7 @@ type VolumeEcBlobDeleteResponse struct {
 func (m *VolumeEcBlobDeleteResponse) Reset()                    { *m = VolumeEcBlobDeleteResponse{} }
 func (m *VolumeEcBlobDeleteResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcBlobDeleteResponse) ProtoMessage()               {}
-func (*VolumeEcBlobDeleteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+func (*VolumeEcBlobDeleteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
 
 type VolumeEcShardsToVolumeRequest struct {
 	VolumeId   uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -1408,7 +1446,7 @@ type VolumeEcShardsToVolumeRequest struct {
 func (m *VolumeEcShardsToVolumeRequest) Reset()                    { *m = VolumeEcShardsToVolumeRequest{} }
 func (m *VolumeEcShardsToVolumeRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsToVolumeRequest) ProtoMessage()               {}
-func (*VolumeEcShardsToVolumeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+func (*VolumeEcShardsToVolumeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
 
 func (m *VolumeEcShardsToVolumeRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1430,7 +1468,7 @@ type VolumeEcShardsToVolumeResponse struct {
 func (m *VolumeEcShardsToVolumeResponse) Reset()                    { *m = VolumeEcShardsToVolumeResponse{} }
 func (m *VolumeEcShardsToVolumeResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeEcShardsToVolumeResponse) ProtoMessage()               {}
-func (*VolumeEcShardsToVolumeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+func (*VolumeEcShardsToVolumeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
 
 type ReadVolumeFileStatusRequest struct {
 	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
@@ -1439,7 +1477,7 @@ type ReadVolumeFileStatusRequest struct {
 func (m *ReadVolumeFileStatusRequest) Reset()                    { *m = ReadVolumeFileStatusRequest{} }
 func (m *ReadVolumeFileStatusRequest) String() string            { return proto.CompactTextString(m) }
 func (*ReadVolumeFileStatusRequest) ProtoMessage()               {}
-func (*ReadVolumeFileStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+func (*ReadVolumeFileStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
 
 func (m *ReadVolumeFileStatusRequest) GetVolumeId() uint32 {
 	if m != nil {
@@ -1462,7 +1500,7 @@ type ReadVolumeFileStatusResponse struct {
 func (m *ReadVolumeFileStatusResponse) Reset()                    { *m = ReadVolumeFileStatusResponse{} }
 func (m *ReadVolumeFileStatusResponse) String() string            { return proto.CompactTextString(m) }
 func (*ReadVolumeFileStatusResponse) ProtoMessage()               {}
-func (*ReadVolumeFileStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+func (*ReadVolumeFileStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
 
 func (m *ReadVolumeFileStatusResponse) GetVolumeId() uint32 {
 	if m != nil {
@@ -1532,7 +1570,7 @@ type DiskStatus struct {
 func (m *DiskStatus) Reset()                    { *m = DiskStatus{} }
 func (m *DiskStatus) String() string            { return proto.CompactTextString(m) }
 func (*DiskStatus) ProtoMessage()               {}
-func (*DiskStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+func (*DiskStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
 
 func (m *DiskStatus) GetDir() string {
 	if m != nil {
@@ -1589,7 +1627,7 @@ type MemStatus struct {
 func (m *MemStatus) Reset()                    { *m = MemStatus{} }
 func (m *MemStatus) String() string            { return proto.CompactTextString(m) }
 func (*MemStatus) ProtoMessage()               {}
-func (*MemStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+func (*MemStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
 
 func (m *MemStatus) GetGoroutines() int32 {
 	if m != nil {
@@ -1654,7 +1692,7 @@ type RemoteFile struct {
 func (m *RemoteFile) Reset()                    { *m = RemoteFile{} }
 func (m *RemoteFile) String() string            { return proto.CompactTextString(m) }
 func (*RemoteFile) ProtoMessage()               {}
-func (*RemoteFile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+func (*RemoteFile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
 
 func (m *RemoteFile) GetBackendType() string {
 	if m != nil {
@@ -1709,12 +1747,13 @@ type VolumeInfo struct {
 	Files       []*RemoteFile `protobuf:"bytes,1,rep,name=files" json:"files,omitempty"`
 	Version     uint32        `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
 	Replication string        `protobuf:"bytes,3,opt,name=replication" json:"replication,omitempty"`
+	AppendOnly  bool          `protobuf:"varint,4,opt,name=append_only,json=appendOnly" json:"append_only,omitempty"`
 }
 
 func (m *VolumeInfo) Reset()                    { *m = VolumeInfo{} }
 func (m *VolumeInfo) String() string            { return proto.CompactTextString(m) }
 func (*VolumeInfo) ProtoMessage()               {}
-func (*VolumeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+func (*VolumeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
 
 func (m *VolumeInfo) GetFiles() []*RemoteFile {
 	if m != nil {
@@ -1737,6 +1776,13 @@ func (m *VolumeInfo) GetReplication() string {
 	return ""
 }
 
+func (m *VolumeInfo) GetAppendOnly() bool {
+	if m != nil {
+		return m.AppendOnly
+	}
+	return false
+}
+
 type VolumeTierMoveDatToRemoteRequest struct {
 	VolumeId               uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
 	Collection             string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
@@ -1748,7 +1794,7 @@ func (m *VolumeTierMoveDatToRemoteRequest) Reset()         { *m = VolumeTierMove
 func (m *VolumeTierMoveDatToRemoteRequest) String() string { return proto.CompactTextString(m) }
 func (*VolumeTierMoveDatToRemoteRequest) ProtoMessage()    {}
 func (*VolumeTierMoveDatToRemoteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{64}
+	return fileDescriptor0, []int{70}
 }
 
 func (m *VolumeTierMoveDatToRemoteRequest) GetVolumeId() uint32 {
@@ -1788,7 +1834,7 @@ func (m *VolumeTierMoveDatToRemoteResponse) Reset()         { *m = VolumeTierMov
 func (m *VolumeTierMoveDatToRemoteResponse) String() string { return proto.CompactTextString(m) }
 func (*VolumeTierMoveDatToRemoteResponse) ProtoMessage()    {}
 func (*VolumeTierMoveDatToRemoteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{65}
+	return fileDescriptor0, []int{71}
 }
 
 func (m *VolumeTierMoveDatToRemoteResponse) GetProcessed() int64 {
@@ -1815,7 +1861,7 @@ func (m *VolumeTierMoveDatFromRemoteRequest) Reset()         { *m = VolumeTierMo
 func (m *VolumeTierMoveDatFromRemoteRequest) String() string { return proto.CompactTextString(m) }
 func (*VolumeTierMoveDatFromRemoteRequest) ProtoMessage()    {}
 func (*VolumeTierMoveDatFromRemoteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{66}
+	return fileDescriptor0, []int{72}
 }
 
 func (m *VolumeTierMoveDatFromRemoteRequest) GetVolumeId() uint32 {
@@ -1848,7 +1894,7 @@ func (m *VolumeTierMoveDatFromRemoteResponse) Reset()         { *m = VolumeTierM
 func (m *VolumeTierMoveDatFromRemoteResponse) String() string { return proto.CompactTextString(m) }
 func (*VolumeTierMoveDatFromRemoteResponse) ProtoMessage()    {}
 func (*VolumeTierMoveDatFromRemoteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{67}
+	return fileDescriptor0, []int{73}
 }
 
 func (m *VolumeTierMoveDatFromRemoteResponse) GetProcessed() int64 {
@@ -1871,7 +1917,7 @@ type VolumeServerStatusRequest struct {
 func (m *VolumeServerStatusRequest) Reset()                    { *m = VolumeServerStatusRequest{} }
 func (m *VolumeServerStatusRequest) String() string            { return proto.CompactTextString(m) }
 func (*VolumeServerStatusRequest) ProtoMessage()               {}
-func (*VolumeServerStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+func (*VolumeServerStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
 
 type VolumeServerStatusResponse struct {
 	DiskStatuses []*DiskStatus `protobuf:"bytes,1,rep,name=disk_statuses,json=diskStatuses" json:"disk_statuses,omitempty"`
@@ -1881,7 +1927,7 @@ type VolumeServerStatusResponse struct {
 func (m *VolumeServerStatusResponse) Reset()                    { *m = VolumeServerStatusResponse{} }
 func (m *VolumeServerStatusResponse) String() string            { return proto.CompactTextString(m) }
 func (*VolumeServerStatusResponse) ProtoMessage()               {}
-func (*VolumeServerStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+func (*VolumeServerStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
 
 func (m *VolumeServerStatusResponse) GetDiskStatuses() []*DiskStatus {
 	if m != nil {
@@ -1909,7 +1955,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()                    { *m = QueryRequest{} }
 func (m *QueryRequest) String() string            { return proto.CompactTextString(m) }
 func (*QueryRequest) ProtoMessage()               {}
-func (*QueryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+func (*QueryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
 
 func (m *QueryRequest) GetSelections() []string {
 	if m != nil {
@@ -1955,7 +2001,7 @@ type QueryRequest_Filter struct {
 func (m *QueryRequest_Filter) Reset()                    { *m = QueryRequest_Filter{} }
 func (m *QueryRequest_Filter) String() string            { return proto.CompactTextString(m) }
 func (*QueryRequest_Filter) ProtoMessage()               {}
-func (*QueryRequest_Filter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70, 0} }
+func (*QueryRequest_Filter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76, 0} }
 
 func (m *QueryRequest_Filter) GetField() string {
 	if m != nil {
@@ -1990,7 +2036,7 @@ func (m *QueryRequest_InputSerialization) Reset()         { *m = QueryRequest_In
 func (m *QueryRequest_InputSerialization) String() string { return proto.CompactTextString(m) }
 func (*QueryRequest_InputSerialization) ProtoMessage()    {}
 func (*QueryRequest_InputSerialization) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{70, 1}
+	return fileDescriptor0, []int{76, 1}
 }
 
 func (m *QueryRequest_InputSerialization) GetCompressionType() string {
@@ -2038,7 +2084,7 @@ func (m *QueryRequest_InputSerialization_CSVInput) Reset() {
 func (m *QueryRequest_InputSerialization_CSVInput) String() string { return proto.CompactTextString(m) }
 func (*QueryRequest_InputSerialization_CSVInput) ProtoMessage()    {}
 func (*QueryRequest_InputSerialization_CSVInput) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{70, 1, 0}
+	return fileDescriptor0, []int{76, 1, 0}
 }
 
 func (m *QueryRequest_InputSerialization_CSVInput) GetFileHeaderInfo() string {
@@ -2100,7 +2146,7 @@ func (m *QueryRequest_InputSerialization_JSONInput) Reset() {
 func (m *QueryRequest_InputSerialization_JSONInput) String() string { return proto.CompactTextString(m) }
 func (*QueryRequest_InputSerialization_JSONInput) ProtoMessage()    {}
 func (*QueryRequest_InputSerialization_JSONInput) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{70, 1, 1}
+	return fileDescriptor0, []int{76, 1, 1}
 }
 
 func (m *QueryRequest_InputSerialization_JSONInput) GetType() string {
@@ -2121,7 +2167,7 @@ func (m *QueryRequest_InputSerialization_ParquetInput) String() string {
 }
 func (*QueryRequest_InputSerialization_ParquetInput) ProtoMessage() {}
 func (*QueryRequest_InputSerialization_ParquetInput) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{70, 1, 2}
+	return fileDescriptor0, []int{76, 1, 2}
 }
 
 type QueryRequest_OutputSerialization struct {
@@ -2133,7 +2179,7 @@ func (m *QueryRequest_OutputSerialization) Reset()         { *m = QueryRequest_O
 func (m *QueryRequest_OutputSerialization) String() string { return proto.CompactTextString(m) }
 func (*QueryRequest_OutputSerialization) ProtoMessage()    {}
 func (*QueryRequest_OutputSerialization) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{70, 2}
+	return fileDescriptor0, []int{76, 2}
 }
 
 func (m *QueryRequest_OutputSerialization) GetCsvOutput() *QueryRequest_OutputSerialization_CSVOutput {
@@ -2166,7 +2212,7 @@ func (m *QueryRequest_OutputSerialization_CSVOutput) String() string {
 }
 func (*QueryRequest_OutputSerialization_CSVOutput) ProtoMessage() {}
 func (*QueryRequest_OutputSerialization_CSVOutput) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{70, 2, 0}
+	return fileDescriptor0, []int{76, 2, 0}
 }
 
 func (m *QueryRequest_OutputSerialization_CSVOutput) GetQuoteFields() string {
@@ -2216,7 +2262,7 @@ func (m *QueryRequest_OutputSerialization_JSONOutput) String() string {
 }
 func (*QueryRequest_OutputSerialization_JSONOutput) ProtoMessage() {}
 func (*QueryRequest_OutputSerialization_JSONOutput) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{70, 2, 1}
+	return fileDescriptor0, []int{76, 2, 1}
 }
 
 func (m *QueryRequest_OutputSerialization_JSONOutput) GetRecordDelimiter() string {
@@ -2233,7 +2279,7 @@ type QueriedStripe struct {
 func (m *QueriedStripe) Reset()                    { *m = QueriedStripe{} }
 func (m *QueriedStripe) String() string            { return proto.CompactTextString(m) }
 func (*QueriedStripe) ProtoMessage()               {}
-func (*QueriedStripe) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+func (*QueriedStripe) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
 
 func (m *QueriedStripe) GetRecords() []byte {
 	if m != nil {
@@ -2242,6 +2288,115 @@ func (m *QueriedStripe) GetRecords() []byte {
 	return nil
 }
 
+type VolumeMarkWritableRequest struct {
+	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
+}
+
+func (m *VolumeMarkWritableRequest) Reset()                    { *m = VolumeMarkWritableRequest{} }
+func (m *VolumeMarkWritableRequest) String() string            { return proto.CompactTextString(m) }
+func (*VolumeMarkWritableRequest) ProtoMessage()               {}
+func (*VolumeMarkWritableRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *VolumeMarkWritableRequest) GetVolumeId() uint32 {
+	if m != nil {
+		return m.VolumeId
+	}
+	return 0
+}
+
+type VolumeMarkWritableResponse struct {
+}
+
+func (m *VolumeMarkWritableResponse) Reset()                    { *m = VolumeMarkWritableResponse{} }
+func (m *VolumeMarkWritableResponse) String() string            { return proto.CompactTextString(m) }
+func (*VolumeMarkWritableResponse) ProtoMessage()               {}
+func (*VolumeMarkWritableResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+// a readonly volume server keeps serving reads, but accepts no writes or new volumes
+type VolumeServerMarkReadonlyRequest struct {
+	Readonly bool `protobuf:"varint,1,opt,name=readonly" json:"readonly,omitempty"`
+}
+
+func (m *VolumeServerMarkReadonlyRequest) Reset()         { *m = VolumeServerMarkReadonlyRequest{} }
+func (m *VolumeServerMarkReadonlyRequest) String() string { return proto.CompactTextString(m) }
+func (*VolumeServerMarkReadonlyRequest) ProtoMessage()    {}
+func (*VolumeServerMarkReadonlyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{34}
+}
+
+func (m *VolumeServerMarkReadonlyRequest) GetReadonly() bool {
+	if m != nil {
+		return m.Readonly
+	}
+	return false
+}
+
+type VolumeServerMarkReadonlyResponse struct {
+}
+
+func (m *VolumeServerMarkReadonlyResponse) Reset()         { *m = VolumeServerMarkReadonlyResponse{} }
+func (m *VolumeServerMarkReadonlyResponse) String() string { return proto.CompactTextString(m) }
+func (*VolumeServerMarkReadonlyResponse) ProtoMessage()    {}
+func (*VolumeServerMarkReadonlyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{35}
+}
+
+type ReadNeedleBlobRequest struct {
+	VolumeId uint32 `protobuf:"varint,1,opt,name=volume_id,json=volumeId" json:"volume_id,omitempty"`
+	NeedleId uint64 `protobuf:"varint,2,opt,name=needle_id,json=needleId" json:"needle_id,omitempty"`
+}
+
+func (m *ReadNeedleBlobRequest) Reset()                    { *m = ReadNeedleBlobRequest{} }
+func (m *ReadNeedleBlobRequest) String() string            { return proto.CompactTextString(m) }
+func (*ReadNeedleBlobRequest) ProtoMessage()               {}
+func (*ReadNeedleBlobRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *ReadNeedleBlobRequest) GetVolumeId() uint32 {
+	if m != nil {
+		return m.VolumeId
+	}
+	return 0
+}
+
+func (m *ReadNeedleBlobRequest) GetNeedleId() uint64 {
+	if m != nil {
+		return m.NeedleId
+	}
+	return 0
+}
+
+type ReadNeedleBlobResponse struct {
+	NeedleBlob []byte `protobuf:"bytes,1,opt,name=needle_blob,json=needleBlob,proto3" json:"needle_blob,omitempty"`
+	Size       uint32 `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
+	Version    uint32 `protobuf:"varint,3,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *ReadNeedleBlobResponse) Reset()                    { *m = ReadNeedleBlobResponse{} }
+func (m *ReadNeedleBlobResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReadNeedleBlobResponse) ProtoMessage()               {}
+func (*ReadNeedleBlobResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *ReadNeedleBlobResponse) GetNeedleBlob() []byte {
+	if m != nil {
+		return m.NeedleBlob
+	}
+	return nil
+}
+
+func (m *ReadNeedleBlobResponse) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *ReadNeedleBlobResponse) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*BatchDeleteRequest)(nil), "volume_server_pb.BatchDeleteRequest")
 	proto.RegisterType((*BatchDeleteResponse)(nil), "volume_server_pb.BatchDeleteResponse")
@@ -2273,6 +2428,12 @@ func init() {
 	proto.RegisterType((*VolumeDeleteResponse)(nil), "volume_server_pb.VolumeDeleteResponse")
 	proto.RegisterType((*VolumeMarkReadonlyRequest)(nil), "volume_server_pb.VolumeMarkReadonlyRequest")
 	proto.RegisterType((*VolumeMarkReadonlyResponse)(nil), "volume_server_pb.VolumeMarkReadonlyResponse")
+	proto.RegisterType((*VolumeMarkWritableRequest)(nil), "volume_server_pb.VolumeMarkWritableRequest")
+	proto.RegisterType((*VolumeMarkWritableResponse)(nil), "volume_server_pb.VolumeMarkWritableResponse")
+	proto.RegisterType((*VolumeServerMarkReadonlyRequest)(nil), "volume_server_pb.VolumeServerMarkReadonlyRequest")
+	proto.RegisterType((*VolumeServerMarkReadonlyResponse)(nil), "volume_server_pb.VolumeServerMarkReadonlyResponse")
+	proto.RegisterType((*ReadNeedleBlobRequest)(nil), "volume_server_pb.ReadNeedleBlobRequest")
+	proto.RegisterType((*ReadNeedleBlobResponse)(nil), "volume_server_pb.ReadNeedleBlobResponse")
 	proto.RegisterType((*VolumeConfigureRequest)(nil), "volume_server_pb.VolumeConfigureRequest")
 	proto.RegisterType((*VolumeConfigureResponse)(nil), "volume_server_pb.VolumeConfigureResponse")
 	proto.RegisterType((*VolumeCopyRequest)(nil), "volume_server_pb.VolumeCopyRequest")
@@ -2339,6 +2500,8 @@ type VolumeServerClient interface {
 	// Experts only: takes multiple fid parameters. This function does not propagate deletes to replicas.
 	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
 	FileGet(ctx context.Context, in *FileGetRequest, opts ...grpc.CallOption) (VolumeServer_FileGetClient, error)
+	// the raw needle bytes, to repair the corrupted needle on a replica
+	ReadNeedleBlob(ctx context.Context, in *ReadNeedleBlobRequest, opts ...grpc.CallOption) (*ReadNeedleBlobResponse, error)
 	VacuumVolumeCheck(ctx context.Context, in *VacuumVolumeCheckRequest, opts ...grpc.CallOption) (*VacuumVolumeCheckResponse, error)
 	VacuumVolumeCompact(ctx context.Context, in *VacuumVolumeCompactRequest, opts ...grpc.CallOption) (*VacuumVolumeCompactResponse, error)
 	VacuumVolumeCommit(ctx context.Context, in *VacuumVolumeCommitRequest, opts ...grpc.CallOption) (*VacuumVolumeCommitResponse, error)
@@ -2351,6 +2514,8 @@ type VolumeServerClient interface {
 	VolumeUnmount(ctx context.Context, in *VolumeUnmountRequest, opts ...grpc.CallOption) (*VolumeUnmountResponse, error)
 	VolumeDelete(ctx context.Context, in *VolumeDeleteRequest, opts ...grpc.CallOption) (*VolumeDeleteResponse, error)
 	VolumeMarkReadonly(ctx context.Context, in *VolumeMarkReadonlyRequest, opts ...grpc.CallOption) (*VolumeMarkReadonlyResponse, error)
+	VolumeMarkWritable(ctx context.Context, in *VolumeMarkWritableRequest, opts ...grpc.CallOption) (*VolumeMarkWritableResponse, error)
+	VolumeServerMarkReadonly(ctx context.Context, in *VolumeServerMarkReadonlyRequest, opts ...grpc.CallOption) (*VolumeServerMarkReadonlyResponse, error)
 	VolumeConfigure(ctx context.Context, in *VolumeConfigureRequest, opts ...grpc.CallOption) (*VolumeConfigureResponse, error)
 	// copy the .idx .dat files, and mount this volume
 	VolumeCopy(ctx context.Context, in *VolumeCopyRequest, opts ...grpc.CallOption) (*VolumeCopyResponse, error)
@@ -2425,6 +2590,15 @@ func (x *volumeServerFileGetClient) Recv() (*FileGetResponse, error) {
 	return m, nil
 }
 
+func (c *volumeServerClient) ReadNeedleBlob(ctx context.Context, in *ReadNeedleBlobRequest, opts ...grpc.CallOption) (*ReadNeedleBlobResponse, error) {
+	out := new(ReadNeedleBlobResponse)
+	err := grpc.Invoke(ctx, "/volume_server_pb.VolumeServer/ReadNeedleBlob", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *volumeServerClient) VacuumVolumeCheck(ctx context.Context, in *VacuumVolumeCheckRequest, opts ...grpc.CallOption) (*VacuumVolumeCheckResponse, error) {
 	out := new(VacuumVolumeCheckResponse)
 	err := grpc.Invoke(ctx, "/volume_server_pb.VolumeServer/VacuumVolumeCheck", in, out, c.cc, opts...)
@@ -2556,6 +2730,24 @@ func (c *volumeServerClient) VolumeMarkReadonly(ctx context.Context, in *VolumeM
 	return out, nil
 }
 
+func (c *volumeServerClient) VolumeMarkWritable(ctx context.Context, in *VolumeMarkWritableRequest, opts ...grpc.CallOption) (*VolumeMarkWritableResponse, error) {
+	out := new(VolumeMarkWritableResponse)
+	err := grpc.Invoke(ctx, "/volume_server_pb.VolumeServer/VolumeMarkWritable", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *volumeServerClient) VolumeServerMarkReadonly(ctx context.Context, in *VolumeServerMarkReadonlyRequest, opts ...grpc.CallOption) (*VolumeServerMarkReadonlyResponse, error) {
+	out := new(VolumeServerMarkReadonlyResponse)
+	err := grpc.Invoke(ctx, "/volume_server_pb.VolumeServer/VolumeServerMarkReadonly", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *volumeServerClient) VolumeConfigure(ctx context.Context, in *VolumeConfigureRequest, opts ...grpc.CallOption) (*VolumeConfigureResponse, error) {
 	out := new(VolumeConfigureResponse)
 	err := grpc.Invoke(ctx, "/volume_server_pb.VolumeServer/VolumeConfigure", in, out, c.cc, opts...)
@@ -2871,6 +3063,8 @@ type VolumeServerServer interface {
 	// Experts only: takes multiple fid parameters. This function does not propagate deletes to replicas.
 	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
 	FileGet(*FileGetRequest, VolumeServer_FileGetServer) error
+	// the raw needle bytes, to repair the corrupted needle on a replica
+	ReadNeedleBlob(context.Context, *ReadNeedleBlobRequest) (*ReadNeedleBlobResponse, error)
 	VacuumVolumeCheck(context.Context, *VacuumVolumeCheckRequest) (*VacuumVolumeCheckResponse, error)
 	VacuumVolumeCompact(context.Context, *VacuumVolumeCompactRequest) (*VacuumVolumeCompactResponse, error)
 	VacuumVolumeCommit(context.Context, *VacuumVolumeCommitRequest) (*VacuumVolumeCommitResponse, error)
@@ -2883,6 +3077,8 @@ type VolumeServerServer interface {
 	VolumeUnmount(context.Context, *VolumeUnmountRequest) (*VolumeUnmountResponse, error)
 	VolumeDelete(context.Context, *VolumeDeleteRequest) (*VolumeDeleteResponse, error)
 	VolumeMarkReadonly(context.Context, *VolumeMarkReadonlyRequest) (*VolumeMarkReadonlyResponse, error)
+	VolumeMarkWritable(context.Context, *VolumeMarkWritableRequest) (*VolumeMarkWritableResponse, error)
+	VolumeServerMarkReadonly(context.Context, *VolumeServerMarkReadonlyRequest) (*VolumeServerMarkReadonlyResponse, error)
 	VolumeConfigure(context.Context, *VolumeConfigureRequest) (*VolumeConfigureResponse, error)
 	// copy the .idx .dat files, and mount this volume
 	VolumeCopy(context.Context, *VolumeCopyRequest) (*VolumeCopyResponse, error)
@@ -2951,6 +3147,24 @@ func (x *volumeServerFileGetServer) Send(m *FileGetResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _VolumeServer_ReadNeedleBlob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadNeedleBlobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeServerServer).ReadNeedleBlob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/volume_server_pb.VolumeServer/ReadNeedleBlob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeServerServer).ReadNeedleBlob(ctx, req.(*ReadNeedleBlobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VolumeServer_VacuumVolumeCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(VacuumVolumeCheckRequest)
 	if err := dec(in); err != nil {
@@ -3170,6 +3384,42 @@ func _VolumeServer_VolumeMarkReadonly_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VolumeServer_VolumeMarkWritable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeMarkWritableRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeServerServer).VolumeMarkWritable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/volume_server_pb.VolumeServer/VolumeMarkWritable",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeServerServer).VolumeMarkWritable(ctx, req.(*VolumeMarkWritableRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VolumeServer_VolumeServerMarkReadonly_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeServerMarkReadonlyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VolumeServerServer).VolumeServerMarkReadonly(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/volume_server_pb.VolumeServer/VolumeServerMarkReadonly",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VolumeServerServer).VolumeServerMarkReadonly(ctx, req.(*VolumeServerMarkReadonlyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _VolumeServer_VolumeConfigure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(VolumeConfigureRequest)
 	if err := dec(in); err != nil {
@@ -3538,6 +3788,10 @@ var _VolumeServer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "BatchDelete",
 			Handler:    _VolumeServer_BatchDelete_Handler,
 		},
+		{
+			MethodName: "ReadNeedleBlob",
+			Handler:    _VolumeServer_ReadNeedleBlob_Handler,
+		},
 		{
 			MethodName: "VacuumVolumeCheck",
 			Handler:    _VolumeServer_VacuumVolumeCheck_Handler,
@@ -3582,6 +3836,14 @@ var _VolumeServer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "VolumeMarkReadonly",
 			Handler:    _VolumeServer_VolumeMarkReadonly_Handler,
 		},
+		{
+			MethodName: "VolumeMarkWritable",
+			Handler:    _VolumeServer_VolumeMarkWritable_Handler,
+		},
+		{
+			MethodName: "VolumeServerMarkReadonly",
+			Handler:    _VolumeServer_VolumeServerMarkReadonly_Handler,
+		},
 		{
 			MethodName: "VolumeConfigure",
 			Handler:    _VolumeServer_VolumeConfigure_Handler,
@@ -3683,214 +3945,226 @@ var _VolumeServer_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("volume_server.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 3329 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xd4, 0x3b, 0x4b, 0x6f, 0x1c, 0xc7,
-	0xd1, 0x5c, 0x2e, 0x1f, 0xbb, 0xb5, 0xbb, 0x22, 0xd5, 0x94, 0xa9, 0xf5, 0x90, 0x92, 0xa8, 0x91,
-	0x1f, 0x92, 0x6c, 0x51, 0x32, 0x6d, 0x7f, 0x96, 0xe5, 0xcf, 0xfe, 0x2c, 0x51, 0xa2, 0x2c, 0x5b,
-	0xa4, 0xec, 0xa1, 0x2c, 0x7f, 0x89, 0x8c, 0x0c, 0x86, 0x33, 0xbd, 0xe4, 0x98, 0xb3, 0xd3, 0xa3,
-	0x99, 0x5e, 0x5a, 0x2b, 0x38, 0x27, 0x07, 0x48, 0x80, 0x20, 0x39, 0x04, 0xb9, 0xe4, 0x12, 0x20,
-	0xc8, 0x3d, 0xd7, 0xfc, 0x05, 0xff, 0x81, 0x00, 0x39, 0xe5, 0x92, 0x73, 0x0e, 0x39, 0x04, 0x08,
-	0x90, 0x4b, 0xd0, 0xaf, 0xd9, 0x79, 0x72, 0x87, 0x11, 0x83, 0x20, 0xb7, 0xe9, 0xea, 0xea, 0xaa,
-	0xae, 0xea, 0xaa, 0xea, 0xea, 0xaa, 0x5d, 0x58, 0x38, 0x20, 0xde, 0xa0, 0x8f, 0xcd, 0x08, 0x87,
-	0x07, 0x38, 0x5c, 0x0d, 0x42, 0x42, 0x09, 0x9a, 0x4f, 0x01, 0xcd, 0x60, 0x47, 0x7f, 0x0c, 0xe8,
-	0x96, 0x45, 0xed, 0xbd, 0xdb, 0xd8, 0xc3, 0x14, 0x1b, 0xf8, 0xc9, 0x00, 0x47, 0x14, 0xbd, 0x08,
-	0x8d, 0x9e, 0xeb, 0x61, 0xd3, 0x75, 0xa2, 0x6e, 0x6d, 0xa5, 0x7e, 0xb1, 0x69, 0xcc, 0xb2, 0xf1,
-	0x3d, 0x27, 0x42, 0x97, 0xe1, 0x64, 0xb4, 0xef, 0x06, 0xa6, 0x4d, 0xc8, 0xbe, 0x8b, 0x4d, 0x7b,
-	0x0f, 0xdb, 0xfb, 0xdd, 0xc9, 0x95, 0xda, 0xc5, 0x86, 0x31, 0xc7, 0x26, 0xd6, 0x39, 0x7c, 0x9d,
-	0x81, 0xf5, 0x07, 0xb0, 0x90, 0x22, 0x1e, 0x05, 0xc4, 0x8f, 0x30, 0xba, 0x0e, 0xb3, 0x21, 0x8e,
-	0x06, 0x1e, 0x15, 0xc4, 0x5b, 0x6b, 0x67, 0x57, 0xb3, 0xfb, 0x5a, 0x8d, 0x97, 0x0c, 0x3c, 0x6a,
-	0x28, 0x74, 0xfd, 0xdb, 0x1a, 0xb4, 0x93, 0x33, 0xe8, 0x34, 0xcc, 0xca, 0x8d, 0x76, 0x6b, 0x2b,
-	0xb5, 0x8b, 0x4d, 0x63, 0x46, 0xec, 0x13, 0x2d, 0xc2, 0x4c, 0x44, 0x2d, 0x3a, 0x88, 0xf8, 0xde,
-	0xa6, 0x0d, 0x39, 0x42, 0xa7, 0x60, 0x1a, 0x87, 0x21, 0x09, 0xbb, 0x75, 0x8e, 0x2e, 0x06, 0x08,
-	0xc1, 0x54, 0xe4, 0x3e, 0xc3, 0xdd, 0xa9, 0x95, 0xda, 0xc5, 0x8e, 0xc1, 0xbf, 0x51, 0x17, 0x66,
-	0x0f, 0x70, 0x18, 0xb9, 0xc4, 0xef, 0x4e, 0x73, 0xb0, 0x1a, 0xea, 0x1f, 0xc3, 0x89, 0x0d, 0xd7,
-	0xc3, 0x77, 0x31, 0x55, 0xfa, 0x2a, 0xdd, 0xc6, 0x39, 0x68, 0x59, 0xb6, 0x8d, 0x03, 0x6a, 0xee,
-	0x3e, 0x73, 0x03, 0xa9, 0x27, 0x10, 0xa0, 0xbb, 0xcf, 0xdc, 0x40, 0xff, 0x71, 0x1d, 0xe6, 0x62,
-	0x62, 0x52, 0x3f, 0x08, 0xa6, 0x1c, 0x8b, 0x5a, 0x9c, 0x54, 0xdb, 0xe0, 0xdf, 0xe8, 0x65, 0x38,
-	0x61, 0x13, 0x9f, 0x62, 0x9f, 0x9a, 0x1e, 0xf6, 0x77, 0xe9, 0x1e, 0xa7, 0xd5, 0x31, 0x3a, 0x12,
-	0x7a, 0x9f, 0x03, 0xd1, 0x79, 0x68, 0x2b, 0x34, 0x3a, 0x0c, 0xb0, 0x94, 0xb2, 0x25, 0x61, 0x0f,
-	0x87, 0x01, 0x46, 0x17, 0xa0, 0xe3, 0x59, 0x11, 0x35, 0xfb, 0xc4, 0x71, 0x7b, 0x2e, 0x76, 0xb8,
-	0xd0, 0x53, 0x46, 0x9b, 0x01, 0x37, 0x25, 0x0c, 0x69, 0xc2, 0x00, 0x7c, 0xab, 0x8f, 0xb9, 0xf4,
-	0x4d, 0x23, 0x1e, 0xb3, 0xed, 0x61, 0x6a, 0xed, 0x76, 0x67, 0x38, 0x9c, 0x7f, 0xa3, 0x33, 0x00,
-	0x6e, 0xc4, 0x65, 0x0c, 0xb0, 0xd3, 0x9d, 0xe5, 0x62, 0x36, 0xdd, 0xe8, 0xae, 0x00, 0xa0, 0x8f,
-	0x60, 0x76, 0x0f, 0x5b, 0x0e, 0x0e, 0xa3, 0x6e, 0x83, 0x9f, 0xf8, 0x6a, 0xfe, 0xc4, 0x33, 0x5a,
-	0x58, 0xfd, 0x48, 0x2c, 0xb8, 0xe3, 0xd3, 0x70, 0x68, 0xa8, 0xe5, 0x68, 0x19, 0x9a, 0xfc, 0xc8,
-	0xd6, 0x89, 0x83, 0xbb, 0x4d, 0x7e, 0xb4, 0x23, 0x80, 0x76, 0x03, 0xda, 0xc9, 0x65, 0x68, 0x1e,
-	0xea, 0xfb, 0x78, 0x28, 0xcf, 0x84, 0x7d, 0xb2, 0xf3, 0x3f, 0xb0, 0xbc, 0x01, 0xe6, 0xea, 0x6b,
-	0x1a, 0x62, 0x70, 0x63, 0xf2, 0x7a, 0x4d, 0x9f, 0x85, 0xe9, 0x3b, 0xfd, 0x80, 0x0e, 0xf5, 0x77,
-	0xa0, 0xfb, 0xc8, 0xb2, 0x07, 0x83, 0xfe, 0x23, 0xbe, 0x45, 0x6e, 0xca, 0xea, 0xa0, 0x97, 0xa0,
-	0x29, 0x37, 0x2e, 0x8f, 0xba, 0x63, 0x34, 0x04, 0xe0, 0x9e, 0xa3, 0x7f, 0x08, 0x2f, 0x16, 0x2c,
-	0x94, 0x87, 0x7a, 0x01, 0x3a, 0xbb, 0x56, 0xb8, 0x63, 0xed, 0x62, 0x33, 0xb4, 0xa8, 0x4b, 0xf8,
-	0xea, 0x9a, 0xd1, 0x96, 0x40, 0x83, 0xc1, 0xf4, 0xc7, 0xa0, 0xa5, 0x28, 0x90, 0x7e, 0x60, 0xd9,
-	0xb4, 0x0a, 0x73, 0xb4, 0x02, 0xad, 0x20, 0xc4, 0x96, 0xe7, 0x11, 0xdb, 0xa2, 0x42, 0xbc, 0xba,
-	0x91, 0x04, 0xe9, 0x67, 0x60, 0xa9, 0x90, 0xb8, 0xd8, 0xa0, 0x7e, 0x3d, 0xb3, 0x7b, 0xd2, 0xef,
-	0xbb, 0x95, 0x58, 0xeb, 0x1f, 0xe4, 0x76, 0xcd, 0x57, 0x4a, 0xc1, 0x57, 0xa0, 0xed, 0x46, 0x66,
-	0x88, 0x2d, 0xc7, 0x24, 0xbe, 0x27, 0x0e, 0xa3, 0x61, 0x80, 0x1b, 0x19, 0xd8, 0x72, 0x1e, 0xf8,
-	0xde, 0x50, 0x7f, 0x37, 0xb3, 0xde, 0xc3, 0x96, 0x3f, 0x08, 0x2a, 0xb1, 0xce, 0xca, 0xa4, 0x96,
-	0x4a, 0x99, 0xde, 0x85, 0xd3, 0x22, 0x5c, 0xac, 0x13, 0xcf, 0xc3, 0x36, 0x75, 0x89, 0xaf, 0xc8,
-	0x9e, 0x05, 0xb0, 0x63, 0xa0, 0xb4, 0x90, 0x04, 0x44, 0xd7, 0xa0, 0x9b, 0x5f, 0x2a, 0xc9, 0xfe,
-	0xa9, 0x06, 0x2f, 0xdc, 0x94, 0x6a, 0x15, 0x8c, 0x2b, 0x1d, 0x51, 0x9a, 0xe5, 0x64, 0x96, 0x65,
-	0xf6, 0x08, 0xeb, 0xb9, 0x23, 0x64, 0x18, 0x21, 0x0e, 0x3c, 0xd7, 0xb6, 0x38, 0x89, 0x29, 0xe1,
-	0xdd, 0x09, 0x10, 0xb3, 0x78, 0x4a, 0x3d, 0xe9, 0xb3, 0xec, 0x13, 0xad, 0xc1, 0x62, 0x1f, 0xf7,
-	0x49, 0x38, 0x34, 0xfb, 0x56, 0x60, 0xf6, 0xad, 0xa7, 0x26, 0x0b, 0x6f, 0x66, 0x7f, 0x87, 0x3b,
-	0x70, 0xc7, 0x40, 0x62, 0x76, 0xd3, 0x0a, 0x36, 0xad, 0xa7, 0xdb, 0xee, 0x33, 0xbc, 0xb9, 0xa3,
-	0x77, 0x61, 0x31, 0x2b, 0x9f, 0x14, 0xfd, 0x7f, 0xe0, 0xb4, 0x80, 0x6c, 0x0f, 0x7d, 0x7b, 0x9b,
-	0xc7, 0xd4, 0x4a, 0x07, 0xf5, 0x8f, 0x1a, 0x74, 0xf3, 0x0b, 0xa5, 0x89, 0x3c, 0xaf, 0xd6, 0x8e,
-	0xac, 0x93, 0x73, 0xd0, 0xa2, 0x96, 0xeb, 0x99, 0xa4, 0xd7, 0x8b, 0x30, 0xe5, 0x8a, 0x98, 0x32,
-	0x80, 0x81, 0x1e, 0x70, 0x08, 0xba, 0x04, 0xf3, 0xb6, 0xf0, 0x0f, 0x33, 0xc4, 0x07, 0x2e, 0xbf,
-	0x05, 0x66, 0xf9, 0xc6, 0xe6, 0x6c, 0xe5, 0x37, 0x02, 0x8c, 0x74, 0xe8, 0xb8, 0xce, 0x53, 0x93,
-	0xc7, 0x7f, 0x7e, 0x89, 0x34, 0x38, 0xb5, 0x96, 0xeb, 0x3c, 0x65, 0x21, 0x8d, 0x69, 0x54, 0x7f,
-	0x04, 0xcb, 0x42, 0xf8, 0x7b, 0xbe, 0x1d, 0xe2, 0x3e, 0xf6, 0xa9, 0xe5, 0xad, 0x93, 0x60, 0x58,
-	0xc9, 0x6c, 0x5e, 0x84, 0x46, 0xe4, 0xfa, 0x36, 0x36, 0x7d, 0x71, 0x99, 0x4d, 0x19, 0xb3, 0x7c,
-	0xbc, 0x15, 0xe9, 0xb7, 0xe0, 0x4c, 0x09, 0x5d, 0xa9, 0xd9, 0xf3, 0xd0, 0xe6, 0x1b, 0x93, 0x17,
-	0x80, 0xbc, 0x52, 0x5a, 0x0c, 0xb6, 0x2e, 0x40, 0xfa, 0x1b, 0x80, 0x04, 0x8d, 0x4d, 0x32, 0xf0,
-	0xab, 0x39, 0xfc, 0x0b, 0xb0, 0x90, 0x5a, 0x22, 0x6d, 0xe3, 0x4d, 0x38, 0x25, 0xc0, 0x9f, 0xfb,
-	0xfd, 0xca, 0xb4, 0x4e, 0xc3, 0x0b, 0x99, 0x45, 0x92, 0xda, 0x9a, 0x62, 0x92, 0x4e, 0x4d, 0x0e,
-	0x25, 0xb6, 0xa8, 0x76, 0x90, 0xce, 0x38, 0x78, 0x6c, 0x13, 0x1b, 0xb6, 0xc2, 0x7d, 0x16, 0x77,
-	0x58, 0x24, 0xaa, 0x44, 0x71, 0x19, 0xb4, 0xa2, 0x95, 0x92, 0xee, 0x17, 0xb0, 0xa8, 0x62, 0x9e,
-	0xdf, 0x73, 0x77, 0x07, 0x21, 0xae, 0x1a, 0xab, 0x93, 0x26, 0x3b, 0x99, 0x33, 0x59, 0xfd, 0xaa,
-	0x72, 0xb3, 0x04, 0x61, 0x79, 0xa4, 0x71, 0x06, 0x53, 0x4b, 0x64, 0x30, 0xfa, 0xef, 0x6a, 0x70,
-	0x52, 0xad, 0xa8, 0x68, 0x57, 0x47, 0x74, 0xac, 0x7a, 0xa9, 0x63, 0x4d, 0x8d, 0x1c, 0xeb, 0x22,
-	0xcc, 0x47, 0x64, 0x10, 0xda, 0xd8, 0x64, 0x59, 0x8b, 0xe9, 0xb3, 0x5b, 0x5a, 0xf8, 0xdd, 0x09,
-	0x01, 0xbf, 0x6d, 0x51, 0x6b, 0x8b, 0x38, 0x58, 0xff, 0x3f, 0x65, 0x76, 0x29, 0x7b, 0xbd, 0x04,
-	0x27, 0x79, 0x72, 0x62, 0x05, 0x01, 0xf6, 0x1d, 0xd3, 0xa2, 0xcc, 0xe8, 0x6b, 0xdc, 0xe8, 0x4f,
-	0xb0, 0x89, 0x9b, 0x1c, 0x7e, 0x93, 0x6e, 0x45, 0xfa, 0x2f, 0x27, 0x61, 0x8e, 0xad, 0x65, 0x4e,
-	0x56, 0x49, 0xde, 0x79, 0xa8, 0xe3, 0xa7, 0x54, 0x0a, 0xca, 0x3e, 0xd1, 0x55, 0x58, 0x90, 0xde,
-	0xec, 0x12, 0x7f, 0xe4, 0xe8, 0x75, 0x11, 0x17, 0x47, 0x53, 0xb1, 0xaf, 0x9f, 0x83, 0x56, 0x44,
-	0x49, 0xa0, 0xe2, 0x86, 0xc8, 0x9c, 0x80, 0x81, 0x64, 0xdc, 0x48, 0xeb, 0x74, 0xba, 0x40, 0xa7,
-	0xec, 0x32, 0xc4, 0xb6, 0x29, 0x76, 0xc5, 0x23, 0x0f, 0xbf, 0x0c, 0xef, 0xd8, 0x42, 0x1b, 0xe8,
-	0x03, 0x58, 0x76, 0x77, 0x7d, 0x12, 0x62, 0x53, 0x2a, 0x92, 0xfb, 0xaf, 0x4f, 0xa8, 0xd9, 0x23,
-	0x03, 0x5f, 0xe5, 0x56, 0x5d, 0x81, 0xb3, 0xcd, 0x51, 0x98, 0x06, 0xb6, 0x08, 0xdd, 0x60, 0xf3,
-	0xfa, 0xdb, 0x30, 0x3f, 0xd2, 0x4a, 0xf5, 0x28, 0xf0, 0x6d, 0x4d, 0x59, 0xdc, 0x43, 0xcb, 0xf5,
-	0xb6, 0xb1, 0xef, 0xe0, 0xf0, 0x39, 0xa3, 0x13, 0xba, 0x06, 0xa7, 0x5c, 0xc7, 0xc3, 0x26, 0x75,
-	0xfb, 0x98, 0x0c, 0xa8, 0x19, 0x61, 0x9b, 0xf8, 0x4e, 0xa4, 0xf4, 0xcb, 0xe6, 0x1e, 0x8a, 0xa9,
-	0x6d, 0x31, 0xa3, 0xff, 0x28, 0xbe, 0x25, 0x92, 0xbb, 0x18, 0x65, 0x50, 0x3e, 0xc6, 0x8c, 0xa0,
-	0x48, 0x06, 0xa5, 0x18, 0x6d, 0x01, 0x14, 0x79, 0x1f, 0x3b, 0x21, 0x89, 0xb4, 0x43, 0x9c, 0x21,
-	0xdf, 0x51, 0xdb, 0x00, 0x01, 0xba, 0x45, 0x9c, 0x21, 0x0f, 0xd7, 0x91, 0xc9, 0x8d, 0xcc, 0xde,
-	0x1b, 0xf8, 0xfb, 0x7c, 0x37, 0x0d, 0xa3, 0xe5, 0x46, 0xf7, 0xad, 0x88, 0xae, 0x33, 0x90, 0xfe,
-	0xfb, 0x9a, 0x8a, 0x17, 0x6c, 0x1b, 0x06, 0xb6, 0xb1, 0x7b, 0xf0, 0x1f, 0x50, 0x07, 0x5b, 0x21,
-	0x8d, 0x20, 0x95, 0x2d, 0x4b, 0x87, 0x43, 0x62, 0x4e, 0xde, 0xaa, 0x7c, 0x66, 0x14, 0xae, 0xd2,
-	0x1b, 0x97, 0xe1, 0xea, 0x4b, 0x75, 0x5d, 0xdc, 0xb1, 0xb7, 0xf7, 0xac, 0xd0, 0x89, 0xee, 0x62,
-	0x1f, 0x87, 0x16, 0x3d, 0x96, 0xf4, 0x45, 0x5f, 0x81, 0xb3, 0x65, 0xd4, 0x25, 0xff, 0xc7, 0xea,
-	0x1a, 0x54, 0x18, 0x06, 0xde, 0x19, 0xb8, 0x9e, 0x73, 0x2c, 0xec, 0x3f, 0xc9, 0x0a, 0x17, 0x13,
-	0x97, 0xf6, 0x73, 0x19, 0x4e, 0x86, 0x1c, 0x44, 0xcd, 0x88, 0x21, 0xc4, 0xaf, 0xdb, 0x8e, 0x31,
-	0x27, 0x27, 0xf8, 0xc2, 0x7b, 0x4e, 0xa4, 0xff, 0x74, 0x52, 0x59, 0x80, 0xa2, 0x76, 0x6c, 0x61,
-	0x75, 0x09, 0x9a, 0x23, 0xf6, 0x75, 0xce, 0xbe, 0x11, 0x49, 0xbe, 0xcc, 0x3a, 0x6d, 0x12, 0x0c,
-	0x4d, 0x6c, 0x8b, 0x8c, 0x82, 0x1f, 0x75, 0x83, 0x3d, 0xe0, 0x82, 0xe1, 0x1d, 0x9b, 0x27, 0x14,
-	0xd5, 0x63, 0x6c, 0x82, 0xda, 0x57, 0x82, 0xda, 0x4c, 0x92, 0xda, 0x57, 0x9c, 0x9a, 0xc2, 0x39,
-	0x70, 0x7b, 0x02, 0x67, 0x76, 0x84, 0xf3, 0xc8, 0xed, 0x31, 0x9c, 0x91, 0x55, 0xa5, 0x95, 0x21,
-	0x4f, 0xf5, 0x6b, 0x58, 0x4a, 0xcf, 0x56, 0xbf, 0xb0, 0x9f, 0x4b, 0x59, 0xfa, 0xd9, 0xac, 0x39,
-	0x65, 0x6e, 0xfd, 0x83, 0xec, 0xb6, 0x2b, 0x67, 0x38, 0xcf, 0xb7, 0xaf, 0x33, 0x59, 0x85, 0xa4,
-	0xd3, 0xa4, 0xff, 0xcf, 0x6e, 0xfb, 0x08, 0xe9, 0xd2, 0xe1, 0x8c, 0xcf, 0x65, 0x5d, 0x20, 0x9b,
-	0x53, 0xfd, 0x2a, 0x8e, 0xaf, 0x12, 0x83, 0x65, 0x34, 0x95, 0xe3, 0x9a, 0xe4, 0x2b, 0x2b, 0x0f,
-	0xb3, 0x92, 0x2d, 0x5a, 0x84, 0x19, 0x79, 0x1f, 0x8a, 0x17, 0x8b, 0x1c, 0xa5, 0x8a, 0x2a, 0x75,
-	0x59, 0x54, 0x51, 0x85, 0x25, 0xf6, 0x2a, 0x9f, 0x16, 0xe1, 0x91, 0x8d, 0x3f, 0xc1, 0x43, 0x7d,
-	0x2b, 0xe3, 0x71, 0x62, 0x6b, 0x87, 0x94, 0x44, 0x44, 0xcd, 0xc1, 0xe1, 0x67, 0xee, 0xc8, 0xd2,
-	0x4a, 0xd3, 0x95, 0x46, 0xe0, 0xe8, 0x3f, 0xab, 0x8d, 0x08, 0xde, 0xf2, 0xc8, 0xce, 0x31, 0x5a,
-	0x65, 0x52, 0x8a, 0x7a, 0x4a, 0x8a, 0x64, 0xd5, 0x68, 0x2a, 0x5d, 0x35, 0x4a, 0x38, 0x51, 0x72,
-	0x3b, 0x65, 0xa1, 0xf9, 0x21, 0x39, 0xbe, 0x97, 0x65, 0x3e, 0x34, 0x8f, 0xa8, 0x4b, 0xfe, 0x37,
-	0x60, 0x89, 0x29, 0x5c, 0x40, 0xf9, 0xbb, 0xa5, 0xfa, 0xdb, 0xee, 0x2f, 0x93, 0xb0, 0x5c, 0xbc,
-	0xb8, 0xca, 0xfb, 0xee, 0x3d, 0xd0, 0xe2, 0xf7, 0x13, 0xbb, 0x1a, 0x23, 0x6a, 0xf5, 0x83, 0xf8,
-	0x72, 0x14, 0x77, 0xe8, 0x69, 0xf9, 0x98, 0x7a, 0xa8, 0xe6, 0xd5, 0x0d, 0x99, 0x7b, 0x7c, 0xd5,
-	0x73, 0x8f, 0x2f, 0xc6, 0xc0, 0xb1, 0x68, 0x19, 0x03, 0x91, 0xc3, 0x9d, 0x76, 0x2c, 0x5a, 0xc6,
-	0x20, 0x5e, 0xcc, 0x19, 0x08, 0xab, 0x6d, 0x49, 0x7c, 0xce, 0xe0, 0x0c, 0x80, 0x4c, 0xaf, 0x06,
-	0xbe, 0x7a, 0x4c, 0x36, 0x45, 0x72, 0x35, 0xf0, 0x4b, 0xb3, 0xcc, 0xd9, 0xd2, 0x2c, 0x33, 0x7d,
-	0x9a, 0x8d, 0xdc, 0x69, 0xfe, 0xba, 0x06, 0x70, 0xdb, 0x8d, 0xf6, 0x85, 0x96, 0x59, 0x5e, 0xeb,
-	0xb8, 0xea, 0x39, 0xc0, 0x3e, 0x19, 0xc4, 0xf2, 0x3c, 0xa9, 0x3b, 0xf6, 0xc9, 0xfc, 0x67, 0x10,
-	0x61, 0x47, 0xaa, 0x87, 0x7f, 0x33, 0x58, 0x2f, 0xc4, 0x58, 0x6a, 0x80, 0x7f, 0xb3, 0x4c, 0x31,
-	0xc0, 0xa1, 0x8d, 0x7d, 0x6a, 0xf2, 0x39, 0x26, 0xed, 0xa4, 0xd1, 0x92, 0xb0, 0x8d, 0x0c, 0x0a,
-	0x27, 0x39, 0x93, 0x42, 0xf9, 0x3c, 0xc2, 0x8e, 0xfe, 0xdb, 0x1a, 0x34, 0x37, 0x71, 0x5f, 0xee,
-	0xef, 0x2c, 0xc0, 0x2e, 0x09, 0xc9, 0x80, 0xba, 0x3e, 0x16, 0xc9, 0xfc, 0xb4, 0x91, 0x80, 0x3c,
-	0xc7, 0x6e, 0x59, 0x84, 0xc1, 0x5e, 0x4f, 0x9e, 0x09, 0xff, 0x66, 0xb0, 0x3d, 0x6c, 0x05, 0xf2,
-	0x18, 0xf8, 0x37, 0x7b, 0x32, 0x45, 0xd4, 0xb2, 0xf7, 0xb9, 0xce, 0xa7, 0x0c, 0x31, 0xd0, 0xff,
-	0x58, 0x03, 0x30, 0x70, 0x9f, 0x50, 0x6e, 0xb2, 0x4c, 0xae, 0x1d, 0xcb, 0xde, 0x67, 0xcf, 0x0e,
-	0x5e, 0x3a, 0x15, 0xfa, 0x6c, 0x49, 0x18, 0x2f, 0x9d, 0x9e, 0x01, 0x50, 0x28, 0x32, 0x0c, 0x36,
-	0x8d, 0xa6, 0x84, 0x88, 0x07, 0x86, 0x8a, 0x08, 0xb2, 0xda, 0x38, 0x0a, 0x8d, 0x62, 0xdb, 0x2a,
-	0x34, 0x2e, 0x41, 0x33, 0x6b, 0x51, 0x3c, 0xa2, 0x70, 0x73, 0xba, 0x00, 0x1d, 0x55, 0x9b, 0xe5,
-	0xf6, 0x2a, 0x45, 0x69, 0x2b, 0x20, 0xb3, 0x51, 0x5e, 0x07, 0x7d, 0x4a, 0xb1, 0x1f, 0x9b, 0x52,
-	0xd3, 0x18, 0x01, 0xf4, 0x6f, 0x00, 0x54, 0x5d, 0xa0, 0x47, 0xd0, 0x1a, 0x4c, 0x33, 0xe2, 0xaa,
-	0xda, 0xbe, 0x9c, 0xaf, 0xbd, 0x8e, 0xd4, 0x60, 0x08, 0xd4, 0x64, 0x1c, 0x9b, 0x4c, 0xc5, 0xb1,
-	0xf1, 0xcf, 0x42, 0xfd, 0xbb, 0x1a, 0xac, 0xc8, 0x2c, 0xd4, 0xc5, 0xe1, 0x26, 0x39, 0x60, 0x19,
-	0xc9, 0x43, 0x22, 0x98, 0x1c, 0x4b, 0x00, 0xbe, 0x0e, 0x5d, 0x07, 0x47, 0xd4, 0xf5, 0x39, 0x43,
-	0x53, 0x1d, 0x0a, 0x2f, 0x57, 0x8b, 0x0d, 0x2d, 0x26, 0xe6, 0x6f, 0x89, 0xe9, 0x2d, 0xab, 0x8f,
-	0xd1, 0x15, 0x58, 0xd8, 0xc7, 0x38, 0x30, 0x3d, 0x62, 0x5b, 0x9e, 0xa9, 0x5c, 0x5b, 0xa6, 0x59,
-	0xf3, 0x6c, 0xea, 0x3e, 0x9b, 0xb9, 0x2d, 0xdc, 0x5b, 0x8f, 0xe0, 0xfc, 0x21, 0x92, 0xc8, 0xf0,
-	0xb6, 0x0c, 0xcd, 0x20, 0x24, 0x36, 0x8e, 0x98, 0xcd, 0xd6, 0xf8, 0x6d, 0x37, 0x02, 0xa0, 0x6b,
-	0xb0, 0x10, 0x0f, 0x3e, 0x15, 0x4e, 0x62, 0xed, 0x8a, 0x02, 0xed, 0xa4, 0x51, 0x34, 0xa5, 0xff,
-	0xa2, 0x06, 0x7a, 0x8e, 0xeb, 0x46, 0x48, 0xfa, 0xc7, 0xa8, 0xc1, 0xab, 0x70, 0x8a, 0xeb, 0x21,
-	0xe4, 0x24, 0x47, 0x8a, 0x10, 0xaf, 0xa1, 0x93, 0x6c, 0x4e, 0x70, 0x53, 0x9a, 0x18, 0xc0, 0x85,
-	0x43, 0xf7, 0xf4, 0x6f, 0xd2, 0xc5, 0x92, 0xba, 0xc4, 0xc5, 0x03, 0x27, 0x75, 0x2b, 0xe9, 0xbf,
-	0xa9, 0xa9, 0x3b, 0x35, 0x3d, 0x2b, 0xf7, 0x72, 0x13, 0x3a, 0x8e, 0x1b, 0xed, 0x9b, 0xa2, 0xf5,
-	0x73, 0x98, 0xfd, 0x8f, 0xa2, 0xa9, 0xd1, 0x76, 0xe2, 0x6f, 0x1c, 0xa1, 0x0f, 0xa1, 0x23, 0x8b,
-	0xa7, 0x89, 0x6e, 0x52, 0x6b, 0x6d, 0x29, 0x4f, 0x22, 0x8e, 0x77, 0x46, 0x5b, 0xac, 0x10, 0x23,
-	0xfd, 0xef, 0x6d, 0x68, 0x7f, 0x36, 0xc0, 0xe1, 0x30, 0x51, 0x78, 0x8e, 0xb0, 0x3c, 0x06, 0xd5,
-	0x5d, 0x4b, 0x40, 0xd8, 0x8d, 0xd3, 0x0b, 0x49, 0xdf, 0x8c, 0x1b, 0x70, 0x93, 0x1c, 0xa5, 0xc5,
-	0x80, 0x1b, 0xb2, 0x09, 0xf7, 0x3e, 0xcc, 0xf4, 0x5c, 0x8f, 0x62, 0xd1, 0xc6, 0x6a, 0xad, 0xbd,
-	0x9c, 0xdf, 0x4f, 0x92, 0xe7, 0xea, 0x06, 0x47, 0x36, 0xe4, 0x22, 0xb4, 0x03, 0x0b, 0xae, 0x1f,
-	0xf0, 0x27, 0x68, 0xe8, 0x5a, 0x9e, 0xfb, 0x6c, 0x54, 0x3a, 0x6d, 0xad, 0xbd, 0x31, 0x86, 0xd6,
-	0x3d, 0xb6, 0x72, 0x3b, 0xb9, 0xd0, 0x40, 0x6e, 0x0e, 0x86, 0x30, 0x9c, 0x22, 0x03, 0x9a, 0x67,
-	0x32, 0xcd, 0x99, 0xac, 0x8d, 0x61, 0xf2, 0x80, 0x2f, 0x4d, 0x73, 0x59, 0x20, 0x79, 0xa0, 0xb6,
-	0x05, 0x33, 0x42, 0x38, 0x16, 0xe4, 0x7b, 0x2e, 0xf6, 0x54, 0x07, 0x4e, 0x0c, 0x58, 0x1c, 0x23,
-	0x01, 0x0e, 0x2d, 0x5f, 0xc5, 0x6b, 0x35, 0x1c, 0x75, 0x82, 0xea, 0x89, 0x4e, 0x90, 0xf6, 0x87,
-	0x69, 0x40, 0x79, 0x09, 0x55, 0x3d, 0x38, 0xc4, 0x11, 0x8b, 0x81, 0xc9, 0x0b, 0x62, 0x2e, 0x01,
-	0xe7, 0x97, 0xc4, 0x17, 0xd0, 0xb4, 0xa3, 0x03, 0x93, 0xab, 0x44, 0x9a, 0xcb, 0x8d, 0x23, 0xab,
-	0x74, 0x75, 0x7d, 0xfb, 0x11, 0x87, 0x1a, 0x0d, 0x3b, 0x3a, 0xe0, 0x5f, 0xe8, 0xfb, 0x00, 0x5f,
-	0x45, 0xc4, 0x97, 0x94, 0xc5, 0xc1, 0xbf, 0x77, 0x74, 0xca, 0x1f, 0x6f, 0x3f, 0xd8, 0x12, 0xa4,
-	0x9b, 0x8c, 0x9c, 0xa0, 0x6d, 0x43, 0x27, 0xb0, 0xc2, 0x27, 0x03, 0x4c, 0x25, 0x79, 0x61, 0x0b,
-	0x1f, 0x1c, 0x9d, 0xfc, 0xa7, 0x82, 0x8c, 0xe0, 0xd0, 0x0e, 0x12, 0x23, 0xed, 0xbb, 0x49, 0x68,
-	0x28, 0xb9, 0xd8, 0x2b, 0x96, 0x5b, 0xb8, 0xa8, 0xe5, 0x98, 0xae, 0xdf, 0x23, 0x52, 0xa3, 0x27,
-	0x18, 0x5c, 0x94, 0x73, 0xf8, 0xf5, 0x75, 0x09, 0xe6, 0x43, 0x6c, 0x93, 0xd0, 0x61, 0xb9, 0xbe,
-	0xdb, 0x77, 0x99, 0xd9, 0x8b, 0xb3, 0x9c, 0x13, 0xf0, 0xdb, 0x0a, 0x8c, 0x5e, 0x85, 0x39, 0x7e,
-	0xec, 0x09, 0xcc, 0xba, 0xa2, 0x89, 0xbd, 0x04, 0xe2, 0x25, 0x98, 0x7f, 0x32, 0x60, 0x81, 0xcf,
-	0xde, 0xb3, 0x42, 0xcb, 0xa6, 0x24, 0xae, 0xaa, 0xcc, 0x71, 0xf8, 0x7a, 0x0c, 0x46, 0x6f, 0xc1,
-	0xa2, 0x40, 0xc5, 0x91, 0x6d, 0x05, 0xf1, 0x0a, 0x1c, 0xca, 0x47, 0xf7, 0x29, 0x3e, 0x7b, 0x87,
-	0x4f, 0xae, 0xab, 0x39, 0xa4, 0x41, 0xc3, 0x26, 0xfd, 0x3e, 0xf6, 0x69, 0x24, 0x1b, 0xa5, 0xf1,
-	0x18, 0xdd, 0x84, 0x33, 0x96, 0xe7, 0x91, 0xaf, 0x4d, 0xbe, 0xd2, 0x31, 0x73, 0xd2, 0x89, 0x27,
-	0xb8, 0xc6, 0x91, 0x3e, 0xe3, 0x38, 0x46, 0x5a, 0x50, 0xed, 0x1c, 0x34, 0xe3, 0x73, 0x64, 0x29,
-	0x4f, 0xc2, 0x20, 0xf9, 0xb7, 0x76, 0x02, 0xda, 0xc9, 0x93, 0xd0, 0xfe, 0x5a, 0x87, 0x85, 0x02,
-	0xa7, 0x42, 0x8f, 0x01, 0x98, 0xb5, 0x0a, 0xd7, 0x92, 0xe6, 0xfa, 0xbf, 0x47, 0x77, 0x4e, 0x66,
-	0xaf, 0x02, 0x6c, 0x30, 0xeb, 0x17, 0x9f, 0xe8, 0x07, 0xd0, 0xe2, 0x16, 0x2b, 0xa9, 0x0b, 0x93,
-	0x7d, 0xff, 0x5f, 0xa0, 0xce, 0x64, 0x95, 0xe4, 0xb9, 0x0f, 0x88, 0x6f, 0xed, 0xcf, 0x35, 0x68,
-	0xc6, 0x8c, 0x59, 0x02, 0x27, 0x0e, 0x8a, 0x9f, 0x75, 0xa4, 0x12, 0x38, 0x0e, 0xdb, 0xe0, 0xa0,
-	0xff, 0x4a, 0x53, 0xd2, 0xde, 0x01, 0x18, 0xc9, 0x5f, 0x28, 0x42, 0xad, 0x50, 0x04, 0xfd, 0x12,
-	0x74, 0x98, 0x66, 0x5d, 0xec, 0x6c, 0xd3, 0xd0, 0x0d, 0xf8, 0x4f, 0x1a, 0x04, 0x4e, 0x24, 0x1f,
-	0xd2, 0x6a, 0xb8, 0xf6, 0xb7, 0x65, 0x68, 0x27, 0x6f, 0x52, 0xf4, 0x25, 0xb4, 0x12, 0x3f, 0xdd,
-	0x40, 0x2f, 0xe5, 0x0f, 0x2d, 0xff, 0xb3, 0x11, 0xed, 0xe5, 0x31, 0x58, 0xf2, 0xad, 0x39, 0x81,
-	0x0c, 0x98, 0x95, 0xed, 0x7e, 0xb4, 0x72, 0xc8, 0x2f, 0x01, 0x04, 0xd5, 0xf3, 0x63, 0x7f, 0x2b,
-	0xa0, 0x4f, 0x5c, 0xab, 0x21, 0x1f, 0x4e, 0xe6, 0xba, 0xef, 0xe8, 0x72, 0x7e, 0x6d, 0x59, 0x6f,
-	0x5f, 0x7b, 0xad, 0x12, 0x6e, 0x2c, 0x03, 0x85, 0x85, 0x82, 0x76, 0x3a, 0x7a, 0x7d, 0x0c, 0x95,
-	0x54, 0x4b, 0x5f, 0xbb, 0x52, 0x11, 0x3b, 0xe6, 0xfa, 0x04, 0x50, 0xbe, 0xd7, 0x8e, 0x5e, 0x1b,
-	0x4b, 0x66, 0xd4, 0xcb, 0xd7, 0x5e, 0xaf, 0x86, 0x5c, 0x2a, 0xa8, 0xe8, 0xb1, 0x8f, 0x15, 0x34,
-	0xd5, 0xc5, 0x1f, 0x2b, 0x68, 0xa6, 0x71, 0x3f, 0x81, 0xf6, 0x61, 0x3e, 0xdb, 0x7f, 0x47, 0x97,
-	0xca, 0x7e, 0x27, 0x94, 0x6b, 0xef, 0x6b, 0x97, 0xab, 0xa0, 0xc6, 0xcc, 0x30, 0x9c, 0x48, 0xf7,
-	0xbb, 0xd1, 0xab, 0xf9, 0xf5, 0x85, 0x1d, 0x7f, 0xed, 0xe2, 0x78, 0xc4, 0xa4, 0x4c, 0xd9, 0x1e,
-	0x78, 0x91, 0x4c, 0x25, 0x0d, 0xf6, 0x22, 0x99, 0xca, 0x5a, 0xea, 0xfa, 0x04, 0xfa, 0x46, 0x35,
-	0x56, 0x33, 0xbd, 0x61, 0xb4, 0x5a, 0x46, 0xa6, 0xb8, 0x39, 0xad, 0x5d, 0xad, 0x8c, 0x9f, 0xf0,
-	0xc6, 0x2f, 0xa1, 0x95, 0x68, 0x11, 0x17, 0xc5, 0x8f, 0x7c, 0xd3, 0xb9, 0x28, 0x7e, 0x14, 0xf5,
-	0x99, 0x27, 0xd0, 0x0e, 0x74, 0x52, 0x4d, 0x63, 0xf4, 0x4a, 0xd9, 0xca, 0x74, 0x6d, 0x55, 0x7b,
-	0x75, 0x2c, 0x5e, 0xcc, 0xc3, 0x54, 0x11, 0x51, 0x86, 0xc0, 0xd2, 0xcd, 0xa5, 0x63, 0xe0, 0x2b,
-	0xe3, 0xd0, 0x52, 0xae, 0x9c, 0x6b, 0x2d, 0x17, 0xba, 0x72, 0x59, 0xeb, 0xba, 0xd0, 0x95, 0xcb,
-	0xbb, 0xd5, 0x13, 0x68, 0x0f, 0xe6, 0x32, 0x6d, 0x65, 0x74, 0xb1, 0x8c, 0x44, 0xb6, 0xa5, 0xad,
-	0x5d, 0xaa, 0x80, 0x19, 0x73, 0xfa, 0x9e, 0xaa, 0x40, 0x70, 0x93, 0xbb, 0x50, 0xbe, 0x74, 0x64,
-	0x67, 0x2f, 0x1d, 0x8e, 0x14, 0x93, 0xfe, 0x1a, 0x4e, 0x15, 0x55, 0x1b, 0xd1, 0x95, 0xa2, 0xba,
-	0x46, 0x69, 0x49, 0x53, 0x5b, 0xad, 0x8a, 0x1e, 0x33, 0xfe, 0x1c, 0x1a, 0xaa, 0xb5, 0x8a, 0x0a,
-	0x2e, 0xa5, 0x4c, 0x33, 0x5a, 0xd3, 0x0f, 0x43, 0x49, 0xb8, 0x4a, 0x5f, 0x45, 0x85, 0x51, 0xcf,
-	0xb3, 0x3c, 0x2a, 0xe4, 0xba, 0xb3, 0xe5, 0x51, 0x21, 0xdf, 0x42, 0xe5, 0xec, 0x62, 0xb3, 0x4b,
-	0xb6, 0x08, 0xcb, 0xcd, 0xae, 0xa0, 0x03, 0x5a, 0x6e, 0x76, 0x85, 0x5d, 0xc7, 0x09, 0xf4, 0x43,
-	0xf5, 0x33, 0x89, 0x6c, 0x67, 0x10, 0x95, 0xc6, 0x96, 0x92, 0x0e, 0xa5, 0x76, 0xad, 0xfa, 0x82,
-	0x98, 0xfd, 0x33, 0x15, 0x09, 0x33, 0x9d, 0xc1, 0xf2, 0x48, 0x58, 0xdc, 0x9f, 0xd4, 0xae, 0x56,
-	0xc6, 0xcf, 0x3b, 0x79, 0xb2, 0x75, 0x56, 0xae, 0xed, 0x82, 0x6e, 0x63, 0xb9, 0xb6, 0x0b, 0xbb,
-	0x71, 0xdc, 0x3f, 0x8a, 0xda, 0x62, 0x45, 0xfe, 0x71, 0x48, 0xdf, 0x4e, 0x5b, 0xad, 0x8a, 0x9e,
-	0x4a, 0x14, 0xf2, 0x7d, 0x2f, 0x34, 0x76, 0xff, 0xa9, 0x3b, 0xe0, 0x4a, 0x45, 0xec, 0xf2, 0xd3,
-	0x55, 0x77, 0xc2, 0x58, 0x01, 0x32, 0x77, 0xc3, 0xd5, 0xca, 0xf8, 0x31, 0xef, 0x40, 0xfd, 0xe8,
-	0x26, 0xd1, 0xb3, 0x42, 0x97, 0xc7, 0xd0, 0x49, 0xf4, 0xdc, 0xb4, 0xd7, 0x2a, 0xe1, 0x16, 0x79,
-	0x6f, 0xb2, 0x8b, 0x74, 0x98, 0x3d, 0xe5, 0x5a, 0x5f, 0x87, 0xd9, 0x53, 0x41, 0x63, 0xaa, 0xc0,
-	0x7b, 0x55, 0xf3, 0x68, 0xbc, 0xf7, 0x66, 0x9a, 0x58, 0xe3, 0xbd, 0x37, 0xd7, 0x97, 0x9a, 0x40,
-	0x3f, 0x19, 0xfd, 0x18, 0x23, 0x5f, 0x83, 0x45, 0x6b, 0xa5, 0xa1, 0xa8, 0xb4, 0xf4, 0xac, 0xbd,
-	0x79, 0xa4, 0x35, 0x09, 0xe5, 0xff, 0xbc, 0xa6, 0x3a, 0xbb, 0x85, 0x45, 0x50, 0xf4, 0x56, 0x05,
-	0xc2, 0xb9, 0x3a, 0xae, 0xf6, 0xf6, 0x11, 0x57, 0x15, 0x59, 0x43, 0xb2, 0xfe, 0x59, 0x6e, 0x0d,
-	0x05, 0x35, 0xd4, 0x72, 0x6b, 0x28, 0x2a, 0xa9, 0xea, 0x13, 0xe8, 0x3e, 0x4c, 0xf3, 0xe7, 0x3a,
-	0x3a, 0x7b, 0xf8, 0x3b, 0x5e, 0x3b, 0x57, 0x3c, 0x1f, 0xbf, 0x46, 0x99, 0x00, 0x3b, 0x33, 0xfc,
-	0x7f, 0x09, 0x6f, 0xfe, 0x33, 0x00, 0x00, 0xff, 0xff, 0x4d, 0x3e, 0xd5, 0xcd, 0xae, 0x30, 0x00,
-	0x00,
+	// 3526 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xd4, 0x3b, 0xdd, 0x6f, 0xdc, 0xc6,
+	0xf1, 0xb9, 0x3b, 0x7d, 0xdc, 0xcd, 0xdd, 0x49, 0xf2, 0x4a, 0x96, 0xcf, 0x94, 0x64, 0xc9, 0x74,
+	0x9c, 0xc8, 0xb6, 0x2c, 0x3b, 0x4a, 0xf2, 0x8b, 0xe3, 0xfc, 0x92, 0xd6, 0x96, 0x3f, 0xe2, 0xc4,
+	0x96, 0x13, 0xca, 0x71, 0xda, 0x3a, 0x28, 0xc1, 0x23, 0xf7, 0x24, 0x46, 0x3c, 0x2e, 0x4d, 0xee,
+	0x29, 0x3e, 0xa3, 0x7d, 0x4a, 0x81, 0x16, 0x28, 0xda, 0x87, 0xa2, 0x2f, 0x7d, 0x68, 0x81, 0xa2,
+	0x7f, 0x44, 0xff, 0x85, 0xfc, 0x03, 0x01, 0x0a, 0xf4, 0xb1, 0xcf, 0x7d, 0xe8, 0x5b, 0x81, 0x3e,
+	0xb4, 0xd8, 0x0f, 0x7e, 0x1d, 0x49, 0x1d, 0x55, 0xbb, 0x28, 0xfa, 0x76, 0x9c, 0x9d, 0x9d, 0x99,
+	0x9d, 0x99, 0x9d, 0x9d, 0x9d, 0xd9, 0x83, 0xf9, 0x43, 0xe2, 0x0c, 0xfa, 0x58, 0x0f, 0xb0, 0x7f,
+	0x88, 0xfd, 0x4d, 0xcf, 0x27, 0x94, 0xa0, 0xb9, 0x14, 0x50, 0xf7, 0xba, 0xea, 0x13, 0x40, 0x37,
+	0x0d, 0x6a, 0xee, 0xdf, 0xc2, 0x0e, 0xa6, 0x58, 0xc3, 0x4f, 0x07, 0x38, 0xa0, 0xe8, 0x34, 0xd4,
+	0x7b, 0xb6, 0x83, 0x75, 0xdb, 0x0a, 0x3a, 0x95, 0xb5, 0xda, 0x7a, 0x43, 0x9b, 0x66, 0xdf, 0xf7,
+	0xac, 0x00, 0x5d, 0x84, 0x13, 0xc1, 0x81, 0xed, 0xe9, 0x26, 0x21, 0x07, 0x36, 0xd6, 0xcd, 0x7d,
+	0x6c, 0x1e, 0x74, 0xaa, 0x6b, 0x95, 0xf5, 0xba, 0x36, 0xcb, 0x06, 0xb6, 0x39, 0x7c, 0x9b, 0x81,
+	0xd5, 0x87, 0x30, 0x9f, 0x22, 0x1e, 0x78, 0xc4, 0x0d, 0x30, 0xba, 0x06, 0xd3, 0x3e, 0x0e, 0x06,
+	0x0e, 0x15, 0xc4, 0x9b, 0x5b, 0x67, 0x36, 0x47, 0xe5, 0xda, 0x8c, 0xa6, 0x0c, 0x1c, 0xaa, 0x85,
+	0xe8, 0xea, 0xd7, 0x15, 0x68, 0x25, 0x47, 0xd0, 0x29, 0x98, 0x96, 0x82, 0x76, 0x2a, 0x6b, 0x95,
+	0xf5, 0x86, 0x36, 0x25, 0xe4, 0x44, 0x8b, 0x30, 0x15, 0x50, 0x83, 0x0e, 0x02, 0x2e, 0xdb, 0xa4,
+	0x26, 0xbf, 0xd0, 0x02, 0x4c, 0x62, 0xdf, 0x27, 0x7e, 0xa7, 0xc6, 0xd1, 0xc5, 0x07, 0x42, 0x30,
+	0x11, 0xd8, 0xcf, 0x71, 0x67, 0x62, 0xad, 0xb2, 0xde, 0xd6, 0xf8, 0x6f, 0xd4, 0x81, 0xe9, 0x43,
+	0xec, 0x07, 0x36, 0x71, 0x3b, 0x93, 0x1c, 0x1c, 0x7e, 0xaa, 0x1f, 0xc1, 0xcc, 0x1d, 0xdb, 0xc1,
+	0x77, 0x31, 0x0d, 0xf5, 0x55, 0x28, 0xc6, 0x2a, 0x34, 0x0d, 0xd3, 0xc4, 0x1e, 0xd5, 0xf7, 0x9e,
+	0xdb, 0x9e, 0xd4, 0x13, 0x08, 0xd0, 0xdd, 0xe7, 0xb6, 0xa7, 0xfe, 0xb4, 0x06, 0xb3, 0x11, 0x31,
+	0xa9, 0x1f, 0x04, 0x13, 0x96, 0x41, 0x0d, 0x4e, 0xaa, 0xa5, 0xf1, 0xdf, 0xe8, 0x3c, 0xcc, 0x98,
+	0xc4, 0xa5, 0xd8, 0xa5, 0xba, 0x83, 0xdd, 0x3d, 0xba, 0xcf, 0x69, 0xb5, 0xb5, 0xb6, 0x84, 0xde,
+	0xe7, 0x40, 0x74, 0x16, 0x5a, 0x21, 0x1a, 0x1d, 0x7a, 0x58, 0xae, 0xb2, 0x29, 0x61, 0x8f, 0x86,
+	0x1e, 0x46, 0xe7, 0xa0, 0xed, 0x18, 0x01, 0xd5, 0xfb, 0xc4, 0xb2, 0x7b, 0x36, 0xb6, 0xf8, 0xa2,
+	0x27, 0xb4, 0x16, 0x03, 0x3e, 0x90, 0x30, 0xa4, 0x08, 0x07, 0x70, 0x8d, 0x3e, 0xe6, 0xab, 0x6f,
+	0x68, 0xd1, 0x37, 0x13, 0x0f, 0x53, 0x63, 0xaf, 0x33, 0xc5, 0xe1, 0xfc, 0x37, 0x5a, 0x01, 0xb0,
+	0x03, 0xbe, 0x46, 0x0f, 0x5b, 0x9d, 0x69, 0xbe, 0xcc, 0x86, 0x1d, 0xdc, 0x15, 0x00, 0xf4, 0x21,
+	0x4c, 0xef, 0x63, 0xc3, 0xc2, 0x7e, 0xd0, 0xa9, 0x73, 0x8b, 0x6f, 0x66, 0x2d, 0x3e, 0xa2, 0x85,
+	0xcd, 0x0f, 0xc5, 0x84, 0xdb, 0x2e, 0xf5, 0x87, 0x5a, 0x38, 0x1d, 0x2d, 0x43, 0x83, 0x9b, 0x6c,
+	0x9b, 0x58, 0xb8, 0xd3, 0xe0, 0xa6, 0x8d, 0x01, 0xca, 0x75, 0x68, 0x25, 0xa7, 0xa1, 0x39, 0xa8,
+	0x1d, 0xe0, 0xa1, 0xb4, 0x09, 0xfb, 0xc9, 0xec, 0x7f, 0x68, 0x38, 0x03, 0xcc, 0xd5, 0xd7, 0xd0,
+	0xc4, 0xc7, 0xf5, 0xea, 0xb5, 0x8a, 0xfa, 0x29, 0x9c, 0xd4, 0xb0, 0x61, 0xed, 0x60, 0x6c, 0x39,
+	0xf8, 0xa6, 0x43, 0xba, 0xa1, 0x71, 0x97, 0xa0, 0x21, 0x85, 0x95, 0xe6, 0x6d, 0x6b, 0x75, 0x01,
+	0xb8, 0x67, 0xb1, 0x41, 0x97, 0xcf, 0x60, 0x83, 0x55, 0xae, 0xc9, 0xba, 0x00, 0xdc, 0xb3, 0xd4,
+	0x3d, 0x58, 0x1c, 0x25, 0x29, 0x4d, 0xbc, 0x0a, 0x4d, 0x39, 0xad, 0xeb, 0x90, 0xae, 0xb4, 0x34,
+	0xb8, 0x11, 0x62, 0xe4, 0x91, 0xd5, 0x7c, 0x8f, 0xac, 0xa5, 0x3d, 0x72, 0x1a, 0x26, 0x6f, 0xf7,
+	0x3d, 0x3a, 0x54, 0xdf, 0x81, 0xce, 0x63, 0xc3, 0x1c, 0x0c, 0xfa, 0x8f, 0xb9, 0x80, 0x7c, 0x1b,
+	0x96, 0x59, 0x87, 0x8a, 0xe1, 0x74, 0xce, 0x44, 0x29, 0xed, 0x39, 0x68, 0xef, 0x19, 0x7e, 0xd7,
+	0xd8, 0xc3, 0xba, 0x6f, 0x50, 0x9b, 0xf0, 0xd9, 0x15, 0xad, 0x25, 0x81, 0x1a, 0x83, 0x25, 0x91,
+	0xba, 0x43, 0x8a, 0x03, 0xa9, 0x8d, 0x10, 0xe9, 0x26, 0x83, 0xa9, 0x4f, 0x40, 0x49, 0xb1, 0x21,
+	0x7d, 0xcf, 0x30, 0x69, 0x29, 0x4d, 0xaf, 0x41, 0xd3, 0xf3, 0xb1, 0xe1, 0x38, 0xc4, 0x34, 0xa8,
+	0x50, 0x4c, 0x4d, 0x4b, 0x82, 0xd4, 0x15, 0x58, 0xca, 0x25, 0x2e, 0x56, 0xa1, 0x5e, 0x1b, 0x59,
+	0x22, 0xe9, 0xf7, 0xed, 0x52, 0xac, 0xd5, 0x3d, 0x50, 0xf2, 0x66, 0x4a, 0xed, 0xac, 0x41, 0xcb,
+	0x0e, 0x74, 0x1f, 0x1b, 0x96, 0x4e, 0x5c, 0x47, 0x78, 0x5b, 0x5d, 0x03, 0x3b, 0x60, 0xb6, 0x7f,
+	0xe8, 0x3a, 0x43, 0xf4, 0x3a, 0xcc, 0xfa, 0xd8, 0x74, 0x0c, 0xbb, 0x8f, 0xad, 0x94, 0x72, 0x66,
+	0x22, 0xb0, 0x50, 0xcf, 0xbb, 0x23, 0x8c, 0x1c, 0x6c, 0xb8, 0x03, 0xaf, 0x94, 0x8c, 0xa3, 0x8b,
+	0x0f, 0xa7, 0xca, 0xc5, 0xbf, 0x0b, 0xa7, 0x44, 0xe0, 0xdc, 0x26, 0x8e, 0x83, 0x4d, 0x6a, 0x13,
+	0x37, 0x24, 0x7b, 0x06, 0xc0, 0x8c, 0x80, 0x72, 0xaf, 0x24, 0x20, 0xaa, 0x02, 0x9d, 0xec, 0x54,
+	0x49, 0xf6, 0x9f, 0x15, 0x38, 0x79, 0x43, 0xea, 0x5f, 0x30, 0x2e, 0x65, 0xcb, 0x34, 0xcb, 0xea,
+	0x28, 0xcb, 0x51, 0x5b, 0xd7, 0x32, 0xb6, 0x66, 0x18, 0x3e, 0xf6, 0x1c, 0xdb, 0x34, 0x38, 0x89,
+	0x09, 0x11, 0xe7, 0x12, 0x20, 0xb6, 0xf7, 0x29, 0x75, 0x64, 0xf4, 0x62, 0x3f, 0xd1, 0x16, 0x2c,
+	0xf6, 0x71, 0x9f, 0xf8, 0x43, 0xbd, 0x6f, 0x78, 0x7a, 0xdf, 0x78, 0xa6, 0xb3, 0x6d, 0xa5, 0xf7,
+	0xbb, 0x3c, 0x94, 0xb5, 0x35, 0x24, 0x46, 0x1f, 0x18, 0xde, 0x03, 0xe3, 0xd9, 0xae, 0xfd, 0x1c,
+	0x3f, 0xe8, 0xf2, 0x00, 0xee, 0x79, 0xd8, 0x95, 0xb6, 0x9d, 0x96, 0x01, 0x9c, 0x83, 0x98, 0x6d,
+	0xd5, 0x0e, 0x2c, 0x8e, 0x2a, 0x40, 0xea, 0xe6, 0xff, 0xe0, 0x94, 0x80, 0xec, 0x0e, 0x5d, 0x73,
+	0x97, 0x1f, 0x3f, 0xa5, 0x2c, 0xf9, 0x8f, 0x0a, 0x74, 0xb2, 0x13, 0xa5, 0xb3, 0xbd, 0xa8, 0x5a,
+	0x8f, 0xad, 0xb4, 0x55, 0x68, 0x52, 0xc3, 0x76, 0x74, 0xd2, 0xeb, 0x05, 0x98, 0x72, 0x4d, 0x4d,
+	0x68, 0xc0, 0x40, 0x0f, 0x39, 0x04, 0x5d, 0x80, 0x39, 0x53, 0xec, 0x34, 0xdd, 0xc7, 0x87, 0x36,
+	0x0f, 0x4f, 0xd3, 0x5c, 0xb0, 0x59, 0x33, 0xdc, 0x81, 0x02, 0x8c, 0x54, 0x68, 0xdb, 0xd6, 0x33,
+	0x9d, 0x1f, 0x95, 0x3c, 0xba, 0xd5, 0x39, 0xb5, 0xa6, 0x6d, 0x3d, 0x63, 0xd1, 0x9f, 0xa9, 0x5c,
+	0x7d, 0x0c, 0xcb, 0x62, 0xf1, 0xf7, 0x5c, 0xd3, 0xc7, 0x7d, 0xec, 0x52, 0xc3, 0xd9, 0x26, 0xde,
+	0xb0, 0x94, 0x5f, 0x9d, 0x86, 0x7a, 0x60, 0xbb, 0x26, 0xd6, 0xdd, 0x70, 0x87, 0x4d, 0xf3, 0xef,
+	0x9d, 0x40, 0xbd, 0x09, 0x2b, 0x05, 0x74, 0xa5, 0x66, 0xcf, 0x42, 0x8b, 0x0b, 0x26, 0xcf, 0x4a,
+	0x19, 0x93, 0x9b, 0x0c, 0xb6, 0x2d, 0x40, 0xea, 0x1b, 0x80, 0x04, 0x8d, 0x07, 0x64, 0xe0, 0x96,
+	0x0b, 0x1d, 0x27, 0x61, 0x3e, 0x35, 0x45, 0xfa, 0xc6, 0x9b, 0xb0, 0x20, 0xc0, 0x9f, 0xb9, 0xfd,
+	0xd2, 0xb4, 0x4e, 0xc1, 0xc9, 0x91, 0x49, 0x92, 0xda, 0x56, 0xc8, 0x24, 0x9d, 0xc5, 0x1d, 0x49,
+	0x6c, 0x11, 0x16, 0xd2, 0x73, 0x12, 0x51, 0x52, 0x08, 0x6c, 0xf8, 0x07, 0x2c, 0x82, 0x31, 0xbf,
+	0x2f, 0x45, 0x71, 0x19, 0x94, 0xbc, 0x99, 0x79, 0x74, 0x3f, 0xf7, 0x6d, 0x6a, 0x74, 0x1d, 0x7c,
+	0x7c, 0xba, 0xf1, 0x4c, 0x49, 0xf7, 0x7d, 0x58, 0x95, 0x9b, 0x85, 0x67, 0x12, 0x79, 0x52, 0x2b,
+	0x50, 0xf7, 0x25, 0x48, 0x06, 0xe7, 0xe8, 0x5b, 0x55, 0x61, 0xad, 0x78, 0xba, 0x64, 0xf1, 0x39,
+	0x2c, 0x86, 0x81, 0xdf, 0xed, 0xd9, 0x7b, 0x03, 0x1f, 0x97, 0x3d, 0xb0, 0x92, 0xbb, 0xad, 0x9a,
+	0xd9, 0x6d, 0xea, 0x15, 0x38, 0x95, 0x21, 0x2c, 0xbd, 0x31, 0xca, 0x53, 0x2b, 0x89, 0x3c, 0x55,
+	0xfd, 0x73, 0x05, 0x4e, 0x84, 0x33, 0x4a, 0x6e, 0x89, 0x63, 0xc6, 0x84, 0x5a, 0x61, 0x4c, 0x98,
+	0x88, 0x63, 0xc2, 0x3a, 0xcc, 0x05, 0x64, 0xe0, 0x9b, 0x58, 0x67, 0xb9, 0xa9, 0xee, 0xb2, 0x5c,
+	0x4c, 0x84, 0x8c, 0x19, 0x01, 0xbf, 0x65, 0x50, 0x63, 0x87, 0x58, 0x18, 0x5d, 0x02, 0x64, 0x13,
+	0x7e, 0xe4, 0xe9, 0x1e, 0xf6, 0xf5, 0x00, 0x9b, 0xc4, 0xb5, 0x78, 0x10, 0xa9, 0x69, 0xb3, 0x36,
+	0x61, 0xa7, 0xde, 0x27, 0xd8, 0xdf, 0xe5, 0x60, 0xf5, 0x3b, 0x80, 0x92, 0x8b, 0x93, 0x9a, 0xb8,
+	0x00, 0x27, 0x78, 0xbe, 0x2a, 0xc3, 0xb0, 0x41, 0xd9, 0xe6, 0xae, 0x88, 0xe3, 0x93, 0x0d, 0xdc,
+	0xe0, 0xf0, 0x1b, 0x74, 0x27, 0x50, 0x7f, 0x5d, 0x85, 0x59, 0x36, 0x97, 0x05, 0x93, 0x52, 0xca,
+	0x99, 0x83, 0x1a, 0x7e, 0x46, 0xa5, 0x56, 0xd8, 0x4f, 0x74, 0x05, 0xe6, 0x65, 0xd4, 0xb2, 0x89,
+	0x1b, 0x07, 0x34, 0x91, 0x6f, 0xa1, 0x78, 0x28, 0x8a, 0x69, 0xab, 0xd0, 0x0c, 0x28, 0xf1, 0xc2,
+	0xf8, 0x28, 0x92, 0x69, 0x60, 0x20, 0x19, 0x1f, 0xd3, 0x06, 0x98, 0xcc, 0x31, 0x00, 0x4b, 0x1f,
+	0xb0, 0xa9, 0x0b, 0xa9, 0x3a, 0x53, 0x61, 0xfa, 0x70, 0xdb, 0x14, 0xda, 0x40, 0x1f, 0xc0, 0xb2,
+	0xbd, 0xe7, 0x12, 0x1f, 0xeb, 0x52, 0xeb, 0x3c, 0x4e, 0xb9, 0x84, 0xea, 0x3d, 0x32, 0x70, 0xc3,
+	0x74, 0xbb, 0x23, 0x70, 0x76, 0x39, 0x0a, 0xd3, 0xc0, 0x0e, 0xa1, 0x77, 0xd8, 0xb8, 0xfa, 0x36,
+	0xcc, 0xc5, 0x5a, 0x29, 0x1f, 0xed, 0xbe, 0xae, 0x84, 0xee, 0xf9, 0xc8, 0xb0, 0x9d, 0x5d, 0xec,
+	0x5a, 0xd8, 0x7f, 0xc1, 0x28, 0x8c, 0xae, 0xc2, 0x82, 0x6d, 0x39, 0x58, 0xa7, 0x76, 0x1f, 0x93,
+	0x01, 0x95, 0x0e, 0x11, 0x84, 0xfa, 0x65, 0x63, 0x8f, 0xc4, 0x90, 0xf0, 0x89, 0x40, 0xfd, 0x49,
+	0x74, 0x1a, 0x26, 0xa5, 0x88, 0x13, 0x53, 0x99, 0x46, 0x8b, 0xfb, 0x81, 0x5c, 0x46, 0x4b, 0x00,
+	0xc5, 0x55, 0x20, 0x99, 0x6b, 0x13, 0x6b, 0xd8, 0xa9, 0xa6, 0x72, 0x6d, 0x62, 0x0d, 0xf9, 0xb1,
+	0x14, 0xe8, 0xdc, 0xc9, 0xcc, 0xfd, 0x81, 0x7b, 0xc0, 0xa5, 0xa9, 0x6b, 0x4d, 0x3b, 0xb8, 0x6f,
+	0x04, 0x74, 0x9b, 0x81, 0xd4, 0x3f, 0x56, 0xe0, 0x74, 0x2c, 0x86, 0x86, 0x4d, 0x6c, 0x1f, 0xfe,
+	0x17, 0xd4, 0xc1, 0x66, 0x48, 0x27, 0x48, 0x5d, 0xa0, 0xe4, 0xee, 0x44, 0x62, 0x2c, 0x19, 0xd1,
+	0xe2, 0xf0, 0x99, 0x16, 0x5c, 0xc6, 0xb6, 0x2f, 0xc2, 0x63, 0xf1, 0xb6, 0xb9, 0xbb, 0x6f, 0xf8,
+	0x56, 0x70, 0x17, 0xbb, 0xd8, 0x37, 0xe8, 0x4b, 0xc9, 0xe3, 0xd4, 0x35, 0x38, 0x53, 0x44, 0x5d,
+	0xf2, 0x7f, 0x02, 0xcb, 0x69, 0x0c, 0x0d, 0x77, 0x07, 0xb6, 0x63, 0xbd, 0x14, 0xf6, 0x1f, 0xc3,
+	0x4a, 0x01, 0x71, 0xe9, 0x3f, 0x17, 0xe1, 0x84, 0xcf, 0x41, 0x54, 0x0f, 0x18, 0x42, 0x54, 0xf0,
+	0x68, 0x6b, 0xb3, 0x72, 0x80, 0x4f, 0xbc, 0x67, 0x05, 0xea, 0xcf, 0xab, 0x70, 0x3a, 0x4d, 0xed,
+	0xa5, 0xc5, 0xe0, 0x25, 0x68, 0xc4, 0xec, 0x6b, 0x9c, 0x7d, 0x3d, 0x90, 0x7c, 0x99, 0x77, 0x9a,
+	0xc4, 0x1b, 0xea, 0xd8, 0x14, 0x99, 0x13, 0x37, 0x75, 0x9d, 0xdd, 0xe9, 0xbd, 0xe1, 0x6d, 0x93,
+	0x27, 0x4e, 0xc7, 0x08, 0xc8, 0x31, 0xb5, 0x2f, 0x05, 0xb5, 0xa9, 0x24, 0xb5, 0x2f, 0x39, 0xb5,
+	0x10, 0xe7, 0xd0, 0xee, 0x09, 0x9c, 0xe9, 0x18, 0xe7, 0xb1, 0xdd, 0x63, 0x38, 0xb1, 0x57, 0xa5,
+	0x95, 0x21, 0xad, 0xfa, 0x15, 0x2c, 0xa5, 0x47, 0xcb, 0x27, 0x26, 0x2f, 0xa4, 0x2c, 0xf5, 0x0c,
+	0x2c, 0xe7, 0x33, 0x96, 0x82, 0x1d, 0x8e, 0x8a, 0x5d, 0x3a, 0x93, 0x7b, 0x31, 0xb9, 0x56, 0x60,
+	0x29, 0x97, 0xaf, 0x14, 0xeb, 0x7b, 0xa3, 0x62, 0x1f, 0x23, 0x2d, 0x3c, 0x9a, 0xf1, 0x2a, 0xac,
+	0x14, 0x50, 0x96, 0xac, 0x7f, 0x13, 0xc5, 0x57, 0x89, 0xc1, 0xd2, 0x9f, 0xd2, 0x71, 0x4d, 0xf2,
+	0x95, 0x65, 0x8a, 0x69, 0xc9, 0x96, 0x55, 0xdf, 0xe4, 0x79, 0x28, 0xae, 0x6e, 0xf2, 0x2b, 0x55,
+	0x67, 0xab, 0xc9, 0xaa, 0x46, 0x58, 0x6b, 0x64, 0x85, 0x9a, 0x49, 0x11, 0x1e, 0xd9, 0xf7, 0xc7,
+	0x78, 0xa8, 0xee, 0xc0, 0xe9, 0x1c, 0xd1, 0x8e, 0xa8, 0x92, 0x89, 0x32, 0x94, 0xc5, 0x6d, 0x6e,
+	0xc9, 0x6a, 0x5b, 0xc3, 0x96, 0x4e, 0x60, 0xa9, 0xbf, 0xa8, 0xc4, 0x04, 0x59, 0x95, 0xe5, 0x25,
+	0x7a, 0x65, 0x72, 0x15, 0xb5, 0xd4, 0x2a, 0x92, 0x65, 0x9b, 0x89, 0x74, 0xd9, 0x26, 0xb1, 0x89,
+	0x92, 0xe2, 0x14, 0x85, 0xe6, 0x47, 0xe4, 0xe5, 0x5d, 0xb1, 0xb3, 0xa1, 0x39, 0xa6, 0x2e, 0xf9,
+	0x5f, 0x87, 0x25, 0xa6, 0x70, 0x01, 0xe5, 0xf7, 0xb3, 0xf2, 0x77, 0xd8, 0xbf, 0x56, 0x61, 0x39,
+	0x7f, 0x72, 0x99, 0x7b, 0xec, 0x7b, 0xa0, 0x44, 0xf7, 0x44, 0x76, 0x34, 0x06, 0xd4, 0xe8, 0x7b,
+	0xd1, 0xe1, 0x28, 0xce, 0xd0, 0x53, 0xf2, 0xd2, 0xf8, 0x28, 0x1c, 0x0f, 0x4f, 0xc8, 0xcc, 0x25,
+	0xb3, 0x96, 0xb9, 0x64, 0x32, 0x06, 0x96, 0x41, 0x8b, 0x18, 0x88, 0x1c, 0xee, 0x94, 0x65, 0xd0,
+	0x22, 0x06, 0xd1, 0x64, 0xce, 0x40, 0x78, 0x6d, 0x53, 0xe2, 0x73, 0x06, 0x2b, 0x00, 0x32, 0xbd,
+	0x1a, 0xb8, 0xe1, 0xa5, 0xb9, 0x21, 0x92, 0xab, 0x81, 0x5b, 0x98, 0x65, 0x4e, 0x17, 0x66, 0x99,
+	0x69, 0x6b, 0xd6, 0x33, 0xd6, 0xfc, 0x5d, 0x05, 0xe0, 0x96, 0x1d, 0x1c, 0x08, 0x2d, 0xb3, 0xbc,
+	0xd6, 0xb2, 0xc3, 0xbb, 0x03, 0xfb, 0xc9, 0x20, 0x86, 0xe3, 0x48, 0xdd, 0xb1, 0x9f, 0x6c, 0xff,
+	0x0c, 0x02, 0x6c, 0x49, 0xf5, 0xf0, 0xdf, 0x0c, 0xd6, 0xf3, 0x31, 0x96, 0x1a, 0xe0, 0xbf, 0x59,
+	0xa6, 0xe8, 0x61, 0xdf, 0xc4, 0x2e, 0xd5, 0xf9, 0x18, 0x5b, 0x6d, 0x55, 0x6b, 0x4a, 0xd8, 0x9d,
+	0x11, 0x14, 0x4e, 0x72, 0x2a, 0x85, 0xf2, 0x59, 0x80, 0x2d, 0xf5, 0x0f, 0x15, 0x68, 0x3c, 0xc0,
+	0x7d, 0x29, 0xdf, 0x19, 0x80, 0x3d, 0xe2, 0x93, 0x01, 0xb5, 0x5d, 0x2c, 0x92, 0xf9, 0x49, 0x2d,
+	0x01, 0x79, 0x01, 0x69, 0x59, 0x84, 0xc1, 0x4e, 0x4f, 0xda, 0x84, 0xff, 0x66, 0xb0, 0x7d, 0x6c,
+	0x78, 0xd2, 0x0c, 0xfc, 0x37, 0xbb, 0x5f, 0x05, 0xd4, 0x30, 0x0f, 0xb8, 0xce, 0x27, 0x34, 0xf1,
+	0xa1, 0xfe, 0xa9, 0x02, 0xa0, 0xe1, 0x3e, 0xa1, 0xdc, 0x65, 0xd9, 0xba, 0xba, 0x86, 0x79, 0xc0,
+	0xae, 0x1d, 0xbc, 0x9a, 0x2e, 0xf4, 0xd9, 0x94, 0x30, 0x5e, 0x4d, 0x5f, 0x01, 0x08, 0x51, 0x64,
+	0x18, 0x6c, 0x68, 0x0d, 0x09, 0x11, 0x17, 0x8c, 0x30, 0x22, 0xc8, 0x02, 0x74, 0x1c, 0x1a, 0x85,
+	0xd8, 0xf2, 0x8b, 0x6d, 0x88, 0x51, 0x8f, 0xaa, 0xf7, 0x42, 0x77, 0x3a, 0x07, 0xed, 0xb0, 0x5c,
+	0xcf, 0xfd, 0x55, 0x2e, 0xa5, 0x15, 0x02, 0x99, 0x8f, 0xf2, 0xd2, 0xf8, 0x33, 0x8a, 0xdd, 0xc8,
+	0x95, 0x1a, 0x5a, 0x0c, 0x50, 0x7f, 0x5b, 0x01, 0x08, 0x0b, 0x20, 0x3d, 0x82, 0xb6, 0x60, 0x92,
+	0x51, 0x0f, 0x3b, 0x30, 0xcb, 0xd9, 0x7a, 0x7c, 0xac, 0x07, 0x4d, 0xa0, 0x26, 0x03, 0x59, 0x35,
+	0x15, 0xc8, 0x4a, 0x5c, 0x22, 0x47, 0xea, 0x68, 0x13, 0x99, 0x3a, 0xda, 0x37, 0x95, 0xf0, 0x26,
+	0xfe, 0xc8, 0xc6, 0xfe, 0x03, 0x72, 0xc8, 0x72, 0x96, 0x47, 0x44, 0x48, 0xf1, 0x52, 0x42, 0xf4,
+	0x35, 0xe8, 0x58, 0x38, 0xa0, 0xb6, 0xcb, 0x25, 0xd2, 0x43, 0xb3, 0xf1, 0x1e, 0x87, 0x90, 0x78,
+	0x31, 0x31, 0x7e, 0x53, 0x0c, 0xef, 0xb0, 0x8e, 0xc7, 0x65, 0x98, 0x3f, 0xc0, 0xd8, 0xd3, 0x1d,
+	0x62, 0x1a, 0x8e, 0x1e, 0x6e, 0x7e, 0xb9, 0x88, 0x39, 0x36, 0x74, 0x9f, 0x8d, 0xdc, 0x12, 0x01,
+	0x40, 0x0d, 0xe0, 0xec, 0x11, 0x2b, 0x91, 0x01, 0x70, 0x19, 0x1a, 0x9e, 0x4f, 0x4c, 0x1c, 0x04,
+	0x58, 0x2c, 0xa5, 0xa6, 0xc5, 0x00, 0x74, 0x15, 0xe6, 0xa3, 0x8f, 0x4f, 0xc4, 0x36, 0x32, 0xf6,
+	0x44, 0xd1, 0xbb, 0xaa, 0xe5, 0x0d, 0xa9, 0xbf, 0xaa, 0x80, 0x9a, 0xe1, 0x7a, 0xc7, 0x27, 0xfd,
+	0x97, 0xa8, 0xc1, 0x2b, 0xb0, 0xc0, 0xf5, 0xe0, 0x73, 0x92, 0xb1, 0x22, 0xc4, 0x7d, 0xe9, 0x04,
+	0x1b, 0x13, 0xdc, 0x42, 0x4d, 0x0c, 0xe0, 0xdc, 0x91, 0x32, 0xfd, 0x87, 0x74, 0xb1, 0x04, 0xa7,
+	0x93, 0x57, 0xa0, 0xd4, 0xb9, 0xa5, 0xfe, 0xbe, 0x02, 0x4a, 0xde, 0xa8, 0x94, 0xe5, 0x06, 0xb4,
+	0x2d, 0x3b, 0x38, 0xd0, 0x45, 0xbf, 0xf0, 0xa8, 0x0d, 0x12, 0xc7, 0x5b, 0xad, 0x65, 0x45, 0xbf,
+	0x71, 0x80, 0xbe, 0x0b, 0x6d, 0x59, 0x67, 0x4e, 0xb4, 0x20, 0x9b, 0x5b, 0x4b, 0x59, 0x12, 0x51,
+	0x44, 0xd4, 0x5a, 0x62, 0x86, 0xf8, 0x52, 0xff, 0xde, 0x82, 0xd6, 0xa7, 0x03, 0xec, 0x0f, 0x13,
+	0x35, 0xfa, 0x00, 0x4b, 0x33, 0x84, 0x2d, 0xd9, 0x04, 0x84, 0x9d, 0x49, 0x3d, 0x9f, 0xf4, 0xf5,
+	0xa8, 0x6b, 0x5b, 0xe5, 0x28, 0x4d, 0x06, 0xbc, 0x23, 0x3b, 0xb7, 0xef, 0x03, 0xeb, 0x4a, 0x52,
+	0x2c, 0x7a, 0x9f, 0xcd, 0xad, 0xf3, 0x59, 0x79, 0x92, 0x3c, 0x59, 0x43, 0x8e, 0x62, 0x5f, 0x93,
+	0x93, 0x50, 0x17, 0xe6, 0x6d, 0xd7, 0xe3, 0x97, 0x54, 0xdf, 0x36, 0x1c, 0xfb, 0x79, 0x5c, 0x44,
+	0x6e, 0x6e, 0xbd, 0x31, 0x86, 0xd6, 0x3d, 0x36, 0x73, 0x37, 0x39, 0x51, 0x43, 0x76, 0x06, 0x86,
+	0x30, 0x2c, 0x90, 0x01, 0xcd, 0x32, 0x99, 0xe4, 0x4c, 0xb6, 0xc6, 0x30, 0x79, 0x38, 0xa0, 0xa3,
+	0x14, 0xb5, 0x79, 0x92, 0x05, 0x2a, 0x3b, 0x30, 0x25, 0x16, 0xc7, 0x8e, 0x81, 0x9e, 0x8d, 0x9d,
+	0xb0, 0x6d, 0x2b, 0x3e, 0x58, 0xa0, 0x23, 0x1e, 0xf6, 0x0d, 0x37, 0x8c, 0xe8, 0xe1, 0x67, 0xdc,
+	0x3e, 0xac, 0x25, 0xda, 0x87, 0xca, 0xb7, 0x93, 0x80, 0xb2, 0x2b, 0x0c, 0x2b, 0xe3, 0x3e, 0x0e,
+	0x58, 0x90, 0x4c, 0x1e, 0x21, 0xb3, 0x09, 0x38, 0x3f, 0x46, 0x3e, 0x87, 0x86, 0x19, 0x1c, 0xea,
+	0x5c, 0x25, 0xd2, 0x5d, 0xae, 0x1f, 0x5b, 0xa5, 0x9b, 0xdb, 0xbb, 0x8f, 0x39, 0x54, 0xab, 0x9b,
+	0xc1, 0x21, 0xff, 0x85, 0x7e, 0x00, 0xf0, 0x65, 0x40, 0x5c, 0x49, 0x59, 0x18, 0xfe, 0xbd, 0xe3,
+	0x53, 0xfe, 0x68, 0xf7, 0xe1, 0x8e, 0x20, 0xdd, 0x60, 0xe4, 0x04, 0x6d, 0x13, 0xda, 0x9e, 0xe1,
+	0x3f, 0x1d, 0x60, 0x2a, 0xc9, 0x0b, 0x5f, 0xf8, 0xe0, 0xf8, 0xe4, 0x3f, 0x11, 0x64, 0x04, 0x87,
+	0x96, 0x97, 0xf8, 0x52, 0xbe, 0xa9, 0x42, 0x3d, 0x5c, 0x17, 0xbb, 0xe7, 0x72, 0x0f, 0x17, 0xd5,
+	0x1e, 0xdd, 0x76, 0x7b, 0x44, 0x6a, 0x74, 0xa6, 0x67, 0x87, 0x05, 0x1f, 0x7e, 0xbe, 0x5d, 0x80,
+	0x39, 0x1f, 0x9b, 0xc4, 0xb7, 0x74, 0x0b, 0x3b, 0x76, 0xdf, 0x66, 0x6e, 0x2f, 0x6c, 0x39, 0x2b,
+	0xe0, 0xb7, 0x42, 0x30, 0xeb, 0xce, 0x71, 0xb3, 0x27, 0x30, 0x6b, 0x21, 0x4d, 0xec, 0x24, 0x10,
+	0x2f, 0xc0, 0xdc, 0xd3, 0x01, 0x0b, 0x7c, 0xe6, 0xbe, 0xe1, 0x1b, 0x26, 0x25, 0x51, 0xdd, 0x65,
+	0x96, 0xc3, 0xb7, 0x23, 0x30, 0x7a, 0x0b, 0x16, 0x05, 0x2a, 0x0e, 0x4c, 0xc3, 0x8b, 0x66, 0x60,
+	0x5f, 0x5e, 0xcb, 0x17, 0xf8, 0xe8, 0x6d, 0x3e, 0xb8, 0x1d, 0x8e, 0xb1, 0x42, 0xb5, 0x49, 0xfa,
+	0x7d, 0xec, 0xd2, 0x40, 0x76, 0xd7, 0xa3, 0x6f, 0x74, 0x03, 0x56, 0x0c, 0xc7, 0x21, 0x5f, 0xe9,
+	0x7c, 0xa6, 0xa5, 0x67, 0x56, 0x27, 0x2e, 0xe9, 0x0a, 0x47, 0xfa, 0x94, 0xe3, 0x68, 0xe9, 0x85,
+	0x2a, 0xab, 0xd0, 0x88, 0xec, 0xc8, 0x92, 0xa2, 0x84, 0x43, 0xf2, 0xdf, 0xca, 0x0c, 0xb4, 0x92,
+	0x96, 0x50, 0xfe, 0x56, 0x83, 0xf9, 0x9c, 0x4d, 0x85, 0x9e, 0x00, 0x30, 0x6f, 0x15, 0x5b, 0x4b,
+	0xba, 0xeb, 0xff, 0x1f, 0x7f, 0x73, 0x32, 0x7f, 0x15, 0x60, 0x8d, 0x79, 0xbf, 0xf8, 0x89, 0x7e,
+	0x08, 0x4d, 0xee, 0xb1, 0x92, 0xba, 0x70, 0xd9, 0xf7, 0xff, 0x0d, 0xea, 0x6c, 0xad, 0x92, 0x3c,
+	0xdf, 0x03, 0xe2, 0xb7, 0xf2, 0x97, 0x0a, 0x34, 0x22, 0xc6, 0x2c, 0xc5, 0x13, 0x86, 0xe2, 0xb6,
+	0x0e, 0xc2, 0x14, 0x8f, 0xc3, 0xee, 0x70, 0xd0, 0xff, 0xa4, 0x2b, 0x29, 0xef, 0x00, 0xc4, 0xeb,
+	0xcf, 0x5d, 0x42, 0x25, 0x77, 0x09, 0xea, 0x05, 0x68, 0x33, 0xcd, 0xda, 0xd8, 0xda, 0xa5, 0xbe,
+	0xed, 0xf1, 0x57, 0x07, 0x02, 0x27, 0x90, 0x57, 0xed, 0xf0, 0x73, 0xeb, 0xdb, 0x55, 0x68, 0x25,
+	0x4f, 0x52, 0xf4, 0x05, 0x34, 0x13, 0xef, 0x7d, 0xd0, 0xab, 0x59, 0xa3, 0x65, 0xdf, 0x1a, 0x29,
+	0xe7, 0xc7, 0x60, 0xc9, 0xdb, 0xe8, 0x2b, 0x48, 0x83, 0x69, 0xf9, 0x46, 0x04, 0xad, 0x1d, 0xf1,
+	0x7c, 0x44, 0x50, 0x3d, 0x3b, 0xf6, 0x81, 0x89, 0xfa, 0xca, 0xd5, 0x0a, 0xc2, 0x30, 0x93, 0x7e,
+	0xa1, 0x81, 0x5e, 0xcf, 0xcb, 0x84, 0x73, 0x9e, 0x85, 0x28, 0xeb, 0xe3, 0x11, 0x23, 0xd1, 0x5d,
+	0x38, 0x91, 0x79, 0x5d, 0x81, 0x2e, 0x66, 0x09, 0x14, 0xbd, 0xdd, 0x50, 0x2e, 0x95, 0xc2, 0x8d,
+	0xf8, 0x51, 0x98, 0xcf, 0x79, 0x09, 0x81, 0x36, 0xc6, 0x50, 0x49, 0xbd, 0xc6, 0x50, 0x2e, 0x97,
+	0xc4, 0x8e, 0xb8, 0x3e, 0x05, 0x94, 0x7d, 0x26, 0x81, 0x2e, 0x8d, 0x25, 0x13, 0x3f, 0xc3, 0x50,
+	0x36, 0xca, 0x21, 0x17, 0x2e, 0x54, 0xbc, 0x7a, 0x18, 0xbb, 0xd0, 0xd4, 0xbb, 0x0a, 0xe5, 0x72,
+	0x49, 0xec, 0x88, 0xeb, 0x01, 0xcc, 0x8d, 0xbe, 0x88, 0x40, 0x17, 0x8a, 0xde, 0xb0, 0x65, 0x1e,
+	0x5c, 0x28, 0x17, 0xcb, 0xa0, 0x46, 0xcc, 0x30, 0xcc, 0xa4, 0x1f, 0x18, 0xe4, 0xb9, 0x68, 0xee,
+	0x1b, 0x0c, 0x65, 0x7d, 0x3c, 0x62, 0x72, 0x4d, 0xa3, 0x8f, 0x0e, 0xf2, 0xd6, 0x54, 0xf0, 0xa2,
+	0x41, 0xb9, 0x58, 0x06, 0x35, 0x62, 0xf6, 0x23, 0x38, 0x99, 0xdb, 0x8c, 0x47, 0x9b, 0x45, 0x64,
+	0xf2, 0x5f, 0x03, 0x28, 0x57, 0x4a, 0xe3, 0x27, 0x36, 0xfd, 0x17, 0xd0, 0x4c, 0xf4, 0xe4, 0xf3,
+	0xc2, 0x54, 0xb6, 0xcb, 0xaf, 0x9c, 0x1f, 0x83, 0x15, 0xad, 0xad, 0x0b, 0xed, 0x54, 0x97, 0x1e,
+	0xbd, 0x56, 0x34, 0x33, 0x5d, 0xe4, 0x55, 0x5e, 0x1f, 0x8b, 0x17, 0xf1, 0xd0, 0xc3, 0xc0, 0x2b,
+	0x23, 0x6d, 0xa1, 0x70, 0xe9, 0x50, 0xfb, 0xda, 0x38, 0xb4, 0xd4, 0x56, 0xce, 0xf4, 0xf2, 0x73,
+	0xb7, 0x72, 0xd1, 0x5b, 0x01, 0x65, 0xa3, 0x1c, 0x72, 0x3e, 0xcb, 0xb0, 0xcd, 0x7f, 0x34, 0xcb,
+	0x91, 0x67, 0x04, 0xca, 0x46, 0x39, 0xe4, 0x88, 0xe5, 0xd7, 0xf1, 0x4b, 0x9b, 0x4c, 0xf7, 0x1f,
+	0xbd, 0x51, 0xe8, 0xd1, 0x45, 0x0f, 0x0d, 0x94, 0xad, 0xe3, 0x4c, 0x89, 0xa4, 0xd8, 0x87, 0xd9,
+	0x91, 0x57, 0x00, 0x68, 0xbd, 0x88, 0xd0, 0xe8, 0x0b, 0x04, 0xe5, 0x42, 0x09, 0xcc, 0x88, 0xd3,
+	0xf7, 0xc3, 0x12, 0x10, 0xdf, 0x6b, 0xe7, 0x8a, 0xa7, 0xc6, 0x1b, 0xec, 0xd5, 0xa3, 0x91, 0x22,
+	0xd2, 0x5f, 0xc1, 0x42, 0x5e, 0xbd, 0x17, 0x5d, 0xce, 0x3f, 0x25, 0x0b, 0x8a, 0xca, 0xca, 0x66,
+	0x59, 0xf4, 0x88, 0xf1, 0x67, 0x50, 0x0f, 0x9b, 0xdb, 0x28, 0xe7, 0xd0, 0x1f, 0x79, 0x0e, 0xa0,
+	0xa8, 0x47, 0xa1, 0x24, 0x62, 0x44, 0x1f, 0xe6, 0xe2, 0xae, 0xa9, 0xe8, 0x3a, 0x17, 0x87, 0xc3,
+	0x4c, 0x7f, 0x5c, 0xb9, 0x58, 0x06, 0x35, 0xc1, 0x2e, 0x72, 0xfe, 0x64, 0x93, 0xb6, 0xd8, 0xf9,
+	0x73, 0x7a, 0xd0, 0xca, 0x46, 0x39, 0xe4, 0x48, 0x71, 0x3f, 0x86, 0xc5, 0x74, 0x03, 0x20, 0xec,
+	0xcd, 0xa2, 0xc2, 0xa0, 0x5a, 0xd0, 0x23, 0x56, 0xae, 0x96, 0x9f, 0x10, 0xb1, 0x7f, 0x0e, 0x27,
+	0xd3, 0x38, 0xb2, 0x37, 0x5b, 0x7c, 0x04, 0xe4, 0x77, 0x88, 0x95, 0x2b, 0xa5, 0xf1, 0xb3, 0xa1,
+	0x26, 0xd9, 0xbc, 0x2c, 0xd6, 0x76, 0x4e, 0xbf, 0x57, 0xd9, 0x28, 0x87, 0x9c, 0xdc, 0x1f, 0x79,
+	0x8d, 0xc9, 0xbc, 0xfd, 0x71, 0x44, 0xe7, 0x54, 0xd9, 0x2c, 0x8b, 0x9e, 0xca, 0x90, 0xb2, 0x9d,
+	0x47, 0x34, 0x56, 0xfe, 0xd4, 0xe1, 0x77, 0xb9, 0x24, 0x76, 0xb1, 0x75, 0xc3, 0xc3, 0x70, 0xec,
+	0x02, 0x46, 0x0e, 0xc5, 0x2b, 0xa5, 0xf1, 0x23, 0xde, 0x1e, 0x9c, 0x48, 0xa1, 0xb0, 0x00, 0x82,
+	0x2e, 0x8e, 0xa1, 0x93, 0xe8, 0x7a, 0x2a, 0x97, 0x4a, 0xe1, 0xe6, 0xed, 0xde, 0x64, 0x1f, 0xef,
+	0x28, 0x7f, 0xca, 0x34, 0x1f, 0x95, 0x8d, 0x72, 0xc8, 0xc5, 0xbb, 0x37, 0x6c, 0xdf, 0x8d, 0xdf,
+	0xbd, 0x23, 0x6d, 0x44, 0xe5, 0x6a, 0xf9, 0x09, 0x11, 0xfb, 0x9f, 0xc5, 0xcf, 0x61, 0xb2, 0x35,
+	0x6e, 0x54, 0x78, 0x0e, 0x16, 0x97, 0xf6, 0x95, 0x37, 0x8f, 0x35, 0x27, 0xa1, 0xfc, 0x5f, 0x56,
+	0x60, 0x29, 0x83, 0x19, 0x17, 0x99, 0xd1, 0x5b, 0x25, 0x08, 0x67, 0xea, 0xe4, 0xca, 0xdb, 0xc7,
+	0x9c, 0x95, 0xe7, 0x0d, 0xc9, 0xfa, 0x72, 0xb1, 0x37, 0xe4, 0xd4, 0xa8, 0x95, 0x8d, 0x72, 0xc8,
+	0x91, 0x39, 0xee, 0xc3, 0x24, 0x2f, 0x87, 0xa0, 0x33, 0x47, 0xd7, 0x49, 0x94, 0xd5, 0xfc, 0xf1,
+	0xe8, 0xb6, 0xcf, 0x16, 0xd0, 0x9d, 0xe2, 0x7f, 0x16, 0x7a, 0xf3, 0x5f, 0x03, 0x00, 0x73, 0xfd,
+	0x2f, 0x48, 0x43, 0x34, 0x00, 0x00,
 }
@@ -0,0 +1,150 @@
+package csv
+
+import (
+	encoding_csv "encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chrislusf/seaweedfs/weed/query/sql"
+	"github.com/chrislusf/seaweedfs/weed/query/sqltypes"
+)
+
+const (
+	FileHeaderNone   = "NONE"
+	FileHeaderUse    = "USE"
+	FileHeaderIgnore = "IGNORE"
+)
+
+type ReaderOptions struct {
+	FileHeaderInfo  string // NONE | USE | IGNORE, default NONE
+	RecordDelimiter string // default \n
+	FieldDelimiter  string // default ,
+	QuoteCharacter  string // default "
+	Comments        string // default #
+}
+
+// Reader reads CSV records one line at a time
+type Reader struct {
+	reader     *encoding_csv.Reader
+	headerInfo string
+	header     []string
+}
+
+func NewReader(r io.Reader, options ReaderOptions) (*Reader, error) {
+
+	reader := encoding_csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	if d := options.RecordDelimiter; d != "" && d != "\n" && d != "\r\n" {
+		return nil, fmt.Errorf("unsupported record delimiter %q", d)
+	}
+	if q := options.QuoteCharacter; q != "" && q != `"` {
+		return nil, fmt.Errorf("unsupported quote character %q", q)
+	}
+	if d := options.FieldDelimiter; d != "" {
+		if utf8.RuneCountInString(d) != 1 {
+			return nil, fmt.Errorf("unsupported field delimiter %q", d)
+		}
+		reader.Comma, _ = utf8.DecodeRuneInString(d)
+	}
+	reader.Comment = '#'
+	if c := options.Comments; c != "" {
+		if utf8.RuneCountInString(c) != 1 {
+			return nil, fmt.Errorf("unsupported comment character %q", c)
+		}
+		reader.Comment, _ = utf8.DecodeRuneInString(c)
+	}
+	if reader.Comment == reader.Comma {
+		return nil, fmt.Errorf("comment character is the same as the field delimiter")
+	}
+
+	headerInfo := strings.ToUpper(options.FileHeaderInfo)
+	switch headerInfo {
+	case "":
+		headerInfo = FileHeaderNone
+	case FileHeaderNone, FileHeaderUse, FileHeaderIgnore:
+	default:
+		return nil, fmt.Errorf("unsupported file header info %q", options.FileHeaderInfo)
+	}
+
+	return &Reader{
+		reader:     reader,
+		headerInfo: headerInfo,
+	}, nil
+}
+
+func (r *Reader) Read() (sql.Record, error) {
+
+	if r.headerInfo != FileHeaderNone {
+		header, err := r.reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		if r.headerInfo == FileHeaderUse {
+			r.header = header
+		}
+		r.headerInfo = FileHeaderNone
+	}
+
+	fields, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{header: r.header, fields: fields}, nil
+}
+
+// Record is one CSV line, with columns addressed by the header names, or by position as "_1", "_2", ...
+type Record struct {
+	header []string
+	fields []string
+}
+
+func (r *Record) Get(path []string) sqltypes.Value {
+
+	if len(path) != 1 {
+		return sqltypes.NULL
+	}
+	name := path[0]
+
+	if strings.HasPrefix(name, "_") {
+		if i, err := strconv.Atoi(name[1:]); err == nil {
+			if i < 1 || i > len(r.fields) {
+				return sqltypes.NULL
+			}
+			return sqltypes.NewVarChar(r.fields[i-1])
+		}
+	}
+
+	i := -1
+	for j, h := range r.header {
+		if h == name {
+			i = j
+			break
+		}
+		if i < 0 && strings.EqualFold(h, name) {
+			i = j
+		}
+	}
+	if i < 0 || i >= len(r.fields) {
+		return sqltypes.NULL
+	}
+
+	return sqltypes.NewVarChar(r.fields[i])
+}
+
+func (r *Record) Columns() (names []string, values []sqltypes.Value) {
+	for i, field := range r.fields {
+		if i < len(r.header) {
+			names = append(names, r.header[i])
+		} else {
+			names = append(names, fmt.Sprintf("_%d", i+1))
+		}
+		values = append(values, sqltypes.NewVarChar(field))
+	}
+	return
+}
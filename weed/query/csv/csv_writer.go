@@ -0,0 +1,90 @@
+package csv
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/query/sqltypes"
+)
+
+const (
+	QuoteFieldsAlways   = "ALWAYS"
+	QuoteFieldsAsNeeded = "ASNEEDED"
+)
+
+type WriterOptions struct {
+	QuoteFields          string // ALWAYS | ASNEEDED, default ASNEEDED
+	RecordDelimiter      string // default \n
+	FieldDelimiter       string // default ,
+	QuoteCharacter       string // default "
+	QuoteEscapeCharacter string // default "
+}
+
+// Writer serializes rows as CSV lines
+type Writer struct {
+	quoteAlways     bool
+	recordDelimiter string
+	fieldDelimiter  string
+	quote           string
+	quoteEscape     string
+}
+
+func NewWriter(options WriterOptions) (*Writer, error) {
+
+	w := &Writer{
+		recordDelimiter: "\n",
+		fieldDelimiter:  ",",
+		quote:           `"`,
+		quoteEscape:     `"`,
+	}
+
+	switch strings.ToUpper(options.QuoteFields) {
+	case "", QuoteFieldsAsNeeded:
+	case QuoteFieldsAlways:
+		w.quoteAlways = true
+	default:
+		return nil, fmt.Errorf("unsupported quote fields %q", options.QuoteFields)
+	}
+	if options.RecordDelimiter != "" {
+		w.recordDelimiter = options.RecordDelimiter
+	}
+	if options.FieldDelimiter != "" {
+		w.fieldDelimiter = options.FieldDelimiter
+	}
+	if options.QuoteCharacter != "" {
+		w.quote = options.QuoteCharacter
+	}
+	if options.QuoteEscapeCharacter != "" {
+		w.quoteEscape = options.QuoteEscapeCharacter
+	}
+
+	return w, nil
+}
+
+func (w *Writer) Write(buf *bytes.Buffer, names []string, values []sqltypes.Value) {
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteString(w.fieldDelimiter)
+		}
+		field := ""
+		if !v.IsNull() {
+			field = v.ToString()
+		}
+		if !w.quoteAlways && !w.needsQuote(field) {
+			buf.WriteString(field)
+			continue
+		}
+		buf.WriteString(w.quote)
+		buf.WriteString(strings.Replace(field, w.quote, w.quoteEscape+w.quote, -1))
+		buf.WriteString(w.quote)
+	}
+	buf.WriteString(w.recordDelimiter)
+}
+
+func (w *Writer) needsQuote(field string) bool {
+	return strings.Contains(field, w.fieldDelimiter) ||
+		strings.Contains(field, w.quote) ||
+		strings.Contains(field, w.recordDelimiter) ||
+		strings.ContainsAny(field, "\r\n")
+}
@@ -0,0 +1,143 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	encoding_json "encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/chrislusf/seaweedfs/weed/query/sql"
+	"github.com/chrislusf/seaweedfs/weed/query/sqltypes"
+)
+
+const (
+	TypeDocument = "DOCUMENT"
+	TypeLines    = "LINES"
+)
+
+// Reader reads json values one at a time, from either a document of concatenated values or json lines
+type Reader struct {
+	decoder *encoding_json.Decoder
+}
+
+func NewReader(r io.Reader, typ string) (*Reader, error) {
+	switch strings.ToUpper(typ) {
+	case TypeDocument, TypeLines:
+	default:
+		return nil, fmt.Errorf("unsupported json type %q", typ)
+	}
+	return &Reader{
+		decoder: encoding_json.NewDecoder(bufio.NewReader(r)),
+	}, nil
+}
+
+func (r *Reader) Read() (sql.Record, error) {
+	var raw encoding_json.RawMessage
+	if err := r.decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return &Record{raw: string(raw)}, nil
+}
+
+// Record is one json value, with columns addressed by the field path
+type Record struct {
+	raw string
+}
+
+func (r *Record) Get(path []string) sqltypes.Value {
+	var escaped []string
+	for _, p := range path {
+		escaped = append(escaped, escapePath(p))
+	}
+	return toValue(gjson.Get(r.raw, strings.Join(escaped, ".")))
+}
+
+func (r *Record) Columns() (names []string, values []sqltypes.Value) {
+	result := gjson.Parse(r.raw)
+	if !result.IsObject() {
+		return []string{"_1"}, []sqltypes.Value{toValue(result)}
+	}
+	result.ForEach(func(key, value gjson.Result) bool {
+		names = append(names, key.String())
+		values = append(values, toValue(value))
+		return true
+	})
+	return
+}
+
+func toValue(result gjson.Result) sqltypes.Value {
+	switch result.Type {
+	case gjson.String:
+		return sqltypes.NewVarChar(result.Str)
+	case gjson.Number:
+		if result.Raw != "" && !strings.ContainsAny(result.Raw, ".eE") {
+			if v, err := sqltypes.NewIntegral(result.Raw); err == nil {
+				return v
+			}
+		}
+		return sqltypes.NewFloat64(result.Num)
+	case gjson.True, gjson.False, gjson.JSON:
+		return sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(result.Raw))
+	}
+	return sqltypes.NULL
+}
+
+// escapePath escapes the gjson path syntax in one field name
+func escapePath(field string) string {
+	if !strings.ContainsAny(field, `.*?|#@\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if strings.IndexByte(`.*?|#@\`, field[i]) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
+
+// Writer serializes rows as json objects
+type Writer struct {
+	recordDelimiter string
+}
+
+func NewWriter(recordDelimiter string) *Writer {
+	if recordDelimiter == "" {
+		recordDelimiter = "\n"
+	}
+	return &Writer{recordDelimiter: recordDelimiter}
+}
+
+func (w *Writer) Write(buf *bytes.Buffer, names []string, values []sqltypes.Value) {
+	buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, names[i])
+		buf.WriteByte(':')
+		switch {
+		case v.IsNull():
+			buf.WriteString("null")
+		case v.IsText() || v.IsBinary():
+			writeString(buf, v.ToString())
+		default:
+			buf.Write(v.Raw())
+		}
+	}
+	buf.WriteByte('}')
+	buf.WriteString(w.recordDelimiter)
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	encoder := encoding_json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	// Encode appends a new line
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,582 @@
+package sql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chrislusf/seaweedfs/weed/query/sqltypes"
+)
+
+var (
+	trueValue  = sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte("true"))
+	falseValue = sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte("false"))
+
+	castTypes = map[string]bool{
+		"INT": true, "INTEGER": true, "FLOAT": true, "DECIMAL": true, "NUMERIC": true,
+		"STRING": true, "VARCHAR": true, "CHAR": true, "BOOL": true, "BOOLEAN": true,
+	}
+)
+
+// boolean values are kept as json true and false, which is also how they are serialized
+func boolValue(b bool) sqltypes.Value {
+	if b {
+		return trueValue
+	}
+	return falseValue
+}
+
+// toBool returns the truth of a value, or isNull for the unknown truth of NULL
+func toBool(v sqltypes.Value) (b, isNull bool, err error) {
+	if v.IsNull() {
+		return false, true, nil
+	}
+	if v.Type() == sqltypes.TypeJSON || v.IsText() {
+		switch strings.ToLower(v.ToString()) {
+		case "true":
+			return true, false, nil
+		case "false":
+			return false, false, nil
+		}
+	}
+	return false, false, fmt.Errorf("%s is not a boolean", v.ToString())
+}
+
+// number is an integer or a float value used in arithmetic and comparisons
+type number struct {
+	isFloat bool
+	i       int64
+	f       float64
+}
+
+func (n number) float() float64 {
+	if n.isFloat {
+		return n.f
+	}
+	return float64(n.i)
+}
+
+func (n number) value() sqltypes.Value {
+	if n.isFloat {
+		return sqltypes.NewFloat64(n.f)
+	}
+	return sqltypes.NewInt64(n.i)
+}
+
+// toNumber converts numeric values, and text holding a number, as CSV fields are always text
+func toNumber(v sqltypes.Value) (n number, ok bool) {
+	switch {
+	case v.IsIntegral():
+		i, err := v.ParseInt64()
+		return number{i: i}, err == nil
+	case v.IsFloat() || v.Type() == sqltypes.Decimal:
+		f, err := v.ParseFloat64()
+		return number{isFloat: true, f: f}, err == nil
+	case v.IsText():
+		s := strings.TrimSpace(v.ToString())
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return number{i: i}, true
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return number{isFloat: true, f: f}, true
+		}
+	}
+	return number{}, false
+}
+
+func isNumeric(v sqltypes.Value) bool {
+	return v.IsIntegral() || v.IsFloat() || v.Type() == sqltypes.Decimal
+}
+
+// compare returns -1, 0, or 1, with comparable false when the values can not be compared
+func compare(a, b sqltypes.Value) (result int, comparable bool) {
+
+	if isNumeric(a) || isNumeric(b) {
+		x, okx := toNumber(a)
+		y, oky := toNumber(b)
+		if !okx || !oky {
+			return 0, false
+		}
+		if !x.isFloat && !y.isFloat {
+			return compareInt(x.i, y.i), true
+		}
+		return compareFloat(x.float(), y.float()), true
+	}
+
+	if a.Type() == sqltypes.TypeJSON || b.Type() == sqltypes.TypeJSON {
+		x, _, errx := toBool(a)
+		y, _, erry := toBool(b)
+		if errx != nil || erry != nil {
+			return strings.Compare(a.ToString(), b.ToString()), a.Type() == b.Type()
+		}
+		return compareInt(boolToInt(x), boolToInt(y)), true
+	}
+
+	return strings.Compare(a.ToString(), b.ToString()), true
+}
+
+func compareInt(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// evaluator evaluates expressions against one record, or against the aggregated results
+type evaluator struct {
+	record     Record
+	aggregated []sqltypes.Value
+}
+
+func (ev *evaluator) eval(e expr) (sqltypes.Value, error) {
+
+	switch e := e.(type) {
+	case *literalExpr:
+		return e.value, nil
+	case *columnExpr:
+		if ev.record == nil {
+			return sqltypes.NULL, fmt.Errorf("column %s used outside of a record", strings.Join(e.path, "."))
+		}
+		return ev.record.Get(e.path), nil
+	case *aggregateExpr:
+		if ev.aggregated == nil {
+			return sqltypes.NULL, fmt.Errorf("aggregate function %s used outside of projections", e.name)
+		}
+		return ev.aggregated[e.index], nil
+	case *unaryExpr:
+		return ev.evalUnary(e)
+	case *binaryExpr:
+		return ev.evalBinary(e)
+	case *isNullExpr:
+		v, err := ev.eval(e.expr)
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		return boolValue(v.IsNull() != e.not), nil
+	case *likeExpr:
+		return ev.evalLike(e)
+	case *betweenExpr:
+		return ev.evalBetween(e)
+	case *inExpr:
+		return ev.evalIn(e)
+	case *castExpr:
+		v, err := ev.eval(e.expr)
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		return cast(v, e.typ)
+	case *functionExpr:
+		return ev.evalFunction(e)
+	}
+
+	return sqltypes.NULL, fmt.Errorf("unsupported expression %T", e)
+}
+
+func (ev *evaluator) evalUnary(e *unaryExpr) (sqltypes.Value, error) {
+	v, err := ev.eval(e.expr)
+	if err != nil || v.IsNull() {
+		return sqltypes.NULL, err
+	}
+	switch e.op {
+	case "NOT":
+		b, _, err := toBool(v)
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		return boolValue(!b), nil
+	case "-":
+		n, ok := toNumber(v)
+		if !ok {
+			return sqltypes.NULL, fmt.Errorf("%s is not a number", v.ToString())
+		}
+		n.i, n.f = -n.i, -n.f
+		return n.value(), nil
+	}
+	return sqltypes.NULL, fmt.Errorf("unsupported operator %s", e.op)
+}
+
+func (ev *evaluator) evalBinary(e *binaryExpr) (sqltypes.Value, error) {
+
+	if e.op == "AND" || e.op == "OR" {
+		return ev.evalLogical(e)
+	}
+
+	left, err := ev.eval(e.left)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	right, err := ev.eval(e.right)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	if left.IsNull() || right.IsNull() {
+		return sqltypes.NULL, nil
+	}
+
+	switch e.op {
+	case "=", "!=", "<", "<=", ">", ">=":
+		c, comparable := compare(left, right)
+		if !comparable {
+			return sqltypes.NULL, nil
+		}
+		switch e.op {
+		case "=":
+			return boolValue(c == 0), nil
+		case "!=":
+			return boolValue(c != 0), nil
+		case "<":
+			return boolValue(c < 0), nil
+		case "<=":
+			return boolValue(c <= 0), nil
+		case ">":
+			return boolValue(c > 0), nil
+		default:
+			return boolValue(c >= 0), nil
+		}
+	case "||":
+		return sqltypes.NewVarChar(left.ToString() + right.ToString()), nil
+	}
+
+	x, okx := toNumber(left)
+	y, oky := toNumber(right)
+	if !okx || !oky {
+		return sqltypes.NULL, fmt.Errorf("arithmetic on non-numeric values %s %s %s", left.ToString(), e.op, right.ToString())
+	}
+	return arithmetic(e.op, x, y)
+}
+
+func arithmetic(op string, x, y number) (sqltypes.Value, error) {
+
+	if !x.isFloat && !y.isFloat {
+		switch op {
+		case "+":
+			return sqltypes.NewInt64(x.i + y.i), nil
+		case "-":
+			return sqltypes.NewInt64(x.i - y.i), nil
+		case "*":
+			return sqltypes.NewInt64(x.i * y.i), nil
+		case "/", "%":
+			if y.i == 0 {
+				return sqltypes.NULL, fmt.Errorf("division by zero")
+			}
+			if op == "/" {
+				return sqltypes.NewInt64(x.i / y.i), nil
+			}
+			return sqltypes.NewInt64(x.i % y.i), nil
+		}
+	}
+
+	a, b := x.float(), y.float()
+	switch op {
+	case "+":
+		return sqltypes.NewFloat64(a + b), nil
+	case "-":
+		return sqltypes.NewFloat64(a - b), nil
+	case "*":
+		return sqltypes.NewFloat64(a * b), nil
+	case "/", "%":
+		if b == 0 {
+			return sqltypes.NULL, fmt.Errorf("division by zero")
+		}
+		if op == "/" {
+			return sqltypes.NewFloat64(a / b), nil
+		}
+		return sqltypes.NewFloat64(math.Mod(a, b)), nil
+	}
+
+	return sqltypes.NULL, fmt.Errorf("unsupported operator %s", op)
+}
+
+// evalLogical follows the three valued logic of SQL, where NULL is unknown
+func (ev *evaluator) evalLogical(e *binaryExpr) (sqltypes.Value, error) {
+
+	left, err := ev.eval(e.left)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	l, lNull, err := toBool(left)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	if !lNull && l == (e.op == "OR") {
+		return boolValue(l), nil
+	}
+
+	right, err := ev.eval(e.right)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	r, rNull, err := toBool(right)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	if !rNull && r == (e.op == "OR") {
+		return boolValue(r), nil
+	}
+	if lNull || rNull {
+		return sqltypes.NULL, nil
+	}
+
+	return boolValue(r), nil
+}
+
+func (ev *evaluator) evalLike(e *likeExpr) (sqltypes.Value, error) {
+
+	v, err := ev.eval(e.expr)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	pattern, err := ev.eval(e.pattern)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	var escape byte
+	if e.escape != nil {
+		escapeValue, err := ev.eval(e.escape)
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		if escapeValue.Len() != 1 {
+			return sqltypes.NULL, fmt.Errorf("LIKE escape should be a single character")
+		}
+		escape = escapeValue.Raw()[0]
+	}
+	if v.IsNull() || pattern.IsNull() {
+		return sqltypes.NULL, nil
+	}
+
+	return boolValue(like(v.ToString(), pattern.ToString(), escape) != e.not), nil
+}
+
+// like matches s against the pattern, where % matches any characters and _ matches one character
+func like(s, pattern string, escape byte) bool {
+
+	for len(pattern) > 0 {
+		c := pattern[0]
+		switch {
+		case escape != 0 && c == escape && len(pattern) > 1:
+			if len(s) == 0 || s[0] != pattern[1] {
+				return false
+			}
+			s, pattern = s[1:], pattern[2:]
+		case c == '%':
+			for len(pattern) > 0 && pattern[0] == '%' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if like(s[i:], pattern, escape) {
+					return true
+				}
+			}
+			return false
+		case c == '_':
+			if len(s) == 0 {
+				return false
+			}
+			_, size := utf8.DecodeRuneInString(s)
+			s, pattern = s[size:], pattern[1:]
+		default:
+			if len(s) == 0 || s[0] != c {
+				return false
+			}
+			s, pattern = s[1:], pattern[1:]
+		}
+	}
+
+	return len(s) == 0
+}
+
+func (ev *evaluator) evalBetween(e *betweenExpr) (sqltypes.Value, error) {
+
+	v, err := ev.eval(e.expr)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	low, err := ev.eval(e.low)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	high, err := ev.eval(e.high)
+	if err != nil {
+		return sqltypes.NULL, err
+	}
+	if v.IsNull() || low.IsNull() || high.IsNull() {
+		return sqltypes.NULL, nil
+	}
+
+	c1, ok1 := compare(v, low)
+	c2, ok2 := compare(v, high)
+	if !ok1 || !ok2 {
+		return sqltypes.NULL, nil
+	}
+
+	return boolValue((c1 >= 0 && c2 <= 0) != e.not), nil
+}
+
+func (ev *evaluator) evalIn(e *inExpr) (sqltypes.Value, error) {
+
+	v, err := ev.eval(e.expr)
+	if err != nil || v.IsNull() {
+		return sqltypes.NULL, err
+	}
+
+	hasNull := false
+	for _, item := range e.list {
+		x, err := ev.eval(item)
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		if x.IsNull() {
+			hasNull = true
+			continue
+		}
+		if c, comparable := compare(v, x); comparable && c == 0 {
+			return boolValue(!e.not), nil
+		}
+	}
+	if hasNull {
+		return sqltypes.NULL, nil
+	}
+
+	return boolValue(e.not), nil
+}
+
+func cast(v sqltypes.Value, typ string) (sqltypes.Value, error) {
+
+	if v.IsNull() {
+		return v, nil
+	}
+
+	switch typ {
+	case "INT", "INTEGER":
+		n, ok := toNumber(v)
+		if !ok {
+			return sqltypes.NULL, fmt.Errorf("can not cast %s to %s", v.ToString(), typ)
+		}
+		if n.isFloat {
+			return sqltypes.NewInt64(int64(n.f)), nil
+		}
+		return n.value(), nil
+	case "FLOAT", "DECIMAL", "NUMERIC":
+		n, ok := toNumber(v)
+		if !ok {
+			return sqltypes.NULL, fmt.Errorf("can not cast %s to %s", v.ToString(), typ)
+		}
+		return sqltypes.NewFloat64(n.float()), nil
+	case "BOOL", "BOOLEAN":
+		b, _, err := toBool(v)
+		if err != nil {
+			if n, ok := toNumber(v); ok {
+				return boolValue(n.float() != 0), nil
+			}
+			return sqltypes.NULL, fmt.Errorf("can not cast %s to %s", v.ToString(), typ)
+		}
+		return boolValue(b), nil
+	}
+
+	return sqltypes.NewVarChar(v.ToString()), nil
+}
+
+func (ev *evaluator) evalFunction(e *functionExpr) (sqltypes.Value, error) {
+
+	args := make([]sqltypes.Value, len(e.args))
+	for i, arg := range e.args {
+		v, err := ev.eval(arg)
+		if err != nil {
+			return sqltypes.NULL, err
+		}
+		args[i] = v
+	}
+
+	switch e.name {
+	case "COALESCE":
+		for _, v := range args {
+			if !v.IsNull() {
+				return v, nil
+			}
+		}
+		return sqltypes.NULL, nil
+	case "NULLIF":
+		if c, comparable := compare(args[0], args[1]); !args[0].IsNull() && !args[1].IsNull() && comparable && c == 0 {
+			return sqltypes.NULL, nil
+		}
+		return args[0], nil
+	}
+
+	if args[0].IsNull() {
+		return sqltypes.NULL, nil
+	}
+	s := args[0].ToString()
+
+	switch e.name {
+	case "LOWER":
+		return sqltypes.NewVarChar(strings.ToLower(s)), nil
+	case "UPPER":
+		return sqltypes.NewVarChar(strings.ToUpper(s)), nil
+	case "TRIM":
+		return sqltypes.NewVarChar(strings.TrimSpace(s)), nil
+	case "CHAR_LENGTH", "CHARACTER_LENGTH":
+		return sqltypes.NewInt64(int64(len([]rune(s)))), nil
+	case "SUBSTRING":
+		return substring(s, args[1:])
+	}
+
+	return sqltypes.NULL, fmt.Errorf("unsupported function %s", e.name)
+}
+
+// substring follows SQL, where the start position counts from 1
+func substring(s string, args []sqltypes.Value) (sqltypes.Value, error) {
+
+	runes := []rune(s)
+
+	start, ok := toNumber(args[0])
+	if !ok || start.isFloat {
+		return sqltypes.NULL, fmt.Errorf("SUBSTRING start should be an integer")
+	}
+	end := int64(len(runes)) + 1
+	if len(args) > 1 {
+		length, ok := toNumber(args[1])
+		if !ok || length.isFloat || length.i < 0 {
+			return sqltypes.NULL, fmt.Errorf("SUBSTRING length should be a non-negative integer")
+		}
+		end = start.i + length.i
+	}
+
+	from := start.i
+	if from < 1 {
+		from = 1
+	}
+	if end > int64(len(runes))+1 {
+		end = int64(len(runes)) + 1
+	}
+	if from >= end {
+		return sqltypes.NewVarChar(""), nil
+	}
+
+	return sqltypes.NewVarChar(string(runes[from-1 : end-1])), nil
+}
@@ -0,0 +1,131 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdentifier
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	typ  tokenType
+	text string
+	pos  int
+}
+
+// isKeyword tells whether the token is the unquoted keyword, case insensitively
+func (t token) isKeyword(keyword string) bool {
+	return t.typ == tokenIdentifier && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.typ == tokenSymbol && t.text == symbol
+}
+
+func (t token) String() string {
+	if t.typ == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.pos)
+}
+
+func tokenize(expression string) (tokens []token, err error) {
+
+	for pos := 0; pos < len(expression); {
+		c := expression[pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+		case isIdentifierStart(c):
+			start := pos
+			for pos < len(expression) && isIdentifierPart(expression[pos]) {
+				pos++
+			}
+			tokens = append(tokens, token{typ: tokenIdentifier, text: expression[start:pos], pos: start})
+		case isDigit(c) || (c == '.' && pos+1 < len(expression) && isDigit(expression[pos+1])):
+			start := pos
+			for pos < len(expression) && isDigit(expression[pos]) {
+				pos++
+			}
+			if pos < len(expression) && expression[pos] == '.' {
+				pos++
+				for pos < len(expression) && isDigit(expression[pos]) {
+					pos++
+				}
+			}
+			if pos < len(expression) && (expression[pos] == 'e' || expression[pos] == 'E') {
+				pos++
+				if pos < len(expression) && (expression[pos] == '+' || expression[pos] == '-') {
+					pos++
+				}
+				for pos < len(expression) && isDigit(expression[pos]) {
+					pos++
+				}
+			}
+			tokens = append(tokens, token{typ: tokenNumber, text: expression[start:pos], pos: start})
+		case c == '\'' || c == '"':
+			// strings are single quoted, identifiers are double quoted, and the quote is escaped by doubling it
+			start := pos
+			var text strings.Builder
+			pos++
+			for {
+				if pos >= len(expression) {
+					return nil, fmt.Errorf("unterminated quote at position %d", start)
+				}
+				if expression[pos] == c {
+					if pos+1 < len(expression) && expression[pos+1] == c {
+						text.WriteByte(c)
+						pos += 2
+						continue
+					}
+					pos++
+					break
+				}
+				text.WriteByte(expression[pos])
+				pos++
+			}
+			typ := tokenString
+			if c == '"' {
+				typ = tokenQuotedIdentifier
+			}
+			tokens = append(tokens, token{typ: typ, text: text.String(), pos: start})
+		default:
+			symbol := string(c)
+			if pos+1 < len(expression) {
+				switch two := expression[pos : pos+2]; two {
+				case "<=", ">=", "<>", "!=", "||":
+					symbol = two
+				}
+			}
+			if !strings.Contains("<=>!|(),.*+-/%[]", symbol[:1]) || symbol == "!" || symbol == "|" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, pos)
+			}
+			tokens = append(tokens, token{typ: tokenSymbol, text: symbol, pos: pos})
+			pos += len(symbol)
+		}
+	}
+
+	tokens = append(tokens, token{typ: tokenEOF, pos: len(expression)})
+	return tokens, nil
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,655 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/query/sqltypes"
+)
+
+// expression nodes
+
+type expr interface{}
+
+type literalExpr struct {
+	value sqltypes.Value
+}
+
+type columnExpr struct {
+	path []string
+}
+
+type unaryExpr struct {
+	op   string
+	expr expr
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+type likeExpr struct {
+	expr, pattern, escape expr
+	not                   bool
+}
+
+type betweenExpr struct {
+	expr, low, high expr
+	not             bool
+}
+
+type inExpr struct {
+	expr expr
+	list []expr
+	not  bool
+}
+
+type isNullExpr struct {
+	expr expr
+	not  bool
+}
+
+type castExpr struct {
+	expr expr
+	typ  string
+}
+
+type functionExpr struct {
+	name string
+	args []expr
+}
+
+type aggregateExpr struct {
+	name  string
+	arg   expr // nil for COUNT(*)
+	index int
+}
+
+type projection struct {
+	expr expr
+	name string
+}
+
+// Select is a parsed "SELECT ... FROM S3Object [alias] [WHERE ...] [LIMIT ...]" statement
+type Select struct {
+	projections []projection // empty for SELECT *
+	aggregates  []*aggregateExpr
+	where       expr
+	limit       int64 // -1 for no limit
+}
+
+// Parse parses the S3 Select SQL expression
+func Parse(expression string) (*Select, error) {
+
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	s, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+type parser struct {
+	tokens      []token
+	pos         int
+	aggregates  []*aggregateExpr
+	inAggregate bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) acceptKeyword(keyword string) bool {
+	if p.peek().isKeyword(keyword) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	if p.peek().isSymbol(symbol) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return fmt.Errorf("expecting %s, found %v", keyword, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return fmt.Errorf("expecting %q, found %v", symbol, p.peek())
+	}
+	return nil
+}
+
+var reservedWords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true, "AS": true,
+	"AND": true, "OR": true, "NOT": true, "LIKE": true, "ESCAPE": true, "BETWEEN": true,
+	"IN": true, "IS": true, "NULL": true, "TRUE": true, "FALSE": true, "CAST": true,
+}
+
+func (p *parser) parseSelect() (s *Select, err error) {
+
+	if err = p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	s = &Select{limit: -1}
+
+	if !p.acceptSymbol("*") {
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			proj := projection{expr: e}
+			if p.acceptKeyword("AS") {
+				if proj.name, err = p.parseName(); err != nil {
+					return nil, err
+				}
+			} else if t := p.peek(); t.typ == tokenQuotedIdentifier || (t.typ == tokenIdentifier && !reservedWords[strings.ToUpper(t.text)]) {
+				proj.name, _ = p.parseName()
+			}
+			s.projections = append(s.projections, proj)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if err = p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	if t := p.next(); !t.isKeyword("S3Object") {
+		return nil, fmt.Errorf("expecting S3Object, found %v", t)
+	}
+	if t := p.peek(); t.isSymbol("[") || t.isSymbol(".") {
+		return nil, fmt.Errorf("unsupported path in FROM clause at position %d", t.pos)
+	}
+	var alias string
+	if p.acceptKeyword("AS") {
+		if alias, err = p.parseName(); err != nil {
+			return nil, err
+		}
+	} else if t := p.peek(); t.typ == tokenQuotedIdentifier || (t.typ == tokenIdentifier && !reservedWords[strings.ToUpper(t.text)]) {
+		alias, _ = p.parseName()
+	}
+
+	if p.acceptKeyword("WHERE") {
+		projectionAggregates := len(p.aggregates)
+		if s.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		if len(p.aggregates) > projectionAggregates {
+			return nil, fmt.Errorf("aggregate functions are not allowed in WHERE clause")
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		t := p.next()
+		if t.typ != tokenNumber {
+			return nil, fmt.Errorf("expecting a number after LIMIT, found %v", t)
+		}
+		if s.limit, err = strconv.ParseInt(t.text, 10, 64); err != nil || s.limit < 0 {
+			return nil, fmt.Errorf("invalid LIMIT %s", t.text)
+		}
+	}
+
+	if t := p.peek(); t.typ != tokenEOF {
+		return nil, fmt.Errorf("unexpected %v", t)
+	}
+
+	s.aggregates = p.aggregates
+	if len(s.aggregates) > 0 {
+		for _, proj := range s.projections {
+			if hasColumnOutsideAggregate(proj.expr) {
+				return nil, fmt.Errorf("projections mixing aggregate and non-aggregate values are not supported")
+			}
+		}
+	}
+
+	// column references may start with the table alias or S3Object
+	for i := range s.projections {
+		stripAlias(s.projections[i].expr, alias)
+	}
+	stripAlias(s.where, alias)
+
+	// unnamed projections are named after the column, or by their position
+	for i := range s.projections {
+		if s.projections[i].name != "" {
+			continue
+		}
+		if c, ok := s.projections[i].expr.(*columnExpr); ok {
+			s.projections[i].name = c.path[len(c.path)-1]
+		} else {
+			s.projections[i].name = fmt.Sprintf("_%d", i+1)
+		}
+	}
+
+	return s, nil
+}
+
+func (p *parser) parseName() (string, error) {
+	t := p.next()
+	if t.typ != tokenIdentifier && t.typ != tokenQuotedIdentifier {
+		return "", fmt.Errorf("expecting a name, found %v", t)
+	}
+	return t.text, nil
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.acceptKeyword("NOT") {
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "NOT", expr: e}, nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *parser) parsePredicate() (expr, error) {
+
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.typ == tokenSymbol {
+		switch t.text {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			op := t.text
+			if op == "<>" {
+				op = "!="
+			}
+			return &binaryExpr{op: op, left: left, right: right}, nil
+		}
+	}
+
+	if p.acceptKeyword("IS") {
+		not := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{expr: left, not: not}, nil
+	}
+
+	not := p.acceptKeyword("NOT")
+	switch {
+	case p.acceptKeyword("LIKE"):
+		pattern, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		e := &likeExpr{expr: left, pattern: pattern, not: not}
+		if p.acceptKeyword("ESCAPE") {
+			if e.escape, err = p.parseAdditive(); err != nil {
+				return nil, err
+			}
+		}
+		return e, nil
+	case p.acceptKeyword("BETWEEN"):
+		low, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &betweenExpr{expr: left, low: low, high: high, not: not}, nil
+	case p.acceptKeyword("IN"):
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		e := &inExpr{expr: left, not: not}
+		for {
+			item, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			e.list = append(e.list, item)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	if not {
+		return nil, fmt.Errorf("expecting LIKE, BETWEEN or IN after NOT, found %v", p.peek())
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !t.isSymbol("+") && !t.isSymbol("-") && !t.isSymbol("||") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !t.isSymbol("*") && !t.isSymbol("/") && !t.isSymbol("%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.acceptSymbol("-") {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "-", expr: e}, nil
+	}
+	if p.acceptSymbol("+") {
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+var aggregateFunctions = map[string]bool{"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true}
+
+var scalarFunctions = map[string]int{
+	// name: minimum number of arguments
+	"LOWER": 1, "UPPER": 1, "TRIM": 1, "CHAR_LENGTH": 1, "CHARACTER_LENGTH": 1,
+	"SUBSTRING": 2, "COALESCE": 1, "NULLIF": 2,
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+
+	t := p.next()
+
+	switch t.typ {
+	case tokenNumber:
+		if strings.ContainsAny(t.text, ".eE") {
+			if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+				return nil, fmt.Errorf("invalid number %v", t)
+			}
+			return &literalExpr{value: sqltypes.MakeTrusted(sqltypes.Float64, []byte(t.text))}, nil
+		}
+		if _, err := strconv.ParseInt(t.text, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid number %v", t)
+		}
+		return &literalExpr{value: sqltypes.MakeTrusted(sqltypes.Int64, []byte(t.text))}, nil
+	case tokenString:
+		return &literalExpr{value: sqltypes.NewVarChar(t.text)}, nil
+	case tokenSymbol:
+		if t.text == "(" {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+		return nil, fmt.Errorf("unexpected %v", t)
+	case tokenQuotedIdentifier:
+		return p.parseColumn(t.text)
+	case tokenIdentifier:
+	default:
+		return nil, fmt.Errorf("unexpected %v", t)
+	}
+
+	name := strings.ToUpper(t.text)
+	switch name {
+	case "NULL":
+		return &literalExpr{value: sqltypes.NULL}, nil
+	case "TRUE":
+		return &literalExpr{value: boolValue(true)}, nil
+	case "FALSE":
+		return &literalExpr{value: boolValue(false)}, nil
+	}
+
+	if !p.peek().isSymbol("(") {
+		if reservedWords[name] {
+			return nil, fmt.Errorf("unexpected %v", t)
+		}
+		return p.parseColumn(t.text)
+	}
+	p.next()
+
+	if name == "CAST" {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectKeyword("AS"); err != nil {
+			return nil, err
+		}
+		typ, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		typ = strings.ToUpper(typ)
+		if !castTypes[typ] {
+			return nil, fmt.Errorf("unsupported CAST type %s", typ)
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return &castExpr{expr: e, typ: typ}, nil
+	}
+
+	if aggregateFunctions[name] {
+		if p.inAggregate {
+			return nil, fmt.Errorf("nested aggregate function %v", t)
+		}
+		agg := &aggregateExpr{name: name, index: len(p.aggregates)}
+		if name != "COUNT" || !p.acceptSymbol("*") {
+			p.inAggregate = true
+			arg, err := p.parseExpr()
+			p.inAggregate = false
+			if err != nil {
+				return nil, err
+			}
+			agg.arg = arg
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		p.aggregates = append(p.aggregates, agg)
+		return agg, nil
+	}
+
+	minArgs, found := scalarFunctions[name]
+	if !found {
+		return nil, fmt.Errorf("unsupported function %v", t)
+	}
+	f := &functionExpr{name: name}
+	if !p.peek().isSymbol(")") {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			f.args = append(f.args, arg)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	if len(f.args) < minArgs {
+		return nil, fmt.Errorf("function %s needs at least %d arguments", name, minArgs)
+	}
+	return f, nil
+}
+
+func (p *parser) parseColumn(name string) (expr, error) {
+	c := &columnExpr{path: []string{name}}
+	for p.acceptSymbol(".") {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		c.path = append(c.path, name)
+	}
+	return c, nil
+}
+
+func stripAlias(e expr, alias string) {
+	walk(e, func(e expr) {
+		c, ok := e.(*columnExpr)
+		if !ok || len(c.path) < 2 {
+			return
+		}
+		if strings.EqualFold(c.path[0], "S3Object") || (alias != "" && strings.EqualFold(c.path[0], alias)) {
+			c.path = c.path[1:]
+		}
+	})
+}
+
+func hasColumnOutsideAggregate(e expr) (found bool) {
+	switch e := e.(type) {
+	case *aggregateExpr:
+		return false
+	case *columnExpr:
+		return true
+	default:
+		for _, child := range children(e) {
+			if hasColumnOutsideAggregate(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func walk(e expr, fn func(e expr)) {
+	if e == nil {
+		return
+	}
+	fn(e)
+	for _, child := range children(e) {
+		walk(child, fn)
+	}
+}
+
+func children(e expr) []expr {
+	switch e := e.(type) {
+	case *unaryExpr:
+		return []expr{e.expr}
+	case *binaryExpr:
+		return []expr{e.left, e.right}
+	case *likeExpr:
+		if e.escape != nil {
+			return []expr{e.expr, e.pattern, e.escape}
+		}
+		return []expr{e.expr, e.pattern}
+	case *betweenExpr:
+		return []expr{e.expr, e.low, e.high}
+	case *inExpr:
+		return append([]expr{e.expr}, e.list...)
+	case *isNullExpr:
+		return []expr{e.expr}
+	case *castExpr:
+		return []expr{e.expr}
+	case *functionExpr:
+		return e.args
+	case *aggregateExpr:
+		if e.arg != nil {
+			return []expr{e.arg}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package sql
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/chrislusf/seaweedfs/weed/query/sqltypes"
+)
+
+// Record is one row of the input data
+type Record interface {
+	// Get returns the value under the column path, or NULL if not found.
+	// Positional columns are named "_1", "_2", ...
+	Get(path []string) sqltypes.Value
+	// Columns returns all column names and values in order, for SELECT *
+	Columns() (names []string, values []sqltypes.Value)
+}
+
+// RecordReader reads records incrementally, and returns io.EOF after the last record
+type RecordReader interface {
+	Read() (Record, error)
+}
+
+// Emit receives the names and values of each selected row
+type Emit func(names []string, values []sqltypes.Value) error
+
+// Execute runs the query over all records, stopping early once the LIMIT is reached
+func (s *Select) Execute(reader RecordReader, emit Emit) error {
+
+	if s.limit == 0 {
+		return nil
+	}
+
+	var names []string
+	for _, proj := range s.projections {
+		names = append(names, proj.name)
+	}
+
+	var accumulators []*accumulator
+	for _, agg := range s.aggregates {
+		accumulators = append(accumulators, &accumulator{name: agg.name})
+	}
+
+	var emitted int64
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		ev := &evaluator{record: record}
+
+		if s.where != nil {
+			v, err := ev.eval(s.where)
+			if err != nil {
+				return err
+			}
+			if matched, _, err := toBool(v); err != nil {
+				return fmt.Errorf("WHERE clause: %v", err)
+			} else if !matched {
+				continue
+			}
+		}
+
+		if len(accumulators) > 0 {
+			for i, agg := range s.aggregates {
+				if err := accumulators[i].add(ev, agg.arg); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if len(s.projections) == 0 {
+			columnNames, values := record.Columns()
+			if err = emit(columnNames, values); err != nil {
+				return err
+			}
+		} else {
+			values := make([]sqltypes.Value, len(s.projections))
+			for i, proj := range s.projections {
+				if values[i], err = ev.eval(proj.expr); err != nil {
+					return err
+				}
+			}
+			if err = emit(names, values); err != nil {
+				return err
+			}
+		}
+
+		emitted++
+		if s.limit > 0 && emitted >= s.limit {
+			return nil
+		}
+	}
+
+	if len(accumulators) == 0 {
+		return nil
+	}
+
+	ev := &evaluator{}
+	for _, acc := range accumulators {
+		ev.aggregated = append(ev.aggregated, acc.result())
+	}
+	values := make([]sqltypes.Value, len(s.projections))
+	for i, proj := range s.projections {
+		var err error
+		if values[i], err = ev.eval(proj.expr); err != nil {
+			return err
+		}
+	}
+
+	return emit(names, values)
+}
+
+// accumulator computes one aggregate function
+type accumulator struct {
+	name  string
+	count int64
+	sum   number
+	best  sqltypes.Value
+}
+
+func (acc *accumulator) add(ev *evaluator, arg expr) error {
+
+	if arg == nil {
+		// COUNT(*)
+		acc.count++
+		return nil
+	}
+
+	v, err := ev.eval(arg)
+	if err != nil {
+		return err
+	}
+	if v.IsNull() {
+		return nil
+	}
+	acc.count++
+
+	switch acc.name {
+	case "SUM", "AVG":
+		n, ok := toNumber(v)
+		if !ok {
+			return fmt.Errorf("%s of non-numeric value %s", acc.name, v.ToString())
+		}
+		if acc.sum.isFloat || n.isFloat {
+			acc.sum = number{isFloat: true, f: acc.sum.float() + n.float()}
+		} else {
+			acc.sum.i += n.i
+		}
+	case "MIN", "MAX":
+		if acc.count == 1 {
+			acc.best = v
+			return nil
+		}
+		c, comparable := compare(v, acc.best)
+		if !comparable {
+			return fmt.Errorf("%s of incomparable values %s and %s", acc.name, v.ToString(), acc.best.ToString())
+		}
+		if (acc.name == "MIN" && c < 0) || (acc.name == "MAX" && c > 0) {
+			acc.best = v
+		}
+	}
+
+	return nil
+}
+
+func (acc *accumulator) result() sqltypes.Value {
+	switch acc.name {
+	case "COUNT":
+		return sqltypes.NewInt64(acc.count)
+	case "SUM":
+		if acc.count == 0 {
+			return sqltypes.NULL
+		}
+		return acc.sum.value()
+	case "AVG":
+		if acc.count == 0 {
+			return sqltypes.NULL
+		}
+		return sqltypes.NewFloat64(acc.sum.float() / float64(acc.count))
+	}
+	return acc.best
+}
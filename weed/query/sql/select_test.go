@@ -0,0 +1,151 @@
+package sql
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/query/sqltypes"
+)
+
+type testRecord struct {
+	names  []string
+	values []string
+}
+
+func (r *testRecord) Get(path []string) sqltypes.Value {
+	if len(path) != 1 {
+		return sqltypes.NULL
+	}
+	for i, name := range r.names {
+		if strings.EqualFold(name, path[0]) {
+			return sqltypes.NewVarChar(r.values[i])
+		}
+	}
+	return sqltypes.NULL
+}
+
+func (r *testRecord) Columns() (names []string, values []sqltypes.Value) {
+	for i, name := range r.names {
+		names = append(names, name)
+		values = append(values, sqltypes.NewVarChar(r.values[i]))
+	}
+	return
+}
+
+type testReader struct {
+	records []*testRecord
+	read    int
+}
+
+func (r *testReader) Read() (Record, error) {
+	if r.read >= len(r.records) {
+		return nil, io.EOF
+	}
+	r.read++
+	return r.records[r.read-1], nil
+}
+
+func newTestReader() *testReader {
+	names := []string{"name", "city", "age"}
+	return &testReader{records: []*testRecord{
+		{names, []string{"alice", "Paris", "30"}},
+		{names, []string{"bob", "Berlin", "25"}},
+		{names, []string{"carol", "Paris", "41"}},
+		{names, []string{"dave", "Rome", "nil"}},
+	}}
+}
+
+func runQuery(t *testing.T, expression string) (rows []string, reader *testReader) {
+	s, err := Parse(expression)
+	if err != nil {
+		t.Fatalf("parse %s: %v", expression, err)
+	}
+	reader = newTestReader()
+	err = s.Execute(reader, func(names []string, values []sqltypes.Value) error {
+		var fields []string
+		for i, v := range values {
+			fields = append(fields, names[i]+"="+v.ToString())
+		}
+		rows = append(rows, strings.Join(fields, ","))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("execute %s: %v", expression, err)
+	}
+	return rows, reader
+}
+
+func TestSelectExecute(t *testing.T) {
+
+	tests := []struct {
+		expression string
+		expected   []string
+	}{
+		{"SELECT * FROM S3Object LIMIT 1", []string{"name=alice,city=Paris,age=30"}},
+		{"select s.name from s3object s where s.city = 'Paris'", []string{"name=alice", "name=carol"}},
+		{"SELECT name, age + 1 AS next FROM S3Object WHERE age > 28", []string{"name=alice,next=31", "name=carol,next=42"}},
+		{"SELECT UPPER(name) FROM S3Object WHERE city IN ('Rome', 'Berlin') AND NOT name LIKE 'd%'", []string{"_1=BOB"}},
+		{"SELECT name FROM S3Object WHERE city != 'Rome' AND CAST(age AS INT) BETWEEN 26 AND 40", []string{"name=alice"}},
+		{"SELECT COUNT(*), MAX(age), AVG(CAST(age AS FLOAT)) FROM S3Object WHERE age > 0", []string{"_1=3,_2=41,_3=32"}},
+		{"SELECT name || '@' || LOWER(city) AS email FROM S3Object WHERE name LIKE '_o%'", []string{"email=bob@berlin"}},
+		{"SELECT S3Object.name FROM S3Object WHERE missing IS NULL AND city <> 'Paris'", []string{"name=bob", "name=dave"}},
+		{"SELECT count(*) FROM S3Object WHERE city = 'Oslo'", []string{"_1=0"}},
+	}
+
+	for _, test := range tests {
+		rows, _ := runQuery(t, test.expression)
+		if strings.Join(rows, ";") != strings.Join(test.expected, ";") {
+			t.Errorf("%s: got %v, expecting %v", test.expression, rows, test.expected)
+		}
+	}
+
+}
+
+func TestSelectLimitStopsReading(t *testing.T) {
+	_, reader := runQuery(t, "SELECT * FROM S3Object s WHERE s.city = 'Paris' LIMIT 1")
+	if reader.read != 1 {
+		t.Errorf("read %d records, expecting 1", reader.read)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"SELECT",
+		"SELECT * FROM table",
+		"SELECT * FROM S3Object[*]",
+		"SELECT name, COUNT(*) FROM S3Object",
+		"SELECT * FROM S3Object WHERE COUNT(*) > 1",
+		"SELECT * FROM S3Object WHERE name = 'unterminated",
+		"SELECT * FROM S3Object LIMIT -1",
+		"SELECT FOO(name) FROM S3Object",
+		"SELECT * FROM S3Object WHERE name NOT = 'a'",
+		"SELECT * FROM S3Object extra tokens",
+	} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("expecting error for %q", expression)
+		}
+	}
+}
+
+func TestLike(t *testing.T) {
+	tests := []struct {
+		s, pattern string
+		expected   bool
+	}{
+		{"hello", "h%", true},
+		{"hello", "%llo", true},
+		{"hello", "h_llo", true},
+		{"hello", "h_lo", false},
+		{"50%", "50!%", true},
+		{"500", "50!%", false},
+		{"", "%", true},
+		{"héllo", "h_llo", true},
+	}
+	for _, test := range tests {
+		if like(test.s, test.pattern, '!') != test.expected {
+			t.Errorf("%q LIKE %q should be %v", test.s, test.pattern, test.expected)
+		}
+	}
+}
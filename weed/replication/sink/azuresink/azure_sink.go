@@ -107,6 +107,11 @@ func (g *AzureSink) CreateEntry(key string, entry *filer_pb.Entry) error {
 		return err
 	}
 
+	if len(entry.Content) > 0 {
+		_, err = appendBlobURL.AppendBlock(context.Background(), bytes.NewReader(entry.Content), azblob.AppendBlobAccessConditions{}, nil)
+		return err
+	}
+
 	for _, chunk := range chunkViews {
 
 		fileUrl, err := g.filerSource.LookupFileId(chunk.FileId)
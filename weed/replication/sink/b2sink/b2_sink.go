@@ -95,6 +95,13 @@ func (g *B2Sink) CreateEntry(key string, entry *filer_pb.Entry) error {
 	targetObject := bucket.Object(key)
 	writer := targetObject.NewWriter(context.Background())
 
+	if len(entry.Content) > 0 {
+		if _, err := writer.Write(entry.Content); err != nil {
+			return err
+		}
+		return writer.Close()
+	}
+
 	for _, chunk := range chunkViews {
 
 		fileUrl, err := g.filerSource.LookupFileId(chunk.FileId)
@@ -94,6 +94,13 @@ func (g *GcsSink) CreateEntry(key string, entry *filer_pb.Entry) error {
 
 	wc := g.client.Bucket(g.bucket).Object(key).NewWriter(context.Background())
 
+	if len(entry.Content) > 0 {
+		if _, err := wc.Write(entry.Content); err != nil {
+			return err
+		}
+		return wc.Close()
+	}
+
 	for _, chunk := range chunkViews {
 
 		fileUrl, err := g.filerSource.LookupFileId(chunk.FileId)
@@ -102,6 +102,10 @@ func (s3sink *S3Sink) CreateEntry(key string, entry *filer_pb.Entry) error {
 		return nil
 	}
 
+	if len(entry.Content) > 0 {
+		return s3sink.putObject(key, entry)
+	}
+
 	uploadId, err := s3sink.createMultipartUpload(key, entry)
 	if err != nil {
 		return err
@@ -0,0 +1,208 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html
+// Only the canned ACLs granting to everyone are supported. The bucket owner always has the full control,
+// and the objects without their own ACL use the bucket ACL.
+
+const (
+	AmzAcl = "X-Amz-Acl"
+
+	// extended attribute keys on the bucket entry
+	bucketAclKey   = "s3-acl"
+	bucketOwnerKey = "s3-owner"
+	// extended attribute key on the object entry, not returned as the object header
+	objectAclKey = "X-Seaweed-Acl"
+
+	bucketAclCacheTTL = 5 * time.Second
+
+	CannedAclPrivate         = "private"
+	CannedAclPublicRead      = "public-read"
+	CannedAclPublicReadWrite = "public-read-write"
+
+	aclPermissionRead        = "READ"
+	aclPermissionWrite       = "WRITE"
+	aclPermissionFullControl = "FULL_CONTROL"
+
+	aclGroupAllUsers     = "http://acs.amazonaws.com/groups/global/AllUsers"
+	xmlSchemaInstanceURI = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// the permissions granted to everyone by the canned ACLs
+var cannedAclPermissions = map[string][]string{
+	CannedAclPrivate:         nil,
+	CannedAclPublicRead:      {aclPermissionRead},
+	CannedAclPublicReadWrite: {aclPermissionRead, aclPermissionWrite},
+}
+
+// the ACL permissions needed for the actions, READ of objects and buckets, and WRITE of buckets
+var aclActionPermissions = map[string]string{
+	"s3:GetObject":                  aclPermissionRead,
+	"s3:GetObjectVersion":           aclPermissionRead,
+	"s3:ListBucket":                 aclPermissionRead,
+	"s3:ListBucketVersions":         aclPermissionRead,
+	"s3:ListBucketMultipartUploads": aclPermissionRead,
+	"s3:PutObject":                  aclPermissionWrite,
+	"s3:DeleteObject":               aclPermissionWrite,
+	"s3:DeleteObjectVersion":        aclPermissionWrite,
+	"s3:AbortMultipartUpload":       aclPermissionWrite,
+	"s3:ListMultipartUploadParts":   aclPermissionWrite,
+}
+
+// AccessControlPolicyResult is the xml of ?acl, the generated AccessControlPolicy has no grantee fields
+type AccessControlPolicyResult struct {
+	XMLName xml.Name      `xml:"AccessControlPolicy"`
+	Xmlns   string        `xml:"xmlns,attr,omitempty"`
+	Owner   CanonicalUser `xml:"Owner"`
+	Grants  []AclGrant    `xml:"AccessControlList>Grant"`
+}
+
+type AclGrant struct {
+	Grantee    AclGrantee `xml:"Grantee"`
+	Permission string     `xml:"Permission"`
+}
+
+type AclGrantee struct {
+	XmlnsXsi     string `xml:"xmlns:xsi,attr,omitempty"`
+	Type         string `xml:"xsi:type,attr,omitempty"`
+	ID           string `xml:"ID,omitempty"`
+	DisplayName  string `xml:"DisplayName,omitempty"`
+	EmailAddress string `xml:"EmailAddress,omitempty"`
+	URI          string `xml:"URI,omitempty"`
+}
+
+func isValidCannedAcl(acl string) bool {
+	_, found := cannedAclPermissions[acl]
+	return found
+}
+
+// aclGrants checks whether the canned ACL grants the permission to everyone
+func aclGrants(acl, permission string) bool {
+	for _, p := range cannedAclPermissions[acl] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareCannedAcl returns the canned ACL of the x-amz-acl header, or empty if not set
+func prepareCannedAcl(r *http.Request) (string, ErrorCode) {
+	for header := range r.Header {
+		if strings.HasPrefix(header, "X-Amz-Grant-") {
+			return "", ErrNotImplemented
+		}
+	}
+	acl := r.Header.Get(AmzAcl)
+	if acl != "" && !isValidCannedAcl(acl) {
+		return "", ErrInvalidCannedAcl
+	}
+	return acl, ErrNone
+}
+
+func newAccessControlPolicy(owner, acl string) *AccessControlPolicyResult {
+	policy := &AccessControlPolicyResult{
+		Owner: CanonicalUser{ID: owner, DisplayName: owner},
+		Grants: []AclGrant{{
+			Grantee:    AclGrantee{XmlnsXsi: xmlSchemaInstanceURI, Type: "CanonicalUser", ID: owner, DisplayName: owner},
+			Permission: aclPermissionFullControl,
+		}},
+	}
+	for _, permission := range cannedAclPermissions[acl] {
+		policy.Grants = append(policy.Grants, AclGrant{
+			Grantee:    AclGrantee{XmlnsXsi: xmlSchemaInstanceURI, Type: "Group", URI: aclGroupAllUsers},
+			Permission: permission,
+		})
+	}
+	return policy
+}
+
+// cannedAcl finds the canned ACL with the same grants, the grants to the owner are implied
+func (policy *AccessControlPolicyResult) cannedAcl(owner string) (string, ErrorCode) {
+	var read, write bool
+	for _, grant := range policy.Grants {
+		switch {
+		case grant.Grantee.URI == aclGroupAllUsers && grant.Permission == aclPermissionRead:
+			read = true
+		case grant.Grantee.URI == aclGroupAllUsers && grant.Permission == aclPermissionWrite:
+			write = true
+		case grant.Grantee.URI == "" && grant.Grantee.EmailAddress == "" && grant.Grantee.ID == owner:
+		default:
+			return "", ErrNotImplemented
+		}
+	}
+	switch {
+	case read && write:
+		return CannedAclPublicReadWrite, ErrNone
+	case read:
+		return CannedAclPublicRead, ErrNone
+	case write:
+		return "", ErrNotImplemented
+	}
+	return CannedAclPrivate, ErrNone
+}
+
+type bucketAcl struct {
+	owner string
+	acl   string
+}
+
+func (s3a *S3ApiServer) getBucketAcl(bucket string) (*bucketAcl, error) {
+
+	if item := s3a.aclCache.Get(bucket); item != nil && !item.Expired() {
+		return item.Value().(*bucketAcl), nil
+	}
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		return nil, err
+	}
+	var acl *bucketAcl
+	if entry != nil {
+		acl = &bucketAcl{
+			owner: string(entry.Extended[bucketOwnerKey]),
+			acl:   string(entry.Extended[bucketAclKey]),
+		}
+	}
+
+	s3a.aclCache.Set(bucket, acl, bucketAclCacheTTL)
+	return acl, nil
+}
+
+// getAcl returns the canned ACL of the object, or of the bucket if the object is empty or has no ACL.
+// The object may not have the leading "/", as in the route variables.
+func (s3a *S3ApiServer) getAcl(bucket, object, versionId string) (string, error) {
+
+	if object = strings.TrimPrefix(object, "/"); object != "" {
+		object = "/" + object
+		var entry *filer_pb.Entry
+		var err error
+		if versionId != "" {
+			_, entry, err = s3a.findVersion(bucket, object, versionId)
+		} else {
+			_, _, entry, err = s3a.objectEntry(bucket, object)
+		}
+		if err != nil {
+			return "", err
+		}
+		if entry != nil {
+			if acl, found := entry.Extended[objectAclKey]; found {
+				return string(acl), nil
+			}
+		}
+	}
+
+	acl, err := s3a.getBucketAcl(bucket)
+	if err != nil || acl == nil {
+		return "", err
+	}
+	return acl.acl, nil
+}
@@ -0,0 +1,124 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAccessControlPolicyCannedAcl(t *testing.T) {
+
+	policy := &AccessControlPolicyResult{}
+	if err := xml.Unmarshal([]byte(`<AccessControlPolicy xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Owner><ID>admin</ID></Owner>
+  <AccessControlList>
+    <Grant>
+      <Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>admin</ID></Grantee>
+      <Permission>FULL_CONTROL</Permission>
+    </Grant>
+    <Grant>
+      <Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee>
+      <Permission>READ</Permission>
+    </Grant>
+  </AccessControlList>
+</AccessControlPolicy>`), policy); err != nil {
+		t.Fatal(err)
+	}
+	if acl, errCode := policy.cannedAcl("admin"); acl != CannedAclPublicRead || errCode != ErrNone {
+		t.Errorf("canned acl %s %v", acl, errCode)
+	}
+	if _, errCode := policy.cannedAcl("someone"); errCode != ErrNotImplemented {
+		t.Errorf("grant to another user: %v", errCode)
+	}
+
+	for acl := range cannedAclPermissions {
+		data, err := xml.Marshal(newAccessControlPolicy("admin", acl))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `xsi:type="CanonicalUser"`) {
+			t.Errorf("acl %s: %s", acl, data)
+		}
+		parsed := &AccessControlPolicyResult{}
+		if err := xml.Unmarshal(data, parsed); err != nil {
+			t.Fatal(err)
+		}
+		if parsedAcl, errCode := parsed.cannedAcl("admin"); parsedAcl != acl || errCode != ErrNone {
+			t.Errorf("acl %s: parsed as %s %v", acl, parsedAcl, errCode)
+		}
+	}
+}
+
+func TestPrepareCannedAcl(t *testing.T) {
+	tests := []struct {
+		header   string
+		value    string
+		acl      string
+		expected ErrorCode
+	}{
+		{"", "", "", ErrNone},
+		{AmzAcl, "public-read", CannedAclPublicRead, ErrNone},
+		{AmzAcl, "authenticated-read", "", ErrInvalidCannedAcl},
+		{"X-Amz-Grant-Read", "uri=http://acs.amazonaws.com/groups/global/AllUsers", "", ErrNotImplemented},
+	}
+	for _, test := range tests {
+		r := &http.Request{Header: make(http.Header)}
+		if test.header != "" {
+			r.Header.Set(test.header, test.value)
+		}
+		if acl, errCode := prepareCannedAcl(r); acl != test.acl || errCode != test.expected {
+			t.Errorf("%s %s: got %s %v", test.header, test.value, acl, errCode)
+		}
+	}
+}
+
+func TestAuthorizeWithAcl(t *testing.T) {
+
+	acls := map[string]string{
+		"public/":        CannedAclPublicRead,
+		"public/private": CannedAclPrivate,
+		"shared/":        CannedAclPublicReadWrite,
+	}
+	iam := &IdentityAccessManagement{
+		loadBucketPolicy: func(bucket string) (*BucketPolicy, error) {
+			if bucket == "shared" {
+				return parseBucketPolicy([]byte(`{"Statement": [{"Effect": "Deny", "Principal": "*", "Action": "s3:DeleteObject", "Resource": "arn:aws:s3:::shared/*"}]}`))
+			}
+			return nil, nil
+		},
+		loadAcl: func(bucket, object, versionId string) (string, error) {
+			if acl, found := acls[bucket+object]; found {
+				return acl, nil
+			}
+			return acls[bucket+"/"], nil
+		},
+	}
+	anonymous := &requester{}
+
+	tests := []struct {
+		requester *requester
+		action    Action
+		s3Action  string
+		bucket    string
+		object    string
+		expected  ErrorCode
+	}{
+		{anonymous, ACTION_READ, "s3:GetObject", "public", "/a.txt", ErrNone},
+		{anonymous, ACTION_READ, "s3:ListBucket", "public", "", ErrNone},
+		{anonymous, ACTION_READ, "s3:GetObject", "public", "/private", ErrAccessDenied},
+		{anonymous, ACTION_READ, "s3:GetObjectTagging", "public", "/a.txt", ErrAccessDenied},
+		{anonymous, ACTION_WRITE, "s3:PutObject", "public", "/a.txt", ErrAccessDenied},
+		{anonymous, ACTION_WRITE, "s3:PutObject", "shared", "/a.txt", ErrNone},
+		{anonymous, ACTION_WRITE, "s3:DeleteObject", "shared", "/a.txt", ErrAccessDenied},
+		{anonymous, ACTION_READ, "s3:GetObject", "other", "/a.txt", ErrAccessDenied},
+		{&requester{identity: &Identity{Name: "reader", Actions: []Action{ACTION_READ}}}, ACTION_WRITE, "s3:PutObject", "shared", "/a.txt", ErrNone},
+	}
+	r := &http.Request{Header: make(http.Header), URL: &url.URL{}, RemoteAddr: "127.0.0.1:12345"}
+	for _, test := range tests {
+		if errCode := iam.authorize(r, test.requester, test.action, test.s3Action, test.bucket, test.object); errCode != test.expected {
+			t.Errorf("%s %s%s: got %v, expecting %v", test.s3Action, test.bucket, test.object, errCode, test.expected)
+		}
+	}
+}
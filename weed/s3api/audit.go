@@ -0,0 +1,97 @@
+package s3api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/audit"
+)
+
+// auditLog records the requests changing the buckets and the objects into the audit log, by the authenticated identity
+func (s3a *S3ApiServer) auditLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation, audited := auditOperation(r)
+		if !audited || !audit.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// the request metrics already record the response
+		writer, ok := w.(*accessLogWriter)
+		if !ok {
+			writer = &accessLogWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(writer, r)
+
+		vars := mux.Vars(r)
+		var object string
+		if _, found := vars["object"]; found {
+			object = getObject(vars)
+		}
+		record := &audit.Record{
+			Service:    "s3",
+			Operation:  operation,
+			Action:     accessLogOperation(r, object),
+			RemoteAddr: r.RemoteAddr,
+			RequestId:  w.Header().Get("x-amz-request-id"),
+			Path:       "/" + vars["bucket"] + object,
+			Result:     audit.ResultSuccess,
+			Status:     writer.status,
+		}
+		if writer.requester != nil && writer.requester.identity != nil {
+			record.Identity = writer.requester.identity.Name
+		}
+		if record.Status == 0 {
+			record.Status = http.StatusOK
+		}
+		if record.Status >= http.StatusBadRequest {
+			record.Result, record.Error = audit.ResultFailure, writer.errorCode
+		}
+		if copySource := r.Header.Get("X-Amz-Copy-Source"); copySource != "" {
+			if source, err := url.QueryUnescape(copySource); err == nil {
+				copySource = source
+			}
+			copySource = "/" + strings.TrimPrefix(copySource, "/")
+			if operation == audit.OperationRename {
+				record.Path, record.NewPath = copySource, record.Path
+			} else {
+				record.Source = copySource
+			}
+		}
+		audit.Log(record)
+	})
+}
+
+// auditOperation tells how the request changes the bucket or the object, the configurations of the sub-resources are updated
+func auditOperation(r *http.Request) (operation audit.Operation, audited bool) {
+
+	switch r.Method {
+	case http.MethodPut, http.MethodPost, http.MethodDelete:
+	default:
+		return "", false
+	}
+
+	query := r.URL.Query()
+	if _, found := query["select"]; found {
+		return "", false
+	}
+	if r.Header.Get(SeaweedRenameHeader) == "true" {
+		return audit.OperationRename, true
+	}
+	for _, subResource := range accessLogSubResources {
+		switch subResource.param {
+		case "uploadId", "uploads", "delete":
+			continue
+		}
+		if _, found := query[subResource.param]; found {
+			return audit.OperationUpdate, true
+		}
+	}
+	if _, found := query["delete"]; found || r.Method == http.MethodDelete {
+		return audit.OperationDelete, true
+	}
+	return audit.OperationCreate, true
+}
@@ -0,0 +1,120 @@
+package s3api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/audit"
+)
+
+func TestAuditOperation(t *testing.T) {
+	tests := []struct {
+		method    string
+		url       string
+		header    string
+		operation audit.Operation
+		audited   bool
+	}{
+		{"GET", "/bucket/object", "", "", false},
+		{"HEAD", "/bucket/object", "", "", false},
+		{"PUT", "/bucket", "", audit.OperationCreate, true},
+		{"PUT", "/bucket/object", "", audit.OperationCreate, true},
+		{"PUT", "/bucket/object?partNumber=1&uploadId=abc", "", audit.OperationCreate, true},
+		{"POST", "/bucket/object?uploadId=abc", "", audit.OperationCreate, true},
+		{"PUT", "/bucket/object?tagging", "", audit.OperationUpdate, true},
+		{"PUT", "/bucket?versioning", "", audit.OperationUpdate, true},
+		{"DELETE", "/bucket?policy", "", audit.OperationUpdate, true},
+		{"DELETE", "/bucket/object", "", audit.OperationDelete, true},
+		{"DELETE", "/bucket/object?uploadId=abc", "", audit.OperationDelete, true},
+		{"POST", "/bucket?delete", "", audit.OperationDelete, true},
+		{"POST", "/bucket/object?select&select-type=2", "", "", false},
+		{"PUT", "/bucket/moved", "true", audit.OperationRename, true},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, test.url, nil)
+		if test.header != "" {
+			r.Header.Set(SeaweedRenameHeader, test.header)
+		}
+		if operation, audited := auditOperation(r); operation != test.operation || audited != test.audited {
+			t.Errorf("%s %s: %q %v, expecting %q %v", test.method, test.url, operation, audited, test.operation, test.audited)
+		}
+	}
+}
+
+type memoryAuditSink struct {
+	data bytes.Buffer
+}
+
+func (sink *memoryAuditSink) Write(data []byte) error {
+	sink.data.Write(data)
+	return nil
+}
+
+func (sink *memoryAuditSink) Close() error {
+	return nil
+}
+
+func TestAuditLog(t *testing.T) {
+	sink := &memoryAuditSink{}
+	logger := audit.NewLogger(sink, 10, time.Hour)
+	audit.SetLogger(logger)
+	defer audit.SetLogger(nil)
+
+	s3a := &S3ApiServer{}
+	handler := s3a.requestMetrics(s3a.auditLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		setAccessLogRequester(w, &requester{identity: &Identity{Name: "someone"}})
+		if r.Header.Get(SeaweedRenameHeader) == "true" {
+			writeErrorResponse(w, ErrAccessDenied, r.URL)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	r := httptest.NewRequest("PUT", "/bucket/dir/copy", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "dir/copy"})
+	r.Header.Set("X-Amz-Copy-Source", "/bucket/dir%2Fobject")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	r = httptest.NewRequest("PUT", "/bucket/moved", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "moved"})
+	r.Header.Set("X-Amz-Copy-Source", "bucket/object")
+	r.Header.Set(SeaweedRenameHeader, "true")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	r = httptest.NewRequest("GET", "/bucket/moved", nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "moved"})
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	logger.Close()
+
+	expected := []audit.Record{
+		{Service: "s3", Operation: audit.OperationCreate, Action: "REST.COPY.OBJECT", Identity: "someone",
+			Path: "/bucket/dir/copy", Source: "/bucket/dir/object", Result: audit.ResultSuccess, Status: http.StatusOK},
+		{Service: "s3", Operation: audit.OperationRename, Action: "REST.COPY.OBJECT", Identity: "someone",
+			Path: "/bucket/object", NewPath: "/bucket/moved", Result: audit.ResultFailure, Status: http.StatusForbidden, Error: "AccessDenied"},
+	}
+	var records []audit.Record
+	decoder := json.NewDecoder(&sink.data)
+	for decoder.More() {
+		var record audit.Record
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		record.Time, record.RemoteAddr, record.RequestId = time.Time{}, "", ""
+		records = append(records, record)
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("records %+v, expecting %+v", records, expected)
+	}
+	for i, record := range records {
+		if record != expected[i] {
+			t.Errorf("record %d: %+v, expecting %+v", i, record, expected[i])
+		}
+	}
+}
@@ -2,9 +2,13 @@ package s3api
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/gorilla/mux"
@@ -26,8 +30,32 @@ type Iam interface {
 }
 
 type IdentityAccessManagement struct {
-	identities []*Identity
-	domain     string
+	identities           []*Identity
+	roles                []*Role
+	webIdentityProviders []*iam_pb.WebIdentityProvider
+	domains              []string
+
+	// the keys of the OpenID Connect providers by issuer
+	jwksCaches map[string]*jwksCache
+	jwksLock   sync.Mutex
+
+	// signs the session tokens of the temporary credentials, STS is disabled if empty
+	stsSigningKey []byte
+
+	// the region the requests are signed for, any region if empty
+	region string
+
+	// loadBucketPolicy returns nil if the bucket has no policy
+	loadBucketPolicy func(bucket string) (*BucketPolicy, error)
+
+	// loadObjectTags returns nil if the object does not exist
+	loadObjectTags func(bucket, object, versionId string) (objectTags, error)
+
+	// loadAcl returns the canned ACL of the object, or of the bucket if the object is empty
+	loadAcl func(bucket, object, versionId string) (string, error)
+
+	// loadRequestPayment returns the bucket owner and whether the requester pays
+	loadRequestPayment func(bucket string) (*bucketRequestPayment, error)
 }
 
 type Identity struct {
@@ -41,9 +69,9 @@ type Credential struct {
 	SecretKey string
 }
 
-func NewIdentityAccessManagement(fileName string, domain string) *IdentityAccessManagement {
+func NewIdentityAccessManagement(fileName string, domainNames string) *IdentityAccessManagement {
 	iam := &IdentityAccessManagement{
-		domain: domain,
+		domains: parseDomainNames(domainNames),
 	}
 	if fileName == "" {
 		return iam
@@ -88,6 +116,16 @@ func (iam *IdentityAccessManagement) loadS3ApiConfiguration(fileName string) err
 		iam.identities = append(iam.identities, t)
 	}
 
+	for _, role := range s3ApiConfiguration.Roles {
+		iam.roles = append(iam.roles, newRole(role))
+	}
+	for _, provider := range s3ApiConfiguration.WebIdentityProviders {
+		if usesJwks(provider) && len(provider.ClientIds) == 0 {
+			return fmt.Errorf("web identity provider %s: clientIds are required with the jwks keys", provider.Issuer)
+		}
+	}
+	iam.webIdentityProviders = s3ApiConfiguration.WebIdentityProviders
+
 	return nil
 }
 
@@ -104,64 +142,223 @@ func (iam *IdentityAccessManagement) lookupByAccessKey(accessKey string) (identi
 
 func (iam *IdentityAccessManagement) Auth(f http.HandlerFunc, action Action) http.HandlerFunc {
 
-	if len(iam.identities) == 0 {
+	if len(iam.identities) == 0 && iam.loadBucketPolicy == nil {
 		return f
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		errCode := iam.authRequest(r, action)
+		requester, errCode := iam.authRequest(r, action)
 		if errCode == ErrNone {
-			f(w, r)
+			setAccessLogRequester(w, requester)
+			dropAdminHeaders(r, requester)
+			iam.setRequestCharged(w, r)
+			f(w, r.WithContext(context.WithValue(r.Context(), requesterContextKey, requester)))
 			return
 		}
 		writeErrorResponse(w, errCode, r.URL)
 	}
 }
 
-// check whether the request has valid access keys
-func (iam *IdentityAccessManagement) authRequest(r *http.Request, action Action) ErrorCode {
-	var identity *Identity
-	var s3Err ErrorCode
-	switch getRequestAuthType(r) {
-	case authTypeStreamingSigned:
-		return ErrNone
+// Authenticate checks the access keys only, for the handlers checking the permissions of the requester themselves
+func (iam *IdentityAccessManagement) Authenticate(f http.HandlerFunc) http.HandlerFunc {
+
+	if len(iam.identities) == 0 && iam.loadBucketPolicy == nil {
+		return f
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requester, errCode := iam.authenticate(r)
+		if errCode == ErrNone {
+			setAccessLogRequester(w, requester)
+			dropAdminHeaders(r, requester)
+			f(w, r.WithContext(context.WithValue(r.Context(), requesterContextKey, requester)))
+			return
+		}
+		writeErrorResponse(w, errCode, r.URL)
+	}
+}
+
+// seaweedFsyncHeader asks the filer to flush the written chunks to disk before responding.
+// Only the admins can pass it on, since each flush slows down the volume servers for everyone.
+const seaweedFsyncHeader = "X-Seaweed-Fsync"
+
+// dropAdminHeaders removes the SeaweedFS headers the requester is not allowed to pass on to the filer
+func dropAdminHeaders(r *http.Request, requester *requester) {
+	if !requester.canDo(ACTION_ADMIN, mux.Vars(r)["bucket"]) {
+		r.Header.Del(seaweedFsyncHeader)
+	}
+}
+
+// requester is who sent the request, the identity is nil for anonymous requests.
+// A trusted requester passes the identity checks, e.g. if no identities are configured.
+type requester struct {
+	identity *Identity
+	trusted  bool
+}
+
+type contextKey string
+
+const requesterContextKey = contextKey("requester")
+
+func getRequester(r *http.Request) *requester {
+	if requester, ok := r.Context().Value(requesterContextKey).(*requester); ok {
+		return requester
+	}
+	return &requester{trusted: true}
+}
+
+// check whether the request has valid access keys, and is allowed by the identity or by the bucket policy
+func (iam *IdentityAccessManagement) authRequest(r *http.Request, action Action) (*requester, ErrorCode) {
+
+	requester, s3Err := iam.authenticate(r)
+	if s3Err != ErrNone {
+		return nil, s3Err
+	}
+
+	vars := mux.Vars(r)
+	bucket, object := vars["bucket"], vars["object"]
+
+	// the multiple objects delete is authorized for each object by the handler
+	if policyAction := policyAction(r, object); policyAction != "" {
+		if errCode := iam.authorize(r, requester, action, policyAction, bucket, object); errCode != ErrNone {
+			return nil, errCode
+		}
+	}
+
+	// copying also reads the source object
+	if copySource := r.Header.Get("X-Amz-Copy-Source"); copySource != "" && r.Method == http.MethodPut && object != "" {
+		if unescaped, err := url.QueryUnescape(copySource); err == nil {
+			copySource = unescaped
+		}
+		if i := strings.Index(copySource, "?"); i >= 0 {
+			copySource = copySource[:i]
+		}
+		srcBucket, srcObject := pathToBucketAndObject(copySource)
+		if errCode := iam.authorize(r, requester, ACTION_READ, "s3:GetObject", srcBucket, srcObject); errCode != ErrNone {
+			return nil, errCode
+		}
+		// renaming also deletes the source object
+		if r.Header.Get(SeaweedRenameHeader) == "true" {
+			if errCode := iam.authorize(r, requester, ACTION_WRITE, "s3:DeleteObject", srcBucket, srcObject); errCode != ErrNone {
+				return nil, errCode
+			}
+		}
+	}
+
+	return requester, ErrNone
+}
+
+func (iam *IdentityAccessManagement) authenticate(r *http.Request) (*requester, ErrorCode) {
+
+	if len(iam.identities) == 0 {
+		return &requester{trusted: true}, ErrNone
+	}
+
+	authType := getRequestAuthType(r)
+	switch authType {
 	case authTypeUnknown:
 		glog.V(3).Infof("unknown auth type")
-		return ErrAccessDenied
-	case authTypePresignedV2, authTypeSignedV2:
-		glog.V(3).Infof("v2 auth type")
-		identity, s3Err = iam.isReqAuthenticatedV2(r)
-	case authTypeSigned, authTypePresigned:
-		glog.V(3).Infof("v4 auth type")
-		identity, s3Err = iam.reqSignatureV4Verify(r)
-	case authTypePostPolicy:
-		glog.V(3).Infof("post policy auth type")
-		return ErrNotImplemented
-	case authTypeJWT:
-		glog.V(3).Infof("jwt auth type")
-		return ErrNotImplemented
+		return nil, ErrAccessDenied
 	case authTypeAnonymous:
-		return ErrAccessDenied
-	default:
-		return ErrNotImplemented
+		// only allowed by bucket policies
+		return &requester{}, ErrNone
 	}
 
-	glog.V(3).Infof("auth error: %v", s3Err)
+	verifier, found := signatureVerifiers[authType]
+	if !found {
+		glog.V(3).Infof("auth type %d not implemented", authType)
+		return nil, ErrNotImplemented
+	}
+	identity, s3Err := verifier.verify(iam, r)
+
+	glog.V(3).Infof("auth type %d error: %v", authType, s3Err)
 	if s3Err != ErrNone {
-		return s3Err
+		return nil, s3Err
 	}
 
 	glog.V(3).Infof("user name: %v actions: %v", identity.Name, identity.Actions)
 
-	vars := mux.Vars(r)
-	bucket := vars["bucket"]
+	return &requester{identity: identity}, ErrNone
+}
+
+// authorize checks the bucket policy and the identity for one bucket or object.
+// An explicit deny of the bucket policy always wins, otherwise the bucket policy, the identity or the canned ACL can allow it.
+func (iam *IdentityAccessManagement) authorize(r *http.Request, requester *requester, action Action, policyAction, bucket, object string) ErrorCode {
+
+	if errCode := iam.checkRequestPayer(r, requester, bucket); errCode != ErrNone {
+		return errCode
+	}
 
-	if !identity.canDo(action, bucket) {
+	decision, errCode := iam.evaluateBucketPolicy(r, requester, policyAction, bucket, object)
+	if errCode != ErrNone {
+		return errCode
+	}
+	switch decision {
+	case policyDenied:
 		return ErrAccessDenied
+	case policyAllowed:
+		return ErrNone
+	}
+
+	if requester.canDo(action, bucket) {
+		return ErrNone
+	}
+
+	// the canned ACLs grant to everyone, signed or not
+	if permission, found := aclActionPermissions[policyAction]; found && bucket != "" && iam.loadAcl != nil {
+		aclObject := object
+		if permission == aclPermissionWrite {
+			aclObject = ""
+		}
+		acl, err := iam.loadAcl(bucket, aclObject, r.URL.Query().Get("versionId"))
+		if err != nil {
+			glog.Errorf("load %s%s acl: %v", bucket, aclObject, err)
+			return ErrInternalError
+		}
+		if aclGrants(acl, permission) {
+			return ErrNone
+		}
 	}
 
-	return ErrNone
+	return ErrAccessDenied
+}
+
+// evaluateBucketPolicy evaluates the bucket policy, if any, for the requester to do the policy action on the object
+func (iam *IdentityAccessManagement) evaluateBucketPolicy(r *http.Request, requester *requester, policyAction, bucket, object string) (policyDecision, ErrorCode) {
+	if bucket == "" || iam.loadBucketPolicy == nil {
+		return policyNotApplicable, ErrNone
+	}
+	policy, err := iam.loadBucketPolicy(bucket)
+	if err != nil {
+		glog.Errorf("load bucket %s policy: %v", bucket, err)
+		return policyNotApplicable, ErrInternalError
+	}
+	if policy == nil {
+		return policyNotApplicable, ErrNone
+	}
+	request := &policyRequest{
+		action:     policyAction,
+		resource:   policyResource(bucket, object),
+		conditions: policyConditionValues(r, requester.identity),
+	}
+	if object != "" && iam.loadObjectTags != nil && policy.usesExistingObjectTags() {
+		tags, err := iam.loadObjectTags(bucket, object, r.URL.Query().Get("versionId"))
+		if err != nil {
+			glog.Errorf("load object %s%s tags: %v", bucket, object, err)
+			return policyNotApplicable, ErrInternalError
+		}
+		for key, value := range tags {
+			request.conditions["s3:existingobjecttag/"+strings.ToLower(key)] = []string{value}
+		}
+	}
+	if requester.identity != nil {
+		request.principal = requester.identity.Name
+	}
+	return policy.evaluate(request), ErrNone
+}
 
+func (requester *requester) canDo(action Action, bucket string) bool {
+	return requester.trusted || (requester.identity != nil && requester.identity.canDo(action, bucket))
 }
 
 func (identity *Identity) canDo(action Action, bucket string) bool {
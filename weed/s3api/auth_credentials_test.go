@@ -1,9 +1,11 @@
 package s3api
 
 import (
+	"net/http/httptest"
 	"testing"
 
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/gorilla/mux"
 
 	"github.com/chrislusf/seaweedfs/weed/pb/iam_pb"
 )
@@ -66,3 +68,25 @@ func TestIdentityListFileFormat(t *testing.T) {
 	println(text)
 
 }
+
+func TestDropAdminHeaders(t *testing.T) {
+	tests := []struct {
+		requester *requester
+		kept      bool
+	}{
+		{&requester{trusted: true}, true},
+		{&requester{identity: &Identity{Name: "admin", Actions: []Action{ACTION_ADMIN}}}, true},
+		{&requester{identity: &Identity{Name: "bucket admin", Actions: []Action{ACTION_ADMIN + ":bucket"}}}, true},
+		{&requester{identity: &Identity{Name: "other bucket admin", Actions: []Action{ACTION_ADMIN + ":other"}}}, false},
+		{&requester{identity: &Identity{Name: "writer", Actions: []Action{ACTION_WRITE}}}, false},
+		{&requester{}, false},
+	}
+	for i, test := range tests {
+		r := mux.SetURLVars(httptest.NewRequest("PUT", "/bucket/object", nil), map[string]string{"bucket": "bucket"})
+		r.Header.Set(seaweedFsyncHeader, "true")
+		dropAdminHeaders(r, test.requester)
+		if kept := r.Header.Get(seaweedFsyncHeader) == "true"; kept != test.kept {
+			t.Errorf("%d: fsync header kept %v, expecting %v", i, kept, test.kept)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package s3api
+
+import (
+	"net/http"
+	"time"
+)
+
+// the signed requests are accepted within this difference between the request time and the server time
+const globalMaxSkewTime = 15 * time.Minute
+
+// timeNow is replaced by the tests
+var timeNow = time.Now
+
+// signatureVerifier verifies the requests of one signature scheme, and returns the identity who signed the request.
+// A new scheme is added by detecting its requests in getRequestAuthType, and registering its verifier in signatureVerifiers.
+type signatureVerifier interface {
+	verify(iam *IdentityAccessManagement, r *http.Request) (*Identity, ErrorCode)
+}
+
+type signatureVerifierFunc func(iam *IdentityAccessManagement, r *http.Request) (*Identity, ErrorCode)
+
+func (f signatureVerifierFunc) verify(iam *IdentityAccessManagement, r *http.Request) (*Identity, ErrorCode) {
+	return f(iam, r)
+}
+
+var signatureVerifiers = map[authType]signatureVerifier{
+	authTypeSignedV2:        signatureVerifierFunc((*IdentityAccessManagement).isReqAuthenticatedV2),
+	authTypePresignedV2:     signatureVerifierFunc((*IdentityAccessManagement).isReqAuthenticatedV2),
+	authTypeSigned:          signatureVerifierFunc((*IdentityAccessManagement).reqSignatureV4Verify),
+	authTypePresigned:       signatureVerifierFunc((*IdentityAccessManagement).reqSignatureV4Verify),
+	authTypeStreamingSigned: signatureVerifierFunc((*IdentityAccessManagement).doesSeedSignatureMatch),
+}
+
+// checkRequestTime rejects the requests signed too long before or after the server time
+func checkRequestTime(t time.Time) ErrorCode {
+	if skew := timeNow().Sub(t); skew > globalMaxSkewTime || skew < -globalMaxSkewTime {
+		return ErrRequestTimeTooSkewed
+	}
+	return ErrNone
+}
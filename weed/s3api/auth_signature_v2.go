@@ -23,7 +23,6 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
-	"net"
 	"net/http"
 	"net/url"
 	"path"
@@ -123,9 +122,25 @@ func (iam *IdentityAccessManagement) doesSignV2Match(r *http.Request) (*Identity
 
 	// Access credentials.
 	// Validate if access key id same.
-	ident, cred, found := iam.lookupByAccessKey(accessKey)
-	if !found {
-		return nil, ErrInvalidAccessKeyID
+	ident, cred, errCode := iam.lookupCredential(r, accessKey)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	// The x-amz-date header replaces the Date header, for the clients unable to set the Date header.
+	date := r.Header.Get("X-Amz-Date")
+	if date == "" {
+		date = r.Header.Get("Date")
+	}
+	if date == "" {
+		return nil, ErrMissingDateHeader
+	}
+	t, err := parseDateV2(date)
+	if err != nil {
+		return nil, ErrMalformedDate
+	}
+	if errCode = checkRequestTime(t); errCode != ErrNone {
+		return nil, errCode
 	}
 
 	// r.RequestURI will have raw encoded URI as sent by the client.
@@ -141,10 +156,7 @@ func (iam *IdentityAccessManagement) doesSignV2Match(r *http.Request) (*Identity
 		return nil, ErrInvalidQueryParams
 	}
 
-	encodedResource, err = getResource(encodedResource, r.Host, iam.domain)
-	if err != nil {
-		return nil, ErrInvalidRequest
-	}
+	encodedResource = getResource(encodedResource, r.Host, iam.domains)
 
 	prefix := fmt.Sprintf("%s %s:", signV2Algorithm, cred.AccessKey)
 	if !strings.HasPrefix(v2Auth, prefix) {
@@ -158,6 +170,14 @@ func (iam *IdentityAccessManagement) doesSignV2Match(r *http.Request) (*Identity
 	return ident, ErrNone
 }
 
+// parseDateV2 parses the HTTP dates, also with the numeric time zones used by many clients
+func parseDateV2(date string) (time.Time, error) {
+	if t, err := http.ParseTime(date); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC1123Z, date)
+}
+
 // doesPresignV2SignatureMatch - Verify query headers with presigned signature
 //     - http://docs.aws.amazon.com/AmazonS3/latest/dev/RESTAuthentication.html#RESTAuthenticationQueryStringAuth
 // returns ErrNone if matches. S3 errors otherwise.
@@ -209,9 +229,9 @@ func (iam *IdentityAccessManagement) doesPresignV2SignatureMatch(r *http.Request
 	}
 
 	// Validate if access key id same.
-	ident, cred, found := iam.lookupByAccessKey(accessKey)
-	if !found {
-		return nil, ErrInvalidAccessKeyID
+	ident, cred, errCode := iam.lookupCredential(r, accessKey)
+	if errCode != ErrNone {
+		return nil, errCode
 	}
 
 	// Make sure the request has not expired.
@@ -221,14 +241,11 @@ func (iam *IdentityAccessManagement) doesPresignV2SignatureMatch(r *http.Request
 	}
 
 	// Check if the presigned URL has expired.
-	if expiresInt < time.Now().UTC().Unix() {
+	if expiresInt < timeNow().UTC().Unix() {
 		return nil, ErrExpiredPresignRequest
 	}
 
-	encodedResource, err = getResource(encodedResource, r.Host, iam.domain)
-	if err != nil {
-		return nil, ErrInvalidRequest
-	}
+	encodedResource = getResource(encodedResource, r.Host, iam.domains)
 
 	expectedSignature := preSignatureV2(cred, r.Method, encodedResource, strings.Join(filteredQueries, "&"), r.Header, expires)
 	if !compareSignatureV2(gotSignature, expectedSignature) {
@@ -253,23 +270,13 @@ func unescapeQueries(encodedQuery string) (unescapedQueries []string, err error)
 }
 
 // Returns "/bucketName/objectName" for path-style or virtual-host-style requests.
-func getResource(path string, host string, domain string) (string, error) {
-	if domain == "" {
-		return path, nil
-	}
+func getResource(path string, host string, domains []string) string {
 	// If virtual-host-style is enabled construct the "resource" properly.
-	if strings.Contains(host, ":") {
-		// In bucket.mydomain.com:9000, strip out :9000
-		var err error
-		if host, _, err = net.SplitHostPort(host); err != nil {
-			return "", err
-		}
-	}
-	if !strings.HasSuffix(host, "."+domain) {
-		return path, nil
+	bucket := virtualHostBucket(host, domains)
+	if bucket == "" {
+		return path
 	}
-	bucket := strings.TrimSuffix(host, "."+domain)
-	return "/" + pathJoin(bucket, path), nil
+	return "/" + pathJoin(bucket, path)
 }
 
 // pathJoin - like path.Join() but retains trailing "/" of the last element
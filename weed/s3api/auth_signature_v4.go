@@ -33,11 +33,17 @@ import (
 	"unicode/utf8"
 )
 
+// The services of the credential scope, the requests signed for any other service are rejected
+const (
+	signingServiceS3  = "s3"
+	signingServiceSts = "sts"
+)
+
 func (iam *IdentityAccessManagement) reqSignatureV4Verify(r *http.Request) (*Identity, ErrorCode) {
 	sha256sum := getContentSha256Cksum(r)
 	switch {
 	case isRequestSignatureV4(r):
-		return iam.doesSignatureMatch(sha256sum, r)
+		return iam.doesSignatureMatch(sha256sum, r, signingServiceS3)
 	case isRequestPresignedSignatureV4(r):
 		return iam.doesPresignedSignatureMatch(sha256sum, r)
 	}
@@ -50,6 +56,11 @@ const (
 	streamingContentSHA256 = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
 	signV4ChunkedAlgorithm = "AWS4-HMAC-SHA256-PAYLOAD"
 
+	// the streaming payloads followed by the trailing headers, with the signed or unsigned chunks
+	streamingContentSHA256Trailer   = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+	streamingUnsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+	signV4TrailerAlgorithm          = "AWS4-HMAC-SHA256-TRAILER"
+
 	// http Header "x-amz-content-sha256" == "UNSIGNED-PAYLOAD" indicates that the
 	// client did not calculate sha256 of the payload.
 	unsignedPayload = "UNSIGNED-PAYLOAD"
@@ -89,7 +100,7 @@ func getContentSha256Cksum(r *http.Request) string {
 }
 
 // Verify authorization header - http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
-func (iam *IdentityAccessManagement) doesSignatureMatch(hashedPayload string, r *http.Request) (*Identity, ErrorCode) {
+func (iam *IdentityAccessManagement) doesSignatureMatch(hashedPayload string, r *http.Request, service string) (*Identity, ErrorCode) {
 
 	// Copy request.
 	req := *r
@@ -102,6 +113,12 @@ func (iam *IdentityAccessManagement) doesSignatureMatch(hashedPayload string, r
 	if err != ErrNone {
 		return nil, err
 	}
+	if err = iam.checkRegion(r, signV4Values.Credential.scope.region); err != ErrNone {
+		return nil, err
+	}
+	if err = checkService(signV4Values.Credential, service); err != ErrNone {
+		return nil, err
+	}
 
 	// Extract all the signed headers along with its values.
 	extractedSignedHeaders, errCode := extractSignedHeaders(signV4Values.SignedHeaders, r)
@@ -110,22 +127,18 @@ func (iam *IdentityAccessManagement) doesSignatureMatch(hashedPayload string, r
 	}
 
 	// Verify if the access key id matches.
-	identity, cred, found := iam.lookupByAccessKey(signV4Values.Credential.accessKey)
-	if !found {
-		return nil, ErrInvalidAccessKeyID
+	identity, cred, errCode := iam.lookupCredential(r, signV4Values.Credential.accessKey)
+	if errCode != ErrNone {
+		return nil, errCode
 	}
 
 	// Extract date, if not present throw error.
-	var date string
-	if date = req.Header.Get(http.CanonicalHeaderKey("X-Amz-Date")); date == "" {
-		if date = r.Header.Get("Date"); date == "" {
-			return nil, ErrMissingDateHeader
-		}
+	t, errCode := parseSignV4Date(&req)
+	if errCode != ErrNone {
+		return nil, errCode
 	}
-	// Parse date header.
-	t, e := time.Parse(iso8601Format, date)
-	if e != nil {
-		return nil, ErrMalformedDate
+	if errCode = checkRequestTime(t); errCode != ErrNone {
+		return nil, errCode
 	}
 
 	// Query string.
@@ -138,7 +151,7 @@ func (iam *IdentityAccessManagement) doesSignatureMatch(hashedPayload string, r
 	stringToSign := getStringToSign(canonicalRequest, t, signV4Values.Credential.getScope())
 
 	// Get hmac signing key.
-	signingKey := getSigningKey(cred.SecretKey, signV4Values.Credential.scope.date, signV4Values.Credential.scope.region)
+	signingKey := getSigningKey(cred.SecretKey, signV4Values.Credential.scope.date, signV4Values.Credential.scope.region, service)
 
 	// Calculate signature.
 	newSignature := getSignature(signingKey, stringToSign)
@@ -152,6 +165,29 @@ func (iam *IdentityAccessManagement) doesSignatureMatch(hashedPayload string, r
 	return identity, ErrNone
 }
 
+// parseSignV4Date returns the time of the request from the X-Amz-Date header, or from the Date header.
+func parseSignV4Date(r *http.Request) (time.Time, ErrorCode) {
+	if date := r.Header.Get("X-Amz-Date"); date != "" {
+		t, err := time.Parse(iso8601Format, date)
+		if err != nil {
+			return time.Time{}, ErrMalformedDate
+		}
+		return t, ErrNone
+	}
+	date := r.Header.Get("Date")
+	if date == "" {
+		return time.Time{}, ErrMissingDateHeader
+	}
+	if t, err := time.Parse(iso8601Format, date); err == nil {
+		return t, ErrNone
+	}
+	t, err := http.ParseTime(date)
+	if err != nil {
+		return time.Time{}, ErrMalformedDate
+	}
+	return t.UTC(), ErrNone
+}
+
 // credentialHeader data type represents structured form of Credential
 // string from authorization header.
 type credentialHeader struct {
@@ -276,6 +312,14 @@ func parseSignedHeader(signedHdrElement string) ([]string, ErrorCode) {
 	return signedHeaders, ErrNone
 }
 
+// checkService checks the credential is scoped to the service of the endpoint
+func checkService(cred credentialHeader, service string) ErrorCode {
+	if cred.scope.service != service {
+		return ErrCredMalformed
+	}
+	return ErrNone
+}
+
 // Parse signature from signature tag.
 func parseSignature(signElement string) (string, ErrorCode) {
 	signFields := strings.Split(strings.TrimSpace(signElement), "=")
@@ -304,11 +348,17 @@ func (iam *IdentityAccessManagement) doesPresignedSignatureMatch(hashedPayload s
 	if err != ErrNone {
 		return nil, err
 	}
+	if err = iam.checkRegion(r, pSignValues.Credential.scope.region); err != ErrNone {
+		return nil, err
+	}
+	if err = checkService(pSignValues.Credential, signingServiceS3); err != ErrNone {
+		return nil, err
+	}
 
 	// Verify if the access key id matches.
-	identity, cred, found := iam.lookupByAccessKey(pSignValues.Credential.accessKey)
-	if !found {
-		return nil, ErrInvalidAccessKeyID
+	identity, cred, errCode := iam.lookupCredential(r, pSignValues.Credential.accessKey)
+	if errCode != ErrNone {
+		return nil, errCode
 	}
 
 	// Extract all the signed headers along with its values.
@@ -324,14 +374,15 @@ func (iam *IdentityAccessManagement) doesPresignedSignatureMatch(hashedPayload s
 
 	query.Set("X-Amz-Algorithm", signV4Algorithm)
 
-	now := time.Now().UTC()
+	now := timeNow().UTC()
 
 	// If the host which signed the request is slightly ahead in time (by less than globalMaxSkewTime) the
 	// request should still be allowed.
-	if pSignValues.Date.After(now.Add(15 * time.Minute)) {
+	if pSignValues.Date.After(now.Add(globalMaxSkewTime)) {
 		return nil, ErrRequestNotReadyYet
 	}
 
+	// The expiry counts from the signing time, without any allowance for the skew.
 	if now.Sub(pSignValues.Date) > pSignValues.Expires {
 		return nil, ErrExpiredPresignRequest
 	}
@@ -345,6 +396,9 @@ func (iam *IdentityAccessManagement) doesPresignedSignatureMatch(hashedPayload s
 	query.Set("X-Amz-Expires", strconv.Itoa(expireSeconds))
 	query.Set("X-Amz-SignedHeaders", getSignedHeaders(extractedSignedHeaders))
 	query.Set("X-Amz-Credential", cred.AccessKey+"/"+getScope(t, pSignValues.Credential.scope.region))
+	if securityToken := req.URL.Query().Get("X-Amz-Security-Token"); securityToken != "" {
+		query.Set("X-Amz-Security-Token", securityToken)
+	}
 
 	// Save other headers available in the request parameters.
 	for k, v := range req.URL.Query() {
@@ -395,7 +449,7 @@ func (iam *IdentityAccessManagement) doesPresignedSignatureMatch(hashedPayload s
 	presignedStringToSign := getStringToSign(presignedCanonicalReq, t, pSignValues.Credential.getScope())
 
 	// Get hmac presigned signing key.
-	presignedSigningKey := getSigningKey(cred.SecretKey, pSignValues.Credential.scope.date, pSignValues.Credential.scope.region)
+	presignedSigningKey := getSigningKey(cred.SecretKey, pSignValues.Credential.scope.date, pSignValues.Credential.scope.region, signingServiceS3)
 
 	// Get new signature.
 	newSignature := getSignature(presignedSigningKey, presignedStringToSign)
@@ -407,6 +461,48 @@ func (iam *IdentityAccessManagement) doesPresignedSignatureMatch(hashedPayload s
 	return identity, ErrNone
 }
 
+// check the form fields of the browser-based upload, signed by the policy
+//  - https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-authentication-HTTPPOST.html
+func (iam *IdentityAccessManagement) doesPolicySignatureV4Match(form http.Header, r *http.Request) (*Identity, ErrorCode) {
+
+	if form.Get("X-Amz-Algorithm") != signV4Algorithm {
+		return nil, ErrSignatureVersionNotSupported
+	}
+	if form.Get("Policy") == "" || form.Get("X-Amz-Signature") == "" || form.Get("X-Amz-Date") == "" {
+		return nil, ErrMissingFields
+	}
+
+	credHeader, errCode := parseCredentialHeader("Credential=" + form.Get("X-Amz-Credential"))
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+	if errCode = iam.checkRegion(r, credHeader.scope.region); errCode != ErrNone {
+		return nil, errCode
+	}
+	if errCode = checkService(credHeader, signingServiceS3); errCode != ErrNone {
+		return nil, errCode
+	}
+	if _, e := time.Parse(iso8601Format, form.Get("X-Amz-Date")); e != nil {
+		return nil, ErrMalformedDate
+	}
+
+	// Verify if the access key id matches.
+	identity, cred, errCode := iam.lookupCredential(r, credHeader.accessKey)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	// The string to sign is the base64 encoded policy.
+	signingKey := getSigningKey(cred.SecretKey, credHeader.scope.date, credHeader.scope.region, signingServiceS3)
+	newSignature := getSignature(signingKey, form.Get("Policy"))
+
+	// Verify signature.
+	if !compareSignatureV4(newSignature, form.Get("X-Amz-Signature")) {
+		return nil, ErrSignatureDoesNotMatch
+	}
+	return identity, ErrNone
+}
+
 func contains(list []string, elem string) bool {
 	for _, t := range list {
 		if t == elem {
@@ -473,20 +569,21 @@ func parsePreSignV4(query url.Values) (psv preSignValues, aec ErrorCode) {
 		return psv, ErrMalformedPresignedDate
 	}
 
-	// Save expires in native time.Duration.
-	preSignV4Values.Expires, e = time.ParseDuration(query.Get("X-Amz-Expires") + "s")
+	// Save expires in native time.Duration, the expires is an integer of seconds.
+	expires, e := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
 	if e != nil {
 		return psv, ErrMalformedExpires
 	}
 
-	if preSignV4Values.Expires < 0 {
+	if expires < 0 {
 		return psv, ErrNegativeExpires
 	}
 
 	// Check if Expiry time is less than 7 days (value in seconds).
-	if preSignV4Values.Expires.Seconds() > 604800 {
+	if expires > 604800 {
 		return psv, ErrMaximumExpires
 	}
+	preSignV4Values.Expires = time.Duration(expires) * time.Second
 
 	// Save signed headers.
 	preSignV4Values.SignedHeaders, err = parseSignedHeader("SignedHeaders=" + query.Get("X-Amz-SignedHeaders"))
@@ -623,11 +720,11 @@ func sumHMAC(key []byte, data []byte) []byte {
 }
 
 // getSigningKey hmac seed to calculate final signature.
-func getSigningKey(secretKey string, t time.Time, region string) []byte {
+func getSigningKey(secretKey string, t time.Time, region string, service string) []byte {
 	date := sumHMAC([]byte("AWS4"+secretKey), []byte(t.Format(yyyymmdd)))
 	regionBytes := sumHMAC(date, []byte(region))
-	service := sumHMAC(regionBytes, []byte("s3"))
-	signingKey := sumHMAC(service, []byte("aws4_request"))
+	serviceBytes := sumHMAC(regionBytes, []byte(service))
+	signingKey := sumHMAC(serviceBytes, []byte("aws4_request"))
 	return signingKey
 }
 
@@ -230,6 +230,14 @@ var ignoredHeaders = map[string]bool{
 
 // Sign given request using Signature V4.
 func signRequestV4(req *http.Request, accessKey, secretKey string) error {
+	return signRequestV4ForRegion(req, accessKey, secretKey, "us-east-1")
+}
+
+func signRequestV4ForRegion(req *http.Request, accessKey, secretKey, region string) error {
+	return signRequestV4ForService(req, accessKey, secretKey, region, "s3")
+}
+
+func signRequestV4ForService(req *http.Request, accessKey, secretKey, region, signingService string) error {
 	// Get hashed payload.
 	hashedPayload := req.Header.Get("x-amz-content-sha256")
 	if hashedPayload == "" {
@@ -257,8 +265,6 @@ func signRequestV4(req *http.Request, accessKey, secretKey string) error {
 	}
 	sort.Strings(headers)
 
-	region := "us-east-1"
-
 	// Get canonical headers.
 	var buf bytes.Buffer
 	for _, k := range headers {
@@ -311,7 +317,7 @@ func signRequestV4(req *http.Request, accessKey, secretKey string) error {
 	scope := strings.Join([]string{
 		currTime.Format(yyyymmdd),
 		region,
-		"s3",
+		signingService,
 		"aws4_request",
 	}, "/")
 
@@ -321,7 +327,7 @@ func signRequestV4(req *http.Request, accessKey, secretKey string) error {
 
 	date := sumHMAC([]byte("AWS4"+secretKey), []byte(currTime.Format(yyyymmdd)))
 	regionHMAC := sumHMAC(date, []byte(region))
-	service := sumHMAC(regionHMAC, []byte("s3"))
+	service := sumHMAC(regionHMAC, []byte(signingService))
 	signingKey := sumHMAC(service, []byte("aws4_request"))
 
 	signature := hex.EncodeToString(sumHMAC(signingKey, []byte(stringToSign)))
@@ -341,12 +347,15 @@ func signRequestV4(req *http.Request, accessKey, secretKey string) error {
 // preSignV4 presign the request, in accordance with
 // http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html.
 func preSignV4(req *http.Request, accessKeyID, secretAccessKey string, expires int64) error {
+	return preSignV4ForRegion(req, accessKeyID, secretAccessKey, expires, "us-east-1")
+}
+
+func preSignV4ForRegion(req *http.Request, accessKeyID, secretAccessKey string, expires int64, region string) error {
 	// Presign is not needed for anonymous credentials.
 	if accessKeyID == "" || secretAccessKey == "" {
 		return errors.New("Presign cannot be generated without access and secret keys")
 	}
 
-	region := "us-east-1"
 	date := time.Now().UTC()
 	scope := getScope(date, region)
 	credential := fmt.Sprintf("%s/%s", accessKeyID, scope)
@@ -367,7 +376,7 @@ func preSignV4(req *http.Request, accessKeyID, secretAccessKey string, expires i
 	queryStr := strings.Replace(query.Encode(), "+", "%20", -1)
 	canonicalRequest := getCanonicalRequest(extractedSignedHeaders, unsignedPayload, queryStr, req.URL.Path, req.Method)
 	stringToSign := getStringToSign(canonicalRequest, date, scope)
-	signingKey := getSigningKey(secretAccessKey, date, region)
+	signingKey := getSigningKey(secretAccessKey, date, region, "s3")
 	signature := getSignature(signingKey, stringToSign)
 
 	req.URL.RawQuery = query.Encode()
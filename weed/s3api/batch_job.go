@@ -0,0 +1,716 @@
+package s3api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The batch jobs apply one operation to the objects under a prefix of a bucket, or listed in a
+// manifest object, similar to the S3 Batch Operations. A job is saved as an entry of the jobs folder,
+// with its progress and a checkpoint, so an unfinished job resumes after a restart. Only one s3 gateway
+// runs a job at a time, holding a lease in the extended attributes of the job entry.
+// When the job ends, the failed tasks are saved as the completion report object.
+
+const (
+	batchJobsDir     = filer2.SystemDir + "/s3/jobs"
+	batchJobKey      = "s3-batch-job"
+	batchJobLockKey  = "s3-batch-job-lock"
+	batchJobLease    = 10 * time.Minute
+	batchJobInterval = time.Minute
+
+	// the progress is saved after each batch of tasks
+	batchJobBatchSize = 100
+	// the failed tasks kept for the completion report
+	batchJobMaxReportedFailures = 1000
+
+	arnPrefix = "arn:aws:s3:::"
+)
+
+const (
+	JobStatusNew       = "New"
+	JobStatusActive    = "Active"
+	JobStatusCancelled = "Cancelled"
+	JobStatusComplete  = "Complete"
+	JobStatusFailed    = "Failed"
+)
+
+var errJobCancelled = errors.New("job cancelled")
+
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_control_CreateJob.html
+type CreateJobRequest struct {
+	XMLName           xml.Name              `xml:"CreateJobRequest"`
+	Description       string                `xml:"Description,omitempty"`
+	Operation         JobOperation          `xml:"Operation"`
+	Manifest          *JobManifest          `xml:"Manifest,omitempty"`
+	ManifestGenerator *JobManifestGenerator `xml:"ManifestGenerator,omitempty"`
+	Report            JobReport             `xml:"Report"`
+}
+
+type JobOperation struct {
+	S3PutObjectTagging   *JobPutObjectTagging   `xml:"S3PutObjectTagging,omitempty"`
+	S3PutObjectRetention *JobPutObjectRetention `xml:"S3PutObjectRetention,omitempty"`
+	S3PutObjectCopy      *JobPutObjectCopy      `xml:"S3PutObjectCopy,omitempty"`
+}
+
+type JobPutObjectTagging struct {
+	TagSet []Tag `xml:"TagSet>Tag"`
+}
+
+type JobPutObjectRetention struct {
+	BypassGovernanceRetention bool         `xml:"BypassGovernanceRetention,omitempty"`
+	Retention                 JobRetention `xml:"Retention"`
+}
+
+type JobRetention struct {
+	Mode            string     `xml:"Mode,omitempty"`
+	RetainUntilDate *time.Time `xml:"RetainUntilDate,omitempty"`
+}
+
+// JobPutObjectCopy copies the objects to the target bucket, with the target key prefix.
+// An object copied to itself changes its storage class or its metadata.
+type JobPutObjectCopy struct {
+	TargetResource    string `xml:"TargetResource"`
+	TargetKeyPrefix   string `xml:"TargetKeyPrefix,omitempty"`
+	StorageClass      string `xml:"StorageClass,omitempty"`
+	MetadataDirective string `xml:"MetadataDirective,omitempty"`
+}
+
+// JobManifest is a csv object of bucket,key, with the url encoded keys
+type JobManifest struct {
+	ObjectArn string `xml:"Location>ObjectArn"`
+}
+
+// JobManifestGenerator lists the objects under the prefix of the source bucket
+type JobManifestGenerator struct {
+	SourceBucket string `xml:"SourceBucket"`
+	Prefix       string `xml:"Prefix,omitempty"`
+}
+
+type JobReport struct {
+	Bucket  string `xml:"Bucket,omitempty"`
+	Enabled bool   `xml:"Enabled"`
+	Prefix  string `xml:"Prefix,omitempty"`
+}
+
+// JobProgressSummary counts the tasks, the total is only known upfront with a manifest
+type JobProgressSummary struct {
+	TotalNumberOfTasks     int64 `xml:"TotalNumberOfTasks"`
+	NumberOfTasksSucceeded int64 `xml:"NumberOfTasksSucceeded"`
+	NumberOfTasksFailed    int64 `xml:"NumberOfTasksFailed"`
+}
+
+// batchJob is saved in the job entry
+type batchJob struct {
+	XMLName         xml.Name           `xml:"Job"`
+	JobId           string             `xml:"JobId"`
+	Status          string             `xml:"Status"`
+	CreationTime    time.Time          `xml:"CreationTime"`
+	TerminationDate *time.Time         `xml:"TerminationDate,omitempty"`
+	FailureReason   string             `xml:"FailureReason,omitempty"`
+	Request         CreateJobRequest   `xml:"CreateJobRequest"`
+	ProgressSummary JobProgressSummary `xml:"ProgressSummary"`
+	// the last key done with a manifest generator, or the number of manifest lines done
+	Checkpoint string          `xml:"Checkpoint,omitempty"`
+	Failures   []jobTaskResult `xml:"Failure"`
+}
+
+type jobTaskResult struct {
+	Bucket         string `xml:"Bucket"`
+	Key            string `xml:"Key"`
+	ErrorCode      string `xml:"ErrorCode"`
+	HTTPStatusCode int    `xml:"HTTPStatusCode"`
+	ResultMessage  string `xml:"ResultMessage"`
+}
+
+type jobTask struct {
+	bucket string
+	key    string
+	code   ErrorCode
+}
+
+// jobOperation applies the operation of a job to one object
+type jobOperation func(bucket, object string) ErrorCode
+
+func trimArn(resource string) string {
+	return strings.TrimPrefix(resource, arnPrefix)
+}
+
+func (job *batchJob) isRunnable() bool {
+	return job.Status == JobStatusNew || job.Status == JobStatusActive
+}
+
+// isPending tells whether the job is still to run, or is cancelled without the completion report
+func (job *batchJob) isPending() bool {
+	return job.isRunnable() || job.Status == JobStatusCancelled && job.TerminationDate == nil
+}
+
+func (job *batchJob) operationName() string {
+	operation := job.Request.Operation
+	switch {
+	case operation.S3PutObjectTagging != nil:
+		return "S3PutObjectTagging"
+	case operation.S3PutObjectRetention != nil:
+		return "S3PutObjectRetention"
+	case operation.S3PutObjectCopy != nil:
+		return "S3PutObjectCopy"
+	}
+	return ""
+}
+
+func (request *CreateJobRequest) validate() ErrorCode {
+	operation := request.Operation
+	operations := 0
+	for _, set := range []bool{operation.S3PutObjectTagging != nil, operation.S3PutObjectRetention != nil, operation.S3PutObjectCopy != nil} {
+		if set {
+			operations++
+		}
+	}
+	if operations != 1 || (request.Manifest == nil) == (request.ManifestGenerator == nil) {
+		return ErrInvalidJob
+	}
+	if request.Manifest != nil {
+		if bucket, key := pathToBucketAndObject(trimArn(request.Manifest.ObjectArn)); bucket == "" || key == "/" {
+			return ErrInvalidJob
+		}
+	}
+	if request.ManifestGenerator != nil && trimArn(request.ManifestGenerator.SourceBucket) == "" {
+		return ErrInvalidJob
+	}
+	if request.Report.Enabled && trimArn(request.Report.Bucket) == "" {
+		return ErrInvalidJob
+	}
+	return ErrNone
+}
+
+// newJobRequest carries the headers of the operation to the object handlers
+func newJobRequest(header http.Header) *http.Request {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Request{Method: "PUT", URL: &url.URL{Path: "/"}, Header: header}
+}
+
+func (s3a *S3ApiServer) prepareJobOperation(operation JobOperation) (jobOperation, ErrorCode) {
+
+	switch {
+	case operation.S3PutObjectTagging != nil:
+		tags, code := (&Tagging{TagSet: operation.S3PutObjectTagging.TagSet}).toObjectTags()
+		if code != ErrNone {
+			return nil, code
+		}
+		return func(bucket, object string) ErrorCode {
+			return s3a.updateObjectTags(newJobRequest(nil), bucket, object, tags)
+		}, ErrNone
+
+	case operation.S3PutObjectRetention != nil:
+		retention := operation.S3PutObjectRetention
+		if !isValidObjectLockMode(retention.Retention.Mode) || retention.Retention.RetainUntilDate == nil {
+			return nil, ErrMalformedXML
+		}
+		mode, retainUntilDate := retention.Retention.Mode, *retention.Retention.RetainUntilDate
+		return func(bucket, object string) ErrorCode {
+			now := time.Now()
+			if !retainUntilDate.After(now) {
+				return ErrPastObjectLockRetainDate
+			}
+			return s3a.updateObjectLock(newJobRequest(nil), bucket, object, func(lock *objectLock) ErrorCode {
+				if code := lock.checkRetentionChange(mode, retainUntilDate, now, retention.BypassGovernanceRetention); code != ErrNone {
+					return code
+				}
+				lock.mode, lock.retainUntilDate = mode, retainUntilDate
+				return ErrNone
+			})
+		}, ErrNone
+
+	case operation.S3PutObjectCopy != nil:
+		copyOperation := operation.S3PutObjectCopy
+		dstBucket := trimArn(copyOperation.TargetResource)
+		if dstBucket == "" {
+			return nil, ErrInvalidJob
+		}
+		if _, _, code := s3a.storageClasses.tier(dstBucket, copyOperation.StorageClass); code != ErrNone {
+			return nil, code
+		}
+		switch copyOperation.MetadataDirective {
+		case "", "COPY", "REPLACE":
+		default:
+			return nil, ErrInvalidMetadataDirective
+		}
+		header := make(http.Header)
+		if copyOperation.StorageClass != "" {
+			header.Set(AmzStorageClass, copyOperation.StorageClass)
+		}
+		if copyOperation.MetadataDirective != "" {
+			header.Set(AmzMetadataDirective, copyOperation.MetadataDirective)
+		}
+		return func(bucket, object string) ErrorCode {
+			dstObject := "/" + copyOperation.TargetKeyPrefix + strings.TrimPrefix(object, "/")
+			if bucket == dstBucket && object == dstObject && copyOperation.StorageClass == "" && copyOperation.MetadataDirective != "REPLACE" {
+				return ErrInvalidCopySource
+			}
+			r := newJobRequest(header)
+			etag, size, versionId, _, _, code := s3a.copyObject(r, bucket, object, dstBucket, dstObject)
+			if code == ErrNone {
+				s3a.notify(r, EventObjectCreatedCopy, dstBucket, dstObject, size, etag, versionId)
+			}
+			return code
+		}, ErrNone
+	}
+
+	return nil, ErrInvalidJob
+}
+
+func (s3a *S3ApiServer) loopBatchJobs() {
+	owner := uuid.New().String()
+	ticker := time.NewTicker(batchJobInterval)
+	defer ticker.Stop()
+	for {
+		s3a.runBatchJobs(owner)
+		select {
+		case <-ticker.C:
+		case <-s3a.batchJobWakeUp:
+		}
+	}
+}
+
+// runBatchJobs runs the new and unfinished jobs one by one
+func (s3a *S3ApiServer) runBatchJobs(owner string) {
+
+	jobs, err := s3a.listBatchJobs()
+	if err != nil {
+		glog.Errorf("list batch jobs: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if !job.isPending() {
+			continue
+		}
+		if !s3a.acquireEntryLock(batchJobsDir, job.JobId, batchJobLockKey, owner, batchJobLease) {
+			continue
+		}
+		// reload the job, which may be run by another gateway since listed
+		if job, err = s3a.loadBatchJob(job.JobId); err != nil || job == nil || !job.isPending() {
+			continue
+		}
+		if err = s3a.runBatchJob(job, owner); err != nil {
+			glog.Errorf("batch job %s: %v", job.JobId, err)
+		}
+	}
+}
+
+func (s3a *S3ApiServer) runBatchJob(job *batchJob, owner string) error {
+
+	if job.Status == JobStatusCancelled {
+		return s3a.finishBatchJob(job)
+	}
+
+	operation, code := s3a.prepareJobOperation(job.Request.Operation)
+	if code != ErrNone {
+		job.Status, job.FailureReason = JobStatusFailed, getAPIError(code).Description
+		return s3a.finishBatchJob(job)
+	}
+
+	if job.Status == JobStatusNew {
+		job.Status = JobStatusActive
+		if err := s3a.saveBatchJobProgress(job, owner); err != nil {
+			return err
+		}
+	}
+	glog.V(0).Infof("batch job %s %s resumes after %q", job.JobId, job.operationName(), job.Checkpoint)
+
+	err := s3a.runJobTasks(job, owner, operation)
+	switch {
+	case err == errJobCancelled:
+		job.Status = JobStatusCancelled
+	case err != nil:
+		// resumed by the next run
+		return err
+	case job.Status != JobStatusFailed:
+		job.Status = JobStatusComplete
+	}
+
+	glog.V(0).Infof("batch job %s %s: %d succeeded, %d failed", job.JobId, job.Status, job.ProgressSummary.NumberOfTasksSucceeded, job.ProgressSummary.NumberOfTasksFailed)
+	return s3a.finishBatchJob(job)
+}
+
+// runJobTasks applies the operation to the objects after the checkpoint, and saves the progress after each batch
+func (s3a *S3ApiServer) runJobTasks(job *batchJob, owner string, operation jobOperation) error {
+
+	var tasks []jobTask
+	var checkpoint string
+	flush := func() error {
+		s3a.runJobTaskBatch(tasks, operation)
+		for _, task := range tasks {
+			if task.code == ErrNone {
+				job.ProgressSummary.NumberOfTasksSucceeded++
+				continue
+			}
+			job.ProgressSummary.NumberOfTasksFailed++
+			if len(job.Failures) < batchJobMaxReportedFailures {
+				apiError := getAPIError(task.code)
+				job.Failures = append(job.Failures, jobTaskResult{
+					Bucket:         task.bucket,
+					Key:            task.key,
+					ErrorCode:      apiError.Code,
+					HTTPStatusCode: apiError.HTTPStatusCode,
+					ResultMessage:  apiError.Description,
+				})
+			}
+		}
+		tasks = tasks[:0]
+		job.Checkpoint = checkpoint
+		return s3a.saveBatchJobProgress(job, owner)
+	}
+	add := func(bucket, key, taskCheckpoint string) error {
+		tasks = append(tasks, jobTask{bucket: bucket, key: key})
+		checkpoint = taskCheckpoint
+		if len(tasks) < batchJobBatchSize {
+			return nil
+		}
+		return flush()
+	}
+
+	if job.Request.Manifest != nil {
+		manifest, err := s3a.readJobManifest(job.Request.Manifest)
+		if err != nil {
+			job.Status, job.FailureReason = JobStatusFailed, err.Error()
+			return nil
+		}
+		job.ProgressSummary.TotalNumberOfTasks = int64(len(manifest))
+		done, _ := strconv.Atoi(job.Checkpoint)
+		for i := done; i < len(manifest); i++ {
+			if err = add(manifest[i].bucket, manifest[i].key, strconv.Itoa(i+1)); err != nil {
+				return err
+			}
+		}
+	} else {
+		generator := job.Request.ManifestGenerator
+		bucket := trimArn(generator.SourceBucket)
+		if err := s3a.walkJobObjects(bucket, generator.Prefix, job.Checkpoint, func(key string) error {
+			job.ProgressSummary.TotalNumberOfTasks++
+			return add(bucket, key, key)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(tasks) == 0 {
+		return nil
+	}
+	return flush()
+}
+
+// runJobTaskBatch applies the operation to the tasks, with the concurrency of the s3 gateway
+func (s3a *S3ApiServer) runJobTaskBatch(tasks []jobTask, operation jobOperation) {
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, s3a.option.BatchJobConcurrency)
+	for i := range tasks {
+		wg.Add(1)
+		limit <- struct{}{}
+		go func(task *jobTask) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			task.code = operation(task.bucket, "/"+task.key)
+		}(&tasks[i])
+	}
+	wg.Wait()
+}
+
+// walkJobObjects calls fn for the objects under the prefix in the order of the filer listing, after the checkpoint key
+func (s3a *S3ApiServer) walkJobObjects(bucket, prefix, checkpoint string, fn func(key string) error) error {
+	bucketDir := fmt.Sprintf("%s/%s", s3a.option.BucketsPath, bucket)
+	dir, namePrefix := bucketDir, prefix
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir, namePrefix = bucketDir+"/"+prefix[:i], prefix[i+1:]
+	}
+	return s3a.walkJobDirectory(bucketDir, dir, namePrefix, checkpoint, fn)
+}
+
+func (s3a *S3ApiServer) walkJobDirectory(bucketDir, dir, namePrefix, checkpoint string, fn func(key string) error) error {
+
+	lastName := ""
+	for {
+		entries, err := s3a.list(dir, namePrefix, lastName, false, 1024)
+		if err != nil {
+			return fmt.Errorf("list %s: %v", dir, err)
+		}
+		for _, entry := range entries {
+			lastName = entry.Name
+			key := strings.TrimPrefix(dir+"/"+entry.Name, bucketDir+"/")
+			if !entry.IsDirectory {
+				if checkpoint == "" || compareKeyPaths(key, checkpoint) > 0 {
+					if err = fn(key); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if dir == bucketDir && (entry.Name == ".uploads" || entry.Name == versionsFolder) {
+				continue
+			}
+			// skip the directories done before the checkpoint
+			if checkpoint != "" && compareKeyPaths(key, checkpoint) < 0 && !strings.HasPrefix(checkpoint, key+"/") {
+				continue
+			}
+			if err = s3a.walkJobDirectory(bucketDir, dir+"/"+entry.Name, "", checkpoint, fn); err != nil {
+				return err
+			}
+		}
+		if len(entries) < 1024 {
+			return nil
+		}
+	}
+}
+
+// compareKeyPaths compares the keys by their path components, which is the order of the filer listing
+func compareKeyPaths(a, b string) int {
+	aParts, bParts := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+	return len(aParts) - len(bParts)
+}
+
+func (s3a *S3ApiServer) readJobManifest(manifest *JobManifest) ([]jobTask, error) {
+	bucket, object := pathToBucketAndObject(trimArn(manifest.ObjectArn))
+	data, err := util.Get(fmt.Sprintf("http://%s%s/%s%s", s3a.option.Filer, s3a.option.BucketsPath, bucket, object))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest %s%s: %v", bucket, object, err)
+	}
+	return parseJobManifest(data)
+}
+
+// parseJobManifest reads the csv lines of bucket,key, and ignores the other columns
+func parseJobManifest(data []byte) (tasks []jobTask, err error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse manifest: %v", err)
+	}
+	for i, record := range records {
+		if len(record) < 2 || record[0] == "" || record[1] == "" {
+			return nil, fmt.Errorf("manifest line %d: expect bucket,key", i+1)
+		}
+		key, err := url.QueryUnescape(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("manifest line %d: %v", i+1, err)
+		}
+		tasks = append(tasks, jobTask{bucket: trimArn(record[0]), key: strings.TrimPrefix(key, "/")})
+	}
+	return tasks, nil
+}
+
+// finishBatchJob saves the completion report and the final status of the job, and releases the job
+func (s3a *S3ApiServer) finishBatchJob(job *batchJob) error {
+
+	now := time.Now()
+	job.TerminationDate = &now
+	if err := s3a.saveJobReport(job); err != nil {
+		glog.Errorf("save report of batch job %s: %v", job.JobId, err)
+		job.FailureReason = fmt.Sprintf("save report: %v", err)
+	}
+
+	for {
+		entry, err := s3a.getEntry(batchJobsDir, job.JobId)
+		if err != nil || entry == nil {
+			return fmt.Errorf("lookup job: %v", err)
+		}
+		if saved, _ := loadBatchJobFromEntry(entry); saved != nil && saved.Status == JobStatusCancelled {
+			job.Status = JobStatusCancelled
+		}
+		if err = job.saveTo(entry); err != nil {
+			return err
+		}
+		delete(entry.Extended, batchJobLockKey)
+		// retry if the job is cancelled since read
+		if err = s3a.updateEntryIfUnchanged(batchJobsDir, entry); err != filer_pb.ErrPreconditionFailed {
+			return err
+		}
+	}
+}
+
+// saveJobReport saves the failed tasks as a csv object of bucket,key,status,error code,http status code,message
+func (s3a *S3ApiServer) saveJobReport(job *batchJob) error {
+	report := job.Request.Report
+	if !report.Enabled {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	for _, failure := range job.Failures {
+		writer.Write([]string{failure.Bucket, url.QueryEscape(failure.Key), "failed",
+			failure.ErrorCode, strconv.Itoa(failure.HTTPStatusCode), failure.ResultMessage})
+	}
+	writer.Flush()
+
+	key := fmt.Sprintf("job-%s/report.csv", job.JobId)
+	if prefix := strings.Trim(report.Prefix, "/"); prefix != "" {
+		key = prefix + "/" + key
+	}
+	dir, name := util.FullPath(fmt.Sprintf("%s/%s/%s", s3a.option.BucketsPath, trimArn(report.Bucket), key)).DirAndName()
+	now := time.Now().Unix()
+	return s3a.createEntry(dir, &filer_pb.Entry{
+		Name: name,
+		Attributes: &filer_pb.FuseAttributes{
+			Crtime:   now,
+			Mtime:    now,
+			FileMode: uint32(0644),
+			Mime:     "text/csv",
+		},
+		Content: buf.Bytes(),
+	})
+}
+
+// saveBatchJobProgress saves the job if it is not cancelled, and renews the lease of the job
+func (s3a *S3ApiServer) saveBatchJobProgress(job *batchJob, owner string) error {
+
+	for {
+		entry, err := s3a.getEntry(batchJobsDir, job.JobId)
+		if err != nil || entry == nil {
+			return fmt.Errorf("lookup job: %v", err)
+		}
+		if saved, _ := loadBatchJobFromEntry(entry); saved != nil && saved.Status == JobStatusCancelled {
+			return errJobCancelled
+		}
+		if lockOwner, _ := parseEntryLock(entry.Extended[batchJobLockKey]); lockOwner != owner {
+			return fmt.Errorf("lost the lock of the job to %s", lockOwner)
+		}
+
+		if err = job.saveTo(entry); err != nil {
+			return err
+		}
+		entry.Extended[batchJobLockKey] = []byte(fmt.Sprintf("%s,%d", owner, time.Now().Add(batchJobLease).Unix()))
+		// check again if the job is cancelled or taken since read
+		if err = s3a.updateEntryIfUnchanged(batchJobsDir, entry); err != filer_pb.ErrPreconditionFailed {
+			return err
+		}
+	}
+}
+
+func (job *batchJob) saveTo(entry *filer_pb.Entry) error {
+	data, err := xml.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshal job %s: %v", job.JobId, err)
+	}
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	entry.Extended[batchJobKey] = data
+	return nil
+}
+
+func loadBatchJobFromEntry(entry *filer_pb.Entry) (*batchJob, error) {
+	data, found := entry.Extended[batchJobKey]
+	if !found {
+		return nil, nil
+	}
+	job := &batchJob{}
+	if err := xml.Unmarshal(data, job); err != nil {
+		return nil, fmt.Errorf("unmarshal job %s: %v", entry.Name, err)
+	}
+	return job, nil
+}
+
+func (s3a *S3ApiServer) createBatchJob(job *batchJob) error {
+	entry := &filer_pb.Entry{
+		Name: job.JobId,
+		Attributes: &filer_pb.FuseAttributes{
+			Crtime:   job.CreationTime.Unix(),
+			Mtime:    job.CreationTime.Unix(),
+			FileMode: uint32(0644),
+		},
+	}
+	if err := job.saveTo(entry); err != nil {
+		return err
+	}
+	entry.Extended[filer2.ETagKey] = []byte(uuid.New().String())
+	return s3a.createEntry(batchJobsDir, entry)
+}
+
+func (s3a *S3ApiServer) loadBatchJob(jobId string) (*batchJob, error) {
+	entry, err := s3a.getEntry(batchJobsDir, jobId)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return loadBatchJobFromEntry(entry)
+}
+
+func (s3a *S3ApiServer) listBatchJobs() (jobs []*batchJob, err error) {
+	entries, err := s3a.list(batchJobsDir, "", "", false, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		job, err := loadBatchJobFromEntry(entry)
+		if err != nil {
+			glog.Errorf("load batch job: %v", err)
+			continue
+		}
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// cancelBatchJob marks the job as cancelled. The gateway running the job stops after the current batch of tasks,
+// and saves the completion report. The job is saved only if unchanged since read, so the cancellation is not
+// overwritten by the progress of the running job, nor a finished job cancelled.
+func (s3a *S3ApiServer) cancelBatchJob(jobId string) (*batchJob, ErrorCode) {
+
+	for {
+		entry, err := s3a.getEntry(batchJobsDir, jobId)
+		if err != nil {
+			glog.Errorf("lookup batch job %s: %v", jobId, err)
+			return nil, ErrInternalError
+		}
+		if entry == nil {
+			return nil, ErrNoSuchJob
+		}
+		job, err := loadBatchJobFromEntry(entry)
+		if err != nil || job == nil {
+			return nil, ErrNoSuchJob
+		}
+		if !job.isRunnable() {
+			return nil, ErrJobStatus
+		}
+
+		job.Status = JobStatusCancelled
+		if err = job.saveTo(entry); err != nil {
+			return nil, ErrInternalError
+		}
+		err = s3a.updateEntryIfUnchanged(batchJobsDir, entry)
+		if err == filer_pb.ErrPreconditionFailed {
+			glog.V(1).Infof("batch job %s changed since read", jobId)
+			continue
+		}
+		if err != nil {
+			glog.Errorf("cancel batch job %s: %v", jobId, err)
+			return nil, ErrInternalError
+		}
+		return job, ErrNone
+	}
+}
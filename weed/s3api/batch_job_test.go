@@ -0,0 +1,278 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/karlseguin/ccache"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func testBatchJobEntries() map[util.FullPath]*filer_pb.Entry {
+	return map[util.FullPath]*filer_pb.Entry{
+		"/buckets":              {Name: "buckets", IsDirectory: true},
+		"/buckets/bucket":       {IsDirectory: true},
+		"/buckets/bucket/a":     {IsDirectory: true},
+		"/buckets/bucket/a/1":   {},
+		"/buckets/bucket/a/2":   {},
+		"/buckets/bucket/a/b":   {IsDirectory: true},
+		"/buckets/bucket/a/b/3": {},
+		"/buckets/bucket/c":     {},
+		"/buckets/reports":      {IsDirectory: true},
+	}
+}
+
+func startBatchJobFiler(t *testing.T, entries map[util.FullPath]*filer_pb.Entry) (*S3ApiServer, func()) {
+	s3a, stop := startFakeFiler(t, entries)
+	s3a.option.BatchJobConcurrency = 2
+	s3a.replicationCache = ccache.New(ccache.Configure())
+	return s3a, stop
+}
+
+func createJob(t *testing.T, s3a *S3ApiServer, request CreateJobRequest) string {
+	body, _ := xml.Marshal(request)
+	w := httptest.NewRecorder()
+	s3a.CreateJobHandler(w, httptest.NewRequest("POST", "/v20180820/jobs", strings.NewReader(string(body))))
+	if w.Code != http.StatusOK {
+		t.Fatalf("create job: %d %s", w.Code, w.Body.String())
+	}
+	var result CreateJobResult
+	if err := xml.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal %s: %v", w.Body.String(), err)
+	}
+	return result.JobId
+}
+
+func describeJob(t *testing.T, s3a *S3ApiServer, jobId string) JobDescriptor {
+	r := httptest.NewRequest("GET", "/v20180820/jobs/"+jobId, nil)
+	r = mux.SetURLVars(r, map[string]string{"id": jobId})
+	w := httptest.NewRecorder()
+	s3a.DescribeJobHandler(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("describe job: %d %s", w.Code, w.Body.String())
+	}
+	var result DescribeJobResult
+	if err := xml.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal %s: %v", w.Body.String(), err)
+	}
+	return result.Job
+}
+
+func TestBatchJobTagging(t *testing.T) {
+	entries := testBatchJobEntries()
+	s3a, stop := startBatchJobFiler(t, entries)
+	defer stop()
+
+	jobId := createJob(t, s3a, CreateJobRequest{
+		Operation: JobOperation{S3PutObjectTagging: &JobPutObjectTagging{
+			TagSet: []Tag{{Key: "project", Value: "archive"}},
+		}},
+		ManifestGenerator: &JobManifestGenerator{SourceBucket: "arn:aws:s3:::bucket", Prefix: "a/"},
+		Report:            JobReport{Enabled: true, Bucket: "arn:aws:s3:::reports", Prefix: "batch"},
+	})
+	if job := describeJob(t, s3a, jobId); job.Status != JobStatusNew {
+		t.Fatalf("new job status %s", job.Status)
+	}
+
+	s3a.runBatchJobs("gateway1")
+
+	for _, p := range []util.FullPath{"/buckets/bucket/a/1", "/buckets/bucket/a/2", "/buckets/bucket/a/b/3"} {
+		if tags := loadObjectTags(entries[p].Extended); tags["project"] != "archive" {
+			t.Errorf("%s tags %v", p, tags)
+		}
+	}
+	if tags := loadObjectTags(entries["/buckets/bucket/c"].Extended); len(tags) != 0 {
+		t.Errorf("tagged the object out of the prefix")
+	}
+
+	job := describeJob(t, s3a, jobId)
+	if job.Status != JobStatusComplete || job.TerminationDate == nil {
+		t.Errorf("job status %s terminated at %v", job.Status, job.TerminationDate)
+	}
+	if job.ProgressSummary.NumberOfTasksSucceeded != 3 || job.ProgressSummary.NumberOfTasksFailed != 0 {
+		t.Errorf("progress %+v", job.ProgressSummary)
+	}
+	report, found := entries[util.FullPath("/buckets/reports/batch/job-"+jobId+"/report.csv")]
+	if !found || len(report.Content) != 0 {
+		t.Errorf("expect an empty completion report, found %v", found)
+	}
+}
+
+func TestBatchJobFailuresReport(t *testing.T) {
+	entries := testBatchJobEntries()
+	s3a, stop := startBatchJobFiler(t, entries)
+	defer stop()
+
+	// the bucket has no object lock configuration
+	retainUntilDate := time.Now().Add(time.Hour).UTC()
+	jobId := createJob(t, s3a, CreateJobRequest{
+		Operation: JobOperation{S3PutObjectRetention: &JobPutObjectRetention{
+			Retention: JobRetention{Mode: ObjectLockModeGovernance, RetainUntilDate: &retainUntilDate},
+		}},
+		ManifestGenerator: &JobManifestGenerator{SourceBucket: "bucket", Prefix: "a/b"},
+		Report:            JobReport{Enabled: true, Bucket: "reports"},
+	})
+
+	s3a.runBatchJobs("gateway1")
+
+	job := describeJob(t, s3a, jobId)
+	if job.Status != JobStatusComplete || job.ProgressSummary.NumberOfTasksFailed != 1 {
+		t.Errorf("job status %s progress %+v", job.Status, job.ProgressSummary)
+	}
+	report := entries[util.FullPath("/buckets/reports/job-"+jobId+"/report.csv")]
+	if report == nil || !strings.HasPrefix(string(report.Content), "bucket,a%2Fb%2F3,failed,InvalidRequest,400,") {
+		t.Errorf("unexpected completion report %+v", report)
+	}
+}
+
+func TestBatchJobResumeAndCancel(t *testing.T) {
+	entries := testBatchJobEntries()
+	s3a, stop := startBatchJobFiler(t, entries)
+	defer stop()
+
+	request := CreateJobRequest{
+		Operation: JobOperation{S3PutObjectTagging: &JobPutObjectTagging{
+			TagSet: []Tag{{Key: "k", Value: "v"}},
+		}},
+		ManifestGenerator: &JobManifestGenerator{SourceBucket: "bucket"},
+	}
+
+	// a job interrupted after a/2
+	resumed := &batchJob{JobId: "resumed", Status: JobStatusActive, CreationTime: time.Now(), Request: request, Checkpoint: "a/2"}
+	resumed.ProgressSummary.NumberOfTasksSucceeded = 2
+	if err := s3a.createBatchJob(resumed); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	cancelled := createJob(t, s3a, request)
+
+	r := httptest.NewRequest("POST", "/v20180820/jobs/"+cancelled+"/status?requestedJobStatus=Cancelled", nil)
+	r = mux.SetURLVars(r, map[string]string{"id": cancelled, "requestedJobStatus": JobStatusCancelled})
+	w := httptest.NewRecorder()
+	s3a.UpdateJobStatusHandler(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("cancel job: %d %s", w.Code, w.Body.String())
+	}
+
+	s3a.runBatchJobs("gateway1")
+
+	for _, p := range []util.FullPath{"/buckets/bucket/a/1", "/buckets/bucket/a/2"} {
+		if len(entries[p].Extended) != 0 {
+			t.Errorf("%s before the checkpoint is tagged again", p)
+		}
+	}
+	for _, p := range []util.FullPath{"/buckets/bucket/a/b/3", "/buckets/bucket/c"} {
+		if tags := loadObjectTags(entries[p].Extended); tags["k"] != "v" {
+			t.Errorf("%s after the checkpoint tags %v", p, tags)
+		}
+	}
+	if job := describeJob(t, s3a, "resumed"); job.Status != JobStatusComplete || job.ProgressSummary.NumberOfTasksSucceeded != 4 {
+		t.Errorf("resumed job status %s progress %+v", job.Status, job.ProgressSummary)
+	}
+	if job := describeJob(t, s3a, cancelled); job.Status != JobStatusCancelled || job.TerminationDate == nil || job.ProgressSummary.NumberOfTasksSucceeded != 0 {
+		t.Errorf("cancelled job status %s progress %+v", job.Status, job.ProgressSummary)
+	}
+
+	w = httptest.NewRecorder()
+	s3a.UpdateJobStatusHandler(w, r)
+	if w.Code != http.StatusConflict {
+		t.Errorf("cancel the cancelled job: %d", w.Code)
+	}
+}
+
+func TestCancelBatchJobWhileRunning(t *testing.T) {
+	s3a, stop := startBatchJobFiler(t, testBatchJobEntries())
+	defer stop()
+
+	job := &batchJob{JobId: "running", Status: JobStatusActive, CreationTime: time.Now()}
+	if err := s3a.createBatchJob(job); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	var wg sync.WaitGroup
+	lockOwners := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(owner string) {
+			defer wg.Done()
+			if s3a.acquireEntryLock(batchJobsDir, job.JobId, batchJobLockKey, owner, batchJobLease) {
+				lockOwners <- owner
+			}
+		}(fmt.Sprintf("gateway%d", i))
+	}
+	wg.Wait()
+	close(lockOwners)
+	owner := <-lockOwners
+	if owner == "" || len(lockOwners) != 0 {
+		t.Fatalf("job locked by %s and %d more", owner, len(lockOwners))
+	}
+
+	// the progress of the running job does not overwrite the cancellation
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			progress := *job
+			progress.ProgressSummary.NumberOfTasksSucceeded = int64(i)
+			errs <- s3a.saveBatchJobProgress(&progress, owner)
+		}(i)
+	}
+	if _, code := s3a.cancelBatchJob(job.JobId); code != ErrNone {
+		t.Errorf("cancel job: %v", code)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil && err != errJobCancelled {
+			t.Errorf("save progress: %v", err)
+		}
+	}
+	if saved, err := s3a.loadBatchJob(job.JobId); err != nil || saved.Status != JobStatusCancelled {
+		t.Errorf("job %+v: %v", saved, err)
+	}
+	if err := s3a.saveBatchJobProgress(job, owner); err != errJobCancelled {
+		t.Errorf("save progress of the cancelled job: %v", err)
+	}
+}
+
+func TestCompareKeyPaths(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		result int
+	}{
+		{"a/1", "a/1", 0},
+		{"a/1", "a/2", -1},
+		{"a/b/3", "a/2", 1},
+		{"a", "a.txt", -1},
+		// the directory a is listed before the file a-b
+		{"a/z", "a-b", -1},
+		{"a", "a/1", -1},
+	}
+	for _, tt := range tests {
+		result := compareKeyPaths(tt.a, tt.b)
+		if result < 0 && tt.result >= 0 || result > 0 && tt.result <= 0 || result == 0 && tt.result != 0 {
+			t.Errorf("compareKeyPaths(%q, %q) = %d, expect %d", tt.a, tt.b, result, tt.result)
+		}
+	}
+}
+
+func TestParseJobManifest(t *testing.T) {
+	tasks, err := parseJobManifest([]byte("bucket,a%2F1\narn:aws:s3:::other,b%20c,version1\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].bucket != "bucket" || tasks[0].key != "a/1" || tasks[1].bucket != "other" || tasks[1].key != "b c" {
+		t.Errorf("unexpected tasks %+v", tasks)
+	}
+	if _, err := parseJobManifest([]byte("bucket\n")); err == nil {
+		t.Errorf("parse a line without key")
+	}
+}
@@ -0,0 +1,240 @@
+package s3api
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/checking-object-integrity.html
+
+const (
+	ChecksumAlgorithmCRC32  = "CRC32"
+	ChecksumAlgorithmCRC32C = "CRC32C"
+	ChecksumAlgorithmSHA1   = "SHA1"
+	ChecksumAlgorithmSHA256 = "SHA256"
+
+	// request headers, the checksum algorithm is also used as the extended attribute key on the upload entry
+	AmzChecksumAlgorithm    = "X-Amz-Checksum-Algorithm"
+	AmzSdkChecksumAlgorithm = "X-Amz-Sdk-Checksum-Algorithm"
+	AmzChecksumMode         = "X-Amz-Checksum-Mode"
+	AmzTrailer              = "X-Amz-Trailer"
+
+	// x-amz-checksum-<algorithm> request and response headers, also used as the extended attribute keys
+	amzChecksumPrefix = "X-Amz-Checksum-"
+)
+
+var checksumAlgorithms = map[string]func() hash.Hash{
+	ChecksumAlgorithmCRC32: func() hash.Hash {
+		return crc32.NewIEEE()
+	},
+	ChecksumAlgorithmCRC32C: func() hash.Hash {
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	},
+	ChecksumAlgorithmSHA1:   sha1.New,
+	ChecksumAlgorithmSHA256: sha256.New,
+}
+
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// checksumHeader is the header carrying the checksum of the algorithm, e.g. X-Amz-Checksum-Crc32c
+func checksumHeader(algorithm string) string {
+	return http.CanonicalHeaderKey(amzChecksumPrefix + algorithm)
+}
+
+// objectChecksum is the base64 encoded checksum of an object or a part.
+// The checksum of a multipart object is the checksum of the part checksums, suffixed with -<number of parts>.
+type objectChecksum struct {
+	algorithm string
+	value     string
+}
+
+// loadObjectChecksum returns nil if no checksum is stored
+func loadObjectChecksum(extended map[string][]byte) *objectChecksum {
+	for algorithm := range checksumAlgorithms {
+		if value, found := extended[checksumHeader(algorithm)]; found {
+			return &objectChecksum{algorithm: algorithm, value: string(value)}
+		}
+	}
+	return nil
+}
+
+func (checksum *objectChecksum) saveTo(extended map[string][]byte) {
+	for algorithm := range checksumAlgorithms {
+		delete(extended, checksumHeader(algorithm))
+	}
+	extended[checksumHeader(checksum.algorithm)] = []byte(checksum.value)
+}
+
+func setChecksumHeaders(w http.ResponseWriter, checksum *objectChecksum) {
+	if checksum != nil {
+		w.Header().Set(checksumHeader(checksum.algorithm), checksum.value)
+	}
+}
+
+// parseChecksumAlgorithm validates the algorithm named in a request header, an empty name is valid
+func parseChecksumAlgorithm(name string) (string, ErrorCode) {
+	algorithm := strings.ToUpper(name)
+	if _, found := checksumAlgorithms[algorithm]; algorithm != "" && !found {
+		return "", ErrInvalidRequest
+	}
+	return algorithm, ErrNone
+}
+
+// checksumReader computes the checksum of the data passing through,
+// failing the read at the end of the data if it does not match the checksum declared by the request
+type checksumReader struct {
+	reader    io.Reader
+	algorithm string
+	hash      hash.Hash
+	expected  string
+	mismatch  bool
+	// the checksum is in the trailing headers of the request, known at the end of the data
+	trailer *http.Request
+	// the digest of the Content-MD5 header, verified along with the checksum
+	contentMd5 []byte
+	md5Hash    hash.Hash
+}
+
+// newChecksumReader verifies the data with the x-amz-checksum-<algorithm> header of the request,
+// or the trailing header declared by x-amz-trailer, if any.
+// The checksum of algorithm is computed when the request declares none.
+func newChecksumReader(r *http.Request, data io.Reader, algorithm string) (*checksumReader, ErrorCode) {
+
+	cr := &checksumReader{reader: data, algorithm: algorithm}
+
+	declared, code := parseChecksumAlgorithm(r.Header.Get(AmzSdkChecksumAlgorithm))
+	if code != ErrNone {
+		return nil, code
+	}
+	for name := range checksumAlgorithms {
+		if value := r.Header.Get(checksumHeader(name)); value != "" {
+			if cr.expected != "" || (declared != "" && declared != name) {
+				return nil, ErrInvalidRequest
+			}
+			if algorithm != "" && algorithm != name {
+				return nil, ErrInvalidRequest
+			}
+			cr.algorithm, cr.expected = name, value
+		}
+	}
+
+	if trailer := http.CanonicalHeaderKey(strings.TrimSpace(r.Header.Get(AmzTrailer))); strings.HasPrefix(trailer, amzChecksumPrefix) {
+		trailed, code := parseChecksumAlgorithm(trailer[len(amzChecksumPrefix):])
+		if code != ErrNone || trailed == "" || cr.expected != "" || (declared != "" && declared != trailed) {
+			return nil, ErrInvalidRequest
+		}
+		if algorithm != "" && algorithm != trailed {
+			return nil, ErrInvalidRequest
+		}
+		cr.algorithm, cr.trailer = trailed, r
+	}
+
+	if cr.algorithm != "" {
+		cr.hash = checksumAlgorithms[cr.algorithm]()
+	}
+	return cr, ErrNone
+}
+
+// verifyContentMd5 also verifies the data with the Content-MD5 header of the request, if any
+func (cr *checksumReader) verifyContentMd5(r *http.Request) ErrorCode {
+	contentMd5, err := validateContentMd5(r.Header)
+	if err != nil {
+		return ErrInvalidDigest
+	}
+	if len(contentMd5) > 0 {
+		cr.contentMd5, cr.md5Hash = contentMd5, md5.New()
+	}
+	return ErrNone
+}
+
+func (cr *checksumReader) Read(p []byte) (n int, err error) {
+	n, err = cr.reader.Read(p)
+	if cr.md5Hash != nil {
+		cr.md5Hash.Write(p[:n])
+	}
+	if cr.hash != nil {
+		cr.hash.Write(p[:n])
+	}
+	if err != io.EOF {
+		return
+	}
+	if cr.md5Hash != nil && !bytes.Equal(cr.md5Hash.Sum(nil), cr.contentMd5) {
+		cr.mismatch = true
+		return n, errChecksumMismatch
+	}
+	if cr.hash == nil {
+		return
+	}
+	expected := cr.expected
+	if cr.trailer != nil {
+		// a missing trailing checksum fails the same as a wrong one
+		if expected = cr.trailer.Trailer.Get(checksumHeader(cr.algorithm)); expected == "" {
+			expected = "-"
+		}
+	}
+	if expected != "" && cr.sum() != expected {
+		cr.mismatch = true
+		return n, errChecksumMismatch
+	}
+	return
+}
+
+func (cr *checksumReader) sum() string {
+	return base64.StdEncoding.EncodeToString(cr.hash.Sum(nil))
+}
+
+// verifyRequestDigest verifies the request body, already read in full,
+// with the Content-MD5 and the x-amz-checksum-<algorithm> headers of the request
+func verifyRequestDigest(r *http.Request, body []byte) ErrorCode {
+	cr, errCode := newChecksumReader(r, bytes.NewReader(body), "")
+	if errCode != ErrNone {
+		return errCode
+	}
+	if errCode = cr.verifyContentMd5(r); errCode != ErrNone {
+		return errCode
+	}
+	io.Copy(ioutil.Discard, cr)
+	if cr.mismatch {
+		return ErrBadDigest
+	}
+	return ErrNone
+}
+
+// checksum returns nil if no checksum is computed
+func (cr *checksumReader) checksum() *objectChecksum {
+	if cr.hash == nil {
+		return nil
+	}
+	return &objectChecksum{algorithm: cr.algorithm, value: cr.sum()}
+}
+
+// compositeChecksum computes the multipart object checksum from the part checksums, in part order
+func compositeChecksum(algorithm string, partChecksums []string) (*objectChecksum, error) {
+	newHash, found := checksumAlgorithms[algorithm]
+	if !found {
+		return nil, fmt.Errorf("unknown checksum algorithm %s", algorithm)
+	}
+	h := newHash()
+	for _, partChecksum := range partChecksums {
+		digest, err := base64.StdEncoding.DecodeString(partChecksum)
+		if err != nil {
+			return nil, fmt.Errorf("part checksum %s: %v", partChecksum, err)
+		}
+		h.Write(digest)
+	}
+	return &objectChecksum{
+		algorithm: algorithm,
+		value:     fmt.Sprintf("%s-%d", base64.StdEncoding.EncodeToString(h.Sum(nil)), len(partChecksums)),
+	}, nil
+}
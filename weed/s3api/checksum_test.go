@@ -0,0 +1,150 @@
+package s3api
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestChecksumReader(t *testing.T) {
+
+	tests := []struct {
+		header   string
+		value    string
+		mismatch bool
+	}{
+		// crc32 and crc32c of "123456789" are 0xCBF43926 and 0xE3069283
+		{"x-amz-checksum-crc32", base64.StdEncoding.EncodeToString([]byte{0xcb, 0xf4, 0x39, 0x26}), false},
+		{"x-amz-checksum-crc32c", base64.StdEncoding.EncodeToString([]byte{0xe3, 0x06, 0x92, 0x83}), false},
+		{"x-amz-checksum-sha1", "9Uw0yFh3Nn/L9EQkV1t3KVAEXFs=", true},
+		{"x-amz-checksum-crc32", base64.StdEncoding.EncodeToString([]byte{0, 0, 0, 0}), true},
+	}
+
+	for _, test := range tests {
+		r := &http.Request{Header: make(http.Header)}
+		r.Header.Set(test.header, test.value)
+		cr, errCode := newChecksumReader(r, strings.NewReader("123456789"), "")
+		if errCode != ErrNone {
+			t.Fatalf("%s: %v", test.header, errCode)
+		}
+		_, err := ioutil.ReadAll(cr)
+		if cr.mismatch != test.mismatch || (err != nil) != test.mismatch {
+			t.Errorf("%s %s: mismatch %v, error %v", test.header, test.value, cr.mismatch, err)
+		}
+		if !test.mismatch && cr.checksum().value != test.value {
+			t.Errorf("%s: checksum %+v", test.header, cr.checksum())
+		}
+	}
+}
+
+func TestChecksumReaderHeaders(t *testing.T) {
+
+	r := &http.Request{Header: make(http.Header)}
+	cr, errCode := newChecksumReader(r, strings.NewReader("data"), "")
+	if errCode != ErrNone || cr.checksum() != nil {
+		t.Errorf("no checksum: %v %+v", errCode, cr.checksum())
+	}
+
+	cr, _ = newChecksumReader(r, strings.NewReader("data"), ChecksumAlgorithmSHA256)
+	ioutil.ReadAll(cr)
+	sum := sha256.Sum256([]byte("data"))
+	if checksum := cr.checksum(); checksum.algorithm != ChecksumAlgorithmSHA256 || checksum.value != base64.StdEncoding.EncodeToString(sum[:]) {
+		t.Errorf("upload checksum algorithm: %+v", checksum)
+	}
+
+	r.Header.Set(AmzSdkChecksumAlgorithm, "MD4")
+	if _, errCode := newChecksumReader(r, strings.NewReader("data"), ""); errCode != ErrInvalidRequest {
+		t.Errorf("unknown algorithm: %v", errCode)
+	}
+
+	r = &http.Request{Header: make(http.Header)}
+	r.Header.Set("x-amz-checksum-crc32", "AAAAAA==")
+	r.Header.Set("x-amz-checksum-sha1", "AAAAAA==")
+	if _, errCode := newChecksumReader(r, strings.NewReader("data"), ""); errCode != ErrInvalidRequest {
+		t.Errorf("two checksums: %v", errCode)
+	}
+}
+
+func TestCompositeChecksum(t *testing.T) {
+
+	part1, part2 := sha256.Sum256([]byte("part1")), sha256.Sum256([]byte("part2"))
+	composite := sha256.Sum256(append(part1[:], part2[:]...))
+
+	checksum, err := compositeChecksum(ChecksumAlgorithmSHA256, []string{
+		base64.StdEncoding.EncodeToString(part1[:]),
+		base64.StdEncoding.EncodeToString(part2[:]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := base64.StdEncoding.EncodeToString(composite[:]) + "-2"; checksum.value != expected {
+		t.Errorf("composite checksum %s, expecting %s", checksum.value, expected)
+	}
+
+	extended := make(map[string][]byte)
+	checksum.saveTo(extended)
+	if !bytes.Equal(extended["X-Amz-Checksum-Sha256"], []byte(checksum.value)) {
+		t.Errorf("saved checksum %v", extended)
+	}
+	if loaded := loadObjectChecksum(extended); *loaded != *checksum {
+		t.Errorf("loaded checksum %+v", loaded)
+	}
+}
+
+func TestContentMd5(t *testing.T) {
+
+	sum := md5.Sum([]byte("123456789"))
+	digest := base64.StdEncoding.EncodeToString(sum[:])
+	wrong := md5.Sum([]byte("12345678"))
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected ErrorCode
+	}{
+		{"no digest", nil, ErrNone},
+		{"correct", map[string]string{"Content-Md5": digest}, ErrNone},
+		{"wrong", map[string]string{"Content-Md5": base64.StdEncoding.EncodeToString(wrong[:])}, ErrBadDigest},
+		{"not base64", map[string]string{"Content-Md5": "not base64!"}, ErrInvalidDigest},
+		{"not md5", map[string]string{"Content-Md5": "AAAA"}, ErrInvalidDigest},
+		{"empty", map[string]string{"Content-Md5": ""}, ErrInvalidDigest},
+		{"with checksum", map[string]string{"Content-Md5": digest, "x-amz-checksum-crc32": "y/Q5Jg=="}, ErrNone},
+		{"with wrong checksum", map[string]string{"Content-Md5": digest, "x-amz-checksum-crc32": "AAAAAA=="}, ErrBadDigest},
+	}
+
+	for _, test := range tests {
+		r := &http.Request{Header: make(http.Header)}
+		for k, v := range test.headers {
+			r.Header[http.CanonicalHeaderKey(k)] = []string{v}
+		}
+		if errCode := verifyRequestDigest(r, []byte("123456789")); errCode != test.expected {
+			t.Errorf("%s: %v, expected %v", test.name, errCode, test.expected)
+		}
+	}
+}
+
+func TestDeleteObjectsContentMd5(t *testing.T) {
+
+	body := `<Delete><Object><Key>a</Key></Object></Delete>`
+	for digest, expected := range map[string]int{
+		"AAAAAAAAAAAAAAAAAAAAAA==": http.StatusBadRequest,
+		"bad":                      http.StatusBadRequest,
+	} {
+		r := httptest.NewRequest("POST", "/bucket?delete", strings.NewReader(body))
+		r = mux.SetURLVars(r, map[string]string{"bucket": "bucket"})
+		r.Header.Set("Content-Md5", digest)
+		w := httptest.NewRecorder()
+		(&S3ApiServer{}).DeleteMultipleObjectsHandler(w, r)
+		if w.Code != expected {
+			t.Errorf("digest %s: status %d", digest, w.Code)
+		}
+	}
+}
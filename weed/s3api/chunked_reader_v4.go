@@ -27,6 +27,8 @@ import (
 	"hash"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -44,7 +46,7 @@ func getChunkSignature(secretKey string, seedSignature string, region string, da
 		hashedChunk
 
 	// Get hmac signing key.
-	signingKey := getSigningKey(secretKey, date, region)
+	signingKey := getSigningKey(secretKey, date, region, "s3")
 
 	// Calculate signature.
 	newSignature := getSignature(signingKey, stringToSign)
@@ -56,7 +58,7 @@ func getChunkSignature(secretKey string, seedSignature string, region string, da
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-streaming.html
 // returns signature, error otherwise if the signature mismatches or any other
 // error while parsing and validating.
-func (iam *IdentityAccessManagement) calculateSeedSignature(r *http.Request) (cred *Credential, signature string, region string, date time.Time, errCode ErrorCode) {
+func (iam *IdentityAccessManagement) calculateSeedSignature(r *http.Request) (identity *Identity, cred *Credential, signature string, region string, date time.Time, errCode ErrorCode) {
 
 	// Copy request.
 	req := *r
@@ -67,43 +69,44 @@ func (iam *IdentityAccessManagement) calculateSeedSignature(r *http.Request) (cr
 	// Parse signature version '4' header.
 	signV4Values, errCode := parseSignV4(v4Auth)
 	if errCode != ErrNone {
-		return nil, "", "", time.Time{}, errCode
+		return nil, nil, "", "", time.Time{}, errCode
 	}
 
 	// Payload streaming.
-	payload := streamingContentSHA256
+	payload := req.Header.Get("X-Amz-Content-Sha256")
 
-	// Payload for STREAMING signature should be 'STREAMING-AWS4-HMAC-SHA256-PAYLOAD'
-	if payload != req.Header.Get("X-Amz-Content-Sha256") {
-		return nil, "", "", time.Time{}, ErrContentSHA256Mismatch
+	// Payload for STREAMING signature should be 'STREAMING-AWS4-HMAC-SHA256-PAYLOAD', or with the trailer
+	if payload != streamingContentSHA256 && payload != streamingContentSHA256Trailer {
+		return nil, nil, "", "", time.Time{}, ErrContentSHA256Mismatch
 	}
 
 	// Extract all the signed headers along with its values.
 	extractedSignedHeaders, errCode := extractSignedHeaders(signV4Values.SignedHeaders, r)
 	if errCode != ErrNone {
-		return nil, "", "", time.Time{}, errCode
+		return nil, nil, "", "", time.Time{}, errCode
 	}
 	// Verify if the access key id matches.
-	_, cred, found := iam.lookupByAccessKey(signV4Values.Credential.accessKey)
-	if !found {
-		return nil, "", "", time.Time{}, ErrInvalidAccessKeyID
+	identity, cred, errCode = iam.lookupCredential(r, signV4Values.Credential.accessKey)
+	if errCode != ErrNone {
+		return nil, nil, "", "", time.Time{}, errCode
 	}
 
 	// Verify if region is valid.
 	region = signV4Values.Credential.scope.region
+	if errCode = iam.checkRegion(r, region); errCode != ErrNone {
+		return nil, nil, "", "", time.Time{}, errCode
+	}
+	if errCode = checkService(signV4Values.Credential, signingServiceS3); errCode != ErrNone {
+		return nil, nil, "", "", time.Time{}, errCode
+	}
 
 	// Extract date, if not present throw error.
-	var dateStr string
-	if dateStr = req.Header.Get(http.CanonicalHeaderKey("x-amz-date")); dateStr == "" {
-		if dateStr = r.Header.Get("Date"); dateStr == "" {
-			return nil, "", "", time.Time{}, ErrMissingDateHeader
-		}
+	date, errCode = parseSignV4Date(&req)
+	if errCode != ErrNone {
+		return nil, nil, "", "", time.Time{}, errCode
 	}
-	// Parse date header.
-	var err error
-	date, err = time.Parse(iso8601Format, dateStr)
-	if err != nil {
-		return nil, "", "", time.Time{}, ErrMalformedDate
+	if errCode = checkRequestTime(date); errCode != ErrNone {
+		return nil, nil, "", "", time.Time{}, errCode
 	}
 
 	// Query string.
@@ -116,18 +119,25 @@ func (iam *IdentityAccessManagement) calculateSeedSignature(r *http.Request) (cr
 	stringToSign := getStringToSign(canonicalRequest, date, signV4Values.Credential.getScope())
 
 	// Get hmac signing key.
-	signingKey := getSigningKey(cred.SecretKey, signV4Values.Credential.scope.date, region)
+	signingKey := getSigningKey(cred.SecretKey, signV4Values.Credential.scope.date, region, "s3")
 
 	// Calculate signature.
 	newSignature := getSignature(signingKey, stringToSign)
 
 	// Verify if signature match.
 	if !compareSignatureV4(newSignature, signV4Values.Signature) {
-		return nil, "", "", time.Time{}, ErrSignatureDoesNotMatch
+		return nil, nil, "", "", time.Time{}, ErrSignatureDoesNotMatch
 	}
 
 	// Return caculated signature.
-	return cred, newSignature, region, date, ErrNone
+	return identity, cred, newSignature, region, date, ErrNone
+}
+
+// doesSeedSignatureMatch verifies the seed signature of the streaming upload,
+// the chunk signatures are verified while reading the body
+func (iam *IdentityAccessManagement) doesSeedSignatureMatch(r *http.Request) (*Identity, ErrorCode) {
+	identity, _, _, _, _, errCode := iam.calculateSeedSignature(r)
+	return identity, errCode
 }
 
 const maxLineLength = 4 * humanize.KiByte // assumed <= bufio.defaultBufSize 4KiB
@@ -138,29 +148,88 @@ var errLineTooLong = errors.New("header line too long")
 // Malformed encoding is generated when chunk header is wrongly formed.
 var errMalformedEncoding = errors.New("malformed chunked encoding")
 
+// errSignatureMismatch is generated when a chunk or the trailer signature does not verify.
+var errSignatureMismatch = errors.New("chunk signature does not match")
+
+// errIncompleteBody is generated when the decoded data is not of x-amz-decoded-content-length.
+var errIncompleteBody = errors.New("decoded content length mismatch")
+
+// newStreamingReader decodes the aws-chunked body of the streaming uploads, other bodies are returned as is.
+// The trailing headers are set to req.Trailer when the body is fully read.
+func (iam *IdentityAccessManagement) newStreamingReader(req *http.Request) (io.ReadCloser, ErrorCode) {
+	switch req.Header.Get("X-Amz-Content-Sha256") {
+	case streamingContentSHA256, streamingContentSHA256Trailer:
+		if req.Method == http.MethodPut {
+			cr, errCode := iam.newSignV4ChunkedReader(req)
+			if errCode != ErrNone {
+				return nil, errCode
+			}
+			return cr, ErrNone
+		}
+	case streamingUnsignedPayloadTrailer:
+		cr := newChunkedReader(req)
+		cr.unsigned = true
+		return cr, ErrNone
+	}
+	return req.Body, ErrNone
+}
+
+// removeAwsChunkedEncoding removes aws-chunked from the content encodings, any other encodings apply to the decoded data
+func removeAwsChunkedEncoding(contentEncoding string) string {
+	var encodings []string
+	for _, encoding := range strings.Split(contentEncoding, ",") {
+		if encoding = strings.TrimSpace(encoding); encoding != "" && encoding != "aws-chunked" {
+			encodings = append(encodings, encoding)
+		}
+	}
+	return strings.Join(encodings, ",")
+}
+
+func newChunkedReader(req *http.Request) *s3ChunkedReader {
+	cr := &s3ChunkedReader{
+		req:               req,
+		reader:            bufio.NewReader(req.Body),
+		chunkSHA256Writer: sha256.New(),
+		state:             readChunkHeader,
+		decodedLength:     -1,
+	}
+	if length := req.Header.Get("X-Amz-Decoded-Content-Length"); length != "" {
+		if n, err := strconv.ParseInt(length, 10, 64); err == nil {
+			cr.decodedLength = n
+		}
+	}
+	for _, names := range req.Header[AmzTrailer] {
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				cr.trailerNames = append(cr.trailerNames, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return cr
+}
+
 // newSignV4ChunkedReader returns a new s3ChunkedReader that translates the data read from r
 // out of HTTP "chunked" format before returning it.
 // The s3ChunkedReader returns io.EOF when the final 0-length chunk is read.
-func (iam *IdentityAccessManagement) newSignV4ChunkedReader(req *http.Request) (io.ReadCloser, ErrorCode) {
-	ident, seedSignature, region, seedDate, errCode := iam.calculateSeedSignature(req)
+func (iam *IdentityAccessManagement) newSignV4ChunkedReader(req *http.Request) (*s3ChunkedReader, ErrorCode) {
+	_, cred, seedSignature, region, seedDate, errCode := iam.calculateSeedSignature(req)
 	if errCode != ErrNone {
 		return nil, errCode
 	}
-	return &s3ChunkedReader{
-		cred:              ident,
-		reader:            bufio.NewReader(req.Body),
-		seedSignature:     seedSignature,
-		seedDate:          seedDate,
-		region:            region,
-		chunkSHA256Writer: sha256.New(),
-		state:             readChunkHeader,
-	}, ErrNone
+	cr := newChunkedReader(req)
+	cr.cred = cred
+	cr.seedSignature = seedSignature
+	cr.seedDate = seedDate
+	cr.region = region
+	cr.signedTrailer = req.Header.Get("X-Amz-Content-Sha256") == streamingContentSHA256Trailer
+	return cr, ErrNone
 }
 
 // Represents the overall state that is required for decoding a
 // AWS Signature V4 chunked reader.
 type s3ChunkedReader struct {
 	cred              *Credential
+	req               *http.Request
 	reader            *bufio.Reader
 	seedSignature     string
 	seedDate          time.Time
@@ -171,6 +240,14 @@ type s3ChunkedReader struct {
 	chunkSHA256Writer hash.Hash // Calculates sha256 of chunk data.
 	n                 uint64    // Unread bytes in chunk
 	err               error
+
+	unsigned         bool     // the chunks carry no signatures
+	signedTrailer    bool     // the trailing headers are signed after the last chunk
+	trailerNames     []string // the trailing headers declared by x-amz-trailer
+	trailer          []byte   // the canonical trailing headers, for the trailer signature
+	trailerSignature string
+	decodedLength    int64 // -1 if not declared
+	decoded          int64
 }
 
 // Read chunk reads the chunk token signature portion.
@@ -243,9 +320,14 @@ func (cr *s3ChunkedReader) Read(buf []byte) (n int, err error) {
 			}
 			cr.state = readChunk
 		case readChunkTrailer:
-			cr.err = readCRLF(cr.reader)
+			if cr.lastChunk {
+				cr.err = cr.readTrailer()
+			} else {
+				cr.err = readCRLF(cr.reader)
+			}
 			if cr.err != nil {
-				return 0, errMalformedEncoding
+				cr.err = errMalformedEncoding
+				return 0, cr.err
 			}
 			cr.state = verifyChunk
 		case readChunk:
@@ -271,6 +353,7 @@ func (cr *s3ChunkedReader) Read(buf []byte) (n int, err error) {
 
 			// Calculate sha256.
 			cr.chunkSHA256Writer.Write(rbuf[:n0])
+			cr.decoded += int64(n0)
 
 			// Update the bytes read into request buffer so far.
 			n += n0
@@ -284,30 +367,122 @@ func (cr *s3ChunkedReader) Read(buf []byte) (n int, err error) {
 				continue
 			}
 		case verifyChunk:
-			// Calculate the hashed chunk.
-			hashedChunk := hex.EncodeToString(cr.chunkSHA256Writer.Sum(nil))
-			// Calculate the chunk signature.
-			newSignature := getChunkSignature(cr.cred.SecretKey, cr.seedSignature, cr.region, cr.seedDate, hashedChunk)
-			if !compareSignatureV4(cr.chunkSignature, newSignature) {
-				// Chunk signature doesn't match we return signature does not match.
-				cr.err = errors.New("chunk signature does not match")
-				return 0, cr.err
+			if !cr.unsigned {
+				// Calculate the hashed chunk.
+				hashedChunk := hex.EncodeToString(cr.chunkSHA256Writer.Sum(nil))
+				// Calculate the chunk signature.
+				newSignature := getChunkSignature(cr.cred.SecretKey, cr.seedSignature, cr.region, cr.seedDate, hashedChunk)
+				if !compareSignatureV4(cr.chunkSignature, newSignature) {
+					// Chunk signature doesn't match we return signature does not match.
+					cr.err = errSignatureMismatch
+					return 0, cr.err
+				}
+				// Newly calculated signature becomes the seed for the next chunk
+				// this follows the chaining.
+				cr.seedSignature = newSignature
 			}
-			// Newly calculated signature becomes the seed for the next chunk
-			// this follows the chaining.
-			cr.seedSignature = newSignature
 			cr.chunkSHA256Writer.Reset()
-			if cr.lastChunk {
-				cr.state = eofChunk
-			} else {
+			if !cr.lastChunk {
 				cr.state = readChunkHeader
+				continue
 			}
+			if cr.signedTrailer {
+				trailerSignature := getTrailerSignature(cr.cred.SecretKey, cr.seedSignature, cr.region, cr.seedDate, cr.trailer)
+				if !compareSignatureV4(cr.trailerSignature, trailerSignature) {
+					cr.err = errSignatureMismatch
+					return 0, cr.err
+				}
+			}
+			if cr.decodedLength >= 0 && cr.decoded != cr.decodedLength {
+				cr.err = errIncompleteBody
+				return 0, cr.err
+			}
+			cr.state = eofChunk
 		case eofChunk:
 			return n, io.EOF
 		}
 	}
 }
 
+// readTrailer reads the trailing headers after the last chunk, until the empty line.
+// Only the headers declared by x-amz-trailer are accepted.
+func (cr *s3ChunkedReader) readTrailer() error {
+	var canonical bytes.Buffer
+	for {
+		line, err := cr.reader.ReadSlice('\n')
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if len(line) >= maxLineLength {
+			return errLineTooLong
+		}
+		line = trimTrailingWhitespace(line)
+		if len(line) == 0 {
+			break
+		}
+		i := bytes.IndexByte(line, ':')
+		if i < 0 {
+			return errMalformedEncoding
+		}
+		name, value := strings.TrimSpace(string(line[:i])), strings.TrimSpace(string(line[i+1:]))
+		if strings.EqualFold(name, "x-amz-trailer-signature") {
+			cr.trailerSignature = value
+			continue
+		}
+		if !cr.isTrailerDeclared(name) {
+			return errMalformedEncoding
+		}
+		if cr.req.Trailer == nil {
+			cr.req.Trailer = make(http.Header)
+		}
+		cr.req.Trailer.Set(name, value)
+		canonical.WriteString(strings.ToLower(name) + ":" + value + "\n")
+	}
+	cr.trailer = canonical.Bytes()
+	return nil
+}
+
+func (cr *s3ChunkedReader) isTrailerDeclared(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	for _, declared := range cr.trailerNames {
+		if declared == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getTrailerSignature - get the signature of the trailing headers, chained to the last chunk signature.
+func getTrailerSignature(secretKey string, seedSignature string, region string, date time.Time, trailer []byte) string {
+	hashedTrailer := sha256.Sum256(trailer)
+
+	stringToSign := signV4TrailerAlgorithm + "\n" +
+		date.Format(iso8601Format) + "\n" +
+		getScope(date, region) + "\n" +
+		seedSignature + "\n" +
+		hex.EncodeToString(hashedTrailer[:])
+
+	signingKey := getSigningKey(secretKey, date, region, "s3")
+
+	return getSignature(signingKey, stringToSign)
+}
+
+// errorCode maps the failure of decoding the body, or returns ErrNone.
+func (cr *s3ChunkedReader) errorCode() ErrorCode {
+	switch cr.err {
+	case nil, io.EOF:
+		return ErrNone
+	case errSignatureMismatch:
+		return ErrSignatureDoesNotMatch
+	case errIncompleteBody, io.ErrUnexpectedEOF:
+		return ErrIncompleteBody
+	}
+	return ErrInvalidRequest
+}
+
 // readCRLF - check if reader only has '\r\n' CRLF character.
 // returns malformed encoding if it doesn't.
 func readCRLF(reader io.Reader) error {
@@ -0,0 +1,137 @@
+package s3api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObject.html#API_GetObject_RequestSyntax
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_CopyObject.html#API_CopyObject_RequestSyntax
+// https://tools.ietf.org/html/rfc7232#section-6
+
+const (
+	AmzCopySourceIfMatch           = "X-Amz-Copy-Source-If-Match"
+	AmzCopySourceIfNoneMatch       = "X-Amz-Copy-Source-If-None-Match"
+	AmzCopySourceIfModifiedSince   = "X-Amz-Copy-Source-If-Modified-Since"
+	AmzCopySourceIfUnmodifiedSince = "X-Amz-Copy-Source-If-Unmodified-Since"
+)
+
+func hasConditionalHeaders(r *http.Request) bool {
+	return r.Header.Get("If-Match") != "" || r.Header.Get("If-None-Match") != "" ||
+		r.Header.Get("If-Modified-Since") != "" || r.Header.Get("If-Unmodified-Since") != ""
+}
+
+// checkConditionalHeaders evaluates the conditional headers of the request against the object entry,
+// a nil entry for an object that does not exist.
+// If-Modified-Since and a matching If-None-Match only result in NotModified for GET and HEAD, other methods fail the precondition.
+func checkConditionalHeaders(r *http.Request, entry *filer_pb.Entry) ErrorCode {
+
+	isRead := r.Method == http.MethodGet || r.Method == http.MethodHead
+
+	if entry == nil {
+		if r.Header.Get("If-Match") != "" && !isRead {
+			return ErrNoSuchKey
+		}
+		// reading a missing object fails later with NoSuchKey
+		return ErrNone
+	}
+
+	etag := filer2.ETag(entry)
+	var mtime time.Time
+	if entry.Attributes != nil {
+		mtime = time.Unix(entry.Attributes.Mtime, 0)
+	}
+
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if !filer2.ETagMatches(ifMatch, etag) {
+			return ErrPreconditionFailed
+		}
+	} else if t, err := http.ParseTime(r.Header.Get("If-Unmodified-Since")); err == nil && mtime.After(t) {
+		return ErrPreconditionFailed
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if filer2.ETagMatches(ifNoneMatch, etag) {
+			if isRead {
+				return ErrNotModified
+			}
+			return ErrPreconditionFailed
+		}
+	} else if t, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && isRead && !mtime.After(t) {
+		return ErrNotModified
+	}
+
+	return ErrNone
+}
+
+// checkCopySourceConditions evaluates the x-amz-copy-source-if-* headers against the copy source entry,
+// a nil entry for a copy source that does not exist, failing the copy later.
+// As for the conditional headers, a matching if-match overrides if-unmodified-since, and a not matching
+// if-none-match overrides if-modified-since. Any unmet condition fails the precondition.
+func checkCopySourceConditions(r *http.Request, entry *filer_pb.Entry) ErrorCode {
+
+	if entry == nil {
+		return ErrNone
+	}
+
+	etag := filer2.ETag(entry)
+	var mtime time.Time
+	if entry.Attributes != nil {
+		mtime = time.Unix(entry.Attributes.Mtime, 0)
+	}
+
+	if ifMatch := r.Header.Get(AmzCopySourceIfMatch); ifMatch != "" {
+		if !filer2.ETagMatches(ifMatch, etag) {
+			return ErrPreconditionFailed
+		}
+	} else if t, err := http.ParseTime(r.Header.Get(AmzCopySourceIfUnmodifiedSince)); err == nil && mtime.After(t) {
+		return ErrPreconditionFailed
+	}
+
+	if ifNoneMatch := r.Header.Get(AmzCopySourceIfNoneMatch); ifNoneMatch != "" {
+		if filer2.ETagMatches(ifNoneMatch, etag) {
+			return ErrPreconditionFailed
+		}
+	} else if t, err := http.ParseTime(r.Header.Get(AmzCopySourceIfModifiedSince)); err == nil && !mtime.After(t) {
+		return ErrPreconditionFailed
+	}
+
+	return ErrNone
+}
+
+// checkObjectPreconditions looks up the object, or the version given by the versionId query parameter,
+// only if the request has conditional headers
+func (s3a *S3ApiServer) checkObjectPreconditions(w http.ResponseWriter, r *http.Request, bucket, object string) ErrorCode {
+
+	if !hasConditionalHeaders(r) {
+		return ErrNone
+	}
+
+	var entry *filer_pb.Entry
+	var err error
+	if versionId := r.URL.Query().Get("versionId"); versionId != "" {
+		_, entry, err = s3a.findVersion(bucket, object, versionId)
+	} else {
+		_, _, entry, err = s3a.objectEntry(bucket, object)
+	}
+	if err != nil {
+		glog.Errorf("lookup object %s%s: %v", bucket, object, err)
+		return ErrInternalError
+	}
+	if entry != nil && (entry.IsDirectory || isDeleteMarker(entry)) {
+		entry = nil
+	}
+
+	code := checkConditionalHeaders(r, entry)
+	if code == ErrNotModified {
+		setEtag(w, filer2.ETag(entry))
+		if entry.Attributes != nil {
+			w.Header().Set("Last-Modified", time.Unix(entry.Attributes.Mtime, 0).UTC().Format(http.TimeFormat))
+		}
+	}
+	return code
+}
@@ -0,0 +1,121 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+func TestCheckConditionalHeaders(t *testing.T) {
+
+	mtime := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	entry := &filer_pb.Entry{
+		Name:       "object",
+		Attributes: &filer_pb.FuseAttributes{Mtime: mtime.Unix(), Md5: []byte{0xab, 0xcd}},
+	}
+	before, after := mtime.Add(-time.Hour).Format(http.TimeFormat), mtime.Add(time.Hour).Format(http.TimeFormat)
+
+	tests := []struct {
+		method   string
+		headers  map[string]string
+		missing  bool
+		expected ErrorCode
+	}{
+		{"GET", map[string]string{}, false, ErrNone},
+		{"GET", map[string]string{"If-Match": `"abcd"`}, false, ErrNone},
+		{"GET", map[string]string{"If-Match": `"other", "abcd"`}, false, ErrNone},
+		{"GET", map[string]string{"If-Match": `"other"`}, false, ErrPreconditionFailed},
+		{"GET", map[string]string{"If-None-Match": `"abcd"`}, false, ErrNotModified},
+		{"HEAD", map[string]string{"If-None-Match": `W/"abcd"`}, false, ErrNotModified},
+		{"GET", map[string]string{"If-None-Match": `"other"`}, false, ErrNone},
+		{"GET", map[string]string{"If-Modified-Since": after}, false, ErrNotModified},
+		{"GET", map[string]string{"If-Modified-Since": before}, false, ErrNone},
+		{"GET", map[string]string{"If-Unmodified-Since": before}, false, ErrPreconditionFailed},
+		{"GET", map[string]string{"If-Unmodified-Since": after}, false, ErrNone},
+		// If-Match takes precedence over If-Unmodified-Since, If-None-Match over If-Modified-Since
+		{"GET", map[string]string{"If-Match": `"abcd"`, "If-Unmodified-Since": before}, false, ErrNone},
+		{"GET", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": after}, false, ErrNone},
+		{"GET", map[string]string{"If-Match": `"abcd"`}, true, ErrNone},
+		{"PUT", map[string]string{"If-None-Match": "*"}, false, ErrPreconditionFailed},
+		{"PUT", map[string]string{"If-None-Match": "*"}, true, ErrNone},
+		{"PUT", map[string]string{"If-Match": `"abcd"`}, false, ErrNone},
+		{"PUT", map[string]string{"If-Match": `"abcd"`}, true, ErrNoSuchKey},
+		{"PUT", map[string]string{"If-Modified-Since": after}, false, ErrNone},
+		{"DELETE", map[string]string{"If-Match": `"other"`}, false, ErrPreconditionFailed},
+	}
+
+	for _, test := range tests {
+		r := &http.Request{Method: test.method, Header: make(http.Header)}
+		for k, v := range test.headers {
+			r.Header.Set(k, v)
+		}
+		e := entry
+		if test.missing {
+			e = nil
+		}
+		if code := checkConditionalHeaders(r, e); code != test.expected {
+			t.Errorf("%s %v missing %v: got %v, expecting %v", test.method, test.headers, test.missing, code, test.expected)
+		}
+	}
+}
+
+func TestCheckCopySourceConditions(t *testing.T) {
+
+	mtime := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	entry := &filer_pb.Entry{
+		Name:       "object",
+		Attributes: &filer_pb.FuseAttributes{Mtime: mtime.Unix(), Md5: []byte{0xab, 0xcd}},
+	}
+	before, after := mtime.Add(-time.Hour).Format(http.TimeFormat), mtime.Add(time.Hour).Format(http.TimeFormat)
+
+	tests := []struct {
+		headers  map[string]string
+		expected ErrorCode
+	}{
+		{map[string]string{}, ErrNone},
+		{map[string]string{AmzCopySourceIfMatch: `"abcd"`}, ErrNone},
+		{map[string]string{AmzCopySourceIfMatch: "*"}, ErrNone},
+		{map[string]string{AmzCopySourceIfMatch: `"other"`}, ErrPreconditionFailed},
+		{map[string]string{AmzCopySourceIfNoneMatch: `"other"`}, ErrNone},
+		{map[string]string{AmzCopySourceIfNoneMatch: `"abcd"`}, ErrPreconditionFailed},
+		{map[string]string{AmzCopySourceIfModifiedSince: before}, ErrNone},
+		{map[string]string{AmzCopySourceIfModifiedSince: after}, ErrPreconditionFailed},
+		{map[string]string{AmzCopySourceIfUnmodifiedSince: after}, ErrNone},
+		{map[string]string{AmzCopySourceIfUnmodifiedSince: before}, ErrPreconditionFailed},
+		// an invalid date is ignored
+		{map[string]string{AmzCopySourceIfUnmodifiedSince: "yesterday"}, ErrNone},
+		// if-match takes precedence over if-unmodified-since, if-none-match over if-modified-since
+		{map[string]string{AmzCopySourceIfMatch: `"abcd"`, AmzCopySourceIfUnmodifiedSince: before}, ErrNone},
+		{map[string]string{AmzCopySourceIfNoneMatch: `"other"`, AmzCopySourceIfModifiedSince: after}, ErrNone},
+		{map[string]string{AmzCopySourceIfNoneMatch: `"abcd"`, AmzCopySourceIfModifiedSince: before}, ErrPreconditionFailed},
+		{map[string]string{AmzCopySourceIfMatch: `"abcd"`, AmzCopySourceIfNoneMatch: `"abcd"`}, ErrPreconditionFailed},
+		// the conditional headers of the destination do not apply to the source
+		{map[string]string{"If-Match": `"other"`}, ErrNone},
+	}
+
+	for _, test := range tests {
+		r := &http.Request{Method: "PUT", Header: make(http.Header)}
+		for k, v := range test.headers {
+			r.Header.Set(k, v)
+		}
+		if code := checkCopySourceConditions(r, entry); code != test.expected {
+			t.Errorf("%v: got %v, expecting %v", test.headers, code, test.expected)
+		}
+	}
+}
+
+func TestNotModifiedResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	setEtag(w, "abcd")
+	writeErrorResponse(w, ErrNotModified, &url.URL{Path: "/bucket/object"})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+		t.Errorf("not modified: %d %q %v", w.Code, w.Body.String(), w.Header())
+	}
+	if w.Header().Get("ETag") != `"abcd"` {
+		t.Errorf("etag: %v", w.Header())
+	}
+}
@@ -0,0 +1,238 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/cors.html
+
+const (
+	// extended attribute key on the bucket entry
+	bucketCORSConfigurationKey = "s3-cors-configuration"
+
+	maxCORSRules = 100
+
+	// the cors configurations are cached by each s3 server, changes by other s3 servers take effect after the ttl
+	bucketCORSCacheTTL = 5 * time.Second
+)
+
+type CORSConfiguration struct {
+	XMLName   xml.Name   `xml:"CORSConfiguration"`
+	Xmlns     string     `xml:"xmlns,attr,omitempty"`
+	CORSRules []CORSRule `xml:"CORSRule"`
+}
+
+type CORSRule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty"`
+}
+
+func (config *CORSConfiguration) validate() ErrorCode {
+	if len(config.CORSRules) == 0 || len(config.CORSRules) > maxCORSRules {
+		return ErrMalformedXML
+	}
+	for _, rule := range config.CORSRules {
+		if code := rule.validate(); code != ErrNone {
+			return code
+		}
+	}
+	return ErrNone
+}
+
+func (rule *CORSRule) validate() ErrorCode {
+	if len(rule.AllowedMethods) == 0 || len(rule.AllowedOrigins) == 0 || rule.MaxAgeSeconds < 0 {
+		return ErrMalformedXML
+	}
+	for _, method := range rule.AllowedMethods {
+		switch method {
+		case "GET", "PUT", "HEAD", "POST", "DELETE":
+		default:
+			return ErrInvalidCORSMethod
+		}
+	}
+	for _, origin := range rule.AllowedOrigins {
+		if strings.Count(origin, "*") > 1 {
+			return ErrInvalidCORSWildcard
+		}
+	}
+	for _, header := range rule.AllowedHeaders {
+		if strings.Count(header, "*") > 1 {
+			return ErrInvalidCORSWildcard
+		}
+	}
+	return ErrNone
+}
+
+// match returns the first rule allowing the origin, the method, and all the request headers
+func (config *CORSConfiguration) match(origin, method string, requestHeaders []string) *CORSRule {
+	for i := range config.CORSRules {
+		rule := &config.CORSRules[i]
+		if rule.allowsOrigin(origin) && rule.allowsMethod(method) && rule.allowsHeaders(requestHeaders) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func (rule *CORSRule) allowsOrigin(origin string) bool {
+	for _, allowed := range rule.AllowedOrigins {
+		if corsWildcardMatch(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule *CORSRule) allowsMethod(method string) bool {
+	for _, allowed := range rule.AllowedMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsHeaders checks the headers of Access-Control-Request-Headers, which are case insensitive
+func (rule *CORSRule) allowsHeaders(requestHeaders []string) bool {
+	for _, header := range requestHeaders {
+		allowed := false
+		for _, pattern := range rule.AllowedHeaders {
+			if corsWildcardMatch(strings.ToLower(pattern), strings.ToLower(header)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// corsWildcardMatch matches the only * of the pattern to any sequence of characters
+func corsWildcardMatch(pattern, s string) bool {
+	i := strings.Index(pattern, "*")
+	if i < 0 {
+		return pattern == s
+	}
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	return len(s) >= len(prefix)+len(suffix) && strings.HasPrefix(s, prefix) && strings.HasSuffix(s, suffix)
+}
+
+// setHeaders writes the Access-Control-* response headers of the matched rule.
+// The preflight responses also list the request headers allowed and how long they can be cached.
+func (rule *CORSRule) setHeaders(h http.Header, origin string, requestHeaders []string, preflight bool) {
+	if rule.allowsAnyOrigin() {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ", "))
+	if len(rule.ExposeHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(rule.ExposeHeaders, ", "))
+	}
+	if !preflight {
+		return
+	}
+	if len(requestHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(requestHeaders, ", "))
+	}
+	if rule.MaxAgeSeconds > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(rule.MaxAgeSeconds))
+	}
+}
+
+func (rule *CORSRule) allowsAnyOrigin() bool {
+	for _, origin := range rule.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCORSRequestHeaders splits the comma separated Access-Control-Request-Headers
+func parseCORSRequestHeaders(value string) (headers []string) {
+	for _, header := range strings.Split(value, ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			headers = append(headers, strings.ToLower(header))
+		}
+	}
+	return
+}
+
+func loadCORSConfiguration(extended map[string][]byte) (config *CORSConfiguration, err error) {
+
+	data, found := extended[bucketCORSConfigurationKey]
+	if !found {
+		return nil, nil
+	}
+
+	config = &CORSConfiguration{}
+	if err = xml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// getBucketCORSConfiguration returns nil if the bucket or its cors configuration does not exist
+func (s3a *S3ApiServer) getBucketCORSConfiguration(bucket string) (*CORSConfiguration, error) {
+
+	if item := s3a.corsCache.Get(bucket); item != nil && !item.Expired() {
+		return item.Value().(*CORSConfiguration), nil
+	}
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		return nil, err
+	}
+	var config *CORSConfiguration
+	if entry != nil {
+		if config, err = loadCORSConfiguration(entry.Extended); err != nil {
+			return nil, err
+		}
+	}
+
+	s3a.corsCache.Set(bucket, config, bucketCORSCacheTTL)
+	return config, nil
+}
+
+// corsHeaders adds the Access-Control-* headers to the responses of cross-origin requests allowed by the bucket
+func (s3a *S3ApiServer) corsHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		bucket := mux.Vars(r)["bucket"]
+		if bucket == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		config, err := s3a.getBucketCORSConfiguration(bucket)
+		if err != nil {
+			glog.Errorf("bucket %s cors configuration: %v", bucket, err)
+		}
+		if config != nil {
+			w.Header().Add("Vary", "Origin")
+			if rule := config.match(origin, r.Method, nil); rule != nil {
+				rule.setHeaders(w.Header(), origin, nil, false)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,117 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"testing"
+)
+
+const testCORSConfiguration = `<CORSConfiguration>
+  <CORSRule>
+    <AllowedOrigin>http://www.example.com</AllowedOrigin>
+    <AllowedOrigin>https://*.example.com</AllowedOrigin>
+    <AllowedMethod>PUT</AllowedMethod>
+    <AllowedMethod>POST</AllowedMethod>
+    <AllowedHeader>Content-*</AllowedHeader>
+    <AllowedHeader>x-amz-date</AllowedHeader>
+    <ExposeHeader>ETag</ExposeHeader>
+    <MaxAgeSeconds>3000</MaxAgeSeconds>
+  </CORSRule>
+  <CORSRule>
+    <AllowedOrigin>*</AllowedOrigin>
+    <AllowedMethod>GET</AllowedMethod>
+  </CORSRule>
+</CORSConfiguration>`
+
+func TestCORSConfigurationMatch(t *testing.T) {
+
+	config := &CORSConfiguration{}
+	if err := xml.Unmarshal([]byte(testCORSConfiguration), config); err != nil {
+		t.Fatal(err)
+	}
+	if errCode := config.validate(); errCode != ErrNone {
+		t.Fatalf("validate: %v", errCode)
+	}
+
+	tests := []struct {
+		origin         string
+		method         string
+		requestHeaders []string
+		rule           int
+	}{
+		{"http://www.example.com", "PUT", nil, 0},
+		{"https://upload.example.com", "POST", []string{"content-type", "x-amz-date"}, 0},
+		{"https://.example.com", "PUT", nil, 0},
+		{"http://upload.example.com", "PUT", nil, -1},
+		{"http://www.example.com", "PUT", []string{"authorization"}, -1},
+		{"http://www.example.com", "DELETE", nil, -1},
+		{"http://www.example.com", "GET", nil, 1},
+		{"http://other.com", "GET", []string{"range"}, -1},
+	}
+	for _, test := range tests {
+		rule := config.match(test.origin, test.method, test.requestHeaders)
+		if test.rule < 0 && rule != nil || test.rule >= 0 && rule != &config.CORSRules[test.rule] {
+			t.Errorf("%s %s %v: matched %+v, expecting rule %d", test.origin, test.method, test.requestHeaders, rule, test.rule)
+		}
+	}
+}
+
+func TestCORSRuleSetHeaders(t *testing.T) {
+
+	rule := &CORSRule{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{"GET", "PUT"},
+		AllowedHeaders: []string{"*"},
+		ExposeHeaders:  []string{"ETag", "x-amz-request-id"},
+		MaxAgeSeconds:  600,
+	}
+
+	h := make(http.Header)
+	rule.setHeaders(h, "https://www.example.com", []string{"content-type"}, true)
+	expected := map[string]string{
+		"Access-Control-Allow-Origin":      "https://www.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET, PUT",
+		"Access-Control-Allow-Headers":     "content-type",
+		"Access-Control-Expose-Headers":    "ETag, x-amz-request-id",
+		"Access-Control-Max-Age":           "600",
+	}
+	for name, value := range expected {
+		if h.Get(name) != value {
+			t.Errorf("preflight %s: %q, expecting %q", name, h.Get(name), value)
+		}
+	}
+
+	h = make(http.Header)
+	rule.AllowedOrigins = []string{"*"}
+	rule.setHeaders(h, "https://www.example.com", nil, false)
+	if h.Get("Access-Control-Allow-Origin") != "*" || h.Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("any origin: %v", h)
+	}
+	if h.Get("Access-Control-Max-Age") != "" || h.Get("Access-Control-Allow-Headers") != "" {
+		t.Errorf("actual request: %v", h)
+	}
+}
+
+func TestCORSConfigurationValidate(t *testing.T) {
+	tests := []struct {
+		rule     CORSRule
+		expected ErrorCode
+	}{
+		{CORSRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}, ErrNone},
+		{CORSRule{AllowedOrigins: []string{"*"}}, ErrMalformedXML},
+		{CORSRule{AllowedMethods: []string{"GET"}}, ErrMalformedXML},
+		{CORSRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"PATCH"}}, ErrInvalidCORSMethod},
+		{CORSRule{AllowedOrigins: []string{"http://*.*.com"}, AllowedMethods: []string{"GET"}}, ErrInvalidCORSWildcard},
+		{CORSRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{"**"}}, ErrInvalidCORSWildcard},
+	}
+	for i, test := range tests {
+		config := &CORSConfiguration{CORSRules: []CORSRule{test.rule}}
+		if errCode := config.validate(); errCode != test.expected {
+			t.Errorf("rule %d: got %v, expecting %v", i, errCode, test.expected)
+		}
+	}
+	if errCode := (&CORSConfiguration{}).validate(); errCode != ErrMalformedXML {
+		t.Errorf("no rules: %v", errCode)
+	}
+}
@@ -0,0 +1,614 @@
+package s3api
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/serv-side-encryption.html
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/ServerSideEncryptionCustomerKeys.html
+
+const (
+	ServerSideEncryptionAES256 = "AES256"
+	ServerSideEncryptionKMS    = "aws:kms"
+
+	// request headers, also used as the extended attribute keys on the object entry, except the customer key
+	AmzServerSideEncryption                  = "X-Amz-Server-Side-Encryption"
+	AmzServerSideEncryptionCustomerAlgorithm = "X-Amz-Server-Side-Encryption-Customer-Algorithm"
+	AmzServerSideEncryptionCustomerKey       = "X-Amz-Server-Side-Encryption-Customer-Key"
+	AmzServerSideEncryptionCustomerKeyMD5    = "X-Amz-Server-Side-Encryption-Customer-Key-Md5"
+
+	AmzCopySourceServerSideEncryptionCustomerAlgorithm = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Algorithm"
+	AmzCopySourceServerSideEncryptionCustomerKey       = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key"
+	AmzCopySourceServerSideEncryptionCustomerKeyMD5    = "X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key-Md5"
+
+	// internal extended attribute keys on the object entry, never returned to the client
+	seaweedEncryptionPrefix = "X-Seaweed-Encryption-"
+	seaweedEncryptionIv     = seaweedEncryptionPrefix + "Iv"
+	seaweedEncryptionParts  = seaweedEncryptionPrefix + "Parts"
+	seaweedEncryptionKey    = seaweedEncryptionPrefix + "Key"
+
+	// extended attribute key on the bucket entry
+	bucketEncryptionConfigurationKey = "s3-encryption-configuration"
+)
+
+type ServerSideEncryptionConfiguration struct {
+	XMLName xml.Name                   `xml:"ServerSideEncryptionConfiguration"`
+	Xmlns   string                     `xml:"xmlns,attr,omitempty"`
+	Rules   []ServerSideEncryptionRule `xml:"Rule"`
+}
+
+type ServerSideEncryptionRule struct {
+	ApplyServerSideEncryptionByDefault ServerSideEncryptionByDefault `xml:"ApplyServerSideEncryptionByDefault"`
+}
+
+type ServerSideEncryptionByDefault struct {
+	SSEAlgorithm   string `xml:"SSEAlgorithm"`
+	KMSMasterKeyID string `xml:"KMSMasterKeyID,omitempty"`
+}
+
+func (config *ServerSideEncryptionConfiguration) validate() ErrorCode {
+	if len(config.Rules) != 1 {
+		return ErrMalformedXML
+	}
+	switch config.Rules[0].ApplyServerSideEncryptionByDefault.SSEAlgorithm {
+	case ServerSideEncryptionAES256:
+	case ServerSideEncryptionKMS:
+		return ErrNotImplemented
+	default:
+		return ErrMalformedXML
+	}
+	return ErrNone
+}
+
+// defaultAlgorithm is the server side encryption of objects written without one
+func (config *ServerSideEncryptionConfiguration) defaultAlgorithm() string {
+	if config == nil || len(config.Rules) == 0 {
+		return ""
+	}
+	return config.Rules[0].ApplyServerSideEncryptionByDefault.SSEAlgorithm
+}
+
+func (s3a *S3ApiServer) getBucketEncryptionConfiguration(bucket string) (config *ServerSideEncryptionConfiguration, code ErrorCode) {
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		return nil, ErrInternalError
+	}
+	if entry == nil {
+		return nil, ErrNoSuchBucket
+	}
+
+	data, found := entry.Extended[bucketEncryptionConfigurationKey]
+	if !found {
+		return nil, ErrNone
+	}
+	config = &ServerSideEncryptionConfiguration{}
+	if err = xml.Unmarshal(data, config); err != nil {
+		glog.Errorf("bucket %s has invalid encryption configuration: %v", bucket, err)
+		return nil, ErrInternalError
+	}
+
+	return config, ErrNone
+}
+
+// loadMasterKey reads the base64 encoded 256-bit key that wraps the data keys of SSE-S3 objects
+func loadMasterKey(encoded string) ([]byte, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode s3 encryption key: %v", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("s3 encryption key has %d bytes, expecting 32", len(key))
+	}
+	return key, nil
+}
+
+// customerKey is the SSE-C key supplied with one request. It is never persisted.
+type customerKey struct {
+	key    []byte
+	keyMD5 string // base64 encoded, as in the request header
+}
+
+// parseRequestCustomerKey reads the SSE-C headers of the object being written or read
+func parseRequestCustomerKey(r *http.Request) (*customerKey, ErrorCode) {
+	return parseCustomerKey(r.Header, AmzServerSideEncryptionCustomerAlgorithm,
+		AmzServerSideEncryptionCustomerKey, AmzServerSideEncryptionCustomerKeyMD5)
+}
+
+// parseCopySourceCustomerKey reads the SSE-C headers of the copy source
+func parseCopySourceCustomerKey(r *http.Request) (*customerKey, ErrorCode) {
+	return parseCustomerKey(r.Header, AmzCopySourceServerSideEncryptionCustomerAlgorithm,
+		AmzCopySourceServerSideEncryptionCustomerKey, AmzCopySourceServerSideEncryptionCustomerKeyMD5)
+}
+
+// parseCustomerKey returns nil when none of the headers is set.
+// The headers are removed, so that the key is not passed on to the filer.
+func parseCustomerKey(header http.Header, algorithmHeader, keyHeader, keyMD5Header string) (*customerKey, ErrorCode) {
+
+	algorithm, encodedKey, keyMD5 := header.Get(algorithmHeader), header.Get(keyHeader), header.Get(keyMD5Header)
+	header.Del(algorithmHeader)
+	header.Del(keyHeader)
+	header.Del(keyMD5Header)
+
+	if algorithm == "" && encodedKey == "" && keyMD5 == "" {
+		return nil, ErrNone
+	}
+	if algorithm != ServerSideEncryptionAES256 {
+		return nil, ErrInvalidEncryptionAlgorithm
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != 32 {
+		return nil, ErrInvalidSSECustomerKey
+	}
+	sum := md5.Sum(key)
+	if keyMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+		return nil, ErrSSECustomerKeyMD5Mismatch
+	}
+
+	return &customerKey{key: key, keyMD5: keyMD5}, ErrNone
+}
+
+// prepareObjectEncryption decides how an object being written is encrypted: with the customer key supplied,
+// or with a server managed key when requested or when the bucket is encrypted by default.
+// It returns a nil encryption for objects stored unencrypted.
+func (s3a *S3ApiServer) prepareObjectEncryption(r *http.Request, bucket string) (encryption *objectEncryption, dataKey []byte, code ErrorCode) {
+
+	key, code := parseRequestCustomerKey(r)
+	if code != ErrNone {
+		return nil, nil, code
+	}
+	algorithm := r.Header.Get(AmzServerSideEncryption)
+
+	if key != nil {
+		if algorithm != "" {
+			return nil, nil, ErrInvalidRequest
+		}
+		encryption, err := newObjectEncryption(key)
+		if err != nil {
+			glog.Errorf("new object encryption: %v", err)
+			return nil, nil, ErrInternalError
+		}
+		return encryption, key.key, ErrNone
+	}
+
+	if algorithm == "" {
+		config, code := s3a.getBucketEncryptionConfiguration(bucket)
+		if code != ErrNone && code != ErrNoSuchBucket {
+			return nil, nil, code
+		}
+		algorithm = config.defaultAlgorithm()
+	}
+
+	switch algorithm {
+	case "":
+		return nil, nil, ErrNone
+	case ServerSideEncryptionAES256:
+	case ServerSideEncryptionKMS:
+		return nil, nil, ErrNotImplemented
+	default:
+		return nil, nil, ErrInvalidEncryptionAlgorithm
+	}
+
+	if s3a.masterKey == nil {
+		glog.V(1).Infof("server side encryption of bucket %s: s3.encryption.key is not configured", bucket)
+		return nil, nil, ErrNotImplemented
+	}
+	encryption, dataKey, err := newServerSideEncryption(s3a.masterKey)
+	if err != nil {
+		glog.Errorf("new server side encryption: %v", err)
+		return nil, nil, ErrInternalError
+	}
+	return encryption, dataKey, ErrNone
+}
+
+// setEncryptionHeaders confirms the encryption in the response
+func setEncryptionHeaders(w http.ResponseWriter, encryption *objectEncryption) {
+	if encryption == nil {
+		return
+	}
+	if encryption.serverSideEncryption != "" {
+		w.Header().Set(AmzServerSideEncryption, encryption.serverSideEncryption)
+		return
+	}
+	w.Header().Set(AmzServerSideEncryptionCustomerAlgorithm, encryption.customerAlgorithm)
+	w.Header().Set(AmzServerSideEncryptionCustomerKeyMD5, encryption.customerKeyMD5)
+}
+
+// objectEncryption is how the data of one object, or of one multipart upload, is encrypted.
+// The data is encrypted with AES-256 in CTR mode, so it keeps its size and can be decrypted from any offset.
+// The key is either supplied by the customer with each request (SSE-C),
+// or a random data key kept wrapped by the master key of the s3 server (SSE-S3).
+type objectEncryption struct {
+	serverSideEncryption string
+	wrappedKey           []byte
+	customerAlgorithm    string
+	customerKeyMD5       string
+	iv                   []byte
+	// parts of a completed multipart upload, each encrypted with its own counter
+	parts []encryptedPart
+}
+
+type encryptedPart struct {
+	partNumber int
+	offset     int64
+}
+
+func newObjectEncryption(key *customerKey) (*objectEncryption, error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	return &objectEncryption{
+		customerAlgorithm: ServerSideEncryptionAES256,
+		customerKeyMD5:    key.keyMD5,
+		iv:                iv,
+	}, nil
+}
+
+func newServerSideEncryption(masterKey []byte) (encryption *objectEncryption, dataKey []byte, err error) {
+	dataKey = make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	if _, err = io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, err
+	}
+	if _, err = io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, nil, err
+	}
+	wrappedKey, err := wrapKey(masterKey, dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &objectEncryption{
+		serverSideEncryption: ServerSideEncryptionAES256,
+		wrappedKey:           wrappedKey,
+		iv:                   iv,
+	}, dataKey, nil
+}
+
+// wrapKey seals the data key with AES-256-GCM, the nonce is prepended to the sealed key
+func wrapKey(masterKey, dataKey []byte) ([]byte, error) {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+func unwrapKey(masterKey, wrappedKey []byte) ([]byte, error) {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) < gcm.NonceSize() {
+		return nil, fmt.Errorf("wrapped key of %d bytes is too short", len(wrappedKey))
+	}
+	return gcm.Open(nil, wrappedKey[:gcm.NonceSize()], wrappedKey[gcm.NonceSize():], nil)
+}
+
+// loadObjectEncryption returns nil if the object is not encrypted
+func loadObjectEncryption(extended map[string][]byte) *objectEncryption {
+	serverSideEncryption, isServerSide := extended[AmzServerSideEncryption]
+	customerAlgorithm, isCustomer := extended[AmzServerSideEncryptionCustomerAlgorithm]
+	if !isServerSide && !isCustomer {
+		return nil
+	}
+	encryption := &objectEncryption{
+		serverSideEncryption: string(serverSideEncryption),
+		customerAlgorithm:    string(customerAlgorithm),
+		customerKeyMD5:       string(extended[AmzServerSideEncryptionCustomerKeyMD5]),
+	}
+	if isServerSide {
+		wrappedKey, err := base64.StdEncoding.DecodeString(string(extended[seaweedEncryptionKey]))
+		if err != nil {
+			glog.Warningf("invalid encryption key %s: %v", string(extended[seaweedEncryptionKey]), err)
+		}
+		encryption.wrappedKey = wrappedKey
+	}
+	iv, err := base64.StdEncoding.DecodeString(string(extended[seaweedEncryptionIv]))
+	if err != nil || len(iv) != aes.BlockSize {
+		glog.Warningf("invalid encryption iv %s: %v", string(extended[seaweedEncryptionIv]), err)
+	}
+	encryption.iv = iv
+	if parts := string(extended[seaweedEncryptionParts]); parts != "" {
+		for _, part := range strings.Split(parts, ",") {
+			var p encryptedPart
+			if _, err := fmt.Sscanf(part, "%d:%d", &p.partNumber, &p.offset); err != nil {
+				glog.Warningf("invalid encryption part %s: %v", part, err)
+				continue
+			}
+			encryption.parts = append(encryption.parts, p)
+		}
+	}
+	return encryption
+}
+
+// loadResponseEncryption reads the encryption of the object from a filer response. The filer only returns
+// the encryption algorithms as headers, so the wrapped key and the iv are read from the entry,
+// which must still be the version of the object in the response.
+func (s3a *S3ApiServer) loadResponseEncryption(resp *http.Response) (*objectEncryption, ErrorCode) {
+
+	if resp.Header.Get(AmzServerSideEncryption) == "" && resp.Header.Get(AmzServerSideEncryptionCustomerAlgorithm) == "" {
+		return nil, ErrNone
+	}
+
+	dir, name := util.FullPath(resp.Request.URL.Path).DirAndName()
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil || entry == nil {
+		glog.Errorf("lookup encrypted object %s/%s: %v", dir, name, err)
+		return nil, ErrInternalError
+	}
+	if etag := strings.Trim(resp.Header.Get("ETag"), "\""); etag != filer2.ETag(entry) {
+		glog.Errorf("encrypted object %s/%s is changed while read", dir, name)
+		return nil, ErrInternalError
+	}
+
+	return loadObjectEncryption(entry.Extended), ErrNone
+}
+
+func (encryption *objectEncryption) saveTo(extended map[string][]byte) {
+	if encryption.serverSideEncryption != "" {
+		extended[AmzServerSideEncryption] = []byte(encryption.serverSideEncryption)
+		extended[seaweedEncryptionKey] = []byte(base64.StdEncoding.EncodeToString(encryption.wrappedKey))
+		delete(extended, AmzServerSideEncryptionCustomerAlgorithm)
+		delete(extended, AmzServerSideEncryptionCustomerKeyMD5)
+	} else {
+		extended[AmzServerSideEncryptionCustomerAlgorithm] = []byte(encryption.customerAlgorithm)
+		extended[AmzServerSideEncryptionCustomerKeyMD5] = []byte(encryption.customerKeyMD5)
+		delete(extended, AmzServerSideEncryption)
+		delete(extended, seaweedEncryptionKey)
+	}
+	extended[seaweedEncryptionIv] = []byte(base64.StdEncoding.EncodeToString(encryption.iv))
+	if len(encryption.parts) == 0 {
+		delete(extended, seaweedEncryptionParts)
+		return
+	}
+	var parts []string
+	for _, p := range encryption.parts {
+		parts = append(parts, fmt.Sprintf("%d:%d", p.partNumber, p.offset))
+	}
+	extended[seaweedEncryptionParts] = []byte(strings.Join(parts, ","))
+}
+
+// dataKey returns the key the object data is encrypted with,
+// after verifying the customer key is the one the object was encrypted with.
+// It returns nil for objects that are not encrypted.
+func (encryption *objectEncryption) dataKey(key *customerKey, masterKey []byte) ([]byte, ErrorCode) {
+	switch {
+	case encryption == nil:
+		if key != nil {
+			return nil, ErrSSEEncryptionParametersNotApplicable
+		}
+		return nil, ErrNone
+	case encryption.serverSideEncryption != "":
+		if key != nil {
+			return nil, ErrSSEEncryptionParametersNotApplicable
+		}
+		if masterKey == nil {
+			glog.Errorf("decrypt server side encrypted object: s3.encryption.key is not configured")
+			return nil, ErrInternalError
+		}
+		dataKey, err := unwrapKey(masterKey, encryption.wrappedKey)
+		if err != nil {
+			glog.Errorf("unwrap object data key: %v", err)
+			return nil, ErrInternalError
+		}
+		return dataKey, ErrNone
+	}
+	if key == nil {
+		return nil, ErrSSECustomerKeyMissing
+	}
+	if key.keyMD5 != encryption.customerKeyMD5 {
+		return nil, ErrAccessDenied
+	}
+	return key.key, ErrNone
+}
+
+// partIv gives each part of a multipart upload a distinct counter range
+func partIv(iv []byte, partNumber int) []byte {
+	p := make([]byte, len(iv))
+	copy(p, iv)
+	binary.BigEndian.PutUint32(p[0:4], binary.BigEndian.Uint32(p[0:4])^uint32(partNumber))
+	return p
+}
+
+// ctrStreamAt returns the key stream positioned at offset bytes from the start of the counter
+func ctrStreamAt(block cipher.Block, iv []byte, offset int64) cipher.Stream {
+	counter := make([]byte, len(iv))
+	copy(counter, iv)
+	carry := uint64(offset / aes.BlockSize)
+	for i := len(counter) - 1; i >= 0 && carry > 0; i-- {
+		sum := uint64(counter[i]) + carry&0xff
+		counter[i] = byte(sum)
+		carry = carry>>8 + sum>>8
+	}
+	stream := cipher.NewCTR(block, counter)
+	if skip := offset % aes.BlockSize; skip > 0 {
+		discard := make([]byte, skip)
+		stream.XORKeyStream(discard, discard)
+	}
+	return stream
+}
+
+// encryptReader encrypts the data of a whole object, or of part partNumber when it is not zero
+func (encryption *objectEncryption) encryptReader(dataKey []byte, r io.Reader, partNumber int) (io.Reader, error) {
+	if len(encryption.iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid encryption iv of %d bytes", len(encryption.iv))
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	iv := encryption.iv
+	if partNumber > 0 {
+		iv = partIv(iv, partNumber)
+	}
+	return cipher.StreamReader{S: cipher.NewCTR(block, iv), R: r}, nil
+}
+
+// decryptReader decrypts the object data read from r, which starts at offset of the object
+func (encryption *objectEncryption) decryptReader(dataKey []byte, r io.Reader, offset int64) (io.Reader, error) {
+	if len(encryption.iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid encryption iv of %d bytes", len(encryption.iv))
+	}
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	dr := &decryptReader{reader: r, block: block, offset: offset}
+	if len(encryption.parts) == 0 {
+		dr.segments = []encryptionSegment{{offset: 0, iv: encryption.iv}}
+	} else {
+		for _, p := range encryption.parts {
+			dr.segments = append(dr.segments, encryptionSegment{offset: p.offset, iv: partIv(encryption.iv, p.partNumber)})
+		}
+		sort.Slice(dr.segments, func(i, j int) bool {
+			return dr.segments[i].offset < dr.segments[j].offset
+		})
+	}
+	return dr, nil
+}
+
+type encryptionSegment struct {
+	offset int64
+	iv     []byte
+}
+
+type decryptReader struct {
+	reader     io.Reader
+	block      cipher.Block
+	segments   []encryptionSegment
+	offset     int64
+	stream     cipher.Stream
+	segmentEnd int64 // where the next segment starts, or -1 in the last segment
+}
+
+func (dr *decryptReader) Read(p []byte) (n int, err error) {
+	if dr.stream == nil || dr.offset == dr.segmentEnd {
+		dr.seek()
+	}
+	if dr.segmentEnd >= 0 && int64(len(p)) > dr.segmentEnd-dr.offset {
+		p = p[:dr.segmentEnd-dr.offset]
+	}
+	n, err = dr.reader.Read(p)
+	dr.stream.XORKeyStream(p[:n], p[:n])
+	dr.offset += int64(n)
+	return
+}
+
+func (dr *decryptReader) seek() {
+	i := sort.Search(len(dr.segments), func(i int) bool {
+		return dr.segments[i].offset > dr.offset
+	}) - 1
+	if i < 0 {
+		i = 0
+	}
+	segment := dr.segments[i]
+	dr.stream = ctrStreamAt(dr.block, segment.iv, dr.offset-segment.offset)
+	dr.segmentEnd = -1
+	if i+1 < len(dr.segments) {
+		dr.segmentEnd = dr.segments[i+1].offset
+	}
+}
+
+// decryptResponse checks the customer key against the encryption of the object read from the filer,
+// and returns the decrypted body
+func (s3a *S3ApiServer) decryptResponse(resp *http.Response, key *customerKey) (io.Reader, ErrorCode) {
+
+	encryption, errCode := s3a.loadResponseEncryption(resp)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+	dataKey, errCode := encryption.dataKey(key, s3a.masterKey)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+	if encryption == nil {
+		return resp.Body, ErrNone
+	}
+
+	var offset int64
+	if resp.StatusCode == http.StatusPartialContent {
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), "multipart/byteranges") {
+			return nil, ErrNotImplemented
+		}
+		var err error
+		if offset, err = parseContentRangeStart(resp.Header.Get("Content-Range")); err != nil {
+			glog.Errorf("encrypted object range %s: %v", resp.Header.Get("Content-Range"), err)
+			return nil, ErrInternalError
+		}
+	}
+
+	body, err := encryption.decryptReader(dataKey, resp.Body, offset)
+	if err != nil {
+		glog.Errorf("decrypt object: %v", err)
+		return nil, ErrInternalError
+	}
+	return body, ErrNone
+}
+
+// parseContentRangeStart returns the first byte position of "bytes start-end/size"
+func parseContentRangeStart(contentRange string) (int64, error) {
+	r := strings.TrimPrefix(contentRange, "bytes ")
+	dash := strings.Index(r, "-")
+	if dash < 0 {
+		return 0, fmt.Errorf("invalid content range %q", contentRange)
+	}
+	return strconv.ParseInt(r[:dash], 10, 64)
+}
+
+// openCopySource reads the copy source, decrypted if it is encrypted
+func (s3a *S3ApiServer) openCopySource(r *http.Request, srcUrl, rangeHeader string) (io.ReadCloser, ErrorCode) {
+
+	sourceKey, errCode := parseCopySourceCustomerKey(r)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	resp, errCode := s3a.getFromFiler(r, srcUrl, rangeHeader)
+	if errCode != ErrNone {
+		return nil, ErrInvalidCopySource
+	}
+
+	body, errCode := s3a.decryptResponse(resp, sourceKey)
+	if errCode != ErrNone {
+		resp.Body.Close()
+		return nil, errCode
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{body, resp.Body}, ErrNone
+}
@@ -0,0 +1,229 @@
+package s3api
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func newTestCustomerKey(t *testing.T) (*customerKey, http.Header) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	sum := md5.Sum(key)
+	header := make(http.Header)
+	header.Set(AmzServerSideEncryptionCustomerAlgorithm, ServerSideEncryptionAES256)
+	header.Set(AmzServerSideEncryptionCustomerKey, base64.StdEncoding.EncodeToString(key))
+	header.Set(AmzServerSideEncryptionCustomerKeyMD5, base64.StdEncoding.EncodeToString(sum[:]))
+	return &customerKey{key: key, keyMD5: base64.StdEncoding.EncodeToString(sum[:])}, header
+}
+
+func TestParseCustomerKey(t *testing.T) {
+
+	expected, header := newTestCustomerKey(t)
+	key, errCode := parseRequestCustomerKey(&http.Request{Header: header})
+	if errCode != ErrNone || !bytes.Equal(key.key, expected.key) || key.keyMD5 != expected.keyMD5 {
+		t.Fatalf("parse customer key: %v %+v", errCode, key)
+	}
+	if header.Get(AmzServerSideEncryptionCustomerKey) != "" {
+		t.Errorf("customer key header is not removed")
+	}
+
+	if key, errCode := parseRequestCustomerKey(&http.Request{Header: make(http.Header)}); key != nil || errCode != ErrNone {
+		t.Errorf("no customer key: %v %+v", errCode, key)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(header http.Header)
+		expected ErrorCode
+	}{
+		{"algorithm", func(h http.Header) { h.Set(AmzServerSideEncryptionCustomerAlgorithm, "AES128") }, ErrInvalidEncryptionAlgorithm},
+		{"short key", func(h http.Header) { h.Set(AmzServerSideEncryptionCustomerKey, "c2hvcnQ=") }, ErrInvalidSSECustomerKey},
+		{"key md5", func(h http.Header) { h.Set(AmzServerSideEncryptionCustomerKeyMD5, "bWQ1") }, ErrSSECustomerKeyMD5Mismatch},
+		{"missing key md5", func(h http.Header) { h.Del(AmzServerSideEncryptionCustomerKeyMD5) }, ErrSSECustomerKeyMD5Mismatch},
+	}
+	for _, test := range tests {
+		_, header := newTestCustomerKey(t)
+		test.modify(header)
+		if _, errCode := parseRequestCustomerKey(&http.Request{Header: header}); errCode != test.expected {
+			t.Errorf("%s: got error %v, expecting %v", test.name, errCode, test.expected)
+		}
+	}
+}
+
+func TestObjectEncryptionDataKey(t *testing.T) {
+
+	key, _ := newTestCustomerKey(t)
+	other, _ := newTestCustomerKey(t)
+	encryption, _ := newObjectEncryption(key)
+
+	extended := make(map[string][]byte)
+	encryption.saveTo(extended)
+	if _, found := extended[AmzServerSideEncryptionCustomerKey]; found {
+		t.Fatalf("customer key is persisted")
+	}
+	loaded := loadObjectEncryption(extended)
+
+	if dataKey, errCode := loaded.dataKey(key, nil); errCode != ErrNone || !bytes.Equal(dataKey, key.key) {
+		t.Errorf("same key: %v", errCode)
+	}
+	if _, errCode := loaded.dataKey(other, nil); errCode != ErrAccessDenied {
+		t.Errorf("other key: %v", errCode)
+	}
+	if _, errCode := loaded.dataKey(nil, nil); errCode != ErrSSECustomerKeyMissing {
+		t.Errorf("no key: %v", errCode)
+	}
+	var unencrypted *objectEncryption
+	if _, errCode := unencrypted.dataKey(key, nil); errCode != ErrSSEEncryptionParametersNotApplicable {
+		t.Errorf("unencrypted object: %v", errCode)
+	}
+}
+
+func TestServerSideEncryptionDataKey(t *testing.T) {
+
+	masterKey, _ := newTestCustomerKey(t)
+	otherMasterKey, _ := newTestCustomerKey(t)
+	encryption, dataKey, err := newServerSideEncryption(masterKey.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extended := make(map[string][]byte)
+	encryption.saveTo(extended)
+	if bytes.Contains(extended[seaweedEncryptionKey], []byte(base64.StdEncoding.EncodeToString(dataKey))) {
+		t.Fatalf("data key is persisted unwrapped")
+	}
+	loaded := loadObjectEncryption(extended)
+	if loaded.serverSideEncryption != ServerSideEncryptionAES256 {
+		t.Fatalf("loaded server side encryption %+v", loaded)
+	}
+
+	if unwrapped, errCode := loaded.dataKey(nil, masterKey.key); errCode != ErrNone || !bytes.Equal(unwrapped, dataKey) {
+		t.Errorf("unwrap data key: %v", errCode)
+	}
+	if _, errCode := loaded.dataKey(nil, otherMasterKey.key); errCode != ErrInternalError {
+		t.Errorf("unwrap with other master key: %v", errCode)
+	}
+	if _, errCode := loaded.dataKey(masterKey, masterKey.key); errCode != ErrSSEEncryptionParametersNotApplicable {
+		t.Errorf("customer key for server side encrypted object: %v", errCode)
+	}
+}
+
+func TestServerSideEncryptionConfiguration(t *testing.T) {
+
+	tests := []struct {
+		xml      string
+		expected ErrorCode
+	}{
+		{`<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>AES256</SSEAlgorithm></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`, ErrNone},
+		{`<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>aws:kms</SSEAlgorithm></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`, ErrNotImplemented},
+		{`<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>DES</SSEAlgorithm></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`, ErrMalformedXML},
+		{`<ServerSideEncryptionConfiguration></ServerSideEncryptionConfiguration>`, ErrMalformedXML},
+	}
+	for _, test := range tests {
+		config := &ServerSideEncryptionConfiguration{}
+		if err := xml.Unmarshal([]byte(test.xml), config); err != nil {
+			t.Fatalf("unmarshal %s: %v", test.xml, err)
+		}
+		if errCode := config.validate(); errCode != test.expected {
+			t.Errorf("validate %s: got %v, expecting %v", test.xml, errCode, test.expected)
+		}
+	}
+
+	var unconfigured *ServerSideEncryptionConfiguration
+	if unconfigured.defaultAlgorithm() != "" {
+		t.Errorf("default algorithm without configuration")
+	}
+}
+
+func TestDecryptMultipartRanges(t *testing.T) {
+
+	key, _ := newTestCustomerKey(t)
+	encryption, _ := newObjectEncryption(key)
+	// counters close to wrapping around the lower bytes
+	for i := 4; i < len(encryption.iv); i++ {
+		encryption.iv[i] = 0xff
+	}
+
+	partSizes := []int{100, 37, 250}
+	var plain, encrypted []byte
+	for i, size := range partSizes {
+		data := make([]byte, size)
+		rand.Read(data)
+		reader, err := encryption.encryptReader(key.key, bytes.NewReader(data), i+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encryptedData, _ := ioutil.ReadAll(reader)
+		encryption.parts = append(encryption.parts, encryptedPart{partNumber: i + 1, offset: int64(len(plain))})
+		plain = append(plain, data...)
+		encrypted = append(encrypted, encryptedData...)
+	}
+
+	extended := make(map[string][]byte)
+	encryption.saveTo(extended)
+	loaded := loadObjectEncryption(extended)
+
+	for _, offset := range []int{0, 5, 16, 99, 100, 101, 137, 150, 386} {
+		reader, err := loaded.decryptReader(key.key, bytes.NewReader(encrypted[offset:]), int64(offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, _ := ioutil.ReadAll(reader)
+		if !bytes.Equal(decrypted, plain[offset:]) {
+			t.Errorf("decrypt from offset %d differs", offset)
+		}
+	}
+}
+
+func TestParseContentRangeStart(t *testing.T) {
+	if start, err := parseContentRangeStart("bytes 100-199/1000"); err != nil || start != 100 {
+		t.Errorf("parse content range: %d %v", start, err)
+	}
+	if _, err := parseContentRangeStart("bytes */1000"); err == nil {
+		t.Errorf("parse unsatisfied content range")
+	}
+}
+
+func TestLoadResponseEncryption(t *testing.T) {
+
+	key, _ := newTestCustomerKey(t)
+	encryption, _ := newObjectEncryption(key)
+	object := &filer_pb.Entry{Name: "object", Attributes: &filer_pb.FuseAttributes{Md5: []byte{1}}, Extended: map[string][]byte{}}
+	encryption.saveTo(object.Extended)
+
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{"/buckets/bucket/object": object})
+	defer stop()
+
+	response := func(algorithm, etag string) *http.Response {
+		resp := &http.Response{Header: make(http.Header), Request: httptest.NewRequest("GET", "http://filer/buckets/bucket/object", nil)}
+		if algorithm != "" {
+			resp.Header.Set(AmzServerSideEncryptionCustomerAlgorithm, algorithm)
+		}
+		resp.Header.Set("ETag", etag)
+		return resp
+	}
+
+	// the filer does not return the iv, which is read from the entry
+	loaded, errCode := s3a.loadResponseEncryption(response(ServerSideEncryptionAES256, `"01"`))
+	if errCode != ErrNone || loaded == nil || !bytes.Equal(loaded.iv, encryption.iv) {
+		t.Errorf("load encryption: %v %+v", errCode, loaded)
+	}
+	if _, errCode = s3a.loadResponseEncryption(response(ServerSideEncryptionAES256, `"02"`)); errCode != ErrInternalError {
+		t.Errorf("load encryption of a changed object: %v", errCode)
+	}
+	if loaded, errCode = s3a.loadResponseEncryption(response("", `"01"`)); errCode != ErrNone || loaded != nil {
+		t.Errorf("load encryption of an unencrypted object: %v %+v", errCode, loaded)
+	}
+}
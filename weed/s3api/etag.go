@@ -0,0 +1,33 @@
+package s3api
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTCommonResponseHeaders.html
+// The etag of an object uploaded by a PUT or a copy is the md5 of the object data, which the filer saves in the entry,
+// unless the object is encrypted with a customer key, whose etag is the md5 of the encrypted data.
+// The etag of a multipart upload is the md5 of the concatenated part md5s, followed by "-" and the number of parts.
+// The etags which are not the md5 of the saved data are kept in the filer2.ETagKey attribute of the entry.
+
+// hasContentETag tells whether the etag of the encrypted object is still the md5 of the object data, as SSE-S3
+func (encryption *objectEncryption) hasContentETag() bool {
+	return encryption != nil && encryption.serverSideEncryption == ServerSideEncryptionAES256
+}
+
+// multipartETag is the etag of the completed multipart upload of the parts
+func multipartETag(parts objectParts) string {
+	h := md5.New()
+	for _, part := range parts {
+		partMd5, err := hex.DecodeString(part.etag)
+		if err != nil || len(partMd5) != md5.Size {
+			// not the md5 of the part data, e.g. of the parts uploaded by old versions
+			sum := md5.Sum([]byte(part.etag))
+			partMd5 = sum[:]
+		}
+		h.Write(partMd5)
+	}
+	return fmt.Sprintf("%x-%d", h.Sum(nil), len(parts))
+}
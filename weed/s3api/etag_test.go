@@ -0,0 +1,64 @@
+package s3api
+
+import (
+	"crypto/md5"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+func TestMultipartETag(t *testing.T) {
+	// md5(md5("part one") + md5("part two")) + "-2", as computed by the aws clients
+	parts := objectParts{
+		{partNumber: 1, etag: "3303e12af474ca11d85ed2966a932992"},
+		{partNumber: 2, etag: "3ea4e15b91a17dc76052c56cfcdf67a2"},
+	}
+	if etag := multipartETag(parts); etag != "0732917abc3288784e318ac0aab1757a-2" {
+		t.Errorf("multipart etag %s", etag)
+	}
+
+	// the parts without md5 etags still count
+	parts = append(parts, objectPart{partNumber: 3, etag: "1a2b3c"})
+	if etag := multipartETag(parts); len(etag) != 2*md5.Size+2 || etag[2*md5.Size:] != "-3" {
+		t.Errorf("multipart etag with a non md5 part %s", etag)
+	}
+}
+
+func TestObjectETag(t *testing.T) {
+	dataMd5 := md5.Sum([]byte("object data"))
+	listEntries := func(dir, prefix, startFrom string, inclusive bool, limit uint32) ([]*filer_pb.Entry, error) {
+		if startFrom != "" {
+			return nil, nil
+		}
+		return []*filer_pb.Entry{
+			{Name: "single", Attributes: &filer_pb.FuseAttributes{Md5: dataMd5[:]}},
+			{Name: "multipart", Attributes: &filer_pb.FuseAttributes{}, Extended: map[string][]byte{
+				filer2.ETagKey: []byte("0732917abc3288784e318ac0aab1757a-2"),
+			}},
+		}, nil
+	}
+
+	response, err := listBucketResult(listEntries, "bucket", "", 10, "", "", false)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	etags := make(map[string]string)
+	for _, content := range response.Contents {
+		etags[content.Key] = content.ETag
+	}
+	if etags["single"] != "\"85f30635602dc09bd85957a6e82a2c21\"" {
+		t.Errorf("single part etag %s", etags["single"])
+	}
+	if etags["multipart"] != "\"0732917abc3288784e318ac0aab1757a-2\"" {
+		t.Errorf("multipart etag %s", etags["multipart"])
+	}
+
+	var encryption *objectEncryption
+	if encryption.hasContentETag() || (&objectEncryption{customerAlgorithm: ServerSideEncryptionAES256}).hasContentETag() {
+		t.Errorf("only the SSE-S3 objects keep the md5 etag of the object data")
+	}
+	if !(&objectEncryption{serverSideEncryption: ServerSideEncryptionAES256}).hasContentETag() {
+		t.Errorf("SSE-S3 objects keep the md5 etag of the object data")
+	}
+}
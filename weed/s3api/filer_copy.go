@@ -0,0 +1,268 @@
+package s3api
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/operation"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/security"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The part copies read the source chunks from the volume servers and write them as new chunks,
+// without going through the filer. The copied chunks are never shared with the source object,
+// so either one can be deleted or overwritten independently.
+//
+// The object copies needing no change of the data share the chunks with the source object instead.
+// The filer counts the references to the shared chunks, and deletes them after both objects are deleted.
+
+// chunkViewReader reads the chunk views one by one
+type chunkViewReader struct {
+	views  []*filer2.ChunkView
+	lookup filer2.LookupFileIdFunctionType
+	buffer []byte
+}
+
+func newChunkViewReader(filerClient filer_pb.FilerClient, views []*filer2.ChunkView) *chunkViewReader {
+	return &chunkViewReader{
+		views:  views,
+		lookup: filer2.LookupFn(filerClient),
+	}
+}
+
+func (cr *chunkViewReader) Read(p []byte) (n int, err error) {
+	for len(cr.buffer) == 0 {
+		if len(cr.views) == 0 {
+			return 0, io.EOF
+		}
+		view := cr.views[0]
+		cr.views = cr.views[1:]
+		if cr.buffer, err = cr.readView(view); err != nil {
+			return 0, err
+		}
+	}
+	n = copy(p, cr.buffer)
+	cr.buffer = cr.buffer[n:]
+	return n, nil
+}
+
+func (cr *chunkViewReader) readView(view *filer2.ChunkView) ([]byte, error) {
+	fileUrl, err := cr.lookup(view.FileId)
+	if err != nil {
+		return nil, fmt.Errorf("lookup %s: %v", view.FileId, err)
+	}
+	data := make([]byte, view.Size)
+	n, err := util.ReadUrl(fileUrl, view.CipherKey, view.IsGzipped, view.IsFullChunk(), view.Offset, int(view.Size), data)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %v", view.FileId, err)
+	}
+	if n != int64(view.Size) {
+		return nil, fmt.Errorf("read %s: %d of %d bytes", view.FileId, n, view.Size)
+	}
+	return data, nil
+}
+
+// copyToPart writes the data of the chunk views, read from the reader, as the chunks of the multipart upload part.
+// The new chunks have the same sizes as the views, written to the tier of the upload, and are encrypted by the volume servers if the source chunks are.
+func (s3a *S3ApiServer) copyToPart(bucket, uploadID string, partID int, tier storageTier, views []*filer2.ChunkView, reader io.Reader) (etag string, code ErrorCode) {
+
+	uploadDirectory := s3a.genUploadsFolder(bucket) + "/" + uploadID
+	partName := fmt.Sprintf("%04d.part", partID-1)
+
+	hash := md5.New()
+	var chunks []*filer_pb.FileChunk
+	var offset int64
+	for _, view := range views {
+		data := make([]byte, view.Size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			glog.Errorf("copy to upload %s part %d: %v", uploadID, partID, err)
+			return "", ErrInternalError
+		}
+		hash.Write(data)
+		chunk, err := s3a.uploadChunk(bucket, uploadDirectory, tier, data, view.CipherKey != nil, offset)
+		if err != nil {
+			glog.Errorf("copy to upload %s part %d: %v", uploadID, partID, err)
+			s3a.deleteChunks(chunks)
+			return "", ErrInternalError
+		}
+		chunks = append(chunks, chunk)
+		offset += int64(view.Size)
+	}
+	// reaching the end verifies the checksum, if any
+	if n, err := reader.Read(make([]byte, 1)); n > 0 || (err != nil && err != io.EOF) {
+		glog.Errorf("copy to upload %s part %d: %d more bytes, %v", uploadID, partID, n, err)
+		s3a.deleteChunks(chunks)
+		return "", ErrInternalError
+	}
+
+	// the data of the replaced part is not referenced anywhere else
+	if oldPart, err := s3a.getEntry(uploadDirectory, partName); err == nil && oldPart != nil {
+		s3a.deleteChunks(oldPart.Chunks)
+	}
+
+	now := time.Now().Unix()
+	md5sum := hash.Sum(nil)
+	if err := s3a.createEntry(uploadDirectory, &filer_pb.Entry{
+		Name: partName,
+		Attributes: &filer_pb.FuseAttributes{
+			Mtime:    now,
+			Crtime:   now,
+			FileMode: uint32(0660),
+			Uid:      filer_pb.OS_UID,
+			Gid:      filer_pb.OS_GID,
+			FileSize: uint64(offset),
+			Md5:      md5sum,
+		},
+		Chunks: chunks,
+	}); err != nil {
+		glog.Errorf("create upload %s part %d: %v", uploadID, partID, err)
+		s3a.deleteChunks(chunks)
+		return "", ErrInternalError
+	}
+
+	return fmt.Sprintf("%x", md5sum), ErrNone
+}
+
+func (s3a *S3ApiServer) uploadChunk(collection, parentPath string, tier storageTier, data []byte, cipher bool, offset int64) (*filer_pb.FileChunk, error) {
+
+	var fileId, host string
+	var auth security.EncodedJwt
+	if err := s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+		request := &filer_pb.AssignVolumeRequest{
+			Count:       1,
+			Collection:  collection,
+			Replication: tier.Replication,
+			TtlSec:      tier.ttlSec(),
+			DataCenter:  tier.DataCenter,
+			DiskType:    tier.DiskType,
+			ParentPath:  parentPath,
+		}
+		resp, err := client.AssignVolume(context.Background(), request)
+		if err != nil {
+			return fmt.Errorf("assign volume %v: %v", request, err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("assign volume %v: %v", request, resp.Error)
+		}
+		fileId, host, auth = resp.FileId, s3a.AdjustedUrl(resp.Url), security.EncodedJwt(resp.Auth)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	fileUrl := fmt.Sprintf("http://%s/%s", host, fileId)
+	uploadResult, err, _ := operation.Upload(fileUrl, "", cipher, bytes.NewReader(data), false, "", nil, auth)
+	if err != nil {
+		return nil, fmt.Errorf("upload %s: %v", fileUrl, err)
+	}
+	if uploadResult.Error != "" {
+		return nil, fmt.Errorf("upload %s: %v", fileUrl, uploadResult.Error)
+	}
+
+	return uploadResult.ToPbFileChunk(fileId, offset), nil
+}
+
+func (s3a *S3ApiServer) deleteChunks(chunks []*filer_pb.FileChunk) {
+	if len(chunks) == 0 {
+		return
+	}
+
+	var fileIds []string
+	for _, chunk := range chunks {
+		fileIds = append(fileIds, chunk.GetFileIdString())
+	}
+
+	s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+
+		lookupFunc := func(vids []string) (map[string]operation.LookupResult, error) {
+
+			m := make(map[string]operation.LookupResult)
+
+			resp, err := client.LookupVolume(context.Background(), &filer_pb.LookupVolumeRequest{
+				VolumeIds: vids,
+			})
+			if err != nil {
+				return m, err
+			}
+
+			for _, vid := range vids {
+				lr := operation.LookupResult{
+					VolumeId: vid,
+				}
+				locations, found := resp.LocationsMap[vid]
+				if !found {
+					continue
+				}
+				for _, loc := range locations.Locations {
+					lr.Locations = append(lr.Locations, operation.Location{
+						Url:       s3a.AdjustedUrl(loc.Url),
+						PublicUrl: loc.PublicUrl,
+					})
+				}
+				m[vid] = lr
+			}
+
+			return m, err
+		}
+
+		if _, err := operation.DeleteFilesWithLookupVolumeId(s3a.option.GrpcDialOption, fileIds, lookupFunc); err != nil {
+			glog.V(0).Infof("delete %d chunks: %v", len(fileIds), err)
+		}
+		return nil
+	})
+}
+
+// canCopyByReference checks the copied object can share the chunks of the source object,
+// i.e. the data is not encrypted, and is stored in the same collection and tier as the destination.
+func canCopyByReference(srcEntry *filer_pb.Entry, dstBucket, storageClass string, tier storageTier, encryption *objectEncryption, checksumAlgorithm string) bool {
+	if srcEntry == nil || srcEntry.IsDirectory || srcEntry.Attributes == nil || isDeleteMarker(srcEntry) {
+		return false
+	}
+	if encryption != nil || loadObjectEncryption(srcEntry.Extended) != nil {
+		return false
+	}
+	attributes := srcEntry.Attributes
+	if attributes.Collection != dstBucket || getStorageClass(srcEntry.Extended) != storageClass {
+		return false
+	}
+	if (tier.Replication != "" && tier.Replication != attributes.Replication) || tier.ttlSec() != attributes.TtlSec || tier.DataCenter != "" || tier.DiskType != "" {
+		return false
+	}
+	if checksumAlgorithm != "" {
+		checksum := loadObjectChecksum(srcEntry.Extended)
+		return checksum != nil && checksum.algorithm == checksumAlgorithm
+	}
+	return true
+}
+
+// copyEntry creates the destination object with the chunks of the source object, without copying the data
+func (s3a *S3ApiServer) copyEntry(srcBucket, srcObject, dstBucket, dstObject string) (entry *filer_pb.Entry, err error) {
+
+	srcDir, srcName := s3a.objectDirAndName(srcBucket, srcObject)
+	dstDir, dstName := s3a.objectDirAndName(dstBucket, dstObject)
+
+	err = s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+		request := &filer_pb.CopyEntryRequest{
+			SourceDirectory: srcDir,
+			SourceName:      srcName,
+			TargetDirectory: dstDir,
+			TargetName:      dstName,
+		}
+		glog.V(1).Infof("copy entry %v", request)
+		resp, err := client.CopyEntry(context.Background(), request)
+		if err != nil {
+			return fmt.Errorf("copy entry %s/%s to %s/%s: %v", srcDir, srcName, dstDir, dstName, err)
+		}
+		entry = resp.Entry
+		return nil
+	})
+
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/filer2"
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
 type InitiateMultipartUploadResult struct {
@@ -22,7 +24,7 @@ type InitiateMultipartUploadResult struct {
 	s3.CreateMultipartUploadOutput
 }
 
-func (s3a *S3ApiServer) createMultipartUpload(input *s3.CreateMultipartUploadInput) (output *InitiateMultipartUploadResult, code ErrorCode) {
+func (s3a *S3ApiServer) createMultipartUpload(input *s3.CreateMultipartUploadInput, encryption *objectEncryption, checksumAlgorithm string, metadata *objectMetadata) (output *InitiateMultipartUploadResult, code ErrorCode) {
 	uploadId, _ := uuid.NewRandom()
 	uploadIdString := uploadId.String()
 
@@ -31,6 +33,33 @@ func (s3a *S3ApiServer) createMultipartUpload(input *s3.CreateMultipartUploadInp
 			entry.Extended = make(map[string][]byte)
 		}
 		entry.Extended["key"] = []byte(*input.Key)
+		lock := &objectLock{
+			mode:            aws.StringValue(input.ObjectLockMode),
+			retainUntilDate: aws.TimeValue(input.ObjectLockRetainUntilDate),
+			legalHold:       aws.StringValue(input.ObjectLockLegalHoldStatus),
+		}
+		lock.saveTo(entry.Extended)
+		if encryption != nil {
+			encryption.saveTo(entry.Extended)
+		}
+		if checksumAlgorithm != "" {
+			entry.Extended[AmzChecksumAlgorithm] = []byte(checksumAlgorithm)
+		}
+		if storageClass := aws.StringValue(input.StorageClass); storageClass != "" && storageClass != StorageClassStandard {
+			entry.Extended[AmzStorageClass] = []byte(storageClass)
+		}
+		// the tags are validated by the handler
+		if input.Tagging != nil {
+			tags, _ := parseTaggingHeader(*input.Tagging)
+			tags.saveTo(entry.Extended)
+		}
+		if input.ACL != nil {
+			entry.Extended[objectAclKey] = []byte(*input.ACL)
+		}
+		// the metadata is kept with the upload until the object is completed
+		if !metadata.isEmpty() {
+			metadata.saveTo(entry)
+		}
 	}); err != nil {
 		glog.Errorf("NewMultipartUpload error: %v", err)
 		return nil, ErrInternalError
@@ -50,35 +79,88 @@ func (s3a *S3ApiServer) createMultipartUpload(input *s3.CreateMultipartUploadInp
 type CompleteMultipartUploadResult struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CompleteMultipartUploadResult"`
 	s3.CompleteMultipartUploadOutput
+	ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+	VersionId      string `xml:"-"` // returned in the x-amz-version-id header
+	Size           int64  `xml:"-"`
+}
+
+func (result *CompleteMultipartUploadResult) setChecksum(checksum *objectChecksum) {
+	if checksum == nil {
+		return
+	}
+	switch checksum.algorithm {
+	case ChecksumAlgorithmCRC32:
+		result.ChecksumCRC32 = checksum.value
+	case ChecksumAlgorithmCRC32C:
+		result.ChecksumCRC32C = checksum.value
+	case ChecksumAlgorithmSHA1:
+		result.ChecksumSHA1 = checksum.value
+	case ChecksumAlgorithmSHA256:
+		result.ChecksumSHA256 = checksum.value
+	}
 }
 
 func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploadInput) (output *CompleteMultipartUploadResult, code ErrorCode) {
 
 	uploadDirectory := s3a.genUploadsFolder(*input.Bucket) + "/" + *input.UploadId
 
+	uploadEntry, err := s3a.getEntry(s3a.genUploadsFolder(*input.Bucket), *input.UploadId)
+	if err != nil || uploadEntry == nil {
+		glog.Errorf("completeMultipartUpload %s %s lookup: %v", *input.Bucket, *input.UploadId, err)
+		return nil, ErrNoSuchUpload
+	}
+
 	entries, err := s3a.list(uploadDirectory, "", "", false, 0)
 	if err != nil {
 		glog.Errorf("completeMultipartUpload %s %s error: %v", *input.Bucket, *input.UploadId, err)
 		return nil, ErrNoSuchUpload
 	}
 
+	encryption := loadObjectEncryption(uploadEntry.Extended)
+	checksumAlgorithm := string(uploadEntry.Extended[AmzChecksumAlgorithm])
+	var partChecksums []string
+	var parts objectParts
+
 	var finalParts []*filer_pb.FileChunk
 	var offset int64
 
 	for _, entry := range entries {
 		if strings.HasSuffix(entry.Name, ".part") && !entry.IsDirectory {
+			partNumber, err := strconv.Atoi(strings.TrimSuffix(entry.Name, ".part"))
+			if err != nil {
+				glog.Errorf("completeMultipartUpload %s %s part %s: %v", *input.Bucket, *input.UploadId, entry.Name, err)
+				return nil, ErrInvalidPart
+			}
+			part := objectPart{partNumber: partNumber + 1, size: int64(filer2.FileSize(entry)), etag: filer2.ETag(entry)}
+			if encryption != nil {
+				encryption.parts = append(encryption.parts, encryptedPart{partNumber: partNumber + 1, offset: offset})
+			}
+			if checksumAlgorithm != "" {
+				partChecksum := loadObjectChecksum(entry.Extended)
+				if partChecksum == nil || partChecksum.algorithm != checksumAlgorithm {
+					glog.Errorf("completeMultipartUpload %s %s part %s has no %s checksum", *input.Bucket, *input.UploadId, entry.Name, checksumAlgorithm)
+					return nil, ErrInvalidPart
+				}
+				partChecksums = append(partChecksums, partChecksum.value)
+				part.checksum = partChecksum.value
+			}
+			parts = append(parts, part)
+			// the parts may be split into chunks of different sizes
 			for _, chunk := range entry.Chunks {
 				p := &filer_pb.FileChunk{
 					FileId:    chunk.GetFileIdString(),
-					Offset:    offset,
+					Offset:    offset + chunk.Offset,
 					Size:      chunk.Size,
 					Mtime:     chunk.Mtime,
 					CipherKey: chunk.CipherKey,
 					ETag:      chunk.ETag,
 				}
 				finalParts = append(finalParts, p)
-				offset += int64(chunk.Size)
 			}
+			offset += int64(filer2.FileSize(entry))
 		}
 	}
 
@@ -97,10 +179,56 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 		dirName = dirName[:len(dirName)-1]
 	}
 
+	if code = s3a.checkObjectSize(*input.Bucket, offset, false); code != ErrNone {
+		return nil, code
+	}
+
+	object := "/" + *objectKey(input.Key)
+	versionId, code := s3a.prepareObjectVersion(*input.Bucket, object)
+	if code != ErrNone {
+		return nil, code
+	}
+	defer func() {
+		s3a.finishObjectVersion(*input.Bucket, object, versionId, code == ErrNone)
+	}()
+
 	err = s3a.mkFile(dirName, entryName, finalParts)
 
 	if err != nil {
 		glog.Errorf("completeMultipartUpload %s/%s error: %v", dirName, entryName, err)
+		return nil, filerWriteErrorCode(err.Error())
+	}
+
+	var checksum *objectChecksum
+	if checksumAlgorithm != "" {
+		if checksum, err = compositeChecksum(checksumAlgorithm, partChecksums); err != nil {
+			glog.Errorf("completeMultipartUpload %s/%s checksum: %v", dirName, entryName, err)
+			return nil, ErrInvalidPart
+		}
+	}
+
+	lock := loadObjectLock(uploadEntry.Extended)
+	if lock.mode == "" && lock.legalHold == "" {
+		lock = nil
+	}
+	tags := loadObjectTags(uploadEntry.Extended)
+	if len(tags) == 0 {
+		tags = nil
+	}
+	etag := multipartETag(parts)
+	if err = s3a.saveObjectAttributes(*input.Bucket, object, objectAttributes{
+		lock:         lock,
+		versionId:    versionId,
+		encryption:   encryption,
+		checksum:     checksum,
+		tags:         tags,
+		acl:          string(uploadEntry.Extended[objectAclKey]),
+		storageClass: getStorageClass(uploadEntry.Extended),
+		parts:        parts,
+		metadata:     loadObjectMetadata(uploadEntry),
+		etag:         etag,
+	}); err != nil {
+		glog.Errorf("completeMultipartUpload %s/%s attributes: %v", dirName, entryName, err)
 		return nil, ErrInternalError
 	}
 
@@ -108,10 +236,13 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 		CompleteMultipartUploadOutput: s3.CompleteMultipartUploadOutput{
 			Location: aws.String(fmt.Sprintf("http://%s%s/%s", s3a.option.Filer, dirName, entryName)),
 			Bucket:   input.Bucket,
-			ETag:     aws.String("\"" + filer2.ETagChunks(finalParts) + "\""),
+			ETag:     aws.String("\"" + etag + "\""),
 			Key:      objectKey(input.Key),
 		},
+		VersionId: versionId,
+		Size:      offset,
 	}
+	output.setChecksum(checksum)
 
 	if err = s3a.rm(s3a.genUploadsFolder(*input.Bucket), *input.UploadId, false, true); err != nil {
 		glog.V(1).Infof("completeMultipartUpload cleanup %s upload %s: %v", *input.Bucket, *input.UploadId, err)
@@ -120,6 +251,29 @@ func (s3a *S3ApiServer) completeMultipartUpload(input *s3.CompleteMultipartUploa
 	return
 }
 
+// savePartChecksum keeps the part checksum, to compute the checksum of the multipart object
+func (s3a *S3ApiServer) savePartChecksum(bucket, uploadID string, partID int, checksum *objectChecksum) error {
+
+	if checksum == nil {
+		return nil
+	}
+
+	uploadDirectory := s3a.genUploadsFolder(bucket) + "/" + uploadID
+	entry, err := s3a.getEntry(uploadDirectory, fmt.Sprintf("%04d.part", partID-1))
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return filer_pb.ErrNotFound
+	}
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	checksum.saveTo(entry.Extended)
+
+	return s3a.updateEntry(uploadDirectory, entry)
+}
+
 func (s3a *S3ApiServer) abortMultipartUpload(input *s3.AbortMultipartUploadInput) (output *s3.AbortMultipartUploadOutput, code ErrorCode) {
 
 	exists, err := s3a.exists(s3a.genUploadsFolder(*input.Bucket), *input.UploadId, true)
@@ -147,29 +301,88 @@ func (s3a *S3ApiServer) listMultipartUploads(input *s3.ListMultipartUploadsInput
 
 	output = &ListMultipartUploadsResult{
 		ListMultipartUploadsOutput: s3.ListMultipartUploadsOutput{
-			Bucket:       input.Bucket,
-			Delimiter:    input.Delimiter,
-			EncodingType: input.EncodingType,
-			KeyMarker:    input.KeyMarker,
-			MaxUploads:   input.MaxUploads,
-			Prefix:       input.Prefix,
+			Bucket:         input.Bucket,
+			Delimiter:      input.Delimiter,
+			EncodingType:   input.EncodingType,
+			KeyMarker:      input.KeyMarker,
+			MaxUploads:     input.MaxUploads,
+			Prefix:         input.Prefix,
+			UploadIdMarker: input.UploadIdMarker,
 		},
 	}
 
-	entries, err := s3a.list(s3a.genUploadsFolder(*input.Bucket), *input.Prefix, *input.KeyMarker, true, uint32(*input.MaxUploads))
+	// the upload folders are named by the upload ids, so all the uploads are read to sort them by the keys
+	var uploads []*s3.MultipartUpload
+	err := filer_pb.ReadDirAllEntries(s3a, util.FullPath(s3a.genUploadsFolder(*input.Bucket)), "", func(entry *filer_pb.Entry, isLast bool) error {
+		key, found := entry.Extended["key"]
+		if !entry.IsDirectory || !found {
+			return nil
+		}
+		upload := &s3.MultipartUpload{
+			Key:      objectKey(aws.String(string(key))),
+			UploadId: aws.String(entry.Name),
+		}
+		if entry.Attributes != nil {
+			upload.Initiated = aws.Time(time.Unix(entry.Attributes.Crtime, 0).UTC())
+		}
+		if strings.HasPrefix(*upload.Key, *input.Prefix) {
+			uploads = append(uploads, upload)
+		}
+		return nil
+	})
 	if err != nil {
 		glog.Errorf("listMultipartUploads %s error: %v", *input.Bucket, err)
 		return
 	}
+	sort.Slice(uploads, func(i, j int) bool {
+		if *uploads[i].Key != *uploads[j].Key {
+			return *uploads[i].Key < *uploads[j].Key
+		}
+		return *uploads[i].UploadId < *uploads[j].UploadId
+	})
+
+	keyMarker, uploadIdMarker, delimiter := *input.KeyMarker, *input.UploadIdMarker, *input.Delimiter
+	var count int64
+	for _, upload := range uploads {
+		key := *upload.Key
+		if uploadIdMarker == "" || key != keyMarker {
+			if key <= keyMarker {
+				continue
+			}
+		} else if *upload.UploadId <= uploadIdMarker {
+			continue
+		}
 
-	for _, entry := range entries {
-		if entry.Extended != nil {
-			key := entry.Extended["key"]
-			output.Uploads = append(output.Uploads, &s3.MultipartUpload{
-				Key:      objectKey(aws.String(string(key))),
-				UploadId: aws.String(entry.Name),
-			})
+		// the uploads under a common prefix are listed once as the common prefix
+		commonPrefix := ""
+		if delimiter != "" {
+			if i := strings.Index(key[len(*input.Prefix):], delimiter); i >= 0 {
+				commonPrefix = key[:len(*input.Prefix)+i+len(delimiter)]
+			}
+		}
+		if commonPrefix != "" && commonPrefix == keyMarker {
+			continue
+		}
+		if n := len(output.CommonPrefixes); commonPrefix != "" && n > 0 && *output.CommonPrefixes[n-1].Prefix == commonPrefix {
+			continue
+		}
+
+		if count >= *input.MaxUploads {
+			output.IsTruncated = aws.Bool(true)
+			break
+		}
+		count++
+
+		if commonPrefix != "" {
+			output.CommonPrefixes = append(output.CommonPrefixes, &s3.CommonPrefix{Prefix: aws.String(commonPrefix)})
+			output.NextKeyMarker, output.NextUploadIdMarker = aws.String(commonPrefix), nil
+			continue
 		}
+		output.Uploads = append(output.Uploads, upload)
+		output.NextKeyMarker, output.NextUploadIdMarker = upload.Key, upload.UploadId
+	}
+	if output.IsTruncated == nil {
+		output.IsTruncated = aws.Bool(false)
 	}
 
 	return
@@ -188,10 +401,24 @@ func (s3a *S3ApiServer) listObjectParts(input *s3.ListPartsInput) (output *ListP
 			UploadId:         input.UploadId,
 			MaxParts:         input.MaxParts,         // the maximum number of parts to return.
 			PartNumberMarker: input.PartNumberMarker, // the part number starts after this, exclusive
+			IsTruncated:      aws.Bool(false),
 		},
 	}
 
-	entries, err := s3a.list(s3a.genUploadsFolder(*input.Bucket)+"/"+*input.UploadId, "", fmt.Sprintf("%04d.part", *input.PartNumberMarker), false, uint32(*input.MaxParts))
+	uploadEntry, err := s3a.getEntry(s3a.genUploadsFolder(*input.Bucket), *input.UploadId)
+	if err != nil || uploadEntry == nil {
+		glog.Errorf("listObjectParts %s %s lookup: %v", *input.Bucket, *input.UploadId, err)
+		return nil, ErrNoSuchUpload
+	}
+
+	// the part files are named by the zero based part index, padded to be listed in the order of the part numbers
+	startFrom := ""
+	if *input.PartNumberMarker > 0 {
+		startFrom = fmt.Sprintf("%04d.part", *input.PartNumberMarker-1)
+	}
+
+	// one more part to know whether the listing is truncated
+	entries, err := s3a.list(s3a.genUploadsFolder(*input.Bucket)+"/"+*input.UploadId, "", startFrom, false, uint32(*input.MaxParts+1))
 	if err != nil {
 		glog.Errorf("listObjectParts %s %s error: %v", *input.Bucket, *input.UploadId, err)
 		return nil, ErrNoSuchUpload
@@ -200,17 +427,22 @@ func (s3a *S3ApiServer) listObjectParts(input *s3.ListPartsInput) (output *ListP
 	for _, entry := range entries {
 		if strings.HasSuffix(entry.Name, ".part") && !entry.IsDirectory {
 			partNumberString := entry.Name[:len(entry.Name)-len(".part")]
-			partNumber, err := strconv.Atoi(partNumberString)
+			partIndex, err := strconv.Atoi(partNumberString)
 			if err != nil {
 				glog.Errorf("listObjectParts %s %s parse %s: %v", *input.Bucket, *input.UploadId, entry.Name, err)
 				continue
 			}
+			if int64(len(output.Parts)) >= *input.MaxParts {
+				output.IsTruncated = aws.Bool(true)
+				break
+			}
 			output.Parts = append(output.Parts, &s3.Part{
-				PartNumber:   aws.Int64(int64(partNumber)),
+				PartNumber:   aws.Int64(int64(partIndex + 1)),
 				LastModified: aws.Time(time.Unix(entry.Attributes.Mtime, 0).UTC()),
-				Size:         aws.Int64(int64(filer2.TotalSize(entry.Chunks))),
+				Size:         aws.Int64(int64(filer2.FileSize(entry))),
 				ETag:         aws.String("\"" + filer2.ETag(entry) + "\""),
 			})
+			output.NextPartNumberMarker = aws.Int64(int64(partIndex + 1))
 		}
 	}
 
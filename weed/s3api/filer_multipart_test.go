@@ -1,9 +1,14 @@
 package s3api
 
 import (
+	"fmt"
+	"testing"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
 func TestInitiateMultipartUploadResult(t *testing.T) {
@@ -24,3 +29,150 @@ func TestInitiateMultipartUploadResult(t *testing.T) {
 	}
 
 }
+
+func TestListObjectPartsPagination(t *testing.T) {
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket/.uploads/upload": {IsDirectory: true},
+	}
+	// part 13 is missing
+	for partNumber := 1; partNumber <= 25; partNumber++ {
+		if partNumber != 13 {
+			entries[util.FullPath(fmt.Sprintf("/buckets/bucket/.uploads/upload/%04d.part", partNumber-1))] = &filer_pb.Entry{
+				Attributes: &filer_pb.FuseAttributes{Mtime: 1},
+				Content:    make([]byte, partNumber),
+			}
+		}
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+
+	var partNumbers []int64
+	var marker int64
+	for page := 0; ; page++ {
+		output, code := s3a.listObjectParts(&s3.ListPartsInput{
+			Bucket:           aws.String("bucket"),
+			Key:              aws.String("key"),
+			UploadId:         aws.String("upload"),
+			MaxParts:         aws.Int64(10),
+			PartNumberMarker: aws.Int64(marker),
+		})
+		if code != ErrNone {
+			t.Fatalf("list parts after %d: %v", marker, code)
+		}
+		if len(output.Parts) > 10 {
+			t.Fatalf("listed %d parts, over max parts", len(output.Parts))
+		}
+		for _, part := range output.Parts {
+			if *part.Size != *part.PartNumber {
+				t.Errorf("part %d has size %d", *part.PartNumber, *part.Size)
+			}
+			partNumbers = append(partNumbers, *part.PartNumber)
+		}
+		if !*output.IsTruncated {
+			break
+		}
+		if page > 3 {
+			t.Fatalf("too many pages")
+		}
+		marker = *output.NextPartNumberMarker
+	}
+
+	if len(partNumbers) != 24 {
+		t.Fatalf("listed parts %v", partNumbers)
+	}
+	for i, partNumber := range partNumbers {
+		expected := int64(i + 1)
+		if expected >= 13 {
+			expected++
+		}
+		if partNumber != expected {
+			t.Fatalf("listed parts %v", partNumbers)
+		}
+	}
+
+	if _, code := s3a.listObjectParts(&s3.ListPartsInput{
+		Bucket:           aws.String("bucket"),
+		Key:              aws.String("key"),
+		UploadId:         aws.String("missing"),
+		MaxParts:         aws.Int64(10),
+		PartNumberMarker: aws.Int64(0),
+	}); code != ErrNoSuchUpload {
+		t.Errorf("list parts of a missing upload: %v", code)
+	}
+}
+
+func listUploads(t *testing.T, s3a *S3ApiServer, prefix, delimiter, keyMarker, uploadIdMarker string, maxUploads int64) *ListMultipartUploadsResult {
+	output, code := s3a.listMultipartUploads(&s3.ListMultipartUploadsInput{
+		Bucket:         aws.String("bucket"),
+		Delimiter:      aws.String(delimiter),
+		EncodingType:   aws.String(""),
+		KeyMarker:      aws.String(keyMarker),
+		MaxUploads:     aws.Int64(maxUploads),
+		Prefix:         aws.String(prefix),
+		UploadIdMarker: aws.String(uploadIdMarker),
+	})
+	if code != ErrNone {
+		t.Fatalf("list uploads: %v", code)
+	}
+	return output
+}
+
+func TestListMultipartUploadsPagination(t *testing.T) {
+	entries := map[util.FullPath]*filer_pb.Entry{}
+	// the upload ids are in the reverse order of the keys
+	var expected []string
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 3; j++ {
+			key, uploadId := fmt.Sprintf("dir/key%02d", i), fmt.Sprintf("%02d-%d", 99-i, j)
+			entries[util.FullPath("/buckets/bucket/.uploads/"+uploadId)] = &filer_pb.Entry{
+				IsDirectory: true,
+				Attributes:  &filer_pb.FuseAttributes{},
+				Extended:    map[string][]byte{"key": []byte(key)},
+			}
+			expected = append(expected, key+" "+uploadId)
+		}
+	}
+	entries["/buckets/bucket/.uploads/other"] = &filer_pb.Entry{
+		IsDirectory: true,
+		Extended:    map[string][]byte{"key": []byte("other/key")},
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+
+	var listed []string
+	keyMarker, uploadIdMarker := "", ""
+	for page := 0; ; page++ {
+		output := listUploads(t, s3a, "dir/", "", keyMarker, uploadIdMarker, 7)
+		if len(output.Uploads) > 7 {
+			t.Fatalf("listed %d uploads, over max uploads", len(output.Uploads))
+		}
+		for _, upload := range output.Uploads {
+			listed = append(listed, *upload.Key+" "+*upload.UploadId)
+		}
+		if !*output.IsTruncated {
+			break
+		}
+		if page > 5 {
+			t.Fatalf("too many pages")
+		}
+		keyMarker, uploadIdMarker = *output.NextKeyMarker, *output.NextUploadIdMarker
+	}
+	if fmt.Sprint(listed) != fmt.Sprint(expected) {
+		t.Errorf("listed uploads %v\nexpecting %v", listed, expected)
+	}
+
+	// a key marker without the upload id marker starts after all the uploads of the key
+	output := listUploads(t, s3a, "dir/", "", "dir/key08", "", 10)
+	if len(output.Uploads) != 3 || *output.Uploads[0].Key != "dir/key09" {
+		t.Errorf("uploads after dir/key08: %v", output.Uploads)
+	}
+
+	output = listUploads(t, s3a, "", "/", "", "", 10)
+	if len(output.Uploads) != 0 || len(output.CommonPrefixes) != 2 || *output.CommonPrefixes[0].Prefix != "dir/" || *output.CommonPrefixes[1].Prefix != "other/" {
+		t.Errorf("common prefixes %v, uploads %v", output.CommonPrefixes, output.Uploads)
+	}
+	output = listUploads(t, s3a, "", "/", "dir/", "", 10)
+	if len(output.CommonPrefixes) != 1 || *output.CommonPrefixes[0].Prefix != "other/" {
+		t.Errorf("common prefixes after dir/: %v", output.CommonPrefixes)
+	}
+}
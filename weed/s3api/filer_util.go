@@ -7,6 +7,7 @@ import (
 
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
 func (s3a *S3ApiServer) mkdir(parentDirectoryPath string, dirName string, fn func(entry *filer_pb.Entry)) error {
@@ -46,13 +47,32 @@ func (s3a *S3ApiServer) rm(parentDirectoryPath, entryName string, isDeleteData,
 
 }
 
+// rmTree deletes the entries one at a time, throttled by the filer
+func (s3a *S3ApiServer) rmTree(parentDirectoryPath, entryName string, isDeleteData bool) error {
+
+	return s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+		return sendDeleteEntry(client, &filer_pb.DeleteEntryRequest{
+			Directory:    parentDirectoryPath,
+			Name:         entryName,
+			IsDeleteData: isDeleteData,
+			IsRecursive:  true,
+			IsDeleteTree: true,
+		})
+	})
+
+}
+
 func doDeleteEntry(client filer_pb.SeaweedFilerClient, parentDirectoryPath string, entryName string, isDeleteData bool, isRecursive bool) error {
-	request := &filer_pb.DeleteEntryRequest{
+	return sendDeleteEntry(client, &filer_pb.DeleteEntryRequest{
 		Directory:    parentDirectoryPath,
 		Name:         entryName,
 		IsDeleteData: isDeleteData,
 		IsRecursive:  isRecursive,
-	}
+	})
+}
+
+func sendDeleteEntry(client filer_pb.SeaweedFilerClient, request *filer_pb.DeleteEntryRequest) error {
+	parentDirectoryPath, entryName := request.Directory, request.Name
 
 	glog.V(1).Infof("delete entry %v/%v: %v", parentDirectoryPath, entryName, request)
 	if resp, err := client.DeleteEntry(context.Background(), request); err != nil {
@@ -72,6 +92,102 @@ func (s3a *S3ApiServer) exists(parentDirectoryPath string, entryName string, isD
 
 }
 
+func (s3a *S3ApiServer) getEntry(parentDirectoryPath, entryName string) (entry *filer_pb.Entry, err error) {
+
+	fullPath := util.NewFullPath(parentDirectoryPath, entryName)
+	return filer_pb.GetEntry(s3a, fullPath)
+
+}
+
+func (s3a *S3ApiServer) updateEntry(parentDirectoryPath string, newEntry *filer_pb.Entry) error {
+
+	return s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+
+		request := &filer_pb.UpdateEntryRequest{
+			Directory: parentDirectoryPath,
+			Entry:     newEntry,
+		}
+
+		glog.V(1).Infof("update entry %v/%v: %v", parentDirectoryPath, newEntry.Name, request)
+		if _, err := client.UpdateEntry(context.Background(), request); err != nil {
+			glog.V(0).Infof("update entry %v: %v", request, err)
+			return fmt.Errorf("update entry %s/%s: %v", parentDirectoryPath, newEntry.Name, err)
+		}
+
+		return nil
+	})
+
+}
+
+func (s3a *S3ApiServer) createEntry(parentDirectoryPath string, entry *filer_pb.Entry) error {
+
+	return s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+
+		request := &filer_pb.CreateEntryRequest{
+			Directory: parentDirectoryPath,
+			Entry:     entry,
+		}
+
+		glog.V(1).Infof("create entry %v/%v: %v", parentDirectoryPath, entry.Name, request)
+		if err := filer_pb.CreateEntry(client, request); err != nil {
+			glog.V(0).Infof("create entry %v: %v", request, err)
+			return fmt.Errorf("create entry %s/%s: %v", parentDirectoryPath, entry.Name, err)
+		}
+
+		return nil
+	})
+
+}
+
+// createEntryIf creates or overwrites the entry only if the etag of the existing entry matches ifMatch,
+// or creates it only if it does not exist for an empty ifMatch. It fails with filer_pb.ErrPreconditionFailed otherwise.
+func (s3a *S3ApiServer) createEntryIf(parentDirectoryPath string, entry *filer_pb.Entry, ifMatch string) error {
+
+	return s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+
+		request := &filer_pb.CreateEntryRequest{
+			Directory: parentDirectoryPath,
+			Entry:     entry,
+			IfMatch:   ifMatch,
+		}
+		if ifMatch == "" {
+			request.IfNoneMatch = "*"
+		}
+
+		glog.V(1).Infof("create entry %v/%v if %q: %v", parentDirectoryPath, entry.Name, ifMatch, request)
+		return filer_pb.CreateEntry(client, request)
+	})
+
+}
+
+func (s3a *S3ApiServer) rename(oldDirectoryPath, oldName, newDirectoryPath, newName string) error {
+
+	return s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+
+		request := &filer_pb.AtomicRenameEntryRequest{
+			OldDirectory: oldDirectoryPath,
+			OldName:      oldName,
+			NewDirectory: newDirectoryPath,
+			NewName:      newName,
+		}
+
+		glog.V(1).Infof("rename entry %v/%v => %v/%v", oldDirectoryPath, oldName, newDirectoryPath, newName)
+		if _, err := client.AtomicRenameEntry(context.Background(), request); err != nil {
+			glog.V(0).Infof("rename entry %v: %v", request, err)
+			return fmt.Errorf("rename entry %s/%s: %v", oldDirectoryPath, oldName, err)
+		}
+
+		return nil
+	})
+
+}
+
+// objectDirAndName converts the bucket and the "/"-prefixed object key into the filer directory and entry name
+func (s3a *S3ApiServer) objectDirAndName(bucket, object string) (dir, name string) {
+	fullPath := util.FullPath(fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, object))
+	return fullPath.DirAndName()
+}
+
 func objectKey(key *string) *string {
 	if strings.HasPrefix(*key, "/") {
 		t := (*key)[1:]
@@ -79,3 +195,18 @@ func objectKey(key *string) *string {
 	}
 	return key
 }
+
+// filerWriteErrorCode maps the errors of the filer on creating the objects, which may come back as text
+func filerWriteErrorCode(err string) ErrorCode {
+	switch {
+	case strings.Contains(err, filer_pb.ErrQuotaExceeded.Error()):
+		return ErrQuotaExceeded
+	case strings.Contains(err, filer_pb.ErrNameTooLong.Error()):
+		return ErrKeyTooLong
+	case strings.Contains(err, filer_pb.ErrInvalidName.Error()):
+		return ErrInvalidObjectName
+	case strings.Contains(err, filer_pb.ErrPreconditionFailed.Error()):
+		return ErrPreconditionFailed
+	}
+	return ErrInternalError
+}
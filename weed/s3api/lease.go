@@ -0,0 +1,116 @@
+package s3api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// A lease elects one s3 gateway for a background task, e.g. the multipart upload cleaner.
+// Each lease is a dedicated entry of the leases folder, with "owner,expiry" as the content and a random revision
+// as the etag. The lease is taken or renewed by overwriting the entry only if the revision is unchanged since read,
+// so two gateways never take it at the same time.
+
+const s3LeasesDir = filer2.SystemDir + "/s3/leases"
+
+// acquireLease takes or renews the lease of the name for the owner, returns false if another owner holds it
+func (s3a *S3ApiServer) acquireLease(name, owner string, lease time.Duration) bool {
+
+	entry, err := s3a.getEntry(s3LeasesDir, name)
+	if err != nil {
+		glog.V(1).Infof("lookup lease %s: %v", name, err)
+		return false
+	}
+
+	now := time.Now()
+	ifMatch := ""
+	if entry != nil {
+		if leaseOwner, expiresAt := parseEntryLock(entry.Content); leaseOwner != owner && now.Before(expiresAt) {
+			glog.V(4).Infof("lease %s held by %s until %v", name, leaseOwner, expiresAt)
+			return false
+		}
+		ifMatch = filer2.ETag(entry)
+	}
+
+	err = s3a.createEntryIf(s3LeasesDir, &filer_pb.Entry{
+		Name: name,
+		Attributes: &filer_pb.FuseAttributes{
+			Crtime:   now.Unix(),
+			Mtime:    now.Unix(),
+			FileMode: uint32(0644),
+		},
+		Extended: map[string][]byte{
+			filer2.ETagKey: []byte(uuid.New().String()),
+		},
+		Content: []byte(fmt.Sprintf("%s,%d", owner, now.Add(lease).Unix())),
+	}, ifMatch)
+	if err == filer_pb.ErrPreconditionFailed {
+		glog.V(4).Infof("lease %s taken by another gateway", name)
+		return false
+	}
+	if err != nil {
+		glog.V(0).Infof("update lease %s: %v", name, err)
+		return false
+	}
+	return true
+}
+
+// acquireEntryLock takes or renews the lease saved as "owner,expiry" in the extended attribute of the entry
+func (s3a *S3ApiServer) acquireEntryLock(dir, name, lockKey, owner string, lease time.Duration) bool {
+
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil || entry == nil {
+		glog.V(1).Infof("lookup %s/%s: %v", dir, name, err)
+		return false
+	}
+
+	if lockOwner, expiresAt := parseEntryLock(entry.Extended[lockKey]); lockOwner != owner && time.Now().Before(expiresAt) {
+		glog.V(4).Infof("%s lock held by %s until %v", lockKey, lockOwner, expiresAt)
+		return false
+	}
+
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	entry.Extended[lockKey] = []byte(fmt.Sprintf("%s,%d", owner, time.Now().Add(lease).Unix()))
+	err = s3a.updateEntryIfUnchanged(dir, entry)
+	if err == filer_pb.ErrPreconditionFailed {
+		glog.V(4).Infof("%s lock taken by another gateway", lockKey)
+		return false
+	}
+	if err != nil {
+		glog.V(0).Infof("update %s lock: %v", lockKey, err)
+		return false
+	}
+	return true
+}
+
+// updateEntryIfUnchanged overwrites the entry with a new random revision as the etag, only if the entry is unchanged
+// since read, otherwise returns filer_pb.ErrPreconditionFailed
+func (s3a *S3ApiServer) updateEntryIfUnchanged(dir string, entry *filer_pb.Entry) error {
+	ifMatch := filer2.ETag(entry)
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	entry.Extended[filer2.ETagKey] = []byte(uuid.New().String())
+	return s3a.createEntryIf(dir, entry, ifMatch)
+}
+
+func parseEntryLock(lock []byte) (owner string, expiresAt time.Time) {
+	parts := strings.SplitN(string(lock), ",", 2)
+	if len(parts) != 2 {
+		return "", time.Time{}
+	}
+	expiresAtUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}
+	}
+	return parts[0], time.Unix(expiresAtUnix, 0)
+}
@@ -0,0 +1,48 @@
+package s3api
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestConcurrentLeases(t *testing.T) {
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets": {Name: "buckets", IsDirectory: true, Extended: map[string][]byte{"checkpoint": []byte("1")}},
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+
+	var lock sync.Mutex
+	holders := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			owner := fmt.Sprintf("gateway%d", i%2)
+			if s3a.acquireLease("cleaner", owner, time.Minute) {
+				lock.Lock()
+				holders[owner] = true
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if len(holders) != 1 {
+		t.Fatalf("lease held by %v", holders)
+	}
+	if string(entries["/buckets"].Extended["checkpoint"]) != "1" || len(entries["/buckets"].Extended) != 1 {
+		t.Errorf("buckets folder entry changed: %v", entries["/buckets"].Extended)
+	}
+
+	// an expired lease is taken over
+	entries[util.FullPath(s3LeasesDir+"/cleaner")].Content = []byte(fmt.Sprintf("gateway0,%d", time.Now().Add(-time.Second).Unix()))
+	if !s3a.acquireLease("cleaner", "gateway2", time.Minute) {
+		t.Errorf("take over the expired lease")
+	}
+}
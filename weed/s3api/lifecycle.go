@@ -0,0 +1,233 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/object-lifecycle-mgmt.html
+
+const (
+	LifecycleRuleEnabled  = "Enabled"
+	LifecycleRuleDisabled = "Disabled"
+
+	// object tags are kept as extended attributes on the object entry, "X-Amz-Tagging-<tag key>": "<tag value>"
+	AmzObjectTaggingPrefix = "X-Amz-Tagging-"
+
+	// extended attribute key on the bucket entry
+	bucketLifecycleConfigurationKey = "s3-lifecycle-configuration"
+
+	maxLifecycleRules = 1000
+)
+
+type LifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Xmlns   string          `xml:"xmlns,attr,omitempty"`
+	Rules   []LifecycleRule `xml:"Rule"`
+}
+
+type LifecycleRule struct {
+	ID                             string                          `xml:"ID,omitempty"`
+	Status                         string                          `xml:"Status"`
+	Prefix                         *string                         `xml:"Prefix,omitempty"`
+	Filter                         *LifecycleFilter                `xml:"Filter,omitempty"`
+	Expiration                     *LifecycleExpiration            `xml:"Expiration,omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+type LifecycleFilter struct {
+	Prefix *string               `xml:"Prefix,omitempty"`
+	Tag    *Tag                  `xml:"Tag,omitempty"`
+	And    *LifecycleAndOperator `xml:"And,omitempty"`
+}
+
+type LifecycleAndOperator struct {
+	Prefix string `xml:"Prefix,omitempty"`
+	Tags   []Tag  `xml:"Tag"`
+}
+
+type Tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+type LifecycleExpiration struct {
+	Days int        `xml:"Days,omitempty"`
+	Date *time.Time `xml:"Date,omitempty"`
+}
+
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
+func (config *LifecycleConfiguration) validate() ErrorCode {
+	if len(config.Rules) == 0 || len(config.Rules) > maxLifecycleRules {
+		return ErrMalformedXML
+	}
+	ids := make(map[string]bool)
+	for _, rule := range config.Rules {
+		if code := rule.validate(); code != ErrNone {
+			return code
+		}
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return ErrInvalidLifecycleConfiguration
+			}
+			ids[rule.ID] = true
+		}
+	}
+	return ErrNone
+}
+
+func (rule *LifecycleRule) validate() ErrorCode {
+	if rule.Status != LifecycleRuleEnabled && rule.Status != LifecycleRuleDisabled {
+		return ErrMalformedXML
+	}
+	if rule.Prefix != nil && rule.Filter != nil {
+		return ErrMalformedXML
+	}
+	if filter := rule.Filter; filter != nil {
+		conditions := 0
+		if filter.Prefix != nil {
+			conditions++
+		}
+		if filter.Tag != nil {
+			conditions++
+		}
+		if filter.And != nil {
+			conditions++
+		}
+		if conditions > 1 {
+			return ErrMalformedXML
+		}
+	}
+	if rule.Expiration == nil && rule.AbortIncompleteMultipartUpload == nil {
+		return ErrInvalidLifecycleConfiguration
+	}
+	if expiration := rule.Expiration; expiration != nil {
+		if (expiration.Days > 0) == (expiration.Date != nil) || expiration.Days < 0 {
+			return ErrInvalidLifecycleConfiguration
+		}
+	}
+	if abort := rule.AbortIncompleteMultipartUpload; abort != nil {
+		if abort.DaysAfterInitiation <= 0 || len(rule.tags()) > 0 {
+			return ErrInvalidLifecycleConfiguration
+		}
+	}
+	return ErrNone
+}
+
+func (rule *LifecycleRule) prefix() string {
+	if rule.Prefix != nil {
+		return *rule.Prefix
+	}
+	if rule.Filter == nil {
+		return ""
+	}
+	if rule.Filter.Prefix != nil {
+		return *rule.Filter.Prefix
+	}
+	if rule.Filter.And != nil {
+		return rule.Filter.And.Prefix
+	}
+	return ""
+}
+
+func (rule *LifecycleRule) tags() []Tag {
+	if rule.Filter == nil {
+		return nil
+	}
+	if rule.Filter.Tag != nil {
+		return []Tag{*rule.Filter.Tag}
+	}
+	if rule.Filter.And != nil {
+		return rule.Filter.And.Tags
+	}
+	return nil
+}
+
+// matches tells whether the rule applies to the object key, without the leading "/", and its extended attributes
+func (rule *LifecycleRule) matches(key string, extended map[string][]byte) bool {
+	if rule.Status != LifecycleRuleEnabled || !strings.HasPrefix(key, rule.prefix()) {
+		return false
+	}
+	for _, tag := range rule.tags() {
+		if value, found := extended[AmzObjectTaggingPrefix+tag.Key]; !found || string(value) != tag.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func (rule *LifecycleRule) isExpired(key string, extended map[string][]byte, mtime, now time.Time) bool {
+	if rule.Expiration == nil || !rule.matches(key, extended) {
+		return false
+	}
+	if rule.Expiration.Date != nil {
+		return !now.Before(*rule.Expiration.Date)
+	}
+	return !now.Before(mtime.AddDate(0, 0, rule.Expiration.Days))
+}
+
+func (rule *LifecycleRule) isUploadAborted(key string, initiated, now time.Time) bool {
+	if rule.AbortIncompleteMultipartUpload == nil || !rule.matches(key, nil) {
+		return false
+	}
+	return !now.Before(initiated.AddDate(0, 0, rule.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+}
+
+func (config *LifecycleConfiguration) isExpired(key string, extended map[string][]byte, mtime, now time.Time) bool {
+	for i := range config.Rules {
+		if config.Rules[i].isExpired(key, extended, mtime, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (config *LifecycleConfiguration) isUploadAborted(key string, initiated, now time.Time) bool {
+	for i := range config.Rules {
+		if config.Rules[i].isUploadAborted(key, initiated, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s3a *S3ApiServer) getBucketLifecycleConfiguration(bucket string) (config *LifecycleConfiguration, code ErrorCode) {
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		return nil, ErrInternalError
+	}
+	if entry == nil {
+		return nil, ErrNoSuchBucket
+	}
+
+	config, err = loadLifecycleConfiguration(entry.Extended)
+	if err != nil {
+		glog.Errorf("bucket %s has invalid lifecycle configuration: %v", bucket, err)
+		return nil, ErrInternalError
+	}
+
+	return config, ErrNone
+}
+
+func loadLifecycleConfiguration(extended map[string][]byte) (config *LifecycleConfiguration, err error) {
+
+	data, found := extended[bucketLifecycleConfigurationKey]
+	if !found {
+		return nil, nil
+	}
+
+	config = &LifecycleConfiguration{}
+	if err = xml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,203 @@
+package s3api
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/stats"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+const (
+	// extended attribute key on the buckets folder entry, holding the last bucket scanned in an unfinished run
+	lifecycleCheckpointKey = "s3-lifecycle-checkpoint"
+	// only one s3 gateway scans at a time, holding the lifecycle scanner lease
+	lifecycleScannerLeaseName = "lifecycle-scanner"
+)
+
+func (s3a *S3ApiServer) loopLifecycleScan() {
+
+	owner := uuid.New().String()
+	lease := 2 * s3a.option.LifecycleScanInterval
+	if lease < time.Minute {
+		lease = time.Minute
+	}
+
+	for {
+		if s3a.acquireLease(lifecycleScannerLeaseName, owner, lease) {
+			if err := s3a.scanLifecycle(owner, lease); err != nil {
+				glog.Errorf("lifecycle scan: %v", err)
+			}
+		}
+		time.Sleep(s3a.option.LifecycleScanInterval)
+	}
+}
+
+// lifecycleScan holds the state of one pass over all buckets
+type lifecycleScan struct {
+	now      time.Time
+	throttle <-chan time.Time
+	expired  int64
+}
+
+func (scan *lifecycleScan) wait() {
+	if scan.throttle != nil {
+		<-scan.throttle
+	}
+}
+
+// scanLifecycle runs one pass holding the lease taken by the owner, and stops if the lease is lost
+func (s3a *S3ApiServer) scanLifecycle(owner string, lease time.Duration) error {
+
+	scan := &lifecycleScan{now: time.Now()}
+	if s3a.option.LifecycleDeletesPerSecond > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(s3a.option.LifecycleDeletesPerSecond))
+		defer ticker.Stop()
+		scan.throttle = ticker.C
+	}
+
+	// resume after the last bucket finished before a restart
+	checkpoint, err := s3a.loadLifecycleCheckpoint()
+	if err != nil {
+		return err
+	}
+	if checkpoint != "" {
+		glog.V(1).Infof("lifecycle scan resumes after bucket %s", checkpoint)
+	}
+
+	startTime := time.Now()
+	leaseRenewed := startTime
+	for {
+		buckets, err := s3a.list(s3a.option.BucketsPath, "", checkpoint, false, 1024)
+		if err != nil {
+			return fmt.Errorf("list buckets: %v", err)
+		}
+		for _, bucket := range buckets {
+			if !bucket.IsDirectory {
+				continue
+			}
+			if time.Since(leaseRenewed) > lease/2 {
+				if !s3a.acquireLease(lifecycleScannerLeaseName, owner, lease) {
+					return fmt.Errorf("lost the lifecycle scanner lease")
+				}
+				leaseRenewed = time.Now()
+			}
+			config, err := loadLifecycleConfiguration(bucket.Extended)
+			if err != nil {
+				glog.Errorf("bucket %s has invalid lifecycle configuration: %v", bucket.Name, err)
+			} else if config != nil {
+				if err = s3a.scanBucketLifecycle(scan, bucket, config); err != nil {
+					return fmt.Errorf("bucket %s: %v", bucket.Name, err)
+				}
+			}
+			checkpoint = bucket.Name
+			if err = s3a.saveLifecycleCheckpoint(checkpoint); err != nil {
+				return err
+			}
+		}
+		if len(buckets) < 1024 {
+			break
+		}
+	}
+
+	glog.V(0).Infof("lifecycle scan expired %d objects in %v", scan.expired, time.Since(startTime))
+
+	return s3a.saveLifecycleCheckpoint("")
+}
+
+func (s3a *S3ApiServer) scanBucketLifecycle(scan *lifecycleScan, bucketEntry *filer_pb.Entry, config *LifecycleConfiguration) error {
+
+	bucket := bucketEntry.Name
+	bucketDir := fmt.Sprintf("%s/%s", s3a.option.BucketsPath, bucket)
+	versioningStatus := string(bucketEntry.Extended[bucketVersioningKey])
+
+	expire := func(parentPath util.FullPath, entry *filer_pb.Entry) {
+		if entry.IsDirectory || entry.Attributes == nil {
+			return
+		}
+		key := strings.TrimPrefix(string(parentPath.Child(entry.Name)), bucketDir+"/")
+		mtime := time.Unix(entry.Attributes.Mtime, 0)
+		if !config.isExpired(key, entry.Extended, mtime, scan.now) {
+			return
+		}
+		scan.wait()
+		if versioningStatus != "" {
+			if _, _, code := s3a.deleteVersionedObject(bucket, "/"+key, "", versioningStatus, false); code != ErrNone {
+				glog.V(1).Infof("lifecycle skips %s/%s: %v", bucket, key, code)
+				return
+			}
+		} else {
+			if code := loadObjectLock(entry.Extended).checkRemoval(scan.now, false); code != ErrNone {
+				glog.V(2).Infof("lifecycle skips retained %s/%s", bucket, key)
+				return
+			}
+			if err := s3a.rm(string(parentPath), entry.Name, true, false); err != nil {
+				glog.Errorf("lifecycle delete %s/%s: %v", bucket, key, err)
+				return
+			}
+		}
+		glog.V(3).Infof("lifecycle expired %s/%s", bucket, key)
+		atomic.AddInt64(&scan.expired, 1)
+		stats.S3LifecycleCounter.WithLabelValues(bucket, "expired").Inc()
+	}
+
+	entries, err := s3a.list(bucketDir, "", "", false, 0)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDirectory {
+			expire(util.FullPath(bucketDir), entry)
+			continue
+		}
+		if entry.Name == ".uploads" || entry.Name == versionsFolder {
+			continue
+		}
+		if err = filer_pb.TraverseBfs(s3a, util.FullPath(bucketDir).Child(entry.Name), expire); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s3a *S3ApiServer) loadLifecycleCheckpoint() (string, error) {
+	dir, name := util.FullPath(s3a.option.BucketsPath).DirAndName()
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil {
+		return "", fmt.Errorf("lookup %s: %v", s3a.option.BucketsPath, err)
+	}
+	if entry == nil {
+		return "", nil
+	}
+	return string(entry.Extended[lifecycleCheckpointKey]), nil
+}
+
+func (s3a *S3ApiServer) saveLifecycleCheckpoint(checkpoint string) error {
+	dir, name := util.FullPath(s3a.option.BucketsPath).DirAndName()
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil {
+		return fmt.Errorf("lookup %s: %v", s3a.option.BucketsPath, err)
+	}
+	if entry == nil {
+		return nil
+	}
+	if checkpoint == "" {
+		if _, found := entry.Extended[lifecycleCheckpointKey]; !found {
+			return nil
+		}
+		delete(entry.Extended, lifecycleCheckpointKey)
+	} else {
+		if entry.Extended == nil {
+			entry.Extended = make(map[string][]byte)
+		}
+		entry.Extended[lifecycleCheckpointKey] = []byte(checkpoint)
+	}
+	return s3a.updateEntry(dir, entry)
+}
@@ -0,0 +1,123 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestLifecycleConfigurationValidate(t *testing.T) {
+
+	tests := []struct {
+		config   string
+		expected ErrorCode
+	}{
+		{`<LifecycleConfiguration><Rule><ID>logs</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>30</Days></Expiration></Rule></LifecycleConfiguration>`, ErrNone},
+		{`<LifecycleConfiguration><Rule><Prefix></Prefix><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`, ErrNone},
+		{`<LifecycleConfiguration><Rule><Filter><Prefix>a</Prefix><Tag><Key>k</Key><Value>v</Value></Tag></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrMalformedXML},
+		{`<LifecycleConfiguration><Rule><Status>Off</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`, ErrMalformedXML},
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status></Rule></LifecycleConfiguration>`, ErrInvalidLifecycleConfiguration},
+		{`<LifecycleConfiguration><Rule><Status>Enabled</Status><Expiration><Days>1</Days><Date>2020-01-01T00:00:00Z</Date></Expiration></Rule></LifecycleConfiguration>`, ErrInvalidLifecycleConfiguration},
+		{`<LifecycleConfiguration><Rule><Filter><Tag><Key>k</Key><Value>v</Value></Tag></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>1</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`, ErrInvalidLifecycleConfiguration},
+		{`<LifecycleConfiguration><Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule><Rule><ID>a</ID><Status>Enabled</Status><Expiration><Days>2</Days></Expiration></Rule></LifecycleConfiguration>`, ErrInvalidLifecycleConfiguration},
+		{`<LifecycleConfiguration></LifecycleConfiguration>`, ErrMalformedXML},
+	}
+
+	for i, test := range tests {
+		config := &LifecycleConfiguration{}
+		if err := xml.Unmarshal([]byte(test.config), config); err != nil {
+			t.Fatalf("case %d: unmarshal: %v", i, err)
+		}
+		if code := config.validate(); code != test.expected {
+			t.Errorf("case %d: validate got %v, expecting %v", i, code, test.expected)
+		}
+	}
+
+}
+
+func TestLifecycleRuleExpiration(t *testing.T) {
+
+	config := &LifecycleConfiguration{}
+	err := xml.Unmarshal([]byte(`<LifecycleConfiguration>
+<Rule><Filter><And><Prefix>logs/</Prefix><Tag><Key>temp</Key><Value>true</Value></Tag></And></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>
+<Rule><Filter><Prefix>archive/</Prefix></Filter><Status>Enabled</Status><Expiration><Date>2020-06-01T00:00:00Z</Date></Expiration></Rule>
+<Rule><Filter><Prefix>disabled/</Prefix></Filter><Status>Disabled</Status><Expiration><Days>1</Days></Expiration></Rule>
+<Rule><Filter><Prefix>uploads/</Prefix></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>2</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule>
+</LifecycleConfiguration>`), config)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	now := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -3)
+	tagged := map[string][]byte{AmzObjectTaggingPrefix + "temp": []byte("true")}
+
+	tests := []struct {
+		key      string
+		extended map[string][]byte
+		mtime    time.Time
+		expected bool
+	}{
+		{"logs/a.log", tagged, old, true},
+		{"logs/a.log", tagged, now, false},
+		{"logs/a.log", nil, old, false},
+		{"other/a.log", tagged, old, false},
+		{"archive/a", nil, now, true},
+		{"disabled/a", nil, old, false},
+		{"uploads/a", nil, old, false},
+	}
+	for _, test := range tests {
+		if expired := config.isExpired(test.key, test.extended, test.mtime, now); expired != test.expected {
+			t.Errorf("%s modified at %v: expired %v, expecting %v", test.key, test.mtime, expired, test.expected)
+		}
+	}
+
+	if !config.isUploadAborted("uploads/a", old, now) {
+		t.Errorf("stale upload should be aborted")
+	}
+	if config.isUploadAborted("uploads/a", now.AddDate(0, 0, -1), now) {
+		t.Errorf("recent upload should not be aborted")
+	}
+	if config.isUploadAborted("logs/a", old, now) {
+		t.Errorf("upload without abort rule should not be aborted")
+	}
+
+}
+
+func TestScanLifecycleHoldsLease(t *testing.T) {
+	lifecycle, _ := xml.Marshal(&LifecycleConfiguration{Rules: []LifecycleRule{{
+		Status:     "Enabled",
+		Filter:     &LifecycleFilter{},
+		Expiration: &LifecycleExpiration{Days: 1},
+	}}})
+	old := time.Now().AddDate(0, 0, -2)
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets":     {Name: "buckets", IsDirectory: true},
+		"/buckets/a":   {IsDirectory: true, Extended: map[string][]byte{bucketLifecycleConfigurationKey: lifecycle}},
+		"/buckets/a/x": {Attributes: &filer_pb.FuseAttributes{Mtime: old.Unix()}},
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+
+	if !s3a.acquireLease(lifecycleScannerLeaseName, "gateway1", time.Minute) {
+		t.Fatalf("acquire the free lease")
+	}
+
+	// a gateway without the lease stops before expiring any object
+	if err := s3a.scanLifecycle("gateway2", 0); err == nil {
+		t.Errorf("scan without the lease")
+	}
+	if _, found := entries["/buckets/a/x"]; !found {
+		t.Fatalf("object expired by the gateway without the lease")
+	}
+
+	if err := s3a.scanLifecycle("gateway1", time.Minute); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if _, found := entries["/buckets/a/x"]; found {
+		t.Errorf("object is not expired")
+	}
+}
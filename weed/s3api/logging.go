@@ -0,0 +1,514 @@
+package s3api
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/stats"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/ServerLogs.html
+// The access log records are buffered in memory, and periodically written into the target bucket
+// as the objects "<target prefix>YYYY-mm-DD-HH-MM-SS-<unique string>".
+
+const (
+	// extended attribute key on the bucket entry
+	bucketLoggingStatusKey = "s3-logging-status"
+
+	bucketLoggingCacheTTL = 5 * time.Second
+
+	accessLogQueueSize     = 10000
+	accessLogFlushInterval = time.Minute
+	accessLogMaxBufferSize = 4 * 1024 * 1024
+)
+
+type BucketLoggingConfiguration struct {
+	XMLName        xml.Name       `xml:"BucketLoggingStatus"`
+	Xmlns          string         `xml:"xmlns,attr,omitempty"`
+	LoggingEnabled *LoggingTarget `xml:"LoggingEnabled,omitempty"`
+}
+
+type LoggingTarget struct {
+	TargetBucket string `xml:"TargetBucket"`
+	TargetPrefix string `xml:"TargetPrefix"`
+}
+
+func loadBucketLoggingConfiguration(extended map[string][]byte) (*BucketLoggingConfiguration, error) {
+	data, found := extended[bucketLoggingStatusKey]
+	if !found {
+		return nil, nil
+	}
+	config := &BucketLoggingConfiguration{}
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// getBucketLoggingTarget returns nil if the access logging of the bucket is not enabled
+func (s3a *S3ApiServer) getBucketLoggingTarget(bucket string) (*LoggingTarget, error) {
+
+	if item := s3a.loggingCache.Get(bucket); item != nil && !item.Expired() {
+		return item.Value().(*LoggingTarget), nil
+	}
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		return nil, err
+	}
+	var target *LoggingTarget
+	if entry != nil {
+		config, err := loadBucketLoggingConfiguration(entry.Extended)
+		if err != nil {
+			return nil, err
+		}
+		if config != nil {
+			target = config.LoggingEnabled
+		}
+	}
+
+	s3a.loggingCache.Set(bucket, target, bucketLoggingCacheTTL)
+	return target, nil
+}
+
+// accessLogWriter records what is sent back for the access log
+type accessLogWriter struct {
+	http.ResponseWriter
+	status    int
+	bytesSent int64
+	firstByte time.Time
+	errorCode string
+	requester *requester
+}
+
+func (w *accessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		w.firstByte = time.Now()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.bytesSent += int64(n)
+	return n, err
+}
+
+func (w *accessLogWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// setAccessLogRequester tells the access log who sent the request, once authenticated
+func setAccessLogRequester(w http.ResponseWriter, requester *requester) {
+	if writer, ok := w.(*accessLogWriter); ok {
+		writer.requester = requester
+	}
+}
+
+func setAccessLogErrorCode(w http.ResponseWriter, code string) {
+	if writer, ok := w.(*accessLogWriter); ok {
+		writer.errorCode = code
+	}
+}
+
+// accessLogRecord is one line of the access log, in the fields order of the AWS log format
+type accessLogRecord struct {
+	target         LoggingTarget
+	bucket         string
+	time           time.Time
+	remoteIP       string
+	requester      string
+	requestId      string
+	operation      string
+	key            string
+	requestURI     string
+	status         int
+	errorCode      string
+	bytesSent      int64
+	objectSize     int64
+	totalTime      time.Duration
+	turnAroundTime time.Duration
+	referer        string
+	userAgent      string
+	versionId      string
+	signature      string
+	cipherSuite    string
+	authType       string
+	host           string
+	tlsVersion     string
+}
+
+func newAccessLogRecord(target LoggingTarget, bucket, object string, r *http.Request, w *accessLogWriter, start time.Time) *accessLogRecord {
+	now := time.Now()
+	record := &accessLogRecord{
+		target:     target,
+		bucket:     bucket,
+		time:       start,
+		remoteIP:   r.RemoteAddr,
+		requestId:  w.Header().Get("x-amz-request-id"),
+		operation:  accessLogOperation(r, object),
+		key:        strings.TrimPrefix(object, "/"),
+		requestURI: r.Method + " " + r.RequestURI + " " + r.Proto,
+		status:     w.status,
+		errorCode:  w.errorCode,
+		bytesSent:  w.bytesSent,
+		objectSize: -1,
+		totalTime:  now.Sub(start),
+		referer:    r.Referer(),
+		userAgent:  r.UserAgent(),
+		versionId:  w.Header().Get("x-amz-version-id"),
+		host:       r.Host,
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		record.remoteIP = host
+	}
+	if w.requester != nil && w.requester.identity != nil {
+		record.requester = w.requester.identity.Name
+	}
+	if record.status == 0 {
+		record.status = http.StatusOK
+	}
+	if object != "" && strings.HasSuffix(record.operation, ".OBJECT") && record.status < http.StatusMultipleChoices {
+		record.objectSize = accessLogObjectSize(r, w.Header())
+	}
+	if !w.firstByte.IsZero() {
+		record.turnAroundTime = w.firstByte.Sub(start)
+	}
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypeStreamingSigned:
+		record.signature, record.authType = "SigV4", "AuthHeader"
+	case authTypePresigned:
+		record.signature, record.authType = "SigV4", "QueryString"
+	case authTypeSignedV2:
+		record.signature, record.authType = "SigV2", "AuthHeader"
+	case authTypePresignedV2:
+		record.signature, record.authType = "SigV2", "QueryString"
+	}
+	if r.TLS != nil {
+		record.cipherSuite = tlsCipherSuiteName(r.TLS.CipherSuite)
+		record.tlsVersion = tlsVersionName(r.TLS.Version)
+	}
+	return record
+}
+
+// the resource types of the operations, by the sub-resource in the query, e.g. REST.GET.VERSIONING
+var accessLogSubResources = []struct {
+	param      string
+	bucketType string
+	objectType string
+}{
+	{"uploadId", "", "UPLOAD"},
+	{"uploads", "UPLOADS", "UPLOADS"},
+	{"tagging", "TAGGING", "OBJECT_TAGGING"},
+	{"retention", "", "RETENTION"},
+	{"legal-hold", "", "LEGAL_HOLD"},
+	{"select", "", "SELECT"},
+	{"acl", "ACL", "ACL"},
+	{"versioning", "VERSIONING", ""},
+	{"versions", "BUCKETVERSIONS", ""},
+	{"object-lock", "OBJECT_LOCK_CONFIGURATION", ""},
+	{"lifecycle", "LIFECYCLE", ""},
+	{"encryption", "ENCRYPTION", ""},
+	{"notification", "NOTIFICATION", ""},
+	{"policy", "BUCKETPOLICY", ""},
+	{"cors", "CORS", ""},
+	{"logging", "LOGGING_STATUS", ""},
+	{"website", "WEBSITE", ""},
+	{"requestPayment", "REQUEST_PAYMENT", ""},
+	{"replication", "REPLICATION", ""},
+	{"location", "LOCATION", ""},
+	{"delete", "MULTI_OBJECT_DELETE", ""},
+}
+
+func accessLogOperation(r *http.Request, object string) string {
+
+	if r.Method == http.MethodOptions {
+		return "REST.OPTIONS.PREFLIGHT"
+	}
+
+	method := r.Method
+	if method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "" {
+		method = "COPY"
+	}
+
+	query := r.URL.Query()
+	for _, subResource := range accessLogSubResources {
+		if _, found := query[subResource.param]; !found {
+			continue
+		}
+		resourceType := subResource.bucketType
+		if object != "" {
+			resourceType = subResource.objectType
+		}
+		if resourceType == "" {
+			continue
+		}
+		if resourceType == "UPLOAD" && (method == http.MethodPut || method == "COPY") {
+			resourceType = "PART"
+		}
+		return "REST." + method + "." + resourceType
+	}
+
+	if object != "" || method == http.MethodPost {
+		return "REST." + method + ".OBJECT"
+	}
+	return "REST." + method + ".BUCKET"
+}
+
+// accessLogObjectSize is the total size of the object read or written, or -1 if unknown
+func accessLogObjectSize(r *http.Request, header http.Header) int64 {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if contentRange := header.Get("Content-Range"); contentRange != "" {
+			if size, err := strconv.ParseInt(contentRange[strings.LastIndex(contentRange, "/")+1:], 10, 64); err == nil {
+				return size
+			}
+		}
+		if size, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+			return size
+		}
+	case http.MethodPut:
+		return declaredObjectSize(r)
+	}
+	return -1
+}
+
+var tlsCipherSuiteNames = map[uint16]string{
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA:            "AES128-SHA",
+	tls.TLS_RSA_WITH_AES_256_CBC_SHA:            "AES256-SHA",
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256:         "AES128-GCM-SHA256",
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384:         "AES256-GCM-SHA384",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA:      "ECDHE-RSA-AES128-SHA",
+	tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA:      "ECDHE-RSA-AES256-SHA",
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256:   "ECDHE-RSA-AES128-GCM-SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:   "ECDHE-RSA-AES256-GCM-SHA384",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256: "ECDHE-ECDSA-AES128-GCM-SHA256",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384: "ECDHE-ECDSA-AES256-GCM-SHA384",
+	tls.TLS_AES_128_GCM_SHA256:                  "TLS_AES_128_GCM_SHA256",
+	tls.TLS_AES_256_GCM_SHA384:                  "TLS_AES_256_GCM_SHA384",
+	tls.TLS_CHACHA20_POLY1305_SHA256:            "TLS_CHACHA20_POLY1305_SHA256",
+}
+
+func tlsCipherSuiteName(cipherSuite uint16) string {
+	if name, found := tlsCipherSuiteNames[cipherSuite]; found {
+		return name
+	}
+	return fmt.Sprintf("0x%04X", cipherSuite)
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLSv1"
+	case tls.VersionTLS11:
+		return "TLSv1.1"
+	case tls.VersionTLS12:
+		return "TLSv1.2"
+	case tls.VersionTLS13:
+		return "TLSv1.3"
+	}
+	return ""
+}
+
+func accessLogField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func accessLogNumber(value int64) string {
+	if value <= 0 {
+		return "-"
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+func accessLogQuoted(value string) string {
+	if value == "" {
+		return "\"-\""
+	}
+	return strconv.Quote(value)
+}
+
+// format writes the record as one line of the AWS server access log format, ending with a newline
+func (record *accessLogRecord) format() string {
+	key := "-"
+	if record.key != "" {
+		key = strings.Replace(url.PathEscape(record.key), "%2F", "/", -1)
+	}
+	objectSize := "-"
+	if record.objectSize >= 0 {
+		objectSize = strconv.FormatInt(record.objectSize, 10)
+	}
+	turnAroundTime := "-"
+	if record.turnAroundTime > 0 {
+		turnAroundTime = strconv.FormatInt(int64(record.turnAroundTime/time.Millisecond), 10)
+	}
+	return strings.Join([]string{
+		"-", // the bucket owner
+		record.bucket,
+		record.time.UTC().Format("[02/Jan/2006:15:04:05 -0700]"),
+		record.remoteIP,
+		accessLogField(record.requester),
+		accessLogField(record.requestId),
+		record.operation,
+		key,
+		accessLogQuoted(record.requestURI),
+		strconv.Itoa(record.status),
+		accessLogField(record.errorCode),
+		accessLogNumber(record.bytesSent),
+		objectSize,
+		strconv.FormatInt(int64(record.totalTime/time.Millisecond), 10),
+		turnAroundTime,
+		accessLogQuoted(record.referer),
+		accessLogQuoted(record.userAgent),
+		accessLogField(record.versionId),
+		"-", // the host id
+		accessLogField(record.signature),
+		accessLogField(record.cipherSuite),
+		accessLogField(record.authType),
+		accessLogField(record.host),
+		accessLogField(record.tlsVersion),
+	}, " ") + "\n"
+}
+
+// accessLog records the requests of the buckets with logging enabled
+func (s3a *S3ApiServer) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		bucket := vars["bucket"]
+		if bucket == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		target, err := s3a.getBucketLoggingTarget(bucket)
+		if err != nil {
+			glog.Errorf("bucket %s logging status: %v", bucket, err)
+		}
+		if target == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		// the request metrics may already record the response
+		writer, ok := w.(*accessLogWriter)
+		if !ok {
+			writer = &accessLogWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(writer, r)
+
+		var object string
+		if _, found := vars["object"]; found {
+			object = getObject(vars)
+		}
+		record := newAccessLogRecord(*target, bucket, object, r, writer, start)
+
+		// never block the request, the records are dropped if the queue is full
+		select {
+		case s3a.accessLogs <- record:
+		default:
+			stats.S3AccessLogCounter.WithLabelValues(bucket, "dropped").Inc()
+		}
+	})
+}
+
+// accessLogBuffer collects the log lines of one target
+type accessLogBuffer struct {
+	data    bytes.Buffer
+	buckets map[string]int
+}
+
+func (s3a *S3ApiServer) loopAccessLog() {
+
+	ticker := time.NewTicker(accessLogFlushInterval)
+	defer ticker.Stop()
+
+	buffers := make(map[LoggingTarget]*accessLogBuffer)
+	for {
+		select {
+		case record := <-s3a.accessLogs:
+			buffer, found := buffers[record.target]
+			if !found {
+				buffer = &accessLogBuffer{buckets: make(map[string]int)}
+				buffers[record.target] = buffer
+			}
+			buffer.data.WriteString(record.format())
+			buffer.buckets[record.bucket]++
+			if buffer.data.Len() >= accessLogMaxBufferSize {
+				s3a.flushAccessLog(record.target, buffer)
+				delete(buffers, record.target)
+			}
+		case <-ticker.C:
+			for target, buffer := range buffers {
+				s3a.flushAccessLog(target, buffer)
+			}
+			buffers = make(map[LoggingTarget]*accessLogBuffer)
+		}
+	}
+}
+
+func (s3a *S3ApiServer) flushAccessLog(target LoggingTarget, buffer *accessLogBuffer) {
+
+	counterType := "written"
+	if err := s3a.writeAccessLogObject(target, buffer.data.Bytes()); err != nil {
+		glog.V(0).Infof("write access log into bucket %s: %v", target.TargetBucket, err)
+		counterType = "dropped"
+	}
+	for bucket, count := range buffer.buckets {
+		stats.S3AccessLogCounter.WithLabelValues(bucket, counterType).Add(float64(count))
+	}
+}
+
+func (s3a *S3ApiServer) writeAccessLogObject(target LoggingTarget, data []byte) error {
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%s%s-%016X", target.TargetPrefix, now.Format("2006-01-02-15-04-05"), rand.Uint64())
+	dir, name := s3a.objectDirAndName(target.TargetBucket, "/"+key)
+
+	chunk, err := s3a.uploadChunk(target.TargetBucket, dir, storageTier{}, data, false, 0)
+	if err != nil {
+		return err
+	}
+
+	md5sum := md5.Sum(data)
+	if err = s3a.createEntry(dir, &filer_pb.Entry{
+		Name: name,
+		Attributes: &filer_pb.FuseAttributes{
+			Mtime:    now.Unix(),
+			Crtime:   now.Unix(),
+			FileMode: uint32(0660),
+			Uid:      filer_pb.OS_UID,
+			Gid:      filer_pb.OS_GID,
+			Mime:     "text/plain",
+			FileSize: uint64(len(data)),
+			Md5:      md5sum[:],
+		},
+		Chunks: []*filer_pb.FileChunk{chunk},
+	}); err != nil {
+		s3a.deleteChunks([]*filer_pb.FileChunk{chunk})
+		return err
+	}
+	return nil
+}
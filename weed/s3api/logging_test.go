@@ -0,0 +1,80 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAccessLogOperation(t *testing.T) {
+	tests := []struct {
+		method   string
+		target   string
+		object   string
+		header   string
+		expected string
+	}{
+		{"GET", "/bucket1/a.txt", "/a.txt", "", "REST.GET.OBJECT"},
+		{"GET", "/bucket1/a.txt?versionId=1", "/a.txt", "", "REST.GET.OBJECT"},
+		{"HEAD", "/bucket1/a.txt", "/a.txt", "", "REST.HEAD.OBJECT"},
+		{"PUT", "/bucket1/a.txt", "/a.txt", "/bucket2/b.txt", "REST.COPY.OBJECT"},
+		{"PUT", "/bucket1/a.txt?partNumber=1&uploadId=x", "/a.txt", "", "REST.PUT.PART"},
+		{"PUT", "/bucket1/a.txt?partNumber=1&uploadId=x", "/a.txt", "/bucket2/b.txt", "REST.COPY.PART"},
+		{"POST", "/bucket1/a.txt?uploads", "/a.txt", "", "REST.POST.UPLOADS"},
+		{"POST", "/bucket1/a.txt?uploadId=x", "/a.txt", "", "REST.POST.UPLOAD"},
+		{"GET", "/bucket1/a.txt?tagging", "/a.txt", "", "REST.GET.OBJECT_TAGGING"},
+		{"GET", "/bucket1?versioning", "", "", "REST.GET.VERSIONING"},
+		{"GET", "/bucket1?list-type=2&prefix=a", "", "", "REST.GET.BUCKET"},
+		{"PUT", "/bucket1?logging", "", "", "REST.PUT.LOGGING_STATUS"},
+		{"POST", "/bucket1?delete", "", "", "REST.POST.MULTI_OBJECT_DELETE"},
+		{"POST", "/bucket1", "", "", "REST.POST.OBJECT"},
+		{"OPTIONS", "/bucket1/a.txt", "/a.txt", "", "REST.OPTIONS.PREFLIGHT"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, test.target, nil)
+		if test.header != "" {
+			r.Header.Set("X-Amz-Copy-Source", test.header)
+		}
+		if operation := accessLogOperation(r, test.object); operation != test.expected {
+			t.Errorf("%s %s: %s, expecting %s", test.method, test.target, operation, test.expected)
+		}
+	}
+}
+
+func TestAccessLogRecordFormat(t *testing.T) {
+
+	r := httptest.NewRequest("GET", "/bucket1/photos/my%20cat.jpg?versionId=v1", nil)
+	r.RemoteAddr = "192.0.2.3:53412"
+	r.Header.Set("User-Agent", "aws-cli/1.16")
+	r.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=AKIA/20200101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=abc")
+
+	recorder := httptest.NewRecorder()
+	w := &accessLogWriter{ResponseWriter: recorder, requester: &requester{identity: &Identity{Name: "someone"}}}
+	w.Header().Set("x-amz-request-id", "3E57427F3EXAMPLE")
+	w.Header().Set("x-amz-version-id", "v1")
+	w.Header().Set("Content-Length", "11")
+	start := time.Date(2019, 2, 6, 0, 0, 38, 0, time.UTC)
+	w.Write([]byte("hello world"))
+
+	record := newAccessLogRecord(LoggingTarget{TargetBucket: "logs"}, "bucket1", "/photos/my cat.jpg", r, w, start)
+	record.totalTime, record.turnAroundTime = 12*time.Millisecond, 7*time.Millisecond
+
+	expected := `- bucket1 [06/Feb/2019:00:00:38 +0000] 192.0.2.3 someone 3E57427F3EXAMPLE REST.GET.OBJECT photos/my%20cat.jpg ` +
+		`"GET /bucket1/photos/my%20cat.jpg?versionId=v1 HTTP/1.1" 200 - 11 11 12 7 "-" "aws-cli/1.16" v1 - SigV4 - AuthHeader example.com -` + "\n"
+	if line := record.format(); line != expected {
+		t.Errorf("line\n%s\nexpecting\n%s", line, expected)
+	}
+}
+
+func TestAccessLogWriterErrorCode(t *testing.T) {
+
+	r := httptest.NewRequest("GET", "/bucket1/a.txt", nil)
+	w := &accessLogWriter{ResponseWriter: httptest.NewRecorder()}
+	writeErrorResponse(w, ErrNoSuchKey, r.URL)
+
+	record := newAccessLogRecord(LoggingTarget{}, "bucket1", "/a.txt", r, w, time.Now())
+	if record.status != http.StatusNotFound || record.errorCode != "NoSuchKey" || record.objectSize != -1 || record.requester != "" {
+		t.Errorf("record %+v", record)
+	}
+}
@@ -0,0 +1,173 @@
+package s3api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/stats"
+)
+
+// The incomplete multipart uploads are aborted by the cleaner, after the upload ttl of the s3 gateway,
+// or earlier by the AbortIncompleteMultipartUpload rules of the bucket lifecycle. Aborting an upload
+// deletes the chunks of its parts. Only one s3 gateway cleans at a time, holding the upload cleaner lease.
+
+const (
+	uploadCleanerLeaseName = "upload-cleaner"
+)
+
+// uploadCleanup holds the state of one pass over all buckets
+type uploadCleanup struct {
+	*lifecycleScan
+	owner          string
+	lease          time.Duration
+	lockRenewed    time.Time
+	aborted        int64
+	reclaimedBytes int64
+}
+
+func (s3a *S3ApiServer) loopMultipartUploadCleanup() {
+
+	owner := uuid.New().String()
+	lease := 2 * s3a.option.MultipartCleanupInterval
+	if lease < time.Minute {
+		lease = time.Minute
+	}
+
+	for range time.Tick(s3a.option.MultipartCleanupInterval) {
+		if !s3a.acquireUploadCleanerLock(owner, lease) {
+			continue
+		}
+		if err := s3a.cleanupMultipartUploads(owner, lease); err != nil {
+			glog.Errorf("multipart upload cleanup: %v", err)
+		}
+	}
+}
+
+func (s3a *S3ApiServer) cleanupMultipartUploads(owner string, lease time.Duration) error {
+
+	cleanup := &uploadCleanup{
+		lifecycleScan: &lifecycleScan{now: time.Now()},
+		owner:         owner,
+		lease:         lease,
+		lockRenewed:   time.Now(),
+	}
+	if s3a.option.LifecycleDeletesPerSecond > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(s3a.option.LifecycleDeletesPerSecond))
+		defer ticker.Stop()
+		cleanup.throttle = ticker.C
+	}
+
+	startTime := time.Now()
+	lastBucket := ""
+	for {
+		buckets, err := s3a.list(s3a.option.BucketsPath, "", lastBucket, false, 1024)
+		if err != nil {
+			return fmt.Errorf("list buckets: %v", err)
+		}
+		for _, bucket := range buckets {
+			lastBucket = bucket.Name
+			if !bucket.IsDirectory {
+				continue
+			}
+			config, err := loadLifecycleConfiguration(bucket.Extended)
+			if err != nil {
+				glog.Errorf("bucket %s has invalid lifecycle configuration: %v", bucket.Name, err)
+			}
+			if err = s3a.abortStaleUploads(cleanup, bucket.Name, config); err != nil {
+				return fmt.Errorf("bucket %s: %v", bucket.Name, err)
+			}
+		}
+		if len(buckets) < 1024 {
+			break
+		}
+	}
+
+	if cleanup.aborted > 0 {
+		glog.V(0).Infof("aborted %d multipart uploads, reclaimed %d bytes in %v", cleanup.aborted, cleanup.reclaimedBytes, time.Since(startTime))
+	}
+	return nil
+}
+
+// isUploadStale tells whether the upload is past the upload ttl, or aborted by the lifecycle rules
+func (s3a *S3ApiServer) isUploadStale(config *LifecycleConfiguration, key string, initiated, now time.Time) (stale, byLifecycle bool) {
+	if config != nil && config.isUploadAborted(key, initiated, now) {
+		return true, true
+	}
+	ttl := s3a.option.MultipartUploadTTL
+	return ttl > 0 && !now.Before(initiated.Add(ttl)), false
+}
+
+func (s3a *S3ApiServer) abortStaleUploads(cleanup *uploadCleanup, bucket string, config *LifecycleConfiguration) error {
+
+	uploadsFolder := s3a.genUploadsFolder(bucket)
+	uploads, err := s3a.list(uploadsFolder, "", "", false, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, upload := range uploads {
+		if !upload.IsDirectory || upload.Attributes == nil {
+			continue
+		}
+		key := strings.TrimPrefix(string(upload.Extended["key"]), "/")
+		stale, byLifecycle := s3a.isUploadStale(config, key, time.Unix(upload.Attributes.Crtime, 0), cleanup.now)
+		if !stale {
+			continue
+		}
+		if err = s3a.renewUploadCleanerLock(cleanup); err != nil {
+			return err
+		}
+		cleanup.wait()
+
+		size := s3a.uploadSize(uploadsFolder + "/" + upload.Name)
+		if err = s3a.rm(uploadsFolder, upload.Name, true, true); err != nil {
+			glog.Errorf("abort upload %s of %s/%s: %v", upload.Name, bucket, key, err)
+			continue
+		}
+		glog.V(3).Infof("aborted upload %s of %s/%s", upload.Name, bucket, key)
+		cleanup.aborted++
+		cleanup.reclaimedBytes += size
+		if byLifecycle {
+			stats.S3LifecycleCounter.WithLabelValues(bucket, "aborted").Inc()
+		}
+		stats.S3UploadCleanupCounter.WithLabelValues("uploads").Inc()
+		stats.S3UploadCleanupCounter.WithLabelValues("bytes").Add(float64(size))
+	}
+
+	return nil
+}
+
+// uploadSize adds up the sizes of the uploaded parts
+func (s3a *S3ApiServer) uploadSize(uploadDirectory string) (size int64) {
+	parts, err := s3a.list(uploadDirectory, "", "", false, 0)
+	if err != nil {
+		glog.V(1).Infof("list parts of %s: %v", uploadDirectory, err)
+		return 0
+	}
+	for _, part := range parts {
+		if !part.IsDirectory {
+			size += int64(filer2.FileSize(part))
+		}
+	}
+	return
+}
+
+func (s3a *S3ApiServer) renewUploadCleanerLock(cleanup *uploadCleanup) error {
+	if time.Since(cleanup.lockRenewed) <= cleanup.lease/2 {
+		return nil
+	}
+	if !s3a.acquireUploadCleanerLock(cleanup.owner, cleanup.lease) {
+		return fmt.Errorf("lost the upload cleaner lock")
+	}
+	cleanup.lockRenewed = time.Now()
+	return nil
+}
+
+func (s3a *S3ApiServer) acquireUploadCleanerLock(owner string, lease time.Duration) bool {
+	return s3a.acquireLease(uploadCleanerLeaseName, owner, lease)
+}
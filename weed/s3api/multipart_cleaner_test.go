@@ -0,0 +1,91 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func testUpload(key string, initiated time.Time) *filer_pb.Entry {
+	return &filer_pb.Entry{
+		IsDirectory: true,
+		Attributes:  &filer_pb.FuseAttributes{Crtime: initiated.Unix()},
+		Extended:    map[string][]byte{"key": []byte(key)},
+	}
+}
+
+func TestCleanupMultipartUploads(t *testing.T) {
+	prefix := "logs/"
+	lifecycle, _ := xml.Marshal(&LifecycleConfiguration{Rules: []LifecycleRule{{
+		Status:                         "Enabled",
+		Filter:                         &LifecycleFilter{Prefix: &prefix},
+		AbortIncompleteMultipartUpload: &AbortIncompleteMultipartUpload{DaysAfterInitiation: 1},
+	}}})
+	now := time.Now()
+	day := 24 * time.Hour
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets":   {Name: "buckets", IsDirectory: true},
+		"/buckets/a": {IsDirectory: true, Extended: map[string][]byte{bucketLifecycleConfigurationKey: lifecycle}},
+		"/buckets/b": {IsDirectory: true},
+		// aborted by the lifecycle rule
+		"/buckets/a/.uploads/u1": testUpload("logs/x", now.Add(-2*day)),
+		"/buckets/a/.uploads/u2": testUpload("data/x", now.Add(-2*day)),
+		// aborted by the upload ttl
+		"/buckets/b/.uploads/u3":           testUpload("data/x", now.Add(-4*day)),
+		"/buckets/b/.uploads/u3/0000.part": {Content: make([]byte, 10)},
+		"/buckets/b/.uploads/u3/0001.part": {Content: make([]byte, 5)},
+		"/buckets/b/.uploads/u4":           testUpload("data/y", now),
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+	s3a.option.MultipartUploadTTL = 3 * day
+
+	if !s3a.acquireUploadCleanerLock("gateway1", time.Minute) {
+		t.Fatalf("acquire the free lock")
+	}
+	if s3a.acquireUploadCleanerLock("gateway2", time.Minute) {
+		t.Fatalf("acquire the lock held by another gateway")
+	}
+	if !s3a.acquireUploadCleanerLock("gateway1", time.Minute) {
+		t.Fatalf("renew the lock")
+	}
+
+	if err := s3a.cleanupMultipartUploads("gateway1", time.Minute); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	for _, p := range []util.FullPath{"/buckets/a/.uploads/u1", "/buckets/b/.uploads/u3"} {
+		if _, found := entries[p]; found {
+			t.Errorf("upload %s is not aborted", p)
+		}
+	}
+	for _, p := range []util.FullPath{"/buckets/a/.uploads/u2", "/buckets/b/.uploads/u4"} {
+		if _, found := entries[p]; !found {
+			t.Errorf("upload %s is aborted", p)
+		}
+	}
+}
+
+func TestIsUploadStale(t *testing.T) {
+	s3a := &S3ApiServer{option: &S3ApiServerOption{}}
+	now := time.Now()
+	initiated := now.Add(-48 * time.Hour)
+
+	if stale, _ := s3a.isUploadStale(nil, "key", initiated, now); stale {
+		t.Errorf("stale without ttl and lifecycle")
+	}
+	s3a.option.MultipartUploadTTL = 24 * time.Hour
+	if stale, byLifecycle := s3a.isUploadStale(nil, "key", initiated, now); !stale || byLifecycle {
+		t.Errorf("past the ttl: %v %v", stale, byLifecycle)
+	}
+	s3a.option.MultipartUploadTTL = 72 * time.Hour
+	config := &LifecycleConfiguration{Rules: []LifecycleRule{{
+		Status:                         "Enabled",
+		AbortIncompleteMultipartUpload: &AbortIncompleteMultipartUpload{DaysAfterInitiation: 2},
+	}}}
+	if stale, byLifecycle := s3a.isUploadStale(config, "key", initiated, now); !stale || !byLifecycle {
+		t.Errorf("aborted by lifecycle: %v %v", stale, byLifecycle)
+	}
+}
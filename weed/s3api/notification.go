@@ -0,0 +1,368 @@
+package s3api
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/messaging/msgclient"
+	"github.com/chrislusf/seaweedfs/weed/pb/messaging_pb"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/NotificationHowTo.html
+// The events are published to the message broker, under the "s3" namespace.
+// The topic is the last segment of the topic or queue ARN, e.g. "uploads" for arn:aws:sns:us-east-1:123456789012:uploads
+
+const (
+	notificationNamespace = "s3"
+
+	// extended attribute key on the bucket entry
+	bucketNotificationConfigurationKey = "s3-notification-configuration"
+
+	notificationQueueSize = 1024
+
+	EventObjectCreatedPut                     = "ObjectCreated:Put"
+	EventObjectCreatedPost                    = "ObjectCreated:Post"
+	EventObjectCreatedCopy                    = "ObjectCreated:Copy"
+	EventObjectCreatedCompleteMultipartUpload = "ObjectCreated:CompleteMultipartUpload"
+	EventObjectRemovedDelete                  = "ObjectRemoved:Delete"
+	EventObjectRemovedDeleteMarkerCreated     = "ObjectRemoved:DeleteMarkerCreated"
+)
+
+var notificationEvents = map[string]bool{
+	"s3:ObjectCreated:*":                              true,
+	"s3:" + EventObjectCreatedPut:                     true,
+	"s3:" + EventObjectCreatedPost:                    true,
+	"s3:" + EventObjectCreatedCopy:                    true,
+	"s3:" + EventObjectCreatedCompleteMultipartUpload: true,
+	"s3:ObjectRemoved:*":                              true,
+	"s3:" + EventObjectRemovedDelete:                  true,
+	"s3:" + EventObjectRemovedDeleteMarkerCreated:     true,
+}
+
+type BucketNotificationConfiguration struct {
+	XMLName                     xml.Name                         `xml:"NotificationConfiguration"`
+	Xmlns                       string                           `xml:"xmlns,attr,omitempty"`
+	TopicConfigurations         []NotificationTopicConfiguration `xml:"TopicConfiguration,omitempty"`
+	QueueConfigurations         []NotificationQueueConfiguration `xml:"QueueConfiguration,omitempty"`
+	CloudFunctionConfigurations []struct {
+		Id string `xml:"Id"`
+	} `xml:"CloudFunctionConfiguration,omitempty"`
+}
+
+type NotificationTopicConfiguration struct {
+	Id     string              `xml:"Id,omitempty"`
+	Topic  string              `xml:"Topic"`
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type NotificationQueueConfiguration struct {
+	Id     string              `xml:"Id,omitempty"`
+	Queue  string              `xml:"Queue"`
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+type NotificationFilter struct {
+	S3Key struct {
+		FilterRules []NotificationFilterRule `xml:"FilterRule"`
+	} `xml:"S3Key"`
+}
+
+type NotificationFilterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+// notificationDestination is one topic or queue configuration
+type notificationDestination struct {
+	id     string
+	arn    string
+	events []string
+	filter *NotificationFilter
+}
+
+func (config *BucketNotificationConfiguration) destinations() (destinations []notificationDestination) {
+	for _, c := range config.TopicConfigurations {
+		destinations = append(destinations, notificationDestination{id: c.Id, arn: c.Topic, events: c.Events, filter: c.Filter})
+	}
+	for _, c := range config.QueueConfigurations {
+		destinations = append(destinations, notificationDestination{id: c.Id, arn: c.Queue, events: c.Events, filter: c.Filter})
+	}
+	return
+}
+
+func (config *BucketNotificationConfiguration) validate() ErrorCode {
+	if len(config.CloudFunctionConfigurations) > 0 {
+		return ErrNotImplemented
+	}
+	for _, destination := range config.destinations() {
+		if topic := destination.topic(); topic == "" || strings.Contains(topic, "/") {
+			return ErrInvalidRequest
+		}
+		if len(destination.events) == 0 {
+			return ErrMalformedXML
+		}
+		for _, event := range destination.events {
+			if !notificationEvents[event] {
+				return ErrInvalidRequest
+			}
+		}
+		if destination.filter != nil {
+			var prefixes, suffixes int
+			for _, rule := range destination.filter.S3Key.FilterRules {
+				switch strings.ToLower(rule.Name) {
+				case "prefix":
+					prefixes++
+				case "suffix":
+					suffixes++
+				default:
+					return ErrInvalidRequest
+				}
+			}
+			if prefixes > 1 || suffixes > 1 {
+				return ErrInvalidRequest
+			}
+		}
+	}
+	return ErrNone
+}
+
+// topic is the message broker topic of the destination
+func (destination notificationDestination) topic() string {
+	return destination.arn[strings.LastIndex(destination.arn, ":")+1:]
+}
+
+// matches checks the event name, e.g. ObjectCreated:Put, and the object key without the leading "/"
+func (destination notificationDestination) matches(eventName, key string) bool {
+	found := false
+	for _, event := range destination.events {
+		event = strings.TrimPrefix(event, "s3:")
+		if event == eventName || (strings.HasSuffix(event, ":*") && strings.HasPrefix(eventName, strings.TrimSuffix(event, "*"))) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	if destination.filter != nil {
+		for _, rule := range destination.filter.S3Key.FilterRules {
+			switch strings.ToLower(rule.Name) {
+			case "prefix":
+				if !strings.HasPrefix(key, rule.Value) {
+					return false
+				}
+			case "suffix":
+				if !strings.HasSuffix(key, rule.Value) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (s3a *S3ApiServer) getBucketNotificationConfiguration(bucket string) (config *BucketNotificationConfiguration, code ErrorCode) {
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		return nil, ErrInternalError
+	}
+	if entry == nil {
+		return nil, ErrNoSuchBucket
+	}
+
+	data, found := entry.Extended[bucketNotificationConfigurationKey]
+	if !found {
+		return nil, ErrNone
+	}
+	config = &BucketNotificationConfiguration{}
+	if err = xml.Unmarshal(data, config); err != nil {
+		glog.Errorf("bucket %s has invalid notification configuration: %v", bucket, err)
+		return nil, ErrInternalError
+	}
+
+	return config, ErrNone
+}
+
+// bucketEvent is what happened to one object, the object has the leading "/"
+type bucketEvent struct {
+	name      string
+	bucket    string
+	object    string
+	size      int64
+	etag      string
+	versionId string
+	sourceIP  string
+	eventTime time.Time
+	region    string
+}
+
+// S3 event message structure, https://docs.aws.amazon.com/AmazonS3/latest/dev/notification-content-structure.html
+type eventRecords struct {
+	Records []eventRecord `json:"Records"`
+}
+
+type eventRecord struct {
+	EventVersion      string            `json:"eventVersion"`
+	EventSource       string            `json:"eventSource"`
+	AwsRegion         string            `json:"awsRegion"`
+	EventTime         string            `json:"eventTime"`
+	EventName         string            `json:"eventName"`
+	UserIdentity      eventIdentity     `json:"userIdentity"`
+	RequestParameters map[string]string `json:"requestParameters"`
+	ResponseElements  map[string]string `json:"responseElements"`
+	S3                eventS3           `json:"s3"`
+}
+
+type eventIdentity struct {
+	PrincipalId string `json:"principalId"`
+}
+
+type eventS3 struct {
+	SchemaVersion   string      `json:"s3SchemaVersion"`
+	ConfigurationId string      `json:"configurationId"`
+	Bucket          eventBucket `json:"bucket"`
+	Object          eventObject `json:"object"`
+}
+
+type eventBucket struct {
+	Name          string        `json:"name"`
+	OwnerIdentity eventIdentity `json:"ownerIdentity"`
+	Arn           string        `json:"arn"`
+}
+
+type eventObject struct {
+	Key       string `json:"key"`
+	Size      int64  `json:"size,omitempty"`
+	ETag      string `json:"eTag,omitempty"`
+	VersionId string `json:"versionId,omitempty"`
+	Sequencer string `json:"sequencer"`
+}
+
+func (event *bucketEvent) message(configurationId string) ([]byte, error) {
+	key := strings.TrimPrefix(event.object, "/")
+	return json.Marshal(eventRecords{Records: []eventRecord{{
+		EventVersion:      "2.1",
+		EventSource:       "aws:s3",
+		AwsRegion:         event.region,
+		EventTime:         event.eventTime.UTC().Format("2006-01-02T15:04:05.000Z"),
+		EventName:         event.name,
+		RequestParameters: map[string]string{"sourceIPAddress": event.sourceIP},
+		ResponseElements:  map[string]string{},
+		S3: eventS3{
+			SchemaVersion:   "1.0",
+			ConfigurationId: configurationId,
+			Bucket:          eventBucket{Name: event.bucket, Arn: "arn:aws:s3:::" + event.bucket},
+			Object: eventObject{
+				Key:       strings.Replace(url.QueryEscape(key), "%2F", "/", -1),
+				Size:      event.size,
+				ETag:      strings.Trim(event.etag, "\""),
+				VersionId: event.versionId,
+				Sequencer: fmt.Sprintf("%016X", event.eventTime.UnixNano()),
+			},
+		},
+	}}})
+}
+
+// eventNotifier publishes the bucket events in the background, dropping them if the queue is full
+type eventNotifier struct {
+	client     *msgclient.MessagingClient
+	events     chan *bucketEvent
+	publishers map[string]*msgclient.Publisher
+}
+
+func newEventNotifier(brokers []string) *eventNotifier {
+	return &eventNotifier{
+		client:     msgclient.NewMessagingClient(brokers...),
+		events:     make(chan *bucketEvent, notificationQueueSize),
+		publishers: make(map[string]*msgclient.Publisher),
+	}
+}
+
+// notify never blocks the request, events of buckets without notification configuration are discarded later
+func (s3a *S3ApiServer) notify(r *http.Request, name, bucket, object string, size int64, etag, versionId string) {
+	if s3a.notifier == nil {
+		return
+	}
+	event := &bucketEvent{
+		name:      name,
+		bucket:    bucket,
+		object:    object,
+		size:      size,
+		etag:      etag,
+		versionId: versionId,
+		sourceIP:  r.RemoteAddr,
+		eventTime: time.Now(),
+		region:    s3a.region(),
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		event.sourceIP = host
+	}
+	select {
+	case s3a.notifier.events <- event:
+	default:
+		glog.V(0).Infof("notification queue is full, drop %s event of %s%s", name, bucket, object)
+	}
+}
+
+func (s3a *S3ApiServer) notifyObjectRemoved(r *http.Request, bucket, object, versionId string, deleteMarker bool) {
+	if deleteMarker {
+		s3a.notify(r, EventObjectRemovedDeleteMarkerCreated, bucket, object, 0, "", versionId)
+	} else {
+		s3a.notify(r, EventObjectRemovedDelete, bucket, object, 0, "", versionId)
+	}
+}
+
+func (s3a *S3ApiServer) loopNotification() {
+	for event := range s3a.notifier.events {
+		config, code := s3a.getBucketNotificationConfiguration(event.bucket)
+		if code != ErrNone || config == nil {
+			continue
+		}
+		for _, destination := range config.destinations() {
+			if !destination.matches(event.name, strings.TrimPrefix(event.object, "/")) {
+				continue
+			}
+			if err := s3a.notifier.publish(destination, event); err != nil {
+				glog.V(0).Infof("publish %s event of %s%s to %s: %v", event.name, event.bucket, event.object, destination.topic(), err)
+			}
+		}
+	}
+}
+
+func (notifier *eventNotifier) publish(destination notificationDestination, event *bucketEvent) error {
+
+	data, err := event.message(destination.id)
+	if err != nil {
+		return err
+	}
+
+	topic := destination.topic()
+	publisher, found := notifier.publishers[topic]
+	if !found {
+		if publisher, err = notifier.client.NewPublisher("s3", notificationNamespace, topic); err != nil {
+			return err
+		}
+		notifier.publishers[topic] = publisher
+	}
+
+	if err = publisher.Publish(&messaging_pb.Message{
+		EventTimeNs: event.eventTime.UnixNano(),
+		Key:         []byte(event.bucket + event.object),
+		Value:       data,
+	}); err != nil {
+		// reconnect on the next event
+		delete(notifier.publishers, topic)
+	}
+	return err
+}
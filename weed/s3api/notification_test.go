@@ -0,0 +1,104 @@
+package s3api
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestNotificationConfiguration(t *testing.T) {
+
+	tests := []struct {
+		xml      string
+		expected ErrorCode
+	}{
+		{`<NotificationConfiguration></NotificationConfiguration>`, ErrNone},
+		{`<NotificationConfiguration><TopicConfiguration><Id>1</Id><Topic>arn:aws:sns:us-east-1:123456789012:uploads</Topic><Event>s3:ObjectCreated:*</Event>
+			<Filter><S3Key><FilterRule><Name>prefix</Name><Value>images/</Value></FilterRule><FilterRule><Name>suffix</Name><Value>.jpg</Value></FilterRule></S3Key></Filter>
+			</TopicConfiguration></NotificationConfiguration>`, ErrNone},
+		{`<NotificationConfiguration><QueueConfiguration><Queue>arn:aws:sqs:us-east-1:123456789012:removed</Queue><Event>s3:ObjectRemoved:Delete</Event></QueueConfiguration></NotificationConfiguration>`, ErrNone},
+		{`<NotificationConfiguration><TopicConfiguration><Topic>uploads</Topic></TopicConfiguration></NotificationConfiguration>`, ErrMalformedXML},
+		{`<NotificationConfiguration><TopicConfiguration><Topic>uploads</Topic><Event>s3:ObjectRestore:Post</Event></TopicConfiguration></NotificationConfiguration>`, ErrInvalidRequest},
+		{`<NotificationConfiguration><TopicConfiguration><Topic>arn:aws:sns:us-east-1:123456789012:</Topic><Event>s3:ObjectCreated:Put</Event></TopicConfiguration></NotificationConfiguration>`, ErrInvalidRequest},
+		{`<NotificationConfiguration><TopicConfiguration><Topic>uploads</Topic><Event>s3:ObjectCreated:Put</Event>
+			<Filter><S3Key><FilterRule><Name>prefix</Name><Value>a</Value></FilterRule><FilterRule><Name>prefix</Name><Value>b</Value></FilterRule></S3Key></Filter>
+			</TopicConfiguration></NotificationConfiguration>`, ErrInvalidRequest},
+		{`<NotificationConfiguration><CloudFunctionConfiguration><Id>1</Id></CloudFunctionConfiguration></NotificationConfiguration>`, ErrNotImplemented},
+	}
+	for _, test := range tests {
+		config := &BucketNotificationConfiguration{}
+		if err := xml.Unmarshal([]byte(test.xml), config); err != nil {
+			t.Fatalf("unmarshal %s: %v", test.xml, err)
+		}
+		if errCode := config.validate(); errCode != test.expected {
+			t.Errorf("validate %s: got %v, expecting %v", test.xml, errCode, test.expected)
+		}
+	}
+}
+
+func TestNotificationDestinationMatches(t *testing.T) {
+
+	destination := notificationDestination{
+		arn:    "arn:aws:sns:us-east-1:123456789012:uploads",
+		events: []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:DeleteMarkerCreated"},
+		filter: &NotificationFilter{},
+	}
+	destination.filter.S3Key.FilterRules = []NotificationFilterRule{{Name: "Prefix", Value: "images/"}, {Name: "suffix", Value: ".jpg"}}
+
+	if destination.topic() != "uploads" {
+		t.Errorf("topic %s", destination.topic())
+	}
+
+	tests := []struct {
+		event    string
+		key      string
+		expected bool
+	}{
+		{EventObjectCreatedPut, "images/a.jpg", true},
+		{EventObjectCreatedCompleteMultipartUpload, "images/b/c.jpg", true},
+		{EventObjectRemovedDeleteMarkerCreated, "images/a.jpg", true},
+		{EventObjectRemovedDelete, "images/a.jpg", false},
+		{EventObjectCreatedPut, "docs/a.jpg", false},
+		{EventObjectCreatedPut, "images/a.png", false},
+	}
+	for _, test := range tests {
+		if matched := destination.matches(test.event, test.key); matched != test.expected {
+			t.Errorf("%s %s: matched %v", test.event, test.key, matched)
+		}
+	}
+}
+
+func TestBucketEventMessage(t *testing.T) {
+
+	event := &bucketEvent{
+		name:      EventObjectCreatedPut,
+		bucket:    "photos",
+		object:    "/2020/my cat.jpg",
+		size:      1024,
+		etag:      "\"d41d8cd98f00b204e9800998ecf8427e\"",
+		versionId: "v1",
+		sourceIP:  "127.0.0.1",
+		eventTime: time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	data, err := event.message("config1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := eventRecords{}
+	if err = json.Unmarshal(data, &records); err != nil || len(records.Records) != 1 {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+	record := records.Records[0]
+	if record.EventName != EventObjectCreatedPut || record.EventTime != "2020-05-01T10:00:00.000Z" || record.S3.ConfigurationId != "config1" {
+		t.Errorf("event record %+v", record)
+	}
+	object := record.S3.Object
+	if object.Key != "2020/my+cat.jpg" || object.Size != 1024 || object.ETag != "d41d8cd98f00b204e9800998ecf8427e" || object.VersionId != "v1" {
+		t.Errorf("event object %+v", object)
+	}
+	if record.S3.Bucket.Name != "photos" || record.S3.Bucket.Arn != "arn:aws:s3:::photos" {
+		t.Errorf("event bucket %+v", record.S3.Bucket)
+	}
+}
@@ -0,0 +1,188 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObjectAttributes.html
+
+const (
+	AmzObjectAttributes         = "X-Amz-Object-Attributes"
+	AmzMaxParts                 = "X-Amz-Max-Parts"
+	AmzPartNumberMarker         = "X-Amz-Part-Number-Marker"
+	ObjectAttributeETag         = "ETag"
+	ObjectAttributeChecksum     = "Checksum"
+	ObjectAttributeObjectParts  = "ObjectParts"
+	ObjectAttributeStorageClass = "StorageClass"
+	ObjectAttributeObjectSize   = "ObjectSize"
+
+	// the parts of a multipart object, kept after the upload is completed,
+	// as comma separated <part number>:<size>[:<checksum>[:<etag>]]
+	objectPartsKey = "X-Seaweed-Parts"
+)
+
+var objectAttributeNames = map[string]bool{
+	ObjectAttributeETag:         true,
+	ObjectAttributeChecksum:     true,
+	ObjectAttributeObjectParts:  true,
+	ObjectAttributeStorageClass: true,
+	ObjectAttributeObjectSize:   true,
+}
+
+type GetObjectAttributesResponse struct {
+	XMLName      xml.Name               `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectAttributesResponse"`
+	ETag         string                 `xml:"ETag,omitempty"`
+	Checksum     *ChecksumValues        `xml:"Checksum,omitempty"`
+	ObjectParts  *ObjectAttributesParts `xml:"ObjectParts,omitempty"`
+	StorageClass string                 `xml:"StorageClass,omitempty"`
+	ObjectSize   *int64                 `xml:"ObjectSize,omitempty"`
+}
+
+type ChecksumValues struct {
+	ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+}
+
+type ObjectAttributesParts struct {
+	IsTruncated          bool                   `xml:"IsTruncated"`
+	MaxParts             int                    `xml:"MaxParts"`
+	NextPartNumberMarker int                    `xml:"NextPartNumberMarker"`
+	PartNumberMarker     int                    `xml:"PartNumberMarker"`
+	Parts                []ObjectAttributesPart `xml:"Part"`
+	PartsCount           int                    `xml:"PartsCount"`
+}
+
+type ObjectAttributesPart struct {
+	ChecksumValues
+	PartNumber int   `xml:"PartNumber"`
+	Size       int64 `xml:"Size"`
+}
+
+func newChecksumValues(checksum *objectChecksum) *ChecksumValues {
+	if checksum == nil {
+		return nil
+	}
+	values := &ChecksumValues{}
+	switch checksum.algorithm {
+	case ChecksumAlgorithmCRC32:
+		values.ChecksumCRC32 = checksum.value
+	case ChecksumAlgorithmCRC32C:
+		values.ChecksumCRC32C = checksum.value
+	case ChecksumAlgorithmSHA1:
+		values.ChecksumSHA1 = checksum.value
+	case ChecksumAlgorithmSHA256:
+		values.ChecksumSHA256 = checksum.value
+	}
+	return values
+}
+
+// parseObjectAttributes reads the requested attributes from the comma separated x-amz-object-attributes headers
+func parseObjectAttributes(headerValues []string) (attributes map[string]bool, code ErrorCode) {
+	attributes = make(map[string]bool)
+	for _, headerValue := range headerValues {
+		for _, name := range strings.Split(headerValue, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !objectAttributeNames[name] {
+				return nil, ErrInvalidObjectAttributes
+			}
+			attributes[name] = true
+		}
+	}
+	if len(attributes) == 0 {
+		return nil, ErrInvalidObjectAttributes
+	}
+	return attributes, ErrNone
+}
+
+// objectPart is one part of a completed multipart object
+type objectPart struct {
+	partNumber int
+	size       int64
+	checksum   string
+	etag       string
+}
+
+type objectParts []objectPart
+
+func loadObjectParts(extended map[string][]byte) (parts objectParts) {
+	value, found := extended[objectPartsKey]
+	if !found {
+		return nil
+	}
+	for _, p := range strings.Split(string(value), ",") {
+		fields := strings.Split(p, ":")
+		if len(fields) < 2 {
+			glog.Warningf("invalid object part %q", p)
+			continue
+		}
+		partNumber, err := strconv.Atoi(fields[0])
+		if err != nil {
+			glog.Warningf("invalid object part %q: %v", p, err)
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			glog.Warningf("invalid object part %q: %v", p, err)
+			continue
+		}
+		part := objectPart{partNumber: partNumber, size: size}
+		if len(fields) > 2 {
+			part.checksum = fields[2]
+		}
+		if len(fields) > 3 {
+			part.etag = fields[3]
+		}
+		parts = append(parts, part)
+	}
+	return
+}
+
+func (parts objectParts) saveTo(extended map[string][]byte) {
+	var values []string
+	for _, part := range parts {
+		value := fmt.Sprintf("%d:%d", part.partNumber, part.size)
+		if part.checksum != "" || part.etag != "" {
+			value += ":" + part.checksum
+		}
+		if part.etag != "" {
+			value += ":" + part.etag
+		}
+		values = append(values, value)
+	}
+	extended[objectPartsKey] = []byte(strings.Join(values, ","))
+}
+
+// toObjectAttributesParts lists the parts after the part number marker, up to maxParts
+func (parts objectParts) toObjectAttributesParts(checksumAlgorithm string, partNumberMarker, maxParts int) *ObjectAttributesParts {
+	result := &ObjectAttributesParts{
+		MaxParts:         maxParts,
+		PartNumberMarker: partNumberMarker,
+		PartsCount:       len(parts),
+	}
+	for _, part := range parts {
+		if part.partNumber <= partNumberMarker {
+			continue
+		}
+		if len(result.Parts) >= maxParts {
+			result.IsTruncated = true
+			break
+		}
+		p := ObjectAttributesPart{PartNumber: part.partNumber, Size: part.size}
+		if part.checksum != "" {
+			p.ChecksumValues = *newChecksumValues(&objectChecksum{algorithm: checksumAlgorithm, value: part.checksum})
+		}
+		result.Parts = append(result.Parts, p)
+		result.NextPartNumberMarker = part.partNumber
+	}
+	return result
+}
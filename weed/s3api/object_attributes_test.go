@@ -0,0 +1,121 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestParseObjectAttributes(t *testing.T) {
+	attributes, code := parseObjectAttributes([]string{"ETag, ObjectSize", "StorageClass"})
+	if code != ErrNone {
+		t.Fatalf("parse: %v", code)
+	}
+	if len(attributes) != 3 || !attributes[ObjectAttributeETag] || !attributes[ObjectAttributeObjectSize] || !attributes[ObjectAttributeStorageClass] {
+		t.Errorf("unexpected attributes %v", attributes)
+	}
+
+	if _, code = parseObjectAttributes(nil); code != ErrInvalidObjectAttributes {
+		t.Errorf("no attributes: %v", code)
+	}
+	if _, code = parseObjectAttributes([]string{"ETag,Owner"}); code != ErrInvalidObjectAttributes {
+		t.Errorf("unknown attribute: %v", code)
+	}
+}
+
+func TestObjectParts(t *testing.T) {
+	parts := objectParts{
+		{partNumber: 1, size: 5 << 20, checksum: "AAAAAA=="},
+		{partNumber: 2, size: 5 << 20, checksum: "BBBBBB=="},
+		{partNumber: 4, size: 1024, checksum: "CCCCCC=="},
+	}
+	extended := make(map[string][]byte)
+	parts.saveTo(extended)
+	loaded := loadObjectParts(extended)
+	if len(loaded) != len(parts) {
+		t.Fatalf("loaded %+v", loaded)
+	}
+	for i := range parts {
+		if loaded[i] != parts[i] {
+			t.Errorf("part %d: %+v, expecting %+v", i, loaded[i], parts[i])
+		}
+	}
+
+	result := loaded.toObjectAttributesParts(ChecksumAlgorithmCRC32, 0, 2)
+	if !result.IsTruncated || result.NextPartNumberMarker != 2 || result.PartsCount != 3 || len(result.Parts) != 2 {
+		t.Fatalf("first page %+v", result)
+	}
+	if result.Parts[1].ChecksumCRC32 != "BBBBBB==" || result.Parts[1].Size != 5<<20 {
+		t.Errorf("unexpected part %+v", result.Parts[1])
+	}
+
+	result = loaded.toObjectAttributesParts(ChecksumAlgorithmCRC32, result.NextPartNumberMarker, 2)
+	if result.IsTruncated || len(result.Parts) != 1 || result.Parts[0].PartNumber != 4 || result.NextPartNumberMarker != 4 {
+		t.Errorf("second page %+v", result)
+	}
+}
+
+func TestGetObjectAttributesHandler(t *testing.T) {
+	extended := make(map[string][]byte)
+	(&objectChecksum{algorithm: ChecksumAlgorithmCRC32, value: "ZZZZZZ==-2"}).saveTo(extended)
+	objectParts{{partNumber: 1, size: 5, checksum: "AAAAAA=="}, {partNumber: 2, size: 3, checksum: "BBBBBB=="}}.saveTo(extended)
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket/a.txt": {
+			Name:       "a.txt",
+			Attributes: &filer_pb.FuseAttributes{FileSize: 8, Mtime: 1600000000},
+			Content:    []byte("12345678"),
+			Extended:   extended,
+		},
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+
+	getAttributes := func(object string, attributes ...string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/bucket/"+object+"?attributes", nil)
+		r.Header[AmzObjectAttributes] = attributes
+		r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": object})
+		w := httptest.NewRecorder()
+		s3a.GetObjectAttributesHandler(w, r)
+		return w
+	}
+
+	w := getAttributes("a.txt", "Checksum,ObjectParts,StorageClass,ObjectSize")
+	if w.Code != 200 {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var response GetObjectAttributesResponse
+	if err := xml.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if response.ETag != "" {
+		t.Errorf("unrequested ETag %s", response.ETag)
+	}
+	if response.ObjectSize == nil || *response.ObjectSize != 8 {
+		t.Errorf("object size %v", response.ObjectSize)
+	}
+	if response.StorageClass != StorageClassStandard {
+		t.Errorf("storage class %s", response.StorageClass)
+	}
+	if response.Checksum == nil || response.Checksum.ChecksumCRC32 != "ZZZZZZ==-2" {
+		t.Errorf("checksum %+v", response.Checksum)
+	}
+	if response.ObjectParts == nil || response.ObjectParts.PartsCount != 2 || len(response.ObjectParts.Parts) != 2 ||
+		response.ObjectParts.Parts[0].ChecksumCRC32 != "AAAAAA==" || response.ObjectParts.Parts[1].Size != 3 {
+		t.Errorf("object parts %+v", response.ObjectParts)
+	}
+	if w.Header().Get("Last-Modified") == "" {
+		t.Errorf("missing Last-Modified")
+	}
+
+	if w = getAttributes("a.txt", "Owner"); w.Code != 400 {
+		t.Errorf("invalid attribute: status %d", w.Code)
+	}
+	if w = getAttributes("missing.txt", "ETag"); w.Code != 404 {
+		t.Errorf("missing object: status %d", w.Code)
+	}
+}
@@ -0,0 +1,316 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/object-lock-overview.html
+
+const (
+	s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+	ObjectLockModeGovernance = "GOVERNANCE"
+	ObjectLockModeCompliance = "COMPLIANCE"
+	ObjectLockEnabled        = "Enabled"
+	LegalHoldOn              = "ON"
+	LegalHoldOff             = "OFF"
+
+	// request headers, also used as the extended attribute keys on the object entry
+	AmzObjectLockMode            = "X-Amz-Object-Lock-Mode"
+	AmzObjectLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
+	AmzObjectLockLegalHold       = "X-Amz-Object-Lock-Legal-Hold"
+	AmzBucketObjectLockEnabled   = "X-Amz-Bucket-Object-Lock-Enabled"
+	AmzBypassGovernanceRetention = "X-Amz-Bypass-Governance-Retention"
+
+	// extended attribute key on the bucket entry
+	bucketObjectLockConfigurationKey = "s3-object-lock-configuration"
+)
+
+type ObjectLockConfiguration struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	Xmlns             string          `xml:"xmlns,attr,omitempty"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled,omitempty"`
+	Rule              *ObjectLockRule `xml:"Rule,omitempty"`
+}
+
+type ObjectLockRule struct {
+	DefaultRetention *DefaultRetention `xml:"DefaultRetention,omitempty"`
+}
+
+type DefaultRetention struct {
+	Mode  string `xml:"Mode,omitempty"`
+	Days  int    `xml:"Days,omitempty"`
+	Years int    `xml:"Years,omitempty"`
+}
+
+type ObjectRetention struct {
+	XMLName         xml.Name   `xml:"Retention"`
+	Xmlns           string     `xml:"xmlns,attr,omitempty"`
+	Mode            string     `xml:"Mode,omitempty"`
+	RetainUntilDate *time.Time `xml:"RetainUntilDate,omitempty"`
+}
+
+type ObjectLegalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Status  string   `xml:"Status"`
+}
+
+func isValidObjectLockMode(mode string) bool {
+	return mode == ObjectLockModeGovernance || mode == ObjectLockModeCompliance
+}
+
+func isValidLegalHoldStatus(status string) bool {
+	return status == LegalHoldOn || status == LegalHoldOff
+}
+
+func (config *ObjectLockConfiguration) isEnabled() bool {
+	return config != nil && config.ObjectLockEnabled == ObjectLockEnabled
+}
+
+// isCompliance checks whether the new objects are locked in the COMPLIANCE mode by default
+func (config *ObjectLockConfiguration) isCompliance() bool {
+	return config.isEnabled() && config.Rule != nil && config.Rule.DefaultRetention != nil &&
+		config.Rule.DefaultRetention.Mode == ObjectLockModeCompliance
+}
+
+func (config *ObjectLockConfiguration) validate() ErrorCode {
+	if config.ObjectLockEnabled != ObjectLockEnabled {
+		return ErrMalformedXML
+	}
+	if config.Rule == nil || config.Rule.DefaultRetention == nil {
+		return ErrNone
+	}
+	retention := config.Rule.DefaultRetention
+	if !isValidObjectLockMode(retention.Mode) {
+		return ErrMalformedXML
+	}
+	if (retention.Days > 0) == (retention.Years > 0) || retention.Days < 0 || retention.Years < 0 {
+		return ErrInvalidRetentionPeriod
+	}
+	return ErrNone
+}
+
+// defaultObjectLock returns the retention a new object gets when the request does not specify one
+func (config *ObjectLockConfiguration) defaultObjectLock(now time.Time) *objectLock {
+	if !config.isEnabled() || config.Rule == nil || config.Rule.DefaultRetention == nil {
+		return nil
+	}
+	retention := config.Rule.DefaultRetention
+	return &objectLock{
+		mode:            retention.Mode,
+		retainUntilDate: now.AddDate(retention.Years, 0, retention.Days),
+	}
+}
+
+// objectLock is the retention and legal hold state of one object
+type objectLock struct {
+	mode            string
+	retainUntilDate time.Time
+	legalHold       string
+}
+
+func loadObjectLock(extended map[string][]byte) *objectLock {
+	lock := &objectLock{
+		mode:      string(extended[AmzObjectLockMode]),
+		legalHold: string(extended[AmzObjectLockLegalHold]),
+	}
+	if retainUntilDate, found := extended[AmzObjectLockRetainUntilDate]; found {
+		if t, err := time.Parse(time.RFC3339, string(retainUntilDate)); err == nil {
+			lock.retainUntilDate = t
+		} else {
+			glog.Warningf("invalid object lock retain until date %s: %v", string(retainUntilDate), err)
+		}
+	}
+	return lock
+}
+
+func (lock *objectLock) saveTo(extended map[string][]byte) {
+	if lock.mode == "" {
+		delete(extended, AmzObjectLockMode)
+		delete(extended, AmzObjectLockRetainUntilDate)
+	} else {
+		extended[AmzObjectLockMode] = []byte(lock.mode)
+		extended[AmzObjectLockRetainUntilDate] = []byte(lock.retainUntilDate.UTC().Format(time.RFC3339))
+	}
+	if lock.legalHold == "" {
+		delete(extended, AmzObjectLockLegalHold)
+	} else {
+		extended[AmzObjectLockLegalHold] = []byte(lock.legalHold)
+	}
+}
+
+func (lock *objectLock) isRetained(now time.Time) bool {
+	return lock.mode != "" && now.Before(lock.retainUntilDate)
+}
+
+// checkRemoval decides whether the object can be deleted or overwritten.
+// COMPLIANCE retention and legal holds can not be bypassed by anyone.
+func (lock *objectLock) checkRemoval(now time.Time, bypassGovernance bool) ErrorCode {
+	if lock.legalHold == LegalHoldOn {
+		return ErrAccessDenied
+	}
+	if !lock.isRetained(now) {
+		return ErrNone
+	}
+	if lock.mode == ObjectLockModeGovernance && bypassGovernance {
+		return ErrNone
+	}
+	return ErrAccessDenied
+}
+
+// checkRetentionChange decides whether the object retention can be replaced by the new one.
+// Retention can always be extended, but only GOVERNANCE retention can be shortened or removed, with bypass.
+func (lock *objectLock) checkRetentionChange(newMode string, newRetainUntilDate time.Time, now time.Time, bypassGovernance bool) ErrorCode {
+	if !lock.isRetained(now) {
+		return ErrNone
+	}
+	isWeakened := newMode == "" || newRetainUntilDate.Before(lock.retainUntilDate) ||
+		(lock.mode == ObjectLockModeCompliance && newMode != ObjectLockModeCompliance)
+	if !isWeakened {
+		return ErrNone
+	}
+	if lock.mode == ObjectLockModeGovernance && bypassGovernance {
+		return ErrNone
+	}
+	return ErrAccessDenied
+}
+
+// parseObjectLockHeaders reads the object lock settings from PutObject or NewMultipartUpload headers
+func parseObjectLockHeaders(h http.Header, now time.Time) (lock *objectLock, code ErrorCode) {
+	mode := h.Get(AmzObjectLockMode)
+	retainUntilDate := h.Get(AmzObjectLockRetainUntilDate)
+	legalHold := h.Get(AmzObjectLockLegalHold)
+	if mode == "" && retainUntilDate == "" && legalHold == "" {
+		return nil, ErrNone
+	}
+	lock = &objectLock{}
+	if mode != "" || retainUntilDate != "" {
+		if !isValidObjectLockMode(mode) || retainUntilDate == "" {
+			return nil, ErrInvalidObjectLockHeaders
+		}
+		t, err := time.Parse(time.RFC3339, retainUntilDate)
+		if err != nil {
+			return nil, ErrInvalidObjectLockHeaders
+		}
+		if !t.After(now) {
+			return nil, ErrPastObjectLockRetainDate
+		}
+		lock.mode, lock.retainUntilDate = mode, t
+	}
+	if legalHold != "" {
+		if !isValidLegalHoldStatus(legalHold) {
+			return nil, ErrInvalidObjectLockHeaders
+		}
+		lock.legalHold = legalHold
+	}
+	return lock, ErrNone
+}
+
+// checkBypassGovernance tells whether the request bypasses the GOVERNANCE retention,
+// which is allowed only for the admins of the bucket
+func checkBypassGovernance(r *http.Request, bucket string) (bool, ErrorCode) {
+	if r.Header.Get(AmzBypassGovernanceRetention) != "true" {
+		return false, ErrNone
+	}
+	if !getRequester(r).canDo(ACTION_ADMIN, bucket) {
+		return false, ErrAccessDenied
+	}
+	return true, ErrNone
+}
+
+func (s3a *S3ApiServer) getBucketObjectLockConfiguration(bucket string) (config *ObjectLockConfiguration, code ErrorCode) {
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		return nil, ErrInternalError
+	}
+	if entry == nil {
+		return nil, ErrNoSuchBucket
+	}
+
+	data, found := entry.Extended[bucketObjectLockConfigurationKey]
+	if !found {
+		return nil, ErrNone
+	}
+
+	config = &ObjectLockConfiguration{}
+	if err := xml.Unmarshal(data, config); err != nil {
+		glog.Errorf("bucket %s has invalid object lock configuration: %v", bucket, err)
+		return nil, ErrInternalError
+	}
+
+	return config, ErrNone
+}
+
+func (s3a *S3ApiServer) objectEntry(bucket, object string) (dir, name string, entry *filer_pb.Entry, err error) {
+	dir, name = s3a.objectDirAndName(bucket, object)
+	entry, err = s3a.getEntry(dir, name)
+	return
+}
+
+// checkObjectRemovable rejects deleting or overwriting an object under active retention or legal hold
+func (s3a *S3ApiServer) checkObjectRemovable(r *http.Request, bucket, object string) ErrorCode {
+
+	_, _, entry, err := s3a.objectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("lookup object %s%s: %v", bucket, object, err)
+		return ErrInternalError
+	}
+	if entry == nil || entry.IsDirectory || entry.Extended == nil {
+		return ErrNone
+	}
+
+	// a versioned bucket keeps the replaced or deleted current version
+	if status, code := s3a.getBucketVersioning(bucket); code == ErrNone && isVersionKept(status, entry) {
+		return ErrNone
+	}
+
+	bypassGovernance, code := checkBypassGovernance(r, bucket)
+	if code != ErrNone {
+		return code
+	}
+	return loadObjectLock(entry.Extended).checkRemoval(time.Now(), bypassGovernance)
+}
+
+// prepareObjectLock validates the object lock of a write, and returns the lock to apply after the write succeeds
+func (s3a *S3ApiServer) prepareObjectLock(r *http.Request, bucket, object string) (lock *objectLock, code ErrorCode) {
+
+	now := time.Now()
+
+	lock, code = parseObjectLockHeaders(r.Header, now)
+	if code != ErrNone {
+		return nil, code
+	}
+
+	config, code := s3a.getBucketObjectLockConfiguration(bucket)
+	if code != ErrNone && code != ErrNoSuchBucket {
+		return nil, code
+	}
+	if !config.isEnabled() {
+		if lock != nil {
+			return nil, ErrMissingObjectLockConfiguration
+		}
+		return nil, ErrNone
+	}
+
+	if code = s3a.checkObjectRemovable(r, bucket, object); code != ErrNone {
+		return nil, code
+	}
+
+	if lock == nil {
+		lock = config.defaultObjectLock(now)
+	} else if lock.mode == "" {
+		if defaultLock := config.defaultObjectLock(now); defaultLock != nil {
+			lock.mode, lock.retainUntilDate = defaultLock.mode, defaultLock.retainUntilDate
+		}
+	}
+
+	return lock, ErrNone
+}
@@ -0,0 +1,126 @@
+package s3api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestObjectLockCheckRemoval(t *testing.T) {
+
+	now := time.Now()
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		lock     objectLock
+		bypass   bool
+		expected ErrorCode
+	}{
+		{objectLock{}, false, ErrNone},
+		{objectLock{mode: ObjectLockModeGovernance, retainUntilDate: future}, false, ErrAccessDenied},
+		{objectLock{mode: ObjectLockModeGovernance, retainUntilDate: future}, true, ErrNone},
+		{objectLock{mode: ObjectLockModeCompliance, retainUntilDate: future}, true, ErrAccessDenied},
+		{objectLock{mode: ObjectLockModeCompliance, retainUntilDate: past}, false, ErrNone},
+		{objectLock{legalHold: LegalHoldOn}, true, ErrAccessDenied},
+		{objectLock{legalHold: LegalHoldOff}, false, ErrNone},
+	}
+
+	for i, tt := range tests {
+		if code := tt.lock.checkRemoval(now, tt.bypass); code != tt.expected {
+			t.Errorf("case %d: expected %v, got %v", i, tt.expected, code)
+		}
+	}
+
+}
+
+func TestObjectLockCheckRetentionChange(t *testing.T) {
+
+	now := time.Now()
+	current := now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+	earlier := now.Add(time.Minute)
+
+	governance := objectLock{mode: ObjectLockModeGovernance, retainUntilDate: current}
+	compliance := objectLock{mode: ObjectLockModeCompliance, retainUntilDate: current}
+
+	tests := []struct {
+		lock     objectLock
+		mode     string
+		until    time.Time
+		bypass   bool
+		expected ErrorCode
+	}{
+		{governance, ObjectLockModeGovernance, later, false, ErrNone},
+		{governance, ObjectLockModeGovernance, earlier, false, ErrAccessDenied},
+		{governance, ObjectLockModeGovernance, earlier, true, ErrNone},
+		{governance, "", time.Time{}, true, ErrNone},
+		{compliance, ObjectLockModeCompliance, later, false, ErrNone},
+		{compliance, ObjectLockModeCompliance, earlier, true, ErrAccessDenied},
+		{compliance, ObjectLockModeGovernance, later, true, ErrAccessDenied},
+	}
+
+	for i, tt := range tests {
+		if code := tt.lock.checkRetentionChange(tt.mode, tt.until, now, tt.bypass); code != tt.expected {
+			t.Errorf("case %d: expected %v, got %v", i, tt.expected, code)
+		}
+	}
+
+}
+
+func TestParseObjectLockHeaders(t *testing.T) {
+
+	now := time.Now()
+
+	h := http.Header{}
+	if lock, code := parseObjectLockHeaders(h, now); lock != nil || code != ErrNone {
+		t.Errorf("no headers: unexpected lock %v, code %v", lock, code)
+	}
+
+	h.Set(AmzObjectLockMode, ObjectLockModeGovernance)
+	if _, code := parseObjectLockHeaders(h, now); code != ErrInvalidObjectLockHeaders {
+		t.Errorf("mode without date: unexpected code %v", code)
+	}
+
+	h.Set(AmzObjectLockRetainUntilDate, now.Add(-time.Hour).Format(time.RFC3339))
+	if _, code := parseObjectLockHeaders(h, now); code != ErrPastObjectLockRetainDate {
+		t.Errorf("past date: unexpected code %v", code)
+	}
+
+	h.Set(AmzObjectLockRetainUntilDate, now.Add(time.Hour).Format(time.RFC3339))
+	h.Set(AmzObjectLockLegalHold, LegalHoldOn)
+	lock, code := parseObjectLockHeaders(h, now)
+	if code != ErrNone || lock.mode != ObjectLockModeGovernance || lock.legalHold != LegalHoldOn {
+		t.Errorf("valid headers: unexpected lock %v, code %v", lock, code)
+	}
+
+}
+
+func TestCheckBypassGovernance(t *testing.T) {
+
+	writer := &requester{identity: &Identity{Name: "writer", Actions: []Action{ACTION_WRITE}}}
+	admin := &requester{identity: &Identity{Name: "admin", Actions: []Action{ACTION_ADMIN + ":bucket"}}}
+
+	tests := []struct {
+		requester *requester
+		header    string
+		bypass    bool
+		expected  ErrorCode
+	}{
+		{writer, "", false, ErrNone},
+		{writer, "true", false, ErrAccessDenied},
+		{admin, "true", true, ErrNone},
+		{admin, "false", false, ErrNone},
+		{&requester{trusted: true}, "true", true, ErrNone},
+	}
+
+	for i, tt := range tests {
+		r := withRequester(&http.Request{Header: make(http.Header)}, tt.requester)
+		if tt.header != "" {
+			r.Header.Set(AmzBypassGovernanceRetention, tt.header)
+		}
+		if bypass, code := checkBypassGovernance(r, "bucket"); bypass != tt.bypass || code != tt.expected {
+			t.Errorf("case %d: expected %v %v, got %v %v", i, tt.bypass, tt.expected, bypass, code)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package s3api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/UsingMetadata.html
+
+const (
+	AmzUserMetaPrefix    = "X-Amz-Meta-"
+	AmzMetadataDirective = "X-Amz-Metadata-Directive"
+
+	// the standard headers are kept aside with this prefix, not to be taken as the headers of the filer responses,
+	// e.g. the filer sets its own Content-Disposition on reads
+	objectHeaderPrefix          = "X-Seaweed-"
+	objectContentDispositionKey = objectHeaderPrefix + "Content-Disposition"
+
+	maxUserMetadataSize = 2 * 1024
+)
+
+// objectHeaders are the standard headers set on PUT and returned on GET and HEAD,
+// each with the query parameter to override it on GET and HEAD
+var objectHeaders = map[string]string{
+	"Cache-Control":       "response-cache-control",
+	"Content-Disposition": "response-content-disposition",
+	"Content-Encoding":    "response-content-encoding",
+	"Content-Language":    "response-content-language",
+	"Expires":             "response-expires",
+}
+
+// objectMetadata is the user metadata and the standard headers kept with the object.
+// The content type is the mime type of the entry, the others are in the extended attributes.
+type objectMetadata struct {
+	userMetadata map[string]string // by the canonical header names, x-amz-meta-*
+	contentType  string
+	headers      map[string]string // the objectHeaders
+}
+
+// parseRequestMetadata reads the metadata of the object from the request headers
+func parseRequestMetadata(r *http.Request) (*objectMetadata, ErrorCode) {
+	metadata := &objectMetadata{
+		userMetadata: make(map[string]string),
+		contentType:  r.Header.Get("Content-Type"),
+		headers:      make(map[string]string),
+	}
+	for header := range objectHeaders {
+		value := r.Header.Get(header)
+		if header == "Content-Encoding" {
+			// the aws-chunked encoding is of the request only
+			value = removeAwsChunkedEncoding(value)
+		}
+		if value != "" {
+			metadata.headers[header] = value
+		}
+	}
+	size := 0
+	for key, values := range r.Header {
+		if !strings.HasPrefix(key, AmzUserMetaPrefix) {
+			continue
+		}
+		value := strings.Join(values, ",")
+		metadata.userMetadata[key] = value
+		size += len(key) - len(AmzUserMetaPrefix) + len(value)
+	}
+	if size > maxUserMetadataSize {
+		return nil, ErrMetadataTooLarge
+	}
+	return metadata, ErrNone
+}
+
+func loadObjectMetadata(entry *filer_pb.Entry) *objectMetadata {
+	metadata := &objectMetadata{
+		userMetadata: make(map[string]string),
+		headers:      make(map[string]string),
+	}
+	if entry.Attributes != nil {
+		metadata.contentType = entry.Attributes.Mime
+	}
+	for key, value := range entry.Extended {
+		if strings.HasPrefix(key, AmzUserMetaPrefix) {
+			metadata.userMetadata[key] = string(value)
+		}
+	}
+	for header := range objectHeaders {
+		if value, found := entry.Extended[objectHeaderPrefix+header]; found {
+			metadata.headers[header] = string(value)
+		}
+	}
+	return metadata
+}
+
+// saveTo replaces the metadata kept in the entry. The mime type is kept if the content type is empty.
+func (metadata *objectMetadata) saveTo(entry *filer_pb.Entry) {
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	for key := range entry.Extended {
+		if strings.HasPrefix(key, AmzUserMetaPrefix) {
+			delete(entry.Extended, key)
+		}
+	}
+	for key, value := range metadata.userMetadata {
+		entry.Extended[key] = []byte(value)
+	}
+	for header := range objectHeaders {
+		if value := metadata.headers[header]; value != "" {
+			entry.Extended[objectHeaderPrefix+header] = []byte(value)
+		} else {
+			delete(entry.Extended, objectHeaderPrefix+header)
+		}
+	}
+	if metadata.contentType != "" && entry.Attributes != nil {
+		entry.Attributes.Mime = metadata.contentType
+	}
+}
+
+// isEmpty returns true if there is nothing to save
+func (metadata *objectMetadata) isEmpty() bool {
+	return metadata == nil || len(metadata.userMetadata) == 0 && metadata.contentType == "" && len(metadata.headers) == 0
+}
+
+// setObjectHeaders returns the standard headers kept with the object, overridden by the response-* query parameters
+func setObjectHeaders(r *http.Request, w http.ResponseWriter, proxyHeader http.Header) {
+	query := r.URL.Query()
+	for header, param := range objectHeaders {
+		if value := query.Get(param); value != "" {
+			w.Header().Set(header, value)
+		} else if values, found := proxyHeader[objectHeaderPrefix+header]; found {
+			w.Header()[header] = values
+		}
+	}
+	if contentType := query.Get("response-content-type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+}
+
+// copyObjectMetadata returns the metadata of the source object, or of the request with x-amz-metadata-directive REPLACE
+func copyObjectMetadata(r *http.Request, srcEntry *filer_pb.Entry) (*objectMetadata, ErrorCode) {
+
+	switch r.Header.Get(AmzMetadataDirective) {
+	case "", "COPY":
+	case "REPLACE":
+		return parseRequestMetadata(r)
+	default:
+		return nil, ErrInvalidMetadataDirective
+	}
+
+	if srcEntry == nil {
+		return nil, ErrNone
+	}
+	return loadObjectMetadata(srcEntry), ErrNone
+}
@@ -0,0 +1,160 @@
+package s3api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+func TestParseRequestMetadata(t *testing.T) {
+	r := httptest.NewRequest("PUT", "/bucket/object", nil)
+	r.Header.Set("Content-Type", "text/plain")
+	r.Header.Set("Content-Disposition", `attachment; filename="a.txt"`)
+	r.Header.Set("Cache-Control", "max-age=3600")
+	r.Header.Set("Content-Encoding", "gzip,aws-chunked")
+	r.Header.Set("x-amz-meta-color", "blue")
+	r.Header.Set("X-Amz-Meta-Size", "large")
+	r.Header.Set("X-Amz-Storage-Class", StorageClassStandard)
+
+	metadata, errCode := parseRequestMetadata(r)
+	if errCode != ErrNone {
+		t.Fatalf("parse: %v", errCode)
+	}
+	if len(metadata.userMetadata) != 2 || metadata.userMetadata["X-Amz-Meta-Color"] != "blue" || metadata.userMetadata["X-Amz-Meta-Size"] != "large" {
+		t.Errorf("user metadata %v", metadata.userMetadata)
+	}
+	if metadata.contentType != "text/plain" || metadata.headers["Content-Disposition"] != `attachment; filename="a.txt"` {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+	if len(metadata.headers) != 3 || metadata.headers["Cache-Control"] != "max-age=3600" || metadata.headers["Content-Encoding"] != "gzip" {
+		t.Errorf("unexpected headers %v", metadata.headers)
+	}
+
+	r.Header.Set("X-Amz-Meta-Large", strings.Repeat("x", maxUserMetadataSize))
+	if _, errCode = parseRequestMetadata(r); errCode != ErrMetadataTooLarge {
+		t.Errorf("too large metadata: %v", errCode)
+	}
+}
+
+func TestObjectMetadataSaveTo(t *testing.T) {
+	entry := &filer_pb.Entry{
+		Attributes: &filer_pb.FuseAttributes{Mime: "text/plain"},
+		Extended: map[string][]byte{
+			"X-Amz-Meta-Old":             []byte("old"),
+			objectContentDispositionKey:  []byte("inline"),
+			AmzObjectTaggingPrefix + "k": []byte("v"),
+		},
+	}
+
+	(&objectMetadata{userMetadata: map[string]string{"X-Amz-Meta-New": "new"}}).saveTo(entry)
+	metadata := loadObjectMetadata(entry)
+	if len(metadata.userMetadata) != 1 || metadata.userMetadata["X-Amz-Meta-New"] != "new" {
+		t.Errorf("user metadata %v", metadata.userMetadata)
+	}
+	if metadata.contentType != "text/plain" {
+		t.Errorf("content type %q, expecting the mime type to be kept", metadata.contentType)
+	}
+	if metadata.headers["Content-Disposition"] != "" {
+		t.Errorf("content disposition %q, expecting it to be removed", metadata.headers["Content-Disposition"])
+	}
+	if string(entry.Extended[AmzObjectTaggingPrefix+"k"]) != "v" {
+		t.Errorf("the tags are changed")
+	}
+}
+
+func TestCopyObjectMetadata(t *testing.T) {
+	srcEntry := &filer_pb.Entry{
+		Attributes: &filer_pb.FuseAttributes{Mime: "image/png"},
+		Extended: map[string][]byte{
+			"X-Amz-Meta-Color":          []byte("blue"),
+			objectContentDispositionKey: []byte("inline"),
+		},
+	}
+
+	tests := []struct {
+		directive          string
+		userMetadata       map[string]string
+		contentType        string
+		contentDisposition string
+		errCode            ErrorCode
+	}{
+		{"", map[string]string{"X-Amz-Meta-Color": "blue"}, "image/png", "inline", ErrNone},
+		{"COPY", map[string]string{"X-Amz-Meta-Color": "blue"}, "image/png", "inline", ErrNone},
+		{"REPLACE", map[string]string{"X-Amz-Meta-Color": "red"}, "image/jpeg", "attachment", ErrNone},
+		{"MOVE", nil, "", "", ErrInvalidMetadataDirective},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("PUT", "/bucket/copy", nil)
+		r.Header.Set("X-Amz-Copy-Source", "/bucket/object")
+		r.Header.Set("X-Amz-Meta-Color", "red")
+		r.Header.Set("Content-Type", "image/jpeg")
+		r.Header.Set("Content-Disposition", "attachment")
+		if test.directive != "" {
+			r.Header.Set(AmzMetadataDirective, test.directive)
+		}
+
+		metadata, errCode := copyObjectMetadata(r, srcEntry)
+		if errCode != test.errCode {
+			t.Errorf("directive %q: %v, expecting %v", test.directive, errCode, test.errCode)
+			continue
+		}
+		if errCode != ErrNone {
+			continue
+		}
+		if len(metadata.userMetadata) != len(test.userMetadata) || metadata.userMetadata["X-Amz-Meta-Color"] != test.userMetadata["X-Amz-Meta-Color"] {
+			t.Errorf("directive %q: user metadata %v", test.directive, metadata.userMetadata)
+		}
+		if metadata.contentType != test.contentType || metadata.headers["Content-Disposition"] != test.contentDisposition {
+			t.Errorf("directive %q: unexpected metadata %+v", test.directive, metadata)
+		}
+	}
+}
+
+func TestPassThroughObjectMetadata(t *testing.T) {
+	s3a := &S3ApiServer{}
+	r := httptest.NewRequest("GET", "/bucket/object", nil)
+
+	proxyResponse := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Disposition":       []string{`inline; filename="object"`},
+			objectContentDispositionKey: []string{"attachment"},
+			"X-Amz-Meta-Color":          []string{"blue"},
+			"X-Seaweed-Cache-Control":   []string{"max-age=3600"},
+			"X-Seaweed-Expires":         []string{"Wed, 21 Oct 2026 07:28:00 GMT"},
+		},
+		Body: ioutil.NopCloser(strings.NewReader("data")),
+	}
+	w := httptest.NewRecorder()
+	s3a.passThroughObjectResponse(r, nil)(proxyResponse, w)
+
+	if w.Header().Get("Content-Disposition") != "attachment" {
+		t.Errorf("Content-Disposition %q", w.Header().Get("Content-Disposition"))
+	}
+	if w.Header().Get(objectContentDispositionKey) != "" {
+		t.Errorf("the kept content disposition is returned")
+	}
+	if w.Header().Get("X-Amz-Meta-Color") != "blue" {
+		t.Errorf("X-Amz-Meta-Color %q", w.Header().Get("X-Amz-Meta-Color"))
+	}
+	if w.Header().Get("Cache-Control") != "max-age=3600" || w.Header().Get("Expires") != "Wed, 21 Oct 2026 07:28:00 GMT" || w.Header().Get("X-Seaweed-Cache-Control") != "" {
+		t.Errorf("unexpected headers %v", w.Header())
+	}
+
+	// the response-* query parameters override the kept headers
+	r = httptest.NewRequest("GET", "/bucket/object?response-cache-control=no-cache&response-content-type=text%2Fcsv&response-content-language=fr", nil)
+	proxyResponse.Body = ioutil.NopCloser(strings.NewReader("data"))
+	w = httptest.NewRecorder()
+	s3a.passThroughObjectResponse(r, nil)(proxyResponse, w)
+	if w.Header().Get("Cache-Control") != "no-cache" || w.Header().Get("Content-Type") != "text/csv" || w.Header().Get("Content-Language") != "fr" {
+		t.Errorf("unexpected overridden headers %v", w.Header())
+	}
+	if w.Header().Get("Expires") != "Wed, 21 Oct 2026 07:28:00 GMT" {
+		t.Errorf("Expires %q", w.Header().Get("Expires"))
+	}
+}
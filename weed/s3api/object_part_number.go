@@ -0,0 +1,107 @@
+package s3api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObject.html#API_GetObject_RequestSyntax
+
+const AmzMpPartsCount = "X-Amz-Mp-Parts-Count"
+
+// objectPartRange is the byte range of the part asked by the partNumber query parameter
+type objectPartRange struct {
+	partsCount int // 0 if the object is not uploaded in parts
+	start      int64
+	end        int64 // inclusive
+	objectSize int64
+	etag       string
+}
+
+// findPartRange locates the part in the object. An object not uploaded in parts is one single part.
+func findPartRange(entry *filer_pb.Entry, partNumber int) (*objectPartRange, ErrorCode) {
+	objectSize := int64(filer2.FileSize(entry))
+	parts := loadObjectParts(entry.Extended)
+	if len(parts) == 0 {
+		if partNumber != 1 {
+			return nil, ErrInvalidPartNumber
+		}
+		return &objectPartRange{start: 0, end: objectSize - 1, objectSize: objectSize}, ErrNone
+	}
+	var offset int64
+	for _, part := range parts {
+		if part.partNumber == partNumber && part.size > 0 {
+			return &objectPartRange{
+				partsCount: len(parts),
+				start:      offset,
+				end:        offset + part.size - 1,
+				objectSize: objectSize,
+				etag:       part.etag,
+			}, ErrNone
+		}
+		offset += part.size
+	}
+	return nil, ErrInvalidPartNumber
+}
+
+// partNumberRange reads the partNumber query parameter, and asks the filer for the part by the Range header.
+// The part is looked up in the object, or in the version given by the versionId query parameter.
+func (s3a *S3ApiServer) partNumberRange(r *http.Request, bucket, object string) (*objectPartRange, ErrorCode) {
+
+	value := r.URL.Query().Get("partNumber")
+	if value == "" {
+		return nil, ErrNone
+	}
+	partNumber, err := strconv.Atoi(value)
+	if err != nil || partNumber < 1 || partNumber > globalMaxPartID {
+		return nil, ErrPartNumberOutOfRange
+	}
+	if r.Header.Get("Range") != "" {
+		return nil, ErrPartNumberWithRange
+	}
+
+	var entry *filer_pb.Entry
+	if versionId := r.URL.Query().Get("versionId"); versionId != "" {
+		_, entry, err = s3a.findVersion(bucket, object, versionId)
+	} else {
+		_, _, entry, err = s3a.objectEntry(bucket, object)
+	}
+	if err != nil {
+		glog.Errorf("lookup object %s%s: %v", bucket, object, err)
+		return nil, ErrInternalError
+	}
+	if entry == nil || entry.IsDirectory || isDeleteMarker(entry) {
+		// the filer responds the object is not found
+		return nil, ErrNone
+	}
+
+	part, code := findPartRange(entry, partNumber)
+	if code != ErrNone {
+		return nil, code
+	}
+	if part.partsCount > 0 {
+		r.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", part.start, part.end))
+	}
+	return part, ErrNone
+}
+
+// setPartHeaders describes the part of a multipart object in the response.
+// The filer responds HEAD requests without the range, so the range of the part is set here.
+func (part *objectPartRange) setPartHeaders(r *http.Request, w http.ResponseWriter, statusCode int) int {
+	if part == nil || part.partsCount == 0 {
+		return statusCode
+	}
+	w.Header().Set(AmzMpPartsCount, strconv.Itoa(part.partsCount))
+	setEtag(w, part.etag)
+	if r.Method == "HEAD" && statusCode == http.StatusOK {
+		w.Header().Set("Content-Length", strconv.FormatInt(part.end-part.start+1, 10))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", part.start, part.end, part.objectSize))
+		return http.StatusPartialContent
+	}
+	return statusCode
+}
@@ -0,0 +1,111 @@
+package s3api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// startFakeFilerContent serves the content of the entries, and like the filer, answers HEAD requests without the range
+func startFakeFilerContent(entries map[util.FullPath]*filer_pb.Entry) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry, found := entries[util.FullPath(r.URL.Path)]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == "HEAD" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(entry.Content)))
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(entry.Content))
+	}))
+}
+
+func getObjectPart(s3a *S3ApiServer, method, object, query string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/bucket/"+object+"?"+query, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": object})
+	w := httptest.NewRecorder()
+	if method == "HEAD" {
+		s3a.HeadObjectHandler(w, r)
+	} else {
+		s3a.GetObjectHandler(w, r)
+	}
+	return w
+}
+
+func TestGetObjectPartNumber(t *testing.T) {
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket": {Name: "bucket", IsDirectory: true},
+		"/buckets/bucket/multipart": {Name: "multipart", Content: []byte("aaaaabbbcccc"), Extended: map[string][]byte{
+			objectPartsKey: []byte("1:5::etag1,2:3::etag2,3:4::etag3"),
+		}},
+		"/buckets/bucket/single": {Name: "single", Content: []byte("0123456789")},
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+	filer := startFakeFilerContent(entries)
+	defer filer.Close()
+	s3a.option.Filer = strings.TrimPrefix(filer.URL, "http://")
+
+	w := getObjectPart(s3a, "GET", "multipart", "partNumber=2", nil)
+	if w.Code != http.StatusPartialContent || w.Body.String() != "bbb" {
+		t.Fatalf("get part 2: %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Range") != "bytes 5-7/12" || w.Header().Get(AmzMpPartsCount) != "3" || w.Header().Get("ETag") != "\"etag2\"" {
+		t.Errorf("get part 2 headers %v", w.Header())
+	}
+
+	w = getObjectPart(s3a, "HEAD", "multipart", "partNumber=3", nil)
+	if w.Code != http.StatusPartialContent || w.Header().Get("Content-Length") != "4" || w.Header().Get("Content-Range") != "bytes 8-11/12" {
+		t.Errorf("head part 3: %d %v", w.Code, w.Header())
+	}
+
+	w = getObjectPart(s3a, "GET", "single", "partNumber=1", nil)
+	if w.Code != http.StatusOK || w.Body.String() != "0123456789" || w.Header().Get(AmzMpPartsCount) != "" {
+		t.Errorf("get part 1 of a single part object: %d %q %v", w.Code, w.Body.String(), w.Header())
+	}
+
+	tests := []struct {
+		object string
+		query  string
+		header http.Header
+		code   string
+	}{
+		{"multipart", "partNumber=4", nil, "InvalidPartNumber"},
+		{"single", "partNumber=2", nil, "InvalidPartNumber"},
+		{"multipart", "partNumber=0", nil, "InvalidArgument"},
+		{"multipart", "partNumber=x", nil, "InvalidArgument"},
+		{"multipart", "partNumber=1", http.Header{"Range": []string{"bytes=0-1"}}, "InvalidRequest"},
+	}
+	for _, tt := range tests {
+		w := getObjectPart(s3a, "GET", tt.object, tt.query, tt.header)
+		if !strings.Contains(w.Body.String(), "<Code>"+tt.code+"</Code>") {
+			t.Errorf("get %s?%s: %d %s, expect %s", tt.object, tt.query, w.Code, w.Body.String(), tt.code)
+		}
+	}
+}
+
+func TestObjectPartsEtag(t *testing.T) {
+	extended := make(map[string][]byte)
+	objectParts{{partNumber: 1, size: 5, etag: "e1"}, {partNumber: 2, size: 3, checksum: "c2", etag: "e2"}, {partNumber: 3, size: 1}}.saveTo(extended)
+	if string(extended[objectPartsKey]) != "1:5::e1,2:3:c2:e2,3:1" {
+		t.Fatalf("saved parts %s", extended[objectPartsKey])
+	}
+	parts := loadObjectParts(extended)
+	if len(parts) != 3 || parts[0].checksum != "" || parts[0].etag != "e1" || parts[1].checksum != "c2" || parts[1].etag != "e2" || parts[2].etag != "" {
+		t.Errorf("loaded parts %+v", parts)
+	}
+}
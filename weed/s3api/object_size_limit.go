@@ -0,0 +1,101 @@
+package s3api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// The objects larger than the max object size are rejected, and the objects larger than the multipart threshold
+// can only be uploaded in parts. The limits of the s3 server can be overridden by the bucket entry.
+
+const bucketSizeLimitCacheTTL = 5 * time.Second
+
+type objectSizeLimits struct {
+	maxObjectSize      int64
+	multipartThreshold int64
+}
+
+// singlePutLimit is the max size of an object uploaded by one request, 0 for unlimited
+func (limits *objectSizeLimits) singlePutLimit() int64 {
+	if limits.multipartThreshold > 0 && (limits.maxObjectSize <= 0 || limits.multipartThreshold < limits.maxObjectSize) {
+		return limits.multipartThreshold
+	}
+	return limits.maxObjectSize
+}
+
+func (limits *objectSizeLimits) limit(singlePut bool) int64 {
+	if singlePut {
+		return limits.singlePutLimit()
+	}
+	return limits.maxObjectSize
+}
+
+// check returns ErrEntityTooLarge if the object of the size exceeds the limits
+func (limits *objectSizeLimits) check(size int64, singlePut bool) ErrorCode {
+	if limit := limits.limit(singlePut); limit > 0 && size > limit {
+		return ErrEntityTooLarge
+	}
+	return ErrNone
+}
+
+func (s3a *S3ApiServer) getObjectSizeLimits(bucket string) (*objectSizeLimits, error) {
+
+	if item := s3a.sizeLimitCache.Get(bucket); item != nil && !item.Expired() {
+		return item.Value().(*objectSizeLimits), nil
+	}
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		return nil, err
+	}
+	limits := &objectSizeLimits{
+		maxObjectSize:      s3a.option.MaxObjectSize,
+		multipartThreshold: s3a.option.MultipartThreshold,
+	}
+	if entry != nil {
+		if size, err := strconv.ParseInt(string(entry.Extended[filer2.BucketMaxObjectSizeKey]), 10, 64); err == nil && size > 0 {
+			limits.maxObjectSize = size
+		}
+		if size, err := strconv.ParseInt(string(entry.Extended[filer2.BucketMultipartThresholdKey]), 10, 64); err == nil && size > 0 {
+			limits.multipartThreshold = size
+		}
+	}
+
+	s3a.sizeLimitCache.Set(bucket, limits, bucketSizeLimitCacheTTL)
+	return limits, nil
+}
+
+// checkObjectSize checks the object size against the limits of the bucket
+func (s3a *S3ApiServer) checkObjectSize(bucket string, size int64, singlePut bool) ErrorCode {
+	limits, err := s3a.getObjectSizeLimits(bucket)
+	if err != nil {
+		glog.Errorf("load size limits of bucket %s: %v", bucket, err)
+		return ErrInternalError
+	}
+	return limits.check(size, singlePut)
+}
+
+// checkRequestSize checks the declared size of a PUT request before reading the body.
+// The size has to be declared if the request is limited.
+func (s3a *S3ApiServer) checkRequestSize(r *http.Request, bucket string, singlePut bool) ErrorCode {
+	limits, err := s3a.getObjectSizeLimits(bucket)
+	if err != nil {
+		glog.Errorf("load size limits of bucket %s: %v", bucket, err)
+		return ErrInternalError
+	}
+	if limits.limit(singlePut) <= 0 {
+		return ErrNone
+	}
+	size := declaredObjectSize(r)
+	if size < 0 {
+		if r.ContentLength != 0 {
+			return ErrMissingContentLength
+		}
+		size = 0
+	}
+	return limits.check(size, singlePut)
+}
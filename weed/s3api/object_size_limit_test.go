@@ -0,0 +1,70 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/karlseguin/ccache"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestObjectSizeLimits(t *testing.T) {
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{
+		"/buckets/default": {Name: "default", IsDirectory: true},
+		"/buckets/limited": {Name: "limited", IsDirectory: true, Extended: map[string][]byte{
+			filer2.BucketMaxObjectSizeKey:      []byte("1000"),
+			filer2.BucketMultipartThresholdKey: []byte("100"),
+		}},
+	})
+	defer stop()
+	s3a.option.MaxObjectSize = 10000
+	s3a.sizeLimitCache = ccache.New(ccache.Configure())
+
+	request := func(size int64, decoded string) *http.Request {
+		r := httptest.NewRequest("PUT", "/bucket/object", strings.NewReader(""))
+		r.ContentLength = size
+		if decoded != "" {
+			r.Header.Set("X-Amz-Decoded-Content-Length", decoded)
+		}
+		return r
+	}
+
+	tests := []struct {
+		bucket    string
+		request   *http.Request
+		singlePut bool
+		expected  ErrorCode
+	}{
+		{"default", request(10000, ""), true, ErrNone},
+		{"default", request(10001, ""), true, ErrEntityTooLarge},
+		{"default", request(10001, ""), false, ErrEntityTooLarge},
+		{"default", request(-1, ""), true, ErrMissingContentLength},
+		{"default", request(0, ""), true, ErrNone},
+		{"limited", request(100, ""), true, ErrNone},
+		{"limited", request(101, ""), true, ErrEntityTooLarge},
+		{"limited", request(101, ""), false, ErrNone},
+		{"limited", request(1001, ""), false, ErrEntityTooLarge},
+		{"limited", request(-1, "101"), true, ErrEntityTooLarge},
+		{"limited", request(200, "90"), true, ErrNone},
+	}
+	for i, test := range tests {
+		if errCode := s3a.checkRequestSize(test.request, test.bucket, test.singlePut); errCode != test.expected {
+			t.Errorf("%d %s: got %v, expecting %v", i, test.bucket, errCode, test.expected)
+		}
+	}
+
+	if errCode := s3a.checkObjectSize("limited", 1001, false); errCode != ErrEntityTooLarge {
+		t.Errorf("completed upload over the max object size: %v", errCode)
+	}
+	if limit := (&objectSizeLimits{maxObjectSize: 100, multipartThreshold: 1000}).singlePutLimit(); limit != 100 {
+		t.Errorf("single put limit %d", limit)
+	}
+	if limit := (&objectSizeLimits{multipartThreshold: 1000}).singlePutLimit(); limit != 1000 {
+		t.Errorf("single put limit %d", limit)
+	}
+}
@@ -0,0 +1,534 @@
+package s3api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/using-iam-policies.html
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements_condition_operators.html
+
+const (
+	// extended attribute key on the bucket entry, the policy json as it was put
+	bucketPolicyKey = "s3-policy"
+
+	maxBucketPolicySize = 20 * 1024
+
+	policyEffectAllow = "Allow"
+	policyEffectDeny  = "Deny"
+
+	s3ResourcePrefix = "arn:aws:s3:::"
+)
+
+type BucketPolicy struct {
+	Version    string           `json:"Version,omitempty"`
+	Id         string           `json:"Id,omitempty"`
+	Statements PolicyStatements `json:"Statement"`
+}
+
+type PolicyStatement struct {
+	Sid        string                             `json:"Sid,omitempty"`
+	Effect     string                             `json:"Effect"`
+	Principal  *PolicyPrincipal                   `json:"Principal"`
+	Actions    PolicyValues                       `json:"Action"`
+	Resources  PolicyValues                       `json:"Resource"`
+	Conditions map[string]map[string]PolicyValues `json:"Condition,omitempty"`
+}
+
+// PolicyStatements is a list of statements, or a single statement
+type PolicyStatements []PolicyStatement
+
+func (statements *PolicyStatements) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var statement PolicyStatement
+		if err := strictUnmarshal(data, &statement); err != nil {
+			return err
+		}
+		*statements = PolicyStatements{statement}
+		return nil
+	}
+	var list []PolicyStatement
+	if err := strictUnmarshal(data, &list); err != nil {
+		return err
+	}
+	*statements = list
+	return nil
+}
+
+// PolicyPrincipal is either "*" for everyone, including anonymous requests, or a map like {"AWS": ["user_name"]}
+type PolicyPrincipal struct {
+	everyone bool
+	values   map[string]PolicyValues
+}
+
+func (principal *PolicyPrincipal) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s != "*" {
+			return fmt.Errorf("invalid principal %s", s)
+		}
+		principal.everyone = true
+		return nil
+	}
+	return json.Unmarshal(data, &principal.values)
+}
+
+// matches the identity name, an empty name for anonymous requests.
+// An AWS principal is the identity name, or an ARN ending with user/<name>, or role/<name> for temporary credentials
+func (principal *PolicyPrincipal) matches(name string) bool {
+	if principal.everyone {
+		return true
+	}
+	for _, value := range principal.values["AWS"] {
+		if value == "*" {
+			return true
+		}
+		if name != "" && (value == name || strings.HasSuffix(value, ":user/"+name) || strings.HasSuffix(value, ":role/"+name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyValues is a list of strings, or a single string, number or boolean
+type PolicyValues []string
+
+func (values *PolicyValues) UnmarshalJSON(data []byte) error {
+	var list []interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		var single interface{}
+		if err := json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		list = []interface{}{single}
+	}
+	*values = nil
+	for _, v := range list {
+		switch v.(type) {
+		case string, bool, float64:
+			*values = append(*values, fmt.Sprint(v))
+		default:
+			return fmt.Errorf("invalid policy value %v", v)
+		}
+	}
+	return nil
+}
+
+func strictUnmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func parseBucketPolicy(data []byte) (*BucketPolicy, error) {
+	policy := &BucketPolicy{}
+	if err := strictUnmarshal(data, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+var policyConditionOperators = map[string]func(values, requestValues []string) bool{
+	"StringEquals": func(values, requestValues []string) bool {
+		return anyValueMatches(values, requestValues, func(v, r string) bool { return v == r })
+	},
+	"StringNotEquals": func(values, requestValues []string) bool {
+		return !anyValueMatches(values, requestValues, func(v, r string) bool { return v == r })
+	},
+	"StringEqualsIgnoreCase": func(values, requestValues []string) bool {
+		return anyValueMatches(values, requestValues, strings.EqualFold)
+	},
+	"StringNotEqualsIgnoreCase": func(values, requestValues []string) bool {
+		return !anyValueMatches(values, requestValues, strings.EqualFold)
+	},
+	"StringLike": func(values, requestValues []string) bool {
+		return anyValueMatches(values, requestValues, wildcardMatch)
+	},
+	"StringNotLike": func(values, requestValues []string) bool {
+		return !anyValueMatches(values, requestValues, wildcardMatch)
+	},
+	"IpAddress": func(values, requestValues []string) bool {
+		return anyValueMatches(values, requestValues, ipMatches)
+	},
+	"NotIpAddress": func(values, requestValues []string) bool {
+		return !anyValueMatches(values, requestValues, ipMatches)
+	},
+	"Bool": func(values, requestValues []string) bool {
+		return anyValueMatches(values, requestValues, strings.EqualFold)
+	},
+}
+
+func anyValueMatches(values, requestValues []string, match func(value, requestValue string) bool) bool {
+	for _, requestValue := range requestValues {
+		for _, value := range values {
+			if match(value, requestValue) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ipMatches(cidr, ip string) bool {
+	if !strings.Contains(cidr, "/") {
+		return net.ParseIP(cidr).Equal(net.ParseIP(ip))
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	return err == nil && network.Contains(net.ParseIP(ip))
+}
+
+// wildcardMatch matches * to any sequence of characters, including "/", and ? to any single character
+func wildcardMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if wildcardMatch(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return len(s) == 0
+}
+
+// validate checks the policy of the bucket. Only resources of the bucket can be granted.
+func (policy *BucketPolicy) validate(bucket string) ErrorCode {
+	if policy.Version != "" && policy.Version != "2012-10-17" && policy.Version != "2008-10-17" {
+		return ErrMalformedPolicy
+	}
+	if len(policy.Statements) == 0 {
+		return ErrMalformedPolicy
+	}
+	for _, statement := range policy.Statements {
+		if statement.Effect != policyEffectAllow && statement.Effect != policyEffectDeny {
+			return ErrMalformedPolicy
+		}
+		if statement.Principal == nil || len(statement.Actions) == 0 || len(statement.Resources) == 0 {
+			return ErrMalformedPolicy
+		}
+		for _, action := range statement.Actions {
+			if action != "*" && !strings.HasPrefix(action, "s3:") {
+				return ErrMalformedPolicy
+			}
+		}
+		for _, resource := range statement.Resources {
+			if resource != s3ResourcePrefix+bucket && !strings.HasPrefix(resource, s3ResourcePrefix+bucket+"/") {
+				return ErrMalformedPolicy
+			}
+		}
+		for operator, conditions := range statement.Conditions {
+			if _, found := policyConditionOperators[strings.TrimSuffix(operator, "IfExists")]; !found {
+				return ErrMalformedPolicy
+			}
+			if strings.Contains(operator, "IpAddress") {
+				for _, values := range conditions {
+					for _, value := range values {
+						if _, _, err := net.ParseCIDR(value); err != nil && net.ParseIP(value) == nil {
+							return ErrMalformedPolicy
+						}
+					}
+				}
+			}
+		}
+	}
+	return ErrNone
+}
+
+type policyDecision int
+
+const (
+	policyNotApplicable policyDecision = iota
+	policyAllowed
+	policyDenied
+)
+
+// policyRequest is what a request asks for, with the values of the condition keys in lower case
+type policyRequest struct {
+	principal  string
+	action     string
+	resource   string
+	conditions map[string][]string
+}
+
+// evaluate returns policyDenied if any statement denies the request
+func (policy *BucketPolicy) evaluate(request *policyRequest) policyDecision {
+	decision := policyNotApplicable
+	for _, statement := range policy.Statements {
+		if !statement.matches(request) {
+			continue
+		}
+		if statement.Effect == policyEffectDeny {
+			return policyDenied
+		}
+		decision = policyAllowed
+	}
+	return decision
+}
+
+func (statement *PolicyStatement) matches(request *policyRequest) bool {
+	if !statement.Principal.matches(request.principal) {
+		return false
+	}
+	if !anyValueMatches(statement.Actions, []string{strings.ToLower(request.action)}, func(action, requestAction string) bool {
+		return wildcardMatch(strings.ToLower(action), requestAction)
+	}) {
+		return false
+	}
+	if !anyValueMatches(statement.Resources, []string{request.resource}, wildcardMatch) {
+		return false
+	}
+	for operator, conditions := range statement.Conditions {
+		ifExists := strings.HasSuffix(operator, "IfExists")
+		fn := policyConditionOperators[strings.TrimSuffix(operator, "IfExists")]
+		for key, values := range conditions {
+			requestValues, found := request.conditions[strings.ToLower(key)]
+			if !found {
+				// a missing key only satisfies the negated operators
+				if ifExists || strings.Contains(operator, "Not") {
+					continue
+				}
+				return false
+			}
+			if !fn(values, requestValues) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// policyResource is the ARN of the bucket, or of the object if any
+func policyResource(bucket, object string) string {
+	object = strings.TrimPrefix(object, "/")
+	if object == "" {
+		return s3ResourcePrefix + bucket
+	}
+	return s3ResourcePrefix + bucket + "/" + object
+}
+
+// policyAction names the S3 operation of the request, e.g. s3:GetObject.
+// The multiple objects delete is authorized per object, as s3:DeleteObject.
+func policyAction(r *http.Request, object string) string {
+
+	query := r.URL.Query()
+	has := func(name string) bool {
+		_, found := query[name]
+		return found
+	}
+	isObject := strings.TrimPrefix(object, "/") != ""
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if isObject {
+			switch {
+			case has("uploadId"):
+				return "s3:ListMultipartUploadParts"
+			case has("retention"):
+				return "s3:GetObjectRetention"
+			case has("legal-hold"):
+				return "s3:GetObjectLegalHold"
+			case has("tagging"):
+				return "s3:GetObjectTagging"
+			case has("acl"):
+				return "s3:GetObjectAcl"
+			case has("attributes") && has("versionId"):
+				return "s3:GetObjectVersionAttributes"
+			case has("attributes"):
+				return "s3:GetObjectAttributes"
+			case has("versionId"):
+				return "s3:GetObjectVersion"
+			}
+			return "s3:GetObject"
+		}
+		switch {
+		case has("uploads"):
+			return "s3:ListBucketMultipartUploads"
+		case has("versions"):
+			return "s3:ListBucketVersions"
+		case has("versioning"):
+			return "s3:GetBucketVersioning"
+		case has("lifecycle"):
+			return "s3:GetLifecycleConfiguration"
+		case has("encryption"):
+			return "s3:GetEncryptionConfiguration"
+		case has("notification"):
+			return "s3:GetBucketNotification"
+		case has("policy"):
+			return "s3:GetBucketPolicy"
+		case has("object-lock"):
+			return "s3:GetBucketObjectLockConfiguration"
+		case has("cors"):
+			return "s3:GetBucketCORS"
+		case has("logging"):
+			return "s3:GetBucketLogging"
+		case has("website"):
+			return "s3:GetBucketWebsite"
+		case has("requestPayment"):
+			return "s3:GetBucketRequestPayment"
+		case has("replication"):
+			return "s3:GetReplicationConfiguration"
+		case has("location"):
+			return "s3:GetBucketLocation"
+		case has("acl"):
+			return "s3:GetBucketAcl"
+		}
+		return "s3:ListBucket"
+	case http.MethodPut:
+		if isObject {
+			switch {
+			case has("retention"):
+				return "s3:PutObjectRetention"
+			case has("legal-hold"):
+				return "s3:PutObjectLegalHold"
+			case has("tagging"):
+				return "s3:PutObjectTagging"
+			case has("acl"):
+				return "s3:PutObjectAcl"
+			}
+			return "s3:PutObject"
+		}
+		switch {
+		case has("versioning"):
+			return "s3:PutBucketVersioning"
+		case has("lifecycle"):
+			return "s3:PutLifecycleConfiguration"
+		case has("encryption"):
+			return "s3:PutEncryptionConfiguration"
+		case has("notification"):
+			return "s3:PutBucketNotification"
+		case has("policy"):
+			return "s3:PutBucketPolicy"
+		case has("object-lock"):
+			return "s3:PutBucketObjectLockConfiguration"
+		case has("cors"):
+			return "s3:PutBucketCORS"
+		case has("logging"):
+			return "s3:PutBucketLogging"
+		case has("website"):
+			return "s3:PutBucketWebsite"
+		case has("requestPayment"):
+			return "s3:PutBucketRequestPayment"
+		case has("replication"):
+			return "s3:PutReplicationConfiguration"
+		case has("acl"):
+			return "s3:PutBucketAcl"
+		}
+		return "s3:CreateBucket"
+	case http.MethodPost:
+		if isObject {
+			if has("select") {
+				return "s3:GetObject"
+			}
+			return "s3:PutObject"
+		}
+		if has("delete") {
+			return ""
+		}
+		return "s3:PutObject"
+	case http.MethodDelete:
+		if isObject {
+			switch {
+			case has("uploadId"):
+				return "s3:AbortMultipartUpload"
+			case has("tagging"):
+				return "s3:DeleteObjectTagging"
+			case has("versionId"):
+				return "s3:DeleteObjectVersion"
+			}
+			return "s3:DeleteObject"
+		}
+		switch {
+		case has("lifecycle"):
+			return "s3:PutLifecycleConfiguration"
+		case has("encryption"):
+			return "s3:PutEncryptionConfiguration"
+		case has("policy"):
+			return "s3:DeleteBucketPolicy"
+		case has("cors"):
+			return "s3:PutBucketCORS"
+		case has("website"):
+			return "s3:DeleteBucketWebsite"
+		case has("replication"):
+			return "s3:PutReplicationConfiguration"
+		}
+		return "s3:DeleteBucket"
+	}
+	return ""
+}
+
+// policyConditionValues are the condition keys of the request, in lower case
+func policyConditionValues(r *http.Request, identity *Identity) map[string][]string {
+
+	values := make(map[string][]string)
+
+	sourceIp := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		sourceIp = host
+	}
+	values["aws:sourceip"] = []string{sourceIp}
+	values["aws:securetransport"] = []string{fmt.Sprint(r.TLS != nil)}
+	if identity != nil {
+		values["aws:username"] = []string{identity.Name}
+	}
+	for key, header := range map[string]string{
+		"aws:useragent":                   "User-Agent",
+		"aws:referer":                     "Referer",
+		"s3:x-amz-acl":                    "X-Amz-Acl",
+		"s3:x-amz-copy-source":            "X-Amz-Copy-Source",
+		"s3:x-amz-metadata-directive":     "X-Amz-Metadata-Directive",
+		"s3:x-amz-server-side-encryption": AmzServerSideEncryption,
+		"s3:x-amz-storage-class":          "X-Amz-Storage-Class",
+	} {
+		if value := r.Header.Get(header); value != "" {
+			values[key] = []string{value}
+		}
+	}
+	query := r.URL.Query()
+	for key, param := range map[string]string{
+		"s3:prefix":    "prefix",
+		"s3:delimiter": "delimiter",
+		"s3:max-keys":  "max-keys",
+		"s3:versionid": "versionId",
+	} {
+		if _, found := query[param]; found {
+			values[key] = []string{query.Get(param)}
+		}
+	}
+	if tags, errCode := prepareObjectTags(r); errCode == ErrNone && tags != nil {
+		values["s3:requestobjecttagkeys"] = tags.sortedKeys()
+		for key, value := range tags {
+			values["s3:requestobjecttag/"+strings.ToLower(key)] = []string{value}
+		}
+	}
+
+	return values
+}
+
+// usesExistingObjectTags tells whether the conditions need the tags of the requested object
+func (policy *BucketPolicy) usesExistingObjectTags() bool {
+	for _, statement := range policy.Statements {
+		for _, conditions := range statement.Conditions {
+			for key := range conditions {
+				if strings.HasPrefix(strings.ToLower(key), "s3:existingobjecttag/") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
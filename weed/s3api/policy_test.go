@@ -0,0 +1,291 @@
+package s3api
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+const testBucketPolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "PublicRead",
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": ["s3:GetObject"],
+      "Resource": "arn:aws:s3:::bucket1/public/*"
+    },
+    {
+      "Effect": "Allow",
+      "Principal": {"AWS": ["arn:aws:iam::123456789012:user/writer"]},
+      "Action": "s3:Put*",
+      "Resource": "arn:aws:s3:::bucket1/*"
+    },
+    {
+      "Effect": "Deny",
+      "Principal": {"AWS": "*"},
+      "Action": "s3:*",
+      "Resource": ["arn:aws:s3:::bucket1/public/secret*"]
+    },
+    {
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": "s3:ListBucket",
+      "Resource": "arn:aws:s3:::bucket1",
+      "Condition": {
+        "IpAddress": {"aws:SourceIp": "10.0.0.0/8"},
+        "StringEquals": {"s3:prefix": ["public/", "shared/"]}
+      }
+    }
+  ]
+}`
+
+func TestBucketPolicyEvaluate(t *testing.T) {
+
+	policy, err := parseBucketPolicy([]byte(testBucketPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errCode := policy.validate("bucket1"); errCode != ErrNone {
+		t.Fatalf("validate: %v", errCode)
+	}
+
+	tests := []struct {
+		principal  string
+		action     string
+		resource   string
+		conditions map[string][]string
+		expected   policyDecision
+	}{
+		{"", "s3:GetObject", "arn:aws:s3:::bucket1/public/a.txt", nil, policyAllowed},
+		{"", "s3:GetObject", "arn:aws:s3:::bucket1/private/a.txt", nil, policyNotApplicable},
+		{"", "s3:PutObject", "arn:aws:s3:::bucket1/public/a.txt", nil, policyNotApplicable},
+		{"writer", "s3:PutObject", "arn:aws:s3:::bucket1/private/a.txt", nil, policyAllowed},
+		{"writer", "s3:PutObjectRetention", "arn:aws:s3:::bucket1/a.txt", nil, policyAllowed},
+		{"reader", "s3:PutObject", "arn:aws:s3:::bucket1/a.txt", nil, policyNotApplicable},
+		// explicit deny wins
+		{"", "s3:GetObject", "arn:aws:s3:::bucket1/public/secret.txt", nil, policyDenied},
+		{"writer", "s3:PutObject", "arn:aws:s3:::bucket1/public/secret.txt", nil, policyDenied},
+		{"", "s3:ListBucket", "arn:aws:s3:::bucket1", map[string][]string{"aws:sourceip": {"10.1.2.3"}, "s3:prefix": {"public/"}}, policyAllowed},
+		{"", "s3:ListBucket", "arn:aws:s3:::bucket1", map[string][]string{"aws:sourceip": {"192.168.1.1"}, "s3:prefix": {"public/"}}, policyNotApplicable},
+		{"", "s3:ListBucket", "arn:aws:s3:::bucket1", map[string][]string{"aws:sourceip": {"10.1.2.3"}, "s3:prefix": {"private/"}}, policyNotApplicable},
+		{"", "s3:ListBucket", "arn:aws:s3:::bucket1", map[string][]string{"aws:sourceip": {"10.1.2.3"}}, policyNotApplicable},
+	}
+	for _, test := range tests {
+		decision := policy.evaluate(&policyRequest{
+			principal:  test.principal,
+			action:     test.action,
+			resource:   test.resource,
+			conditions: test.conditions,
+		})
+		if decision != test.expected {
+			t.Errorf("%q %s %s %v: got %v, expecting %v", test.principal, test.action, test.resource, test.conditions, decision, test.expected)
+		}
+	}
+}
+
+func TestBucketPolicyValidate(t *testing.T) {
+
+	tests := []struct {
+		policy   string
+		expected ErrorCode
+	}{
+		{`{"Statement": {"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*"}}`, ErrNone},
+		{`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*",
+			"Condition": {"NotIpAddress": {"aws:SourceIp": ["192.168.0.0/16", "10.1.1.1"]}, "BoolIfExists": {"aws:SecureTransport": false}}}]}`, ErrNone},
+		{`{"Version": "2000-01-01", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*"}]}`, ErrMalformedPolicy},
+		{`{"Statement": []}`, ErrMalformedPolicy},
+		{`{"Statement": [{"Effect": "Permit", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*"}]}`, ErrMalformedPolicy},
+		{`{"Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*"}]}`, ErrMalformedPolicy},
+		{`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "iam:CreateUser", "Resource": "arn:aws:s3:::bucket1/*"}]}`, ErrMalformedPolicy},
+		{`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket2/*"}]}`, ErrMalformedPolicy},
+		{`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*",
+			"Condition": {"DateGreaterThan": {"aws:CurrentTime": "2020-01-01T00:00:00Z"}}}]}`, ErrMalformedPolicy},
+		{`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*",
+			"Condition": {"IpAddress": {"aws:SourceIp": "not an ip"}}}]}`, ErrMalformedPolicy},
+	}
+	for _, test := range tests {
+		policy, err := parseBucketPolicy([]byte(test.policy))
+		if err != nil {
+			t.Fatalf("parse %s: %v", test.policy, err)
+		}
+		if errCode := policy.validate("bucket1"); errCode != test.expected {
+			t.Errorf("validate %s: got %v, expecting %v", test.policy, errCode, test.expected)
+		}
+	}
+
+	for _, data := range []string{
+		`{"Statement": [{"Effect": "Allow", "Principal": "*", "NotAction": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*"}]}`,
+		`{"Statement": [{"Effect": "Allow", "Principal": "someone", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket1/*"}]}`,
+	} {
+		if _, err := parseBucketPolicy([]byte(data)); err == nil {
+			t.Errorf("parse unsupported %s", data)
+		}
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		s        string
+		expected bool
+	}{
+		{"*", "", true},
+		{"a/*", "a/b/c", true},
+		{"a/*/c", "a/b/x/c", true},
+		{"a/?", "a/b", true},
+		{"a/?", "a/bc", false},
+		{"a*b", "acb", true},
+		{"a*b", "acbd", false},
+	}
+	for _, test := range tests {
+		if matched := wildcardMatch(test.pattern, test.s); matched != test.expected {
+			t.Errorf("%s %s: %v", test.pattern, test.s, matched)
+		}
+	}
+}
+
+func TestPolicyAction(t *testing.T) {
+	tests := []struct {
+		method   string
+		query    string
+		object   string
+		expected string
+	}{
+		{"GET", "", "a.txt", "s3:GetObject"},
+		{"GET", "versionId=1", "a.txt", "s3:GetObjectVersion"},
+		{"GET", "attributes", "a.txt", "s3:GetObjectAttributes"},
+		{"GET", "attributes&versionId=1", "a.txt", "s3:GetObjectVersionAttributes"},
+		{"HEAD", "", "", "s3:ListBucket"},
+		{"GET", "list-type=2&prefix=a", "", "s3:ListBucket"},
+		{"GET", "policy", "", "s3:GetBucketPolicy"},
+		{"PUT", "", "a.txt", "s3:PutObject"},
+		{"PUT", "partNumber=1&uploadId=x", "a.txt", "s3:PutObject"},
+		{"PUT", "", "", "s3:CreateBucket"},
+		{"DELETE", "uploadId=x", "a.txt", "s3:AbortMultipartUpload"},
+		{"DELETE", "policy", "", "s3:DeleteBucketPolicy"},
+		{"GET", "cors", "", "s3:GetBucketCORS"},
+		{"DELETE", "cors", "", "s3:PutBucketCORS"},
+		{"GET", "logging", "", "s3:GetBucketLogging"},
+		{"PUT", "logging", "", "s3:PutBucketLogging"},
+		{"GET", "website", "", "s3:GetBucketWebsite"},
+		{"PUT", "website", "", "s3:PutBucketWebsite"},
+		{"DELETE", "website", "", "s3:DeleteBucketWebsite"},
+		{"GET", "requestPayment", "", "s3:GetBucketRequestPayment"},
+		{"PUT", "requestPayment", "", "s3:PutBucketRequestPayment"},
+		{"GET", "replication", "", "s3:GetReplicationConfiguration"},
+		{"DELETE", "replication", "", "s3:PutReplicationConfiguration"},
+		{"POST", "uploads", "a.txt", "s3:PutObject"},
+		{"POST", "delete", "", ""},
+	}
+	for _, test := range tests {
+		r := &http.Request{Method: test.method, URL: &url.URL{RawQuery: test.query}}
+		if action := policyAction(r, test.object); action != test.expected {
+			t.Errorf("%s ?%s %s: %s, expecting %s", test.method, test.query, test.object, action, test.expected)
+		}
+	}
+}
+
+func TestAuthorizeWithBucketPolicy(t *testing.T) {
+
+	policy, err := parseBucketPolicy([]byte(testBucketPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	iam := &IdentityAccessManagement{
+		loadBucketPolicy: func(bucket string) (*BucketPolicy, error) {
+			if bucket == "bucket1" {
+				return policy, nil
+			}
+			return nil, nil
+		},
+	}
+	reader := &requester{identity: &Identity{Name: "reader", Actions: []Action{ACTION_READ}}}
+	writer := &requester{identity: &Identity{Name: "writer"}}
+	anonymous := &requester{}
+
+	tests := []struct {
+		requester *requester
+		action    Action
+		s3Action  string
+		bucket    string
+		object    string
+		expected  ErrorCode
+	}{
+		{reader, ACTION_READ, "s3:GetObject", "bucket1", "private/a.txt", ErrNone},
+		{reader, ACTION_READ, "s3:GetObject", "bucket1", "public/secret.txt", ErrAccessDenied},
+		{reader, ACTION_WRITE, "s3:PutObject", "bucket1", "a.txt", ErrAccessDenied},
+		{writer, ACTION_WRITE, "s3:PutObject", "bucket1", "a.txt", ErrNone},
+		{writer, ACTION_WRITE, "s3:PutObject", "bucket2", "a.txt", ErrAccessDenied},
+		{anonymous, ACTION_READ, "s3:GetObject", "bucket1", "public/a.txt", ErrNone},
+		{anonymous, ACTION_READ, "s3:GetObject", "bucket1", "private/a.txt", ErrAccessDenied},
+		{anonymous, ACTION_READ, "s3:GetObject", "bucket2", "public/a.txt", ErrAccessDenied},
+		{&requester{trusted: true}, ACTION_WRITE, "s3:PutObject", "bucket2", "a.txt", ErrNone},
+		{&requester{trusted: true}, ACTION_READ, "s3:GetObject", "bucket1", "public/secret.txt", ErrAccessDenied},
+	}
+	r := &http.Request{Header: make(http.Header), URL: &url.URL{}, RemoteAddr: "127.0.0.1:12345"}
+	for _, test := range tests {
+		if errCode := iam.authorize(r, test.requester, test.action, test.s3Action, test.bucket, test.object); errCode != test.expected {
+			t.Errorf("%+v %s %s/%s: got %v, expecting %v", test.requester, test.s3Action, test.bucket, test.object, errCode, test.expected)
+		}
+	}
+}
+
+func TestAuthorizeWithObjectTags(t *testing.T) {
+
+	policy, err := parseBucketPolicy([]byte(`{
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": "s3:GetObject",
+      "Resource": "arn:aws:s3:::bucket1/*",
+      "Condition": {"StringEquals": {"s3:ExistingObjectTag/Classification": "public"}}
+    },
+    {
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": "s3:PutObject",
+      "Resource": "arn:aws:s3:::bucket1/*",
+      "Condition": {"StringEquals": {"s3:RequestObjectTag/Project": "x"}}
+    }
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	iam := &IdentityAccessManagement{
+		loadBucketPolicy: func(bucket string) (*BucketPolicy, error) {
+			return policy, nil
+		},
+		loadObjectTags: func(bucket, object, versionId string) (objectTags, error) {
+			if object == "/public.txt" {
+				return objectTags{"Classification": "public"}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	tests := []struct {
+		s3Action string
+		object   string
+		tagging  string
+		expected ErrorCode
+	}{
+		{"s3:GetObject", "/public.txt", "", ErrNone},
+		{"s3:GetObject", "/private.txt", "", ErrAccessDenied},
+		{"s3:PutObject", "/a.txt", "Project=x&b=c", ErrNone},
+		{"s3:PutObject", "/a.txt", "Project=y", ErrAccessDenied},
+		{"s3:PutObject", "/a.txt", "", ErrAccessDenied},
+	}
+	for _, test := range tests {
+		r := &http.Request{Header: make(http.Header), URL: &url.URL{}, RemoteAddr: "127.0.0.1:12345"}
+		if test.tagging != "" {
+			r.Header.Set(AmzTagging, test.tagging)
+		}
+		if errCode := iam.authorize(r, &requester{}, ACTION_READ, test.s3Action, "bucket1", test.object); errCode != test.expected {
+			t.Errorf("%s %s %s: got %v, expecting %v", test.s3Action, test.object, test.tagging, errCode, test.expected)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package s3api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-HTTPPOSTConstructPolicy.html
+
+const (
+	// the size of the form fields other than the file
+	maxPostFormSize = 64 * 1024
+
+	postPolicyExpirationFormat = "2006-01-02T15:04:05.000Z"
+)
+
+type postPolicyCondition struct {
+	operator string // "eq" or "starts-with"
+	field    string // the lower case form field name, without "$"
+	value    string
+}
+
+type postPolicy struct {
+	expiration     time.Time
+	conditions     []postPolicyCondition
+	hasLengthRange bool
+	minLength      int64
+	maxLength      int64
+}
+
+// parsePostPolicy decodes the base64 policy document of the form.
+// A condition is either {"field": "value"}, ["eq", "$field", "value"], ["starts-with", "$field", "prefix"],
+// or ["content-length-range", min, max].
+func parsePostPolicy(encodedPolicy string) (*postPolicy, error) {
+
+	data, err := base64.StdEncoding.DecodeString(encodedPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	var document struct {
+		Expiration string            `json:"expiration"`
+		Conditions []json.RawMessage `json:"conditions"`
+	}
+	if err = json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	policy := &postPolicy{}
+	if policy.expiration, err = time.Parse(postPolicyExpirationFormat, document.Expiration); err != nil {
+		if policy.expiration, err = time.Parse(time.RFC3339, document.Expiration); err != nil {
+			return nil, fmt.Errorf("expiration %q: %v", document.Expiration, err)
+		}
+	}
+
+	for _, raw := range document.Conditions {
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 && raw[0] == '{' {
+			var exact map[string]string
+			if err = json.Unmarshal(raw, &exact); err != nil || len(exact) != 1 {
+				return nil, fmt.Errorf("condition %s", raw)
+			}
+			for field, value := range exact {
+				policy.conditions = append(policy.conditions, postPolicyCondition{"eq", strings.ToLower(strings.TrimPrefix(field, "$")), value})
+			}
+			continue
+		}
+
+		var values []interface{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err = decoder.Decode(&values); err != nil || len(values) != 3 {
+			return nil, fmt.Errorf("condition %s", raw)
+		}
+		operator, _ := values[0].(string)
+		switch strings.ToLower(operator) {
+		case "eq", "starts-with":
+			field, ok1 := values[1].(string)
+			value, ok2 := values[2].(string)
+			if !ok1 || !ok2 || !strings.HasPrefix(field, "$") {
+				return nil, fmt.Errorf("condition %s", raw)
+			}
+			policy.conditions = append(policy.conditions, postPolicyCondition{strings.ToLower(operator), strings.ToLower(field[1:]), value})
+		case "content-length-range":
+			min, err1 := jsonInt64(values[1])
+			max, err2 := jsonInt64(values[2])
+			if err1 != nil || err2 != nil || min < 0 || min > max {
+				return nil, fmt.Errorf("condition %s", raw)
+			}
+			policy.hasLengthRange, policy.minLength, policy.maxLength = true, min, max
+		default:
+			return nil, fmt.Errorf("condition %s", raw)
+		}
+	}
+
+	return policy, nil
+}
+
+func jsonInt64(v interface{}) (int64, error) {
+	if n, ok := v.(json.Number); ok {
+		return n.Int64()
+	}
+	return 0, fmt.Errorf("not a number: %v", v)
+}
+
+// postPolicyExemptFields are not required to be covered by the policy conditions
+var postPolicyExemptFields = map[string]bool{
+	"policy":          true,
+	"x-amz-signature": true,
+	"file":            true,
+}
+
+// checkForm verifies the form fields and the bucket against the conditions.
+// Every condition must be met, and every form field must be covered by a condition.
+func (policy *postPolicy) checkForm(bucket string, form http.Header, now time.Time) ErrorCode {
+
+	if !now.Before(policy.expiration) {
+		return ErrPostPolicyExpired
+	}
+
+	covered := make(map[string]bool)
+	for _, condition := range policy.conditions {
+		var value string
+		if condition.field == "bucket" {
+			value = bucket
+		} else {
+			value = form.Get(condition.field)
+		}
+		switch condition.operator {
+		case "eq":
+			if value != condition.value {
+				return ErrPostPolicyConditionFailed
+			}
+		case "starts-with":
+			if !strings.HasPrefix(value, condition.value) {
+				return ErrPostPolicyConditionFailed
+			}
+		}
+		covered[condition.field] = true
+	}
+
+	for name := range form {
+		field := strings.ToLower(name)
+		if postPolicyExemptFields[field] || strings.HasPrefix(field, "x-ignore-") || covered[field] {
+			continue
+		}
+		return ErrPostPolicyConditionFailed
+	}
+
+	return ErrNone
+}
+
+// lengthRangeReader fails the upload of a file out of the content-length-range
+type lengthRangeReader struct {
+	reader   io.Reader
+	min, max int64
+	count    int64
+	code     ErrorCode
+}
+
+var errContentLengthRange = fmt.Errorf("file size out of the content-length-range")
+
+func (r *lengthRangeReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	r.count += int64(n)
+	if r.count > r.max {
+		r.code = ErrEntityTooLarge
+		return n, errContentLengthRange
+	}
+	if err == io.EOF && r.count < r.min {
+		r.code = ErrEntityTooSmall
+		return n, errContentLengthRange
+	}
+	return
+}
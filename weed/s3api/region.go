@@ -0,0 +1,142 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// The region of the s3 gateway, given by the -region option, is
+//   - reported by GetBucketLocation, by the x-amz-bucket-region header of all the responses, and in the error responses,
+//   - checked with the region of the signature v4 credential scope, rejecting the requests signed for another region,
+//   - checked with the LocationConstraint of CreateBucket.
+// Without the -region option, the requests signed for any region are accepted, and us-east-1 is reported,
+// but GetBucketLocation and HeadBucket report the LocationConstraint the bucket was created with, which the clients may check,
+// and CreateBucket only checks the LocationConstraint is a valid region name.
+// GetBucketLocation is also accepted signed for us-east-1, where the clients send it to find the region of a bucket.
+
+const (
+	defaultRegion   = "us-east-1"
+	AmzBucketRegion = "X-Amz-Bucket-Region"
+
+	// extended attribute key on the bucket entry
+	bucketLocationConstraintKey = "s3-location-constraint"
+
+	// the legacy location constraint of eu-west-1
+	legacyEuLocationConstraint = "EU"
+)
+
+// regionNamePattern matches the region names like us-west-2, eu-central-1, or us-gov-west-1
+var regionNamePattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+
+type LocationConstraintResponse struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+	Location string   `xml:",chardata"`
+}
+
+func (s3a *S3ApiServer) region() string {
+	if s3a.option == nil || s3a.option.Region == "" {
+		return defaultRegion
+	}
+	return s3a.option.Region
+}
+
+// regionHeader tells the region in all the responses, including the errors
+func (s3a *S3ApiServer) regionHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(AmzBucketRegion, s3a.region())
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bucketRegion is the configured region, or the region of the location constraint the bucket was created with
+func (s3a *S3ApiServer) bucketRegion(entry *filer_pb.Entry) string {
+	if s3a.option.Region != "" {
+		return s3a.option.Region
+	}
+	switch constraint := string(entry.Extended[bucketLocationConstraintKey]); constraint {
+	case "":
+		return defaultRegion
+	case legacyEuLocationConstraint:
+		return "eu-west-1"
+	default:
+		return constraint
+	}
+}
+
+// GetBucketLocationHandler - GET bucket?location
+func (s3a *S3ApiServer) GetBucketLocationHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if entry == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	w.Header().Set(AmzBucketRegion, s3a.bucketRegion(entry))
+
+	// the configured region, which the requests are signed for, even if the bucket was created before it was configured
+	location := s3a.option.Region
+	if location == "" {
+		location = string(entry.Extended[bucketLocationConstraintKey])
+	}
+	// the buckets of us-east-1 have no location constraint
+	response := LocationConstraintResponse{}
+	if location != defaultRegion {
+		response.Location = location
+	}
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// locationConstraint reads the optional CreateBucketConfiguration of CreateBucket,
+// the location constraint must be empty or the configured region, or a valid region name without a region configured
+func (s3a *S3ApiServer) locationConstraint(w http.ResponseWriter, r *http.Request) (constraint string, ok bool) {
+	configuration := &CreateBucketConfiguration{}
+	if err := decodeXMLBody(r, configuration, maxXMLBodySize); err != nil && err != io.EOF {
+		writeXMLBodyError(w, r, err)
+		return "", false
+	}
+	constraint = configuration.LocationConstraint
+	if constraint == "" {
+		return constraint, true
+	}
+	if s3a.option.Region != "" {
+		if constraint == s3a.region() {
+			return constraint, true
+		}
+		writeErrorResponseWithMessage(w, ErrIllegalLocationConstraint, r.URL,
+			"The "+constraint+" location constraint is incompatible for the region specific endpoint this request was sent to.")
+		return "", false
+	}
+	if constraint == legacyEuLocationConstraint || regionNamePattern.MatchString(constraint) {
+		return constraint, true
+	}
+	writeErrorResponse(w, ErrInvalidLocationConstraint, r.URL)
+	return "", false
+}
+
+// checkRegion checks the region a request is signed for
+func (iam *IdentityAccessManagement) checkRegion(r *http.Request, region string) ErrorCode {
+	if iam.region == "" || region == iam.region {
+		return ErrNone
+	}
+	if region == defaultRegion && r.Method == "GET" && r.URL.Query()["location"] != nil {
+		return ErrNone
+	}
+	glog.V(2).Infof("request %s %s signed for region %s, expecting %s", r.Method, r.URL.Path, region, iam.region)
+	return ErrAuthorizationHeaderMalformed
+}
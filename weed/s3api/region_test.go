@@ -0,0 +1,243 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestSignedForWrongRegion(t *testing.T) {
+	iam := NewIdentityAccessManagement("", "")
+	iam.identities = []*Identity{{
+		Name:        "someone",
+		Credentials: []*Credential{{AccessKey: "access_key_1", SecretKey: "secret_key_1"}},
+	}}
+	iam.region = "eu-west-1"
+
+	signed := func(urlStr, region string) *http.Request {
+		req := mustNewRequest("GET", urlStr, 0, nil, t)
+		if err := signRequestV4ForRegion(req, "access_key_1", "secret_key_1", region); err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+	presigned := func(urlStr, region string) *http.Request {
+		req := mustNewRequest("GET", urlStr, 0, nil, t)
+		if err := preSignV4ForRegion(req, "access_key_1", "secret_key_1", int64(10*time.Minute.Seconds()), region); err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	tests := []struct {
+		req      *http.Request
+		expected ErrorCode
+	}{
+		{signed("http://127.0.0.1:8333/bucket/object", "eu-west-1"), ErrNone},
+		{signed("http://127.0.0.1:8333/bucket/object", "us-east-1"), ErrAuthorizationHeaderMalformed},
+		{signed("http://127.0.0.1:8333/bucket/object", "us-west-2"), ErrAuthorizationHeaderMalformed},
+		{presigned("http://127.0.0.1:8333/bucket/object", "eu-west-1"), ErrNone},
+		{presigned("http://127.0.0.1:8333/bucket/object", "us-west-2"), ErrAuthorizationHeaderMalformed},
+		// the clients look up the bucket region signing for us-east-1
+		{signed("http://127.0.0.1:8333/bucket?location", "us-east-1"), ErrNone},
+		{signed("http://127.0.0.1:8333/bucket?location", "us-west-2"), ErrAuthorizationHeaderMalformed},
+	}
+	for i, test := range tests {
+		if _, errCode := iam.reqSignatureV4Verify(test.req); errCode != test.expected {
+			t.Errorf("%d: got %v, expecting %v", i, errCode, test.expected)
+		}
+	}
+
+	// any region is accepted without a region configured
+	iam.region = ""
+	if _, errCode := iam.reqSignatureV4Verify(signed("http://127.0.0.1:8333/bucket/object", "us-west-2")); errCode != ErrNone {
+		t.Errorf("without region: %v", errCode)
+	}
+}
+
+func TestSignedForWrongService(t *testing.T) {
+	iam := NewIdentityAccessManagement("", "")
+	iam.identities = []*Identity{{
+		Name:        "someone",
+		Credentials: []*Credential{{AccessKey: "access_key_1", SecretKey: "secret_key_1"}},
+	}}
+
+	signed := func(service string) *http.Request {
+		req := mustNewRequest("GET", "http://127.0.0.1:8333/bucket/object", 0, nil, t)
+		if err := signRequestV4ForService(req, "access_key_1", "secret_key_1", "us-east-1", service); err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	tests := []struct {
+		signedFor string
+		service   string
+		expected  ErrorCode
+	}{
+		{"s3", signingServiceS3, ErrNone},
+		{"sts", signingServiceS3, ErrCredMalformed},
+		{"iam", signingServiceS3, ErrCredMalformed},
+		{"sts", signingServiceSts, ErrNone},
+		{"s3", signingServiceSts, ErrCredMalformed},
+	}
+	for _, test := range tests {
+		req := signed(test.signedFor)
+		if _, errCode := iam.doesSignatureMatch(getContentSha256Cksum(req), req, test.service); errCode != test.expected {
+			t.Errorf("signed for %s, verified for %s: got %v, expecting %v", test.signedFor, test.service, errCode, test.expected)
+		}
+	}
+}
+
+func TestRegionInResponses(t *testing.T) {
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket1": {Name: "bucket1", IsDirectory: true, Attributes: &filer_pb.FuseAttributes{}},
+	})
+	defer stop()
+	s3a.option.Region = "eu-west-1"
+
+	serve := func(handler http.HandlerFunc, method, urlStr, body string, vars map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, urlStr, strings.NewReader(body))
+		r = mux.SetURLVars(r, vars)
+		w := httptest.NewRecorder()
+		s3a.regionHeader(handler).ServeHTTP(w, r)
+		if region := w.Header().Get(AmzBucketRegion); region != "eu-west-1" {
+			t.Errorf("%s %s: region header %q", method, urlStr, region)
+		}
+		return w
+	}
+
+	w := serve(s3a.GetBucketLocationHandler, "GET", "/bucket1?location", "", map[string]string{"bucket": "bucket1"})
+	location := &LocationConstraintResponse{}
+	if err := xml.Unmarshal(w.Body.Bytes(), location); err != nil || location.Location != "eu-west-1" {
+		t.Errorf("location %q: %v", w.Body.String(), err)
+	}
+
+	w = serve(s3a.GetBucketLocationHandler, "GET", "/bucket2?location", "", map[string]string{"bucket": "bucket2"})
+	response := &RESTErrorResponse{}
+	if err := xml.Unmarshal(w.Body.Bytes(), response); err != nil || response.Code != "NoSuchBucket" || response.Region != "eu-west-1" {
+		t.Errorf("error %q: %v", w.Body.String(), err)
+	}
+
+	createBucket := `<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>%s</LocationConstraint></CreateBucketConfiguration>`
+	w = serve(s3a.PutBucketHandler, "PUT", "/bucket3", strings.Replace(createBucket, "%s", "us-west-2", 1), map[string]string{"bucket": "bucket3"})
+	if w.Code != http.StatusBadRequest || errorResponseCode(t, w) != "IllegalLocationConstraintException" {
+		t.Errorf("create in another region: %d %s", w.Code, w.Body.String())
+	}
+	w = serve(s3a.PutBucketHandler, "PUT", "/bucket3", strings.Replace(createBucket, "%s", "eu-west-1", 1), map[string]string{"bucket": "bucket3"})
+	if w.Code != http.StatusOK || w.Header().Get("Location") != "/bucket3" {
+		t.Errorf("create: %d %s", w.Code, w.Body.String())
+	}
+	w = serve(s3a.PutBucketHandler, "PUT", "/bucket4", "", map[string]string{"bucket": "bucket4"})
+	if w.Code != http.StatusOK {
+		t.Errorf("create without configuration: %d %s", w.Code, w.Body.String())
+	}
+
+	// us-east-1 has no location constraint
+	s3a.option.Region = ""
+	w = httptest.NewRecorder()
+	s3a.GetBucketLocationHandler(w, mux.SetURLVars(httptest.NewRequest("GET", "/bucket1?location", nil), map[string]string{"bucket": "bucket1"}))
+	if !strings.Contains(w.Body.String(), `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`) {
+		t.Errorf("default location %q", w.Body.String())
+	}
+}
+
+func TestBucketLocationRoundTrip(t *testing.T) {
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{})
+	defer stop()
+
+	createBucket := func(bucket, constraint string) *httptest.ResponseRecorder {
+		var body string
+		if constraint != "" {
+			body = `<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>` + constraint + `</LocationConstraint></CreateBucketConfiguration>`
+		}
+		r := httptest.NewRequest("PUT", "/"+bucket, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		s3a.PutBucketHandler(w, mux.SetURLVars(r, map[string]string{"bucket": bucket}))
+		return w
+	}
+	getBucketLocation := func(bucket string) (string, *httptest.ResponseRecorder) {
+		r := httptest.NewRequest("GET", "/"+bucket+"?location", nil)
+		w := httptest.NewRecorder()
+		s3a.GetBucketLocationHandler(w, mux.SetURLVars(r, map[string]string{"bucket": bucket}))
+		location := &LocationConstraintResponse{}
+		if w.Code == http.StatusOK {
+			if err := xml.Unmarshal(w.Body.Bytes(), location); err != nil {
+				t.Errorf("bucket %s location %q: %v", bucket, w.Body.String(), err)
+			}
+		}
+		return location.Location, w
+	}
+
+	// without a region configured, the buckets are in the region they are created for
+	tests := []struct {
+		bucket     string
+		constraint string
+		location   string
+	}{
+		{"ireland", "eu-west-1", "eu-west-1"},
+		{"oregon", "us-west-2", "us-west-2"},
+		{"virginia", "us-east-1", ""},
+		{"legacy", "EU", "EU"},
+		{"default", "", ""},
+	}
+	for _, test := range tests {
+		if w := createBucket(test.bucket, test.constraint); w.Code != http.StatusOK {
+			t.Errorf("create %s in %q: %d %s", test.bucket, test.constraint, w.Code, w.Body.String())
+			continue
+		}
+		if location, w := getBucketLocation(test.bucket); w.Code != http.StatusOK || location != test.location {
+			t.Errorf("bucket %s location %q, expecting %q: %s", test.bucket, location, test.location, w.Body.String())
+		}
+	}
+	for _, constraint := range []string{"mars-1", "us-west", "US-WEST-2", "us-west-2 "} {
+		if w := createBucket("invalid", constraint); w.Code != http.StatusBadRequest || errorResponseCode(t, w) != "InvalidLocationConstraint" {
+			t.Errorf("create in %q: %d %s", constraint, w.Code, w.Body.String())
+		}
+	}
+
+	// the region of the bucket is reported by HeadBucket and GetBucketLocation
+	headBucket := func(bucket string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s3a.regionHeader(http.HandlerFunc(s3a.HeadBucketHandler)).ServeHTTP(w, mux.SetURLVars(httptest.NewRequest("HEAD", "/"+bucket, nil), map[string]string{"bucket": bucket}))
+		return w
+	}
+	for bucket, region := range map[string]string{"ireland": "eu-west-1", "legacy": "eu-west-1", "default": "us-east-1"} {
+		if w := headBucket(bucket); w.Code != http.StatusOK || w.Header().Get(AmzBucketRegion) != region {
+			t.Errorf("head bucket %s: %d region %q, expecting %q", bucket, w.Code, w.Header().Get(AmzBucketRegion), region)
+		}
+	}
+	if _, w := getBucketLocation("oregon"); w.Header().Get(AmzBucketRegion) != "us-west-2" {
+		t.Errorf("bucket oregon region header %q", w.Header().Get(AmzBucketRegion))
+	}
+	if w := headBucket("missing"); w.Code != http.StatusNotFound {
+		t.Errorf("head missing bucket: %d", w.Code)
+	}
+
+	// with a region configured, the constraint must be the region
+	s3a.option.Region = "eu-west-1"
+	if w := createBucket("dublin", "eu-west-1"); w.Code != http.StatusOK {
+		t.Errorf("create in the region: %d %s", w.Code, w.Body.String())
+	}
+	if location, _ := getBucketLocation("dublin"); location != "eu-west-1" {
+		t.Errorf("bucket location %q", location)
+	}
+	if w := createBucket("frankfurt", "eu-central-1"); w.Code != http.StatusBadRequest || errorResponseCode(t, w) != "IllegalLocationConstraintException" {
+		t.Errorf("create in another region: %d %s", w.Code, w.Body.String())
+	}
+	if _, w := getBucketLocation("frankfurt"); w.Code != http.StatusNotFound {
+		t.Errorf("bucket created in another region: %d %s", w.Code, w.Body.String())
+	}
+	// the buckets created before are reported in the configured region, which the requests are signed for
+	if location, _ := getBucketLocation("oregon"); location != "eu-west-1" {
+		t.Errorf("bucket created before location %q", location)
+	}
+}
@@ -0,0 +1,257 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/replication.html
+//
+// The objects matching a replication rule are marked PENDING when they are written,
+// and copied to the destination bucket in the background, see replicator.go.
+
+const (
+	ReplicationRuleEnabled  = "Enabled"
+	ReplicationRuleDisabled = "Disabled"
+
+	// response header, also used as the extended attribute key on the object entry
+	AmzReplicationStatus = "X-Amz-Replication-Status"
+
+	ReplicationStatusPending   = "PENDING"
+	ReplicationStatusCompleted = "COMPLETED"
+	ReplicationStatusFailed    = "FAILED"
+	ReplicationStatusReplica   = "REPLICA"
+
+	// extended attribute key on the bucket entry
+	bucketReplicationKey = "s3-replication"
+
+	bucketReplicationCacheTTL = 5 * time.Second
+
+	maxReplicationRules = 1000
+
+	// the destination buckets are named by their ARN
+	s3BucketArnPrefix = "arn:aws:s3:::"
+)
+
+type ReplicationConfiguration struct {
+	XMLName xml.Name          `xml:"ReplicationConfiguration"`
+	Xmlns   string            `xml:"xmlns,attr,omitempty"`
+	Role    string            `xml:"Role"`
+	Rules   []ReplicationRule `xml:"Rule"`
+}
+
+type ReplicationRule struct {
+	ID                      string                   `xml:"ID,omitempty"`
+	Priority                int                      `xml:"Priority,omitempty"`
+	Status                  string                   `xml:"Status"`
+	Prefix                  *string                  `xml:"Prefix,omitempty"`
+	Filter                  *ReplicationRuleFilter   `xml:"Filter,omitempty"`
+	Destination             ReplicationDestination   `xml:"Destination"`
+	DeleteMarkerReplication *DeleteMarkerReplication `xml:"DeleteMarkerReplication,omitempty"`
+}
+
+type ReplicationRuleFilter struct {
+	Prefix *string                     `xml:"Prefix,omitempty"`
+	Tag    *Tag                        `xml:"Tag,omitempty"`
+	And    *ReplicationRuleAndOperator `xml:"And,omitempty"`
+}
+
+type ReplicationRuleAndOperator struct {
+	Prefix string `xml:"Prefix,omitempty"`
+	Tags   []Tag  `xml:"Tag"`
+}
+
+type ReplicationDestination struct {
+	Bucket       string `xml:"Bucket"`
+	StorageClass string `xml:"StorageClass,omitempty"`
+}
+
+type DeleteMarkerReplication struct {
+	Status string `xml:"Status"`
+}
+
+// validate checks the rules, and that the destination buckets are configured for the s3 server
+func (config *ReplicationConfiguration) validate(targets map[string]replicationTarget) ErrorCode {
+	if len(config.Rules) == 0 || len(config.Rules) > maxReplicationRules {
+		return ErrMalformedXML
+	}
+	ids := make(map[string]bool)
+	priorities := make(map[int]bool)
+	for _, rule := range config.Rules {
+		if code := rule.validate(targets); code != ErrNone {
+			return code
+		}
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return ErrInvalidRequest
+			}
+			ids[rule.ID] = true
+		}
+		// the rules with a filter are chosen by their priority when several of them match
+		if rule.Filter != nil {
+			if priorities[rule.Priority] {
+				return ErrInvalidRequest
+			}
+			priorities[rule.Priority] = true
+		}
+	}
+	return ErrNone
+}
+
+func (rule *ReplicationRule) validate(targets map[string]replicationTarget) ErrorCode {
+	if rule.Status != ReplicationRuleEnabled && rule.Status != ReplicationRuleDisabled {
+		return ErrMalformedXML
+	}
+	if len(rule.ID) > 255 || rule.Priority < 0 {
+		return ErrInvalidRequest
+	}
+	if rule.Prefix != nil && rule.Filter != nil {
+		return ErrMalformedXML
+	}
+	if filter := rule.Filter; filter != nil {
+		conditions := 0
+		if filter.Prefix != nil {
+			conditions++
+		}
+		if filter.Tag != nil {
+			conditions++
+		}
+		if filter.And != nil {
+			conditions++
+		}
+		if conditions > 1 {
+			return ErrMalformedXML
+		}
+	}
+	if marker := rule.DeleteMarkerReplication; marker != nil {
+		if marker.Status != ReplicationRuleEnabled && marker.Status != ReplicationRuleDisabled {
+			return ErrMalformedXML
+		}
+		// the deleted objects have no tags to match
+		if marker.Status == ReplicationRuleEnabled && len(rule.tags()) > 0 {
+			return ErrInvalidRequest
+		}
+	}
+	if _, found := targets[rule.Destination.Bucket]; !found {
+		return ErrInvalidRequest
+	}
+	return ErrNone
+}
+
+func (rule *ReplicationRule) prefix() string {
+	if rule.Prefix != nil {
+		return *rule.Prefix
+	}
+	if rule.Filter == nil {
+		return ""
+	}
+	if rule.Filter.Prefix != nil {
+		return *rule.Filter.Prefix
+	}
+	if rule.Filter.And != nil {
+		return rule.Filter.And.Prefix
+	}
+	return ""
+}
+
+func (rule *ReplicationRule) tags() []Tag {
+	if rule.Filter == nil {
+		return nil
+	}
+	if rule.Filter.Tag != nil {
+		return []Tag{*rule.Filter.Tag}
+	}
+	if rule.Filter.And != nil {
+		return rule.Filter.And.Tags
+	}
+	return nil
+}
+
+// matches tells whether the rule applies to the object key, without the leading "/", and its extended attributes
+func (rule *ReplicationRule) matches(key string, extended map[string][]byte) bool {
+	if rule.Status != ReplicationRuleEnabled || !strings.HasPrefix(key, rule.prefix()) {
+		return false
+	}
+	for _, tag := range rule.tags() {
+		if value, found := extended[AmzObjectTaggingPrefix+tag.Key]; !found || string(value) != tag.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func (rule *ReplicationRule) replicatesDeletes() bool {
+	return rule.DeleteMarkerReplication != nil && rule.DeleteMarkerReplication.Status == ReplicationRuleEnabled
+}
+
+// match returns the rule of the highest priority applying to the object, or nil
+func (config *ReplicationConfiguration) match(key string, extended map[string][]byte) (matched *ReplicationRule) {
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+		if rule.matches(key, extended) && (matched == nil || rule.Priority > matched.Priority) {
+			matched = rule
+		}
+	}
+	return matched
+}
+
+func loadReplicationConfiguration(extended map[string][]byte) (*ReplicationConfiguration, error) {
+	data, found := extended[bucketReplicationKey]
+	if !found {
+		return nil, nil
+	}
+	config := &ReplicationConfiguration{}
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// getBucketReplication returns nil if the bucket has no replication configuration
+func (s3a *S3ApiServer) getBucketReplication(bucket string) (config *ReplicationConfiguration, code ErrorCode) {
+
+	if item := s3a.replicationCache.Get(bucket); item != nil && !item.Expired() {
+		config = item.Value().(*ReplicationConfiguration)
+	} else {
+		entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+		if err != nil {
+			glog.Errorf("lookup bucket %s: %v", bucket, err)
+			return nil, ErrInternalError
+		}
+		if entry == nil {
+			return nil, ErrNoSuchBucket
+		}
+		if config, err = loadReplicationConfiguration(entry.Extended); err != nil {
+			glog.Errorf("bucket %s has invalid replication configuration: %v", bucket, err)
+			return nil, ErrInternalError
+		}
+		if config == nil {
+			config = &ReplicationConfiguration{}
+		}
+		s3a.replicationCache.Set(bucket, config, bucketReplicationCacheTTL)
+	}
+
+	if len(config.Rules) == 0 {
+		return nil, ErrNone
+	}
+	return config, ErrNone
+}
+
+func (s3a *S3ApiServer) hasBucketReplication(bucket string) bool {
+	config, code := s3a.getBucketReplication(bucket)
+	return code == ErrNone && config != nil
+}
+
+// setReplicationPending marks the current version of an object for replication, if a rule applies to it
+func (s3a *S3ApiServer) setReplicationPending(bucket, object string, extended map[string][]byte) {
+	config, code := s3a.getBucketReplication(bucket)
+	if code != ErrNone || config == nil {
+		return
+	}
+	if config.match(strings.TrimPrefix(object, "/"), extended) != nil {
+		extended[AmzReplicationStatus] = []byte(ReplicationStatusPending)
+	}
+}
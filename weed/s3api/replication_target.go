@@ -0,0 +1,254 @@
+package s3api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// replicationDestinations is the json file of the s3 -replication.config option
+type replicationDestinations struct {
+	Destinations []replicationDestinationConfig `json:"destinations"`
+}
+
+// replicationDestinationConfig is a bucket on another S3 endpoint, or on the filer of another SeaweedFS cluster
+type replicationDestinationConfig struct {
+	Bucket string `json:"bucket"`
+
+	Endpoint  string `json:"endpoint"`
+	Region    string `json:"region"`
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+
+	Filer       string `json:"filer"`
+	BucketsPath string `json:"bucketsPath"`
+}
+
+// replicatedObject is the current version of an object, read from the source bucket
+type replicatedObject struct {
+	body         io.Reader
+	size         int64
+	contentType  string
+	tags         objectTags
+	storageClass string
+}
+
+// replicationTarget writes the replicas into a destination bucket
+type replicationTarget interface {
+	putObject(key string, object *replicatedObject) error
+	deleteObject(key string) error
+}
+
+// loadReplicationTargets returns the destination buckets by their ARN, or nil if fileName is empty
+func loadReplicationTargets(fileName string, grpcDialOption grpc.DialOption) (map[string]replicationTarget, error) {
+
+	if fileName == "" {
+		return nil, nil
+	}
+
+	rawData, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("read replication config %s: %v", fileName, err)
+	}
+	config := &replicationDestinations{}
+	if err = json.Unmarshal(rawData, config); err != nil {
+		return nil, fmt.Errorf("parse replication config %s: %v", fileName, err)
+	}
+
+	targets := make(map[string]replicationTarget)
+	for _, destination := range config.Destinations {
+		if destination.Bucket == "" {
+			return nil, fmt.Errorf("replication config %s: destination without bucket", fileName)
+		}
+		var target replicationTarget
+		if destination.Filer != "" {
+			target = newFilerReplicationTarget(destination, grpcDialOption)
+		} else if target, err = newS3ReplicationTarget(destination); err != nil {
+			return nil, fmt.Errorf("replication destination %s: %v", destination.Bucket, err)
+		}
+		targets[s3BucketArnPrefix+destination.Bucket] = target
+	}
+
+	return targets, nil
+}
+
+// s3ReplicationTarget is a bucket of an S3 endpoint
+type s3ReplicationTarget struct {
+	bucket   string
+	conn     s3iface.S3API
+	uploader *s3manager.Uploader
+}
+
+func newS3ReplicationTarget(destination replicationDestinationConfig) (*s3ReplicationTarget, error) {
+
+	region := destination.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	config := &aws.Config{
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(true),
+	}
+	if destination.Endpoint != "" {
+		config.Endpoint = aws.String(destination.Endpoint)
+	}
+	if destination.AccessKey != "" && destination.SecretKey != "" {
+		config.Credentials = credentials.NewStaticCredentials(destination.AccessKey, destination.SecretKey, "")
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("create aws session: %v", err)
+	}
+	conn := s3.New(sess)
+
+	return &s3ReplicationTarget{
+		bucket:   destination.Bucket,
+		conn:     conn,
+		uploader: s3manager.NewUploaderWithClient(conn),
+	}, nil
+}
+
+func (target *s3ReplicationTarget) putObject(key string, object *replicatedObject) error {
+	input := &s3manager.UploadInput{
+		Bucket: aws.String(target.bucket),
+		Key:    aws.String(key),
+		Body:   object.body,
+	}
+	if object.contentType != "" {
+		input.ContentType = aws.String(object.contentType)
+	}
+	if len(object.tags) > 0 {
+		input.Tagging = aws.String(object.tags.encode())
+	}
+	if object.storageClass != "" {
+		input.StorageClass = aws.String(object.storageClass)
+	}
+	_, err := target.uploader.Upload(input)
+	return err
+}
+
+func (target *s3ReplicationTarget) deleteObject(key string) error {
+	_, err := target.conn.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(target.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// filerReplicationTarget is a bucket of another SeaweedFS cluster, written through its filer
+type filerReplicationTarget struct {
+	bucket         string
+	filer          string
+	bucketsPath    string
+	grpcDialOption grpc.DialOption
+}
+
+func newFilerReplicationTarget(destination replicationDestinationConfig, grpcDialOption grpc.DialOption) *filerReplicationTarget {
+	bucketsPath := destination.BucketsPath
+	if bucketsPath == "" {
+		bucketsPath = "/buckets"
+	}
+	return &filerReplicationTarget{
+		bucket:         destination.Bucket,
+		filer:          destination.Filer,
+		bucketsPath:    bucketsPath,
+		grpcDialOption: grpcDialOption,
+	}
+}
+
+func (target *filerReplicationTarget) objectUrl(key string) string {
+	return fmt.Sprintf("http://%s%s/%s/%s", target.filer, target.bucketsPath, target.bucket, key)
+}
+
+func (target *filerReplicationTarget) putObject(key string, object *replicatedObject) error {
+
+	req, err := http.NewRequest("PUT", target.objectUrl(key), object.body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = object.size
+	contentType := object.contentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("put %s: %v", req.URL, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("put %s: %v", req.URL, err)
+	}
+	var ret struct {
+		Error string `json:"error"`
+	}
+	if err = json.Unmarshal(respBody, &ret); err != nil {
+		return fmt.Errorf("put %s: %s %s", req.URL, resp.Status, string(respBody))
+	}
+	if ret.Error != "" {
+		return fmt.Errorf("put %s: %s", req.URL, ret.Error)
+	}
+
+	// the tags and the replica status are kept the same way the s3 gateway of the cluster does
+	dir, name := util.FullPath(fmt.Sprintf("%s/%s/%s", target.bucketsPath, target.bucket, key)).DirAndName()
+	return pb.WithFilerClient(target.filer, target.grpcDialOption, func(client filer_pb.SeaweedFilerClient) error {
+		resp, err := filer_pb.LookupEntry(client, &filer_pb.LookupDirectoryEntryRequest{
+			Directory: dir,
+			Name:      name,
+		})
+		if err != nil {
+			return fmt.Errorf("lookup %s/%s: %v", dir, name, err)
+		}
+		entry := resp.Entry
+		if entry.Extended == nil {
+			entry.Extended = make(map[string][]byte)
+		}
+		object.tags.saveTo(entry.Extended)
+		entry.Extended[AmzReplicationStatus] = []byte(ReplicationStatusReplica)
+		if _, err = client.UpdateEntry(context.Background(), &filer_pb.UpdateEntryRequest{
+			Directory: dir,
+			Entry:     entry,
+		}); err != nil {
+			return fmt.Errorf("update %s/%s: %v", dir, name, err)
+		}
+		return nil
+	})
+}
+
+func (target *filerReplicationTarget) deleteObject(key string) error {
+
+	req, err := http.NewRequest("DELETE", target.objectUrl(key), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("delete %s: %v", req.URL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("delete %s: %s", req.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestReplicationConfigurationValidate(t *testing.T) {
+	targets := map[string]replicationTarget{
+		"arn:aws:s3:::backup": &filerReplicationTarget{bucket: "backup"},
+	}
+	tests := []struct {
+		config   string
+		expected ErrorCode
+	}{
+		{`<ReplicationConfiguration><Role>r</Role><Rule><Status>Enabled</Status><Prefix></Prefix><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination></Rule></ReplicationConfiguration>`, ErrNone},
+		{`<ReplicationConfiguration><Rule><ID>a</ID><Priority>1</Priority><Status>Enabled</Status><Filter><Prefix>logs/</Prefix></Filter><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination><DeleteMarkerReplication><Status>Enabled</Status></DeleteMarkerReplication></Rule>` +
+			`<Rule><ID>b</ID><Priority>2</Priority><Status>Enabled</Status><Filter><Tag><Key>k</Key><Value>v</Value></Tag></Filter><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination></Rule></ReplicationConfiguration>`, ErrNone},
+		{`<ReplicationConfiguration></ReplicationConfiguration>`, ErrMalformedXML},
+		{`<ReplicationConfiguration><Rule><Status>On</Status><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination></Rule></ReplicationConfiguration>`, ErrMalformedXML},
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Destination><Bucket>arn:aws:s3:::unknown</Bucket></Destination></Rule></ReplicationConfiguration>`, ErrInvalidRequest},
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Prefix>a</Prefix><Filter><Prefix>a</Prefix></Filter><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination></Rule></ReplicationConfiguration>`, ErrMalformedXML},
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Filter><Prefix>a</Prefix><Tag><Key>k</Key><Value>v</Value></Tag></Filter><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination></Rule></ReplicationConfiguration>`, ErrMalformedXML},
+		{`<ReplicationConfiguration><Rule><Priority>1</Priority><Status>Enabled</Status><Filter><Prefix>a</Prefix></Filter><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination></Rule>` +
+			`<Rule><Priority>1</Priority><Status>Enabled</Status><Filter><Prefix>b</Prefix></Filter><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination></Rule></ReplicationConfiguration>`, ErrInvalidRequest},
+		{`<ReplicationConfiguration><Rule><Status>Enabled</Status><Filter><Tag><Key>k</Key><Value>v</Value></Tag></Filter><Destination><Bucket>arn:aws:s3:::backup</Bucket></Destination><DeleteMarkerReplication><Status>Enabled</Status></DeleteMarkerReplication></Rule></ReplicationConfiguration>`, ErrInvalidRequest},
+	}
+	for _, test := range tests {
+		config := &ReplicationConfiguration{}
+		if err := xml.Unmarshal([]byte(test.config), config); err != nil {
+			t.Fatalf("unmarshal %s: %v", test.config, err)
+		}
+		if errCode := config.validate(targets); errCode != test.expected {
+			t.Errorf("%s: %v, expecting %v", test.config, errCode, test.expected)
+		}
+	}
+
+	config := &ReplicationConfiguration{}
+	xml.Unmarshal([]byte(tests[0].config), config)
+	if errCode := config.validate(nil); errCode != ErrInvalidRequest {
+		t.Errorf("validate without destinations: %v", errCode)
+	}
+}
+
+func TestReplicationConfigurationMatch(t *testing.T) {
+	config := &ReplicationConfiguration{}
+	if err := xml.Unmarshal([]byte(`<ReplicationConfiguration>
+  <Rule><ID>logs</ID><Priority>1</Priority><Status>Enabled</Status><Filter><Prefix>logs/</Prefix></Filter>
+    <Destination><Bucket>arn:aws:s3:::logs</Bucket></Destination>
+    <DeleteMarkerReplication><Status>Enabled</Status></DeleteMarkerReplication></Rule>
+  <Rule><ID>important</ID><Priority>2</Priority><Status>Enabled</Status>
+    <Filter><And><Prefix>logs/</Prefix><Tag><Key>class</Key><Value>important</Value></Tag></And></Filter>
+    <Destination><Bucket>arn:aws:s3:::important</Bucket></Destination></Rule>
+  <Rule><ID>disabled</ID><Priority>3</Priority><Status>Disabled</Status><Filter><Prefix></Prefix></Filter>
+    <Destination><Bucket>arn:aws:s3:::all</Bucket></Destination></Rule>
+</ReplicationConfiguration>`), config); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	important := map[string][]byte{AmzObjectTaggingPrefix + "class": []byte("important")}
+	tests := []struct {
+		key      string
+		extended map[string][]byte
+		expected string
+	}{
+		{"logs/1.log", nil, "logs"},
+		{"logs/1.log", important, "important"},
+		{"data/1.log", important, ""},
+		{"logs", nil, ""},
+	}
+	for _, test := range tests {
+		rule := config.match(test.key, test.extended)
+		id := ""
+		if rule != nil {
+			id = rule.ID
+		}
+		if id != test.expected {
+			t.Errorf("%s %v: rule %q, expecting %q", test.key, test.extended, id, test.expected)
+		}
+	}
+
+	if rule := config.match("logs/1.log", nil); !rule.replicatesDeletes() {
+		t.Errorf("rule %s replicates deletes", rule.ID)
+	}
+	if rule := config.match("logs/1.log", important); rule.replicatesDeletes() {
+		t.Errorf("rule %s does not replicate deletes", rule.ID)
+	}
+}
+
+func TestBucketObjectKey(t *testing.T) {
+	s3a := &S3ApiServer{option: &S3ApiServerOption{BucketsPath: "/buckets"}}
+	tests := []struct {
+		dir    string
+		name   string
+		bucket string
+		key    string
+		ok     bool
+	}{
+		{"/buckets/b", "a.txt", "b", "a.txt", true},
+		{"/buckets/b/x/y", "a.txt", "b", "x/y/a.txt", true},
+		{"/buckets", "b", "", "", false},
+		{"/other/b", "a.txt", "", "", false},
+		{"/buckets/b/.uploads/1", "0001.part", "", "", false},
+		{"/buckets/b/" + versionsFolder + "/a.txt", "v1", "", "", false},
+	}
+	for _, test := range tests {
+		bucket, key, ok := s3a.bucketObjectKey(test.dir, test.name)
+		if bucket != test.bucket || key != test.key || ok != test.ok {
+			t.Errorf("%s/%s: %s %s %v", test.dir, test.name, bucket, key, ok)
+		}
+	}
+}
+
+func TestReplicatorCheckpoint(t *testing.T) {
+	replicator := newBucketReplicator(nil)
+	replicator.followed(100)
+
+	first := &replicationTask{bucket: "b", key: "a.txt", tsNs: 101}
+	second := &replicationTask{bucket: "b", key: "b.txt", tsNs: 102}
+	replicator.enqueue(first)
+	replicator.enqueue(second)
+	replicator.enqueue(&replicationTask{bucket: "b", key: "a.txt", tsNs: 103})
+	replicator.followed(103)
+	if checkpoint := replicator.checkpoint(); checkpoint != 100 {
+		t.Errorf("checkpoint with queued tasks: %d", checkpoint)
+	}
+
+	// the second task is replicated, the first is retried
+	<-replicator.tasks
+	<-replicator.tasks
+	replicator.dequeue(first)
+	replicator.dequeue(second)
+	replicator.done(second)
+	if checkpoint := replicator.checkpoint(); checkpoint != 100 {
+		t.Errorf("checkpoint with a retried task: %d", checkpoint)
+	}
+
+	replicator.done(first)
+	if checkpoint := replicator.checkpoint(); checkpoint != 103 {
+		t.Errorf("checkpoint without tasks: %d", checkpoint)
+	}
+}
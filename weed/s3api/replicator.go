@@ -0,0 +1,426 @@
+package s3api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The replicator follows the filer metadata changes under the buckets folder.
+// The objects written with the PENDING replication status, and the deleted objects, are queued by their key.
+// A queued key is replicated according to the current state of the object, so the queue order does not matter,
+// and the failures are retried with backoff, without blocking the writes.
+// Only one s3 gateway follows the changes, holding the replicator lease. The checkpoint is saved before the
+// earliest change still queued or retried, so the changes not replicated yet are followed again after a restart.
+
+const (
+	replicationQueueSize   = 1024
+	replicationWorkers     = 4
+	replicationMaxAttempts = 10
+	replicationMaxBackoff  = 5 * time.Minute
+
+	// extended attribute key on the buckets folder entry, holding the time of the last metadata change followed
+	replicationCheckpointKey      = "s3-replication-checkpoint"
+	replicationCheckpointInterval = 10 * time.Second
+
+	replicatorLeaseName = "replicator"
+	replicatorLease     = 3 * replicationCheckpointInterval
+)
+
+// errReplicationNotSupported fails the replication without retrying
+var errReplicationNotSupported = errors.New("objects encrypted with customer provided keys are not replicated")
+
+type bucketReplicator struct {
+	targets map[string]replicationTarget
+	tasks   chan *replicationTask
+
+	queuedLock sync.Mutex
+	queued     map[string]bool
+	inFlight   map[*replicationTask]bool // the tasks queued or retried, until replicated or failed
+	lastTsNs   int64                     // time of the last metadata change followed
+}
+
+type replicationTask struct {
+	bucket  string
+	key     string
+	tsNs    int64 // time of the metadata change which queued the task
+	attempt int
+}
+
+func (task *replicationTask) String() string {
+	return task.bucket + "/" + task.key
+}
+
+func newBucketReplicator(targets map[string]replicationTarget) *bucketReplicator {
+	return &bucketReplicator{
+		targets:  targets,
+		tasks:    make(chan *replicationTask, replicationQueueSize),
+		queued:   make(map[string]bool),
+		inFlight: make(map[*replicationTask]bool),
+	}
+}
+
+// enqueue skips the keys already queued, they are replicated with their latest state anyway
+func (replicator *bucketReplicator) enqueue(task *replicationTask) {
+	replicator.queuedLock.Lock()
+	if replicator.queued[task.String()] {
+		delete(replicator.inFlight, task)
+		replicator.queuedLock.Unlock()
+		return
+	}
+	replicator.queued[task.String()] = true
+	replicator.inFlight[task] = true
+	replicator.queuedLock.Unlock()
+
+	replicator.tasks <- task
+}
+
+func (replicator *bucketReplicator) dequeue(task *replicationTask) {
+	replicator.queuedLock.Lock()
+	delete(replicator.queued, task.String())
+	replicator.queuedLock.Unlock()
+}
+
+// done marks the task replicated or failed, no longer holding back the checkpoint
+func (replicator *bucketReplicator) done(task *replicationTask) {
+	replicator.queuedLock.Lock()
+	delete(replicator.inFlight, task)
+	replicator.queuedLock.Unlock()
+}
+
+// followed records the time of the metadata change, after its tasks are queued
+func (replicator *bucketReplicator) followed(tsNs int64) {
+	replicator.queuedLock.Lock()
+	replicator.lastTsNs = tsNs
+	replicator.queuedLock.Unlock()
+}
+
+// checkpoint is the time until which all the metadata changes followed are replicated
+func (replicator *bucketReplicator) checkpoint() int64 {
+	replicator.queuedLock.Lock()
+	defer replicator.queuedLock.Unlock()
+	checkpoint := replicator.lastTsNs
+	for task := range replicator.inFlight {
+		if task.tsNs <= checkpoint {
+			checkpoint = task.tsNs - 1
+		}
+	}
+	return checkpoint
+}
+
+// replicationTargets returns nil if no replication destination is configured
+func (s3a *S3ApiServer) replicationTargets() map[string]replicationTarget {
+	if s3a.replicator == nil {
+		return nil
+	}
+	return s3a.replicator.targets
+}
+
+func (s3a *S3ApiServer) loopReplication() {
+
+	for i := 0; i < replicationWorkers; i++ {
+		go s3a.loopReplicationTasks()
+	}
+
+	owner := uuid.New().String()
+	for {
+		if s3a.acquireLease(replicatorLeaseName, owner, replicatorLease) {
+			s3a.followReplication(owner)
+		}
+		time.Sleep(replicationCheckpointInterval)
+	}
+}
+
+// followReplication queues the metadata changes since the checkpoint, until the replicator lease is lost
+func (s3a *S3ApiServer) followReplication(owner string) {
+
+	sinceNs, err := s3a.loadReplicationCheckpoint()
+	if err != nil {
+		glog.Errorf("load replication checkpoint: %v", err)
+		return
+	}
+	if sinceNs == 0 {
+		sinceNs = time.Now().UnixNano()
+	}
+	s3a.replicator.followed(sinceNs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s3a.loopReplicationCheckpoint(ctx, cancel, owner)
+
+	for ctx.Err() == nil {
+		err := s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+			stream, err := client.SubscribeMetadata(ctx, &filer_pb.SubscribeMetadataRequest{
+				ClientName: "s3.replication",
+				PathPrefix: s3a.option.BucketsPath + "/",
+				SinceNs:    sinceNs,
+			})
+			if err != nil {
+				return fmt.Errorf("subscribe: %v", err)
+			}
+
+			for {
+				resp, listenErr := stream.Recv()
+				if listenErr == io.EOF {
+					return nil
+				}
+				if listenErr != nil {
+					return listenErr
+				}
+				s3a.processReplicationEvent(resp)
+				sinceNs = resp.TsNs
+				s3a.replicator.followed(sinceNs)
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			glog.V(0).Infof("replication subscribing filer meta change: %v", err)
+			time.Sleep(time.Second)
+		}
+	}
+}
+
+func (s3a *S3ApiServer) processReplicationEvent(resp *filer_pb.SubscribeMetadataResponse) {
+
+	message := resp.EventNotification
+
+	if entry := message.NewEntry; entry != nil && !entry.IsDirectory {
+		dir := resp.Directory
+		if message.NewParentPath != "" {
+			dir = message.NewParentPath
+		}
+		if isDeleteMarker(entry) || string(entry.Extended[AmzReplicationStatus]) == ReplicationStatusPending {
+			s3a.queueReplication(dir, entry.Name, resp.TsNs)
+		}
+	}
+
+	if entry := message.OldEntry; entry != nil && message.NewEntry == nil && !entry.IsDirectory {
+		s3a.queueReplication(resp.Directory, entry.Name, resp.TsNs)
+	}
+}
+
+func (s3a *S3ApiServer) queueReplication(dir, name string, tsNs int64) {
+	bucket, key, ok := s3a.bucketObjectKey(dir, name)
+	if !ok || !s3a.hasBucketReplication(bucket) {
+		return
+	}
+	s3a.replicator.enqueue(&replicationTask{bucket: bucket, key: key, tsNs: tsNs})
+}
+
+// bucketObjectKey splits the path of an entry into the bucket and the object key,
+// skipping the multipart uploads and the noncurrent versions
+func (s3a *S3ApiServer) bucketObjectKey(dir, name string) (bucket, key string, ok bool) {
+	if !strings.HasPrefix(dir, s3a.option.BucketsPath+"/") {
+		return "", "", false
+	}
+	path := dir[len(s3a.option.BucketsPath)+1:] + "/" + name
+	slash := strings.Index(path, "/")
+	bucket, key = path[:slash], path[slash+1:]
+	if strings.HasPrefix(key, ".uploads/") || strings.HasPrefix(key, versionsFolder+"/") {
+		return "", "", false
+	}
+	return bucket, key, true
+}
+
+func (s3a *S3ApiServer) loopReplicationTasks() {
+	for task := range s3a.replicator.tasks {
+		s3a.replicator.dequeue(task)
+
+		err := s3a.replicate(task)
+		if err == nil {
+			s3a.replicator.done(task)
+			continue
+		}
+
+		task.attempt++
+		if err == errReplicationNotSupported || task.attempt >= replicationMaxAttempts {
+			glog.Errorf("replicate %s: %v", task, err)
+			s3a.setReplicationStatus(task, "", ReplicationStatusFailed)
+			s3a.replicator.done(task)
+			continue
+		}
+
+		backoff := time.Second << uint(task.attempt-1)
+		if backoff > replicationMaxBackoff {
+			backoff = replicationMaxBackoff
+		}
+		glog.V(0).Infof("replicate %s: %v, retry in %v", task, err, backoff)
+		retry := task
+		time.AfterFunc(backoff, func() {
+			s3a.replicator.enqueue(retry)
+		})
+	}
+}
+
+// replicate copies the current version of the object to the destination of the matching rule,
+// or deletes the replica of a deleted object
+func (s3a *S3ApiServer) replicate(task *replicationTask) error {
+
+	config, code := s3a.getBucketReplication(task.bucket)
+	if code == ErrNoSuchBucket || (code == ErrNone && config == nil) {
+		return nil
+	}
+	if code != ErrNone {
+		return fmt.Errorf("lookup replication configuration: %s", getAPIError(code).Code)
+	}
+
+	dir, name := s3a.objectDirAndName(task.bucket, "/"+task.key)
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil {
+		return err
+	}
+
+	if entry == nil || isDeleteMarker(entry) {
+		rule := config.match(task.key, nil)
+		if rule == nil || !rule.replicatesDeletes() {
+			return nil
+		}
+		target, found := s3a.replicator.targets[rule.Destination.Bucket]
+		if !found {
+			return fmt.Errorf("replication destination %s is not configured", rule.Destination.Bucket)
+		}
+		glog.V(2).Infof("replicate deletion of %s to %s", task, rule.Destination.Bucket)
+		return target.deleteObject(task.key)
+	}
+
+	if entry.IsDirectory || string(entry.Extended[AmzReplicationStatus]) != ReplicationStatusPending {
+		return nil
+	}
+	rule := config.match(task.key, entry.Extended)
+	if rule == nil {
+		return nil
+	}
+	target, found := s3a.replicator.targets[rule.Destination.Bucket]
+	if !found {
+		return fmt.Errorf("replication destination %s is not configured", rule.Destination.Bucket)
+	}
+
+	if err = s3a.replicateObject(task, entry, rule, target); err != nil {
+		return err
+	}
+	glog.V(2).Infof("replicated %s to %s", task, rule.Destination.Bucket)
+
+	s3a.setReplicationStatus(task, filer2.ETag(entry), ReplicationStatusCompleted)
+	return nil
+}
+
+func (s3a *S3ApiServer) replicateObject(task *replicationTask, entry *filer_pb.Entry, rule *ReplicationRule, target replicationTarget) error {
+
+	srcUrl := fmt.Sprintf("http://%s%s/%s/%s", s3a.option.Filer, s3a.option.BucketsPath, task.bucket, task.key)
+	resp, err := client.Get(srcUrl)
+	if err != nil {
+		return fmt.Errorf("read %s: %v", srcUrl, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("read %s: %s", srcUrl, resp.Status)
+	}
+
+	body, code := s3a.decryptResponse(resp, nil)
+	if code == ErrSSECustomerKeyMissing {
+		return errReplicationNotSupported
+	}
+	if code != ErrNone {
+		return fmt.Errorf("decrypt %s: %s", srcUrl, getAPIError(code).Code)
+	}
+
+	return target.putObject(task.key, &replicatedObject{
+		body:         body,
+		size:         int64(filer2.FileSize(entry)),
+		contentType:  entry.Attributes.Mime,
+		tags:         loadObjectTags(entry.Extended),
+		storageClass: rule.Destination.StorageClass,
+	})
+}
+
+// setReplicationStatus updates the object still pending, unless it is written again since replicated as of etag
+func (s3a *S3ApiServer) setReplicationStatus(task *replicationTask, etag string, status string) {
+
+	dir, name := s3a.objectDirAndName(task.bucket, "/"+task.key)
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil || entry == nil || entry.IsDirectory {
+		return
+	}
+	if string(entry.Extended[AmzReplicationStatus]) != ReplicationStatusPending {
+		return
+	}
+	if etag != "" && filer2.ETag(entry) != etag {
+		return
+	}
+
+	entry.Extended[AmzReplicationStatus] = []byte(status)
+	if err = s3a.updateEntry(dir, entry); err != nil {
+		glog.Errorf("set replication status of %s: %v", task, err)
+	}
+}
+
+// loopReplicationCheckpoint renews the replicator lease and saves the checkpoint, or stops following once the lease is lost
+func (s3a *S3ApiServer) loopReplicationCheckpoint(ctx context.Context, cancel context.CancelFunc, owner string) {
+	ticker := time.NewTicker(replicationCheckpointInterval)
+	defer ticker.Stop()
+
+	var saved int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !s3a.acquireLease(replicatorLeaseName, owner, replicatorLease) {
+			glog.V(0).Infof("replicator lease lost")
+			cancel()
+			return
+		}
+		if tsNs := s3a.replicator.checkpoint(); tsNs != saved {
+			if err := s3a.saveReplicationCheckpoint(tsNs); err != nil {
+				glog.Errorf("save replication checkpoint: %v", err)
+				continue
+			}
+			saved = tsNs
+		}
+	}
+}
+
+func (s3a *S3ApiServer) loadReplicationCheckpoint() (int64, error) {
+	dir, name := util.FullPath(s3a.option.BucketsPath).DirAndName()
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil {
+		return 0, fmt.Errorf("lookup %s: %v", s3a.option.BucketsPath, err)
+	}
+	if entry == nil {
+		return 0, nil
+	}
+	checkpoint, found := entry.Extended[replicationCheckpointKey]
+	if !found {
+		return 0, nil
+	}
+	return strconv.ParseInt(string(checkpoint), 10, 64)
+}
+
+func (s3a *S3ApiServer) saveReplicationCheckpoint(tsNs int64) error {
+	dir, name := util.FullPath(s3a.option.BucketsPath).DirAndName()
+	entry, err := s3a.getEntry(dir, name)
+	if err != nil {
+		return fmt.Errorf("lookup %s: %v", s3a.option.BucketsPath, err)
+	}
+	if entry == nil {
+		return nil
+	}
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	entry.Extended[replicationCheckpointKey] = []byte(strconv.FormatInt(tsNs, 10))
+	return s3a.updateEntry(dir, entry)
+}
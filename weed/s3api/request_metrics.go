@@ -0,0 +1,69 @@
+package s3api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/stats"
+)
+
+// requestBody counts the bytes of the request body read by the handler
+type requestBody struct {
+	io.ReadCloser
+	count int64
+}
+
+func (b *requestBody) Read(p []byte) (n int, err error) {
+	n, err = b.ReadCloser.Read(p)
+	b.count += int64(n)
+	return
+}
+
+// requestMetrics observes the latency, and the request and response sizes, by the operation and the status class
+func (s3a *S3ApiServer) requestMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		start := time.Now()
+		body := &requestBody{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+		writer := &accessLogWriter{ResponseWriter: w}
+		next.ServeHTTP(writer, r)
+
+		action := requestAction(r)
+		status := statusClass(writer.status)
+		requestSize := r.ContentLength
+		if requestSize < 0 {
+			requestSize = body.count
+		}
+		stats.S3RequestHistogram.WithLabelValues(action, status).Observe(time.Since(start).Seconds())
+		stats.S3RequestSizeHistogram.WithLabelValues(action, status).Observe(float64(requestSize))
+		stats.S3ResponseSizeHistogram.WithLabelValues(action, status).Observe(float64(writer.bytesSent))
+	})
+}
+
+// requestAction is the operation of the request, named as in the access log
+func requestAction(r *http.Request) string {
+	vars := mux.Vars(r)
+	if vars["bucket"] == "" {
+		return "REST." + r.Method + ".SERVICE"
+	}
+	var object string
+	if _, found := vars["object"]; found {
+		object = getObject(vars)
+	}
+	return accessLogOperation(r, object)
+}
+
+// statusClass is the class of the http status, e.g. 2xx
+func statusClass(status int) string {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,72 @@
+package s3api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/stats"
+)
+
+// histogramSample returns the count and sum of the histogram with the labels
+func histogramSample(t *testing.T, name, action, status string) (count uint64, sum float64) {
+	families, err := stats.S3Gather.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["action"] == action && labels["status"] == status {
+				return metric.GetHistogram().GetSampleCount(), metric.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	return 0, 0
+}
+
+func TestRequestMetrics(t *testing.T) {
+	s3a := &S3ApiServer{}
+	router := mux.NewRouter()
+	bucket := router.PathPrefix("/{bucket}").Subrouter()
+	bucket.Use(s3a.requestMetrics)
+	bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Write([]byte("done"))
+	})
+	bucket.Methods("GET").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	})
+
+	r := httptest.NewRequest("PUT", "/b/dir/o", strings.NewReader("0123456789"))
+	r.ContentLength = -1
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/b?acl", nil))
+
+	if count, _ := histogramSample(t, "SeaweedFS_s3_request_seconds", "REST.PUT.OBJECT", "2xx"); count != 1 {
+		t.Errorf("put latency count %d", count)
+	}
+	if count, sum := histogramSample(t, "SeaweedFS_s3_request_bytes", "REST.PUT.OBJECT", "2xx"); count != 1 || sum != 10 {
+		t.Errorf("put request size %d %v", count, sum)
+	}
+	if count, sum := histogramSample(t, "SeaweedFS_s3_response_bytes", "REST.GET.ACL", "4xx"); count != 1 || sum != 9 {
+		t.Errorf("get acl response size %d %v", count, sum)
+	}
+
+	for status, class := range map[int]string{0: "2xx", 204: "2xx", 304: "3xx", 412: "4xx", 503: "5xx"} {
+		if statusClass(status) != class {
+			t.Errorf("status class of %d: %s", status, statusClass(status))
+		}
+	}
+}
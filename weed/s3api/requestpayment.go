@@ -0,0 +1,98 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/RequesterPaysBuckets.html
+// The requests to the Requester Pays buckets need to acknowledge the charges with the x-amz-request-payer header,
+// except from the bucket owner, or the trusted requesters if no identities are configured.
+
+const (
+	AmzRequestPayer   = "X-Amz-Request-Payer"
+	AmzRequestCharged = "X-Amz-Request-Charged"
+
+	// extended attribute key on the bucket entry
+	bucketRequestPaymentKey = "s3-request-payment"
+
+	bucketRequestPaymentCacheTTL = 5 * time.Second
+
+	PayerBucketOwner = "BucketOwner"
+	PayerRequester   = "Requester"
+
+	requestPayerRequester = "requester"
+)
+
+// RequestPaymentConfigurationResult is the xml of ?requestPayment, the generated RequestPaymentConfiguration has no xmlns
+type RequestPaymentConfigurationResult struct {
+	XMLName xml.Name `xml:"RequestPaymentConfiguration"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Payer   string   `xml:"Payer"`
+}
+
+type bucketRequestPayment struct {
+	owner         string
+	requesterPays bool
+}
+
+func (s3a *S3ApiServer) getBucketRequestPayment(bucket string) (*bucketRequestPayment, error) {
+
+	if item := s3a.requestPaymentCache.Get(bucket); item != nil && !item.Expired() {
+		return item.Value().(*bucketRequestPayment), nil
+	}
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		return nil, err
+	}
+	payment := &bucketRequestPayment{}
+	if entry != nil {
+		payment.owner = string(entry.Extended[bucketOwnerKey])
+		payment.requesterPays = string(entry.Extended[bucketRequestPaymentKey]) == PayerRequester
+	}
+
+	s3a.requestPaymentCache.Set(bucket, payment, bucketRequestPaymentCacheTTL)
+	return payment, nil
+}
+
+// checkRequestPayer rejects the requests to the Requester Pays buckets without the x-amz-request-payer header,
+// and the anonymous requests which have no requester to charge
+func (iam *IdentityAccessManagement) checkRequestPayer(r *http.Request, requester *requester, bucket string) ErrorCode {
+
+	if bucket == "" || requester.trusted || iam.loadRequestPayment == nil {
+		return ErrNone
+	}
+	payment, err := iam.loadRequestPayment(bucket)
+	if err != nil {
+		return ErrInternalError
+	}
+	if !payment.requesterPays {
+		return ErrNone
+	}
+	if requester.identity == nil {
+		return ErrAccessDenied
+	}
+	if r.Header.Get(AmzRequestPayer) == requestPayerRequester || payment.owner != "" && requester.identity.Name == payment.owner {
+		return ErrNone
+	}
+	return ErrAccessDenied
+}
+
+// setRequestCharged confirms the charges to the requester, if the request is to a Requester Pays bucket
+func (iam *IdentityAccessManagement) setRequestCharged(w http.ResponseWriter, r *http.Request) {
+
+	if r.Header.Get(AmzRequestPayer) != requestPayerRequester || iam.loadRequestPayment == nil {
+		return
+	}
+	bucket := mux.Vars(r)["bucket"]
+	if bucket == "" {
+		return
+	}
+	if payment, err := iam.loadRequestPayment(bucket); err == nil && payment.requesterPays {
+		w.Header().Set(AmzRequestCharged, requestPayerRequester)
+	}
+}
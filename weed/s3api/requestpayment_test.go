@@ -0,0 +1,46 @@
+package s3api
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestAuthorizeWithRequestPayment(t *testing.T) {
+
+	iam := &IdentityAccessManagement{
+		loadRequestPayment: func(bucket string) (*bucketRequestPayment, error) {
+			return &bucketRequestPayment{owner: "owner", requesterPays: bucket == "paid"}, nil
+		},
+		loadBucketPolicy: func(bucket string) (*BucketPolicy, error) {
+			return parseBucketPolicy([]byte(`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::*"}]}`))
+		},
+	}
+	owner := &requester{identity: &Identity{Name: "owner", Actions: []Action{ACTION_ADMIN}}}
+	reader := &requester{identity: &Identity{Name: "reader", Actions: []Action{ACTION_READ}}}
+
+	tests := []struct {
+		requester *requester
+		bucket    string
+		payer     string
+		expected  ErrorCode
+	}{
+		{reader, "free", "", ErrNone},
+		{reader, "paid", "", ErrAccessDenied},
+		{reader, "paid", "requester", ErrNone},
+		{reader, "paid", "bucket-owner", ErrAccessDenied},
+		{owner, "paid", "", ErrNone},
+		{&requester{trusted: true}, "paid", "", ErrNone},
+		{&requester{}, "free", "", ErrNone},
+		{&requester{}, "paid", "requester", ErrAccessDenied},
+	}
+	for i, test := range tests {
+		r := &http.Request{Header: make(http.Header), URL: &url.URL{}, RemoteAddr: "127.0.0.1:12345"}
+		if test.payer != "" {
+			r.Header.Set(AmzRequestPayer, test.payer)
+		}
+		if errCode := iam.authorize(r, test.requester, ACTION_READ, "s3:GetObject", test.bucket, "/a.txt"); errCode != test.expected {
+			t.Errorf("%d %s %q: got %v, expecting %v", i, test.bucket, test.payer, errCode, test.expected)
+		}
+	}
+}
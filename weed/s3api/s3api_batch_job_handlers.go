@@ -0,0 +1,202 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+type CreateJobResult struct {
+	XMLName xml.Name `xml:"http://awss3control.amazonaws.com/doc/2018-08-20/ CreateJobResult"`
+	JobId   string   `xml:"JobId"`
+}
+
+type DescribeJobResult struct {
+	XMLName xml.Name      `xml:"http://awss3control.amazonaws.com/doc/2018-08-20/ DescribeJobResult"`
+	Job     JobDescriptor `xml:"Job"`
+}
+
+type JobDescriptor struct {
+	JobId             string                `xml:"JobId"`
+	Description       string                `xml:"Description,omitempty"`
+	Status            string                `xml:"Status"`
+	CreationTime      time.Time             `xml:"CreationTime"`
+	TerminationDate   *time.Time            `xml:"TerminationDate,omitempty"`
+	Operation         JobOperation          `xml:"Operation"`
+	Manifest          *JobManifest          `xml:"Manifest,omitempty"`
+	ManifestGenerator *JobManifestGenerator `xml:"ManifestGenerator,omitempty"`
+	Report            JobReport             `xml:"Report"`
+	ProgressSummary   JobProgressSummary    `xml:"ProgressSummary"`
+	FailureReasons    []JobFailure          `xml:"FailureReasons>JobFailure,omitempty"`
+}
+
+type JobFailure struct {
+	FailureCode   string `xml:"FailureCode"`
+	FailureReason string `xml:"FailureReason"`
+}
+
+type ListJobsResult struct {
+	XMLName xml.Name            `xml:"http://awss3control.amazonaws.com/doc/2018-08-20/ ListJobsResult"`
+	Jobs    []JobListDescriptor `xml:"Jobs>member"`
+}
+
+type JobListDescriptor struct {
+	JobId           string             `xml:"JobId"`
+	Description     string             `xml:"Description,omitempty"`
+	Operation       string             `xml:"Operation"`
+	Status          string             `xml:"Status"`
+	CreationTime    time.Time          `xml:"CreationTime"`
+	TerminationDate *time.Time         `xml:"TerminationDate,omitempty"`
+	ProgressSummary JobProgressSummary `xml:"ProgressSummary"`
+}
+
+type UpdateJobStatusResult struct {
+	XMLName xml.Name `xml:"http://awss3control.amazonaws.com/doc/2018-08-20/ UpdateJobStatusResult"`
+	JobId   string   `xml:"JobId"`
+	Status  string   `xml:"Status"`
+}
+
+// CreateJobHandler - POST /v20180820/jobs
+func (s3a *S3ApiServer) CreateJobHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_control_CreateJob.html
+
+	if s3a.option.BatchJobConcurrency <= 0 {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	request := CreateJobRequest{}
+	if err := decodeXMLBody(r, &request, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if errCode := request.validate(); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if _, errCode := s3a.prepareJobOperation(request.Operation); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	job := &batchJob{
+		JobId:        uuid.New().String(),
+		Status:       JobStatusNew,
+		CreationTime: time.Now().UTC(),
+		Request:      request,
+	}
+	if err := s3a.createBatchJob(job); err != nil {
+		glog.Errorf("create batch job: %v", err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	s3a.wakeUpBatchJobs()
+
+	writeSuccessResponseXML(w, encodeResponse(CreateJobResult{JobId: job.JobId}))
+}
+
+// ListJobsHandler - GET /v20180820/jobs
+func (s3a *S3ApiServer) ListJobsHandler(w http.ResponseWriter, r *http.Request) {
+
+	jobs, err := s3a.listBatchJobs()
+	if err != nil {
+		glog.Errorf("list batch jobs: %v", err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	statuses := make(map[string]bool)
+	for _, status := range r.URL.Query()["jobStatuses"] {
+		statuses[status] = true
+	}
+
+	// the most recent jobs first
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTime.After(jobs[j].CreationTime)
+	})
+
+	response := ListJobsResult{}
+	for _, job := range jobs {
+		if len(statuses) > 0 && !statuses[job.Status] {
+			continue
+		}
+		response.Jobs = append(response.Jobs, JobListDescriptor{
+			JobId:           job.JobId,
+			Description:     job.Request.Description,
+			Operation:       job.operationName(),
+			Status:          job.Status,
+			CreationTime:    job.CreationTime,
+			TerminationDate: job.TerminationDate,
+			ProgressSummary: job.ProgressSummary,
+		})
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// DescribeJobHandler - GET /v20180820/jobs/{id}
+func (s3a *S3ApiServer) DescribeJobHandler(w http.ResponseWriter, r *http.Request) {
+
+	job, err := s3a.loadBatchJob(mux.Vars(r)["id"])
+	if err != nil {
+		glog.Errorf("load batch job: %v", err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if job == nil {
+		writeErrorResponse(w, ErrNoSuchJob, r.URL)
+		return
+	}
+
+	descriptor := JobDescriptor{
+		JobId:             job.JobId,
+		Description:       job.Request.Description,
+		Status:            job.Status,
+		CreationTime:      job.CreationTime,
+		TerminationDate:   job.TerminationDate,
+		Operation:         job.Request.Operation,
+		Manifest:          job.Request.Manifest,
+		ManifestGenerator: job.Request.ManifestGenerator,
+		Report:            job.Request.Report,
+		ProgressSummary:   job.ProgressSummary,
+	}
+	if job.FailureReason != "" {
+		descriptor.FailureReasons = []JobFailure{{FailureCode: job.Status, FailureReason: job.FailureReason}}
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(DescribeJobResult{Job: descriptor}))
+}
+
+// UpdateJobStatusHandler - POST /v20180820/jobs/{id}/status?requestedJobStatus=Cancelled
+func (s3a *S3ApiServer) UpdateJobStatusHandler(w http.ResponseWriter, r *http.Request) {
+
+	// the jobs run once created, so they can only be cancelled
+	vars := mux.Vars(r)
+	if vars["requestedJobStatus"] != JobStatusCancelled {
+		writeErrorResponse(w, ErrJobStatus, r.URL)
+		return
+	}
+
+	job, errCode := s3a.cancelBatchJob(vars["id"])
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.wakeUpBatchJobs()
+
+	writeSuccessResponseXML(w, encodeResponse(UpdateJobStatusResult{JobId: job.JobId, Status: job.Status}))
+}
+
+func (s3a *S3ApiServer) wakeUpBatchJobs() {
+	select {
+	case s3a.batchJobWakeUp <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,97 @@
+package s3api
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutBucketAclHandler - PUT bucket ?acl
+func (s3a *S3ApiServer) PutBucketAclHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketAcl.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	current, err := s3a.getBucketAcl(bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s acl: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if current == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	acl, errCode := readAclRequest(r, current.owner)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		if acl == CannedAclPrivate {
+			delete(extended, bucketAclKey)
+		} else {
+			extended[bucketAclKey] = []byte(acl)
+		}
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.aclCache.Delete(bucket)
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketAclHandler - GET bucket ?acl
+func (s3a *S3ApiServer) GetBucketAclHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	acl, err := s3a.getBucketAcl(bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s acl: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if acl == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	policy := newAccessControlPolicy(acl.owner, acl.acl)
+	policy.Xmlns = s3Namespace
+	writeSuccessResponseXML(w, encodeResponse(policy))
+}
+
+// readAclRequest returns the canned ACL of the x-amz-acl header, or of the grants in the request body
+func readAclRequest(r *http.Request, owner string) (string, ErrorCode) {
+
+	acl, errCode := prepareCannedAcl(r)
+	if errCode != ErrNone || acl != "" {
+		return acl, errCode
+	}
+
+	policy := &AccessControlPolicyResult{}
+	if err := decodeXMLBody(r, policy, maxXMLBodySize); err != nil {
+		switch err.(type) {
+		case *xmlSyntaxError:
+			return "", ErrMalformedACLError
+		}
+		switch err {
+		case errXMLBodyTooLarge:
+			return "", ErrMaxMessageLengthExceeded
+		case io.EOF, io.ErrUnexpectedEOF:
+			return "", ErrMalformedACLError
+		}
+		return "", ErrIncompleteBody
+	}
+	return policy.cannedAcl(owner)
+}
@@ -0,0 +1,129 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutBucketCorsHandler - PUT bucket ?cors
+func (s3a *S3ApiServer) PutBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketCors.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &CORSConfiguration{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if errCode := config.validate(); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	config.Xmlns = ""
+	configXMLBytes, _ := xml.Marshal(config)
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		extended[bucketCORSConfigurationKey] = configXMLBytes
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.corsCache.Delete(bucket)
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketCorsHandler - GET bucket ?cors
+func (s3a *S3ApiServer) GetBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if entry == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+	config, err := loadCORSConfiguration(entry.Extended)
+	if err != nil {
+		glog.Errorf("bucket %s has invalid cors configuration: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if config == nil {
+		writeErrorResponse(w, ErrNoSuchCORSConfiguration, r.URL)
+		return
+	}
+
+	config.Xmlns = s3Namespace
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
+
+// DeleteBucketCorsHandler - DELETE bucket ?cors
+func (s3a *S3ApiServer) DeleteBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		delete(extended, bucketCORSConfigurationKey)
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.corsCache.Delete(bucket)
+
+	writeResponse(w, http.StatusNoContent, nil, mimeNone)
+}
+
+// PreflightHandler - OPTIONS bucket or object, sent by browsers before the cross-origin requests.
+// The preflight requests are not signed, and are answered by the cors configuration of the bucket.
+func (s3a *S3ApiServer) PreflightHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTOPTIONSobject.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	origin := r.Header.Get("Origin")
+	method := r.Header.Get("Access-Control-Request-Method")
+	if origin == "" || method == "" {
+		writeErrorResponse(w, ErrMissingCORSHeaders, r.URL)
+		return
+	}
+	requestHeaders := parseCORSRequestHeaders(r.Header.Get("Access-Control-Request-Headers"))
+
+	config, err := s3a.getBucketCORSConfiguration(bucket)
+	if err != nil {
+		glog.Errorf("bucket %s cors configuration: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if config == nil {
+		writeErrorResponse(w, ErrCORSForbidden, r.URL)
+		return
+	}
+
+	w.Header().Add("Vary", "Origin, Access-Control-Request-Headers, Access-Control-Request-Method")
+	rule := config.match(origin, method, requestHeaders)
+	if rule == nil {
+		writeErrorResponse(w, ErrCORSForbidden, r.URL)
+		return
+	}
+	rule.setHeaders(w.Header(), origin, requestHeaders, true)
+
+	writeSuccessResponseEmpty(w)
+}
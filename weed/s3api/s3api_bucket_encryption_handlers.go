@@ -0,0 +1,82 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutBucketEncryptionHandler - PUT bucket ?encryption
+func (s3a *S3ApiServer) PutBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketEncryption.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &ServerSideEncryptionConfiguration{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if errCode := config.validate(); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if s3a.masterKey == nil {
+		glog.V(1).Infof("bucket %s encryption: s3.encryption.key is not configured", bucket)
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	config.Xmlns = ""
+	configXMLBytes, _ := xml.Marshal(config)
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		extended[bucketEncryptionConfigurationKey] = configXMLBytes
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketEncryptionHandler - GET bucket ?encryption
+func (s3a *S3ApiServer) GetBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config, errCode := s3a.getBucketEncryptionConfiguration(bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if config == nil {
+		writeErrorResponse(w, ErrNoSuchBucketEncryptionConfiguration, r.URL)
+		return
+	}
+
+	config.Xmlns = s3Namespace
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
+
+// DeleteBucketEncryptionHandler - DELETE bucket ?encryption
+func (s3a *S3ApiServer) DeleteBucketEncryptionHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		delete(extended, bucketEncryptionConfigurationKey)
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeResponse(w, http.StatusNoContent, nil, mimeNone)
+}
@@ -6,26 +6,39 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/gorilla/mux"
 
+	"github.com/chrislusf/seaweedfs/weed/filer2"
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
 )
 
+// bucketCreationTimeKey keeps the creation time of the bucket, the creation time of the entry is in seconds,
+// and may not be kept by all the filer stores
+const bucketCreationTimeKey = "s3-created"
+
 type ListAllMyBucketsResult struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
 	Owner   *s3.Owner
 	Buckets []*s3.Bucket `xml:"Buckets>Bucket"`
 }
 
+// ListBucketsHandler lists the buckets the requester can access, by name, with the requester as the owner
 func (s3a *S3ApiServer) ListBucketsHandler(w http.ResponseWriter, r *http.Request) {
 
 	var response ListAllMyBucketsResult
 
+	requester := getRequester(r)
+	if !requester.trusted && requester.identity == nil {
+		writeErrorResponse(w, ErrAccessDenied, r.URL)
+		return
+	}
+
 	entries, err := s3a.list(s3a.option.BucketsPath, "", "", false, math.MaxInt32)
 
 	if err != nil {
@@ -35,18 +48,25 @@ func (s3a *S3ApiServer) ListBucketsHandler(w http.ResponseWriter, r *http.Reques
 
 	var buckets []*s3.Bucket
 	for _, entry := range entries {
-		if entry.IsDirectory {
+		if entry.IsDirectory && s3a.canAccessBucket(r, requester, entry) {
 			buckets = append(buckets, &s3.Bucket{
 				Name:         aws.String(entry.Name),
-				CreationDate: aws.Time(time.Unix(entry.Attributes.Crtime, 0).UTC()),
+				CreationDate: aws.Time(bucketCreationTime(entry)),
 			})
 		}
 	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return *buckets[i].Name < *buckets[j].Name
+	})
 
+	ownerName := ""
+	if requester.identity != nil {
+		ownerName = requester.identity.Name
+	}
 	response = ListAllMyBucketsResult{
 		Owner: &s3.Owner{
-			ID:          aws.String(""),
-			DisplayName: aws.String(""),
+			ID:          aws.String(ownerName),
+			DisplayName: aws.String(ownerName),
 		},
 		Buckets: buckets,
 	}
@@ -59,21 +79,167 @@ func (s3a *S3ApiServer) PutBucketHandler(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 
+	acl, errCode := prepareCannedAcl(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	constraint, ok := s3a.locationConstraint(w, r)
+	if !ok {
+		return
+	}
+
 	// create the folder for bucket, but lazily create actual collection
-	if err := s3a.mkdir(s3a.option.BucketsPath, bucket, nil); err != nil {
+	now := timeNow().UTC()
+	if err := s3a.mkdir(s3a.option.BucketsPath, bucket, func(entry *filer_pb.Entry) {
+		entry.Attributes.Crtime = now.Unix()
+		entry.Extended = make(map[string][]byte)
+		entry.Extended[bucketCreationTimeKey] = []byte(now.Format(time.RFC3339Nano))
+		if r.Header.Get(AmzBucketObjectLockEnabled) == "true" {
+			entry.Extended[bucketObjectLockConfigurationKey], _ = xml.Marshal(&ObjectLockConfiguration{
+				ObjectLockEnabled: ObjectLockEnabled,
+			})
+		}
+		if requester := getRequester(r); requester.identity != nil {
+			entry.Extended[bucketOwnerKey] = []byte(requester.identity.Name)
+		}
+		if acl != "" && acl != CannedAclPrivate {
+			entry.Extended[bucketAclKey] = []byte(acl)
+		}
+		if constraint != "" {
+			entry.Extended[bucketLocationConstraintKey] = []byte(constraint)
+		}
+	}); err != nil {
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
 
+	w.Header().Set("Location", "/"+bucket)
 	writeSuccessResponseEmpty(w)
 }
 
+// bucketCreationTime returns the creation time kept with the bucket, or the creation time of the bucket entry
+func bucketCreationTime(entry *filer_pb.Entry) time.Time {
+	if created, err := time.Parse(time.RFC3339Nano, string(entry.Extended[bucketCreationTimeKey])); err == nil {
+		return created.UTC()
+	}
+	if entry.Attributes == nil {
+		return time.Unix(0, 0).UTC()
+	}
+	if entry.Attributes.Crtime == 0 {
+		return time.Unix(entry.Attributes.Mtime, 0).UTC()
+	}
+	return time.Unix(entry.Attributes.Crtime, 0).UTC()
+}
+
+// canAccessBucket returns true if the bucket policy allows the requester to list the bucket,
+// or if the requester owns the bucket or has any permission on it, unless the bucket policy denies the listing
+func (s3a *S3ApiServer) canAccessBucket(r *http.Request, requester *requester, entry *filer_pb.Entry) bool {
+	decision, errCode := s3a.iam.evaluateBucketPolicy(r, requester, "s3:ListBucket", entry.Name, "")
+	if errCode != ErrNone {
+		return false
+	}
+	switch decision {
+	case policyDenied:
+		return false
+	case policyAllowed:
+		return true
+	}
+	if requester.trusted {
+		return true
+	}
+	if requester.identity == nil {
+		return false
+	}
+	if owner := string(entry.Extended[bucketOwnerKey]); owner != "" && owner == requester.identity.Name {
+		return true
+	}
+	for _, action := range []Action{ACTION_READ, ACTION_WRITE, ACTION_ADMIN} {
+		if requester.identity.canDo(action, entry.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteBucketHandler deletes an empty bucket, with no objects, versions, or multipart uploads in progress.
+// The non-standard ?force=true deletes the bucket with all its contents in the background, for the admins only,
+// and is refused for the buckets with object lock or append only volumes.
 func (s3a *S3ApiServer) DeleteBucketHandler(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 
-	err := s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+	force := r.URL.Query().Get("force") == "true"
+	if force {
+		if requester := getRequester(r); !requester.trusted && (requester.identity == nil || !requester.identity.canDo(ACTION_ADMIN, bucket)) {
+			writeErrorResponse(w, ErrAccessDenied, r.URL)
+			return
+		}
+	}
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if entry == nil || !entry.IsDirectory {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	if force {
+		// the locked objects and the append only volumes must not go away with the bucket
+		objectLock, errCode := s3a.getBucketObjectLockConfiguration(bucket)
+		if errCode != ErrNone {
+			writeErrorResponse(w, errCode, r.URL)
+			return
+		}
+		if objectLock.isEnabled() || string(entry.Extended[filer2.BucketAppendOnlyKey]) == "true" {
+			writeErrorResponse(w, ErrAccessDenied, r.URL)
+			return
+		}
+
+		// a large bucket takes long to delete, so the objects are removed in the background
+		go func() {
+			if err := s3a.deleteBucketData(bucket); err != nil {
+				glog.Errorf("force delete bucket %s: %v", bucket, err)
+			}
+		}()
+		writeResponse(w, http.StatusNoContent, nil, mimeNone)
+		return
+	}
+
+	hasContent, err := s3a.hasFiles(s3a.option.BucketsPath + "/" + bucket)
+	if err != nil {
+		glog.Errorf("list bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if hasContent {
+		writeErrorResponse(w, ErrBucketNotEmpty, r.URL)
+		return
+	}
+
+	if err = s3a.deleteBucketData(bucket); err != nil {
+		glog.Errorf("delete bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	writeResponse(w, http.StatusNoContent, nil, mimeNone)
+}
+
+// deleteBucketData removes the bucket folder, then the collection of the bucket,
+// so the entries left by a failed removal still have their data.
+func (s3a *S3ApiServer) deleteBucketData(bucket string) error {
+
+	if err := s3a.rmTree(s3a.option.BucketsPath, bucket, false); err != nil {
+		return err
+	}
+
+	return s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
 
 		// delete collection
 		deleteCollectionRequest := &filer_pb.DeleteCollectionRequest{
@@ -87,15 +253,30 @@ func (s3a *S3ApiServer) DeleteBucketHandler(w http.ResponseWriter, r *http.Reque
 
 		return nil
 	})
+}
 
-	err = s3a.rm(s3a.option.BucketsPath, bucket, false, true)
-
-	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
-		return
+// hasFiles checks for any file under the directory, including the versions and the multipart upload parts.
+// The empty directories left by the deleted objects do not count.
+func (s3a *S3ApiServer) hasFiles(dir string) (found bool, err error) {
+	startFrom := ""
+	for {
+		entries, err := s3a.list(dir, "", startFrom, false, 1024)
+		if err != nil {
+			return false, err
+		}
+		for _, entry := range entries {
+			startFrom = entry.Name
+			if !entry.IsDirectory {
+				return true, nil
+			}
+			if found, err = s3a.hasFiles(dir + "/" + entry.Name); found || err != nil {
+				return found, err
+			}
+		}
+		if len(entries) < 1024 {
+			return false, nil
+		}
 	}
-
-	writeResponse(w, http.StatusNoContent, nil, mimeNone)
 }
 
 func (s3a *S3ApiServer) HeadBucketHandler(w http.ResponseWriter, r *http.Request) {
@@ -103,28 +284,41 @@ func (s3a *S3ApiServer) HeadBucketHandler(w http.ResponseWriter, r *http.Request
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 
-	err := s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
-
-		request := &filer_pb.LookupDirectoryEntryRequest{
-			Directory: s3a.option.BucketsPath,
-			Name:      bucket,
+	glog.V(1).Infof("lookup bucket: %s/%s", s3a.option.BucketsPath, bucket)
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil || entry == nil {
+		if err != nil {
+			glog.Errorf("lookup bucket %s/%s: %v", s3a.option.BucketsPath, bucket, err)
 		}
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
 
-		glog.V(1).Infof("lookup bucket: %v", request)
-		if _, err := filer_pb.LookupEntry(client, request); err != nil {
-			if err == filer_pb.ErrNotFound {
-				return filer_pb.ErrNotFound
-			}
-			return fmt.Errorf("lookup bucket %s/%s: %v", s3a.option.BucketsPath, bucket, err)
-		}
+	w.Header().Set(AmzBucketRegion, s3a.bucketRegion(entry))
+	writeSuccessResponseEmpty(w)
+}
 
-		return nil
-	})
+// updateBucketExtended changes the extended attributes of the bucket entry, where the bucket configurations are kept
+func (s3a *S3ApiServer) updateBucketExtended(bucket string, fn func(extended map[string][]byte)) ErrorCode {
 
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
 	if err != nil {
-		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
-		return
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		return ErrInternalError
+	}
+	if entry == nil {
+		return ErrNoSuchBucket
 	}
 
-	writeSuccessResponseEmpty(w)
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	fn(entry.Extended)
+
+	if err = s3a.updateEntry(s3a.option.BucketsPath, entry); err != nil {
+		glog.Errorf("update bucket %s: %v", bucket, err)
+		return ErrInternalError
+	}
+
+	return ErrNone
 }
@@ -1,11 +1,21 @@
 package s3api
 
 import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
 func TestListBucketsHandler(t *testing.T) {
@@ -37,3 +47,205 @@ func TestListBucketsHandler(t *testing.T) {
 		t.Errorf("unexpected output: %s\nexpecting:%s", encoded, expected)
 	}
 }
+
+func withRequester(r *http.Request, requester *requester) *http.Request {
+	if requester == nil {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), requesterContextKey, requester))
+}
+
+func TestListBucketsCreationTimeAndOwner(t *testing.T) {
+	s3a, stop := startFakeFiler(t, make(map[util.FullPath]*filer_pb.Entry))
+	defer stop()
+
+	previous := timeNow
+	defer func() {
+		timeNow = previous
+	}()
+
+	alice := &requester{identity: &Identity{Name: "alice", Actions: []Action{ACTION_READ + ":beta"}}}
+	bob := &requester{identity: &Identity{Name: "bob", Actions: []Action{ACTION_WRITE}}}
+	created := map[string]time.Time{
+		"beta":  time.Date(2020, 3, 1, 10, 0, 0, 123000000, time.UTC),
+		"alpha": time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC),
+		"gamma": time.Date(2020, 3, 3, 10, 0, 0, 0, time.UTC),
+		"delta": time.Date(2020, 3, 4, 10, 0, 0, 0, time.UTC),
+	}
+	owners := map[string]*requester{"gamma": alice, "delta": bob}
+	for _, bucket := range []string{"beta", "alpha", "gamma", "delta"} {
+		createdAt := created[bucket]
+		timeNow = func() time.Time {
+			return createdAt
+		}
+		r := httptest.NewRequest("PUT", "/"+bucket, nil)
+		r = withRequester(mux.SetURLVars(r, map[string]string{"bucket": bucket}), owners[bucket])
+		w := httptest.NewRecorder()
+		s3a.PutBucketHandler(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("create %s: %d %s", bucket, w.Code, w.Body.String())
+		}
+	}
+
+	tests := []struct {
+		requester *requester
+		code      int
+		owner     string
+		buckets   []string
+	}{
+		{nil, http.StatusOK, "", []string{"alpha", "beta", "delta", "gamma"}},
+		{alice, http.StatusOK, "alice", []string{"beta", "gamma"}},
+		{bob, http.StatusOK, "bob", []string{"alpha", "beta", "delta", "gamma"}},
+		{&requester{identity: &Identity{Name: "carol"}}, http.StatusOK, "carol", nil},
+		{&requester{}, http.StatusForbidden, "", nil},
+	}
+	for i, test := range tests {
+		w := httptest.NewRecorder()
+		s3a.ListBucketsHandler(w, withRequester(httptest.NewRequest("GET", "/", nil), test.requester))
+		if w.Code != test.code {
+			t.Errorf("%d: status %d %s", i, w.Code, w.Body.String())
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		var result ListAllMyBucketsResult
+		if err := xml.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if *result.Owner.ID != test.owner || *result.Owner.DisplayName != test.owner {
+			t.Errorf("%d: owner %v", i, result.Owner)
+		}
+		var names []string
+		for _, bucket := range result.Buckets {
+			names = append(names, *bucket.Name)
+			if !bucket.CreationDate.Equal(created[*bucket.Name]) {
+				t.Errorf("%d: bucket %s created at %v, expected %v", i, *bucket.Name, *bucket.CreationDate, created[*bucket.Name])
+			}
+		}
+		if !reflect.DeepEqual(names, test.buckets) {
+			t.Errorf("%d: buckets %v, expected %v", i, names, test.buckets)
+		}
+	}
+}
+
+func TestListBucketsWithBucketPolicy(t *testing.T) {
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{
+		"/buckets/public":  {Name: "public", IsDirectory: true},
+		"/buckets/private": {Name: "private", IsDirectory: true},
+		"/buckets/shared":  {Name: "shared", IsDirectory: true},
+	})
+	defer stop()
+	s3a.iam.loadBucketPolicy = func(bucket string) (*BucketPolicy, error) {
+		switch bucket {
+		case "public":
+			return parseBucketPolicy([]byte(`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:ListBucket", "Resource": "arn:aws:s3:::public"}]}`))
+		case "private":
+			return parseBucketPolicy([]byte(`{"Statement": [{"Effect": "Deny", "Principal": {"AWS": "bob"}, "Action": "s3:*", "Resource": "arn:aws:s3:::private"}]}`))
+		}
+		return nil, nil
+	}
+
+	tests := []struct {
+		requester *requester
+		buckets   []string
+	}{
+		{&requester{identity: &Identity{Name: "carol"}}, []string{"public"}},
+		{&requester{identity: &Identity{Name: "bob", Actions: []Action{ACTION_READ}}}, []string{"public", "shared"}},
+		{&requester{identity: &Identity{Name: "dave", Actions: []Action{ACTION_READ}}}, []string{"private", "public", "shared"}},
+	}
+	for i, test := range tests {
+		w := httptest.NewRecorder()
+		s3a.ListBucketsHandler(w, withRequester(httptest.NewRequest("GET", "/", nil), test.requester))
+		var result ListAllMyBucketsResult
+		if err := xml.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("%d: %v %s", i, err, w.Body.String())
+		}
+		var names []string
+		for _, bucket := range result.Buckets {
+			names = append(names, *bucket.Name)
+		}
+		if !reflect.DeepEqual(names, test.buckets) {
+			t.Errorf("%d: buckets %v, expected %v", i, names, test.buckets)
+		}
+	}
+}
+
+func deleteBucket(s3a *S3ApiServer, bucket, query string, requester *requester) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("DELETE", "/"+bucket+query, nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": bucket})
+	if requester != nil {
+		r = r.WithContext(context.WithValue(r.Context(), requesterContextKey, requester))
+	}
+	w := httptest.NewRecorder()
+	s3a.DeleteBucketHandler(w, r)
+	return w
+}
+
+func TestDeleteBucket(t *testing.T) {
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets/empty":                   {Name: "empty", IsDirectory: true},
+		"/buckets/empty/dir":               {Name: "dir", IsDirectory: true},
+		"/buckets/full":                    {Name: "full", IsDirectory: true},
+		"/buckets/full/dir":                {Name: "dir", IsDirectory: true},
+		"/buckets/full/dir/object":         {Name: "object"},
+		"/buckets/uploading":               {Name: "uploading", IsDirectory: true},
+		"/buckets/uploading/.uploads":      {Name: ".uploads", IsDirectory: true},
+		"/buckets/uploading/.uploads/u1":   {Name: "u1", IsDirectory: true},
+		"/buckets/uploading/.uploads/u1/p": {Name: "0000.part"},
+		"/buckets/locked": {Name: "locked", IsDirectory: true, Extended: map[string][]byte{
+			bucketObjectLockConfigurationKey: []byte(`<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>`),
+		}},
+		"/buckets/appendonly": {Name: "appendonly", IsDirectory: true, Extended: map[string][]byte{
+			filer2.BucketAppendOnlyKey: []byte("true"),
+		}},
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+
+	user := &requester{identity: &Identity{Name: "user", Actions: []Action{ACTION_WRITE}}}
+	admin := &requester{identity: &Identity{Name: "admin", Actions: []Action{ACTION_ADMIN}}}
+
+	tests := []struct {
+		bucket    string
+		query     string
+		requester *requester
+		code      int
+	}{
+		{"missing", "", nil, http.StatusNotFound},
+		{"full", "", nil, http.StatusConflict},
+		{"uploading", "", nil, http.StatusConflict},
+		{"full", "?force=true", user, http.StatusForbidden},
+		{"empty", "", user, http.StatusNoContent},
+		{"full", "?force=true", admin, http.StatusNoContent},
+		{"uploading", "?force=true", nil, http.StatusNoContent},
+		{"locked", "?force=true", admin, http.StatusForbidden},
+		{"appendonly", "?force=true", admin, http.StatusForbidden},
+	}
+	for _, test := range tests {
+		if w := deleteBucket(s3a, test.bucket, test.query, test.requester); w.Code != test.code {
+			t.Errorf("delete bucket %s%s: %d %s", test.bucket, test.query, w.Code, w.Body.String())
+		}
+	}
+
+	// the forced deletions run in the background
+	for _, bucket := range []string{"full", "uploading"} {
+		for i := 0; ; i++ {
+			exists, err := s3a.exists(s3a.option.BucketsPath, bucket, true)
+			if err != nil {
+				t.Fatalf("lookup bucket %s: %v", bucket, err)
+			}
+			if !exists {
+				break
+			}
+			if i == 100 {
+				t.Fatalf("bucket %s is not deleted", bucket)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if _, found := entries["/buckets/locked"]; !found || len(entries) != 2 {
+		t.Errorf("entries left %v", entries)
+	}
+}
@@ -0,0 +1,75 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// PutBucketLifecycleConfigurationHandler - PUT bucket ?lifecycle
+func (s3a *S3ApiServer) PutBucketLifecycleConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketLifecycleConfiguration.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &LifecycleConfiguration{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if errCode := config.validate(); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	config.Xmlns = ""
+	configXMLBytes, _ := xml.Marshal(config)
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		extended[bucketLifecycleConfigurationKey] = configXMLBytes
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketLifecycleConfigurationHandler - GET bucket ?lifecycle
+func (s3a *S3ApiServer) GetBucketLifecycleConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config, errCode := s3a.getBucketLifecycleConfiguration(bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if config == nil {
+		writeErrorResponse(w, ErrNoSuchLifecycleConfiguration, r.URL)
+		return
+	}
+
+	config.Xmlns = s3Namespace
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
+
+// DeleteBucketLifecycleHandler - DELETE bucket ?lifecycle
+func (s3a *S3ApiServer) DeleteBucketLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		delete(extended, bucketLifecycleConfigurationKey)
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeResponse(w, http.StatusNoContent, nil, mimeNone)
+}
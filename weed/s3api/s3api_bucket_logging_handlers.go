@@ -0,0 +1,96 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutBucketLoggingHandler - PUT bucket ?logging
+func (s3a *S3ApiServer) PutBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketLogging.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &BucketLoggingConfiguration{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+
+	if target := config.LoggingEnabled; target != nil {
+		if target.TargetBucket == "" {
+			writeErrorResponse(w, ErrMalformedXML, r.URL)
+			return
+		}
+		exists, err := s3a.exists(s3a.option.BucketsPath, target.TargetBucket, true)
+		if err != nil {
+			glog.Errorf("lookup logging target bucket %s: %v", target.TargetBucket, err)
+			writeErrorResponse(w, ErrInternalError, r.URL)
+			return
+		}
+		if !exists {
+			writeErrorResponse(w, ErrInvalidTargetBucketForLogging, r.URL)
+			return
+		}
+		// the gateway writes the logs for the caller, who must be able to put any object under the target prefix
+		if errCode := s3a.iam.authorize(r, getRequester(r), ACTION_WRITE, "s3:PutObject", target.TargetBucket, target.TargetPrefix+"*"); errCode != ErrNone {
+			writeErrorResponse(w, errCode, r.URL)
+			return
+		}
+	}
+
+	// an empty status turns off the access logging
+	config.Xmlns = ""
+	configXMLBytes, _ := xml.Marshal(config)
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		if config.LoggingEnabled == nil {
+			delete(extended, bucketLoggingStatusKey)
+		} else {
+			extended[bucketLoggingStatusKey] = configXMLBytes
+		}
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.loggingCache.Delete(bucket)
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketLoggingHandler - GET bucket ?logging
+func (s3a *S3ApiServer) GetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if entry == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	config, err := loadBucketLoggingConfiguration(entry.Extended)
+	if err != nil {
+		glog.Errorf("bucket %s has invalid logging status: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if config == nil {
+		config = &BucketLoggingConfiguration{}
+	}
+
+	config.Xmlns = s3Namespace
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
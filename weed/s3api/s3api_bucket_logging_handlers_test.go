@@ -0,0 +1,42 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/karlseguin/ccache"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestPutBucketLoggingTargetAccess(t *testing.T) {
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{
+		"/buckets/source": {Name: "source", IsDirectory: true},
+		"/buckets/target": {Name: "target", IsDirectory: true},
+	})
+	defer stop()
+	s3a.loggingCache = ccache.New(ccache.Configure())
+
+	putLogging := func(requester *requester) int {
+		body := `<BucketLoggingStatus><LoggingEnabled><TargetBucket>target</TargetBucket><TargetPrefix>logs/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`
+		r := httptest.NewRequest("PUT", "/source?logging", strings.NewReader(body))
+		r = withRequester(mux.SetURLVars(r, map[string]string{"bucket": "source"}), requester)
+		w := httptest.NewRecorder()
+		s3a.PutBucketLoggingHandler(w, r)
+		return w.Code
+	}
+
+	// the admin of the source bucket can not write to the target bucket
+	sourceAdmin := &requester{identity: &Identity{Name: "source-admin", Actions: []Action{"Admin:source"}}}
+	if code := putLogging(sourceAdmin); code != http.StatusForbidden {
+		t.Errorf("logging to a target bucket not writable: %d", code)
+	}
+	writer := &requester{identity: &Identity{Name: "writer", Actions: []Action{"Admin:source", "Write:target"}}}
+	if code := putLogging(writer); code != http.StatusOK {
+		t.Errorf("logging to a writable target bucket: %d", code)
+	}
+}
@@ -0,0 +1,72 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutBucketNotificationHandler - PUT bucket ?notification
+func (s3a *S3ApiServer) PutBucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketNotificationConfiguration.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &BucketNotificationConfiguration{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if errCode := config.validate(); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	destinations := config.destinations()
+	if len(destinations) > 0 && s3a.notifier == nil {
+		glog.V(1).Infof("bucket %s notification: no message broker is configured", bucket)
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	// an empty configuration turns off the notifications
+	config.Xmlns = ""
+	configXMLBytes, _ := xml.Marshal(config)
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		if len(destinations) == 0 {
+			delete(extended, bucketNotificationConfigurationKey)
+		} else {
+			extended[bucketNotificationConfigurationKey] = configXMLBytes
+		}
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketNotificationHandler - GET bucket ?notification
+func (s3a *S3ApiServer) GetBucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config, errCode := s3a.getBucketNotificationConfiguration(bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if config == nil {
+		config = &BucketNotificationConfiguration{}
+	}
+
+	config.Xmlns = s3Namespace
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
@@ -0,0 +1,121 @@
+package s3api
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// the bucket policies are cached by each s3 server, changes by other s3 servers take effect after the ttl
+const bucketPolicyCacheTTL = 5 * time.Second
+
+// PutBucketPolicyHandler - PUT bucket ?policy
+func (s3a *S3ApiServer) PutBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketPolicy.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	policyBytes, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBucketPolicySize+1))
+	if err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if len(policyBytes) > maxBucketPolicySize {
+		writeErrorResponse(w, ErrMalformedPolicy, r.URL)
+		return
+	}
+
+	policy, err := parseBucketPolicy(policyBytes)
+	if err != nil {
+		glog.V(1).Infof("bucket %s policy: %v", bucket, err)
+		writeErrorResponse(w, ErrMalformedPolicy, r.URL)
+		return
+	}
+	if errCode := policy.validate(bucket); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		extended[bucketPolicyKey] = policyBytes
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.policyCache.Delete(bucket)
+
+	writeResponse(w, http.StatusNoContent, nil, mimeNone)
+}
+
+// GetBucketPolicyHandler - GET bucket ?policy
+func (s3a *S3ApiServer) GetBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if entry == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+	policyBytes, found := entry.Extended[bucketPolicyKey]
+	if !found {
+		writeErrorResponse(w, ErrNoSuchBucketPolicy, r.URL)
+		return
+	}
+
+	writeResponse(w, http.StatusOK, policyBytes, mimeJSON)
+}
+
+// DeleteBucketPolicyHandler - DELETE bucket ?policy
+func (s3a *S3ApiServer) DeleteBucketPolicyHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		delete(extended, bucketPolicyKey)
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.policyCache.Delete(bucket)
+
+	writeResponse(w, http.StatusNoContent, nil, mimeNone)
+}
+
+// getBucketPolicy returns nil if the bucket or its policy does not exist
+func (s3a *S3ApiServer) getBucketPolicy(bucket string) (*BucketPolicy, error) {
+
+	if item := s3a.policyCache.Get(bucket); item != nil && !item.Expired() {
+		return item.Value().(*BucketPolicy), nil
+	}
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		return nil, err
+	}
+	var policy *BucketPolicy
+	if entry != nil {
+		if policyBytes, found := entry.Extended[bucketPolicyKey]; found {
+			if policy, err = parseBucketPolicy(policyBytes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s3a.policyCache.Set(bucket, policy, bucketPolicyCacheTTL)
+	return policy, nil
+}
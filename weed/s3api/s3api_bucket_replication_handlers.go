@@ -0,0 +1,91 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutBucketReplicationHandler - PUT bucket ?replication
+func (s3a *S3ApiServer) PutBucketReplicationHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketReplication.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &ReplicationConfiguration{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if errCode := config.validate(s3a.replicationTargets()); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	config.Xmlns = ""
+	configXMLBytes, _ := xml.Marshal(config)
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		extended[bucketReplicationKey] = configXMLBytes
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.replicationCache.Delete(bucket)
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketReplicationHandler - GET bucket ?replication
+func (s3a *S3ApiServer) GetBucketReplicationHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if entry == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	config, err := loadReplicationConfiguration(entry.Extended)
+	if err != nil {
+		glog.Errorf("bucket %s has invalid replication configuration: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if config == nil {
+		writeErrorResponse(w, ErrReplicationConfigurationNotFound, r.URL)
+		return
+	}
+
+	config.Xmlns = s3Namespace
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
+
+// DeleteBucketReplicationHandler - DELETE bucket ?replication
+func (s3a *S3ApiServer) DeleteBucketReplicationHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		delete(extended, bucketReplicationKey)
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.replicationCache.Delete(bucket)
+
+	writeResponse(w, http.StatusNoContent, nil, mimeNone)
+}
@@ -0,0 +1,69 @@
+package s3api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutBucketRequestPaymentHandler - PUT bucket ?requestPayment
+func (s3a *S3ApiServer) PutBucketRequestPaymentHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketRequestPayment.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &RequestPaymentConfigurationResult{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if config.Payer != PayerBucketOwner && config.Payer != PayerRequester {
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		if config.Payer == PayerBucketOwner {
+			delete(extended, bucketRequestPaymentKey)
+		} else {
+			extended[bucketRequestPaymentKey] = []byte(config.Payer)
+		}
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	s3a.requestPaymentCache.Delete(bucket)
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketRequestPaymentHandler - GET bucket ?requestPayment
+func (s3a *S3ApiServer) GetBucketRequestPaymentHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if entry == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	config := &RequestPaymentConfigurationResult{
+		Xmlns: s3Namespace,
+		Payer: PayerBucketOwner,
+	}
+	if string(entry.Extended[bucketRequestPaymentKey]) == PayerRequester {
+		config.Payer = PayerRequester
+	}
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
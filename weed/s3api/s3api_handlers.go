@@ -2,6 +2,8 @@ package s3api
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
 	"encoding/base64"
 	"encoding/xml"
 	"fmt"
@@ -48,6 +50,15 @@ func (s3a *S3ApiServer) WithFilerClient(fn func(filer_pb.SeaweedFilerClient) err
 	}, s3a.option.FilerGrpcAddress, s3a.option.GrpcDialOption)
 
 }
+
+// pingFiler checks the filer for the readiness of the s3 server
+func (s3a *S3ApiServer) pingFiler(ctx context.Context) error {
+	return s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+		_, err := client.GetFilerConfiguration(ctx, &filer_pb.GetFilerConfigurationRequest{})
+		return err
+	})
+}
+
 func (s3a *S3ApiServer) AdjustedUrl(hostAndPort string) string {
 	return hostAndPort
 }
@@ -59,8 +70,24 @@ func notFoundHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func writeErrorResponse(w http.ResponseWriter, errorCode ErrorCode, reqURL *url.URL) {
+	writeErrorResponseWithMessage(w, errorCode, reqURL, "")
+}
+
+// writeErrorResponseWithMessage tells more about the error than the description of the error code, if the message is not empty
+func writeErrorResponseWithMessage(w http.ResponseWriter, errorCode ErrorCode, reqURL *url.URL, message string) {
 	apiError := getAPIError(errorCode)
+	setAccessLogErrorCode(w, apiError.Code)
+	// a not modified response has no body
+	if apiError.HTTPStatusCode == http.StatusNotModified {
+		writeResponse(w, apiError.HTTPStatusCode, nil, mimeNone)
+		return
+	}
 	errorResponse := getRESTErrorResponse(apiError, reqURL.Path)
+	if message != "" {
+		errorResponse.Message = message
+	}
+	// set by the router for all the responses
+	errorResponse.Region = w.Header().Get(AmzBucketRegion)
 	encodedErrorResponse := encodeResponse(errorResponse)
 	writeResponse(w, apiError.HTTPStatusCode, encodedErrorResponse, mimeXML)
 }
@@ -101,7 +128,14 @@ func validateContentMd5(h http.Header) ([]byte, error) {
 		if md5B64[0] == "" {
 			return nil, fmt.Errorf("Content-Md5 header set to empty value")
 		}
-		return base64.StdEncoding.DecodeString(md5B64[0])
+		digest, err := base64.StdEncoding.DecodeString(md5B64[0])
+		if err != nil {
+			return nil, err
+		}
+		if len(digest) != md5.Size {
+			return nil, fmt.Errorf("Content-Md5 header has %d bytes instead of %d", len(digest), md5.Size)
+		}
+		return digest, nil
 	}
 	return []byte{}, nil
 }
@@ -0,0 +1,91 @@
+package s3api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutObjectAclHandler - PUT object ?acl
+func (s3a *S3ApiServer) PutObjectAclHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectAcl.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	bucketAcl, err := s3a.getBucketAcl(bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s acl: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if bucketAcl == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	dir, entry, errCode := s3a.objectVersionEntry(bucket, object, r.URL.Query().Get("versionId"))
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	acl, errCode := readAclRequest(r, bucketAcl.owner)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	// private is kept, not to fall back to the bucket ACL
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	entry.Extended[objectAclKey] = []byte(acl)
+	if err := s3a.updateEntry(dir, entry); err != nil {
+		glog.Errorf("update object acl %s%s: %v", bucket, object, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	setVersionHeaders(w, r.URL.Query().Get("versionId"), false)
+	writeSuccessResponseEmpty(w)
+}
+
+// GetObjectAclHandler - GET object ?acl
+func (s3a *S3ApiServer) GetObjectAclHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	bucketAcl, err := s3a.getBucketAcl(bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s acl: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if bucketAcl == nil {
+		writeErrorResponse(w, ErrNoSuchBucket, r.URL)
+		return
+	}
+
+	_, entry, errCode := s3a.objectVersionEntry(bucket, object, r.URL.Query().Get("versionId"))
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	acl := bucketAcl.acl
+	if objectAcl, found := entry.Extended[objectAclKey]; found {
+		acl = string(objectAcl)
+	}
+
+	policy := newAccessControlPolicy(bucketAcl.owner, acl)
+	policy.Xmlns = s3Namespace
+	setVersionHeaders(w, r.URL.Query().Get("versionId"), false)
+	writeSuccessResponseXML(w, encodeResponse(policy))
+}
@@ -0,0 +1,96 @@
+package s3api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+)
+
+const maxObjectAttributesParts = 1000
+
+// GetObjectAttributesHandler - GET object ?attributes
+func (s3a *S3ApiServer) GetObjectAttributesHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	attributes, errCode := parseObjectAttributes(r.Header[AmzObjectAttributes])
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	maxParts, partNumberMarker := maxObjectAttributesParts, 0
+	if value := r.Header.Get(AmzMaxParts); value != "" {
+		var err error
+		if maxParts, err = strconv.Atoi(value); err != nil || maxParts < 0 {
+			writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
+			return
+		}
+		if maxParts > maxObjectAttributesParts {
+			maxParts = maxObjectAttributesParts
+		}
+	}
+	if value := r.Header.Get(AmzPartNumberMarker); value != "" {
+		var err error
+		if partNumberMarker, err = strconv.Atoi(value); err != nil || partNumberMarker < 0 {
+			writeErrorResponse(w, ErrInvalidPartNumberMarker, r.URL)
+			return
+		}
+	}
+
+	_, entry, errCode := s3a.objectVersionEntry(bucket, object, r.URL.Query().Get("versionId"))
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	// the objects encrypted with customer keys need the same key
+	key, errCode := parseRequestCustomerKey(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if _, errCode := loadObjectEncryption(entry.Extended).dataKey(key, s3a.masterKey); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	checksum := loadObjectChecksum(entry.Extended)
+	response := GetObjectAttributesResponse{}
+	if attributes[ObjectAttributeETag] {
+		response.ETag = filer2.ETag(entry)
+	}
+	if attributes[ObjectAttributeChecksum] {
+		response.Checksum = newChecksumValues(checksum)
+	}
+	if attributes[ObjectAttributeObjectParts] {
+		if parts := loadObjectParts(entry.Extended); len(parts) > 0 {
+			checksumAlgorithm := ""
+			if checksum != nil {
+				checksumAlgorithm = checksum.algorithm
+			}
+			response.ObjectParts = parts.toObjectAttributesParts(checksumAlgorithm, partNumberMarker, maxParts)
+		}
+	}
+	if attributes[ObjectAttributeStorageClass] {
+		response.StorageClass = getStorageClass(entry.Extended)
+	}
+	if attributes[ObjectAttributeObjectSize] {
+		size := int64(filer2.FileSize(entry))
+		response.ObjectSize = &size
+	}
+
+	if _, found := entry.Extended[AmzVersionId]; found {
+		setVersionHeaders(w, getVersionId(entry), false)
+	}
+	if entry.Attributes != nil {
+		w.Header().Set("Last-Modified", time.Unix(entry.Attributes.Mtime, 0).UTC().Format(http.TimeFormat))
+	}
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
@@ -1,7 +1,9 @@
 package s3api
 
 import (
+	"crypto/md5"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -10,7 +12,9 @@ import (
 
 	"github.com/gorilla/mux"
 
-	"github.com/chrislusf/seaweedfs/weed/util"
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
 )
 
 func (s3a *S3ApiServer) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
@@ -33,39 +37,219 @@ func (s3a *S3ApiServer) CopyObjectHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if srcBucket == dstBucket && srcObject == dstObject {
+	// an object is copied to itself to change its storage class or its metadata
+	if srcBucket == dstBucket && srcObject == dstObject && r.Header.Get(AmzStorageClass) == "" && r.Header.Get(AmzMetadataDirective) != "REPLACE" {
 		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
 		return
 	}
 
-	dstUrl := fmt.Sprintf("http://%s%s/%s%s?collection=%s",
-		s3a.option.Filer, s3a.option.BucketsPath, dstBucket, dstObject, dstBucket)
+	etag, size, versionId, encryption, checksum, errCode := s3a.copyObject(r, srcBucket, srcObject, dstBucket, dstObject)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	setEtag(w, etag)
+	setVersionHeaders(w, versionId, false)
+	setEncryptionHeaders(w, encryption)
+	setChecksumHeaders(w, checksum)
+
+	response := CopyObjectResult{
+		ETag:         etag,
+		LastModified: time.Now(),
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+
+	s3a.notify(r, EventObjectCreatedCopy, dstBucket, dstObject, size, etag, versionId)
+}
+
+// copyObject copies the source object with the lock, storage class, encryption, checksum, tags, ACL and metadata of the request headers
+func (s3a *S3ApiServer) copyObject(r *http.Request, srcBucket, srcObject, dstBucket, dstObject string) (etag string, size int64, versionId string, encryption *objectEncryption, checksum *objectChecksum, code ErrorCode) {
+
+	lock, code := s3a.prepareObjectLock(r, dstBucket, dstObject)
+	if code != ErrNone {
+		return
+	}
+
+	// the storage class is not copied
+	storageClass, tier, code := s3a.prepareStorageClass(r, dstBucket)
+	if code != ErrNone {
+		return
+	}
+
+	dstUrl := tier.addTo(fmt.Sprintf("http://%s%s/%s%s?collection=%s",
+		s3a.option.Filer, s3a.option.BucketsPath, dstBucket, dstObject, dstBucket))
 	srcUrl := fmt.Sprintf("http://%s%s/%s%s",
 		s3a.option.Filer, s3a.option.BucketsPath, srcBucket, srcObject)
 
-	_, _, dataReader, err := util.DownloadFile(srcUrl)
+	encryption, dataKey, code := s3a.prepareObjectEncryption(r, dstBucket)
+	if code != ErrNone {
+		return
+	}
+
+	checksumAlgorithm, code := parseChecksumAlgorithm(r.Header.Get(AmzChecksumAlgorithm))
+	if code != ErrNone {
+		return
+	}
+
+	tags, code := s3a.copyObjectTags(r, srcBucket, srcObject)
+	if code != ErrNone {
+		return
+	}
+
+	// the ACL is not copied
+	acl, code := prepareCannedAcl(r)
+	if code != ErrNone {
+		return
+	}
+
+	_, _, srcEntry, err := s3a.objectEntry(srcBucket, srcObject)
 	if err != nil {
-		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+		glog.Errorf("lookup copy source %s%s: %v", srcBucket, srcObject, err)
+		code = ErrInternalError
 		return
 	}
-	defer dataReader.Close()
+	if srcEntry != nil && !srcEntry.IsDirectory && !isDeleteMarker(srcEntry) {
+		if code = checkCopySourceConditions(r, srcEntry); code != ErrNone {
+			return
+		}
+	}
 
-	etag, errCode := s3a.putToFiler(r, dstUrl, dataReader)
+	// the metadata is saved after the data, replacing the content type the filer takes from the request
+	metadata, code := copyObjectMetadata(r, srcEntry)
+	if code != ErrNone {
+		return
+	}
 
-	if errCode != ErrNone {
-		writeErrorResponse(w, errCode, r.URL)
+	if srcEntry != nil {
+		if code = s3a.checkObjectSize(dstBucket, int64(filer2.FileSize(srcEntry)), true); code != ErrNone {
+			return
+		}
+	}
+
+	if (srcBucket != dstBucket || srcObject != dstObject) && canCopyByReference(srcEntry, dstBucket, storageClass, tier, encryption, checksumAlgorithm) {
+		etag, size, checksum, versionId, code = s3a.copyObjectByReference(srcEntry, srcBucket, srcObject, dstBucket, dstObject, checksumAlgorithm)
+	} else {
+		etag, size, checksum, versionId, code = s3a.copyObjectData(r, srcUrl, dstUrl, dstBucket, dstObject, encryption, dataKey, checksumAlgorithm)
+	}
+	if code != ErrNone {
 		return
 	}
+	var contentETag string
+	if encryption.hasContentETag() {
+		contentETag = etag
+	}
 
-	setEtag(w, etag)
+	if err := s3a.saveObjectAttributes(dstBucket, dstObject, objectAttributes{
+		lock:         lock,
+		versionId:    versionId,
+		encryption:   encryption,
+		checksum:     checksum,
+		tags:         tags,
+		acl:          acl,
+		storageClass: storageClass,
+		metadata:     metadata,
+		etag:         contentETag,
+	}); err != nil {
+		glog.Errorf("save object attributes %s%s: %v", dstBucket, dstObject, err)
+		code = ErrInternalError
+		return
+	}
 
-	response := CopyObjectResult{
-		ETag:         etag,
-		LastModified: time.Now(),
+	return
+}
+
+// copyObjectByReference creates the destination object sharing the chunks of the source object
+func (s3a *S3ApiServer) copyObjectByReference(srcEntry *filer_pb.Entry, srcBucket, srcObject, dstBucket, dstObject, checksumAlgorithm string) (etag string, size int64, checksum *objectChecksum, versionId string, code ErrorCode) {
+
+	if versionId, code = s3a.prepareObjectVersion(dstBucket, dstObject); code != ErrNone {
+		return
 	}
+	defer func() {
+		s3a.finishObjectVersion(dstBucket, dstObject, versionId, code == ErrNone)
+	}()
 
-	writeSuccessResponseXML(w, encodeResponse(response))
+	entry, err := s3a.copyEntry(srcBucket, srcObject, dstBucket, dstObject)
+	if err != nil {
+		glog.Errorf("copy %s%s to %s%s: %v", srcBucket, srcObject, dstBucket, dstObject, err)
+		code = filerWriteErrorCode(err.Error())
+		return
+	}
+
+	if checksumAlgorithm != "" {
+		checksum = loadObjectChecksum(srcEntry.Extended)
+	}
+
+	return filer2.ETag(entry), int64(filer2.FileSize(entry)), checksum, versionId, ErrNone
+}
+
+// copyObjectData reads the data of the source object, and writes it as the destination object
+func (s3a *S3ApiServer) copyObjectData(r *http.Request, srcUrl, dstUrl, dstBucket, dstObject string, encryption *objectEncryption, dataKey []byte, checksumAlgorithm string) (etag string, size int64, checksum *objectChecksum, versionId string, code ErrorCode) {
+
+	dataReader, code := s3a.openCopySource(r, srcUrl, "")
+	if code != ErrNone {
+		return
+	}
+	defer dataReader.Close()
+
+	counter := &countingReader{reader: dataReader}
+	contentHash := md5.New()
+	checksumReader, code := newChecksumReader(r, io.TeeReader(counter, contentHash), checksumAlgorithm)
+	if code != ErrNone {
+		return
+	}
+
+	var body io.Reader = checksumReader
+	if encryption != nil {
+		var err error
+		if body, err = encryption.encryptReader(dataKey, checksumReader, 0); err != nil {
+			glog.Errorf("encrypt %s%s: %v", dstBucket, dstObject, err)
+			return "", 0, nil, "", ErrInternalError
+		}
+	}
+
+	if versionId, code = s3a.prepareObjectVersion(dstBucket, dstObject); code != ErrNone {
+		return
+	}
+	defer func() {
+		s3a.finishObjectVersion(dstBucket, dstObject, versionId, code == ErrNone)
+	}()
+
+	if etag, code = s3a.putToFiler(r, dstUrl, body, -1, false); code != ErrNone {
+		return
+	}
+	// the filer saves the md5 of the encrypted data
+	if encryption.hasContentETag() {
+		etag = fmt.Sprintf("%x", contentHash.Sum(nil))
+	}
+
+	return etag, counter.count, checksumReader.checksum(), versionId, ErrNone
+}
 
+// copyObjectTags returns the tags of the source object, or the x-amz-tagging header with x-amz-tagging-directive REPLACE
+func (s3a *S3ApiServer) copyObjectTags(r *http.Request, srcBucket, srcObject string) (objectTags, ErrorCode) {
+
+	switch r.Header.Get(AmzTaggingDirective) {
+	case "", "COPY":
+	case "REPLACE":
+		return prepareObjectTags(r)
+	default:
+		return nil, ErrInvalidTaggingDirective
+	}
+
+	_, _, entry, err := s3a.objectEntry(srcBucket, srcObject)
+	if err != nil {
+		glog.Errorf("lookup copy source %s%s: %v", srcBucket, srcObject, err)
+		return nil, ErrInternalError
+	}
+	if entry == nil {
+		return nil, ErrNone
+	}
+	if tags := loadObjectTags(entry.Extended); len(tags) > 0 {
+		return tags, ErrNone
+	}
+	return nil, ErrNone
 }
 
 func pathToBucketAndObject(path string) (bucket, object string) {
@@ -95,6 +279,7 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 		// Save unescaped string as is.
 		cpSrcPath = r.Header.Get("X-Amz-Copy-Source")
 	}
+	cpSrcPath, srcVersionId := splitCopySourceVersion(cpSrcPath)
 
 	srcBucket, srcObject := pathToBucketAndObject(cpSrcPath)
 	// If source object is empty or bucket is empty, reply back invalid copy source.
@@ -113,6 +298,10 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	// check partID with maximum part ID for multipart objects
+	if partID < 1 {
+		writeErrorResponse(w, ErrInvalidPart, r.URL)
+		return
+	}
 	if partID > globalMaxPartID {
 		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
 		return
@@ -120,26 +309,91 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 
 	rangeHeader := r.Header.Get("x-amz-copy-source-range")
 
-	dstUrl := fmt.Sprintf("http://%s%s/%s/%04d.part?collection=%s",
-		s3a.option.Filer, s3a.genUploadsFolder(dstBucket), uploadID, partID-1, dstBucket)
-	srcUrl := fmt.Sprintf("http://%s%s/%s%s",
-		s3a.option.Filer, s3a.option.BucketsPath, srcBucket, srcObject)
+	upload, errCode := s3a.prepareUploadPart(r, dstBucket, uploadID)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
 
-	dataReader, err := util.ReadUrlAsReaderCloser(srcUrl, rangeHeader)
-	if err != nil {
-		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+	srcDir, srcEntry, errCode := s3a.objectVersionEntry(srcBucket, srcObject, srcVersionId)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if errCode = checkCopySourceConditions(r, srcEntry); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
 		return
 	}
-	defer dataReader.Close()
 
-	etag, errCode := s3a.putToFiler(r, dstUrl, dataReader)
+	offset, size, errCode := parseCopySourceRange(rangeHeader, int64(filer2.FileSize(srcEntry)))
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	// the chunks of an encrypted source are decrypted, and the content saved inside the entry is read, through the filer
+	var dataReader io.Reader
+	var views []*filer2.ChunkView
+	streamed := loadObjectEncryption(srcEntry.Extended) != nil || len(srcEntry.Content) > 0
+	if streamed {
+		srcUrl := fmt.Sprintf("http://%s%s/%s", s3a.option.Filer, srcDir, srcEntry.Name)
+		srcReader, errCode := s3a.openCopySource(r, srcUrl, rangeHeader)
+		if errCode != ErrNone {
+			writeErrorResponse(w, errCode, r.URL)
+			return
+		}
+		defer srcReader.Close()
+		dataReader = srcReader
+	} else {
+		views = filer2.ViewFromChunks(srcEntry.Chunks, offset, size)
+		dataReader = newChunkViewReader(s3a, views)
+	}
+
+	checksum, errCode := newChecksumReader(r, dataReader, upload.checksumAlgorithm)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
 
+	var body io.Reader = checksum
+	if upload.encryption != nil {
+		if body, err = upload.encryption.encryptReader(upload.dataKey, checksum, partID); err != nil {
+			glog.Errorf("encrypt upload %s part %d: %v", uploadID, partID, err)
+			writeErrorResponse(w, ErrInternalError, r.URL)
+			return
+		}
+	}
+
+	var etag string
+	if streamed {
+		dstUrl := upload.tier.addTo(fmt.Sprintf("http://%s%s/%s/%04d.part?collection=%s&saveInside=false",
+			s3a.option.Filer, s3a.genUploadsFolder(dstBucket), uploadID, partID-1, dstBucket))
+		etag, errCode = s3a.putToFiler(r, dstUrl, body, size, false)
+	} else {
+		etag, errCode = s3a.copyToPart(dstBucket, uploadID, partID, upload.tier, views, body)
+	}
+
+	if checksum.mismatch {
+		writeErrorResponse(w, ErrBadDigest, r.URL)
+		return
+	}
 	if errCode != ErrNone {
 		writeErrorResponse(w, errCode, r.URL)
 		return
 	}
 
+	if err := s3a.savePartChecksum(dstBucket, uploadID, partID, checksum.checksum()); err != nil {
+		glog.Errorf("save upload %s part %d checksum: %v", uploadID, partID, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
 	setEtag(w, etag)
+	setEncryptionHeaders(w, upload.encryption)
+	setChecksumHeaders(w, checksum.checksum())
+	if srcVersionId != "" {
+		w.Header().Set("x-amz-copy-source-version-id", srcVersionId)
+	}
 
 	response := CopyPartResult{
 		ETag:         etag,
@@ -149,3 +403,41 @@ func (s3a *S3ApiServer) CopyObjectPartHandler(w http.ResponseWriter, r *http.Req
 	writeSuccessResponseXML(w, encodeResponse(response))
 
 }
+
+// splitCopySourceVersion separates the ?versionId=<id> suffix of the copy source
+func splitCopySourceVersion(copySource string) (path, versionId string) {
+	if i := strings.Index(copySource, "?"); i >= 0 {
+		query, _ := url.ParseQuery(copySource[i+1:])
+		return copySource[:i], query.Get("versionId")
+	}
+	return copySource, ""
+}
+
+// parseCopySourceRange parses the x-amz-copy-source-range header, bytes=first-last, into the offset and size to copy
+func parseCopySourceRange(rangeHeader string, objectSize int64) (offset, size int64, code ErrorCode) {
+
+	if rangeHeader == "" {
+		return 0, objectSize, ErrNone
+	}
+
+	if !strings.HasPrefix(rangeHeader, "bytes=") {
+		return 0, 0, ErrInvalidCopySourceRange
+	}
+	parts := strings.Split(strings.TrimPrefix(rangeHeader, "bytes="), "-")
+	if len(parts) != 2 {
+		return 0, 0, ErrInvalidCopySourceRange
+	}
+	first, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || first < 0 {
+		return 0, 0, ErrInvalidCopySourceRange
+	}
+	last, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || last < first {
+		return 0, 0, ErrInvalidCopySourceRange
+	}
+	if last >= objectSize {
+		return 0, 0, ErrInvalidCopySourceRange
+	}
+
+	return first, last - first + 1, ErrNone
+}
@@ -0,0 +1,189 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestParseCopySourceRange(t *testing.T) {
+	tests := []struct {
+		header   string
+		offset   int64
+		size     int64
+		expected ErrorCode
+	}{
+		{"", 0, 100, ErrNone},
+		{"bytes=0-99", 0, 100, ErrNone},
+		{"bytes=10-19", 10, 10, ErrNone},
+		{"bytes=99-99", 99, 1, ErrNone},
+		{"bytes=0-100", 0, 0, ErrInvalidCopySourceRange},
+		{"bytes=20-10", 0, 0, ErrInvalidCopySourceRange},
+		{"bytes=10-", 0, 0, ErrInvalidCopySourceRange},
+		{"bytes=-10", 0, 0, ErrInvalidCopySourceRange},
+		{"bytes=0-1,5-6", 0, 0, ErrInvalidCopySourceRange},
+		{"0-10", 0, 0, ErrInvalidCopySourceRange},
+	}
+	for _, test := range tests {
+		offset, size, errCode := parseCopySourceRange(test.header, 100)
+		if errCode != test.expected || offset != test.offset || size != test.size {
+			t.Errorf("%q: got %d %d %v, expecting %d %d %v", test.header, offset, size, errCode, test.offset, test.size, test.expected)
+		}
+	}
+}
+
+func TestSplitCopySourceVersion(t *testing.T) {
+	tests := []struct {
+		copySource string
+		path       string
+		versionId  string
+	}{
+		{"bucket/dir/key", "bucket/dir/key", ""},
+		{"/bucket/key?versionId=abc", "/bucket/key", "abc"},
+		{"bucket/key?other=1", "bucket/key", ""},
+	}
+	for _, test := range tests {
+		path, versionId := splitCopySourceVersion(test.copySource)
+		if path != test.path || versionId != test.versionId {
+			t.Errorf("%s: got %s %s", test.copySource, path, versionId)
+		}
+	}
+}
+
+func TestCanCopyByReference(t *testing.T) {
+	source := func(collection string, extended map[string][]byte) *filer_pb.Entry {
+		return &filer_pb.Entry{
+			Name:       "key",
+			Attributes: &filer_pb.FuseAttributes{Collection: collection, Replication: "001"},
+			Extended:   extended,
+		}
+	}
+	withChecksum := map[string][]byte{checksumHeader(ChecksumAlgorithmCRC32): []byte("AAAAAA==")}
+
+	tests := []struct {
+		name              string
+		srcEntry          *filer_pb.Entry
+		storageClass      string
+		tier              storageTier
+		encryption        *objectEncryption
+		checksumAlgorithm string
+		expected          bool
+	}{
+		{"same collection", source("b", nil), StorageClassStandard, storageTier{}, nil, "", true},
+		{"same replication", source("b", nil), StorageClassStandard, storageTier{Replication: "001"}, nil, "", true},
+		{"missing source", nil, StorageClassStandard, storageTier{}, nil, "", false},
+		{"other collection", source("a", nil), StorageClassStandard, storageTier{}, nil, "", false},
+		{"other storage class", source("b", nil), "STANDARD_IA", storageTier{}, nil, "", false},
+		{"other replication", source("b", nil), StorageClassStandard, storageTier{Replication: "010"}, nil, "", false},
+		{"other ttl", source("b", nil), StorageClassStandard, storageTier{Ttl: "7d"}, nil, "", false},
+		{"encrypted destination", source("b", nil), StorageClassStandard, storageTier{}, &objectEncryption{serverSideEncryption: "AES256"}, "", false},
+		{"encrypted source", source("b", map[string][]byte{AmzServerSideEncryption: []byte("AES256")}), StorageClassStandard, storageTier{}, nil, "", false},
+		{"same checksum", source("b", withChecksum), StorageClassStandard, storageTier{}, nil, ChecksumAlgorithmCRC32, true},
+		{"other checksum", source("b", withChecksum), StorageClassStandard, storageTier{}, nil, ChecksumAlgorithmSHA256, false},
+	}
+	for _, test := range tests {
+		if actual := canCopyByReference(test.srcEntry, "b", test.storageClass, test.tier, test.encryption, test.checksumAlgorithm); actual != test.expected {
+			t.Errorf("%s: expected %v", test.name, test.expected)
+		}
+	}
+}
+
+func renameObject(s3a *S3ApiServer, bucket, object, copySource string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("PUT", "/"+bucket+"/"+object, nil)
+	r = mux.SetURLVars(r, map[string]string{"bucket": bucket, "object": object})
+	r.Header.Set("X-Amz-Copy-Source", copySource)
+	r.Header.Set(SeaweedRenameHeader, "true")
+	w := httptest.NewRecorder()
+	s3a.RenameObjectHandler(w, r)
+	return w
+}
+
+func TestRenameObject(t *testing.T) {
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket":            {Name: "bucket", IsDirectory: true},
+		"/buckets/bucket/big":        {Name: "big", Attributes: &filer_pb.FuseAttributes{FileSize: 100 << 30}},
+		"/buckets/bucket/dir":        {Name: "dir", IsDirectory: true},
+		"/buckets/bucket/dir/a":      {Name: "a"},
+		"/buckets/bucket/dir/sub":    {Name: "sub", IsDirectory: true},
+		"/buckets/bucket/dir/sub/b":  {Name: "b"},
+		"/buckets/bucket/existing":   {Name: "existing", IsDirectory: true},
+		"/buckets/bucket/existing/c": {Name: "c"},
+		"/buckets/other":             {Name: "other", IsDirectory: true},
+		"/buckets/versioned":         {Name: "versioned", IsDirectory: true, Extended: map[string][]byte{bucketVersioningKey: []byte(VersioningEnabled)}},
+		"/buckets/versioned/object":  {Name: "object"},
+		"/buckets/bucket/locked":     {Name: "locked", Extended: map[string][]byte{AmzObjectLockLegalHold: []byte(LegalHoldOn)}},
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+
+	if w := renameObject(s3a, "bucket", "renamed", "/bucket/big"); w.Code != http.StatusNotImplemented {
+		t.Errorf("renamed without -allowRename: %d", w.Code)
+	}
+	s3a.option.AllowRename = true
+
+	if w := renameObject(s3a, "bucket", "renamed", "/bucket/big"); w.Code != http.StatusOK {
+		t.Fatalf("rename object: %d %s", w.Code, w.Body.String())
+	}
+	if _, found := entries["/buckets/bucket/big"]; found {
+		t.Errorf("source object left")
+	}
+	if entry, found := entries["/buckets/bucket/renamed"]; !found || entry.Attributes.FileSize != 100<<30 {
+		t.Errorf("renamed object %+v", entry)
+	}
+
+	if w := renameObject(s3a, "bucket", "moved/dir/", "/bucket/dir/"); w.Code != http.StatusOK {
+		t.Fatalf("rename prefix: %d %s", w.Code, w.Body.String())
+	}
+	for _, p := range []util.FullPath{"/buckets/bucket/moved/dir/a", "/buckets/bucket/moved/dir/sub/b"} {
+		if _, found := entries[p]; !found {
+			t.Errorf("%s not found", p)
+		}
+	}
+	if _, found := entries["/buckets/bucket/dir/sub/b"]; found {
+		t.Errorf("source prefix left")
+	}
+
+	tests := []struct {
+		bucket, object, copySource string
+		code                       int
+	}{
+		{"bucket", "x", "/bucket/missing", http.StatusNotFound},
+		{"bucket", "x", "/bucket/moved/dir/", http.StatusBadRequest},
+		{"bucket", "x/", "/bucket/renamed", http.StatusBadRequest},
+		{"bucket", "existing/", "/bucket/moved/dir/", http.StatusBadRequest},
+		{"other", "x", "/bucket/renamed", http.StatusBadRequest},
+		{"bucket", "x", "/bucket/locked", http.StatusForbidden},
+		{"versioned", "x", "/versioned/object", http.StatusNotImplemented},
+	}
+	for _, test := range tests {
+		if w := renameObject(s3a, test.bucket, test.object, test.copySource); w.Code != test.code {
+			t.Errorf("rename %s to %s/%s: %d, expecting %d", test.copySource, test.bucket, test.object, w.Code, test.code)
+		}
+	}
+}
+
+func TestCopyObjectSourceConditions(t *testing.T) {
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket/object": {Name: "object", Attributes: &filer_pb.FuseAttributes{Md5: []byte{0xab, 0xcd}}},
+	})
+	defer stop()
+	s3a.storageClasses = &storageClassConfig{}
+
+	for _, directive := range []string{"COPY", "REPLACE"} {
+		r := httptest.NewRequest("PUT", "/bucket/copy", nil)
+		r = mux.SetURLVars(r, map[string]string{"bucket": "bucket", "object": "copy"})
+		r.Header.Set("X-Amz-Copy-Source", "/bucket/object")
+		r.Header.Set(AmzMetadataDirective, directive)
+		r.Header.Set(AmzCopySourceIfMatch, `"other"`)
+		w := httptest.NewRecorder()
+		s3a.CopyObjectHandler(w, r)
+		if w.Code != http.StatusPreconditionFailed || errorResponseCode(t, w) != "PreconditionFailed" {
+			t.Errorf("metadata directive %s: %d %s", directive, w.Code, w.Body.String())
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package s3api
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/json"
 	"encoding/xml"
@@ -8,7 +9,9 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/gorilla/mux"
 
@@ -42,30 +45,153 @@ func (s3a *S3ApiServer) PutObjectHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	rAuthType := getRequestAuthType(r)
-	dataReader := r.Body
-	var s3ErrCode ErrorCode
-	if rAuthType == authTypeStreamingSigned {
-		dataReader, s3ErrCode = s3a.iam.newSignV4ChunkedReader(r)
+	if errCode := s3a.checkRequestSize(r, bucket, true); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
 	}
+
+	if errCode := s3a.checkObjectPreconditions(w, r, bucket, object); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	dataReader, s3ErrCode := s3a.iam.newStreamingReader(r)
 	if s3ErrCode != ErrNone {
 		writeErrorResponse(w, s3ErrCode, r.URL)
 		return
 	}
 	defer dataReader.Close()
 
-	uploadUrl := fmt.Sprintf("http://%s%s/%s%s", s3a.option.Filer, s3a.option.BucketsPath, bucket, object)
-
-	etag, errCode := s3a.putToFiler(r, uploadUrl, dataReader)
-
+	result, errCode := s3a.putObject(r, bucket, object, dataReader)
 	if errCode != ErrNone {
 		writeErrorResponse(w, errCode, r.URL)
 		return
 	}
 
-	setEtag(w, etag)
+	setEtag(w, result.etag)
+	setVersionHeaders(w, result.versionId, false)
+	setEncryptionHeaders(w, result.encryption)
+	setChecksumHeaders(w, result.checksum)
 
 	writeSuccessResponseEmpty(w)
+
+	s3a.notify(r, EventObjectCreatedPut, bucket, object, result.size, result.etag, result.versionId)
+}
+
+type putObjectResult struct {
+	etag       string
+	versionId  string
+	encryption *objectEncryption
+	checksum   *objectChecksum
+	size       int64
+}
+
+// putObject writes the object data to the filer, with the lock, encryption, and checksum of the request headers
+func (s3a *S3ApiServer) putObject(r *http.Request, bucket, object string, dataReader io.Reader) (result *putObjectResult, code ErrorCode) {
+
+	lock, errCode := s3a.prepareObjectLock(r, bucket, object)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	encryption, dataKey, errCode := s3a.prepareObjectEncryption(r, bucket)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	versionId, errCode := s3a.prepareObjectVersion(bucket, object)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+	defer func() {
+		s3a.finishObjectVersion(bucket, object, versionId, code == ErrNone)
+	}()
+
+	tags, errCode := prepareObjectTags(r)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	acl, errCode := prepareCannedAcl(r)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	storageClass, tier, errCode := s3a.prepareStorageClass(r, bucket)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	metadata, errCode := parseRequestMetadata(r)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+	// the filer already keeps the content type of the written data
+	metadata.contentType = ""
+
+	counter := &countingReader{reader: dataReader}
+	contentHash := md5.New()
+	checksum, errCode := newChecksumReader(r, io.TeeReader(counter, contentHash), "")
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+	if errCode = checksum.verifyContentMd5(r); errCode != ErrNone {
+		return nil, errCode
+	}
+
+	var body io.Reader = checksum
+	if encryption != nil {
+		var err error
+		if body, err = encryption.encryptReader(dataKey, checksum, 0); err != nil {
+			glog.Errorf("encrypt %s%s: %v", bucket, object, err)
+			return nil, ErrInternalError
+		}
+	}
+
+	uploadUrl := tier.addTo(fmt.Sprintf("http://%s%s/%s%s", s3a.option.Filer, s3a.option.BucketsPath, bucket, object))
+
+	// without versioning, the filer checks the conditional headers again when it overwrites the object
+	etag, errCode := s3a.putToFiler(r, uploadUrl, body, declaredObjectSize(r), versionId == "")
+
+	if chunked, ok := dataReader.(*s3ChunkedReader); ok && chunked.errorCode() != ErrNone {
+		return nil, chunked.errorCode()
+	}
+	if checksum.mismatch {
+		return nil, ErrBadDigest
+	}
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+
+	// the filer saves the md5 of the encrypted data
+	var contentETag string
+	if encryption.hasContentETag() {
+		contentETag = fmt.Sprintf("%x", contentHash.Sum(nil))
+		etag = contentETag
+	}
+
+	if err := s3a.saveObjectAttributes(bucket, object, objectAttributes{
+		lock:         lock,
+		versionId:    versionId,
+		encryption:   encryption,
+		checksum:     checksum.checksum(),
+		tags:         tags,
+		acl:          acl,
+		storageClass: storageClass,
+		metadata:     metadata,
+		etag:         contentETag,
+	}); err != nil {
+		glog.Errorf("save object attributes %s%s: %v", bucket, object, err)
+		return nil, ErrInternalError
+	}
+
+	return &putObjectResult{
+		etag:       etag,
+		versionId:  versionId,
+		encryption: encryption,
+		checksum:   checksum.checksum(),
+		size:       counter.count,
+	}, ErrNone
 }
 
 func (s3a *S3ApiServer) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
@@ -79,10 +205,35 @@ func (s3a *S3ApiServer) GetObjectHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	destUrl := fmt.Sprintf("http://%s%s/%s%s",
-		s3a.option.Filer, s3a.option.BucketsPath, bucket, object)
+	if hasMultipleRanges(r.Header.Get("Range")) {
+		writeErrorResponse(w, ErrInvalidRange, r.URL)
+		return
+	}
+
+	key, errCode := parseRequestCustomerKey(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	if errCode := s3a.checkObjectPreconditions(w, r, bucket, object); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	part, errCode := s3a.partNumberRange(r, bucket, object)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	destUrl, errCode := s3a.objectUrl(w, r, bucket, object)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
 
-	s3a.proxyToFiler(w, r, destUrl, passThroughResponse)
+	s3a.proxyToFiler(w, r, destUrl, s3a.passThroughObjectPartResponse(r, key, part))
 
 }
 
@@ -92,10 +243,47 @@ func (s3a *S3ApiServer) HeadObjectHandler(w http.ResponseWriter, r *http.Request
 	bucket := vars["bucket"]
 	object := getObject(vars)
 
-	destUrl := fmt.Sprintf("http://%s%s/%s%s",
-		s3a.option.Filer, s3a.option.BucketsPath, bucket, object)
+	if hasMultipleRanges(r.Header.Get("Range")) {
+		writeErrorResponse(w, ErrInvalidRange, r.URL)
+		return
+	}
+
+	key, errCode := parseRequestCustomerKey(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	if errCode := s3a.checkObjectPreconditions(w, r, bucket, object); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	part, errCode := s3a.partNumberRange(r, bucket, object)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	destUrl, errCode := s3a.objectUrl(w, r, bucket, object)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	s3a.proxyToFiler(w, r, destUrl, s3a.passThroughObjectPartResponse(r, key, part))
 
-	s3a.proxyToFiler(w, r, destUrl, passThroughResponse)
+}
+
+// objectUrl returns the filer url of the current object, or of the version given by the versionId query parameter
+func (s3a *S3ApiServer) objectUrl(w http.ResponseWriter, r *http.Request, bucket, object string) (destUrl string, code ErrorCode) {
+
+	if versionId := r.URL.Query().Get("versionId"); versionId != "" {
+		return s3a.objectVersionUrl(w, bucket, object, versionId)
+	}
+
+	return fmt.Sprintf("http://%s%s/%s%s",
+		s3a.option.Filer, s3a.option.BucketsPath, bucket, object), ErrNone
 
 }
 
@@ -105,21 +293,65 @@ func (s3a *S3ApiServer) DeleteObjectHandler(w http.ResponseWriter, r *http.Reque
 	bucket := vars["bucket"]
 	object := getObject(vars)
 
+	if errCode := s3a.checkObjectPreconditions(w, r, bucket, object); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	versionId := r.URL.Query().Get("versionId")
+	status, errCode := s3a.getBucketVersioning(bucket)
+	if errCode != ErrNone && errCode != ErrNoSuchBucket {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if status != "" || versionId != "" {
+		bypassGovernance, errCode := checkBypassGovernance(r, bucket)
+		if errCode != ErrNone {
+			writeErrorResponse(w, errCode, r.URL)
+			return
+		}
+		deletedVersionId, deleteMarker, errCode := s3a.deleteVersionedObject(bucket, object, versionId, status, bypassGovernance)
+		if errCode != ErrNone {
+			writeErrorResponse(w, errCode, r.URL)
+			return
+		}
+		setVersionHeaders(w, deletedVersionId, deleteMarker)
+		w.WriteHeader(http.StatusNoContent)
+		s3a.notifyObjectRemoved(r, bucket, object, deletedVersionId, deleteMarker)
+		return
+	}
+
+	if errCode := s3a.checkObjectRemovable(r, bucket, object); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
 	destUrl := fmt.Sprintf("http://%s%s/%s%s",
 		s3a.option.Filer, s3a.option.BucketsPath, bucket, object)
 
+	// the filer checks the conditional headers again when it deletes the object
 	s3a.proxyToFiler(w, r, destUrl, func(proxyResonse *http.Response, w http.ResponseWriter) {
+		if proxyResonse.StatusCode == http.StatusPreconditionFailed {
+			writeErrorResponse(w, ErrPreconditionFailed, r.URL)
+			return
+		}
 		for k, v := range proxyResonse.Header {
 			w.Header()[k] = v
 		}
 		w.WriteHeader(http.StatusNoContent)
+		if proxyResonse.StatusCode < http.StatusMultipleChoices {
+			s3a.notifyObjectRemoved(r, bucket, object, "", false)
+		}
 	})
 
 }
 
 /// ObjectIdentifier carries key name for the object to delete.
 type ObjectIdentifier struct {
-	ObjectName string `xml:"Key"`
+	ObjectName            string `xml:"Key"`
+	VersionId             string `xml:"VersionId,omitempty"`
+	DeleteMarker          bool   `xml:"DeleteMarker,omitempty"`
+	DeleteMarkerVersionId string `xml:"DeleteMarkerVersionId,omitempty"`
 }
 
 // DeleteObjectsRequest - xml carrying the object key names which needs to be deleted.
@@ -132,9 +364,10 @@ type DeleteObjectsRequest struct {
 
 // DeleteError structure.
 type DeleteError struct {
-	Code    string
-	Message string
-	Key     string
+	Code      string
+	Message   string
+	Key       string
+	VersionId string `xml:"VersionId,omitempty"`
 }
 
 // DeleteObjectsResponse container for multiple object deletes.
@@ -148,51 +381,83 @@ type DeleteObjectsResponse struct {
 	Errors []DeleteError `xml:"Error,omitempty"`
 }
 
+const (
+	maxDeleteObjects         = 1000
+	deleteObjectsConcurrency = 16
+)
+
 // DeleteMultipleObjectsHandler - Delete multiple objects
 func (s3a *S3ApiServer) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 
-	deleteXMLBytes, err := ioutil.ReadAll(r.Body)
+	deleteXMLBytes, err := readRequestBody(r, maxXMLBodySize)
 	if err != nil {
-		writeErrorResponse(w, ErrInternalError, r.URL)
+		writeXMLBodyError(w, r, err)
 		return
 	}
 
-	deleteObjects := &DeleteObjectsRequest{}
-	if err := xml.Unmarshal(deleteXMLBytes, deleteObjects); err != nil {
-		writeErrorResponse(w, ErrMalformedXML, r.URL)
+	if errCode := verifyRequestDigest(r, deleteXMLBytes); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
 		return
 	}
 
-	var deletedObjects []ObjectIdentifier
-	var deleteErrors []DeleteError
+	deleteObjects := &DeleteObjectsRequest{}
+	if err := decodeXML(bytes.NewReader(deleteXMLBytes), deleteObjects); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if len(deleteObjects.Objects) == 0 || len(deleteObjects.Objects) > maxDeleteObjects {
+		writeErrorResponse(w, ErrTooManyKeysToDelete, r.URL)
+		return
+	}
 
-	s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+	status, errCode := s3a.getBucketVersioning(bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
 
-		for _, object := range deleteObjects.Objects {
-			lastSeparator := strings.LastIndex(object.ObjectName, "/")
-			parentDirectoryPath, entryName, isDeleteData, isRecursive := "/", object.ObjectName, true, true
-			if lastSeparator > 0 && lastSeparator+1 < len(object.ObjectName) {
-				entryName = object.ObjectName[lastSeparator+1:]
-				parentDirectoryPath = "/" + object.ObjectName[:lastSeparator]
-			}
-			parentDirectoryPath = fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, parentDirectoryPath)
-
-			err := doDeleteEntry(client, parentDirectoryPath, entryName, isDeleteData, isRecursive)
-			if err == nil {
-				deletedObjects = append(deletedObjects, object)
-			} else {
-				deleteErrors = append(deleteErrors, DeleteError{
-					Code:    "",
-					Message: err.Error(),
-					Key:     object.ObjectName,
-				})
-			}
+	requester := getRequester(r)
+
+	// the objects are deleted in parallel, and the results are kept in the request order
+	deleted := make([]*ObjectIdentifier, len(deleteObjects.Objects))
+	failures := make([]*DeleteError, len(deleteObjects.Objects))
+	err = s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+		objectIndexes := make(chan int)
+		var wg sync.WaitGroup
+		for i := 0; i < deleteObjectsConcurrency && i < len(deleteObjects.Objects); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for index := range objectIndexes {
+					deleted[index], failures[index] = s3a.deleteObjectInBatch(r, client, requester, bucket, status, deleteObjects.Objects[index])
+				}
+			}()
 		}
+		for index := range deleteObjects.Objects {
+			objectIndexes <- index
+		}
+		close(objectIndexes)
+		wg.Wait()
 		return nil
 	})
+	if err != nil {
+		glog.Errorf("delete objects in %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	var deletedObjects []ObjectIdentifier
+	var deleteErrors []DeleteError
+	for index := range deleteObjects.Objects {
+		if failures[index] != nil {
+			deleteErrors = append(deleteErrors, *failures[index])
+		} else {
+			deletedObjects = append(deletedObjects, *deleted[index])
+		}
+	}
 
 	deleteResp := DeleteObjectsResponse{}
 	if !deleteObjects.Quiet {
@@ -204,6 +469,68 @@ func (s3a *S3ApiServer) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *h
 
 }
 
+// deleteObjectInBatch deletes one object of DeleteObjects, and returns either the deleted object or the error
+func (s3a *S3ApiServer) deleteObjectInBatch(r *http.Request, client filer_pb.SeaweedFilerClient, requester *requester, bucket, status string, object ObjectIdentifier) (*ObjectIdentifier, *DeleteError) {
+
+	policyAction := "s3:DeleteObject"
+	if object.VersionId != "" {
+		policyAction = "s3:DeleteObjectVersion"
+	}
+	if errCode := s3a.iam.authorize(r, requester, ACTION_WRITE, policyAction, bucket, object.ObjectName); errCode != ErrNone {
+		return nil, newDeleteError(object, errCode)
+	}
+
+	if status != "" || object.VersionId != "" {
+		bypassGovernance, errCode := checkBypassGovernance(r, bucket)
+		if errCode != ErrNone {
+			return nil, newDeleteError(object, errCode)
+		}
+		deletedVersionId, deleteMarker, errCode := s3a.deleteVersionedObject(bucket, "/"+object.ObjectName, object.VersionId, status, bypassGovernance)
+		if errCode != ErrNone {
+			return nil, newDeleteError(object, errCode)
+		}
+		if deleteMarker {
+			object.DeleteMarker, object.DeleteMarkerVersionId = true, deletedVersionId
+		}
+		s3a.notifyObjectRemoved(r, bucket, "/"+object.ObjectName, deletedVersionId, deleteMarker)
+		return &object, nil
+	}
+
+	lastSeparator := strings.LastIndex(object.ObjectName, "/")
+	parentDirectoryPath, entryName, isDeleteData, isRecursive := "/", object.ObjectName, true, true
+	if lastSeparator > 0 && lastSeparator+1 < len(object.ObjectName) {
+		entryName = object.ObjectName[lastSeparator+1:]
+		parentDirectoryPath = "/" + object.ObjectName[:lastSeparator]
+	}
+	parentDirectoryPath = fmt.Sprintf("%s/%s%s", s3a.option.BucketsPath, bucket, parentDirectoryPath)
+
+	if errCode := s3a.checkObjectRemovable(r, bucket, "/"+object.ObjectName); errCode != ErrNone {
+		return nil, newDeleteError(object, errCode)
+	}
+
+	if err := doDeleteEntry(client, parentDirectoryPath, entryName, isDeleteData, isRecursive); err != nil {
+		// deleting a missing object succeeds, the same as DeleteObject
+		if strings.Contains(err.Error(), filer_pb.ErrNotFound.Error()) {
+			return &object, nil
+		}
+		deleteError := newDeleteError(object, ErrInternalError)
+		deleteError.Message = err.Error()
+		return nil, deleteError
+	}
+	s3a.notifyObjectRemoved(r, bucket, "/"+object.ObjectName, "", false)
+	return &object, nil
+}
+
+func newDeleteError(object ObjectIdentifier, errCode ErrorCode) *DeleteError {
+	apiError := getAPIError(errCode)
+	return &DeleteError{
+		Code:      apiError.Code,
+		Message:   apiError.Description,
+		Key:       object.ObjectName,
+		VersionId: object.VersionId,
+	}
+}
+
 func (s3a *S3ApiServer) proxyToFiler(w http.ResponseWriter, r *http.Request, destUrl string, responseFn func(proxyResonse *http.Response, w http.ResponseWriter)) {
 
 	glog.V(2).Infof("s3 proxying %s to %s", r.Method, destUrl)
@@ -245,7 +572,71 @@ func passThroughResponse(proxyResonse *http.Response, w http.ResponseWriter) {
 	io.Copy(w, proxyResonse.Body)
 }
 
-func (s3a *S3ApiServer) putToFiler(r *http.Request, uploadUrl string, dataReader io.Reader) (etag string, code ErrorCode) {
+// passThroughObjectResponse passes on the object read from the filer, decrypted if it is encrypted
+func (s3a *S3ApiServer) passThroughObjectResponse(r *http.Request, key *customerKey) func(proxyResponse *http.Response, w http.ResponseWriter) {
+	return s3a.passThroughObjectPartResponse(r, key, nil)
+}
+
+// hasMultipleRanges checks the Range header for more than one byte range, which S3 does not support
+func hasMultipleRanges(rangeHeader string) bool {
+	return strings.HasPrefix(rangeHeader, "bytes=") && strings.Contains(rangeHeader, ",")
+}
+
+// passThroughObjectPartResponse passes on the object, or the part of the object asked by the partNumber query parameter
+func (s3a *S3ApiServer) passThroughObjectPartResponse(r *http.Request, key *customerKey, part *objectPartRange) func(proxyResponse *http.Response, w http.ResponseWriter) {
+	return func(proxyResponse *http.Response, w http.ResponseWriter) {
+		if proxyResponse.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			if contentRange := proxyResponse.Header.Get("Content-Range"); contentRange != "" {
+				w.Header().Set("Content-Range", contentRange)
+			}
+			writeErrorResponse(w, ErrInvalidRange, r.URL)
+			return
+		}
+		if proxyResponse.StatusCode >= http.StatusMultipleChoices {
+			passThroughResponse(proxyResponse, w)
+			return
+		}
+		body, errCode := s3a.decryptResponse(proxyResponse, key)
+		if errCode != ErrNone {
+			writeErrorResponse(w, errCode, r.URL)
+			return
+		}
+		// the checksum is of the whole object, and only returned when asked for
+		withChecksum := strings.EqualFold(r.Header.Get(AmzChecksumMode), "ENABLED") &&
+			proxyResponse.StatusCode != http.StatusPartialContent
+		// the tags are only counted
+		tagCount := 0
+		for k, v := range proxyResponse.Header {
+			if strings.HasPrefix(k, seaweedEncryptionPrefix) || k == objectAclKey || k == objectPartsKey {
+				continue
+			}
+			// the filer names the file in its Content-Disposition, instead of the one kept with the object,
+			// which is returned with the other standard headers kept aside
+			if k == "Content-Disposition" || strings.HasPrefix(k, objectHeaderPrefix) {
+				continue
+			}
+			if strings.HasPrefix(k, amzChecksumPrefix) && !withChecksum {
+				continue
+			}
+			if strings.HasPrefix(k, AmzObjectTaggingPrefix) {
+				tagCount++
+				continue
+			}
+			w.Header()[k] = v
+		}
+		if tagCount > 0 {
+			w.Header().Set(AmzTaggingCount, strconv.Itoa(tagCount))
+		}
+		setObjectHeaders(r, w, proxyResponse.Header)
+		w.WriteHeader(part.setPartHeaders(r, w, proxyResponse.StatusCode))
+		io.Copy(w, body)
+	}
+}
+
+// putToFiler writes the data to the filer. The size lets the filer choose the chunk size, or save small objects inside the entries.
+// If conditional, the filer saves the entry only if the If-Match and If-None-Match headers still hold for the current one,
+// which is checked atomically.
+func (s3a *S3ApiServer) putToFiler(r *http.Request, uploadUrl string, dataReader io.Reader, size int64, conditional bool) (etag string, code ErrorCode) {
 
 	hash := md5.New()
 	var body = io.TeeReader(dataReader, hash)
@@ -259,12 +650,25 @@ func (s3a *S3ApiServer) putToFiler(r *http.Request, uploadUrl string, dataReader
 
 	proxyReq.Header.Set("Host", s3a.option.Filer)
 	proxyReq.Header.Set("X-Forwarded-For", r.RemoteAddr)
+	if size > 0 {
+		proxyReq.ContentLength = size
+	}
 
 	for header, values := range r.Header {
 		for _, value := range values {
 			proxyReq.Header.Add(header, value)
 		}
 	}
+	if !conditional {
+		proxyReq.Header.Del("If-Match")
+		proxyReq.Header.Del("If-None-Match")
+	}
+	// the aws-chunked encoding is already decoded by the data reader
+	if encoding := removeAwsChunkedEncoding(r.Header.Get("Content-Encoding")); encoding != "" {
+		proxyReq.Header.Set("Content-Encoding", encoding)
+	} else {
+		proxyReq.Header.Del("Content-Encoding")
+	}
 
 	resp, postErr := client.Do(proxyReq)
 
@@ -289,12 +693,29 @@ func (s3a *S3ApiServer) putToFiler(r *http.Request, uploadUrl string, dataReader
 	}
 	if ret.Error != "" {
 		glog.Errorf("upload to filer error: %v", ret.Error)
-		return "", ErrInternalError
+		if resp.StatusCode == http.StatusInsufficientStorage {
+			return "", ErrQuotaExceeded
+		}
+		return "", filerWriteErrorCode(ret.Error)
 	}
 
 	return etag, ErrNone
 }
 
+// declaredObjectSize is the size of the object data of a PUT request, or -1 if unknown
+func declaredObjectSize(r *http.Request) int64 {
+	if r.Method != http.MethodPut {
+		return -1
+	}
+	if size, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64); err == nil {
+		return size
+	}
+	if r.ContentLength > 0 {
+		return r.ContentLength
+	}
+	return -1
+}
+
 func setEtag(w http.ResponseWriter, etag string) {
 	if etag != "" {
 		if strings.HasPrefix(etag, "\"") {
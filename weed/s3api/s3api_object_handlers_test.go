@@ -0,0 +1,272 @@
+package s3api
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestHasMultipleRanges(t *testing.T) {
+	tests := []struct {
+		rangeHeader string
+		multiple    bool
+	}{
+		{"", false},
+		{"bytes=0-99", false},
+		{"bytes=-100", false},
+		{"bytes=100-", false},
+		{"bytes=0-99,200-299", true},
+		{"bytes=0-99, -100", true},
+	}
+	for _, tt := range tests {
+		if got := hasMultipleRanges(tt.rangeHeader); got != tt.multiple {
+			t.Errorf("hasMultipleRanges(%q) = %v, expect %v", tt.rangeHeader, got, tt.multiple)
+		}
+	}
+}
+
+func TestPassThroughObjectResponseInvalidRange(t *testing.T) {
+	s3a := &S3ApiServer{}
+	r := httptest.NewRequest("GET", "/bucket/object", nil)
+	r.Header.Set("Range", "bytes=1000-")
+
+	proxyResponse := &http.Response{
+		StatusCode: http.StatusRequestedRangeNotSatisfiable,
+		Header:     http.Header{"Content-Range": []string{"bytes */100"}},
+		Body:       ioutil.NopCloser(strings.NewReader("invalid range: failed to overlap\n")),
+	}
+	w := httptest.NewRecorder()
+	s3a.passThroughObjectResponse(r, nil)(proxyResponse, w)
+
+	if w.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Fatalf("status %d", w.Code)
+	}
+	if w.Header().Get("Content-Range") != "bytes */100" {
+		t.Errorf("Content-Range %q", w.Header().Get("Content-Range"))
+	}
+	if !strings.Contains(w.Body.String(), "<Code>InvalidRange</Code>") {
+		t.Errorf("unexpected body %s", w.Body.String())
+	}
+}
+
+// fakeFiler keeps the entries in memory, and only implements the lookup, list, create, update and delete
+type fakeFiler struct {
+	filer_pb.SeaweedFilerServer
+	sync.Mutex
+	entries map[util.FullPath]*filer_pb.Entry
+}
+
+func (f *fakeFiler) LookupDirectoryEntry(ctx context.Context, req *filer_pb.LookupDirectoryEntryRequest) (*filer_pb.LookupDirectoryEntryResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	entry, found := f.entries[util.NewFullPath(req.Directory, req.Name)]
+	if !found {
+		return nil, filer_pb.ErrNotFound
+	}
+	return &filer_pb.LookupDirectoryEntryResponse{Entry: entry}, nil
+}
+
+func (f *fakeFiler) ListEntries(req *filer_pb.ListEntriesRequest, stream filer_pb.SeaweedFiler_ListEntriesServer) error {
+	f.Lock()
+	var children []*filer_pb.Entry
+	for p, entry := range f.entries {
+		dir, name := p.DirAndName()
+		if dir != req.Directory || !strings.HasPrefix(name, req.Prefix) {
+			continue
+		}
+		if name < req.StartFromFileName || name == req.StartFromFileName && !req.InclusiveStartFrom {
+			continue
+		}
+		entry.Name = name
+		children = append(children, entry)
+	}
+	f.Unlock()
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Name < children[j].Name
+	})
+	if req.Limit > 0 && len(children) > int(req.Limit) {
+		children = children[:req.Limit]
+	}
+	for _, entry := range children {
+		if err := stream.Send(&filer_pb.ListEntriesResponse{Entry: entry}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeFiler) CreateEntry(ctx context.Context, req *filer_pb.CreateEntryRequest) (*filer_pb.CreateEntryResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	p := util.NewFullPath(req.Directory, req.Entry.Name)
+	if existing, found := f.entries[p]; req.IfMatch != "" && (!found || !filer2.ETagMatches(req.IfMatch, filer2.ETag(existing))) ||
+		req.IfNoneMatch != "" && found && filer2.ETagMatches(req.IfNoneMatch, filer2.ETag(existing)) {
+		return &filer_pb.CreateEntryResponse{Error: filer_pb.ErrPreconditionFailed.Error()}, nil
+	}
+	f.entries[p] = req.Entry
+	return &filer_pb.CreateEntryResponse{}, nil
+}
+
+func (f *fakeFiler) UpdateEntry(ctx context.Context, req *filer_pb.UpdateEntryRequest) (*filer_pb.UpdateEntryResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.entries[util.NewFullPath(req.Directory, req.Entry.Name)] = req.Entry
+	return &filer_pb.UpdateEntryResponse{}, nil
+}
+
+func (f *fakeFiler) DeleteEntry(ctx context.Context, req *filer_pb.DeleteEntryRequest) (*filer_pb.DeleteEntryResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	p := util.NewFullPath(req.Directory, req.Name)
+	if _, found := f.entries[p]; !found {
+		return &filer_pb.DeleteEntryResponse{Error: filer_pb.ErrNotFound.Error()}, nil
+	}
+	delete(f.entries, p)
+	if req.IsRecursive {
+		for child := range f.entries {
+			if strings.HasPrefix(string(child), string(p)+"/") {
+				delete(f.entries, child)
+			}
+		}
+	}
+	return &filer_pb.DeleteEntryResponse{}, nil
+}
+
+func (f *fakeFiler) AtomicRenameEntry(ctx context.Context, req *filer_pb.AtomicRenameEntryRequest) (*filer_pb.AtomicRenameEntryResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	oldPath, newPath := util.NewFullPath(req.OldDirectory, req.OldName), util.NewFullPath(req.NewDirectory, req.NewName)
+	entry, found := f.entries[oldPath]
+	if !found {
+		return nil, filer_pb.ErrNotFound
+	}
+	entry.Name = req.NewName
+	delete(f.entries, oldPath)
+	f.entries[newPath] = entry
+	for child, childEntry := range f.entries {
+		if strings.HasPrefix(string(child), string(oldPath)+"/") {
+			delete(f.entries, child)
+			f.entries[newPath+child[len(oldPath):]] = childEntry
+		}
+	}
+	return &filer_pb.AtomicRenameEntryResponse{}, nil
+}
+
+func (f *fakeFiler) DeleteCollection(ctx context.Context, req *filer_pb.DeleteCollectionRequest) (*filer_pb.DeleteCollectionResponse, error) {
+	return &filer_pb.DeleteCollectionResponse{}, nil
+}
+
+func startFakeFiler(t *testing.T, entries map[util.FullPath]*filer_pb.Entry) (*S3ApiServer, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	filer_pb.RegisterSeaweedFilerServer(grpcServer, &fakeFiler{entries: entries})
+	go grpcServer.Serve(listener)
+
+	s3a := &S3ApiServer{
+		option: &S3ApiServerOption{
+			FilerGrpcAddress: listener.Addr().String(),
+			BucketsPath:      "/buckets",
+			GrpcDialOption:   grpc.WithInsecure(),
+		},
+		iam: &IdentityAccessManagement{},
+	}
+	return s3a, grpcServer.Stop
+}
+
+func deleteObjects(s3a *S3ApiServer, request DeleteObjectsRequest) *httptest.ResponseRecorder {
+	body, _ := xml.Marshal(request)
+	r := httptest.NewRequest("POST", "/bucket?delete", strings.NewReader(string(body)))
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket"})
+	w := httptest.NewRecorder()
+	s3a.DeleteMultipleObjectsHandler(w, r)
+	return w
+}
+
+func TestDeleteMultipleObjects(t *testing.T) {
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket": {Name: "bucket", IsDirectory: true},
+		"/buckets/bucket/locked": {Name: "locked", Extended: map[string][]byte{
+			AmzObjectLockLegalHold: []byte(LegalHoldOn),
+		}},
+	}
+	var request DeleteObjectsRequest
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("dir/%03d", i)
+		entries[util.FullPath("/buckets/bucket/"+name)] = &filer_pb.Entry{Name: name[len("dir/"):]}
+		request.Objects = append(request.Objects, ObjectIdentifier{ObjectName: name})
+		if i == 50 {
+			request.Objects = append(request.Objects, ObjectIdentifier{ObjectName: "locked"}, ObjectIdentifier{ObjectName: "missing"})
+		}
+	}
+
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+
+	w := deleteObjects(s3a, request)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body.String())
+	}
+	var response DeleteObjectsResponse
+	if err := xml.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unmarshal %s: %v", w.Body.String(), err)
+	}
+	if len(response.DeletedObjects) != 101 {
+		t.Errorf("deleted %d objects, expect 101", len(response.DeletedObjects))
+	}
+	for i, object := range response.DeletedObjects[:50] {
+		if object.ObjectName != fmt.Sprintf("dir/%03d", i) {
+			t.Fatalf("deleted object %d is %s, not in the request order", i, object.ObjectName)
+		}
+	}
+	if len(response.Errors) != 1 || response.Errors[0].Key != "locked" || response.Errors[0].Code != "AccessDenied" {
+		t.Errorf("unexpected errors %+v", response.Errors)
+	}
+	if len(entries) != 2 {
+		t.Errorf("%d entries left, expect the bucket and the locked object", len(entries))
+	}
+
+	request.Quiet = true
+	w = deleteObjects(s3a, request)
+	response = DeleteObjectsResponse{}
+	if err := xml.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unmarshal %s: %v", w.Body.String(), err)
+	}
+	if len(response.DeletedObjects) != 0 || len(response.Errors) != 1 {
+		t.Errorf("quiet response should only have the errors: %s", w.Body.String())
+	}
+}
+
+func TestDeleteMultipleObjectsLimit(t *testing.T) {
+	var request DeleteObjectsRequest
+	for i := 0; i <= maxDeleteObjects; i++ {
+		request.Objects = append(request.Objects, ObjectIdentifier{ObjectName: fmt.Sprintf("%d", i)})
+	}
+	w := deleteObjects(&S3ApiServer{}, request)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "<Code>MalformedXML</Code>") {
+		t.Errorf("status %d: %s", w.Code, w.Body.String())
+	}
+
+	w = deleteObjects(&S3ApiServer{}, DeleteObjectsRequest{})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("empty request status %d", w.Code)
+	}
+}
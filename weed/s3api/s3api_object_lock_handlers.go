@@ -0,0 +1,250 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PutObjectLockConfigurationHandler - PUT bucket ?object-lock
+func (s3a *S3ApiServer) PutObjectLockConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectLockConfiguration.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &ObjectLockConfiguration{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if errCode := config.validate(); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	config.Xmlns = ""
+	configXMLBytes, _ := xml.Marshal(config)
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		extended[bucketObjectLockConfigurationKey] = configXMLBytes
+		// the compliance locked objects are written to append only volumes, where they can not be deleted.
+		// The volumes stay append only, so the bucket does too, even if the default retention is changed later.
+		if config.isCompliance() {
+			extended[filer2.BucketAppendOnlyKey] = []byte("true")
+		}
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetObjectLockConfigurationHandler - GET bucket ?object-lock
+func (s3a *S3ApiServer) GetObjectLockConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config, errCode := s3a.getBucketObjectLockConfiguration(bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if config == nil {
+		writeErrorResponse(w, ErrObjectLockConfigurationNotFound, r.URL)
+		return
+	}
+
+	config.Xmlns = s3Namespace
+	writeSuccessResponseXML(w, encodeResponse(config))
+}
+
+// PutObjectRetentionHandler - PUT object ?retention
+func (s3a *S3ApiServer) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectRetention.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	retention := &ObjectRetention{}
+	if err := decodeXMLBody(r, retention, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+
+	now := time.Now()
+	var retainUntilDate time.Time
+	if retention.Mode != "" || retention.RetainUntilDate != nil {
+		if !isValidObjectLockMode(retention.Mode) || retention.RetainUntilDate == nil {
+			writeErrorResponse(w, ErrMalformedXML, r.URL)
+			return
+		}
+		retainUntilDate = *retention.RetainUntilDate
+		if !retainUntilDate.After(now) {
+			writeErrorResponse(w, ErrPastObjectLockRetainDate, r.URL)
+			return
+		}
+	}
+
+	bypassGovernance, errCode := checkBypassGovernance(r, bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	errCode = s3a.updateObjectLock(r, bucket, object, func(lock *objectLock) ErrorCode {
+		if code := lock.checkRetentionChange(retention.Mode, retainUntilDate, now, bypassGovernance); code != ErrNone {
+			return code
+		}
+		lock.mode, lock.retainUntilDate = retention.Mode, retainUntilDate
+		return ErrNone
+	})
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetObjectRetentionHandler - GET object ?retention
+func (s3a *S3ApiServer) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	lock, errCode := s3a.getObjectLock(bucket, object)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if lock.mode == "" {
+		writeErrorResponse(w, ErrNoSuchObjectLockConfiguration, r.URL)
+		return
+	}
+
+	response := ObjectRetention{
+		Xmlns:           s3Namespace,
+		Mode:            lock.mode,
+		RetainUntilDate: &lock.retainUntilDate,
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// PutObjectLegalHoldHandler - PUT object ?legal-hold
+func (s3a *S3ApiServer) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectLegalHold.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	legalHold := &ObjectLegalHold{}
+	if err := decodeXMLBody(r, legalHold, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if !isValidLegalHoldStatus(legalHold.Status) {
+		writeErrorResponse(w, ErrMalformedXML, r.URL)
+		return
+	}
+
+	errCode := s3a.updateObjectLock(r, bucket, object, func(lock *objectLock) ErrorCode {
+		lock.legalHold = legalHold.Status
+		return ErrNone
+	})
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetObjectLegalHoldHandler - GET object ?legal-hold
+func (s3a *S3ApiServer) GetObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	lock, errCode := s3a.getObjectLock(bucket, object)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	if lock.legalHold == "" {
+		writeErrorResponse(w, ErrNoSuchObjectLockConfiguration, r.URL)
+		return
+	}
+
+	response := ObjectLegalHold{
+		Xmlns:  s3Namespace,
+		Status: lock.legalHold,
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+func (s3a *S3ApiServer) getObjectLock(bucket, object string) (lock *objectLock, code ErrorCode) {
+
+	_, _, entry, err := s3a.objectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("lookup object %s%s: %v", bucket, object, err)
+		return nil, ErrInternalError
+	}
+	if entry == nil || entry.IsDirectory {
+		return nil, ErrNoSuchKey
+	}
+
+	return loadObjectLock(entry.Extended), ErrNone
+}
+
+// updateObjectLock changes the retention or legal hold of an existing object in a bucket with object lock enabled
+func (s3a *S3ApiServer) updateObjectLock(r *http.Request, bucket, object string, fn func(lock *objectLock) ErrorCode) ErrorCode {
+
+	config, errCode := s3a.getBucketObjectLockConfiguration(bucket)
+	if errCode != ErrNone {
+		return errCode
+	}
+	if !config.isEnabled() {
+		return ErrMissingObjectLockConfiguration
+	}
+
+	dir, _, entry, err := s3a.objectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("lookup object %s%s: %v", bucket, object, err)
+		return ErrInternalError
+	}
+	if entry == nil || entry.IsDirectory {
+		return ErrNoSuchKey
+	}
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+
+	lock := loadObjectLock(entry.Extended)
+	if errCode = fn(lock); errCode != ErrNone {
+		return errCode
+	}
+	lock.saveTo(entry.Extended)
+
+	if err = s3a.updateEntry(dir, entry); err != nil {
+		glog.Errorf("update object lock %s%s: %v", bucket, object, err)
+		return ErrInternalError
+	}
+
+	return ErrNone
+}
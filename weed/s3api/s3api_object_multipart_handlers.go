@@ -2,21 +2,23 @@ package s3api
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
 )
 
 const (
 	maxObjectListSizeLimit = 10000 // Limit number of objects in a listObjectsResponse.
-	maxUploadsList         = 10000 // Limit number of uploads in a listUploadsResponse.
-	maxPartsList           = 10000 // Limit number of parts in a listPartsResponse.
-	globalMaxPartID        = 100000
+	maxUploadsList         = 1000  // Limit number of uploads in a listUploadsResponse.
+	maxPartsList           = 1000  // Limit number of parts in a listPartsResponse.
+	globalMaxPartID        = 10000
 )
 
 // NewMultipartUploadHandler - New multipart upload.
@@ -26,10 +28,70 @@ func (s3a *S3ApiServer) NewMultipartUploadHandler(w http.ResponseWriter, r *http
 	bucket = vars["bucket"]
 	object = vars["object"]
 
-	response, errCode := s3a.createMultipartUpload(&s3.CreateMultipartUploadInput{
-		Bucket: aws.String(bucket),
-		Key:    objectKey(aws.String(object)),
-	})
+	lock, errCode := s3a.prepareObjectLock(r, bucket, "/"+object)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	encryption, _, errCode := s3a.prepareObjectEncryption(r, bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	checksumAlgorithm, errCode := parseChecksumAlgorithm(r.Header.Get(AmzChecksumAlgorithm))
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	tags, errCode := prepareObjectTags(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	acl, errCode := prepareCannedAcl(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	storageClass, _, errCode := s3a.prepareStorageClass(r, bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	metadata, errCode := parseRequestMetadata(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:       aws.String(bucket),
+		Key:          objectKey(aws.String(object)),
+		StorageClass: aws.String(storageClass),
+	}
+	if acl != "" {
+		input.ACL = aws.String(acl)
+	}
+	if tags != nil {
+		input.Tagging = aws.String(tags.encode())
+	}
+	if lock != nil {
+		if lock.mode != "" {
+			input.ObjectLockMode = aws.String(lock.mode)
+			input.ObjectLockRetainUntilDate = aws.Time(lock.retainUntilDate)
+		}
+		if lock.legalHold != "" {
+			input.ObjectLockLegalHoldStatus = aws.String(lock.legalHold)
+		}
+	}
+
+	response, errCode := s3a.createMultipartUpload(input, encryption, checksumAlgorithm, metadata)
 
 	if errCode != ErrNone {
 		writeErrorResponse(w, errCode, r.URL)
@@ -38,6 +100,10 @@ func (s3a *S3ApiServer) NewMultipartUploadHandler(w http.ResponseWriter, r *http
 
 	// println("NewMultipartUploadHandler", string(encodeResponse(response)))
 
+	setEncryptionHeaders(w, encryption)
+	if checksumAlgorithm != "" {
+		w.Header().Set(AmzChecksumAlgorithm, checksumAlgorithm)
+	}
 	writeSuccessResponseXML(w, encodeResponse(response))
 
 }
@@ -51,6 +117,16 @@ func (s3a *S3ApiServer) CompleteMultipartUploadHandler(w http.ResponseWriter, r
 	// Get upload id.
 	uploadID, _, _, _ := getObjectResources(r.URL.Query())
 
+	if errCode := s3a.checkObjectRemovable(r, bucket, object); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	if errCode := s3a.checkObjectPreconditions(w, r, bucket, object); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
 	response, errCode := s3a.completeMultipartUpload(&s3.CompleteMultipartUploadInput{
 		Bucket:   aws.String(bucket),
 		Key:      objectKey(aws.String(object)),
@@ -64,8 +140,11 @@ func (s3a *S3ApiServer) CompleteMultipartUploadHandler(w http.ResponseWriter, r
 		return
 	}
 
+	setVersionHeaders(w, response.VersionId, false)
+
 	writeSuccessResponseXML(w, encodeResponse(response))
 
+	s3a.notify(r, EventObjectCreatedCompleteMultipartUpload, bucket, object, response.Size, *response.ETag, response.VersionId)
 }
 
 // AbortMultipartUploadHandler - Aborts multipart upload.
@@ -104,12 +183,8 @@ func (s3a *S3ApiServer) ListMultipartUploadsHandler(w http.ResponseWriter, r *ht
 		writeErrorResponse(w, ErrInvalidMaxUploads, r.URL)
 		return
 	}
-	if keyMarker != "" {
-		// Marker not common with prefix is not implemented.
-		if !strings.HasPrefix(keyMarker, prefix) {
-			writeErrorResponse(w, ErrNotImplemented, r.URL)
-			return
-		}
+	if maxUploads > maxUploadsList {
+		maxUploads = maxUploadsList
 	}
 
 	response, errCode := s3a.listMultipartUploads(&s3.ListMultipartUploadsInput{
@@ -148,6 +223,9 @@ func (s3a *S3ApiServer) ListObjectPartsHandler(w http.ResponseWriter, r *http.Re
 		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
 		return
 	}
+	if maxParts > maxPartsList {
+		maxParts = maxPartsList
+	}
 
 	response, errCode := s3a.listObjectParts(&s3.ListPartsInput{
 		Bucket:           aws.String(bucket),
@@ -173,12 +251,10 @@ func (s3a *S3ApiServer) PutObjectPartHandler(w http.ResponseWriter, r *http.Requ
 	vars := mux.Vars(r)
 	bucket := vars["bucket"]
 
-	rAuthType := getRequestAuthType(r)
-
 	uploadID := r.URL.Query().Get("uploadId")
-	exists, err := s3a.exists(s3a.genUploadsFolder(bucket), uploadID, true)
-	if !exists {
-		writeErrorResponse(w, ErrNoSuchUpload, r.URL)
+	upload, errCode := s3a.prepareUploadPart(r, bucket, uploadID)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
 		return
 	}
 
@@ -188,38 +264,111 @@ func (s3a *S3ApiServer) PutObjectPartHandler(w http.ResponseWriter, r *http.Requ
 		writeErrorResponse(w, ErrInvalidPart, r.URL)
 		return
 	}
+	if partID < 1 {
+		writeErrorResponse(w, ErrInvalidPart, r.URL)
+		return
+	}
 	if partID > globalMaxPartID {
 		writeErrorResponse(w, ErrInvalidMaxParts, r.URL)
 		return
 	}
 
-	var s3ErrCode ErrorCode
-	dataReader := r.Body
-	if rAuthType == authTypeStreamingSigned {
-		dataReader, s3ErrCode = s3a.iam.newSignV4ChunkedReader(r)
+	if errCode := s3a.checkRequestSize(r, bucket, false); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
 	}
+
+	dataReader, s3ErrCode := s3a.iam.newStreamingReader(r)
 	if s3ErrCode != ErrNone {
 		writeErrorResponse(w, s3ErrCode, r.URL)
 		return
 	}
 	defer dataReader.Close()
 
-	uploadUrl := fmt.Sprintf("http://%s%s/%s/%04d.part?collection=%s",
-		s3a.option.Filer, s3a.genUploadsFolder(bucket), uploadID, partID-1, bucket)
+	checksum, errCode := newChecksumReader(r, dataReader, upload.checksumAlgorithm)
+	if errCode == ErrNone {
+		errCode = checksum.verifyContentMd5(r)
+	}
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	var body io.Reader = checksum
+	if upload.encryption != nil {
+		if body, err = upload.encryption.encryptReader(upload.dataKey, checksum, partID); err != nil {
+			glog.Errorf("encrypt upload %s part %d: %v", uploadID, partID, err)
+			writeErrorResponse(w, ErrInternalError, r.URL)
+			return
+		}
+	}
+
+	// the parts are combined by their chunks, so they are never saved inside the entries
+	uploadUrl := upload.tier.addTo(fmt.Sprintf("http://%s%s/%s/%04d.part?collection=%s&saveInside=false",
+		s3a.option.Filer, s3a.genUploadsFolder(bucket), uploadID, partID-1, bucket))
 
-	etag, errCode := s3a.putToFiler(r, uploadUrl, dataReader)
+	etag, errCode := s3a.putToFiler(r, uploadUrl, body, declaredObjectSize(r), false)
 
+	if chunked, ok := dataReader.(*s3ChunkedReader); ok && chunked.errorCode() != ErrNone {
+		writeErrorResponse(w, chunked.errorCode(), r.URL)
+		return
+	}
+	if checksum.mismatch {
+		writeErrorResponse(w, ErrBadDigest, r.URL)
+		return
+	}
 	if errCode != ErrNone {
 		writeErrorResponse(w, errCode, r.URL)
 		return
 	}
 
+	if err := s3a.savePartChecksum(bucket, uploadID, partID, checksum.checksum()); err != nil {
+		glog.Errorf("save upload %s part %d checksum: %v", uploadID, partID, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
 	setEtag(w, etag)
+	setEncryptionHeaders(w, upload.encryption)
+	setChecksumHeaders(w, checksum.checksum())
 
 	writeSuccessResponseEmpty(w)
 
 }
 
+// uploadPart is how the parts are written, as decided when the multipart upload was initiated
+type uploadPart struct {
+	encryption        *objectEncryption
+	dataKey           []byte
+	checksumAlgorithm string
+	tier              storageTier
+}
+
+// prepareUploadPart checks the customer key of a part against the one of the multipart upload
+func (s3a *S3ApiServer) prepareUploadPart(r *http.Request, bucket, uploadID string) (upload uploadPart, code ErrorCode) {
+
+	key, code := parseRequestCustomerKey(r)
+	if code != ErrNone {
+		return upload, code
+	}
+
+	uploadEntry, err := s3a.getEntry(s3a.genUploadsFolder(bucket), uploadID)
+	if err != nil || uploadEntry == nil || !uploadEntry.IsDirectory {
+		return upload, ErrNoSuchUpload
+	}
+
+	upload.encryption = loadObjectEncryption(uploadEntry.Extended)
+	if upload.dataKey, code = upload.encryption.dataKey(key, s3a.masterKey); code != ErrNone {
+		return upload, code
+	}
+	upload.checksumAlgorithm = string(uploadEntry.Extended[AmzChecksumAlgorithm])
+	if _, upload.tier, code = s3a.storageClasses.tier(bucket, getStorageClass(uploadEntry.Extended)); code != ErrNone {
+		return upload, code
+	}
+
+	return upload, ErrNone
+}
+
 func (s3a *S3ApiServer) genUploadsFolder(bucket string) string {
 	return fmt.Sprintf("%s/%s/.uploads", s3a.option.BucketsPath, bucket)
 }
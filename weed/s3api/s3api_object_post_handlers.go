@@ -0,0 +1,248 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// PostObjectResult is returned for success_action_status=201
+type PostObjectResult struct {
+	XMLName  xml.Name `xml:"PostResponse"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+// the form fields controlling the upload, instead of passed to the filer as the object headers
+var postFormControlFields = map[string]bool{
+	"Key":                     true,
+	"Policy":                  true,
+	"X-Amz-Algorithm":         true,
+	"X-Amz-Credential":        true,
+	"X-Amz-Date":              true,
+	"X-Amz-Signature":         true,
+	"X-Amz-Security-Token":    true,
+	"Success_action_redirect": true,
+	"Success_action_status":   true,
+	"Redirect":                true,
+	"Acl":                     true,
+	"Bucket":                  true,
+	"Tagging":                 true,
+}
+
+// PostPolicyBucketHandler - POST bucket with multipart/form-data, the browser-based uploads
+func (s3a *S3ApiServer) PostPolicyBucketHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectPOST.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	form, file, errCode := readPostForm(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	defer file.Close()
+
+	key := form.Get("Key")
+	if key == "" {
+		writeErrorResponse(w, ErrMalformedPOSTRequest, r.URL)
+		return
+	}
+	key = strings.Replace(key, "${filename}", file.FileName(), -1)
+	form.Set("Key", key)
+	object := "/" + strings.TrimPrefix(key, "/")
+
+	// the object headers, and the session token looked up by the signature check
+	objectRequest := new(http.Request)
+	*objectRequest = *r
+	objectRequest.Header = make(http.Header)
+	for name, values := range form {
+		if !postFormControlFields[name] && !strings.HasPrefix(strings.ToLower(name), "x-ignore-") {
+			objectRequest.Header[name] = values
+		}
+	}
+	if objectRequest.Header.Get("Content-Type") == "" {
+		objectRequest.Header.Set("Content-Type", file.Header.Get("Content-Type"))
+	}
+	if acl := form.Get("Acl"); acl != "" {
+		objectRequest.Header.Set(AmzAcl, acl)
+	}
+	if token := form.Get("X-Amz-Security-Token"); token != "" {
+		objectRequest.Header.Set("X-Amz-Security-Token", token)
+	}
+	if taggingXML := form.Get("Tagging"); taggingXML != "" {
+		tagging := &Tagging{}
+		if err := xml.Unmarshal([]byte(taggingXML), tagging); err != nil {
+			writeErrorResponse(w, ErrMalformedXML, r.URL)
+			return
+		}
+		tags, errCode := tagging.toObjectTags()
+		if errCode != ErrNone {
+			writeErrorResponse(w, errCode, r.URL)
+			return
+		}
+		objectRequest.Header.Set(AmzTagging, tags.encode())
+	}
+
+	requester, errCode := s3a.authenticatePostPolicy(form, objectRequest)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	objectRequest.Header.Del("X-Amz-Security-Token")
+	setAccessLogRequester(w, requester)
+	dropAdminHeaders(objectRequest, requester)
+
+	var dataReader io.Reader = file
+	var lengthRange *lengthRangeReader
+	if encodedPolicy := form.Get("Policy"); encodedPolicy != "" {
+		policy, err := parsePostPolicy(encodedPolicy)
+		if err != nil {
+			glog.V(1).Infof("post policy to %s: %v", bucket, err)
+			writeErrorResponse(w, ErrInvalidPolicyDocument, r.URL)
+			return
+		}
+		if errCode = policy.checkForm(bucket, form, time.Now()); errCode != ErrNone {
+			writeErrorResponse(w, errCode, r.URL)
+			return
+		}
+		if policy.hasLengthRange {
+			lengthRange = &lengthRangeReader{reader: file, min: policy.minLength, max: policy.maxLength}
+		}
+	}
+
+	limits, err := s3a.getObjectSizeLimits(bucket)
+	if err != nil {
+		glog.Errorf("load size limits of bucket %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if limit := limits.singlePutLimit(); limit > 0 {
+		if lengthRange == nil {
+			lengthRange = &lengthRangeReader{reader: file, max: limit}
+		} else if lengthRange.max > limit {
+			lengthRange.max = limit
+		}
+	}
+	if lengthRange != nil {
+		dataReader = lengthRange
+	}
+
+	if errCode = s3a.iam.authorize(r, requester, ACTION_WRITE, "s3:PutObject", bucket, object); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	result, errCode := s3a.putObject(objectRequest, bucket, object, dataReader)
+	if lengthRange != nil && lengthRange.code != ErrNone {
+		errCode = lengthRange.code
+	}
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	location := s3a.objectLocation(r, bucket, object)
+	setEtag(w, result.etag)
+	setVersionHeaders(w, result.versionId, false)
+	setEncryptionHeaders(w, result.encryption)
+	setChecksumHeaders(w, result.checksum)
+	w.Header().Set("Location", location)
+
+	redirect := form.Get("Success_action_redirect")
+	if redirect == "" {
+		redirect = form.Get("Redirect")
+	}
+	if redirectUrl, err := url.Parse(redirect); redirect != "" && err == nil {
+		query := redirectUrl.Query()
+		query.Set("bucket", bucket)
+		query.Set("key", key)
+		query.Set("etag", "\""+result.etag+"\"")
+		redirectUrl.RawQuery = query.Encode()
+		w.Header().Set("Location", redirectUrl.String())
+		writeResponse(w, http.StatusSeeOther, nil, mimeNone)
+	} else {
+		switch form.Get("Success_action_status") {
+		case "200":
+			writeSuccessResponseEmpty(w)
+		case "201":
+			writeResponse(w, http.StatusCreated, encodeResponse(PostObjectResult{
+				Location: location,
+				Bucket:   bucket,
+				Key:      key,
+				ETag:     "\"" + result.etag + "\"",
+			}), mimeXML)
+		default:
+			writeResponse(w, http.StatusNoContent, nil, mimeNone)
+		}
+	}
+
+	s3a.notify(r, EventObjectCreatedPost, bucket, object, result.size, result.etag, result.versionId)
+}
+
+// readPostForm reads the form fields up to the file, which must be the last field
+func readPostForm(r *http.Request) (form http.Header, file *multipart.Part, code ErrorCode) {
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, nil, ErrMalformedPOSTRequest
+	}
+
+	form = make(http.Header)
+	remaining := int64(maxPostFormSize)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			// no file in the form
+			return nil, nil, ErrMalformedPOSTRequest
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		if strings.ToLower(name) == "file" {
+			return form, part, ErrNone
+		}
+		value, err := ioutil.ReadAll(io.LimitReader(part, remaining+1))
+		part.Close()
+		if err != nil {
+			return nil, nil, ErrMalformedPOSTRequest
+		}
+		if remaining -= int64(len(value)); remaining < 0 {
+			return nil, nil, ErrMaxPostPreDataLengthExceeded
+		}
+		form.Add(name, string(value))
+	}
+}
+
+// authenticatePostPolicy checks the policy signature of the form.
+// The form without any signature is anonymous, only allowed by the bucket policies.
+func (s3a *S3ApiServer) authenticatePostPolicy(form http.Header, r *http.Request) (*requester, ErrorCode) {
+
+	if len(s3a.iam.identities) == 0 {
+		return &requester{trusted: true}, ErrNone
+	}
+
+	if form.Get("X-Amz-Signature") == "" && form.Get("X-Amz-Credential") == "" {
+		return &requester{}, ErrNone
+	}
+
+	identity, errCode := s3a.iam.doesPolicySignatureV4Match(form, r)
+	if errCode != ErrNone {
+		return nil, errCode
+	}
+	return &requester{identity: identity}, ErrNone
+}
@@ -0,0 +1,133 @@
+package s3api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// SeaweedRenameHeader turns a CopyObject request into a rename, a SeaweedFS extension not in the S3 API.
+// The filer entries are moved without copying the data, so renaming a large object takes no longer than a small one.
+// A copy source ending with "/" renames the prefix, i.e. the filer directory and everything under it,
+// in one transaction if the filer store supports transactions, or journaled and rolled forward after a crash otherwise.
+// The extension is enabled by the s3 -allowRename option.
+const SeaweedRenameHeader = "X-Seaweedfs-Rename"
+
+// RenameObjectHandler - PUT object with x-amz-copy-source and x-seaweedfs-rename: true
+func (s3a *S3ApiServer) RenameObjectHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	dstObject := getObject(vars)
+
+	if !s3a.option.AllowRename {
+		writeErrorResponseWithMessage(w, ErrNotImplemented, r.URL, "Renaming is a SeaweedFS extension, not enabled on this server.")
+		return
+	}
+
+	cpSrcPath, err := url.QueryUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		cpSrcPath = r.Header.Get("X-Amz-Copy-Source")
+	}
+	srcBucket, srcObject := pathToBucketAndObject(cpSrcPath)
+	if srcBucket == "" || srcObject == "/" || srcObject == dstObject {
+		writeErrorResponse(w, ErrInvalidCopySource, r.URL)
+		return
+	}
+	// the chunks of the objects are in the collection of their bucket
+	if srcBucket != bucket {
+		writeErrorResponseWithMessage(w, ErrInvalidCopySource, r.URL, "An object can only be renamed within its bucket.")
+		return
+	}
+	isPrefix := strings.HasSuffix(srcObject, "/")
+	if isPrefix != strings.HasSuffix(dstObject, "/") {
+		writeErrorResponseWithMessage(w, ErrInvalidRequest, r.URL, "A prefix can only be renamed to a prefix, and an object to an object.")
+		return
+	}
+
+	if errCode := s3a.checkRename(r, bucket, srcObject, dstObject, isPrefix); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	srcDir, srcName := s3a.objectDirAndName(bucket, strings.TrimSuffix(srcObject, "/"))
+	_, _, srcEntry, err := s3a.objectEntry(bucket, strings.TrimSuffix(srcObject, "/"))
+	if err != nil {
+		glog.Errorf("lookup rename source %s%s: %v", bucket, srcObject, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	if srcEntry == nil || srcEntry.IsDirectory != isPrefix {
+		writeErrorResponse(w, ErrNoSuchKey, r.URL)
+		return
+	}
+
+	dstDir, dstName := s3a.objectDirAndName(bucket, strings.TrimSuffix(dstObject, "/"))
+	dstEntry, err := s3a.getEntry(dstDir, dstName)
+	if err != nil {
+		glog.Errorf("lookup rename destination %s%s: %v", bucket, dstObject, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+	// an object can be overwritten, as by a PutObject, but not a prefix
+	if dstEntry != nil && (isPrefix || dstEntry.IsDirectory) {
+		writeErrorResponseWithMessage(w, ErrInvalidRequest, r.URL, "The rename destination already exists.")
+		return
+	}
+
+	if err := s3a.rename(srcDir, srcName, dstDir, dstName); err != nil {
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	response := CopyObjectResult{
+		LastModified: time.Now(),
+	}
+	if !isPrefix {
+		response.ETag = filer2.ETag(srcEntry)
+		setEtag(w, response.ETag)
+	}
+	writeSuccessResponseXML(w, encodeResponse(response))
+
+	// the objects renamed with their prefix are not notified one by one
+	if !isPrefix {
+		s3a.notify(r, EventObjectCreatedCopy, bucket, dstObject, int64(filer2.FileSize(srcEntry)), response.ETag, "")
+		s3a.notify(r, EventObjectRemovedDelete, bucket, srcObject, 0, "", "")
+	}
+}
+
+// checkRename rejects the renames which would lose object versions or locked objects
+func (s3a *S3ApiServer) checkRename(r *http.Request, bucket, srcObject, dstObject string, isPrefix bool) ErrorCode {
+
+	status, errCode := s3a.getBucketVersioning(bucket)
+	if errCode != ErrNone {
+		return errCode
+	}
+	// the versions are kept by their keys
+	if status != "" {
+		return ErrNotImplemented
+	}
+
+	if isPrefix {
+		// the locks of all the objects under the prefix are not checked
+		config, errCode := s3a.getBucketObjectLockConfiguration(bucket)
+		if errCode != ErrNone {
+			return errCode
+		}
+		if config.isEnabled() {
+			return ErrNotImplemented
+		}
+		return ErrNone
+	}
+
+	if errCode = s3a.checkObjectRemovable(r, bucket, srcObject); errCode != ErrNone {
+		return errCode
+	}
+	return s3a.checkObjectRemovable(r, bucket, dstObject)
+}
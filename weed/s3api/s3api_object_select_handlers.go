@@ -0,0 +1,361 @@
+package s3api
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/query/csv"
+	"github.com/chrislusf/seaweedfs/weed/query/json"
+	"github.com/chrislusf/seaweedfs/weed/query/sql"
+	"github.com/chrislusf/seaweedfs/weed/query/sqltypes"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+const (
+	maxSelectRequestSize = 256 * 1024
+	// selected records are sent once this many bytes are buffered
+	selectRecordsEventSize = 64 * 1024
+)
+
+type SelectObjectContentRequest struct {
+	XMLName             xml.Name                  `xml:"SelectObjectContentRequest"`
+	Expression          string                    `xml:"Expression"`
+	ExpressionType      string                    `xml:"ExpressionType"`
+	RequestProgress     SelectRequestProgress     `xml:"RequestProgress"`
+	InputSerialization  SelectInputSerialization  `xml:"InputSerialization"`
+	OutputSerialization SelectOutputSerialization `xml:"OutputSerialization"`
+	ScanRange           *SelectScanRange          `xml:"ScanRange"`
+}
+
+type SelectRequestProgress struct {
+	Enabled bool `xml:"Enabled"`
+}
+
+type SelectScanRange struct {
+	Start *int64 `xml:"Start"`
+	End   *int64 `xml:"End"`
+}
+
+type SelectInputSerialization struct {
+	CompressionType string              `xml:"CompressionType"`
+	CSV             *SelectCSVInput     `xml:"CSV"`
+	JSON            *SelectJSONInput    `xml:"JSON"`
+	Parquet         *SelectParquetInput `xml:"Parquet"`
+}
+
+type SelectCSVInput struct {
+	FileHeaderInfo             string `xml:"FileHeaderInfo"`
+	RecordDelimiter            string `xml:"RecordDelimiter"`
+	FieldDelimiter             string `xml:"FieldDelimiter"`
+	QuoteCharacter             string `xml:"QuoteCharacter"`
+	QuoteEscapeCharacter       string `xml:"QuoteEscapeCharacter"`
+	Comments                   string `xml:"Comments"`
+	AllowQuotedRecordDelimiter bool   `xml:"AllowQuotedRecordDelimiter"`
+}
+
+type SelectJSONInput struct {
+	Type string `xml:"Type"`
+}
+
+type SelectParquetInput struct {
+}
+
+type SelectOutputSerialization struct {
+	CSV  *SelectCSVOutput  `xml:"CSV"`
+	JSON *SelectJSONOutput `xml:"JSON"`
+}
+
+type SelectCSVOutput struct {
+	QuoteFields          string `xml:"QuoteFields"`
+	RecordDelimiter      string `xml:"RecordDelimiter"`
+	FieldDelimiter       string `xml:"FieldDelimiter"`
+	QuoteCharacter       string `xml:"QuoteCharacter"`
+	QuoteEscapeCharacter string `xml:"QuoteEscapeCharacter"`
+}
+
+type SelectJSONOutput struct {
+	RecordDelimiter string `xml:"RecordDelimiter"`
+}
+
+// selectInputFormat reads records of one input serialization format
+type selectInputFormat struct {
+	newReader func(input *SelectInputSerialization, r io.Reader) (sql.RecordReader, error)
+	// event stream error code when the data can not be parsed
+	parsingErrorCode string
+}
+
+// selectInputFormats is keyed by the element name under InputSerialization
+var selectInputFormats = map[string]selectInputFormat{
+	"CSV": {
+		newReader: func(input *SelectInputSerialization, r io.Reader) (sql.RecordReader, error) {
+			return csv.NewReader(r, csv.ReaderOptions{
+				FileHeaderInfo:  input.CSV.FileHeaderInfo,
+				RecordDelimiter: input.CSV.RecordDelimiter,
+				FieldDelimiter:  input.CSV.FieldDelimiter,
+				QuoteCharacter:  input.CSV.QuoteCharacter,
+				Comments:        input.CSV.Comments,
+			})
+		},
+		parsingErrorCode: "CSVParsingError",
+	},
+	"JSON": {
+		newReader: func(input *SelectInputSerialization, r io.Reader) (sql.RecordReader, error) {
+			return json.NewReader(r, input.JSON.Type)
+		},
+		parsingErrorCode: "JSONParsingError",
+	},
+}
+
+func (input *SelectInputSerialization) formatName() (name string, code ErrorCode) {
+	var names []string
+	if input.CSV != nil {
+		names = append(names, "CSV")
+	}
+	if input.JSON != nil {
+		names = append(names, "JSON")
+	}
+	if input.Parquet != nil {
+		names = append(names, "Parquet")
+	}
+	if len(names) != 1 {
+		return "", ErrInvalidRequestParameter
+	}
+	return names[0], ErrNone
+}
+
+// selectRecordWriter serializes the selected rows
+type selectRecordWriter interface {
+	Write(buf *bytes.Buffer, names []string, values []sqltypes.Value)
+}
+
+func (output *SelectOutputSerialization) recordWriter() (selectRecordWriter, ErrorCode) {
+	switch {
+	case output.CSV != nil && output.JSON == nil:
+		writer, err := csv.NewWriter(csv.WriterOptions{
+			QuoteFields:          output.CSV.QuoteFields,
+			RecordDelimiter:      output.CSV.RecordDelimiter,
+			FieldDelimiter:       output.CSV.FieldDelimiter,
+			QuoteCharacter:       output.CSV.QuoteCharacter,
+			QuoteEscapeCharacter: output.CSV.QuoteEscapeCharacter,
+		})
+		if err != nil {
+			glog.V(1).Infof("select output serialization: %v", err)
+			return nil, ErrInvalidRequestParameter
+		}
+		return writer, ErrNone
+	case output.JSON != nil && output.CSV == nil:
+		return json.NewWriter(output.JSON.RecordDelimiter), ErrNone
+	}
+	return nil, ErrInvalidRequestParameter
+}
+
+// SelectObjectContentHandler - POST object ?select&select-type=2
+func (s3a *S3ApiServer) SelectObjectContentHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_SelectObjectContent.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	request := &SelectObjectContentRequest{}
+	if err := decodeXMLBody(r, request, maxSelectRequestSize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	if !strings.EqualFold(request.ExpressionType, "SQL") {
+		writeErrorResponse(w, ErrInvalidExpressionType, r.URL)
+		return
+	}
+	if request.ScanRange != nil {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	query, err := sql.Parse(request.Expression)
+	if err != nil {
+		glog.V(1).Infof("select %s%s: %v", bucket, object, err)
+		writeErrorResponse(w, ErrUnsupportedSyntax, r.URL)
+		return
+	}
+
+	formatName, errCode := request.InputSerialization.formatName()
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	format, found := selectInputFormats[formatName]
+	if !found {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+	recordWriter, errCode := request.OutputSerialization.recordWriter()
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	key, errCode := parseRequestCustomerKey(r)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	destUrl, errCode := s3a.objectUrl(w, r, bucket, object)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	resp, errCode := s3a.getFromFiler(r, destUrl, "")
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+	defer util.CloseResponse(resp)
+
+	body, errCode := s3a.decryptResponse(resp, key)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	scanned := &countingReader{reader: body}
+	var data io.Reader = scanned
+	switch strings.ToUpper(request.InputSerialization.CompressionType) {
+	case "", "NONE":
+	case "GZIP":
+		if data, err = gzip.NewReader(scanned); err != nil {
+			writeErrorResponse(w, ErrInvalidCompressionFormat, r.URL)
+			return
+		}
+	case "BZIP2":
+		data = bzip2.NewReader(scanned)
+	default:
+		writeErrorResponse(w, ErrInvalidCompressionFormat, r.URL)
+		return
+	}
+	processed := &countingReader{reader: data}
+
+	recordReader, err := format.newReader(&request.InputSerialization, processed)
+	if err != nil {
+		glog.V(1).Infof("select %s%s input serialization: %v", bucket, object, err)
+		writeErrorResponse(w, ErrInvalidRequestParameter, r.URL)
+		return
+	}
+	records := &selectRecordReader{reader: recordReader}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	events := newSelectEventWriter(w)
+
+	var buf bytes.Buffer
+	var returned int64
+	sendRecords := func() error {
+		if buf.Len() == 0 {
+			return nil
+		}
+		returned += int64(buf.Len())
+		if err := events.writeRecords(buf.Bytes()); err != nil {
+			return err
+		}
+		buf.Reset()
+		if request.RequestProgress.Enabled {
+			return events.writeProgress("Progress", scanned.count, processed.count, returned)
+		}
+		return nil
+	}
+
+	err = query.Execute(records, func(names []string, values []sqltypes.Value) error {
+		recordWriter.Write(&buf, names, values)
+		if buf.Len() >= selectRecordsEventSize {
+			return sendRecords()
+		}
+		return nil
+	})
+	if err == nil {
+		err = sendRecords()
+	}
+	if err != nil {
+		if events.err != nil {
+			glog.V(1).Infof("select %s%s response: %v", bucket, object, events.err)
+			return
+		}
+		errorCode := "EvaluatorInvalidArguments"
+		if err == records.err {
+			errorCode = format.parsingErrorCode
+		}
+		glog.V(1).Infof("select %s%s: %v", bucket, object, err)
+		events.writeError(errorCode, err.Error())
+		return
+	}
+
+	events.writeProgress("Stats", scanned.count, processed.count, returned)
+	events.writeEnd()
+}
+
+func (s3a *S3ApiServer) getFromFiler(r *http.Request, destUrl string, rangeHeader string) (resp *http.Response, code ErrorCode) {
+
+	proxyReq, err := http.NewRequest("GET", destUrl, nil)
+	if err != nil {
+		glog.Errorf("NewRequest %s: %v", destUrl, err)
+		return nil, ErrInternalError
+	}
+
+	proxyReq.Header.Set("Host", s3a.option.Filer)
+	proxyReq.Header.Set("X-Forwarded-For", r.RemoteAddr)
+	if rangeHeader != "" {
+		proxyReq.Header.Set("Range", rangeHeader)
+	}
+
+	resp, err = client.Do(proxyReq)
+	if err != nil {
+		glog.Errorf("get from filer %s: %v", destUrl, err)
+		return nil, ErrInternalError
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		util.CloseResponse(resp)
+		return nil, ErrNoSuchKey
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		glog.Errorf("get from filer %s: %s", destUrl, resp.Status)
+		util.CloseResponse(resp)
+		return nil, ErrInternalError
+	}
+
+	return resp, ErrNone
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	r.count += int64(n)
+	return
+}
+
+// selectRecordReader keeps the error of reading the input, to tell it apart from evaluation errors
+type selectRecordReader struct {
+	reader sql.RecordReader
+	err    error
+}
+
+func (r *selectRecordReader) Read() (sql.Record, error) {
+	record, err := r.reader.Read()
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return record, err
+}
@@ -0,0 +1,114 @@
+package s3api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// PutObjectTaggingHandler - PUT object ?tagging
+func (s3a *S3ApiServer) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObjectTagging.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	tagging := &Tagging{}
+	if err := decodeXMLBody(r, tagging, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	tags, errCode := tagging.toObjectTags()
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	if errCode := s3a.updateObjectTags(r, bucket, object, tags); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	setVersionHeaders(w, r.URL.Query().Get("versionId"), false)
+	writeSuccessResponseEmpty(w)
+}
+
+// GetObjectTaggingHandler - GET object ?tagging
+func (s3a *S3ApiServer) GetObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	_, entry, errCode := s3a.objectVersionEntry(bucket, object, r.URL.Query().Get("versionId"))
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	tagging := loadObjectTags(entry.Extended).toTagging()
+	tagging.Xmlns = s3Namespace
+	setVersionHeaders(w, r.URL.Query().Get("versionId"), false)
+	writeSuccessResponseXML(w, encodeResponse(tagging))
+}
+
+// DeleteObjectTaggingHandler - DELETE object ?tagging
+func (s3a *S3ApiServer) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := getObject(vars)
+
+	if errCode := s3a.updateObjectTags(r, bucket, object, objectTags{}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeResponse(w, http.StatusNoContent, nil, mimeNone)
+}
+
+func (s3a *S3ApiServer) updateObjectTags(r *http.Request, bucket, object string, tags objectTags) ErrorCode {
+
+	dir, entry, errCode := s3a.objectVersionEntry(bucket, object, r.URL.Query().Get("versionId"))
+	if errCode != ErrNone {
+		return errCode
+	}
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	tags.saveTo(entry.Extended)
+	// the replica of the current version gets the new tags
+	if currentDir, _ := s3a.objectDirAndName(bucket, object); dir == currentDir {
+		s3a.setReplicationPending(bucket, object, entry.Extended)
+	}
+
+	if err := s3a.updateEntry(dir, entry); err != nil {
+		glog.Errorf("update object tags %s%s: %v", bucket, object, err)
+		return ErrInternalError
+	}
+
+	return ErrNone
+}
+
+// getObjectTags loads the tags for the s3:ExistingObjectTag policy conditions
+func (s3a *S3ApiServer) getObjectTags(bucket, object, versionId string) (objectTags, error) {
+
+	object = "/" + strings.TrimPrefix(object, "/")
+	var entry *filer_pb.Entry
+	var err error
+	if versionId != "" {
+		_, entry, err = s3a.findVersion(bucket, object, versionId)
+	} else {
+		_, _, entry, err = s3a.objectEntry(bucket, object)
+	}
+	if err != nil || entry == nil || entry.IsDirectory {
+		return nil, err
+	}
+	return loadObjectTags(entry.Extended), nil
+}
@@ -0,0 +1,282 @@
+package s3api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// PutBucketVersioningHandler - PUT bucket ?versioning
+func (s3a *S3ApiServer) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketVersioning.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	config := &VersioningConfiguration{}
+	if err := decodeXMLBody(r, config, maxXMLBodySize); err != nil {
+		writeXMLBodyError(w, r, err)
+		return
+	}
+	status := string(config.Status)
+	if status != VersioningEnabled && status != VersioningSuspended {
+		writeErrorResponse(w, ErrIllegalVersioningConfiguration, r.URL)
+		return
+	}
+
+	if errCode := s3a.updateBucketExtended(bucket, func(extended map[string][]byte) {
+		extended[bucketVersioningKey] = []byte(status)
+	}); errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	writeSuccessResponseEmpty(w)
+}
+
+// GetBucketVersioningHandler - GET bucket ?versioning
+func (s3a *S3ApiServer) GetBucketVersioningHandler(w http.ResponseWriter, r *http.Request) {
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	status, errCode := s3a.getBucketVersioning(bucket)
+	if errCode != ErrNone {
+		writeErrorResponse(w, errCode, r.URL)
+		return
+	}
+
+	response := VersioningConfiguration{
+		Xmlns:  s3Namespace,
+		Status: VersioningStatus(status),
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// ListObjectVersionsHandler - GET bucket ?versions
+func (s3a *S3ApiServer) ListObjectVersionsHandler(w http.ResponseWriter, r *http.Request) {
+
+	// https://docs.aws.amazon.com/AmazonS3/latest/API/API_ListObjectVersions.html
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	originalPrefix, keyMarker, versionIdMarker, delimiter, maxKeys := getListObjectVersionsArgs(r.URL.Query())
+
+	if maxKeys < 0 {
+		writeErrorResponse(w, ErrInvalidMaxKeys, r.URL)
+		return
+	}
+	if delimiter != "" && delimiter != "/" {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	response, err := s3a.listObjectVersions(bucket, originalPrefix, keyMarker, versionIdMarker, maxKeys)
+	if err != nil {
+		glog.Errorf("ListObjectVersions %s: %v", bucket, err)
+		writeErrorResponse(w, ErrInternalError, r.URL)
+		return
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// listedName is one name found in the object directory or in its versions folder
+type listedName struct {
+	current     *filer_pb.Entry
+	isDirectory bool
+	hasVersions bool
+}
+
+func (s3a *S3ApiServer) listObjectVersions(bucket, originalPrefix, keyMarker, versionIdMarker string, maxKeys int) (response ListVersionsResult, err error) {
+
+	// convert full path prefix into directory name and prefix for entry name
+	dir, prefix := filepath.Split(originalPrefix)
+	if strings.HasPrefix(dir, "/") {
+		dir = dir[1:]
+	}
+
+	// resume from the key marker, and from the version id marker within that key
+	startFrom, inclusive := "", false
+	if strings.HasPrefix(keyMarker, dir) {
+		startFrom = keyMarker[len(dir):]
+		if i := strings.Index(startFrom, "/"); i >= 0 {
+			startFrom = startFrom[:i]
+		} else {
+			inclusive = versionIdMarker != ""
+		}
+	}
+
+	limit := uint32(maxKeys + 1)
+	names := make(map[string]*listedName)
+	var lastNames []string
+
+	for i, folder := range []string{
+		fmt.Sprintf("%s/%s/%s", s3a.option.BucketsPath, bucket, dir),
+		fmt.Sprintf("%s/%s/%s/%s", s3a.option.BucketsPath, bucket, versionsFolder, dir),
+	} {
+		entries, listErr := s3a.list(folder, prefix, startFrom, inclusive, limit)
+		if listErr != nil {
+			return response, listErr
+		}
+		if len(entries) == int(limit) {
+			lastNames = append(lastNames, entries[len(entries)-1].Name)
+		}
+		for _, entry := range entries {
+			if dir == "" && (entry.Name == ".uploads" || entry.Name == versionsFolder) {
+				continue
+			}
+			name, found := names[entry.Name]
+			if !found {
+				name = &listedName{}
+				names[entry.Name] = name
+			}
+			if i == 1 {
+				name.hasVersions = entry.IsDirectory
+			} else if entry.IsDirectory {
+				name.isDirectory = true
+			} else {
+				name.current = entry
+			}
+		}
+	}
+
+	// when a listing is cut by the limit, names after its last name may be missing from the other listing
+	var cutOff string
+	for _, lastName := range lastNames {
+		if cutOff == "" || lastName < cutOff {
+			cutOff = lastName
+		}
+	}
+
+	var sortedNames []string
+	for name := range names {
+		if cutOff == "" || name <= cutOff {
+			sortedNames = append(sortedNames, name)
+		}
+	}
+	sort.Strings(sortedNames)
+
+	response = ListVersionsResult{
+		Name:            bucket,
+		Prefix:          originalPrefix,
+		KeyMarker:       keyMarker,
+		VersionIdMarker: versionIdMarker,
+		MaxKeys:         maxKeys,
+		Delimiter:       "/",
+	}
+
+	var counter int
+	var nextKeyMarker, nextVersionIdMarker string
+
+	isFull := func() bool {
+		if counter >= maxKeys {
+			response.IsTruncated = true
+			return true
+		}
+		counter++
+		return false
+	}
+
+LIST:
+	for _, name := range sortedNames {
+		listed := names[name]
+		key := dir + name
+
+		var versions []*filer_pb.Entry
+		if listed.current != nil {
+			versions = append(versions, listed.current)
+		}
+		if listed.hasVersions {
+			archived, listErr := s3a.listVersions(bucket, "/"+key)
+			if listErr != nil {
+				return response, listErr
+			}
+			versions = append(versions, archived...)
+		}
+
+		skipping := key == keyMarker && versionIdMarker != ""
+		for i, entry := range versions {
+			versionId := getVersionId(entry)
+			if skipping {
+				skipping = versionId != versionIdMarker
+				continue
+			}
+			if isFull() {
+				break LIST
+			}
+			nextKeyMarker, nextVersionIdMarker = key, versionId
+			owner := CanonicalUser{
+				ID:          fmt.Sprintf("%x", entry.Attributes.Uid),
+				DisplayName: entry.Attributes.UserName,
+			}
+			if isDeleteMarker(entry) {
+				response.DeleteMarker = append(response.DeleteMarker, DeleteMarkerEntry{
+					Key:          key,
+					VersionId:    versionId,
+					IsLatest:     i == 0,
+					LastModified: time.Unix(entry.Attributes.Mtime, 0),
+					Owner:        owner,
+				})
+			} else {
+				response.Version = append(response.Version, VersionEntry{
+					Key:          key,
+					VersionId:    versionId,
+					IsLatest:     i == 0,
+					LastModified: time.Unix(entry.Attributes.Mtime, 0),
+					ETag:         "\"" + filer2.ETag(entry) + "\"",
+					Size:         int64(filer2.FileSize(entry)),
+					Owner:        owner,
+					StorageClass: StorageClass(getStorageClass(entry.Extended)),
+				})
+			}
+		}
+
+		if listed.isDirectory {
+			if isFull() {
+				break LIST
+			}
+			nextKeyMarker, nextVersionIdMarker = key+"/", ""
+			response.CommonPrefixes = append(response.CommonPrefixes, PrefixEntry{
+				Prefix: key + "/",
+			})
+		}
+	}
+
+	if !response.IsTruncated && cutOff != "" {
+		response.IsTruncated = true
+		nextKeyMarker, nextVersionIdMarker = dir+cutOff, ""
+	}
+	if response.IsTruncated {
+		response.NextKeyMarker, response.NextVersionIdMarker = nextKeyMarker, nextVersionIdMarker
+	}
+
+	return response, nil
+}
+
+func getListObjectVersionsArgs(values url.Values) (prefix, keyMarker, versionIdMarker, delimiter string, maxkeys int) {
+	prefix = values.Get("prefix")
+	keyMarker = values.Get("key-marker")
+	versionIdMarker = values.Get("version-id-marker")
+	delimiter = values.Get("delimiter")
+	if values.Get("max-keys") != "" {
+		maxkeys, _ = strconv.Atoi(values.Get("max-keys"))
+	} else {
+		maxkeys = maxObjectListSizeLimit
+	}
+	return
+}
@@ -1,9 +1,9 @@
 package s3api
 
 import (
-	"context"
+	"encoding/base64"
+	"encoding/xml"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -18,6 +18,26 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
 )
 
+const (
+	// number of entries read from the filer at a time
+	listObjectsBatchSize = 1024
+)
+
+type ListBucketResultV2 struct {
+	XMLName               xml.Name      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string        `xml:"Name"`
+	Prefix                string        `xml:"Prefix"`
+	MaxKeys               int           `xml:"MaxKeys"`
+	Delimiter             string        `xml:"Delimiter,omitempty"`
+	IsTruncated           bool          `xml:"IsTruncated"`
+	Contents              []ListEntry   `xml:"Contents,omitempty"`
+	CommonPrefixes        []PrefixEntry `xml:"CommonPrefixes,omitempty"`
+	ContinuationToken     string        `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string        `xml:"NextContinuationToken,omitempty"`
+	KeyCount              int           `xml:"KeyCount"`
+	StartAfter            string        `xml:"StartAfter,omitempty"`
+}
+
 func (s3a *S3ApiServer) ListObjectsV2Handler(w http.ResponseWriter, r *http.Request) {
 
 	// https://docs.aws.amazon.com/AmazonS3/latest/API/v2-RESTBucketGET.html
@@ -28,7 +48,7 @@ func (s3a *S3ApiServer) ListObjectsV2Handler(w http.ResponseWriter, r *http.Requ
 
 	glog.V(4).Infof("read v2: %v", vars)
 
-	originalPrefix, marker, startAfter, delimiter, _, maxKeys := getListObjectsV2Args(r.URL.Query())
+	originalPrefix, continuationToken, startAfter, delimiter, fetchOwner, maxKeys := getListObjectsV2Args(r.URL.Query())
 
 	if maxKeys < 0 {
 		writeErrorResponse(w, ErrInvalidMaxKeys, r.URL)
@@ -39,18 +59,41 @@ func (s3a *S3ApiServer) ListObjectsV2Handler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if marker == "" {
-		marker = startAfter
+	// the continuation token takes precedence over start-after
+	marker := startAfter
+	if continuationToken != "" {
+		var ok bool
+		if marker, ok = decodeContinuationToken(continuationToken); !ok {
+			writeErrorResponse(w, ErrInvalidContinuationToken, r.URL)
+			return
+		}
 	}
 
-	response, err := s3a.listFilerEntries(bucket, originalPrefix, maxKeys, marker)
+	response, err := s3a.listFilerEntries(bucket, originalPrefix, maxKeys, marker, delimiter, fetchOwner)
 
 	if err != nil {
+		glog.Errorf("list bucket %s: %v", bucket, err)
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
 
-	writeSuccessResponseXML(w, encodeResponse(response))
+	responseV2 := &ListBucketResultV2{
+		Name:              response.Name,
+		Prefix:            response.Prefix,
+		MaxKeys:           response.MaxKeys,
+		Delimiter:         response.Delimiter,
+		IsTruncated:       response.IsTruncated,
+		Contents:          response.Contents,
+		CommonPrefixes:    response.CommonPrefixes,
+		ContinuationToken: continuationToken,
+		KeyCount:          len(response.Contents) + len(response.CommonPrefixes),
+		StartAfter:        startAfter,
+	}
+	if response.IsTruncated {
+		responseV2.NextContinuationToken = encodeContinuationToken(response.NextMarker)
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(responseV2))
 }
 
 func (s3a *S3ApiServer) ListObjectsV1Handler(w http.ResponseWriter, r *http.Request) {
@@ -72,9 +115,10 @@ func (s3a *S3ApiServer) ListObjectsV1Handler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	response, err := s3a.listFilerEntries(bucket, originalPrefix, maxKeys, marker)
+	response, err := s3a.listFilerEntries(bucket, originalPrefix, maxKeys, marker, delimiter, true)
 
 	if err != nil {
+		glog.Errorf("list bucket %s: %v", bucket, err)
 		writeErrorResponse(w, ErrInternalError, r.URL)
 		return
 	}
@@ -82,7 +126,95 @@ func (s3a *S3ApiServer) ListObjectsV1Handler(w http.ResponseWriter, r *http.Requ
 	writeSuccessResponseXML(w, encodeResponse(response))
 }
 
-func (s3a *S3ApiServer) listFilerEntries(bucket, originalPrefix string, maxKeys int, marker string) (response ListBucketResult, err error) {
+// the continuation token is the last returned key, opaque to the clients
+func encodeContinuationToken(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func decodeContinuationToken(token string) (string, bool) {
+	key, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+func (s3a *S3ApiServer) listFilerEntries(bucket, originalPrefix string, maxKeys int, marker, delimiter string, fetchOwner bool) (response ListBucketResult, err error) {
+
+	list := func(dir, prefix, startFrom string, inclusive bool, limit uint32) ([]*filer_pb.Entry, error) {
+		return s3a.list(fmt.Sprintf("%s/%s/%s", s3a.option.BucketsPath, bucket, dir), prefix, startFrom, inclusive, limit)
+	}
+
+	response, err = listBucketResult(list, bucket, originalPrefix, maxKeys, marker, delimiter, fetchOwner)
+
+	glog.V(4).Infof("list bucket %s prefix %s marker %s: %+v", bucket, originalPrefix, marker, response)
+
+	return
+}
+
+// listBucketResult lists up to max keys and common prefixes, the next marker is set if truncated
+func listBucketResult(list listEntriesFunc, bucket, originalPrefix string, maxKeys int, marker, delimiter string, fetchOwner bool) (response ListBucketResult, err error) {
+
+	response = ListBucketResult{
+		Name:      bucket,
+		Prefix:    originalPrefix,
+		Marker:    marker,
+		MaxKeys:   maxKeys,
+		Delimiter: delimiter,
+	}
+
+	// no entries are read for max-keys=0
+	if maxKeys == 0 {
+		return response, nil
+	}
+
+	var counter int
+	var lastKey string
+
+	err = listObjects(list, originalPrefix, marker, delimiter != "", func(key string, entry *filer_pb.Entry) bool {
+		// the common prefixes are counted the same as the keys
+		if counter >= maxKeys {
+			response.IsTruncated = true
+			return false
+		}
+		counter++
+		lastKey = key
+		if entry == nil {
+			response.CommonPrefixes = append(response.CommonPrefixes, PrefixEntry{
+				Prefix: key,
+			})
+			return true
+		}
+		listEntry := ListEntry{
+			Key:          key,
+			LastModified: time.Unix(entry.Attributes.Mtime, 0),
+			ETag:         "\"" + filer2.ETag(entry) + "\"",
+			Size:         int64(filer2.FileSize(entry)),
+			StorageClass: StorageClass(getStorageClass(entry.Extended)),
+		}
+		if fetchOwner {
+			listEntry.Owner = &CanonicalUser{
+				ID:          fmt.Sprintf("%x", entry.Attributes.Uid),
+				DisplayName: entry.Attributes.UserName,
+			}
+		}
+		response.Contents = append(response.Contents, listEntry)
+		return true
+	})
+
+	if response.IsTruncated {
+		response.NextMarker = lastKey
+	}
+
+	return
+}
+
+// listEntriesFunc lists one directory relative to the bucket
+type listEntriesFunc func(dir, prefix, startFrom string, inclusive bool, limit uint32) ([]*filer_pb.Entry, error)
+
+// listObjects visits the objects with the prefix, after the marker, until fn returns false.
+// With the delimiter, the sub directories are visited once as the common prefixes, with the entry as nil.
+func listObjects(list listEntriesFunc, originalPrefix, marker string, hasDelimiter bool, fn func(key string, entry *filer_pb.Entry) bool) error {
 
 	// convert full path prefix into directory name and prefix for entry name
 	dir, prefix := filepath.Split(originalPrefix)
@@ -90,85 +222,74 @@ func (s3a *S3ApiServer) listFilerEntries(bucket, originalPrefix string, maxKeys
 		dir = dir[1:]
 	}
 
-	// check filer
-	err = s3a.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+	// the keys in the directory are either all before or all after a marker outside of it
+	var relativeMarker string
+	if strings.HasPrefix(marker, dir) {
+		relativeMarker = marker[len(dir):]
+	} else if marker > dir {
+		return nil
+	}
 
-		request := &filer_pb.ListEntriesRequest{
-			Directory:          fmt.Sprintf("%s/%s/%s", s3a.option.BucketsPath, bucket, dir),
-			Prefix:             prefix,
-			Limit:              uint32(maxKeys + 1),
-			StartFromFileName:  marker,
-			InclusiveStartFrom: false,
-		}
+	_, err := walkObjects(list, dir, prefix, relativeMarker, !hasDelimiter, fn)
+	return err
+}
 
-		stream, err := client.ListEntries(context.Background(), request)
+// walkObjects visits the directory depth first, in the order of the names, after the marker relative to the directory.
+// It returns false if the walk is stopped by fn.
+func walkObjects(list listEntriesFunc, dir, prefix, marker string, recursive bool, fn func(key string, entry *filer_pb.Entry) bool) (bool, error) {
+
+	// the marker may end within a sub directory, which is still walked for the keys after the marker
+	markerName, markerRest := marker, ""
+	markerInDirectory := false
+	if i := strings.Index(marker, "/"); i >= 0 {
+		markerName, markerRest, markerInDirectory = marker[:i], marker[i+1:], true
+	}
+
+	startFrom, inclusive := markerName, markerName != ""
+	for {
+		entries, err := list(dir, prefix, startFrom, inclusive, listObjectsBatchSize)
 		if err != nil {
-			return fmt.Errorf("list buckets: %v", err)
+			return false, err
 		}
-
-		var contents []ListEntry
-		var commonPrefixes []PrefixEntry
-		var counter int
-		var lastEntryName string
-		var isTruncated bool
-
-		for {
-			resp, recvErr := stream.Recv()
-			if recvErr != nil {
-				if recvErr == io.EOF {
-					break
-				} else {
-					return recvErr
-				}
+		for _, entry := range entries {
+			if dir == "" && entry.IsDirectory && (entry.Name == ".uploads" || entry.Name == versionsFolder) {
+				continue
 			}
-
-			entry := resp.Entry
-			counter++
-			if counter > maxKeys {
-				isTruncated = true
-				break
+			key := dir + entry.Name
+			atMarker := inclusive && entry.Name == markerName
+			if !entry.IsDirectory {
+				// the key is the marker, or before the keys in the marker directory
+				if atMarker || isDeleteMarker(entry) {
+					continue
+				}
+				if !fn(key, entry) {
+					return false, nil
+				}
+				continue
 			}
-			lastEntryName = entry.Name
-			if entry.IsDirectory {
-				if entry.Name != ".uploads" {
-					commonPrefixes = append(commonPrefixes, PrefixEntry{
-						Prefix: fmt.Sprintf("%s%s/", dir, entry.Name),
-					})
+			if !recursive {
+				// the common prefix is at or before a marker within it
+				if atMarker && markerInDirectory {
+					continue
 				}
-			} else {
-				contents = append(contents, ListEntry{
-					Key:          fmt.Sprintf("%s%s", dir, entry.Name),
-					LastModified: time.Unix(entry.Attributes.Mtime, 0),
-					ETag:         "\"" + filer2.ETag(entry) + "\"",
-					Size:         int64(filer2.TotalSize(entry.Chunks)),
-					Owner: CanonicalUser{
-						ID:          fmt.Sprintf("%x", entry.Attributes.Uid),
-						DisplayName: entry.Attributes.UserName,
-					},
-					StorageClass: "STANDARD",
-				})
+				if !fn(key+"/", nil) {
+					return false, nil
+				}
+				continue
+			}
+			childMarker := ""
+			if atMarker {
+				childMarker = markerRest
+			}
+			if more, err := walkObjects(list, key+"/", "", childMarker, recursive, fn); err != nil || !more {
+				return more, err
 			}
-
 		}
-
-		response = ListBucketResult{
-			Name:           bucket,
-			Prefix:         originalPrefix,
-			Marker:         marker,
-			NextMarker:     lastEntryName,
-			MaxKeys:        maxKeys,
-			Delimiter:      "/",
-			IsTruncated:    isTruncated,
-			Contents:       contents,
-			CommonPrefixes: commonPrefixes,
+		if len(entries) < listObjectsBatchSize {
+			return true, nil
 		}
-
-		glog.V(4).Infof("read directory: %v, found: %v, %+v", request, counter, response)
-
-		return nil
-	})
-
-	return
+		startFrom, inclusive = entries[len(entries)-1].Name, false
+	}
 }
 
 func getListObjectsV2Args(values url.Values) (prefix, token, startAfter, delimiter string, fetchOwner bool, maxkeys int) {
@@ -1,8 +1,13 @@
 package s3api
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
 )
 
 func TestListObjectsHandler(t *testing.T) {
@@ -24,7 +29,7 @@ func TestListObjectsHandler(t *testing.T) {
 			LastModified: time.Date(2011, 4, 9, 12, 34, 49, 0, time.UTC),
 			ETag:         "\"4397da7a7649e8085de9916c240e8166\"",
 			Size:         1234567,
-			Owner: CanonicalUser{
+			Owner: &CanonicalUser{
 				ID: "65a011niqo39cdf8ec533ec3d1ccaafsa932",
 			},
 			StorageClass: "STANDARD",
@@ -36,3 +41,166 @@ func TestListObjectsHandler(t *testing.T) {
 		t.Errorf("unexpected output: %s\nexpecting:%s", encoded, expected)
 	}
 }
+
+// listTestBucket lists the directories of the keys, like the filer does
+func listTestBucket(keys []string) listEntriesFunc {
+	return func(dir, prefix, startFrom string, inclusive bool, limit uint32) (entries []*filer_pb.Entry, err error) {
+		children := make(map[string]bool)
+		for _, key := range keys {
+			if !strings.HasPrefix(key, dir) {
+				continue
+			}
+			name := key[len(dir):]
+			isDirectory := false
+			if i := strings.Index(name, "/"); i >= 0 {
+				name, isDirectory = name[:i], true
+			}
+			children[name] = isDirectory
+		}
+		var names []string
+		for name := range children {
+			if strings.HasPrefix(name, prefix) && (name > startFrom || inclusive && name == startFrom) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if len(entries) >= int(limit) {
+				break
+			}
+			entries = append(entries, &filer_pb.Entry{Name: name, IsDirectory: children[name], Attributes: &filer_pb.FuseAttributes{}})
+		}
+		return
+	}
+}
+
+func TestListBucketResultPagination(t *testing.T) {
+
+	var keys []string
+	for i := 0; i < 3000; i++ {
+		keys = append(keys, fmt.Sprintf("photos/%02d/%02d/%04d.jpg", i%7, i%13, i))
+	}
+	for i := 0; i < 1500; i++ {
+		keys = append(keys, fmt.Sprintf("top%04d", i))
+	}
+	keys = append(keys, ".uploads/x/1.part", versionsFolder+"/top0001/v1")
+	list := listTestBucket(keys)
+
+	tests := []struct {
+		prefix    string
+		delimiter string
+		maxKeys   int
+		expected  int
+	}{
+		{"", "", 1000, 4500},
+		{"", "", 7, 4500},
+		{"photos/", "", 333, 3000},
+		{"photos/0", "", 100, 3000},
+		{"photos/04", "", 100, 428},
+		{"photos/3/", "", 50, 0},
+		{"photos/3/1", "", 50, 0},
+		{"photos/03/1", "", 50, 99},
+		{"", "/", 100, 1501},
+		{"photos/", "/", 3, 7},
+		{"photos/05/", "/", 5, 13},
+		{"top1", "/", 64, 500},
+	}
+	for _, test := range tests {
+		var listed []string
+		var marker string
+		for pages := 0; ; pages++ {
+			if pages > 10000 {
+				t.Fatalf("%s %q: too many pages", test.prefix, test.delimiter)
+			}
+			response, err := listBucketResult(list, "bucket", test.prefix, test.maxKeys, marker, test.delimiter, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			count := len(response.Contents) + len(response.CommonPrefixes)
+			if count > test.maxKeys || (response.IsTruncated && count != test.maxKeys) {
+				t.Fatalf("%s %q: %d keys for max keys %d", test.prefix, test.delimiter, count, test.maxKeys)
+			}
+			for _, content := range response.Contents {
+				if content.Owner != nil {
+					t.Fatalf("%s: owner without fetch-owner", content.Key)
+				}
+				listed = append(listed, content.Key)
+			}
+			for _, commonPrefix := range response.CommonPrefixes {
+				listed = append(listed, commonPrefix.Prefix)
+			}
+			if !response.IsTruncated {
+				break
+			}
+			// resume from the continuation token
+			var ok bool
+			if marker, ok = decodeContinuationToken(encodeContinuationToken(response.NextMarker)); !ok {
+				t.Fatalf("decode token of %s", response.NextMarker)
+			}
+		}
+		if len(listed) != test.expected {
+			t.Errorf("%s %q %d: listed %d, expecting %d", test.prefix, test.delimiter, test.maxKeys, len(listed), test.expected)
+		}
+		seen := make(map[string]bool)
+		for _, key := range listed {
+			if seen[key] {
+				t.Errorf("%s %q %d: %s listed twice", test.prefix, test.delimiter, test.maxKeys, key)
+			}
+			seen[key] = true
+			if !strings.HasPrefix(key, test.prefix) || strings.HasPrefix(key, ".uploads") || strings.HasPrefix(key, versionsFolder) {
+				t.Errorf("%s %q: unexpected %s", test.prefix, test.delimiter, key)
+			}
+		}
+	}
+}
+
+func TestListBucketResultStartAfter(t *testing.T) {
+
+	list := listTestBucket([]string{"a", "b/1", "b/2", "b/c/1", "c", "d/1"})
+
+	tests := []struct {
+		marker    string
+		delimiter string
+		expected  string
+	}{
+		{"", "", "a b/1 b/2 b/c/1 c d/1"},
+		{"a", "", "b/1 b/2 b/c/1 c d/1"},
+		{"b", "", "b/1 b/2 b/c/1 c d/1"},
+		{"b/1", "", "b/2 b/c/1 c d/1"},
+		{"b/b", "", "b/c/1 c d/1"},
+		{"b/c/1", "", "c d/1"},
+		{"bb", "", "c d/1"},
+		{"d/1", "", ""},
+		{"", "/", "a b/ c d/"},
+		{"b", "/", "b/ c d/"},
+		{"b/", "/", "c d/"},
+		{"b/1", "/", "c d/"},
+	}
+	for _, test := range tests {
+		response, err := listBucketResult(list, "bucket", "", 1000, test.marker, test.delimiter, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var listed []string
+		for _, content := range response.Contents {
+			listed = append(listed, content.Key)
+		}
+		for _, commonPrefix := range response.CommonPrefixes {
+			listed = append(listed, commonPrefix.Prefix)
+		}
+		sort.Strings(listed)
+		if strings.Join(listed, " ") != test.expected {
+			t.Errorf("after %s %q: %v, expecting %s", test.marker, test.delimiter, listed, test.expected)
+		}
+	}
+}
+
+func TestListBucketResultMaxKeysZero(t *testing.T) {
+	response, err := listBucketResult(listTestBucket([]string{"a"}), "bucket", "", 0, "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.IsTruncated || len(response.Contents) > 0 {
+		t.Errorf("max keys 0: %+v", response)
+	}
+}
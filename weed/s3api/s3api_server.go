@@ -2,9 +2,14 @@ package s3api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/karlseguin/ccache"
 	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/server"
+	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
 type S3ApiServerOption struct {
@@ -14,35 +19,148 @@ type S3ApiServerOption struct {
 	DomainName       string
 	BucketsPath      string
 	GrpcDialOption   grpc.DialOption
+
+	LifecycleScanInterval     time.Duration
+	LifecycleDeletesPerSecond int
+	MessageBrokers            []string
+	WebsiteDomainName         string
+	ReplicationConfig         string
+	StorageClassConfig        string
+	MultipartUploadTTL        time.Duration
+	MultipartCleanupInterval  time.Duration
+	BatchJobConcurrency       int
+	MaxObjectSize             int64
+	MultipartThreshold        int64
+	AllowRename               bool
+	Region                    string
 }
 
 type S3ApiServer struct {
-	option *S3ApiServerOption
-	iam    *IdentityAccessManagement
+	option              *S3ApiServerOption
+	iam                 *IdentityAccessManagement
+	masterKey           []byte
+	notifier            *eventNotifier
+	policyCache         *ccache.Cache
+	corsCache           *ccache.Cache
+	loggingCache        *ccache.Cache
+	aclCache            *ccache.Cache
+	websiteCache        *ccache.Cache
+	requestPaymentCache *ccache.Cache
+	replicationCache    *ccache.Cache
+	sizeLimitCache      *ccache.Cache
+	accessLogs          chan *accessLogRecord
+	replicator          *bucketReplicator
+	storageClasses      *storageClassConfig
+	health              *weed_server.HealthChecker
+	domains             []string
+	batchJobWakeUp      chan struct{}
 }
 
 func NewS3ApiServer(router *mux.Router, option *S3ApiServerOption) (s3ApiServer *S3ApiServer, err error) {
 	s3ApiServer = &S3ApiServer{
-		option: option,
-		iam:    NewIdentityAccessManagement(option.Config, option.DomainName),
+		option:              option,
+		iam:                 NewIdentityAccessManagement(option.Config, option.DomainName),
+		domains:             parseDomainNames(option.DomainName),
+		policyCache:         ccache.New(ccache.Configure().MaxSize(1000).ItemsToPrune(100)),
+		corsCache:           ccache.New(ccache.Configure().MaxSize(1000).ItemsToPrune(100)),
+		loggingCache:        ccache.New(ccache.Configure().MaxSize(1000).ItemsToPrune(100)),
+		aclCache:            ccache.New(ccache.Configure().MaxSize(1000).ItemsToPrune(100)),
+		websiteCache:        ccache.New(ccache.Configure().MaxSize(1000).ItemsToPrune(100)),
+		requestPaymentCache: ccache.New(ccache.Configure().MaxSize(1000).ItemsToPrune(100)),
+		replicationCache:    ccache.New(ccache.Configure().MaxSize(1000).ItemsToPrune(100)),
+		sizeLimitCache:      ccache.New(ccache.Configure().MaxSize(1000).ItemsToPrune(100)),
+		accessLogs:          make(chan *accessLogRecord, accessLogQueueSize),
+		batchJobWakeUp:      make(chan struct{}, 1),
+	}
+	s3ApiServer.iam.loadBucketPolicy = s3ApiServer.getBucketPolicy
+	s3ApiServer.iam.loadObjectTags = s3ApiServer.getObjectTags
+	s3ApiServer.iam.loadAcl = s3ApiServer.getAcl
+	s3ApiServer.iam.loadRequestPayment = s3ApiServer.getBucketRequestPayment
+	s3ApiServer.iam.stsSigningKey = []byte(util.GetViper().GetString("s3.sts.key"))
+	s3ApiServer.iam.region = option.Region
+
+	if s3ApiServer.masterKey, err = loadMasterKey(util.GetViper().GetString("s3.encryption.key")); err != nil {
+		return nil, err
+	}
+
+	if s3ApiServer.storageClasses, err = loadStorageClassConfig(option.StorageClassConfig); err != nil {
+		return nil, err
 	}
 
+	replicationTargets, err := loadReplicationTargets(option.ReplicationConfig, option.GrpcDialOption)
+	if err != nil {
+		return nil, err
+	}
+
+	s3ApiServer.health = weed_server.NewHealthChecker(weed_server.DefaultHealthCheckTimeout)
+	s3ApiServer.health.AddCheck("filer", s3ApiServer.pingFiler)
+
 	s3ApiServer.registerRouter(router)
 
+	if option.LifecycleScanInterval > 0 {
+		go s3ApiServer.loopLifecycleScan()
+	}
+
+	if option.MultipartCleanupInterval > 0 {
+		go s3ApiServer.loopMultipartUploadCleanup()
+	}
+
+	if option.BatchJobConcurrency > 0 {
+		go s3ApiServer.loopBatchJobs()
+	}
+
+	go s3ApiServer.loopAccessLog()
+
+	if len(option.MessageBrokers) > 0 {
+		s3ApiServer.notifier = newEventNotifier(option.MessageBrokers)
+		go s3ApiServer.loopNotification()
+	}
+
+	if len(replicationTargets) > 0 {
+		s3ApiServer.replicator = newBucketReplicator(replicationTargets)
+		go s3ApiServer.loopReplication()
+	}
+
 	return s3ApiServer, nil
 }
 
 func (s3a *S3ApiServer) registerRouter(router *mux.Router) {
 	// API Router
 	apiRouter := router.PathPrefix("/").Subrouter()
+	apiRouter.Use(s3a.regionHeader)
+
+	// liveness and readiness, before the bucket names, on the paths which are not valid bucket names
+	apiRouter.Methods("GET", "HEAD").Path("/_healthz").MatcherFunc(s3a.matchPathStyle).HandlerFunc(s3a.health.LivenessHandler)
+	apiRouter.Methods("GET", "HEAD").Path("/_readyz").MatcherFunc(s3a.matchPathStyle).HandlerFunc(s3a.health.ReadinessHandler)
+
+	// batch jobs, before the bucket names, only for the path-style requests, so the objects of the virtual-hosted
+	// buckets under the same paths are served as objects
+	// CreateJob
+	apiRouter.Methods("POST").Path("/v20180820/jobs").MatcherFunc(s3a.matchPathStyle).HandlerFunc(s3a.iam.Auth(s3a.CreateJobHandler, ACTION_ADMIN))
+	// ListJobs
+	apiRouter.Methods("GET").Path("/v20180820/jobs").MatcherFunc(s3a.matchPathStyle).HandlerFunc(s3a.iam.Auth(s3a.ListJobsHandler, ACTION_ADMIN))
+	// DescribeJob
+	apiRouter.Methods("GET").Path("/v20180820/jobs/{id}").MatcherFunc(s3a.matchPathStyle).HandlerFunc(s3a.iam.Auth(s3a.DescribeJobHandler, ACTION_ADMIN))
+	// UpdateJobStatus
+	apiRouter.Methods("POST").Path("/v20180820/jobs/{id}/status").MatcherFunc(s3a.matchPathStyle).HandlerFunc(s3a.iam.Auth(s3a.UpdateJobStatusHandler, ACTION_ADMIN)).Queries("requestedJobStatus", "{requestedJobStatus}")
+
 	var routers []*mux.Router
-	if s3a.option.DomainName != "" {
-		routers = append(routers, apiRouter.Host("{bucket:.+}."+s3a.option.DomainName).Subrouter())
+	if len(s3a.domains) > 0 {
+		routers = append(routers, apiRouter.MatcherFunc(s3a.matchVirtualHost).Subrouter())
 	}
 	routers = append(routers, apiRouter.PathPrefix("/{bucket}").Subrouter())
 
 	for _, bucket := range routers {
 
+		bucket.Use(s3a.requestMetrics)
+		bucket.Use(s3a.accessLog)
+		bucket.Use(s3a.auditLog)
+		bucket.Use(s3a.corsHeaders)
+
+		// OPTIONS preflight of the cross-origin requests
+		bucket.Methods("OPTIONS").Path("/{object:.+}").HandlerFunc(s3a.PreflightHandler)
+		bucket.Methods("OPTIONS").HandlerFunc(s3a.PreflightHandler)
+
 		// HeadObject
 		bucket.Methods("HEAD").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.HeadObjectHandler, ACTION_READ))
 		// HeadBucket
@@ -63,6 +181,109 @@ func (s3a *S3ApiServer) registerRouter(router *mux.Router) {
 		// ListMultipartUploads
 		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.ListMultipartUploadsHandler, ACTION_WRITE)).Queries("uploads", "")
 
+		// PutObjectTagging
+		bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.PutObjectTaggingHandler, ACTION_WRITE)).Queries("tagging", "")
+		// GetObjectTagging
+		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.GetObjectTaggingHandler, ACTION_READ)).Queries("tagging", "")
+		// DeleteObjectTagging
+		bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.DeleteObjectTaggingHandler, ACTION_WRITE)).Queries("tagging", "")
+
+		// PutObjectAcl
+		bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.PutObjectAclHandler, ACTION_WRITE)).Queries("acl", "")
+		// GetObjectAcl
+		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.GetObjectAclHandler, ACTION_READ)).Queries("acl", "")
+
+		// PutObjectRetention
+		bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.PutObjectRetentionHandler, ACTION_WRITE)).Queries("retention", "")
+		// GetObjectRetention
+		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.GetObjectRetentionHandler, ACTION_READ)).Queries("retention", "")
+		// PutObjectLegalHold
+		bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.PutObjectLegalHoldHandler, ACTION_WRITE)).Queries("legal-hold", "")
+		// GetObjectLegalHold
+		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.GetObjectLegalHoldHandler, ACTION_READ)).Queries("legal-hold", "")
+		// GetObjectAttributes
+		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.GetObjectAttributesHandler, ACTION_READ)).Queries("attributes", "")
+		// PutObjectLockConfiguration
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutObjectLockConfigurationHandler, ACTION_ADMIN)).Queries("object-lock", "")
+		// GetObjectLockConfiguration
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetObjectLockConfigurationHandler, ACTION_READ)).Queries("object-lock", "")
+
+		// PutBucketVersioning
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketVersioningHandler, ACTION_ADMIN)).Queries("versioning", "")
+		// GetBucketVersioning
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketVersioningHandler, ACTION_READ)).Queries("versioning", "")
+		// GetBucketLocation
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketLocationHandler, ACTION_READ)).Queries("location", "")
+		// ListObjectVersions
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.ListObjectVersionsHandler, ACTION_READ)).Queries("versions", "")
+
+		// SelectObjectContent
+		bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(s3a.iam.Auth(s3a.SelectObjectContentHandler, ACTION_READ)).Queries("select", "", "select-type", "2")
+
+		// PutBucketLifecycleConfiguration
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketLifecycleConfigurationHandler, ACTION_ADMIN)).Queries("lifecycle", "")
+		// GetBucketLifecycleConfiguration
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketLifecycleConfigurationHandler, ACTION_READ)).Queries("lifecycle", "")
+		// DeleteBucketLifecycle
+		bucket.Methods("DELETE").HandlerFunc(s3a.iam.Auth(s3a.DeleteBucketLifecycleHandler, ACTION_ADMIN)).Queries("lifecycle", "")
+
+		// PutBucketEncryption
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketEncryptionHandler, ACTION_ADMIN)).Queries("encryption", "")
+		// GetBucketEncryption
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketEncryptionHandler, ACTION_READ)).Queries("encryption", "")
+		// DeleteBucketEncryption
+		bucket.Methods("DELETE").HandlerFunc(s3a.iam.Auth(s3a.DeleteBucketEncryptionHandler, ACTION_ADMIN)).Queries("encryption", "")
+
+		// PutBucketNotificationConfiguration
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketNotificationHandler, ACTION_ADMIN)).Queries("notification", "")
+		// GetBucketNotificationConfiguration
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketNotificationHandler, ACTION_READ)).Queries("notification", "")
+
+		// PutBucketPolicy
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketPolicyHandler, ACTION_ADMIN)).Queries("policy", "")
+		// GetBucketPolicy
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketPolicyHandler, ACTION_READ)).Queries("policy", "")
+		// DeleteBucketPolicy
+		bucket.Methods("DELETE").HandlerFunc(s3a.iam.Auth(s3a.DeleteBucketPolicyHandler, ACTION_ADMIN)).Queries("policy", "")
+
+		// PutBucketCors
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketCorsHandler, ACTION_ADMIN)).Queries("cors", "")
+		// GetBucketCors
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketCorsHandler, ACTION_READ)).Queries("cors", "")
+		// DeleteBucketCors
+		bucket.Methods("DELETE").HandlerFunc(s3a.iam.Auth(s3a.DeleteBucketCorsHandler, ACTION_ADMIN)).Queries("cors", "")
+
+		// PutBucketAcl
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketAclHandler, ACTION_ADMIN)).Queries("acl", "")
+		// GetBucketAcl
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketAclHandler, ACTION_READ)).Queries("acl", "")
+
+		// PutBucketLogging
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketLoggingHandler, ACTION_ADMIN)).Queries("logging", "")
+		// GetBucketLogging
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketLoggingHandler, ACTION_READ)).Queries("logging", "")
+
+		// PutBucketWebsite
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketWebsiteHandler, ACTION_ADMIN)).Queries("website", "")
+		// GetBucketWebsite
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketWebsiteHandler, ACTION_READ)).Queries("website", "")
+		// DeleteBucketWebsite
+		bucket.Methods("DELETE").HandlerFunc(s3a.iam.Auth(s3a.DeleteBucketWebsiteHandler, ACTION_ADMIN)).Queries("website", "")
+
+		// PutBucketRequestPayment
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketRequestPaymentHandler, ACTION_ADMIN)).Queries("requestPayment", "")
+		// GetBucketRequestPayment
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketRequestPaymentHandler, ACTION_READ)).Queries("requestPayment", "")
+
+		// PutBucketReplication
+		bucket.Methods("PUT").HandlerFunc(s3a.iam.Auth(s3a.PutBucketReplicationHandler, ACTION_ADMIN)).Queries("replication", "")
+		// GetBucketReplication
+		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.GetBucketReplicationHandler, ACTION_READ)).Queries("replication", "")
+		// DeleteBucketReplication
+		bucket.Methods("DELETE").HandlerFunc(s3a.iam.Auth(s3a.DeleteBucketReplicationHandler, ACTION_ADMIN)).Queries("replication", "")
+
+		// RenameObject, a SeaweedFS extension
+		bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").Headers(SeaweedRenameHeader, "true").HandlerFunc(s3a.iam.Auth(s3a.RenameObjectHandler, ACTION_WRITE))
 		// CopyObject
 		bucket.Methods("PUT").Path("/{object:.+}").HeadersRegexp("X-Amz-Copy-Source", ".*?(\\/|%2F).*?").HandlerFunc(s3a.iam.Auth(s3a.CopyObjectHandler, ACTION_WRITE))
 		// PutObject
@@ -82,31 +303,18 @@ func (s3a *S3ApiServer) registerRouter(router *mux.Router) {
 		// ListObjectsV1 (Legacy)
 		bucket.Methods("GET").HandlerFunc(s3a.iam.Auth(s3a.ListObjectsV1Handler, ACTION_READ))
 
+		// PostPolicy, authenticated by the signature of the form
+		bucket.Methods("POST").HeadersRegexp("Content-Type", "multipart/form-data*").HandlerFunc(s3a.PostPolicyBucketHandler)
+
 		// DeleteMultipleObjects
 		bucket.Methods("POST").HandlerFunc(s3a.iam.Auth(s3a.DeleteMultipleObjectsHandler, ACTION_WRITE)).Queries("delete", "")
-		/*
-
-			// not implemented
-			// GetBucketLocation
-			bucket.Methods("GET").HandlerFunc(s3a.GetBucketLocationHandler).Queries("location", "")
-			// GetBucketPolicy
-			bucket.Methods("GET").HandlerFunc(s3a.GetBucketPolicyHandler).Queries("policy", "")
-			// GetObjectACL
-			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(s3a.GetObjectACLHandler).Queries("acl", "")
-			// GetBucketACL
-			bucket.Methods("GET").HandlerFunc(s3a.GetBucketACLHandler).Queries("acl", "")
-			// PutBucketPolicy
-			bucket.Methods("PUT").HandlerFunc(s3a.PutBucketPolicyHandler).Queries("policy", "")
-			// DeleteBucketPolicy
-			bucket.Methods("DELETE").HandlerFunc(s3a.DeleteBucketPolicyHandler).Queries("policy", "")
-			// PostPolicy
-			bucket.Methods("POST").HeadersRegexp("Content-Type", "multipart/form-data*").HandlerFunc(s3a.PostPolicyBucketHandler)
-		*/
 
 	}
 
 	// ListBuckets
-	apiRouter.Methods("GET").Path("/").HandlerFunc(s3a.iam.Auth(s3a.ListBucketsHandler, ACTION_ADMIN))
+	apiRouter.Methods("GET").Path("/").Handler(s3a.requestMetrics(s3a.iam.Authenticate(s3a.ListBucketsHandler)))
+	// AssumeRole, AssumeRoleWithWebIdentity
+	apiRouter.Methods("POST").Path("/").HeadersRegexp("Content-Type", "application/x-www-form-urlencoded").HandlerFunc(s3a.StsHandler)
 
 	// NotFound
 	apiRouter.NotFoundHandler = http.HandlerFunc(notFoundHandler)
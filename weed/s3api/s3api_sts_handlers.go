@@ -0,0 +1,252 @@
+package s3api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// https://docs.aws.amazon.com/STS/latest/APIReference/API_Operations.html
+
+const maxStsFormSize = 64 * 1024
+
+type StsCredentials struct {
+	AccessKeyId     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+	Expiration      string `xml:"Expiration"`
+}
+
+type AssumedRoleUser struct {
+	Arn           string `xml:"Arn"`
+	AssumedRoleId string `xml:"AssumedRoleId"`
+}
+
+type StsResponseMetadata struct {
+	RequestId string `xml:"RequestId"`
+}
+
+type AssumeRoleResponse struct {
+	XMLName          xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleResponse"`
+	AssumeRoleResult struct {
+		Credentials     StsCredentials  `xml:"Credentials"`
+		AssumedRoleUser AssumedRoleUser `xml:"AssumedRoleUser"`
+	} `xml:"AssumeRoleResult"`
+	ResponseMetadata StsResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type AssumeRoleWithWebIdentityResponse struct {
+	XMLName                         xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithWebIdentityResponse"`
+	AssumeRoleWithWebIdentityResult struct {
+		Credentials                 StsCredentials  `xml:"Credentials"`
+		AssumedRoleUser             AssumedRoleUser `xml:"AssumedRoleUser"`
+		SubjectFromWebIdentityToken string          `xml:"SubjectFromWebIdentityToken"`
+		Provider                    string          `xml:"Provider"`
+		Audience                    string          `xml:"Audience,omitempty"`
+	} `xml:"AssumeRoleWithWebIdentityResult"`
+	ResponseMetadata StsResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type StsErrorResponse struct {
+	XMLName xml.Name `xml:"https://sts.amazonaws.com/doc/2011-06-15/ ErrorResponse"`
+	Error   struct {
+		Type    string `xml:"Type"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+	RequestId string `xml:"RequestId"`
+}
+
+// StsHandler - POST / with the form parameter Action=AssumeRole or Action=AssumeRoleWithWebIdentity
+func (s3a *S3ApiServer) StsHandler(w http.ResponseWriter, r *http.Request) {
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxStsFormSize+1))
+	if err != nil {
+		writeStsErrorResponse(w, ErrInternalError)
+		return
+	}
+	if len(body) > maxStsFormSize {
+		writeStsErrorResponse(w, ErrInvalidRequest)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err = r.ParseForm(); err != nil {
+		writeStsErrorResponse(w, ErrInvalidRequest)
+		return
+	}
+
+	if len(s3a.iam.stsSigningKey) == 0 {
+		writeStsErrorResponse(w, ErrNotImplemented)
+		return
+	}
+
+	switch r.Form.Get("Action") {
+	case "AssumeRole":
+		s3a.assumeRole(w, r, body)
+	case "AssumeRoleWithWebIdentity":
+		s3a.assumeRoleWithWebIdentity(w, r)
+	default:
+		writeStsErrorResponse(w, ErrInvalidAction)
+	}
+}
+
+func (s3a *S3ApiServer) assumeRole(w http.ResponseWriter, r *http.Request, body []byte) {
+
+	// the form parameters are signed as the payload
+	if getRequestAuthType(r) != authTypeSigned {
+		writeStsErrorResponse(w, ErrAccessDenied)
+		return
+	}
+	sum := sha256.Sum256(body)
+	hashedPayload := hex.EncodeToString(sum[:])
+	if contentSha256 := r.Header.Get("X-Amz-Content-Sha256"); contentSha256 != "" && contentSha256 != hashedPayload {
+		writeStsErrorResponse(w, ErrContentSHA256Mismatch)
+		return
+	}
+	identity, errCode := s3a.iam.doesSignatureMatch(hashedPayload, r, signingServiceSts)
+	if errCode != ErrNone {
+		writeStsErrorResponse(w, errCode)
+		return
+	}
+
+	role, sessionName, duration, errCode := s3a.iam.sessionParameters(r)
+	if errCode != ErrNone {
+		writeStsErrorResponse(w, errCode)
+		return
+	}
+	if !role.trusts(identity.Name) {
+		glog.V(1).Infof("%s is not allowed to assume role %s", identity.Name, role.Name)
+		writeStsErrorResponse(w, ErrAccessDenied)
+		return
+	}
+
+	credentials, err := s3a.iam.newSessionCredentials(role, sessionName, identity.Name, duration)
+	if err != nil {
+		glog.Errorf("assume role %s: %v", role.Name, err)
+		writeStsErrorResponse(w, ErrInternalError)
+		return
+	}
+
+	response := AssumeRoleResponse{}
+	response.AssumeRoleResult.Credentials = credentials.toXml()
+	response.AssumeRoleResult.AssumedRoleUser = assumedRoleUser(role, sessionName)
+	response.ResponseMetadata.RequestId = newRequestId()
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+func (s3a *S3ApiServer) assumeRoleWithWebIdentity(w http.ResponseWriter, r *http.Request) {
+
+	token := r.Form.Get("WebIdentityToken")
+	if token == "" {
+		writeStsErrorResponse(w, ErrMissingParameter)
+		return
+	}
+
+	role, sessionName, duration, errCode := s3a.iam.sessionParameters(r)
+	if errCode != ErrNone {
+		writeStsErrorResponse(w, errCode)
+		return
+	}
+
+	webIdentity, errCode := s3a.iam.verifyWebIdentityToken(token)
+	if errCode != ErrNone {
+		writeStsErrorResponse(w, errCode)
+		return
+	}
+	if !role.trustsWebIdentity(webIdentity) {
+		glog.V(1).Infof("web identity %s of %s is not allowed to assume role %s", webIdentity.subject, webIdentity.issuer, role.Name)
+		writeStsErrorResponse(w, ErrAccessDenied)
+		return
+	}
+
+	credentials, err := s3a.iam.newSessionCredentials(role, sessionName, webIdentity.subject, duration)
+	if err != nil {
+		glog.Errorf("assume role %s with web identity: %v", role.Name, err)
+		writeStsErrorResponse(w, ErrInternalError)
+		return
+	}
+
+	response := AssumeRoleWithWebIdentityResponse{}
+	result := &response.AssumeRoleWithWebIdentityResult
+	result.Credentials = credentials.toXml()
+	result.AssumedRoleUser = assumedRoleUser(role, sessionName)
+	result.SubjectFromWebIdentityToken = webIdentity.subject
+	result.Provider = webIdentity.issuer
+	if len(webIdentity.audiences) > 0 {
+		result.Audience = webIdentity.audiences[0]
+	}
+	response.ResponseMetadata.RequestId = newRequestId()
+
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// sessionParameters checks the RoleArn, RoleSessionName and DurationSeconds parameters
+func (iam *IdentityAccessManagement) sessionParameters(r *http.Request) (role *Role, sessionName string, duration time.Duration, errCode ErrorCode) {
+
+	roleArn, sessionName := r.Form.Get("RoleArn"), r.Form.Get("RoleSessionName")
+	if roleArn == "" || sessionName == "" {
+		return nil, "", 0, ErrMissingParameter
+	}
+	if !roleSessionNameRegexp.MatchString(sessionName) {
+		return nil, "", 0, ErrInvalidParameterValue
+	}
+
+	if role = iam.lookupRole(roleName(roleArn)); role == nil {
+		return nil, "", 0, ErrAccessDenied
+	}
+
+	var seconds int64
+	if durationSeconds := r.Form.Get("DurationSeconds"); durationSeconds != "" {
+		var err error
+		if seconds, err = strconv.ParseInt(durationSeconds, 10, 64); err != nil || seconds <= 0 {
+			return nil, "", 0, ErrInvalidParameterValue
+		}
+	}
+	if duration, errCode = role.sessionDuration(seconds); errCode != ErrNone {
+		return nil, "", 0, errCode
+	}
+
+	return role, sessionName, duration, ErrNone
+}
+
+func (credentials *sessionCredentials) toXml() StsCredentials {
+	return StsCredentials{
+		AccessKeyId:     credentials.AccessKeyId,
+		SecretAccessKey: credentials.SecretAccessKey,
+		SessionToken:    credentials.SessionToken,
+		Expiration:      credentials.Expiration.Format(time.RFC3339),
+	}
+}
+
+func assumedRoleUser(role *Role, sessionName string) AssumedRoleUser {
+	return AssumedRoleUser{
+		Arn:           fmt.Sprintf("arn:aws:sts::%s:assumed-role/%s/%s", stsAccountId, role.Name, sessionName),
+		AssumedRoleId: role.Name + ":" + sessionName,
+	}
+}
+
+func newRequestId() string {
+	return fmt.Sprintf("%d", time.Now().UnixNano())
+}
+
+func writeStsErrorResponse(w http.ResponseWriter, errorCode ErrorCode) {
+	apiError := getAPIError(errorCode)
+	errorResponse := StsErrorResponse{RequestId: newRequestId()}
+	errorResponse.Error.Type = "Sender"
+	if apiError.HTTPStatusCode >= http.StatusInternalServerError {
+		errorResponse.Error.Type = "Receiver"
+	}
+	errorResponse.Error.Code = apiError.Code
+	errorResponse.Error.Message = apiError.Description
+	writeResponse(w, apiError.HTTPStatusCode, encodeResponse(errorResponse), mimeXML)
+}
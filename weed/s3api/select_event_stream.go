@@ -0,0 +1,105 @@
+package s3api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTSelectObjectAppendix.html
+
+const eventStreamHeaderTypeString = 7
+
+type eventStreamHeader struct {
+	name, value string
+}
+
+// encodeEventStreamMessage frames one message:
+// total length, headers length, prelude crc, headers, payload, message crc
+func encodeEventStreamMessage(headers []eventStreamHeader, payload []byte) []byte {
+
+	var headerBytes bytes.Buffer
+	for _, h := range headers {
+		headerBytes.WriteByte(byte(len(h.name)))
+		headerBytes.WriteString(h.name)
+		headerBytes.WriteByte(eventStreamHeaderTypeString)
+		binary.Write(&headerBytes, binary.BigEndian, uint16(len(h.value)))
+		headerBytes.WriteString(h.value)
+	}
+
+	totalLength := 4 + 4 + 4 + headerBytes.Len() + len(payload) + 4
+
+	message := make([]byte, 0, totalLength)
+	message = appendUint32(message, uint32(totalLength))
+	message = appendUint32(message, uint32(headerBytes.Len()))
+	message = appendUint32(message, crc32.ChecksumIEEE(message))
+	message = append(message, headerBytes.Bytes()...)
+	message = append(message, payload...)
+	message = appendUint32(message, crc32.ChecksumIEEE(message))
+
+	return message
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// selectEventWriter writes SelectObjectContent events to the response
+type selectEventWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	err     error
+}
+
+func newSelectEventWriter(w http.ResponseWriter) *selectEventWriter {
+	flusher, _ := w.(http.Flusher)
+	return &selectEventWriter{w: w, flusher: flusher}
+}
+
+func (ew *selectEventWriter) write(headers []eventStreamHeader, payload []byte) error {
+	if ew.err != nil {
+		return ew.err
+	}
+	if _, ew.err = ew.w.Write(encodeEventStreamMessage(headers, payload)); ew.err != nil {
+		return ew.err
+	}
+	if ew.flusher != nil {
+		ew.flusher.Flush()
+	}
+	return nil
+}
+
+func (ew *selectEventWriter) writeRecords(records []byte) error {
+	return ew.write([]eventStreamHeader{
+		{":event-type", "Records"},
+		{":content-type", "application/octet-stream"},
+		{":message-type", "event"},
+	}, records)
+}
+
+func (ew *selectEventWriter) writeProgress(eventType string, bytesScanned, bytesProcessed, bytesReturned int64) error {
+	payload := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?><%s><BytesScanned>%d</BytesScanned><BytesProcessed>%d</BytesProcessed><BytesReturned>%d</BytesReturned></%s>`,
+		eventType, bytesScanned, bytesProcessed, bytesReturned, eventType)
+	return ew.write([]eventStreamHeader{
+		{":event-type", eventType},
+		{":content-type", "text/xml"},
+		{":message-type", "event"},
+	}, []byte(payload))
+}
+
+func (ew *selectEventWriter) writeEnd() error {
+	return ew.write([]eventStreamHeader{
+		{":event-type", "End"},
+		{":message-type", "event"},
+	}, nil)
+}
+
+func (ew *selectEventWriter) writeError(code, message string) error {
+	return ew.write([]eventStreamHeader{
+		{":error-code", code},
+		{":error-message", message},
+		{":message-type", "error"},
+	}, nil)
+}
@@ -0,0 +1,76 @@
+package s3api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"net/http/httptest"
+	"testing"
+)
+
+type decodedEvent struct {
+	headers map[string]string
+	payload []byte
+}
+
+func decodeEventStream(t *testing.T, data []byte) (events []decodedEvent) {
+	for len(data) > 0 {
+		if len(data) < 16 {
+			t.Fatalf("truncated message of %d bytes", len(data))
+		}
+		totalLength := binary.BigEndian.Uint32(data[0:4])
+		headersLength := binary.BigEndian.Uint32(data[4:8])
+		if crc32.ChecksumIEEE(data[0:8]) != binary.BigEndian.Uint32(data[8:12]) {
+			t.Fatalf("prelude crc mismatch")
+		}
+		message := data[:totalLength]
+		if crc32.ChecksumIEEE(message[:totalLength-4]) != binary.BigEndian.Uint32(message[totalLength-4:]) {
+			t.Fatalf("message crc mismatch")
+		}
+
+		event := decodedEvent{headers: make(map[string]string)}
+		headers := message[12 : 12+headersLength]
+		for len(headers) > 0 {
+			nameLength := int(headers[0])
+			name := string(headers[1 : 1+nameLength])
+			if headers[1+nameLength] != eventStreamHeaderTypeString {
+				t.Fatalf("unexpected header type %d", headers[1+nameLength])
+			}
+			valueLength := int(binary.BigEndian.Uint16(headers[2+nameLength:]))
+			event.headers[name] = string(headers[4+nameLength : 4+nameLength+valueLength])
+			headers = headers[4+nameLength+valueLength:]
+		}
+		event.payload = message[12+headersLength : totalLength-4]
+
+		events = append(events, event)
+		data = data[totalLength:]
+	}
+	return
+}
+
+func TestSelectEventStream(t *testing.T) {
+
+	recorder := httptest.NewRecorder()
+	events := newSelectEventWriter(recorder)
+
+	events.writeRecords([]byte("a,b\n"))
+	events.writeProgress("Stats", 10, 20, 4)
+	events.writeEnd()
+
+	decoded := decodeEventStream(t, recorder.Body.Bytes())
+	if len(decoded) != 3 {
+		t.Fatalf("decoded %d events, expecting 3", len(decoded))
+	}
+
+	if decoded[0].headers[":event-type"] != "Records" || !bytes.Equal(decoded[0].payload, []byte("a,b\n")) {
+		t.Errorf("unexpected records event %+v", decoded[0])
+	}
+	if decoded[1].headers[":event-type"] != "Stats" || decoded[1].headers[":content-type"] != "text/xml" ||
+		!bytes.Contains(decoded[1].payload, []byte("<BytesScanned>10</BytesScanned><BytesProcessed>20</BytesProcessed><BytesReturned>4</BytesReturned>")) {
+		t.Errorf("unexpected stats event %+v", decoded[1])
+	}
+	if decoded[2].headers[":event-type"] != "End" || decoded[2].headers[":message-type"] != "event" || len(decoded[2].payload) != 0 {
+		t.Errorf("unexpected end event %+v", decoded[2])
+	}
+
+}
@@ -0,0 +1,160 @@
+package s3api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/storage-class-intro.html
+//
+// The storage classes are mapped to the storage tiers of the deployment, in the -storageClass.config file.
+// The chunks of an object are written with the replication, ttl, data center and disk type of the tier of its class.
+
+const (
+	// request and response header, also used as the extended attribute key on the object entry
+	AmzStorageClass = "X-Amz-Storage-Class"
+
+	StorageClassStandard = "STANDARD"
+)
+
+var knownStorageClasses = map[string]bool{
+	StorageClassStandard:  true,
+	"REDUCED_REDUNDANCY":  true,
+	"STANDARD_IA":         true,
+	"ONEZONE_IA":          true,
+	"INTELLIGENT_TIERING": true,
+	"GLACIER":             true,
+	"DEEP_ARCHIVE":        true,
+}
+
+// storageTier is where the chunks are written, the empty fields keep the defaults of the bucket
+type storageTier struct {
+	Replication string `json:"replication"`
+	Ttl         string `json:"ttl"`
+	DataCenter  string `json:"dataCenter"`
+	DiskType    string `json:"diskType"`
+}
+
+// storageClassConfig is the json file of the s3 -storageClass.config option
+type storageClassConfig struct {
+	StorageClasses map[string]storageTier `json:"storageClasses"`
+	// the tiers of some classes, overridden for a bucket
+	Buckets map[string]map[string]storageTier `json:"buckets"`
+}
+
+func loadStorageClassConfig(fileName string) (*storageClassConfig, error) {
+
+	config := &storageClassConfig{}
+	if fileName == "" {
+		return config, nil
+	}
+
+	rawData, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("read storage class config %s: %v", fileName, err)
+	}
+	if err = json.Unmarshal(rawData, config); err != nil {
+		return nil, fmt.Errorf("parse storage class config %s: %v", fileName, err)
+	}
+
+	if err = checkStorageTiers(config.StorageClasses); err != nil {
+		return nil, fmt.Errorf("storage class config %s: %v", fileName, err)
+	}
+	for bucket, classes := range config.Buckets {
+		if err = checkStorageTiers(classes); err != nil {
+			return nil, fmt.Errorf("storage class config %s, bucket %s: %v", fileName, bucket, err)
+		}
+	}
+
+	return config, nil
+}
+
+func checkStorageTiers(tiers map[string]storageTier) error {
+	for class, tier := range tiers {
+		if !knownStorageClasses[class] {
+			return fmt.Errorf("unknown storage class %s", class)
+		}
+		if tier.Replication != "" {
+			if _, err := super_block.NewReplicaPlacementFromString(tier.Replication); err != nil {
+				return fmt.Errorf("storage class %s: %v", class, err)
+			}
+		}
+		if _, err := needle.ReadTTL(tier.Ttl); err != nil {
+			return fmt.Errorf("storage class %s: %v", class, err)
+		}
+	}
+	return nil
+}
+
+// tier returns the effective storage class of the objects of the bucket written with the class,
+// which is STANDARD for the classes without a tier, and the tier to write them to
+func (config *storageClassConfig) tier(bucket, class string) (effectiveClass string, tier storageTier, code ErrorCode) {
+	if class == "" {
+		class = StorageClassStandard
+	}
+	if !knownStorageClasses[class] {
+		return "", tier, ErrInvalidStorageClass
+	}
+	if tier, found := config.Buckets[bucket][class]; found {
+		return class, tier, ErrNone
+	}
+	if tier, found := config.StorageClasses[class]; found {
+		return class, tier, ErrNone
+	}
+	if class != StorageClassStandard {
+		return config.tier(bucket, StorageClassStandard)
+	}
+	return StorageClassStandard, tier, ErrNone
+}
+
+func (tier storageTier) ttlSec() int32 {
+	ttl, err := needle.ReadTTL(tier.Ttl)
+	if err != nil {
+		return 0
+	}
+	return int32(ttl.Minutes()) * 60
+}
+
+// addTo adds the tier to the query of a filer upload url
+func (tier storageTier) addTo(uploadUrl string) string {
+	query := url.Values{}
+	if tier.Replication != "" {
+		query.Set("replication", tier.Replication)
+	}
+	if tier.Ttl != "" {
+		query.Set("ttl", tier.Ttl)
+	}
+	if tier.DataCenter != "" {
+		query.Set("dataCenter", tier.DataCenter)
+	}
+	if tier.DiskType != "" {
+		query.Set("diskType", tier.DiskType)
+	}
+	if len(query) == 0 {
+		return uploadUrl
+	}
+	if strings.Contains(uploadUrl, "?") {
+		return uploadUrl + "&" + query.Encode()
+	}
+	return uploadUrl + "?" + query.Encode()
+}
+
+// prepareStorageClass checks the storage class header of the request
+func (s3a *S3ApiServer) prepareStorageClass(r *http.Request, bucket string) (class string, tier storageTier, code ErrorCode) {
+	return s3a.storageClasses.tier(bucket, r.Header.Get(AmzStorageClass))
+}
+
+// getStorageClass returns the storage class of an object, or of a multipart upload
+func getStorageClass(extended map[string][]byte) string {
+	if class, found := extended[AmzStorageClass]; found {
+		return string(class)
+	}
+	return StorageClassStandard
+}
@@ -0,0 +1,99 @@
+package s3api
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestStorageClassTier(t *testing.T) {
+	config := &storageClassConfig{
+		StorageClasses: map[string]storageTier{
+			"STANDARD_IA": {Replication: "000"},
+			"GLACIER":     {Replication: "010", DataCenter: "dc-cold"},
+		},
+		Buckets: map[string]map[string]storageTier{
+			"logs": {
+				"STANDARD":    {Replication: "001"},
+				"STANDARD_IA": {Ttl: "30d"},
+			},
+		},
+	}
+
+	tests := []struct {
+		bucket   string
+		class    string
+		expected string
+		tier     storageTier
+		code     ErrorCode
+	}{
+		{"b", "", StorageClassStandard, storageTier{}, ErrNone},
+		{"b", "STANDARD", StorageClassStandard, storageTier{}, ErrNone},
+		{"b", "STANDARD_IA", "STANDARD_IA", storageTier{Replication: "000"}, ErrNone},
+		{"b", "GLACIER", "GLACIER", storageTier{Replication: "010", DataCenter: "dc-cold"}, ErrNone},
+		{"b", "DEEP_ARCHIVE", StorageClassStandard, storageTier{}, ErrNone},
+		{"b", "COLD", "", storageTier{}, ErrInvalidStorageClass},
+		{"logs", "", StorageClassStandard, storageTier{Replication: "001"}, ErrNone},
+		{"logs", "STANDARD_IA", "STANDARD_IA", storageTier{Ttl: "30d"}, ErrNone},
+		{"logs", "GLACIER", "GLACIER", storageTier{Replication: "010", DataCenter: "dc-cold"}, ErrNone},
+		{"logs", "ONEZONE_IA", StorageClassStandard, storageTier{Replication: "001"}, ErrNone},
+	}
+	for _, test := range tests {
+		class, tier, code := config.tier(test.bucket, test.class)
+		if class != test.expected || tier != test.tier || code != test.code {
+			t.Errorf("%s %s: %s %+v %v, expecting %s %+v %v", test.bucket, test.class, class, tier, code, test.expected, test.tier, test.code)
+		}
+	}
+}
+
+func TestStorageTierAddTo(t *testing.T) {
+	tests := []struct {
+		tier     storageTier
+		url      string
+		expected string
+	}{
+		{storageTier{}, "http://filer/buckets/b/a.txt", "http://filer/buckets/b/a.txt"},
+		{storageTier{Replication: "001", Ttl: "7d"}, "http://filer/buckets/b/a.txt", "http://filer/buckets/b/a.txt?replication=001&ttl=7d"},
+		{storageTier{DataCenter: "dc2"}, "http://filer/buckets/b/a.txt?collection=b", "http://filer/buckets/b/a.txt?collection=b&dataCenter=dc2"},
+		{storageTier{Replication: "000", DiskType: "ssd"}, "http://filer/buckets/b/a.txt", "http://filer/buckets/b/a.txt?diskType=ssd&replication=000"},
+	}
+	for _, test := range tests {
+		if url := test.tier.addTo(test.url); url != test.expected {
+			t.Errorf("%+v: %s, expecting %s", test.tier, url, test.expected)
+		}
+	}
+	if ttlSec := (storageTier{Ttl: "2h"}).ttlSec(); ttlSec != 7200 {
+		t.Errorf("ttl 2h: %d seconds", ttlSec)
+	}
+}
+
+func TestLoadStorageClassConfig(t *testing.T) {
+	tests := []struct {
+		config string
+		valid  bool
+	}{
+		{`{"storageClasses": {"GLACIER": {"replication": "010", "ttl": "1y"}}, "buckets": {"logs": {"STANDARD_IA": {"dataCenter": "dc2"}}}}`, true},
+		{`{"storageClasses": {"COLD": {"replication": "000"}}}`, false},
+		{`{"buckets": {"logs": {"COLD": {}}}}`, false},
+		{`{"storageClasses": {"GLACIER": {"replication": "abc"}}}`, false},
+		{`{"storageClasses": {"GLACIER": {"ttl": "ad"}}}`, false},
+		{`{"storageClasses": [`, false},
+	}
+	for _, test := range tests {
+		file, err := ioutil.TempFile("", "storage_class")
+		if err != nil {
+			t.Fatalf("temp file: %v", err)
+		}
+		file.WriteString(test.config)
+		file.Close()
+		_, err = loadStorageClassConfig(file.Name())
+		os.Remove(file.Name())
+		if (err == nil) != test.valid {
+			t.Errorf("%s: %v", test.config, err)
+		}
+	}
+
+	if config, err := loadStorageClassConfig(""); err != nil || config == nil {
+		t.Errorf("load without config file: %v", err)
+	}
+}
@@ -0,0 +1,348 @@
+package s3api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/iam_pb"
+)
+
+// The temporary credentials issued by AssumeRole and AssumeRoleWithWebIdentity are not stored anywhere.
+// The session token is a JWT signed by the STS key of security.toml, carrying the access key, the role and the expiration.
+// The secret key is derived from the access key by the same STS key, so any s3 server sharing the key can verify them.
+
+const (
+	stsAccountId = "000000000000"
+
+	stsMinSessionDuration     = 15 * time.Minute
+	stsDefaultSessionDuration = time.Hour
+	stsMaxSessionDuration     = 12 * time.Hour
+
+	sessionAccessKeyPrefix = "ASIA"
+)
+
+var roleSessionNameRegexp = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+
+type Role struct {
+	Name                 string
+	Actions              []Action
+	TrustedIdentities    []string
+	TrustedWebIdentities []*iam_pb.WebIdentityTrust
+	MaxSessionDuration   time.Duration
+}
+
+func (role *Role) trusts(identityName string) bool {
+	for _, name := range role.TrustedIdentities {
+		if name == identityName {
+			return true
+		}
+	}
+	return false
+}
+
+func (role *Role) trustsWebIdentity(identity *webIdentity) bool {
+	for _, trust := range role.TrustedWebIdentities {
+		if trust.Issuer != identity.issuer {
+			continue
+		}
+		if trust.Audience != "" && !contains(identity.audiences, trust.Audience) {
+			continue
+		}
+		if len(trust.Subjects) > 0 && !contains(trust.Subjects, identity.subject) {
+			continue
+		}
+		if !identity.matches(trust.Conditions) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// sessionDuration checks the requested DurationSeconds against the role
+func (role *Role) sessionDuration(seconds int64) (time.Duration, ErrorCode) {
+	if seconds == 0 {
+		if role.MaxSessionDuration < stsDefaultSessionDuration {
+			return role.MaxSessionDuration, ErrNone
+		}
+		return stsDefaultSessionDuration, ErrNone
+	}
+	duration := time.Duration(seconds) * time.Second
+	if duration < stsMinSessionDuration || duration > role.MaxSessionDuration {
+		return 0, ErrInvalidParameterValue
+	}
+	return duration, ErrNone
+}
+
+func newRole(role *iam_pb.Role) *Role {
+	t := &Role{
+		Name:                 role.Name,
+		TrustedIdentities:    role.TrustedIdentities,
+		TrustedWebIdentities: role.TrustedWebIdentities,
+		MaxSessionDuration:   time.Duration(role.MaxSessionDurationSeconds) * time.Second,
+	}
+	for _, action := range role.Actions {
+		t.Actions = append(t.Actions, Action(action))
+	}
+	if t.MaxSessionDuration <= 0 {
+		t.MaxSessionDuration = stsDefaultSessionDuration
+	}
+	if t.MaxSessionDuration > stsMaxSessionDuration {
+		t.MaxSessionDuration = stsMaxSessionDuration
+	}
+	return t
+}
+
+func (iam *IdentityAccessManagement) lookupRole(name string) *Role {
+	for _, role := range iam.roles {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+// roleName accepts the role ARN, e.g. arn:aws:iam::123456789012:role/path/name, or just the role name
+func roleName(roleArn string) string {
+	if !strings.HasPrefix(roleArn, "arn:") {
+		return roleArn
+	}
+	if !strings.Contains(roleArn, ":role/") {
+		return ""
+	}
+	return roleArn[strings.LastIndex(roleArn, "/")+1:]
+}
+
+type sessionClaims struct {
+	AccessKey   string `json:"ak"`
+	Role        string `json:"role"`
+	SessionName string `json:"session"`
+	jwt.StandardClaims
+}
+
+type sessionCredentials struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+}
+
+func (iam *IdentityAccessManagement) newSessionCredentials(role *Role, sessionName, subject string, duration time.Duration) (*sessionCredentials, error) {
+
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	credentials := &sessionCredentials{
+		AccessKeyId: sessionAccessKeyPrefix + base32.StdEncoding.EncodeToString(random),
+		Expiration:  now.Add(duration).UTC().Truncate(time.Second),
+	}
+	credentials.SecretAccessKey = iam.sessionSecretKey(credentials.AccessKeyId)
+
+	claims := sessionClaims{
+		AccessKey:   credentials.AccessKeyId,
+		Role:        role.Name,
+		SessionName: sessionName,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   subject,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: credentials.Expiration.Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(iam.stsSigningKey)
+	if err != nil {
+		return nil, err
+	}
+	credentials.SessionToken = token
+
+	return credentials, nil
+}
+
+func (iam *IdentityAccessManagement) sessionSecretKey(accessKey string) string {
+	mac := hmac.New(sha256.New, iam.stsSigningKey)
+	mac.Write([]byte("secret/" + accessKey))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)[:30])
+}
+
+// lookupCredential finds the identity of a long-term access key,
+// or of a temporary access key with its session token in the request
+func (iam *IdentityAccessManagement) lookupCredential(r *http.Request, accessKey string) (*Identity, *Credential, ErrorCode) {
+
+	if identity, cred, found := iam.lookupByAccessKey(accessKey); found {
+		return identity, cred, ErrNone
+	}
+
+	token := r.Header.Get("X-Amz-Security-Token")
+	if token == "" {
+		token = r.URL.Query().Get("X-Amz-Security-Token")
+	}
+	if token == "" || len(iam.stsSigningKey) == 0 || !strings.HasPrefix(accessKey, sessionAccessKeyPrefix) {
+		return nil, nil, ErrInvalidAccessKeyID
+	}
+
+	claims, errCode := iam.parseSessionToken(token)
+	if errCode != ErrNone {
+		return nil, nil, errCode
+	}
+	if claims.AccessKey != accessKey {
+		return nil, nil, ErrInvalidToken
+	}
+	// removing the role revokes its sessions
+	role := iam.lookupRole(claims.Role)
+	if role == nil {
+		return nil, nil, ErrInvalidToken
+	}
+
+	identity := &Identity{
+		Name:    role.Name,
+		Actions: role.Actions,
+	}
+	return identity, &Credential{AccessKey: accessKey, SecretKey: iam.sessionSecretKey(accessKey)}, ErrNone
+}
+
+func (iam *IdentityAccessManagement) parseSessionToken(token string) (*sessionClaims, ErrorCode) {
+	claims := &sessionClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return iam.stsSigningKey, nil
+	})
+	if err != nil {
+		if ve, ok := err.(*jwt.ValidationError); ok && ve.Errors&jwt.ValidationErrorExpired != 0 {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+	return claims, ErrNone
+}
+
+// webIdentity is the verified identity of a web identity token, e.g. an OpenID Connect ID token
+type webIdentity struct {
+	issuer    string
+	subject   string
+	audiences []string
+	claims    jwt.MapClaims
+}
+
+// matches checks the claims against all the conditions
+func (identity *webIdentity) matches(conditions []*iam_pb.WebIdentityClaimCondition) bool {
+	for _, condition := range conditions {
+		claim, found := identity.claims[condition.Claim]
+		if !found {
+			return false
+		}
+		// "*" only requires the claim
+		if !contains(condition.Values, "*") && !containsAny(condition.Values, claimStrings(claim)) {
+			return false
+		}
+	}
+	return true
+}
+
+// claimStrings returns a string claim, or the strings of an array claim
+func claimStrings(claim interface{}) (values []string) {
+	switch claim := claim.(type) {
+	case string:
+		values = []string{claim}
+	case []interface{}:
+		for _, v := range claim {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	return
+}
+
+func containsAny(list []string, values []string) bool {
+	for _, v := range values {
+		if contains(list, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (iam *IdentityAccessManagement) verifyWebIdentityToken(token string) (*webIdentity, ErrorCode) {
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		issuer, _ := claims["iss"].(string)
+		provider := iam.lookupWebIdentityProvider(issuer)
+		if provider == nil {
+			return nil, fmt.Errorf("unknown issuer %q", issuer)
+		}
+		// the provider keys only, never the HMAC of a public key
+		usesJwks := usesJwks(provider)
+		if usesJwks && len(provider.ClientIds) == 0 {
+			return nil, fmt.Errorf("no client ids of %s", issuer)
+		}
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if provider.SigningKey != "" {
+				return []byte(provider.SigningKey), nil
+			}
+		case *jwt.SigningMethodRSA:
+			if provider.PublicKey != "" {
+				return jwt.ParseRSAPublicKeyFromPEM([]byte(provider.PublicKey))
+			}
+			if usesJwks {
+				kid, _ := token.Header["kid"].(string)
+				return iam.jwksCacheOf(provider).key(kid)
+			}
+		case *jwt.SigningMethodECDSA:
+			if provider.PublicKey != "" {
+				return jwt.ParseECPublicKeyFromPEM([]byte(provider.PublicKey))
+			}
+			if usesJwks {
+				kid, _ := token.Header["kid"].(string)
+				return iam.jwksCacheOf(provider).key(kid)
+			}
+		}
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	})
+	if err != nil {
+		if ve, ok := err.(*jwt.ValidationError); ok && ve.Errors&jwt.ValidationErrorExpired != 0 {
+			return nil, ErrExpiredToken
+		}
+		glog.V(1).Infof("invalid web identity token: %v", err)
+		return nil, ErrInvalidIdentityToken
+	}
+
+	identity := &webIdentity{claims: claims}
+	identity.issuer, _ = claims["iss"].(string)
+	identity.subject, _ = claims["sub"].(string)
+	identity.audiences = claimStrings(claims["aud"])
+	// tokens never expiring are not accepted
+	if _, found := claims["exp"]; !found || identity.subject == "" {
+		return nil, ErrInvalidIdentityToken
+	}
+	// the token is issued to one of the clients of the provider, always checked with the jwks keys
+	if provider := iam.lookupWebIdentityProvider(identity.issuer); len(provider.ClientIds) > 0 && !containsAny(provider.ClientIds, identity.audiences) {
+		glog.V(1).Infof("web identity token of %s for audiences %v", identity.issuer, identity.audiences)
+		return nil, ErrInvalidIdentityToken
+	}
+	return identity, ErrNone
+}
+
+func (iam *IdentityAccessManagement) lookupWebIdentityProvider(issuer string) *iam_pb.WebIdentityProvider {
+	for _, provider := range iam.webIdentityProviders {
+		if provider.Issuer == issuer {
+			return provider
+		}
+	}
+	return nil
+}
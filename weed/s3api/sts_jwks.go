@@ -0,0 +1,249 @@
+package s3api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/iam_pb"
+)
+
+// The keys of an OpenID Connect provider are fetched from its JWKS uri, and cached for the provider jwks_refresh_seconds.
+// A token signed by a key not in the cache refreshes the keys at once, since the provider may have rotated its keys,
+// but at most once every jwksMinRefreshInterval, so tokens with random key ids do not flood the provider.
+// If the provider can not be reached, the cached keys are used until the provider is back.
+// The keys are fetched without holding the cache lock, and only the tokens signed by the keys not in the cache
+// wait for the fetch in progress.
+
+const (
+	jwksDefaultRefreshInterval = time.Hour
+	jwksMaxResponseSize        = 1 << 20
+)
+
+var (
+	jwksMinRefreshInterval = time.Minute
+	jwksHttpClient         = &http.Client{Timeout: 10 * time.Second}
+)
+
+type jwksCache struct {
+	sync.Mutex
+	provider    *iam_pb.WebIdentityProvider
+	keys        map[string]interface{}
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	fetching    chan struct{} // closed when the fetch in progress is done
+}
+
+// usesJwks tells whether the keys of the provider are fetched from the JWKS uri, for an OpenID Connect provider
+func usesJwks(provider *iam_pb.WebIdentityProvider) bool {
+	return provider.SigningKey == "" && provider.PublicKey == ""
+}
+
+func (iam *IdentityAccessManagement) jwksCacheOf(provider *iam_pb.WebIdentityProvider) *jwksCache {
+	iam.jwksLock.Lock()
+	defer iam.jwksLock.Unlock()
+	if iam.jwksCaches == nil {
+		iam.jwksCaches = make(map[string]*jwksCache)
+	}
+	cache, found := iam.jwksCaches[provider.Issuer]
+	if !found || cache.provider != provider {
+		cache = &jwksCache{provider: provider}
+		iam.jwksCaches[provider.Issuer] = cache
+	}
+	return cache
+}
+
+// key returns the public key of the key id, or the only key of the provider if the token has no key id
+func (cache *jwksCache) key(kid string) (interface{}, error) {
+	cache.Lock()
+
+	key, found := cache.lookup(kid)
+	for !found && cache.fetching != nil {
+		fetching := cache.fetching
+		cache.Unlock()
+		<-fetching
+		cache.Lock()
+		key, found = cache.lookup(kid)
+	}
+
+	refreshInterval := time.Duration(cache.provider.JwksRefreshSeconds) * time.Second
+	if refreshInterval <= 0 {
+		refreshInterval = jwksDefaultRefreshInterval
+	}
+	stale := time.Since(cache.fetchedAt) > refreshInterval
+	if (found && !stale) || cache.fetching != nil || time.Since(cache.attemptedAt) < jwksMinRefreshInterval {
+		cache.Unlock()
+		if !found {
+			return nil, fmt.Errorf("unknown key %q of %s", kid, cache.provider.Issuer)
+		}
+		return key, nil
+	}
+
+	attemptedAt, fetching := time.Now(), make(chan struct{})
+	cache.attemptedAt, cache.fetching = attemptedAt, fetching
+	cache.Unlock()
+
+	keys, err := fetchJwks(cache.provider)
+
+	cache.Lock()
+	defer cache.Unlock()
+	cache.fetching = nil
+	close(fetching)
+	if err != nil {
+		glog.Warningf("fetch the keys of %s: %v", cache.provider.Issuer, err)
+		if found {
+			return key, nil
+		}
+		return nil, err
+	}
+	cache.keys, cache.fetchedAt = keys, attemptedAt
+
+	if key, found = cache.lookup(kid); !found {
+		return nil, fmt.Errorf("unknown key %q of %s", kid, cache.provider.Issuer)
+	}
+	return key, nil
+}
+
+func (cache *jwksCache) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(cache.keys) == 1 {
+		for _, key := range cache.keys {
+			return key, true
+		}
+	}
+	key, found := cache.keys[kid]
+	return key, found
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func fetchJwks(provider *iam_pb.WebIdentityProvider) (map[string]interface{}, error) {
+
+	jwksUri := provider.JwksUri
+	if jwksUri == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JwksUri string `json:"jwks_uri"`
+		}
+		if err := getJson(strings.TrimSuffix(provider.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, err
+		}
+		if discovery.Issuer != provider.Issuer {
+			return nil, fmt.Errorf("discovered issuer %q, expecting %q", discovery.Issuer, provider.Issuer)
+		}
+		if discovery.JwksUri == "" {
+			return nil, fmt.Errorf("no jwks_uri discovered for %s", provider.Issuer)
+		}
+		jwksUri = discovery.JwksUri
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJson(jwksUri, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			glog.V(1).Infof("skip key %q of %s: %v", jwk.Kid, provider.Issuer, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys at %s", jwksUri)
+	}
+	return keys, nil
+}
+
+func getJson(url string, v interface{}) error {
+	resp, err := jwksHttpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return fmt.Errorf("get %s: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("decode %s: %v", url, err)
+	}
+	return nil
+}
+
+func (jwk *jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid exponent %s", jwk.E)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point not on the curve %s", jwk.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid base64url integer %q", s)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
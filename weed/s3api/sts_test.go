@@ -0,0 +1,392 @@
+package s3api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/iam_pb"
+)
+
+func newTestStsIam() *IdentityAccessManagement {
+	return &IdentityAccessManagement{
+		stsSigningKey: []byte("sts signing key"),
+		roles: []*Role{newRole(&iam_pb.Role{
+			Name:              "uploader",
+			Actions:           []string{"Write:bucket1"},
+			TrustedIdentities: []string{"admin"},
+			TrustedWebIdentities: []*iam_pb.WebIdentityTrust{
+				{Issuer: "https://accounts.example.com", Audience: "seaweedfs"},
+			},
+			MaxSessionDurationSeconds: 7200,
+		})},
+		webIdentityProviders: []*iam_pb.WebIdentityProvider{
+			{Issuer: "https://accounts.example.com", SigningKey: "provider key"},
+		},
+	}
+}
+
+func TestSessionCredentials(t *testing.T) {
+
+	iam := newTestStsIam()
+	role := iam.lookupRole("uploader")
+
+	credentials, err := iam.newSessionCredentials(role, "session1", "admin", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &http.Request{Header: make(http.Header), URL: &url.URL{}}
+	if _, _, errCode := iam.lookupCredential(r, credentials.AccessKeyId); errCode != ErrInvalidAccessKeyID {
+		t.Errorf("without session token: %v", errCode)
+	}
+
+	r.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	identity, cred, errCode := iam.lookupCredential(r, credentials.AccessKeyId)
+	if errCode != ErrNone {
+		t.Fatalf("lookup: %v", errCode)
+	}
+	if identity.Name != "uploader" || !identity.canDo(ACTION_WRITE, "bucket1") || identity.canDo(ACTION_WRITE, "bucket2") {
+		t.Errorf("identity %+v", identity)
+	}
+	if cred.SecretKey != credentials.SecretAccessKey {
+		t.Errorf("secret key %s, expecting %s", cred.SecretKey, credentials.SecretAccessKey)
+	}
+
+	// the session token belongs to another access key
+	other, _ := iam.newSessionCredentials(role, "session2", "admin", time.Hour)
+	if _, _, errCode := iam.lookupCredential(r, other.AccessKeyId); errCode != ErrInvalidToken {
+		t.Errorf("mismatched access key: %v", errCode)
+	}
+
+	// presigned requests carry the session token in the query
+	r = &http.Request{Header: make(http.Header), URL: &url.URL{RawQuery: url.Values{"X-Amz-Security-Token": {other.SessionToken}}.Encode()}}
+	if _, _, errCode := iam.lookupCredential(r, other.AccessKeyId); errCode != ErrNone {
+		t.Errorf("presigned: %v", errCode)
+	}
+
+	// signed by another key
+	forged := &IdentityAccessManagement{stsSigningKey: []byte("another key"), roles: iam.roles}
+	if _, _, errCode := forged.lookupCredential(r, other.AccessKeyId); errCode != ErrInvalidToken {
+		t.Errorf("forged: %v", errCode)
+	}
+
+	// the role is removed
+	iam.roles = nil
+	if _, _, errCode := iam.lookupCredential(r, other.AccessKeyId); errCode != ErrInvalidToken {
+		t.Errorf("removed role: %v", errCode)
+	}
+}
+
+func TestExpiredSessionToken(t *testing.T) {
+
+	iam := newTestStsIam()
+	role := iam.lookupRole("uploader")
+
+	credentials, err := iam.newSessionCredentials(role, "session1", "admin", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &http.Request{Header: make(http.Header), URL: &url.URL{}}
+	r.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	if _, _, errCode := iam.lookupCredential(r, credentials.AccessKeyId); errCode != ErrExpiredToken {
+		t.Errorf("expired: %v", errCode)
+	}
+}
+
+func TestSessionParameters(t *testing.T) {
+
+	iam := newTestStsIam()
+
+	tests := []struct {
+		form     url.Values
+		duration time.Duration
+		expected ErrorCode
+	}{
+		{url.Values{"RoleArn": {"arn:aws:iam::123456789012:role/uploader"}, "RoleSessionName": {"s1"}}, time.Hour, ErrNone},
+		{url.Values{"RoleArn": {"arn:aws:iam::123456789012:role/path/uploader"}, "RoleSessionName": {"s1"}, "DurationSeconds": {"7200"}}, 2 * time.Hour, ErrNone},
+		{url.Values{"RoleArn": {"uploader"}, "RoleSessionName": {"user@example.com"}, "DurationSeconds": {"900"}}, 15 * time.Minute, ErrNone},
+		{url.Values{"RoleArn": {"uploader"}, "RoleSessionName": {"s1"}, "DurationSeconds": {"7201"}}, 0, ErrInvalidParameterValue},
+		{url.Values{"RoleArn": {"uploader"}, "RoleSessionName": {"s1"}, "DurationSeconds": {"899"}}, 0, ErrInvalidParameterValue},
+		{url.Values{"RoleArn": {"uploader"}, "RoleSessionName": {"s1"}, "DurationSeconds": {"x"}}, 0, ErrInvalidParameterValue},
+		{url.Values{"RoleArn": {"uploader"}, "RoleSessionName": {"a b"}}, 0, ErrInvalidParameterValue},
+		{url.Values{"RoleArn": {"uploader"}}, 0, ErrMissingParameter},
+		{url.Values{"RoleArn": {"arn:aws:iam::123456789012:user/uploader"}, "RoleSessionName": {"s1"}}, 0, ErrAccessDenied},
+		{url.Values{"RoleArn": {"downloader"}, "RoleSessionName": {"s1"}}, 0, ErrAccessDenied},
+	}
+	for _, test := range tests {
+		r := &http.Request{Form: test.form}
+		role, _, duration, errCode := iam.sessionParameters(r)
+		if errCode != test.expected {
+			t.Errorf("%v: got %v, expecting %v", test.form, errCode, test.expected)
+			continue
+		}
+		if errCode == ErrNone && (role.Name != "uploader" || duration != test.duration) {
+			t.Errorf("%v: role %s duration %v", test.form, role.Name, duration)
+		}
+	}
+}
+
+func TestVerifyWebIdentityToken(t *testing.T) {
+
+	iam := newTestStsIam()
+	role := iam.lookupRole("uploader")
+
+	sign := func(key string, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		token    string
+		trusted  bool
+		expected ErrorCode
+	}{
+		{sign("provider key", jwt.MapClaims{"iss": "https://accounts.example.com", "sub": "user1", "aud": "seaweedfs", "exp": exp}), true, ErrNone},
+		{sign("provider key", jwt.MapClaims{"iss": "https://accounts.example.com", "sub": "user1", "aud": []string{"other", "seaweedfs"}, "exp": exp}), true, ErrNone},
+		{sign("provider key", jwt.MapClaims{"iss": "https://accounts.example.com", "sub": "user1", "aud": "other", "exp": exp}), false, ErrNone},
+		{sign("provider key", jwt.MapClaims{"iss": "https://accounts.example.com", "sub": "user1", "aud": "seaweedfs"}), false, ErrInvalidIdentityToken},
+		{sign("provider key", jwt.MapClaims{"iss": "https://accounts.example.com", "sub": "user1", "aud": "seaweedfs", "exp": time.Now().Add(-time.Minute).Unix()}), false, ErrExpiredToken},
+		{sign("provider key", jwt.MapClaims{"iss": "https://other.example.com", "sub": "user1", "aud": "seaweedfs", "exp": exp}), false, ErrInvalidIdentityToken},
+		{sign("wrong key", jwt.MapClaims{"iss": "https://accounts.example.com", "sub": "user1", "aud": "seaweedfs", "exp": exp}), false, ErrInvalidIdentityToken},
+		{"not a token", false, ErrInvalidIdentityToken},
+	}
+	for i, test := range tests {
+		identity, errCode := iam.verifyWebIdentityToken(test.token)
+		if errCode != test.expected {
+			t.Errorf("token %d: got %v, expecting %v", i, errCode, test.expected)
+			continue
+		}
+		if errCode == ErrNone && role.trustsWebIdentity(identity) != test.trusted {
+			t.Errorf("token %d: %+v trusted %v", i, identity, !test.trusted)
+		}
+	}
+}
+
+type testOidcProvider struct {
+	sync.Mutex
+	*httptest.Server
+	keys     []map[string]string
+	jwksGets int
+}
+
+func newTestOidcProvider() *testOidcProvider {
+	provider := &testOidcProvider{}
+	provider.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provider.Lock()
+		defer provider.Unlock()
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{"issuer": provider.URL, "jwks_uri": provider.URL + "/keys"})
+		case "/keys":
+			provider.jwksGets++
+			json.NewEncoder(w).Encode(map[string]interface{}{"keys": provider.keys})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return provider
+}
+
+func (provider *testOidcProvider) setKeys(keys ...map[string]string) {
+	provider.Lock()
+	defer provider.Unlock()
+	provider.keys = keys
+}
+
+func (provider *testOidcProvider) fetches() int {
+	provider.Lock()
+	defer provider.Unlock()
+	return provider.jwksGets
+}
+
+func rsaJwk(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "use": "sig", "kid": kid,
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJwk(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		"y": base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+}
+
+func TestWebIdentityJwks(t *testing.T) {
+
+	provider := newTestOidcProvider()
+	defer provider.Close()
+
+	rsaKey1, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaKey2, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	provider.setKeys(rsaJwk("key1", &rsaKey1.PublicKey), ecJwk("key3", &ecKey.PublicKey))
+
+	iam := &IdentityAccessManagement{
+		roles: []*Role{newRole(&iam_pb.Role{
+			Name: "admins",
+			TrustedWebIdentities: []*iam_pb.WebIdentityTrust{{
+				Issuer: provider.URL,
+				Conditions: []*iam_pb.WebIdentityClaimCondition{
+					{Claim: "groups", Values: []string{"admin"}},
+					{Claim: "email_verified_at", Values: []string{"*"}},
+				},
+			}},
+		})},
+		webIdentityProviders: []*iam_pb.WebIdentityProvider{
+			{Issuer: provider.URL, ClientIds: []string{"seaweedfs"}},
+		},
+	}
+	role := iam.lookupRole("admins")
+
+	sign := func(method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{"iss": provider.URL, "sub": "user1", "aud": "seaweedfs", "exp": time.Now().Add(time.Hour).Unix(),
+			"groups": []string{"dev", "admin"}, "email_verified_at": 1}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		token    string
+		trusted  bool
+		expected ErrorCode
+	}{
+		{sign(jwt.SigningMethodRS256, "key1", rsaKey1, claims(nil)), true, ErrNone},
+		{sign(jwt.SigningMethodES256, "key3", ecKey, claims(nil)), true, ErrNone},
+		{sign(jwt.SigningMethodRS256, "key1", rsaKey1, claims(jwt.MapClaims{"groups": "dev"})), false, ErrNone},
+		{sign(jwt.SigningMethodRS256, "key1", rsaKey1, claims(jwt.MapClaims{"email_verified_at": nil})), true, ErrNone},
+		{sign(jwt.SigningMethodRS256, "key1", rsaKey1, claims(jwt.MapClaims{"aud": "other"})), false, ErrInvalidIdentityToken},
+		{sign(jwt.SigningMethodRS256, "key1", rsaKey1, claims(jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()})), false, ErrInvalidIdentityToken},
+		{sign(jwt.SigningMethodRS256, "key1", rsaKey1, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})), false, ErrExpiredToken},
+		{sign(jwt.SigningMethodRS256, "key1", rsaKey2, claims(nil)), false, ErrInvalidIdentityToken},
+		{sign(jwt.SigningMethodRS256, "key3", rsaKey1, claims(nil)), false, ErrInvalidIdentityToken},
+		{sign(jwt.SigningMethodHS256, "key1", []byte("key1"), claims(nil)), false, ErrInvalidIdentityToken},
+	}
+	for i, test := range tests {
+		identity, errCode := iam.verifyWebIdentityToken(test.token)
+		if errCode != test.expected {
+			t.Errorf("token %d: got %v, expecting %v", i, errCode, test.expected)
+			continue
+		}
+		if errCode == ErrNone && role.trustsWebIdentity(identity) != test.trusted {
+			t.Errorf("token %d: %+v trusted %v", i, identity, !test.trusted)
+		}
+	}
+	if fetches := provider.fetches(); fetches != 1 {
+		t.Errorf("fetched the keys %d times", fetches)
+	}
+
+	// the provider rotates its keys, the unknown key refreshes the keys once in a while only
+	provider.setKeys(rsaJwk("key2", &rsaKey2.PublicKey))
+	rotated := sign(jwt.SigningMethodRS256, "key2", rsaKey2, claims(nil))
+	if _, errCode := iam.verifyWebIdentityToken(rotated); errCode != ErrInvalidIdentityToken || provider.fetches() != 1 {
+		t.Errorf("refreshed before the min refresh interval: %v", errCode)
+	}
+	iam.jwksCaches[provider.URL].attemptedAt = time.Now().Add(-jwksMinRefreshInterval)
+	if _, errCode := iam.verifyWebIdentityToken(rotated); errCode != ErrNone || provider.fetches() != 2 {
+		t.Errorf("rotated key: %v", errCode)
+	}
+	if _, errCode := iam.verifyWebIdentityToken(tests[0].token); errCode != ErrInvalidIdentityToken {
+		t.Errorf("removed key: %v", errCode)
+	}
+
+	// the cached keys are used while the provider is down
+	provider.Close()
+	iam.jwksCaches[provider.URL].fetchedAt = time.Now().Add(-2 * jwksDefaultRefreshInterval)
+	iam.jwksCaches[provider.URL].attemptedAt = time.Time{}
+	if _, errCode := iam.verifyWebIdentityToken(rotated); errCode != ErrNone {
+		t.Errorf("provider down: %v", errCode)
+	}
+}
+
+func TestWebIdentityJwksWithoutClientIds(t *testing.T) {
+
+	provider := newTestOidcProvider()
+	defer provider.Close()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	provider.setKeys(rsaJwk("key1", &rsaKey.PublicKey))
+
+	iam := &IdentityAccessManagement{
+		webIdentityProviders: []*iam_pb.WebIdentityProvider{{Issuer: provider.URL}},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"iss": provider.URL, "sub": "user1", "aud": "any", "exp": time.Now().Add(time.Hour).Unix()})
+	token.Header["kid"] = "key1"
+	signed, _ := token.SignedString(rsaKey)
+	if _, errCode := iam.verifyWebIdentityToken(signed); errCode != ErrInvalidIdentityToken {
+		t.Errorf("token of a jwks provider without client ids: %v", errCode)
+	}
+}
+
+func TestJwksFetchOutsideLock(t *testing.T) {
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	entered, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{rsaJwk("key2", &rsaKey.PublicKey)}})
+	}))
+	defer server.Close()
+
+	cache := &jwksCache{
+		provider: &iam_pb.WebIdentityProvider{Issuer: server.URL, JwksUri: server.URL + "/keys", ClientIds: []string{"seaweedfs"}},
+		keys:     map[string]interface{}{"key1": &rsaKey.PublicKey},
+	}
+	fetched := make(chan error, 1)
+	go func() {
+		_, err := cache.key("key2")
+		fetched <- err
+	}()
+	<-entered
+
+	// the cached keys are served during the fetch
+	if _, err := cache.key("key1"); err != nil {
+		t.Errorf("cached key during the fetch: %v", err)
+	}
+	// the new keys are waited for
+	waited := make(chan error, 1)
+	go func() {
+		_, err := cache.key("key2")
+		waited <- err
+	}()
+	close(release)
+	if err := <-fetched; err != nil {
+		t.Errorf("fetch the new key: %v", err)
+	}
+	if err := <-waited; err != nil {
+		t.Errorf("wait for the new key: %v", err)
+	}
+}
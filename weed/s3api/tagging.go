@@ -0,0 +1,130 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/object-tagging.html
+
+const (
+	AmzTagging          = "X-Amz-Tagging"
+	AmzTaggingCount     = "X-Amz-Tagging-Count"
+	AmzTaggingDirective = "X-Amz-Tagging-Directive"
+
+	maxObjectTags     = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+type Tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	TagSet  []Tag    `xml:"TagSet>Tag"`
+}
+
+// objectTags maps the tag keys to the tag values
+type objectTags map[string]string
+
+func (tagging *Tagging) toObjectTags() (objectTags, ErrorCode) {
+	tags := make(objectTags)
+	for _, tag := range tagging.TagSet {
+		if _, found := tags[tag.Key]; found {
+			return nil, ErrInvalidTag
+		}
+		tags[tag.Key] = tag.Value
+	}
+	return tags, tags.validate()
+}
+
+// parseTaggingHeader parses the url encoded tags of x-amz-tagging, e.g. "key1=value1&key2=value2"
+func parseTaggingHeader(value string) (objectTags, ErrorCode) {
+	values, err := url.ParseQuery(value)
+	if err != nil {
+		return nil, ErrInvalidTag
+	}
+	tags := make(objectTags)
+	for key, v := range values {
+		if len(v) != 1 {
+			return nil, ErrInvalidTag
+		}
+		tags[key] = v[0]
+	}
+	return tags, tags.validate()
+}
+
+func (tags objectTags) validate() ErrorCode {
+	if len(tags) > maxObjectTags {
+		return ErrInvalidTag
+	}
+	for key, value := range tags {
+		if key == "" || utf8.RuneCountInString(key) > maxTagKeyLength || utf8.RuneCountInString(value) > maxTagValueLength {
+			return ErrInvalidTag
+		}
+		if strings.HasPrefix(key, "aws:") {
+			return ErrInvalidTag
+		}
+	}
+	return ErrNone
+}
+
+func (tags objectTags) sortedKeys() []string {
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (tags objectTags) toTagging() *Tagging {
+	tagging := &Tagging{TagSet: []Tag{}}
+	for _, key := range tags.sortedKeys() {
+		tagging.TagSet = append(tagging.TagSet, Tag{Key: key, Value: tags[key]})
+	}
+	return tagging
+}
+
+// encode formats the tags as the x-amz-tagging header
+func (tags objectTags) encode() string {
+	values := make(url.Values)
+	for key, value := range tags {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
+
+// saveTo replaces all the tags kept in the extended attributes
+func (tags objectTags) saveTo(extended map[string][]byte) {
+	for key := range extended {
+		if strings.HasPrefix(key, AmzObjectTaggingPrefix) {
+			delete(extended, key)
+		}
+	}
+	for key, value := range tags {
+		extended[AmzObjectTaggingPrefix+key] = []byte(value)
+	}
+}
+
+func loadObjectTags(extended map[string][]byte) objectTags {
+	tags := make(objectTags)
+	for key, value := range extended {
+		if strings.HasPrefix(key, AmzObjectTaggingPrefix) {
+			tags[key[len(AmzObjectTaggingPrefix):]] = string(value)
+		}
+	}
+	return tags
+}
+
+// prepareObjectTags returns the tags of the x-amz-tagging header, or nil if not set
+func prepareObjectTags(r *http.Request) (objectTags, ErrorCode) {
+	value, found := r.Header[AmzTagging]
+	if !found {
+		return nil, ErrNone
+	}
+	return parseTaggingHeader(strings.Join(value, "&"))
+}
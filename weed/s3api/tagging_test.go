@@ -0,0 +1,126 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestParseTaggingHeader(t *testing.T) {
+
+	tags, errCode := parseTaggingHeader("key1=value1&a%20b=c%26d%3De&empty=")
+	if errCode != ErrNone {
+		t.Fatalf("parse: %v", errCode)
+	}
+	if len(tags) != 3 || tags["key1"] != "value1" || tags["a b"] != "c&d=e" || tags["empty"] != "" {
+		t.Errorf("tags %+v", tags)
+	}
+
+	parsed, errCode := parseTaggingHeader(tags.encode())
+	if errCode != ErrNone || len(parsed) != len(tags) {
+		t.Fatalf("parse encoded %s: %v", tags.encode(), errCode)
+	}
+	for key, value := range tags {
+		if parsed[key] != value {
+			t.Errorf("tag %s: %s, expecting %s", key, parsed[key], value)
+		}
+	}
+
+	invalid := []string{
+		"key1=a&key1=b",
+		"key1=%zz",
+		"aws:key=v",
+		"=value",
+		strings.Repeat("k", maxTagKeyLength+1) + "=v",
+		"k=" + strings.Repeat("v", maxTagValueLength+1),
+		"k1=1&k2=2&k3=3&k4=4&k5=5&k6=6&k7=7&k8=8&k9=9&k10=10&k11=11",
+	}
+	for _, value := range invalid {
+		if _, errCode := parseTaggingHeader(value); errCode != ErrInvalidTag {
+			t.Errorf("header %s: got %v, expecting %v", value, errCode, ErrInvalidTag)
+		}
+	}
+}
+
+func TestTaggingToObjectTags(t *testing.T) {
+
+	tagging := &Tagging{TagSet: []Tag{{Key: "b", Value: "2"}, {Key: "a", Value: "1"}}}
+	tags, errCode := tagging.toObjectTags()
+	if errCode != ErrNone || len(tags) != 2 {
+		t.Fatalf("tags %+v: %v", tags, errCode)
+	}
+	if sorted := tags.toTagging().TagSet; sorted[0].Key != "a" || sorted[1].Key != "b" {
+		t.Errorf("tag set %+v", sorted)
+	}
+
+	tagging.TagSet = append(tagging.TagSet, Tag{Key: "a", Value: "3"})
+	if _, errCode := tagging.toObjectTags(); errCode != ErrInvalidTag {
+		t.Errorf("duplicated key: got %v", errCode)
+	}
+}
+
+func TestObjectTagsSaveTo(t *testing.T) {
+
+	extended := map[string][]byte{
+		AmzObjectTaggingPrefix + "old": []byte("1"),
+		"X-Amz-Meta-Color":             []byte("red"),
+	}
+	objectTags{"new": "2"}.saveTo(extended)
+
+	tags := loadObjectTags(extended)
+	if len(tags) != 1 || tags["new"] != "2" {
+		t.Errorf("tags %+v", tags)
+	}
+	if string(extended["X-Amz-Meta-Color"]) != "red" {
+		t.Errorf("lost the metadata: %+v", extended)
+	}
+
+	r := &http.Request{Header: make(http.Header)}
+	if tags, errCode := prepareObjectTags(r); tags != nil || errCode != ErrNone {
+		t.Errorf("no header: %+v %v", tags, errCode)
+	}
+	r.Header.Set(AmzTagging, "")
+	if tags, errCode := prepareObjectTags(r); tags == nil || len(tags) != 0 || errCode != ErrNone {
+		t.Errorf("empty header: %+v %v", tags, errCode)
+	}
+}
+
+func TestCopyObjectTags(t *testing.T) {
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket/object": {Name: "object", Extended: map[string][]byte{
+			AmzObjectTaggingPrefix + "color": []byte("blue"),
+		}},
+	})
+	defer stop()
+
+	tests := []struct {
+		directive string
+		tags      objectTags
+		errCode   ErrorCode
+	}{
+		{"", objectTags{"color": "blue"}, ErrNone},
+		{"COPY", objectTags{"color": "blue"}, ErrNone},
+		{"REPLACE", objectTags{"color": "red", "size": "large"}, ErrNone},
+		{"MOVE", nil, ErrInvalidTaggingDirective},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("PUT", "/bucket/copy", nil)
+		r.Header.Set(AmzTagging, "color=red&size=large")
+		if test.directive != "" {
+			r.Header.Set(AmzTaggingDirective, test.directive)
+		}
+		tags, errCode := s3a.copyObjectTags(r, "bucket", "/object")
+		if errCode != test.errCode {
+			t.Errorf("directive %q: %v, expecting %v", test.directive, errCode, test.errCode)
+			continue
+		}
+		if !reflect.DeepEqual(tags, test.tags) {
+			t.Errorf("directive %q: tags %v, expecting %v", test.directive, tags, test.tags)
+		}
+	}
+}
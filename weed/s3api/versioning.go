@@ -0,0 +1,437 @@
+package s3api
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/Versioning.html
+//
+// The current version of an object stays at its usual path, so reads, range reads and listings work as before.
+// Noncurrent versions and delete markers of "<bucket>/<key>" are moved under "<bucket>/.versions/<key>/",
+// one entry per version, named so that the newest version is listed first.
+
+const (
+	VersioningEnabled   = "Enabled"
+	VersioningSuspended = "Suspended"
+
+	// response headers, also used as the extended attribute keys on the object entry
+	AmzVersionId    = "X-Amz-Version-Id"
+	AmzDeleteMarker = "X-Amz-Delete-Marker"
+
+	// extended attribute key on the bucket entry
+	bucketVersioningKey = "s3-versioning"
+
+	versionsFolder = ".versions"
+	nullVersionId  = "null"
+)
+
+// newVersionId returns a version id that sorts before all the version ids generated earlier
+func newVersionId(now time.Time) string {
+	return fmt.Sprintf("%016x%08x", math.MaxInt64-now.UnixNano(), rand.Uint32())
+}
+
+// versionFileName is the entry name of a version under the versions folder
+func versionFileName(versionId string, entry *filer_pb.Entry) string {
+	if versionId != nullVersionId {
+		return versionId
+	}
+	return fmt.Sprintf("%016x%s", math.MaxInt64-entry.Attributes.Mtime*int64(time.Second), nullVersionId)
+}
+
+// getVersionId returns the version id of an object entry, objects written without versioning are the "null" version
+func getVersionId(entry *filer_pb.Entry) string {
+	if versionId, found := entry.Extended[AmzVersionId]; found {
+		return string(versionId)
+	}
+	return nullVersionId
+}
+
+func isDeleteMarker(entry *filer_pb.Entry) bool {
+	return string(entry.Extended[AmzDeleteMarker]) == "true"
+}
+
+// isVersionKept tells whether the current version is archived, instead of destroyed, when it is replaced or deleted
+func isVersionKept(status string, entry *filer_pb.Entry) bool {
+	return status == VersioningEnabled || (status == VersioningSuspended && getVersionId(entry) != nullVersionId)
+}
+
+func setVersionHeaders(w http.ResponseWriter, versionId string, deleteMarker bool) {
+	if versionId != "" {
+		w.Header().Set(AmzVersionId, versionId)
+	}
+	if deleteMarker {
+		w.Header().Set(AmzDeleteMarker, "true")
+	}
+}
+
+func (s3a *S3ApiServer) getBucketVersioning(bucket string) (status string, code ErrorCode) {
+
+	entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+	if err != nil {
+		glog.Errorf("lookup bucket %s: %v", bucket, err)
+		return "", ErrInternalError
+	}
+	if entry == nil {
+		return "", ErrNoSuchBucket
+	}
+
+	return string(entry.Extended[bucketVersioningKey]), ErrNone
+}
+
+func (s3a *S3ApiServer) genVersionsFolder(bucket, object string) string {
+	return fmt.Sprintf("%s/%s/%s%s", s3a.option.BucketsPath, bucket, versionsFolder, object)
+}
+
+// listVersions returns the noncurrent versions and delete markers of an object, newest first
+func (s3a *S3ApiServer) listVersions(bucket, object string) (versions []*filer_pb.Entry, err error) {
+
+	err = filer_pb.ReadDirAllEntries(s3a, util.FullPath(s3a.genVersionsFolder(bucket, object)), "", func(entry *filer_pb.Entry, isLast bool) error {
+		if !entry.IsDirectory {
+			versions = append(versions, entry)
+		}
+		return nil
+	})
+
+	return
+}
+
+// findVersion locates one version of an object, either the current one or one under the versions folder
+func (s3a *S3ApiServer) findVersion(bucket, object, versionId string) (dir string, entry *filer_pb.Entry, err error) {
+
+	dir, _, entry, err = s3a.objectEntry(bucket, object)
+	if err != nil {
+		return
+	}
+	if entry != nil && !entry.IsDirectory && getVersionId(entry) == versionId {
+		return
+	}
+
+	dir = s3a.genVersionsFolder(bucket, object)
+	if versionId != nullVersionId {
+		entry, err = s3a.getEntry(dir, versionId)
+		return
+	}
+
+	versions, err := s3a.listVersions(bucket, object)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, version := range versions {
+		if getVersionId(version) == nullVersionId {
+			return dir, version, nil
+		}
+	}
+
+	return dir, nil, nil
+}
+
+// objectVersionEntry looks up the current object, or the version of the object if versionId is not empty
+func (s3a *S3ApiServer) objectVersionEntry(bucket, object, versionId string) (dir string, entry *filer_pb.Entry, code ErrorCode) {
+
+	var err error
+	if versionId != "" {
+		dir, entry, err = s3a.findVersion(bucket, object, versionId)
+	} else {
+		dir, _, entry, err = s3a.objectEntry(bucket, object)
+	}
+	if err != nil {
+		glog.Errorf("lookup object %s%s version %s: %v", bucket, object, versionId, err)
+		return "", nil, ErrInternalError
+	}
+	if entry == nil && versionId != "" {
+		return "", nil, ErrNoSuchVersion
+	}
+	if entry == nil || entry.IsDirectory || isDeleteMarker(entry) {
+		return "", nil, ErrNoSuchKey
+	}
+	return dir, entry, ErrNone
+}
+
+// keepCurrentVersion prepares the object for a new current version or a delete marker.
+// The current version is moved into the versions folder, except the "null" version of a suspended bucket,
+// which is replaced in place. A suspended bucket only keeps one "null" version.
+func (s3a *S3ApiServer) keepCurrentVersion(bucket, object, status string) error {
+
+	dir, name, current, err := s3a.objectEntry(bucket, object)
+	if err != nil {
+		return err
+	}
+
+	if status == VersioningSuspended {
+		if err = s3a.removeNoncurrentNullVersion(bucket, object); err != nil {
+			return err
+		}
+	}
+
+	return s3a.archiveCurrentVersion(bucket, object, status, dir, name, current)
+}
+
+// archiveCurrentVersion moves the current version into the versions folder, if the version is kept
+func (s3a *S3ApiServer) archiveCurrentVersion(bucket, object, status, dir, name string, current *filer_pb.Entry) error {
+	if current == nil || current.IsDirectory || !isVersionKept(status, current) {
+		return nil
+	}
+	return s3a.rename(dir, name, s3a.genVersionsFolder(bucket, object), versionFileName(getVersionId(current), current))
+}
+
+// removeNoncurrentNullVersion removes the "null" version under the versions folder, which a new "null" version replaces
+func (s3a *S3ApiServer) removeNoncurrentNullVersion(bucket, object string) error {
+	versions, err := s3a.listVersions(bucket, object)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if getVersionId(version) == nullVersionId {
+			return s3a.rm(s3a.genVersionsFolder(bucket, object), version.Name, true, false)
+		}
+	}
+	return nil
+}
+
+// promoteLatestVersion makes the newest noncurrent version current again, unless the newest one is a delete marker
+func (s3a *S3ApiServer) promoteLatestVersion(bucket, object string) error {
+
+	dir, name, current, err := s3a.objectEntry(bucket, object)
+	if err != nil || current != nil {
+		return err
+	}
+
+	versions, err := s3a.listVersions(bucket, object)
+	if err != nil || len(versions) == 0 || isDeleteMarker(versions[0]) {
+		return err
+	}
+
+	return s3a.rename(s3a.genVersionsFolder(bucket, object), versions[0].Name, dir, name)
+}
+
+// prepareObjectVersion archives the current version before a write, and returns the version id of the new object.
+// The write must be followed by finishObjectVersion, which restores the archived version if the write fails.
+func (s3a *S3ApiServer) prepareObjectVersion(bucket, object string) (versionId string, code ErrorCode) {
+
+	status, code := s3a.getBucketVersioning(bucket)
+	if code == ErrNoSuchBucket || status == "" {
+		return "", ErrNone
+	}
+	if code != ErrNone {
+		return "", code
+	}
+
+	// the noncurrent "null" version of a suspended bucket is only removed after the new "null" version is written
+	dir, name, current, err := s3a.objectEntry(bucket, object)
+	if err == nil {
+		err = s3a.archiveCurrentVersion(bucket, object, status, dir, name, current)
+	}
+	if err != nil {
+		glog.Errorf("keep current version %s%s: %v", bucket, object, err)
+		return "", ErrInternalError
+	}
+
+	if status == VersioningEnabled {
+		return newVersionId(time.Now()), ErrNone
+	}
+	return nullVersionId, ErrNone
+}
+
+// finishObjectVersion completes prepareObjectVersion after the write. A failed write makes the archived version current again,
+// and a written "null" version replaces the noncurrent "null" version.
+func (s3a *S3ApiServer) finishObjectVersion(bucket, object, versionId string, written bool) {
+	if versionId == "" {
+		return
+	}
+	if !written {
+		if err := s3a.promoteLatestVersion(bucket, object); err != nil {
+			glog.Errorf("restore current version %s%s: %v", bucket, object, err)
+		}
+		return
+	}
+	if versionId == nullVersionId {
+		if err := s3a.removeNoncurrentNullVersion(bucket, object); err != nil {
+			glog.Errorf("remove noncurrent null version %s%s: %v", bucket, object, err)
+		}
+	}
+}
+
+// deleteVersionedObject removes the specified version permanently,
+// or adds a delete marker as the current version when no version is specified.
+func (s3a *S3ApiServer) deleteVersionedObject(bucket, object, versionId, status string, bypassGovernance bool) (deletedVersionId string, deleteMarker bool, code ErrorCode) {
+
+	now := time.Now()
+
+	if versionId != "" {
+		dir, entry, err := s3a.findVersion(bucket, object, versionId)
+		if err != nil {
+			glog.Errorf("lookup object %s%s version %s: %v", bucket, object, versionId, err)
+			return "", false, ErrInternalError
+		}
+		if entry == nil {
+			return versionId, false, ErrNone
+		}
+		deleteMarker = isDeleteMarker(entry)
+		if !deleteMarker {
+			if code = loadObjectLock(entry.Extended).checkRemoval(now, bypassGovernance); code != ErrNone {
+				return "", false, code
+			}
+		}
+		if err = s3a.rm(dir, entry.Name, true, false); err != nil {
+			glog.Errorf("delete object %s%s version %s: %v", bucket, object, versionId, err)
+			return "", false, ErrInternalError
+		}
+		if err = s3a.promoteLatestVersion(bucket, object); err != nil {
+			glog.Errorf("promote latest version %s%s: %v", bucket, object, err)
+			return "", false, ErrInternalError
+		}
+		return versionId, deleteMarker, ErrNone
+	}
+
+	if status == VersioningSuspended {
+		dir, name, current, err := s3a.objectEntry(bucket, object)
+		if err != nil {
+			glog.Errorf("lookup object %s%s: %v", bucket, object, err)
+			return "", false, ErrInternalError
+		}
+		if current != nil && !current.IsDirectory && !isVersionKept(status, current) {
+			if code = loadObjectLock(current.Extended).checkRemoval(now, bypassGovernance); code != ErrNone {
+				return "", false, code
+			}
+			if err = s3a.rm(dir, name, true, false); err != nil {
+				glog.Errorf("delete object %s%s: %v", bucket, object, err)
+				return "", false, ErrInternalError
+			}
+		}
+	}
+
+	if err := s3a.keepCurrentVersion(bucket, object, status); err != nil {
+		glog.Errorf("keep current version %s%s: %v", bucket, object, err)
+		return "", false, ErrInternalError
+	}
+
+	deletedVersionId = nullVersionId
+	if status == VersioningEnabled {
+		deletedVersionId = newVersionId(now)
+	}
+
+	marker := &filer_pb.Entry{
+		Attributes: &filer_pb.FuseAttributes{
+			Mtime:    now.Unix(),
+			Crtime:   now.Unix(),
+			FileMode: uint32(0770),
+			Uid:      filer_pb.OS_UID,
+			Gid:      filer_pb.OS_GID,
+		},
+		Extended: map[string][]byte{
+			AmzVersionId:    []byte(deletedVersionId),
+			AmzDeleteMarker: []byte("true"),
+		},
+	}
+	marker.Name = versionFileName(deletedVersionId, marker)
+
+	if err := s3a.createEntry(s3a.genVersionsFolder(bucket, object), marker); err != nil {
+		glog.Errorf("create delete marker %s%s: %v", bucket, object, err)
+		return "", false, ErrInternalError
+	}
+
+	return deletedVersionId, true, ErrNone
+}
+
+// objectVersionUrl returns the filer url to read one version of an object
+func (s3a *S3ApiServer) objectVersionUrl(w http.ResponseWriter, bucket, object, versionId string) (destUrl string, code ErrorCode) {
+
+	dir, entry, err := s3a.findVersion(bucket, object, versionId)
+	if err != nil {
+		glog.Errorf("lookup object %s%s version %s: %v", bucket, object, versionId, err)
+		return "", ErrInternalError
+	}
+	if entry == nil {
+		return "", ErrNoSuchVersion
+	}
+
+	setVersionHeaders(w, versionId, isDeleteMarker(entry))
+	if isDeleteMarker(entry) {
+		return "", ErrMethodNotAllowed
+	}
+
+	return fmt.Sprintf("http://%s%s/%s", s3a.option.Filer, dir, entry.Name), ErrNone
+}
+
+// objectAttributes are applied to a newly written object
+type objectAttributes struct {
+	lock         *objectLock
+	versionId    string
+	encryption   *objectEncryption
+	checksum     *objectChecksum
+	tags         objectTags
+	acl          string
+	storageClass string
+	parts        objectParts
+	metadata     *objectMetadata
+	// the etag if it is not the md5 of the saved data
+	etag string
+}
+
+func (s3a *S3ApiServer) saveObjectAttributes(bucket, object string, attributes objectAttributes) error {
+
+	lock, versionId := attributes.lock, attributes.versionId
+	replicated := s3a.hasBucketReplication(bucket)
+	if lock == nil && (versionId == "" || versionId == nullVersionId) && attributes.encryption == nil && attributes.checksum == nil && attributes.tags == nil && attributes.parts == nil && attributes.metadata.isEmpty() && attributes.acl == "" && attributes.etag == "" && (attributes.storageClass == "" || attributes.storageClass == StorageClassStandard) && !replicated {
+		return nil
+	}
+
+	dir, _, entry, err := s3a.objectEntry(bucket, object)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return filer_pb.ErrNotFound
+	}
+	if entry.Extended == nil {
+		entry.Extended = make(map[string][]byte)
+	}
+	if lock != nil {
+		lock.saveTo(entry.Extended)
+	}
+	if attributes.encryption != nil {
+		attributes.encryption.saveTo(entry.Extended)
+	}
+	if attributes.checksum != nil {
+		attributes.checksum.saveTo(entry.Extended)
+	}
+	if attributes.tags != nil {
+		attributes.tags.saveTo(entry.Extended)
+	}
+	if attributes.acl != "" {
+		entry.Extended[objectAclKey] = []byte(attributes.acl)
+	}
+	if attributes.parts != nil {
+		attributes.parts.saveTo(entry.Extended)
+	}
+	if !attributes.metadata.isEmpty() {
+		attributes.metadata.saveTo(entry)
+	}
+	if attributes.etag != "" {
+		entry.Extended[filer2.ETagKey] = []byte(attributes.etag)
+	}
+	if attributes.storageClass != "" && attributes.storageClass != StorageClassStandard {
+		entry.Extended[AmzStorageClass] = []byte(attributes.storageClass)
+	} else {
+		delete(entry.Extended, AmzStorageClass)
+	}
+	if versionId != "" && versionId != nullVersionId {
+		entry.Extended[AmzVersionId] = []byte(versionId)
+	} else {
+		delete(entry.Extended, AmzVersionId)
+	}
+	if replicated {
+		s3a.setReplicationPending(bucket, object, entry.Extended)
+	}
+
+	return s3a.updateEntry(dir, entry)
+}
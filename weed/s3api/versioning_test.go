@@ -0,0 +1,100 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestVersionFileNameOrder(t *testing.T) {
+
+	now := time.Now()
+
+	older := newVersionId(now.Add(-time.Second))
+	newer := newVersionId(now)
+	nullVersion := versionFileName(nullVersionId, &filer_pb.Entry{
+		Attributes: &filer_pb.FuseAttributes{Mtime: now.Add(-time.Hour).Unix()},
+	})
+
+	names := []string{nullVersion, older, newer}
+	sort.Strings(names)
+
+	expected := []string{newer, older, nullVersion}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("unexpected order %v, expecting %v", names, expected)
+			break
+		}
+	}
+
+	if versionFileName(newer, nil) != newer {
+		t.Errorf("version file name should be the version id")
+	}
+
+}
+
+func TestListVersionsResult(t *testing.T) {
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<ListVersionsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Name>example-bucket</Name><Prefix></Prefix><KeyMarker></KeyMarker><VersionIdMarker></VersionIdMarker><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated><DeleteMarker><Key>example-object</Key><VersionId>null</VersionId><IsLatest>true</IsLatest><Owner><ID></ID></Owner><LastModified>2020-06-01T00:00:00Z</LastModified></DeleteMarker></ListVersionsResult>`
+
+	response := ListVersionsResult{
+		Name:    "example-bucket",
+		MaxKeys: 1000,
+		DeleteMarker: []DeleteMarkerEntry{
+			{
+				Key:          "example-object",
+				VersionId:    nullVersionId,
+				IsLatest:     true,
+				LastModified: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	encoded := string(encodeResponse(response))
+	if encoded != expected {
+		t.Errorf("unexpected output: %s\nexpecting:%s", encoded, expected)
+	}
+
+}
+
+func TestFailedWriteKeepsCurrentVersion(t *testing.T) {
+
+	entries := map[util.FullPath]*filer_pb.Entry{
+		"/buckets/bucket": {Name: "bucket", IsDirectory: true, Extended: map[string][]byte{
+			bucketVersioningKey: []byte(VersioningEnabled),
+		}},
+		"/buckets/bucket/object": {Name: "object", Extended: map[string][]byte{
+			AmzVersionId: []byte("v1"),
+		}},
+	}
+	s3a, stop := startFakeFiler(t, entries)
+	defer stop()
+	s3a.storageClasses = &storageClassConfig{}
+
+	filer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer filer.Close()
+	s3a.option.Filer = strings.TrimPrefix(filer.URL, "http://")
+
+	r := httptest.NewRequest("PUT", "/bucket/object", strings.NewReader("new content"))
+	if _, code := s3a.putObject(r, "bucket", "/object", r.Body); code == ErrNone {
+		t.Fatalf("put object to a failing filer")
+	}
+
+	current, found := entries["/buckets/bucket/object"]
+	if !found || getVersionId(current) != "v1" {
+		t.Errorf("current version is not restored: %v", current)
+	}
+	versions, err := s3a.listVersions("bucket", "/object")
+	if err != nil || len(versions) != 0 {
+		t.Errorf("noncurrent versions %v: %v", versions, err)
+	}
+}
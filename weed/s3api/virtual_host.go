@@ -0,0 +1,82 @@
+package s3api
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// The buckets are addressed in the host names as {bucket}.{domain} by the virtual-hosted-style requests,
+// or in the paths as {domain}/{bucket} by the path-style requests. The requests to the host names not
+// under any configured domain are path-style. The request paths are routed as they are, so the signatures
+// are verified with the paths signed by the clients.
+
+// parseDomainNames splits the comma separated domain names, the longest first to match the most specific domain
+func parseDomainNames(domainNames string) (domains []string) {
+	for _, domain := range strings.Split(domainNames, ",") {
+		domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), "."))
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	sort.SliceStable(domains, func(i, j int) bool {
+		return len(domains[i]) > len(domains[j])
+	})
+	return
+}
+
+// virtualHostBucketPattern matches the bucket names which can be in the host names
+var virtualHostBucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// virtualHostBucket returns the bucket in the host name, or "" if the request is path-style.
+// A host name which is a configured domain itself has no bucket, even if it is under another configured domain.
+func virtualHostBucket(host string, domains []string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	for _, domain := range domains {
+		if host == domain {
+			return ""
+		}
+	}
+	for _, domain := range domains {
+		if strings.HasSuffix(host, "."+domain) {
+			if bucket := strings.TrimSuffix(host, "."+domain); virtualHostBucketPattern.MatchString(bucket) {
+				return bucket
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// matchVirtualHost routes the virtual-hosted-style requests, with the bucket taken from the host name
+func (s3a *S3ApiServer) matchVirtualHost(r *http.Request, match *mux.RouteMatch) bool {
+	bucket := virtualHostBucket(r.Host, s3a.domains)
+	if bucket == "" {
+		return false
+	}
+	if match.Vars == nil {
+		match.Vars = make(map[string]string)
+	}
+	match.Vars["bucket"] = bucket
+	return true
+}
+
+// matchPathStyle routes the path-style requests, so the objects of the virtual-hosted-style requests are not shadowed
+func (s3a *S3ApiServer) matchPathStyle(r *http.Request, match *mux.RouteMatch) bool {
+	return virtualHostBucket(r.Host, s3a.domains) == ""
+}
+
+// objectLocation is the url of the object, in the same style as the request
+func (s3a *S3ApiServer) objectLocation(r *http.Request, bucket, object string) string {
+	if virtualHostBucket(r.Host, s3a.domains) == bucket {
+		return requestProtocol(r) + "://" + r.Host + object
+	}
+	return requestProtocol(r) + "://" + r.Host + "/" + bucket + object
+}
@@ -0,0 +1,128 @@
+package s3api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestVirtualHostBucket(t *testing.T) {
+	domains := parseDomainNames("example.com, s3.example.com,.s3.local.")
+	if !reflect.DeepEqual(domains, []string{"s3.example.com", "example.com", "s3.local"}) {
+		t.Fatalf("parsed domains %v", domains)
+	}
+
+	tests := []struct {
+		host   string
+		bucket string
+	}{
+		{"bucket.s3.example.com", "bucket"},
+		{"bucket.s3.example.com:8333", "bucket"},
+		{"Bucket.S3.Example.com", "bucket"},
+		{"my.bucket.s3.example.com", "my.bucket"},
+		{"bucket.example.com", "bucket"},
+		{"s3.example.com", ""},
+		{"S3.Example.com:8333", ""},
+		{"example.com", ""},
+		{"b.s3.example.com", ""},
+		{"-bucket.s3.example.com", ""},
+		{"bucket_1.s3.example.com", ""},
+		{"bucket.s3.local", "bucket"},
+		{"localhost:8333", ""},
+		{"127.0.0.1:8333", ""},
+		{"bucket.other.com", ""},
+	}
+	for _, test := range tests {
+		if bucket := virtualHostBucket(test.host, domains); bucket != test.bucket {
+			t.Errorf("%s: bucket %q, expecting %q", test.host, bucket, test.bucket)
+		}
+	}
+}
+
+func TestVirtualHostRouting(t *testing.T) {
+	s3a := &S3ApiServer{domains: parseDomainNames("s3.example.com")}
+
+	router := mux.NewRouter()
+	var vars map[string]string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		vars = mux.Vars(r)
+	}
+	router.Methods("GET").Path("/_healthz").MatcherFunc(s3a.matchPathStyle).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars = map[string]string{"health": "true"}
+	})
+	for _, bucket := range []*mux.Router{
+		router.MatcherFunc(s3a.matchVirtualHost).Subrouter(),
+		router.PathPrefix("/{bucket}").Subrouter(),
+	} {
+		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(handler)
+		bucket.Methods("GET").HandlerFunc(handler)
+	}
+
+	tests := []struct {
+		url    string
+		bucket string
+		object string
+	}{
+		{"http://bucket.s3.example.com:8333/dir/key", "bucket", "dir/key"},
+		{"http://bucket.s3.example.com/", "bucket", ""},
+		{"http://s3.example.com/bucket/dir/key", "bucket", "dir/key"},
+		{"http://localhost:8333/bucket/key", "bucket", "key"},
+		{"http://localhost:8333/bucket", "bucket", ""},
+		{"http://localhost:8333/healthz", "healthz", ""},
+		{"http://bucket.s3.example.com/_healthz", "bucket", "_healthz"},
+	}
+	for _, test := range tests {
+		vars = nil
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", test.url, nil))
+		if vars["bucket"] != test.bucket || vars["object"] != test.object {
+			t.Errorf("%s: routed to %v", test.url, vars)
+		}
+	}
+
+	for _, url := range []string{"http://localhost:8333/_healthz", "http://s3.example.com/_healthz"} {
+		vars = nil
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", url, nil))
+		if vars["health"] != "true" {
+			t.Errorf("%s: routed to %v", url, vars)
+		}
+	}
+
+	r := httptest.NewRequest("POST", "http://bucket.s3.example.com/key", nil)
+	if location := s3a.objectLocation(r, "bucket", "/key"); location != "http://bucket.s3.example.com/key" {
+		t.Errorf("virtual-hosted-style location %s", location)
+	}
+	r = httptest.NewRequest("POST", "http://localhost:8333/bucket/key", nil)
+	if location := s3a.objectLocation(r, "bucket", "/key"); location != "http://localhost:8333/bucket/key" {
+		t.Errorf("path-style location %s", location)
+	}
+}
+
+func TestBatchJobRoutesArePathStyle(t *testing.T) {
+	s3a := &S3ApiServer{domains: parseDomainNames("s3.example.com"), iam: &IdentityAccessManagement{}}
+	router := mux.NewRouter().SkipClean(true)
+	s3a.registerRouter(router)
+
+	tests := []struct {
+		url    string
+		bucket string
+		object string
+	}{
+		{"http://s3.example.com/v20180820/jobs", "", ""},
+		{"http://localhost:8333/v20180820/jobs/id", "", ""},
+		{"http://bucket.s3.example.com/v20180820/jobs", "bucket", "v20180820/jobs"},
+		{"http://bucket.s3.example.com/v20180820/jobs/id", "bucket", "v20180820/jobs/id"},
+	}
+	for _, test := range tests {
+		var match mux.RouteMatch
+		if !router.Match(httptest.NewRequest("GET", test.url, nil), &match) {
+			t.Errorf("%s: not routed", test.url)
+			continue
+		}
+		if match.Vars["bucket"] != test.bucket || match.Vars["object"] != test.object {
+			t.Errorf("%s: routed to %v", test.url, match.Vars)
+		}
+	}
+}
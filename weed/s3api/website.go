@@ -0,0 +1,377 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/WebsiteHosting.html
+// The website endpoint is served on its own port. The bucket is the host name without the website
+// domain name suffix, or the whole host name, e.g. for "www.example.com" pointed to the website endpoint.
+
+const (
+	// extended attribute key on the bucket entry
+	bucketWebsiteConfigurationKey = "s3-website-configuration"
+
+	bucketWebsiteCacheTTL = 5 * time.Second
+
+	maxWebsiteRoutingRules = 50
+)
+
+type WebsiteConfiguration struct {
+	XMLName               xml.Name              `xml:"WebsiteConfiguration"`
+	Xmlns                 string                `xml:"xmlns,attr,omitempty"`
+	RedirectAllRequestsTo *WebsiteRedirectAll   `xml:"RedirectAllRequestsTo,omitempty"`
+	IndexDocument         *WebsiteIndexDocument `xml:"IndexDocument,omitempty"`
+	ErrorDocument         *WebsiteErrorDocument `xml:"ErrorDocument,omitempty"`
+	RoutingRules          []WebsiteRoutingRule  `xml:"RoutingRules>RoutingRule,omitempty"`
+}
+
+type WebsiteRedirectAll struct {
+	HostName string `xml:"HostName"`
+	Protocol string `xml:"Protocol,omitempty"`
+}
+
+type WebsiteIndexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+type WebsiteErrorDocument struct {
+	Key string `xml:"Key"`
+}
+
+type WebsiteRoutingRule struct {
+	Condition *WebsiteCondition `xml:"Condition,omitempty"`
+	Redirect  WebsiteRedirect   `xml:"Redirect"`
+}
+
+type WebsiteCondition struct {
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty"`
+	HttpErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+type WebsiteRedirect struct {
+	HostName             string  `xml:"HostName,omitempty"`
+	HttpRedirectCode     string  `xml:"HttpRedirectCode,omitempty"`
+	Protocol             string  `xml:"Protocol,omitempty"`
+	ReplaceKeyPrefixWith *string `xml:"ReplaceKeyPrefixWith"`
+	ReplaceKeyWith       string  `xml:"ReplaceKeyWith,omitempty"`
+}
+
+func isValidWebsiteProtocol(protocol string) bool {
+	return protocol == "" || protocol == "http" || protocol == "https"
+}
+
+func (config *WebsiteConfiguration) validate() ErrorCode {
+	if redirect := config.RedirectAllRequestsTo; redirect != nil {
+		if redirect.HostName == "" || !isValidWebsiteProtocol(redirect.Protocol) {
+			return ErrInvalidWebsiteConfiguration
+		}
+		if config.IndexDocument != nil || config.ErrorDocument != nil || len(config.RoutingRules) > 0 {
+			return ErrInvalidWebsiteConfiguration
+		}
+		return ErrNone
+	}
+	if config.IndexDocument == nil || config.IndexDocument.Suffix == "" || strings.Contains(config.IndexDocument.Suffix, "/") || hasDotSegment(config.IndexDocument.Suffix) {
+		return ErrInvalidWebsiteConfiguration
+	}
+	if config.ErrorDocument != nil && (config.ErrorDocument.Key == "" || hasDotSegment(config.ErrorDocument.Key)) {
+		return ErrInvalidWebsiteConfiguration
+	}
+	if len(config.RoutingRules) > maxWebsiteRoutingRules {
+		return ErrInvalidWebsiteConfiguration
+	}
+	for _, rule := range config.RoutingRules {
+		redirect := rule.Redirect
+		if redirect.ReplaceKeyPrefixWith != nil && redirect.ReplaceKeyWith != "" {
+			return ErrInvalidWebsiteConfiguration
+		}
+		if !isValidWebsiteProtocol(redirect.Protocol) {
+			return ErrInvalidWebsiteConfiguration
+		}
+		if redirect.HttpRedirectCode != "" {
+			if code, err := strconv.Atoi(redirect.HttpRedirectCode); err != nil || code < 300 || code >= 400 {
+				return ErrInvalidWebsiteConfiguration
+			}
+		}
+		if rule.Condition != nil && rule.Condition.HttpErrorCodeReturnedEquals != "" {
+			if code, err := strconv.Atoi(rule.Condition.HttpErrorCodeReturnedEquals); err != nil || code < 400 || code >= 600 {
+				return ErrInvalidWebsiteConfiguration
+			}
+		}
+	}
+	return ErrNone
+}
+
+// matchRoutingRule finds the first rule for the key, for the rules without the error code condition if the status is 0
+func (config *WebsiteConfiguration) matchRoutingRule(key string, status int) *WebsiteRoutingRule {
+	for i, rule := range config.RoutingRules {
+		var prefix, errorCode string
+		if rule.Condition != nil {
+			prefix, errorCode = rule.Condition.KeyPrefixEquals, rule.Condition.HttpErrorCodeReturnedEquals
+		}
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if (status == 0 && errorCode == "") || (status != 0 && errorCode == strconv.Itoa(status)) {
+			return &config.RoutingRules[i]
+		}
+	}
+	return nil
+}
+
+// location is where the key is redirected to, with the status code of the redirect
+func (rule *WebsiteRoutingRule) location(r *http.Request, key string) (string, int) {
+	redirect := rule.Redirect
+
+	protocol := redirect.Protocol
+	if protocol == "" {
+		protocol = requestProtocol(r)
+	}
+	host := redirect.HostName
+	if host == "" {
+		host = r.Host
+	}
+	switch {
+	case redirect.ReplaceKeyWith != "":
+		key = redirect.ReplaceKeyWith
+	case redirect.ReplaceKeyPrefixWith != nil:
+		var prefix string
+		if rule.Condition != nil {
+			prefix = rule.Condition.KeyPrefixEquals
+		}
+		key = *redirect.ReplaceKeyPrefixWith + strings.TrimPrefix(key, prefix)
+	}
+	code := http.StatusMovedPermanently
+	if redirect.HttpRedirectCode != "" {
+		code, _ = strconv.Atoi(redirect.HttpRedirectCode)
+	}
+	return protocol + "://" + host + "/" + key, code
+}
+
+func requestProtocol(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func loadWebsiteConfiguration(extended map[string][]byte) (*WebsiteConfiguration, error) {
+	data, found := extended[bucketWebsiteConfigurationKey]
+	if !found {
+		return nil, nil
+	}
+	config := &WebsiteConfiguration{}
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (s3a *S3ApiServer) getBucketWebsiteConfiguration(bucket string) (config *WebsiteConfiguration, code ErrorCode) {
+
+	if item := s3a.websiteCache.Get(bucket); item != nil && !item.Expired() {
+		config = item.Value().(*WebsiteConfiguration)
+	} else {
+		entry, err := s3a.getEntry(s3a.option.BucketsPath, bucket)
+		if err != nil {
+			glog.Errorf("lookup bucket %s: %v", bucket, err)
+			return nil, ErrInternalError
+		}
+		if entry == nil {
+			return nil, ErrNoSuchBucket
+		}
+		if config, err = loadWebsiteConfiguration(entry.Extended); err != nil {
+			glog.Errorf("bucket %s has invalid website configuration: %v", bucket, err)
+			return nil, ErrInternalError
+		}
+		if config == nil {
+			config = &WebsiteConfiguration{}
+		}
+		s3a.websiteCache.Set(bucket, config, bucketWebsiteCacheTTL)
+	}
+
+	if config.RedirectAllRequestsTo == nil && config.IndexDocument == nil {
+		return nil, ErrNoSuchWebsiteConfiguration
+	}
+	return config, ErrNone
+}
+
+// websiteBucket is the bucket of the website host name
+func (s3a *S3ApiServer) websiteBucket(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if domain := s3a.option.WebsiteDomainName; domain != "" && strings.HasSuffix(host, "."+domain) {
+		return strings.TrimSuffix(host, "."+domain)
+	}
+	return host
+}
+
+// WebsiteHandler serves the buckets as websites, for the website endpoint
+func (s3a *S3ApiServer) WebsiteHandler() http.Handler {
+	return http.HandlerFunc(s3a.serveWebsite)
+}
+
+func (s3a *S3ApiServer) serveWebsite(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeWebsiteError(w, r, ErrMethodNotAllowed, "")
+		return
+	}
+
+	bucket := s3a.websiteBucket(r.Host)
+	config, errCode := s3a.getBucketWebsiteConfiguration(bucket)
+	if errCode != ErrNone {
+		writeWebsiteError(w, r, errCode, "")
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	if hasDotSegment(key) {
+		writeWebsiteError(w, r, ErrInvalidRequest, "")
+		return
+	}
+
+	if redirect := config.RedirectAllRequestsTo; redirect != nil {
+		protocol := redirect.Protocol
+		if protocol == "" {
+			protocol = requestProtocol(r)
+		}
+		http.Redirect(w, r, protocol+"://"+redirect.HostName+"/"+key, http.StatusMovedPermanently)
+		return
+	}
+
+	if rule := config.matchRoutingRule(key, 0); rule != nil {
+		location, code := rule.location(r, key)
+		http.Redirect(w, r, location, code)
+		return
+	}
+
+	// the website requests are not signed, only allowed by the bucket policy or the ACL if there are identities
+	caller, errCode := s3a.iam.authenticate(r)
+	if errCode != ErrNone {
+		caller = &requester{}
+	}
+
+	indexKey := key
+	if indexKey == "" || strings.HasSuffix(indexKey, "/") {
+		indexKey += config.IndexDocument.Suffix
+	}
+	errCode = s3a.serveWebsiteObject(w, r, caller, bucket, indexKey, http.StatusOK)
+	if errCode == ErrNone {
+		return
+	}
+
+	// a "folder" without the trailing "/" is redirected to the folder
+	if errCode == ErrNoSuchKey && indexKey == key {
+		if _, _, entry, err := s3a.objectEntry(bucket, "/"+key+"/"+config.IndexDocument.Suffix); err == nil && entry != nil && !entry.IsDirectory {
+			http.Redirect(w, r, "/"+key+"/", http.StatusFound)
+			return
+		}
+	}
+
+	status := getAPIError(errCode).HTTPStatusCode
+	if rule := config.matchRoutingRule(key, status); rule != nil {
+		location, code := rule.location(r, key)
+		http.Redirect(w, r, location, code)
+		return
+	}
+
+	if status < http.StatusInternalServerError && config.ErrorDocument != nil {
+		if s3a.serveWebsiteObject(w, r, caller, bucket, config.ErrorDocument.Key, status) == ErrNone {
+			return
+		}
+	}
+
+	writeWebsiteError(w, r, errCode, key)
+}
+
+// serveWebsiteObject sends the object with the status code, or returns the error without writing anything
+func (s3a *S3ApiServer) serveWebsiteObject(w http.ResponseWriter, r *http.Request, caller *requester, bucket, key string, status int) ErrorCode {
+
+	if hasDotSegment(key) {
+		return ErrInvalidRequest
+	}
+	object := "/" + key
+	if errCode := s3a.iam.authorize(r, caller, ACTION_READ, "s3:GetObject", bucket, object); errCode != ErrNone {
+		return errCode
+	}
+
+	_, _, entry, err := s3a.objectEntry(bucket, object)
+	if err != nil {
+		glog.Errorf("lookup object %s%s: %v", bucket, object, err)
+		return ErrInternalError
+	}
+	if entry == nil || entry.IsDirectory || isDeleteMarker(entry) {
+		return ErrNoSuchKey
+	}
+
+	destUrl := fmt.Sprintf("http://%s%s/%s%s", s3a.option.Filer, s3a.option.BucketsPath, bucket, object)
+	if status != http.StatusOK {
+		// the error document is sent as is
+		r.Header.Del("Range")
+		w = &websiteStatusWriter{ResponseWriter: w, status: status}
+	}
+	s3a.proxyToFiler(w, r, destUrl, s3a.passThroughObjectResponse(r, nil))
+	return ErrNone
+}
+
+// hasDotSegment tells whether the key has "." or ".." path segments, which would step out of the bucket on the filer
+func hasDotSegment(key string) bool {
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// websiteStatusWriter replaces the success status code, for the error documents
+type websiteStatusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *websiteStatusWriter) WriteHeader(status int) {
+	if status == http.StatusOK {
+		status = w.status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *websiteStatusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// writeWebsiteError writes the error as a html page, instead of the xml of the REST API
+func writeWebsiteError(w http.ResponseWriter, r *http.Request, errorCode ErrorCode, key string) {
+	apiError := getAPIError(errorCode)
+	title := fmt.Sprintf("%d %s", apiError.HTTPStatusCode, http.StatusText(apiError.HTTPStatusCode))
+
+	var body strings.Builder
+	body.WriteString("<html>\n<head><title>" + title + "</title></head>\n<body>\n<h1>" + title + "</h1>\n<ul>\n")
+	body.WriteString("<li>Code: " + html.EscapeString(apiError.Code) + "</li>\n")
+	body.WriteString("<li>Message: " + html.EscapeString(apiError.Description) + "</li>\n")
+	if key != "" {
+		body.WriteString("<li>Key: " + html.EscapeString(key) + "</li>\n")
+	}
+	body.WriteString("</ul>\n<hr/>\n</body>\n</html>\n")
+
+	setCommonHeaders(w)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(apiError.HTTPStatusCode)
+	if r.Method != http.MethodHead {
+		w.Write([]byte(body.String()))
+	}
+}
@@ -0,0 +1,141 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karlseguin/ccache"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func TestWebsiteConfigurationValidate(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected ErrorCode
+	}{
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`, ErrNone},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>404.html</Key></ErrorDocument></WebsiteConfiguration>`, ErrNone},
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo></WebsiteConfiguration>`, ErrNone},
+		{`<WebsiteConfiguration></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>a/index.html</Suffix></IndexDocument></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key></Key></ErrorDocument></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>..</Suffix></IndexDocument></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>../other/404.html</Key></ErrorDocument></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName><Protocol>ftp</Protocol></RedirectAllRequestsTo></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+		{`<WebsiteConfiguration><RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><HttpRedirectCode>200</HttpRedirectCode></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+		{`<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><RoutingRules><RoutingRule><Redirect><ReplaceKeyPrefixWith>a/</ReplaceKeyPrefixWith><ReplaceKeyWith>b</ReplaceKeyWith></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`, ErrInvalidWebsiteConfiguration},
+	}
+	for _, test := range tests {
+		config := &WebsiteConfiguration{}
+		if err := xml.Unmarshal([]byte(test.config), config); err != nil {
+			t.Fatalf("unmarshal %s: %v", test.config, err)
+		}
+		if errCode := config.validate(); errCode != test.expected {
+			t.Errorf("%s: %v, expecting %v", test.config, errCode, test.expected)
+		}
+	}
+}
+
+func TestWebsiteRoutingRules(t *testing.T) {
+	config := &WebsiteConfiguration{}
+	err := xml.Unmarshal([]byte(`<WebsiteConfiguration>
+  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+  <RoutingRules>
+    <RoutingRule>
+      <Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition>
+      <Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect>
+    </RoutingRule>
+    <RoutingRule>
+      <Condition><KeyPrefixEquals>old/</KeyPrefixEquals></Condition>
+      <Redirect><HostName>example.com</HostName><Protocol>https</Protocol><ReplaceKeyWith>new.html</ReplaceKeyWith><HttpRedirectCode>302</HttpRedirectCode></Redirect>
+    </RoutingRule>
+    <RoutingRule>
+      <Condition><HttpErrorCodeReturnedEquals>404</HttpErrorCodeReturnedEquals></Condition>
+      <Redirect><HostName>fallback.example.com</HostName></Redirect>
+    </RoutingRule>
+  </RoutingRules>
+</WebsiteConfiguration>`), config)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if errCode := config.validate(); errCode != ErrNone {
+		t.Fatalf("validate: %v", errCode)
+	}
+
+	r := httptest.NewRequest("GET", "/docs/a.html", nil)
+	r.Host = "bucket1.website.local"
+
+	tests := []struct {
+		key      string
+		status   int
+		location string
+		code     int
+	}{
+		{"docs/a.html", 0, "http://bucket1.website.local/documents/a.html", 301},
+		{"old/a.html", 0, "https://example.com/new.html", 302},
+		{"a.html", 404, "http://fallback.example.com/a.html", 301},
+		{"a.html", 0, "", 0},
+		{"a.html", 403, "", 0},
+	}
+	for _, test := range tests {
+		rule := config.matchRoutingRule(test.key, test.status)
+		if rule == nil {
+			if test.location != "" {
+				t.Errorf("%s %d: no rule, expecting %s", test.key, test.status, test.location)
+			}
+			continue
+		}
+		location, code := rule.location(r, test.key)
+		if location != test.location || code != test.code {
+			t.Errorf("%s %d: %d %s, expecting %d %s", test.key, test.status, code, location, test.code, test.location)
+		}
+	}
+}
+
+func TestWebsiteBucket(t *testing.T) {
+	s3a := &S3ApiServer{option: &S3ApiServerOption{WebsiteDomainName: "website.local"}}
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{"bucket1.website.local", "bucket1"},
+		{"bucket1.website.local:8334", "bucket1"},
+		{"www.example.com", "www.example.com"},
+		{"www.example.com:8334", "www.example.com"},
+	}
+	for _, test := range tests {
+		if bucket := s3a.websiteBucket(test.host); bucket != test.expected {
+			t.Errorf("%s: %s, expecting %s", test.host, bucket, test.expected)
+		}
+	}
+}
+
+func TestWebsiteTraversal(t *testing.T) {
+	s3a, stop := startFakeFiler(t, map[util.FullPath]*filer_pb.Entry{
+		"/buckets/site":         {Name: "site", IsDirectory: true},
+		"/buckets/other":        {Name: "other", IsDirectory: true},
+		"/buckets/other/secret": {Name: "secret"},
+	})
+	defer stop()
+	s3a.websiteCache = ccache.New(ccache.Configure())
+	s3a.websiteCache.Set("site", &WebsiteConfiguration{
+		IndexDocument: &WebsiteIndexDocument{Suffix: "index.html"},
+		ErrorDocument: &WebsiteErrorDocument{Key: "404.html"},
+	}, time.Minute)
+
+	for _, path := range []string{"/../other/secret", "/%2e%2e/other/secret", "/dir/../../other/secret", "/./secret"} {
+		r := httptest.NewRequest("GET", "http://site"+path, nil)
+		w := httptest.NewRecorder()
+		s3a.serveWebsite(w, r)
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "InvalidRequest") {
+			t.Errorf("%s: status %d %s", path, w.Code, w.Body.String())
+		}
+	}
+}
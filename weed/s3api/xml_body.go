@@ -0,0 +1,124 @@
+package s3api
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// maxXMLBodySize limits the xml request bodies, e.g. the configurations of the buckets or the keys to delete
+const maxXMLBodySize = 2 * 1024 * 1024
+
+var errXMLBodyTooLarge = errors.New("request body too large")
+
+// xmlSyntaxError is an xml body which is not well-formed or does not match the expected document
+type xmlSyntaxError struct {
+	err error
+}
+
+func (e *xmlSyntaxError) Error() string {
+	return e.err.Error()
+}
+
+// limitedBodyReader fails with errXMLBodyTooLarge past the limit, instead of the silent EOF of io.LimitReader
+type limitedBodyReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (l *limitedBodyReader) Read(p []byte) (n int, err error) {
+	if l.remaining < 0 {
+		return 0, errXMLBodyTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err = l.reader.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errXMLBodyTooLarge
+	}
+	return n, err
+}
+
+// decodeXMLBody decodes the request body while reading it, failing as soon as the body is larger than maxSize
+func decodeXMLBody(r *http.Request, v interface{}, maxSize int64) error {
+	if r.ContentLength > maxSize {
+		return errXMLBodyTooLarge
+	}
+	return decodeXML(&limitedBodyReader{reader: r.Body, remaining: maxSize}, v)
+}
+
+// readRequestBody reads the whole request body, for the bodies needed as bytes, e.g. to verify their digest
+func readRequestBody(r *http.Request, maxSize int64) ([]byte, error) {
+	if r.ContentLength > maxSize {
+		return nil, errXMLBodyTooLarge
+	}
+	return ioutil.ReadAll(&limitedBodyReader{reader: r.Body, remaining: maxSize})
+}
+
+// decodeXML decodes one xml document, with nothing but white spaces, comments and processing instructions after it
+func decodeXML(reader io.Reader, v interface{}) error {
+	decoder := xml.NewDecoder(reader)
+	if err := decoder.Decode(v); err != nil {
+		if err == io.EOF && decoder.InputOffset() > 0 {
+			return &xmlSyntaxError{errors.New("no root element")}
+		}
+		return toXMLBodyError(err)
+	}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return toXMLBodyError(err)
+		}
+		switch t := token.(type) {
+		case xml.Comment, xml.ProcInst:
+		case xml.CharData:
+			if strings.TrimSpace(string(t)) != "" {
+				return &xmlSyntaxError{fmt.Errorf("unexpected text %q after the document", strings.TrimSpace(string(t)))}
+			}
+		default:
+			return &xmlSyntaxError{errors.New("unexpected content after the document")}
+		}
+	}
+}
+
+// toXMLBodyError keeps the errors of reading the body apart from the ones of the xml
+func toXMLBodyError(err error) error {
+	switch err.(type) {
+	case *xml.SyntaxError, *xml.UnmarshalError, *xml.TagPathError, *strconv.NumError, *time.ParseError:
+		return &xmlSyntaxError{err}
+	}
+	return err
+}
+
+// writeXMLBodyError writes the error response for the errors of decodeXMLBody and readRequestBody
+func writeXMLBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch e := err.(type) {
+	case *xmlSyntaxError:
+		writeErrorResponseWithMessage(w, ErrMalformedXML, r.URL, fmt.Sprintf("The XML you provided was not well-formed or did not validate against our published schema: %v", e.err))
+		return
+	}
+	switch err {
+	case errXMLBodyTooLarge:
+		writeErrorResponse(w, ErrMaxMessageLengthExceeded, r.URL)
+	case io.EOF:
+		writeErrorResponse(w, ErrMissingRequestBody, r.URL)
+	case io.ErrUnexpectedEOF:
+		writeErrorResponseWithMessage(w, ErrMalformedXML, r.URL, "The XML you provided was truncated.")
+	default:
+		glog.V(1).Infof("read %s body: %v", r.URL.Path, err)
+		writeErrorResponse(w, ErrIncompleteBody, r.URL)
+	}
+}
@@ -0,0 +1,113 @@
+package s3api
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func putVersioning(body string, contentLength int64) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("PUT", "/bucket?versioning", strings.NewReader(body))
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket"})
+	r.ContentLength = contentLength
+	w := httptest.NewRecorder()
+	(&S3ApiServer{}).PutBucketVersioningHandler(w, r)
+	return w
+}
+
+func errorResponseCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	response := &RESTErrorResponse{}
+	if err := xml.Unmarshal(w.Body.Bytes(), response); err != nil {
+		t.Fatalf("parse error response %q: %v", w.Body.String(), err)
+	}
+	return response.Code
+}
+
+func TestXMLBodyTruncated(t *testing.T) {
+	body := `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Status>Enabled</Status></VersioningConfiguration>`
+	for i := 0; i < len(body); i++ {
+		w := putVersioning(body[:i], int64(i))
+		expected := "MalformedXML"
+		if i == 0 {
+			expected = "MissingRequestBodyError"
+		}
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%q: status %d", body[:i], w.Code)
+		}
+		if code := errorResponseCode(t, w); code != expected {
+			t.Errorf("%q: code %s, expecting %s", body[:i], code, expected)
+		}
+	}
+}
+
+func TestXMLBodyOversized(t *testing.T) {
+	body := `<VersioningConfiguration><!-- ` + strings.Repeat("a", maxXMLBodySize) + ` --><Status>Enabled</Status></VersioningConfiguration>`
+	for _, contentLength := range []int64{int64(len(body)), -1} {
+		w := putVersioning(body, contentLength)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("content length %d: status %d", contentLength, w.Code)
+		}
+		if code := errorResponseCode(t, w); code != "MaxMessageLengthExceeded" {
+			t.Errorf("content length %d: code %s", contentLength, code)
+		}
+	}
+
+	// rejected by the content length, before reading the body
+	r := httptest.NewRequest("PUT", "/bucket?versioning", strings.NewReader("<"))
+	r.ContentLength = maxXMLBodySize + 1
+	if err := decodeXMLBody(r, &VersioningConfiguration{}, maxXMLBodySize); err != errXMLBodyTooLarge {
+		t.Errorf("content length over the limit: %v", err)
+	}
+}
+
+func TestXMLBodyMalformed(t *testing.T) {
+	for _, body := range []string{
+		`<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration><Other/>`,
+		`<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>trailing`,
+		`<VersioningConfiguration><Status>Enabled</Status></Versioning>`,
+		`<VersioningConfiguration><Status>Enabled</Status>`,
+		`not xml at all`,
+		"<VersioningConfiguration><Status>\x00</Status></VersioningConfiguration>",
+	} {
+		w := putVersioning(body, int64(len(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%q: status %d", body, w.Code)
+		}
+		response := &RESTErrorResponse{}
+		xml.Unmarshal(w.Body.Bytes(), response)
+		if response.Code != "MalformedXML" || response.Message == errorCodeResponse[ErrMalformedXML].Description {
+			t.Errorf("%q: %+v", body, response)
+		}
+	}
+
+	// a valid document followed by white spaces and comments
+	config := &VersioningConfiguration{}
+	if err := decodeXML(strings.NewReader("<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>\n<!-- end -->\n"), config); err != nil || config.Status != VersioningEnabled {
+		t.Errorf("decode %+v: %v", config, err)
+	}
+
+	// a value not matching the field type
+	var days struct {
+		Days int `xml:"Days"`
+	}
+	if _, ok := decodeXML(strings.NewReader("<Expiration><Days>ten</Days></Expiration>"), &days).(*xmlSyntaxError); !ok {
+		t.Errorf("invalid number accepted")
+	}
+}
+
+func TestDeleteObjectsBodyLimit(t *testing.T) {
+	body := `<Delete>` + strings.Repeat(`<Object><Key>a</Key></Object>`, maxXMLBodySize/29+1) + `</Delete>`
+	r := httptest.NewRequest("POST", "/bucket?delete", bytes.NewReader([]byte(body)))
+	r = mux.SetURLVars(r, map[string]string{"bucket": "bucket"})
+	r.ContentLength = -1
+	w := httptest.NewRecorder()
+	(&S3ApiServer{}).DeleteMultipleObjectsHandler(w, r)
+	if w.Code != http.StatusBadRequest || errorResponseCode(t, w) != "MaxMessageLengthExceeded" {
+		t.Errorf("status %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -1,6 +1,10 @@
 package weed_server
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -29,3 +33,53 @@ func TestParseURL(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessRangeRequest(t *testing.T) {
+	content := "0123456789abcdefghij"
+	tests := []struct {
+		rangeHeader  string
+		status       int
+		contentRange string
+		body         string
+	}{
+		{"", http.StatusOK, "", content},
+		{"bytes=3-12", http.StatusPartialContent, "bytes 3-12/20", "3456789abc"},
+		{"bytes=19-19", http.StatusPartialContent, "bytes 19-19/20", "j"},
+		{"bytes=15-", http.StatusPartialContent, "bytes 15-19/20", "fghij"},
+		{"bytes=-4", http.StatusPartialContent, "bytes 16-19/20", "ghij"},
+		{"bytes=-30", http.StatusPartialContent, "bytes 0-19/20", content},
+		{"bytes=10-100", http.StatusPartialContent, "bytes 10-19/20", "abcdefghij"},
+		{"bytes=20-", http.StatusRequestedRangeNotSatisfiable, "bytes */20", ""},
+		{"bytes=25-30", http.StatusRequestedRangeNotSatisfiable, "bytes */20", ""},
+		{"bytes=5-2", http.StatusRequestedRangeNotSatisfiable, "", ""},
+		{"bytes=-", http.StatusRequestedRangeNotSatisfiable, "", ""},
+		{"bytes=0-19,0-19", http.StatusOK, "", content},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		if tt.rangeHeader != "" {
+			r.Header.Set("Range", tt.rangeHeader)
+		}
+		w := httptest.NewRecorder()
+		processRangeRequest(r, w, int64(len(content)), "text/plain", func(writer io.Writer, offset int64, size int64) error {
+			_, err := io.WriteString(writer, content[offset:offset+size])
+			return err
+		})
+		if w.Code != tt.status {
+			t.Errorf("%q: status %d, expect %d", tt.rangeHeader, w.Code, tt.status)
+			continue
+		}
+		if got := w.Header().Get("Content-Range"); got != tt.contentRange {
+			t.Errorf("%q: Content-Range %q, expect %q", tt.rangeHeader, got, tt.contentRange)
+		}
+		if tt.status == http.StatusRequestedRangeNotSatisfiable {
+			continue
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("%q: body %q, expect %q", tt.rangeHeader, w.Body.String(), tt.body)
+		}
+		if got := w.Header().Get("Content-Length"); got != "" && got != strconv.Itoa(len(tt.body)) {
+			t.Errorf("%q: Content-Length %s, expect %d", tt.rangeHeader, got, len(tt.body))
+		}
+	}
+}
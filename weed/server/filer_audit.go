@@ -0,0 +1,161 @@
+package weed_server
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/chrislusf/seaweedfs/weed/audit"
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// FilerAuditInterceptor records the grpc requests changing the entries into the audit log,
+// by the common name of the client certificate with mutual tls, and the client address.
+func FilerAuditInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+	if !audit.Enabled() {
+		return handler(ctx, req)
+	}
+	record := auditedFilerRequest(req)
+	if record == nil {
+		return handler(ctx, req)
+	}
+
+	resp, err := handler(ctx, req)
+
+	record.Service = "filer"
+	record.Action = info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	record.Result = audit.ResultSuccess
+	if p, ok := peer.FromContext(ctx); ok {
+		record.RemoteAddr = p.Addr.String()
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			record.Identity = tlsIdentity(&tlsInfo.State)
+		}
+	}
+	if err != nil {
+		record.Result, record.Error = audit.ResultFailure, err.Error()
+	} else if response, ok := resp.(interface{ GetError() string }); ok && response.GetError() != "" {
+		record.Result, record.Error = audit.ResultFailure, response.GetError()
+	}
+	audit.Log(record)
+
+	return resp, err
+}
+
+// auditedFilerRequest returns the audit record of the requests changing the entries, nil for the others
+func auditedFilerRequest(req interface{}) *audit.Record {
+	switch req := req.(type) {
+	case *filer_pb.CreateEntryRequest:
+		return &audit.Record{Operation: audit.OperationCreate, Path: string(util.NewFullPath(req.Directory, req.GetEntry().GetName()))}
+	case *filer_pb.UpdateEntryRequest:
+		return &audit.Record{Operation: audit.OperationUpdate, Path: string(util.NewFullPath(req.Directory, req.GetEntry().GetName()))}
+	case *filer_pb.AppendToEntryRequest:
+		return &audit.Record{Operation: audit.OperationUpdate, Path: string(util.NewFullPath(req.Directory, req.EntryName))}
+	case *filer_pb.UpdateXattrsRequest:
+		return &audit.Record{Operation: audit.OperationUpdate, Path: string(util.NewFullPath(req.Directory, req.Name))}
+	case *filer_pb.DeleteEntryRequest:
+		return &audit.Record{Operation: audit.OperationDelete, Path: string(util.NewFullPath(req.Directory, req.Name))}
+	case *filer_pb.AtomicRenameEntryRequest:
+		return &audit.Record{
+			Operation: audit.OperationRename,
+			Path:      string(util.NewFullPath(req.OldDirectory, req.OldName)),
+			NewPath:   string(util.NewFullPath(req.NewDirectory, req.NewName)),
+		}
+	case *filer_pb.CopyEntryRequest:
+		return &audit.Record{
+			Operation: audit.OperationCreate,
+			Path:      string(util.NewFullPath(req.TargetDirectory, req.TargetName)),
+			Source:    string(util.NewFullPath(req.SourceDirectory, req.SourceName)),
+		}
+	}
+	return nil
+}
+
+// auditResponseWriter records the status of the response
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+type auditReplacedKey struct{}
+
+// auditedWrite makes the creation of the entry tell the audited request whether it overwrites an existing file
+func auditedWrite(ctx context.Context, r *http.Request) context.Context {
+	if replaced, ok := r.Context().Value(auditReplacedKey{}).(*bool); ok {
+		return filer2.ReportingReplaced(ctx, replaced)
+	}
+	return ctx
+}
+
+// audited records the http request changing the path into the audit log, a write over an existing file is an update
+func (fs *FilerServer) audited(w http.ResponseWriter, r *http.Request, operation audit.Operation, path util.FullPath, handler http.HandlerFunc) {
+
+	if !audit.Enabled() {
+		handler(w, r)
+		return
+	}
+
+	var replaced bool
+	if operation == audit.OperationCreate {
+		r = r.WithContext(context.WithValue(r.Context(), auditReplacedKey{}, &replaced))
+	}
+
+	writer := &auditResponseWriter{ResponseWriter: w}
+	handler(writer, r)
+	if replaced {
+		operation = audit.OperationUpdate
+	}
+
+	record := &audit.Record{
+		Service:    "filer",
+		Operation:  operation,
+		Action:     r.Method,
+		Identity:   tlsIdentity(r.TLS),
+		RemoteAddr: r.RemoteAddr,
+		Path:       string(path),
+		Result:     audit.ResultSuccess,
+		Status:     writer.status,
+	}
+	if record.Status == 0 {
+		record.Status = http.StatusOK
+	}
+	if record.Status >= http.StatusBadRequest {
+		record.Result, record.Error = audit.ResultFailure, http.StatusText(record.Status)
+	}
+	audit.Log(record)
+}
+
+// tlsIdentity is the common name of the verified client certificate
+func tlsIdentity(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return state.VerifiedChains[0][0].Subject.CommonName
+}
@@ -0,0 +1,94 @@
+package weed_server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/chrislusf/seaweedfs/weed/audit"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+type memoryAuditSink struct {
+	sync.Mutex
+	data bytes.Buffer
+}
+
+func (sink *memoryAuditSink) Write(data []byte) error {
+	sink.Lock()
+	defer sink.Unlock()
+	sink.data.Write(data)
+	return nil
+}
+
+func (sink *memoryAuditSink) Close() error {
+	return nil
+}
+
+func TestFilerAuditInterceptor(t *testing.T) {
+	sink := &memoryAuditSink{}
+	logger := audit.NewLogger(sink, 100, time.Hour)
+	audit.SetLogger(logger)
+	defer audit.SetLogger(nil)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4321}})
+	calls := []struct {
+		method string
+		req    interface{}
+		resp   interface{}
+		err    error
+	}{
+		{"CreateEntry", &filer_pb.CreateEntryRequest{Directory: "/dir", Entry: &filer_pb.Entry{Name: "file"}}, &filer_pb.CreateEntryResponse{}, nil},
+		{"LookupDirectoryEntry", &filer_pb.LookupDirectoryEntryRequest{Directory: "/dir", Name: "file"}, &filer_pb.LookupDirectoryEntryResponse{}, nil},
+		{"CreateEntry", &filer_pb.CreateEntryRequest{Directory: "/dir", Entry: &filer_pb.Entry{Name: "file"}}, &filer_pb.CreateEntryResponse{Error: "quota exceeded"}, nil},
+		{"AtomicRenameEntry", &filer_pb.AtomicRenameEntryRequest{OldDirectory: "/dir", OldName: "file", NewDirectory: "/other", NewName: "moved"}, &filer_pb.AtomicRenameEntryResponse{}, nil},
+		{"DeleteEntry", &filer_pb.DeleteEntryRequest{Directory: "/other", Name: "moved"}, nil, errors.New("not found")},
+	}
+	for _, call := range calls {
+		info := &grpc.UnaryServerInfo{FullMethod: "/filer_pb.SeaweedFiler/" + call.method}
+		resp, err := FilerAuditInterceptor(ctx, call.req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call.resp, call.err
+		})
+		if resp != call.resp || err != call.err {
+			t.Errorf("%s: %v %v", call.method, resp, err)
+		}
+	}
+	logger.Close()
+
+	expected := []audit.Record{
+		{Operation: audit.OperationCreate, Action: "CreateEntry", Path: "/dir/file", Result: audit.ResultSuccess},
+		{Operation: audit.OperationCreate, Action: "CreateEntry", Path: "/dir/file", Result: audit.ResultFailure, Error: "quota exceeded"},
+		{Operation: audit.OperationRename, Action: "AtomicRenameEntry", Path: "/dir/file", NewPath: "/other/moved", Result: audit.ResultSuccess},
+		{Operation: audit.OperationDelete, Action: "DeleteEntry", Path: "/other/moved", Result: audit.ResultFailure, Error: "not found"},
+	}
+	var records []audit.Record
+	scanner := bufio.NewScanner(&sink.data)
+	for scanner.Scan() {
+		var record audit.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decode %s: %v", scanner.Text(), err)
+		}
+		if record.Service != "filer" || record.RemoteAddr != "10.0.0.1:4321" {
+			t.Errorf("record %s", scanner.Text())
+		}
+		record.Time, record.Service, record.RemoteAddr = time.Time{}, "", ""
+		records = append(records, record)
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("records %+v, expecting %+v", records, expected)
+	}
+	for i, record := range records {
+		if record != expected[i] {
+			t.Errorf("record %d: %+v, expecting %+v", i, record, expected[i])
+		}
+	}
+}
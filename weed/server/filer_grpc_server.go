@@ -21,6 +21,10 @@ func (fs *FilerServer) LookupDirectoryEntry(ctx context.Context, req *filer_pb.L
 
 	glog.V(4).Infof("LookupDirectoryEntry %s", filepath.Join(req.Directory, req.Name))
 
+	if err := filer2.CheckNotKvPath(util.JoinPath(req.Directory, req.Name), false); err != nil {
+		return nil, err
+	}
+
 	entry, err := fs.filer.FindEntry(ctx, util.JoinPath(req.Directory, req.Name))
 	if err == filer_pb.ErrNotFound {
 		return &filer_pb.LookupDirectoryEntryResponse{}, err
@@ -37,6 +41,7 @@ func (fs *FilerServer) LookupDirectoryEntry(ctx context.Context, req *filer_pb.L
 			Attributes:  filer2.EntryAttributeToPb(entry),
 			Chunks:      entry.Chunks,
 			Extended:    entry.Extended,
+			Content:     entry.Content,
 		},
 	}, nil
 }
@@ -45,6 +50,10 @@ func (fs *FilerServer) ListEntries(req *filer_pb.ListEntriesRequest, stream file
 
 	glog.V(4).Infof("ListEntries %v", req)
 
+	if err := filer2.CheckNotKvPath(util.FullPath(req.Directory), false); err != nil {
+		return err
+	}
+
 	limit := int(req.Limit)
 	if limit == 0 {
 		limit = fs.option.DirListingLimit
@@ -57,6 +66,21 @@ func (fs *FilerServer) ListEntries(req *filer_pb.ListEntriesRequest, stream file
 
 	lastFileName := req.StartFromFileName
 	includeLastFile := req.InclusiveStartFrom
+	directory := req.Directory
+	if strings.HasSuffix(directory, "/") && len(directory) > 1 {
+		directory = directory[:len(directory)-1]
+	}
+	if req.Cursor != "" {
+		cursor, err := filer2.ParseListCursor(req.Cursor)
+		if err != nil {
+			return err
+		}
+		if cursor.Directory != directory {
+			return fmt.Errorf("list cursor of %s used for %s", cursor.Directory, directory)
+		}
+		lastFileName, includeLastFile = cursor.LastFileName, cursor.IncludeLastFile
+	}
+
 	for limit > 0 {
 		entries, err := fs.filer.ListDirectoryEntries(stream.Context(), util.FullPath(req.Directory), lastFileName, includeLastFile, paginationLimit)
 
@@ -86,7 +110,9 @@ func (fs *FilerServer) ListEntries(req *filer_pb.ListEntriesRequest, stream file
 					Chunks:      entry.Chunks,
 					Attributes:  filer2.EntryAttributeToPb(entry),
 					Extended:    entry.Extended,
+					Content:     entry.Content,
 				},
+				Cursor: filer2.ListCursor{Directory: directory, LastFileName: entry.Name()}.Encode(),
 			}); err != nil {
 				return err
 			}
@@ -151,15 +177,33 @@ func (fs *FilerServer) CreateEntry(ctx context.Context, req *filer_pb.CreateEntr
 		return
 	}
 
-	createErr := fs.filer.CreateEntry(ctx, &filer2.Entry{
-		FullPath: util.JoinPath(req.Directory, req.Entry.Name),
-		Attr:     filer2.PbToEntryAttribute(req.Entry.Attributes),
-		Chunks:   chunks,
-	}, req.OExcl)
+	fullPath := util.JoinPath(req.Directory, req.Entry.Name)
+	if kvErr := filer2.CheckNotKvPath(fullPath, false); kvErr != nil {
+		resp.Error = kvErr.Error()
+		return
+	}
+	attr := filer2.PbToEntryAttribute(req.Entry.Attributes)
+	if !attr.IsDirectory() && attr.TtlSec == 0 {
+		attr.TtlSec = fs.pathPolicyTtlSec(string(fullPath))
+	}
 
-	if createErr == nil {
-		fs.filer.DeleteChunks(garbages)
+	newEntry := &filer2.Entry{
+		FullPath: fullPath,
+		Attr:     attr,
+		Extended: req.Entry.Extended,
+		Chunks:   chunks,
+		Content:  req.Entry.Content,
+	}
+	// the garbage chunks are deleted by the filer along with the replaced chunks, once each
+	ctx = filer2.WithGarbageChunks(ctx, garbages)
+	var createErr error
+	if condition := filer2.ETagCondition(req.IfMatch, req.IfNoneMatch); condition != nil {
+		createErr = fs.filer.CreateEntryIf(ctx, newEntry, condition)
 	} else {
+		createErr = fs.filer.CreateEntry(ctx, newEntry, req.OExcl)
+	}
+
+	if createErr != nil {
 		glog.V(3).Infof("CreateEntry %s: %v", filepath.Join(req.Directory, req.Entry.Name), createErr)
 		resp.Error = createErr.Error()
 	}
@@ -172,6 +216,9 @@ func (fs *FilerServer) UpdateEntry(ctx context.Context, req *filer_pb.UpdateEntr
 	glog.V(4).Infof("UpdateEntry %v", req)
 
 	fullpath := util.Join(req.Directory, req.Entry.Name)
+	if err := filer2.CheckNotKvPath(util.FullPath(fullpath), false); err != nil {
+		return nil, err
+	}
 	entry, err := fs.filer.FindEntry(ctx, util.FullPath(fullpath))
 	if err != nil {
 		return &filer_pb.UpdateEntryResponse{}, fmt.Errorf("not found %s: %v", fullpath, err)
@@ -187,6 +234,7 @@ func (fs *FilerServer) UpdateEntry(ctx context.Context, req *filer_pb.UpdateEntr
 		Attr:     entry.Attr,
 		Extended: req.Entry.Extended,
 		Chunks:   chunks,
+		Content:  req.Entry.Content,
 	}
 
 	glog.V(3).Infof("updating %s: %+v, chunks %d: %v => %+v, chunks %d: %v, extended: %v => %v",
@@ -230,6 +278,9 @@ func (fs *FilerServer) AppendToEntry(ctx context.Context, req *filer_pb.AppendTo
 	glog.V(4).Infof("AppendToEntry %v", req)
 
 	fullpath := util.NewFullPath(req.Directory, req.EntryName)
+	if err := filer2.CheckNotKvPath(fullpath, false); err != nil {
+		return nil, err
+	}
 	var offset int64 = 0
 	entry, err := fs.filer.FindEntry(ctx, util.FullPath(fullpath))
 	if err == filer_pb.ErrNotFound {
@@ -244,6 +295,12 @@ func (fs *FilerServer) AppendToEntry(ctx context.Context, req *filer_pb.AppendTo
 			},
 		}
 	} else {
+		// the content saved inside the entry moves to a volume server, before the appended chunks
+		if len(entry.Content) > 0 {
+			if err = fs.moveContentToChunk(entry); err != nil {
+				return nil, fmt.Errorf("append to %s: %v", fullpath, err)
+			}
+		}
 		offset = int64(filer2.TotalSize(entry.Chunks))
 	}
 
@@ -253,17 +310,65 @@ func (fs *FilerServer) AppendToEntry(ctx context.Context, req *filer_pb.AppendTo
 	}
 
 	entry.Chunks = append(entry.Chunks, req.Chunks...)
+	entry.Md5 = nil
+	delete(entry.Extended, filer2.ETagKey)
 
 	err = fs.filer.CreateEntry(context.Background(), entry, false)
 
 	return &filer_pb.AppendToEntryResponse{}, err
 }
 
+// moveContentToChunk uploads the content saved inside the entry to a volume server, as the first chunk
+func (fs *FilerServer) moveContentToChunk(entry *filer2.Entry) error {
+
+	ttlStr := ""
+	if entry.TtlSec > 0 {
+		ttlStr = strconv.Itoa(int(entry.TtlSec))
+	}
+	collection, replication, _ := fs.detectCollection(string(entry.FullPath), entry.Collection, entry.Replication)
+
+	assignResult, err := operation.Assign(fs.filer.GetMaster(), fs.grpcDialOption, &operation.VolumeAssignRequest{
+		Count:       1,
+		Replication: replication,
+		Collection:  collection,
+		Ttl:         ttlStr,
+		DataCenter:  fs.option.DataCenter,
+		DiskType:    fs.filer.MatchPathPolicy(string(entry.FullPath)).DiskType,
+		AppendOnly:  fs.isAppendOnlyPath(string(entry.FullPath)),
+	})
+	if err != nil {
+		return fmt.Errorf("assign volume: %v", err)
+	}
+	if assignResult.Error != "" {
+		return fmt.Errorf("assign volume result: %v", assignResult.Error)
+	}
+
+	uploadResult, err := operation.UploadData("http://"+assignResult.Url+"/"+assignResult.Fid, entry.Name(), fs.option.Cipher, entry.Content, false, entry.Mime, nil, assignResult.Auth)
+	if err != nil {
+		return fmt.Errorf("upload data: %v", err)
+	}
+	if uploadResult.Error != "" {
+		return fmt.Errorf("upload result: %v", uploadResult.Error)
+	}
+
+	entry.Chunks = append([]*filer_pb.FileChunk{uploadResult.ToPbFileChunk(assignResult.Fid, 0)}, entry.Chunks...)
+	entry.Content = nil
+	return nil
+}
+
 func (fs *FilerServer) DeleteEntry(ctx context.Context, req *filer_pb.DeleteEntryRequest) (resp *filer_pb.DeleteEntryResponse, err error) {
 
 	glog.V(4).Infof("DeleteEntry %v", req)
 
-	err = fs.filer.DeleteEntryMetaAndData(ctx, util.JoinPath(req.Directory, req.Name), req.IsRecursive, req.IgnoreRecursiveError, req.IsDeleteData)
+	if err = filer2.CheckNotKvPath(util.JoinPath(req.Directory, req.Name), true); err != nil {
+		return &filer_pb.DeleteEntryResponse{Error: err.Error()}, nil
+	}
+
+	if req.IsDeleteTree {
+		err = fs.filer.DeleteTree(ctx, util.JoinPath(req.Directory, req.Name), req.IsRecursive, req.IgnoreRecursiveError, req.IsDeleteData)
+	} else {
+		err = fs.filer.DeleteEntryMetaAndData(ctx, util.JoinPath(req.Directory, req.Name), req.IsRecursive, req.IgnoreRecursiveError, req.IsDeleteData)
+	}
 	resp = &filer_pb.DeleteEntryResponse{}
 	if err != nil {
 		resp.Error = err.Error()
@@ -273,11 +378,19 @@ func (fs *FilerServer) DeleteEntry(ctx context.Context, req *filer_pb.DeleteEntr
 
 func (fs *FilerServer) AssignVolume(ctx context.Context, req *filer_pb.AssignVolumeRequest) (resp *filer_pb.AssignVolumeResponse, err error) {
 
-	ttlStr := ""
+	// the parent path is a directory, to match the path policies by the prefix of the files in it
+	parentPath := strings.TrimSuffix(req.ParentPath, "/") + "/"
+	policy := fs.filer.MatchPathPolicy(parentPath)
+
+	ttlStr := policy.Ttl
 	if req.TtlSec > 0 {
 		ttlStr = strconv.Itoa(int(req.TtlSec))
 	}
-	collection, replication, _ := fs.detectCollection(req.ParentPath, req.Collection, req.Replication)
+	diskType := req.DiskType
+	if diskType == "" {
+		diskType = policy.DiskType
+	}
+	collection, replication, _ := fs.detectCollection(parentPath, req.Collection, req.Replication)
 
 	var altRequest *operation.VolumeAssignRequest
 
@@ -292,6 +405,8 @@ func (fs *FilerServer) AssignVolume(ctx context.Context, req *filer_pb.AssignVol
 		Collection:  collection,
 		Ttl:         ttlStr,
 		DataCenter:  dataCenter,
+		DiskType:    diskType,
+		AppendOnly:  fs.isAppendOnlyPath(parentPath),
 	}
 	if dataCenter != "" {
 		altRequest = &operation.VolumeAssignRequest{
@@ -300,6 +415,8 @@ func (fs *FilerServer) AssignVolume(ctx context.Context, req *filer_pb.AssignVol
 			Collection:  collection,
 			Ttl:         ttlStr,
 			DataCenter:  "",
+			DiskType:    diskType,
+			AppendOnly:  assignRequest.AppendOnly,
 		}
 	}
 	assignResult, err := operation.Assign(fs.filer.GetMaster(), fs.grpcDialOption, assignRequest, altRequest)
@@ -0,0 +1,35 @@
+package weed_server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func (fs *FilerServer) CopyEntry(ctx context.Context, req *filer_pb.CopyEntryRequest) (*filer_pb.CopyEntryResponse, error) {
+
+	glog.V(1).Infof("CopyEntry %v", req)
+
+	sourcePath := util.FullPath(filepath.ToSlash(req.SourceDirectory)).Child(req.SourceName)
+	targetPath := util.FullPath(filepath.ToSlash(req.TargetDirectory)).Child(req.TargetName)
+	if err := filer2.CheckNotKvPath(sourcePath, true); err != nil {
+		return nil, err
+	}
+	if err := filer2.CheckNotKvPath(targetPath, false); err != nil {
+		return nil, err
+	}
+
+	entry, err := fs.filer.CopyEntry(ctx, sourcePath, targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("copy %s to %s: %v", sourcePath, targetPath, err)
+	}
+
+	return &filer_pb.CopyEntryResponse{
+		Entry: entry.ToProtoEntry(),
+	}, nil
+}
@@ -1,6 +1,7 @@
 package weed_server
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -22,6 +23,19 @@ func (fs *FilerServer) SubscribeMetadata(req *filer_pb.SubscribeMetadataRequest,
 	defer fs.deleteClient(clientName)
 
 	lastReadTime := time.Unix(0, req.SinceNs)
+
+	// a named subscriber resumes right after its last acknowledged event
+	var ackedTsNs int64
+	if req.SubscriberName != "" {
+		var err error
+		if ackedTsNs, err = fs.filer.LoadSubscriberOffset(stream.Context(), req.SubscriberName); err != nil {
+			return err
+		}
+		if ackedTsNs > 0 {
+			lastReadTime = time.Unix(0, ackedTsNs)
+		}
+	}
+
 	glog.V(0).Infof(" %v starts to subscribe %s from %+v", clientName, req.PathPrefix, lastReadTime)
 	var processedTsNs int64
 
@@ -37,8 +51,12 @@ func (fs *FilerServer) SubscribeMetadata(req *filer_pb.SubscribeMetadataRequest,
 
 		fullpath := util.Join(dirPath, entryName)
 
-		// skip on filer internal meta logs
-		if strings.HasPrefix(fullpath, filer2.SystemLogDir) {
+		// skip on filer internal meta logs, rename journals, and subscriber offsets, unless subscribed to them
+		if strings.HasPrefix(fullpath, filer2.SystemDir+"/") && !strings.HasPrefix(req.PathPrefix, filer2.SystemDir+"/") {
+			return nil
+		}
+
+		if tsNs <= ackedTsNs {
 			return nil
 		}
 
@@ -93,6 +111,19 @@ func (fs *FilerServer) SubscribeMetadata(req *filer_pb.SubscribeMetadataRequest,
 
 }
 
+func (fs *FilerServer) AckMetadata(ctx context.Context, req *filer_pb.AckMetadataRequest) (*filer_pb.AckMetadataResponse, error) {
+
+	tsNs, err := fs.filer.SaveSubscriberOffset(ctx, req.SubscriberName, req.TsNs)
+	if err != nil {
+		glog.V(0).Infof("ack metadata %+v: %v", req, err)
+		return nil, err
+	}
+
+	return &filer_pb.AckMetadataResponse{
+		TsNs: tsNs,
+	}, nil
+}
+
 func (fs *FilerServer) addClient(clientType string, clientAddress string) (clientName string) {
 	clientName = clientType + "@" + clientAddress
 	glog.V(0).Infof("+ listener %v", clientName)
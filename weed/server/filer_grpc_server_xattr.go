@@ -0,0 +1,45 @@
+package weed_server
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func (fs *FilerServer) GetXattrs(ctx context.Context, req *filer_pb.GetXattrsRequest) (*filer_pb.GetXattrsResponse, error) {
+
+	fullpath := util.FullPath(filepath.ToSlash(req.Directory)).Child(req.Name)
+	if err := filer2.CheckNotKvPath(fullpath, false); err != nil {
+		return nil, err
+	}
+
+	xattrs, err := fs.filer.GetXattrs(ctx, fullpath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filer_pb.GetXattrsResponse{
+		Xattrs: xattrs,
+	}, nil
+}
+
+func (fs *FilerServer) UpdateXattrs(ctx context.Context, req *filer_pb.UpdateXattrsRequest) (*filer_pb.UpdateXattrsResponse, error) {
+
+	glog.V(4).Infof("UpdateXattrs %s/%s", req.Directory, req.Name)
+
+	fullpath := util.FullPath(filepath.ToSlash(req.Directory)).Child(req.Name)
+	if err := filer2.CheckNotKvPath(fullpath, false); err != nil {
+		return nil, err
+	}
+
+	if err := fs.filer.UpdateXattrs(ctx, fullpath, req.Set, req.Remove); err != nil {
+		glog.V(1).Infof("update xattrs %s: %v", fullpath, err)
+		return nil, err
+	}
+
+	return &filer_pb.UpdateXattrsResponse{}, nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/chrislusf/seaweedfs/weed/util/grace"
 
+	"github.com/chrislusf/seaweedfs/weed/audit"
 	"github.com/chrislusf/seaweedfs/weed/operation"
 	"github.com/chrislusf/seaweedfs/weed/pb"
 	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
@@ -44,6 +45,7 @@ type FilerOption struct {
 	DefaultReplication string
 	DisableDirListing  bool
 	MaxMB              int
+	SaveToFilerLimit   int
 	DirListingLimit    int
 	DataCenter         string
 	DefaultLevelDbDir  string
@@ -52,6 +54,9 @@ type FilerOption struct {
 	Port               uint32
 	recursiveDelete    bool
 	Cipher             bool
+	MaxFilenameLength  int
+	MaxPathLength      int
+	Peers              []string
 }
 
 type FilerServer struct {
@@ -59,6 +64,7 @@ type FilerServer struct {
 	secret         security.SigningKey
 	filer          *filer2.Filer
 	grpcDialOption grpc.DialOption
+	health         *HealthChecker
 
 	// notifying clients
 	listenersLock sync.Mutex
@@ -80,9 +86,14 @@ func NewFilerServer(defaultMux, readonlyMux *http.ServeMux, option *FilerOption)
 	if len(option.Masters) == 0 {
 		glog.Fatal("master list is required!")
 	}
+	if option.MaxMB < 0 || option.MaxMB > maxChunkSize/(1024*1024) {
+		return nil, fmt.Errorf("maxMB %d is out of the range 0 to %d", option.MaxMB, maxChunkSize/(1024*1024))
+	}
 
 	fs.filer = filer2.NewFiler(option.Masters, fs.grpcDialOption, option.Host, option.Port, option.Collection, option.DefaultReplication, fs.notifyMetaListeners)
 	fs.filer.Cipher = option.Cipher
+	fs.filer.MaxFilenameLength = option.MaxFilenameLength
+	fs.filer.MaxPathLength = option.MaxPathLength
 
 	maybeStartMetrics(fs, option)
 
@@ -98,6 +109,7 @@ func NewFilerServer(defaultMux, readonlyMux *http.ServeMux, option *FilerOption)
 		}
 	}
 	util.LoadConfiguration("notification", false)
+	util.LoadConfiguration("audit", false)
 
 	fs.option.recursiveDelete = v.GetBool("filer.options.recursive_delete")
 	v.SetDefault("filer.options.buckets_folder", "/buckets")
@@ -106,9 +118,25 @@ func NewFilerServer(defaultMux, readonlyMux *http.ServeMux, option *FilerOption)
 	fs.filer.LoadConfiguration(v)
 
 	notification.LoadConfiguration(v, "notification.")
+	audit.LoadConfiguration(v, "audit.")
+
+	fs.health = NewHealthChecker(DefaultHealthCheckTimeout)
+	fs.health.AddCheck("master", fs.filer.PingMaster)
+	fs.health.AddCheck("store", fs.filer.PingStore)
+	defaultMux.HandleFunc("/healthz", fs.health.LivenessHandler)
+	defaultMux.HandleFunc("/readyz", fs.health.ReadinessHandler)
+	if defaultMux != readonlyMux {
+		readonlyMux.HandleFunc("/healthz", fs.health.LivenessHandler)
+		readonlyMux.HandleFunc("/readyz", fs.health.ReadinessHandler)
+	}
+
+	fs.secret = security.SigningKey(v.GetString("jwt.filer_signing.key"))
 
 	handleStaticResources(defaultMux)
 	if !option.DisableHttp {
+		defaultMux.HandleFunc(kvPathPrefix, fs.kvHandler)
+		defaultMux.HandleFunc(deleteJobPathPrefix, fs.deleteJobHandler)
+		defaultMux.HandleFunc(xattrPathPrefix, fs.xattrHandler)
 		defaultMux.HandleFunc("/", fs.filerHandler)
 	}
 	if defaultMux != readonlyMux {
@@ -116,6 +144,28 @@ func NewFilerServer(defaultMux, readonlyMux *http.ServeMux, option *FilerOption)
 	}
 
 	fs.filer.LoadBuckets()
+	fs.filer.LoadQuotas()
+	fs.filer.LoadPathPolicies()
+	for _, peer := range option.Peers {
+		if peer != "" && peer != fmt.Sprintf("%s:%d", option.Host, option.Port) {
+			go fs.followPeerPathPolicies(peer)
+		}
+	}
+	fs.filer.RecoverRenames()
+
+	v.SetDefault("filer.options.delete_tree_entries_per_second", 1000)
+	fs.filer.DeleteTreeLimiter = util.NewRateLimiter(v.GetInt64("filer.options.delete_tree_entries_per_second"))
+	fs.filer.RecoverDeletes()
+
+	v.SetDefault("filer.options.ttl_sweep_entries_per_second", 100)
+	v.SetDefault("filer.options.ttl_sweep_interval_seconds", 60)
+	if interval := v.GetInt("filer.options.ttl_sweep_interval_seconds"); interval > 0 {
+		go fs.filer.LoopSweepExpiredEntries(filer2.TtlSweeperOption{
+			Owner:            fmt.Sprintf("%s:%d", option.Host, option.Port),
+			EntriesPerSecond: v.GetInt("filer.options.ttl_sweep_entries_per_second"),
+			Interval:         time.Duration(interval) * time.Second,
+		})
+	}
 
 	grace.OnInterrupt(func() {
 		fs.filer.Shutdown()
@@ -4,11 +4,18 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/chrislusf/seaweedfs/weed/audit"
+	"github.com/chrislusf/seaweedfs/weed/filer2"
 	"github.com/chrislusf/seaweedfs/weed/stats"
+	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
 func (fs *FilerServer) filerHandler(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
+	if err := filer2.CheckNotKvPath(util.FullPath(r.URL.Path), r.Method == "DELETE"); err != nil {
+		writeJsonError(w, r, http.StatusForbidden, err)
+		return
+	}
 	switch r.Method {
 	case "GET":
 		stats.FilerRequestCounter.WithLabelValues("get").Inc()
@@ -20,21 +27,25 @@ func (fs *FilerServer) filerHandler(w http.ResponseWriter, r *http.Request) {
 		stats.FilerRequestHistogram.WithLabelValues("head").Observe(time.Since(start).Seconds())
 	case "DELETE":
 		stats.FilerRequestCounter.WithLabelValues("delete").Inc()
-		fs.DeleteHandler(w, r)
+		fs.audited(w, r, audit.OperationDelete, util.FullPath(r.URL.Path), fs.DeleteHandler)
 		stats.FilerRequestHistogram.WithLabelValues("delete").Observe(time.Since(start).Seconds())
 	case "PUT":
 		stats.FilerRequestCounter.WithLabelValues("put").Inc()
-		fs.PostHandler(w, r)
+		fs.audited(w, r, audit.OperationCreate, util.FullPath(r.URL.Path), fs.PostHandler)
 		stats.FilerRequestHistogram.WithLabelValues("put").Observe(time.Since(start).Seconds())
 	case "POST":
 		stats.FilerRequestCounter.WithLabelValues("post").Inc()
-		fs.PostHandler(w, r)
+		fs.audited(w, r, audit.OperationCreate, util.FullPath(r.URL.Path), fs.PostHandler)
 		stats.FilerRequestHistogram.WithLabelValues("post").Observe(time.Since(start).Seconds())
 	}
 }
 
 func (fs *FilerServer) readonlyFilerHandler(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
+	if err := filer2.CheckNotKvPath(util.FullPath(r.URL.Path), false); err != nil {
+		writeJsonError(w, r, http.StatusForbidden, err)
+		return
+	}
 	switch r.Method {
 	case "GET":
 		stats.FilerRequestCounter.WithLabelValues("get").Inc()
@@ -0,0 +1,50 @@
+package weed_server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// deleteJobPathPrefix serves the progress of the tree deletions started with async=true
+const deleteJobPathPrefix = "/.delete/"
+
+// startDeleteJob deletes the tree in the background, and responds with the job to poll
+func (fs *FilerServer) startDeleteJob(w http.ResponseWriter, r *http.Request, isRecursive, ignoreRecursiveError, shouldDeleteChunks bool) {
+	job, err := fs.filer.StartDeleteTree(context.Background(), util.FullPath(r.URL.Path), isRecursive, ignoreRecursiveError, shouldDeleteChunks)
+	if err != nil {
+		glog.V(1).Infoln("deleting", r.URL.Path, ":", err.Error())
+		httpStatus := http.StatusInternalServerError
+		if err == filer_pb.ErrNotFound {
+			httpStatus = http.StatusNotFound
+		}
+		writeJsonError(w, r, httpStatus, err)
+		return
+	}
+	writeJsonQuiet(w, r, http.StatusAccepted, job.Progress())
+}
+
+// curl http://localhost:8888/.delete/<jobId>
+// curl -X DELETE http://localhost:8888/.delete/<jobId>
+func (fs *FilerServer) deleteJobHandler(w http.ResponseWriter, r *http.Request) {
+	job := fs.filer.FindDeleteTreeJob(strings.TrimPrefix(r.URL.Path, deleteJobPathPrefix))
+	if job == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJsonQuiet(w, r, http.StatusOK, job.Progress())
+	case "DELETE":
+		job.Cancel()
+		job.Wait()
+		writeJsonQuiet(w, r, http.StatusOK, job.Progress())
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,112 @@
+package weed_server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/security"
+	"github.com/chrislusf/seaweedfs/weed/stats"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+)
+
+// kvPathPrefix serves the key value pairs of the applications, saved under filer2.KvDir.
+// The requests need a jwt signed by jwt.filer_signing.key in security.toml, and without the key the api is disabled.
+// The generic file apis, including the gRPC ones used by the mount and webdav, deny the paths under filer2.KvDir.
+const kvPathPrefix = "/.kv/"
+
+// curl -X PUT -d 'value' "http://localhost:8888/.kv/app/config?ttl=1d"
+// curl http://localhost:8888/.kv/app/config
+// curl -X DELETE http://localhost:8888/.kv/app/config
+func (fs *FilerServer) kvHandler(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	requestType := "kv" + strings.Title(strings.ToLower(r.Method))
+	stats.FilerRequestCounter.WithLabelValues(requestType).Inc()
+	defer func() {
+		stats.FilerRequestHistogram.WithLabelValues(requestType).Observe(time.Since(start).Seconds())
+	}()
+
+	if len(fs.secret) == 0 {
+		writeJsonError(w, r, http.StatusForbidden, errors.New("the kv api needs jwt.filer_signing.key in security.toml"))
+		return
+	}
+	if err := fs.checkKvJwt(r); err != nil {
+		writeJsonError(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, kvPathPrefix)
+	if err := filer2.CheckKvKey(key); err != nil {
+		writeJsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := context.Background()
+	switch r.Method {
+	case "GET":
+		value, err := fs.filer.KvGet(ctx, key)
+		if err == filer_pb.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			glog.V(1).Infof("kv get %s: %v", key, err)
+			writeJsonError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(value)
+	case "PUT":
+		value, err := ioutil.ReadAll(io.LimitReader(r.Body, filer2.KvMaxValueLength+1))
+		if err != nil {
+			writeJsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if len(value) > filer2.KvMaxValueLength {
+			writeJsonError(w, r, http.StatusRequestEntityTooLarge, fmt.Errorf("value is larger than %d bytes", filer2.KvMaxValueLength))
+			return
+		}
+		ttl, err := needle.ReadTTL(r.FormValue("ttl"))
+		if err != nil {
+			writeJsonError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if err = fs.filer.KvPut(ctx, key, value, int32(ttl.Minutes())*60); err != nil {
+			glog.V(1).Infof("kv put %s: %v", key, err)
+			writeJsonError(w, r, writeErrorStatus(err), err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE":
+		err := fs.filer.KvDelete(ctx, key)
+		if err != nil && err != filer_pb.ErrNotFound {
+			glog.V(1).Infof("kv delete %s: %v", key, err)
+			writeJsonError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// checkKvJwt checks the jwt of the request is signed by the signing key of the filer
+func (fs *FilerServer) checkKvJwt(r *http.Request) error {
+	encodedJwt := security.GetJwt(r)
+	if encodedJwt == "" {
+		return errors.New("missing jwt")
+	}
+	token, err := security.DecodeJwt(fs.secret, encodedJwt)
+	if err != nil || !token.Valid {
+		return fmt.Errorf("invalid jwt: %v", err)
+	}
+	return nil
+}
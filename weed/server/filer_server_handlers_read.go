@@ -19,6 +19,24 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
+// extendedResponseHeaderPrefix is the prefix of the extended attributes returned as the response headers,
+// the s3 object metadata including the user metadata. The standard headers the s3 gateway keeps with the objects
+// are also returned. The other extended attributes, such as the wrapped encryption keys, the acls and the xattrs,
+// are only read with gRPC.
+const extendedResponseHeaderPrefix = "X-Amz-"
+
+var extendedResponseHeaders = map[string]bool{
+	"X-Seaweed-Cache-Control":       true,
+	"X-Seaweed-Content-Disposition": true,
+	"X-Seaweed-Content-Encoding":    true,
+	"X-Seaweed-Content-Language":    true,
+	"X-Seaweed-Expires":             true,
+}
+
+func isExtendedResponseHeader(key string) bool {
+	return strings.HasPrefix(key, extendedResponseHeaderPrefix) || extendedResponseHeaders[key]
+}
+
 func (fs *FilerServer) GetOrHeadHandler(w http.ResponseWriter, r *http.Request, isGetMethod bool) {
 
 	path := r.URL.Path
@@ -59,7 +77,14 @@ func (fs *FilerServer) GetOrHeadHandler(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	if len(entry.Chunks) == 0 {
+	// extended attributes, e.g. the ones the s3 gateway keeps on objects
+	for k, v := range entry.Extended {
+		if isExtendedResponseHeader(k) {
+			w.Header().Set(k, string(v))
+		}
+	}
+
+	if len(entry.Chunks) == 0 && len(entry.Content) == 0 {
 		glog.V(1).Infof("no file chunks for %s, attr=%+v", path, entry.Attr)
 		stats.FilerRequestCounter.WithLabelValues("read.nocontent").Inc()
 		w.WriteHeader(http.StatusNoContent)
@@ -102,24 +127,27 @@ func (fs *FilerServer) GetOrHeadHandler(w http.ResponseWriter, r *http.Request,
 	setEtag(w, etag)
 
 	if r.Method == "HEAD" {
-		w.Header().Set("Content-Length", strconv.FormatInt(int64(filer2.TotalSize(entry.Chunks)), 10))
+		w.Header().Set("Content-Length", strconv.FormatInt(int64(entry.Size()), 10))
 		return
 	}
 
 	filename := entry.Name()
 	adjustHeadersAfterHEAD(w, r, filename)
 
-	totalSize := int64(filer2.TotalSize(entry.Chunks))
+	totalSize := int64(entry.Size())
 
 	if rangeReq := r.Header.Get("Range"); rangeReq == "" {
 		ext := filepath.Ext(filename)
 		width, height, mode, shouldResize := shouldResizeImages(ext, r)
 		if shouldResize {
-			data, err := filer2.ReadAll(fs.filer.MasterClient, entry.Chunks)
-			if err != nil {
-				glog.Errorf("failed to read %s: %v", path, err)
-				w.WriteHeader(http.StatusNotModified)
-				return
+			data := entry.Content
+			if len(data) == 0 {
+				var err error
+				if data, err = filer2.ReadAll(fs.filer.MasterClient, entry.Chunks); err != nil {
+					glog.Errorf("failed to read %s: %v", path, err)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
 			}
 			rs, _, _ := images.Resized(ext, bytes.NewReader(data), width, height, mode)
 			io.Copy(w, rs)
@@ -128,6 +156,10 @@ func (fs *FilerServer) GetOrHeadHandler(w http.ResponseWriter, r *http.Request,
 	}
 
 	processRangeRequest(r, w, totalSize, mimeType, func(writer io.Writer, offset int64, size int64) error {
+		if len(entry.Content) > 0 {
+			_, err := writer.Write(entry.Content[offset : offset+size])
+			return err
+		}
 		return filer2.StreamContent(fs.filer.MasterClient, writer, entry.Chunks, offset, size)
 	})
 
@@ -0,0 +1,19 @@
+package weed_server
+
+import "testing"
+
+func TestIsExtendedResponseHeader(t *testing.T) {
+	for key, expected := range map[string]bool{
+		"X-Amz-Meta-Color":              true,
+		"X-Amz-Server-Side-Encryption":  true,
+		"X-Seaweed-Content-Disposition": true,
+		"X-Seaweed-Encryption-Key":      false,
+		"X-Seaweed-Acl":                 false,
+		"xattr-user.comment":            false,
+		"s3-encryption-configuration":   false,
+	} {
+		if isExtendedResponseHeader(key) != expected {
+			t.Errorf("extended %s as the response header: %v", key, !expected)
+		}
+	}
+}
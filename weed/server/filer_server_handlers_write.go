@@ -32,6 +32,10 @@ var (
 	OS_GID = uint32(os.Getgid())
 )
 
+// fsyncHeader asks the volume servers to flush the written chunks to disk before responding, same as ?fsync=true.
+// The s3 gateway passes it along with the other request headers, only for the admins.
+const fsyncHeader = "X-Seaweed-Fsync"
+
 type FilerPostResult struct {
 	Name  string `json:"name,omitempty"`
 	Size  int64  `json:"size,omitempty"`
@@ -46,12 +50,19 @@ func (fs *FilerServer) assignNewFileInfo(w http.ResponseWriter, r *http.Request,
 	start := time.Now()
 	defer func() { stats.FilerRequestHistogram.WithLabelValues("assign").Observe(time.Since(start).Seconds()) }()
 
+	// the disk type is required, only the data center falls back to any
+	diskType := r.URL.Query().Get("diskType")
+	if diskType == "" {
+		diskType = fs.filer.MatchPathPolicy(r.URL.Path).DiskType
+	}
 	ar := &operation.VolumeAssignRequest{
 		Count:       1,
 		Replication: replication,
 		Collection:  collection,
 		Ttl:         ttlString,
 		DataCenter:  dataCenter,
+		DiskType:    diskType,
+		AppendOnly:  fs.isAppendOnlyPath(r.URL.Path),
 	}
 	var altRequest *operation.VolumeAssignRequest
 	if dataCenter != "" {
@@ -61,6 +72,8 @@ func (fs *FilerServer) assignNewFileInfo(w http.ResponseWriter, r *http.Request,
 			Collection:  collection,
 			Ttl:         ttlString,
 			DataCenter:  "",
+			DiskType:    diskType,
+			AppendOnly:  ar.AppendOnly,
 		}
 	}
 
@@ -86,11 +99,15 @@ func (fs *FilerServer) PostHandler(w http.ResponseWriter, r *http.Request) {
 
 	query := r.URL.Query()
 	collection, replication, fsync := fs.detectCollection(r.RequestURI, query.Get("collection"), query.Get("replication"))
+	fsync = fsync || query.Get("fsync") == "true" || r.Header.Get(fsyncHeader) == "true"
 	dataCenter := query.Get("dataCenter")
 	if dataCenter == "" {
 		dataCenter = fs.option.DataCenter
 	}
 	ttlString := r.URL.Query().Get("ttl")
+	if ttlString == "" {
+		ttlString = fs.filer.MatchPathPolicy(r.URL.Path).Ttl
+	}
 
 	// read ttl in seconds
 	ttl, err := needle.ReadTTL(ttlString)
@@ -99,6 +116,16 @@ func (fs *FilerServer) PostHandler(w http.ResponseWriter, r *http.Request) {
 		ttlSeconds = int32(ttl.Minutes()) * 60
 	}
 
+	// reject the invalid paths and the writes over the directory quotas before uploading the content
+	if err := fs.filer.CheckPath(util.FullPath(r.URL.Path)); err != nil {
+		writeJsonError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := fs.filer.CheckQuota(ctx, util.FullPath(r.URL.Path), r.ContentLength); err != nil {
+		writeJsonError(w, r, http.StatusInsufficientStorage, err)
+		return
+	}
+
 	if autoChunked := fs.autoChunk(ctx, w, r, replication, collection, dataCenter, ttlSeconds, ttlString, fsync); autoChunked {
 		return
 	}
@@ -204,10 +231,10 @@ func (fs *FilerServer) updateFilerStore(ctx context.Context, r *http.Request, w
 		}
 	}
 	// glog.V(4).Infof("saving %s => %+v", path, entry)
-	if dbErr := fs.filer.CreateEntry(ctx, entry, false); dbErr != nil {
+	if dbErr := fs.createEntry(ctx, r, entry); dbErr != nil {
 		fs.filer.DeleteChunks(entry.Chunks)
 		glog.V(0).Infof("failing to write %s to filer server : %v", path, dbErr)
-		writeJsonError(w, r, http.StatusInternalServerError, dbErr)
+		writeJsonError(w, r, writeErrorStatus(dbErr), dbErr)
 		err = dbErr
 		return
 	}
@@ -301,10 +328,34 @@ func (fs *FilerServer) uploadToVolumeServer(r *http.Request, u *url.URL, auth se
 	return
 }
 
+// createEntry saves the entry, only if the If-Match and If-None-Match headers hold for the current one
+func (fs *FilerServer) createEntry(ctx context.Context, r *http.Request, entry *filer2.Entry) error {
+	ctx = auditedWrite(ctx, r)
+	if condition := filer2.ETagCondition(r.Header.Get("If-Match"), r.Header.Get("If-None-Match")); condition != nil {
+		return fs.filer.CreateEntryIf(ctx, entry, condition)
+	}
+	return fs.filer.CreateEntry(ctx, entry, false)
+}
+
+// writeErrorStatus is the http status of the errors on creating the entries
+func writeErrorStatus(err error) int {
+	switch err {
+	case filer_pb.ErrQuotaExceeded:
+		return http.StatusInsufficientStorage
+	case filer_pb.ErrNameTooLong, filer_pb.ErrInvalidName:
+		return http.StatusBadRequest
+	case filer_pb.ErrPreconditionFailed:
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusInternalServerError
+}
+
 // curl -X DELETE http://localhost:8888/path/to
 // curl -X DELETE http://localhost:8888/path/to?recursive=true
 // curl -X DELETE http://localhost:8888/path/to?recursive=true&ignoreRecursiveError=true
 // curl -X DELETE http://localhost:8888/path/to?recursive=true&skipChunkDeletion=true
+// curl -X DELETE -H 'If-Match: "etag"' http://localhost:8888/path/to
+// curl -X DELETE http://localhost:8888/path/to?recursive=true&async=true
 func (fs *FilerServer) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 
 	isRecursive := r.FormValue("recursive") == "true"
@@ -316,12 +367,24 @@ func (fs *FilerServer) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 	ignoreRecursiveError := r.FormValue("ignoreRecursiveError") == "true"
 	skipChunkDeletion := r.FormValue("skipChunkDeletion") == "true"
 
-	err := fs.filer.DeleteEntryMetaAndData(context.Background(), util.FullPath(r.URL.Path), isRecursive, ignoreRecursiveError, !skipChunkDeletion)
+	if r.FormValue("async") == "true" {
+		fs.startDeleteJob(w, r, isRecursive, ignoreRecursiveError, !skipChunkDeletion)
+		return
+	}
+
+	var err error
+	if condition := filer2.ETagCondition(r.Header.Get("If-Match"), r.Header.Get("If-None-Match")); condition != nil {
+		err = fs.filer.DeleteEntryIf(context.Background(), util.FullPath(r.URL.Path), !skipChunkDeletion, condition)
+	} else {
+		err = fs.filer.DeleteEntryMetaAndData(context.Background(), util.FullPath(r.URL.Path), isRecursive, ignoreRecursiveError, !skipChunkDeletion)
+	}
 	if err != nil {
 		glog.V(1).Infoln("deleting", r.URL.Path, ":", err.Error())
 		httpStatus := http.StatusInternalServerError
 		if err == filer_pb.ErrNotFound {
 			httpStatus = http.StatusNotFound
+		} else if err == filer_pb.ErrPreconditionFailed {
+			httpStatus = http.StatusPreconditionFailed
 		}
 		writeJsonError(w, r, httpStatus, err)
 		return
@@ -335,6 +398,19 @@ func (fs *FilerServer) detectCollection(requestURI, qCollection, qReplication st
 	collection = fs.option.Collection
 	replication = fs.option.DefaultReplication
 
+	// the path policy, unless asked otherwise
+	policyPath := requestURI
+	if t := strings.Index(policyPath, "?"); t >= 0 {
+		policyPath = policyPath[:t]
+	}
+	policy := fs.filer.MatchPathPolicy(policyPath)
+	if policy.Collection != "" {
+		collection = policy.Collection
+	}
+	if policy.Replication != "" {
+		replication = policy.Replication
+	}
+
 	// get default collection settings
 	if qCollection != "" {
 		collection = qCollection
@@ -353,8 +429,38 @@ func (fs *FilerServer) detectCollection(requestURI, qCollection, qReplication st
 		if t > 0 {
 			collection = bucketAndObjectKey[:t]
 		}
-		replication, fsync = fs.filer.ReadBucketOption(collection)
+		// the replication of the bucket, unless another one is asked for, like for the s3 storage classes, or set by the path policy
+		var bucketReplication string
+		bucketReplication, fsync = fs.filer.ReadBucketOption(collection)
+		if qReplication == "" && policy.Replication == "" {
+			replication = bucketReplication
+		}
 	}
 
 	return
 }
+
+// isAppendOnlyPath checks whether the files of the path go to append only volumes, as asked by the bucket
+func (fs *FilerServer) isAppendOnlyPath(path string) bool {
+	if !strings.HasPrefix(path, fs.filer.DirBucketsPath+"/") {
+		return false
+	}
+	bucketAndObjectKey := path[len(fs.filer.DirBucketsPath)+1:]
+	if t := strings.Index(bucketAndObjectKey, "/"); t > 0 {
+		return fs.filer.IsBucketAppendOnly(bucketAndObjectKey[:t])
+	}
+	return false
+}
+
+// pathPolicyTtlSec is the ttl in seconds set by the path policy, or 0
+func (fs *FilerServer) pathPolicyTtlSec(path string) int32 {
+	ttlString := fs.filer.MatchPathPolicy(path).Ttl
+	if ttlString == "" {
+		return 0
+	}
+	ttl, err := needle.ReadTTL(ttlString)
+	if err != nil {
+		return 0
+	}
+	return int32(ttl.Minutes()) * 60
+}
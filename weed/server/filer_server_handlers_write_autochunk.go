@@ -1,8 +1,10 @@
 package weed_server
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -20,54 +22,99 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
+const (
+	// chunkSizeHeader asks for the chunk size in bytes, instead of the -maxMB default
+	chunkSizeHeader = "X-Seaweed-Chunk-Size"
+	// the chunks are kept under the default file size limit of the volume servers
+	maxChunkSize = 256 * 1024 * 1024
+	// huge files use bigger chunks, to keep the number of chunks under this
+	maxChunkCount = 1000
+)
+
 func (fs *FilerServer) autoChunk(ctx context.Context, w http.ResponseWriter, r *http.Request,
 	replication string, collection string, dataCenter string, ttlSec int32, ttlString string, fsync bool) bool {
-	if r.Method != "POST" {
+	if r.Method != "POST" && r.Method != "PUT" {
 		glog.V(4).Infoln("AutoChunking not supported for method", r.Method)
 		return false
 	}
 
-	// autoChunking can be set at the command-line level or as a query param. Query param overrides command-line
-	query := r.URL.Query()
-
-	parsedMaxMB, _ := strconv.ParseInt(query.Get("maxMB"), 10, 32)
-	maxMB := int32(parsedMaxMB)
-	if maxMB <= 0 && fs.option.MaxMB > 0 {
-		maxMB = int32(fs.option.MaxMB)
-	}
-	if maxMB <= 0 {
-		glog.V(4).Infoln("AutoChunking not enabled")
-		return false
-	}
-	glog.V(4).Infoln("AutoChunking level set to", maxMB, "(MB)")
-
-	chunkSize := 1024 * 1024 * maxMB
-
 	contentLength := int64(0)
 	if contentLengthHeader := r.Header["Content-Length"]; len(contentLengthHeader) == 1 {
 		contentLength, _ = strconv.ParseInt(contentLengthHeader[0], 10, 64)
-		if contentLength <= int64(chunkSize) {
-			glog.V(4).Infoln("Content-Length of", contentLength, "is less than the chunk size of", chunkSize, "so autoChunking will be skipped.")
-			return false
-		}
 	}
 
-	if contentLength <= 0 {
-		glog.V(4).Infoln("Content-Length value is missing or unexpected so autoChunking will be skipped.")
+	chunkSize, err := fs.chunkSize(r, contentLength)
+	if err != nil {
+		writeJsonError(w, r, http.StatusBadRequest, err)
+		return true
+	}
+
+	// small files are saved inside the entries, unless asked not to, e.g. for the s3 multipart upload parts
+	saveInsideLimit := fs.option.SaveToFilerLimit
+	if r.URL.Query().Get("saveInside") == "false" {
+		saveInsideLimit = 0
+	}
+
+	switch {
+	case contentLength > 0 && contentLength <= int64(saveInsideLimit):
+		glog.V(4).Infoln("Content-Length of", contentLength, "is small enough to be saved inside the entry.")
+	case chunkSize <= 0:
+		glog.V(4).Infoln("AutoChunking not enabled")
+		return false
+	case contentLength <= 0:
+		glog.V(4).Infoln("Content-Length value is missing or unexpected, so the content is chunked as it streams in.")
+	case contentLength <= int64(chunkSize):
+		glog.V(4).Infoln("Content-Length of", contentLength, "is less than the chunk size of", chunkSize, "so autoChunking will be skipped.")
 		return false
 	}
+	glog.V(4).Infoln("AutoChunking with chunk size", chunkSize)
 
-	reply, err := fs.doAutoChunk(ctx, w, r, contentLength, chunkSize, replication, collection, dataCenter, ttlSec, ttlString, fsync)
+	reply, err := fs.doAutoChunk(ctx, w, r, chunkSize, saveInsideLimit, replication, collection, dataCenter, ttlSec, ttlString, fsync)
 	if err != nil {
-		writeJsonError(w, r, http.StatusInternalServerError, err)
+		writeJsonError(w, r, writeErrorStatus(err), err)
 	} else if reply != nil {
 		writeJsonQuiet(w, r, http.StatusCreated, reply)
 	}
 	return true
 }
 
+// chunkSize returns the size to split the content into, or 0 to not split it.
+// A request can ask for the size with the X-Seaweed-Chunk-Size header, or the maxMB query.
+func (fs *FilerServer) chunkSize(r *http.Request, contentLength int64) (int32, error) {
+	if header := r.Header.Get(chunkSizeHeader); header != "" {
+		size, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || size <= 0 || size > maxChunkSize {
+			return 0, fmt.Errorf("invalid %s %q, expecting 1 to %d bytes", chunkSizeHeader, header, maxChunkSize)
+		}
+		return int32(size), nil
+	}
+
+	// autoChunking can be set at the command-line level or as a query param. Query param overrides command-line
+	if maxMB, _ := strconv.ParseInt(r.URL.Query().Get("maxMB"), 10, 32); maxMB > 0 {
+		if maxMB > maxChunkSize/(1024*1024) {
+			maxMB = maxChunkSize / (1024 * 1024)
+		}
+		return int32(maxMB * 1024 * 1024), nil
+	}
+	if fs.option.MaxMB <= 0 {
+		return 0, nil
+	}
+	return defaultChunkSize(int64(fs.option.MaxMB)*1024*1024, contentLength), nil
+}
+
+// defaultChunkSize grows the chunk size in whole MB for huge files, to keep the number of chunks under maxChunkCount
+func defaultChunkSize(chunkSize, contentLength int64) int32 {
+	if contentLength > chunkSize*maxChunkCount {
+		chunkSize = ((contentLength+maxChunkCount-1)/maxChunkCount + 1024*1024 - 1) / (1024 * 1024) * (1024 * 1024)
+	}
+	if chunkSize > maxChunkSize {
+		chunkSize = maxChunkSize
+	}
+	return int32(chunkSize)
+}
+
 func (fs *FilerServer) doAutoChunk(ctx context.Context, w http.ResponseWriter, r *http.Request,
-	contentLength int64, chunkSize int32, replication string, collection string, dataCenter string, ttlSec int32, ttlString string, fsync bool) (filerResult *FilerPostResult, replyerr error) {
+	chunkSize int32, saveInsideLimit int, replication string, collection string, dataCenter string, ttlSec int32, ttlString string, fsync bool) (filerResult *FilerPostResult, replyerr error) {
 
 	stats.FilerRequestCounter.WithLabelValues("postAutoChunk").Inc()
 	start := time.Now()
@@ -75,30 +122,55 @@ func (fs *FilerServer) doAutoChunk(ctx context.Context, w http.ResponseWriter, r
 		stats.FilerRequestHistogram.WithLabelValues("postAutoChunk").Observe(time.Since(start).Seconds())
 	}()
 
-	multipartReader, multipartReaderErr := r.MultipartReader()
-	if multipartReaderErr != nil {
-		return nil, multipartReaderErr
-	}
+	var fileName, contentType string
+	var reader io.Reader = r.Body
+	if r.Method == "POST" {
+		multipartReader, multipartReaderErr := r.MultipartReader()
+		if multipartReaderErr != nil {
+			return nil, multipartReaderErr
+		}
+
+		part1, part1Err := multipartReader.NextPart()
+		if part1Err != nil {
+			return nil, part1Err
+		}
 
-	part1, part1Err := multipartReader.NextPart()
-	if part1Err != nil {
-		return nil, part1Err
+		fileName = part1.FileName()
+		if fileName != "" {
+			fileName = path.Base(fileName)
+		}
+		contentType = part1.Header.Get("Content-Type")
+		reader = part1
+	} else {
+		contentType = r.Header.Get("Content-Type")
 	}
 
-	fileName := part1.FileName()
-	if fileName != "" {
-		fileName = path.Base(fileName)
+	if strings.HasSuffix(r.URL.Path, "/") && fileName == "" {
+		return nil, fmt.Errorf("can not to write to folder %s without a file name", r.URL.Path)
 	}
-	contentType := part1.Header.Get("Content-Type")
 
 	var fileChunks []*filer_pb.FileChunk
 
 	md5Hash := md5.New()
-	var partReader = ioutil.NopCloser(io.TeeReader(part1, md5Hash))
+	var partReader = ioutil.NopCloser(io.TeeReader(reader, md5Hash))
+
+	// small files are saved inside the entry, without any chunks
+	var content []byte
+	if saveInsideLimit > 0 {
+		data, readErr := ioutil.ReadAll(io.LimitReader(partReader, int64(saveInsideLimit)+1))
+		if readErr != nil {
+			return nil, readErr
+		}
+		if len(data) <= saveInsideLimit {
+			content = data
+		} else {
+			partReader = ioutil.NopCloser(io.MultiReader(bytes.NewReader(data), partReader))
+		}
+	}
 
-	chunkOffset := int64(0)
+	chunkOffset := int64(len(content))
 
-	for chunkOffset < contentLength {
+	for content == nil {
 		limitedReader := io.LimitReader(partReader, int64(chunkSize))
 
 		// assign one file id for one chunk
@@ -110,6 +182,7 @@ func (fs *FilerServer) doAutoChunk(ctx context.Context, w http.ResponseWriter, r
 		// upload the chunk to the volume server
 		uploadResult, uploadErr := fs.doUpload(urlLocation, w, r, limitedReader, fileName, contentType, nil, auth)
 		if uploadErr != nil {
+			fs.filer.DeleteChunks(fileChunks)
 			return nil, uploadErr
 		}
 
@@ -121,7 +194,7 @@ func (fs *FilerServer) doAutoChunk(ctx context.Context, w http.ResponseWriter, r
 		// Save to chunk manifest structure
 		fileChunks = append(fileChunks, uploadResult.ToPbFileChunk(fileId, chunkOffset))
 
-		glog.V(4).Infof("uploaded %s chunk %d to %s [%d,%d) of %d", fileName, len(fileChunks), fileId, chunkOffset, chunkOffset+int64(uploadResult.Size), contentLength)
+		glog.V(4).Infof("uploaded %s chunk %d to %s [%d,%d)", fileName, len(fileChunks), fileId, chunkOffset, chunkOffset+int64(uploadResult.Size))
 
 		// reset variables for the next chunk
 		chunkOffset = chunkOffset + int64(uploadResult.Size)
@@ -154,7 +227,8 @@ func (fs *FilerServer) doAutoChunk(ctx context.Context, w http.ResponseWriter, r
 			Mime:        contentType,
 			Md5:         md5Hash.Sum(nil),
 		},
-		Chunks: fileChunks,
+		Chunks:  fileChunks,
+		Content: content,
 	}
 
 	filerResult = &FilerPostResult{
@@ -162,7 +236,7 @@ func (fs *FilerServer) doAutoChunk(ctx context.Context, w http.ResponseWriter, r
 		Size: chunkOffset,
 	}
 
-	if dbErr := fs.filer.CreateEntry(ctx, entry, false); dbErr != nil {
+	if dbErr := fs.createEntry(ctx, r, entry); dbErr != nil {
 		fs.filer.DeleteChunks(entry.Chunks)
 		replyerr = dbErr
 		filerResult.Error = dbErr.Error()
@@ -0,0 +1,54 @@
+package weed_server
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChunkSize(t *testing.T) {
+	fs := &FilerServer{option: &FilerOption{MaxMB: 4}}
+
+	testcases := []struct {
+		url           string
+		header        string
+		contentLength int64
+		expected      int32
+		expectErr     bool
+	}{
+		{url: "/a", contentLength: 10 << 20, expected: 4 << 20},
+		{url: "/a", contentLength: -1, expected: 4 << 20},
+		// huge files use bigger chunks, in whole MB
+		{url: "/a", contentLength: 10 << 30, expected: 11 << 20},
+		{url: "/a", contentLength: 4000<<20 + 1, expected: 5 << 20},
+		{url: "/a?maxMB=8", contentLength: 10 << 30, expected: 8 << 20},
+		{url: "/a?maxMB=8", header: "65536", contentLength: 10 << 30, expected: 65536},
+		{url: "/a", header: "0", expectErr: true},
+		{url: "/a", header: "2147483648", expectErr: true},
+		{url: "/a", header: "536870912", expectErr: true},
+		{url: "/a?maxMB=1024", contentLength: 10 << 30, expected: 256 << 20},
+		// the grown chunk size is capped too
+		{url: "/a", contentLength: 1 << 40, expected: 256 << 20},
+		{url: "/a", header: "abc", expectErr: true},
+	}
+
+	for i, testcase := range testcases {
+		r := httptest.NewRequest("PUT", testcase.url, nil)
+		if testcase.header != "" {
+			r.Header.Set(chunkSizeHeader, testcase.header)
+		}
+		size, err := fs.chunkSize(r, testcase.contentLength)
+		if testcase.expectErr {
+			if err == nil {
+				t.Errorf("case %d: expected error, got chunk size %d", i, size)
+			}
+			continue
+		}
+		if err != nil || size != testcase.expected {
+			t.Errorf("case %d: chunk size %d, %v, expected %d", i, size, err, testcase.expected)
+		}
+	}
+
+	if size, _ := (&FilerServer{option: &FilerOption{}}).chunkSize(httptest.NewRequest("PUT", "/a", nil), 10<<30); size != 0 {
+		t.Errorf("chunking is not enabled, but got chunk size %d", size)
+	}
+}
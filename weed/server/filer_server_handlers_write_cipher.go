@@ -2,7 +2,10 @@ package weed_server
 
 import (
 	"context"
+	"crypto/md5"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
@@ -31,6 +34,12 @@ func (fs *FilerServer) encrypt(ctx context.Context, w http.ResponseWriter, r *ht
 
 	sizeLimit := int64(fs.option.MaxMB) * 1024 * 1024
 
+	// as in the other writes, only PUT keeps the md5 of the content
+	md5Hash := md5.New()
+	if r.Method == "PUT" {
+		r.Body = ioutil.NopCloser(io.TeeReader(r.Body, md5Hash))
+	}
+
 	pu, err := needle.ParseUpload(r, sizeLimit)
 	uncompressedData := pu.Data
 	if pu.IsGzipped {
@@ -72,13 +81,16 @@ func (fs *FilerServer) encrypt(ctx context.Context, w http.ResponseWriter, r *ht
 		},
 		Chunks: fileChunks,
 	}
+	if r.Method == "PUT" {
+		entry.Attr.Md5 = md5Hash.Sum(nil)
+	}
 
 	filerResult = &FilerPostResult{
 		Name: pu.FileName,
 		Size: int64(pu.OriginalDataSize),
 	}
 
-	if dbErr := fs.filer.CreateEntry(ctx, entry, false); dbErr != nil {
+	if dbErr := fs.createEntry(ctx, r, entry); dbErr != nil {
 		fs.filer.DeleteChunks(entry.Chunks)
 		err = dbErr
 		filerResult.Error = dbErr.Error()
@@ -0,0 +1,107 @@
+package weed_server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/audit"
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/stats"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// xattrPathPrefix serves the extended attributes of the files and folders, the same ones as through the mount
+const xattrPathPrefix = "/.xattr/"
+
+// curl http://localhost:8888/.xattr/path/to/file
+// curl "http://localhost:8888/.xattr/path/to/file?name=user.tag"
+// curl -X PUT -d 'value' "http://localhost:8888/.xattr/path/to/file?name=user.tag"
+// curl -X DELETE "http://localhost:8888/.xattr/path/to/file?name=user.tag"
+func (fs *FilerServer) xattrHandler(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	requestType := "xattr" + strings.Title(strings.ToLower(r.Method))
+	stats.FilerRequestCounter.WithLabelValues(requestType).Inc()
+	defer func() {
+		stats.FilerRequestHistogram.WithLabelValues(requestType).Observe(time.Since(start).Seconds())
+	}()
+
+	path := util.FullPath("/" + strings.TrimPrefix(r.URL.Path, xattrPathPrefix))
+	if len(path) > 1 && strings.HasSuffix(string(path), "/") {
+		path = path[:len(path)-1]
+	}
+	name := r.FormValue("name")
+	if err := filer2.CheckNotKvPath(path, false); err != nil {
+		writeJsonError(w, r, http.StatusForbidden, err)
+		return
+	}
+
+	ctx := context.Background()
+	switch r.Method {
+	case "GET":
+		xattrs, err := fs.filer.GetXattrs(ctx, path)
+		if err != nil {
+			writeJsonError(w, r, xattrErrorStatus(err), err)
+			return
+		}
+		if name == "" {
+			names := make([]string, 0, len(xattrs))
+			for name := range xattrs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			writeJsonQuiet(w, r, http.StatusOK, map[string]interface{}{"names": names})
+			return
+		}
+		value, found := xattrs[name]
+		if !found {
+			writeJsonError(w, r, http.StatusNotFound, filer_pb.ErrXattrNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(value)
+	case "PUT":
+		fs.audited(w, r, audit.OperationUpdate, path, func(w http.ResponseWriter, r *http.Request) {
+			value, err := ioutil.ReadAll(io.LimitReader(r.Body, filer2.XattrMaxValueLength+1))
+			if err != nil {
+				writeJsonError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			if err = fs.filer.UpdateXattrs(ctx, path, map[string][]byte{name: value}, nil); err != nil {
+				glog.V(1).Infof("set xattr %s %s: %v", path, name, err)
+				writeJsonError(w, r, xattrErrorStatus(err), err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	case "DELETE":
+		fs.audited(w, r, audit.OperationUpdate, path, func(w http.ResponseWriter, r *http.Request) {
+			if err := fs.filer.UpdateXattrs(ctx, path, nil, []string{name}); err != nil {
+				glog.V(1).Infof("remove xattr %s %s: %v", path, name, err)
+				writeJsonError(w, r, xattrErrorStatus(err), err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func xattrErrorStatus(err error) int {
+	switch err {
+	case filer_pb.ErrNotFound, filer_pb.ErrXattrNotFound:
+		return http.StatusNotFound
+	case filer_pb.ErrXattrInvalidName:
+		return http.StatusBadRequest
+	case filer_pb.ErrXattrTooLarge, filer_pb.ErrXattrNoSpace:
+		return http.StatusRequestEntityTooLarge
+	}
+	return writeErrorStatus(err)
+}
@@ -0,0 +1,49 @@
+package weed_server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// followPeerPathPolicies subscribes to the metadata changes of the path policy file on a peer filer,
+// and reloads the path policies from the shared store when the peer updates them
+func (fs *FilerServer) followPeerPathPolicies(peer string) {
+
+	sinceNs := time.Now().UnixNano()
+	for {
+		err := pb.WithFilerClient(peer, fs.grpcDialOption, func(client filer_pb.SeaweedFilerClient) error {
+			stream, err := client.SubscribeMetadata(context.Background(), &filer_pb.SubscribeMetadataRequest{
+				ClientName: fmt.Sprintf("filer:%s:%d", fs.option.Host, fs.option.Port),
+				PathPrefix: filer2.PathPolicyFile,
+				SinceNs:    sinceNs,
+			})
+			if err != nil {
+				return fmt.Errorf("subscribe: %v", err)
+			}
+
+			for {
+				resp, listenErr := stream.Recv()
+				if listenErr == io.EOF {
+					return nil
+				}
+				if listenErr != nil {
+					return listenErr
+				}
+				glog.V(0).Infof("peer filer %s updated the path policies", peer)
+				fs.filer.ReloadPathPolicies()
+				sinceNs = resp.TsNs
+			}
+		})
+		if err != nil {
+			glog.V(0).Infof("follow path policies of peer filer %s: %v", peer, err)
+			time.Sleep(time.Second)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package weed_server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// The liveness only tells the process is serving http, while the readiness checks the dependencies,
+// such as the master and the filer store, so the load balancers and the kubernetes probes
+// stop sending the requests to a server which can not serve them.
+
+const DefaultHealthCheckTimeout = 2 * time.Second
+
+type HealthCheck func(ctx context.Context) error
+
+type HealthChecker struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]HealthCheck
+
+	sync.Mutex
+	lastSuccess map[string]time.Time
+}
+
+type DependencyHealth struct {
+	Healthy     bool
+	Error       string     `json:",omitempty"`
+	LastSuccess *time.Time `json:",omitempty"`
+}
+
+type HealthStatus struct {
+	Version      string
+	Ready        bool
+	Dependencies map[string]*DependencyHealth `json:",omitempty"`
+}
+
+func NewHealthChecker(timeout time.Duration) *HealthChecker {
+	return &HealthChecker{
+		timeout:     timeout,
+		checks:      make(map[string]HealthCheck),
+		lastSuccess: make(map[string]time.Time),
+	}
+}
+
+// AddCheck adds a dependency to the readiness, before serving the requests
+func (h *HealthChecker) AddCheck(name string, check HealthCheck) {
+	h.names = append(h.names, name)
+	h.checks[name] = check
+}
+
+// Check runs all the checks in parallel, each within the timeout
+func (h *HealthChecker) Check(ctx context.Context) *HealthStatus {
+	status := &HealthStatus{
+		Version:      util.VERSION,
+		Ready:        true,
+		Dependencies: make(map[string]*DependencyHealth),
+	}
+
+	results := make([]error, len(h.names))
+	var wg sync.WaitGroup
+	for i, name := range h.names {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			results[i] = h.runCheck(ctx, check)
+		}(i, h.checks[name])
+	}
+	wg.Wait()
+
+	h.Lock()
+	defer h.Unlock()
+	now := time.Now()
+	for i, name := range h.names {
+		dependency := &DependencyHealth{Healthy: results[i] == nil}
+		if results[i] != nil {
+			dependency.Error = results[i].Error()
+			status.Ready = false
+			glog.V(1).Infof("health check %s: %v", name, results[i])
+		} else {
+			h.lastSuccess[name] = now
+		}
+		if t, found := h.lastSuccess[name]; found {
+			dependency.LastSuccess = &t
+		}
+		status.Dependencies[name] = dependency
+	}
+	return status
+}
+
+// runCheck does not wait for the checks ignoring the context longer than the timeout
+func (h *HealthChecker) runCheck(ctx context.Context, check HealthCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// LivenessHandler serves the liveness, without checking the dependencies
+func (h *HealthChecker) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	statsHealthHandler(w, r)
+}
+
+// ReadinessHandler serves the readiness, with 503 if any dependency is unhealthy
+func (h *HealthChecker) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	status := h.Check(r.Context())
+	httpStatus := http.StatusOK
+	if !status.Ready {
+		httpStatus = http.StatusServiceUnavailable
+	}
+	writeJsonQuiet(w, r, httpStatus, status)
+}
@@ -0,0 +1,75 @@
+package weed_server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func readiness(t *testing.T, h *HealthChecker) (int, *HealthStatus) {
+	w := httptest.NewRecorder()
+	h.ReadinessHandler(w, httptest.NewRequest("GET", "/readyz", nil))
+	status := &HealthStatus{}
+	if err := json.Unmarshal(w.Body.Bytes(), status); err != nil {
+		t.Fatalf("unmarshal %s: %v", w.Body.String(), err)
+	}
+	return w.Code, status
+}
+
+func TestHealthCheckerReadiness(t *testing.T) {
+	var storeErr error
+	h := NewHealthChecker(100 * time.Millisecond)
+	h.AddCheck("master", func(ctx context.Context) error {
+		return nil
+	})
+	h.AddCheck("store", func(ctx context.Context) error {
+		return storeErr
+	})
+
+	code, status := readiness(t, h)
+	if code != http.StatusOK || !status.Ready {
+		t.Fatalf("healthy: %d %+v", code, status)
+	}
+	lastSuccess := status.Dependencies["store"].LastSuccess
+	if lastSuccess == nil {
+		t.Fatalf("no last success time")
+	}
+
+	storeErr = fmt.Errorf("store is down")
+	code, status = readiness(t, h)
+	if code != http.StatusServiceUnavailable || status.Ready {
+		t.Fatalf("unhealthy store: %d %+v", code, status)
+	}
+	store := status.Dependencies["store"]
+	if store.Healthy || store.Error != "store is down" {
+		t.Errorf("unexpected store status %+v", store)
+	}
+	if store.LastSuccess == nil || !store.LastSuccess.Equal(*lastSuccess) {
+		t.Errorf("last success %v, expecting %v", store.LastSuccess, lastSuccess)
+	}
+	if !status.Dependencies["master"].Healthy {
+		t.Errorf("unexpected master status %+v", status.Dependencies["master"])
+	}
+}
+
+func TestHealthCheckerTimeout(t *testing.T) {
+	h := NewHealthChecker(50 * time.Millisecond)
+	h.AddCheck("master", func(ctx context.Context) error {
+		// ignores the context
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	code, status := readiness(t, h)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("waited %v for the hanging check", elapsed)
+	}
+	if code != http.StatusServiceUnavailable || status.Dependencies["master"].LastSuccess != nil {
+		t.Errorf("hanging check: %d %+v", code, status.Dependencies["master"])
+	}
+}
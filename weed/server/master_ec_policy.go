@@ -0,0 +1,245 @@
+package weed_server
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+	"github.com/chrislusf/seaweedfs/weed/shell"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/topology"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+const maxEcPolicyFailures = 100
+
+// EcPolicy erasure codes the sealed volumes in the background.
+// A volume is sealed when all its replicas are readonly and not modified for a while.
+type EcPolicy struct {
+	topo          *topology.Topology
+	commandEnv    *shell.CommandEnv
+	quietFor      time.Duration
+	concurrency   int
+	checkInterval time.Duration
+	paused        int32
+
+	sync.Mutex
+	lastCheck time.Time
+	encoded   int
+	running   map[needle.VolumeId]*EcVolumeProgress
+	failures  map[needle.VolumeId]*EcVolumeProgress
+}
+
+type EcVolumeProgress struct {
+	VolumeId   needle.VolumeId
+	Collection string
+	Step       string
+	StartedAt  time.Time
+	Error      string `json:",omitempty"`
+}
+
+type EcPolicyStatus struct {
+	Paused      bool
+	QuietFor    string
+	Concurrency int
+	LastCheck   time.Time
+	Encoded     int
+	Running     []*EcVolumeProgress
+	Failures    []*EcVolumeProgress
+}
+
+type ecCandidate struct {
+	volumeId   needle.VolumeId
+	collection string
+}
+
+func (ms *MasterServer) startEcPolicy() *EcPolicy {
+	v := util.GetViper()
+	if !v.GetBool("master.erasure_coding.enabled") {
+		return nil
+	}
+	v.SetDefault("master.erasure_coding.quiet_for", "24h")
+	v.SetDefault("master.erasure_coding.concurrency", 1)
+	v.SetDefault("master.erasure_coding.check_interval_minutes", 17)
+
+	masterAddress := "localhost:" + strconv.Itoa(ms.option.Port)
+	var shellOptions shell.ShellOptions
+	shellOptions.GrpcDialOption = ms.grpcDialOption
+	shellOptions.Masters = &masterAddress
+
+	p := &EcPolicy{
+		topo:          ms.Topo,
+		commandEnv:    shell.NewCommandEnv(shellOptions),
+		quietFor:      v.GetDuration("master.erasure_coding.quiet_for"),
+		concurrency:   v.GetInt("master.erasure_coding.concurrency"),
+		checkInterval: time.Duration(v.GetInt("master.erasure_coding.check_interval_minutes")) * time.Minute,
+		running:       make(map[needle.VolumeId]*EcVolumeProgress),
+		failures:      make(map[needle.VolumeId]*EcVolumeProgress),
+	}
+	if p.concurrency <= 0 {
+		p.concurrency = 1
+	}
+	glog.V(0).Infof("erasure code volumes readonly and quiet for %v, %d at a time", p.quietFor, p.concurrency)
+
+	go p.commandEnv.MasterClient.KeepConnectedToMaster()
+
+	go func() {
+		p.commandEnv.MasterClient.WaitUntilConnected()
+		for range time.Tick(p.checkInterval) {
+			if p.topo.IsLeader() && !p.IsPaused() {
+				p.encodeSealedVolumes()
+			}
+		}
+	}()
+
+	return p
+}
+
+func (p *EcPolicy) IsPaused() bool {
+	return atomic.LoadInt32(&p.paused) == 1
+}
+
+// Pause stops starting new volumes. The volumes being encoded are not interrupted.
+func (p *EcPolicy) Pause() {
+	atomic.StoreInt32(&p.paused, 1)
+}
+
+// Resume also retries the failed volumes
+func (p *EcPolicy) Resume() {
+	p.Lock()
+	p.failures = make(map[needle.VolumeId]*EcVolumeProgress)
+	p.Unlock()
+	atomic.StoreInt32(&p.paused, 0)
+}
+
+func (p *EcPolicy) Status() *EcPolicyStatus {
+	p.Lock()
+	defer p.Unlock()
+	status := &EcPolicyStatus{
+		Paused:      p.IsPaused(),
+		QuietFor:    p.quietFor.String(),
+		Concurrency: p.concurrency,
+		LastCheck:   p.lastCheck,
+		Encoded:     p.encoded,
+	}
+	for _, progress := range p.running {
+		progressCopy := *progress
+		status.Running = append(status.Running, &progressCopy)
+	}
+	for _, progress := range p.failures {
+		progressCopy := *progress
+		status.Failures = append(status.Failures, &progressCopy)
+	}
+	sort.Slice(status.Running, func(i, j int) bool { return status.Running[i].VolumeId < status.Running[j].VolumeId })
+	sort.Slice(status.Failures, func(i, j int) bool { return status.Failures[i].VolumeId < status.Failures[j].VolumeId })
+	return status
+}
+
+func (p *EcPolicy) encodeSealedVolumes() {
+	now := time.Now()
+	candidates := selectEcCandidates(p.topo.ToTopologyInfo(), p.quietFor, now, func(vid needle.VolumeId) bool {
+		p.Lock()
+		defer p.Unlock()
+		_, failed := p.failures[vid]
+		return failed
+	})
+	p.Lock()
+	p.lastCheck = now
+	p.Unlock()
+	if len(candidates) == 0 {
+		return
+	}
+
+	// do not compete with the admin scripts or weed shell
+	p.commandEnv.Lock()
+	defer p.commandEnv.Unlock()
+
+	limit := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+	for _, candidate := range candidates {
+		if p.IsPaused() || !p.topo.IsLeader() {
+			break
+		}
+		limit <- struct{}{}
+		wg.Add(1)
+		go func(candidate ecCandidate) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			p.encodeVolume(candidate)
+		}(candidate)
+	}
+	wg.Wait()
+}
+
+func (p *EcPolicy) encodeVolume(candidate ecCandidate) {
+	progress := &EcVolumeProgress{
+		VolumeId:   candidate.volumeId,
+		Collection: candidate.collection,
+		StartedAt:  time.Now(),
+	}
+	p.Lock()
+	p.running[candidate.volumeId] = progress
+	p.Unlock()
+
+	glog.V(0).Infof("erasure code volume %d collection %q", candidate.volumeId, candidate.collection)
+	err := shell.EcEncodeVolume(p.commandEnv, candidate.collection, candidate.volumeId, func(step string) {
+		p.Lock()
+		progress.Step = step
+		p.Unlock()
+	})
+
+	p.Lock()
+	defer p.Unlock()
+	delete(p.running, candidate.volumeId)
+	if err != nil {
+		glog.Errorf("erasure code volume %d: %v", candidate.volumeId, err)
+		progress.Error = err.Error()
+		if len(p.failures) < maxEcPolicyFailures {
+			p.failures[candidate.volumeId] = progress
+		}
+		return
+	}
+	p.encoded++
+}
+
+// selectEcCandidates finds the volumes with all replicas present, readonly, local, and not modified for quietFor
+func selectEcCandidates(topologyInfo *master_pb.TopologyInfo, quietFor time.Duration, now time.Time, skip func(vid needle.VolumeId) bool) (candidates []ecCandidate) {
+	replicas := make(map[uint32][]*master_pb.VolumeInformationMessage)
+	for _, dc := range topologyInfo.DataCenterInfos {
+		for _, rack := range dc.RackInfos {
+			for _, dn := range rack.DataNodeInfos {
+				for _, v := range dn.VolumeInfos {
+					replicas[v.Id] = append(replicas[v.Id], v)
+				}
+			}
+		}
+	}
+
+	quietSince := now.Add(-quietFor).Unix()
+	for vid, volumes := range replicas {
+		rp, err := super_block.NewReplicaPlacementFromByte(byte(volumes[0].ReplicaPlacement))
+		if err != nil || len(volumes) != rp.GetCopyCount() {
+			continue
+		}
+		sealed := true
+		for _, v := range volumes {
+			if !v.ReadOnly || v.RemoteStorageName != "" || v.Size == 0 || v.ModifiedAtSecond > quietSince {
+				sealed = false
+				break
+			}
+		}
+		if sealed && !skip(needle.VolumeId(vid)) {
+			candidates = append(candidates, ecCandidate{volumeId: needle.VolumeId(vid), collection: volumes[0].Collection})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].volumeId < candidates[j].volumeId })
+	return
+}
@@ -0,0 +1,63 @@
+package weed_server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+)
+
+func TestSelectEcCandidates(t *testing.T) {
+	now := time.Now()
+	old := uint64(now.Add(-48 * time.Hour).Unix())
+	recent := uint64(now.Add(-time.Hour).Unix())
+
+	volume := func(id uint32, replicaPlacement uint32, readOnly bool, modifiedAt uint64) *master_pb.VolumeInformationMessage {
+		return &master_pb.VolumeInformationMessage{
+			Id:               id,
+			Size:             1024,
+			Collection:       "c",
+			ReadOnly:         readOnly,
+			ReplicaPlacement: replicaPlacement,
+			ModifiedAtSecond: int64(modifiedAt),
+		}
+	}
+	remote := volume(6, 0, true, old)
+	remote.RemoteStorageName = "s3.default"
+
+	topologyInfo := &master_pb.TopologyInfo{
+		DataCenterInfos: []*master_pb.DataCenterInfo{{
+			RackInfos: []*master_pb.RackInfo{{
+				DataNodeInfos: []*master_pb.DataNodeInfo{
+					{VolumeInfos: []*master_pb.VolumeInformationMessage{
+						volume(1, 0, true, old),    // sealed
+						volume(2, 0, false, old),   // writable
+						volume(3, 0, true, recent), // modified recently
+						volume(4, 1, true, old),    // sealed on both replicas
+						volume(5, 1, true, old),    // one replica is writable
+						remote,
+						volume(7, 1, true, old), // one replica is missing
+						volume(8, 0, true, old), // skipped
+					}},
+					{VolumeInfos: []*master_pb.VolumeInformationMessage{
+						volume(4, 1, true, old),
+						volume(5, 1, false, old),
+					}},
+				},
+			}},
+		}},
+	}
+
+	candidates := selectEcCandidates(topologyInfo, 24*time.Hour, now, func(vid needle.VolumeId) bool {
+		return vid == 8
+	})
+	var vids []needle.VolumeId
+	for _, candidate := range candidates {
+		vids = append(vids, candidate.volumeId)
+	}
+	if fmt.Sprint(vids) != "[1 4]" {
+		t.Errorf("unexpected candidates %v", vids)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
 	"github.com/chrislusf/seaweedfs/weed/storage/backend"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 	"github.com/chrislusf/seaweedfs/weed/topology"
 )
 
@@ -24,10 +25,13 @@ func (ms *MasterServer) SendHeartbeat(stream master_pb.Seaweed_SendHeartbeatServ
 	defer func() {
 		if dn != nil {
 
+			leaveEvent := newDataNodeStateEvent(TopologyEventDataNodeLeave, dn)
+
 			// if the volume server disconnects and reconnects quickly
 			//  the unregister and register can race with each other
 			t.UnRegisterDataNode(dn)
 			glog.V(0).Infof("unregister disconnected volume server %s:%d", dn.Ip, dn.Port)
+			ms.topologyEvents.append(leaveEvent)
 
 			message := &master_pb.VolumeLocation{
 				Url:       dn.Url(),
@@ -71,7 +75,9 @@ func (ms *MasterServer) SendHeartbeat(stream master_pb.Seaweed_SendHeartbeatServ
 			dn = rack.GetOrCreateDataNode(heartbeat.Ip,
 				int(heartbeat.Port), heartbeat.PublicUrl,
 				int64(heartbeat.MaxVolumeCount))
+			dn.DiskType = types.ToDiskType(heartbeat.DiskType)
 			glog.V(0).Infof("added volume server %v:%d", heartbeat.GetIp(), heartbeat.GetPort())
+			ms.topologyEvents.append(newDataNodeEvent(TopologyEventDataNodeJoin, dn))
 			if err := stream.Send(&master_pb.HeartbeatResponse{
 				VolumeSizeLimit:        uint64(ms.option.VolumeSizeLimitMB) * 1024 * 1024,
 				MetricsAddress:         ms.option.MetricsAddress,
@@ -93,6 +99,7 @@ func (ms *MasterServer) SendHeartbeat(stream master_pb.Seaweed_SendHeartbeatServ
 			Url:       dn.Url(),
 			PublicUrl: dn.PublicUrl,
 		}
+		changes := &topologyChanges{dn: dn}
 		if len(heartbeat.NewVolumes) > 0 || len(heartbeat.DeletedVolumes) > 0 {
 			// process delta volume ids if exists for fast volume id updates
 			for _, volInfo := range heartbeat.NewVolumes {
@@ -103,26 +110,35 @@ func (ms *MasterServer) SendHeartbeat(stream master_pb.Seaweed_SendHeartbeatServ
 			}
 			// update master internal volume layouts
 			t.IncrementalSyncDataNodeRegistration(heartbeat.NewVolumes, heartbeat.DeletedVolumes, dn)
+			changes.addVolumes(TopologyEventVolumeCreate, heartbeat.NewVolumes)
+			changes.addVolumes(TopologyEventVolumeDelete, heartbeat.DeletedVolumes)
 		}
 
 		if len(heartbeat.Volumes) > 0 || heartbeat.HasNoVolumes {
 			// process heartbeat.Volumes
 			newVolumes, deletedVolumes := t.SyncDataNodeRegistration(heartbeat.Volumes, dn)
 
+			var createdVolumes, removedVolumes []*master_pb.VolumeShortInformationMessage
 			for _, v := range newVolumes {
 				glog.V(0).Infof("master see new volume %d from %s", uint32(v.Id), dn.Url())
 				message.NewVids = append(message.NewVids, uint32(v.Id))
+				createdVolumes = append(createdVolumes, v.ToVolumeShortInformationMessage())
 			}
 			for _, v := range deletedVolumes {
 				glog.V(0).Infof("master see deleted volume %d from %s", uint32(v.Id), dn.Url())
 				message.DeletedVids = append(message.DeletedVids, uint32(v.Id))
+				removedVolumes = append(removedVolumes, v.ToVolumeShortInformationMessage())
 			}
+			changes.addVolumes(TopologyEventVolumeCreate, createdVolumes)
+			changes.addVolumes(TopologyEventVolumeDelete, removedVolumes)
 		}
 
 		if len(heartbeat.NewEcShards) > 0 || len(heartbeat.DeletedEcShards) > 0 {
 
 			// update master internal volume layouts
 			t.IncrementalSyncDataNodeEcShards(heartbeat.NewEcShards, heartbeat.DeletedEcShards, dn)
+			changes.addEcShards(TopologyEventEcShardAdd, heartbeat.NewEcShards)
+			changes.addEcShards(TopologyEventEcShardRemove, heartbeat.DeletedEcShards)
 
 			for _, s := range heartbeat.NewEcShards {
 				message.NewVids = append(message.NewVids, s.Id)
@@ -141,15 +157,20 @@ func (ms *MasterServer) SendHeartbeat(stream master_pb.Seaweed_SendHeartbeatServ
 			newShards, deletedShards := t.SyncDataNodeEcShards(heartbeat.EcShards, dn)
 
 			// broadcast the ec vid changes to master clients
+			var addedShards, removedShards []*master_pb.VolumeEcShardInformationMessage
 			for _, s := range newShards {
 				message.NewVids = append(message.NewVids, uint32(s.VolumeId))
+				addedShards = append(addedShards, s.ToVolumeEcShardInformationMessage())
 			}
 			for _, s := range deletedShards {
+				removedShards = append(removedShards, s.ToVolumeEcShardInformationMessage())
 				if dn.HasVolumesById(s.VolumeId) {
 					continue
 				}
 				message.DeletedVids = append(message.DeletedVids, uint32(s.VolumeId))
 			}
+			changes.addEcShards(TopologyEventEcShardAdd, addedShards)
+			changes.addEcShards(TopologyEventEcShardRemove, removedShards)
 
 		}
 
@@ -161,6 +182,7 @@ func (ms *MasterServer) SendHeartbeat(stream master_pb.Seaweed_SendHeartbeatServ
 			}
 			ms.clientChansLock.RUnlock()
 		}
+		ms.topologyEvents.append(changes.events...)
 
 		// tell the volume servers about the leader
 		newLeader, err := t.Leader()
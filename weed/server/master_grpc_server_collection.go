@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/chrislusf/raft"
+	"google.golang.org/grpc"
 
 	"github.com/chrislusf/seaweedfs/weed/operation"
 	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
@@ -27,15 +28,20 @@ func (ms *MasterServer) CollectionList(ctx context.Context, req *master_pb.Colle
 	return resp, nil
 }
 
-func (ms *MasterServer) CollectionDelete(ctx context.Context, req *master_pb.CollectionDeleteRequest) (*master_pb.CollectionDeleteResponse, error) {
+func (ms *MasterServer) CollectionDelete(ctx context.Context, req *master_pb.CollectionDeleteRequest) (resp *master_pb.CollectionDeleteResponse, err error) {
 
 	if !ms.Topo.IsLeader() {
-		return nil, raft.NotLeaderError
+		err = ms.withLeaderClient(ctx, func(ctx context.Context, client master_pb.SeaweedClient, opts ...grpc.CallOption) error {
+			resp, err = client.CollectionDelete(ctx, req, opts...)
+			return err
+		})
+		return
 	}
+	ms.setHandledBy(ctx)
 
-	resp := &master_pb.CollectionDeleteResponse{}
+	resp = &master_pb.CollectionDeleteResponse{}
 
-	err := ms.doDeleteNormalCollection(req.Name)
+	err = ms.doDeleteNormalCollection(req.Name)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,52 @@
+package weed_server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chrislusf/raft"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+)
+
+var (
+	masterHandledByKey = strings.ToLower(MasterHandledByHeader)
+	masterProxiedByKey = strings.ToLower(MasterProxiedByHeader)
+)
+
+// withLeaderClient runs the write operation on the leader, for the clients talking to a follower master.
+// The leader name is returned in the response header, and the request is forwarded only once,
+// so the masters do not bounce it around during an election.
+func (ms *MasterServer) withLeaderClient(ctx context.Context, fn func(ctx context.Context, client master_pb.SeaweedClient, opts ...grpc.CallOption) error) error {
+
+	if ms.Topo.RaftServer == nil || ms.Topo.RaftServer.Leader() == "" {
+		return raft.NotLeaderError
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(masterProxiedByKey)) > 0 {
+		return raft.NotLeaderError
+	}
+
+	leader := ms.Topo.RaftServer.Leader()
+	glog.V(4).Infoln("forwarding to leader", leader)
+
+	var header metadata.MD
+	forwardCtx := metadata.AppendToOutgoingContext(ctx, masterProxiedByKey, ms.masterName())
+	err := pb.WithMasterClient(leader, ms.grpcDialOption, func(client master_pb.SeaweedClient) error {
+		return fn(forwardCtx, client, grpc.Header(&header))
+	})
+	if err != nil {
+		return err
+	}
+
+	grpc.SetHeader(ctx, metadata.Join(header, metadata.Pairs(masterProxiedByKey, ms.masterName())))
+	return nil
+}
+
+// setHandledBy tells the client this master handled the request
+func (ms *MasterServer) setHandledBy(ctx context.Context) {
+	grpc.SetHeader(ctx, metadata.Pairs(masterHandledByKey, ms.masterName()))
+}
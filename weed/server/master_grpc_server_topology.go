@@ -0,0 +1,81 @@
+package weed_server
+
+import (
+	"time"
+
+	"github.com/chrislusf/raft"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+)
+
+const defaultTopologyHeartbeatSeconds = 10
+
+// SubscribeTopology streams the topology changes to the external controllers: volume servers joining and leaving,
+// volumes created and deleted, ec shards added and removed. The stream resumes after the since_sequence,
+// or starts over with a reset and the current topology if the events are no longer kept.
+// The heartbeat events carry the last sequence, and a follower master only tells the leader.
+func (ms *MasterServer) SubscribeTopology(req *master_pb.SubscribeTopologyRequest, stream master_pb.Seaweed_SubscribeTopologyServer) error {
+
+	if !ms.Topo.IsLeader() {
+		return ms.informTopologyLeader(stream)
+	}
+
+	clientName := req.ClientName + "@" + findClientAddress(stream.Context(), 0)
+	glog.V(0).Infof("+ topology subscriber %v since %d", clientName, req.SinceSequence)
+	defer glog.V(0).Infof("- topology subscriber %v", clientName)
+
+	heartbeatSeconds := req.HeartbeatSeconds
+	if heartbeatSeconds == 0 {
+		heartbeatSeconds = defaultTopologyHeartbeatSeconds
+	}
+	ticker := time.NewTicker(time.Duration(heartbeatSeconds) * time.Second)
+	defer ticker.Stop()
+
+	sequence := req.SinceSequence
+	for {
+		events, lastSequence, notify, found := ms.topologyEvents.readSince(sequence)
+		if !found {
+			glog.V(0).Infof("topology subscriber %v starts over from %d", clientName, lastSequence)
+			events = ms.topologySnapshot(lastSequence)
+		}
+		for _, event := range events {
+			if err := stream.Send(event); err != nil {
+				glog.V(0).Infof("=> topology subscriber %v: %v", clientName, err)
+				return err
+			}
+		}
+		sequence = lastSequence
+
+		select {
+		case <-notify:
+		case <-ticker.C:
+			if !ms.Topo.IsLeader() {
+				return ms.informTopologyLeader(stream)
+			}
+			if err := stream.Send(&master_pb.TopologyEvent{
+				Sequence: sequence,
+				TsNs:     time.Now().UnixNano(),
+				Type:     TopologyEventHeartbeat,
+			}); err != nil {
+				glog.V(0).Infof("=> topology subscriber %v: %v", clientName, err)
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (ms *MasterServer) informTopologyLeader(stream master_pb.Seaweed_SubscribeTopologyServer) error {
+	leader, err := ms.Topo.Leader()
+	if err != nil {
+		glog.Errorf("topo leader: %v", err)
+		return raft.NotLeaderError
+	}
+	return stream.Send(&master_pb.TopologyEvent{
+		TsNs:   time.Now().UnixNano(),
+		Type:   TopologyEventLeader,
+		Leader: leader,
+	})
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 
 	"github.com/chrislusf/raft"
+	"google.golang.org/grpc"
 
 	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
 	"github.com/chrislusf/seaweedfs/weed/security"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 	"github.com/chrislusf/seaweedfs/weed/topology"
 )
 
@@ -40,11 +42,16 @@ func (ms *MasterServer) LookupVolume(ctx context.Context, req *master_pb.LookupV
 	return resp, nil
 }
 
-func (ms *MasterServer) Assign(ctx context.Context, req *master_pb.AssignRequest) (*master_pb.AssignResponse, error) {
+func (ms *MasterServer) Assign(ctx context.Context, req *master_pb.AssignRequest) (resp *master_pb.AssignResponse, err error) {
 
 	if !ms.Topo.IsLeader() {
-		return nil, raft.NotLeaderError
+		err = ms.withLeaderClient(ctx, func(ctx context.Context, client master_pb.SeaweedClient, opts ...grpc.CallOption) error {
+			resp, err = client.Assign(ctx, req, opts...)
+			return err
+		})
+		return
 	}
+	ms.setHandledBy(ctx)
 
 	if req.Count == 0 {
 		req.Count = 1
@@ -71,11 +78,13 @@ func (ms *MasterServer) Assign(ctx context.Context, req *master_pb.AssignRequest
 		Rack:               req.Rack,
 		DataNode:           req.DataNode,
 		MemoryMapMaxSizeMb: req.MemoryMapMaxSizeMb,
+		DiskType:           types.ToDiskType(req.DiskType),
+		AppendOnly:         req.AppendOnly,
 	}
 
 	if !ms.Topo.HasWritableVolume(option) {
-		if ms.Topo.FreeSpace() <= 0 {
-			return nil, fmt.Errorf("No free volumes left!")
+		if ms.Topo.FreeSpaceOfDiskType(option.DiskType) <= 0 {
+			return nil, fmt.Errorf("No free volumes left on %s disks!", option.DiskType)
 		}
 		ms.vgLock.Lock()
 		if !ms.Topo.HasWritableVolume(option) {
@@ -118,7 +127,7 @@ func (ms *MasterServer) Statistics(ctx context.Context, req *master_pb.Statistic
 		return nil, err
 	}
 
-	volumeLayout := ms.Topo.GetVolumeLayout(req.Collection, replicaPlacement, ttl)
+	volumeLayout := ms.Topo.GetVolumeLayout(req.Collection, replicaPlacement, ttl, types.HardDriveType, false)
 	stats := volumeLayout.Stats()
 
 	totalSize := ms.Topo.GetMaxVolumeCount() * int64(ms.option.VolumeSizeLimitMB) * 1024 * 1024
@@ -188,3 +197,38 @@ func (ms *MasterServer) GetMasterConfiguration(ctx context.Context, req *master_
 
 	return resp, nil
 }
+
+func (ms *MasterServer) VacuumVolume(ctx context.Context, req *master_pb.VacuumVolumeRequest) (*master_pb.VacuumVolumeResponse, error) {
+
+	if !ms.Topo.IsLeader() {
+		return nil, raft.NotLeaderError
+	}
+
+	results, err := ms.Topo.Vacuum(ms.grpcDialOption, topology.VacuumOption{
+		GarbageThreshold: req.GarbageThreshold,
+		VolumeId:         needle.VolumeId(req.VolumeId),
+		Collections:      req.Collections,
+		DryRun:           req.DryRun,
+	}, ms.preallocateSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &master_pb.VacuumVolumeResponse{}
+	for _, result := range results {
+		volume := &master_pb.VacuumVolumeResult{
+			VolumeId:       uint32(result.VolumeId),
+			Collection:     result.Collection,
+			GarbageRatio:   result.GarbageRatio,
+			GarbageBytes:   result.GarbageBytes,
+			Vacuumed:       result.Vacuumed,
+			ReclaimedBytes: result.ReclaimedBytes,
+		}
+		if result.Error != nil {
+			volume.Error = result.Error.Error()
+		}
+		resp.Volumes = append(resp.Volumes, volume)
+	}
+
+	return resp, nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/security"
 	"github.com/chrislusf/seaweedfs/weed/sequence"
 	"github.com/chrislusf/seaweedfs/weed/shell"
+	"github.com/chrislusf/seaweedfs/weed/stats"
 	"github.com/chrislusf/seaweedfs/weed/topology"
 	"github.com/chrislusf/seaweedfs/weed/util"
 	"github.com/chrislusf/seaweedfs/weed/wdclient"
@@ -31,6 +32,13 @@ const (
 	SequencerEtcdUrls = "master.sequencer.sequencer_etcd_urls"
 )
 
+const (
+	// MasterHandledByHeader tells which master handled the request, for debugging
+	MasterHandledByHeader = "X-Seaweed-Master"
+	// MasterProxiedByHeader tells which follower master proxied the request to the leader
+	MasterProxiedByHeader = "X-Seaweed-Master-Proxied-By"
+)
+
 type MasterOption struct {
 	Host                    string
 	Port                    int
@@ -62,11 +70,17 @@ type MasterServer struct {
 	clientChansLock sync.RWMutex
 	clientChans     map[string]chan *master_pb.VolumeLocation
 
+	// streaming topology changes to external controllers
+	topologyEvents *topologyEventLog
+
 	grpcDialOption grpc.DialOption
 
 	MasterClient *wdclient.MasterClient
 
 	adminLocks          *AdminLocks
+
+	ecPolicy         *EcPolicy
+	volumeRebalancer *VolumeRebalancer
 }
 
 func NewMasterServer(r *mux.Router, option *MasterOption, peers []string) *MasterServer {
@@ -93,6 +107,7 @@ func NewMasterServer(r *mux.Router, option *MasterOption, peers []string) *Maste
 		option:          option,
 		preallocateSize: preallocateSize,
 		clientChans:     make(map[string]chan *master_pb.VolumeLocation),
+		topologyEvents:  newTopologyEventLog(topologyEventLogSize),
 		grpcDialOption:  grpcDialOption,
 		MasterClient:    wdclient.NewMasterClient(grpcDialOption, "master", option.Host, 0, peers),
 		adminLocks:      NewAdminLocks(),
@@ -105,6 +120,19 @@ func NewMasterServer(r *mux.Router, option *MasterOption, peers []string) *Maste
 	}
 	ms.Topo = topology.NewTopology("topo", seq, uint64(ms.option.VolumeSizeLimitMB)*1024*1024, ms.option.PulseSeconds, replicationAsMin)
 	ms.vg = topology.NewDefaultVolumeGrowth()
+	v.SetDefault("master.replication.best_effort_placement", false)
+	ms.vg.BestEffortPlacement = v.GetBool("master.replication.best_effort_placement")
+	volumeGrowthStrategy, err := topology.LoadVolumeGrowthStrategy(v)
+	if err != nil {
+		glog.Fatalf("%v", err)
+	}
+	ms.vg.Strategy = volumeGrowthStrategy
+	collectionGarbageThresholds, err := topology.ParseCollectionGarbageThresholds(v.GetStringSlice("master.vacuum.collection_garbage_thresholds"))
+	if err != nil {
+		glog.Fatalf("master.vacuum.collection_garbage_thresholds: %v", err)
+	}
+	ms.Topo.SetGarbageThresholds(ms.option.GarbageThreshold, collectionGarbageThresholds)
+	glog.V(0).Infof("volume growth strategy: %s", volumeGrowthStrategy.GetName())
 	glog.V(0).Infoln("Volume Size Limit is", ms.option.VolumeSizeLimitMB, "MB")
 
 	ms.guard = security.NewGuard(ms.option.WhiteList, signingKey, expiresAfterSec, readSigningKey, readExpiresAfterSec)
@@ -114,12 +142,19 @@ func NewMasterServer(r *mux.Router, option *MasterOption, peers []string) *Maste
 		r.HandleFunc("/", ms.proxyToLeader(ms.uiStatusHandler))
 		r.HandleFunc("/ui/index.html", ms.uiStatusHandler)
 		r.HandleFunc("/dir/assign", ms.proxyToLeader(ms.guard.WhiteList(ms.dirAssignHandler)))
-		r.HandleFunc("/dir/lookup", ms.guard.WhiteList(ms.dirLookupHandler))
+		r.HandleFunc("/dir/lookup", ms.handledLocally(ms.guard.WhiteList(ms.dirLookupHandler)))
 		r.HandleFunc("/dir/status", ms.proxyToLeader(ms.guard.WhiteList(ms.dirStatusHandler)))
 		r.HandleFunc("/col/delete", ms.proxyToLeader(ms.guard.WhiteList(ms.collectionDeleteHandler)))
 		r.HandleFunc("/vol/grow", ms.proxyToLeader(ms.guard.WhiteList(ms.volumeGrowHandler)))
 		r.HandleFunc("/vol/status", ms.proxyToLeader(ms.guard.WhiteList(ms.volumeStatusHandler)))
 		r.HandleFunc("/vol/vacuum", ms.proxyToLeader(ms.guard.WhiteList(ms.volumeVacuumHandler)))
+		r.HandleFunc("/vol/readonly", ms.proxyToLeader(ms.guard.WhiteList(ms.volumeReadonlyHandler)))
+		r.HandleFunc("/vol/rebalance", ms.proxyToLeader(ms.guard.WhiteList(ms.volumeRebalanceHandler)))
+		r.HandleFunc("/vol/rebalance/status", ms.proxyToLeader(ms.guard.WhiteList(ms.volumeRebalanceStatusHandler)))
+		r.HandleFunc("/vol/rebalance/stop", ms.proxyToLeader(ms.guard.WhiteList(ms.volumeRebalanceStopHandler)))
+		r.HandleFunc("/ec/status", ms.proxyToLeader(ms.guard.WhiteList(ms.ecPolicyStatusHandler)))
+		r.HandleFunc("/ec/pause", ms.proxyToLeader(ms.guard.WhiteList(ms.ecPolicyPauseHandler)))
+		r.HandleFunc("/ec/resume", ms.proxyToLeader(ms.guard.WhiteList(ms.ecPolicyResumeHandler)))
 		r.HandleFunc("/submit", ms.guard.WhiteList(ms.submitFromMasterServerHandler))
 		/*
 			r.HandleFunc("/stats/health", ms.guard.WhiteList(statsHealthHandler))
@@ -129,10 +164,18 @@ func NewMasterServer(r *mux.Router, option *MasterOption, peers []string) *Maste
 		r.HandleFunc("/{fileId}", ms.redirectHandler)
 	}
 
-	ms.Topo.StartRefreshWritableVolumes(ms.grpcDialOption, ms.option.GarbageThreshold, ms.preallocateSize)
+	ms.Topo.StartRefreshWritableVolumes(ms.grpcDialOption, ms.preallocateSize)
 
 	ms.startAdminScripts()
 
+	ms.ecPolicy = ms.startEcPolicy()
+	ms.volumeRebalancer = ms.newVolumeRebalancer()
+
+	go stats.LoopPushingMetric("master", fmt.Sprintf("%s:%d", option.Host, option.Port), stats.MasterGather,
+		func() (addr string, intervalSeconds int) {
+			return option.MetricsAddress, option.MetricsIntervalSec
+		})
+
 	return ms
 }
 
@@ -159,7 +202,7 @@ func (ms *MasterServer) SetRaftServer(raftServer *RaftServer) {
 func (ms *MasterServer) proxyToLeader(f http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if ms.Topo.IsLeader() {
-			f(w, r)
+			ms.handledLocally(f)(w, r)
 		} else if ms.Topo.RaftServer != nil && ms.Topo.RaftServer.Leader() != "" {
 			ms.bounedLeaderChan <- 1
 			defer func() { <-ms.bounedLeaderChan }()
@@ -179,15 +222,33 @@ func (ms *MasterServer) proxyToLeader(f http.HandlerFunc) http.HandlerFunc {
 				}
 				director(req)
 			}
+			proxy.ModifyResponse = func(resp *http.Response) error {
+				resp.Header.Set(MasterProxiedByHeader, ms.masterName())
+				return nil
+			}
 			proxy.Transport = util.Transport
 			proxy.ServeHTTP(w, r)
 		} else {
-			// drop it to the floor
-			// writeJsonError(w, r, errors.New(ms.Topo.RaftServer.Name()+" does not know Leader yet:"+ms.Topo.RaftServer.Leader()))
+			writeJsonError(w, r, http.StatusServiceUnavailable, fmt.Errorf("%s does not know the leader yet", ms.masterName()))
 		}
 	}
 }
 
+// handledLocally serves the request on this master, e.g. the lookups which the followers can answer
+func (ms *MasterServer) handledLocally(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(MasterHandledByHeader, ms.masterName())
+		f(w, r)
+	}
+}
+
+func (ms *MasterServer) masterName() string {
+	if ms.Topo.RaftServer != nil {
+		return ms.Topo.RaftServer.Name()
+	}
+	return fmt.Sprintf("%s:%d", ms.option.Host, ms.option.Port)
+}
+
 func (ms *MasterServer) startAdminScripts() {
 	var err error
 
@@ -13,6 +13,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/storage/backend/memory_map"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 	"github.com/chrislusf/seaweedfs/weed/topology"
 	"github.com/chrislusf/seaweedfs/weed/util"
 )
@@ -51,7 +52,8 @@ func (ms *MasterServer) dirStatusHandler(w http.ResponseWriter, r *http.Request)
 
 func (ms *MasterServer) volumeVacuumHandler(w http.ResponseWriter, r *http.Request) {
 	gcString := r.FormValue("garbageThreshold")
-	gcThreshold := ms.option.GarbageThreshold
+	// 0 for the threshold of each collection
+	var gcThreshold float64
 	if gcString != "" {
 		var err error
 		gcThreshold, err = strconv.ParseFloat(gcString, 32)
@@ -62,7 +64,10 @@ func (ms *MasterServer) volumeVacuumHandler(w http.ResponseWriter, r *http.Reque
 		}
 	}
 	// glog.Infoln("garbageThreshold =", gcThreshold)
-	ms.Topo.Vacuum(ms.grpcDialOption, gcThreshold, ms.preallocateSize)
+	if _, err := ms.Topo.Vacuum(ms.grpcDialOption, topology.VacuumOption{GarbageThreshold: gcThreshold}, ms.preallocateSize); err != nil {
+		writeJsonError(w, r, http.StatusConflict, err)
+		return
+	}
 	ms.dirStatusHandler(w, r)
 }
 
@@ -75,8 +80,8 @@ func (ms *MasterServer) volumeGrowHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	if count, err = strconv.Atoi(r.FormValue("count")); err == nil {
-		if ms.Topo.FreeSpace() < int64(count*option.ReplicaPlacement.GetCopyCount()) {
-			err = fmt.Errorf("only %d volumes left, not enough for %d", ms.Topo.FreeSpace(), count*option.ReplicaPlacement.GetCopyCount())
+		if freeSpace := ms.Topo.FreeSpaceOfDiskType(option.DiskType); freeSpace < int64(count*option.ReplicaPlacement.GetCopyCount()) {
+			err = fmt.Errorf("only %d volumes left on %s disks, not enough for %d", freeSpace, option.DiskType, count*option.ReplicaPlacement.GetCopyCount())
 		} else {
 			count, err = ms.vg.GrowByCountAndType(ms.grpcDialOption, count, option, ms.Topo)
 		}
@@ -91,6 +96,163 @@ func (ms *MasterServer) volumeGrowHandler(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// volumeReadonlyHandler marks one volume, or a whole volume server, readonly or writable again.
+// Readonly volumes are not assigned for writes, but still serve reads.
+func (ms *MasterServer) volumeReadonlyHandler(w http.ResponseWriter, r *http.Request) {
+	readonly, err := strconv.ParseBool(r.FormValue("readonly"))
+	if err != nil {
+		writeJsonError(w, r, http.StatusBadRequest, fmt.Errorf("can not parse parameter readonly %s", r.FormValue("readonly")))
+		return
+	}
+	node := r.FormValue("node")
+	var dn *topology.DataNode
+	if node != "" {
+		if dn = ms.Topo.FindDataNode(node); dn == nil {
+			writeJsonError(w, r, http.StatusNotFound, fmt.Errorf("volume server %s not found", node))
+			return
+		}
+	}
+
+	if r.FormValue("volumeId") == "" {
+		if dn == nil {
+			writeJsonError(w, r, http.StatusBadRequest, fmt.Errorf("missing parameter volumeId or node"))
+			return
+		}
+		err = operation.WithVolumeServerClient(dn.Url(), ms.grpcDialOption, func(client volume_server_pb.VolumeServerClient) error {
+			_, markErr := client.VolumeServerMarkReadonly(context.Background(), &volume_server_pb.VolumeServerMarkReadonlyRequest{
+				Readonly: readonly,
+			})
+			return markErr
+		})
+		if err != nil {
+			writeJsonError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		// becoming writable again is picked up from the next heartbeat
+		if readonly {
+			for _, v := range dn.GetVolumes() {
+				ms.Topo.SetVolumeReadOnly(dn, v.Id, true)
+			}
+		}
+		writeJsonQuiet(w, r, http.StatusOK, map[string]interface{}{"node": dn.Url(), "readonly": readonly})
+		return
+	}
+
+	vid, err := needle.NewVolumeId(r.FormValue("volumeId"))
+	if err != nil {
+		writeJsonError(w, r, http.StatusBadRequest, fmt.Errorf("can not parse parameter volumeId %s", r.FormValue("volumeId")))
+		return
+	}
+	dataNodes := ms.Topo.Lookup("", vid)
+	if dn != nil {
+		dataNodes = []*topology.DataNode{dn}
+	}
+	if len(dataNodes) == 0 {
+		writeJsonError(w, r, http.StatusNotFound, fmt.Errorf("volume %d not found", vid))
+		return
+	}
+	var servers []string
+	for _, location := range dataNodes {
+		err = operation.WithVolumeServerClient(location.Url(), ms.grpcDialOption, func(client volume_server_pb.VolumeServerClient) error {
+			if readonly {
+				_, markErr := client.VolumeMarkReadonly(context.Background(), &volume_server_pb.VolumeMarkReadonlyRequest{
+					VolumeId: uint32(vid),
+				})
+				return markErr
+			}
+			_, markErr := client.VolumeMarkWritable(context.Background(), &volume_server_pb.VolumeMarkWritableRequest{
+				VolumeId: uint32(vid),
+			})
+			return markErr
+		})
+		if err != nil {
+			writeJsonError(w, r, http.StatusInternalServerError, fmt.Errorf("mark volume %d on %s: %v", vid, location.Url(), err))
+			return
+		}
+		if readonly {
+			ms.Topo.SetVolumeReadOnly(location, vid, true)
+		}
+		servers = append(servers, location.Url())
+	}
+	writeJsonQuiet(w, r, http.StatusOK, map[string]interface{}{"volumeId": vid, "servers": servers, "readonly": readonly})
+}
+
+// volumeRebalanceHandler moves volumes from fuller to emptier volume servers in the background.
+// With dryRun=true, it only returns the planned moves.
+func (ms *MasterServer) volumeRebalanceHandler(w http.ResponseWriter, r *http.Request) {
+	option := VolumeRebalanceOption{
+		Collection: r.FormValue("collection"),
+		DataCenter: r.FormValue("dataCenter"),
+	}
+	var err error
+	if r.FormValue("maxMoves") != "" {
+		if option.MaxMoves, err = strconv.Atoi(r.FormValue("maxMoves")); err != nil || option.MaxMoves < 0 {
+			writeJsonError(w, r, http.StatusBadRequest, fmt.Errorf("can not parse parameter maxMoves %s", r.FormValue("maxMoves")))
+			return
+		}
+	}
+	if r.FormValue("bandwidthMBps") != "" {
+		bandwidthMBps, err := strconv.ParseInt(r.FormValue("bandwidthMBps"), 10, 64)
+		if err != nil || bandwidthMBps < 0 {
+			writeJsonError(w, r, http.StatusBadRequest, fmt.Errorf("can not parse parameter bandwidthMBps %s", r.FormValue("bandwidthMBps")))
+			return
+		}
+		option.BytesPerSecond = bandwidthMBps * 1024 * 1024
+	}
+
+	if dryRun, _ := strconv.ParseBool(r.FormValue("dryRun")); dryRun {
+		moves := ms.volumeRebalancer.Plan(option)
+		var totalBytes uint64
+		for _, move := range moves {
+			totalBytes += move.Size
+		}
+		writeJsonQuiet(w, r, http.StatusOK, map[string]interface{}{"moves": moves, "bytes": totalBytes})
+		return
+	}
+
+	if err = ms.volumeRebalancer.Start(option); err != nil {
+		writeJsonError(w, r, http.StatusConflict, err)
+		return
+	}
+	writeJsonQuiet(w, r, http.StatusAccepted, ms.volumeRebalancer.Status())
+}
+
+func (ms *MasterServer) volumeRebalanceStatusHandler(w http.ResponseWriter, r *http.Request) {
+	writeJsonQuiet(w, r, http.StatusOK, ms.volumeRebalancer.Status())
+}
+
+// volumeRebalanceStopHandler skips the remaining moves, after the volume being moved
+func (ms *MasterServer) volumeRebalanceStopHandler(w http.ResponseWriter, r *http.Request) {
+	ms.volumeRebalancer.Stop()
+	writeJsonQuiet(w, r, http.StatusOK, ms.volumeRebalancer.Status())
+}
+
+func (ms *MasterServer) ecPolicyStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.ecPolicy == nil {
+		writeJsonError(w, r, http.StatusNotFound, fmt.Errorf("master.erasure_coding is not enabled"))
+		return
+	}
+	writeJsonQuiet(w, r, http.StatusOK, ms.ecPolicy.Status())
+}
+
+func (ms *MasterServer) ecPolicyPauseHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.ecPolicy == nil {
+		writeJsonError(w, r, http.StatusNotFound, fmt.Errorf("master.erasure_coding is not enabled"))
+		return
+	}
+	ms.ecPolicy.Pause()
+	writeJsonQuiet(w, r, http.StatusOK, ms.ecPolicy.Status())
+}
+
+func (ms *MasterServer) ecPolicyResumeHandler(w http.ResponseWriter, r *http.Request) {
+	if ms.ecPolicy == nil {
+		writeJsonError(w, r, http.StatusNotFound, fmt.Errorf("master.erasure_coding is not enabled"))
+		return
+	}
+	ms.ecPolicy.Resume()
+	writeJsonQuiet(w, r, http.StatusOK, ms.ecPolicy.Status())
+}
+
 func (ms *MasterServer) volumeStatusHandler(w http.ResponseWriter, r *http.Request) {
 	m := make(map[string]interface{})
 	m["Version"] = util.VERSION
@@ -136,7 +298,7 @@ func (ms *MasterServer) submitFromMasterServerHandler(w http.ResponseWriter, r *
 }
 
 func (ms *MasterServer) HasWritableVolume(option *topology.VolumeGrowOption) bool {
-	vl := ms.Topo.GetVolumeLayout(option.Collection, option.ReplicaPlacement, option.Ttl)
+	vl := ms.Topo.GetVolumeLayout(option.Collection, option.ReplicaPlacement, option.Ttl, option.DiskType, option.AppendOnly)
 	return vl.GetActiveVolumeCount(option) > 0
 }
 
@@ -174,6 +336,8 @@ func (ms *MasterServer) getVolumeGrowOption(r *http.Request) (*topology.VolumeGr
 		Rack:               r.FormValue("rack"),
 		DataNode:           r.FormValue("dataNode"),
 		MemoryMapMaxSizeMb: memoryMapMaxSizeMb,
+		DiskType:           types.ToDiskType(r.FormValue("diskType")),
+		AppendOnly:         r.FormValue("appendOnly") == "true",
 	}
 	return volumeGrowOption, nil
 }
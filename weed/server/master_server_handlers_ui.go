@@ -19,12 +19,17 @@ func (ms *MasterServer) uiStatusHandler(w http.ResponseWriter, r *http.Request)
 		RaftServer raft.Server
 		Stats      map[string]interface{}
 		Counters   *stats.ServerStats
+		EcPolicy   *EcPolicyStatus
 	}{
 		util.VERSION,
 		ms.Topo.ToMap(),
 		ms.Topo.RaftServer,
 		infos,
 		serverStats,
+		nil,
+	}
+	if ms.ecPolicy != nil {
+		args.EcPolicy = ms.ecPolicy.Status()
 	}
 	ui.StatusTpl.Execute(w, args)
 }
@@ -0,0 +1,160 @@
+package weed_server
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chrislusf/raft"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/chrislusf/seaweedfs/weed/pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+	"github.com/chrislusf/seaweedfs/weed/topology"
+)
+
+// fakeRaftServer is a raft member of a settled cluster
+type fakeRaftServer struct {
+	raft.Server
+	name   string
+	leader *string
+}
+
+func (s *fakeRaftServer) Name() string   { return s.name }
+func (s *fakeRaftServer) Leader() string { return *s.leader }
+func (s *fakeRaftServer) State() string {
+	if s.name == *s.leader {
+		return raft.Leader
+	}
+	return raft.Follower
+}
+
+func TestFollowerProxiesWritesToLeader(t *testing.T) {
+
+	var leader string
+	var masters []*httptest.Server
+	for i := 0; i < 3; i++ {
+		server := httptest.NewUnstartedServer(nil)
+		name := server.Listener.Addr().String()
+		ms := &MasterServer{
+			Topo:             &topology.Topology{RaftServer: &fakeRaftServer{name: name, leader: &leader}},
+			bounedLeaderChan: make(chan int, 16),
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/vol/grow", ms.proxyToLeader(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("grown by " + name))
+		}))
+		mux.HandleFunc("/dir/lookup", ms.handledLocally(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("looked up by " + name))
+		}))
+		server.Config.Handler = mux
+		server.Start()
+		defer server.Close()
+		masters = append(masters, server)
+	}
+	leader = masters[0].Listener.Addr().String()
+	follower := masters[2].Listener.Addr().String()
+
+	get := func(url string) (*http.Response, string) {
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatalf("get %s: %v", url, err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, body := get(masters[2].URL + "/vol/grow")
+	if body != "grown by "+leader {
+		t.Errorf("write to the follower: %s", body)
+	}
+	if resp.Header.Get(MasterHandledByHeader) != leader || resp.Header.Get(MasterProxiedByHeader) != follower {
+		t.Errorf("write to the follower headers: %v", resp.Header)
+	}
+
+	resp, body = get(masters[0].URL + "/vol/grow")
+	if body != "grown by "+leader || resp.Header.Get(MasterProxiedByHeader) != "" {
+		t.Errorf("write to the leader: %s %v", body, resp.Header)
+	}
+
+	resp, body = get(masters[2].URL + "/dir/lookup")
+	if body != "looked up by "+follower || resp.Header.Get(MasterHandledByHeader) != follower {
+		t.Errorf("lookup on the follower: %s %v", body, resp.Header)
+	}
+
+	// a new leader is elected
+	leader = masters[1].Listener.Addr().String()
+	resp, body = get(masters[0].URL + "/vol/grow")
+	if body != "grown by "+leader || resp.Header.Get(MasterHandledByHeader) != leader {
+		t.Errorf("write to the old leader: %s %v", body, resp.Header)
+	}
+}
+
+func TestFollowerForwardsGrpcWritesToLeader(t *testing.T) {
+
+	// each master has its own view of the leader
+	var names []string
+	leaders := make([]string, 3)
+	for i := 0; i < 3; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		// the grpc port of a master is its http port + 10000
+		name := fmt.Sprintf("127.0.0.1:%d", listener.Addr().(*net.TCPAddr).Port-10000)
+		ms := newTopologyEventsMasterServer(name, &leaders[i])
+		ms.grpcDialOption = grpc.WithInsecure()
+		grpcServer := grpc.NewServer()
+		master_pb.RegisterSeaweedServer(grpcServer, ms)
+		go grpcServer.Serve(listener)
+		defer grpcServer.Stop()
+		names = append(names, name)
+	}
+	setLeader := func(leader string) {
+		for i := range leaders {
+			leaders[i] = leader
+		}
+	}
+
+	deleteCollection := func(master string) (handledBy, proxiedBy string, err error) {
+		var header metadata.MD
+		err = pb.WithMasterClient(master, grpc.WithInsecure(), func(client master_pb.SeaweedClient) error {
+			_, deleteErr := client.CollectionDelete(context.Background(), &master_pb.CollectionDeleteRequest{Name: "c"}, grpc.Header(&header))
+			return deleteErr
+		})
+		if values := header.Get(masterHandledByKey); len(values) > 0 {
+			handledBy = values[0]
+		}
+		if values := header.Get(masterProxiedByKey); len(values) > 0 {
+			proxiedBy = values[0]
+		}
+		return
+	}
+
+	setLeader(names[0])
+	if handledBy, proxiedBy, err := deleteCollection(names[2]); err != nil || handledBy != names[0] || proxiedBy != names[2] {
+		t.Errorf("write to the follower: handled by %s proxied by %s: %v", handledBy, proxiedBy, err)
+	}
+	if handledBy, proxiedBy, err := deleteCollection(names[0]); err != nil || handledBy != names[0] || proxiedBy != "" {
+		t.Errorf("write to the leader: handled by %s proxied by %s: %v", handledBy, proxiedBy, err)
+	}
+
+	// a new leader is elected
+	setLeader(names[1])
+	if handledBy, proxiedBy, err := deleteCollection(names[0]); err != nil || handledBy != names[1] || proxiedBy != names[0] {
+		t.Errorf("write to the old leader: handled by %s proxied by %s: %v", handledBy, proxiedBy, err)
+	}
+
+	// during the election, a write is forwarded only once
+	leaders[2] = names[0]
+	if _, _, err := deleteCollection(names[2]); err == nil || !strings.Contains(err.Error(), raft.NotLeaderError.Error()) {
+		t.Errorf("write forwarded to a follower: %v", err)
+	}
+}
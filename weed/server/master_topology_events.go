@@ -0,0 +1,149 @@
+package weed_server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+	"github.com/chrislusf/seaweedfs/weed/topology"
+)
+
+// The types of the topology events streamed to the external controllers by SubscribeTopology
+const (
+	TopologyEventReset         = "reset"
+	TopologyEventHeartbeat     = "heartbeat"
+	TopologyEventLeader        = "leader"
+	TopologyEventDataNodeJoin  = "data_node_join"
+	TopologyEventDataNodeLeave = "data_node_leave"
+	TopologyEventVolumeCreate  = "volume_create"
+	TopologyEventVolumeDelete  = "volume_delete"
+	TopologyEventEcShardAdd    = "ec_shard_add"
+	TopologyEventEcShardRemove = "ec_shard_remove"
+)
+
+const topologyEventLogSize = 10000
+
+// topologyEventLog keeps the latest topology events in a ring buffer, for the subscribers to resume from a sequence.
+// The sequences start from the creation time in nanoseconds, so the sequences of a previous leader
+// are not mistaken for the sequences of this master.
+type topologyEventLog struct {
+	sync.RWMutex
+	events       []*master_pb.TopologyEvent
+	next         int // position of the next event in the ring buffer
+	count        int // number of the events kept
+	lastSequence uint64
+	notify       chan struct{} // closed when events are appended
+}
+
+func newTopologyEventLog(size int) *topologyEventLog {
+	return &topologyEventLog{
+		events:       make([]*master_pb.TopologyEvent, size),
+		lastSequence: uint64(time.Now().UnixNano()),
+		notify:       make(chan struct{}),
+	}
+}
+
+// append assigns the sequences to the events, and wakes up the subscribers
+func (l *topologyEventLog) append(events ...*master_pb.TopologyEvent) {
+	if len(events) == 0 {
+		return
+	}
+	tsNs := time.Now().UnixNano()
+
+	l.Lock()
+	defer l.Unlock()
+
+	for _, event := range events {
+		l.lastSequence++
+		event.Sequence, event.TsNs = l.lastSequence, tsNs
+		l.events[l.next] = event
+		l.next = (l.next + 1) % len(l.events)
+		if l.count < len(l.events) {
+			l.count++
+		}
+	}
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// readSince returns the events after the sequence, the last sequence, and the channel closed on the next events.
+// found is false if the events after the sequence are no longer kept, or the sequence is unknown to this master.
+func (l *topologyEventLog) readSince(sequence uint64) (events []*master_pb.TopologyEvent, lastSequence uint64, notify <-chan struct{}, found bool) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if sequence > l.lastSequence || l.lastSequence-sequence > uint64(l.count) {
+		return nil, l.lastSequence, l.notify, false
+	}
+	for i := int(l.lastSequence - sequence); i > 0; i-- {
+		events = append(events, l.events[(l.next-i+len(l.events))%len(l.events)])
+	}
+	return events, l.lastSequence, l.notify, true
+}
+
+func newDataNodeEvent(eventType string, dn *topology.DataNode) *master_pb.TopologyEvent {
+	return &master_pb.TopologyEvent{
+		Type:       eventType,
+		DataCenter: string(dn.GetDataCenter().Id()),
+		Rack:       string(dn.GetRack().Id()),
+		Url:        dn.Url(),
+		PublicUrl:  dn.PublicUrl,
+	}
+}
+
+// newDataNodeStateEvent tells the volumes and the ec shards of the volume server
+func newDataNodeStateEvent(eventType string, dn *topology.DataNode) *master_pb.TopologyEvent {
+	event := newDataNodeEvent(eventType, dn)
+	for _, v := range dn.GetVolumes() {
+		event.Volumes = append(event.Volumes, v.ToVolumeShortInformationMessage())
+	}
+	for _, s := range dn.GetEcShards() {
+		event.EcShards = append(event.EcShards, s.ToVolumeEcShardInformationMessage())
+	}
+	return event
+}
+
+// topologySnapshot starts over the subscribers with the current topology: a reset event,
+// then a data_node_join event with the volumes and the ec shards of each volume server, all of the same sequence.
+func (ms *MasterServer) topologySnapshot(sequence uint64) (events []*master_pb.TopologyEvent) {
+	tsNs := time.Now().UnixNano()
+	events = append(events, &master_pb.TopologyEvent{
+		Sequence: sequence,
+		TsNs:     tsNs,
+		Type:     TopologyEventReset,
+	})
+	for _, c := range ms.Topo.Children() {
+		for _, r := range c.Children() {
+			for _, d := range r.Children() {
+				event := newDataNodeStateEvent(TopologyEventDataNodeJoin, d.(*topology.DataNode))
+				event.Sequence, event.TsNs = sequence, tsNs
+				events = append(events, event)
+			}
+		}
+	}
+	return
+}
+
+// topologyChanges collects the topology events of a heartbeat from the volume server
+type topologyChanges struct {
+	dn     *topology.DataNode
+	events []*master_pb.TopologyEvent
+}
+
+func (c *topologyChanges) addVolumes(eventType string, volumes []*master_pb.VolumeShortInformationMessage) {
+	if len(volumes) == 0 {
+		return
+	}
+	event := newDataNodeEvent(eventType, c.dn)
+	event.Volumes = volumes
+	c.events = append(c.events, event)
+}
+
+func (c *topologyChanges) addEcShards(eventType string, ecShards []*master_pb.VolumeEcShardInformationMessage) {
+	if len(ecShards) == 0 {
+		return
+	}
+	event := newDataNodeEvent(eventType, c.dn)
+	event.EcShards = ecShards
+	c.events = append(c.events, event)
+}
@@ -0,0 +1,197 @@
+package weed_server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+	"github.com/chrislusf/seaweedfs/weed/sequence"
+	"github.com/chrislusf/seaweedfs/weed/topology"
+)
+
+func TestTopologyEventLog(t *testing.T) {
+	l := newTopologyEventLog(3)
+	_, start, _, _ := l.readSince(0)
+
+	for i := 0; i < 2; i++ {
+		l.append(&master_pb.TopologyEvent{Type: TopologyEventVolumeCreate})
+	}
+	events, last, _, found := l.readSince(start)
+	if !found || len(events) != 2 || last != start+2 || events[0].Sequence != start+1 || events[1].Sequence != start+2 {
+		t.Fatalf("read since %d: %v %d %v", start, events, last, found)
+	}
+	if events, _, _, found = l.readSince(start + 2); !found || len(events) != 0 {
+		t.Errorf("read since the last sequence: %v %v", events, found)
+	}
+
+	// the ring buffer drops the oldest event
+	_, _, notify, _ := l.readSince(start + 2)
+	l.append(&master_pb.TopologyEvent{Type: TopologyEventVolumeDelete}, &master_pb.TopologyEvent{Type: TopologyEventEcShardAdd})
+	select {
+	case <-notify:
+	default:
+		t.Errorf("subscribers are not notified")
+	}
+	if events, _, _, found = l.readSince(start + 1); !found || len(events) != 3 || events[0].Sequence != start+2 || events[2].Type != TopologyEventEcShardAdd {
+		t.Errorf("read since %d: %v %v", start+1, events, found)
+	}
+	if _, _, _, found = l.readSince(start); found {
+		t.Errorf("read since the dropped event")
+	}
+	if _, _, _, found = l.readSince(start + 5); found {
+		t.Errorf("read since an unknown sequence")
+	}
+	if _, _, _, found = l.readSince(0); found {
+		t.Errorf("read since 0")
+	}
+}
+
+type fakeHeartbeatStream struct {
+	grpc.ServerStream
+	heartbeats []*master_pb.Heartbeat
+}
+
+func (s *fakeHeartbeatStream) Send(*master_pb.HeartbeatResponse) error { return nil }
+func (s *fakeHeartbeatStream) Recv() (*master_pb.Heartbeat, error) {
+	if len(s.heartbeats) == 0 {
+		return nil, io.EOF
+	}
+	heartbeat := s.heartbeats[0]
+	s.heartbeats = s.heartbeats[1:]
+	return heartbeat, nil
+}
+
+type fakeTopologyStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *master_pb.TopologyEvent
+}
+
+func (s *fakeTopologyStream) Context() context.Context { return s.ctx }
+func (s *fakeTopologyStream) Send(event *master_pb.TopologyEvent) error {
+	s.events <- event
+	return nil
+}
+
+func newTopologyEventsMasterServer(name string, leader *string) *MasterServer {
+	ms := &MasterServer{
+		option:         &MasterOption{},
+		Topo:           topology.NewTopology("topo", sequence.NewMemorySequencer(), 32*1024, 5, false),
+		clientChans:    make(map[string]chan *master_pb.VolumeLocation),
+		topologyEvents: newTopologyEventLog(topologyEventLogSize),
+	}
+	ms.Topo.RaftServer = &fakeRaftServer{name: name, leader: leader}
+	return ms
+}
+
+func TestSendHeartbeatTopologyEvents(t *testing.T) {
+	leader := "master:9333"
+	ms := newTopologyEventsMasterServer(leader, &leader)
+	_, start, _, _ := ms.topologyEvents.readSince(0)
+
+	err := ms.SendHeartbeat(&fakeHeartbeatStream{heartbeats: []*master_pb.Heartbeat{
+		{
+			Ip: "127.0.0.1", Port: 8080, PublicUrl: "localhost:8080", MaxVolumeCount: 10, DataCenter: "dc1", Rack: "rack1",
+			Volumes: []*master_pb.VolumeInformationMessage{{Id: 1, Collection: "c", Version: 3}},
+		},
+		{NewVolumes: []*master_pb.VolumeShortInformationMessage{{Id: 2, Collection: "c", Version: 3}}},
+		{NewEcShards: []*master_pb.VolumeEcShardInformationMessage{{Id: 3, Collection: "c", EcIndexBits: 0x3}}},
+		{DeletedVolumes: []*master_pb.VolumeShortInformationMessage{{Id: 2, Collection: "c", Version: 3}}},
+	}})
+	if err != io.EOF {
+		t.Fatalf("send heartbeat: %v", err)
+	}
+
+	events, _, _, _ := ms.topologyEvents.readSince(start)
+	expected := []string{
+		TopologyEventDataNodeJoin,
+		TopologyEventVolumeCreate,
+		TopologyEventVolumeCreate,
+		TopologyEventEcShardAdd,
+		TopologyEventVolumeDelete,
+		TopologyEventDataNodeLeave,
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("events: %v", events)
+	}
+	for i, event := range events {
+		if event.Type != expected[i] || event.Url != "127.0.0.1:8080" || event.DataCenter != "dc1" || event.Rack != "rack1" {
+			t.Errorf("event %d: %v", i, event)
+		}
+	}
+	if events[1].Volumes[0].Id != 1 || events[2].Volumes[0].Id != 2 || events[3].EcShards[0].EcIndexBits != 0x3 {
+		t.Errorf("volume events: %v", events[1:4])
+	}
+	if leave := events[5]; len(leave.Volumes) != 1 || leave.Volumes[0].Id != 1 || len(leave.EcShards) != 1 || leave.EcShards[0].Id != 3 {
+		t.Errorf("leave event: %v", leave)
+	}
+}
+
+func TestSubscribeTopology(t *testing.T) {
+	leader := "master:9333"
+	ms := newTopologyEventsMasterServer(leader, &leader)
+	dn := ms.Topo.GetOrCreateDataCenter("dc1").GetOrCreateRack("rack1").GetOrCreateDataNode("127.0.0.1", 8080, "localhost:8080", 10)
+	ms.Topo.SyncDataNodeRegistration([]*master_pb.VolumeInformationMessage{{Id: 1, Collection: "c", Version: 3}}, dn)
+	ms.topologyEvents.append(newDataNodeEvent(TopologyEventDataNodeJoin, dn))
+
+	subscribe := func(ms *MasterServer, since uint64) (*fakeTopologyStream, context.CancelFunc, chan error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := &fakeTopologyStream{ctx: ctx, events: make(chan *master_pb.TopologyEvent, 16)}
+		done := make(chan error, 1)
+		go func() {
+			done <- ms.SubscribeTopology(&master_pb.SubscribeTopologyRequest{ClientName: "test", SinceSequence: since, HeartbeatSeconds: 1}, stream)
+		}()
+		return stream, cancel, done
+	}
+	next := func(stream *fakeTopologyStream) *master_pb.TopologyEvent {
+		select {
+		case event := <-stream.events:
+			return event
+		case <-time.After(10 * time.Second):
+			t.Fatalf("no topology event")
+		}
+		return nil
+	}
+
+	// starts with the current topology
+	stream, cancel, done := subscribe(ms, 0)
+	reset, join := next(stream), next(stream)
+	if reset.Type != TopologyEventReset || join.Type != TopologyEventDataNodeJoin || join.Sequence != reset.Sequence || len(join.Volumes) != 1 || join.Url != "127.0.0.1:8080" {
+		t.Fatalf("snapshot: %v %v", reset, join)
+	}
+
+	ms.topologyEvents.append(&master_pb.TopologyEvent{Type: TopologyEventVolumeCreate, Url: dn.Url()})
+	if event := next(stream); event.Type != TopologyEventVolumeCreate || event.Sequence != reset.Sequence+1 {
+		t.Errorf("live event: %v", event)
+	}
+	if event := next(stream); event.Type != TopologyEventHeartbeat || event.Sequence != reset.Sequence+1 {
+		t.Errorf("heartbeat event: %v", event)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("subscribe: %v", err)
+	}
+
+	// resumes after the sequence
+	ms.topologyEvents.append(&master_pb.TopologyEvent{Type: TopologyEventVolumeDelete, Url: dn.Url()})
+	stream, cancel, done = subscribe(ms, reset.Sequence+1)
+	if event := next(stream); event.Type != TopologyEventVolumeDelete || event.Sequence != reset.Sequence+2 {
+		t.Errorf("resumed event: %v", event)
+	}
+	cancel()
+	<-done
+
+	// a follower tells the leader
+	stream, cancel, done = subscribe(newTopologyEventsMasterServer("master:9334", &leader), 0)
+	defer cancel()
+	if event := next(stream); event.Type != TopologyEventLeader || event.Leader != leader {
+		t.Errorf("follower event: %v", event)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("subscribe to follower: %v", err)
+	}
+}
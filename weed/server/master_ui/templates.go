@@ -67,6 +67,48 @@ var StatusTpl = template.Must(template.New("status").Parse(`<!DOCTYPE html>
         </div>
       </div>
 
+      {{ with .EcPolicy }}
+      <div class="row">
+        <h2>Erasure Coding Policy</h2>
+        <p>
+          {{ if .Paused }}Paused{{ else }}Running{{ end }},
+          volumes readonly and quiet for {{ .QuietFor }}, {{ .Concurrency }} at a time.
+          {{ .Encoded }} volumes encoded, last checked at {{ .LastCheck.Format "2006-01-02 15:04:05" }}.
+        </p>
+        <table class="table table-striped">
+          <thead>
+            <tr>
+              <th>Volume</th>
+              <th>Collection</th>
+              <th>Started At</th>
+              <th>Step</th>
+              <th>Error</th>
+            </tr>
+          </thead>
+          <tbody>
+          {{ range $progress := .Running }}
+            <tr>
+              <td>{{ $progress.VolumeId }}</td>
+              <td>{{ $progress.Collection }}</td>
+              <td>{{ $progress.StartedAt.Format "2006-01-02 15:04:05" }}</td>
+              <td>{{ $progress.Step }}</td>
+              <td></td>
+            </tr>
+          {{ end }}
+          {{ range $progress := .Failures }}
+            <tr>
+              <td>{{ $progress.VolumeId }}</td>
+              <td>{{ $progress.Collection }}</td>
+              <td>{{ $progress.StartedAt.Format "2006-01-02 15:04:05" }}</td>
+              <td>{{ $progress.Step }}</td>
+              <td>{{ $progress.Error }}</td>
+            </tr>
+          {{ end }}
+          </tbody>
+        </table>
+      </div>
+      {{ end }}
+
       <div class="row">
         <h2>Topology</h2>
         <table class="table table-striped">
@@ -0,0 +1,170 @@
+package weed_server
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/shell"
+	"github.com/chrislusf/seaweedfs/weed/topology"
+)
+
+// VolumeRebalancer moves volumes from fuller to emptier volume servers in the background, one volume at a time.
+// The moves are planned once when started, the same way as volume.rebalance in weed shell.
+type VolumeRebalancer struct {
+	topo            *topology.Topology
+	commandEnv      *shell.CommandEnv
+	grpcDialOption  grpc.DialOption
+	volumeSizeLimit uint64
+
+	sync.Mutex
+	running    bool
+	stopping   bool
+	startedAt  time.Time
+	finishedAt time.Time
+	planned    []*shell.VolumeMove
+	moved      int
+	movedBytes uint64
+	current    *shell.VolumeMove
+	failures   []string
+}
+
+type VolumeRebalanceOption struct {
+	Collection     string
+	DataCenter     string
+	MaxMoves       int
+	BytesPerSecond int64
+}
+
+type VolumeRebalanceStatus struct {
+	Running    bool
+	Stopping   bool
+	StartedAt  time.Time
+	FinishedAt time.Time `json:",omitempty"`
+	Planned    []string
+	Moved      int
+	MovedBytes uint64
+	Current    string   `json:",omitempty"`
+	Failures   []string `json:",omitempty"`
+}
+
+func (ms *MasterServer) newVolumeRebalancer() *VolumeRebalancer {
+	masterAddress := "localhost:" + strconv.Itoa(ms.option.Port)
+	var shellOptions shell.ShellOptions
+	shellOptions.GrpcDialOption = ms.grpcDialOption
+	shellOptions.Masters = &masterAddress
+
+	r := &VolumeRebalancer{
+		topo:            ms.Topo,
+		commandEnv:      shell.NewCommandEnv(shellOptions),
+		grpcDialOption:  ms.grpcDialOption,
+		volumeSizeLimit: uint64(ms.option.VolumeSizeLimitMB) * 1024 * 1024,
+	}
+
+	go r.commandEnv.MasterClient.KeepConnectedToMaster()
+
+	return r
+}
+
+// Plan returns the moves without applying them
+func (r *VolumeRebalancer) Plan(option VolumeRebalanceOption) []*shell.VolumeMove {
+	return shell.PlanVolumeRebalance(r.topo.ToTopologyInfo(), r.volumeSizeLimit, option.Collection, option.DataCenter, option.MaxMoves)
+}
+
+// Start plans the moves and applies them in the background
+func (r *VolumeRebalancer) Start(option VolumeRebalanceOption) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.running {
+		return fmt.Errorf("volume rebalancing started at %v is still running", r.startedAt)
+	}
+
+	r.running = true
+	r.stopping = false
+	r.startedAt = time.Now()
+	r.finishedAt = time.Time{}
+	r.planned = r.Plan(option)
+	r.moved = 0
+	r.movedBytes = 0
+	r.failures = nil
+
+	go r.run(r.planned, option.BytesPerSecond)
+
+	return nil
+}
+
+// Stop skips the remaining moves. The volume being moved is not interrupted.
+func (r *VolumeRebalancer) Stop() {
+	r.Lock()
+	defer r.Unlock()
+	if r.running {
+		r.stopping = true
+	}
+}
+
+func (r *VolumeRebalancer) Status() *VolumeRebalanceStatus {
+	r.Lock()
+	defer r.Unlock()
+	status := &VolumeRebalanceStatus{
+		Running:    r.running,
+		Stopping:   r.stopping,
+		StartedAt:  r.startedAt,
+		FinishedAt: r.finishedAt,
+		Moved:      r.moved,
+		MovedBytes: r.movedBytes,
+		Failures:   append([]string(nil), r.failures...),
+	}
+	for _, move := range r.planned {
+		status.Planned = append(status.Planned, move.String())
+	}
+	if r.current != nil {
+		status.Current = r.current.String()
+	}
+	return status
+}
+
+func (r *VolumeRebalancer) run(moves []*shell.VolumeMove, bytesPerSecond int64) {
+	defer func() {
+		r.Lock()
+		r.running = false
+		r.stopping = false
+		r.current = nil
+		r.finishedAt = time.Now()
+		r.Unlock()
+	}()
+
+	r.commandEnv.MasterClient.WaitUntilConnected()
+
+	// do not compete with the admin scripts or weed shell
+	r.commandEnv.Lock()
+	defer r.commandEnv.Unlock()
+
+	glog.V(0).Infof("rebalance volumes: %d moves", len(moves))
+	for _, move := range moves {
+		r.Lock()
+		if r.stopping || !r.topo.IsLeader() {
+			r.Unlock()
+			break
+		}
+		r.current = move
+		r.Unlock()
+
+		glog.V(0).Infof("rebalance volumes: move %s", move)
+		err := shell.MoveVolumeVerified(r.grpcDialOption, move, bytesPerSecond, 5*time.Second)
+
+		r.Lock()
+		r.current = nil
+		if err != nil {
+			glog.Errorf("rebalance volumes: move %s: %v", move, err)
+			r.failures = append(r.failures, fmt.Sprintf("%s: %v", move, err))
+		} else {
+			r.moved++
+			r.movedBytes += move.Size
+		}
+		r.Unlock()
+	}
+}
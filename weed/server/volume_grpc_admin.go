@@ -40,6 +40,7 @@ func (vs *VolumeServer) AllocateVolume(ctx context.Context, req *volume_server_p
 		req.Ttl,
 		req.Preallocate,
 		req.MemoryMapMaxSizeMb,
+		req.AppendOnly,
 	)
 
 	if err != nil {
@@ -151,6 +152,34 @@ func (vs *VolumeServer) VolumeMarkReadonly(ctx context.Context, req *volume_serv
 
 }
 
+func (vs *VolumeServer) VolumeMarkWritable(ctx context.Context, req *volume_server_pb.VolumeMarkWritableRequest) (*volume_server_pb.VolumeMarkWritableResponse, error) {
+
+	resp := &volume_server_pb.VolumeMarkWritableResponse{}
+
+	err := vs.store.MarkVolumeWritable(needle.VolumeId(req.VolumeId))
+
+	if err != nil {
+		glog.Errorf("volume mark writable %v: %v", req, err)
+	} else {
+		glog.V(2).Infof("volume mark writable %v", req)
+	}
+
+	return resp, err
+
+}
+
+func (vs *VolumeServer) VolumeServerMarkReadonly(ctx context.Context, req *volume_server_pb.VolumeServerMarkReadonlyRequest) (*volume_server_pb.VolumeServerMarkReadonlyResponse, error) {
+
+	resp := &volume_server_pb.VolumeServerMarkReadonlyResponse{}
+
+	vs.store.SetReadOnly(req.Readonly)
+
+	glog.V(0).Infof("volume server mark readonly %v", req.Readonly)
+
+	return resp, nil
+
+}
+
 func (vs *VolumeServer) VolumeServerStatus(ctx context.Context, req *volume_server_pb.VolumeServerStatusRequest) (*volume_server_pb.VolumeServerStatusResponse, error) {
 
 	resp := &volume_server_pb.VolumeServerStatusResponse{}
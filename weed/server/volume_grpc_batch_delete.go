@@ -72,7 +72,7 @@ func (vs *VolumeServer) BatchDelete(ctx context.Context, req *volume_server_pb.B
 		if size, err := vs.store.DeleteVolumeNeedle(volumeId, n); err != nil {
 			resp.Results = append(resp.Results, &volume_server_pb.DeleteResult{
 				FileId: fid,
-				Status: http.StatusInternalServerError,
+				Status: int32(writeErrorHttpStatus(err)),
 				Error:  err.Error()},
 			)
 		} else {
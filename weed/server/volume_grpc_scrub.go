@@ -0,0 +1,63 @@
+package weed_server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/operation"
+	"github.com/chrislusf/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/chrislusf/seaweedfs/weed/storage"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
+)
+
+func (vs *VolumeServer) ReadNeedleBlob(ctx context.Context, req *volume_server_pb.ReadNeedleBlobRequest) (*volume_server_pb.ReadNeedleBlobResponse, error) {
+
+	blob, size, version, err := vs.store.ReadVolumeNeedleBlob(needle.VolumeId(req.VolumeId), types.Uint64ToNeedleId(req.NeedleId))
+	if err != nil {
+		return nil, fmt.Errorf("read needle %d,%x: %v", req.VolumeId, req.NeedleId, err)
+	}
+	vs.replicationLimiter.Wait(int64(len(blob)))
+
+	return &volume_server_pb.ReadNeedleBlobResponse{
+		NeedleBlob: blob,
+		Size:       size,
+		Version:    uint32(version),
+	}, nil
+}
+
+// repairNeedle replaces the corrupted needle with a good copy from one of the replicas looked up from the master
+func (vs *VolumeServer) repairNeedle(vid needle.VolumeId, corrupted *storage.CorruptedNeedle) error {
+
+	lookupResult, err := operation.Lookup(vs.GetMaster(), vid.String())
+	if err != nil {
+		return fmt.Errorf("lookup volume %d: %v", vid, err)
+	}
+
+	selfUrl := vs.store.Ip + ":" + strconv.Itoa(vs.store.Port)
+	err = fmt.Errorf("volume %d has no other replicas", vid)
+	for _, location := range lookupResult.Locations {
+		if location.Url == selfUrl {
+			continue
+		}
+		// the replica could be corrupted too, which fails the CRC check before the repair
+		repairErr := operation.WithVolumeServerClient(location.Url, vs.grpcDialOption, func(client volume_server_pb.VolumeServerClient) error {
+			resp, readErr := client.ReadNeedleBlob(context.Background(), &volume_server_pb.ReadNeedleBlobRequest{
+				VolumeId: uint32(vid),
+				NeedleId: types.NeedleIdToUint64(corrupted.Id),
+			})
+			if readErr != nil {
+				return readErr
+			}
+			return vs.store.RepairVolumeNeedle(vid, corrupted, resp.NeedleBlob, resp.Size, needle.Version(resp.Version))
+		})
+		if repairErr == nil {
+			glog.V(0).Infof("repaired needle %d,%s from %s", vid, corrupted.Id, location.Url)
+			return nil
+		}
+		err = fmt.Errorf("repair from %s: %v", location.Url, repairErr)
+	}
+	return err
+}
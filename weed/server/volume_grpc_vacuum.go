@@ -12,9 +12,10 @@ func (vs *VolumeServer) VacuumVolumeCheck(ctx context.Context, req *volume_serve
 
 	resp := &volume_server_pb.VacuumVolumeCheckResponse{}
 
-	garbageRatio, err := vs.store.CheckCompactVolume(needle.VolumeId(req.VolumeId))
+	garbageRatio, garbageBytes, err := vs.store.CheckCompactVolume(needle.VolumeId(req.VolumeId))
 
 	resp.GarbageRatio = garbageRatio
+	resp.GarbageBytes = garbageBytes
 
 	if err != nil {
 		glog.V(3).Infof("check volume %d: %v", req.VolumeId, err)
@@ -44,13 +45,14 @@ func (vs *VolumeServer) VacuumVolumeCommit(ctx context.Context, req *volume_serv
 
 	resp := &volume_server_pb.VacuumVolumeCommitResponse{}
 
-	err := vs.store.CommitCompactVolume(needle.VolumeId(req.VolumeId))
+	reclaimedBytes, err := vs.store.CommitCompactVolume(needle.VolumeId(req.VolumeId))
 
 	if err != nil {
 		glog.Errorf("commit volume %d: %v", req.VolumeId, err)
 	} else {
-		glog.V(1).Infof("commit volume %d", req.VolumeId)
+		glog.V(1).Infof("commit volume %d, reclaimed %d bytes", req.VolumeId, reclaimedBytes)
 	}
+	resp.ReclaimedBytes = reclaimedBytes
 	if err == nil {
 		if vs.store.GetVolume(needle.VolumeId(req.VolumeId)).IsReadOnly() {
 			resp.IsReadOnly = true
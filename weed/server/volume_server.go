@@ -3,6 +3,7 @@ package weed_server
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -12,6 +13,8 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/security"
 	"github.com/chrislusf/seaweedfs/weed/storage"
+	"github.com/chrislusf/seaweedfs/weed/storage/backend"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 )
 
 type VolumeServer struct {
@@ -20,6 +23,7 @@ type VolumeServer struct {
 	pulseSeconds    int
 	dataCenter      string
 	rack            string
+	diskType        types.DiskType
 	store           *storage.Store
 	guard           *security.Guard
 	grpcDialOption  grpc.DialOption
@@ -31,6 +35,16 @@ type VolumeServer struct {
 	MetricsAddress          string
 	MetricsIntervalSec      int
 	fileSizeLimitBytes      int64
+
+	// the read responses, and the replicated writes and volume copies, are throttled separately
+	readLimiter        *util.RateLimiter
+	replicationLimiter *util.RateLimiter
+
+	// the volumes are scrubbed every scrubInterval, only in the scrub hours if set
+	scrubInterval time.Duration
+	scrubHours    *scrubHours
+	scrubLimiter  *util.RateLimiter
+	scrubber      *volumeScrubber
 }
 
 func NewVolumeServer(adminMux, publicMux *http.ServeMux, ip string,
@@ -38,12 +52,19 @@ func NewVolumeServer(adminMux, publicMux *http.ServeMux, ip string,
 	folders []string, maxCounts []int,
 	needleMapKind storage.NeedleMapType,
 	masterNodes []string, pulseSeconds int,
-	dataCenter string, rack string,
+	dataCenter string, rack string, diskType string,
 	whiteList []string,
 	fixJpgOrientation bool,
 	readRedirect bool,
 	compactionMBPerSecond int,
 	fileSizeLimitMB int,
+	readMBPerSecond int,
+	replicationMBPerSecond int,
+	scrubIntervalHours int,
+	scrubHoursWindow string,
+	scrubMBPerSecond int,
+	fsyncPolicy string, fsyncBatchMs int, fsyncBatchWrites int,
+	dataFileIO string,
 ) *VolumeServer {
 
 	v := util.GetViper()
@@ -60,15 +81,33 @@ func NewVolumeServer(adminMux, publicMux *http.ServeMux, ip string,
 		pulseSeconds:            pulseSeconds,
 		dataCenter:              dataCenter,
 		rack:                    rack,
+		diskType:                types.ToDiskType(diskType),
 		needleMapKind:           needleMapKind,
 		FixJpgOrientation:       fixJpgOrientation,
 		ReadRedirect:            readRedirect,
 		grpcDialOption:          security.LoadClientTLS(util.GetViper(), "grpc.volume"),
 		compactionBytePerSecond: int64(compactionMBPerSecond) * 1024 * 1024,
 		fileSizeLimitBytes:      int64(fileSizeLimitMB) * 1024 * 1024,
+		readLimiter:             util.NewRateLimiter(int64(readMBPerSecond) * 1024 * 1024),
+		replicationLimiter:      util.NewRateLimiter(int64(replicationMBPerSecond) * 1024 * 1024),
+		scrubInterval:           time.Duration(scrubIntervalHours) * time.Hour,
+		scrubLimiter:            util.NewRateLimiter(int64(scrubMBPerSecond) * 1024 * 1024),
+		scrubber:                &volumeScrubber{},
+	}
+	var err error
+	if vs.scrubHours, err = parseScrubHours(scrubHoursWindow); err != nil {
+		glog.Fatalf("%v", err)
 	}
 	vs.SeedMasterNodes = masterNodes
+	if err = backend.SetDataFileIO(dataFileIO); err != nil {
+		glog.Fatalf("%v", err)
+	}
 	vs.store = storage.NewStore(vs.grpcDialOption, port, ip, publicUrl, folders, maxCounts, vs.needleMapKind)
+	policy, err := storage.ParseFsyncPolicy(fsyncPolicy, fsyncBatchMs, fsyncBatchWrites)
+	if err != nil {
+		glog.Fatalf("%v", err)
+	}
+	vs.store.SetFsyncPolicy(policy)
 
 	vs.guard = security.NewGuard(whiteList, signingKey, expiresAfterSec, readSigningKey, readExpiresAfterSec)
 
@@ -83,6 +122,7 @@ func NewVolumeServer(adminMux, publicMux *http.ServeMux, ip string,
 			adminMux.HandleFunc("/stats/disk", vs.guard.WhiteList(vs.statsDiskHandler))
 		*/
 	}
+	adminMux.HandleFunc("/admin/bandwidth", vs.guard.WhiteList(vs.bandwidthHandler))
 	adminMux.HandleFunc("/", vs.privateStoreHandler)
 	if publicMux != adminMux {
 		// separated admin and public port
@@ -91,6 +131,9 @@ func NewVolumeServer(adminMux, publicMux *http.ServeMux, ip string,
 	}
 
 	go vs.heartbeat()
+	if vs.scrubInterval > 0 {
+		go vs.loopScrubVolumes()
+	}
 	hostAddress := fmt.Sprintf("%s:%d", ip, port)
 	go stats.LoopPushingMetric("volumeServer", hostAddress, stats.VolumeServerGather,
 		func() (addr string, intervalSeconds int) {
@@ -1,8 +1,10 @@
 package weed_server
 
 import (
+	"fmt"
 	"net/http"
 	"path/filepath"
+	"strconv"
 
 	"github.com/chrislusf/seaweedfs/weed/pb/volume_server_pb"
 	"github.com/chrislusf/seaweedfs/weed/stats"
@@ -20,6 +22,9 @@ func (vs *VolumeServer) statusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	m["DiskStatuses"] = ds
 	m["Volumes"] = vs.store.VolumeInfos()
+	if vs.scrubInterval > 0 {
+		m["Scrub"] = vs.scrubber.Status()
+	}
 	writeJsonQuiet(w, r, http.StatusOK, m)
 }
 
@@ -35,3 +40,36 @@ func (vs *VolumeServer) statsDiskHandler(w http.ResponseWriter, r *http.Request)
 	m["DiskStatuses"] = ds
 	writeJsonQuiet(w, r, http.StatusOK, m)
 }
+
+// bandwidthHandler shows the bandwidth limits, and changes them with the readMBps, replicationMBps and scrubMBps parameters.
+// 0 means no limit. The heartbeats and other admin requests are not limited.
+func (vs *VolumeServer) bandwidthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "POST" || r.Method == "PUT" {
+		limits := map[string]*util.RateLimiter{
+			"readMBps":        vs.readLimiter,
+			"replicationMBps": vs.replicationLimiter,
+			"scrubMBps":       vs.scrubLimiter,
+		}
+		rates := make(map[*util.RateLimiter]int64)
+		for name, limiter := range limits {
+			value := r.FormValue(name)
+			if value == "" {
+				continue
+			}
+			mbps, err := strconv.ParseFloat(value, 64)
+			if err != nil || mbps < 0 {
+				writeJsonError(w, r, http.StatusBadRequest, fmt.Errorf("invalid %s %q", name, value))
+				return
+			}
+			rates[limiter] = int64(mbps * 1024 * 1024)
+		}
+		for limiter, rate := range rates {
+			limiter.SetRate(rate)
+		}
+	}
+	m := make(map[string]interface{})
+	m["ReadBytesPerSecond"] = vs.readLimiter.Rate()
+	m["ReplicationBytesPerSecond"] = vs.replicationLimiter.Rate()
+	m["ScrubBytesPerSecond"] = vs.scrubLimiter.Rate()
+	writeJsonQuiet(w, r, http.StatusOK, m)
+}
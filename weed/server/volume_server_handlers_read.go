@@ -162,7 +162,7 @@ func (vs *VolumeServer) GetOrHeadHandler(w http.ResponseWriter, r *http.Request)
 
 	rs := conditionallyResizeImages(bytes.NewReader(n.Data), ext, r)
 
-	if e := writeResponseContent(filename, mtype, rs, w, r); e != nil {
+	if e := vs.writeResponseContent(filename, mtype, rs, w, r); e != nil {
 		glog.V(2).Infoln("response write error:", e)
 	}
 }
@@ -200,7 +200,7 @@ func (vs *VolumeServer) tryHandleChunkedFile(n *needle.Needle, fileName string,
 
 	rs := conditionallyResizeImages(chunkedFileReader, ext, r)
 
-	if e := writeResponseContent(fileName, mType, rs, w, r); e != nil {
+	if e := vs.writeResponseContent(fileName, mType, rs, w, r); e != nil {
 		glog.V(2).Infoln("response write error:", e)
 	}
 	return true
@@ -233,7 +233,7 @@ func shouldResizeImages(ext string, r *http.Request) (width, height int, mode st
 	return
 }
 
-func writeResponseContent(filename, mimeType string, rs io.ReadSeeker, w http.ResponseWriter, r *http.Request) error {
+func (vs *VolumeServer) writeResponseContent(filename, mimeType string, rs io.ReadSeeker, w http.ResponseWriter, r *http.Request) error {
 	totalSize, e := rs.Seek(0, 2)
 	if mimeType == "" {
 		if ext := filepath.Ext(filename); ext != "" {
@@ -256,7 +256,7 @@ func writeResponseContent(filename, mimeType string, rs io.ReadSeeker, w http.Re
 		if _, e = rs.Seek(offset, 0); e != nil {
 			return e
 		}
-		_, e = io.CopyN(writer, rs, size)
+		_, e = io.CopyN(util.NewRateLimitedWriter(writer, vs.readLimiter), rs, size)
 		return e
 	})
 	return nil
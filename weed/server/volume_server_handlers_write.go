@@ -3,6 +3,7 @@ package weed_server
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,8 +12,10 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/operation"
 	"github.com/chrislusf/seaweedfs/weed/stats"
+	"github.com/chrislusf/seaweedfs/weed/storage"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/topology"
+	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
 func (vs *VolumeServer) PostHandler(w http.ResponseWriter, r *http.Request) {
@@ -42,6 +45,10 @@ func (vs *VolumeServer) PostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.FormValue("type") == "replicate" {
+		r.Body = ioutil.NopCloser(util.NewRateLimitedReader(r.Body, vs.replicationLimiter))
+	}
+
 	needle, originalSize, ne := needle.CreateNeedleFromRequest(r, vs.FixJpgOrientation, vs.fileSizeLimitBytes)
 	if ne != nil {
 		writeJsonError(w, r, http.StatusBadRequest, ne)
@@ -49,7 +56,7 @@ func (vs *VolumeServer) PostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ret := operation.UploadResult{}
-	isUnchanged, writeError := topology.ReplicatedWrite(vs.GetMaster(), vs.store, volumeId, needle, r)
+	isUnchanged, writeError := topology.ReplicatedWrite(vs.GetMaster(), vs.store, volumeId, needle, r, vs.replicationLimiter)
 
 	// http 204 status code does not allow body
 	if writeError == nil && isUnchanged {
@@ -60,7 +67,7 @@ func (vs *VolumeServer) PostHandler(w http.ResponseWriter, r *http.Request) {
 
 	httpStatus := http.StatusCreated
 	if writeError != nil {
-		httpStatus = http.StatusInternalServerError
+		httpStatus = writeErrorHttpStatus(writeError)
 		ret.Error = writeError.Error()
 	}
 	if needle.HasName() {
@@ -153,8 +160,16 @@ func writeDeleteResult(err error, count int64, w http.ResponseWriter, r *http.Re
 		m["size"] = count
 		writeJsonQuiet(w, r, http.StatusAccepted, m)
 	} else {
-		writeJsonError(w, r, http.StatusInternalServerError, fmt.Errorf("Deletion Failed: %v", err))
+		writeJsonError(w, r, writeErrorHttpStatus(err), fmt.Errorf("Deletion Failed: %v", err))
+	}
+}
+
+// writeErrorHttpStatus forbids the overwrites and deletes on the append only volumes
+func writeErrorHttpStatus(err error) int {
+	if storage.IsAppendOnlyError(err) {
+		return http.StatusForbidden
 	}
+	return http.StatusInternalServerError
 }
 
 func setEtag(w http.ResponseWriter, etag string) {
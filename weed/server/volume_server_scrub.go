@@ -0,0 +1,185 @@
+package weed_server
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/stats"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+)
+
+// scrubHours is the window of local hours [start, end) to scrub the volumes in, e.g. 1-5, or 22-4 across midnight
+type scrubHours struct {
+	start, end int
+}
+
+func parseScrubHours(hours string) (*scrubHours, error) {
+	if hours == "" {
+		return nil, nil
+	}
+	parts := strings.Split(hours, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid scrub hours %q, expecting <start hour>-<end hour>", hours)
+	}
+	start, startErr := strconv.Atoi(strings.TrimSpace(parts[0]))
+	end, endErr := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if startErr != nil || endErr != nil || start < 0 || start > 23 || end < 0 || end > 24 || start == end {
+		return nil, fmt.Errorf("invalid scrub hours %q, expecting <start hour>-<end hour>", hours)
+	}
+	return &scrubHours{start: start, end: end}, nil
+}
+
+// contains checks the hour of the time. A nil window contains any time.
+func (h *scrubHours) contains(t time.Time) bool {
+	if h == nil {
+		return true
+	}
+	hour := t.Hour()
+	if h.start < h.end {
+		return h.start <= hour && hour < h.end
+	}
+	return h.start <= hour || hour < h.end
+}
+
+type VolumeScrubStatus struct {
+	RoundStartTime    time.Time
+	LastRoundEndTime  time.Time
+	Progress          float64
+	NeedleCount       int64
+	ByteCount         int64
+	CorruptedCount    int64
+	RepairedCount     int64
+	UnrepairableCount int64
+	// the needles without a good replica, found in the last scrub of each volume
+	Unrepairable map[needle.VolumeId][]string `json:",omitempty"`
+}
+
+type volumeScrubber struct {
+	sync.Mutex
+	status VolumeScrubStatus
+}
+
+func (s *volumeScrubber) Status() VolumeScrubStatus {
+	s.Lock()
+	defer s.Unlock()
+	status := s.status
+	status.Unrepairable = make(map[needle.VolumeId][]string)
+	for vid, needleIds := range s.status.Unrepairable {
+		status.Unrepairable[vid] = needleIds
+	}
+	return status
+}
+
+// countUnrepairable updates the unrepairable needle count, which is alerted on, with the lock held
+func (s *volumeScrubber) countUnrepairable() {
+	s.status.UnrepairableCount = 0
+	for _, needleIds := range s.status.Unrepairable {
+		s.status.UnrepairableCount += int64(len(needleIds))
+	}
+	stats.VolumeServerScrubUnrepairableGauge.Set(float64(s.status.UnrepairableCount))
+}
+
+func (vs *VolumeServer) loopScrubVolumes() {
+	glog.V(0).Infof("scrub volumes every %v, in hours %+v, at %d bytes per second", vs.scrubInterval, vs.scrubHours, vs.scrubLimiter.Rate())
+	for {
+		time.Sleep(vs.scrubInterval)
+		vs.scrubVolumes()
+	}
+}
+
+// scrubVolumes verifies all the needles of the local volumes, and repairs the corrupted ones from the replicas.
+// Outside of the scrub hours, the scrub pauses until the next window.
+func (vs *VolumeServer) scrubVolumes() {
+
+	vids := vs.store.ScrubbedVolumeIds()
+	sort.Slice(vids, func(i, j int) bool {
+		return vids[i] < vids[j]
+	})
+
+	vs.scrubber.Lock()
+	vs.scrubber.status.RoundStartTime = time.Now()
+	vs.scrubber.status.Progress = 0
+	// forget the volumes not on this server any more
+	for vid := range vs.scrubber.status.Unrepairable {
+		if !vs.store.HasVolume(vid) {
+			delete(vs.scrubber.status.Unrepairable, vid)
+		}
+	}
+	vs.scrubber.countUnrepairable()
+	vs.scrubber.Unlock()
+	stats.VolumeServerScrubProgressGauge.Set(0)
+
+	for i, vid := range vids {
+		vs.waitForScrubHours()
+		result, err := vs.store.ScrubVolume(vid, vs.scrubLimiter, func(walked, total int64) error {
+			if total > 0 {
+				vs.setScrubProgress((float64(i) + float64(walked)/float64(total)) / float64(len(vids)))
+			}
+			vs.waitForScrubHours()
+			return nil
+		})
+		if err != nil {
+			// e.g. the volume is deleted or moved during the scrub
+			glog.Warningf("scrub volume %d: %v", vid, err)
+			continue
+		}
+
+		var unrepairable []string
+		var repairedCount int64
+		for _, corrupted := range result.Corrupted {
+			glog.Errorf("volume %d needle %s at offset %d is corrupted: %v", vid, corrupted.Id, corrupted.Offset.ToAcutalOffset(), corrupted.Err)
+			if repairErr := vs.repairNeedle(vid, corrupted); repairErr != nil {
+				glog.Errorf("volume %d needle %s is unrepairable: %v", vid, corrupted.Id, repairErr)
+				unrepairable = append(unrepairable, corrupted.Id.String())
+				continue
+			}
+			repairedCount++
+		}
+
+		stats.VolumeServerScrubCounter.WithLabelValues("needles").Add(float64(result.NeedleCount))
+		stats.VolumeServerScrubCounter.WithLabelValues("bytes").Add(float64(result.ByteCount))
+		stats.VolumeServerScrubCounter.WithLabelValues("corrupted").Add(float64(len(result.Corrupted)))
+		stats.VolumeServerScrubCounter.WithLabelValues("repaired").Add(float64(repairedCount))
+		stats.VolumeServerScrubCounter.WithLabelValues("unrepairable").Add(float64(len(unrepairable)))
+
+		vs.scrubber.Lock()
+		status := &vs.scrubber.status
+		status.NeedleCount += result.NeedleCount
+		status.ByteCount += result.ByteCount
+		status.CorruptedCount += int64(len(result.Corrupted))
+		status.RepairedCount += repairedCount
+		if status.Unrepairable == nil {
+			status.Unrepairable = make(map[needle.VolumeId][]string)
+		}
+		if len(unrepairable) > 0 {
+			status.Unrepairable[vid] = unrepairable
+		} else {
+			delete(status.Unrepairable, vid)
+		}
+		vs.scrubber.countUnrepairable()
+		vs.scrubber.Unlock()
+	}
+
+	vs.scrubber.Lock()
+	vs.scrubber.status.LastRoundEndTime = time.Now()
+	vs.scrubber.Unlock()
+	vs.setScrubProgress(1)
+}
+
+func (vs *VolumeServer) setScrubProgress(progress float64) {
+	vs.scrubber.Lock()
+	vs.scrubber.status.Progress = progress
+	vs.scrubber.Unlock()
+	stats.VolumeServerScrubProgressGauge.Set(progress)
+}
+
+func (vs *VolumeServer) waitForScrubHours() {
+	for !vs.scrubHours.contains(time.Now()) {
+		time.Sleep(time.Minute)
+	}
+}
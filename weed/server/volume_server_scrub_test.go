@@ -0,0 +1,37 @@
+package weed_server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScrubHours(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2020, 1, 1, hour, 30, 0, 0, time.Local)
+	}
+
+	hours, err := parseScrubHours("1-5")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !hours.contains(at(1)) || !hours.contains(at(4)) || hours.contains(at(5)) || hours.contains(at(0)) {
+		t.Errorf("unexpected window %+v", hours)
+	}
+
+	hours, err = parseScrubHours("22-4")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !hours.contains(at(23)) || !hours.contains(at(0)) || !hours.contains(at(3)) || hours.contains(at(4)) || hours.contains(at(12)) {
+		t.Errorf("unexpected window across midnight %+v", hours)
+	}
+
+	if hours, _ = parseScrubHours(""); !hours.contains(at(12)) {
+		t.Errorf("empty window should contain any time")
+	}
+	for _, invalid := range []string{"1", "3-3", "a-5", "1-25", "24-1"} {
+		if _, err := parseScrubHours(invalid); err == nil {
+			t.Errorf("parse invalid hours %q", invalid)
+		}
+	}
+}
@@ -2,12 +2,17 @@ package weed_server
 
 import (
 	"context"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"math"
+	"mime"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/chrislusf/seaweedfs/weed/util/grace"
@@ -44,25 +49,83 @@ type WebDavServer struct {
 	secret         security.SigningKey
 	filer          *filer2.Filer
 	grpcDialOption grpc.DialOption
+	fs             *WebDavFileSystem
 	Handler        *webdav.Handler
 }
 
+const (
+	// PROPFIND with infinite depth is refused for trees with more entries
+	webDavMaxInfiniteDepthEntries = 10000
+	webDavListPageSize            = 1024
+	// locks expire if the client crashes and never unlocks
+	webDavMaxLockDuration = time.Hour
+)
+
 func NewWebDavServer(option *WebDavOption) (ws *WebDavServer, err error) {
 
-	fs, _ := NewWebDavFileSystem(option)
+	fs := newWebDavFileSystem(option)
 
 	ws = &WebDavServer{
 		option:         option,
 		grpcDialOption: security.LoadClientTLS(util.GetViper(), "grpc.filer"),
+		fs:             fs,
 		Handler: &webdav.Handler{
 			FileSystem: fs,
-			LockSystem: webdav.NewMemLS(),
+			LockSystem: webDavLockSystem{webdav.NewMemLS()},
 		},
 	}
 
 	return ws, nil
 }
 
+func (ws *WebDavServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	// the webdav handler treats a missing Depth header as infinity
+	if r.Method == "PROPFIND" {
+		if depth := r.Header.Get("Depth"); depth == "" || strings.EqualFold(depth, "infinity") {
+			if fi, err := ws.fs.Stat(r.Context(), r.URL.Path); err == nil && fi.IsDir() {
+				count, err := ws.fs.countEntries(r.URL.Path, webDavMaxInfiniteDepthEntries)
+				if err != nil {
+					glog.Errorf("PROPFIND %s count entries: %v", r.URL.Path, err)
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if count > webDavMaxInfiniteDepthEntries {
+					glog.V(1).Infof("PROPFIND %s: more than %d entries for infinite depth", r.URL.Path, webDavMaxInfiniteDepthEntries)
+					w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+					w.WriteHeader(http.StatusForbidden)
+					fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><D:error xmlns:D="DAV:"><D:propfind-finite-depth/></D:error>`)
+					return
+				}
+			}
+		}
+	}
+
+	ws.Handler.ServeHTTP(w, r)
+}
+
+// webDavLockSystem caps the lock durations, so that the advisory locks of a crashed client do not last forever
+type webDavLockSystem struct {
+	webdav.LockSystem
+}
+
+func (ls webDavLockSystem) Create(now time.Time, details webdav.LockDetails) (token string, err error) {
+	details.Duration = capLockDuration(details.Duration)
+	return ls.LockSystem.Create(now, details)
+}
+
+func (ls webDavLockSystem) Refresh(now time.Time, token string, duration time.Duration) (webdav.LockDetails, error) {
+	return ls.LockSystem.Refresh(now, token, capLockDuration(duration))
+}
+
+func capLockDuration(duration time.Duration) time.Duration {
+	// negative duration means infinite
+	if duration < 0 || duration > webDavMaxLockDuration {
+		return webDavMaxLockDuration
+	}
+	return duration
+}
+
 // adapted from https://github.com/mattn/davfs/blob/master/plugin/mysql/mysql.go
 
 type WebDavFileSystem struct {
@@ -78,7 +141,10 @@ type FileInfo struct {
 	size          int64
 	mode          os.FileMode
 	modifiledTime time.Time
+	createdTime   time.Time
 	isDirectory   bool
+	etag          string
+	mimeType      string
 }
 
 func (fi *FileInfo) Name() string       { return fi.name }
@@ -88,17 +154,56 @@ func (fi *FileInfo) ModTime() time.Time { return fi.modifiledTime }
 func (fi *FileInfo) IsDir() bool        { return fi.isDirectory }
 func (fi *FileInfo) Sys() interface{}   { return nil }
 
+// ETag avoids the webdav handler computing etags from the modification time in seconds
+func (fi *FileInfo) ETag(ctx context.Context) (string, error) {
+	return fi.etag, nil
+}
+
+// ContentType avoids the webdav handler reading the file content to detect the type
+func (fi *FileInfo) ContentType(ctx context.Context) (string, error) {
+	return fi.mimeType, nil
+}
+
+func newFileInfo(name string, entry *filer_pb.Entry) *FileInfo {
+	fi := &FileInfo{
+		name:          name,
+		size:          int64(filer2.FileSize(entry)),
+		mode:          os.FileMode(entry.Attributes.FileMode),
+		modifiledTime: time.Unix(entry.Attributes.Mtime, 0),
+		createdTime:   time.Unix(entry.Attributes.Crtime, 0),
+		isDirectory:   entry.IsDirectory,
+	}
+	if fi.isDirectory {
+		fi.etag = fmt.Sprintf(`"%x"`, entry.Attributes.Mtime)
+		return fi
+	}
+	fi.etag = fmt.Sprintf(`"%s"`, filer2.ETag(entry))
+	fi.mimeType = entry.Attributes.Mime
+	if fi.mimeType == "" {
+		fi.mimeType = mime.TypeByExtension(filepath.Ext(name))
+	}
+	if fi.mimeType == "" {
+		fi.mimeType = "application/octet-stream"
+	}
+	return fi
+}
+
 type WebDavFile struct {
 	fs             *WebDavFileSystem
 	name           string
 	isDirectory    bool
 	off            int64
+	lastListedName string
 	entry          *filer_pb.Entry
 	entryViewCache []filer2.VisibleInterval
 	reader         io.ReaderAt
 }
 
 func NewWebDavFileSystem(option *WebDavOption) (webdav.FileSystem, error) {
+	return newWebDavFileSystem(option), nil
+}
+
+func newWebDavFileSystem(option *WebDavOption) *WebDavFileSystem {
 
 	chunkCache := chunk_cache.NewChunkCache(256, option.CacheDir, option.CacheSizeMB)
 	grace.OnInterrupt(func() {
@@ -107,7 +212,7 @@ func NewWebDavFileSystem(option *WebDavOption) (webdav.FileSystem, error) {
 	return &WebDavFileSystem{
 		option:     option,
 		chunkCache: chunkCache,
-	}, nil
+	}
 }
 
 var _ = filer_pb.FilerClient(&WebDavFileSystem{})
@@ -235,18 +340,19 @@ func (fs *WebDavFileSystem) OpenFile(ctx context.Context, fullFilePath string, f
 		}, nil
 	}
 
-	fi, err := fs.stat(ctx, fullFilePath)
+	entry, err := fs.getEntry(fullFilePath)
 	if err != nil {
 		return nil, os.ErrNotExist
 	}
-	if !strings.HasSuffix(fullFilePath, "/") && fi.IsDir() {
+	if !strings.HasSuffix(fullFilePath, "/") && entry.IsDirectory {
 		fullFilePath += "/"
 	}
 
 	return &WebDavFile{
 		fs:          fs,
 		name:        fullFilePath,
-		isDirectory: false,
+		isDirectory: entry.IsDirectory,
+		entry:       entry,
 	}, nil
 
 }
@@ -312,7 +418,13 @@ func (fs *WebDavFileSystem) Rename(ctx context.Context, oldName, newName string)
 			NewName:      newBaseName,
 		}
 
-		_, err := client.AtomicRenameEntry(ctx, request)
+		err := filer_pb.AtomicRenameEntry(client, request)
+		if err == filer_pb.ErrRenameTargetExists {
+			return os.ErrExist
+		}
+		if err == filer_pb.ErrRenameTargetNotEmpty {
+			return syscall.ENOTEMPTY
+		}
 		if err != nil {
 			return fmt.Errorf("renaming %s/%s => %s/%s: %v", oldDir, oldBaseName, newDir, newBaseName, err)
 		}
@@ -322,33 +434,65 @@ func (fs *WebDavFileSystem) Rename(ctx context.Context, oldName, newName string)
 	})
 }
 
-func (fs *WebDavFileSystem) stat(ctx context.Context, fullFilePath string) (os.FileInfo, error) {
+func (fs *WebDavFileSystem) getEntry(fullFilePath string) (*filer_pb.Entry, error) {
 	var err error
 	if fullFilePath, err = clearName(fullFilePath); err != nil {
 		return nil, err
 	}
 
-	fullpath := util.FullPath(fullFilePath)
+	if fullFilePath != "/" {
+		fullFilePath = strings.TrimSuffix(fullFilePath, "/")
+	}
 
-	var fi FileInfo
-	entry, err := filer_pb.GetEntry(fs, fullpath)
+	entry, err := filer_pb.GetEntry(fs, util.FullPath(fullFilePath))
 	if entry == nil {
 		return nil, os.ErrNotExist
 	}
 	if err != nil {
 		return nil, err
 	}
-	fi.size = int64(filer2.TotalSize(entry.GetChunks()))
-	fi.name = string(fullpath)
-	fi.mode = os.FileMode(entry.Attributes.FileMode)
-	fi.modifiledTime = time.Unix(entry.Attributes.Mtime, 0)
-	fi.isDirectory = entry.IsDirectory
+	if entry.Attributes == nil {
+		entry.Attributes = &filer_pb.FuseAttributes{}
+	}
+	if fullFilePath == "/" {
+		entry.IsDirectory = true
+		entry.Attributes.Mtime = time.Now().Unix()
+	}
+	return entry, nil
+}
+
+func (fs *WebDavFileSystem) stat(ctx context.Context, fullFilePath string) (os.FileInfo, error) {
+	entry, err := fs.getEntry(fullFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return newFileInfo(path.Base(fullFilePath), entry), nil
+}
 
-	if fi.name == "/" {
-		fi.modifiledTime = time.Now()
-		fi.isDirectory = true
+// countEntries counts the entries under the directory recursively, and stops soon after exceeding the limit
+func (fs *WebDavFileSystem) countEntries(fullDirPath string, limit int) (count int, err error) {
+	dirs := []util.FullPath{util.FullPath(path.Clean(fullDirPath))}
+	for len(dirs) > 0 && count <= limit {
+		dir := dirs[0]
+		dirs = dirs[1:]
+		err = filer_pb.ReadDirAllEntries(fs, dir, "", func(entry *filer_pb.Entry, isLast bool) error {
+			count++
+			if count > limit {
+				return io.EOF
+			}
+			if entry.IsDirectory {
+				dirs = append(dirs, dir.Child(entry.Name))
+			}
+			return nil
+		})
+		if count > limit {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
 	}
-	return &fi, nil
+	return count, nil
 }
 
 func (fs *WebDavFileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
@@ -377,11 +521,56 @@ func (f *WebDavFile) Write(buf []byte) (int, error) {
 		return 0, err
 	}
 
+	// the content saved inside the entry moves to a volume server, before the written chunks
+	if len(f.entry.Content) > 0 {
+		chunk, _, _, err := f.uploadChunk(ctx, dir, f.entry.Content, 0)
+		if err != nil {
+			return 0, err
+		}
+		f.entry.Chunks = append([]*filer_pb.FileChunk{chunk}, f.entry.Chunks...)
+		f.entry.Content = nil
+	}
+
+	chunk, collection, replication, err := f.uploadChunk(ctx, dir, buf, f.off)
+	if err != nil {
+		return 0, err
+	}
+	f.entry.Chunks = append(f.entry.Chunks, chunk)
+	f.entry.Attributes.Md5 = nil
+	delete(f.entry.Extended, filer2.ETagKey)
+
+	err = f.fs.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+		f.entry.Attributes.Mtime = time.Now().Unix()
+		f.entry.Attributes.Collection = collection
+		f.entry.Attributes.Replication = replication
+
+		request := &filer_pb.UpdateEntryRequest{
+			Directory: dir,
+			Entry:     f.entry,
+		}
+
+		if _, err := client.UpdateEntry(ctx, request); err != nil {
+			return fmt.Errorf("update %s: %v", f.name, err)
+		}
+
+		return nil
+	})
+
+	if err == nil {
+		glog.V(3).Infof("WebDavFileSystem.Write %v: written [%d,%d)", f.name, f.off, f.off+int64(len(buf)))
+		f.off += int64(len(buf))
+	}
+
+	return len(buf), err
+}
+
+// uploadChunk writes the data to a volume server, as the chunk at the offset
+func (f *WebDavFile) uploadChunk(ctx context.Context, dir string, data []byte, offset int64) (chunk *filer_pb.FileChunk, collection, replication string, err error) {
+
 	var fileId, host string
 	var auth security.EncodedJwt
-	var collection, replication string
 
-	if err = f.fs.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+	if err := f.fs.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
 
 		request := &filer_pb.AssignVolumeRequest{
 			Count:       1,
@@ -404,45 +593,21 @@ func (f *WebDavFile) Write(buf []byte) (int, error) {
 
 		return nil
 	}); err != nil {
-		return 0, fmt.Errorf("filerGrpcAddress assign volume: %v", err)
+		return nil, "", "", fmt.Errorf("filerGrpcAddress assign volume: %v", err)
 	}
 
 	fileUrl := fmt.Sprintf("http://%s/%s", host, fileId)
-	uploadResult, err := operation.UploadData(fileUrl, f.name, f.fs.option.Cipher, buf, false, "", nil, auth)
+	uploadResult, err := operation.UploadData(fileUrl, f.name, f.fs.option.Cipher, data, false, "", nil, auth)
 	if err != nil {
 		glog.V(0).Infof("upload data %v to %s: %v", f.name, fileUrl, err)
-		return 0, fmt.Errorf("upload data: %v", err)
+		return nil, "", "", fmt.Errorf("upload data: %v", err)
 	}
 	if uploadResult.Error != "" {
 		glog.V(0).Infof("upload failure %v to %s: %v", f.name, fileUrl, err)
-		return 0, fmt.Errorf("upload result: %v", uploadResult.Error)
-	}
-
-	f.entry.Chunks = append(f.entry.Chunks, uploadResult.ToPbFileChunk(fileId, f.off))
-
-	err = f.fs.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
-		f.entry.Attributes.Mtime = time.Now().Unix()
-		f.entry.Attributes.Collection = collection
-		f.entry.Attributes.Replication = replication
-
-		request := &filer_pb.UpdateEntryRequest{
-			Directory: dir,
-			Entry:     f.entry,
-		}
-
-		if _, err := client.UpdateEntry(ctx, request); err != nil {
-			return fmt.Errorf("update %s: %v", f.name, err)
-		}
-
-		return nil
-	})
-
-	if err == nil {
-		glog.V(3).Infof("WebDavFileSystem.Write %v: written [%d,%d)", f.name, f.off, f.off+int64(len(buf)))
-		f.off += int64(len(buf))
+		return nil, "", "", fmt.Errorf("upload result: %v", uploadResult.Error)
 	}
 
-	return len(buf), err
+	return uploadResult.ToPbFileChunk(fileId, offset), collection, replication, nil
 }
 
 func (f *WebDavFile) Close() error {
@@ -470,6 +635,14 @@ func (f *WebDavFile) Read(p []byte) (readSize int, err error) {
 	if err != nil {
 		return 0, err
 	}
+	if len(f.entry.Content) > 0 {
+		if f.off >= int64(len(f.entry.Content)) {
+			return 0, io.EOF
+		}
+		readSize = copy(p, f.entry.Content[f.off:])
+		f.off += int64(readSize)
+		return
+	}
 	if len(f.entry.Chunks) == 0 {
 		return 0, io.EOF
 	}
@@ -501,41 +674,40 @@ func (f *WebDavFile) Readdir(count int) (ret []os.FileInfo, err error) {
 
 	dir, _ := util.FullPath(f.name).DirAndName()
 
-	err = filer_pb.ReadDirAllEntries(f.fs, util.FullPath(dir), "", func(entry *filer_pb.Entry, isLast bool) error {
-		fi := FileInfo{
-			size:          int64(filer2.TotalSize(entry.GetChunks())),
-			name:          entry.Name,
-			mode:          os.FileMode(entry.Attributes.FileMode),
-			modifiledTime: time.Unix(entry.Attributes.Mtime, 0),
-			isDirectory:   entry.IsDirectory,
+	// list page by page, continuing after the last listed entry
+	for count <= 0 || len(ret) < count {
+		limit := webDavListPageSize
+		if count > 0 && count-len(ret) < limit {
+			limit = count - len(ret)
 		}
-
-		if !strings.HasSuffix(fi.name, "/") && fi.IsDir() {
-			fi.name += "/"
+		listed := 0
+		err = filer_pb.List(f.fs, dir, "", func(entry *filer_pb.Entry, isLast bool) error {
+			listed++
+			f.lastListedName = entry.Name
+			if entry.Attributes == nil {
+				entry.Attributes = &filer_pb.FuseAttributes{}
+			}
+			fi := newFileInfo(entry.Name, entry)
+			if fi.IsDir() {
+				fi.name += "/"
+			}
+			glog.V(4).Infof("entry: %v", fi.name)
+			ret = append(ret, fi)
+			return nil
+		}, f.lastListedName, false, uint32(limit))
+		if err != nil {
+			return nil, err
 		}
-		glog.V(4).Infof("entry: %v", fi.name)
-		ret = append(ret, &fi)
-		return nil
-	})
-
-	old := f.off
-	if old >= int64(len(ret)) {
-		if count > 0 {
-			return nil, io.EOF
+		if listed < limit {
+			break
 		}
-		return nil, nil
 	}
-	if count > 0 {
-		f.off += int64(count)
-		if f.off > int64(len(ret)) {
-			f.off = int64(len(ret))
-		}
-	} else {
-		f.off = int64(len(ret))
-		old = 0
+
+	if count > 0 && len(ret) == 0 {
+		return nil, io.EOF
 	}
 
-	return ret[old:f.off], nil
+	return ret, nil
 }
 
 func (f *WebDavFile) Seek(offset int64, whence int) (int64, error) {
@@ -563,7 +735,61 @@ func (f *WebDavFile) Stat() (os.FileInfo, error) {
 
 	glog.V(2).Infof("WebDavFile.Stat %v", f.name)
 
+	if f.entry != nil {
+		return newFileInfo(path.Base(f.name), f.entry), nil
+	}
+
 	ctx := context.Background()
 
 	return f.fs.stat(ctx, f.name)
 }
+
+var (
+	creationDatePropName = xml.Name{Space: "DAV:", Local: "creationdate"}
+	etagPropName         = xml.Name{Space: "DAV:", Local: "getetag"}
+)
+
+// DeadProps reports creationdate, and getetag for directories,
+// which the webdav handler does not provide as live properties.
+func (f *WebDavFile) DeadProps() (map[xml.Name]webdav.Property, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	fileInfo := fi.(*FileInfo)
+	props := map[xml.Name]webdav.Property{
+		creationDatePropName: {
+			XMLName:  creationDatePropName,
+			InnerXML: []byte(fileInfo.createdTime.UTC().Format(time.RFC3339)),
+		},
+	}
+	if fileInfo.isDirectory {
+		props[etagPropName] = webdav.Property{
+			XMLName:  etagPropName,
+			InnerXML: []byte(fileInfo.etag),
+		}
+	}
+	return props, nil
+}
+
+// Patch accepts and ignores the Windows file times and attributes, which Windows clients set after each upload.
+// Other properties are not stored.
+func (f *WebDavFile) Patch(patches []webdav.Proppatch) ([]webdav.Propstat, error) {
+	accepted := webdav.Propstat{Status: http.StatusOK}
+	forbidden := webdav.Propstat{Status: http.StatusForbidden}
+	for _, patch := range patches {
+		for _, p := range patch.Props {
+			if p.XMLName.Space == "urn:schemas-microsoft-com:" {
+				accepted.Props = append(accepted.Props, webdav.Property{XMLName: p.XMLName})
+			} else {
+				forbidden.Props = append(forbidden.Props, webdav.Property{XMLName: p.XMLName})
+			}
+		}
+	}
+	if len(forbidden.Props) > 0 {
+		// patching is atomic
+		forbidden.Props = append(forbidden.Props, accepted.Props...)
+		return []webdav.Propstat{forbidden}, nil
+	}
+	return []webdav.Propstat{accepted}, nil
+}
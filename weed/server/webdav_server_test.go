@@ -0,0 +1,66 @@
+package weed_server
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/net/webdav"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+func TestWebDavFileInfo(t *testing.T) {
+	file := newFileInfo("a.txt", &filer_pb.Entry{
+		Name:       "a.txt",
+		Attributes: &filer_pb.FuseAttributes{Mtime: 2, Crtime: 1},
+		Chunks:     []*filer_pb.FileChunk{{Size: 5, ETag: "abc"}},
+	})
+	etag, _ := file.ETag(context.Background())
+	contentType, _ := file.ContentType(context.Background())
+	if file.Size() != 5 || etag != `"abc"` || contentType != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected file info %+v", file)
+	}
+
+	dir := newFileInfo("dir", &filer_pb.Entry{
+		Name:        "dir",
+		IsDirectory: true,
+		Attributes:  &filer_pb.FuseAttributes{Mtime: 255},
+	})
+	if etag, _ = dir.ETag(context.Background()); !dir.IsDir() || etag != `"ff"` {
+		t.Errorf("unexpected dir info %+v", dir)
+	}
+}
+
+func TestWebDavPatch(t *testing.T) {
+	f := &WebDavFile{}
+	win32Times := webdav.Proppatch{Props: []webdav.Property{
+		{XMLName: xml.Name{Space: "urn:schemas-microsoft-com:", Local: "Win32LastModifiedTime"}},
+	}}
+	if propstats, _ := f.Patch([]webdav.Proppatch{win32Times}); len(propstats) != 1 || propstats[0].Status != http.StatusOK {
+		t.Errorf("windows properties should be accepted: %+v", propstats)
+	}
+
+	custom := webdav.Proppatch{Props: []webdav.Property{
+		{XMLName: xml.Name{Space: "example:", Local: "color"}},
+	}}
+	if propstats, _ := f.Patch([]webdav.Proppatch{win32Times, custom}); len(propstats) != 1 || propstats[0].Status != http.StatusForbidden || len(propstats[0].Props) != 2 {
+		t.Errorf("patching should be atomic: %+v", propstats)
+	}
+}
+
+func TestCapLockDuration(t *testing.T) {
+	for _, tt := range []struct {
+		duration, expected time.Duration
+	}{
+		{-1, webDavMaxLockDuration},
+		{time.Minute, time.Minute},
+		{24 * time.Hour, webDavMaxLockDuration},
+	} {
+		if capped := capLockDuration(tt.duration); capped != tt.expected {
+			t.Errorf("cap %v: %v, expected %v", tt.duration, capped, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package shell
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+func init() {
+	Commands = append(Commands, &commandBucketLimits{})
+}
+
+type commandBucketLimits struct {
+}
+
+func (c *commandBucketLimits) Name() string {
+	return "bucket.limits"
+}
+
+func (c *commandBucketLimits) Help() string {
+	return `show or set the object size limits of a bucket
+
+	bucket.limits -name <bucket_name>                                      # show the limits
+	bucket.limits -name <bucket_name> -maxObjectSize=5GiB                  # reject the larger objects
+	bucket.limits -name <bucket_name> -multipartThreshold=100MiB           # the larger objects must be uploaded in parts
+	bucket.limits -name <bucket_name> -maxObjectSize=0                     # use the limit of the s3 server
+
+	The limits of the bucket override the -object.maxSizeMB and -object.multipartThresholdMB of the s3 server.
+`
+}
+
+func (c *commandBucketLimits) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	bucketCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	bucketName := bucketCommand.String("name", "", "bucket name")
+	maxObjectSize := bucketCommand.String("maxObjectSize", "", "the max object size, e.g. 5GiB, 0 to use the limit of the s3 server")
+	multipartThreshold := bucketCommand.String("multipartThreshold", "", "the max size of the objects uploaded in one request, 0 to use the limit of the s3 server")
+	if err = bucketCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	if *bucketName == "" {
+		return fmt.Errorf("empty bucket name")
+	}
+
+	return commandEnv.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+
+		resp, err := client.GetFilerConfiguration(context.Background(), &filer_pb.GetFilerConfigurationRequest{})
+		if err != nil {
+			return fmt.Errorf("get filer configuration: %v", err)
+		}
+		filerBucketsPath := resp.DirBuckets
+
+		lookupResp, err := filer_pb.LookupEntry(client, &filer_pb.LookupDirectoryEntryRequest{
+			Directory: filerBucketsPath,
+			Name:      *bucketName,
+		})
+		if err != nil {
+			return fmt.Errorf("lookup bucket %s: %v", *bucketName, err)
+		}
+		entry := lookupResp.Entry
+
+		if *maxObjectSize == "" && *multipartThreshold == "" {
+			fmt.Fprintf(writer, "%s\n", *bucketName)
+			fmt.Fprintf(writer, "  max object size: %s\n", bucketSizeLimit(entry.Extended[filer2.BucketMaxObjectSizeKey]))
+			fmt.Fprintf(writer, "  multipart threshold: %s\n", bucketSizeLimit(entry.Extended[filer2.BucketMultipartThresholdKey]))
+			return nil
+		}
+
+		if entry.Extended == nil {
+			entry.Extended = make(map[string][]byte)
+		}
+		if err = setBucketSizeLimit(entry.Extended, filer2.BucketMaxObjectSizeKey, *maxObjectSize); err != nil {
+			return fmt.Errorf("parse maxObjectSize %s: %v", *maxObjectSize, err)
+		}
+		if err = setBucketSizeLimit(entry.Extended, filer2.BucketMultipartThresholdKey, *multipartThreshold); err != nil {
+			return fmt.Errorf("parse multipartThreshold %s: %v", *multipartThreshold, err)
+		}
+
+		if _, err = client.UpdateEntry(context.Background(), &filer_pb.UpdateEntryRequest{
+			Directory: filerBucketsPath,
+			Entry:     entry,
+		}); err != nil {
+			return fmt.Errorf("update bucket %s: %v", *bucketName, err)
+		}
+
+		fmt.Fprintf(writer, "set the limits of bucket %s: max object size %s, multipart threshold %s\n", *bucketName,
+			bucketSizeLimit(entry.Extended[filer2.BucketMaxObjectSizeKey]), bucketSizeLimit(entry.Extended[filer2.BucketMultipartThresholdKey]))
+
+		return nil
+
+	})
+
+}
+
+// setBucketSizeLimit saves the size in bytes, or removes the limit if the size is 0
+func setBucketSizeLimit(extended map[string][]byte, key, value string) error {
+	if value == "" {
+		return nil
+	}
+	size, err := humanize.ParseBytes(value)
+	if err != nil {
+		return err
+	}
+	if size == 0 {
+		delete(extended, key)
+		return nil
+	}
+	extended[key] = []byte(strconv.FormatUint(size, 10))
+	return nil
+}
+
+func bucketSizeLimit(value []byte) string {
+	size, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil || size == 0 {
+		return "the limit of the s3 server"
+	}
+	return humanize.IBytes(size)
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/wdclient"
 )
 
+const ecShardsRegisterTimeout = time.Minute
+
 func init() {
 	Commands = append(Commands, &commandEcEncode{})
 }
@@ -37,8 +39,9 @@ func (c *commandEcEncode) Help() string {
 
 	This command will:
 	1. freeze one volume
-	2. apply erasure coding to the volume
+	2. apply erasure coding to the volume, and verify the encoded shards
 	3. move the encoded shards to multiple volume servers
+	4. delete the original volume after all the shards are registered on the master
 
 	The erasure coding is 10.4. So ideally you have more than 14 volume servers, and you can afford
 	to lose 4 volume servers.
@@ -71,7 +74,7 @@ func (c *commandEcEncode) Do(args []string, commandEnv *CommandEnv, writer io.Wr
 
 	// volumeId is provided
 	if vid != 0 {
-		return doEcEncode(commandEnv, *collection, vid)
+		return doEcEncode(commandEnv, *collection, vid, nil)
 	}
 
 	// apply to all volumes in the collection
@@ -81,7 +84,7 @@ func (c *commandEcEncode) Do(args []string, commandEnv *CommandEnv, writer io.Wr
 	}
 	fmt.Printf("ec encode volumes: %v\n", volumeIds)
 	for _, vid := range volumeIds {
-		if err = doEcEncode(commandEnv, *collection, vid); err != nil {
+		if err = doEcEncode(commandEnv, *collection, vid, nil); err != nil {
 			return err
 		}
 	}
@@ -89,7 +92,18 @@ func (c *commandEcEncode) Do(args []string, commandEnv *CommandEnv, writer io.Wr
 	return nil
 }
 
-func doEcEncode(commandEnv *CommandEnv, collection string, vid needle.VolumeId) (err error) {
+// EcEncodeVolume applies erasure coding to one volume, and reports each step to progressFn
+func EcEncodeVolume(commandEnv *CommandEnv, collection string, vid needle.VolumeId, progressFn func(step string)) error {
+	return doEcEncode(commandEnv, collection, vid, progressFn)
+}
+
+func doEcEncode(commandEnv *CommandEnv, collection string, vid needle.VolumeId, progressFn func(step string)) (err error) {
+	progress := func(step string) {
+		if progressFn != nil {
+			progressFn(step)
+		}
+	}
+
 	// find volume location
 	locations, found := commandEnv.MasterClient.GetLocations(uint32(vid))
 	if !found {
@@ -99,26 +113,73 @@ func doEcEncode(commandEnv *CommandEnv, collection string, vid needle.VolumeId)
 	// fmt.Printf("found ec %d shards on %v\n", vid, locations)
 
 	// mark the volume as readonly
+	progress("mark readonly")
 	err = markVolumeReadonly(commandEnv.option.GrpcDialOption, needle.VolumeId(vid), locations)
 	if err != nil {
 		return fmt.Errorf("mark volume %d as readonly on %s: %v", vid, locations[0].Url, err)
 	}
 
-	// generate ec shards
+	// generate ec shards, which are verified against the volume data
+	progress("generate ec shards")
 	err = generateEcShards(commandEnv.option.GrpcDialOption, needle.VolumeId(vid), collection, locations[0].Url)
 	if err != nil {
 		return fmt.Errorf("generate ec shards for volume %d on %s: %v", vid, locations[0].Url, err)
 	}
 
 	// balance the ec shards to current cluster
+	progress("spread ec shards")
 	err = spreadEcShards(commandEnv, vid, collection, locations)
 	if err != nil {
 		return fmt.Errorf("spread ec shards for volume %d from %s: %v", vid, locations[0].Url, err)
 	}
 
+	// only delete the original volume after all ec shards are in service
+	progress("verify ec shards")
+	err = waitUntilEcShardsRegistered(commandEnv, vid, ecShardsRegisterTimeout)
+	if err != nil {
+		return err
+	}
+
+	// ask the source volume servers to delete the original volume
+	progress("delete volume replicas")
+	for _, location := range locations {
+		fmt.Printf("delete volume %d from %s\n", vid, location.Url)
+		err = deleteVolume(commandEnv.option.GrpcDialOption, vid, location.Url)
+		if err != nil {
+			return fmt.Errorf("deleteVolume %s volume %d: %v", location.Url, vid, err)
+		}
+	}
+
 	return nil
 }
 
+func waitUntilEcShardsRegistered(commandEnv *CommandEnv, vid needle.VolumeId, timeout time.Duration) error {
+	var shardCount int
+	for deadline := time.Now().Add(timeout); ; time.Sleep(time.Second) {
+		err := commandEnv.MasterClient.WithClient(func(client master_pb.SeaweedClient) error {
+			resp, lookupErr := client.LookupEcVolume(context.Background(), &master_pb.LookupEcVolumeRequest{
+				VolumeId: uint32(vid),
+			})
+			if lookupErr != nil {
+				return lookupErr
+			}
+			shardCount = 0
+			for _, shardIdLocation := range resp.ShardIdLocations {
+				if len(shardIdLocation.Locations) > 0 {
+					shardCount++
+				}
+			}
+			return nil
+		})
+		if err == nil && shardCount == erasure_coding.TotalShardsCount {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("only %d of %d ec shards of volume %d are registered: %v", shardCount, erasure_coding.TotalShardsCount, vid, err)
+		}
+	}
+}
+
 func markVolumeReadonly(grpcDialOption grpc.DialOption, volumeId needle.VolumeId, locations []wdclient.Location) error {
 
 	for _, location := range locations {
@@ -189,15 +250,6 @@ func spreadEcShards(commandEnv *CommandEnv, volumeId needle.VolumeId, collection
 		return fmt.Errorf("source delete copied ecShards %s %d.%v: %v", existingLocations[0].Url, volumeId, copiedShardIds, err)
 	}
 
-	// ask the source volume server to delete the original volume
-	for _, location := range existingLocations {
-		fmt.Printf("delete volume %d from %s\n", volumeId, location.Url)
-		err = deleteVolume(commandEnv.option.GrpcDialOption, volumeId, location.Url)
-		if err != nil {
-			return fmt.Errorf("deleteVolume %s volume %d: %v", location.Url, volumeId, err)
-		}
-	}
-
 	return err
 
 }
@@ -0,0 +1,125 @@
+package shell
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsPolicy{})
+}
+
+type commandFsPolicy struct {
+}
+
+func (c *commandFsPolicy) Name() string {
+	return "fs.policy"
+}
+
+func (c *commandFsPolicy) Help() string {
+	return `show or set the storage policies of the path prefixes
+
+	fs.policy                                                    # list the policies
+	fs.policy -prefix=/logs/ -replication=000 -ttl=7d            # add or change the policy of the prefix
+	fs.policy -prefix=/critical/ -replication=010 -diskType=ssd
+	fs.policy -prefix=/logs/ -delete                             # remove the policy of the prefix
+
+	The files written under the prefix get the collection, replication, ttl and disk type of the policy,
+	unless the writes ask for their own. The longest matching prefix wins.
+	The filer reloads the policies when they are changed, and so do the filers started with it in -peers.
+`
+}
+
+func (c *commandFsPolicy) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	policyCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	prefix := policyCommand.String("prefix", "", "the path prefix, e.g. /logs/")
+	collection := policyCommand.String("collection", "", "the collection of the files")
+	replication := policyCommand.String("replication", "", "the replication of the files, e.g. 001")
+	ttl := policyCommand.String("ttl", "", "the time to live of the files, e.g. 1h, 7d")
+	diskType := policyCommand.String("diskType", "", "the disk type of the files, e.g. hdd, ssd")
+	deletePolicy := policyCommand.Bool("delete", false, "remove the policy of the prefix")
+	if err = policyCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	dir, name := util.FullPath(filer2.PathPolicyFile).DirAndName()
+
+	return commandEnv.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+
+		var content []byte
+		resp, lookupErr := filer_pb.LookupEntry(client, &filer_pb.LookupDirectoryEntryRequest{
+			Directory: dir,
+			Name:      name,
+		})
+		if lookupErr == nil {
+			content = resp.Entry.Content
+		} else if lookupErr != filer_pb.ErrNotFound {
+			return lookupErr
+		}
+
+		policies, err := filer2.ParsePathPolicies(content)
+		if err != nil {
+			return err
+		}
+
+		if *prefix == "" {
+			if len(policies.Policies) == 0 {
+				fmt.Fprintf(writer, "no policies\n")
+				return nil
+			}
+			for _, p := range policies.Policies {
+				fmt.Fprintf(writer, "%s\tcollection:%s\treplication:%s\tttl:%s\tdiskType:%s\n", p.Prefix, p.Collection, p.Replication, p.Ttl, p.DiskType)
+			}
+			return nil
+		}
+
+		if *deletePolicy {
+			if !policies.Delete(*prefix) {
+				return fmt.Errorf("no policy for %s", *prefix)
+			}
+		} else {
+			if err = policies.Set(&filer2.PathPolicy{
+				Prefix:      *prefix,
+				Collection:  *collection,
+				Replication: *replication,
+				Ttl:         *ttl,
+				DiskType:    *diskType,
+			}); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now().Unix()
+		if err = filer_pb.CreateEntry(client, &filer_pb.CreateEntryRequest{
+			Directory: dir,
+			Entry: &filer_pb.Entry{
+				Name:    name,
+				Content: policies.Bytes(),
+				Attributes: &filer_pb.FuseAttributes{
+					Mtime:    now,
+					Crtime:   now,
+					FileMode: uint32(os.FileMode(0644)),
+				},
+			},
+		}); err != nil {
+			return fmt.Errorf("save %s: %v", filer2.PathPolicyFile, err)
+		}
+
+		if *deletePolicy {
+			fmt.Fprintf(writer, "removed the policy of %s\n", *prefix)
+		} else {
+			fmt.Fprintf(writer, "set the policy of %s\n", *prefix)
+		}
+		return nil
+
+	})
+
+}
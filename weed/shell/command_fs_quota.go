@@ -0,0 +1,138 @@
+package shell
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+func init() {
+	Commands = append(Commands, &commandFsQuota{})
+}
+
+type commandFsQuota struct {
+}
+
+func (c *commandFsQuota) Name() string {
+	return "fs.quota"
+}
+
+func (c *commandFsQuota) Help() string {
+	return `show or set the quota of a directory
+
+	fs.quota /dir/                                   # show the quota and the current usage
+	fs.quota -maxBytes=10GiB -maxFiles=100000 /dir/  # set the quota, 0 is unlimited
+	fs.quota -clear /dir/                            # remove the quota
+
+	The writes putting the directory tree over its quota fail.
+	The usage is saved by the filer every few seconds.
+`
+}
+
+func (c *commandFsQuota) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	quotaCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	maxBytes := quotaCommand.String("maxBytes", "", "the max total size of the files, e.g. 500MiB, 10GiB")
+	maxFiles := quotaCommand.Int64("maxFiles", -1, "the max number of files")
+	clearQuota := quotaCommand.Bool("clear", false, "remove the quota")
+	if err = quotaCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	path, err := commandEnv.parseUrl(findInputDirectory(quotaCommand.Args()))
+	if err != nil {
+		return err
+	}
+
+	if err = commandEnv.checkDirectory(path); err != nil {
+		return err
+	}
+
+	dir, name := util.FullPath(path).DirAndName()
+
+	return commandEnv.WithFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+
+		resp, err := filer_pb.LookupEntry(client, &filer_pb.LookupDirectoryEntryRequest{
+			Directory: dir,
+			Name:      name,
+		})
+		if err != nil {
+			return err
+		}
+		entry := resp.Entry
+
+		quota, found := filer2.LoadDirectoryQuota(entry.Extended)
+
+		if !*clearQuota && *maxBytes == "" && *maxFiles < 0 {
+			if !found {
+				fmt.Fprintf(writer, "%s has no quota\n", path)
+				return nil
+			}
+			usage := filer2.LoadQuotaUsage(entry.Extended)
+			fmt.Fprintf(writer, "%s\n", path)
+			fmt.Fprintf(writer, "  bytes: %s of %s\n", humanize.IBytes(uint64(usage.Bytes)), quotaLimit(quota.MaxBytes, humanize.IBytes))
+			fmt.Fprintf(writer, "  files: %d of %s\n", usage.Files, quotaLimit(quota.MaxFiles, func(n uint64) string {
+				return fmt.Sprintf("%d", n)
+			}))
+			return nil
+		}
+
+		if *clearQuota {
+			if !found {
+				return nil
+			}
+			delete(entry.Extended, filer2.QuotaKey)
+			delete(entry.Extended, filer2.QuotaUsageKey)
+		} else {
+			if *maxBytes != "" {
+				size, parseErr := humanize.ParseBytes(*maxBytes)
+				if parseErr != nil {
+					return fmt.Errorf("parse maxBytes %s: %v", *maxBytes, parseErr)
+				}
+				quota.MaxBytes = int64(size)
+			}
+			if *maxFiles >= 0 {
+				quota.MaxFiles = *maxFiles
+			}
+			if entry.Extended == nil {
+				entry.Extended = make(map[string][]byte)
+			}
+			entry.Extended[filer2.QuotaKey], _ = json.Marshal(quota)
+		}
+
+		if _, err = client.UpdateEntry(context.Background(), &filer_pb.UpdateEntryRequest{
+			Directory: dir,
+			Entry:     entry,
+		}); err != nil {
+			return fmt.Errorf("update %s: %v", path, err)
+		}
+
+		if *clearQuota {
+			fmt.Fprintf(writer, "removed the quota of %s\n", path)
+		} else {
+			fmt.Fprintf(writer, "set the quota of %s: %s, %s files\n", path,
+				quotaLimit(quota.MaxBytes, humanize.IBytes), quotaLimit(quota.MaxFiles, func(n uint64) string {
+					return fmt.Sprintf("%d", n)
+				}))
+		}
+
+		return nil
+
+	})
+
+}
+
+func quotaLimit(limit int64, format func(uint64) string) string {
+	if limit <= 0 {
+		return "unlimited"
+	}
+	return format(uint64(limit))
+}
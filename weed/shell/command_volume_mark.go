@@ -0,0 +1,126 @@
+package shell
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/operation"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+)
+
+func init() {
+	Commands = append(Commands, &commandVolumeMark{})
+}
+
+type commandVolumeMark struct {
+}
+
+func (c *commandVolumeMark) Name() string {
+	return "volume.mark"
+}
+
+func (c *commandVolumeMark) Help() string {
+	return `mark a volume, or a whole volume server, readonly or writable
+
+	volume.mark -volumeId=<volume id> [-node=<volume server host:port>] -readonly|-writable
+	volume.mark -node=<volume server host:port> -readonly|-writable
+
+	With -volumeId, the volume is marked on the given volume server, or on all its replicas if -node is empty.
+	Without -volumeId, the whole volume server is marked, e.g. to drain it before disk maintenance.
+	A readonly volume server accepts no writes or new volumes, and reads continue to be served.
+
+	The master stops assigning writes to readonly volumes within one heartbeat,
+	and assigns writes again after they are marked writable. No restart is needed.
+
+`
+}
+
+func (c *commandVolumeMark) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	if err = commandEnv.confirmIsLocked(); err != nil {
+		return
+	}
+
+	markCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	volumeId := markCommand.Uint("volumeId", 0, "the volume id")
+	node := markCommand.String("node", "", "the volume server <host>:<port>")
+	readonly := markCommand.Bool("readonly", false, "mark readonly")
+	writable := markCommand.Bool("writable", false, "mark writable")
+	if err = markCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	if *readonly == *writable {
+		return fmt.Errorf("use either -readonly or -writable")
+	}
+
+	if *volumeId == 0 {
+		if *node == "" {
+			return fmt.Errorf("need -volumeId or -node")
+		}
+		if err = markVolumeServerReadonly(commandEnv.option.GrpcDialOption, *node, *readonly); err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "volume server %s readonly:%v\n", *node, *readonly)
+		return nil
+	}
+
+	vid := needle.VolumeId(*volumeId)
+	servers := []string{*node}
+	if *node == "" {
+		topologyInfo, err := collectTopologyInfo(commandEnv)
+		if err != nil {
+			return err
+		}
+		servers = nil
+		eachDataNode(topologyInfo, func(dc string, rack RackId, dn *master_pb.DataNodeInfo) {
+			for _, v := range dn.VolumeInfos {
+				if v.Id == uint32(vid) {
+					servers = append(servers, dn.Id)
+				}
+			}
+		})
+		if len(servers) == 0 {
+			return fmt.Errorf("volume %d not found", vid)
+		}
+	}
+
+	for _, server := range servers {
+		if err = markVolume(commandEnv.option.GrpcDialOption, vid, server, *readonly); err != nil {
+			return fmt.Errorf("mark volume %d on %s: %v", vid, server, err)
+		}
+		fmt.Fprintf(writer, "volume %d on %s readonly:%v\n", vid, server, *readonly)
+	}
+
+	return nil
+}
+
+func markVolume(grpcDialOption grpc.DialOption, volumeId needle.VolumeId, sourceVolumeServer string, readonly bool) (err error) {
+	return operation.WithVolumeServerClient(sourceVolumeServer, grpcDialOption, func(volumeServerClient volume_server_pb.VolumeServerClient) error {
+		if readonly {
+			_, markErr := volumeServerClient.VolumeMarkReadonly(context.Background(), &volume_server_pb.VolumeMarkReadonlyRequest{
+				VolumeId: uint32(volumeId),
+			})
+			return markErr
+		}
+		_, markErr := volumeServerClient.VolumeMarkWritable(context.Background(), &volume_server_pb.VolumeMarkWritableRequest{
+			VolumeId: uint32(volumeId),
+		})
+		return markErr
+	})
+}
+
+func markVolumeServerReadonly(grpcDialOption grpc.DialOption, volumeServer string, readonly bool) (err error) {
+	return operation.WithVolumeServerClient(volumeServer, grpcDialOption, func(volumeServerClient volume_server_pb.VolumeServerClient) error {
+		_, markErr := volumeServerClient.VolumeServerMarkReadonly(context.Background(), &volume_server_pb.VolumeServerMarkReadonlyRequest{
+			Readonly: readonly,
+		})
+		return markErr
+	})
+}
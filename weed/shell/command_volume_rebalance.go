@@ -0,0 +1,395 @@
+package shell
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/chrislusf/seaweedfs/weed/operation"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/volume_server_pb"
+	"github.com/chrislusf/seaweedfs/weed/storage/idx"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
+)
+
+func init() {
+	Commands = append(Commands, &commandVolumeRebalance{})
+}
+
+type commandVolumeRebalance struct {
+}
+
+func (c *commandVolumeRebalance) Name() string {
+	return "volume.rebalance"
+}
+
+func (c *commandVolumeRebalance) Help() string {
+	return `move volumes from fuller to emptier volume servers, to even out the disk usage
+
+	volume.rebalance [-collection=<collection_name>] [-dataCenter=<data_center_name>] [-maxMoves=<n>] [-bandwidthMBps=<n>] [-force]
+
+	Without -force, this only prints the planned moves and the estimated bytes to move.
+
+	The disk usage of a volume server is the total size of its volumes, relative to its max volume count.
+	A volume is moved only if the move brings both volume servers closer to the average disk usage,
+	and the volume replicas still satisfy the replica placement after the move.
+	With -collection, all volumes count for the disk usage, but only the volumes of the collection are moved.
+
+	Each volume is moved this way:
+	1. The target volume server copies the volume, limited to -bandwidthMBps if set.
+	2. The source volume is marked readonly, and the target volume server tails the writes made during the copy.
+	3. The needles of both copies are compared.
+	4. Only if they are the same, the source volume is deleted. Otherwise the target copy is deleted.
+	The source volume is never deleted before the target copy is verified.
+
+	The master can also run the rebalancing in the background, see http://<master>/vol/rebalance
+
+`
+}
+
+func (c *commandVolumeRebalance) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	rebalanceCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	collection := rebalanceCommand.String("collection", "", "only move the volumes of this collection")
+	dc := rebalanceCommand.String("dataCenter", "", "only rebalance the volume servers in this data center")
+	maxMoves := rebalanceCommand.Int("maxMoves", 0, "stop after this many moves, 0 for no limit")
+	bandwidthMBps := rebalanceCommand.Int("bandwidthMBps", 0, "limit the copying of each volume to this many MB per second, 0 for the volume server default")
+	applyMoves := rebalanceCommand.Bool("force", false, "apply the moves, instead of only printing them")
+	if err = rebalanceCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	if *applyMoves {
+		if err = commandEnv.confirmIsLocked(); err != nil {
+			return
+		}
+	}
+
+	var resp *master_pb.VolumeListResponse
+	err = commandEnv.MasterClient.WithClient(func(client master_pb.SeaweedClient) error {
+		resp, err = client.VolumeList(context.Background(), &master_pb.VolumeListRequest{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	moves := PlanVolumeRebalance(resp.TopologyInfo, resp.VolumeSizeLimitMb*1024*1024, *collection, *dc, *maxMoves)
+	var totalBytes uint64
+	for _, move := range moves {
+		fmt.Fprintf(writer, "move %s\n", move)
+		totalBytes += move.Size
+	}
+	fmt.Fprintf(writer, "%d moves, %d bytes\n", len(moves), totalBytes)
+
+	if !*applyMoves {
+		return nil
+	}
+
+	for i, move := range moves {
+		fmt.Fprintf(writer, "moving %d/%d: %s\n", i+1, len(moves), move)
+		if err = MoveVolumeVerified(commandEnv.option.GrpcDialOption, move, int64(*bandwidthMBps)*1024*1024, 5*time.Second); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VolumeMove moves one replica of a volume from the source to the target volume server
+type VolumeMove struct {
+	VolumeId   needle.VolumeId
+	Collection string
+	Size       uint64
+	Source     string
+	Target     string
+	readOnly   bool
+}
+
+func (move *VolumeMove) String() string {
+	return fmt.Sprintf("volume %d collection %q %d bytes %s => %s", move.VolumeId, move.Collection, move.Size, move.Source, move.Target)
+}
+
+type rebalanceNode struct {
+	location
+	usage       float64
+	capacity    float64
+	freeVolumes int64
+}
+
+// PlanVolumeRebalance plans the moves of volumes from fuller to emptier volume servers of the same disk type.
+// Each move reduces the total distance of the two volume servers from the average disk usage,
+// and keeps the replica placement of the volume. A volume is moved at most once.
+func PlanVolumeRebalance(topologyInfo *master_pb.TopologyInfo, volumeSizeLimit uint64, collection, dataCenter string, maxMoves int) (moves []*VolumeMove) {
+
+	var nodes []*rebalanceNode
+	replicas := make(map[uint32][]location)
+	eachDataNode(topologyInfo, func(dc string, rack RackId, dn *master_pb.DataNodeInfo) {
+		for _, v := range dn.VolumeInfos {
+			replicas[v.Id] = append(replicas[v.Id], newLocation(dc, string(rack), dn))
+		}
+		if (dataCenter != "" && dc != dataCenter) || dn.MaxVolumeCount == 0 {
+			return
+		}
+		node := &rebalanceNode{
+			location:    newLocation(dc, string(rack), dn),
+			capacity:    float64(dn.MaxVolumeCount * volumeSizeLimit),
+			freeVolumes: int64(dn.FreeVolumeCount),
+		}
+		for _, v := range dn.VolumeInfos {
+			node.usage += float64(v.Size)
+		}
+		nodes = append(nodes, node)
+	})
+	if len(nodes) < 2 {
+		return nil
+	}
+
+	moved := make(map[uint32]bool)
+	for maxMoves <= 0 || len(moves) < maxMoves {
+		move := planOneVolumeMove(nodes, replicas, collection, moved)
+		if move == nil {
+			break
+		}
+		moved[uint32(move.VolumeId)] = true
+		moves = append(moves, move)
+	}
+
+	return
+}
+
+func planOneVolumeMove(nodes []*rebalanceNode, replicas map[uint32][]location, collection string, moved map[uint32]bool) *VolumeMove {
+
+	var totalUsage, totalCapacity float64
+	for _, node := range nodes {
+		totalUsage += node.usage
+		totalCapacity += node.capacity
+	}
+	averageRatio := totalUsage / totalCapacity
+	deviation := func(node *rebalanceNode) float64 {
+		return node.usage - averageRatio*node.capacity
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return deviation(nodes[i]) > deviation(nodes[j])
+	})
+
+	for _, full := range nodes {
+		fullDeviation := deviation(full)
+		if fullDeviation <= 0 {
+			break
+		}
+
+		var bestVolume *master_pb.VolumeInformationMessage
+		var bestTarget *rebalanceNode
+		var bestGain float64
+		// prefer the emptiest volume servers
+		for i := len(nodes) - 1; i >= 0; i-- {
+			empty := nodes[i]
+			emptyDeviation := deviation(empty)
+			if emptyDeviation >= 0 || empty.freeVolumes <= 0 || empty.dataNode.DiskType != full.dataNode.DiskType {
+				continue
+			}
+			for _, v := range full.dataNode.VolumeInfos {
+				if moved[v.Id] || v.RemoteStorageName != "" || (collection != "" && v.Collection != collection) {
+					continue
+				}
+				size := float64(v.Size)
+				gain := math.Abs(fullDeviation) + math.Abs(emptyDeviation) - math.Abs(fullDeviation-size) - math.Abs(emptyDeviation+size)
+				if gain <= bestGain {
+					continue
+				}
+				if !canMoveReplica(v, replicas[v.Id], full.location, empty.location) {
+					continue
+				}
+				bestVolume, bestTarget, bestGain = v, empty, gain
+			}
+		}
+		if bestVolume == nil {
+			continue
+		}
+
+		full.usage -= float64(bestVolume.Size)
+		full.freeVolumes++
+		bestTarget.usage += float64(bestVolume.Size)
+		bestTarget.freeVolumes--
+		var newReplicas []location
+		for _, replica := range replicas[bestVolume.Id] {
+			if replica.dataNode.Id != full.dataNode.Id {
+				newReplicas = append(newReplicas, replica)
+			}
+		}
+		replicas[bestVolume.Id] = append(newReplicas, bestTarget.location)
+
+		return &VolumeMove{
+			VolumeId:   needle.VolumeId(bestVolume.Id),
+			Collection: bestVolume.Collection,
+			Size:       bestVolume.Size,
+			Source:     full.dataNode.Id,
+			Target:     bestTarget.dataNode.Id,
+			readOnly:   bestVolume.ReadOnly,
+		}
+	}
+
+	return nil
+}
+
+// canMoveReplica checks the other replicas and the target satisfy the replica placement
+func canMoveReplica(v *master_pb.VolumeInformationMessage, replicas []location, source, target location) bool {
+	replicaPlacement, err := super_block.NewReplicaPlacementFromByte(byte(v.ReplicaPlacement))
+	if err != nil {
+		return false
+	}
+	var others []location
+	for _, replica := range replicas {
+		if replica.dataNode.Id != source.dataNode.Id {
+			others = append(others, replica)
+		}
+	}
+	return satisfyReplicaPlacement(replicaPlacement, others, target)
+}
+
+// MoveVolumeVerified moves the volume, and only deletes the source volume after verifying the target copy has the same needles.
+// The copying is limited to bytesPerSecond, if positive.
+func MoveVolumeVerified(grpcDialOption grpc.DialOption, move *VolumeMove, bytesPerSecond int64, idleTimeout time.Duration) (err error) {
+
+	lastAppendAtNs, err := copyVolumeWithLimit(grpcDialOption, move.VolumeId, move.Source, move.Target, bytesPerSecond)
+	if err != nil {
+		return fmt.Errorf("copy volume %d from %s to %s: %v", move.VolumeId, move.Source, move.Target, err)
+	}
+
+	// the target copy is incomplete until verified
+	verified := false
+	defer func() {
+		if err == nil || verified {
+			return
+		}
+		if deleteErr := deleteVolume(grpcDialOption, move.VolumeId, move.Target); deleteErr != nil {
+			err = fmt.Errorf("%v, and delete the target copy: %v", err, deleteErr)
+		}
+		if !move.readOnly {
+			if markErr := markVolume(grpcDialOption, move.VolumeId, move.Source, false); markErr != nil {
+				err = fmt.Errorf("%v, and mark the source writable again: %v", err, markErr)
+			}
+		}
+	}()
+
+	if err = markVolume(grpcDialOption, move.VolumeId, move.Source, true); err != nil {
+		return fmt.Errorf("mark volume %d readonly on %s: %v", move.VolumeId, move.Source, err)
+	}
+
+	if err = tailVolume(grpcDialOption, move.VolumeId, move.Source, move.Target, lastAppendAtNs, idleTimeout); err != nil {
+		return fmt.Errorf("tail volume %d from %s to %s: %v", move.VolumeId, move.Source, move.Target, err)
+	}
+
+	if err = verifyVolumeCopy(grpcDialOption, move.VolumeId, move.Source, move.Target); err != nil {
+		return fmt.Errorf("verify volume %d on %s: %v", move.VolumeId, move.Target, err)
+	}
+	verified = true
+
+	// failing from now on leaves both copies
+	if err = deleteVolume(grpcDialOption, move.VolumeId, move.Source); err != nil {
+		return fmt.Errorf("delete volume %d from %s: %v", move.VolumeId, move.Source, err)
+	}
+
+	if !move.readOnly {
+		if err = markVolume(grpcDialOption, move.VolumeId, move.Target, false); err != nil {
+			return fmt.Errorf("mark volume %d writable on %s: %v", move.VolumeId, move.Target, err)
+		}
+	}
+
+	return nil
+}
+
+func copyVolumeWithLimit(grpcDialOption grpc.DialOption, volumeId needle.VolumeId, sourceVolumeServer, targetVolumeServer string, bytesPerSecond int64) (lastAppendAtNs uint64, err error) {
+
+	err = operation.WithVolumeServerClient(targetVolumeServer, grpcDialOption, func(volumeServerClient volume_server_pb.VolumeServerClient) error {
+		resp, replicateErr := volumeServerClient.VolumeCopy(context.Background(), &volume_server_pb.VolumeCopyRequest{
+			VolumeId:        uint32(volumeId),
+			SourceDataNode:  sourceVolumeServer,
+			IoBytePerSecond: bytesPerSecond,
+		})
+		if replicateErr == nil {
+			lastAppendAtNs = resp.LastAppendAtNs
+		}
+		return replicateErr
+	})
+
+	return
+}
+
+// verifyVolumeCopy compares the live needles and their sizes in the .idx files of both volume copies
+func verifyVolumeCopy(grpcDialOption grpc.DialOption, volumeId needle.VolumeId, sourceVolumeServer, targetVolumeServer string) error {
+
+	sourceNeedles, err := readVolumeNeedles(grpcDialOption, volumeId, sourceVolumeServer)
+	if err != nil {
+		return fmt.Errorf("read index on %s: %v", sourceVolumeServer, err)
+	}
+	targetNeedles, err := readVolumeNeedles(grpcDialOption, volumeId, targetVolumeServer)
+	if err != nil {
+		return fmt.Errorf("read index on %s: %v", targetVolumeServer, err)
+	}
+
+	if len(sourceNeedles) != len(targetNeedles) {
+		return fmt.Errorf("%d needles, expected %d", len(targetNeedles), len(sourceNeedles))
+	}
+	for key, size := range sourceNeedles {
+		if targetSize, found := targetNeedles[key]; !found || targetSize != size {
+			return fmt.Errorf("needle %s size %d, expected %d", key, targetSize, size)
+		}
+	}
+
+	return nil
+}
+
+// readVolumeNeedles returns the sizes of the live needles in the volume
+func readVolumeNeedles(grpcDialOption grpc.DialOption, volumeId needle.VolumeId, volumeServer string) (needles map[types.NeedleId]uint32, err error) {
+
+	needles = make(map[types.NeedleId]uint32)
+	err = operation.WithVolumeServerClient(volumeServer, grpcDialOption, func(volumeServerClient volume_server_pb.VolumeServerClient) error {
+		stream, err := volumeServerClient.CopyFile(context.Background(), &volume_server_pb.CopyFileRequest{
+			VolumeId:           uint32(volumeId),
+			Ext:                ".idx",
+			CompactionRevision: math.MaxUint32,
+			StopOffset:         math.MaxInt64,
+		})
+		if err != nil {
+			return err
+		}
+
+		var buffer []byte
+		for {
+			resp, recvErr := stream.Recv()
+			if recvErr == io.EOF {
+				break
+			}
+			if recvErr != nil {
+				return recvErr
+			}
+			buffer = append(buffer, resp.FileContent...)
+			for len(buffer) >= types.NeedleMapEntrySize {
+				key, offset, size := idx.IdxFileEntry(buffer[:types.NeedleMapEntrySize])
+				if offset.IsZero() || size == types.TombstoneFileSize {
+					delete(needles, key)
+				} else {
+					needles[key] = size
+				}
+				buffer = buffer[types.NeedleMapEntrySize:]
+			}
+		}
+		if len(buffer) != 0 {
+			return fmt.Errorf("%d trailing bytes", len(buffer))
+		}
+		return nil
+	})
+
+	return
+}
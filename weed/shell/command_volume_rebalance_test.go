@@ -0,0 +1,58 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+)
+
+func TestPlanVolumeRebalance(t *testing.T) {
+
+	volume := func(id uint32, replicaPlacement uint32) *master_pb.VolumeInformationMessage {
+		return &master_pb.VolumeInformationMessage{Id: id, Size: 100, ReplicaPlacement: replicaPlacement}
+	}
+	dataNode := func(id string, volumes ...*master_pb.VolumeInformationMessage) *master_pb.DataNodeInfo {
+		return &master_pb.DataNodeInfo{Id: id, MaxVolumeCount: 10, FreeVolumeCount: uint64(10 - len(volumes)), VolumeInfos: volumes}
+	}
+	topologyInfo := func() *master_pb.TopologyInfo {
+		return &master_pb.TopologyInfo{
+			DataCenterInfos: []*master_pb.DataCenterInfo{{
+				Id: "dc1",
+				RackInfos: []*master_pb.RackInfo{
+					{Id: "r1", DataNodeInfos: []*master_pb.DataNodeInfo{
+						dataNode("dn1", volume(1, 0), volume(2, 0), volume(3, 0), volume(4, 0), volume(5, 10)),
+						dataNode("dn2"),
+					}},
+					{Id: "r2", DataNodeInfos: []*master_pb.DataNodeInfo{
+						dataNode("dn3", volume(5, 10)),
+					}},
+				},
+			}},
+		}
+	}
+
+	moves := PlanVolumeRebalance(topologyInfo(), 1000, "", "", 0)
+	usage := map[string]uint64{"dn1": 500, "dn2": 0, "dn3": 100}
+	for _, move := range moves {
+		if move.Source != "dn1" {
+			t.Errorf("unexpected move %s", move)
+		}
+		if move.VolumeId == 5 && move.Target == "dn3" {
+			t.Errorf("move to the other replica %s", move)
+		}
+		usage[move.Source] -= move.Size
+		usage[move.Target] += move.Size
+	}
+	for node, bytes := range usage {
+		if bytes != 200 {
+			t.Errorf("%s has %d bytes after %d moves", node, bytes, len(moves))
+		}
+	}
+
+	if moves = PlanVolumeRebalance(topologyInfo(), 1000, "", "", 1); len(moves) != 1 || moves[0].Target != "dn2" {
+		t.Errorf("expected 1 move to dn2: %v", moves)
+	}
+	if moves = PlanVolumeRebalance(topologyInfo(), 1000, "other", "", 0); len(moves) != 0 {
+		t.Errorf("unexpected moves of other collections: %v", moves)
+	}
+}
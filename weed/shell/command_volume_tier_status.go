@@ -0,0 +1,90 @@
+package shell
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+)
+
+func init() {
+	Commands = append(Commands, &commandVolumeTierStatus{})
+}
+
+type commandVolumeTierStatus struct {
+}
+
+func (c *commandVolumeTierStatus) Name() string {
+	return "volume.tier.status"
+}
+
+func (c *commandVolumeTierStatus) Help() string {
+	return `show where the dat files of the volumes are stored
+
+	volume.tier.status [-collection=""] [-volumeId=<volume_id>] [-remoteOnly]
+
+	For each volume replica, this command shows the remote tier and the key of its dat file,
+	or "local" if the dat file is on the volume server disk.
+
+`
+}
+
+func (c *commandVolumeTierStatus) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	tierCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	volumeId := tierCommand.Uint("volumeId", 0, "the volume id")
+	collection := tierCommand.String("collection", "", "the collection name, all collections if empty")
+	remoteOnly := tierCommand.Bool("remoteOnly", false, "only show the volumes tiered to a remote storage")
+	if err = tierCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	topologyInfo, err := collectTopologyInfo(commandEnv)
+	if err != nil {
+		return err
+	}
+
+	type volumeReplica struct {
+		server string
+		volume *master_pb.VolumeInformationMessage
+	}
+	var replicas []volumeReplica
+	eachDataNode(topologyInfo, func(dc string, rack RackId, dn *master_pb.DataNodeInfo) {
+		for _, v := range dn.VolumeInfos {
+			if *volumeId != 0 && v.Id != uint32(*volumeId) {
+				continue
+			}
+			if *collection != "" && v.Collection != *collection {
+				continue
+			}
+			if *remoteOnly && v.RemoteStorageName == "" {
+				continue
+			}
+			replicas = append(replicas, volumeReplica{server: dn.Id, volume: v})
+		}
+	})
+
+	sort.Slice(replicas, func(i, j int) bool {
+		if replicas[i].volume.Id != replicas[j].volume.Id {
+			return replicas[i].volume.Id < replicas[j].volume.Id
+		}
+		return replicas[i].server < replicas[j].server
+	})
+
+	remoteCount := 0
+	for _, replica := range replicas {
+		v := replica.volume
+		tier := "local"
+		if v.RemoteStorageName != "" {
+			tier = fmt.Sprintf("%s key:%s", v.RemoteStorageName, v.RemoteStorageKey)
+			remoteCount++
+		}
+		fmt.Fprintf(writer, "volume %d collection:%q server:%s size:%d readonly:%v %s\n",
+			v.Id, v.Collection, replica.server, v.Size, v.ReadOnly, tier)
+	}
+	fmt.Fprintf(writer, "total %d volume replicas, %d on remote tiers\n", len(replicas), remoteCount)
+
+	return nil
+}
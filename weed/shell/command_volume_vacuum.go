@@ -0,0 +1,102 @@
+package shell
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+)
+
+func init() {
+	Commands = append(Commands, &commandVolumeVacuum{})
+}
+
+type commandVolumeVacuum struct {
+}
+
+func (c *commandVolumeVacuum) Name() string {
+	return "volume.vacuum"
+}
+
+func (c *commandVolumeVacuum) Help() string {
+	return `compact the volumes with deleted files, reclaiming their disk space
+
+	volume.vacuum [-garbageThreshold=0.3] [-collection=<collection name>] [-volumeId=<volume id>] [-dryRun]
+
+	The master vacuums the volumes with a garbage ratio above -garbageThreshold, or if not set,
+	above the threshold of their collection in master.vacuum.collection_garbage_thresholds of master.toml,
+	falling back to the master -garbageThreshold.
+	Without -collection, the volumes of all the collections are checked. The empty collection is -collection="".
+	With -dryRun, the volumes are only checked, showing the bytes a vacuum would reclaim.
+
+`
+}
+
+func (c *commandVolumeVacuum) Do(args []string, commandEnv *CommandEnv, writer io.Writer) (err error) {
+
+	vacuumCommand := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	garbageThreshold := vacuumCommand.Float64("garbageThreshold", 0, "vacuum the volumes with a garbage ratio above this, 0 for the threshold of each collection")
+	collection := vacuumCommand.String("collection", "", "only the volumes of this collection")
+	volumeId := vacuumCommand.Uint("volumeId", 0, "only this volume")
+	dryRun := vacuumCommand.Bool("dryRun", false, "only show the volumes to vacuum and their garbage")
+	if err = vacuumCommand.Parse(args); err != nil {
+		return nil
+	}
+
+	if !*dryRun {
+		if err = commandEnv.confirmIsLocked(); err != nil {
+			return
+		}
+	}
+
+	request := &master_pb.VacuumVolumeRequest{
+		GarbageThreshold: *garbageThreshold,
+		VolumeId:         uint32(*volumeId),
+		DryRun:           *dryRun,
+	}
+	vacuumCommand.Visit(func(f *flag.Flag) {
+		if f.Name == "collection" {
+			request.Collections = []string{*collection}
+		}
+	})
+
+	var resp *master_pb.VacuumVolumeResponse
+	err = commandEnv.MasterClient.WithClient(func(client master_pb.SeaweedClient) error {
+		resp, err = client.VacuumVolume(context.Background(), request)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var garbageBytes, reclaimedBytes uint64
+	vacuumed := 0
+	for _, volume := range resp.Volumes {
+		garbageBytes += volume.GarbageBytes
+		reclaimedBytes += volume.ReclaimedBytes
+		if volume.Vacuumed {
+			vacuumed++
+		}
+		fmt.Fprintf(writer, "volume %d collection:%q garbage:%.1f%% %s", volume.VolumeId, volume.Collection, volume.GarbageRatio*100, humanize.IBytes(volume.GarbageBytes))
+		switch {
+		case volume.Error != "":
+			fmt.Fprintf(writer, " error:%s\n", volume.Error)
+		case volume.Vacuumed:
+			fmt.Fprintf(writer, " reclaimed:%s\n", humanize.IBytes(volume.ReclaimedBytes))
+		default:
+			fmt.Fprintf(writer, "\n")
+		}
+	}
+
+	if *dryRun {
+		fmt.Fprintf(writer, "%d volumes to vacuum, %s reclaimable\n", len(resp.Volumes), humanize.IBytes(garbageBytes))
+	} else {
+		fmt.Fprintf(writer, "%d volumes vacuumed, %s reclaimed\n", vacuumed, humanize.IBytes(reclaimedBytes))
+	}
+
+	return nil
+}
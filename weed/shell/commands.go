@@ -78,6 +78,15 @@ func (ce *CommandEnv) confirmIsLocked() error {
 
 }
 
+// Lock takes the cluster-wide admin lock, the same as the lock command
+func (ce *CommandEnv) Lock() {
+	ce.locker.RequestLock()
+}
+
+func (ce *CommandEnv) Unlock() {
+	ce.locker.ReleaseLock()
+}
+
 func (ce *CommandEnv) checkDirectory(path string) error {
 
 	dir, name := util.FullPath(path).DirAndName()
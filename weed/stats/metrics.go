@@ -15,6 +15,32 @@ import (
 var (
 	FilerGather        = prometheus.NewRegistry()
 	VolumeServerGather = prometheus.NewRegistry()
+	S3Gather           = prometheus.NewRegistry()
+	MasterGather       = prometheus.NewRegistry()
+
+	MasterVolumeGrowthCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "master",
+			Name:      "volume_growth_total",
+			Help:      "Counter of volumes grown automatically, by the volume growth strategy. Its rate is the volumes created per minute.",
+		}, []string{"strategy"})
+
+	MasterVacuumReclaimedBytesCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "master",
+			Name:      "vacuum_reclaimed_bytes",
+			Help:      "Counter of bytes reclaimed by vacuuming the volumes of a collection, with all their replicas.",
+		}, []string{"collection"})
+
+	MasterVacuumRunReclaimedBytesGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "master",
+			Name:      "vacuum_last_run_reclaimed_bytes",
+			Help:      "Bytes reclaimed by the last vacuum run.",
+		})
 
 	FilerRequestCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -50,6 +76,22 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 24),
 		}, []string{"store", "type"})
 
+	FilerTtlSweepCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "filer",
+			Name:      "ttl_sweep_total",
+			Help:      "Counter of expired entries swept, and of their bytes reclaimed.",
+		}, []string{"type"})
+
+	FilerTtlSweepGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "filer",
+			Name:      "ttl_sweep_last_run",
+			Help:      "Expired entries swept, and their bytes reclaimed, in the last run.",
+		}, []string{"type"})
+
 	VolumeServerRequestCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "SeaweedFS",
@@ -90,14 +132,105 @@ var (
 			Name:      "total_disk_size",
 			Help:      "Actual disk size used by volumes.",
 		}, []string{"collection", "type"})
+
+	VolumeServerScrubCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "volumeServer",
+			Name:      "scrub_total",
+			Help:      "Counter of needles and bytes scrubbed, and of corrupted needles found, repaired, or unrepairable.",
+		}, []string{"type"})
+
+	VolumeServerScrubProgressGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "volumeServer",
+			Name:      "scrub_progress",
+			Help:      "Progress of the current scrub round, from 0 to 1.",
+		})
+
+	VolumeServerScrubUnrepairableGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "volumeServer",
+			Name:      "scrub_unrepairable_needles",
+			Help:      "Corrupted needles without a good replica, found in the last scrub of each volume.",
+		})
+
+	S3RequestHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "s3",
+			Name:      "request_seconds",
+			Help:      "Bucketed histogram of s3 request processing time.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 24),
+		}, []string{"action", "status"})
+
+	S3RequestSizeHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "s3",
+			Name:      "request_bytes",
+			Help:      "Bucketed histogram of s3 request body size.",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 12),
+		}, []string{"action", "status"})
+
+	S3ResponseSizeHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "s3",
+			Name:      "response_bytes",
+			Help:      "Bucketed histogram of s3 response body size.",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 12),
+		}, []string{"action", "status"})
+
+	S3LifecycleCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "s3",
+			Name:      "lifecycle_total",
+			Help:      "Counter of objects expired and multipart uploads aborted by lifecycle rules.",
+		}, []string{"bucket", "type"})
+
+	S3UploadCleanupCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "s3",
+			Name:      "upload_cleanup_total",
+			Help:      "Counter of incomplete multipart uploads aborted, and the bytes of their parts reclaimed.",
+		}, []string{"type"})
+
+	S3AccessLogCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "s3",
+			Name:      "access_log_total",
+			Help:      "Counter of access log records written into the target buckets, or dropped.",
+		}, []string{"bucket", "type"})
+
+	AuditLogCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "SeaweedFS",
+			Subsystem: "audit",
+			Name:      "log_total",
+			Help:      "Counter of audit log records written into the audit log sink, or dropped.",
+		}, []string{"service", "type"})
 )
 
 func init() {
 
+	MasterGather.MustRegister(MasterVolumeGrowthCounter)
+	MasterGather.MustRegister(MasterVacuumReclaimedBytesCounter)
+	MasterGather.MustRegister(MasterVacuumRunReclaimedBytesGauge)
+	MasterGather.MustRegister(prometheus.NewGoCollector())
+
 	FilerGather.MustRegister(FilerRequestCounter)
 	FilerGather.MustRegister(FilerRequestHistogram)
 	FilerGather.MustRegister(FilerStoreCounter)
 	FilerGather.MustRegister(FilerStoreHistogram)
+	FilerGather.MustRegister(FilerTtlSweepCounter)
+	FilerGather.MustRegister(FilerTtlSweepGauge)
+	FilerGather.MustRegister(AuditLogCounter)
 	FilerGather.MustRegister(prometheus.NewGoCollector())
 
 	VolumeServerGather.MustRegister(VolumeServerRequestCounter)
@@ -105,6 +238,18 @@ func init() {
 	VolumeServerGather.MustRegister(VolumeServerVolumeCounter)
 	VolumeServerGather.MustRegister(VolumeServerMaxVolumeCounter)
 	VolumeServerGather.MustRegister(VolumeServerDiskSizeGauge)
+	VolumeServerGather.MustRegister(VolumeServerScrubCounter)
+	VolumeServerGather.MustRegister(VolumeServerScrubProgressGauge)
+	VolumeServerGather.MustRegister(VolumeServerScrubUnrepairableGauge)
+
+	S3Gather.MustRegister(S3RequestHistogram)
+	S3Gather.MustRegister(S3RequestSizeHistogram)
+	S3Gather.MustRegister(S3ResponseSizeHistogram)
+	S3Gather.MustRegister(S3LifecycleCounter)
+	S3Gather.MustRegister(S3UploadCleanupCounter)
+	S3Gather.MustRegister(S3AccessLogCounter)
+	S3Gather.MustRegister(AuditLogCounter)
+	S3Gather.MustRegister(prometheus.NewGoCollector())
 
 }
 
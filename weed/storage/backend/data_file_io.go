@@ -0,0 +1,51 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// DataFileIO is how a volume server reads and writes the .dat files of its local volumes.
+//
+//   - buffered: the needles are read and written with pread and pwrite through the OS page cache.
+//   - mmap: the needles are read from a shared memory mapping of the file, saving a system call and a copy
+//     into the kernel for each read, and written with pwrite. The mapping is not safe on some network filesystems,
+//     where the file can change or shrink behind the volume server, and a read of a page gone fails with SIGBUS.
+//
+// Both implement BackendStorageFile, so the needle reads and writes do not depend on the choice.
+type DataFileIO string
+
+const (
+	DataFileIOBuffered DataFileIO = "buffered"
+	DataFileIOMmap     DataFileIO = "mmap"
+)
+
+// the same for all the volumes of a volume server, set before loading the volumes
+var dataFileIO = DataFileIOBuffered
+
+func SetDataFileIO(mode string) error {
+	switch DataFileIO(mode) {
+	case "", DataFileIOBuffered:
+		dataFileIO = DataFileIOBuffered
+	case DataFileIOMmap:
+		dataFileIO = DataFileIOMmap
+	default:
+		return fmt.Errorf("unknown data file io %q, expecting buffered or mmap", mode)
+	}
+	return nil
+}
+
+// OpenDataFile returns the volume data file with the io of the volume server,
+// falling back to the buffered io if the file can not be memory mapped
+func OpenDataFile(f *os.File) BackendStorageFile {
+	if dataFileIO == DataFileIOMmap {
+		mmapFile, err := NewMmapFile(f)
+		if err == nil {
+			return mmapFile
+		}
+		glog.Warningf("memory map %s: %v, using buffered io", f.Name(), err)
+	}
+	return NewDiskFile(f)
+}
@@ -0,0 +1,91 @@
+package backend
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestSetDataFileIO(t *testing.T) {
+	defer SetDataFileIO("")
+	if err := SetDataFileIO("mmap"); err != nil || dataFileIO != DataFileIOMmap {
+		t.Errorf("mmap: %v", err)
+	}
+	if err := SetDataFileIO("directio"); err == nil {
+		t.Errorf("unknown data file io")
+	}
+	if err := SetDataFileIO(""); err != nil || dataFileIO != DataFileIOBuffered {
+		t.Errorf("default: %v", err)
+	}
+}
+
+const (
+	benchmarkFileSize = 64 << 20
+	benchmarkReadSize = 4 << 10
+)
+
+// BenchmarkDataFileIO compares reading needles with the buffered and the memory mapped data files
+func BenchmarkDataFileIO(b *testing.B) {
+	f, err := ioutil.TempFile("", "datafileio")
+	if err != nil {
+		b.Fatalf("temp file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	data := make([]byte, benchmarkFileSize)
+	rand.Read(data)
+	if _, err = f.Write(data); err != nil {
+		b.Fatalf("write: %v", err)
+	}
+
+	files := []struct {
+		name string
+		open func() (BackendStorageFile, error)
+	}{
+		{"buffered", func() (BackendStorageFile, error) { return NewDiskFile(f), nil }},
+		{"mmap", func() (BackendStorageFile, error) { return NewMmapFile(f) }},
+	}
+	for _, file := range files {
+		datFile, err := file.open()
+		if err != nil {
+			b.Logf("%s: %v", file.name, err)
+			continue
+		}
+		b.Run(file.name+"/sequential", func(b *testing.B) {
+			p := make([]byte, benchmarkReadSize)
+			b.SetBytes(benchmarkReadSize)
+			for i := 0; i < b.N; i++ {
+				off := int64(i) * benchmarkReadSize % benchmarkFileSize
+				if _, err := datFile.ReadAt(p, off); err != nil {
+					b.Fatalf("read %d: %v", off, err)
+				}
+			}
+		})
+		b.Run(file.name+"/random", func(b *testing.B) {
+			p := make([]byte, benchmarkReadSize)
+			r := rand.New(rand.NewSource(1))
+			b.SetBytes(benchmarkReadSize)
+			for i := 0; i < b.N; i++ {
+				off := r.Int63n(benchmarkFileSize - benchmarkReadSize)
+				if _, err := datFile.ReadAt(p, off); err != nil {
+					b.Fatalf("read %d: %v", off, err)
+				}
+			}
+		})
+		b.Run(file.name+"/random-parallel", func(b *testing.B) {
+			b.SetBytes(benchmarkReadSize)
+			b.RunParallel(func(pb *testing.PB) {
+				p := make([]byte, benchmarkReadSize)
+				r := rand.New(rand.NewSource(rand.Int63()))
+				for pb.Next() {
+					off := r.Int63n(benchmarkFileSize - benchmarkReadSize)
+					if _, err := datFile.ReadAt(p, off); err != nil {
+						b.Errorf("read %d: %v", off, err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// +build linux darwin freebsd
+
+package backend
+
+import (
+	"io"
+	"os"
+	"sync"
+	"syscall"
+)
+
+var (
+	_ BackendStorageFile = &MmapFile{}
+)
+
+// the mapping grows by this step, reserving the address space only
+var mmapGrowStep int64 = 1 << 30
+
+// MmapFile reads from a read only shared mapping of the file, which sees the writes to the file right away.
+// The mapping may extend past the end of the file, the reads stop at the file size.
+type MmapFile struct {
+	*DiskFile
+	mapLock sync.RWMutex
+	data    []byte
+	size    int64
+}
+
+func NewMmapFile(f *os.File) (*MmapFile, error) {
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	m := &MmapFile{
+		DiskFile: NewDiskFile(f),
+		size:     stat.Size(),
+	}
+	if m.size > 0 {
+		if err = m.remap(m.size); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *MmapFile) ReadAt(p []byte, off int64) (n int, err error) {
+	m.mapLock.RLock()
+	if off+int64(len(p)) > int64(len(m.data)) && off < m.size {
+		m.mapLock.RUnlock()
+		m.mapLock.Lock()
+		err = m.remap(off + int64(len(p)))
+		m.mapLock.Unlock()
+		if err != nil {
+			return 0, err
+		}
+		m.mapLock.RLock()
+	}
+	defer m.mapLock.RUnlock()
+
+	if off >= m.size {
+		return 0, io.EOF
+	}
+	end := off + int64(len(p))
+	if end > m.size {
+		end = m.size
+	}
+	n = copy(p, m.data[off:end])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (m *MmapFile) WriteAt(p []byte, off int64) (n int, err error) {
+	n, err = m.DiskFile.WriteAt(p, off)
+	if n > 0 {
+		m.mapLock.Lock()
+		if off+int64(n) > m.size {
+			m.size = off + int64(n)
+		}
+		m.mapLock.Unlock()
+	}
+	return
+}
+
+func (m *MmapFile) Truncate(off int64) error {
+	// no read may touch the pages past the new end of the file
+	m.mapLock.Lock()
+	defer m.mapLock.Unlock()
+	if err := m.DiskFile.Truncate(off); err != nil {
+		return err
+	}
+	m.size = off
+	return nil
+}
+
+func (m *MmapFile) Close() error {
+	m.mapLock.Lock()
+	if m.data != nil {
+		syscall.Munmap(m.data)
+		m.data = nil
+	}
+	m.mapLock.Unlock()
+	return m.DiskFile.Close()
+}
+
+// remap maps at least minSize bytes, under the write lock
+func (m *MmapFile) remap(minSize int64) error {
+	if int64(len(m.data)) >= minSize {
+		return nil
+	}
+	length := (minSize + mmapGrowStep - 1) / mmapGrowStep * mmapGrowStep
+	data, err := syscall.Mmap(int(m.File.Fd()), 0, int(length), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+	if m.data != nil {
+		syscall.Munmap(m.data)
+	}
+	m.data = data
+	return nil
+}
@@ -0,0 +1,17 @@
+// +build !linux,!darwin,!freebsd
+
+package backend
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+type MmapFile struct {
+	*DiskFile
+}
+
+func NewMmapFile(f *os.File) (*MmapFile, error) {
+	return nil, fmt.Errorf("memory mapped data files are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,60 @@
+// +build linux darwin freebsd
+
+package backend
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestMmapFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "mmap")
+	if err != nil {
+		t.Fatalf("temp file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.Write([]byte("hello")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	defer func(step int64) { mmapGrowStep = step }(mmapGrowStep)
+	mmapGrowStep = 1 << 16
+	m, err := NewMmapFile(f)
+	if err != nil {
+		t.Skipf("memory map: %v", err)
+	}
+	defer m.Close()
+
+	p := make([]byte, 5)
+	if n, err := m.ReadAt(p, 0); n != 5 || err != nil || string(p) != "hello" {
+		t.Errorf("read %q %d %v", p[:n], n, err)
+	}
+
+	// the writes are seen by the reads right away, also past the initial mapping
+	big := bytes.Repeat([]byte("x"), 3*int(mmapGrowStep))
+	if _, err = m.WriteAt(big, 5); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	p = make([]byte, 10)
+	if n, err := m.ReadAt(p, int64(len(big))-5); n != 10 || err != nil || string(p) != "xxxxxxxxxx" {
+		t.Errorf("read the end %q %d %v", p[:n], n, err)
+	}
+	if n, err := m.ReadAt(p, int64(len(big))+1); n != 4 || err != io.EOF {
+		t.Errorf("read past the end %d %v", n, err)
+	}
+	if size, _, err := m.GetStat(); size != int64(len(big))+5 || err != nil {
+		t.Errorf("stat %d %v", size, err)
+	}
+
+	if err = m.Truncate(3); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	if n, err := m.ReadAt(p, 0); n != 3 || err != io.EOF || string(p[:n]) != "hel" {
+		t.Errorf("read the truncated %q %d %v", p[:n], n, err)
+	}
+	if n, err := m.ReadAt(p, 5); n != 0 || err != io.EOF {
+		t.Errorf("read past the truncated end %d %v", n, err)
+	}
+}
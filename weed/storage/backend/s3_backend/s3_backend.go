@@ -4,18 +4,23 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/google/uuid"
+	"github.com/karlseguin/ccache"
 
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/pb/volume_server_pb"
 	"github.com/chrislusf/seaweedfs/weed/storage/backend"
 )
 
+// only the small reads, usually one needle each, are cached
+const readCacheSizeLimit = 1024 * 1024
+
 func init() {
 	backend.BackendStorageFactories["s3"] = &S3BackendFactory{}
 }
@@ -37,6 +42,8 @@ type S3BackendStorage struct {
 	region                string
 	bucket                string
 	endpoint              string
+	readCacheEntries      int64
+	readCache             *ccache.Cache
 	conn                  s3iface.S3API
 }
 
@@ -48,6 +55,14 @@ func newS3BackendStorage(configuration backend.StringProperties, configPrefix st
 	s.region = configuration.GetString(configPrefix + "region")
 	s.bucket = configuration.GetString(configPrefix + "bucket")
 	s.endpoint = configuration.GetString(configPrefix + "endpoint")
+	if readCacheEntries := configuration.GetString(configPrefix + "read_cache_entries"); readCacheEntries != "" {
+		if s.readCacheEntries, err = strconv.ParseInt(readCacheEntries, 10, 64); err != nil {
+			return nil, fmt.Errorf("parse read_cache_entries %s: %v", readCacheEntries, err)
+		}
+	}
+	if s.readCacheEntries > 0 {
+		s.readCache = ccache.New(ccache.Configure().MaxSize(s.readCacheEntries).ItemsToPrune(uint32(s.readCacheEntries>>3 + 1)))
+	}
 
 	s.conn, err = createSession(s.aws_access_key_id, s.aws_secret_access_key, s.region, s.endpoint)
 
@@ -62,6 +77,7 @@ func (s *S3BackendStorage) ToProperties() map[string]string {
 	m["region"] = s.region
 	m["bucket"] = s.bucket
 	m["endpoint"] = s.endpoint
+	m["read_cache_entries"] = strconv.FormatInt(s.readCacheEntries, 10)
 	return m
 }
 
@@ -80,12 +96,12 @@ func (s *S3BackendStorage) NewStorageFile(key string, tierInfo *volume_server_pb
 }
 
 func (s *S3BackendStorage) CopyFile(f *os.File, attributes map[string]string, fn func(progressed int64, percentage float32) error) (key string, size int64, err error) {
-	randomUuid, _ := uuid.NewRandom()
-	key = randomUuid.String()
+	glog.V(1).Infof("copying dat file of %s to remote s3.%s", f.Name(), s.id)
 
-	glog.V(1).Infof("copying dat file of %s to remote s3.%s as %s", f.Name(), s.id, key)
-
-	size, err = uploadToS3(s.conn, f.Name(), s.bucket, key, attributes, fn)
+	key, size, err = uploadToS3(s.conn, f.Name(), s.bucket, func() string {
+		randomUuid, _ := uuid.NewRandom()
+		return randomUuid.String()
+	}, attributes, fn)
 
 	return
 }
@@ -116,6 +132,26 @@ type S3BackendStorageFile struct {
 
 func (s3backendStorageFile S3BackendStorageFile) ReadAt(p []byte, off int64) (n int, err error) {
 
+	readCache := s3backendStorageFile.backendStorage.readCache
+	if readCache == nil || len(p) >= readCacheSizeLimit {
+		return s3backendStorageFile.readRemote(p, off)
+	}
+
+	cacheKey := fmt.Sprintf("%s@%d+%d", s3backendStorageFile.key, off, len(p))
+	if item := readCache.Get(cacheKey); item != nil && !item.Expired() {
+		return copy(p, item.Value().([]byte)), nil
+	}
+	if n, err = s3backendStorageFile.readRemote(p, off); err != nil {
+		return
+	}
+	data := make([]byte, n)
+	copy(data, p[:n])
+	readCache.Set(cacheKey, data, time.Hour)
+	return
+}
+
+func (s3backendStorageFile S3BackendStorageFile) readRemote(p []byte, off int64) (n int, err error) {
+
 	bytesRange := fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1)
 
 	// glog.V(0).Infof("read %s %s", s3backendStorageFile.key, bytesRange)
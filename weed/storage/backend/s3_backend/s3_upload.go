@@ -1,31 +1,71 @@
 package s3_backend
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
-	"sync/atomic"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 
 	"github.com/chrislusf/seaweedfs/weed/glog"
 )
 
-func uploadToS3(sess s3iface.S3API, filename string, destBucket string, destKey string,
+const uploadConcurrency = 5
+
+// s3UploadState is saved next to the uploaded file, so an interrupted upload resumes with the parts already uploaded
+type s3UploadState struct {
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+	UploadId string `json:"uploadId"`
+	PartSize int64  `json:"partSize"`
+	FileSize int64  `json:"fileSize"`
+	ModTime  int64  `json:"modTime"`
+}
+
+func uploadStateFileName(filename string) string {
+	return filename + ".upload"
+}
+
+func loadUploadState(filename string) (state *s3UploadState) {
+	data, err := ioutil.ReadFile(uploadStateFileName(filename))
+	if err != nil {
+		return nil
+	}
+	state = &s3UploadState{}
+	if err = json.Unmarshal(data, state); err != nil {
+		glog.Warningf("parse %s: %v", uploadStateFileName(filename), err)
+		return nil
+	}
+	return state
+}
+
+func saveUploadState(filename string, state *s3UploadState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(uploadStateFileName(filename), data, 0644)
+}
+
+func uploadToS3(sess s3iface.S3API, filename string, destBucket string, newKey func() string,
 	attributes map[string]string,
-	fn func(progressed int64, percentage float32) error) (fileSize int64, err error) {
+	fn func(progressed int64, percentage float32) error) (key string, fileSize int64, err error) {
 
 	//open the file
 	f, err := os.Open(filename)
 	if err != nil {
-		return 0, fmt.Errorf("failed to open file %q, %v", filename, err)
+		return "", 0, fmt.Errorf("failed to open file %q, %v", filename, err)
 	}
 	defer f.Close()
 
 	info, err := f.Stat()
 	if err != nil {
-		return 0, fmt.Errorf("failed to stat file %q, %v", filename, err)
+		return "", 0, fmt.Errorf("failed to stat file %q, %v", filename, err)
 	}
 
 	fileSize = info.Size()
@@ -35,18 +75,49 @@ func uploadToS3(sess s3iface.S3API, filename string, destBucket string, destKey
 		partSize *= 4
 	}
 
-	// Create an uploader with the session and custom options
-	uploader := s3manager.NewUploaderWithClient(sess, func(u *s3manager.Uploader) {
-		u.PartSize = partSize
-		u.Concurrency = 5
-	})
+	// resume the previous upload of the same file
+	state := loadUploadState(filename)
+	if state != nil && (state.Bucket != destBucket || state.FileSize != fileSize || state.ModTime != info.ModTime().Unix()) {
+		glog.V(0).Infof("discard the previous upload of %s to %s/%s", filename, state.Bucket, state.Key)
+		abortUpload(sess, state)
+		state = nil
+	}
+	var uploadedParts map[int64]*s3.CompletedPart
+	if state != nil {
+		if uploadedParts, err = listUploadedParts(sess, state); err != nil {
+			glog.V(0).Infof("restart the upload of %s: %v", filename, err)
+			state = nil
+		} else {
+			glog.V(0).Infof("resume the upload of %s to %s/%s with %d uploaded parts", filename, state.Bucket, state.Key, len(uploadedParts))
+		}
+	}
+	if state == nil {
+		if state, err = createUpload(sess, destBucket, newKey(), attributes); err != nil {
+			return "", 0, fmt.Errorf("failed to upload file %s: %v", filename, err)
+		}
+		state.PartSize, state.FileSize, state.ModTime = partSize, fileSize, info.ModTime().Unix()
+		if err = saveUploadState(filename, state); err != nil {
+			return "", 0, fmt.Errorf("failed to save upload state of %s: %v", filename, err)
+		}
+		uploadedParts = make(map[int64]*s3.CompletedPart)
+	}
+
+	if err = uploadParts(sess, f, state, uploadedParts, fn); err != nil {
+		// the upload state is kept to resume later
+		return "", 0, fmt.Errorf("failed to upload file %s: %v", filename, err)
+	}
 
-	fileReader := &s3UploadProgressedReader{
-		fp:   f,
-		size: fileSize,
-		read: -fileSize,
-		fn:   fn,
+	if err = completeUpload(sess, state, uploadedParts); err != nil {
+		return "", 0, fmt.Errorf("failed to upload file %s: %v", filename, err)
 	}
+	os.Remove(uploadStateFileName(filename))
+
+	glog.V(1).Infof("file %s uploaded to %s/%s\n", filename, state.Bucket, state.Key)
+
+	return state.Key, fileSize, nil
+}
+
+func createUpload(sess s3iface.S3API, destBucket, destKey string, attributes map[string]string) (*s3UploadState, error) {
 
 	// process tagging
 	tags := ""
@@ -57,58 +128,139 @@ func uploadToS3(sess s3iface.S3API, filename string, destBucket string, destKey
 		tags = tags + k + "=" + v
 	}
 
-	// Upload the file to S3.
-	var result *s3manager.UploadOutput
-	result, err = uploader.Upload(&s3manager.UploadInput{
+	output, err := sess.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
 		Bucket:               aws.String(destBucket),
 		Key:                  aws.String(destKey),
-		Body:                 fileReader,
 		ACL:                  aws.String("private"),
 		ServerSideEncryption: aws.String("AES256"),
 		StorageClass:         aws.String("STANDARD_IA"),
 		Tagging:              aws.String(tags),
 	})
-
-	//in case it fails to upload
 	if err != nil {
-		return 0, fmt.Errorf("failed to upload file %s: %v", filename, err)
+		return nil, err
 	}
-	glog.V(1).Infof("file %s uploaded to %s\n", filename, result.Location)
 
-	return
+	return &s3UploadState{
+		Bucket:   destBucket,
+		Key:      destKey,
+		UploadId: *output.UploadId,
+	}, nil
 }
 
-// adapted from https://github.com/aws/aws-sdk-go/pull/1868
-type s3UploadProgressedReader struct {
-	fp   *os.File
-	size int64
-	read int64
-	fn   func(progressed int64, percentage float32) error
+func listUploadedParts(sess s3iface.S3API, state *s3UploadState) (parts map[int64]*s3.CompletedPart, err error) {
+	parts = make(map[int64]*s3.CompletedPart)
+	err = sess.ListPartsPages(&s3.ListPartsInput{
+		Bucket:   aws.String(state.Bucket),
+		Key:      aws.String(state.Key),
+		UploadId: aws.String(state.UploadId),
+	}, func(output *s3.ListPartsOutput, lastPage bool) bool {
+		for _, part := range output.Parts {
+			parts[*part.PartNumber] = &s3.CompletedPart{
+				ETag:       part.ETag,
+				PartNumber: part.PartNumber,
+			}
+		}
+		return true
+	})
+	return
 }
 
-func (r *s3UploadProgressedReader) Read(p []byte) (int, error) {
-	return r.fp.Read(p)
-}
+// uploadParts uploads the missing parts, a few at a time
+func uploadParts(sess s3iface.S3API, f *os.File, state *s3UploadState, uploadedParts map[int64]*s3.CompletedPart,
+	fn func(progressed int64, percentage float32) error) error {
 
-func (r *s3UploadProgressedReader) ReadAt(p []byte, off int64) (int, error) {
-	n, err := r.fp.ReadAt(p, off)
-	if err != nil {
-		return n, err
+	partCount := (state.FileSize + state.PartSize - 1) / state.PartSize
+	progressed := int64(len(uploadedParts)) * state.PartSize
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var uploadErr error
+	limit := make(chan struct{}, uploadConcurrency)
+
+	for partNumber := int64(1); partNumber <= partCount; partNumber++ {
+		if _, found := uploadedParts[partNumber]; found {
+			continue
+		}
+		lock.Lock()
+		failed := uploadErr != nil
+		lock.Unlock()
+		if failed {
+			break
+		}
+
+		limit <- struct{}{}
+		wg.Add(1)
+		go func(partNumber int64) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+
+			offset := (partNumber - 1) * state.PartSize
+			size := state.PartSize
+			if offset+size > state.FileSize {
+				size = state.FileSize - offset
+			}
+			output, err := sess.UploadPart(&s3.UploadPartInput{
+				Bucket:     aws.String(state.Bucket),
+				Key:        aws.String(state.Key),
+				UploadId:   aws.String(state.UploadId),
+				PartNumber: aws.Int64(partNumber),
+				Body:       io.NewSectionReader(f, offset, size),
+			})
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				if uploadErr == nil {
+					uploadErr = fmt.Errorf("upload part %d: %v", partNumber, err)
+				}
+				return
+			}
+			uploadedParts[partNumber] = &s3.CompletedPart{
+				ETag:       output.ETag,
+				PartNumber: aws.Int64(partNumber),
+			}
+			progressed += size
+			if fn != nil && uploadErr == nil {
+				if err := fn(progressed, float32(progressed*100)/float32(state.FileSize)); err != nil {
+					uploadErr = err
+				}
+			}
+		}(partNumber)
 	}
 
-	// Got the length have read( or means has uploaded), and you can construct your message
-	atomic.AddInt64(&r.read, int64(n))
+	wg.Wait()
+	return uploadErr
+}
 
-	if r.fn != nil {
-		read := r.read
-		if err := r.fn(read, float32(read*100)/float32(r.size)); err != nil {
-			return n, err
+func completeUpload(sess s3iface.S3API, state *s3UploadState, uploadedParts map[int64]*s3.CompletedPart) error {
+	partCount := (state.FileSize + state.PartSize - 1) / state.PartSize
+	var parts []*s3.CompletedPart
+	for partNumber := int64(1); partNumber <= partCount; partNumber++ {
+		part, found := uploadedParts[partNumber]
+		if !found {
+			return fmt.Errorf("part %d is not uploaded", partNumber)
 		}
+		parts = append(parts, part)
 	}
 
-	return n, err
+	_, err := sess.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(state.Bucket),
+		Key:             aws.String(state.Key),
+		UploadId:        aws.String(state.UploadId),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
 }
 
-func (r *s3UploadProgressedReader) Seek(offset int64, whence int) (int64, error) {
-	return r.fp.Seek(offset, whence)
+func abortUpload(sess s3iface.S3API, state *s3UploadState) {
+	_, err := sess.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(state.Bucket),
+		Key:      aws.String(state.Key),
+		UploadId: aws.String(state.UploadId),
+	})
+	if err != nil {
+		glog.V(1).Infof("abort upload %s/%s: %v", state.Bucket, state.Key, err)
+	}
 }
@@ -0,0 +1,107 @@
+package s3_backend
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// fakeMultipartS3 keeps one multipart upload in memory, and fails the upload of the part numbered failPart
+type fakeMultipartS3 struct {
+	s3iface.S3API
+	sync.Mutex
+	parts     map[int64][]byte
+	uploads   int
+	failPart  int64
+	completed []byte
+}
+
+func (s *fakeMultipartS3) CreateMultipartUpload(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+	s.uploads++
+	s.parts = make(map[int64][]byte)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(fmt.Sprintf("upload%d", s.uploads))}, nil
+}
+
+func (s *fakeMultipartS3) UploadPart(input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+	if *input.PartNumber == s.failPart {
+		return nil, fmt.Errorf("connection reset")
+	}
+	data, _ := ioutil.ReadAll(input.Body)
+	s.Lock()
+	s.parts[*input.PartNumber] = data
+	s.Unlock()
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag%d", *input.PartNumber))}, nil
+}
+
+func (s *fakeMultipartS3) ListPartsPages(input *s3.ListPartsInput, fn func(*s3.ListPartsOutput, bool) bool) error {
+	output := &s3.ListPartsOutput{}
+	for partNumber := range s.parts {
+		output.Parts = append(output.Parts, &s3.Part{
+			PartNumber: aws.Int64(partNumber),
+			ETag:       aws.String(fmt.Sprintf("etag%d", partNumber)),
+		})
+	}
+	fn(output, true)
+	return nil
+}
+
+func (s *fakeMultipartS3) CompleteMultipartUpload(input *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+	var buf bytes.Buffer
+	for _, part := range input.MultipartUpload.Parts {
+		buf.Write(s.parts[*part.PartNumber])
+	}
+	s.completed = buf.Bytes()
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func TestResumeUpload(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "seaweedfs_s3_upload_test")
+	defer os.RemoveAll(dir)
+
+	// 3 parts of 64MB, the last one partial
+	content := make([]byte, 2*64*1024*1024+1024)
+	for i := range content {
+		content[i] = byte(i)
+	}
+	fileName := filepath.Join(dir, "1.dat")
+	if err := ioutil.WriteFile(fileName, content, 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	sess := &fakeMultipartS3{failPart: 2}
+	keys := 0
+	newKey := func() string {
+		keys++
+		return fmt.Sprintf("key%d", keys)
+	}
+
+	if _, _, err := uploadToS3(sess, fileName, "bucket", newKey, nil, nil); err == nil {
+		t.Fatalf("upload should fail")
+	}
+	if _, err := os.Stat(uploadStateFileName(fileName)); err != nil {
+		t.Fatalf("upload state not kept: %v", err)
+	}
+
+	sess.failPart = 0
+	key, size, err := uploadToS3(sess, fileName, "bucket", newKey, nil, nil)
+	if err != nil {
+		t.Fatalf("resume upload: %v", err)
+	}
+	if key != "key1" || size != int64(len(content)) || sess.uploads != 1 {
+		t.Errorf("unexpected key %s size %d uploads %d", key, size, sess.uploads)
+	}
+	if !bytes.Equal(sess.completed, content) {
+		t.Errorf("uploaded content differs")
+	}
+	if _, err := os.Stat(uploadStateFileName(fileName)); !os.IsNotExist(err) {
+		t.Errorf("upload state not removed: %v", err)
+	}
+}
@@ -121,6 +121,17 @@ func (l *DiskLocation) loadExistingVolumes(needleMapKind NeedleMapType) {
 
 }
 
+func (l *DiskLocation) findAppendOnlyVolume(collection string) *Volume {
+	l.volumesLock.RLock()
+	defer l.volumesLock.RUnlock()
+	for _, v := range l.volumes {
+		if v.Collection == collection && v.IsAppendOnly() {
+			return v
+		}
+	}
+	return nil
+}
+
 func (l *DiskLocation) DeleteCollectionFromDiskLocation(collection string) (e error) {
 
 	l.volumesLock.Lock()
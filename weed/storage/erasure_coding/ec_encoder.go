@@ -1,6 +1,7 @@
 package erasure_coding
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -218,7 +219,7 @@ func encodeDatFile(remainingSize int64, err error, baseFileName string, bufferSi
 		processedSize += largeBlockSize * DataShardsCount
 	}
 	for remainingSize > 0 {
-		encodeData(file, enc, processedSize, smallBlockSize, buffers, outputs)
+		err = encodeData(file, enc, processedSize, smallBlockSize, buffers, outputs)
 		if err != nil {
 			return fmt.Errorf("failed to encode small chunk data: %v", err)
 		}
@@ -228,6 +229,87 @@ func encodeDatFile(remainingSize int64, err error, baseFileName string, bufferSi
 	return nil
 }
 
+// VerifyEcFiles checks the .ec00 ~ .ec13 files against the .dat file they are generated from
+func VerifyEcFiles(baseFileName string) error {
+	return verifyEcFiles(baseFileName, 256*1024, ErasureCodingLargeBlockSize, ErasureCodingSmallBlockSize)
+}
+
+func verifyEcFiles(baseFileName string, bufferSize int, largeBlockSize int64, smallBlockSize int64) error {
+	file, err := os.OpenFile(baseFileName+".dat", os.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open dat file: %v", err)
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat dat file: %v", err)
+	}
+
+	enc, err := reedsolomon.New(DataShardsCount, ParityShardsCount)
+	if err != nil {
+		return fmt.Errorf("failed to create encoder: %v", err)
+	}
+
+	inputs, err := openEcFiles(baseFileName, true)
+	defer closeEcFiles(inputs)
+	if err != nil {
+		return fmt.Errorf("failed to open ec files %s: %v", baseFileName, err)
+	}
+
+	buffers := make([][]byte, TotalShardsCount)
+	for i := range buffers {
+		buffers[i] = make([]byte, bufferSize)
+	}
+	datBuffer := make([]byte, bufferSize)
+
+	var processedSize, shardOffset int64
+	verifyRow := func(blockSize int64) error {
+		for b := int64(0); b < blockSize/int64(bufferSize); b++ {
+			for i := 0; i < TotalShardsCount; i++ {
+				if n, err := inputs[i].ReadAt(buffers[i], shardOffset); n != bufferSize {
+					return fmt.Errorf("read %s at %d: %v", inputs[i].Name(), shardOffset, err)
+				}
+			}
+			if ok, err := enc.Verify(buffers); err != nil || !ok {
+				return fmt.Errorf("parity mismatch at shard offset %d: %v", shardOffset, err)
+			}
+			for i := 0; i < DataShardsCount; i++ {
+				datOffset := processedSize + blockSize*int64(i) + b*int64(bufferSize)
+				n, err := file.ReadAt(datBuffer, datOffset)
+				if err != nil && err != io.EOF {
+					return fmt.Errorf("read dat file at %d: %v", datOffset, err)
+				}
+				for t := n; t < bufferSize; t++ {
+					datBuffer[t] = 0
+				}
+				if !bytes.Equal(datBuffer, buffers[i]) {
+					return fmt.Errorf("%s differs from dat file at offset %d", inputs[i].Name(), datOffset)
+				}
+			}
+			shardOffset += int64(bufferSize)
+		}
+		return nil
+	}
+
+	remainingSize := fi.Size()
+	for remainingSize > largeBlockSize*DataShardsCount {
+		if err = verifyRow(largeBlockSize); err != nil {
+			return err
+		}
+		remainingSize -= largeBlockSize * DataShardsCount
+		processedSize += largeBlockSize * DataShardsCount
+	}
+	for remainingSize > 0 {
+		if err = verifyRow(smallBlockSize); err != nil {
+			return err
+		}
+		remainingSize -= smallBlockSize * DataShardsCount
+		processedSize += smallBlockSize * DataShardsCount
+	}
+	return nil
+}
+
 func rebuildEcFiles(shardHasData []bool, inputFiles []*os.File, outputFiles []*os.File) error {
 
 	enc, err := reedsolomon.New(DataShardsCount, ParityShardsCount)
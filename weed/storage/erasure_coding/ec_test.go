@@ -41,6 +41,34 @@ func TestEncodingDecoding(t *testing.T) {
 
 }
 
+func TestVerifyEcFiles(t *testing.T) {
+	bufferSize := 50
+	baseFileName := "1"
+	defer removeGeneratedFiles(baseFileName)
+
+	if err := generateEcFiles(baseFileName, bufferSize, largeBlockSize, smallBlockSize); err != nil {
+		t.Fatalf("generateEcFiles: %v", err)
+	}
+	if err := verifyEcFiles(baseFileName, bufferSize, largeBlockSize, smallBlockSize); err != nil {
+		t.Fatalf("verifyEcFiles: %v", err)
+	}
+
+	// corrupt one byte of a data shard
+	shardFile, err := os.OpenFile(baseFileName+ToExt(3), os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("open shard: %v", err)
+	}
+	b := make([]byte, 1)
+	shardFile.ReadAt(b, 7)
+	b[0]++
+	shardFile.WriteAt(b, 7)
+	shardFile.Close()
+
+	if err := verifyEcFiles(baseFileName, bufferSize, largeBlockSize, smallBlockSize); err == nil {
+		t.Fatalf("corrupted shard not detected")
+	}
+}
+
 func validateFiles(baseFileName string) error {
 	nm, err := readNeedleMap(baseFileName)
 	defer nm.Close()
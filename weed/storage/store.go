@@ -16,6 +16,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
 	. "github.com/chrislusf/seaweedfs/weed/storage/types"
+	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
 const (
@@ -35,12 +36,16 @@ type Store struct {
 	Locations           []*DiskLocation
 	dataCenter          string //optional informaton, overwriting master setting if exists
 	rack                string //optional information, overwriting master setting if exists
+	diskType            DiskType
 	connected           bool
 	NeedleMapType       NeedleMapType
 	NewVolumesChan      chan master_pb.VolumeShortInformationMessage
 	DeletedVolumesChan  chan master_pb.VolumeShortInformationMessage
 	NewEcShardsChan     chan master_pb.VolumeEcShardInformationMessage
 	DeletedEcShardsChan chan master_pb.VolumeEcShardInformationMessage
+	readOnly            int32 // a readonly store serves reads, but accepts no writes or new volumes
+	fsyncPolicy         FsyncPolicy
+	fsyncStop           chan struct{}
 }
 
 func (s *Store) String() (str string) {
@@ -65,7 +70,7 @@ func NewStore(grpcDialOption grpc.DialOption, port int, ip, publicUrl string, di
 
 	return
 }
-func (s *Store) AddVolume(volumeId needle.VolumeId, collection string, needleMapKind NeedleMapType, replicaPlacement string, ttlString string, preallocate int64, MemoryMapMaxSizeMb uint32) error {
+func (s *Store) AddVolume(volumeId needle.VolumeId, collection string, needleMapKind NeedleMapType, replicaPlacement string, ttlString string, preallocate int64, MemoryMapMaxSizeMb uint32, appendOnly bool) error {
 	rt, e := super_block.NewReplicaPlacementFromString(replicaPlacement)
 	if e != nil {
 		return e
@@ -74,10 +79,15 @@ func (s *Store) AddVolume(volumeId needle.VolumeId, collection string, needleMap
 	if e != nil {
 		return e
 	}
-	e = s.addVolume(volumeId, collection, needleMapKind, rt, ttl, preallocate, MemoryMapMaxSizeMb)
+	e = s.addVolume(volumeId, collection, needleMapKind, rt, ttl, preallocate, MemoryMapMaxSizeMb, appendOnly)
 	return e
 }
 func (s *Store) DeleteCollection(collection string) (e error) {
+	for _, location := range s.Locations {
+		if v := location.findAppendOnlyVolume(collection); v != nil {
+			return &AppendOnlyError{VolumeId: v.Id, Op: "delete collection " + collection}
+		}
+	}
 	for _, location := range s.Locations {
 		e = location.DeleteCollectionFromDiskLocation(collection)
 		if e != nil {
@@ -110,14 +120,23 @@ func (s *Store) FindFreeLocation() (ret *DiskLocation) {
 	}
 	return ret
 }
-func (s *Store) addVolume(vid needle.VolumeId, collection string, needleMapKind NeedleMapType, replicaPlacement *super_block.ReplicaPlacement, ttl *needle.TTL, preallocate int64, memoryMapMaxSizeMb uint32) error {
+func (s *Store) addVolume(vid needle.VolumeId, collection string, needleMapKind NeedleMapType, replicaPlacement *super_block.ReplicaPlacement, ttl *needle.TTL, preallocate int64, memoryMapMaxSizeMb uint32, appendOnly bool) error {
 	if s.findVolume(vid) != nil {
 		return fmt.Errorf("Volume Id %d already exists!", vid)
 	}
+	if s.IsReadOnly() {
+		return fmt.Errorf("volume server %s:%d is read only", s.Ip, s.Port)
+	}
 	if location := s.FindFreeLocation(); location != nil {
-		glog.V(0).Infof("In dir %s adds volume:%v collection:%s replicaPlacement:%v ttl:%v",
-			location.Directory, vid, collection, replicaPlacement, ttl)
+		glog.V(0).Infof("In dir %s adds volume:%v collection:%s replicaPlacement:%v ttl:%v appendOnly:%v",
+			location.Directory, vid, collection, replicaPlacement, ttl, appendOnly)
 		if volume, err := NewVolume(location.Directory, collection, vid, needleMapKind, replicaPlacement, ttl, preallocate, memoryMapMaxSizeMb); err == nil {
+			if appendOnly {
+				if err = volume.MarkAppendOnly(); err != nil {
+					volume.Destroy()
+					return err
+				}
+			}
 			location.SetVolume(vid, volume)
 			glog.V(0).Infof("add volume %d", vid)
 			s.NewVolumesChan <- master_pb.VolumeShortInformationMessage{
@@ -126,6 +145,7 @@ func (s *Store) addVolume(vid needle.VolumeId, collection string, needleMapKind
 				ReplicaPlacement: uint32(replicaPlacement.Byte()),
 				Version:          uint32(volume.Version()),
 				Ttl:              ttl.ToUint32(),
+				AppendOnly:       appendOnly,
 			}
 			return nil
 		} else {
@@ -189,6 +209,9 @@ func (s *Store) SetDataCenter(dataCenter string) {
 func (s *Store) SetRack(rack string) {
 	s.rack = rack
 }
+func (s *Store) SetDiskType(diskType DiskType) {
+	s.diskType = diskType
+}
 
 func (s *Store) CollectHeartbeat() *master_pb.Heartbeat {
 	var volumeMessages []*master_pb.VolumeInformationMessage
@@ -204,7 +227,11 @@ func (s *Store) CollectHeartbeat() *master_pb.Heartbeat {
 				maxFileKey = v.MaxFileKey()
 			}
 			if !v.expired(s.GetVolumeSizeLimit()) {
-				volumeMessages = append(volumeMessages, v.ToVolumeInformationMessage())
+				volumeMessage := v.ToVolumeInformationMessage()
+				if s.IsReadOnly() {
+					volumeMessage.ReadOnly = true
+				}
+				volumeMessages = append(volumeMessages, volumeMessage)
 			} else {
 				if v.expiredLongEnough(MAX_TTL_VOLUME_REMOVAL_DELAY) {
 					deleteVids = append(deleteVids, v.Id)
@@ -238,6 +265,11 @@ func (s *Store) CollectHeartbeat() *master_pb.Heartbeat {
 		stats.VolumeServerDiskSizeGauge.WithLabelValues(col, "normal").Set(float64(size))
 	}
 
+	// no free slots, so that the master grows no volumes here
+	if s.IsReadOnly() && len(volumeMessages) < maxVolumeCount {
+		maxVolumeCount = len(volumeMessages)
+	}
+
 	return &master_pb.Heartbeat{
 		Ip:             s.Ip,
 		Port:           uint32(s.Port),
@@ -246,6 +278,7 @@ func (s *Store) CollectHeartbeat() *master_pb.Heartbeat {
 		MaxFileKey:     NeedleIdToUint64(maxFileKey),
 		DataCenter:     s.dataCenter,
 		Rack:           s.rack,
+		DiskType:       string(s.diskType),
 		Volumes:        volumeMessages,
 		HasNoVolumes:   len(volumeMessages) == 0,
 	}
@@ -253,6 +286,9 @@ func (s *Store) CollectHeartbeat() *master_pb.Heartbeat {
 }
 
 func (s *Store) Close() {
+	if s.fsyncStop != nil {
+		close(s.fsyncStop)
+	}
 	for _, location := range s.Locations {
 		location.Close()
 	}
@@ -260,11 +296,17 @@ func (s *Store) Close() {
 
 func (s *Store) WriteVolumeNeedle(i needle.VolumeId, n *needle.Needle, fsync bool) (isUnchanged bool, err error) {
 	if v := s.findVolume(i); v != nil {
-		if v.IsReadOnly() {
+		if v.IsReadOnly() || s.IsReadOnly() {
 			err = fmt.Errorf("volume %d is read only", i)
 			return
 		}
-		_, _, isUnchanged, err = v.writeNeedle2(n, fsync)
+		fsync = fsync || s.fsyncPolicy.Mode == FsyncAlways
+		if _, _, isUnchanged, err = v.writeNeedle2(n, fsync); err != nil || fsync || isUnchanged {
+			return
+		}
+		if s.fsyncPolicy.Mode == FsyncBatch {
+			err = v.countUnsyncedWrite(s.fsyncPolicy.BatchWrites)
+		}
 		return
 	}
 	glog.V(0).Infoln("volume", i, "not found!")
@@ -274,7 +316,7 @@ func (s *Store) WriteVolumeNeedle(i needle.VolumeId, n *needle.Needle, fsync boo
 
 func (s *Store) DeleteVolumeNeedle(i needle.VolumeId, n *needle.Needle) (uint32, error) {
 	if v := s.findVolume(i); v != nil {
-		if v.noWriteOrDelete {
+		if v.noWriteOrDelete || s.IsReadOnly() {
 			return 0, fmt.Errorf("volume %d is read only", i)
 		}
 		return v.deleteNeedle2(n)
@@ -306,6 +348,36 @@ func (s *Store) MarkVolumeReadonly(i needle.VolumeId) error {
 	return nil
 }
 
+// MarkVolumeWritable reverts MarkVolumeReadonly
+func (s *Store) MarkVolumeWritable(i needle.VolumeId) error {
+	v := s.findVolume(i)
+	if v == nil {
+		return fmt.Errorf("volume %d not found", i)
+	}
+	if v.HasRemoteFile() {
+		storageName, _ := v.RemoteStorageNameKey()
+		return fmt.Errorf("volume %d is stored on remote tier %s", i, storageName)
+	}
+	if _, _, canWrite, _, _ := util.CheckFile(v.FileName() + ".dat"); !canWrite {
+		return fmt.Errorf("volume %d data file is not writable", i)
+	}
+	v.noWriteOrDelete = false
+	return nil
+}
+
+func (s *Store) IsReadOnly() bool {
+	return atomic.LoadInt32(&s.readOnly) == 1
+}
+
+// SetReadOnly marks the whole volume server readonly, e.g. during disk maintenance
+func (s *Store) SetReadOnly(readOnly bool) {
+	if readOnly {
+		atomic.StoreInt32(&s.readOnly, 1)
+	} else {
+		atomic.StoreInt32(&s.readOnly, 0)
+	}
+}
+
 func (s *Store) MountVolume(i needle.VolumeId) error {
 	for _, location := range s.Locations {
 		if found := location.LoadVolume(i, s.NeedleMapType); found == true {
@@ -317,6 +389,7 @@ func (s *Store) MountVolume(i needle.VolumeId) error {
 				ReplicaPlacement: uint32(v.ReplicaPlacement.Byte()),
 				Version:          uint32(v.Version()),
 				Ttl:              v.Ttl.ToUint32(),
+				AppendOnly:       v.IsAppendOnly(),
 			}
 			return nil
 		}
@@ -336,6 +409,7 @@ func (s *Store) UnmountVolume(i needle.VolumeId) error {
 		ReplicaPlacement: uint32(v.ReplicaPlacement.Byte()),
 		Version:          uint32(v.Version()),
 		Ttl:              v.Ttl.ToUint32(),
+		AppendOnly:       v.IsAppendOnly(),
 	}
 
 	for _, location := range s.Locations {
@@ -354,12 +428,16 @@ func (s *Store) DeleteVolume(i needle.VolumeId) error {
 	if v == nil {
 		return fmt.Errorf("delete volume %d not found on disk", i)
 	}
+	if v.IsAppendOnly() {
+		return &AppendOnlyError{VolumeId: i, Op: "delete volume"}
+	}
 	message := master_pb.VolumeShortInformationMessage{
 		Id:               uint32(v.Id),
 		Collection:       v.Collection,
 		ReplicaPlacement: uint32(v.ReplicaPlacement.Byte()),
 		Version:          uint32(v.Version()),
 		Ttl:              v.Ttl.ToUint32(),
+		AppendOnly:       v.IsAppendOnly(),
 	}
 	for _, location := range s.Locations {
 		if found, error := location.deleteVolumeById(i); found && error == nil {
@@ -7,12 +7,13 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 )
 
-func (s *Store) CheckCompactVolume(volumeId needle.VolumeId) (float64, error) {
+func (s *Store) CheckCompactVolume(volumeId needle.VolumeId) (garbageRatio float64, garbageBytes uint64, err error) {
 	if v := s.findVolume(volumeId); v != nil {
+		garbageBytes, _ = v.garbageSize()
 		glog.V(3).Infof("volumd %d garbage level: %f", volumeId, v.garbageLevel())
-		return v.garbageLevel(), nil
+		return v.garbageLevel(), garbageBytes, nil
 	}
-	return 0, fmt.Errorf("volume id %d is not found during check compact", volumeId)
+	return 0, 0, fmt.Errorf("volume id %d is not found during check compact", volumeId)
 }
 func (s *Store) CompactVolume(vid needle.VolumeId, preallocate int64, compactionBytePerSecond int64) error {
 	if v := s.findVolume(vid); v != nil {
@@ -20,11 +21,20 @@ func (s *Store) CompactVolume(vid needle.VolumeId, preallocate int64, compaction
 	}
 	return fmt.Errorf("volume id %d is not found during compact", vid)
 }
-func (s *Store) CommitCompactVolume(vid needle.VolumeId) error {
+
+// CommitCompactVolume returns the bytes the compaction reclaimed from the .dat file
+func (s *Store) CommitCompactVolume(vid needle.VolumeId) (reclaimedBytes uint64, err error) {
 	if v := s.findVolume(vid); v != nil {
-		return v.CommitCompact()
+		datSize, _, _ := v.FileStat()
+		if err = v.CommitCompact(); err != nil {
+			return 0, err
+		}
+		if compactedSize, _, _ := v.FileStat(); compactedSize < datSize {
+			reclaimedBytes = datSize - compactedSize
+		}
+		return reclaimedBytes, nil
 	}
-	return fmt.Errorf("volume id %d is not found during commit compact", vid)
+	return 0, fmt.Errorf("volume id %d is not found during commit compact", vid)
 }
 func (s *Store) CommitCleanupVolume(vid needle.VolumeId) error {
 	if v := s.findVolume(vid); v != nil {
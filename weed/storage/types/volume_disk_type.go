@@ -0,0 +1,29 @@
+package types
+
+import (
+	"strings"
+)
+
+// DiskType is the kind of disks of a volume server, e.g. hdd or ssd.
+// The master only places the volumes of a disk type on the volume servers of the same disk type.
+type DiskType string
+
+const (
+	HardDriveType DiskType = ""
+	SsdType       DiskType = "ssd"
+)
+
+func ToDiskType(diskType string) DiskType {
+	diskType = strings.ToLower(strings.TrimSpace(diskType))
+	if diskType == "hdd" {
+		return HardDriveType
+	}
+	return DiskType(diskType)
+}
+
+func (diskType DiskType) String() string {
+	if diskType == HardDriveType {
+		return "hdd"
+	}
+	return string(diskType)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+)
+
+// An append only volume accepts only the writes of new needles, e.g. for the audit logs and the compliance locked objects.
+// The needles can not be overwritten or deleted, and the volume is not compacted.
+// The flag is set when the volume is created, saved in the .vif file, and can not be unset.
+// The volume can not be deleted, either alone or with its collection, nor erasure coded, which deletes the volume.
+
+// AppendOnlyError refuses a change to an append only volume
+type AppendOnlyError struct {
+	VolumeId needle.VolumeId
+	Op       string
+}
+
+func (e *AppendOnlyError) Error() string {
+	return fmt.Sprintf("%s: volume %d is append only", e.Op, e.VolumeId)
+}
+
+func IsAppendOnlyError(err error) bool {
+	_, ok := err.(*AppendOnlyError)
+	return ok
+}
+
+func (v *Volume) IsAppendOnly() bool {
+	return v.volumeInfo != nil && v.volumeInfo.AppendOnly
+}
+
+func (v *Volume) MarkAppendOnly() error {
+	if v.IsAppendOnly() {
+		return nil
+	}
+	v.volumeInfo.AppendOnly = true
+	if err := v.SaveVolumeInfo(); err != nil {
+		v.volumeInfo.AppendOnly = false
+		return fmt.Errorf("save volume %d info: %v", v.Id, err)
+	}
+	glog.V(0).Infof("volume %d is append only", v.Id)
+	return nil
+}
+
+// checkAppendOnlyWrite rejects the overwrites of the existing needles. It requires serialized access in the same volume.
+func (v *Volume) checkAppendOnlyWrite(n *needle.Needle) error {
+	if !v.IsAppendOnly() {
+		return nil
+	}
+	if _, found := v.nm.Get(n.Id); found {
+		return &AppendOnlyError{VolumeId: v.Id, Op: "overwrite needle " + n.Id.String()}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+)
+
+func TestAppendOnlyVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appendonly")
+	if err != nil {
+		t.Fatalf("temp dir creation: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	v, err := NewVolume(dir, "", 1, NeedleMapInMemory, &super_block.ReplicaPlacement{}, &needle.TTL{}, 0, 0)
+	if err != nil {
+		t.Fatalf("volume creation: %v", err)
+	}
+	if err = v.MarkAppendOnly(); err != nil {
+		t.Fatalf("mark append only: %v", err)
+	}
+
+	n := newRandomNeedle(1)
+	if _, _, _, err = v.writeNeedle2(n, false); err != nil {
+		t.Fatalf("write needle: %v", err)
+	}
+	// the same content again, e.g. a retried upload
+	if _, _, isUnchanged, err := v.writeNeedle2(n, false); err != nil || !isUnchanged {
+		t.Errorf("write the same needle again: unchanged %v, %v", isUnchanged, err)
+	}
+
+	overwrite := newRandomNeedle(1)
+	overwrite.Data = append(overwrite.Data, 'x')
+	overwrite.Checksum = needle.NewCRC(overwrite.Data)
+	if _, _, _, err = v.writeNeedle2(overwrite, false); !IsAppendOnlyError(err) {
+		t.Errorf("overwrote the needle")
+	}
+	if _, err = v.deleteNeedle2(newEmptyNeedle(1)); !IsAppendOnlyError(err) {
+		t.Errorf("deleted the needle")
+	}
+	if err = v.Compact2(0, 0); !IsAppendOnlyError(err) {
+		t.Errorf("compacted the volume")
+	}
+	if _, _, _, err = v.writeNeedle2(newRandomNeedle(2), false); err != nil {
+		t.Errorf("write a new needle: %v", err)
+	}
+	v.Close()
+
+	// the flag is kept in the .vif file
+	v, err = NewVolume(dir, "", 1, NeedleMapInMemory, nil, nil, 0, 0)
+	if err != nil {
+		t.Fatalf("volume loading: %v", err)
+	}
+	defer v.Close()
+	if !v.IsAppendOnly() || !v.ToVolumeInformationMessage().AppendOnly {
+		t.Errorf("the loaded volume is not append only")
+	}
+	if _, err = v.deleteNeedle2(newEmptyNeedle(2)); err == nil {
+		t.Errorf("deleted the needle after loading")
+	}
+}
+
+func TestDeleteAppendOnlyVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appendonly")
+	if err != nil {
+		t.Fatalf("temp dir creation: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewStore(nil, 8080, "localhost", "localhost:8080", []string{dir}, []int{2}, NeedleMapInMemory)
+	defer store.Close()
+	if err = store.AddVolume(1, "compliance", NeedleMapInMemory, "000", "", 0, 0, true); err != nil {
+		t.Fatalf("add volume: %v", err)
+	}
+
+	if err = store.DeleteVolume(1); !IsAppendOnlyError(err) {
+		t.Errorf("delete volume: %v", err)
+	}
+	if err = store.DeleteCollection("compliance"); !IsAppendOnlyError(err) {
+		t.Errorf("delete collection: %v", err)
+	}
+	if store.GetVolume(1) == nil {
+		t.Errorf("the volume is deleted")
+	}
+}
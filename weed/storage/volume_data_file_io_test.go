@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/storage/backend"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+)
+
+func TestMmapDataFileVolume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmap")
+	if err != nil {
+		t.Fatalf("temp dir creation: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err = backend.SetDataFileIO("mmap"); err != nil {
+		t.Fatalf("set data file io: %v", err)
+	}
+	defer backend.SetDataFileIO("")
+
+	v, err := NewVolume(dir, "", 1, NeedleMapInMemory, &super_block.ReplicaPlacement{}, &needle.TTL{}, 0, 0)
+	if err != nil {
+		t.Fatalf("volume creation: %v", err)
+	}
+	if _, ok := v.DataBackend.(*backend.MmapFile); !ok {
+		t.Skipf("data file %T is not memory mapped", v.DataBackend)
+	}
+
+	needles := make(map[uint64]*needle.Needle)
+	for id := uint64(1); id <= 20; id++ {
+		needles[id] = newRandomNeedle(id)
+		if _, _, _, err = v.writeNeedle2(needles[id], false); err != nil {
+			t.Fatalf("write needle %d: %v", id, err)
+		}
+	}
+	for id := uint64(1); id <= 10; id++ {
+		if _, err = v.deleteNeedle2(newEmptyNeedle(id)); err != nil {
+			t.Fatalf("delete needle %d: %v", id, err)
+		}
+	}
+	if err = v.Compact2(0, 0); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if err = v.CommitCompact(); err != nil {
+		t.Fatalf("commit compact: %v", err)
+	}
+	v.Close()
+
+	v, err = NewVolume(dir, "", 1, NeedleMapInMemory, nil, nil, 0, 0)
+	if err != nil {
+		t.Fatalf("volume loading: %v", err)
+	}
+	defer v.Close()
+	if _, ok := v.DataBackend.(*backend.MmapFile); !ok {
+		t.Errorf("loaded data file %T is not memory mapped", v.DataBackend)
+	}
+	for id := uint64(1); id <= 20; id++ {
+		n := newEmptyNeedle(id)
+		_, err := v.readNeedle(n)
+		if id <= 10 {
+			if err == nil {
+				t.Errorf("read deleted needle %d", id)
+			}
+			continue
+		}
+		if err != nil || string(n.Data) != string(needles[id].Data) {
+			t.Errorf("read needle %d: %v", id, err)
+		}
+	}
+}
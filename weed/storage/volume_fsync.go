@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// The fsync policy of a volume server decides when the needle appends are flushed from the OS page cache to the disk.
+//
+//   - os: the OS flushes the pages when it wants, usually within half a minute. On a power loss or a kernel crash,
+//     the writes acknowledged since the last flush of the OS are lost, while a crash of the volume server process loses nothing.
+//   - always: each write is flushed before it is acknowledged, the concurrent writes of a volume share one fsync.
+//   - batch: the writes are acknowledged before being flushed, and flushed every batch interval, or once a volume has
+//     the batch count of unflushed writes. On a power loss, at most the writes of the last interval are lost.
+//
+// A write with fsync=true, e.g. of the filer buckets_fsync buckets, is always flushed before it is acknowledged.
+// Only the .dat file is flushed. If a power loss leaves .idx entries pointing past the end of the .dat file,
+// the volume is loaded read only. The deletions are not flushed, a deleted needle may come back after a power loss.
+
+type FsyncMode int
+
+const (
+	FsyncOs FsyncMode = iota
+	FsyncAlways
+	FsyncBatch
+)
+
+type FsyncPolicy struct {
+	Mode          FsyncMode
+	BatchInterval time.Duration
+	// flush a volume once it has these unflushed writes, 0 to only flush every BatchInterval
+	BatchWrites int
+}
+
+func ParseFsyncPolicy(mode string, batchIntervalMs, batchWrites int) (policy FsyncPolicy, err error) {
+	switch mode {
+	case "", "os":
+		policy.Mode = FsyncOs
+	case "always":
+		policy.Mode = FsyncAlways
+	case "batch":
+		policy.Mode = FsyncBatch
+		if batchIntervalMs <= 0 {
+			return policy, fmt.Errorf("fsync batch interval should be positive, got %d ms", batchIntervalMs)
+		}
+		if batchWrites < 0 {
+			return policy, fmt.Errorf("fsync batch writes should not be negative, got %d", batchWrites)
+		}
+		policy.BatchInterval = time.Duration(batchIntervalMs) * time.Millisecond
+		policy.BatchWrites = batchWrites
+	default:
+		return policy, fmt.Errorf("unknown fsync policy %q, expecting os, always or batch", mode)
+	}
+	return policy, nil
+}
+
+// SetFsyncPolicy applies the policy to the following writes, and flushes the volumes every batch interval for the batch policy
+func (s *Store) SetFsyncPolicy(policy FsyncPolicy) {
+	s.fsyncPolicy = policy
+	if policy.Mode != FsyncBatch {
+		return
+	}
+	s.fsyncStop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(policy.BatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.flushVolumes()
+			case <-s.fsyncStop:
+				// the volumes are flushed when closed
+				return
+			}
+		}
+	}()
+}
+
+// flushVolumes flushes the volumes with unflushed writes, without holding the volumes lock during the flushes
+func (s *Store) flushVolumes() {
+	var volumes []*Volume
+	for _, location := range s.Locations {
+		location.volumesLock.RLock()
+		for _, v := range location.volumes {
+			volumes = append(volumes, v)
+		}
+		location.volumesLock.RUnlock()
+	}
+	for _, v := range volumes {
+		if err := v.syncDataFile(); err != nil {
+			glog.Errorf("flush volume %d: %v", v.Id, err)
+		}
+	}
+}
+
+// countUnsyncedWrite flushes the volume once it has batchWrites unflushed writes
+func (v *Volume) countUnsyncedWrite(batchWrites int) error {
+	if unsynced := atomic.AddInt64(&v.unsyncedWrites, 1); batchWrites > 0 && unsynced >= int64(batchWrites) {
+		return v.syncDataFile()
+	}
+	return nil
+}
+
+// syncDataFile flushes the .dat file if it has unflushed writes
+func (v *Volume) syncDataFile() error {
+	if atomic.LoadInt64(&v.unsyncedWrites) == 0 {
+		return nil
+	}
+	v.dataFileAccessLock.Lock()
+	defer v.dataFileAccessLock.Unlock()
+	if v.DataBackend == nil {
+		return nil
+	}
+	// a write counted after the reset may be flushed already, costing one more flush only
+	atomic.StoreInt64(&v.unsyncedWrites, 0)
+	return v.DataBackend.Sync()
+}
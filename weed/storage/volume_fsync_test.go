@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/storage/backend"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+)
+
+// powerLossFile remembers the size of the last flush, to drop the writes not flushed as a power loss would
+type powerLossFile struct {
+	backend.BackendStorageFile
+	syncedSize int64
+}
+
+func (f *powerLossFile) Sync() (err error) {
+	if f.syncedSize, _, err = f.BackendStorageFile.GetStat(); err != nil {
+		return err
+	}
+	return f.BackendStorageFile.Sync()
+}
+
+func (f *powerLossFile) powerLoss() error {
+	return f.Truncate(f.syncedSize)
+}
+
+func TestParseFsyncPolicy(t *testing.T) {
+	if policy, err := ParseFsyncPolicy("batch", 50, 10); err != nil || policy.Mode != FsyncBatch || policy.BatchInterval != 50*time.Millisecond || policy.BatchWrites != 10 {
+		t.Errorf("batch: %+v %v", policy, err)
+	}
+	if policy, err := ParseFsyncPolicy("", 0, 0); err != nil || policy.Mode != FsyncOs {
+		t.Errorf("default: %+v %v", policy, err)
+	}
+	if _, err := ParseFsyncPolicy("batch", 0, 10); err == nil {
+		t.Errorf("batch without interval")
+	}
+	if _, err := ParseFsyncPolicy("sometimes", 0, 0); err == nil {
+		t.Errorf("unknown policy")
+	}
+}
+
+func TestFsyncBatchPowerLoss(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fsync")
+	if err != nil {
+		t.Fatalf("temp dir creation: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(nil, 8080, "localhost", "localhost:8080", []string{dir}, []int{1}, NeedleMapInMemory)
+	defer s.Close()
+	if err = s.AddVolume(1, "", NeedleMapInMemory, "000", "", 0, 0, false); err != nil {
+		t.Fatalf("volume creation: %v", err)
+	}
+	v := s.findVolume(1)
+	datFile := &powerLossFile{BackendStorageFile: v.DataBackend}
+	if datFile.syncedSize, _, err = v.DataBackend.GetStat(); err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	v.DataBackend = datFile
+
+	// only flushed by the write count, the interval is never reached
+	s.SetFsyncPolicy(FsyncPolicy{Mode: FsyncBatch, BatchInterval: time.Hour, BatchWrites: 3})
+
+	needles := make(map[uint64]*needle.Needle)
+	write := func(id uint64, fsync bool) {
+		needles[id] = newRandomNeedle(id)
+		if _, err := s.WriteVolumeNeedle(1, needles[id], fsync); err != nil {
+			t.Fatalf("write %d: %v", id, err)
+		}
+	}
+	checkAfterPowerLoss := func(step string, durable, lost []uint64) {
+		if err := datFile.powerLoss(); err != nil {
+			t.Fatalf("%s: power loss: %v", step, err)
+		}
+		for _, id := range durable {
+			n := newEmptyNeedle(id)
+			if _, err := v.readNeedle(n); err != nil || string(n.Data) != string(needles[id].Data) {
+				t.Errorf("%s: needle %d is lost: %v", step, id, err)
+			}
+		}
+		for _, id := range lost {
+			if _, err := v.readNeedle(newEmptyNeedle(id)); err == nil {
+				t.Errorf("%s: needle %d was flushed", step, id)
+			}
+		}
+	}
+
+	for id := uint64(1); id <= 4; id++ {
+		write(id, false)
+	}
+	checkAfterPowerLoss("batch writes", []uint64{1, 2, 3}, []uint64{4})
+
+	// a critical write is flushed before being acknowledged
+	write(5, true)
+	checkAfterPowerLoss("fsync write", []uint64{1, 2, 3, 5}, nil)
+
+	// the batch interval flushes the volumes
+	write(6, false)
+	s.flushVolumes()
+	checkAfterPowerLoss("batch interval", []uint64{1, 2, 3, 5, 6}, nil)
+}
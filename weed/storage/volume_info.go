@@ -7,6 +7,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 )
 
 type VolumeInfo struct {
@@ -24,6 +25,8 @@ type VolumeInfo struct {
 	ModifiedAtSecond  int64
 	RemoteStorageName string
 	RemoteStorageKey  string
+	DiskType          types.DiskType // the disk type of the volume server, known by the master
+	AppendOnly        bool
 }
 
 func NewVolumeInfo(m *master_pb.VolumeInformationMessage) (vi VolumeInfo, err error) {
@@ -40,6 +43,7 @@ func NewVolumeInfo(m *master_pb.VolumeInformationMessage) (vi VolumeInfo, err er
 		ModifiedAtSecond:  m.ModifiedAtSecond,
 		RemoteStorageName: m.RemoteStorageName,
 		RemoteStorageKey:  m.RemoteStorageKey,
+		AppendOnly:        m.AppendOnly,
 	}
 	rp, e := super_block.NewReplicaPlacementFromByte(byte(m.ReplicaPlacement))
 	if e != nil {
@@ -55,6 +59,7 @@ func NewVolumeInfoFromShort(m *master_pb.VolumeShortInformationMessage) (vi Volu
 		Id:         needle.VolumeId(m.Id),
 		Collection: m.Collection,
 		Version:    needle.Version(m.Version),
+		AppendOnly: m.AppendOnly,
 	}
 	rp, e := super_block.NewReplicaPlacementFromByte(byte(m.ReplicaPlacement))
 	if e != nil {
@@ -90,6 +95,18 @@ func (vi VolumeInfo) ToVolumeInformationMessage() *master_pb.VolumeInformationMe
 		ModifiedAtSecond:  vi.ModifiedAtSecond,
 		RemoteStorageName: vi.RemoteStorageName,
 		RemoteStorageKey:  vi.RemoteStorageKey,
+		AppendOnly:        vi.AppendOnly,
+	}
+}
+
+func (vi VolumeInfo) ToVolumeShortInformationMessage() *master_pb.VolumeShortInformationMessage {
+	return &master_pb.VolumeShortInformationMessage{
+		Id:               uint32(vi.Id),
+		Collection:       vi.Collection,
+		ReplicaPlacement: uint32(vi.ReplicaPlacement.Byte()),
+		Version:          uint32(vi.Version),
+		Ttl:              vi.Ttl.ToUint32(),
+		AppendOnly:       vi.AppendOnly,
 	}
 }
 
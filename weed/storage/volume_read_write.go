@@ -60,6 +60,7 @@ func (v *Volume) Destroy() (err error) {
 	os.Remove(v.FileName() + ".sdx")
 	os.Remove(v.FileName() + ".cpd")
 	os.Remove(v.FileName() + ".cpx")
+	os.Remove(v.FileName() + ".dat.upload")
 	os.RemoveAll(v.FileName() + ".ldb")
 	return
 }
@@ -84,6 +85,9 @@ func (v *Volume) syncWrite(n *needle.Needle) (offset uint64, size uint32, isUnch
 		isUnchanged = true
 		return
 	}
+	if err = v.checkAppendOnlyWrite(n); err != nil {
+		return
+	}
 
 	// check whether existing needle cookie matches
 	nv, ok := v.nm.Get(n.Id)
@@ -148,6 +152,9 @@ func (v *Volume) doWriteRequest(n *needle.Needle) (offset uint64, size uint32, i
 		isUnchanged = true
 		return
 	}
+	if err = v.checkAppendOnlyWrite(n); err != nil {
+		return
+	}
 
 	// check whether existing needle cookie matches
 	nv, ok := v.nm.Get(n.Id)
@@ -214,6 +221,10 @@ func (v *Volume) syncDelete(n *needle.Needle) (uint32, error) {
 }
 
 func (v *Volume) deleteNeedle2(n *needle.Needle) (uint32, error) {
+	if v.IsAppendOnly() {
+		return 0, &AppendOnlyError{VolumeId: v.Id, Op: "delete needle " + n.Id.String()}
+	}
+
 	// todo: delete info is always appended no fsync, it may need fsync in future
 	fsync := false
 
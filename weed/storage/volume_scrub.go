@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/storage/idx"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	. "github.com/chrislusf/seaweedfs/weed/storage/types"
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// CorruptedNeedle is a live needle which can not be read back, or fails its CRC check.
+type CorruptedNeedle struct {
+	Id     NeedleId
+	Offset Offset
+	Size   uint32
+	Err    error
+}
+
+type VolumeScrubResult struct {
+	NeedleCount int64
+	ByteCount   int64
+	Corrupted   []*CorruptedNeedle
+}
+
+// Scrub reads all the live needles of the volume, and verifies their stored CRC.
+// The reads are throttled by the limiter. After each needle, progressFn is called with the
+// index file bytes walked and the index file size, and the scrub stops if it returns an error.
+func (v *Volume) Scrub(limiter *util.RateLimiter, progressFn func(walked, total int64) error) (result *VolumeScrubResult, err error) {
+	if v.HasRemoteFile() {
+		return nil, fmt.Errorf("volume %d is tiered to the remote storage", v.Id)
+	}
+
+	indexFile, err := os.OpenFile(v.FileName()+".idx", os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open index file: %v", err)
+	}
+	defer indexFile.Close()
+	total, err := util.GetFileSize(indexFile)
+	if err != nil {
+		return nil, fmt.Errorf("stat index file: %v", err)
+	}
+
+	result = &VolumeScrubResult{}
+	var walked int64
+	err = idx.WalkIndexFile(indexFile, func(key NeedleId, offset Offset, size uint32) error {
+		walked += NeedleMapEntrySize
+		if !offset.IsZero() && size != TombstoneFileSize {
+			actualSize := needle.GetActualSize(size, v.Version())
+			limiter.Wait(actualSize)
+			isLive, readErr := v.scrubNeedle(key, offset, size)
+			if isLive {
+				result.NeedleCount++
+				result.ByteCount += actualSize
+			}
+			if readErr != nil {
+				result.Corrupted = append(result.Corrupted, &CorruptedNeedle{Id: key, Offset: offset, Size: size, Err: readErr})
+			}
+		}
+		if progressFn != nil {
+			return progressFn(walked, total)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// scrubNeedle verifies the needle, unless it is deleted or overwritten since the index entry is written
+func (v *Volume) scrubNeedle(key NeedleId, offset Offset, size uint32) (isLive bool, err error) {
+	v.dataFileAccessLock.RLock()
+	defer v.dataFileAccessLock.RUnlock()
+
+	if v.nm == nil {
+		return false, nil
+	}
+	nv, ok := v.nm.Get(key)
+	if !ok || nv.Offset != offset || nv.Size != size {
+		return false, nil
+	}
+	_, err = verifyNeedleIntegrity(v.DataBackend, v.Version(), offset.ToAcutalOffset(), key, size)
+	return true, err
+}
+
+// ReadNeedleBlob reads the raw bytes of the live needle, without verifying them.
+func (v *Volume) ReadNeedleBlob(key NeedleId) (blob []byte, size uint32, err error) {
+	v.dataFileAccessLock.RLock()
+	defer v.dataFileAccessLock.RUnlock()
+
+	nv, ok := v.nm.Get(key)
+	if !ok || nv.Offset.IsZero() {
+		return nil, 0, ErrorNotFound
+	}
+	if nv.Size == TombstoneFileSize {
+		return nil, 0, fmt.Errorf("needle %s already deleted", key)
+	}
+	blob, err = needle.ReadNeedleBlob(v.DataBackend, nv.Offset.ToAcutalOffset(), nv.Size, v.Version())
+	return blob, nv.Size, err
+}
+
+// RepairNeedle appends the good copy of a corrupted needle, e.g. the needle blob read from a replica.
+// The copy is only taken if the corrupted needle is still the live one.
+// A readonly volume is not repaired, it is left unchanged until marked writable again.
+func (v *Volume) RepairNeedle(corrupted *CorruptedNeedle, blob []byte, size uint32, version needle.Version) error {
+	n := new(needle.Needle)
+	if err := n.ReadBytes(blob, 0, size, version); err != nil {
+		return fmt.Errorf("read needle copy: %v", err)
+	}
+	if n.Id != corrupted.Id {
+		return fmt.Errorf("needle copy %s does not match needle %s", n.Id, corrupted.Id)
+	}
+
+	if v.IsAppendOnly() {
+		return &AppendOnlyError{VolumeId: v.Id, Op: "repair needle " + n.Id.String()}
+	}
+	if v.IsReadOnly() {
+		return fmt.Errorf("repair needle %s: volume %d is read only", n.Id, v.Id)
+	}
+
+	v.dataFileAccessLock.Lock()
+	defer v.dataFileAccessLock.Unlock()
+
+	nv, ok := v.nm.Get(n.Id)
+	if !ok || nv.Offset != corrupted.Offset {
+		return fmt.Errorf("needle %s is changed since the scrub", n.Id)
+	}
+
+	n.AppendAtNs = uint64(time.Now().UnixNano())
+	offset, _, _, err := n.Append(v.DataBackend, v.Version())
+	if err != nil {
+		return fmt.Errorf("append needle %s: %v", n.Id, err)
+	}
+	v.lastAppendAtNs = n.AppendAtNs
+	return v.nm.Put(n.Id, ToOffset(int64(offset)), n.Size)
+}
+
+// ScrubVolume verifies the needles of the local volume
+func (s *Store) ScrubVolume(vid needle.VolumeId, limiter *util.RateLimiter, progressFn func(walked, total int64) error) (*VolumeScrubResult, error) {
+	if v := s.findVolume(vid); v != nil {
+		return v.Scrub(limiter, progressFn)
+	}
+	return nil, fmt.Errorf("volume %d not found", vid)
+}
+
+func (s *Store) ReadVolumeNeedleBlob(vid needle.VolumeId, key NeedleId) (blob []byte, size uint32, version needle.Version, err error) {
+	if v := s.findVolume(vid); v != nil {
+		blob, size, err = v.ReadNeedleBlob(key)
+		return blob, size, v.Version(), err
+	}
+	return nil, 0, 0, fmt.Errorf("volume %d not found", vid)
+}
+
+func (s *Store) RepairVolumeNeedle(vid needle.VolumeId, corrupted *CorruptedNeedle, blob []byte, size uint32, version needle.Version) error {
+	if s.IsReadOnly() {
+		return fmt.Errorf("volume server %s:%d is read only", s.Ip, s.Port)
+	}
+	if v := s.findVolume(vid); v != nil {
+		return v.RepairNeedle(corrupted, blob, size, version)
+	}
+	return fmt.Errorf("volume %d not found", vid)
+}
+
+// ScrubbedVolumeIds lists the local volumes which can be scrubbed, i.e. not tiered to the remote storage
+func (s *Store) ScrubbedVolumeIds() (vids []needle.VolumeId) {
+	for _, location := range s.Locations {
+		location.volumesLock.RLock()
+		for vid, v := range location.volumes {
+			if !v.HasRemoteFile() {
+				vids = append(vids, vid)
+			}
+		}
+		location.volumesLock.RUnlock()
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
+)
+
+func TestScrubAndRepair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrub")
+	if err != nil {
+		t.Fatalf("temp dir creation: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	v, err := NewVolume(dir, "", 1, NeedleMapInMemory, &super_block.ReplicaPlacement{}, &needle.TTL{}, 0, 0)
+	if err != nil {
+		t.Fatalf("volume creation: %v", err)
+	}
+	defer v.Close()
+	replica, err := NewVolume(dir, "", 2, NeedleMapInMemory, &super_block.ReplicaPlacement{}, &needle.TTL{}, 0, 0)
+	if err != nil {
+		t.Fatalf("volume creation: %v", err)
+	}
+	defer replica.Close()
+
+	for i := 1; i <= 10; i++ {
+		n := newRandomNeedle(uint64(i))
+		n.Data = append(n.Data, 'x')
+		n.Checksum = needle.NewCRC(n.Data)
+		if _, _, _, err := v.writeNeedle2(n, false); err != nil {
+			t.Fatalf("write needle %d: %v", i, err)
+		}
+		if _, _, _, err := replica.writeNeedle2(n, false); err != nil {
+			t.Fatalf("write replica needle %d: %v", i, err)
+		}
+	}
+	v.deleteNeedle2(newEmptyNeedle(3))
+
+	// flip a byte of the needle 5 data
+	nv, _ := v.nm.Get(types.Uint64ToNeedleId(5))
+	dataOffset := nv.Offset.ToAcutalOffset() + types.NeedleHeaderSize + 4
+	b := make([]byte, 1)
+	v.DataBackend.ReadAt(b, dataOffset)
+	b[0] ^= 0xff
+	v.DataBackend.WriteAt(b, dataOffset)
+
+	result, err := v.Scrub(nil, nil)
+	if err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	if result.NeedleCount != 9 || len(result.Corrupted) != 1 || result.Corrupted[0].Id != types.Uint64ToNeedleId(5) {
+		t.Fatalf("scrubbed %d needles, corrupted %+v", result.NeedleCount, result.Corrupted)
+	}
+
+	blob, size, err := replica.ReadNeedleBlob(types.Uint64ToNeedleId(5))
+	if err != nil {
+		t.Fatalf("read replica needle: %v", err)
+	}
+	// a readonly volume is not repaired
+	v.noWriteOrDelete = true
+	if err := v.RepairNeedle(result.Corrupted[0], blob, size, replica.Version()); err == nil {
+		t.Errorf("repaired a readonly volume")
+	}
+	v.noWriteOrDelete = false
+	if err := v.RepairNeedle(result.Corrupted[0], blob, size, replica.Version()); err != nil {
+		t.Fatalf("repair: %v", err)
+	}
+	// the needle is repaired already
+	if err := v.RepairNeedle(result.Corrupted[0], blob, size, replica.Version()); err == nil {
+		t.Errorf("repaired the needle twice")
+	}
+
+	if _, err := v.readNeedle(newEmptyNeedle(5)); err != nil {
+		t.Errorf("read repaired needle: %v", err)
+	}
+	result, err = v.Scrub(nil, nil)
+	if err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	if result.NeedleCount != 9 || len(result.Corrupted) != 0 {
+		t.Errorf("scrubbed %d needles after repair, corrupted %+v", result.NeedleCount, result.Corrupted)
+	}
+}
@@ -24,7 +24,7 @@ func (v *Volume) maybeWriteSuperBlock() error {
 			//read-only, but zero length - recreate it!
 			var dataFile *os.File
 			if dataFile, e = os.Create(v.DataBackend.Name()); e == nil {
-				v.DataBackend = backend.NewDiskFile(dataFile)
+				v.DataBackend = backend.OpenDataFile(dataFile)
 				if _, e = v.DataBackend.WriteAt(v.SuperBlock.Bytes(), 0); e == nil {
 					v.noWriteOrDelete = false
 					v.noWriteCanDelete = false
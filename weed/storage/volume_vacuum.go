@@ -18,19 +18,28 @@ import (
 )
 
 func (v *Volume) garbageLevel() float64 {
-	if v.ContentSize() == 0 {
+	deletedSize, fileSize := v.garbageSize()
+	if fileSize == 0 {
 		return 0
 	}
-	deletedSize := v.DeletedSize()
-	fileSize := v.ContentSize()
-	if v.DeletedCount() > 0 && v.DeletedSize() == 0 {
+	return float64(deletedSize) / float64(fileSize)
+}
+
+// garbageSize returns the bytes of the deleted needles, which a vacuum reclaims, and the bytes they are part of
+func (v *Volume) garbageSize() (deletedSize, fileSize uint64) {
+	fileSize = v.ContentSize()
+	if fileSize == 0 {
+		return 0, 0
+	}
+	deletedSize = v.DeletedSize()
+	if v.DeletedCount() > 0 && deletedSize == 0 {
 		// this happens for .sdx converted back to normal .idx
 		// where deleted entry size is missing
 		datFileSize, _, _ := v.FileStat()
 		deletedSize = datFileSize - fileSize - super_block.SuperBlockSize
 		fileSize = datFileSize
 	}
-	return float64(deletedSize) / float64(fileSize)
+	return deletedSize, fileSize
 }
 
 // compact a volume based on deletions in .dat files
@@ -39,6 +48,9 @@ func (v *Volume) Compact(preallocate int64, compactionBytePerSecond int64) error
 	if v.MemoryMapMaxSizeMb != 0 { //it makes no sense to compact in memory
 		return nil
 	}
+	if v.IsAppendOnly() {
+		return &AppendOnlyError{VolumeId: v.Id, Op: "compact"}
+	}
 	glog.V(3).Infof("Compacting volume %d ...", v.Id)
 	//no need to lock for copy on write
 	//v.accessLock.Lock()
@@ -68,6 +80,9 @@ func (v *Volume) Compact2(preallocate int64, compactionBytePerSecond int64) erro
 	if v.MemoryMapMaxSizeMb != 0 { //it makes no sense to compact in memory
 		return nil
 	}
+	if v.IsAppendOnly() {
+		return &AppendOnlyError{VolumeId: v.Id, Op: "compact"}
+	}
 	glog.V(3).Infof("Compact2 volume %d ...", v.Id)
 
 	v.isCompacting = true
@@ -88,6 +103,10 @@ func (v *Volume) Compact2(preallocate int64, compactionBytePerSecond int64) erro
 	return copyDataBasedOnIndexFile(filePath+".dat", filePath+".idx", filePath+".cpd", filePath+".cpx", v.SuperBlock, v.Version(), preallocate, compactionBytePerSecond)
 }
 
+// compactCatchUpRounds is the max number of times the writes during the compaction are replayed
+// before locking the volume, each round replaying the writes during the previous one.
+const compactCatchUpRounds = 3
+
 func (v *Volume) CommitCompact() error {
 	if v.MemoryMapMaxSizeMb != 0 { //it makes no sense to compact in memory
 		return nil
@@ -99,6 +118,17 @@ func (v *Volume) CommitCompact() error {
 		v.isCompacting = false
 	}()
 
+	// the volume stays readable and writable while most of the writes are replayed and the copied needles verified
+	indexes, e := v.catchUpCompact()
+	if e != nil {
+		glog.V(0).Infof("catch up compaction of volume %d failed: %v", v.Id, e)
+		if cleanupErr := v.cleanupCompact(); cleanupErr != nil {
+			glog.V(0).Infof("clean up compaction of volume %d: %v", v.Id, cleanupErr)
+		}
+		return fmt.Errorf("compact volume %d: %v", v.Id, e)
+	}
+	defer indexes.Close()
+
 	v.dataFileAccessLock.Lock()
 	defer v.dataFileAccessLock.Unlock()
 
@@ -112,8 +142,8 @@ func (v *Volume) CommitCompact() error {
 	v.DataBackend = nil
 	stats.VolumeServerVolumeCounter.WithLabelValues(v.Collection, "volume").Dec()
 
-	var e error
-	if e = v.makeupDiff(v.FileName()+".cpd", v.FileName()+".cpx", v.FileName()+".dat", v.FileName()+".idx"); e != nil {
+	var verifyErr error
+	if _, e = v.makeupDiff(v.FileName()+".cpd", v.FileName()+".cpx", v.FileName()+".dat", v.FileName()+".idx", true); e != nil {
 		glog.V(0).Infof("makeupDiff in CommitCompact volume %d failed %v", v.Id, e)
 		e = os.Remove(v.FileName() + ".cpd")
 		if e != nil {
@@ -123,6 +153,12 @@ func (v *Volume) CommitCompact() error {
 		if e != nil {
 			return e
 		}
+	} else if verifyErr = v.verifyCompacted(indexes); verifyErr != nil {
+		// keep the old files, which are loaded back
+		glog.V(0).Infof("verify compacted volume %d failed: %v", v.Id, verifyErr)
+		if e = v.cleanupCompact(); e != nil {
+			return e
+		}
 	} else {
 		if runtime.GOOS == "windows" {
 			e = os.RemoveAll(v.FileName() + ".dat")
@@ -152,6 +188,9 @@ func (v *Volume) CommitCompact() error {
 	if e = v.load(true, false, v.needleMapKind, 0); e != nil {
 		return e
 	}
+	if verifyErr != nil {
+		return fmt.Errorf("compacted volume %d failed verification: %v", v.Id, verifyErr)
+	}
 	return nil
 }
 
@@ -177,8 +216,10 @@ func fetchCompactRevisionFromDatFile(datBackend backend.BackendStorageFile) (com
 	return superBlock.CompactionRevision, nil
 }
 
-// if old .dat and .idx files are updated, this func tries to apply the same changes to new files accordingly
-func (v *Volume) makeupDiff(newDatFileName, newIdxFileName, oldDatFileName, oldIdxFileName string) (err error) {
+// if old .dat and .idx files are updated, this func tries to apply the same changes to new files accordingly.
+// The changes are applied up to the current end of the old .idx file, which becomes the new lastCompactIndexOffset.
+// If the volume is not locked, the old files can be appended at the same time.
+func (v *Volume) makeupDiff(newDatFileName, newIdxFileName, oldDatFileName, oldIdxFileName string, isVolumeLocked bool) (replayed int, err error) {
 	var indexSize int64
 
 	oldIdxFile, err := os.Open(oldIdxFileName)
@@ -189,20 +230,28 @@ func (v *Volume) makeupDiff(newDatFileName, newIdxFileName, oldDatFileName, oldI
 	defer oldDatBackend.Close()
 
 	// skip if the old .idx file has not changed
-	if indexSize, err = verifyIndexFileIntegrity(oldIdxFile); err != nil {
-		return fmt.Errorf("verifyIndexFileIntegrity %s failed: %v", oldIdxFileName, err)
+	if isVolumeLocked {
+		if indexSize, err = verifyIndexFileIntegrity(oldIdxFile); err != nil {
+			return 0, fmt.Errorf("verifyIndexFileIntegrity %s failed: %v", oldIdxFileName, err)
+		}
+	} else {
+		// skip the index entry being written
+		if indexSize, err = util.GetFileSize(oldIdxFile); err != nil {
+			return 0, fmt.Errorf("stat %s failed: %v", oldIdxFileName, err)
+		}
+		indexSize -= indexSize % NeedleMapEntrySize
 	}
 	if indexSize == 0 || uint64(indexSize) <= v.lastCompactIndexOffset {
-		return nil
+		return 0, nil
 	}
 
 	// fail if the old .dat file has changed to a new revision
 	oldDatCompactRevision, err := fetchCompactRevisionFromDatFile(oldDatBackend)
 	if err != nil {
-		return fmt.Errorf("fetchCompactRevisionFromDatFile src %s failed: %v", oldDatFile.Name(), err)
+		return 0, fmt.Errorf("fetchCompactRevisionFromDatFile src %s failed: %v", oldDatFile.Name(), err)
 	}
 	if oldDatCompactRevision != v.lastCompactRevision {
-		return fmt.Errorf("current old dat file's compact revision %d is not the expected one %d", oldDatCompactRevision, v.lastCompactRevision)
+		return 0, fmt.Errorf("current old dat file's compact revision %d is not the expected one %d", oldDatCompactRevision, v.lastCompactRevision)
 	}
 
 	type keyField struct {
@@ -214,7 +263,7 @@ func (v *Volume) makeupDiff(newDatFileName, newIdxFileName, oldDatFileName, oldI
 	for idxOffset := indexSize - NeedleMapEntrySize; uint64(idxOffset) >= v.lastCompactIndexOffset; idxOffset -= NeedleMapEntrySize {
 		var IdxEntry []byte
 		if IdxEntry, err = readIndexEntryAtOffset(oldIdxFile, idxOffset); err != nil {
-			return fmt.Errorf("readIndexEntry %s at offset %d failed: %v", oldIdxFileName, idxOffset, err)
+			return 0, fmt.Errorf("readIndexEntry %s at offset %d failed: %v", oldIdxFileName, idxOffset, err)
 		}
 		key, offset, size := idx2.IdxFileEntry(IdxEntry)
 		glog.V(4).Infof("key %d offset %d size %d", key, offset, size)
@@ -228,7 +277,8 @@ func (v *Volume) makeupDiff(newDatFileName, newIdxFileName, oldDatFileName, oldI
 
 	// no updates during commit step
 	if len(incrementedHasUpdatedIndexEntry) == 0 {
-		return nil
+		v.lastCompactIndexOffset = uint64(indexSize)
+		return 0, nil
 	}
 
 	// deal with updates during commit step
@@ -236,23 +286,23 @@ func (v *Volume) makeupDiff(newDatFileName, newIdxFileName, oldDatFileName, oldI
 		dst, idx *os.File
 	)
 	if dst, err = os.OpenFile(newDatFileName, os.O_RDWR, 0644); err != nil {
-		return fmt.Errorf("open dat file %s failed: %v", newDatFileName, err)
+		return 0, fmt.Errorf("open dat file %s failed: %v", newDatFileName, err)
 	}
 	dstDatBackend := backend.NewDiskFile(dst)
 	defer dstDatBackend.Close()
 
 	if idx, err = os.OpenFile(newIdxFileName, os.O_RDWR, 0644); err != nil {
-		return fmt.Errorf("open idx file %s failed: %v", newIdxFileName, err)
+		return 0, fmt.Errorf("open idx file %s failed: %v", newIdxFileName, err)
 	}
 	defer idx.Close()
 
 	var newDatCompactRevision uint16
 	newDatCompactRevision, err = fetchCompactRevisionFromDatFile(dstDatBackend)
 	if err != nil {
-		return fmt.Errorf("fetchCompactRevisionFromDatFile dst %s failed: %v", dst.Name(), err)
+		return 0, fmt.Errorf("fetchCompactRevisionFromDatFile dst %s failed: %v", dst.Name(), err)
 	}
 	if oldDatCompactRevision+1 != newDatCompactRevision {
-		return fmt.Errorf("oldDatFile %s 's compact revision is %d while newDatFile %s 's compact revision is %d", oldDatFileName, oldDatCompactRevision, newDatFileName, newDatCompactRevision)
+		return 0, fmt.Errorf("oldDatFile %s 's compact revision is %d while newDatFile %s 's compact revision is %d", oldDatFileName, oldDatCompactRevision, newDatFileName, newDatCompactRevision)
 	}
 
 	for key, increIdxEntry := range incrementedHasUpdatedIndexEntry {
@@ -273,14 +323,14 @@ func (v *Volume) makeupDiff(newDatFileName, newIdxFileName, oldDatFileName, oldI
 			}
 		}
 
-		//updated needle
-		if !increIdxEntry.offset.IsZero() && increIdxEntry.size != 0 && increIdxEntry.size != TombstoneFileSize {
+		//updated needle, which can be empty
+		if !increIdxEntry.offset.IsZero() && increIdxEntry.size != TombstoneFileSize {
 			//even the needle cache in memory is hit, the need_bytes is correct
 			glog.V(4).Infof("file %d offset %d size %d", key, increIdxEntry.offset.ToAcutalOffset(), increIdxEntry.size)
 			var needleBytes []byte
 			needleBytes, err = needle.ReadNeedleBlob(oldDatBackend, increIdxEntry.offset.ToAcutalOffset(), increIdxEntry.size, v.Version())
 			if err != nil {
-				return fmt.Errorf("ReadNeedleBlob %s key %d offset %d size %d failed: %v", oldDatFile.Name(), key, increIdxEntry.offset.ToAcutalOffset(), increIdxEntry.size, err)
+				return 0, fmt.Errorf("ReadNeedleBlob %s key %d offset %d size %d failed: %v", oldDatFile.Name(), key, increIdxEntry.offset.ToAcutalOffset(), increIdxEntry.size, err)
 			}
 			dst.Write(needleBytes)
 			util.Uint32toBytes(idxEntryBytes[8:12], uint32(offset/NeedlePaddingSize))
@@ -292,19 +342,23 @@ func (v *Volume) makeupDiff(newDatFileName, newIdxFileName, oldDatFileName, oldI
 			fakeDelNeedle.AppendAtNs = uint64(time.Now().UnixNano())
 			_, _, _, err = fakeDelNeedle.Append(dstDatBackend, v.Version())
 			if err != nil {
-				return fmt.Errorf("append deleted %d failed: %v", key, err)
+				return 0, fmt.Errorf("append deleted %d failed: %v", key, err)
 			}
 			util.Uint32toBytes(idxEntryBytes[8:12], uint32(0))
 		}
 
 		if _, err := idx.Seek(0, 2); err != nil {
-			return fmt.Errorf("cannot seek end of indexfile %s: %v",
+			return 0, fmt.Errorf("cannot seek end of indexfile %s: %v",
 				newIdxFileName, err)
 		}
-		_, err = idx.Write(idxEntryBytes)
+		if _, err = idx.Write(idxEntryBytes); err != nil {
+			return 0, fmt.Errorf("write indexfile %s: %v", newIdxFileName, err)
+		}
+		replayed++
 	}
 
-	return nil
+	v.lastCompactIndexOffset = uint64(indexSize)
+	return replayed, nil
 }
 
 type VolumeFileScanner4Vacuum struct {
@@ -49,11 +49,12 @@ func TestMakeDiff(t *testing.T) {
 	v.lastCompactIndexOffset = 96
 	v.SuperBlock.Version = 0x2
 	/*
-		err := v.makeupDiff(
+		_, err := v.makeupDiff(
 			"/yourpath/1.cpd",
 			"/yourpath/1.cpx",
 			"/yourpath/1.dat",
-			"/yourpath/1.idx")
+			"/yourpath/1.idx",
+			true)
 		if err != nil {
 			t.Errorf("makeupDiff err is %v", err)
 		} else {
@@ -92,7 +93,9 @@ func TestCompaction(t *testing.T) {
 		doSomeWritesDeletes(i+beforeCommitFileCount, v, t, infos)
 	}
 
-	v.CommitCompact()
+	if err = v.CommitCompact(); err != nil {
+		t.Fatalf("commit compaction: %v", err)
+	}
 
 	v.Close()
 
@@ -127,6 +130,104 @@ func TestCompaction(t *testing.T) {
 	}
 
 }
+func TestCompactionVerification(t *testing.T) {
+	dir, err := ioutil.TempDir("", "example")
+	if err != nil {
+		t.Fatalf("temp dir creation: %v", err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	v, err := NewVolume(dir, "", 1, NeedleMapInMemory, &super_block.ReplicaPlacement{}, &needle.TTL{}, 0, 0)
+	if err != nil {
+		t.Fatalf("volume creation: %v", err)
+	}
+	defer v.Close()
+
+	for i := 1; i <= 10; i++ {
+		n := newEmptyNeedle(uint64(i))
+		n.Data = []byte("some data to be compacted")
+		n.Checksum = needle.NewCRC(n.Data)
+		if _, _, _, err := v.writeNeedle2(n, false); err != nil {
+			t.Fatalf("write file %d: %v", i, err)
+		}
+	}
+	v.deleteNeedle2(newEmptyNeedle(1))
+
+	if err = v.Compact2(0, 0); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+
+	// corrupt the data of the first compacted needle
+	cpd, err := os.OpenFile(v.FileName()+".cpd", os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("open cpd: %v", err)
+	}
+	cpd.WriteAt([]byte("X"), super_block.SuperBlockSize+types.NeedleHeaderSize+4)
+	cpd.Close()
+
+	if err = v.CommitCompact(); err == nil {
+		t.Fatalf("committed a corrupted compaction")
+	}
+	if _, err = os.Stat(v.FileName() + ".cpd"); !os.IsNotExist(err) {
+		t.Errorf("corrupted compaction is not removed: %v", err)
+	}
+	for i := 2; i <= 10; i++ {
+		n := newEmptyNeedle(uint64(i))
+		if _, err := v.readNeedle(n); err != nil || string(n.Data) != "some data to be compacted" {
+			t.Errorf("read file %d: %q %v", i, n.Data, err)
+		}
+	}
+}
+
+func TestCompactionVerificationCatchUp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "example")
+	if err != nil {
+		t.Fatalf("temp dir creation: %v", err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	v, err := NewVolume(dir, "", 1, NeedleMapInMemory, &super_block.ReplicaPlacement{}, &needle.TTL{}, 0, 0)
+	if err != nil {
+		t.Fatalf("volume creation: %v", err)
+	}
+	defer v.Close()
+
+	write := func(i int) {
+		n := newEmptyNeedle(uint64(i))
+		n.Data = []byte("some data to be compacted")
+		n.Checksum = needle.NewCRC(n.Data)
+		if _, _, _, err := v.writeNeedle2(n, false); err != nil {
+			t.Fatalf("write file %d: %v", i, err)
+		}
+	}
+	for i := 1; i <= 10; i++ {
+		write(i)
+	}
+	if err = v.Compact2(0, 0); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	indexes, err := v.catchUpCompact()
+	if err != nil {
+		t.Fatalf("catch up compaction: %v", err)
+	}
+	defer indexes.Close()
+
+	// the writes after the needle maps are loaded are only in the volume files, until replayed
+	write(11)
+	if err = v.verifyCompacted(indexes); err == nil {
+		t.Errorf("verified the compaction missing a needle")
+	}
+	if _, err = v.makeupDiff(v.FileName()+".cpd", v.FileName()+".cpx", v.FileName()+".dat", v.FileName()+".idx", false); err != nil {
+		t.Fatalf("makeup diff: %v", err)
+	}
+	if err = v.verifyCompacted(indexes); err != nil {
+		t.Errorf("verify compaction: %v", err)
+	}
+	if _, found := indexes.newNm.Get(types.NeedleId(11)); !found {
+		t.Errorf("replayed needle is not caught up")
+	}
+}
+
 func doSomeWritesDeletes(i int, v *Volume, t *testing.T, infos []*needleInfo) {
 	n := newRandomNeedle(uint64(i))
 	_, size, _, err := v.writeNeedle2(n, false)
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/storage/backend"
+	"github.com/chrislusf/seaweedfs/weed/storage/idx"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/needle_map"
+	. "github.com/chrislusf/seaweedfs/weed/storage/types"
+)
+
+// compactedIndexes holds the needle maps of the .idx file and of the compacted .cpx file,
+// loaded without locking the volume, and caught up with the entries appended since then before the final compare.
+type compactedIndexes struct {
+	oldNm, newNm               *needle_map.MemDb
+	oldIndexSize, newIndexSize int64
+	verifiedIndexSize          int64 // size of the part of the .cpx file with the needles passing their CRC check
+}
+
+func (c *compactedIndexes) Close() {
+	c.oldNm.Close()
+	c.newNm.Close()
+}
+
+// catchUp applies the index entries appended after the loaded sizes
+func (c *compactedIndexes) catchUp(oldIdxFileName, newIdxFileName string) (err error) {
+	if c.oldIndexSize, err = walkIndexFileFrom(oldIdxFileName, c.oldIndexSize, applyIndexEntry(c.oldNm)); err != nil {
+		return fmt.Errorf("load %s: %v", oldIdxFileName, err)
+	}
+	if c.newIndexSize, err = walkIndexFileFrom(newIdxFileName, c.newIndexSize, applyIndexEntry(c.newNm)); err != nil {
+		return fmt.Errorf("load %s: %v", newIdxFileName, err)
+	}
+	return nil
+}
+
+// applyIndexEntry sets or deletes the needle of the index entry, the same as MemDb.LoadFromIdx
+func applyIndexEntry(nm *needle_map.MemDb) func(key NeedleId, offset Offset, size uint32) error {
+	return func(key NeedleId, offset Offset, size uint32) error {
+		if offset.IsZero() || size == TombstoneFileSize {
+			return nm.Delete(key)
+		}
+		return nm.Set(key, offset, size)
+	}
+}
+
+// catchUpCompact replays the writes during the compaction, verifies the compacted needles, and loads the needle maps
+// to compare, without locking the volume.
+func (v *Volume) catchUpCompact() (*compactedIndexes, error) {
+	for i := 0; i < compactCatchUpRounds; i++ {
+		replayed, err := v.makeupDiff(v.FileName()+".cpd", v.FileName()+".cpx", v.FileName()+".dat", v.FileName()+".idx", false)
+		if err != nil {
+			return nil, err
+		}
+		if replayed == 0 {
+			break
+		}
+	}
+	verifiedIndexSize, err := verifyCompactedNeedles(v.FileName()+".cpd", v.FileName()+".cpx", v.Version(), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compactedIndexes{
+		oldNm:             needle_map.NewMemDb(),
+		newNm:             needle_map.NewMemDb(),
+		verifiedIndexSize: verifiedIndexSize,
+	}
+	if err = c.catchUp(v.FileName()+".idx", v.FileName()+".cpx"); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// verifyCompacted checks the compacted files before they replace the volume files, which are not changing.
+// The compacted .cpx file should have the same live needles of the same sizes as the .idx file,
+// except the expired or unreadable needles which are dropped by the compaction,
+// and the needles after the verified part should pass their CRC check.
+// Only the index entries appended since catchUpCompact are read here.
+func (v *Volume) verifyCompacted(c *compactedIndexes) error {
+	if _, err := verifyCompactedNeedles(v.FileName()+".cpd", v.FileName()+".cpx", v.Version(), c.verifiedIndexSize); err != nil {
+		return err
+	}
+	if err := c.catchUp(v.FileName()+".idx", v.FileName()+".cpx"); err != nil {
+		return err
+	}
+	oldNm, newNm := c.oldNm, c.newNm
+
+	var oldCount, newCount int
+	if err := newNm.AscendingVisit(func(value needle_map.NeedleValue) error {
+		newCount++
+		oldValue, found := oldNm.Get(value.Key)
+		if !found {
+			return fmt.Errorf("needle %s is not in the volume", value.Key)
+		}
+		if oldValue.Size != value.Size {
+			return fmt.Errorf("needle %s size %d is not the volume needle size %d", value.Key, value.Size, oldValue.Size)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	datFile, err := os.Open(v.FileName() + ".dat")
+	if err != nil {
+		return fmt.Errorf("open %s.dat: %v", v.FileName(), err)
+	}
+	datBackend := backend.NewDiskFile(datFile)
+	defer datBackend.Close()
+
+	now := uint64(time.Now().Unix())
+	if err = oldNm.AscendingVisit(func(value needle_map.NeedleValue) error {
+		oldCount++
+		if _, found := newNm.Get(value.Key); found {
+			return nil
+		}
+		n := new(needle.Needle)
+		if readErr := n.ReadData(datBackend, value.Offset.ToAcutalOffset(), value.Size, v.Version()); readErr != nil {
+			return nil
+		}
+		if n.HasTtl() && now >= n.LastModified+uint64(v.Ttl.Minutes()*60) {
+			return nil
+		}
+		return fmt.Errorf("needle %s is missing", value.Key)
+	}); err != nil {
+		return err
+	}
+
+	if newCount > oldCount {
+		return fmt.Errorf("%d needles are more than the %d volume needles", newCount, oldCount)
+	}
+	return nil
+}
+
+// verifyCompactedNeedles checks the CRC of the needles in the index file starting from fromIndexSize.
+// It returns the size of the verified index file.
+func verifyCompactedNeedles(datFileName, idxFileName string, version needle.Version, fromIndexSize int64) (indexSize int64, err error) {
+	datFile, err := os.Open(datFileName)
+	if err != nil {
+		return 0, fmt.Errorf("open %s: %v", datFileName, err)
+	}
+	datBackend := backend.NewDiskFile(datFile)
+	defer datBackend.Close()
+
+	return walkIndexFileFrom(idxFileName, fromIndexSize, func(key NeedleId, offset Offset, size uint32) error {
+		if offset.IsZero() || size == TombstoneFileSize {
+			return nil
+		}
+		if _, err := verifyNeedleIntegrity(datBackend, version, offset.ToAcutalOffset(), key, size); err != nil {
+			return fmt.Errorf("needle %s in %s: %v", key, datFileName, err)
+		}
+		return nil
+	})
+}
+
+// walkIndexFileFrom calls fn with the index entries after fromIndexSize, and returns the size of the walked index file
+func walkIndexFileFrom(idxFileName string, fromIndexSize int64, fn func(key NeedleId, offset Offset, size uint32) error) (indexSize int64, err error) {
+	idxFile, err := os.Open(idxFileName)
+	if err != nil {
+		return 0, fmt.Errorf("open %s: %v", idxFileName, err)
+	}
+	defer idxFile.Close()
+	stat, err := idxFile.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("stat %s: %v", idxFileName, err)
+	}
+	indexSize = stat.Size() / NeedleMapEntrySize * NeedleMapEntrySize
+
+	bytes := make([]byte, NeedleMapEntrySize*idx.RowsToRead)
+	for readerOffset := fromIndexSize; readerOffset < indexSize; {
+		count := int64(len(bytes))
+		if indexSize-readerOffset < count {
+			count = indexSize - readerOffset
+		}
+		if _, err = idxFile.ReadAt(bytes[:count], readerOffset); err != nil {
+			return 0, fmt.Errorf("read %s: %v", idxFileName, err)
+		}
+		for i := int64(0); i < count; i += NeedleMapEntrySize {
+			if err = fn(idx.IdxFileEntry(bytes[i : i+NeedleMapEntrySize])); err != nil {
+				return 0, err
+			}
+		}
+		readerOffset += count
+	}
+	return indexSize, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/glog"
 	"github.com/chrislusf/seaweedfs/weed/storage/erasure_coding"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 )
 
 type NodeId string
@@ -17,7 +18,7 @@ type Node interface {
 	Id() NodeId
 	String() string
 	FreeSpace() int64
-	ReserveOneVolume(r int64) (*DataNode, error)
+	ReserveOneVolume(r int64, diskType types.DiskType) (*DataNode, error)
 	UpAdjustMaxVolumeCountDelta(maxVolumeCountDelta int64)
 	UpAdjustVolumeCountDelta(volumeCountDelta int64)
 	UpAdjustRemoteVolumeCountDelta(remoteVolumeCountDelta int64)
@@ -61,8 +62,8 @@ type NodeImpl struct {
 	value    interface{}
 }
 
-// the first node must satisfy filterFirstNodeFn(), the rest nodes must have one free slot
-func (n *NodeImpl) PickNodesByWeight(numberOfNodes int, filterFirstNodeFn func(dn Node) error) (firstNode Node, restNodes []Node, err error) {
+// the first node must satisfy filterFirstNodeFn(), the rest nodes must have one free slot of the disk type
+func (n *NodeImpl) PickNodesByWeight(numberOfNodes int, diskType types.DiskType, filterFirstNodeFn func(dn Node) error) (firstNode Node, restNodes []Node, err error) {
 	var totalWeights int64
 	var errs []string
 	n.RLock()
@@ -70,12 +71,13 @@ func (n *NodeImpl) PickNodesByWeight(numberOfNodes int, filterFirstNodeFn func(d
 	candidatesWeights := make([]int64, 0, len(n.children))
 	//pick nodes which has enough free volumes as candidates, and use free volumes number as node weight.
 	for _, node := range n.children {
-		if node.FreeSpace() <= 0 {
+		freeSpace := freeSpaceOfDiskType(node, diskType)
+		if freeSpace <= 0 {
 			continue
 		}
-		totalWeights += node.FreeSpace()
+		totalWeights += freeSpace
 		candidates = append(candidates, node)
-		candidatesWeights = append(candidatesWeights, node.FreeSpace())
+		candidatesWeights = append(candidatesWeights, freeSpace)
 	}
 	n.RUnlock()
 	if len(candidates) < numberOfNodes {
@@ -166,11 +168,11 @@ func (n *NodeImpl) Parent() Node {
 func (n *NodeImpl) GetValue() interface{} {
 	return n.value
 }
-func (n *NodeImpl) ReserveOneVolume(r int64) (assignedNode *DataNode, err error) {
+func (n *NodeImpl) ReserveOneVolume(r int64, diskType types.DiskType) (assignedNode *DataNode, err error) {
 	n.RLock()
 	defer n.RUnlock()
 	for _, node := range n.children {
-		freeSpace := node.FreeSpace()
+		freeSpace := freeSpaceOfDiskType(node, diskType)
 		// fmt.Println("r =", r, ", node =", node, ", freeSpace =", freeSpace)
 		if freeSpace <= 0 {
 			continue
@@ -178,11 +180,11 @@ func (n *NodeImpl) ReserveOneVolume(r int64) (assignedNode *DataNode, err error)
 		if r >= freeSpace {
 			r -= freeSpace
 		} else {
-			if node.IsDataNode() && node.FreeSpace() > 0 {
+			if node.IsDataNode() {
 				// fmt.Println("vid =", vid, " assigned to node =", node, ", freeSpace =", node.FreeSpace())
 				return node.(*DataNode), nil
 			}
-			assignedNode, err = node.ReserveOneVolume(r)
+			assignedNode, err = node.ReserveOneVolume(r, diskType)
 			if err == nil {
 				return
 			}
@@ -191,6 +193,36 @@ func (n *NodeImpl) ReserveOneVolume(r int64) (assignedNode *DataNode, err error)
 	return nil, errors.New("No free volume slot found!")
 }
 
+// freeSpaceOfDiskType counts the free volume slots of the data nodes of the disk type under the node
+func freeSpaceOfDiskType(node Node, diskType types.DiskType) int64 {
+	if node.IsDataNode() {
+		if node.GetValue().(*DataNode).DiskType != diskType {
+			return 0
+		}
+		return node.FreeSpace()
+	}
+	var freeSpace int64
+	for _, child := range node.Children() {
+		freeSpace += freeSpaceOfDiskType(child, diskType)
+	}
+	return freeSpace
+}
+
+// maxVolumeCountOfDiskType counts the volume slots of the data nodes of the disk type under the node
+func maxVolumeCountOfDiskType(node Node, diskType types.DiskType) int64 {
+	if node.IsDataNode() {
+		if node.GetValue().(*DataNode).DiskType != diskType {
+			return 0
+		}
+		return node.GetMaxVolumeCount()
+	}
+	var maxVolumeCount int64
+	for _, child := range node.Children() {
+		maxVolumeCount += maxVolumeCountOfDiskType(child, diskType)
+	}
+	return maxVolumeCount
+}
+
 func (n *NodeImpl) UpAdjustMaxVolumeCountDelta(maxVolumeCountDelta int64) { //can be negative
 	if maxVolumeCountDelta == 0 {
 		return
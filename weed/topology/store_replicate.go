@@ -17,7 +17,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
-func ReplicatedWrite(masterNode string, s *storage.Store, volumeId needle.VolumeId, n *needle.Needle, r *http.Request) (isUnchanged bool, err error) {
+func ReplicatedWrite(masterNode string, s *storage.Store, volumeId needle.VolumeId, n *needle.Needle, r *http.Request, limiter *util.RateLimiter) (isUnchanged bool, err error) {
 
 	//check JWT
 	jwt := security.GetJwt(r)
@@ -42,7 +42,10 @@ func ReplicatedWrite(masterNode string, s *storage.Store, volumeId needle.Volume
 	if s.GetVolume(volumeId) != nil {
 		isUnchanged, err = s.WriteVolumeNeedle(volumeId, n, fsync)
 		if err != nil {
-			err = fmt.Errorf("failed to write to local disk: %v", err)
+			// keep the refusal of an append only volume for the caller
+			if !storage.IsAppendOnlyError(err) {
+				err = fmt.Errorf("failed to write to local disk: %v", err)
+			}
 			glog.V(0).Infoln(err)
 			return
 		}
@@ -80,6 +83,7 @@ func ReplicatedWrite(masterNode string, s *storage.Store, volumeId needle.Volume
 			}
 
 			// volume server do not know about encryption
+			limiter.Wait(int64(len(n.Data)))
 			_, err := operation.UploadData(u.String(), string(n.Name), false, n.Data, n.IsGzipped(), string(n.Mime), pairMap, jwt)
 			return err
 		}); err != nil {
@@ -14,6 +14,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/storage"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 	"github.com/chrislusf/seaweedfs/weed/util"
 )
 
@@ -21,6 +22,9 @@ type Topology struct {
 	vacuumLockCounter int64
 	NodeImpl
 
+	garbageThreshold            float64
+	collectionGarbageThresholds map[string]float64
+
 	collectionMap  *util.ConcurrentReadMap
 	ecShardMap     map[needle.VolumeId]*EcShardLocations
 	ecShardMapLock sync.RWMutex
@@ -122,26 +126,31 @@ func (t *Topology) NextVolumeId() (needle.VolumeId, error) {
 }
 
 func (t *Topology) HasWritableVolume(option *VolumeGrowOption) bool {
-	vl := t.GetVolumeLayout(option.Collection, option.ReplicaPlacement, option.Ttl)
+	vl := t.GetVolumeLayout(option.Collection, option.ReplicaPlacement, option.Ttl, option.DiskType, option.AppendOnly)
 	return vl.GetActiveVolumeCount(option) > 0
 }
 
+// FreeSpaceOfDiskType counts the free volume slots on the volume servers of the disk type
+func (t *Topology) FreeSpaceOfDiskType(diskType types.DiskType) int64 {
+	return freeSpaceOfDiskType(t, diskType)
+}
+
 func (t *Topology) PickForWrite(count uint64, option *VolumeGrowOption) (string, uint64, *DataNode, error) {
-	vid, count, datanodes, err := t.GetVolumeLayout(option.Collection, option.ReplicaPlacement, option.Ttl).PickForWrite(count, option)
+	vid, count, datanodes, err := t.GetVolumeLayout(option.Collection, option.ReplicaPlacement, option.Ttl, option.DiskType, option.AppendOnly).PickForWrite(count, option)
 	if err != nil {
-		return "", 0, nil, fmt.Errorf("failed to find writable volumes for collection:%s replication:%s ttl:%s error: %v", option.Collection, option.ReplicaPlacement.String(), option.Ttl.String(), err)
+		return "", 0, nil, fmt.Errorf("failed to find writable volumes for collection:%s replication:%s ttl:%s disk:%s error: %v", option.Collection, option.ReplicaPlacement.String(), option.Ttl.String(), option.DiskType, err)
 	}
 	if datanodes.Length() == 0 {
-		return "", 0, nil, fmt.Errorf("no writable volumes available for collection:%s replication:%s ttl:%s", option.Collection, option.ReplicaPlacement.String(), option.Ttl.String())
+		return "", 0, nil, fmt.Errorf("no writable volumes available for collection:%s replication:%s ttl:%s disk:%s", option.Collection, option.ReplicaPlacement.String(), option.Ttl.String(), option.DiskType)
 	}
 	fileId := t.Sequence.NextFileId(count)
 	return needle.NewFileId(*vid, fileId, rand.Uint32()).String(), count, datanodes.Head(), nil
 }
 
-func (t *Topology) GetVolumeLayout(collectionName string, rp *super_block.ReplicaPlacement, ttl *needle.TTL) *VolumeLayout {
+func (t *Topology) GetVolumeLayout(collectionName string, rp *super_block.ReplicaPlacement, ttl *needle.TTL, diskType types.DiskType, appendOnly bool) *VolumeLayout {
 	return t.collectionMap.Get(collectionName, func() interface{} {
 		return NewCollection(collectionName, t.volumeSizeLimit, t.replicationAsMin)
-	}).(*Collection).GetOrCreateVolumeLayout(rp, ttl)
+	}).(*Collection).GetOrCreateVolumeLayout(rp, ttl, diskType, appendOnly)
 }
 
 func (t *Topology) ListCollections(includeNormalVolumes, includeEcVolumes bool) (ret []string) {
@@ -178,11 +187,11 @@ func (t *Topology) DeleteCollection(collectionName string) {
 }
 
 func (t *Topology) RegisterVolumeLayout(v storage.VolumeInfo, dn *DataNode) {
-	t.GetVolumeLayout(v.Collection, v.ReplicaPlacement, v.Ttl).RegisterVolume(&v, dn)
+	t.GetVolumeLayout(v.Collection, v.ReplicaPlacement, v.Ttl, v.DiskType, v.AppendOnly).RegisterVolume(&v, dn)
 }
 func (t *Topology) UnRegisterVolumeLayout(v storage.VolumeInfo, dn *DataNode) {
 	glog.Infof("removing volume info:%+v", v)
-	volumeLayout := t.GetVolumeLayout(v.Collection, v.ReplicaPlacement, v.Ttl)
+	volumeLayout := t.GetVolumeLayout(v.Collection, v.ReplicaPlacement, v.Ttl, v.DiskType, v.AppendOnly)
 	volumeLayout.UnRegisterVolume(&v, dn)
 	if volumeLayout.isEmpty() {
 		t.DeleteCollection(v.Collection)
@@ -201,21 +210,53 @@ func (t *Topology) GetOrCreateDataCenter(dcName string) *DataCenter {
 	return dc
 }
 
+func (t *Topology) FindDataNode(url string) *DataNode {
+	for _, c := range t.Children() {
+		dc := c.(*DataCenter)
+		for _, r := range dc.Children() {
+			rack := r.(*Rack)
+			for _, n := range rack.Children() {
+				if dn := n.(*DataNode); dn.Url() == url {
+					return dn
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SetVolumeReadOnly applies the change before the volume server reports it in the next heartbeat
+func (t *Topology) SetVolumeReadOnly(dn *DataNode, vid needle.VolumeId, readOnly bool) {
+	v, err := dn.GetVolumesById(vid)
+	if err != nil {
+		return
+	}
+	v.ReadOnly = readOnly
+	if _, isChangedRO := dn.AddOrUpdateVolume(v); isChangedRO {
+		t.RegisterVolumeLayout(v, dn)
+	}
+}
+
 func (t *Topology) SyncDataNodeRegistration(volumes []*master_pb.VolumeInformationMessage, dn *DataNode) (newVolumes, deletedVolumes []storage.VolumeInfo) {
 	// convert into in memory struct storage.VolumeInfo
 	var volumeInfos []storage.VolumeInfo
 	for _, v := range volumes {
 		if vi, err := storage.NewVolumeInfo(v); err == nil {
+			vi.DiskType = dn.DiskType
 			volumeInfos = append(volumeInfos, vi)
 		} else {
 			glog.V(0).Infof("Fail to convert joined volume information: %v", err)
 		}
 	}
 	// find out the delta volumes
-	newVolumes, deletedVolumes = dn.UpdateVolumes(volumeInfos)
+	newVolumes, deletedVolumes, changeRO := dn.UpdateVolumes(volumeInfos)
 	for _, v := range newVolumes {
 		t.RegisterVolumeLayout(v, dn)
 	}
+	// a volume marked readonly or writable again
+	for _, v := range changeRO {
+		t.RegisterVolumeLayout(v, dn)
+	}
 	for _, v := range deletedVolumes {
 		t.UnRegisterVolumeLayout(v, dn)
 	}
@@ -230,6 +271,7 @@ func (t *Topology) IncrementalSyncDataNodeRegistration(newVolumes, deletedVolume
 			glog.V(0).Infof("NewVolumeInfoFromShort %v: %v", v, err)
 			continue
 		}
+		vi.DiskType = dn.DiskType
 		newVis = append(newVis, vi)
 	}
 	for _, v := range deletedVolumes {
@@ -238,6 +280,7 @@ func (t *Topology) IncrementalSyncDataNodeRegistration(newVolumes, deletedVolume
 			glog.V(0).Infof("NewVolumeInfoFromShort %v: %v", v, err)
 			continue
 		}
+		vi.DiskType = dn.DiskType
 		oldVis = append(oldVis, vi)
 	}
 	dn.DeltaUpdateVolumes(newVis, oldVis)
@@ -6,6 +6,7 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/storage"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 
 	"testing"
 )
@@ -75,7 +76,7 @@ func TestHandlingVolumeServerHeartbeat(t *testing.T) {
 		topo.SyncDataNodeRegistration(volumeMessages, dn)
 
 		//rp, _ := storage.NewReplicaPlacementFromString("000")
-		//layout := topo.GetVolumeLayout("", rp, needle.EMPTY_TTL)
+		//layout := topo.GetVolumeLayout("", rp, needle.EMPTY_TTL, types.HardDriveType, false)
 		//assert(t, "writables", len(layout.writables), volumeCount)
 
 		assert(t, "activeVolumeCount1", int(topo.activeVolumeCount), volumeCount)
@@ -96,7 +97,7 @@ func TestHandlingVolumeServerHeartbeat(t *testing.T) {
 			nil,
 			dn)
 		rp, _ := super_block.NewReplicaPlacementFromString("000")
-		layout := topo.GetVolumeLayout("", rp, needle.EMPTY_TTL)
+		layout := topo.GetVolumeLayout("", rp, needle.EMPTY_TTL, types.HardDriveType, false)
 		assert(t, "writables after repeated add", len(layout.writables), volumeCount)
 
 		assert(t, "activeVolumeCount1", int(topo.activeVolumeCount), volumeCount)
@@ -174,3 +175,77 @@ func TestAddRemoveVolume(t *testing.T) {
 	}
 
 }
+
+func TestVolumeReadOnlyChange(t *testing.T) {
+	topo := NewTopology("weedfs", sequence.NewMemorySequencer(), 32*1024, 5, false)
+
+	dc := topo.GetOrCreateDataCenter("dc1")
+	rack := dc.GetOrCreateRack("rack1")
+	dn := rack.GetOrCreateDataNode("127.0.0.1", 34534, "127.0.0.1", 25)
+
+	heartbeat := func(readOnlyVolumeId uint32) {
+		var volumeMessages []*master_pb.VolumeInformationMessage
+		for k := 1; k <= 3; k++ {
+			volumeMessages = append(volumeMessages, &master_pb.VolumeInformationMessage{
+				Id:       uint32(k),
+				Size:     uint64(25432),
+				ReadOnly: uint32(k) == readOnlyVolumeId,
+				Version:  uint32(needle.CurrentVersion),
+			})
+		}
+		topo.SyncDataNodeRegistration(volumeMessages, dn)
+	}
+
+	rp, _ := super_block.NewReplicaPlacementFromString("000")
+	layout := topo.GetVolumeLayout("", rp, needle.EMPTY_TTL, types.HardDriveType, false)
+
+	heartbeat(0)
+	assert(t, "writables", len(layout.writables), 3)
+
+	heartbeat(2)
+	assert(t, "writables after marked readonly", len(layout.writables), 2)
+	assert(t, "activeVolumeCount after marked readonly", int(topo.activeVolumeCount), 2)
+
+	heartbeat(0)
+	assert(t, "writables after marked writable", len(layout.writables), 3)
+	assert(t, "activeVolumeCount after marked writable", int(topo.activeVolumeCount), 3)
+
+	if topo.FindDataNode("127.0.0.1:34534") != dn {
+		t.Fatalf("data node not found")
+	}
+	topo.SetVolumeReadOnly(dn, needle.VolumeId(3), true)
+	assert(t, "writables after set readonly", len(layout.writables), 2)
+}
+
+func TestAppendOnlyVolumeLayout(t *testing.T) {
+	topo := NewTopology("weedfs", sequence.NewMemorySequencer(), 32*1024, 5, false)
+
+	dc := topo.GetOrCreateDataCenter("dc1")
+	rack := dc.GetOrCreateRack("rack1")
+	dn := rack.GetOrCreateDataNode("127.0.0.1", 34534, "127.0.0.1", 25)
+
+	var volumeMessages []*master_pb.VolumeInformationMessage
+	for k := 1; k <= 3; k++ {
+		volumeMessages = append(volumeMessages, &master_pb.VolumeInformationMessage{
+			Id:         uint32(k),
+			Size:       uint64(25432),
+			Version:    uint32(needle.CurrentVersion),
+			AppendOnly: k == 3,
+		})
+	}
+	topo.SyncDataNodeRegistration(volumeMessages, dn)
+
+	rp, _ := super_block.NewReplicaPlacementFromString("000")
+	layout := topo.GetVolumeLayout("", rp, needle.EMPTY_TTL, types.HardDriveType, false)
+	appendOnlyLayout := topo.GetVolumeLayout("", rp, needle.EMPTY_TTL, types.HardDriveType, true)
+	assert(t, "writables", len(layout.writables), 2)
+	assert(t, "append only writables", len(appendOnlyLayout.writables), 1)
+
+	_, _, _, err := topo.PickForWrite(1, &VolumeGrowOption{ReplicaPlacement: rp, Ttl: needle.EMPTY_TTL, DiskType: types.HardDriveType, AppendOnly: true})
+	if err != nil {
+		t.Fatalf("pick append only volume: %v", err)
+	}
+	if appendOnlyLayout.Lookup(3) == nil || layout.Lookup(3) != nil {
+		t.Errorf("volume 3 is not in the append only layout")
+	}
+}
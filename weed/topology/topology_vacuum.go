@@ -2,11 +2,16 @@ package topology
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
 
+	"github.com/chrislusf/seaweedfs/weed/stats"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 
 	"github.com/chrislusf/seaweedfs/weed/glog"
@@ -14,9 +19,84 @@ import (
 	"github.com/chrislusf/seaweedfs/weed/pb/volume_server_pb"
 )
 
+var ErrVacuumInProgress = errors.New("a vacuum is already in progress")
+
+// VacuumOption selects the volumes to vacuum
+type VacuumOption struct {
+	// 0 for the garbage threshold of each collection
+	GarbageThreshold float64
+	// 0 for all the volumes
+	VolumeId needle.VolumeId
+	// empty for all the collections
+	Collections []string
+	// only check the garbage of the volumes
+	DryRun bool
+}
+
+func (option VacuumOption) hasCollection(collection string) bool {
+	if len(option.Collections) == 0 {
+		return true
+	}
+	for _, c := range option.Collections {
+		if c == collection {
+			return true
+		}
+	}
+	return false
+}
+
+// VacuumResult is a volume with its garbage above the threshold, vacuumed unless in a dry run.
+// The bytes are of all the replicas together.
+type VacuumResult struct {
+	VolumeId       needle.VolumeId
+	Collection     string
+	GarbageRatio   float64
+	GarbageBytes   uint64
+	Vacuumed       bool
+	ReclaimedBytes uint64
+	Error          error
+}
+
+// ParseCollectionGarbageThresholds parses the <collection>=<garbage ratio> thresholds
+func ParseCollectionGarbageThresholds(values []string) (map[string]float64, error) {
+	thresholds := make(map[string]float64)
+	for _, value := range values {
+		i := strings.LastIndex(value, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("expecting <collection>=<garbage ratio>, got %q", value)
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(value[i+1:]), 64)
+		if err != nil || threshold <= 0 {
+			return nil, fmt.Errorf("invalid garbage threshold %q of collection %q", value[i+1:], value[:i])
+		}
+		thresholds[strings.TrimSpace(value[:i])] = threshold
+	}
+	return thresholds, nil
+}
+
+// SetGarbageThresholds sets the garbage ratio above which the volumes are vacuumed, overridden for some collections
+func (t *Topology) SetGarbageThresholds(garbageThreshold float64, collectionThresholds map[string]float64) {
+	t.garbageThreshold = garbageThreshold
+	t.collectionGarbageThresholds = collectionThresholds
+}
+
+func (t *Topology) CollectionGarbageThreshold(collection string) float64 {
+	if threshold, found := t.collectionGarbageThresholds[collection]; found {
+		return threshold
+	}
+	return t.garbageThreshold
+}
+
+type vacuumCheck struct {
+	index        int
+	garbageRatio float64
+	garbageBytes uint64
+}
+
+// batchVacuumVolumeCheck returns the replicas with their garbage above the threshold, with the highest garbage ratio and their garbage bytes
 func batchVacuumVolumeCheck(grpcDialOption grpc.DialOption, vl *VolumeLayout, vid needle.VolumeId,
-	locationlist *VolumeLocationList, garbageThreshold float64) (*VolumeLocationList, bool) {
-	ch := make(chan int, locationlist.Length())
+	locationlist *VolumeLocationList, garbageThreshold float64) (vacuumLocationList *VolumeLocationList, needVacuum bool, garbageRatio float64, garbageBytes uint64) {
+	ch := make(chan vacuumCheck, locationlist.Length())
 	errCount := int32(0)
 	for index, dn := range locationlist.list {
 		go func(index int, url string, vid needle.VolumeId) {
@@ -26,13 +106,13 @@ func batchVacuumVolumeCheck(grpcDialOption grpc.DialOption, vl *VolumeLayout, vi
 				})
 				if err != nil {
 					atomic.AddInt32(&errCount, 1)
-					ch <- -1
+					ch <- vacuumCheck{index: -1}
 					return err
 				}
 				if resp.GarbageRatio >= garbageThreshold {
-					ch <- index
+					ch <- vacuumCheck{index: index, garbageRatio: resp.GarbageRatio, garbageBytes: resp.GarbageBytes}
 				} else {
-					ch <- -1
+					ch <- vacuumCheck{index: -1}
 				}
 				return nil
 			})
@@ -41,18 +121,22 @@ func batchVacuumVolumeCheck(grpcDialOption grpc.DialOption, vl *VolumeLayout, vi
 			}
 		}(index, dn.Url(), vid)
 	}
-	vacuumLocationList := NewVolumeLocationList()
+	vacuumLocationList = NewVolumeLocationList()
 	for range locationlist.list {
 		select {
-		case index := <-ch:
-			if index != -1 {
-				vacuumLocationList.list = append(vacuumLocationList.list, locationlist.list[index])
+		case check := <-ch:
+			if check.index != -1 {
+				vacuumLocationList.list = append(vacuumLocationList.list, locationlist.list[check.index])
+				if check.garbageRatio > garbageRatio {
+					garbageRatio = check.garbageRatio
+				}
+				garbageBytes += check.garbageBytes
 			}
 		case <-time.After(30 * time.Minute):
-			return vacuumLocationList, false
+			return vacuumLocationList, false, garbageRatio, garbageBytes
 		}
 	}
-	return vacuumLocationList, errCount == 0 && len(vacuumLocationList.list) > 0
+	return vacuumLocationList, errCount == 0 && len(vacuumLocationList.list) > 0, garbageRatio, garbageBytes
 }
 func batchVacuumVolumeCompact(grpcDialOption grpc.DialOption, vl *VolumeLayout, vid needle.VolumeId,
 	locationlist *VolumeLocationList, preallocate int64) bool {
@@ -91,8 +175,8 @@ func batchVacuumVolumeCompact(grpcDialOption grpc.DialOption, vl *VolumeLayout,
 	}
 	return isVacuumSuccess
 }
-func batchVacuumVolumeCommit(grpcDialOption grpc.DialOption, vl *VolumeLayout, vid needle.VolumeId, locationlist *VolumeLocationList) bool {
-	isCommitSuccess := true
+func batchVacuumVolumeCommit(grpcDialOption grpc.DialOption, vl *VolumeLayout, vid needle.VolumeId, locationlist *VolumeLocationList) (isCommitSuccess bool, reclaimedBytes uint64) {
+	isCommitSuccess = true
 	isReadOnly := false
 	for _, dn := range locationlist.list {
 		glog.V(0).Infoln("Start Committing vacuum", vid, "on", dn.Url())
@@ -100,10 +184,14 @@ func batchVacuumVolumeCommit(grpcDialOption grpc.DialOption, vl *VolumeLayout, v
 			resp, err := volumeServerClient.VacuumVolumeCommit(context.Background(), &volume_server_pb.VacuumVolumeCommitRequest{
 				VolumeId: uint32(vid),
 			})
+			if err != nil {
+				return err
+			}
 			if resp.IsReadOnly {
 				isReadOnly = true
 			}
-			return err
+			reclaimedBytes += resp.ReclaimedBytes
+			return nil
 		})
 		if err != nil {
 			glog.Errorf("Error when committing vacuum %d on %s: %v", vid, dn.Url(), err)
@@ -117,7 +205,7 @@ func batchVacuumVolumeCommit(grpcDialOption grpc.DialOption, vl *VolumeLayout, v
 			vl.SetVolumeAvailable(dn, vid, isReadOnly)
 		}
 	}
-	return isCommitSuccess
+	return isCommitSuccess, reclaimedBytes
 }
 func batchVacuumVolumeCleanup(grpcDialOption grpc.DialOption, vl *VolumeLayout, vid needle.VolumeId, locationlist *VolumeLocationList) {
 	for _, dn := range locationlist.list {
@@ -136,36 +224,65 @@ func batchVacuumVolumeCleanup(grpcDialOption grpc.DialOption, vl *VolumeLayout,
 	}
 }
 
-func (t *Topology) Vacuum(grpcDialOption grpc.DialOption, garbageThreshold float64, preallocate int64) int {
+// Vacuum compacts the volumes with their garbage ratio above the threshold, or only checks them in a dry run
+func (t *Topology) Vacuum(grpcDialOption grpc.DialOption, option VacuumOption, preallocate int64) ([]*VacuumResult, error) {
 
-	// if there is vacuum going on, return immediately
-	swapped := atomic.CompareAndSwapInt64(&t.vacuumLockCounter, 0, 1)
-	if !swapped {
-		return 0
+	if !option.DryRun {
+		// if there is vacuum going on, return immediately
+		swapped := atomic.CompareAndSwapInt64(&t.vacuumLockCounter, 0, 1)
+		if !swapped {
+			return nil, ErrVacuumInProgress
+		}
+		defer atomic.StoreInt64(&t.vacuumLockCounter, 0)
 	}
-	defer atomic.StoreInt64(&t.vacuumLockCounter, 0)
 
 	// now only one vacuum process going on
 
-	glog.V(1).Infof("Start vacuum on demand with threshold: %f", garbageThreshold)
+	glog.V(1).Infof("Start vacuum on demand with threshold: %f, volume: %d, collections: %v, dry run: %v",
+		option.GarbageThreshold, option.VolumeId, option.Collections, option.DryRun)
+	var results []*VacuumResult
 	for _, col := range t.collectionMap.Items() {
 		c := col.(*Collection)
+		if !option.hasCollection(c.Name) {
+			continue
+		}
+		garbageThreshold := option.GarbageThreshold
+		if garbageThreshold <= 0 {
+			garbageThreshold = t.CollectionGarbageThreshold(c.Name)
+		}
 		for _, vl := range c.storageType2VolumeLayout.Items() {
 			if vl != nil {
 				volumeLayout := vl.(*VolumeLayout)
-				vacuumOneVolumeLayout(grpcDialOption, volumeLayout, c, garbageThreshold, preallocate)
+				// compacting the append only volumes would drop the deleted needles, which should never exist
+				if volumeLayout.appendOnly {
+					continue
+				}
+				results = append(results, vacuumOneVolumeLayout(grpcDialOption, volumeLayout, c, garbageThreshold, preallocate, option)...)
 			}
 		}
 	}
-	return 0
+
+	if !option.DryRun {
+		var reclaimedBytes uint64
+		for _, result := range results {
+			stats.MasterVacuumReclaimedBytesCounter.WithLabelValues(result.Collection).Add(float64(result.ReclaimedBytes))
+			reclaimedBytes += result.ReclaimedBytes
+		}
+		stats.MasterVacuumRunReclaimedBytesGauge.Set(float64(reclaimedBytes))
+		glog.V(0).Infof("vacuumed %d volumes, reclaimed %d bytes", len(results), reclaimedBytes)
+	}
+
+	return results, nil
 }
 
-func vacuumOneVolumeLayout(grpcDialOption grpc.DialOption, volumeLayout *VolumeLayout, c *Collection, garbageThreshold float64, preallocate int64) {
+func vacuumOneVolumeLayout(grpcDialOption grpc.DialOption, volumeLayout *VolumeLayout, c *Collection, garbageThreshold float64, preallocate int64, option VacuumOption) (results []*VacuumResult) {
 
 	volumeLayout.accessLock.RLock()
 	tmpMap := make(map[needle.VolumeId]*VolumeLocationList)
 	for vid, locationList := range volumeLayout.vid2location {
-		tmpMap[vid] = locationList
+		if option.VolumeId == 0 || vid == option.VolumeId {
+			tmpMap[vid] = locationList
+		}
 	}
 	volumeLayout.accessLock.RUnlock()
 
@@ -180,13 +297,33 @@ func vacuumOneVolumeLayout(grpcDialOption grpc.DialOption, volumeLayout *VolumeL
 		}
 
 		glog.V(2).Infof("check vacuum on collection:%s volume:%d", c.Name, vid)
-		if vacuumLocationList, needVacuum := batchVacuumVolumeCheck(
-			grpcDialOption, volumeLayout, vid, locationList, garbageThreshold); needVacuum {
-			if batchVacuumVolumeCompact(grpcDialOption, volumeLayout, vid, vacuumLocationList, preallocate) {
-				batchVacuumVolumeCommit(grpcDialOption, volumeLayout, vid, vacuumLocationList)
+		vacuumLocationList, needVacuum, garbageRatio, garbageBytes := batchVacuumVolumeCheck(
+			grpcDialOption, volumeLayout, vid, locationList, garbageThreshold)
+		if !needVacuum {
+			continue
+		}
+		result := &VacuumResult{
+			VolumeId:     vid,
+			Collection:   c.Name,
+			GarbageRatio: garbageRatio,
+			GarbageBytes: garbageBytes,
+		}
+		results = append(results, result)
+		if option.DryRun {
+			continue
+		}
+		if batchVacuumVolumeCompact(grpcDialOption, volumeLayout, vid, vacuumLocationList, preallocate) {
+			var isCommitSuccess bool
+			isCommitSuccess, result.ReclaimedBytes = batchVacuumVolumeCommit(grpcDialOption, volumeLayout, vid, vacuumLocationList)
+			if isCommitSuccess {
+				result.Vacuumed = true
 			} else {
-				batchVacuumVolumeCleanup(grpcDialOption, volumeLayout, vid, vacuumLocationList)
+				result.Error = fmt.Errorf("commit vacuumed volume %d failed on some of its replicas", vid)
 			}
+		} else {
+			batchVacuumVolumeCleanup(grpcDialOption, volumeLayout, vid, vacuumLocationList)
+			result.Error = fmt.Errorf("vacuum volume %d failed on some of its replicas", vid)
 		}
 	}
+	return results
 }
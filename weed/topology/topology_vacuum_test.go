@@ -0,0 +1,44 @@
+package topology
+
+import (
+	"testing"
+
+	"github.com/chrislusf/seaweedfs/weed/sequence"
+)
+
+func TestCollectionGarbageThresholds(t *testing.T) {
+	thresholds, err := ParseCollectionGarbageThresholds([]string{"logs=0.1", " images = 0.5", "=0.2"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	topo := NewTopology("weedfs", sequence.NewMemorySequencer(), 32*1024, 5, false)
+	topo.SetGarbageThresholds(0.3, thresholds)
+	for collection, expected := range map[string]float64{
+		"logs":   0.1,
+		"images": 0.5,
+		"":       0.2,
+		"other":  0.3,
+	} {
+		if threshold := topo.CollectionGarbageThreshold(collection); threshold != expected {
+			t.Errorf("collection %q threshold %v, expecting %v", collection, threshold, expected)
+		}
+	}
+
+	for _, invalid := range []string{"logs", "logs=", "logs=abc", "logs=0", "logs=-0.1"} {
+		if _, err := ParseCollectionGarbageThresholds([]string{invalid}); err == nil {
+			t.Errorf("parsed %q", invalid)
+		}
+	}
+}
+
+func TestVacuumOptionCollections(t *testing.T) {
+	all := VacuumOption{}
+	if !all.hasCollection("") || !all.hasCollection("logs") {
+		t.Errorf("all the collections are not selected by default")
+	}
+	defaultOnly := VacuumOption{Collections: []string{""}}
+	if !defaultOnly.hasCollection("") || defaultOnly.hasCollection("logs") {
+		t.Errorf("the empty collection is not selected alone")
+	}
+}
@@ -7,11 +7,13 @@ import (
 
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 	"github.com/chrislusf/seaweedfs/weed/util"
 
 	"google.golang.org/grpc"
 
 	"github.com/chrislusf/seaweedfs/weed/glog"
+	"github.com/chrislusf/seaweedfs/weed/stats"
 	"github.com/chrislusf/seaweedfs/weed/storage"
 )
 
@@ -32,14 +34,21 @@ type VolumeGrowOption struct {
 	Rack               string
 	DataNode           string
 	MemoryMapMaxSizeMb uint32
+	DiskType           types.DiskType
+	AppendOnly         bool
 }
 
 type VolumeGrowth struct {
 	accessLock sync.Mutex
+	// when the replica placement can not be satisfied, spread the replicas as much as the topology allows,
+	// instead of failing the allocation
+	BestEffortPlacement bool
+	// decides how many volumes to grow automatically, the default strategy if nil
+	Strategy VolumeGrowthStrategy
 }
 
 func (o *VolumeGrowOption) String() string {
-	return fmt.Sprintf("Collection:%s, ReplicaPlacement:%v, Ttl:%v, DataCenter:%s, Rack:%s, DataNode:%s", o.Collection, o.ReplicaPlacement, o.Ttl, o.DataCenter, o.Rack, o.DataNode)
+	return fmt.Sprintf("Collection:%s, ReplicaPlacement:%v, Ttl:%v, DataCenter:%s, Rack:%s, DataNode:%s, DiskType:%s, AppendOnly:%v", o.Collection, o.ReplicaPlacement, o.Ttl, o.DataCenter, o.Rack, o.DataNode, o.DiskType, o.AppendOnly)
 }
 
 func NewDefaultVolumeGrowth() *VolumeGrowth {
@@ -48,7 +57,7 @@ func NewDefaultVolumeGrowth() *VolumeGrowth {
 
 // one replication type may need rp.GetCopyCount() actual volumes
 // given copyCount, how many logical volumes to create
-func (vg *VolumeGrowth) findVolumeCount(copyCount int) (count int) {
+func findVolumeCount(copyCount int) (count int) {
 	v := util.GetViper()
 	v.SetDefault("master.volume_growth.copy_1", 7)
 	v.SetDefault("master.volume_growth.copy_2", 6)
@@ -68,10 +77,13 @@ func (vg *VolumeGrowth) findVolumeCount(copyCount int) (count int) {
 }
 
 func (vg *VolumeGrowth) AutomaticGrowByType(option *VolumeGrowOption, grpcDialOption grpc.DialOption, topo *Topology, targetCount int) (count int, err error) {
-	if targetCount == 0 {
-		targetCount = vg.findVolumeCount(option.ReplicaPlacement.GetCopyCount())
+	var strategy VolumeGrowthStrategy = &DefaultVolumeGrowthStrategy{}
+	if vg.Strategy != nil {
+		strategy = vg.Strategy
 	}
+	targetCount = strategy.TargetCount(topo, option, targetCount)
 	count, err = vg.GrowByCountAndType(grpcDialOption, targetCount, option, topo)
+	stats.MasterVolumeGrowthCounter.WithLabelValues(strategy.GetName()).Add(float64(count))
 	if count > 0 && count%option.ReplicaPlacement.GetCopyCount() == 0 {
 		return count, nil
 	}
@@ -105,38 +117,60 @@ func (vg *VolumeGrowth) findAndGrow(grpcDialOption grpc.DialOption, topo *Topolo
 	return len(servers), err
 }
 
+func (vg *VolumeGrowth) findEmptySlotsForOneVolume(topo *Topology, option *VolumeGrowOption) (servers []*DataNode, err error) {
+	servers, err = vg.findStrictEmptySlotsForOneVolume(topo, option)
+	if err == nil {
+		err = checkReplicaPlacement(servers, option.ReplicaPlacement)
+	}
+	if err != nil && vg.BestEffortPlacement {
+		glog.V(0).Infof("replica placement %s not satisfied: %v, spreading the replicas as much as possible", option.ReplicaPlacement, err)
+		return findBestEffortSlotsForOneVolume(topo, option)
+	}
+	return
+}
+
 // 1. find the main data node
 // 1.1 collect all data nodes that have 1 slots
 // 2.2 collect all racks that have rp.SameRackCount+1
 // 2.2 collect all data centers that have DiffRackCount+rp.SameRackCount+1
 // 2. find rest data nodes
-func (vg *VolumeGrowth) findEmptySlotsForOneVolume(topo *Topology, option *VolumeGrowOption) (servers []*DataNode, err error) {
+func (vg *VolumeGrowth) findStrictEmptySlotsForOneVolume(topo *Topology, option *VolumeGrowOption) (servers []*DataNode, err error) {
 	//find main datacenter and other data centers
 	rp := option.ReplicaPlacement
-	mainDataCenter, otherDataCenters, dc_err := topo.PickNodesByWeight(rp.DiffDataCenterCount+1, func(node Node) error {
+	freeSpace := func(node Node) int64 {
+		return freeSpaceOfDiskType(node, option.DiskType)
+	}
+	mainDataCenter, otherDataCenters, dc_err := topo.PickNodesByWeight(rp.DiffDataCenterCount+1, option.DiskType, func(node Node) error {
 		if option.DataCenter != "" && node.IsDataCenter() && node.Id() != NodeId(option.DataCenter) {
 			return fmt.Errorf("Not matching preferred data center:%s", option.DataCenter)
 		}
 		if len(node.Children()) < rp.DiffRackCount+1 {
 			return fmt.Errorf("Only has %d racks, not enough for %d.", len(node.Children()), rp.DiffRackCount+1)
 		}
-		if node.FreeSpace() < int64(rp.DiffRackCount+rp.SameRackCount+1) {
-			return fmt.Errorf("Free:%d < Expected:%d", node.FreeSpace(), rp.DiffRackCount+rp.SameRackCount+1)
+		if freeSpace(node) < int64(rp.DiffRackCount+rp.SameRackCount+1) {
+			return fmt.Errorf("Free:%d < Expected:%d", freeSpace(node), rp.DiffRackCount+rp.SameRackCount+1)
 		}
-		possibleRacksCount := 0
+		// the main rack needs rp.SameRackCount+1 free data nodes, the other racks only one
+		possibleMainRacksCount, possibleRacksCount := 0, 0
 		for _, rack := range node.Children() {
 			possibleDataNodesCount := 0
 			for _, n := range rack.Children() {
-				if n.FreeSpace() >= 1 {
+				if freeSpace(n) >= 1 {
 					possibleDataNodesCount++
 				}
 			}
 			if possibleDataNodesCount >= rp.SameRackCount+1 {
+				possibleMainRacksCount++
+			}
+			if possibleDataNodesCount >= 1 {
 				possibleRacksCount++
 			}
 		}
+		if possibleMainRacksCount < 1 {
+			return fmt.Errorf("No rack with %d free data nodes.", rp.SameRackCount+1)
+		}
 		if possibleRacksCount < rp.DiffRackCount+1 {
-			return fmt.Errorf("Only has %d racks with more than %d free data nodes, not enough for %d.", possibleRacksCount, rp.SameRackCount+1, rp.DiffRackCount+1)
+			return fmt.Errorf("Only has %d racks with free data nodes, not enough for %d.", possibleRacksCount, rp.DiffRackCount+1)
 		}
 		return nil
 	})
@@ -145,12 +179,12 @@ func (vg *VolumeGrowth) findEmptySlotsForOneVolume(topo *Topology, option *Volum
 	}
 
 	//find main rack and other racks
-	mainRack, otherRacks, rackErr := mainDataCenter.(*DataCenter).PickNodesByWeight(rp.DiffRackCount+1, func(node Node) error {
+	mainRack, otherRacks, rackErr := mainDataCenter.(*DataCenter).PickNodesByWeight(rp.DiffRackCount+1, option.DiskType, func(node Node) error {
 		if option.Rack != "" && node.IsRack() && node.Id() != NodeId(option.Rack) {
 			return fmt.Errorf("Not matching preferred rack:%s", option.Rack)
 		}
-		if node.FreeSpace() < int64(rp.SameRackCount+1) {
-			return fmt.Errorf("Free:%d < Expected:%d", node.FreeSpace(), rp.SameRackCount+1)
+		if freeSpace(node) < int64(rp.SameRackCount+1) {
+			return fmt.Errorf("Free:%d < Expected:%d", freeSpace(node), rp.SameRackCount+1)
 		}
 		if len(node.Children()) < rp.SameRackCount+1 {
 			// a bit faster way to test free racks
@@ -158,7 +192,7 @@ func (vg *VolumeGrowth) findEmptySlotsForOneVolume(topo *Topology, option *Volum
 		}
 		possibleDataNodesCount := 0
 		for _, n := range node.Children() {
-			if n.FreeSpace() >= 1 {
+			if freeSpace(n) >= 1 {
 				possibleDataNodesCount++
 			}
 		}
@@ -172,12 +206,12 @@ func (vg *VolumeGrowth) findEmptySlotsForOneVolume(topo *Topology, option *Volum
 	}
 
 	//find main rack and other racks
-	mainServer, otherServers, serverErr := mainRack.(*Rack).PickNodesByWeight(rp.SameRackCount+1, func(node Node) error {
+	mainServer, otherServers, serverErr := mainRack.(*Rack).PickNodesByWeight(rp.SameRackCount+1, option.DiskType, func(node Node) error {
 		if option.DataNode != "" && node.IsDataNode() && node.Id() != NodeId(option.DataNode) {
 			return fmt.Errorf("Not matching preferred data node:%s", option.DataNode)
 		}
-		if node.FreeSpace() < 1 {
-			return fmt.Errorf("Free:%d < Expected:%d", node.FreeSpace(), 1)
+		if freeSpace(node) < 1 {
+			return fmt.Errorf("Free:%d < Expected:%d", freeSpace(node), 1)
 		}
 		return nil
 	})
@@ -190,16 +224,16 @@ func (vg *VolumeGrowth) findEmptySlotsForOneVolume(topo *Topology, option *Volum
 		servers = append(servers, server.(*DataNode))
 	}
 	for _, rack := range otherRacks {
-		r := rand.Int63n(rack.FreeSpace())
-		if server, e := rack.ReserveOneVolume(r); e == nil {
+		r := rand.Int63n(freeSpace(rack))
+		if server, e := rack.ReserveOneVolume(r, option.DiskType); e == nil {
 			servers = append(servers, server)
 		} else {
 			return servers, e
 		}
 	}
 	for _, datacenter := range otherDataCenters {
-		r := rand.Int63n(datacenter.FreeSpace())
-		if server, e := datacenter.ReserveOneVolume(r); e == nil {
+		r := rand.Int63n(freeSpace(datacenter))
+		if server, e := datacenter.ReserveOneVolume(r, option.DiskType); e == nil {
 			servers = append(servers, server)
 		} else {
 			return servers, e
@@ -208,6 +242,115 @@ func (vg *VolumeGrowth) findEmptySlotsForOneVolume(topo *Topology, option *Volum
 	return
 }
 
+// checkReplicaPlacement verifies the servers, the first one being the main server, are spread as the replica placement requires
+func checkReplicaPlacement(servers []*DataNode, rp *super_block.ReplicaPlacement) error {
+	if len(servers) != rp.GetCopyCount() {
+		return fmt.Errorf("found %d servers for %d copies", len(servers), rp.GetCopyCount())
+	}
+	mainServer := servers[0]
+	dataCenters, racks, nodes := make(map[*DataCenter]bool), make(map[*Rack]bool), make(map[*DataNode]bool)
+	mainRackServers := 0
+	for _, server := range servers {
+		if nodes[server] {
+			return fmt.Errorf("more than one copy on %s", server.Id())
+		}
+		nodes[server] = true
+		dataCenters[server.GetDataCenter()] = true
+		if server.GetDataCenter() == mainServer.GetDataCenter() {
+			racks[server.GetRack()] = true
+		}
+		if server.GetRack() == mainServer.GetRack() {
+			mainRackServers++
+		}
+	}
+	if len(dataCenters) != rp.DiffDataCenterCount+1 {
+		return fmt.Errorf("copies in %d data centers, expected %d", len(dataCenters), rp.DiffDataCenterCount+1)
+	}
+	if len(racks) != rp.DiffRackCount+1 {
+		return fmt.Errorf("copies in %d racks of data center %s, expected %d", len(racks), mainServer.GetDataCenter().Id(), rp.DiffRackCount+1)
+	}
+	if mainRackServers != rp.SameRackCount+1 {
+		return fmt.Errorf("%d copies in rack %s, expected %d", mainRackServers, mainServer.GetRack().Id(), rp.SameRackCount+1)
+	}
+	return nil
+}
+
+// findBestEffortSlotsForOneVolume places each copy where the replica placement expects it when possible,
+// otherwise on any other data node of the disk type with a free slot, never two copies on the same data node
+func findBestEffortSlotsForOneVolume(topo *Topology, option *VolumeGrowOption) (servers []*DataNode, err error) {
+	rp := option.ReplicaPlacement
+
+	var candidates []*DataNode
+	for _, dc := range topo.Children() {
+		for _, rack := range dc.Children() {
+			for _, n := range rack.Children() {
+				if n.FreeSpace() >= 1 && n.(*DataNode).DiskType == option.DiskType {
+					candidates = append(candidates, n.(*DataNode))
+				}
+			}
+		}
+	}
+
+	picked := make(map[*DataNode]bool)
+	pick := func(preferences ...func(dn *DataNode) bool) *DataNode {
+		for _, preferred := range preferences {
+			var best *DataNode
+			for _, dn := range candidates {
+				if picked[dn] || !preferred(dn) {
+					continue
+				}
+				if best == nil || dn.FreeSpace() > best.FreeSpace() {
+					best = dn
+				}
+			}
+			if best != nil {
+				picked[best] = true
+				servers = append(servers, best)
+				return best
+			}
+		}
+		return nil
+	}
+	anyNode := func(dn *DataNode) bool { return true }
+
+	mainServer := pick(func(dn *DataNode) bool {
+		return (option.DataCenter == "" || dn.GetDataCenter().Id() == NodeId(option.DataCenter)) &&
+			(option.Rack == "" || dn.GetRack().Id() == NodeId(option.Rack)) &&
+			(option.DataNode == "" || dn.Id() == NodeId(option.DataNode))
+	})
+	if mainServer == nil {
+		return nil, fmt.Errorf("no data node with a free slot matching %s", option)
+	}
+	mainDataCenter, mainRack := mainServer.GetDataCenter(), mainServer.GetRack()
+
+	usedDataCenters := map[*DataCenter]bool{mainDataCenter: true}
+	usedRacks := map[*Rack]bool{mainRack: true}
+	newDataCenter := func(dn *DataNode) bool { return !usedDataCenters[dn.GetDataCenter()] }
+	newRack := func(dn *DataNode) bool { return !usedRacks[dn.GetRack()] }
+	newRackInMainDataCenter := func(dn *DataNode) bool { return newRack(dn) && dn.GetDataCenter() == mainDataCenter }
+	inMainRack := func(dn *DataNode) bool { return dn.GetRack() == mainRack }
+	inMainDataCenter := func(dn *DataNode) bool { return dn.GetDataCenter() == mainDataCenter }
+
+	for i := 0; i < rp.DiffDataCenterCount; i++ {
+		if dn := pick(newDataCenter, newRack, anyNode); dn != nil {
+			usedDataCenters[dn.GetDataCenter()], usedRacks[dn.GetRack()] = true, true
+		}
+	}
+	for i := 0; i < rp.DiffRackCount; i++ {
+		if dn := pick(newRackInMainDataCenter, newRack, anyNode); dn != nil {
+			usedRacks[dn.GetRack()] = true
+		}
+	}
+	for i := 0; i < rp.SameRackCount; i++ {
+		pick(inMainRack, inMainDataCenter, anyNode)
+	}
+
+	if len(servers) < rp.GetCopyCount() {
+		return nil, fmt.Errorf("only %d data nodes with a free slot for %d copies", len(servers), rp.GetCopyCount())
+	}
+	return servers, nil
+}
+
 func (vg *VolumeGrowth) grow(grpcDialOption grpc.DialOption, topo *Topology, vid needle.VolumeId, option *VolumeGrowOption, servers ...*DataNode) error {
 	for _, server := range servers {
 		if err := AllocateVolume(server, grpcDialOption, vid, option); err == nil {
@@ -218,6 +361,8 @@ func (vg *VolumeGrowth) grow(grpcDialOption grpc.DialOption, topo *Topology, vid
 				ReplicaPlacement: option.ReplicaPlacement,
 				Ttl:              option.Ttl,
 				Version:          needle.CurrentVersion,
+				DiskType:         option.DiskType,
+				AppendOnly:       option.AppendOnly,
 			}
 			server.AddOrUpdateVolume(vi)
 			topo.RegisterVolumeLayout(vi, server)
@@ -0,0 +1,212 @@
+package topology
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chrislusf/seaweedfs/weed/util"
+)
+
+// VolumeGrowthStrategy decides how many logical volumes to grow when no volume is writable for the option.
+// The strategies register themselves into VolumeGrowthStrategies. The master uses the one named by
+// master.volume_growth.strategy, initialized with the master.volume_growth.<name>.* settings.
+type VolumeGrowthStrategy interface {
+	GetName() string
+	Initialize(configuration util.Configuration, prefix string) error
+	// TargetCount returns the logical volumes to grow, requestedCount is the writable volume count asked by the client, 0 if not asked
+	TargetCount(topo *Topology, option *VolumeGrowOption, requestedCount int) int
+}
+
+var (
+	VolumeGrowthStrategies []VolumeGrowthStrategy
+)
+
+func init() {
+	VolumeGrowthStrategies = append(VolumeGrowthStrategies,
+		&DefaultVolumeGrowthStrategy{},
+		&FixedVolumeGrowthStrategy{},
+		&WriteRateVolumeGrowthStrategy{},
+		&CapacityWatermarkVolumeGrowthStrategy{},
+	)
+}
+
+// LoadVolumeGrowthStrategy returns the strategy named by master.volume_growth.strategy, the default one if not set
+func LoadVolumeGrowthStrategy(configuration util.Configuration) (VolumeGrowthStrategy, error) {
+	configuration.SetDefault("master.volume_growth.strategy", "default")
+	name := configuration.GetString("master.volume_growth.strategy")
+	for _, strategy := range VolumeGrowthStrategies {
+		if strategy.GetName() == name {
+			if err := strategy.Initialize(configuration, "master.volume_growth."+name+"."); err != nil {
+				return nil, fmt.Errorf("initialize volume growth strategy %s: %v", name, err)
+			}
+			return strategy, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown volume growth strategy %s", name)
+}
+
+// DefaultVolumeGrowthStrategy grows the volumes asked by the client, or the copy_x volumes for the copy count
+type DefaultVolumeGrowthStrategy struct{}
+
+func (s *DefaultVolumeGrowthStrategy) GetName() string {
+	return "default"
+}
+
+func (s *DefaultVolumeGrowthStrategy) Initialize(configuration util.Configuration, prefix string) error {
+	return nil
+}
+
+func (s *DefaultVolumeGrowthStrategy) TargetCount(topo *Topology, option *VolumeGrowOption, requestedCount int) int {
+	if requestedCount > 0 {
+		return requestedCount
+	}
+	return findVolumeCount(option.ReplicaPlacement.GetCopyCount())
+}
+
+// FixedVolumeGrowthStrategy always grows count volumes, preallocating ahead of the write bursts
+type FixedVolumeGrowthStrategy struct {
+	count int
+}
+
+func (s *FixedVolumeGrowthStrategy) GetName() string {
+	return "fixed"
+}
+
+func (s *FixedVolumeGrowthStrategy) Initialize(configuration util.Configuration, prefix string) error {
+	configuration.SetDefault(prefix+"count", 7)
+	if s.count = configuration.GetInt(prefix + "count"); s.count <= 0 {
+		return fmt.Errorf("%scount should be positive", prefix)
+	}
+	return nil
+}
+
+func (s *FixedVolumeGrowthStrategy) TargetCount(topo *Topology, option *VolumeGrowOption, requestedCount int) int {
+	return s.count
+}
+
+// WriteRateVolumeGrowthStrategy follows how fast the writable volumes of a layout fill up.
+// It grows min_count volumes, doubled up to max_count each time the previous growth of the layout
+// was less than fast_fill_seconds ago, and halved back otherwise.
+// All the layouts together grow at most max_per_minute volumes a minute, beyond which one volume is grown to keep the writes going.
+type WriteRateVolumeGrowthStrategy struct {
+	minCount     int
+	maxCount     int
+	maxPerMinute int
+	fastFill     time.Duration
+
+	sync.Mutex
+	layouts map[string]*layoutGrowth
+	growths []volumeGrowthRecord
+}
+
+type layoutGrowth struct {
+	lastTime  time.Time
+	lastCount int
+}
+
+type volumeGrowthRecord struct {
+	time  time.Time
+	count int
+}
+
+func (s *WriteRateVolumeGrowthStrategy) GetName() string {
+	return "write_rate"
+}
+
+func (s *WriteRateVolumeGrowthStrategy) Initialize(configuration util.Configuration, prefix string) error {
+	configuration.SetDefault(prefix+"min_count", 1)
+	configuration.SetDefault(prefix+"max_count", 16)
+	configuration.SetDefault(prefix+"max_per_minute", 32)
+	configuration.SetDefault(prefix+"fast_fill_seconds", 300)
+	s.minCount = configuration.GetInt(prefix + "min_count")
+	s.maxCount = configuration.GetInt(prefix + "max_count")
+	s.maxPerMinute = configuration.GetInt(prefix + "max_per_minute")
+	s.fastFill = time.Duration(configuration.GetInt(prefix+"fast_fill_seconds")) * time.Second
+	if s.minCount <= 0 || s.maxCount < s.minCount {
+		return fmt.Errorf("expecting 0 < %smin_count <= %smax_count", prefix, prefix)
+	}
+	s.layouts = make(map[string]*layoutGrowth)
+	return nil
+}
+
+func (s *WriteRateVolumeGrowthStrategy) TargetCount(topo *Topology, option *VolumeGrowOption, requestedCount int) int {
+	return s.targetCount(time.Now(), option)
+}
+
+func (s *WriteRateVolumeGrowthStrategy) targetCount(now time.Time, option *VolumeGrowOption) int {
+	s.Lock()
+	defer s.Unlock()
+
+	key := fmt.Sprintf("%s/%s/%s/%s/%v", option.Collection, option.ReplicaPlacement, option.Ttl, option.DiskType, option.AppendOnly)
+	layout, found := s.layouts[key]
+	if !found {
+		layout = &layoutGrowth{}
+		s.layouts[key] = layout
+	}
+
+	count := s.minCount
+	if layout.lastCount > 0 {
+		if now.Sub(layout.lastTime) < s.fastFill {
+			count = layout.lastCount * 2
+		} else {
+			count = layout.lastCount / 2
+		}
+	}
+	if count > s.maxCount {
+		count = s.maxCount
+	}
+	if count < s.minCount {
+		count = s.minCount
+	}
+	layout.lastTime, layout.lastCount = now, count
+
+	if s.maxPerMinute > 0 {
+		grownLastMinute := 0
+		var recent []volumeGrowthRecord
+		for _, growth := range s.growths {
+			if now.Sub(growth.time) < time.Minute {
+				recent = append(recent, growth)
+				grownLastMinute += growth.count
+			}
+		}
+		s.growths = recent
+		if grownLastMinute+count > s.maxPerMinute {
+			count = s.maxPerMinute - grownLastMinute
+			if count < 1 {
+				count = 1
+			}
+		}
+		s.growths = append(s.growths, volumeGrowthRecord{time: now, count: count})
+	}
+
+	return count
+}
+
+// CapacityWatermarkVolumeGrowthStrategy grows volumes as the default strategy while there is room,
+// but only one volume at a time once the free volume slots of the disk type drop below free_percent of all its slots
+type CapacityWatermarkVolumeGrowthStrategy struct {
+	DefaultVolumeGrowthStrategy
+	freePercent int64
+}
+
+func (s *CapacityWatermarkVolumeGrowthStrategy) GetName() string {
+	return "capacity_watermark"
+}
+
+func (s *CapacityWatermarkVolumeGrowthStrategy) Initialize(configuration util.Configuration, prefix string) error {
+	configuration.SetDefault(prefix+"free_percent", 20)
+	s.freePercent = int64(configuration.GetInt(prefix + "free_percent"))
+	if s.freePercent < 0 || s.freePercent > 100 {
+		return fmt.Errorf("%sfree_percent should be between 0 and 100", prefix)
+	}
+	return nil
+}
+
+func (s *CapacityWatermarkVolumeGrowthStrategy) TargetCount(topo *Topology, option *VolumeGrowOption, requestedCount int) int {
+	maxVolumeCount := maxVolumeCountOfDiskType(topo, option.DiskType)
+	if maxVolumeCount > 0 && topo.FreeSpaceOfDiskType(option.DiskType)*100 < maxVolumeCount*s.freePercent {
+		return 1
+	}
+	return s.DefaultVolumeGrowthStrategy.TargetCount(topo, option, requestedCount)
+}
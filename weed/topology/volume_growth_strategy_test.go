@@ -0,0 +1,90 @@
+package topology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+)
+
+func TestLoadVolumeGrowthStrategy(t *testing.T) {
+	rp, _ := super_block.NewReplicaPlacementFromString("000")
+	option := &VolumeGrowOption{ReplicaPlacement: rp}
+
+	v := viper.New()
+	strategy, err := LoadVolumeGrowthStrategy(v)
+	if err != nil || strategy.GetName() != "default" {
+		t.Fatalf("default strategy: %v %v", strategy, err)
+	}
+	if count := strategy.TargetCount(nil, option, 3); count != 3 {
+		t.Errorf("default strategy with a requested count: %d", count)
+	}
+
+	v.Set("master.volume_growth.strategy", "fixed")
+	v.Set("master.volume_growth.fixed.count", 5)
+	if strategy, err = LoadVolumeGrowthStrategy(v); err != nil {
+		t.Fatalf("fixed strategy: %v", err)
+	}
+	if count := strategy.TargetCount(nil, option, 3); count != 5 {
+		t.Errorf("fixed strategy: %d", count)
+	}
+
+	v.Set("master.volume_growth.strategy", "unknown")
+	if _, err = LoadVolumeGrowthStrategy(v); err == nil {
+		t.Errorf("loaded an unknown strategy")
+	}
+}
+
+func TestWriteRateVolumeGrowthStrategy(t *testing.T) {
+	v := viper.New()
+	v.Set("master.volume_growth.strategy", "write_rate")
+	v.Set("master.volume_growth.write_rate.min_count", 2)
+	v.Set("master.volume_growth.write_rate.max_count", 8)
+	v.Set("master.volume_growth.write_rate.max_per_minute", 20)
+	v.Set("master.volume_growth.write_rate.fast_fill_seconds", 60)
+	strategy, err := LoadVolumeGrowthStrategy(v)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	s := strategy.(*WriteRateVolumeGrowthStrategy)
+
+	rp, _ := super_block.NewReplicaPlacementFromString("000")
+	option := &VolumeGrowOption{Collection: "c", ReplicaPlacement: rp}
+	other := &VolumeGrowOption{Collection: "other", ReplicaPlacement: rp}
+	start := time.Now()
+
+	for i, expected := range []struct {
+		after  time.Duration
+		option *VolumeGrowOption
+		count  int
+	}{
+		{0, option, 2},
+		{10 * time.Second, option, 4},
+		{20 * time.Second, option, 8},
+		{30 * time.Second, option, 6}, // limited by max_per_minute
+		{40 * time.Second, other, 1},  // limited by max_per_minute
+		{5 * time.Minute, option, 4},
+		{20 * time.Minute, option, 2},
+	} {
+		if count := s.targetCount(start.Add(expected.after), expected.option); count != expected.count {
+			t.Errorf("growth %d: %d volumes, expected %d", i, count, expected.count)
+		}
+	}
+}
+
+func TestCapacityWatermarkVolumeGrowthStrategy(t *testing.T) {
+	topo := setup(topologyLayout)
+	rp, _ := super_block.NewReplicaPlacementFromString("000")
+	option := &VolumeGrowOption{ReplicaPlacement: rp}
+
+	s := &CapacityWatermarkVolumeGrowthStrategy{freePercent: 0}
+	if count := s.TargetCount(topo, option, 3); count != 3 {
+		t.Errorf("above the watermark: %d", count)
+	}
+	s.freePercent = 100
+	if count := s.TargetCount(topo, option, 3); count != 1 {
+		t.Errorf("below the watermark: %d", count)
+	}
+}
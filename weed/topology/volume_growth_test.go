@@ -3,12 +3,15 @@ package topology
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
 	"github.com/chrislusf/seaweedfs/weed/sequence"
 	"github.com/chrislusf/seaweedfs/weed/storage"
 	"github.com/chrislusf/seaweedfs/weed/storage/needle"
 	"github.com/chrislusf/seaweedfs/weed/storage/super_block"
+	"github.com/chrislusf/seaweedfs/weed/storage/types"
 )
 
 var topologyLayout = `
@@ -94,13 +97,17 @@ func setup(topologyLayout string) *Topology {
 			for serverKey, serverValue := range rackMap {
 				server := NewDataNode(serverKey)
 				serverMap := serverValue.(map[string]interface{})
+				if diskType, found := serverMap["disk"]; found {
+					server.DiskType = types.ToDiskType(diskType.(string))
+				}
 				rack.LinkChildNode(server)
 				for _, v := range serverMap["volumes"].([]interface{}) {
 					m := v.(map[string]interface{})
 					vi := storage.VolumeInfo{
-						Id:      needle.VolumeId(int64(m["id"].(float64))),
-						Size:    uint64(m["size"].(float64)),
-						Version: needle.CurrentVersion}
+						Id:       needle.VolumeId(int64(m["id"].(float64))),
+						Size:     uint64(m["size"].(float64)),
+						Version:  needle.CurrentVersion,
+						DiskType: server.DiskType}
 					server.AddOrUpdateVolume(vi)
 				}
 				server.UpAdjustMaxVolumeCountDelta(int64(serverMap["limit"].(float64)))
@@ -340,3 +347,219 @@ func TestFindEmptySlotsForOneVolumeScheduleByWeight(t *testing.T) {
 		fmt.Printf("%s : %d\n", k, v)
 	}
 }
+
+var topologyLayoutForPlacement = `
+{
+  "dc1":{
+    "rack1":{
+      "server111":{"volumes":[], "limit":10},
+      "server112":{"volumes":[], "limit":10},
+      "server113":{"volumes":[], "limit":10}
+    },
+    "rack2":{
+      "server121":{"volumes":[], "limit":10},
+      "server122":{"volumes":[], "limit":10}
+    }
+  },
+  "dc2":{
+    "rack1":{
+      "server211":{"volumes":[], "limit":10}
+    }
+  }
+}
+`
+
+func placementSummary(servers []*DataNode) (dataCenters, racks int) {
+	dcSet, rackSet := make(map[*DataCenter]bool), make(map[*Rack]bool)
+	for _, server := range servers {
+		dcSet[server.GetDataCenter()] = true
+		rackSet[server.GetRack()] = true
+	}
+	return len(dcSet), len(rackSet)
+}
+
+func TestStrictReplicaPlacement(t *testing.T) {
+	topo := setup(topologyLayoutForPlacement)
+	vg := NewDefaultVolumeGrowth()
+
+	for _, replication := range []string{"000", "001", "002", "010", "011", "100", "101", "110", "012"} {
+		rp, _ := super_block.NewReplicaPlacementFromString(replication)
+		for i := 0; i < 20; i++ {
+			servers, err := vg.findEmptySlotsForOneVolume(topo, &VolumeGrowOption{ReplicaPlacement: rp})
+			if err != nil {
+				t.Fatalf("replication %s: %v", replication, err)
+			}
+			if err = checkReplicaPlacement(servers, rp); err != nil {
+				t.Fatalf("replication %s: %v", replication, err)
+			}
+		}
+	}
+
+	// not enough racks, data centers, or servers in a rack
+	for _, tt := range []struct {
+		replication string
+		rack        string
+	}{
+		{"020", ""},
+		{"200", ""},
+		{"002", "rack2"},
+	} {
+		rp, _ := super_block.NewReplicaPlacementFromString(tt.replication)
+		if servers, err := vg.findEmptySlotsForOneVolume(topo, &VolumeGrowOption{ReplicaPlacement: rp, DataCenter: "dc1", Rack: tt.rack}); err == nil {
+			t.Errorf("replication %s in rack %q should fail, got %v", tt.replication, tt.rack, servers)
+		}
+	}
+}
+
+func TestBestEffortReplicaPlacement(t *testing.T) {
+	topo := setup(topologyLayoutForPlacement)
+	vg := NewDefaultVolumeGrowth()
+	vg.BestEffortPlacement = true
+
+	tests := []struct {
+		replication         string
+		rack                string
+		expectedDataCenters int
+		expectedRacks       int
+	}{
+		{"010", "", 1, 2},
+		{"020", "", 2, 3},      // only 2 racks in dc1, the third copy goes to the rack in dc2
+		{"200", "", 2, 3},      // only 2 data centers, the third copy goes to another rack
+		{"002", "rack2", 1, 2}, // only 2 servers in rack2, the third copy goes to rack1
+	}
+	for _, tt := range tests {
+		rp, _ := super_block.NewReplicaPlacementFromString(tt.replication)
+		servers, err := vg.findEmptySlotsForOneVolume(topo, &VolumeGrowOption{ReplicaPlacement: rp, DataCenter: "dc1", Rack: tt.rack})
+		if err != nil {
+			t.Fatalf("replication %s: %v", tt.replication, err)
+		}
+		distinct := make(map[*DataNode]bool)
+		for _, server := range servers {
+			distinct[server] = true
+		}
+		if len(distinct) != rp.GetCopyCount() {
+			t.Errorf("replication %s: %d distinct servers for %d copies", tt.replication, len(distinct), rp.GetCopyCount())
+		}
+		if dataCenters, racks := placementSummary(servers); dataCenters != tt.expectedDataCenters || racks != tt.expectedRacks {
+			t.Errorf("replication %s: %d data centers %d racks, expected %d %d", tt.replication, dataCenters, racks, tt.expectedDataCenters, tt.expectedRacks)
+		}
+	}
+
+	// more copies than servers
+	rp, _ := super_block.NewReplicaPlacementFromString("222")
+	if _, err := vg.findEmptySlotsForOneVolume(topo, &VolumeGrowOption{ReplicaPlacement: rp}); err == nil {
+		t.Errorf("9 copies on 6 servers should fail")
+	}
+}
+
+var topologyLayoutWithDiskTypes = `
+{
+  "dc1":{
+    "rack1":{
+      "server111":{"volumes":[], "limit":5},
+      "server112":{"volumes":[], "limit":5},
+      "server113":{"volumes":[], "limit":5, "disk":"ssd"}
+    },
+    "rack2":{
+      "server121":{"volumes":[], "limit":5, "disk":"SSD"},
+      "server122":{"volumes":[], "limit":5, "disk":"hdd"}
+    }
+  }
+}
+`
+
+func TestDiskTypePlacement(t *testing.T) {
+	topo := setup(topologyLayoutWithDiskTypes)
+	vg := NewDefaultVolumeGrowth()
+
+	if free := topo.FreeSpaceOfDiskType(types.SsdType); free != 10 {
+		t.Errorf("free ssd slots %d, expected 10", free)
+	}
+	if free := topo.FreeSpaceOfDiskType(types.HardDriveType); free != 15 {
+		t.Errorf("free hdd slots %d, expected 15", free)
+	}
+
+	for _, tt := range []struct {
+		replication string
+		diskType    types.DiskType
+	}{
+		{"000", types.SsdType},
+		{"010", types.SsdType},
+		{"000", types.HardDriveType},
+		{"001", types.HardDriveType},
+		{"010", types.HardDriveType},
+	} {
+		rp, _ := super_block.NewReplicaPlacementFromString(tt.replication)
+		for i := 0; i < 20; i++ {
+			servers, err := vg.findEmptySlotsForOneVolume(topo, &VolumeGrowOption{ReplicaPlacement: rp, DiskType: tt.diskType})
+			if err != nil {
+				t.Fatalf("replication %s on %s: %v", tt.replication, tt.diskType, err)
+			}
+			for _, server := range servers {
+				if server.DiskType != tt.diskType {
+					t.Fatalf("replication %s on %s: picked %s of disk type %s", tt.replication, tt.diskType, server.Id(), server.DiskType)
+				}
+			}
+		}
+	}
+
+	// only one ssd server in each rack, and no nvme servers
+	rp, _ := super_block.NewReplicaPlacementFromString("001")
+	if servers, err := vg.findEmptySlotsForOneVolume(topo, &VolumeGrowOption{ReplicaPlacement: rp, DiskType: types.SsdType}); err == nil {
+		t.Errorf("replication 001 on ssd should fail, got %v", servers)
+	}
+	rp, _ = super_block.NewReplicaPlacementFromString("000")
+	if servers, err := vg.findEmptySlotsForOneVolume(topo, &VolumeGrowOption{ReplicaPlacement: rp, DiskType: "nvme"}); err == nil {
+		t.Errorf("no nvme servers, got %v", servers)
+	}
+
+	vg.BestEffortPlacement = true
+	rp, _ = super_block.NewReplicaPlacementFromString("002")
+	if servers, err := vg.findEmptySlotsForOneVolume(topo, &VolumeGrowOption{ReplicaPlacement: rp, DiskType: types.SsdType}); err == nil {
+		t.Errorf("3 copies on 2 ssd servers should fail, got %v", servers)
+	}
+}
+
+func TestDiskTypeVolumeLayout(t *testing.T) {
+	topo := NewTopology("weedfs", sequence.NewMemorySequencer(), 32*1024, 5, false)
+	rack := topo.GetOrCreateDataCenter("dc1").GetOrCreateRack("rack1")
+	hdd := rack.GetOrCreateDataNode("127.0.0.1", 8080, "127.0.0.1", 10)
+	ssd := rack.GetOrCreateDataNode("127.0.0.1", 8081, "127.0.0.1", 10)
+	ssd.DiskType = types.SsdType
+
+	topo.SyncDataNodeRegistration([]*master_pb.VolumeInformationMessage{
+		{Id: 1, Version: uint32(needle.CurrentVersion)},
+	}, hdd)
+	topo.IncrementalSyncDataNodeRegistration([]*master_pb.VolumeShortInformationMessage{
+		{Id: 2, Version: uint32(needle.CurrentVersion)},
+	}, nil, ssd)
+
+	rp, _ := super_block.NewReplicaPlacementFromString("000")
+	for _, tt := range []struct {
+		diskType types.DiskType
+		expected needle.VolumeId
+	}{
+		{types.HardDriveType, 1},
+		{types.SsdType, 2},
+	} {
+		option := &VolumeGrowOption{ReplicaPlacement: rp, Ttl: needle.EMPTY_TTL, DiskType: tt.diskType}
+		if !topo.HasWritableVolume(option) {
+			t.Fatalf("no writable volume on %s", tt.diskType)
+		}
+		fid, _, dn, err := topo.PickForWrite(1, option)
+		if err != nil {
+			t.Fatalf("pick for write on %s: %v", tt.diskType, err)
+		}
+		if dn.DiskType != tt.diskType || !strings.HasPrefix(fid, fmt.Sprintf("%d,", tt.expected)) {
+			t.Errorf("picked %s on %s of disk type %s, expected volume %d", fid, dn.Id(), dn.DiskType, tt.expected)
+		}
+	}
+
+	option := &VolumeGrowOption{ReplicaPlacement: rp, Ttl: needle.EMPTY_TTL, DiskType: "nvme"}
+	if topo.HasWritableVolume(option) {
+		t.Errorf("unexpected writable nvme volume")
+	}
+	if _, _, _, err := topo.PickForWrite(1, option); err == nil {
+		t.Errorf("picked a volume without nvme volumes")
+	}
+}
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket of bytes shared by all the callers.
+// The bucket holds up to one second of tokens, and the rate can be changed at any time.
+// A nil RateLimiter, or a rate of 0, does not limit.
+type RateLimiter struct {
+	sync.Mutex
+	bytesPerSecond int64
+	tokens         float64
+	lastRefillTime time.Time
+}
+
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	return &RateLimiter{
+		bytesPerSecond: bytesPerSecond,
+		tokens:         float64(bytesPerSecond),
+		lastRefillTime: time.Now(),
+	}
+}
+
+func (l *RateLimiter) SetRate(bytesPerSecond int64) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.refill(time.Now())
+	l.bytesPerSecond = bytesPerSecond
+	if l.tokens > float64(bytesPerSecond) {
+		l.tokens = float64(bytesPerSecond)
+	}
+}
+
+func (l *RateLimiter) Rate() int64 {
+	if l == nil {
+		return 0
+	}
+	l.Lock()
+	defer l.Unlock()
+	return l.bytesPerSecond
+}
+
+// Wait takes n tokens, and sleeps until the tokens taken beyond the bucket are refilled
+func (l *RateLimiter) Wait(n int64) {
+	if l == nil {
+		return
+	}
+
+	l.Lock()
+	if l.bytesPerSecond <= 0 {
+		l.Unlock()
+		return
+	}
+	l.refill(time.Now())
+	l.tokens -= float64(n)
+	var waitTime time.Duration
+	if l.tokens < 0 {
+		waitTime = time.Duration(-l.tokens / float64(l.bytesPerSecond) * float64(time.Second))
+	}
+	l.Unlock()
+
+	time.Sleep(waitTime)
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.lastRefillTime).Seconds() * float64(l.bytesPerSecond)
+	if l.tokens > float64(l.bytesPerSecond) {
+		l.tokens = float64(l.bytesPerSecond)
+	}
+	l.lastRefillTime = now
+}
+
+// the data is passed in small pieces, so a large read or write does not take all the tokens at once
+const rateLimitedPieceSize = 64 * 1024
+
+type rateLimitedWriter struct {
+	w       io.Writer
+	limiter *RateLimiter
+}
+
+func NewRateLimitedWriter(w io.Writer, limiter *RateLimiter) io.Writer {
+	if limiter == nil {
+		return w
+	}
+	return &rateLimitedWriter{w: w, limiter: limiter}
+}
+
+func (rw *rateLimitedWriter) Write(p []byte) (n int, err error) {
+	for n < len(p) && err == nil {
+		piece := p[n:]
+		if len(piece) > rateLimitedPieceSize {
+			piece = piece[:rateLimitedPieceSize]
+		}
+		rw.limiter.Wait(int64(len(piece)))
+		var written int
+		written, err = rw.w.Write(piece)
+		n += written
+	}
+	return
+}
+
+type rateLimitedReader struct {
+	r       io.Reader
+	limiter *RateLimiter
+}
+
+func NewRateLimitedReader(r io.Reader, limiter *RateLimiter) io.Reader {
+	if limiter == nil {
+		return r
+	}
+	return &rateLimitedReader{r: r, limiter: limiter}
+}
+
+func (rr *rateLimitedReader) Read(p []byte) (n int, err error) {
+	if len(p) > rateLimitedPieceSize {
+		p = p[:rateLimitedPieceSize]
+	}
+	n, err = rr.r.Read(p)
+	rr.limiter.Wait(int64(n))
+	return
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(1024 * 1024)
+
+	// the first second of data is in the bucket
+	start := time.Now()
+	limiter.Wait(1024 * 1024)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("waited %v for the tokens in the bucket", elapsed)
+	}
+
+	start = time.Now()
+	limiter.Wait(256 * 1024)
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("waited %v for a quarter of the rate, expect about 250ms", elapsed)
+	}
+
+	limiter.SetRate(0)
+	start = time.Now()
+	limiter.Wait(100 * 1024 * 1024)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("waited %v without limit", elapsed)
+	}
+
+	var nilLimiter *RateLimiter
+	nilLimiter.Wait(100 * 1024 * 1024)
+}
+
+func TestRateLimitedReaderWriter(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 32*1024) // 512KB
+
+	limiter := NewRateLimiter(1024 * 1024)
+	limiter.Wait(1024 * 1024) // empty the bucket
+
+	var buf bytes.Buffer
+	start := time.Now()
+	if _, err := io.Copy(NewRateLimitedWriter(&buf, limiter), bytes.NewReader(data)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("wrote 512KB in %v at 1MB/s", elapsed)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("unexpected written data")
+	}
+
+	limiter.SetRate(4 * 1024 * 1024)
+	start = time.Now()
+	read, err := ioutil.ReadAll(NewRateLimitedReader(bytes.NewReader(data), limiter))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("read 512KB in %v after raising the rate to 4MB/s", elapsed)
+	}
+	if !bytes.Equal(read, data) {
+		t.Errorf("unexpected read data")
+	}
+}